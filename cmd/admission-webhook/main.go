@@ -18,6 +18,8 @@ import (
 	"os"
 	"runtime"
 	"time"
+	// embed the time zone database for the time zones of the backup schedules
+	_ "time/tzdata"
 
 	"github.com/openshift/generic-admission-server/pkg/apiserver"
 	"github.com/openshift/generic-admission-server/pkg/cmd/server"
@@ -27,6 +29,8 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/version"
+	"github.com/pingcap/tidb-operator/pkg/webhook/backup"
+	"github.com/pingcap/tidb-operator/pkg/webhook/pod"
 	"github.com/pingcap/tidb-operator/pkg/webhook/statefulset"
 	"github.com/pingcap/tidb-operator/pkg/webhook/strategy"
 
@@ -79,8 +83,10 @@ func main() {
 
 	statefulSetAdmissionHook := statefulset.NewStatefulSetAdmissionControl()
 	strategyAdmissionHook := strategy.NewStrategyAdmissionHook(&strategy.Registry)
+	backupAdmissionHook := backup.NewBackupAdmissionControl()
+	podAdmissionHook := pod.NewPodAdmissionControl()
 
-	runAdmissionServer(statefulSetAdmissionHook, strategyAdmissionHook)
+	runAdmissionServer(statefulSetAdmissionHook, strategyAdmissionHook, backupAdmissionHook, podAdmissionHook)
 }
 
 // the following code copied from generic-admission-server before the commit
@@ -115,6 +115,12 @@ func (bo *Options) backupData(
 		if backup.Spec.CommitTs != "" {
 			specificArgs = append(specificArgs, fmt.Sprintf("--backupts=%s", backup.Spec.CommitTs))
 		}
+		if bo.ReplicaReadLabel != "" {
+			specificArgs = append(specificArgs, fmt.Sprintf("--replica-read-label=%s", bo.ReplicaReadLabel))
+		}
+		logCallback = func(line string) {
+			bo.updateProgressAccordingToBrLog(line, backup, statusUpdater)
+		}
 	}
 
 	fullArgs, err := bo.backupCommandTemplate(backup, specificArgs, false)
@@ -355,6 +361,29 @@ func (bo *Options) brCommandRunWithLogCallback(ctx context.Context, fullArgs []s
 	return nil
 }
 
+// updateProgressAccordingToBrLog updates the backup progress according to the progress lines of br log.
+func (bo *Options) updateProgressAccordingToBrLog(line string, backup *v1alpha1.Backup, statusUpdater controller.BackupConditionUpdaterInterface) {
+	step, progress := backupUtil.ParseRestoreProgress(line)
+	if step == "" {
+		return
+	}
+	fvalue, err := strconv.ParseFloat(progress, 64)
+	if err != nil {
+		klog.Errorf("parse backup %s progress string value %s to float error %v", bo, progress, err)
+		fvalue = 0
+	}
+	speed := backupUtil.ParseProgressSpeed(line)
+	klog.Infof("update backup %s step %s progress %f speed %s", bo, step, fvalue, speed)
+	if err := statusUpdater.Update(backup, nil, &controller.BackupUpdateStatus{
+		ProgressStep:       &step,
+		Progress:           &fvalue,
+		ProgressSpeed:      &speed,
+		ProgressUpdateTime: &metav1.Time{Time: time.Now()},
+	}); err != nil {
+		klog.Errorf("update backup %s progress error %v", bo, err)
+	}
+}
+
 func (bo *Options) updateProgressFromFile(
 	stopCh <-chan struct{},
 	backup *v1alpha1.Backup,
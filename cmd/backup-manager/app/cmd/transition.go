@@ -0,0 +1,73 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/transition"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// NewTransitionCommand implements the transition command
+func NewTransitionCommand() *cobra.Command {
+	opts := transition.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "transition",
+		Short: "Transition the data of specific backup to another storage class.",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.ValidCmdFlags(cmd.CommandPath(), cmd.LocalFlags())
+			cmdutil.CheckErr(runTransition(opts, kubecfg))
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Backup CRD object namespace")
+	cmd.Flags().StringVar(&opts.BackupName, "backupName", "", "Backup CRD object name")
+	cmd.Flags().StringVar(&opts.StorageClass, "storageClass", "", "The storage class the backup data is transitioned to")
+	return cmd
+}
+
+func runTransition(opts transition.Options, kubecfg string) error {
+	kubeCli, cli, err := util.NewKubeAndCRCli(kubecfg)
+	if err != nil {
+		return err
+	}
+	options := []informers.SharedInformerOption{
+		informers.WithNamespace(opts.Namespace),
+	}
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(cli, constants.ResyncDuration, options...)
+
+	recorder := util.NewEventRecorder(kubeCli, "backup")
+	backupInformer := informerFactory.Pingcap().V1alpha1().Backups()
+	statusUpdater := controller.NewRealBackupConditionUpdater(cli, backupInformer.Lister(), recorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go informerFactory.Start(ctx.Done())
+
+	// waiting for the shared informer's store has synced.
+	cache.WaitForCacheSync(ctx.Done(), backupInformer.Informer().HasSynced)
+
+	klog.Infof("start to transition backup %s to storage class %s", opts.String(), opts.StorageClass)
+	tm := transition.NewManager(backupInformer.Lister(), statusUpdater, opts)
+	return tm.ProcessTransition()
+}
@@ -0,0 +1,303 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	kvbackup "github.com/pingcap/kvproto/pkg/brpb"
+	backupUtil "github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxRecordedChecksumMismatches limits the mismatches recorded in the restore status
+	maxRecordedChecksumMismatches = 100
+	// errNoSuchTable is the error code of MySQL when the table doesn't exist
+	errNoSuchTable = 1146
+)
+
+// systemSchemas are restored specially by BR, so they are never checked
+var systemSchemas = map[string]struct{}{
+	"mysql":              {},
+	"sys":                {},
+	"information_schema": {},
+	"performance_schema": {},
+	"metrics_schema":     {},
+	"inspection_schema":  {},
+}
+
+// backupTable is a table recorded in the backup meta
+type backupTable struct {
+	db       string
+	table    string
+	checksum v1alpha1.TableChecksum
+}
+
+// checkDataIntegrity runs `ADMIN CHECKSUM TABLE` on the restored tables and compares the
+// total kvs and bytes with the ones recorded in the backup meta.
+func (rm *Manager) checkDataIntegrity(ctx context.Context, restore *v1alpha1.Restore, db *sql.DB) (*v1alpha1.RestoreIntegrityCheckStatus, error) {
+	filter, err := newTableFilter(integrityCheckTableFilter(restore))
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := backupUtil.GetBRSchemas(ctx, restore.Spec.StorageProvider)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := parseBackupTables(schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &v1alpha1.RestoreIntegrityCheckStatus{Passed: true}
+	for _, table := range tables {
+		if !filter.match(table.db, table.table) {
+			continue
+		}
+		actual, err := adminChecksumTable(ctx, db, table.db, table.table)
+		if err != nil {
+			return nil, fmt.Errorf("checksum table %s.%s failed, err: %v", table.db, table.table, err)
+		}
+		status.CheckedTables++
+		if checksumMatches(table.checksum, actual) {
+			continue
+		}
+		klog.Warningf("restore %s table %s.%s checksum mismatch, expected %+v, actual %+v", rm, table.db, table.table, table.checksum, actual)
+		status.Passed = false
+		if len(status.Mismatches) < maxRecordedChecksumMismatches {
+			status.Mismatches = append(status.Mismatches, v1alpha1.TableChecksumMismatch{
+				Database: table.db,
+				Table:    table.table,
+				Expected: table.checksum,
+				Actual:   actual,
+			})
+		}
+	}
+	status.TimeCompleted = metav1.Time{Time: time.Now()}
+	klog.Infof("restore %s integrity check finished, checked %d tables, passed: %t", rm, status.CheckedTables, status.Passed)
+	return status, nil
+}
+
+// checksumMatches returns whether the restored table has the same total kvs and bytes as the backup.
+// The crc64xor isn't compared as it covers the encoded keys, which contain the table ID rewritten by
+// the restore, so it always differs unless the table gets the same ID in the restored cluster.
+func checksumMatches(expected v1alpha1.TableChecksum, actual *v1alpha1.TableChecksum) bool {
+	return actual != nil && actual.TotalKvs == expected.TotalKvs && actual.TotalBytes == expected.TotalBytes
+}
+
+// integrityCheckTableFilter returns the rules of the tables to check, the tables
+// not restored are excluded by the rules of the restore.
+func integrityCheckTableFilter(restore *v1alpha1.Restore) []string {
+	if restore.Spec.IntegrityCheck != nil && len(restore.Spec.IntegrityCheck.TableFilter) > 0 {
+		return restore.Spec.IntegrityCheck.TableFilter
+	}
+	if len(restore.Spec.TableFilter) > 0 {
+		return restore.Spec.TableFilter
+	}
+	if restore.Spec.BR != nil && restore.Spec.BR.DB != "" {
+		tableRule := "/.*/"
+		if restore.Spec.Type == v1alpha1.BackupTypeTable && restore.Spec.BR.Table != "" {
+			tableRule = fmt.Sprintf("/^%s$/", regexp.QuoteMeta(restore.Spec.BR.Table))
+		}
+		return []string{fmt.Sprintf("/^%s$/.%s", regexp.QuoteMeta(restore.Spec.BR.DB), tableRule)}
+	}
+	return nil
+}
+
+// parseBackupTables gets the tables and their checksums from the schemas of the backup meta
+func parseBackupTables(schemas []*kvbackup.Schema) ([]backupTable, error) {
+	type name struct {
+		O string `json:"O"`
+	}
+	var tables []backupTable
+	for _, schema := range schemas {
+		// the schema of an empty database has no table
+		if len(schema.Table) == 0 {
+			continue
+		}
+		var dbInfo struct {
+			Name name `json:"db_name"`
+		}
+		if err := json.Unmarshal(schema.Db, &dbInfo); err != nil {
+			return nil, fmt.Errorf("unmarshal db info failed, err: %v", err)
+		}
+		var tableInfo struct {
+			Name     name             `json:"name"`
+			View     *json.RawMessage `json:"view"`
+			Sequence *json.RawMessage `json:"sequence"`
+		}
+		if err := json.Unmarshal(schema.Table, &tableInfo); err != nil {
+			return nil, fmt.Errorf("unmarshal table info of db %s failed, err: %v", dbInfo.Name.O, err)
+		}
+		// views and sequences have no data
+		if tableInfo.View != nil || tableInfo.Sequence != nil {
+			continue
+		}
+		tables = append(tables, backupTable{
+			db:    dbInfo.Name.O,
+			table: tableInfo.Name.O,
+			checksum: v1alpha1.TableChecksum{
+				Crc64Xor:   strconv.FormatUint(schema.Crc64Xor, 10),
+				TotalKvs:   schema.TotalKvs,
+				TotalBytes: schema.TotalBytes,
+			},
+		})
+	}
+	return tables, nil
+}
+
+// adminChecksumTable returns the checksum of the table, or nil if the table doesn't exist
+func adminChecksumTable(ctx context.Context, db *sql.DB, dbName, table string) (*v1alpha1.TableChecksum, error) {
+	query := fmt.Sprintf("ADMIN CHECKSUM TABLE %s.%s", quoteName(dbName), quoteName(table))
+	var (
+		name     string
+		crc64Xor uint64
+		checksum v1alpha1.TableChecksum
+	)
+	err := db.QueryRowContext(ctx, query).Scan(&name, &name, &crc64Xor, &checksum.TotalKvs, &checksum.TotalBytes)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errNoSuchTable {
+			return nil, nil
+		}
+		return nil, err
+	}
+	checksum.Crc64Xor = strconv.FormatUint(crc64Xor, 10)
+	return &checksum, nil
+}
+
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// tableFilter is a simplified BR table filter. It supports the wildcards, the regular
+// expressions and the exclusion rules, and the last matched rule decides whether a
+// table is selected. All the tables except the system tables are selected if there
+// is no rule.
+type tableFilter struct {
+	rules []tableFilterRule
+}
+
+type tableFilterRule struct {
+	exclude bool
+	db      *regexp.Regexp
+	table   *regexp.Regexp
+}
+
+func newTableFilter(rules []string) (*tableFilter, error) {
+	f := &tableFilter{}
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		r := tableFilterRule{}
+		if strings.HasPrefix(rule, "!") {
+			r.exclude = true
+			rule = rule[1:]
+		}
+		dbPattern, tablePattern, err := splitTableFilterRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		if r.db, err = compileTableFilterPattern(dbPattern); err != nil {
+			return nil, fmt.Errorf("invalid table filter %q, err: %v", rule, err)
+		}
+		if r.table, err = compileTableFilterPattern(tablePattern); err != nil {
+			return nil, fmt.Errorf("invalid table filter %q, err: %v", rule, err)
+		}
+		f.rules = append(f.rules, r)
+	}
+	return f, nil
+}
+
+func (f *tableFilter) match(db, table string) bool {
+	if _, ok := systemSchemas[strings.ToLower(db)]; ok {
+		return false
+	}
+	if len(f.rules) == 0 {
+		return true
+	}
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		r := f.rules[i]
+		if r.db.MatchString(strings.ToLower(db)) && r.table.MatchString(strings.ToLower(table)) {
+			return !r.exclude
+		}
+	}
+	return false
+}
+
+// splitTableFilterRule splits `db.table` into the db pattern and the table pattern
+func splitTableFilterRule(rule string) (string, string, error) {
+	end := 0
+	if strings.HasPrefix(rule, "/") {
+		// the db pattern is a regular expression which may contain '.'
+		i := strings.Index(rule[1:], "/")
+		if i < 0 {
+			return "", "", fmt.Errorf("invalid table filter %q, unclosed regular expression", rule)
+		}
+		end = i + 2
+	}
+	i := strings.Index(rule[end:], ".")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid table filter %q, missing '.' between the db and table patterns", rule)
+	}
+	return rule[:end+i], rule[end+i+1:], nil
+}
+
+// compileTableFilterPattern compiles a wildcard pattern or a `/regexp/` pattern, the pattern
+// is matched case-insensitively.
+func compileTableFilterPattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+	}
+	pattern = strings.Trim(pattern, "`\"")
+	var sb strings.Builder
+	sb.WriteString("(?i)^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '[':
+			j := strings.IndexByte(pattern[i:], ']')
+			if j < 0 {
+				return nil, fmt.Errorf("unclosed '[' in %q", pattern)
+			}
+			class := pattern[i+1 : i+j]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += j
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
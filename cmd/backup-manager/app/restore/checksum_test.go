@@ -0,0 +1,139 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	kvbackup "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func TestTableFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		rules   []string
+		matched []string
+		skipped []string
+	}{
+		{
+			rules:   nil,
+			matched: []string{"db.t1", "test.t2"},
+			skipped: []string{"mysql.user", "INFORMATION_SCHEMA.TABLES"},
+		},
+		{
+			rules:   []string{"db*.*", "!db2.t?"},
+			matched: []string{"db.t1", "DB1.t1", "db2.t10"},
+			skipped: []string{"test.t1", "db2.t1"},
+		},
+		{
+			rules:   []string{"/^(db|test)$/.t[0-9]"},
+			matched: []string{"db.t1", "test.T2"},
+			skipped: []string{"db1.t1", "db.t10"},
+		},
+		{
+			rules:   []string{"*.*", "!/^(mysql|test)$/.*"},
+			matched: []string{"db.t1"},
+			skipped: []string{"test.t1", "mysql.user"},
+		},
+	}
+	for _, c := range cases {
+		f, err := newTableFilter(c.rules)
+		g.Expect(err).NotTo(HaveOccurred())
+		for _, table := range c.matched {
+			db, tbl, err := splitTableFilterRule(table)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(f.match(db, tbl)).To(BeTrue(), "rules %v, table %s", c.rules, table)
+		}
+		for _, table := range c.skipped {
+			db, tbl, err := splitTableFilterRule(table)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(f.match(db, tbl)).To(BeFalse(), "rules %v, table %s", c.rules, table)
+		}
+	}
+
+	_, err := newTableFilter([]string{"db"})
+	g.Expect(err).To(HaveOccurred())
+	_, err = newTableFilter([]string{"/db.*"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestIntegrityCheckTableFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	restore := &v1alpha1.Restore{
+		Spec: v1alpha1.RestoreSpec{
+			Type: v1alpha1.BackupTypeTable,
+			BR:   &v1alpha1.BRConfig{DB: "db.1", Table: "t1"},
+		},
+	}
+	f, err := newTableFilter(integrityCheckTableFilter(restore))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(f.match("db.1", "t1")).To(BeTrue())
+	g.Expect(f.match("db.1", "t2")).To(BeFalse())
+	g.Expect(f.match("dbx1", "t1")).To(BeFalse())
+
+	restore.Spec.TableFilter = []string{"db.*"}
+	g.Expect(integrityCheckTableFilter(restore)).To(Equal([]string{"db.*"}))
+	restore.Spec.IntegrityCheck = &v1alpha1.RestoreIntegrityCheckSpec{TableFilter: []string{"db.t1"}}
+	g.Expect(integrityCheckTableFilter(restore)).To(Equal([]string{"db.t1"}))
+}
+
+func TestParseBackupTables(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tables, err := parseBackupTables([]*kvbackup.Schema{
+		{Db: []byte(`{"db_name":{"O":"Db","L":"db"}}`)},
+		{
+			Db:         []byte(`{"db_name":{"O":"Db","L":"db"}}`),
+			Table:      []byte(`{"name":{"O":"T1","L":"t1"},"view":null}`),
+			Crc64Xor:   18446744073709551615,
+			TotalKvs:   10,
+			TotalBytes: 100,
+		},
+		{
+			Db:    []byte(`{"db_name":{"O":"Db","L":"db"}}`),
+			Table: []byte(`{"name":{"O":"v1","L":"v1"},"view":{"view_select":"select 1"}}`),
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tables).To(Equal([]backupTable{
+		{
+			db:    "Db",
+			table: "T1",
+			checksum: v1alpha1.TableChecksum{
+				Crc64Xor:   "18446744073709551615",
+				TotalKvs:   10,
+				TotalBytes: 100,
+			},
+		},
+	}))
+
+	_, err = parseBackupTables([]*kvbackup.Schema{{Db: []byte(`{`), Table: []byte(`{}`)}})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestChecksumMatches(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	expected := v1alpha1.TableChecksum{Crc64Xor: "1", TotalKvs: 10, TotalBytes: 100}
+	// the crc64xor differs as the table ID is rewritten
+	g.Expect(checksumMatches(expected, &v1alpha1.TableChecksum{Crc64Xor: "2", TotalKvs: 10, TotalBytes: 100})).To(BeTrue())
+	g.Expect(checksumMatches(expected, &v1alpha1.TableChecksum{Crc64Xor: "1", TotalKvs: 9, TotalBytes: 100})).To(BeFalse())
+	g.Expect(checksumMatches(expected, &v1alpha1.TableChecksum{Crc64Xor: "1", TotalKvs: 10, TotalBytes: 99})).To(BeFalse())
+	// the table isn't restored
+	g.Expect(checksumMatches(expected, nil)).To(BeFalse())
+}
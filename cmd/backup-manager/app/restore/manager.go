@@ -336,6 +336,33 @@ func (rm *Manager) performRestore(ctx context.Context, restore *v1alpha1.Restore
 	updateStatus := &controller.RestoreUpdateStatus{
 		CommitTs: commitTS,
 	}
+	if allFinished && restore.Spec.IntegrityCheck != nil && restore.Spec.Mode != v1alpha1.RestoreModePiTR {
+		if db == nil {
+			klog.Warningf("skip the integrity check of restore %s as .spec.to is not specified", rm)
+		} else {
+			err = rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+				Type:   v1alpha1.RestoreIntegrityChecking,
+				Status: corev1.ConditionTrue,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			integrityCheck, err := rm.checkDataIntegrity(ctx, restore, db)
+			if err != nil {
+				errs = append(errs, err)
+				klog.Errorf("check data integrity of cluster %s failed, err: %s", rm, err)
+				uerr := rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+					Type:    v1alpha1.RestoreFailed,
+					Status:  corev1.ConditionTrue,
+					Reason:  "IntegrityCheckFailed",
+					Message: err.Error(),
+				}, nil)
+				errs = append(errs, uerr)
+				return errorutils.NewAggregate(errs)
+			}
+			updateStatus.IntegrityCheck = integrityCheck
+		}
+	}
 	if restore.Status.TimeStarted.Unix() <= 0 {
 		updateStatus.TimeStarted = &metav1.Time{Time: started}
 	}
@@ -256,6 +256,10 @@ func constructBROptions(restore *v1alpha1.Restore) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the options required by the cross-version restore can be overridden by the spec
+	if compatibility := restore.Status.VersionCompatibility; compatibility != nil {
+		args = append(args, compatibility.BROptions...)
+	}
 	config := restore.Spec.BR
 	if config.Concurrency != nil {
 		args = append(args, fmt.Sprintf("--concurrency=%d", *config.Concurrency))
@@ -285,10 +289,12 @@ func (ro *Options) updateProgressAccordingToBrLog(line string, restore *v1alpha1
 			klog.Errorf("parse restore %s progress string value %s to float error %v", ro, progress, progressUpdateErr)
 			fvalue = 0
 		}
-		klog.Infof("update restore %s step %s progress %s float value %f", ro, step, progress, fvalue)
+		speed := backupUtil.ParseProgressSpeed(line)
+		klog.Infof("update restore %s step %s progress %s float value %f speed %s", ro, step, progress, fvalue, speed)
 		progressUpdateErr = statusUpdater.Update(restore, nil, &controller.RestoreUpdateStatus{
 			ProgressStep:       &step,
 			Progress:           &fvalue,
+			ProgressSpeed:      &speed,
 			ProgressUpdateTime: &metav1.Time{Time: time.Now()},
 		})
 		if progressUpdateErr != nil {
@@ -0,0 +1,88 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transition
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Options contains the input arguments to the transition command
+type Options struct {
+	Namespace    string
+	BackupName   string
+	StorageClass string
+}
+
+func (o *Options) String() string {
+	return fmt.Sprintf("%s/%s", o.Namespace, o.BackupName)
+}
+
+// Manager transitions the data of a backup to another storage class
+type Manager struct {
+	backupLister  listers.BackupLister
+	StatusUpdater controller.BackupConditionUpdaterInterface
+	Options
+}
+
+// NewManager return a Manager
+func NewManager(
+	backupLister listers.BackupLister,
+	statusUpdater controller.BackupConditionUpdaterInterface,
+	opts Options) *Manager {
+	return &Manager{
+		backupLister,
+		statusUpdater,
+		opts,
+	}
+}
+
+// ProcessTransition transitions the data of the backup to the storage class and records it in the backup status
+func (m *Manager) ProcessTransition() error {
+	ctx, cancel := util.GetContextForTerminationSignals(fmt.Sprintf("transition %s", m.BackupName))
+	defer cancel()
+
+	backup, err := m.backupLister.Backups(m.Namespace).Get(m.BackupName)
+	if err != nil {
+		return fmt.Errorf("can't find backup %s CRD object, err: %v", m, err)
+	}
+	backup = backup.DeepCopy()
+	if backup.Status.BackupPath == "" {
+		return fmt.Errorf("backup %s path is empty", m)
+	}
+
+	// rclone changes the storage class of the objects in place, e.g. by copying the S3 objects to themselves,
+	// the syntax is `rclone settier <tier> <remote:path>`
+	opts := util.GetOptions(backup.Spec.StorageProvider)
+	args := util.ConstructRcloneArgs(constants.RcloneConfigArg, opts, "settier", m.StorageClass, util.NormalizeBucketURI(backup.Status.BackupPath), true)
+	output, err := exec.CommandContext(ctx, "rclone", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("backup %s, execute rclone settier command failed, output: %s, err: %v", m, string(output), err)
+	}
+	klog.Infof("backup %s data %s was transitioned to storage class %s successfully", m, backup.Status.BackupPath, m.StorageClass)
+
+	return m.StatusUpdater.Update(backup, nil, &controller.BackupUpdateStatus{
+		StorageClass:               &m.StorageClass,
+		StorageClassTransitionTime: &metav1.Time{Time: time.Now()},
+	})
+}
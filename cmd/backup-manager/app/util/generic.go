@@ -48,6 +48,8 @@ type GenericOptions struct {
 	TruncateUntil  string
 	PitrRestoredTs string
 	Initialize     bool
+	// ReplicaReadLabel is the label of the TiKV stores the snapshot backup reads from
+	ReplicaReadLabel string
 }
 
 func (bo *GenericOptions) String() string {
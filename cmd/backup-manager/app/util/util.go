@@ -418,6 +418,51 @@ func GetCommitTsFromBRMetaData(ctx context.Context, provider v1alpha1.StoragePro
 	return backupMeta.EndVersion, nil
 }
 
+// GetBRSchemas gets the schemas of the backed up tables from the backup meta. It supports
+// both the v1 backup meta and the v2 backup meta which stores the schemas in meta files.
+func GetBRSchemas(ctx context.Context, provider v1alpha1.StorageProvider) ([]*kvbackup.Schema, error) {
+	backupMeta, err := GetBRMetaData(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	if backupMeta.SchemaIndex == nil {
+		return backupMeta.Schemas, nil
+	}
+
+	s, err := util.NewStorageBackend(provider, &util.StorageCredential{})
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	var schemas []*kvbackup.Schema
+	var walk func(metaFile *kvbackup.MetaFile) error
+	walk = func(metaFile *kvbackup.MetaFile) error {
+		schemas = append(schemas, metaFile.Schemas...)
+		for _, file := range metaFile.MetaFiles {
+			if len(file.CipherIv) > 0 {
+				return fmt.Errorf("meta file %s is encrypted", file.Name)
+			}
+			data, err := s.ReadAll(ctx, file.Name)
+			if err != nil {
+				return errors.Annotatef(err, "read meta file %s", file.Name)
+			}
+			child := &kvbackup.MetaFile{}
+			if err := proto.Unmarshal(data, child); err != nil {
+				return errors.Annotatef(err, "unmarshal meta file %s", file.Name)
+			}
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(backupMeta.SchemaIndex); err != nil {
+		return nil, errors.Annotatef(err, "read schemas from bucket %s and prefix %s", s.GetBucket(), s.GetPrefix())
+	}
+	return schemas, nil
+}
+
 // ConstructRcloneArgs constructs the rclone args
 func ConstructRcloneArgs(conf string, opts []string, command, source, dest string, verboseLog bool) []string {
 	var args []string
@@ -520,6 +565,17 @@ func ParseRestoreProgress(line string) (step, progress string) {
 	return
 }
 
+// ParseProgressSpeed parses the speed of the progress line of br log, e.g. [speed="12.3MB/s"]
+func ParseProgressSpeed(line string) string {
+	matchs := progressSpeedRegex.FindStringSubmatch(line)
+	if len(matchs) < 2 {
+		return ""
+	}
+	return matchs[1]
+}
+
+var progressSpeedRegex = regexp.MustCompile(`\[progress\].*?\[speed="?([^"\]]*)"?\]`)
+
 // ReadAllStdErrToChannel read the stdErr and send the output to channel
 func ReadAllStdErrToChannel(stdErr io.Reader, errMsgCh chan []byte) {
 	errMsg, err := io.ReadAll(stdErr)
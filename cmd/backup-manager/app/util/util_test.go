@@ -629,6 +629,20 @@ func TestParseRestoreProgress(t *testing.T) {
 	}
 }
 
+func TestParseProgressSpeed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cases := map[string]string{
+		"": "",
+		"abcdef, [progress] [step=\"Full Backup\"] [progress=10%] abcdefeg":                                                      "",
+		"[progress] [step=\"Full Backup\"] [progress=47.29%] [count=\"47 / 100\"] [speed=\"12.3 MB/s\"] [elapsed=1m0s]":          "12.3 MB/s",
+		"[progress] [step=\"Restore KV Files\"] [progress=10.9%] [count=\"1 / 10\"] [speed=1.2MB/s] [elapsed=1s] [remaining=9s]": "1.2MB/s",
+		"[INFO] [client.go:1] [\"backup started\"] [speed=1MB/s]":                                                                "",
+	}
+	for line, speed := range cases {
+		g.Expect(ParseProgressSpeed(line)).To(Equal(speed), line)
+	}
+}
+
 func newBackup() *v1alpha1.Backup {
 	return &v1alpha1.Backup{
 		TypeMeta: metav1.TypeMeta{
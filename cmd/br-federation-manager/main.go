@@ -22,6 +22,8 @@ import (
 	"os/signal"
 	"reflect"
 	"syscall"
+	// embed the time zone database for the time zones of the backup schedules
+	_ "time/tzdata"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -21,26 +21,38 @@ import (
 	"os"
 	"os/signal"
 	"reflect"
+	"sync/atomic"
 	"syscall"
+	"time"
+	// embed the time zone database for the time zones of the backup schedules
+	_ "time/tzdata"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	asclientset "github.com/pingcap/advanced-statefulset/client/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apiserver"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/controller/backup"
 	"github.com/pingcap/tidb-operator/pkg/controller/backupschedule"
+	"github.com/pingcap/tidb-operator/pkg/controller/brjobgc"
 	compact "github.com/pingcap/tidb-operator/pkg/controller/compactbackup"
 	"github.com/pingcap/tidb-operator/pkg/controller/dmcluster"
+	"github.com/pingcap/tidb-operator/pkg/controller/orphangc"
+	"github.com/pingcap/tidb-operator/pkg/controller/pumpmigration"
 	"github.com/pingcap/tidb-operator/pkg/controller/restore"
+	"github.com/pingcap/tidb-operator/pkg/controller/tidbaccount"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbcluster"
+	"github.com/pingcap/tidb-operator/pkg/controller/tidbclusterreplication"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbdashboard"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbinitializer"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbmonitor"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbngmonitoring"
 	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/scheme"
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 	"github.com/pingcap/tidb-operator/pkg/upgrader"
 	"github.com/pingcap/tidb-operator/pkg/version"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -83,6 +95,18 @@ func main() {
 
 	logCustomPorts()
 
+	if err := cliCfg.ValidateAPIServer(); err != nil {
+		klog.Fatalf("invalid API server config: %v", err)
+	}
+	if err := cliCfg.ValidateLeaderElection(); err != nil {
+		klog.Fatalf("invalid leader election config: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cliCfg.TracingEndpoint, cliCfg.TracingInsecure, cliCfg.TracingSampleRatio)
+	if err != nil {
+		klog.Fatalf("failed to init tracing: %v", err)
+	}
+
 	hostName, err := os.Hostname()
 	if err != nil {
 		klog.Fatalf("failed to get hostname: %v", err)
@@ -111,22 +135,35 @@ func main() {
 	// If they are zero, the created client will use the default values: 5, 10.
 	cfg.QPS = float32(cliCfg.KubeClientQPS)
 	cfg.Burst = cliCfg.KubeClientBurst
+	pdapi.SetCircuitBreaker(cliCfg.PDCircuitBreakerThreshold, cliCfg.PDCircuitBreakerCoolOff)
+
+	// the clients of the controllers are rate limited per namespace by a copy of the config,
+	// so the leader election, whose lock is in the namespace of the operator, is not throttled
+	// by a cluster syncing in a hot loop in the same namespace
+	clientCfg := rest.CopyConfig(cfg)
+	if limiter := controller.NewNamespaceRateLimiter(cliCfg.KubeClientQPSPerNamespace, cliCfg.KubeClientBurstPerNamespace); limiter != nil {
+		clientCfg.Wrap(limiter.Wrap)
+	}
 
-	cli, err := versioned.NewForConfig(cfg)
+	cli, err := versioned.NewForConfig(clientCfg)
 	if err != nil {
 		klog.Fatalf("failed to create Clientset: %v", err)
 	}
 	var kubeCli kubernetes.Interface
-	kubeCli, err = kubernetes.NewForConfig(cfg)
+	kubeCli, err = kubernetes.NewForConfig(clientCfg)
 	if err != nil {
 		klog.Fatalf("failed to get kubernetes Clientset: %v", err)
 	}
-	asCli, err := asclientset.NewForConfig(cfg)
+	leaderElectionCli, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("failed to get kubernetes Clientset for leader election: %v", err)
+	}
+	asCli, err := asclientset.NewForConfig(clientCfg)
 	if err != nil {
 		klog.Fatalf("failed to get advanced-statefulset Clientset: %v", err)
 	}
 	// TODO: optimize the read of genericCli with the shared cache
-	genericCli, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	genericCli, err := client.New(clientCfg, client.Options{Scheme: scheme.Scheme})
 	if err != nil {
 		klog.Fatalf("failed to get the generic kube-apiserver client: %v", err)
 	}
@@ -150,13 +187,54 @@ func main() {
 		klog.Fatalf("failed to create Dependencies: %s", err)
 	}
 
+	// the API server reads and patches the CRs through the API server of Kubernetes rather than the
+	// informers, so that it's served by all the replicas regardless of the leader election
+	var apiSrv *http.Server
+	if cliCfg.APIServerAddress != "" {
+		apiSrv = apiserver.NewHTTPServer(deps, cliCfg.APIServerAddress)
+		go func() {
+			var err error
+			if cliCfg.APIServerTLSCertFile != "" {
+				err = apiSrv.ListenAndServeTLS(cliCfg.APIServerTLSCertFile, cliCfg.APIServerTLSKeyFile)
+			} else {
+				err = apiSrv.ListenAndServe()
+			}
+			if err != http.ErrServerClosed {
+				klog.Fatalf("failed to serve the API server: %v", err)
+			}
+		}()
+	}
+
+	endPointsName := "tidb-controller-manager"
+	if helmRelease != "" {
+		endPointsName += "-" + helmRelease
+	}
+	// the ConfigMap to persist the keys not synced successfully when the operator exits
+	requeueHintsName := endPointsName + "-requeue-hints"
+	var leading atomic.Bool
+
 	onStarted := func(ctx context.Context) {
+		leading.Store(true)
 		// Upgrade before running any controller logic. If it fails, we wait
 		// for process supervisor to restart it again.
 		if err := operatorUpgrader.Upgrade(); err != nil {
 			klog.Fatalf("failed to upgrade: %v", err)
 		}
 
+		// Load the keys not synced successfully by the last leader, they are synced first.
+		// The hints persisted before the draining is disabled are deleted.
+		if cliCfg.ShutdownGracePeriod > 0 {
+			hints, err := controller.LoadRequeueHints(ctx, kubeCli, ns, requeueHintsName)
+			if err != nil {
+				klog.Warningf("failed to load requeue hints from configmap %s/%s: %v", ns, requeueHintsName, err)
+			} else if len(hints) > 0 {
+				klog.Infof("load requeue hints %v", hints)
+				deps.SyncTracker.AddHints(hints)
+			}
+		} else if err := controller.DeleteRequeueHints(ctx, kubeCli, ns, requeueHintsName); err != nil {
+			klog.Warningf("failed to delete requeue hints configmap %s/%s: %v", ns, requeueHintsName, err)
+		}
+
 		// Define some nested types to simplify the codebase
 		type Controller interface {
 			Run(int, <-chan struct{})
@@ -189,6 +267,11 @@ func main() {
 			tidbmonitor.NewController(deps),
 			tidbngmonitoring.NewController(deps),
 			tidbdashboard.NewController(deps),
+			tidbclusterreplication.NewController(deps),
+			pumpmigration.NewController(deps),
+			tidbaccount.NewController(deps),
+			orphangc.NewController(deps),
+			brjobgc.NewController(deps),
 		}
 
 		// Start informer factories after all controllers are initialized.
@@ -218,17 +301,13 @@ func main() {
 		klog.Fatal("leader election lost")
 	}
 
-	endPointsName := "tidb-controller-manager"
-	if helmRelease != "" {
-		endPointsName += "-" + helmRelease
-	}
 	// leader election for multiple tidb-controller-manager instances
 	go wait.Forever(func() {
 		lock, err := resourcelock.New(cliCfg.ResourceLock,
 			ns,
 			endPointsName,
-			kubeCli.CoreV1(),
-			kubeCli.CoordinationV1(),
+			leaderElectionCli.CoreV1(),
+			leaderElectionCli.CoordinationV1(),
 			resourcelock.ResourceLockConfig{
 				Identity:      hostName,
 				EventRecorder: &record.FakeRecorder{},
@@ -236,6 +315,10 @@ func main() {
 		if err != nil {
 			klog.Fatalf("failed to create lock: %v", err)
 		}
+		// the pod, PVC and statefulset deletions and the PD member and store deletions are fenced by the lock,
+		// the token is reused within the renew deadline except for the PD deletions, which read the record live
+		deps.Fencing.SetLock(lock, cliCfg.RenewDeadline)
+		pdapi.SetFence(deps.Fencing.CheckLive)
 
 		leaderelection.RunOrDie(context.TODO(), leaderelection.LeaderElectionConfig{
 			Lock:          lock,
@@ -243,7 +326,17 @@ func main() {
 			RenewDeadline: cliCfg.RenewDeadline,
 			RetryPeriod:   cliCfg.RetryPeriod,
 			Callbacks: leaderelection.LeaderCallbacks{
-				OnStartedLeading: onStarted,
+				OnStartedLeading: func(ctx context.Context) {
+					// the token read here is reused by the fencing within the renew deadline, after which
+					// the deposed leader has stepped down or reads the record again
+					token, err := deps.Fencing.Token()
+					if err != nil {
+						klog.Errorf("failed to get the fencing token: %v", err)
+					} else {
+						klog.Infof("acquired the leadership with fencing token %d", token)
+					}
+					onStarted(ctx)
+				},
 				OnStoppedLeading: onStopped,
 			},
 		})
@@ -261,6 +354,18 @@ func main() {
 	go func() {
 		sig := <-sc
 		klog.Infof("got signal %s to exit", sig)
+		shutdownControllers(deps, cliCfg.ShutdownGracePeriod, leading.Load(), ns, requeueHintsName)
+		// flush the spans of the finished syncs
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err2 := shutdownTracing(ctx); err2 != nil {
+			klog.Errorf("failed to shutdown tracing: %v", err2)
+		}
+		cancel()
+		if apiSrv != nil {
+			if err2 := apiSrv.Shutdown(context.Background()); err2 != nil {
+				klog.Errorf("failed to shutdown the API server: %v", err2)
+			}
+		}
 		if err2 := srv.Shutdown(context.Background()); err2 != nil {
 			klog.Fatal("fail to shutdown the HTTP server", err2)
 		}
@@ -272,6 +377,28 @@ func main() {
 	klog.Infof("tidb-controller-manager exited")
 }
 
+// shutdownControllers stops syncing new keys, waits for the in-flight syncs to finish
+// and persists the keys not synced successfully for the next leader.
+func shutdownControllers(deps *controller.Dependencies, gracePeriod time.Duration, leading bool, ns, requeueHintsName string) {
+	if gracePeriod <= 0 {
+		return
+	}
+	if !deps.SyncTracker.Drain(gracePeriod) {
+		klog.Warningf("in-flight syncs are not finished in %v, exit anyway", gracePeriod)
+	}
+	if !leading {
+		return
+	}
+	hints := deps.SyncTracker.PendingKeys()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := controller.SaveRequeueHints(ctx, deps.KubeClientset, ns, requeueHintsName, hints); err != nil {
+		klog.Errorf("failed to save requeue hints %v to configmap %s/%s: %v", hints, ns, requeueHintsName, err)
+		return
+	}
+	klog.Infof("save requeue hints %v", hints)
+}
+
 func createHTTPServer() *http.Server {
 	serverMux := http.NewServeMux()
 	// HTTP path for pprof
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/discovery/server"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
@@ -43,9 +44,11 @@ import (
 )
 
 var (
-	printVersion bool
-	port         int
-	proxyPort    int
+	printVersion       bool
+	port               int
+	proxyPort          int
+	statelessBootstrap bool
+	membersCacheTTL    time.Duration
 )
 
 func init() {
@@ -54,6 +57,8 @@ func init() {
 	flag.BoolVar(&printVersion, "version", false, "Show version and quit")
 	flag.IntVar(&port, "port", 10261, "The port that the tidb discovery's http service runs on (default 10261)")
 	flag.IntVar(&proxyPort, "proxy-port", 10262, "The port that the tidb discovery's proxy service runs on (default 10262)")
+	flag.BoolVar(&statelessBootstrap, "stateless-bootstrap", false, "Bootstrap the PD cluster with the PD member of the lowest ordinal instead of the last registered one, required when running multiple replicas")
+	flag.DurationVar(&membersCacheTTL, "members-cache-ttl", 5*time.Second, "The TTL of the cached PD members, 0 disables the cache")
 	flag.Parse()
 }
 
@@ -112,7 +117,10 @@ func main() {
 		addr := fmt.Sprintf("0.0.0.0:%d", port)
 		klog.Infof("starting TiDB Discovery server, listening on %s", addr)
 		lister := kubeInformerFactory.Core().V1().Secrets().Lister()
-		discoveryServer := server.NewServer(pdapi.NewDefaultPDControl(lister), dmapi.NewDefaultMasterControl(lister), cli, kubeCli)
+		discoveryServer := server.NewServer(pdapi.NewDefaultPDControl(lister), dmapi.NewDefaultMasterControl(lister), cli, kubeCli,
+			discovery.WithStatelessBootstrap(statelessBootstrap),
+			discovery.WithMembersCacheTTL(membersCacheTTL),
+		)
 		discoveryServer.ListenAndServe(addr)
 	}, 5*time.Second)
 	go wait.Forever(func() {
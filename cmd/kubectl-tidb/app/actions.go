@@ -0,0 +1,199 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewBackupCommand returns the command to operate the backups
+func NewBackupCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Operate the backups",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "now SCHEDULE",
+		Short: "Trigger a backup of the backup schedule now",
+		Long: "Trigger a backup of the backup schedule now, the backup is created by TiDB Operator from the backup " +
+			"template of the schedule once the last backup of the schedule is finished.",
+		Args: cobra.ExactArgs(1),
+		RunE: runE(o, func(args []string) error {
+			return o.BackupNow(args[0])
+		}),
+	})
+	return cmd
+}
+
+// BackupNow requests TiDB Operator to create a backup of the backup schedule
+func (o *Options) BackupNow(name string) error {
+	bs, err := o.Cli.PingcapV1alpha1().BackupSchedules(o.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	value := time.Now().Format(time.RFC3339)
+	data, pt, err := annotationsPatch(label.AnnBackupNowKey, &value)
+	if err != nil {
+		return err
+	}
+	if _, err := o.Cli.PingcapV1alpha1().BackupSchedules(o.Namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "backupschedule/%s backup requested\n", name)
+	if bs.Spec.Pause {
+		fmt.Fprintf(o.Out, "backupschedule/%s is paused, the backup is created after it is resumed\n", name)
+	}
+	return nil
+}
+
+// NewScheduleCommand returns the command to operate the backup schedules
+func NewScheduleCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Operate the backup schedules",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "pause SCHEDULE",
+		Short: "Pause the backup schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: runE(o, func(args []string) error {
+			return o.PauseSchedule(args[0], true)
+		}),
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "resume SCHEDULE",
+		Short: "Resume the paused backup schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: runE(o, func(args []string) error {
+			return o.PauseSchedule(args[0], false)
+		}),
+	})
+	return cmd
+}
+
+// PauseSchedule sets spec.pause of the backup schedule
+func (o *Options) PauseSchedule(name string, pause bool) error {
+	data, pt, err := mergePatch(map[string]interface{}{
+		"spec": map[string]interface{}{"pause": pause},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := o.Cli.PingcapV1alpha1().BackupSchedules(o.Namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	action := "resumed"
+	if pause {
+		action = "paused"
+	}
+	fmt.Fprintf(o.Out, "backupschedule/%s %s\n", name, action)
+	return nil
+}
+
+// NewUpgradeCommand returns the command to operate the upgrades of the tidb clusters
+func NewUpgradeCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Operate the upgrades of the TiDB clusters",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "continue CLUSTER",
+		Short: "Force the blocked upgrade of the TiDB cluster to continue",
+		Long: "Force the blocked upgrade of the TiDB cluster to continue, TiDB Operator upgrades the pods " +
+			"without waiting for the cluster to be healthy.",
+		Args: cobra.ExactArgs(1),
+		RunE: runE(o, func(args []string) error {
+			return o.ForceUpgrade(args[0])
+		}),
+	})
+	return cmd
+}
+
+// ForceUpgrade sets the force-upgrade annotation of the tidb cluster
+func (o *Options) ForceUpgrade(name string) error {
+	value := label.AnnForceUpgradeVal
+	data, pt, err := annotationsPatch(label.AnnForceUpgradeKey, &value)
+	if err != nil {
+		return err
+	}
+	if _, err := o.Cli.PingcapV1alpha1().TidbClusters(o.Namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "tidbcluster/%s force upgrade enabled\n", name)
+	return nil
+}
+
+// NewEvictLeaderCommand returns the command to evict the region leaders from the tikv pods
+func NewEvictLeaderCommand(o *Options) *cobra.Command {
+	var value string
+	var remove bool
+	cmd := &cobra.Command{
+		Use:   "evict-leader POD...",
+		Short: "Evict the region leaders from the TiKV pods",
+		Long: "Evict the region leaders from the TiKV pods. With --value=delete-pod, the pods are deleted after " +
+			"the leaders are evicted. With --remove, the leaders are allowed to be transferred back.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: runE(o, func(args []string) error {
+			if remove {
+				return o.EvictLeader(args, nil)
+			}
+			return o.EvictLeader(args, &value)
+		}),
+	}
+	cmd.Flags().StringVar(&value, "value", v1alpha1.EvictLeaderValueNone,
+		fmt.Sprintf("What to do after the leaders are evicted, %q or %q", v1alpha1.EvictLeaderValueNone, v1alpha1.EvictLeaderValueDeletePod))
+	cmd.Flags().BoolVar(&remove, "remove", false, "Stop evicting the leaders from the pods")
+	return cmd
+}
+
+// EvictLeader sets the evict-leader annotation of the tikv pods, the annotation is removed if value is nil
+func (o *Options) EvictLeader(pods []string, value *string) error {
+	if value != nil && *value != v1alpha1.EvictLeaderValueNone && *value != v1alpha1.EvictLeaderValueDeletePod {
+		return fmt.Errorf("invalid value %q, must be %q or %q", *value, v1alpha1.EvictLeaderValueNone, v1alpha1.EvictLeaderValueDeletePod)
+	}
+	data, pt, err := annotationsPatch(v1alpha1.EvictLeaderAnnKey, value)
+	if err != nil {
+		return err
+	}
+	for _, name := range pods {
+		pod, err := o.KubeCli.CoreV1().Pods(o.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !isTiKVPod(pod) {
+			return fmt.Errorf("pod %s is not a TiKV pod", name)
+		}
+		if _, err := o.KubeCli.CoreV1().Pods(o.Namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+		if value == nil {
+			fmt.Fprintf(o.Out, "pod/%s leader eviction stopped\n", name)
+		} else {
+			fmt.Fprintf(o.Out, "pod/%s leader eviction requested\n", name)
+		}
+	}
+	return nil
+}
+
+func isTiKVPod(pod *corev1.Pod) bool {
+	return pod.Labels[label.ComponentLabelKey] == label.TiKVLabelVal
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+)
+
+func newFakeOptions() *Options {
+	bs := &v1alpha1.BackupSchedule{ObjectMeta: metav1.ObjectMeta{Name: "bs", Namespace: "ns"}}
+	tc := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Name: "tc", Namespace: "ns"}}
+	tikv := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "tc-tikv-0", Namespace: "ns",
+		Labels: map[string]string{label.ComponentLabelKey: label.TiKVLabelVal}}}
+	tidb := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "tc-tidb-0", Namespace: "ns",
+		Labels: map[string]string{label.ComponentLabelKey: label.TiDBLabelVal}}}
+	return &Options{
+		Out:       &bytes.Buffer{},
+		Namespace: "ns",
+		KubeCli:   kubefake.NewSimpleClientset(tikv, tidb),
+		Cli:       fake.NewSimpleClientset(bs, tc),
+	}
+}
+
+func TestActions(t *testing.T) {
+	g := NewGomegaWithT(t)
+	o := newFakeOptions()
+
+	getBackupSchedule := func() *v1alpha1.BackupSchedule {
+		bs, err := o.Cli.PingcapV1alpha1().BackupSchedules("ns").Get(context.TODO(), "bs", metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+		return bs
+	}
+	g.Expect(o.BackupNow("bs")).Should(Succeed())
+	g.Expect(getBackupSchedule().Annotations).Should(HaveKey(label.AnnBackupNowKey))
+	g.Expect(o.BackupNow("notexist")).ShouldNot(Succeed())
+
+	g.Expect(o.PauseSchedule("bs", true)).Should(Succeed())
+	g.Expect(getBackupSchedule().Spec.Pause).Should(BeTrue())
+	g.Expect(o.PauseSchedule("bs", false)).Should(Succeed())
+	g.Expect(getBackupSchedule().Spec.Pause).Should(BeFalse())
+
+	g.Expect(o.ForceUpgrade("tc")).Should(Succeed())
+	tc, err := o.Cli.PingcapV1alpha1().TidbClusters("ns").Get(context.TODO(), "tc", metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(tc.Annotations).Should(HaveKeyWithValue(label.AnnForceUpgradeKey, label.AnnForceUpgradeVal))
+
+	getPodAnnotations := func(name string) map[string]string {
+		pod, err := o.KubeCli.CoreV1().Pods("ns").Get(context.TODO(), name, metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+		return pod.Annotations
+	}
+	g.Expect(o.EvictLeader([]string{"tc-tikv-0"}, pointer.StringPtr(v1alpha1.EvictLeaderValueDeletePod))).Should(Succeed())
+	g.Expect(getPodAnnotations("tc-tikv-0")).Should(HaveKeyWithValue(v1alpha1.EvictLeaderAnnKey, v1alpha1.EvictLeaderValueDeletePod))
+	g.Expect(o.EvictLeader([]string{"tc-tikv-0"}, nil)).Should(Succeed())
+	g.Expect(getPodAnnotations("tc-tikv-0")).ShouldNot(HaveKey(v1alpha1.EvictLeaderAnnKey))
+	g.Expect(o.EvictLeader([]string{"tc-tikv-0"}, pointer.StringPtr("invalid"))).ShouldNot(Succeed())
+	g.Expect(o.EvictLeader([]string{"tc-tidb-0"}, pointer.StringPtr(v1alpha1.EvictLeaderValueNone))).ShouldNot(Succeed())
+	g.Expect(getPodAnnotations("tc-tidb-0")).Should(BeEmpty())
+}
+
+func TestGetClusterHealth(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Spec.PD = &v1alpha1.PDSpec{}
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{}
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{}
+	tc.Status.Conditions = []v1alpha1.TidbClusterCondition{{
+		Type:   v1alpha1.TidbClusterReady,
+		Status: corev1.ConditionFalse,
+		Reason: "TiKVStoreNotUp",
+	}}
+	tc.Status.PD.Phase = v1alpha1.NormalPhase
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{"pd-0": {Health: true}, "pd-1": {Health: true}, "pd-2": {Health: false}}
+	tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{"1": {State: v1alpha1.TiKVStateUp}, "4": {State: v1alpha1.TiKVStateDown}}
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{"tidb-0": {Health: true}}
+	// the components not in the spec are ignored
+	tc.Status.TiCDC.Captures = map[string]v1alpha1.TiCDCCapture{"ticdc-0": {Ready: true}}
+
+	health := GetClusterHealth(tc)
+	g.Expect(health.Ready).Should(BeFalse())
+	g.Expect(health.Reason).Should(Equal("TiKVStoreNotUp"))
+	g.Expect(health.Components).Should(Equal([]ComponentHealth{
+		{Component: v1alpha1.PDMemberType, Phase: v1alpha1.NormalPhase, Healthy: 2, Total: 3},
+		{Component: v1alpha1.TiKVMemberType, Phase: v1alpha1.UpgradePhase, Healthy: 1, Total: 2},
+		{Component: v1alpha1.TiDBMemberType, Healthy: 1, Total: 1},
+	}))
+}
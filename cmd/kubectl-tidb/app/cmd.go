@@ -0,0 +1,115 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options holds the clients and the common flags of the kubectl-tidb commands
+type Options struct {
+	ConfigFlags *genericclioptions.ConfigFlags
+	Out         io.Writer
+
+	// Namespace is the namespace of the objects, it is loaded from the kubeconfig if not set
+	Namespace string
+	// KubeCli and Cli are created from the kubeconfig if not set
+	KubeCli kubernetes.Interface
+	Cli     versioned.Interface
+}
+
+// Complete loads the namespace and the clients from the kubeconfig
+func (o *Options) Complete() error {
+	if o.Namespace == "" {
+		ns, _, err := o.ConfigFlags.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return err
+		}
+		o.Namespace = ns
+	}
+	if o.KubeCli != nil && o.Cli != nil {
+		return nil
+	}
+	cfg, err := o.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	if o.KubeCli, err = kubernetes.NewForConfig(cfg); err != nil {
+		return fmt.Errorf("failed to create Clientset: %v", err)
+	}
+	if o.Cli, err = versioned.NewForConfig(cfg); err != nil {
+		return fmt.Errorf("failed to create Clientset: %v", err)
+	}
+	return nil
+}
+
+// NewTidbCommand returns the root command of the kubectl-tidb plugin
+func NewTidbCommand(out io.Writer) *cobra.Command {
+	o := &Options{
+		ConfigFlags: genericclioptions.NewConfigFlags(true),
+		Out:         out,
+	}
+
+	cmds := &cobra.Command{
+		Use:   "kubectl-tidb",
+		Short: "Operate the TiDB clusters managed by TiDB Operator",
+		Long: "Operate the TiDB clusters managed by TiDB Operator. The commands only change the custom resources " +
+			"and their annotations, the actions are performed by TiDB Operator.",
+		SilenceUsage: true,
+		Run: func(cmd *cobra.Command, _ []string) {
+			cmd.Help()
+		},
+	}
+	o.ConfigFlags.AddFlags(cmds.PersistentFlags())
+
+	cmds.AddCommand(NewBackupCommand(o))
+	cmds.AddCommand(NewScheduleCommand(o))
+	cmds.AddCommand(NewUpgradeCommand(o))
+	cmds.AddCommand(NewEvictLeaderCommand(o))
+	cmds.AddCommand(NewHealthCommand(o))
+	return cmds
+}
+
+// runE completes the options before running the command
+func runE(o *Options, run func(args []string) error) func(*cobra.Command, []string) error {
+	return func(_ *cobra.Command, args []string) error {
+		if err := o.Complete(); err != nil {
+			return err
+		}
+		return run(args)
+	}
+}
+
+// mergePatch marshals the object as a json merge patch
+func mergePatch(obj map[string]interface{}) ([]byte, types.PatchType, error) {
+	data, err := json.Marshal(obj)
+	return data, types.MergePatchType, err
+}
+
+// annotationsPatch returns the patch setting the annotation, the annotation is removed if the value is nil
+func annotationsPatch(key string, value *string) ([]byte, types.PatchType, error) {
+	return mergePatch(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{key: value},
+		},
+	})
+}
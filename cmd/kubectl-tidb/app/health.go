@@ -0,0 +1,139 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComponentHealth is the aggregated health of a component of a tidb cluster
+type ComponentHealth struct {
+	Component v1alpha1.MemberType
+	Phase     v1alpha1.MemberPhase
+	// Healthy is the number of the healthy members, stores or captures
+	Healthy int
+	// Total is the number of the members, stores or captures in the status
+	Total int
+}
+
+// ClusterHealth is the aggregated health of a tidb cluster
+type ClusterHealth struct {
+	Ready      bool
+	Reason     string
+	Message    string
+	Components []ComponentHealth
+}
+
+// NewHealthCommand returns the command to show the aggregated health of the tidb clusters
+func NewHealthCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "health CLUSTER",
+		Short: "Show the aggregated health of the TiDB cluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: runE(o, func(args []string) error {
+			return o.Health(args[0])
+		}),
+	}
+}
+
+// Health prints the aggregated health of the tidb cluster
+func (o *Options) Health(name string) error {
+	tc, err := o.Cli.PingcapV1alpha1().TidbClusters(o.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	health := GetClusterHealth(tc)
+
+	fmt.Fprintf(o.Out, "TidbCluster: %s/%s\n", tc.Namespace, tc.Name)
+	fmt.Fprintf(o.Out, "Ready: %t", health.Ready)
+	if health.Reason != "" {
+		fmt.Fprintf(o.Out, " (%s)", health.Reason)
+	}
+	fmt.Fprintln(o.Out)
+	if health.Message != "" {
+		fmt.Fprintf(o.Out, "Message: %s\n", health.Message)
+	}
+	fmt.Fprintln(o.Out)
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tPHASE\tHEALTHY")
+	for _, c := range health.Components {
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\n", c.Component, c.Phase, c.Healthy, c.Total)
+	}
+	return w.Flush()
+}
+
+// GetClusterHealth aggregates the health of the tidb cluster from its status,
+// only the components in the spec are included
+func GetClusterHealth(tc *v1alpha1.TidbCluster) ClusterHealth {
+	health := ClusterHealth{}
+	if cond := utiltidbcluster.GetTidbClusterReadyCondition(tc.Status); cond != nil {
+		health.Ready = cond.Status == corev1.ConditionTrue
+		health.Reason = cond.Reason
+		health.Message = cond.Message
+	}
+
+	if tc.Spec.PD != nil {
+		c := ComponentHealth{Component: v1alpha1.PDMemberType, Phase: tc.Status.PD.Phase, Total: len(tc.Status.PD.Members)}
+		for _, m := range tc.Status.PD.Members {
+			if m.Health {
+				c.Healthy++
+			}
+		}
+		health.Components = append(health.Components, c)
+	}
+	if tc.Spec.TiKV != nil {
+		health.Components = append(health.Components, storesHealth(v1alpha1.TiKVMemberType, tc.Status.TiKV.Phase, tc.Status.TiKV.Stores))
+	}
+	if tc.Spec.TiFlash != nil {
+		health.Components = append(health.Components, storesHealth(v1alpha1.TiFlashMemberType, tc.Status.TiFlash.Phase, tc.Status.TiFlash.Stores))
+	}
+	if tc.Spec.TiDB != nil {
+		c := ComponentHealth{Component: v1alpha1.TiDBMemberType, Phase: tc.Status.TiDB.Phase, Total: len(tc.Status.TiDB.Members)}
+		for _, m := range tc.Status.TiDB.Members {
+			if m.Health {
+				c.Healthy++
+			}
+		}
+		health.Components = append(health.Components, c)
+	}
+	if tc.Spec.TiCDC != nil {
+		c := ComponentHealth{Component: v1alpha1.TiCDCMemberType, Phase: tc.Status.TiCDC.Phase, Total: len(tc.Status.TiCDC.Captures)}
+		for _, capture := range tc.Status.TiCDC.Captures {
+			if capture.Ready {
+				c.Healthy++
+			}
+		}
+		health.Components = append(health.Components, c)
+	}
+	return health
+}
+
+func storesHealth(component v1alpha1.MemberType, phase v1alpha1.MemberPhase, stores map[string]v1alpha1.TiKVStore) ComponentHealth {
+	c := ComponentHealth{Component: component, Phase: phase, Total: len(stores)}
+	for _, store := range stores {
+		if store.State == v1alpha1.TiKVStateUp {
+			c.Healthy++
+		}
+	}
+	return c
+}
@@ -28,7 +28,9 @@ import (
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
 		"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.BRConfig":                   schema_apis_federation_pingcap_v1alpha1_BRConfig(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.ExternalTSOService":         schema_apis_federation_pingcap_v1alpha1_ExternalTSOService(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackup":               schema_apis_federation_pingcap_v1alpha1_VolumeBackup(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupCoordination":   schema_apis_federation_pingcap_v1alpha1_VolumeBackupCoordination(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupList":           schema_apis_federation_pingcap_v1alpha1_VolumeBackupList(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupMemberCluster":  schema_apis_federation_pingcap_v1alpha1_VolumeBackupMemberCluster(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupMemberSpec":     schema_apis_federation_pingcap_v1alpha1_VolumeBackupMemberSpec(ref),
@@ -93,6 +95,36 @@ func schema_apis_federation_pingcap_v1alpha1_BRConfig(ref common.ReferenceCallba
 	}
 }
 
+func schema_apis_federation_pingcap_v1alpha1_ExternalTSOService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExternalTSOService is a timestamp service the coordinated timestamp of a VolumeBackup is requested from",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL of the timestamp service, the response of a GET request to it must be a TSO in decimal, i.e. the physical time in milliseconds shifted left by 18 bits plus the logical counter.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout of the request. Optional: Defaults to 10s",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_apis_federation_pingcap_v1alpha1_VolumeBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -136,6 +168,40 @@ func schema_apis_federation_pingcap_v1alpha1_VolumeBackup(ref common.ReferenceCa
 	}
 }
 
+func schema_apis_federation_pingcap_v1alpha1_VolumeBackupCoordination(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VolumeBackupCoordination describes how the volume snapshots of the data planes are coordinated. The timestamp is taken after the GC and the PD schedulers are paused and before the volume snapshots of any data plane are taken, the commit ts, i.e. the resolved ts, of every backup member must not be earlier than it by more than the tolerated clock skew, otherwise the VolumeBackup fails.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"tsoSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TSOSource is the source of the coordinated timestamp. Optional: Defaults to ControlPlane",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"externalTSO": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExternalTSO is the external timestamp service, it's required if the TSO source is External.",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.ExternalTSOService"),
+						},
+					},
+					"maxClockSkew": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxClockSkew is the tolerated skew between the clock of the TSO source and the TSO of the TiDB cluster. Optional: Defaults to 1s",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.ExternalTSOService", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_apis_federation_pingcap_v1alpha1_VolumeBackupList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -484,6 +550,13 @@ func schema_apis_federation_pingcap_v1alpha1_VolumeBackupScheduleSpec(ref common
 							Format:      "",
 						},
 					},
+					"timeZone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeZone is the name of the time zone in the IANA time zone database, e.g. \"Asia/Shanghai\", in which the schedule is evaluated. The times skipped by the DST transitions are delayed until the transitions, and the times repeated are scheduled only once. The local time zone of the operator is used if empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"pause": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Pause means paused backupSchedule",
@@ -493,14 +566,21 @@ func schema_apis_federation_pingcap_v1alpha1_VolumeBackupScheduleSpec(ref common
 					},
 					"maxBackups": {
 						SchemaProps: spec.SchemaProps{
-							Description: "MaxBackups is to specify how many backups we want to keep 0 is magic number to indicate un-limited backups. if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred and MaxBackups is ignored.",
+							Description: "MaxBackups is to specify how many backups we want to keep 0 is magic number to indicate un-limited backups. if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred and MaxBackups is ignored unless GCPolicy is CountAndAge.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
 					"maxReservedTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "MaxReservedTime is to specify how long backups we want to keep.",
+							Description: "MaxReservedTime is to specify how long backups we want to keep. Besides the units supported by Go durations, the units of day \"d\" and week \"w\" are supported, e.g. \"7d\", \"2w\" or \"1d12h\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"gcPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GCPolicy specifies how MaxBackups and MaxReservedTime work together when both of them are set. \"PreferMaxReservedTime\" means MaxBackups is ignored. \"CountAndAge\" means a backup is kept if it is one of the newest MaxBackups backups or it is created within MaxReservedTime. Optional: Defaults to PreferMaxReservedTime",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -547,11 +627,17 @@ func schema_apis_federation_pingcap_v1alpha1_VolumeBackupSpec(ref common.Referen
 							Ref:     ref("github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupMemberSpec"),
 						},
 					},
+					"coordination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Coordination coordinates the volume snapshots of all the data planes at a timestamp chosen by the control plane or an external timestamp service, and verifies the commit ts of the backup members against it to make sure the backup is consistent at the timestamp. Optional: Defaults to nil, the backup is consistent at the minimal commit ts of the backup members",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupCoordination"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupMemberCluster", "github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupMemberSpec"},
+			"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupCoordination", "github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupMemberCluster", "github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1.VolumeBackupMemberSpec"},
 	}
 }
 
@@ -63,6 +63,54 @@ type VolumeBackupList struct {
 type VolumeBackupSpec struct {
 	Clusters []VolumeBackupMemberCluster `json:"clusters,omitempty"`
 	Template VolumeBackupMemberSpec      `json:"template,omitempty"`
+	// Coordination coordinates the volume snapshots of all the data planes at a timestamp chosen by the
+	// control plane or an external timestamp service, and verifies the commit ts of the backup members
+	// against it to make sure the backup is consistent at the timestamp.
+	// Optional: Defaults to nil, the backup is consistent at the minimal commit ts of the backup members
+	// +optional
+	Coordination *VolumeBackupCoordination `json:"coordination,omitempty"`
+}
+
+// VolumeBackupTSOSource is the source of the timestamp the volume snapshots are coordinated at
+type VolumeBackupTSOSource string
+
+const (
+	// VolumeBackupTSOSourceControlPlane means the timestamp is chosen by the clock of the control plane
+	VolumeBackupTSOSourceControlPlane VolumeBackupTSOSource = "ControlPlane"
+	// VolumeBackupTSOSourceExternal means the timestamp is requested from an external timestamp service
+	VolumeBackupTSOSourceExternal VolumeBackupTSOSource = "External"
+)
+
+// VolumeBackupCoordination describes how the volume snapshots of the data planes are coordinated.
+// The timestamp is taken after the GC and the PD schedulers are paused and before the volume snapshots
+// of any data plane are taken, the commit ts, i.e. the resolved ts, of every backup member must not be
+// earlier than it by more than the tolerated clock skew, otherwise the VolumeBackup fails.
+// +k8s:openapi-gen=true
+type VolumeBackupCoordination struct {
+	// TSOSource is the source of the coordinated timestamp.
+	// Optional: Defaults to ControlPlane
+	// +kubebuilder:validation:Enum=ControlPlane;External
+	// +optional
+	TSOSource VolumeBackupTSOSource `json:"tsoSource,omitempty"`
+	// ExternalTSO is the external timestamp service, it's required if the TSO source is External.
+	// +optional
+	ExternalTSO *ExternalTSOService `json:"externalTSO,omitempty"`
+	// MaxClockSkew is the tolerated skew between the clock of the TSO source and the TSO of the TiDB cluster.
+	// Optional: Defaults to 1s
+	// +optional
+	MaxClockSkew *metav1.Duration `json:"maxClockSkew,omitempty"`
+}
+
+// ExternalTSOService is a timestamp service the coordinated timestamp of a VolumeBackup is requested from
+// +k8s:openapi-gen=true
+type ExternalTSOService struct {
+	// URL of the timestamp service, the response of a GET request to it must be a TSO in decimal,
+	// i.e. the physical time in milliseconds shifted left by 18 bits plus the logical counter.
+	URL string `json:"url"`
+	// Timeout of the request.
+	// Optional: Defaults to 10s
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // VolumeBackupMemberCluster contains the TiDB cluster which need to execute volume backup
@@ -168,6 +216,9 @@ type VolumeBackupStatus struct {
 	BackupSize int64 `json:"backupSize,omitempty"`
 	// CommitTs is the commit ts of the backup, snapshot ts for full backup or start ts for log backup.
 	CommitTs string `json:"commitTs,omitempty"`
+	// CoordinatedTs is the timestamp the volume snapshots are coordinated at by spec.coordination.
+	// +optional
+	CoordinatedTs string `json:"coordinatedTs,omitempty"`
 	// Phase is a user readable state inferred from the underlying Backup conditions
 	Phase VolumeBackupConditionType `json:"phase,omitempty"`
 	// +nullable
@@ -266,19 +317,44 @@ type VolumeBackupScheduleList struct {
 type VolumeBackupScheduleSpec struct {
 	// Schedule specifies the cron string used for backup scheduling.
 	Schedule string `json:"schedule"`
+	// TimeZone is the name of the time zone in the IANA time zone database, e.g. "Asia/Shanghai", in which
+	// the schedule is evaluated. The times skipped by the DST transitions are delayed until the transitions,
+	// and the times repeated are scheduled only once. The local time zone of the operator is used if empty.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 	// Pause means paused backupSchedule
 	Pause bool `json:"pause,omitempty"`
 	// MaxBackups is to specify how many backups we want to keep
 	// 0 is magic number to indicate un-limited backups.
 	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred
-	// and MaxBackups is ignored.
+	// and MaxBackups is ignored unless GCPolicy is CountAndAge.
 	MaxBackups *int32 `json:"maxBackups,omitempty"`
 	// MaxReservedTime is to specify how long backups we want to keep.
+	// Besides the units supported by Go durations, the units of day "d" and week "w"
+	// are supported, e.g. "7d", "2w" or "1d12h".
 	MaxReservedTime *string `json:"maxReservedTime,omitempty"`
+	// GCPolicy specifies how MaxBackups and MaxReservedTime work together when both of them are set.
+	// "PreferMaxReservedTime" means MaxBackups is ignored.
+	// "CountAndAge" means a backup is kept if it is one of the newest MaxBackups backups or
+	// it is created within MaxReservedTime.
+	// Optional: Defaults to PreferMaxReservedTime
+	// +optional
+	// +kubebuilder:validation:Enum=PreferMaxReservedTime;CountAndAge
+	GCPolicy VolumeBackupScheduleGCPolicy `json:"gcPolicy,omitempty"`
 	// BackupTemplate is the specification of the volume backup structure to get scheduled.
 	BackupTemplate VolumeBackupSpec `json:"backupTemplate"`
 }
 
+// VolumeBackupScheduleGCPolicy represents how the backups of a volume backup schedule are GCed.
+type VolumeBackupScheduleGCPolicy string
+
+const (
+	// VolumeBackupScheduleGCPolicyPreferMaxReservedTime means only MaxReservedTime is respected if it is set
+	VolumeBackupScheduleGCPolicyPreferMaxReservedTime VolumeBackupScheduleGCPolicy = "PreferMaxReservedTime"
+	// VolumeBackupScheduleGCPolicyCountAndAge means a backup is GCed only if it exceeds both MaxBackups and MaxReservedTime
+	VolumeBackupScheduleGCPolicyCountAndAge VolumeBackupScheduleGCPolicy = "CountAndAge"
+)
+
 // VolumeBackupScheduleStatus represents the current status of a volume backup schedule.
 type VolumeBackupScheduleStatus struct {
 	// LastBackup represents the last backup.
@@ -287,8 +363,49 @@ type VolumeBackupScheduleStatus struct {
 	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
 	// AllBackupCleanTime represents the time when all backup entries are cleaned up
 	AllBackupCleanTime *metav1.Time `json:"allBackupCleanTime,omitempty"`
+	// GCProgress represents the progress of the backup being GCed in the data planes.
+	// A backup is GCed only when all the data planes confirm that it can be deleted.
+	// +optional
+	GCProgress *VolumeBackupGCProgress `json:"gcProgress,omitempty"`
+}
+
+// VolumeBackupGCProgress represents the progress of a backup being GCed.
+type VolumeBackupGCProgress struct {
+	// BackupName is the name of the VolumeBackup being GCed
+	BackupName string `json:"backupName"`
+	// DataPlanes are the GC progress of the backup in every data plane
+	DataPlanes []VolumeBackupDataPlaneGCStatus `json:"dataPlanes,omitempty"`
+}
+
+// VolumeBackupDataPlaneGCStatus represents the GC progress of a backup in a data plane.
+type VolumeBackupDataPlaneGCStatus struct {
+	// K8sClusterName is the name of the k8s cluster of the data plane
+	K8sClusterName string `json:"k8sClusterName"`
+	// BackupName is the name of the Backup CR in the data plane
+	BackupName string `json:"backupName,omitempty"`
+	// Phase is the GC phase of the backup in the data plane
+	Phase VolumeBackupDataPlaneGCPhase `json:"phase"`
+	// Message is the reason why the data plane doesn't confirm the deletion or the deletion failed
+	Message string `json:"message,omitempty"`
 }
 
+// VolumeBackupDataPlaneGCPhase represents the GC phase of a backup in a data plane.
+type VolumeBackupDataPlaneGCPhase string
+
+const (
+	// VolumeBackupDataPlaneGCUnconfirmed means the data plane can't confirm that the backup can be deleted,
+	// e.g. the data plane is unreachable or the backup is still running
+	VolumeBackupDataPlaneGCUnconfirmed VolumeBackupDataPlaneGCPhase = "Unconfirmed"
+	// VolumeBackupDataPlaneGCConfirmed means the data plane confirms that the backup can be deleted
+	VolumeBackupDataPlaneGCConfirmed VolumeBackupDataPlaneGCPhase = "Confirmed"
+	// VolumeBackupDataPlaneGCDeleting means the backup is being deleted in the data plane
+	VolumeBackupDataPlaneGCDeleting VolumeBackupDataPlaneGCPhase = "Deleting"
+	// VolumeBackupDataPlaneGCDeleted means the backup has been deleted in the data plane
+	VolumeBackupDataPlaneGCDeleted VolumeBackupDataPlaneGCPhase = "Deleted"
+	// VolumeBackupDataPlaneGCFailed means the backup failed to be cleaned in the data plane
+	VolumeBackupDataPlaneGCFailed VolumeBackupDataPlaneGCPhase = "Failed"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
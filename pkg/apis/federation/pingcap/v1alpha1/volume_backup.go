@@ -16,6 +16,7 @@ package v1alpha1
 import (
 	"sort"
 	"strings"
+	"time"
 
 	pingcapv1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -48,6 +49,37 @@ func (vb *VolumeBackup) GetCombinedTCName() string {
 	return strings.Join(tcNames, ",")
 }
 
+const (
+	// defaultMaxClockSkew is the default tolerated skew between the clock of the TSO source and the TSO of the TiDB cluster
+	defaultMaxClockSkew = time.Second
+	// defaultExternalTSOTimeout is the default timeout of the requests to the external timestamp service
+	defaultExternalTSOTimeout = 10 * time.Second
+)
+
+// GetTSOSource returns the source of the coordinated timestamp
+func (c *VolumeBackupCoordination) GetTSOSource() VolumeBackupTSOSource {
+	if c.TSOSource == "" {
+		return VolumeBackupTSOSourceControlPlane
+	}
+	return c.TSOSource
+}
+
+// GetMaxClockSkew returns the tolerated skew between the clock of the TSO source and the TSO of the TiDB cluster
+func (c *VolumeBackupCoordination) GetMaxClockSkew() time.Duration {
+	if c.MaxClockSkew == nil {
+		return defaultMaxClockSkew
+	}
+	return c.MaxClockSkew.Duration
+}
+
+// GetTimeout returns the timeout of the requests to the external timestamp service
+func (s *ExternalTSOService) GetTimeout() time.Duration {
+	if s.Timeout == nil {
+		return defaultExternalTSOTimeout
+	}
+	return s.Timeout.Duration
+}
+
 // UpdateVolumeBackupCondition adds new condition or update condition if it exists in status
 func UpdateVolumeBackupCondition(volumeBackupStatus *VolumeBackupStatus, condition *VolumeBackupCondition) {
 	condition.LastTransitionTime = metav1.Now()
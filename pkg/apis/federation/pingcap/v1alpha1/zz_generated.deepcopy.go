@@ -19,7 +19,8 @@
 package v1alpha1
 
 import (
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -59,6 +60,27 @@ func (in *BRConfig) DeepCopy() *BRConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalTSOService) DeepCopyInto(out *ExternalTSOService) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalTSOService.
+func (in *ExternalTSOService) DeepCopy() *ExternalTSOService {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalTSOService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeBackup) DeepCopyInto(out *VolumeBackup) {
 	*out = *in
@@ -104,6 +126,69 @@ func (in *VolumeBackupCondition) DeepCopy() *VolumeBackupCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeBackupCoordination) DeepCopyInto(out *VolumeBackupCoordination) {
+	*out = *in
+	if in.ExternalTSO != nil {
+		in, out := &in.ExternalTSO, &out.ExternalTSO
+		*out = new(ExternalTSOService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxClockSkew != nil {
+		in, out := &in.MaxClockSkew, &out.MaxClockSkew
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeBackupCoordination.
+func (in *VolumeBackupCoordination) DeepCopy() *VolumeBackupCoordination {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeBackupCoordination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeBackupDataPlaneGCStatus) DeepCopyInto(out *VolumeBackupDataPlaneGCStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeBackupDataPlaneGCStatus.
+func (in *VolumeBackupDataPlaneGCStatus) DeepCopy() *VolumeBackupDataPlaneGCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeBackupDataPlaneGCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeBackupGCProgress) DeepCopyInto(out *VolumeBackupGCProgress) {
+	*out = *in
+	if in.DataPlanes != nil {
+		in, out := &in.DataPlanes, &out.DataPlanes
+		*out = make([]VolumeBackupDataPlaneGCStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeBackupGCProgress.
+func (in *VolumeBackupGCProgress) DeepCopy() *VolumeBackupGCProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeBackupGCProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeBackupList) DeepCopyInto(out *VolumeBackupList) {
 	*out = *in
@@ -159,7 +244,7 @@ func (in *VolumeBackupMemberSpec) DeepCopyInto(out *VolumeBackupMemberSpec) {
 	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -172,26 +257,26 @@ func (in *VolumeBackupMemberSpec) DeepCopyInto(out *VolumeBackupMemberSpec) {
 	in.StorageProvider.DeepCopyInto(&out.StorageProvider)
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
+		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.AdditionalVolumes != nil {
 		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = make([]v1.Volume, len(*in))
+		*out = make([]corev1.Volume, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.AdditionalVolumeMounts != nil {
 		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
+		*out = make([]corev1.VolumeMount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -324,6 +409,11 @@ func (in *VolumeBackupScheduleStatus) DeepCopyInto(out *VolumeBackupScheduleStat
 		in, out := &in.AllBackupCleanTime, &out.AllBackupCleanTime
 		*out = (*in).DeepCopy()
 	}
+	if in.GCProgress != nil {
+		in, out := &in.GCProgress, &out.GCProgress
+		*out = new(VolumeBackupGCProgress)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -346,6 +436,11 @@ func (in *VolumeBackupSpec) DeepCopyInto(out *VolumeBackupSpec) {
 		copy(*out, *in)
 	}
 	in.Template.DeepCopyInto(&out.Template)
+	if in.Coordination != nil {
+		in, out := &in.Coordination, &out.Coordination
+		*out = new(VolumeBackupCoordination)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -507,7 +602,7 @@ func (in *VolumeRestoreMemberSpec) DeepCopyInto(out *VolumeRestoreMemberSpec) {
 	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -519,26 +614,26 @@ func (in *VolumeRestoreMemberSpec) DeepCopyInto(out *VolumeRestoreMemberSpec) {
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
+		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.AdditionalVolumes != nil {
 		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = make([]v1.Volume, len(*in))
+		*out = make([]corev1.Volume, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.AdditionalVolumeMounts != nil {
 		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
+		*out = make([]corev1.VolumeMount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
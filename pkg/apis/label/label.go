@@ -40,6 +40,8 @@ const (
 	// UsedByLabelKey indicate where it is used. for example, tidb has two services,
 	// one for internal component access and the other for end-user
 	UsedByLabelKey string = "app.kubernetes.io/used-by"
+	// TiDBServiceLabelKey is the label key of the named client services of TiDB, its value is the name of the service in spec
+	TiDBServiceLabelKey string = "tidb.pingcap.com/tidb-service"
 	// ClusterIDLabelKey is cluster id label key
 	ClusterIDLabelKey string = "tidb.pingcap.com/cluster-id"
 	// StoreIDLabelKey is store id label key
@@ -47,6 +49,11 @@ const (
 	// MemberIDLabelKey is member id label key
 	MemberIDLabelKey string = "tidb.pingcap.com/member-id"
 
+	// VeleroExcludeFromBackupLabelKey is the label key to exclude a resource from the Velero backups
+	VeleroExcludeFromBackupLabelKey string = "velero.io/exclude-from-backup"
+	// VeleroRestoreNameLabelKey is the label key set by Velero on the restored resources
+	VeleroRestoreNameLabelKey string = "velero.io/restore-name"
+
 	// InitLabelKey is the key for TiDB initializer
 	InitLabelKey string = "tidb.pingcap.com/initializer"
 
@@ -70,6 +77,17 @@ const (
 	// VolumeRestoreFederationFinalizer is the name of finalizer on federation restores
 	VolumeRestoreFederationFinalizer string = "tidb.pingcap.com/restore-protection"
 
+	// ClusterDeletionFinalizer is the name of finalizer on TidbClusters and DMClusters to carry out
+	// the deletion policies of the clusters
+	ClusterDeletionFinalizer string = "tidb.pingcap.com/cluster-deletion"
+
+	// AccountProtectionFinalizer is the name of finalizer on TidbAccounts to drop the users
+	// when the TidbAccounts with the Delete reclaim policy are deleted
+	AccountProtectionFinalizer string = "tidb.pingcap.com/account-protection"
+
+	// RetainedLabelKey is the label key of the PVCs retained by the deletion policy of a deleted cluster
+	RetainedLabelKey string = "tidb.pingcap.com/retained"
+
 	// AnnHATopologyKey defines the High availability topology key
 	AnnHATopologyKey = "pingcap.com/ha-topology-key"
 
@@ -163,6 +181,73 @@ const (
 	// Listed from store status, but has a store id in label. This is an alternate way to detect tombstone stores.
 	AnnTiKVNoActiveStoreSince = "tidb.pingcap.com/tikv-no-active-store-since"
 
+	// AnnSelfTestKey is tc annotation key to request a non-destructive self-test of the tidb cluster,
+	// the self-test runs once for each distinct value and its report is written to the tc status.
+	AnnSelfTestKey = "tidb.pingcap.com/self-test"
+
+	// AnnProfileCaptureKey is tc annotation key to request a capture of the profile described by spec.profileCapture,
+	// the profile is captured once for each distinct value and saved to the storage.
+	AnnProfileCaptureKey = "tidb.pingcap.com/profile-capture"
+
+	// AnnTiKVMasterKey is the pod annotation key of the fingerprint of the master key TiKV encrypts the data keys with,
+	// the pods of TiKV are rolling restarted when the master key is rotated.
+	AnnTiKVMasterKey = "tidb.pingcap.com/tikv-master-key"
+
+	// AnnBackupNowKey is backup schedule annotation key to request a backup out of the schedule,
+	// the annotation is removed once the backup is created.
+	AnnBackupNowKey = "tidb.pingcap.com/backup-now"
+
+	// AnnPropagatedLabelsKey and AnnPropagatedAnnotationsKey are annotation keys of the resources of a tidb
+	// cluster to record the keys of the labels and annotations propagated from the tidb cluster, so that
+	// they can be removed when they are removed from the tidb cluster.
+	AnnPropagatedLabelsKey      = "tidb.pingcap.com/propagated-labels"
+	AnnPropagatedAnnotationsKey = "tidb.pingcap.com/propagated-annotations"
+
+	// AnnVeleroRestoreKey is annotation key of the resources restored by Velero to record the name of
+	// the restore they have been reconciled for
+	AnnVeleroRestoreKey = "tidb.pingcap.com/velero-restore"
+
+	// AnnSkipPDDeletionProtectionKey is pd pod annotation key to skip the check of the pod admission webhook
+	// which denies deleting the pd pods that hold the leadership or whose deletion breaks the quorum
+	AnnSkipPDDeletionProtectionKey = "tidb.pingcap.com/skip-pd-deletion-protection"
+
+	// AnnForceContinueUpgradeKey is tc annotation key to force the upgrade blocked on an unhealthy member to
+	// continue, the value must be the id of the stuck upgrade report published in the tc status.
+	AnnForceContinueUpgradeKey = "tidb.pingcap.com/force-continue-upgrade"
+	// AnnForceContinueUpgradeByKey is tc annotation key set by the admission webhook to record the user
+	// who set the AnnForceContinueUpgradeKey annotation
+	AnnForceContinueUpgradeByKey = "tidb.pingcap.com/force-continue-upgrade-by"
+
+	// AnnRetainedFromKey is PVC annotation key to record the uid of the deleted cluster the PVC is retained from
+	AnnRetainedFromKey = "tidb.pingcap.com/retained-from"
+	// AnnForceDeleteKey is tc and dc annotation key to skip the remaining steps of the deletion policy
+	// when the cluster is being deleted, the value must be "true"
+	AnnForceDeleteKey = "tidb.pingcap.com/force-delete"
+	// AnnResyncDurationKey is tc and dc annotation key to override the resync time of the cluster,
+	// e.g. 30s for a cluster under change or 10m for a stable one
+	AnnResyncDurationKey = "tidb.pingcap.com/resync-duration"
+
+	// AnnRestartAtKey is pod annotation key set in spec.annotations or the annotations of a component to schedule
+	// a rolling restart of the pods at the time in RFC3339 format, the pods are restarted one by one with the same
+	// safety checks of an upgrade, e.g. the leader eviction of TiKV, once the time is reached. It's not supported
+	// by DMCluster
+	AnnRestartAtKey = "tidb.pingcap.com/restart-at"
+
+	// AnnFencingTokenKey is the annotation key of the fencing token of the operator leader stamped into the
+	// statefulsets it updates, so that the late updates of a deposed leader with a less token are rejected
+	AnnFencingTokenKey = "tidb.pingcap.com/fencing-token"
+
+	// AnnConfigBackupReasonKey is the annotation key of the config backups to record the destructive change
+	// the backup is taken before, e.g. rolling-restart, scale-in or volume-replace
+	AnnConfigBackupReasonKey = "tidb.pingcap.com/config-backup-reason"
+	// AnnConfigBackupDigestKey is the annotation key of the config backups to record the digest of the
+	// backed up content, so that the same content is not backed up again
+	AnnConfigBackupDigestKey = "tidb.pingcap.com/config-backup-digest"
+
+	// AnnBackupThrottleOriginalThreadsKey is tc annotation key to record the backup threads of TiKV before
+	// the throttled backups of the cluster change them, which are set back when the last of them finishes
+	AnnBackupThrottleOriginalThreadsKey = "tidb.pingcap.com/backup-throttle-original-threads"
+
 	// PDLabelVal is PD label value
 	PDLabelVal string = "pd"
 	// PDMSTSOLabelVal is pd microservice tso member type
@@ -173,6 +258,8 @@ const (
 	TiDBLabelVal string = "tidb"
 	// TiKVLabelVal is TiKV label value
 	TiKVLabelVal string = "tikv"
+	// TiKVWitnessLabelVal is TiKV witness label value
+	TiKVWitnessLabelVal string = "tikv-witness"
 	// TiFlashLabelVal is TiFlash label value
 	TiFlashLabelVal string = "tiflash"
 	// TiCDCLabelVal is TiCDC label value
@@ -191,6 +278,8 @@ const (
 
 	// CleanJobLabelVal is clean job label value
 	CleanJobLabelVal string = "clean"
+	// TransitionJobLabelVal is storage class transition job label value
+	TransitionJobLabelVal string = "transition"
 	// RestoreJobLabelVal is restore job label value
 	RestoreJobLabelVal string = "restore"
 	// RestoreWarmUpJobLabelVal is restore warmup job label value
@@ -199,6 +288,8 @@ const (
 	BackupJobLabelVal string = "backup"
 	// BackupScheduleJobLabelVal is backup schedule job label value
 	BackupScheduleJobLabelVal string = "backup-schedule"
+	// ObjectStoreLabelVal is the label value of the in-cluster object store for backup and restore
+	ObjectStoreLabelVal string = "object-store"
 	// InitJobLabelVal is TiDB initializer job label value
 	InitJobLabelVal string = "initializer"
 	// TiDBOperator is ManagedByLabelKey label value
@@ -353,6 +444,12 @@ func (l Label) UsedByEndUser() Label {
 	return l
 }
 
+// TiDBService adds the name of the named client service of TiDB to label
+func (l Label) TiDBService(name string) Label {
+	l[TiDBServiceLabelKey] = name
+	return l
+}
+
 // Namespace adds namespace kv pair to label
 func (l Label) Namespace(name string) Label {
 	l[NamespaceLabelKey] = name
@@ -387,6 +484,11 @@ func (l Label) CleanJob() Label {
 	return l.Component(CleanJobLabelVal)
 }
 
+// TransitionJob assigns transition to component key in label
+func (l Label) TransitionJob() Label {
+	return l.Component(TransitionJobLabelVal)
+}
+
 // BackupJob assigns backup to component key in label
 func (l Label) BackupJob() Label {
 	return l.Component(BackupJobLabelVal)
@@ -535,6 +637,11 @@ func (l Label) TiKV() Label {
 	return l.Component(TiKVLabelVal)
 }
 
+// TiKVWitness assigns tikv-witness to component key in label
+func (l Label) TiKVWitness() Label {
+	return l.Component(TiKVWitnessLabelVal)
+}
+
 // IsTiKV returns whether label is a TiKV component
 func (l Label) IsTiKV() bool {
 	return l[ComponentLabelKey] == TiKVLabelVal
@@ -15,6 +15,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
@@ -42,6 +43,23 @@ func (bk *Backup) GetCleanJobName() string {
 	return fmt.Sprintf("clean-%s", bk.GetName())
 }
 
+// GetStorageClassTransitionJobName return the job name to transition the storage class of the backup data,
+// the storage class is included so that the data can be transitioned again after the storage class is changed
+func (bk *Backup) GetStorageClassTransitionJobName(storageClass string) string {
+	return fmt.Sprintf("transition-%s-%s", bk.GetName(), strings.ReplaceAll(strings.ToLower(storageClass), "_", "-"))
+}
+
+// IsArchiveStorageClass returns whether the objects in the storage class must be restored or rehydrated
+// before they can be read, the backup data in such a storage class can't be restored by BR
+func IsArchiveStorageClass(storageClass string) bool {
+	for _, sc := range []string{"GLACIER", "DEEP_ARCHIVE", "Archive"} {
+		if strings.EqualFold(storageClass, sc) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCleanJobName return the clean job name for log backup
 func (bk *Backup) GetStopLogBackupJobName() string {
 	return fmt.Sprintf("stop-%s", bk.GetName())
@@ -317,8 +335,10 @@ func IsCleanCandidate(backup *Backup) bool {
 }
 
 // NeedRetainData returns true if a Backup need not to be cleaned up according to cleanPolicy
+// or the backup is protected from deletion
 func NeedRetainData(backup *Backup) bool {
-	return backup.Spec.CleanPolicy == CleanPolicyTypeRetain ||
+	return backup.Spec.DeletionProtection ||
+		backup.Spec.CleanPolicy == CleanPolicyTypeRetain ||
 		(backup.Spec.CleanPolicy == CleanPolicyTypeOnFailure && !IsBackupFailed(backup))
 }
 
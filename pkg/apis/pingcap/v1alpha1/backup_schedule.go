@@ -15,6 +15,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -29,3 +30,78 @@ func (bs *BackupSchedule) GetLogBackupCRDName() string {
 func (bs *BackupSchedule) GetCompactBackupCRDName(timestamp time.Time) string {
 	return fmt.Sprintf("%s-%s-%s", "compact", bs.GetName(), timestamp.UTC().Format(BackupNameTimeFormat))
 }
+
+// GetMissedRunPolicy returns the missed run policy of the backup schedule, defaults to RunOnce.
+func (bs *BackupSchedule) GetMissedRunPolicy() MissedRunPolicy {
+	if bs.Spec.MissedRunPolicy == "" {
+		return MissedRunPolicyRunOnce
+	}
+	return bs.Spec.MissedRunPolicy
+}
+
+// GetPauseMode returns what is paused by the backup schedule, it's empty if the schedule
+// is not paused, and defaults to Snapshot if the schedule is paused.
+func (bs *BackupSchedule) GetPauseMode() BackupSchedulePauseMode {
+	if !bs.Spec.Pause {
+		return ""
+	}
+	if bs.Spec.PauseMode == "" {
+		return BackupSchedulePauseModeSnapshot
+	}
+	return bs.Spec.PauseMode
+}
+
+// IsSnapshotBackupPaused returns whether the snapshot backups of the schedule are paused.
+func (bs *BackupSchedule) IsSnapshotBackupPaused() bool {
+	mode := bs.GetPauseMode()
+	return mode == BackupSchedulePauseModeSnapshot || mode == BackupSchedulePauseModeAll
+}
+
+// IsLogBackupPaused returns whether the log backup of the schedule should be paused.
+func (bs *BackupSchedule) IsLogBackupPaused() bool {
+	mode := bs.GetPauseMode()
+	return mode == BackupSchedulePauseModeLog || mode == BackupSchedulePauseModeAll
+}
+
+// ParseCompactWindow parses a window of the compact schedule in the format of "HH:MM-HH:MM",
+// and returns the start and the end of the window as the offsets from the midnight.
+func ParseCompactWindow(window string) (start, end time.Duration, err error) {
+	parts := strings.Split(window, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("window %q is not in the format of HH:MM-HH:MM", window)
+	}
+	var offsets [2]time.Duration
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, fmt.Errorf("window %q is not in the format of HH:MM-HH:MM: %v", window, err)
+		}
+		offsets[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if offsets[0] == offsets[1] {
+		return 0, 0, fmt.Errorf("window %q is empty", window)
+	}
+	return offsets[0], offsets[1], nil
+}
+
+// InCompactWindows returns whether the time is in one of the windows of the compact schedule,
+// the windows ending before their start span the midnight. It's always true if there is no window.
+func (cs *CompactSchedule) InCompactWindows(t time.Time) bool {
+	if len(cs.Windows) == 0 {
+		return true
+	}
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	for _, window := range cs.Windows {
+		start, end, err := ParseCompactWindow(window)
+		if err != nil {
+			continue
+		}
+		if start < end && offset >= start && offset < end {
+			return true
+		}
+		if start > end && (offset >= start || offset < end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestInCompactWindows(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	cs := &CompactSchedule{}
+	g.Expect(cs.InCompactWindows(at(12, 0))).To(BeTrue())
+
+	cs.Windows = []string{"22:00-06:00", "12:00-13:30"}
+	g.Expect(cs.InCompactWindows(at(23, 0))).To(BeTrue())
+	g.Expect(cs.InCompactWindows(at(0, 0))).To(BeTrue())
+	g.Expect(cs.InCompactWindows(at(5, 59))).To(BeTrue())
+	g.Expect(cs.InCompactWindows(at(6, 0))).To(BeFalse())
+	g.Expect(cs.InCompactWindows(at(12, 0))).To(BeTrue())
+	g.Expect(cs.InCompactWindows(at(13, 30))).To(BeFalse())
+	g.Expect(cs.InCompactWindows(at(21, 59))).To(BeFalse())
+
+	_, _, err := ParseCompactWindow("06:00-06:00")
+	g.Expect(err).To(HaveOccurred())
+	_, _, err = ParseCompactWindow("6am-7am")
+	g.Expect(err).To(HaveOccurred())
+	start, end, err := ParseCompactWindow("01:30-02:00")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(start).To(Equal(90 * time.Minute))
+	g.Expect(end).To(Equal(2 * time.Hour))
+}
@@ -21,7 +21,8 @@ import (
 )
 
 const (
-	defaultHostNetwork = false
+	defaultHostNetwork         = false
+	defaultInjectResourceHints = false
 )
 
 var (
@@ -57,17 +58,22 @@ type ComponentAccessor interface {
 	HostNetwork() bool
 	Affinity() *corev1.Affinity
 	PriorityClassName() *string
+	PreemptionPolicy() *corev1.PreemptionPolicy
+	RuntimeClassName() *string
 	NodeSelector() map[string]string
 	Labels() map[string]string
 	Annotations() map[string]string
 	Tolerations() []corev1.Toleration
 	PodSecurityContext() *corev1.PodSecurityContext
 	SchedulerName() string
+	SchedulingGates() []corev1.PodSchedulingGate
 	DnsPolicy() corev1.DNSPolicy
+	HostAliases() []corev1.HostAlias
 	ConfigUpdateStrategy() ConfigUpdateStrategy
 	BuildPodSpec() corev1.PodSpec
 	Env() []corev1.EnvVar
 	EnvFrom() []corev1.EnvFromSource
+	AdditionalArgs() []string
 	AdditionalContainers() []corev1.Container
 	InitContainers() []corev1.Container
 	AdditionalVolumes() []corev1.Volume
@@ -77,6 +83,7 @@ type ComponentAccessor interface {
 	PodManagementPolicy() apps.PodManagementPolicyType
 	TopologySpreadConstraints() []corev1.TopologySpreadConstraint
 	SuspendAction() *SuspendAction
+	InjectResourceHints() bool
 }
 
 func (tc *TidbCluster) AllComponentSpec() []ComponentAccessor {
@@ -166,18 +173,23 @@ type componentAccessorImpl struct {
 	affinity                  *corev1.Affinity
 	priorityClassName         *string
 	schedulerName             string
+	schedulingGates           []corev1.PodSchedulingGate
 	clusterNodeSelector       map[string]string
 	clusterAnnotations        map[string]string
 	clusterLabels             map[string]string
 	tolerations               []corev1.Toleration
 	dnsConfig                 *corev1.PodDNSConfig
 	dnsPolicy                 corev1.DNSPolicy
+	hostAliases               []corev1.HostAlias
+	preemptionPolicy          *corev1.PreemptionPolicy
+	runtimeClassName          *string
 	configUpdateStrategy      ConfigUpdateStrategy
 	statefulSetUpdateStrategy apps.StatefulSetUpdateStrategyType
 	podManagementPolicy       apps.PodManagementPolicyType
 	podSecurityContext        *corev1.PodSecurityContext
 	topologySpreadConstraints []TopologySpreadConstraint
 	suspendAction             *SuspendAction
+	injectResourceHints       *bool
 
 	// ComponentSpec is the Component Spec
 	ComponentSpec *ComponentSpec
@@ -244,10 +256,63 @@ func (a *componentAccessorImpl) HostNetwork() bool {
 }
 
 func (a *componentAccessorImpl) Affinity() *corev1.Affinity {
-	if a.ComponentSpec == nil || a.ComponentSpec.Affinity == nil {
-		return a.affinity
+	affinity := a.affinity
+	if a.ComponentSpec != nil && a.ComponentSpec.Affinity != nil {
+		affinity = a.ComponentSpec.Affinity
+	}
+	if a.ComponentSpec == nil || a.ComponentSpec.AffinityPreset == nil {
+		return affinity
+	}
+	return a.withAffinityPreset(affinity, *a.ComponentSpec.AffinityPreset)
+}
+
+// withAffinityPreset appends the pod anti-affinity rule of the preset to a copy of the affinity,
+// the label selector of the rule matches the pods of the same component in the same cluster.
+func (a *componentAccessorImpl) withAffinityPreset(affinity *corev1.Affinity, preset AffinityPreset) *corev1.Affinity {
+	l := a.newLabel()
+	l[label.ComponentLabelKey] = getComponentLabelValue(a.component)
+	l[label.InstanceLabelKey] = a.name
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string(l),
+		},
+		TopologyKey: corev1.LabelHostname,
+	}
+
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	} else {
+		affinity = affinity.DeepCopy()
+	}
+	if affinity.PodAntiAffinity == nil {
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	anti := affinity.PodAntiAffinity
+	switch preset {
+	case AffinityPresetRequiredPerNode:
+		anti.RequiredDuringSchedulingIgnoredDuringExecution = append(anti.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	case AffinityPresetPreferredPerNode:
+		anti.PreferredDuringSchedulingIgnoredDuringExecution = append(anti.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+	case AffinityPresetRequiredPerZone:
+		term.TopologyKey = corev1.LabelTopologyZone
+		anti.RequiredDuringSchedulingIgnoredDuringExecution = append(anti.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	}
+	return affinity
+}
+
+// newLabel returns the label of the pods of the kind of object the component belongs to
+func (a *componentAccessorImpl) newLabel() label.Label {
+	switch a.kind {
+	case DMClusterKind:
+		return label.NewDM()
+	case TiDBNGMonitoringKind:
+		return label.NewTiDBNGMonitoring()
+	case TiDBDashboardKind:
+		return label.NewTiDBDashboard()
+	default:
+		return label.New()
 	}
-	return a.ComponentSpec.Affinity
 }
 
 func (a *componentAccessorImpl) PriorityClassName() *string {
@@ -257,6 +322,20 @@ func (a *componentAccessorImpl) PriorityClassName() *string {
 	return a.ComponentSpec.PriorityClassName
 }
 
+func (a *componentAccessorImpl) PreemptionPolicy() *corev1.PreemptionPolicy {
+	if a.ComponentSpec == nil || a.ComponentSpec.PreemptionPolicy == nil {
+		return a.preemptionPolicy
+	}
+	return a.ComponentSpec.PreemptionPolicy
+}
+
+func (a *componentAccessorImpl) RuntimeClassName() *string {
+	if a.ComponentSpec == nil || a.ComponentSpec.RuntimeClassName == nil {
+		return a.runtimeClassName
+	}
+	return a.ComponentSpec.RuntimeClassName
+}
+
 func (a *componentAccessorImpl) SchedulerName() string {
 	if a.ComponentSpec == nil || a.ComponentSpec.SchedulerName == nil {
 		return a.schedulerName
@@ -264,6 +343,13 @@ func (a *componentAccessorImpl) SchedulerName() string {
 	return *a.ComponentSpec.SchedulerName
 }
 
+func (a *componentAccessorImpl) SchedulingGates() []corev1.PodSchedulingGate {
+	if a.ComponentSpec == nil || len(a.ComponentSpec.SchedulingGates) == 0 {
+		return a.schedulingGates
+	}
+	return a.ComponentSpec.SchedulingGates
+}
+
 func (a *componentAccessorImpl) NodeSelector() map[string]string {
 	sel := map[string]string{}
 	for k, v := range a.clusterNodeSelector {
@@ -332,6 +418,13 @@ func (a *componentAccessorImpl) DNSConfig() *corev1.PodDNSConfig {
 	return a.ComponentSpec.DNSConfig
 }
 
+func (a *componentAccessorImpl) HostAliases() []corev1.HostAlias {
+	if a.ComponentSpec == nil || len(a.ComponentSpec.HostAliases) == 0 {
+		return a.hostAliases
+	}
+	return a.ComponentSpec.HostAliases
+}
+
 func (a *componentAccessorImpl) ConfigUpdateStrategy() ConfigUpdateStrategy {
 	// defaulting logic will set a default value for configUpdateStrategy field, but if the
 	// object is created in early version without this field being set, we should set a safe default
@@ -359,6 +452,10 @@ func (a *componentAccessorImpl) BuildPodSpec() corev1.PodSpec {
 		TopologySpreadConstraints: a.TopologySpreadConstraints(),
 		DNSPolicy:                 a.DnsPolicy(),
 		DNSConfig:                 a.DNSConfig(),
+		HostAliases:               a.HostAliases(),
+		PreemptionPolicy:          a.PreemptionPolicy(),
+		RuntimeClassName:          a.RuntimeClassName(),
+		SchedulingGates:           a.SchedulingGates(),
 	}
 	if a.PriorityClassName() != nil {
 		spec.PriorityClassName = *a.PriorityClassName()
@@ -386,6 +483,13 @@ func (a *componentAccessorImpl) EnvFrom() []corev1.EnvFromSource {
 	return a.ComponentSpec.EnvFrom
 }
 
+func (a *componentAccessorImpl) AdditionalArgs() []string {
+	if a.ComponentSpec == nil {
+		return nil
+	}
+	return a.ComponentSpec.AdditionalArgs
+}
+
 func (a *componentAccessorImpl) InitContainers() []corev1.Container {
 	if a.ComponentSpec == nil {
 		return nil
@@ -445,13 +549,7 @@ func (a *componentAccessorImpl) TopologySpreadConstraints() []corev1.TopologySpr
 		}
 
 		componentLabelVal := getComponentLabelValue(a.component)
-		var l label.Label
-		switch a.kind {
-		case TiDBClusterKind:
-			l = label.New()
-		case DMClusterKind:
-			l = label.NewDM()
-		}
+		l := a.newLabel()
 		if v, ok := tsc.MatchLabels[label.ComponentLabelKey]; ok {
 			componentLabelVal = v
 		}
@@ -477,6 +575,16 @@ func (a *componentAccessorImpl) SuspendAction() *SuspendAction {
 	return action
 }
 
+func (a *componentAccessorImpl) InjectResourceHints() bool {
+	if a.ComponentSpec == nil || a.ComponentSpec.InjectResourceHints == nil {
+		if a.injectResourceHints == nil {
+			return defaultInjectResourceHints
+		}
+		return *a.injectResourceHints
+	}
+	return *a.ComponentSpec.InjectResourceHints
+}
+
 func getComponentLabelValue(c MemberType) string {
 	switch c {
 	case PDMemberType:
@@ -507,6 +615,8 @@ func getComponentLabelValue(c MemberType) string {
 		return label.DMWorkerLabelVal
 	case NGMonitoringMemberType:
 		return label.NGMonitorLabelVal
+	case TiDBDashboardMemberType:
+		return label.TiDBDashboardLabelVal
 	}
 	return ""
 }
@@ -522,19 +632,24 @@ func buildTidbClusterComponentAccessor(c MemberType, tc *TidbCluster, componentS
 		hostNetwork:               spec.HostNetwork,
 		affinity:                  spec.Affinity,
 		priorityClassName:         spec.PriorityClassName,
+		preemptionPolicy:          spec.PreemptionPolicy,
+		runtimeClassName:          spec.RuntimeClassName,
 		schedulerName:             spec.SchedulerName,
+		schedulingGates:           spec.SchedulingGates,
 		clusterNodeSelector:       spec.NodeSelector,
 		clusterLabels:             spec.Labels,
 		clusterAnnotations:        spec.Annotations,
 		tolerations:               spec.Tolerations,
 		dnsConfig:                 spec.DNSConfig,
 		dnsPolicy:                 spec.DNSPolicy,
+		hostAliases:               spec.HostAliases,
 		configUpdateStrategy:      spec.ConfigUpdateStrategy,
 		statefulSetUpdateStrategy: spec.StatefulSetUpdateStrategy,
 		podManagementPolicy:       spec.PodManagementPolicy,
 		podSecurityContext:        spec.PodSecurityContext,
 		topologySpreadConstraints: spec.TopologySpreadConstraints,
 		suspendAction:             spec.SuspendAction,
+		injectResourceHints:       spec.InjectResourceHints,
 
 		ComponentSpec: componentSpec,
 	}
@@ -551,19 +666,24 @@ func buildDMClusterComponentAccessor(c MemberType, dc *DMCluster, componentSpec
 		hostNetwork:               spec.HostNetwork,
 		affinity:                  spec.Affinity,
 		priorityClassName:         spec.PriorityClassName,
+		preemptionPolicy:          spec.PreemptionPolicy,
+		runtimeClassName:          spec.RuntimeClassName,
 		schedulerName:             spec.SchedulerName,
+		schedulingGates:           spec.SchedulingGates,
 		clusterNodeSelector:       spec.NodeSelector,
 		clusterLabels:             spec.Labels,
 		clusterAnnotations:        spec.Annotations,
 		tolerations:               spec.Tolerations,
 		dnsConfig:                 spec.DNSConfig,
 		dnsPolicy:                 spec.DNSPolicy,
+		hostAliases:               spec.HostAliases,
 		configUpdateStrategy:      spec.ConfigUpdateStrategy,
 		statefulSetUpdateStrategy: spec.StatefulSetUpdateStrategy,
 		podManagementPolicy:       spec.PodManagementPolicy,
 		podSecurityContext:        spec.PodSecurityContext,
 		topologySpreadConstraints: spec.TopologySpreadConstraints,
 		suspendAction:             spec.SuspendAction,
+		injectResourceHints:       spec.InjectResourceHints,
 
 		ComponentSpec: componentSpec,
 	}
@@ -579,6 +699,9 @@ func buildTiDBNGMonitoringComponentAccessor(c MemberType, tngm *TidbNGMonitoring
 		hostNetwork:               commonSpec.HostNetwork,
 		affinity:                  commonSpec.Affinity,
 		priorityClassName:         commonSpec.PriorityClassName,
+		preemptionPolicy:          commonSpec.PreemptionPolicy,
+		runtimeClassName:          commonSpec.RuntimeClassName,
+		schedulingGates:           commonSpec.SchedulingGates,
 		clusterNodeSelector:       commonSpec.NodeSelector,
 		clusterLabels:             commonSpec.Labels,
 		clusterAnnotations:        commonSpec.Annotations,
@@ -588,6 +711,7 @@ func buildTiDBNGMonitoringComponentAccessor(c MemberType, tngm *TidbNGMonitoring
 		topologySpreadConstraints: commonSpec.TopologySpreadConstraints,
 		dnsConfig:                 commonSpec.DNSConfig,
 		dnsPolicy:                 commonSpec.DNSPolicy,
+		hostAliases:               commonSpec.HostAliases,
 
 		ComponentSpec: componentSpec,
 	}
@@ -610,6 +734,9 @@ func buildTiDBDashboardComponentAccessor(c MemberType, td *TidbDashboard, compon
 		hostNetwork:               commonSpec.HostNetwork,
 		affinity:                  commonSpec.Affinity,
 		priorityClassName:         commonSpec.PriorityClassName,
+		preemptionPolicy:          commonSpec.PreemptionPolicy,
+		runtimeClassName:          commonSpec.RuntimeClassName,
+		schedulingGates:           commonSpec.SchedulingGates,
 		clusterNodeSelector:       commonSpec.NodeSelector,
 		clusterLabels:             commonSpec.Labels,
 		clusterAnnotations:        commonSpec.Annotations,
@@ -619,6 +746,7 @@ func buildTiDBDashboardComponentAccessor(c MemberType, td *TidbDashboard, compon
 		topologySpreadConstraints: commonSpec.TopologySpreadConstraints,
 		dnsConfig:                 commonSpec.DNSConfig,
 		dnsPolicy:                 commonSpec.DNSPolicy,
+		hostAliases:               commonSpec.HostAliases,
 
 		ComponentSpec: componentSpec,
 	}
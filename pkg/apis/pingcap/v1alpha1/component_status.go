@@ -73,6 +73,14 @@ type ComponentStatus interface {
 	SetStatefulSet(sts *appsv1.StatefulSetStatus)
 	// SetVolReplaceInProgress sets the status.VolReplaceInProgress
 	SetVolReplaceInProgress(status bool)
+	// GetObservedGeneration returns `status.observedGeneration`
+	//
+	// For dm-master and dm-worker, it is always 0.
+	GetObservedGeneration() int64
+	// SetObservedGeneration sets the `status.observedGeneration`
+	//
+	// Not supported for dm-master and dm-worker
+	SetObservedGeneration(generation int64)
 }
 
 func (tc *TidbCluster) AllComponentStatus() []ComponentStatus {
@@ -185,6 +193,12 @@ func (s *PDStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStatus) {
 func (s *PDStatus) SetVolReplaceInProgress(status bool) {
 	s.VolReplaceInProgress = status
 }
+func (s *PDStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *PDStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
 
 func (s *PDMSStatus) MemberType() MemberType {
 	return PDMSMemberType(s.Name)
@@ -230,6 +244,12 @@ func (s *PDMSStatus) SetStatefulSet(sts *appsv1.StatefulSetStatus) {
 	s.StatefulSet = sts
 }
 func (s *PDMSStatus) SetVolReplaceInProgress(status bool) {}
+func (s *PDMSStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *PDMSStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
 
 func (s *TiKVStatus) MemberType() MemberType {
 	return TiKVMemberType
@@ -283,6 +303,12 @@ func (s *TiKVStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStatus)
 func (s *TiKVStatus) SetVolReplaceInProgress(status bool) {
 	s.VolReplaceInProgress = status
 }
+func (s *TiKVStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *TiKVStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
 
 func (s *TiDBStatus) MemberType() MemberType {
 	return TiDBMemberType
@@ -334,6 +360,12 @@ func (s *TiDBStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStatus)
 func (s *TiDBStatus) SetVolReplaceInProgress(status bool) {
 	s.VolReplaceInProgress = status
 }
+func (s *TiDBStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *TiDBStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
 
 func (s *PumpStatus) MemberType() MemberType {
 	return PumpMemberType
@@ -383,6 +415,12 @@ func (s *PumpStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStatus)
 	s.Volumes = vols
 }
 func (s *PumpStatus) SetVolReplaceInProgress(status bool) {}
+func (s *PumpStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *PumpStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
 
 func (s *TiFlashStatus) MemberType() MemberType {
 	return TiFlashMemberType
@@ -436,6 +474,12 @@ func (s *TiFlashStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStat
 func (s *TiFlashStatus) SetVolReplaceInProgress(status bool) {
 	s.VolReplaceInProgress = status
 }
+func (s *TiFlashStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *TiFlashStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
 
 func (s *TiCDCStatus) MemberType() MemberType {
 	return TiCDCMemberType
@@ -487,6 +531,12 @@ func (s *TiCDCStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStatus
 	s.Volumes = vols
 }
 func (s *TiCDCStatus) SetVolReplaceInProgress(status bool) {}
+func (s *TiCDCStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *TiCDCStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
 
 func (s *MasterStatus) MemberType() MemberType {
 	return DMMasterMemberType
@@ -538,6 +588,10 @@ func (s *MasterStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStatu
 	s.Volumes = vols
 }
 func (s *MasterStatus) SetVolReplaceInProgress(status bool) {}
+func (s *MasterStatus) GetObservedGeneration() int64 {
+	return 0
+}
+func (s *MasterStatus) SetObservedGeneration(generation int64) {}
 
 func (s *WorkerStatus) MemberType() MemberType {
 	return DMWorkerMemberType
@@ -589,6 +643,10 @@ func (s *WorkerStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStatu
 	s.Volumes = vols
 }
 func (s *WorkerStatus) SetVolReplaceInProgress(status bool) {}
+func (s *WorkerStatus) GetObservedGeneration() int64 {
+	return 0
+}
+func (s *WorkerStatus) SetObservedGeneration(generation int64) {}
 
 func (s *TiProxyStatus) MemberType() MemberType {
 	return TiProxyMemberType
@@ -640,3 +698,9 @@ func (s *TiProxyStatus) SetVolumes(vols map[StorageVolumeName]*StorageVolumeStat
 	s.Volumes = vols
 }
 func (s *TiProxyStatus) SetVolReplaceInProgress(status bool) {}
+func (s *TiProxyStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+func (s *TiProxyStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
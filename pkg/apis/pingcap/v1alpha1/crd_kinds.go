@@ -57,6 +57,18 @@ const (
 	TiDBDashboardKind    = "TidbDashboard"
 	TiDBDashboardKindKey = "tidbdashboard"
 
+	TiDBClusterReplicationName    = "tidbclusterreplications"
+	TiDBClusterReplicationKind    = "TidbClusterReplication"
+	TiDBClusterReplicationKindKey = "tidbclusterreplication"
+
+	PumpMigrationName    = "pumpmigrations"
+	PumpMigrationKind    = "PumpMigration"
+	PumpMigrationKindKey = "pumpmigration"
+
+	TiDBAccountName    = "tidbaccounts"
+	TiDBAccountKind    = "TidbAccount"
+	TiDBAccountKindKey = "tidbaccount"
+
 	SpecPath = "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1."
 )
 
@@ -94,6 +94,10 @@ func setTidbSpecDefault(tc *v1alpha1.TidbCluster) {
 	if tc.Spec.TiDB.MaxFailoverCount == nil {
 		tc.Spec.TiDB.MaxFailoverCount = pointer.Int32Ptr(3)
 	}
+	// the log file of the log volume is set in the config file
+	if tc.Spec.TiDB.LogVolume != nil && tc.Spec.TiDB.Config == nil {
+		tc.Spec.TiDB.Config = v1alpha1.NewTiDBConfig()
+	}
 
 	// Start set config if need.
 	if tc.Spec.TiDB.Config == nil {
@@ -118,6 +122,18 @@ func setTikvSpecDefault(tc *v1alpha1.TidbCluster) {
 	if tc.Spec.TiKV.SpareVolReplaceReplicas == nil {
 		tc.Spec.TiKV.SpareVolReplaceReplicas = pointer.Int32Ptr(1)
 	}
+	// the log file of the log volume is set in the config file
+	if tc.Spec.TiKV.LogVolume != nil && tc.Spec.TiKV.Config == nil {
+		tc.Spec.TiKV.Config = v1alpha1.NewTiKVConfig()
+	}
+	// the witness stores are started with the config file of TiKV
+	if tc.Spec.TiKV.Witness != nil && tc.Spec.TiKV.Config == nil {
+		tc.Spec.TiKV.Config = v1alpha1.NewTiKVConfig()
+	}
+	// the storage profile is expanded into the config file of TiKV
+	if tc.Spec.TiKV.StorageProfile != "" && tc.Spec.TiKV.Config == nil {
+		tc.Spec.TiKV.Config = v1alpha1.NewTiKVConfig()
+	}
 }
 
 func setPdSpecDefault(tc *v1alpha1.TidbCluster) {
@@ -132,6 +148,10 @@ func setPdSpecDefault(tc *v1alpha1.TidbCluster) {
 	if tc.Spec.PD.SpareVolReplaceReplicas == nil {
 		tc.Spec.PD.SpareVolReplaceReplicas = pointer.Int32Ptr(1)
 	}
+	// the log file of the log volume is set in the config file
+	if tc.Spec.PD.LogVolume != nil && tc.Spec.PD.Config == nil {
+		tc.Spec.PD.Config = v1alpha1.NewPDConfig()
+	}
 }
 
 func setPDMSSpecDefault(tc *v1alpha1.TidbCluster) {
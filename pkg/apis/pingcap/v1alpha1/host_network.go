@@ -0,0 +1,181 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HostNetworkPort is a port bound on the node by a component running in the host network.
+type HostNetworkPort struct {
+	// Component is the member type of the component binding the port.
+	Component MemberType
+	// Name is the name of the port, or the name of the additional container binding the port.
+	Name string
+	// Port is the port number.
+	Port int32
+	// Declared is true if the port is declared in the pod spec, so the scheduler doesn't
+	// place another pod declaring the same host port to the same node.
+	Declared bool
+}
+
+func (p HostNetworkPort) String() string {
+	return fmt.Sprintf("%s %s(%d)", p.Component, p.Name, p.Port)
+}
+
+// HostNetworkPortConflict is a port claimed by more than one component in the host network.
+type HostNetworkPortConflict struct {
+	Port   int32
+	Claims []HostNetworkPort
+}
+
+func (c HostNetworkPortConflict) String() string {
+	s := fmt.Sprintf("port %d is claimed by", c.Port)
+	for i, claim := range c.Claims {
+		if i > 0 {
+			s += " and"
+		}
+		s += fmt.Sprintf(" %s %s", claim.Component, claim.Name)
+	}
+	return s
+}
+
+// HostNetworkPorts returns the ports bound on the nodes by the components of the cluster
+// running in the host network, including the ports that are listened by the components but
+// not declared in the pod spec, and the ports of the additional containers.
+func (tc *TidbCluster) HostNetworkPorts() []HostNetworkPort {
+	var ports []HostNetworkPort
+	if tc.Spec.PD != nil && tc.BasePDSpec().HostNetwork() {
+		ports = append(ports, componentHostNetworkPorts(tc.BasePDSpec(), PDMemberType, []HostNetworkPort{
+			{Name: "client", Port: DefaultPDClientPort, Declared: true},
+			{Name: "peer", Port: DefaultPDPeerPort, Declared: true},
+		})...)
+	}
+	for _, pdms := range tc.Spec.PDMS {
+		spec := tc.BasePDMSSpec(pdms)
+		if !spec.HostNetwork() {
+			continue
+		}
+		ports = append(ports, componentHostNetworkPorts(spec, PDMSMemberType(pdms.Name), []HostNetworkPort{
+			{Name: "client", Port: DefaultPDClientPort, Declared: true},
+		})...)
+	}
+	if tc.Spec.TiKV != nil && tc.BaseTiKVSpec().HostNetwork() {
+		ports = append(ports, componentHostNetworkPorts(tc.BaseTiKVSpec(), TiKVMemberType, []HostNetworkPort{
+			{Name: "server", Port: DefaultTiKVServerPort, Declared: true},
+			{Name: "status", Port: DefaultTiKVStatusPort, Declared: tc.Spec.TiKV.EnableNamedStatusPort},
+		})...)
+	}
+	if tc.Spec.TiFlash != nil && tc.BaseTiFlashSpec().HostNetwork() {
+		ports = append(ports, componentHostNetworkPorts(tc.BaseTiFlashSpec(), TiFlashMemberType, []HostNetworkPort{
+			{Name: "tiflash", Port: DefaultTiFlashFlashPort, Declared: true},
+			{Name: "proxy", Port: DefaultTiFlashProxyPort, Declared: true},
+			{Name: "tcp", Port: DefaultTiFlashTcpPort, Declared: true},
+			{Name: "http", Port: DefaultTiFlashHttpPort, Declared: true},
+			{Name: "internal", Port: DefaultTiFlashInternalPort, Declared: true},
+			{Name: "metrics", Port: DefaultTiFlashMetricsPort, Declared: true},
+			{Name: "proxy-status", Port: DefaultTiFlashProxyStatusPort},
+		})...)
+	}
+	if tc.Spec.TiDB != nil && tc.BaseTiDBSpec().HostNetwork() {
+		ports = append(ports, componentHostNetworkPorts(tc.BaseTiDBSpec(), TiDBMemberType, []HostNetworkPort{
+			{Name: "server", Port: DefaultTiDBServerPort, Declared: true},
+			{Name: "status", Port: DefaultTiDBStatusPort, Declared: true},
+		})...)
+	}
+	if tc.Spec.TiProxy != nil && tc.BaseTiProxySpec().HostNetwork() {
+		ports = append(ports, componentHostNetworkPorts(tc.BaseTiProxySpec(), TiProxyMemberType, []HostNetworkPort{
+			{Name: "server", Port: DefaultTiProxyServerPort, Declared: true},
+			{Name: "api", Port: DefaultTiProxyStatusPort, Declared: true},
+			{Name: "peer", Port: 3081, Declared: true},
+		})...)
+	}
+	if tc.Spec.TiCDC != nil && tc.BaseTiCDCSpec().HostNetwork() {
+		ports = append(ports, componentHostNetworkPorts(tc.BaseTiCDCSpec(), TiCDCMemberType, []HostNetworkPort{
+			{Name: "ticdc", Port: DefaultTiCDCPort, Declared: true},
+		})...)
+	}
+	if tc.Spec.Pump != nil && tc.BasePumpSpec().HostNetwork() {
+		ports = append(ports, componentHostNetworkPorts(tc.BasePumpSpec(), PumpMemberType, []HostNetworkPort{
+			{Name: "pump", Port: DefaultPumpPort, Declared: true},
+		})...)
+	}
+	return ports
+}
+
+// HostNetworkPortsOf returns the ports bound on the node by a pod of the component.
+func (tc *TidbCluster) HostNetworkPortsOf(component MemberType) []HostNetworkPort {
+	var ports []HostNetworkPort
+	for _, p := range tc.HostNetworkPorts() {
+		if p.Component == component {
+			ports = append(ports, p)
+		}
+	}
+	return ports
+}
+
+func componentHostNetworkPorts(spec ComponentAccessor, component MemberType, ports []HostNetworkPort) []HostNetworkPort {
+	for _, c := range spec.AdditionalContainers() {
+		for _, p := range c.Ports {
+			port := p.ContainerPort
+			if p.HostPort != 0 {
+				port = p.HostPort
+			}
+			ports = append(ports, HostNetworkPort{Name: c.Name, Port: port, Declared: true})
+		}
+	}
+	for i := range ports {
+		ports[i].Component = component
+	}
+	return ports
+}
+
+// HostNetworkPortConflicts returns the ports that can't be bound by all the claims when the
+// pods are placed to the same node. The ports claimed twice by a component always conflict
+// as they are bound in the same pod. The ports claimed by different components conflict
+// only if any of the claims is not declared in the pod spec, otherwise the scheduler keeps
+// the pods apart.
+func HostNetworkPortConflicts(ports []HostNetworkPort) []HostNetworkPortConflict {
+	claims := map[int32][]HostNetworkPort{}
+	for _, p := range ports {
+		claims[p.Port] = append(claims[p.Port], p)
+	}
+
+	var conflicts []HostNetworkPortConflict
+	for port, ps := range claims {
+		if len(ps) < 2 || !hostNetworkClaimsConflict(ps) {
+			continue
+		}
+		conflicts = append(conflicts, HostNetworkPortConflict{Port: port, Claims: ps})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Port < conflicts[j].Port
+	})
+	return conflicts
+}
+
+func hostNetworkClaimsConflict(claims []HostNetworkPort) bool {
+	for i := range claims {
+		for j := i + 1; j < len(claims); j++ {
+			if claims[i].Component == claims[j].Component {
+				return true
+			}
+			if !claims[i].Declared || !claims[j].Declared {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestHostNetworkPorts(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &TidbCluster{}
+	tc.Spec.PD = &PDSpec{}
+	tc.Spec.TiKV = &TiKVSpec{}
+	tc.Spec.TiDB = &TiDBSpec{}
+	g.Expect(tc.HostNetworkPorts()).To(BeEmpty())
+
+	// only the components in the host network bind the ports of the nodes
+	tc.Spec.TiKV.HostNetwork = pointer.BoolPtr(true)
+	tc.Spec.TiKV.AdditionalContainers = []corev1.Container{{
+		Name:  "exporter",
+		Ports: []corev1.ContainerPort{{ContainerPort: 9100, HostPort: 9101}},
+	}}
+	g.Expect(tc.HostNetworkPorts()).To(Equal([]HostNetworkPort{
+		{Component: TiKVMemberType, Name: "server", Port: DefaultTiKVServerPort, Declared: true},
+		{Component: TiKVMemberType, Name: "status", Port: DefaultTiKVStatusPort},
+		{Component: TiKVMemberType, Name: "exporter", Port: 9101, Declared: true},
+	}))
+	g.Expect(HostNetworkPortConflicts(tc.HostNetworkPorts())).To(BeEmpty())
+
+	tc.Spec.HostNetwork = pointer.BoolPtr(true)
+	g.Expect(tc.HostNetworkPortsOf(PDMemberType)).To(HaveLen(2))
+	g.Expect(tc.HostNetworkPortsOf(TiDBMemberType)).To(HaveLen(2))
+	g.Expect(HostNetworkPortConflicts(tc.HostNetworkPorts())).To(BeEmpty())
+
+	// PD and the PD microservices declare the same client port, so they are kept apart by the scheduler
+	tc.Spec.PDMS = []*PDMSSpec{{Name: "tso"}, {Name: "scheduling"}}
+	g.Expect(HostNetworkPortConflicts(tc.HostNetworkPorts())).To(BeEmpty())
+
+	// the undeclared status port of TiKV conflicts with the additional container of TiDB
+	tc.Spec.TiDB.AdditionalContainers = []corev1.Container{{
+		Name:  "sidecar",
+		Ports: []corev1.ContainerPort{{ContainerPort: DefaultTiKVStatusPort}},
+	}}
+	conflicts := HostNetworkPortConflicts(tc.HostNetworkPorts())
+	g.Expect(conflicts).To(HaveLen(1))
+	g.Expect(conflicts[0].Port).To(Equal(DefaultTiKVStatusPort))
+	g.Expect(conflicts[0].String()).To(Equal("port 20180 is claimed by tikv status and tidb sidecar"))
+
+	// the conflict is resolved by declaring the status port of TiKV
+	tc.Spec.TiKV.EnableNamedStatusPort = true
+	g.Expect(HostNetworkPortConflicts(tc.HostNetworkPorts())).To(BeEmpty())
+
+	// the ports bound in the same pod always conflict
+	tc.Spec.TiDB.AdditionalContainers[0].Ports[0].ContainerPort = DefaultTiDBStatusPort
+	conflicts = HostNetworkPortConflicts(tc.HostNetworkPorts())
+	g.Expect(conflicts).To(HaveLen(1))
+	g.Expect(conflicts[0].Port).To(Equal(DefaultTiDBStatusPort))
+}
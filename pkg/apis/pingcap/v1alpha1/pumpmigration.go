@@ -0,0 +1,30 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import "time"
+
+const defaultMaxCatchUpLag = 10 * time.Second
+
+// MaxCatchUpLag returns the max lag of the changefeeds to be regarded as caught up
+func (pm *PumpMigration) MaxCatchUpLag() time.Duration {
+	if pm.Spec.MaxCatchUpLag == nil {
+		return defaultMaxCatchUpLag
+	}
+	d, err := time.ParseDuration(*pm.Spec.MaxCatchUpLag)
+	if err != nil {
+		return defaultMaxCatchUpLag
+	}
+	return d
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PumpMigration migrates a TidbCluster from the deprecated TiDB Binlog (Pump and Drainer) to TiCDC.
+// It provisions TiCDC for the cluster, creates the changefeeds equivalent to the drainers, waits
+// for the changefeeds to catch up, then disables the binlog of TiDB and decommissions the pumps.
+//
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName="pmig"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.cluster.name`,description="The cluster to be migrated"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The current phase of the migration"
+// +kubebuilder:printcolumn:name="Lag",type=string,JSONPath=`.status.lag`,description="The max lag of the changefeeds"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type PumpMigration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec contains all spec about the migration.
+	Spec PumpMigrationSpec `json:"spec"`
+
+	// Status is most recently observed status of the migration.
+	//
+	// +k8s:openapi-gen=false
+	Status PumpMigrationStatus `json:"status,omitempty"`
+}
+
+// PumpMigrationList is a PumpMigration list.
+//
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PumpMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PumpMigration `json:"items"`
+}
+
+// PumpMigrationSpec is spec of the migration.
+//
+// +k8s:openapi-gen=true
+type PumpMigrationSpec struct {
+	// Cluster is the TidbCluster using Pump to be migrated, it must be in the namespace of the migration.
+	Cluster TidbClusterRef `json:"cluster"`
+
+	// TiCDC is the spec of the TiCDC provisioned for the cluster if the cluster has no TiCDC.
+	// +optional
+	TiCDC *TiCDCSpec `json:"ticdc,omitempty"`
+
+	// Changefeeds are the changefeeds equivalent to the drainers of the cluster.
+	// To avoid replicating the same changes twice, the drainers should be stopped and the
+	// start ts of the changefeeds set to the checkpoints of the drainers.
+	// +kubebuilder:validation:MinItems=1
+	Changefeeds []PumpMigrationChangefeed `json:"changefeeds"`
+
+	// MaxCatchUpLag is the max lag of the changefeeds to be regarded as caught up.
+	// Optional: Defaults to 10s
+	// +optional
+	MaxCatchUpLag *string `json:"maxCatchUpLag,omitempty"`
+
+	// DecommissionPump indicates whether to disable the binlog of TiDB and decommission the
+	// pumps once the changefeeds catch up. The migration waits in the CaughtUp phase until it's set.
+	// +optional
+	DecommissionPump bool `json:"decommissionPump,omitempty"`
+}
+
+// PumpMigrationChangefeed is a changefeed equivalent to a drainer.
+//
+// +k8s:openapi-gen=true
+type PumpMigrationChangefeed struct {
+	// ID is the ID of the changefeed.
+	ID string `json:"id"`
+
+	// SinkURI is the URI of the downstream of the changefeed, e.g. mysql://root:@tidb:4000/.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// SinkURISecretName is the name of the Secret in the namespace of the migration whose
+	// `sinkURI` key is the URI of the downstream, it's used if the URI contains a password.
+	// +optional
+	SinkURISecretName string `json:"sinkURISecretName,omitempty"`
+
+	// StartTs is the TSO the changefeed starts to replicate from, usually the checkpoint
+	// of the drainer. Optional: Defaults to the current TSO
+	// +optional
+	StartTs uint64 `json:"startTs,omitempty"`
+}
+
+// PumpMigrationPhase is the current phase of the migration.
+type PumpMigrationPhase string
+
+const (
+	// PumpMigrationPending means the migration is not started yet.
+	PumpMigrationPending PumpMigrationPhase = "Pending"
+	// PumpMigrationProvisioningTiCDC means TiCDC is being provisioned for the cluster.
+	PumpMigrationProvisioningTiCDC PumpMigrationPhase = "ProvisioningTiCDC"
+	// PumpMigrationCatchingUp means the changefeeds are created and catching up.
+	PumpMigrationCatchingUp PumpMigrationPhase = "CatchingUp"
+	// PumpMigrationCaughtUp means the changefeeds have caught up and wait for the pumps to be decommissioned.
+	PumpMigrationCaughtUp PumpMigrationPhase = "CaughtUp"
+	// PumpMigrationDisablingBinlog means the binlog of TiDB is being disabled.
+	PumpMigrationDisablingBinlog PumpMigrationPhase = "DisablingBinlog"
+	// PumpMigrationDecommissioningPump means the pumps are being decommissioned.
+	PumpMigrationDecommissioningPump PumpMigrationPhase = "DecommissioningPump"
+	// PumpMigrationComplete means the cluster has been migrated to TiCDC.
+	PumpMigrationComplete PumpMigrationPhase = "Complete"
+	// PumpMigrationFailed means the migration is failed and needs manual intervention.
+	PumpMigrationFailed PumpMigrationPhase = "Failed"
+)
+
+// PumpMigrationStatus is status of the migration.
+type PumpMigrationStatus struct {
+	// Phase is the current phase of the migration.
+	Phase PumpMigrationPhase `json:"phase,omitempty"`
+
+	// Message is a human readable message indicating details about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Changefeeds is the status of the changefeeds.
+	// +optional
+	Changefeeds []PumpMigrationChangefeedStatus `json:"changefeeds,omitempty"`
+
+	// Lag is the max lag of the changefeeds.
+	// +optional
+	Lag string `json:"lag,omitempty"`
+
+	// CaughtUpTime is the time the changefeeds caught up.
+	// +optional
+	CaughtUpTime *metav1.Time `json:"caughtUpTime,omitempty"`
+
+	// BinlogDisabledTime is the time the binlog of TiDB was disabled, the TiDB pods
+	// created before it are still writing binlog.
+	// +optional
+	BinlogDisabledTime *metav1.Time `json:"binlogDisabledTime,omitempty"`
+
+	// CompleteTime is the time the migration completed.
+	// +optional
+	CompleteTime *metav1.Time `json:"completeTime,omitempty"`
+}
+
+// PumpMigrationChangefeedStatus is the status of a changefeed.
+type PumpMigrationChangefeedStatus struct {
+	// ID is the ID of the changefeed.
+	ID string `json:"id"`
+
+	// State is the state of the changefeed reported by TiCDC.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// CheckpointTime is the time up to which the data has been replicated.
+	// +optional
+	CheckpointTime *metav1.Time `json:"checkpointTime,omitempty"`
+
+	// Lag is the replication lag of the changefeed.
+	// +optional
+	Lag string `json:"lag,omitempty"`
+}
@@ -71,6 +71,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TidbNGMonitoringList{},
 		&TidbDashboard{},
 		&TidbDashboardList{},
+		&TidbClusterReplication{},
+		&TidbClusterReplicationList{},
+		&PumpMigration{},
+		&PumpMigrationList{},
+		&TidbAccount{},
+		&TidbAccountList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
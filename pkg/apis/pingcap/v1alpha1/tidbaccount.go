@@ -0,0 +1,72 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import "strings"
+
+const (
+	defaultTidbAccountHost        = "%"
+	defaultTidbAccountAdmin       = "root"
+	defaultTidbAccountGrantTarget = "*.*"
+)
+
+// ClusterNamespace returns the namespace of the TidbCluster the account is created in
+func (ta *TidbAccount) ClusterNamespace() string {
+	if ta.Spec.Cluster.Namespace != "" {
+		return ta.Spec.Cluster.Namespace
+	}
+	return ta.Namespace
+}
+
+// GetHost returns the host the user connects from
+func (ta *TidbAccount) GetHost() string {
+	if ta.Spec.Host == "" {
+		return defaultTidbAccountHost
+	}
+	return ta.Spec.Host
+}
+
+// GetAdminUser returns the TiDB user to manage the account
+func (ta *TidbAccount) GetAdminUser() string {
+	if ta.Spec.AdminUser == "" {
+		return defaultTidbAccountAdmin
+	}
+	return ta.Spec.AdminUser
+}
+
+// GetReclaimPolicy returns whether to drop the user when the TidbAccount is deleted
+func (ta *TidbAccount) GetReclaimPolicy() TidbAccountReclaimPolicy {
+	if ta.Spec.ReclaimPolicy == "" {
+		return TidbAccountReclaimRetain
+	}
+	return ta.Spec.ReclaimPolicy
+}
+
+// GetOn returns the level the privileges are granted on
+func (p *TidbAccountPrivilege) GetOn() string {
+	if p.On == "" {
+		return defaultTidbAccountGrantTarget
+	}
+	return p.On
+}
+
+// GetLevel returns the database and table of the level the privileges are granted on,
+// * means all the databases or tables, ok is false if the level is malformed
+func (p *TidbAccountPrivilege) GetLevel() (database, table string, ok bool) {
+	parts := strings.Split(p.GetOn(), ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || (parts[0] == "*" && parts[1] != "*") {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
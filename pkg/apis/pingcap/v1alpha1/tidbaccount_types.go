@@ -0,0 +1,203 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TidbAccount is a user of a TidbCluster with its password, privileges and TLS requirements,
+// the account is created and kept in sync with the spec by SQL.
+//
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName="ta"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="User",type=string,JSONPath=`.spec.user`,description="The name of the user"
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=`.spec.host`,description="The host the user connects from"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The current phase of the account"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type TidbAccount struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec contains all spec about the account.
+	Spec TidbAccountSpec `json:"spec"`
+
+	// Status is most recently observed status of the account.
+	//
+	// +k8s:openapi-gen=false
+	Status TidbAccountStatus `json:"status,omitempty"`
+}
+
+// TidbAccountList is a TidbAccount list.
+//
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TidbAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TidbAccount `json:"items"`
+}
+
+// TidbAccountSpec is spec of the account.
+//
+// +k8s:openapi-gen=true
+type TidbAccountSpec struct {
+	// Cluster is the TidbCluster the account is created in.
+	Cluster TidbClusterRef `json:"cluster"`
+
+	// User is the name of the user.
+	User string `json:"user"`
+
+	// Host is the host the user connects from.
+	// Optional: Defaults to %
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// PasswordSecret selects the key of the Secret in the namespace of the TidbAccount
+	// whose value is the password of the user, the password is changed along with the Secret.
+	PasswordSecret corev1.SecretKeySelector `json:"passwordSecret"`
+
+	// Privileges are the privileges granted to the user, the privileges granted out of
+	// the spec are revoked.
+	// +optional
+	Privileges []TidbAccountPrivilege `json:"privileges,omitempty"`
+
+	// TLS is the TLS requirements of the connections of the user.
+	// +optional
+	TLS *TidbAccountTLS `json:"tls,omitempty"`
+
+	// AdminUser is the TiDB user to manage the account, the user needs the CREATE USER
+	// privilege and the privileges granted to the account with the GRANT OPTION.
+	// Optional: Defaults to root
+	// +optional
+	AdminUser string `json:"adminUser,omitempty"`
+
+	// AdminSecretName is the name of the Secret in the namespace of the TidbAccount with the
+	// password of the admin user in the `password` key, the password is empty if not set.
+	// +optional
+	AdminSecretName string `json:"adminSecretName,omitempty"`
+
+	// TLSClientSecretName is the name of secret which stores tidb server client certificate
+	// Optional: Defaults to nil
+	// +optional
+	TLSClientSecretName *string `json:"tlsClientSecretName,omitempty"`
+
+	// ReclaimPolicy is whether to drop the user when the TidbAccount is deleted.
+	// Optional: Defaults to Retain
+	// +kubebuilder:default=Retain
+	// +kubebuilder:validation:Enum:="Retain";"Delete"
+	// +optional
+	ReclaimPolicy TidbAccountReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// TidbAccountPrivilege is the privileges granted to the user on a level.
+//
+// +k8s:openapi-gen=true
+type TidbAccountPrivilege struct {
+	// Privileges are the names of the privileges, e.g. SELECT, INSERT or ALL PRIVILEGES.
+	Privileges []string `json:"privileges"`
+
+	// On is the level the privileges are granted on, e.g. *.*, db.* or db.table.
+	// Optional: Defaults to *.*
+	// +optional
+	On string `json:"on,omitempty"`
+}
+
+// TLSRequirement is the TLS requirement of the connections of a user.
+type TLSRequirement string
+
+const (
+	// TLSRequireNone means the connections are not required to use TLS.
+	TLSRequireNone TLSRequirement = "None"
+	// TLSRequireSSL means the connections must use TLS.
+	TLSRequireSSL TLSRequirement = "SSL"
+	// TLSRequireX509 means the connections must use TLS with a valid client certificate.
+	TLSRequireX509 TLSRequirement = "X509"
+)
+
+// TidbAccountTLS is the TLS requirements of the connections of the user.
+//
+// +k8s:openapi-gen=true
+type TidbAccountTLS struct {
+	// Require is the TLS requirement of the connections, Subject and Issuer take precedence.
+	// Optional: Defaults to None
+	// +kubebuilder:validation:Enum:="None";"SSL";"X509"
+	// +optional
+	Require TLSRequirement `json:"require,omitempty"`
+
+	// Subject is the required subject of the client certificate.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// Issuer is the required issuer of the client certificate.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// TidbAccountReclaimPolicy is whether to drop the user when the TidbAccount is deleted.
+type TidbAccountReclaimPolicy string
+
+const (
+	// TidbAccountReclaimRetain keeps the user when the TidbAccount is deleted.
+	TidbAccountReclaimRetain TidbAccountReclaimPolicy = "Retain"
+	// TidbAccountReclaimDelete drops the user when the TidbAccount is deleted.
+	TidbAccountReclaimDelete TidbAccountReclaimPolicy = "Delete"
+)
+
+// TidbAccountPhase is the current phase of the account.
+type TidbAccountPhase string
+
+const (
+	// TidbAccountPending means the account is not created yet.
+	TidbAccountPending TidbAccountPhase = "Pending"
+	// TidbAccountSynced means the account is in sync with the spec.
+	TidbAccountSynced TidbAccountPhase = "Synced"
+	// TidbAccountFailed means the account failed to be synced, it's retried in the next sync.
+	TidbAccountFailed TidbAccountPhase = "Failed"
+)
+
+// TidbAccountStatus is status of the account.
+type TidbAccountStatus struct {
+	// Phase is the current phase of the account.
+	Phase TidbAccountPhase `json:"phase,omitempty"`
+
+	// Message is a human readable message indicating details about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the account was last synced with.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PasswordSecretVersion is the resource version of the password Secret last set to the user.
+	// +optional
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+
+	// LastDriftTime is the time the account was last found changed out of the spec and set back.
+	// +optional
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+
+	// Drifts are the differences from the spec found in the last drift.
+	// +optional
+	Drifts []string `json:"drifts,omitempty"`
+}
@@ -16,12 +16,15 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
@@ -42,11 +45,24 @@ const (
 	defaultEvictLeaderTimeout            = 1500 * time.Minute
 	defaultWaitLeaderTransferBackTimeout = 400 * time.Second
 	RetryEvictLeaderInterval             = 10 * time.Minute
+	// defaults of spec.tikv.scaleOutBalancePolicy
+	defaultScaleOutBalanceMinReplicas     = 2
+	defaultScaleOutBalanceStoreLimit      = 50
+	defaultScaleOutBalanceBalancedPercent = 90
+	defaultScaleOutBalanceTimeout         = 2 * time.Hour
+	// defaults of spec.tikv.slowStoreDetection
+	defaultSlowStoreScoreThreshold = 80
+	defaultSlowStoreDuration       = 5 * time.Minute
+	defaultMaxMitigatedSlowStores  = 1
 	// defaultTiCDCGracefulShutdownTimeout is the timeout limit of graceful
 	// shutdown a TiCDC pod.
 	defaultTiCDCGracefulShutdownTimeout = 10 * time.Minute
 	defaultPDStartTimeout               = 30
 	defaultPDInitWaitTime               = 0
+	// defaultConfigBackupLimit is the default max number of the config backups kept for each component
+	defaultConfigBackupLimit = 10
+	// defaultLoadBalancerReadinessConditionType is the default condition type of the load balancer readiness gate of TiDB
+	defaultLoadBalancerReadinessConditionType = "tidb.pingcap.com/load-balancer-ready"
 
 	// the latest version
 	versionLatest = "latest"
@@ -193,6 +209,14 @@ func (tc *TidbCluster) TiKVEvictLeaderTimeout() time.Duration {
 	return defaultEvictLeaderTimeout
 }
 
+// TiKVDeletionSafetyLevel returns the strictness of the region check before a TiKV pod is deleted
+func (tc *TidbCluster) TiKVDeletionSafetyLevel() TiKVDeletionSafetyLevel {
+	if tc.Spec.TiKV != nil && tc.Spec.TiKV.DeletionSafetyLevel != "" {
+		return tc.Spec.TiKV.DeletionSafetyLevel
+	}
+	return TiKVDeletionSafetyNone
+}
+
 func (tc *TidbCluster) TiKVWaitLeaderTransferBackTimeout() time.Duration {
 	if tc.Spec.TiKV != nil && tc.Spec.TiKV.WaitLeaderTransferBackTimeout != nil {
 		return tc.Spec.TiKV.WaitLeaderTransferBackTimeout.Duration
@@ -200,6 +224,75 @@ func (tc *TidbCluster) TiKVWaitLeaderTransferBackTimeout() time.Duration {
 	return defaultWaitLeaderTransferBackTimeout
 }
 
+// TiKVScaleOutBalanceMinReplicas returns the minimum number of the replicas scaled out at once to tune the scheduling
+func (tc *TidbCluster) TiKVScaleOutBalanceMinReplicas() int32 {
+	if p := tc.Spec.TiKV.ScaleOutBalancePolicy; p != nil && p.MinReplicas != nil {
+		return *p.MinReplicas
+	}
+	return defaultScaleOutBalanceMinReplicas
+}
+
+// TiKVScaleOutBalanceStoreLimit returns the add-peer store limit of the new stores during the rebalancing
+func (tc *TidbCluster) TiKVScaleOutBalanceStoreLimit() int32 {
+	if p := tc.Spec.TiKV.ScaleOutBalancePolicy; p != nil && p.StoreLimit != nil {
+		return *p.StoreLimit
+	}
+	return defaultScaleOutBalanceStoreLimit
+}
+
+// TiKVScaleOutBalancedPercent returns the percentage of the average region count the new stores must reach
+func (tc *TidbCluster) TiKVScaleOutBalancedPercent() int32 {
+	if p := tc.Spec.TiKV.ScaleOutBalancePolicy; p != nil && p.BalancedPercent != nil {
+		return *p.BalancedPercent
+	}
+	return defaultScaleOutBalanceBalancedPercent
+}
+
+// TiKVScaleOutBalanceTimeout returns the max duration of the rebalancing
+func (tc *TidbCluster) TiKVScaleOutBalanceTimeout() time.Duration {
+	if p := tc.Spec.TiKV.ScaleOutBalancePolicy; p != nil && p.Timeout != nil {
+		return p.Timeout.Duration
+	}
+	return defaultScaleOutBalanceTimeout
+}
+
+// TiKVSlowStoreDetectionEnabled returns whether the slow stores of TiKV are detected
+func (tc *TidbCluster) TiKVSlowStoreDetectionEnabled() bool {
+	return tc.Spec.TiKV != nil && tc.Spec.TiKV.SlowStoreDetection != nil
+}
+
+// TiKVSlowStoreScoreThreshold returns the slow score at and above which a TiKV store is slow
+func (tc *TidbCluster) TiKVSlowStoreScoreThreshold() int32 {
+	if d := tc.Spec.TiKV.SlowStoreDetection; d != nil && d.SlowScoreThreshold != nil {
+		return *d.SlowScoreThreshold
+	}
+	return defaultSlowStoreScoreThreshold
+}
+
+// TiKVSlowStoreDuration returns the time the slow score stays high before a TiKV store is flagged as slow
+func (tc *TidbCluster) TiKVSlowStoreDuration() time.Duration {
+	if d := tc.Spec.TiKV.SlowStoreDetection; d != nil && d.Duration != nil {
+		return d.Duration.Duration
+	}
+	return defaultSlowStoreDuration
+}
+
+// TiKVSlowStoreMitigation returns the mitigation applied to the slow TiKV stores
+func (tc *TidbCluster) TiKVSlowStoreMitigation() TiKVSlowStoreMitigation {
+	if d := tc.Spec.TiKV.SlowStoreDetection; d != nil && d.Mitigation != "" {
+		return d.Mitigation
+	}
+	return TiKVSlowStoreMitigationNone
+}
+
+// TiKVMaxMitigatedSlowStores returns the max number of the slow TiKV stores mitigated at the same time
+func (tc *TidbCluster) TiKVMaxMitigatedSlowStores() int {
+	if d := tc.Spec.TiKV.SlowStoreDetection; d != nil && d.MaxMitigatedStores != nil {
+		return int(*d.MaxMitigatedStores)
+	}
+	return defaultMaxMitigatedSlowStores
+}
+
 // TiFlashImage return the image used by TiFlash.
 //
 // If TiFlash isn't specified, return empty string.
@@ -470,6 +563,18 @@ func (tc *TidbCluster) TiKVBootStrapped() bool {
 	return tc.Status.TiKV.BootStrapped
 }
 
+// TiKVWitnessUpgradingOrScaling returns whether the TiKV witness stores are being upgraded or scaled
+func (tc *TidbCluster) TiKVWitnessUpgradingOrScaling() bool {
+	witness := tc.Status.TiKV.Witness
+	return witness != nil && (witness.Phase == UpgradePhase || witness.Phase == ScalePhase)
+}
+
+// TiKVWitnessRulesApplied returns whether the placement rules of the witness replicas are applied,
+// one replica of each region is placed on the witness stores if it's true
+func (tc *TidbCluster) TiKVWitnessRulesApplied() bool {
+	return tc.Status.TiKV.Witness != nil && tc.Status.TiKV.Witness.PlacementRulesApplied
+}
+
 func (tc *TidbCluster) TiDBUpgrading() bool {
 	return tc.Status.TiDB.Phase == UpgradePhase
 }
@@ -634,7 +739,58 @@ func (tc *TidbCluster) PDStsDesiredReplicas() int32 {
 	if tc.Status.PD.VolReplaceInProgress {
 		spareReplaceReplicas = *tc.Spec.PD.SpareVolReplaceReplicas
 	}
-	return tc.Spec.PD.Replicas + tc.GetPDDeletedFailureReplicas() + spareReplaceReplicas
+	return tc.Spec.PD.Replicas + tc.PDLearnerReplicas() + tc.GetPDDeletedFailureReplicas() + spareReplaceReplicas
+}
+
+// PDLearnerReplicas returns the number of the PD learners
+func (tc *TidbCluster) PDLearnerReplicas() int32 {
+	if tc.Spec.PD == nil {
+		return 0
+	}
+	return tc.Spec.PD.LearnerReplicas
+}
+
+// PDIsLearnerOrdinal returns whether the PD member of the ordinal joins the PD cluster as a learner
+func (tc *TidbCluster) PDIsLearnerOrdinal(ordinal int32) bool {
+	if tc.Spec.PD == nil {
+		return false
+	}
+	return ordinal >= tc.Spec.PD.Replicas && ordinal < tc.Spec.PD.Replicas+tc.PDLearnerReplicas()
+}
+
+// PDFeature is a feature of PD which is supported since a version
+type PDFeature string
+
+const (
+	// PDFeatureLearner is running the PD members as the non-voting learners of the embedded etcd
+	PDFeatureLearner PDFeature = "Learner"
+	// PDFeatureMicroservices is running PD in the microservices mode
+	PDFeatureMicroservices PDFeature = "Microservices"
+)
+
+// pdFeatureMinVersions are the first versions of PD which support the features
+var pdFeatureMinVersions = map[PDFeature]string{
+	PDFeatureLearner:       "v7.1.0",
+	PDFeatureMicroservices: "v8.0.0",
+}
+
+// PDFeatureMinVersion returns the first version of PD which supports the feature
+func PDFeatureMinVersion(feature PDFeature) string {
+	return pdFeatureMinVersions[feature]
+}
+
+// PDSupports returns whether the version of PD supports the feature. The versions which can't be
+// parsed, e.g. latest and nightly, are regarded as the latest version.
+func (tc *TidbCluster) PDSupports(feature PDFeature) bool {
+	minVersion, ok := pdFeatureMinVersions[feature]
+	if !ok {
+		return false
+	}
+	v, err := semver.NewVersion(tc.PDVersion())
+	if err != nil {
+		return true
+	}
+	return !v.LessThan(semver.MustParse(minVersion))
 }
 
 func (tc *TidbCluster) PDStsActualReplicas() int32 {
@@ -649,7 +805,7 @@ func (tc *TidbCluster) PDStsDesiredOrdinals(excludeFailover bool) sets.Int32 {
 	if tc.Spec.PD == nil {
 		return sets.Int32{}
 	}
-	replicas := tc.Spec.PD.Replicas
+	replicas := tc.Spec.PD.Replicas + tc.PDLearnerReplicas()
 	if !excludeFailover {
 		replicas = tc.PDStsDesiredReplicas()
 	}
@@ -1038,6 +1194,38 @@ func (tc *TidbCluster) IsRecoveryMode() bool {
 	return tc.Spec.RecoveryMode
 }
 
+// CloneSourceNamespace returns the namespace of the cluster in spec.clusterCloneFrom
+func (tc *TidbCluster) CloneSourceNamespace() string {
+	if tc.Spec.ClusterCloneFrom != nil && tc.Spec.ClusterCloneFrom.Namespace != "" {
+		return tc.Spec.ClusterCloneFrom.Namespace
+	}
+	return tc.Namespace
+}
+
+// CloneMethod returns the way the data is cloned from the cluster in spec.clusterCloneFrom
+func (tc *TidbCluster) CloneMethod() ClusterCloneMethod {
+	if tc.Spec.ClusterCloneFrom == nil || tc.Spec.ClusterCloneFrom.Method == "" {
+		return ClusterCloneMethodBR
+	}
+	return tc.Spec.ClusterCloneFrom.Method
+}
+
+// CloneBackupName returns the name of the backup of the source cluster created to clone the cluster
+func (tc *TidbCluster) CloneBackupName() string {
+	return tc.Name + "-clone-backup"
+}
+
+// CloneRestoreName returns the name of the restore created to clone the cluster
+func (tc *TidbCluster) CloneRestoreName() string {
+	return tc.Name + "-clone-restore"
+}
+
+// IsCloneFinished returns whether the clone from the cluster in spec.clusterCloneFrom is complete or failed
+func (tc *TidbCluster) IsCloneFinished() bool {
+	return tc.Status.Clone != nil &&
+		(tc.Status.Clone.Phase == ClusterCloneComplete || tc.Status.Clone.Phase == ClusterCloneFailed)
+}
+
 func (tc *TidbCluster) NeedToSyncTiDBInitializer() bool {
 	return tc.Spec.TiDB != nil && tc.Spec.TiDB.Initializer != nil && tc.Spec.TiDB.Initializer.CreatePassword && tc.Status.TiDB.PasswordInitialized == nil
 }
@@ -1073,6 +1261,18 @@ func (tc *TidbCluster) IsPVCReplaceEnabled() bool {
 	return *enabled
 }
 
+// ConfigBackupLimit returns the max number of the config backups kept for each component,
+// 0 is returned if the config backups are disabled
+func (tc *TidbCluster) ConfigBackupLimit() int {
+	if tc.Spec.ConfigBackup == nil {
+		return 0
+	}
+	if tc.Spec.ConfigBackup.Limit == nil || *tc.Spec.ConfigBackup.Limit < 1 {
+		return defaultConfigBackupLimit
+	}
+	return int(*tc.Spec.ConfigBackup.Limit)
+}
+
 func (tc *TidbCluster) IsTiDBBinlogEnabled() bool {
 	var binlogEnabled *bool
 	if tc.Spec.TiDB != nil {
@@ -1122,6 +1322,18 @@ func (tidb *TiDBSpec) GetServicePort() int32 {
 	return port
 }
 
+// GetLoadBalancerReadinessConditionType returns the condition type of the load balancer readiness gate,
+// empty is returned if the readiness gate is not enabled
+func (tidb *TiDBSpec) GetLoadBalancerReadinessConditionType() corev1.PodConditionType {
+	if tidb.LoadBalancerReadiness == nil {
+		return ""
+	}
+	if tidb.LoadBalancerReadiness.ConditionType == "" {
+		return defaultLoadBalancerReadinessConditionType
+	}
+	return corev1.PodConditionType(tidb.LoadBalancerReadiness.ConditionType)
+}
+
 func (tidb *TiDBSpec) GetScaleInParallelism() int {
 	if tidb.ScalePolicy.ScaleInParallelism == nil {
 		return 1
@@ -1180,6 +1392,26 @@ func (tikv *TiKVSpec) GetScaleOutParallelism() int {
 	return int(*(tikv.ScalePolicy.ScaleOutParallelism))
 }
 
+// GetMaxUnavailable returns the max number of pods upgraded at the same time, which is at least 1
+func (s *RollingUpdateStrategy) GetMaxUnavailable(replicas int32) int {
+	if s == nil || s.MaxUnavailable == nil {
+		return 1
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(s.MaxUnavailable, int(replicas), false)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// GetPartition returns the ordinal below which the pods are kept at the old revision
+func (s *RollingUpdateStrategy) GetPartition() int32 {
+	if s == nil || s.Partition == nil {
+		return 0
+	}
+	return *s.Partition
+}
+
 func (tiflash *TiFlashSpec) GetRecoverByUID() types.UID {
 	if tiflash.Failover == nil {
 		return ""
@@ -1341,10 +1573,44 @@ func (tc *TidbCluster) Heterogeneous() bool {
 	return tc.Spec.Cluster != nil && len(tc.Spec.Cluster.Name) > 0
 }
 
+// DiscoveryReplicas returns the desired replicas of discovery
+func (tc *TidbCluster) DiscoveryReplicas() int32 {
+	if tc.Spec.Discovery.Replicas == nil {
+		return 1
+	}
+	return *tc.Spec.Discovery.Replicas
+}
+
 func (tc *TidbCluster) WithoutLocalPD() bool {
 	return tc.Spec.PD == nil
 }
 
+// HasExternalPD returns whether the tidbcluster uses the PD deployed outside of this Kubernetes cluster
+func (tc *TidbCluster) HasExternalPD() bool {
+	return tc.Spec.ExternalPD != nil && len(tc.Spec.ExternalPD.Endpoints) > 0
+}
+
+// IsExternalPDTLSEnabled returns whether the external PD is accessed with TLS
+func (tc *TidbCluster) IsExternalPDTLSEnabled() bool {
+	return tc.HasExternalPD() && strings.HasPrefix(tc.Spec.ExternalPD.Endpoints[0], "https://")
+}
+
+// ExternalPDAddresses returns the addresses in the format of host:port of the external PD
+func (tc *TidbCluster) ExternalPDAddresses() []string {
+	if !tc.HasExternalPD() {
+		return nil
+	}
+	addrs := make([]string, 0, len(tc.Spec.ExternalPD.Endpoints))
+	for _, endpoint := range tc.Spec.ExternalPD.Endpoints {
+		if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+			addrs = append(addrs, u.Host)
+		} else {
+			addrs = append(addrs, endpoint)
+		}
+	}
+	return addrs
+}
+
 func (tc *TidbCluster) WithoutLocalTiDB() bool {
 	return tc.Spec.TiDB == nil
 }
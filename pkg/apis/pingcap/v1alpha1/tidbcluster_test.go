@@ -441,6 +441,8 @@ func TestComponentAccessor(t *testing.T) {
 		component *ComponentSpec
 		expectFn  func(*GomegaWithT, ComponentAccessor)
 	}
+	preemptNever := corev1.PreemptNever
+	preemptLowerPriority := corev1.PreemptLowerPriority
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
 
@@ -582,6 +584,142 @@ func TestComponentAccessor(t *testing.T) {
 				g.Expect(a.Tolerations()).Should(ConsistOf(toleration2))
 			},
 		},
+		{
+			name: "affinity preset appended to cluster-level affinity",
+			cluster: &TidbClusterSpec{
+				Affinity: affinity,
+			},
+			component: &ComponentSpec{
+				AffinityPreset: func() *AffinityPreset { a := AffinityPresetRequiredPerZone; return &a }(),
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(a.Affinity().PodAffinity).Should(Equal(affinity.PodAffinity))
+				g.Expect(a.Affinity().PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).Should(Equal([]corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app.kubernetes.io/name":       "tidb-cluster",
+							"app.kubernetes.io/managed-by": "tidb-operator",
+							"app.kubernetes.io/component":  "tidb",
+							"app.kubernetes.io/instance":   "test",
+						},
+					},
+					TopologyKey: corev1.LabelTopologyZone,
+				}}))
+				// the cluster-level affinity is not modified
+				g.Expect(affinity.PodAntiAffinity).Should(BeNil())
+			},
+		},
+		{
+			name:    "preferred affinity preset",
+			cluster: &TidbClusterSpec{},
+			component: &ComponentSpec{
+				AffinityPreset: func() *AffinityPreset { a := AffinityPresetPreferredPerNode; return &a }(),
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				terms := a.Affinity().PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+				g.Expect(terms).Should(HaveLen(1))
+				g.Expect(terms[0].Weight).Should(Equal(int32(100)))
+				g.Expect(terms[0].PodAffinityTerm.TopologyKey).Should(Equal(corev1.LabelHostname))
+				g.Expect(terms[0].PodAffinityTerm.LabelSelector.MatchLabels).Should(HaveKeyWithValue("app.kubernetes.io/component", "tidb"))
+			},
+		},
+		{
+			name: "dns and host aliases override at component-level",
+			cluster: &TidbClusterSpec{
+				DNSPolicy:   corev1.DNSDefault,
+				DNSConfig:   &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.1"}},
+				HostAliases: []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"pd.cluster-1"}}},
+			},
+			component: &ComponentSpec{
+				DNSPolicy:   corev1.DNSNone,
+				HostAliases: []corev1.HostAlias{{IP: "10.0.0.3", Hostnames: []string{"pd.cluster-2"}}},
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				podSpec := a.BuildPodSpec()
+				g.Expect(podSpec.DNSPolicy).Should(Equal(corev1.DNSNone))
+				g.Expect(podSpec.DNSConfig.Nameservers).Should(ConsistOf("10.0.0.1"))
+				g.Expect(podSpec.HostAliases).Should(Equal([]corev1.HostAlias{{IP: "10.0.0.3", Hostnames: []string{"pd.cluster-2"}}}))
+			},
+		},
+		{
+			name: "preemption policy override at component-level",
+			cluster: &TidbClusterSpec{
+				PriorityClassName: pointer.StringPtr("cluster-priority"),
+				PreemptionPolicy:  &preemptNever,
+			},
+			component: &ComponentSpec{
+				PriorityClassName: pointer.StringPtr("component-priority"),
+				PreemptionPolicy:  &preemptLowerPriority,
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				podSpec := a.BuildPodSpec()
+				g.Expect(podSpec.PriorityClassName).Should(Equal("component-priority"))
+				g.Expect(*podSpec.PreemptionPolicy).Should(Equal(corev1.PreemptLowerPriority))
+			},
+		},
+		{
+			name: "preemption policy at cluster-level",
+			cluster: &TidbClusterSpec{
+				PreemptionPolicy: &preemptNever,
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(*a.BuildPodSpec().PreemptionPolicy).Should(Equal(corev1.PreemptNever))
+			},
+		},
+		{
+			name: "runtime class name override at component-level",
+			cluster: &TidbClusterSpec{
+				RuntimeClassName: pointer.StringPtr("runc"),
+			},
+			component: &ComponentSpec{
+				RuntimeClassName: pointer.StringPtr("kata"),
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(*a.BuildPodSpec().RuntimeClassName).Should(Equal("kata"))
+			},
+		},
+		{
+			name: "runtime class name at cluster-level",
+			cluster: &TidbClusterSpec{
+				RuntimeClassName: pointer.StringPtr("runc"),
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(*a.BuildPodSpec().RuntimeClassName).Should(Equal("runc"))
+			},
+		},
+		{
+			name:    "runtime class name not set",
+			cluster: &TidbClusterSpec{},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(a.BuildPodSpec().RuntimeClassName).Should(BeNil())
+			},
+		},
+		{
+			name: "scheduler name and scheduling gates override at component-level",
+			cluster: &TidbClusterSpec{
+				SchedulerName:   "tidb-scheduler",
+				SchedulingGates: []corev1.PodSchedulingGate{{Name: "example.com/capacity"}},
+			},
+			component: &ComponentSpec{
+				SchedulerName:   pointer.StringPtr("volcano"),
+				SchedulingGates: []corev1.PodSchedulingGate{{Name: "kueue.x-k8s.io/admission"}},
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				podSpec := a.BuildPodSpec()
+				g.Expect(podSpec.SchedulerName).Should(Equal("volcano"))
+				g.Expect(podSpec.SchedulingGates).Should(Equal([]corev1.PodSchedulingGate{{Name: "kueue.x-k8s.io/admission"}}))
+			},
+		},
+		{
+			name: "scheduling gates at cluster-level",
+			cluster: &TidbClusterSpec{
+				SchedulingGates: []corev1.PodSchedulingGate{{Name: "example.com/capacity"}},
+			},
+			component: &ComponentSpec{},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(a.BuildPodSpec().SchedulingGates).Should(Equal([]corev1.PodSchedulingGate{{Name: "example.com/capacity"}}))
+			},
+		},
 	}
 
 	for i := range tests {
@@ -813,6 +951,49 @@ func TestTiCDCGracefulShutdownTimeout(t *testing.T) {
 	g.Expect(tc.TiCDCGracefulShutdownTimeout()).To(Equal(time.Minute))
 }
 
+func TestExternalPD(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	g.Expect(tc.HasExternalPD()).To(BeFalse())
+	g.Expect(tc.IsExternalPDTLSEnabled()).To(BeFalse())
+	g.Expect(tc.ExternalPDAddresses()).To(BeNil())
+
+	tc.Spec.ExternalPD = &ExternalPDSpec{
+		Endpoints: []string{"https://pd-0.example.com:2379", "https://10.0.0.1:12379"},
+	}
+	g.Expect(tc.HasExternalPD()).To(BeTrue())
+	g.Expect(tc.IsExternalPDTLSEnabled()).To(BeTrue())
+	g.Expect(tc.ExternalPDAddresses()).To(Equal([]string{"pd-0.example.com:2379", "10.0.0.1:12379"}))
+}
+
+func TestAffinityPresetLabelSelector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	preset := AffinityPresetRequiredPerNode
+	tngm := &TidbNGMonitoring{ObjectMeta: metav1.ObjectMeta{Name: "ngm"}}
+	tngm.Spec.NGMonitoring.AffinityPreset = &preset
+	terms := tngm.BaseNGMonitoringSpec().Affinity().PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	g.Expect(terms).Should(HaveLen(1))
+	g.Expect(terms[0].LabelSelector.MatchLabels).Should(Equal(map[string]string{
+		"app.kubernetes.io/name":       "tidb-ng-monitoring",
+		"app.kubernetes.io/managed-by": "tidb-operator",
+		"app.kubernetes.io/component":  "ng-monitoring",
+		"app.kubernetes.io/instance":   "ngm",
+	}))
+
+	td := &TidbDashboard{ObjectMeta: metav1.ObjectMeta{Name: "dashboard"}}
+	td.Spec.AffinityPreset = &preset
+	terms = td.BaseTidbDashboardSpec().Affinity().PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	g.Expect(terms).Should(HaveLen(1))
+	g.Expect(terms[0].LabelSelector.MatchLabels).Should(Equal(map[string]string{
+		"app.kubernetes.io/name":       "tidb-dashboard",
+		"app.kubernetes.io/managed-by": "tidb-operator",
+		"app.kubernetes.io/component":  "tidb-dashboard",
+		"app.kubernetes.io/instance":   "dashboard",
+	}))
+}
+
 func TestComponentFunc(t *testing.T) {
 	t.Run("ComponentIsNormal", func(t *testing.T) {
 		g := NewGomegaWithT(t)
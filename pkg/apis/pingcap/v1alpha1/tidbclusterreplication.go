@@ -0,0 +1,72 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import "time"
+
+const defaultMaxPromoteLag = 10 * time.Second
+
+// PrimaryNamespace returns the namespace of the primary cluster
+func (tcr *TidbClusterReplication) PrimaryNamespace() string {
+	if tcr.Spec.Primary.Namespace != "" {
+		return tcr.Spec.Primary.Namespace
+	}
+	return tcr.Namespace
+}
+
+// SecondaryNamespace returns the namespace of the secondary cluster
+func (tcr *TidbClusterReplication) SecondaryNamespace() string {
+	if tcr.Spec.Secondary.Namespace != "" {
+		return tcr.Spec.Secondary.Namespace
+	}
+	return tcr.Namespace
+}
+
+// GetMode returns the way the data is replicated
+func (tcr *TidbClusterReplication) GetMode() ReplicationMode {
+	if tcr.Spec.Mode == "" {
+		return ReplicationModeTiCDC
+	}
+	return tcr.Spec.Mode
+}
+
+// ChangefeedID returns the ID of the changefeed created in TiCDC mode
+func (tcr *TidbClusterReplication) ChangefeedID() string {
+	if tcr.Spec.TiCDC != nil && tcr.Spec.TiCDC.ChangefeedID != "" {
+		return tcr.Spec.TiCDC.ChangefeedID
+	}
+	return tcr.Name
+}
+
+// LogBackupName returns the name of the log backup created in PITR mode
+func (tcr *TidbClusterReplication) LogBackupName() string {
+	return tcr.Name + "-log"
+}
+
+// RestoreName returns the name of the PiTR restore created in PITR mode
+func (tcr *TidbClusterReplication) RestoreName() string {
+	return tcr.Name + "-restore"
+}
+
+// MaxPromoteLag returns the max replication lag allowed to promote the secondary cluster
+func (tcr *TidbClusterReplication) MaxPromoteLag() time.Duration {
+	if tcr.Spec.MaxPromoteLag == nil {
+		return defaultMaxPromoteLag
+	}
+	d, err := time.ParseDuration(*tcr.Spec.MaxPromoteLag)
+	if err != nil {
+		return defaultMaxPromoteLag
+	}
+	return d
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TidbClusterReplication pairs two TidbClusters as a primary and a hot standby secondary,
+// it replicates the data of the primary to the secondary and promotes the secondary on demand.
+//
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName="tcr"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`,description="The way the data is replicated"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The current phase of the replication"
+// +kubebuilder:printcolumn:name="Lag",type=string,JSONPath=`.status.lag`,description="The replication lag of the secondary cluster"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type TidbClusterReplication struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec contains all spec about the replication.
+	Spec TidbClusterReplicationSpec `json:"spec"`
+
+	// Status is most recently observed status of the replication.
+	//
+	// +k8s:openapi-gen=false
+	Status TidbClusterReplicationStatus `json:"status,omitempty"`
+}
+
+// TidbClusterReplicationList is a TidbClusterReplication list.
+//
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TidbClusterReplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TidbClusterReplication `json:"items"`
+}
+
+// ReplicationMode is the way the data of the primary cluster is replicated.
+type ReplicationMode string
+
+const (
+	// ReplicationModeTiCDC replicates the data by a changefeed of the TiCDC of the primary cluster.
+	ReplicationModeTiCDC ReplicationMode = "TiCDC"
+	// ReplicationModePITR replicates the data by a log backup of the primary cluster, which is
+	// restored to the secondary cluster by a PiTR restore when the secondary is promoted.
+	ReplicationModePITR ReplicationMode = "PITR"
+)
+
+// TidbClusterReplicationSpec is spec of the replication.
+//
+// +k8s:openapi-gen=true
+type TidbClusterReplicationSpec struct {
+	// Primary is the TidbCluster the data is replicated from.
+	Primary TidbClusterRef `json:"primary"`
+
+	// Secondary is the TidbCluster the data is replicated to.
+	Secondary TidbClusterRef `json:"secondary"`
+
+	// Mode is the way the data is replicated.
+	//
+	// +kubebuilder:default=TiCDC
+	// +kubebuilder:validation:Enum:="TiCDC";"PITR"
+	Mode ReplicationMode `json:"mode,omitempty"`
+
+	// TiCDC configures the changefeed, it's used in TiCDC mode.
+	// +optional
+	TiCDC *TiCDCReplicationSpec `json:"ticdc,omitempty"`
+
+	// PITR configures the log backup and the PiTR restore, it's required in PITR mode.
+	// +optional
+	PITR *PITRReplicationSpec `json:"pitr,omitempty"`
+
+	// Promote indicates whether to promote the secondary cluster to a standalone cluster.
+	// The replication is stopped after the secondary is promoted, and it can't be resumed.
+	// +optional
+	Promote bool `json:"promote,omitempty"`
+
+	// MaxPromoteLag is the max replication lag allowed to promote the secondary cluster,
+	// the promotion waits for the lag to be within it unless ForcePromote is set.
+	// Optional: Defaults to 10s
+	// +optional
+	MaxPromoteLag *string `json:"maxPromoteLag,omitempty"`
+
+	// ForcePromote indicates whether to promote the secondary cluster without waiting for
+	// the replication lag, which is used to fail over when the primary cluster is down.
+	// +optional
+	ForcePromote bool `json:"forcePromote,omitempty"`
+}
+
+// TiCDCReplicationSpec configures the changefeed that replicates the data to the TiDB of the
+// secondary cluster.
+//
+// +k8s:openapi-gen=true
+type TiCDCReplicationSpec struct {
+	// ChangefeedID is the ID of the changefeed.
+	// Optional: Defaults to the name of the TidbClusterReplication
+	// +optional
+	ChangefeedID string `json:"changefeedID,omitempty"`
+
+	// User is the user to connect to the TiDB of the secondary cluster.
+	// Optional: Defaults to root
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// SecretName is the name of the Secret in the namespace of the TidbClusterReplication,
+	// whose `password` key is the password of the user.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// PITRReplicationSpec configures the log backup of the primary cluster and the PiTR restore
+// to the secondary cluster.
+//
+// +k8s:openapi-gen=true
+type PITRReplicationSpec struct {
+	// LogBackupTemplate is the template of the log backup of the primary cluster,
+	// its mode and BR cluster are set by the controller.
+	LogBackupTemplate BackupSpec `json:"logBackupTemplate"`
+
+	// RestoreTemplate is the template of the PiTR restore to the secondary cluster,
+	// its mode, BR cluster, storage and restored ts are set by the controller.
+	// The full backup the logs are applied to is set by pitrFullBackupStorageProvider.
+	RestoreTemplate RestoreSpec `json:"restoreTemplate"`
+}
+
+// ReplicationPhase is the current phase of the replication.
+type ReplicationPhase string
+
+const (
+	// ReplicationPending means the replication is not started yet.
+	ReplicationPending ReplicationPhase = "Pending"
+	// ReplicationReplicating means the data is being replicated to the secondary cluster.
+	ReplicationReplicating ReplicationPhase = "Replicating"
+	// ReplicationPromoting means the secondary cluster is being promoted.
+	ReplicationPromoting ReplicationPhase = "Promoting"
+	// ReplicationPromoted means the secondary cluster has been promoted.
+	ReplicationPromoted ReplicationPhase = "Promoted"
+	// ReplicationFailed means the replication is failed and needs manual intervention.
+	ReplicationFailed ReplicationPhase = "Failed"
+)
+
+// TidbClusterReplicationStatus is status of the replication.
+type TidbClusterReplicationStatus struct {
+	// Phase is the current phase of the replication.
+	Phase ReplicationPhase `json:"phase,omitempty"`
+
+	// Message is a human readable message indicating details about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ChangefeedID is the ID of the changefeed created in TiCDC mode.
+	// +optional
+	ChangefeedID string `json:"changefeedID,omitempty"`
+
+	// LogBackup is the name of the log backup created in PITR mode.
+	// +optional
+	LogBackup string `json:"logBackup,omitempty"`
+
+	// Restore is the name of the PiTR restore created in PITR mode.
+	// +optional
+	Restore string `json:"restore,omitempty"`
+
+	// CheckpointTime is the time up to which the data has been replicated.
+	// +optional
+	CheckpointTime *metav1.Time `json:"checkpointTime,omitempty"`
+
+	// Lag is the replication lag of the secondary cluster.
+	// +optional
+	Lag string `json:"lag,omitempty"`
+
+	// PromoteTime is the time the secondary cluster was promoted.
+	// +optional
+	PromoteTime *metav1.Time `json:"promoteTime,omitempty"`
+}
@@ -88,6 +88,12 @@ type TidbInitializerSpec struct {
 	// +optional
 	PasswordSecret *string `json:"passwordSecret,omitempty"`
 
+	// RotatePassword applies the changes of the passwords in passwordSecret to the TiDB cluster by
+	// ALTER USER after the initialization, and creates the users added to passwordSecret. The applied
+	// passwords are kept in the secret ${cluster}-tidb-initializer-applied-password.
+	// +optional
+	RotatePassword bool `json:"rotatePassword,omitempty"`
+
 	// +optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
@@ -115,6 +121,18 @@ type TidbInitializerStatus struct {
 
 	// Phase is a user readable state inferred from the underlying Job status and TidbCluster status
 	Phase InitializePhase `json:"phase,omitempty"`
+
+	// PasswordRotations are the last rotations of the passwords of the users in spec.passwordSecret
+	// +optional
+	PasswordRotations []PasswordRotation `json:"passwordRotations,omitempty"`
+}
+
+// PasswordRotation is the last rotation of the password of a user
+// +k8s:openapi-gen=true
+type PasswordRotation struct {
+	User string `json:"user"`
+
+	LastRotationTime metav1.Time `json:"lastRotationTime"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
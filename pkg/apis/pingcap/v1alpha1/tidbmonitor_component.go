@@ -74,3 +74,14 @@ func (tm *TidbMonitor) BaseReloaderSpec() MonitorComponentAccessor {
 		MonitorServiceSpec:   &tm.Spec.Reloader.Service,
 	}
 }
+
+func (tm *TidbMonitor) BaseAlertmanagerSpec() MonitorComponentAccessor {
+	if tm.Spec.Alertmanager != nil {
+		return &monitorComponentAccessorImpl{
+			MonitorSpec:          &tm.Spec,
+			MonitorComponentSpec: &tm.Spec.Alertmanager.MonitorContainer,
+			MonitorServiceSpec:   &tm.Spec.Alertmanager.Service,
+		}
+	}
+	return nil
+}
@@ -62,6 +62,11 @@ type TidbMonitorSpec struct {
 	// +optional
 	Grafana *GrafanaSpec `json:"grafana,omitempty"`
 
+	// GrafanaExport exports the effective Grafana dashboards and datasources as ConfigMaps
+	// labeled for the sidecar of an external Grafana, it works with or without the bundled Grafana
+	// +optional
+	GrafanaExport *GrafanaExportSpec `json:"grafanaExport,omitempty"`
+
 	// Reloader spec
 	Reloader ReloaderSpec `json:"reloader"`
 
@@ -91,6 +96,17 @@ type TidbMonitorSpec struct {
 	// +optional
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 
+	// PriorityClassName of TidbMonitor Pods
+	// Optional: Defaults to omitted
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// PreemptionPolicy of TidbMonitor Pods. It requires PriorityClassName and must match the
+	// preemptionPolicy of the PriorityClass, otherwise the admission rejects the pods
+	// Optional: Defaults to omitted
+	// +optional
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
 	// If Persistent enabled, storageClassName must be set to an existing storage.
 	// Defaults to false.
 	// +optional
@@ -132,6 +148,17 @@ type TidbMonitorSpec struct {
 	// +optional
 	AlertmanagerURL *string `json:"alertmanagerURL,omitempty"`
 
+	// Alertmanager is the Alertmanager the alerts are sent to, which is either deployed in the
+	// TidbMonitor pods or an external one. It takes precedence over `alertmanagerURL`, and the
+	// built-in alert rules are loaded if it's set.
+	// +optional
+	Alertmanager *AlertmanagerSpec `json:"alertmanager,omitempty"`
+
+	// ExtraAlertRules are the ConfigMaps of the alert rules loaded by Prometheus in addition to the
+	// built-in alert rules. The keys of the ConfigMaps must have the suffix `.rules.yml`.
+	// +optional
+	ExtraAlertRules []corev1.LocalObjectReference `json:"extraAlertRules,omitempty"`
+
 	// alertManagerRulesVersion is the version of the tidb cluster that used for alert rules.
 	// default to current tidb cluster version, for example: v3.0.15
 	// +optional
@@ -297,6 +324,58 @@ type GrafanaSpec struct {
 	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
 }
 
+// GrafanaExportSpec is the desired state of the Grafana dashboards and datasources export.
+// The dashboards and datasources generated by the initializers are applied as ConfigMaps in the
+// namespace of TidbMonitor by an init container, the image of which must provide `kubectl`.
+type GrafanaExportSpec struct {
+	MonitorContainer `json:",inline"`
+
+	// DashboardLabels are the labels of the dashboard ConfigMaps the Grafana sidecar watches.
+	// Defaults to `grafana_dashboard: "1"`.
+	// +optional
+	DashboardLabels map[string]string `json:"dashboardLabels,omitempty"`
+
+	// DatasourceLabels are the labels of the datasource ConfigMaps the Grafana sidecar watches.
+	// Defaults to `grafana_datasource: "1"`.
+	// +optional
+	DatasourceLabels map[string]string `json:"datasourceLabels,omitempty"`
+
+	// Annotations of the exported ConfigMaps, e.g. the folder annotation of the Grafana sidecar.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// PrometheusURL is the URL the exported datasources query, which must be reachable from the
+	// external Grafana. Defaults to the service of Thanos Query if it's deployed, otherwise the
+	// Prometheus service of TidbMonitor.
+	// +optional
+	PrometheusURL *string `json:"prometheusURL,omitempty"`
+}
+
+// AlertmanagerSpec is the desired state of Alertmanager
+type AlertmanagerSpec struct {
+	MonitorContainer `json:",inline"`
+
+	// URL is the address of an external Alertmanager, e.g. "alertmanager.monitoring:9093".
+	// Alertmanager is deployed in the TidbMonitor pods if it's not set.
+	// +optional
+	URL *string `json:"url,omitempty"`
+
+	// ConfigMapRef is the ConfigMap of the config of Alertmanager deployed, which must contain
+	// the key `alertmanager.yml`.
+	// Optional: Defaults to a config receiving the alerts without sending them to anywhere
+	// +optional
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// Alertmanager log level
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Service defines a Kubernetes service of Alertmanager.
+	Service ServiceSpec `json:"service,omitempty"`
+
+	// Additional volume mounts of alertmanager.
+	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+}
+
 // ReloaderSpec is the desired state of reloader
 type ReloaderSpec struct {
 	MonitorContainer `json:",inline"`
@@ -342,6 +421,71 @@ type ThanosSpec struct {
 	RoutePrefix string `json:"routePrefix,omitempty"`
 	// Additional volume mounts of thanos pod.
 	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+
+	// Query deploys Thanos Query, which queries the Prometheus of all the shards and replicas
+	// through the Thanos sidecars and deduplicates the series by the replica labels.
+	// Grafana uses Thanos Query as the data source if it's deployed.
+	// +optional
+	Query *ThanosQuerySpec `json:"query,omitempty"`
+
+	// Store deploys Thanos Store Gateway, which serves the blocks uploaded to the object storage
+	// to Thanos Query for the long-term retention.
+	// ObjectStorageConfig is required to deploy it.
+	// +optional
+	Store *ThanosComponentSpec `json:"store,omitempty"`
+
+	// Ruler deploys Thanos Ruler, which evaluates the rules against Thanos Query
+	// and sends the alerts to Alertmanager.
+	// +optional
+	Ruler *ThanosRulerSpec `json:"ruler,omitempty"`
+}
+
+// ThanosComponentSpec is the common attributes of the Thanos components deployed by TidbMonitor,
+// the image of the Thanos sidecar is used.
+type ThanosComponentSpec struct {
+	corev1.ResourceRequirements `json:",inline"`
+
+	// Replicas is the number of desired replicas.
+	// Optional: Defaults to 2
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Log level of the component, defaults to the log level of the Thanos sidecar
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Service defines a Kubernetes service of the component.
+	// +optional
+	Service ServiceSpec `json:"service,omitempty"`
+}
+
+// ThanosQuerySpec is the desired state of Thanos Query
+type ThanosQuerySpec struct {
+	ThanosComponentSpec `json:",inline"`
+
+	// ReplicaLabels are the labels to deduplicate the series by.
+	// Optional: Defaults to the replica external label of Prometheus and the replica label of Thanos Ruler
+	// +optional
+	ReplicaLabels []string `json:"replicaLabels,omitempty"`
+
+	// Endpoints are the addresses of additional StoreAPI servers, e.g. the sidecars of other TidbMonitors.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// ThanosRulerSpec is the desired state of Thanos Ruler
+type ThanosRulerSpec struct {
+	ThanosComponentSpec `json:",inline"`
+
+	// RuleConfigMaps are the ConfigMaps of the rule files evaluated, the keys ending with
+	// `.yml` or `.yaml` in each ConfigMap are loaded.
+	// +optional
+	RuleConfigMaps []corev1.LocalObjectReference `json:"ruleConfigMaps,omitempty"`
+
+	// EvaluationInterval is the interval of evaluating the rules.
+	// Optional: Defaults to 1m
+	// +optional
+	EvaluationInterval string `json:"evaluationInterval,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -558,6 +702,31 @@ func (tm *TidbMonitor) GetShards() int32 {
 	return shards
 }
 
+// AlertmanagerDeployed returns whether Alertmanager is deployed in the TidbMonitor pods
+func (tm *TidbMonitor) AlertmanagerDeployed() bool {
+	return tm.Spec.Alertmanager != nil && tm.Spec.Alertmanager.URL == nil
+}
+
+// ThanosQueryDeployed returns whether Thanos Query is deployed by the TidbMonitor
+func (tm *TidbMonitor) ThanosQueryDeployed() bool {
+	return tm.Spec.Thanos != nil && tm.Spec.Thanos.Query != nil
+}
+
+// GrafanaExportEnabled returns whether the Grafana dashboards and datasources are exported as ConfigMaps
+func (tm *TidbMonitor) GrafanaExportEnabled() bool {
+	return tm.Spec.GrafanaExport != nil
+}
+
+// ThanosStoreDeployed returns whether Thanos Store Gateway is deployed by the TidbMonitor
+func (tm *TidbMonitor) ThanosStoreDeployed() bool {
+	return tm.Spec.Thanos != nil && tm.Spec.Thanos.Store != nil
+}
+
+// ThanosRulerDeployed returns whether Thanos Ruler is deployed by the TidbMonitor
+func (tm *TidbMonitor) ThanosRulerDeployed() bool {
+	return tm.Spec.Thanos != nil && tm.Spec.Thanos.Ruler != nil
+}
+
 func (tm *TidbMonitor) Timezone() string {
 	tz := tm.Spec.Timezone
 	if len(tz) <= 0 {
@@ -13,7 +13,13 @@
 
 package v1alpha1
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+// defaultNGMonitoringCleanupInterval is the default interval between two cleanup jobs of ng monitoring
+const defaultNGMonitoringCleanupInterval = 6 * time.Hour
 
 func (tngm *TidbNGMonitoring) GetInstanceName() string {
 	return tngm.Name
@@ -37,3 +43,21 @@ func (tngm *TidbNGMonitoring) NGMonitoringImage() string {
 	}
 	return image
 }
+
+// NGMonitoringRetentionPeriod returns the retention period of the Top SQL data passed to ng monitoring
+func (tngm *TidbNGMonitoring) NGMonitoringRetentionPeriod() string {
+	if r := tngm.Spec.NGMonitoring.Retention; r != nil && r.TopSQL != "" {
+		return r.TopSQL
+	}
+	return tngm.Spec.NGMonitoring.RetentionPeriod
+}
+
+// NGMonitoringCleanupInterval returns the interval between two cleanup jobs of ng monitoring
+func (tngm *TidbNGMonitoring) NGMonitoringCleanupInterval() time.Duration {
+	if c := tngm.Spec.NGMonitoring.Cleanup; c != nil && c.Interval != "" {
+		if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultNGMonitoringCleanupInterval
+}
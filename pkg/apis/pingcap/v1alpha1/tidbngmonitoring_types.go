@@ -17,6 +17,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -115,6 +116,15 @@ type NGMonitoringSpec struct {
 	// Retention period to store ng monitoring data
 	RetentionPeriod string `json:"retentionPeriod,omitempty"`
 
+	// Retention configures the retention period of each kind of ng monitoring data
+	// +optional
+	Retention *NGMonitoringRetentionSpec `json:"retention,omitempty"`
+
+	// Cleanup configures the jobs applying the retention of ng monitoring data periodically,
+	// the storage usage reported by the jobs is shown in the status.
+	// +optional
+	Cleanup *NGMonitoringCleanupSpec `json:"cleanup,omitempty"`
+
 	// Config is the configuration of ng monitoring
 	//
 	// +kubebuilder:validation:Schemaless
@@ -122,10 +132,56 @@ type NGMonitoringSpec struct {
 	Config *config.GenericConfig `json:"config,omitempty"`
 }
 
+// NGMonitoringRetentionSpec is the retention period of each kind of ng monitoring data
+//
+// +k8s:openapi-gen=true
+type NGMonitoringRetentionSpec struct {
+	// TopSQL is the retention period of the Top SQL data, the suffix h (hour), d (day), w (week) or y (year)
+	// is required, e.g. 3d. It overrides RetentionPeriod if set.
+	// +optional
+	TopSQL string `json:"topSQL,omitempty"`
+
+	// ConProf is the retention period of the continuous profiling data, e.g. 72h.
+	// It's applied by the cleanup jobs, so Cleanup must be set too.
+	// +optional
+	ConProf string `json:"conprof,omitempty"`
+}
+
+// NGMonitoringCleanupSpec is spec of the cleanup jobs of ng monitoring
+//
+// +k8s:openapi-gen=true
+type NGMonitoringCleanupSpec struct {
+	// Interval between two cleanup jobs, e.g. 6h.
+	// Optional: Defaults to 6h
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Image of the cleanup jobs, must have `wget` and `du` installed.
+	// Optional: Defaults to the helper image of the TidbCluster
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
 // NGMonitoringStatus is latest status of ng monitoring
 type NGMonitoringStatus struct {
 	Synced bool        `json:"synced,omitempty"`
 	Phase  MemberPhase `json:"phase,omitempty"`
 
 	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+
+	// Storage is the status of the data volume of ng monitoring
+	// +optional
+	Storage *NGMonitoringStorageStatus `json:"storage,omitempty"`
+}
+
+// NGMonitoringStorageStatus is the status of the data volume of ng monitoring
+type NGMonitoringStorageStatus struct {
+	// Capacity is the current capacity of the data volume
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+	// Resizing indicates the data volume is being resized to the storage request
+	Resizing bool `json:"resizing,omitempty"`
+	// Used is the size of the data reported by the last cleanup job
+	Used *resource.Quantity `json:"used,omitempty"`
+	// LastCleanupTime is the completion time of the last cleanup job
+	LastCleanupTime *metav1.Time `json:"lastCleanupTime,omitempty"`
 }
@@ -0,0 +1,100 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// TiKVStorageProfile is the tier of the storage of TiKV data
+type TiKVStorageProfile string
+
+const (
+	// TiKVStorageProfileGP3 is the AWS EBS gp3 volume with the baseline throughput of 125MiB/s
+	TiKVStorageProfileGP3 TiKVStorageProfile = "gp3"
+	// TiKVStorageProfileIO2 is the AWS EBS io2 volume with the provisioned IOPS
+	TiKVStorageProfileIO2 TiKVStorageProfile = "io2"
+	// TiKVStorageProfileLocalNVMe is the local NVMe SSD of the node
+	TiKVStorageProfileLocalNVMe TiKVStorageProfile = "local-nvme"
+	// TiKVStorageProfilePDBalanced is the GCP pd-balanced persistent disk
+	TiKVStorageProfilePDBalanced TiKVStorageProfile = "pd-balanced"
+	// TiKVStorageProfilePDSSD is the GCP pd-ssd persistent disk
+	TiKVStorageProfilePDSSD TiKVStorageProfile = "pd-ssd"
+)
+
+// tikvStorageProfiles are the TiKV config items of the storage profiles
+var tikvStorageProfiles = map[TiKVStorageProfile]map[string]interface{}{
+	TiKVStorageProfileGP3: {
+		"storage.io-rate-limit.max-bytes-per-sec": "120MiB",
+		"rocksdb.rate-bytes-per-sec":              "80MiB",
+		"backup.num-threads":                      int64(2),
+	},
+	TiKVStorageProfileIO2: {
+		"storage.io-rate-limit.max-bytes-per-sec": "500MiB",
+		"rocksdb.rate-bytes-per-sec":              "300MiB",
+		"backup.num-threads":                      int64(4),
+	},
+	TiKVStorageProfileLocalNVMe: {
+		"storage.io-rate-limit.max-bytes-per-sec": "2GiB",
+		"rocksdb.rate-bytes-per-sec":              "1GiB",
+		"backup.num-threads":                      int64(8),
+	},
+	TiKVStorageProfilePDBalanced: {
+		"storage.io-rate-limit.max-bytes-per-sec": "200MiB",
+		"rocksdb.rate-bytes-per-sec":              "120MiB",
+		"backup.num-threads":                      int64(2),
+	},
+	TiKVStorageProfilePDSSD: {
+		"storage.io-rate-limit.max-bytes-per-sec": "400MiB",
+		"rocksdb.rate-bytes-per-sec":              "200MiB",
+		"backup.num-threads":                      int64(4),
+	},
+}
+
+// tikvStorageProfileReadPoolThreads are the max thread counts of the unified read pool of the storage profiles,
+// which serves the reads of the storage unless readpool.storage.use-unified-pool is false
+var tikvStorageProfileReadPoolThreads = map[TiKVStorageProfile]int64{
+	TiKVStorageProfileGP3:        4,
+	TiKVStorageProfileIO2:        8,
+	TiKVStorageProfileLocalNVMe:  16,
+	TiKVStorageProfilePDBalanced: 4,
+	TiKVStorageProfilePDSSD:      8,
+}
+
+// IsValid returns true if the storage profile is known
+func (p TiKVStorageProfile) IsValid() bool {
+	_, ok := tikvStorageProfiles[p]
+	return ok
+}
+
+// TiKVStorageProfiles returns the known storage profiles
+func TiKVStorageProfiles() []string {
+	return []string{
+		string(TiKVStorageProfileGP3),
+		string(TiKVStorageProfileIO2),
+		string(TiKVStorageProfileLocalNVMe),
+		string(TiKVStorageProfilePDBalanced),
+		string(TiKVStorageProfilePDSSD),
+	}
+}
+
+// ReadPoolThreads returns the max thread count of the unified read pool of the storage profile, or 0 if
+// the profile is unknown
+func (p TiKVStorageProfile) ReadPoolThreads() int64 {
+	return tikvStorageProfileReadPoolThreads[p]
+}
+
+// ApplyStorageProfile sets the config items of the storage profile which aren't set in the config, the
+// read pool is sized along with the cpu limit by the caller
+func (c *TiKVConfigWraper) ApplyStorageProfile(profile TiKVStorageProfile) {
+	for key, value := range tikvStorageProfiles[profile] {
+		c.SetIfNil(key, value)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestApplyStorageProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, profile := range TiKVStorageProfiles() {
+		g.Expect(TiKVStorageProfile(profile).IsValid()).To(BeTrue(), profile)
+	}
+	g.Expect(TiKVStorageProfile("gp2").IsValid()).To(BeFalse())
+
+	config := NewTiKVConfig()
+	config.Set("backup.num-threads", int64(6))
+	config.ApplyStorageProfile(TiKVStorageProfileIO2)
+	g.Expect(config.Get("backup.num-threads").MustInt()).To(Equal(int64(6)))
+	g.Expect(config.Get("storage.io-rate-limit.max-bytes-per-sec").MustString()).To(Equal("500MiB"))
+	g.Expect(config.Get("rocksdb.rate-bytes-per-sec").MustString()).To(Equal("300MiB"))
+	g.Expect(config.Get("readpool")).To(BeNil())
+	g.Expect(TiKVStorageProfileIO2.ReadPoolThreads()).To(Equal(int64(8)))
+	g.Expect(TiKVStorageProfile("").ReadPoolThreads()).To(BeZero())
+
+	// an unknown profile sets nothing
+	config = NewTiKVConfig()
+	config.ApplyStorageProfile("gp2")
+	g.Expect(config.MP).To(BeEmpty())
+}
@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
@@ -60,6 +61,7 @@ const (
 	ContainerSlowLogTailer    ContainerName = "slowlog"
 	ContainerRocksDBLogTailer ContainerName = "rocksdblog"
 	ContainerRaftLogTailer    ContainerName = "raftlog"
+	ContainerLogTailer        ContainerName = "log"
 )
 
 // MemberType represents member type
@@ -276,6 +278,40 @@ type TidbClusterSpec struct {
 	// +optional
 	RecoveryMode bool `json:"recoveryMode,omitempty"`
 
+	// ClusterCloneFrom references the TidbCluster this cluster is cloned from, a backup of the source
+	// cluster is taken and restored to this cluster when it's created. It's ignored after the clone finishes.
+	// +optional
+	ClusterCloneFrom *ClusterCloneFrom `json:"clusterCloneFrom,omitempty"`
+
+	// DriftProtection detects the fields of the StatefulSets and ConfigMaps of the cluster owned by the
+	// operator which are mutated by other field managers, e.g. GitOps tools or `kubectl edit`.
+	// Optional: Defaults to nil, the drifts aren't detected
+	// +optional
+	DriftProtection *DriftProtection `json:"driftProtection,omitempty"`
+
+	// ResourceRecommendation makes the operator recommend the resource requests of the components
+	// from their resource usage, and optionally apply them during the maintenance windows.
+	// +optional
+	ResourceRecommendation *ResourceRecommendationPolicy `json:"resourceRecommendation,omitempty"`
+
+	// DeletionPolicy is the policy to delete the cluster and its data, the policies other than Background
+	// are carried out by a finalizer of the operator before the cluster is deleted.
+	// Optional: Defaults to Background
+	// +kubebuilder:validation:Enum=Background;RetainData;Purge
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ProfileCapture describes the profile captured when it's requested by the
+	// `tidb.pingcap.com/profile-capture` annotation, and the storage it's saved to.
+	// +optional
+	ProfileCapture *ProfileCaptureSpec `json:"profileCapture,omitempty"`
+
+	// PrometheusMonitor makes the operator create a prometheus-operator PodMonitor for each component of the
+	// cluster, for the clusters monitored by e.g. kube-prometheus-stack instead of TidbMonitor.
+	// It's ignored if the PodMonitor CRD isn't installed in the Kubernetes cluster.
+	// +optional
+	PrometheusMonitor *PrometheusMonitorSpec `json:"prometheusMonitor,omitempty"`
+
 	// TiDB cluster version
 	// +optional
 	Version string `json:"version"`
@@ -333,6 +369,24 @@ type TidbClusterSpec struct {
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 
+	// PreemptionPolicy of TiDB cluster Pods, e.g. Never to prevent the pods from preempting
+	// the pods with lower priority. It requires PriorityClassName and must match the
+	// preemptionPolicy of the PriorityClass, otherwise the admission rejects the pods
+	// Optional: Defaults to omitted
+	// +optional
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
+	// RuntimeClassName of TiDB cluster Pods, e.g. to run the pods in a sandboxed container runtime
+	// Optional: Defaults to omitted
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// SchedulingGates of TiDB cluster Pods, the pods are not scheduled until the gates are removed
+	// from them, e.g. by the controllers of kueue or other external capacity controllers
+	// Optional: Defaults to omitted
+	// +optional
+	SchedulingGates []corev1.PodSchedulingGate `json:"schedulingGates,omitempty"`
+
 	// Base node selectors of TiDB cluster Pods, components may add or override selectors upon this respectively
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -347,6 +401,16 @@ type TidbClusterSpec struct {
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
 
+	// PropagatePolicy selects the labels and annotations in the metadata of the TidbCluster which are
+	// propagated to the statefulsets, pods, services, PVCs and jobs of the cluster. The propagated ones
+	// are removed from the resources when they are removed from the TidbCluster or not selected any more.
+	// +optional
+	PropagatePolicy *PropagatePolicy `json:"propagatePolicy,omitempty"`
+
+	// Velero prepares the resources of the cluster for the cluster-level backups and restores by Velero
+	// +optional
+	Velero *VeleroSpec `json:"velero,omitempty"`
+
 	// Base tolerations of TiDB cluster Pods, components may add more tolerations upon this respectively
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
@@ -359,6 +423,10 @@ type TidbClusterSpec struct {
 	// +optional
 	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
 
+	// HostAliases is an optional list of hosts and IPs that will be injected into the pod's hosts file.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
 	// Time zone of TiDB cluster Pods
 	// Optional: Defaults to UTC
 	// +optional
@@ -383,6 +451,12 @@ type TidbClusterSpec struct {
 	// +optional
 	AcrossK8s bool `json:"acrossK8s,omitempty"`
 
+	// AcrossK8sResolver configures how the services of the peer clusters and the addresses advertised by the
+	// members are resolved when the TiDB cluster is deployed across multiple Kubernetes clusters,
+	// `<service>.<namespace>.svc.<clusterDomain>` is used by default.
+	// +optional
+	AcrossK8sResolver *AcrossK8sResolver `json:"acrossK8sResolver,omitempty"`
+
 	// Cluster is the external cluster, if configured, the components in this TidbCluster will join to this configured cluster.
 	// +optional
 	Cluster *TidbClusterRef `json:"cluster,omitempty"`
@@ -391,6 +465,11 @@ type TidbClusterSpec struct {
 	// +optional
 	PDAddresses []string `json:"pdAddresses,omitempty"`
 
+	// ExternalPD is the PD cluster deployed outside of this Kubernetes cluster, if configured, no PD is
+	// managed for this TidbCluster and the other components use the endpoints of the external PD.
+	// +optional
+	ExternalPD *ExternalPDSpec `json:"externalPD,omitempty"`
+
 	// StatefulSetUpdateStrategy of TiDB cluster StatefulSets
 	// +optional
 	StatefulSetUpdateStrategy apps.StatefulSetUpdateStrategyType `json:"statefulSetUpdateStrategy,omitempty"`
@@ -424,6 +503,31 @@ type TidbClusterSpec struct {
 	// +optional
 	SuspendAction *SuspendAction `json:"suspendAction,omitempty"`
 
+	// InjectResourceHints indicates whether to inject the hints derived from the resource limits
+	// for all components: GOMAXPROCS and GOMEMLIMIT for the components written in Go except TiDB,
+	// which manages its own memory limit, and the thread pool sizes for TiKV and TiFlash if they
+	// are not configured.
+	// Note that changing it may cause the pods to be recreated.
+	// Optional: Defaults to false
+	// +optional
+	InjectResourceHints *bool `json:"injectResourceHints,omitempty"`
+
+	// UpgradePolicy makes the operator upgrade spec.version automatically to the latest
+	// release within the allowed version range during the maintenance windows.
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// NodeDrain makes the operator move the PD and TiKV leaders, and optionally the TiCDC captures,
+	// off the pods on the nodes that are going to be drained, before the pods are evicted.
+	// +optional
+	NodeDrain *NodeDrainPolicy `json:"nodeDrain,omitempty"`
+
+	// ConfigBackup makes the operator back up the StatefulSet and the config in use of a component
+	// into a ConfigMap before applying a destructive change to it, i.e. a rolling restart, a scale-in
+	// or a volume replacement, for diffing and rollback investigation.
+	// +optional
+	ConfigBackup *ConfigBackupPolicy `json:"configBackup,omitempty"`
+
 	// PreferIPv6 indicates whether to prefer IPv6 addresses for all components.
 	PreferIPv6 bool `json:"preferIPv6,omitempty"`
 
@@ -449,6 +553,186 @@ type TidbClusterStatus struct {
 	// +optional
 	// +nullable
 	Conditions []TidbClusterCondition `json:"conditions,omitempty"`
+	// SuggestedActions are the operational actions suggested by the controllers
+	// for the stuck states they have detected, e.g. a store stuck in Offline state.
+	// +optional
+	// +nullable
+	SuggestedActions []SuggestedAction `json:"suggestedActions,omitempty"`
+	// ZoneDistributions are the distributions of the PD, TiKV and TiDB members across the
+	// topology domains declared by their topology spread constraints.
+	// +optional
+	// +nullable
+	ZoneDistributions []ZoneDistribution `json:"zoneDistributions,omitempty"`
+	// SelfTest is the report of the last self-test requested by the
+	// `tidb.pingcap.com/self-test` annotation.
+	// +optional
+	// +nullable
+	SelfTest *SelfTestReport `json:"selfTest,omitempty"`
+	// ProfileCapture is the status of the last profile capture requested by the
+	// `tidb.pingcap.com/profile-capture` annotation.
+	// +optional
+	// +nullable
+	ProfileCapture *ProfileCaptureStatus `json:"profileCapture,omitempty"`
+	// PodMonitors are the names of the PodMonitors created for the components by spec.prometheusMonitor.
+	// +optional
+	// +nullable
+	PodMonitors []string `json:"podMonitors,omitempty"`
+	// DisruptionLock is the lock of the PD of this cluster shared with the heterogeneous clusters, the holder
+	// of the lock is the only one of them running a disruptive operation, e.g. upgrading or scaling in TiKV.
+	// +optional
+	// +nullable
+	DisruptionLock *DisruptionLock `json:"disruptionLock,omitempty"`
+	// Suspend is the status of the ordered suspension of the components by the suspend action.
+	// +optional
+	// +nullable
+	Suspend *ClusterSuspendStatus `json:"suspend,omitempty"`
+	// Upgrade is the status of the automated upgrade by the upgrade policy.
+	// +optional
+	// +nullable
+	Upgrade *UpgradePolicyStatus `json:"upgrade,omitempty"`
+	// ConfigConflicts are the items of the config defaults of the operator overridden by
+	// the config of the components with different values.
+	// +optional
+	// +nullable
+	ConfigConflicts []ConfigConflict `json:"configConflicts,omitempty"`
+	// StuckUpgrade is the safety report of the upgrade blocked on an unhealthy member, the upgrade
+	// can be forced to continue by setting the `tidb.pingcap.com/force-continue-upgrade` annotation
+	// to the id of the report.
+	// +optional
+	// +nullable
+	StuckUpgrade *StuckUpgradeReport `json:"stuckUpgrade,omitempty"`
+	// Clone is the progress of the clone from the cluster in spec.clusterCloneFrom.
+	// +optional
+	// +nullable
+	Clone *ClusterCloneStatus `json:"clone,omitempty"`
+	// Drifts are the child resources whose fields owned by the operator are mutated by other
+	// field managers, detected by spec.driftProtection.
+	// +optional
+	// +nullable
+	Drifts []ResourceDrift `json:"drifts,omitempty"`
+	// SyncHistory are the outcomes of the recent syncs of the cluster, the oldest first. The consecutive
+	// syncs with the same outcome are recorded once.
+	// +optional
+	// +nullable
+	SyncHistory []SyncRecord `json:"syncHistory,omitempty"`
+	// ResourceRecommendations are the recommended resources of the components by spec.resourceRecommendation.
+	// +optional
+	// +nullable
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+	// VolumeReplaces are the progresses of the volume replacements of the components by spec.enablePVCReplace,
+	// which is how the volumes are moved from a deprecated StorageClass to another one. Only the progress is
+	// reported, the data is not copied to the new volumes but rebuilt on them, see VolumeReplaceProgress.
+	// +optional
+	// +nullable
+	VolumeReplaces []VolumeReplaceProgress `json:"volumeReplaces,omitempty"`
+	// ObservedGeneration is the generation of the spec acted upon by the last sync of all the components
+	// without error, so `.status.observedGeneration == .metadata.generation` tells the spec has been applied.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// SuggestedActionType represents the kind of a stuck state detected by the controllers.
+type SuggestedActionType string
+
+const (
+	// SuggestedActionStoreStuckOffline indicates that a TiKV store stays in Offline state for too long.
+	SuggestedActionStoreStuckOffline SuggestedActionType = "StoreStuckOffline"
+	// SuggestedActionEvictLeaderBlocked indicates that the upgrade of a TiKV Pod is blocked on evicting region leaders.
+	SuggestedActionEvictLeaderBlocked SuggestedActionType = "EvictLeaderBlocked"
+	// SuggestedActionPVCPending indicates that a PVC of a component stays in Pending phase for too long.
+	SuggestedActionPVCPending SuggestedActionType = "PVCPending"
+	// SuggestedActionZoneSkewed indicates that a member should be moved to another topology domain
+	// to satisfy the topology spread constraints of its component.
+	SuggestedActionZoneSkewed SuggestedActionType = "ZoneSkewed"
+)
+
+// SuggestedAction describes a machine-readable action that can be applied by the user
+// to get a tidb cluster out of a stuck state.
+type SuggestedAction struct {
+	// Type of the stuck state.
+	Type SuggestedActionType `json:"type"`
+	// Component the action applies to.
+	// +optional
+	Component MemberType `json:"component,omitempty"`
+	// Target is the name of the object the action applies to, e.g. the name of a Pod or PVC.
+	// +optional
+	Target string `json:"target,omitempty"`
+	// A human readable message indicating details about the stuck state.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Command is the concrete command that can be applied to resolve the stuck state.
+	// +optional
+	Command string `json:"command,omitempty"`
+	// The time the stuck state was first observed.
+	// +optional
+	// +nullable
+	Since metav1.Time `json:"since,omitempty"`
+}
+
+// ZoneDistribution is the distribution of the members of a component across the
+// domains of a topology key.
+type ZoneDistribution struct {
+	// Component the distribution is computed for.
+	Component MemberType `json:"component"`
+	// TopologyKey is the node label key of the topology spread constraint.
+	TopologyKey string `json:"topologyKey"`
+	// Zones maps the topology domains to the number of members scheduled to them.
+	// +optional
+	Zones map[string]int32 `json:"zones,omitempty"`
+	// MaxSkew is the max skew allowed by the topology spread constraint.
+	MaxSkew int32 `json:"maxSkew"`
+	// Skew is the difference between the numbers of members in the most and least
+	// populated domains.
+	Skew int32 `json:"skew"`
+}
+
+// SelfTestCheckType represents a non-destructive check run by the self-test of a tidb cluster.
+type SelfTestCheckType string
+
+const (
+	// SelfTestCheckPDHealth checks that all PD members are healthy.
+	SelfTestCheckPDHealth SelfTestCheckType = "PDHealth"
+	// SelfTestCheckTiDBConnectivity checks that all TiDB members can be connected.
+	SelfTestCheckTiDBConnectivity SelfTestCheckType = "TiDBConnectivity"
+	// SelfTestCheckPeerDNS checks that the peer domain names of all members can be resolved.
+	SelfTestCheckPeerDNS SelfTestCheckType = "PeerDNS"
+	// SelfTestCheckTLSChain checks that the cluster certificates are signed by the CA and not expired.
+	SelfTestCheckTLSChain SelfTestCheckType = "TLSChain"
+	// SelfTestCheckBackupStorage checks that the storage of the latest Backup of the cluster is reachable.
+	SelfTestCheckBackupStorage SelfTestCheckType = "BackupStorage"
+)
+
+// SelfTestReport is the report of a self-test run against a tidb cluster.
+type SelfTestReport struct {
+	// Request is the value of the `tidb.pingcap.com/self-test` annotation this report answers.
+	Request string `json:"request"`
+	// Passed is true if all checks passed.
+	Passed bool `json:"passed"`
+	// The time the self-test was started.
+	// +optional
+	// +nullable
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// The time the self-test was completed, it's not set while the self-test is running.
+	// +optional
+	// +nullable
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+	// Checks are the results of the individual checks.
+	// +optional
+	Checks []SelfTestCheck `json:"checks,omitempty"`
+}
+
+// SelfTestCheck is the result of one check of a self-test.
+type SelfTestCheck struct {
+	// Type of the check.
+	Type SelfTestCheckType `json:"type"`
+	// Passed is true if the check passed.
+	Passed bool `json:"passed"`
+	// Skipped is true if the check does not apply to the cluster, e.g. TLS is not enabled.
+	// +optional
+	Skipped bool `json:"skipped,omitempty"`
+	// A human readable message indicating details about the check.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // TidbClusterCondition describes the state of a tidb cluster at a certain point.
@@ -484,12 +768,23 @@ const (
 	// - All TiKV stores are up.
 	// - All TiFlash stores are up.
 	TidbClusterReady TidbClusterConditionType = "Ready"
+	// TidbClusterZoneBalanced indicates whether the PD, TiKV and TiDB members are distributed
+	// across the topology domains as declared by their topology spread constraints.
+	TidbClusterZoneBalanced TidbClusterConditionType = "ZoneBalanced"
+	// TidbClusterCapabilitiesDegraded indicates that some features used by the cluster are not
+	// supported by the Kubernetes cluster, and the reconciliation falls back to degraded behaviors.
+	TidbClusterCapabilitiesDegraded TidbClusterConditionType = "CapabilitiesDegraded"
+	// TidbClusterHostPortConflict indicates that some pods of the cluster in the host network are
+	// placed to a node where the ports they bind are already bound by other pods.
+	TidbClusterHostPortConflict TidbClusterConditionType = "HostPortConflict"
 )
 
 // The `Type` of the component condition
 const (
 	// ComponentVolumeResizing indicates that any volume of this component is resizing.
 	ComponentVolumeResizing string = "ComponentVolumeResizing"
+	// ComponentSuspending indicates that the component is being suspended or has been suspended.
+	ComponentSuspending string = "ComponentSuspending"
 )
 
 // +k8s:openapi-gen=true
@@ -498,6 +793,14 @@ type DiscoverySpec struct {
 	*ComponentSpec              `json:",inline"`
 	corev1.ResourceRequirements `json:",inline"`
 
+	// Replicas is the number of discovery replicas serving behind the discovery service.
+	// When it's larger than 1, the PD member with the lowest ordinal bootstraps the PD cluster
+	// instead of the last registered one, as the registered members are not shared between replicas.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
 	// LivenessProbe describes actions that probe the discovery's liveness.
 	// the default behavior is like setting type as "tcp"
 	// NOTE: only used for TiDB Operator discovery now,
@@ -519,6 +822,18 @@ type PDSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	Replicas int32 `json:"replicas"`
 
+	// LearnerReplicas is the number of the non-voting learner members in addition to the replicas, e.g. the
+	// read-only followers in the other zones or the members staged before being promoted to voters.
+	// The learners are the members with the ordinals in [replicas, replicas+learnerReplicas), which join the
+	// PD cluster as learners and are promoted to voters once their ordinals are less than replicas, e.g. when
+	// one is moved from learnerReplicas to replicas. The voters are never demoted to learners.
+	// The embedded etcd of PD allows one learner by default, and PD v7.1.0 or later and start script v2
+	// are required.
+	// Optional: Defaults to 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LearnerReplicas int32 `json:"learnerReplicas,omitempty"`
+
 	// Base image of the component, image tag is now allowed during validation
 	// +kubebuilder:default=pingcap/pd
 	// +optional
@@ -544,6 +859,11 @@ type PDSpec struct {
 	// +optional
 	StorageVolumes []StorageVolume `json:"storageVolumes,omitempty"`
 
+	// LogVolume configures a dedicated volume for the PD log, so that verbose logs
+	// can not fill the data disk.
+	// +optional
+	LogVolume *LogVolumeSpec `json:"logVolume,omitempty"`
+
 	// Subdirectory within the volume to store PD Data. By default, the data
 	// is stored in the root directory of volume which is mounted at
 	// /var/lib/pd.
@@ -601,6 +921,33 @@ type PDSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	SpareVolReplaceReplicas *int32 `json:"spareVolReplaceReplicas,omitempty"`
+
+	// LeaderPriorities sets the leader priorities of the PD members, e.g. to prefer the leader
+	// in a specific zone. The first matched item is applied to each member, and the priorities
+	// of the members not matched are reset to 0. They are reconciled when the members change.
+	// +optional
+	LeaderPriorities []PDLeaderPriority `json:"leaderPriorities,omitempty"`
+
+	// EnforceRuntimeConfig indicates whether to reconcile the runtime config of PD with the
+	// schedule.* and replication.* items in spec.pd.config. If enabled, the items changed by
+	// pd-ctl or the PD API are reverted to the values in the spec.
+	// Optional: Defaults to false
+	// +optional
+	EnforceRuntimeConfig bool `json:"enforceRuntimeConfig,omitempty"`
+}
+
+// PDLeaderPriority is the leader priority of the PD members matched by the ordinals or the zone
+type PDLeaderPriority struct {
+	// Ordinals are the ordinals of the PD pods
+	// +optional
+	Ordinals []int32 `json:"ordinals,omitempty"`
+
+	// Zone is the value of the topology.kubernetes.io/zone label of the nodes the PD pods run on
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// Priority is the leader priority, the member with a higher priority is preferred to be the leader
+	Priority int `json:"priority"`
 }
 
 // +k8s:openapi-gen=true
@@ -722,11 +1069,25 @@ type TiKVSpec struct {
 	// +optional
 	LogTailer *LogTailerSpec `json:"logTailer,omitempty"`
 
+	// LogVolume configures a dedicated volume for the TiKV log, so that verbose logs
+	// can not fill the data disk.
+	// +optional
+	LogVolume *LogVolumeSpec `json:"logVolume,omitempty"`
+
 	// The storageClassName of the persistent volume for TiKV data storage.
 	// Defaults to Kubernetes default storage class.
 	// +optional
 	StorageClassName *string `json:"storageClassName,omitempty"`
 
+	// StorageProfile is the tier of the storage of TiKV data, which is expanded into the TiKV config
+	// matching the storage, e.g. the IO rate limit, the size of the unified read pool and the backup threads.
+	// The items set in the config take precedence over the ones of the profile, and the size of the read pool
+	// is capped by the cpu limit if it's set.
+	// Optional: Defaults to omitted
+	// +kubebuilder:validation:Enum=gp3;io2;local-nvme;pd-balanced;pd-ssd
+	// +optional
+	StorageProfile TiKVStorageProfile `json:"storageProfile,omitempty"`
+
 	// Subdirectory within the volume to store TiKV Data. By default, the data
 	// is stored in the root directory of volume which is mounted at
 	// /var/lib/tikv.
@@ -776,6 +1137,18 @@ type TiKVSpec struct {
 	// +optional
 	StoreLabels []string `json:"storeLabels,omitempty"`
 
+	// StoreWeights sets the leader and region weights of the TiKV stores in PD, so fewer leaders and
+	// regions are placed to the stores on the slower hardware. The first entry matching a store applies,
+	// and the weights of the stores matched by no entry are reset to 1. The weights are not managed
+	// if it's empty, so the weights are kept as they are after all the entries are removed.
+	// +optional
+	StoreWeights []TiKVStoreWeight `json:"storeWeights,omitempty"`
+
+	// SlowStoreDetection detects the stores whose slow scores reported to PD stay high, e.g. on failing
+	// disks, flags them in the status and optionally mitigates them.
+	// +optional
+	SlowStoreDetection *TiKVSlowStoreDetection `json:"slowStoreDetection,omitempty"`
+
 	// EnableNamedStatusPort enables status port(20180) in the Pod spec.
 	// If you set it to `true` for an existing cluster, the TiKV cluster will be rolling updated.
 	EnableNamedStatusPort bool `json:"enableNamedStatusPort,omitempty"`
@@ -784,6 +1157,10 @@ type TiKVSpec struct {
 	// +optional
 	ScalePolicy ScalePolicy `json:"scalePolicy,omitempty"`
 
+	// RollingUpdateStrategy is the rolling update configuration for TiKV
+	// +optional
+	RollingUpdateStrategy *RollingUpdateStrategy `json:"rollingUpdateStrategy,omitempty"`
+
 	// The default number of spare replicas to scale up when using VolumeReplace feature.
 	// In multi-az deployments with topology spread constraints you may need to set this to number of zones to avoid
 	// zone skew after volume replace (total replicas always whole multiples of zones).
@@ -791,6 +1168,307 @@ type TiKVSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	SpareVolReplaceReplicas *int32 `json:"spareVolReplaceReplicas,omitempty"`
+
+	// Witness is the group of the TiKV witness stores, which keep the raft logs of the regions
+	// without the full data, so that the cluster can run with 2 full replicas and 1 witness replica.
+	// +optional
+	Witness *TiKVWitnessSpec `json:"witness,omitempty"`
+
+	// DeletionSafetyLevel is the strictness of the check of the regions in PD before a TiKV pod
+	// is deleted by the upgrade, scale-in, failover or volume replacement.
+	// "None": the regions are not checked.
+	// "Quorum": the regions with a replica on the store must keep a quorum of healthy voters
+	// on the other stores.
+	// down or pending peers on the other stores, or fewer voters than required by the placement rules.
+	// down or pending peers on the other stores, or fewer voters than max-replicas.
+	// Optional: Defaults to None
+	// +kubebuilder:validation:Enum:="";"None";"Quorum";"Strict"
+	// +optional
+	DeletionSafetyLevel TiKVDeletionSafetyLevel `json:"deletionSafetyLevel,omitempty"`
+
+	// ScaleOutBalancePolicy tunes the scheduling of PD temporarily when TiKV is scaled out by
+	// multiple replicas, so that the regions are rebalanced to the new stores faster.
+	// The PD settings are reverted after the rebalancing.
+	// +optional
+	ScaleOutBalancePolicy *ScaleOutBalancePolicy `json:"scaleOutBalancePolicy,omitempty"`
+
+	// Encryption configures the encryption at rest of TiKV, which overrides `security.encryption`
+	// in the config. The config of TiKV must not be nil to enable it.
+	// +optional
+	Encryption *TiKVEncryptionSpec `json:"encryption,omitempty"`
+}
+
+// TiKVEncryptionSpec is the encryption at rest of TiKV. The data files are encrypted with the data keys,
+// which are rotated by TiKV every data key rotation period, and the data keys are encrypted with the master key.
+// When the master key is changed, the old one is set as the previous master key and TiKV is rolling restarted
+// to re-encrypt the data keys with the new one.
+// +k8s:openapi-gen=true
+type TiKVEncryptionSpec struct {
+	// Method is the encryption method of the data files.
+	// Optional: Defaults to aes256-ctr
+	// +kubebuilder:validation:Enum:="";"aes128-ctr";"aes192-ctr";"aes256-ctr";"sm4-ctr"
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// DataKeyRotationPeriod is how often TiKV rotates the data key, e.g. "7d".
+	// Optional: Defaults to the default of TiKV, 7d
+	// +optional
+	DataKeyRotationPeriod string `json:"dataKeyRotationPeriod,omitempty"`
+
+	// MasterKey is the master key the data keys are encrypted with.
+	MasterKey TiKVMasterKeySource `json:"masterKey"`
+}
+
+// TiKVMasterKeySource is where the master key of TiKV comes from, only one of the sources can be set.
+// +k8s:openapi-gen=true
+type TiKVMasterKeySource struct {
+	// AWSKMS uses a CMK of AWS KMS as the master key.
+	// +optional
+	AWSKMS *TiKVAWSKMSMasterKey `json:"awsKMS,omitempty"`
+
+	// GCPKMS uses a key of GCP KMS as the master key.
+	// +optional
+	GCPKMS *TiKVGCPKMSMasterKey `json:"gcpKMS,omitempty"`
+
+	// Vault reads the master key from the KV secrets engine (v2) of Vault by the Vault Agent Injector,
+	// which must be installed in the Kubernetes cluster.
+	// +optional
+	Vault *TiKVVaultMasterKey `json:"vault,omitempty"`
+
+	// Secret reads the master key from a Secret, which must contain a 256-bit key encoded as hex string
+	// and end with a newline. It's NOT recommended in production.
+	// +optional
+	Secret *TiKVSecretMasterKey `json:"secret,omitempty"`
+}
+
+// TiKVAWSKMSMasterKey is a master key of AWS KMS, TiKV should be granted the access to it by IAM.
+// +k8s:openapi-gen=true
+type TiKVAWSKMSMasterKey struct {
+	// KeyID is the id of the CMK.
+	KeyID string `json:"keyID"`
+	// Region is the region of the CMK.
+	Region string `json:"region"`
+	// Endpoint is the endpoint of KMS, which is only required for a non-default endpoint.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// TiKVGCPKMSMasterKey is a master key of GCP KMS, it's supported since TiKV v7.5.
+// +k8s:openapi-gen=true
+type TiKVGCPKMSMasterKey struct {
+	// KeyID is the resource name of the key, e.g.
+	// `projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>`.
+	KeyID string `json:"keyID"`
+	// CredentialsSecret is the name of the Secret which contains the credentials of a service account
+	// in the key `credentials.json`. The default credentials of the pod are used if it's not set.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// TiKVVaultMasterKey is a master key stored in Vault.
+// +k8s:openapi-gen=true
+type TiKVVaultMasterKey struct {
+	// Role is the Vault role the pods of TiKV authenticate as.
+	Role string `json:"role"`
+	// Path is the path of the secret in Vault, e.g. `secret/data/tikv`.
+	Path string `json:"path"`
+	// Field is the field of the secret which contains the key encoded as hex string.
+	// Optional: Defaults to key
+	// +optional
+	Field string `json:"field,omitempty"`
+}
+
+// TiKVSecretMasterKey is a master key stored in a Secret.
+// +k8s:openapi-gen=true
+type TiKVSecretMasterKey struct {
+	// Name is the name of the Secret.
+	Name string `json:"name"`
+	// Key is the key of the Secret which contains the master key.
+	// Optional: Defaults to master-key
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// TiKVEncryptionPhase is the phase of the encryption at rest of TiKV
+type TiKVEncryptionPhase string
+
+const (
+	// TiKVEncryptionReady means all the TiKVs run with the master key.
+	TiKVEncryptionReady TiKVEncryptionPhase = "Ready"
+	// TiKVEncryptionRotating means the master key is being rotated, TiKV is rolling restarted with
+	// the old master key as the previous master key until all the TiKVs run with the new one.
+	TiKVEncryptionRotating TiKVEncryptionPhase = "Rotating"
+)
+
+// TiKVEncryptionStatus is the status of the encryption at rest of TiKV
+type TiKVEncryptionStatus struct {
+	// Phase is the phase of the encryption.
+	Phase TiKVEncryptionPhase `json:"phase"`
+	// MasterKey is the master key TiKV runs with or is being rotated to.
+	MasterKey TiKVMasterKeySource `json:"masterKey"`
+	// PreviousMasterKey is the master key before the rotation, which is kept in the config of TiKV
+	// during the rotation and removed once the rotation completes.
+	// +optional
+	PreviousMasterKey *TiKVMasterKeySource `json:"previousMasterKey,omitempty"`
+	// RotationRevision is the update revision of the StatefulSet when the last rotation started.
+	// +optional
+	RotationRevision string `json:"rotationRevision,omitempty"`
+	// LastRotationStartTime is the time the last rotation of the master key started.
+	// +optional
+	LastRotationStartTime *metav1.Time `json:"lastRotationStartTime,omitempty"`
+	// LastRotationCompletionTime is the time the last rotation of the master key completed.
+	// +optional
+	LastRotationCompletionTime *metav1.Time `json:"lastRotationCompletionTime,omitempty"`
+}
+
+// TiKVDeletionSafetyLevel is the strictness of the region check before a TiKV pod is deleted
+type TiKVDeletionSafetyLevel string
+
+const (
+	// TiKVDeletionSafetyNone does not check the regions
+	TiKVDeletionSafetyNone TiKVDeletionSafetyLevel = "None"
+	// TiKVDeletionSafetyQuorum checks that the regions keep a quorum of healthy voters
+	TiKVDeletionSafetyQuorum TiKVDeletionSafetyLevel = "Quorum"
+	// TiKVDeletionSafetyStrict checks that the regions are fully replicated and healthy
+	TiKVDeletionSafetyStrict TiKVDeletionSafetyLevel = "Strict"
+)
+
+// ScaleOutBalancePolicy is the policy to tune the scheduling of PD while the regions are
+// rebalanced to the new TiKV stores after scaling out.
+// Only the add-peer store limit of the new stores is raised, so the removal of the peers
+// on the existing stores, which serve the foreground traffic, is not sped up.
+// +k8s:openapi-gen=true
+type ScaleOutBalancePolicy struct {
+	// MinReplicas is the minimum number of the replicas scaled out at once to tune the scheduling.
+	// Optional: Defaults to 2
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// StoreLimit is the add-peer store limit of the new stores during the rebalancing,
+	// which is the number of the peers added to a store per minute.
+	// Optional: Defaults to 50
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StoreLimit *int32 `json:"storeLimit,omitempty"`
+
+	// RegionScheduleLimit is the schedule.region-schedule-limit of PD during the rebalancing.
+	// Optional: Defaults to not changed
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RegionScheduleLimit *int64 `json:"regionScheduleLimit,omitempty"`
+
+	// BalancedPercent is the percentage of the average region count of the stores that every
+	// new store must reach for the rebalancing to be complete.
+	// Optional: Defaults to 90
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	BalancedPercent *int32 `json:"balancedPercent,omitempty"`
+
+	// Timeout is the max duration of the rebalancing, the PD settings are reverted after it
+	// even if the new stores are not balanced.
+	// Optional: Defaults to 2h
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// TiKVStoreWeight is the leader and region weights of the TiKV stores selected by their pods.
+// +k8s:openapi-gen=true
+type TiKVStoreWeight struct {
+	// PodNames selects the stores by the names of their pods.
+	// +optional
+	PodNames []string `json:"podNames,omitempty"`
+	// NodeSelector selects the stores by the labels of the nodes their pods run on.
+	// A store is selected if it's selected by either PodNames or NodeSelector.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// LeaderWeight is the weight of the stores in the leader balancing, a store with a lower weight
+	// gets fewer leaders.
+	// Optional: Defaults to 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LeaderWeight *float64 `json:"leaderWeight,omitempty"`
+	// RegionWeight is the weight of the stores in the region balancing, a store with a lower weight
+	// gets fewer regions.
+	// Optional: Defaults to 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RegionWeight *float64 `json:"regionWeight,omitempty"`
+}
+
+// TiKVSlowStoreMitigation is the mitigation applied to the slow TiKV stores
+type TiKVSlowStoreMitigation string
+
+const (
+	// TiKVSlowStoreMitigationNone only flags the slow stores in the status
+	TiKVSlowStoreMitigationNone TiKVSlowStoreMitigation = "None"
+	// TiKVSlowStoreMitigationLeaderWeight reduces the leader weights of the slow stores, so most leaders
+	// are moved out of them by the balancing of PD
+	TiKVSlowStoreMitigationLeaderWeight TiKVSlowStoreMitigation = "LeaderWeight"
+	// TiKVSlowStoreMitigationEvictLeader evicts all the leaders out of the slow stores
+	TiKVSlowStoreMitigationEvictLeader TiKVSlowStoreMitigation = "EvictLeader"
+)
+
+// TiKVSlowStoreDetection is the detection of the slow TiKV stores by the slow scores of the stores
+// reported to PD, which range from 1 to 100 and are 1 for the healthy stores.
+// +k8s:openapi-gen=true
+type TiKVSlowStoreDetection struct {
+	// SlowScoreThreshold is the slow score at and above which a store is slow.
+	// Optional: Defaults to 80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SlowScoreThreshold *int32 `json:"slowScoreThreshold,omitempty"`
+	// Duration is the time the slow score of a store stays at or above the threshold before the store is
+	// flagged as slow, so the short spikes are ignored.
+	// Optional: Defaults to 5m
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Mitigation is applied to the slow stores, and is reverted once their slow scores fall below the
+	// threshold. It requires the feature gate SlowStoreMitigation of tidb-controller-manager, and is
+	// not applied during the upgrade of TiKV.
+	// Optional: Defaults to None
+	// +kubebuilder:validation:Enum=None;LeaderWeight;EvictLeader
+	// +optional
+	Mitigation TiKVSlowStoreMitigation `json:"mitigation,omitempty"`
+	// MaxMitigatedStores is the max number of the stores mitigated at the same time, the other slow
+	// stores are only flagged, so the leaders are not moved out of most stores if the whole cluster is slow.
+	// Optional: Defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxMitigatedStores *int32 `json:"maxMitigatedStores,omitempty"`
+}
+
+// ConfigBackupPolicy is the policy of the config backups taken before the destructive changes
+// +k8s:openapi-gen=true
+type ConfigBackupPolicy struct {
+	// Limit is the max number of the backups kept for each component, the oldest ones are deleted
+	// Optional: Defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Limit *int32 `json:"limit,omitempty"`
+}
+
+// TiKVWitnessSpec contains details of the TiKV witness stores.
+// The witness stores share the spec of TiKV, except the fields below, and the placement rules
+// are set in PD to place one witness replica of each region on the witness stores.
+// +k8s:openapi-gen=true
+type TiKVWitnessSpec struct {
+	// The resource requirements of the witness stores, the storage request is the size of the data volume.
+	// Optional: Defaults to the resource requirements of TiKV
+	// +optional
+	corev1.ResourceRequirements `json:",inline"`
+
+	// The desired ready replicas of the witness stores.
+	// Set it to 0 to remove the placement rules of the witness replicas before removing the witness stores.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+
+	// The storageClassName of the persistent volume for the witness data storage.
+	// Optional: Defaults to the storageClassName of TiKV
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
 }
 
 // TiFlashSpec contains details of TiFlash members
@@ -851,16 +1529,86 @@ type TiFlashSpec struct {
 	// ScalePolicy is the scale configuration for TiFlash
 	// +optional
 	ScalePolicy ScalePolicy `json:"scalePolicy,omitempty"`
+
+	// TableReplicas declares the TiFlash replicas of the databases and tables, the
+	// operator sets the replicas by SQL and reports the sync progress in the status
+	// +optional
+	TableReplicas *TiFlashTableReplicas `json:"tableReplicas,omitempty"`
+
+	// ScaleInPreflight checks the TiFlash replicas of the tables before removing the TiFlash stores.
+	// The stores are removed in parallel as long as the remaining Up stores can hold the replicas
+	// required by the placement rules in PD and information_schema.tiflash_replica, and no new store
+	// is removed while the replicas of any table are unavailable.
+	// spec.tiflash.scalePolicy.scaleInParallelism caps the parallelism if it's set.
+	// +optional
+	ScaleInPreflight *TiFlashScaleInPreflight `json:"scaleInPreflight,omitempty"`
 }
 
-// TiCDCSpec contains details of TiCDC members
+// TiFlashScaleInPreflight is the TiDB user to check the TiFlash replicas before scaling in TiFlash
 // +k8s:openapi-gen=true
-type TiCDCSpec struct {
-	ComponentSpec               `json:",inline"`
-	corev1.ResourceRequirements `json:",inline"`
+type TiFlashScaleInPreflight struct {
+	// User is the TiDB user to query information_schema.tiflash_replica
+	// Optional: Defaults to root
+	// +optional
+	User string `json:"user,omitempty"`
 
-	// Specify a Service Account for TiCDC
-	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// SecretName is the name of the secret with the password of the user in the
+	// `password` key, the password is empty if not set
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// TLSClientSecretName is the name of secret which stores tidb server client certificate
+	// Optional: Defaults to nil
+	// +optional
+	TLSClientSecretName *string `json:"tlsClientSecretName,omitempty"`
+}
+
+// TiFlashTableReplicas is the TiFlash replicas of the databases and tables
+// +k8s:openapi-gen=true
+type TiFlashTableReplicas struct {
+	// User is the TiDB user to set the TiFlash replicas, the user needs the ALTER
+	// privilege of the tables
+	// Optional: Defaults to root
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// SecretName is the name of the secret with the password of the user in the
+	// `password` key, the password is empty if not set
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// TLSClientSecretName is the name of secret which stores tidb server client certificate
+	// Optional: Defaults to nil
+	// +optional
+	TLSClientSecretName *string `json:"tlsClientSecretName,omitempty"`
+
+	// Tables are the databases and tables with TiFlash replicas
+	Tables []TiFlashTableReplica `json:"tables"`
+}
+
+// TiFlashTableReplica is the number of the TiFlash replicas of a database or table
+// +k8s:openapi-gen=true
+type TiFlashTableReplica struct {
+	// Database is the name of the database
+	Database string `json:"database"`
+
+	// Table is the name of the table, all the tables of the database are set if empty
+	// +optional
+	Table string `json:"table,omitempty"`
+
+	// Count is the number of the TiFlash replicas, the replicas are removed if it is 0
+	// +kubebuilder:validation:Minimum=0
+	Count int32 `json:"count"`
+}
+
+// TiCDCSpec contains details of TiCDC members
+// +k8s:openapi-gen=true
+type TiCDCSpec struct {
+	ComponentSpec               `json:",inline"`
+	corev1.ResourceRequirements `json:",inline"`
+
+	// Specify a Service Account for TiCDC
+	ServiceAccount string `json:"serviceAccount,omitempty"`
 
 	// The desired ready replicas
 	// +kubebuilder:validation:Minimum=0
@@ -992,6 +1740,48 @@ type LogTailerSpec struct {
 	UseSidecar bool `json:"useSidecar,omitempty"`
 }
 
+// LogVolumeSpec configures where the log file of a component is written
+// +k8s:openapi-gen=true
+type LogVolumeSpec struct {
+	// VolumeName is the name of the volume in `storageVolumes` or `additionalVolumeMounts`
+	// used to store the log file.
+	// Optional: Defaults to an emptyDir volume
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+
+	// SizeLimit is the size limit of the emptyDir volume, it's ignored if VolumeName is set.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// Rotation configures the rotation of the log file
+	// +optional
+	Rotation *LogRotationSpec `json:"rotation,omitempty"`
+
+	// Tailer configures a sidecar container which tails the log file to stdout
+	// Optional: No log tailer by default
+	// +optional
+	Tailer *LogTailerSpec `json:"tailer,omitempty"`
+}
+
+// LogRotationSpec configures the rotation of a log file
+// +k8s:openapi-gen=true
+type LogRotationSpec struct {
+	// MaxSize is the max size of a log file in MB before it's rotated
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSize *int64 `json:"maxSize,omitempty"`
+
+	// MaxDays is the max number of days to retain the rotated log files
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDays *int64 `json:"maxDays,omitempty"`
+
+	// MaxBackups is the max number of rotated log files to retain
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBackups *int64 `json:"maxBackups,omitempty"`
+}
+
 // InitContainerSpec contains basic spec about a init container
 //
 // +k8s:openapi-gen=true
@@ -1035,6 +1825,15 @@ type TiDBSpec struct {
 	// +optional
 	Service *TiDBServiceSpec `json:"service,omitempty"`
 
+	// Services defines the named client services of TiDB cluster in addition to the one of `service`,
+	// e.g. an internal load balancer, an external load balancer and the services of node groups.
+	// The services removed from the list are deleted.
+	// Optional: Defaults to omitted
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Services []TiDBService `json:"services,omitempty"`
+
 	// Whether enable TiDB Binlog, it is encouraged to not set this field and rely on the default behavior
 	// Optional: Defaults to true if PumpSpec is non-nil, otherwise false
 	// +optional
@@ -1059,6 +1858,16 @@ type TiDBSpec struct {
 	// +optional
 	SlowLogTailer *TiDBSlowLogTailerSpec `json:"slowLogTailer,omitempty"`
 
+	// LogShipping deploys a log shipper sidecar which ships the slow log, and the audit log if
+	// configured, to Loki, S3 or Kafka. It replaces the slow log tailer if set.
+	// +optional
+	LogShipping *TiDBLogShippingSpec `json:"logShipping,omitempty"`
+
+	// LogVolume configures a dedicated volume for the TiDB log, so that verbose logs
+	// can not fill the data disk.
+	// +optional
+	LogVolume *LogVolumeSpec `json:"logVolume,omitempty"`
+
 	// Whether enable the TLS connection between the SQL client and TiDB server
 	// Optional: Defaults to nil
 	// +optional
@@ -1112,6 +1921,21 @@ type TiDBSpec struct {
 	// +optional
 	ScalePolicy ScalePolicy `json:"scalePolicy,omitempty"`
 
+	// RollingUpdateStrategy is the rolling update configuration for TiDB.
+	// +optional
+	RollingUpdateStrategy *RollingUpdateStrategy `json:"rollingUpdateStrategy,omitempty"`
+
+	// Canary upgrades a subset of the pods first and soaks it before the others are upgraded,
+	// the canary is rolled back automatically if its SLOs are breached.
+	// +optional
+	Canary *TiDBCanaryStrategy `json:"canary,omitempty"`
+
+	// LoadBalancerReadiness adds a readiness gate to the TiDB pods, whose condition is set to true only after
+	// the pod is registered and healthy in the external load balancer, so that the rolling upgrade waits for
+	// the registration before proceeding to the next pod.
+	// +optional
+	LoadBalancerReadiness *TiDBLoadBalancerReadiness `json:"loadBalancerReadiness,omitempty"`
+
 	// CustomizedStartupProbe is the customized startup probe for TiDB.
 	// You can provide your own startup probe for TiDB.
 	// The image will be an init container, and the tidb-server container will copy the probe binary from it, and execute it.
@@ -1172,6 +1996,8 @@ const (
 	TCPProbeType string = "tcp"
 	// CommandProbeType represents the readiness prob method with arbitrary unix `exec` call format commands
 	CommandProbeType string = "command"
+	// GRPCProbeType represents the readiness prob method with the gRPC health checking protocol
+	GRPCProbeType string = "grpc"
 )
 
 // Probe contains details of probing tidb.
@@ -1183,9 +2009,13 @@ type Probe struct {
 	// "command" will probe the status api of tidb.
 	// This will use curl command to request tidb, before v4.0.9 there is no curl in the image,
 	// So do not use this before v4.0.9.
-	// +kubebuilder:validation:Enum=tcp;command
+	//
+	// "grpc" will use the Kubernetes native gRPC probe to check whether the gRPC server
+	// of tikv or tiflash is serving. It requires Kubernetes v1.24+ and is not supported
+	// when TLS is enabled between the cluster components.
+	// +kubebuilder:validation:Enum=tcp;command;grpc
 	// +optional
-	Type *string `json:"type,omitempty"` // tcp or command
+	Type *string `json:"type,omitempty"` // tcp, command or grpc
 	// Number of seconds after the container has started before liveness probes are initiated.
 	// Default to 10 seconds.
 	// +kubebuilder:validation:Minimum=0
@@ -1270,6 +2100,103 @@ type TiDBSlowLogTailerSpec struct {
 	UseSidecar bool `json:"useSidecar,omitempty"`
 }
 
+// TiDBLogShippingSpec configures the fluent-bit compatible log shipper sidecar of TiDB
+// +k8s:openapi-gen=true
+type TiDBLogShippingSpec struct {
+	corev1.ResourceRequirements `json:",inline"`
+
+	// Image of the log shipper, it must be compatible with fluent-bit
+	// Optional: Defaults to fluent/fluent-bit:3.1.9
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// AuditLogFile is the absolute path of the audit log file of TiDB, it must be in a volume
+	// mounted to TiDB, e.g. a volume in `storageVolumes` or `additionalVolumeMounts`.
+	// Optional: The audit log is not shipped by default
+	// +optional
+	AuditLogFile string `json:"auditLogFile,omitempty"`
+
+	// Outputs are the destinations the logs are shipped to
+	// +kubebuilder:validation:MinItems=1
+	Outputs []LogShippingOutput `json:"outputs"`
+
+	// Env of the log shipper, e.g. AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for the S3 outputs
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// If true, we use native sidecar feature to run the log shipper
+	// +optional
+	UseSidecar bool `json:"useSidecar,omitempty"`
+}
+
+// LogShippingOutput is a destination of the shipped logs, exactly one of loki, s3 and kafka must be set
+// +k8s:openapi-gen=true
+type LogShippingOutput struct {
+	// +optional
+	Loki *LokiLogOutput `json:"loki,omitempty"`
+	// +optional
+	S3 *S3LogOutput `json:"s3,omitempty"`
+	// +optional
+	Kafka *KafkaLogOutput `json:"kafka,omitempty"`
+}
+
+// LokiLogOutput ships the logs to Loki, the streams are labeled with `cluster`, `instance` and `log`
+// +k8s:openapi-gen=true
+type LokiLogOutput struct {
+	// Host of the Loki server
+	Host string `json:"host"`
+	// Port of the Loki server
+	// Optional: Defaults to 3100
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// TenantID of the logs if Loki runs in multi-tenant mode
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+	// Labels are the extra labels of the log streams
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// TLS enables TLS for the connection to Loki
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+}
+
+// S3LogOutput uploads the logs to S3 compatible object storage
+// +k8s:openapi-gen=true
+type S3LogOutput struct {
+	// Bucket of the objects
+	Bucket string `json:"bucket"`
+	// Region of the bucket
+	Region string `json:"region"`
+	// Endpoint of the S3 compatible storage
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Prefix of the object keys
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// KafkaLogOutput produces the logs to a Kafka topic
+// +k8s:openapi-gen=true
+type KafkaLogOutput struct {
+	// Brokers of the Kafka cluster
+	// +kubebuilder:validation:MinItems=1
+	Brokers []string `json:"brokers"`
+	// Topic of the logs
+	Topic string `json:"topic"`
+}
+
+// AffinityPreset is a preset of the pod anti-affinity rules of a component
+type AffinityPreset string
+
+const (
+	// AffinityPresetRequiredPerNode schedules at most one pod of the component to each node
+	AffinityPresetRequiredPerNode AffinityPreset = "required-per-node"
+	// AffinityPresetPreferredPerNode prefers to schedule the pods of the component to different nodes
+	AffinityPresetPreferredPerNode AffinityPreset = "preferred-per-node"
+	// AffinityPresetRequiredPerZone schedules at most one pod of the component to each zone
+	AffinityPresetRequiredPerZone AffinityPreset = "required-per-zone"
+)
+
 // ComponentSpec is the base spec of each component, the fields should always accessed by the Basic<Component>Spec() method to respect the cluster-level properties
 // +k8s:openapi-gen=true
 type ComponentSpec struct {
@@ -1302,16 +2229,42 @@ type ComponentSpec struct {
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 
+	// AffinityPreset expands into the pod anti-affinity rules that spread the pods of the component,
+	// whose label selector always matches the peer pods of the component.
+	// The rules are appended to the affinity of the component.
+	// +kubebuilder:validation:Enum:="required-per-node";"preferred-per-node";"required-per-zone"
+	// +optional
+	AffinityPreset *AffinityPreset `json:"affinityPreset,omitempty"`
+
 	// PriorityClassName of the component. Override the cluster-level one if present
 	// Optional: Defaults to cluster-level setting
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 
+	// PreemptionPolicy of the component. Override the cluster-level one if present.
+	// It requires the PriorityClassName of the component or the cluster and must match the
+	// preemptionPolicy of that PriorityClass, otherwise the admission rejects the pods
+	// Optional: Defaults to cluster-level setting
+	// +optional
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
+	// RuntimeClassName of the component, e.g. to run the component in a sandboxed container runtime
+	// such as kata. Override the cluster-level one if present
+	// Optional: Defaults to cluster-level setting
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
 	// SchedulerName of the component. Override the cluster-level one if present
 	// Optional: Defaults to cluster-level setting
 	// +optional
 	SchedulerName *string `json:"schedulerName,omitempty"`
 
+	// SchedulingGates of the component, the pods are not scheduled until the gates are removed from them.
+	// Override the cluster-level ones if non-empty
+	// Optional: Defaults to cluster-level setting
+	// +optional
+	SchedulingGates []corev1.PodSchedulingGate `json:"schedulingGates,omitempty"`
+
 	// NodeSelector of the component. Merged into the cluster-level nodeSelector if non-empty
 	// Optional: Defaults to cluster-level setting
 	// +optional
@@ -1361,6 +2314,15 @@ type ComponentSpec struct {
 	// +optional
 	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
 
+	// AdditionalArgs are appended to the command line arguments generated by the start
+	// script of the component, e.g. `--metrics-flush-interval=10s` for TiKV.
+	// `$(VAR_NAME)` is expanded to the environment variable of the container, which can
+	// be defined in `env` with the downward API, e.g. `--advertise-host=$(POD_IP)`.
+	// Arguments must not contain whitespaces, quotes or other shell special characters.
+	// Changing it causes a rolling update of the component.
+	// +optional
+	AdditionalArgs []string `json:"additionalArgs,omitempty"`
+
 	// Init containers of the components
 	// +optional
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
@@ -1390,6 +2352,10 @@ type ComponentSpec struct {
 	// +optional
 	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
 
+	// HostAliases is an optional list of hosts and IPs that will be injected into the pod's hosts file.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
 	// Optional duration in seconds the pod needs to terminate gracefully. May be decreased in delete request.
 	// Value must be non-negative integer. The value zero indicates delete immediately.
 	// If this value is nil, the default grace period will be used instead.
@@ -1423,6 +2389,11 @@ type ComponentSpec struct {
 	// +optional
 	SuspendAction *SuspendAction `json:"suspendAction,omitempty"`
 
+	// InjectResourceHints indicates whether to inject the hints derived from the resource limits
+	// for the component. Override the cluster-level setting if present.
+	// +optional
+	InjectResourceHints *bool `json:"injectResourceHints,omitempty"`
+
 	// ReadinessProbe describes actions that probe the components' readiness.
 	// the default behavior is like setting type as "tcp"
 	// +optional
@@ -1520,6 +2491,27 @@ type TiDBServiceSpec struct {
 	AdditionalPorts []corev1.ServicePort `json:"additionalPorts,omitempty"`
 }
 
+// TiDBService is a named client service of TiDB
+// +k8s:openapi-gen=true
+type TiDBService struct {
+	// Name of the service, the service is named `<cluster>-tidb-<name>`.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Labels of the service, which are merged with the labels of TiDB.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations of the service.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Spec of the service. The selector is merged with the one of the TiDB pods, so the pods of a
+	// node group can be selected by their labels. The ports default to the MySQL port and the status port of TiDB.
+	// +optional
+	Spec corev1.ServiceSpec `json:"spec,omitempty"`
+}
+
 // (Deprecated) Service represent service type used in TidbCluster
 // +k8s:openapi-gen=false
 type Service struct {
@@ -1532,6 +2524,692 @@ type Service struct {
 // +k8s:openapi-gen=true
 type SuspendAction struct {
 	SuspendStatefulSet bool `json:"suspendStatefulSet,omitempty"`
+
+	// Component limits the suspension to the component and the components depending on it.
+	// The components are suspended in the dependency order, e.g. TiProxy, TiDB, TiCDC, TiFlash,
+	// TiKV, Pump and PD for TidbCluster, and resumed in the reverse order once the suspend action
+	// is removed. For example, `tikv` suspends all the components except Pump and PD.
+	// Optional: Defaults to all the components
+	// +optional
+	Component MemberType `json:"component,omitempty"`
+
+	// GracePeriod is the time to wait after a component begins to be suspended before its
+	// resources are deleted, e.g. to drain the connections to TiDB.
+	// Optional: Defaults to 0
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// ClusterSuspendPhase is the phase of the suspension of a cluster.
+type ClusterSuspendPhase string
+
+const (
+	// ClusterSuspending means the components are being suspended one by one.
+	ClusterSuspending ClusterSuspendPhase = "Suspending"
+	// ClusterSuspended means all the components to be suspended have been suspended.
+	ClusterSuspended ClusterSuspendPhase = "Suspended"
+	// ClusterResuming means the components are being resumed one by one in the reverse order.
+	ClusterResuming ClusterSuspendPhase = "Resuming"
+)
+
+// ClusterSuspendStatus is the status of the ordered suspension of a cluster.
+type ClusterSuspendStatus struct {
+	// Phase is the phase of the suspension.
+	Phase ClusterSuspendPhase `json:"phase"`
+	// Component is the component being suspended or resumed.
+	// +optional
+	Component MemberType `json:"component,omitempty"`
+	// SuspendedComponents are the components that have been suspended.
+	// +optional
+	SuspendedComponents []MemberType `json:"suspendedComponents,omitempty"`
+	// LastTransitionTime is the last time the phase transitioned.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// PropagatePolicy selects the labels and annotations propagated from a TidbCluster to its resources.
+// A key ending with "*" selects all the keys with the prefix, e.g. "example.com/*".
+// The keys reserved by Kubernetes and the operator, e.g. "app.kubernetes.io/*" and "tidb.pingcap.com/*",
+// are never propagated.
+//
+// +k8s:openapi-gen=true
+type PropagatePolicy struct {
+	// Labels are the keys of the labels to propagate
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Annotations are the keys of the annotations to propagate
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// VeleroSpec describes how the resources of a TidbCluster are prepared for Velero.
+//
+// +k8s:openapi-gen=true
+type VeleroSpec struct {
+	// ExcludeGeneratedResources excludes the statefulsets, pods, services, configmaps and deployments
+	// generated by the operator from the Velero backups, so that only the TidbCluster and the PVCs are
+	// backed up and the rest are generated again after the restore. The pods of the components with
+	// hooks are not excluded, as Velero only runs the hooks of the pods backed up.
+	// Optional: Defaults to true
+	// +optional
+	ExcludeGeneratedResources *bool `json:"excludeGeneratedResources,omitempty"`
+
+	// Hooks are the Velero backup hooks run in the pods of the components
+	// +optional
+	Hooks []VeleroBackupHook `json:"hooks,omitempty"`
+
+	// RestoreCompatibility reconciles the statefulsets and PVCs restored by Velero once, the owner
+	// references are pointed to the restored TidbCluster, and the IDs of the members and stores in
+	// the labels of the PVCs are removed until they are set by the new pods.
+	// +optional
+	RestoreCompatibility bool `json:"restoreCompatibility,omitempty"`
+}
+
+// VeleroBackupHook is a Velero backup hook run in the pods of a component.
+//
+// +k8s:openapi-gen=true
+type VeleroBackupHook struct {
+	// Component is the component whose pods run the hook, e.g. tikv
+	Component MemberType `json:"component"`
+
+	// Container runs the hook
+	// Optional: Defaults to the main container of the component
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// PreCommand is run before the pod is backed up
+	// +optional
+	PreCommand []string `json:"preCommand,omitempty"`
+
+	// PostCommand is run after the pod is backed up
+	// +optional
+	PostCommand []string `json:"postCommand,omitempty"`
+
+	// Timeout of the commands
+	// Optional: Defaults to the timeout of Velero
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// OnError is what Velero does when the commands fail, Continue or Fail
+	// Optional: Defaults to Fail
+	// +kubebuilder:validation:Enum:="";"Continue";"Fail"
+	// +optional
+	OnError string `json:"onError,omitempty"`
+}
+
+// NodeDrainPolicy describes how the operator prepares the pods on the nodes that are going to be drained.
+// A node is going to be drained if it is cordoned, or it has any of the taints.
+//
+// +k8s:openapi-gen=true
+type NodeDrainPolicy struct {
+	// TaintKeys are the keys of the taints that signal the node is going to be drained, e.g. the taints
+	// of the cluster autoscaler or the node termination handlers.
+	// +optional
+	TaintKeys []string `json:"taintKeys,omitempty"`
+
+	// MigrateTiCDCCaptures moves the ownership and the tables off the TiCDC captures on the draining nodes.
+	// +optional
+	MigrateTiCDCCaptures bool `json:"migrateTiCDCCaptures,omitempty"`
+}
+
+// UpgradePolicy describes how the operator upgrades a cluster automatically.
+//
+// +k8s:openapi-gen=true
+type UpgradePolicy struct {
+	// VersionRange is the range of the versions the cluster can be upgraded to, in the semver
+	// constraint format, e.g. "~7.5" for the patch releases of v7.5 or ">= 7.1.0, < 8.0.0".
+	VersionRange string `json:"versionRange"`
+
+	// Versions are the released versions the operator chooses from, e.g. v7.5.1, the latest
+	// one within VersionRange that is newer than spec.version is upgraded to.
+	// The components with their own version are not upgraded.
+	// +kubebuilder:validation:MinItems=1
+	Versions []string `json:"versions"`
+
+	// Schedule is the start time of the maintenance windows in the cron format, e.g. "0 2 * * 6".
+	Schedule string `json:"schedule"`
+
+	// Duration is the duration of the maintenance windows, the upgrade only begins
+	// within the windows but may last after the windows end.
+	// Optional: Defaults to 2h
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Paused stops the automated upgrade, the decisions are still recorded.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// UpgradeDecisionAction is the action decided by the upgrade policy.
+type UpgradeDecisionAction string
+
+const (
+	// UpgradeDecisionUpgrade means the cluster is upgraded to the target version.
+	UpgradeDecisionUpgrade UpgradeDecisionAction = "Upgrade"
+	// UpgradeDecisionSkip means a newer version is available but the cluster is not upgraded.
+	UpgradeDecisionSkip UpgradeDecisionAction = "Skip"
+)
+
+// UpgradeDecision is a decision made by the upgrade policy.
+type UpgradeDecision struct {
+	// Time is the time the decision was made.
+	Time metav1.Time `json:"time"`
+	// Action is the decided action.
+	Action UpgradeDecisionAction `json:"action"`
+	// FromVersion is the version of the cluster when the decision was made.
+	// +optional
+	FromVersion string `json:"fromVersion,omitempty"`
+	// ToVersion is the target version.
+	ToVersion string `json:"toVersion"`
+	// Reason is the reason of the decision, e.g. OutsideMaintenanceWindow.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message about the decision.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UpgradePolicyStatus is the status of the automated upgrade.
+type UpgradePolicyStatus struct {
+	// TargetVersion is the latest version allowed by the upgrade policy.
+	// +optional
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// NextWindowTime is the start time of the next maintenance window.
+	// +optional
+	// +nullable
+	NextWindowTime *metav1.Time `json:"nextWindowTime,omitempty"`
+	// Decisions are the recent decisions made by the upgrade policy, the oldest first.
+	// +optional
+	Decisions []UpgradeDecision `json:"decisions,omitempty"`
+}
+
+// MaxSyncHistory is the max number of the records in the sync history of a TidbCluster
+const MaxSyncHistory = 10
+
+// SyncResult is the result of a sync of the cluster
+type SyncResult string
+
+const (
+	// SyncResultSuccess means the sync finishes without errors.
+	SyncResultSuccess SyncResult = "Success"
+	// SyncResultRequeue means the sync waits for a state to be reached, e.g. a component to be ready.
+	SyncResultRequeue SyncResult = "Requeue"
+	// SyncResultError means the sync fails.
+	SyncResultError SyncResult = "Error"
+)
+
+// SyncRecord is the outcome of the syncs of the cluster.
+type SyncRecord struct {
+	// Time is the time of the first sync with the outcome.
+	Time metav1.Time `json:"time"`
+	// Result of the sync.
+	Result SyncResult `json:"result"`
+	// Phases are the phases of the components after the sync.
+	// +optional
+	Phases map[MemberType]MemberPhase `json:"phases,omitempty"`
+	// Errors are the errors of the sync, the long ones are truncated.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+	// Actions are the decisions made by the sync, e.g. the phase transitions of the components.
+	// +optional
+	Actions []string `json:"actions,omitempty"`
+}
+
+// ResourceRecommendationPolicy describes how the operator recommends the resource requests of
+// the components from their resource usage reported by the metrics API.
+//
+// +k8s:openapi-gen=true
+type ResourceRecommendationPolicy struct {
+	// Components are the components to recommend the resources for.
+	// Optional: Defaults to all of pd, tidb, tikv, tiflash, ticdc and tiproxy deployed in the cluster
+	// +optional
+	Components []MemberType `json:"components,omitempty"`
+
+	// MarginPercent is the headroom added to the peak usage of the containers, in percentage.
+	// Optional: Defaults to 20
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MarginPercent *int32 `json:"marginPercent,omitempty"`
+
+	// Window is the duration the peak usage is observed in, the peak older than
+	// the window is replaced by the current usage.
+	// Optional: Defaults to 24h
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// MinAllowed is the lower bound of the recommended cpu and memory requests.
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+
+	// MaxAllowed is the upper bound of the recommended cpu and memory requests.
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+
+	// AutoApply makes the operator apply the recommendations to the requests of the components
+	// during the maintenance windows, the recommendations are only published in the status if it's nil.
+	// +optional
+	AutoApply *ResourceRecommendationAutoApply `json:"autoApply,omitempty"`
+}
+
+// ResourceRecommendationAutoApply describes when the recommended resources are applied.
+//
+// +k8s:openapi-gen=true
+type ResourceRecommendationAutoApply struct {
+	// Schedule is the start time of the maintenance windows in the cron format, e.g. "0 2 * * 6".
+	Schedule string `json:"schedule"`
+
+	// Duration is the duration of the maintenance windows.
+	// Optional: Defaults to 2h
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// MinChangePercent is the min difference between the recommendation and the current requests
+	// to apply the recommendation, in percentage, which avoids rolling the pods for small changes.
+	// Optional: Defaults to 10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinChangePercent *int32 `json:"minChangePercent,omitempty"`
+}
+
+// ResourceRecommendation is the recommended resources of a component.
+type ResourceRecommendation struct {
+	// Component is the component the recommendation is for.
+	Component MemberType `json:"component"`
+	// PeakUsage is the peak cpu and memory usage of a container of the component in the window.
+	// +optional
+	PeakUsage corev1.ResourceList `json:"peakUsage,omitempty"`
+	// PeakTime is the time the peak usage is observed.
+	// +optional
+	// +nullable
+	PeakTime *metav1.Time `json:"peakTime,omitempty"`
+	// Target is the recommended cpu and memory requests.
+	// +optional
+	Target corev1.ResourceList `json:"target,omitempty"`
+	// LastUpdateTime is the last time the usage is observed.
+	// +optional
+	// +nullable
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// LastApplyTime is the last time the target is applied to the requests of the component.
+	// +optional
+	// +nullable
+	LastApplyTime *metav1.Time `json:"lastApplyTime,omitempty"`
+}
+
+// VolumeReplaceProgress is the progress of the volume replacement of a component, the volumes of the pods are
+// replaced one by one, e.g. the store of a TiKV pod is deleted and rebuilt on the new volumes once PD finds it
+// safe to delete the store. There is no separate migration mode replicating the data to temporary volumes
+// before switching, so a component that can't rebuild its data from the other replicas must not be replaced.
+type VolumeReplaceProgress struct {
+	// Component is the component whose volumes are replaced.
+	Component MemberType `json:"component"`
+	// Pods is the number of the pods of the component.
+	Pods int32 `json:"pods"`
+	// ReplacedPods is the number of the pods whose volumes match the desired ones.
+	ReplacedPods int32 `json:"replacedPods"`
+	// ReplacingPod is the pod whose volumes are being replaced.
+	// +optional
+	ReplacingPod string `json:"replacingPod,omitempty"`
+	// StartTime is the time the replacement started.
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// DeletionPolicy is the policy to delete a cluster and its data.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyBackground deletes the cluster at once, the resources owned by the cluster are deleted
+	// by the garbage collector in the background and the PVCs are left as they are.
+	DeletionPolicyBackground DeletionPolicy = "Background"
+	// DeletionPolicyRetainData deletes the components of the cluster one by one before the cluster is deleted,
+	// the components depending on others first, and retains the PVCs and PVs of the cluster for reuse.
+	DeletionPolicyRetainData DeletionPolicy = "RetainData"
+	// DeletionPolicyPurge deletes the components like RetainData and then deletes the PVCs and PVs of the cluster.
+	// The stores of a cluster joining the PD of another cluster are deregistered from PD first.
+	DeletionPolicyPurge DeletionPolicy = "Purge"
+)
+
+// DisruptionLock is the lock held by a cluster sharing a PD while it runs a disruptive operation.
+type DisruptionLock struct {
+	// Holder is the `namespace/name` of the cluster holding the lock.
+	Holder string `json:"holder"`
+	// Operation is the disruptive operation run by the holder.
+	Operation string `json:"operation"`
+	// The time the lock was acquired.
+	// +optional
+	// +nullable
+	AcquireTime metav1.Time `json:"acquireTime,omitempty"`
+	// The time the lock was renewed for the last time, the lock expires if it isn't renewed in 10 minutes.
+	// +optional
+	// +nullable
+	RenewTime metav1.Time `json:"renewTime,omitempty"`
+}
+
+// ProfileType is the type of a profile captured from a component.
+type ProfileType string
+
+const (
+	// ProfileTypeCPU is the CPU profile sampled in a duration. The CPU profile of TiKV is a flame graph.
+	ProfileTypeCPU ProfileType = "CPU"
+	// ProfileTypeHeap is the heap profile at the moment.
+	ProfileTypeHeap ProfileType = "Heap"
+)
+
+// ProfileCaptureSpec describes the profile captured from a member of the cluster.
+type ProfileCaptureSpec struct {
+	// Component is the component the profile is captured from.
+	// +kubebuilder:validation:Enum=pd;tikv;tidb
+	Component MemberType `json:"component"`
+	// Ordinal is the ordinal of the member the profile is captured from.
+	// Optional: Defaults to 0
+	// +optional
+	Ordinal int32 `json:"ordinal,omitempty"`
+	// Type is the type of the profile.
+	// Optional: Defaults to CPU
+	// +kubebuilder:validation:Enum=CPU;Heap
+	// +optional
+	Type ProfileType `json:"type,omitempty"`
+	// Duration is the duration the CPU profile is sampled in.
+	// Optional: Defaults to 30s
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// StorageProvider is the storage the profile is saved to, only S3, GCS and Azblob are supported.
+	StorageProvider `json:",inline"`
+}
+
+// PrometheusMonitorSpec describes the prometheus-operator PodMonitors created for the components.
+// The `prometheus.io` annotations of the pods are kept for the Prometheus scraping by annotations.
+type PrometheusMonitorSpec struct {
+	// Labels are added to the PodMonitors, they should match the podMonitorSelector of the Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Interval is the interval the metrics are scraped at.
+	// Optional: Defaults to the scrape interval of the Prometheus
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// ProfileCapturePhase is the phase of a profile capture.
+type ProfileCapturePhase string
+
+const (
+	// ProfileCaptureRunning means the profile is being captured.
+	ProfileCaptureRunning ProfileCapturePhase = "Running"
+	// ProfileCaptureComplete means the profile has been saved to the storage.
+	ProfileCaptureComplete ProfileCapturePhase = "Complete"
+	// ProfileCaptureFailed means the profile failed to be captured or saved.
+	ProfileCaptureFailed ProfileCapturePhase = "Failed"
+)
+
+// ProfileCaptureStatus is the status of a profile capture.
+type ProfileCaptureStatus struct {
+	// Request is the value of the `tidb.pingcap.com/profile-capture` annotation this capture answers.
+	Request string `json:"request"`
+	// Phase of the capture.
+	Phase ProfileCapturePhase `json:"phase"`
+	// Member is the name of the pod the profile is captured from.
+	// +optional
+	Member string `json:"member,omitempty"`
+	// Type is the type of the profile.
+	// +optional
+	Type ProfileType `json:"type,omitempty"`
+	// Path is the path of the profile in the storage.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// The time the capture was started.
+	// +optional
+	// +nullable
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// The time the capture was completed.
+	// +optional
+	// +nullable
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+	// A human readable message indicating details about the capture.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DriftPolicy is the action taken on the drifts of the child resources
+type DriftPolicy string
+
+const (
+	// DriftPolicyAlert emits a warning event for a drift and reports it in the status.
+	DriftPolicyAlert DriftPolicy = "Alert"
+	// DriftPolicyRevert reverts a drifted StatefulSet to the spec and a drifted ConfigMap to the data
+	// last applied by the operator in addition to the alert.
+	DriftPolicyRevert DriftPolicy = "Revert"
+)
+
+// DriftProtection is the anti-tamper mode of the child resources of the cluster. A drift is detected by
+// the managed fields of a child resource, when a field manager other than the operator owns the fields of its
+// spec or data last applied by the operator.
+type DriftProtection struct {
+	// Policy is the action taken on a drift, `Alert` or `Revert`.
+	// Optional: Defaults to Alert
+	// +kubebuilder:validation:Enum=Alert;Revert
+	// +optional
+	Policy DriftPolicy `json:"policy,omitempty"`
+
+	// ExemptManagers are the field managers allowed to mutate the child resources, e.g. `kubectl-edit`
+	// or a GitOps tool managing the same fields on purpose.
+	// +optional
+	ExemptManagers []string `json:"exemptManagers,omitempty"`
+}
+
+// ResourceDrift is a child resource mutated by other field managers.
+type ResourceDrift struct {
+	// Kind of the resource, StatefulSet or ConfigMap.
+	Kind string `json:"kind"`
+	// Name of the resource.
+	Name string `json:"name"`
+	// Managers are the field managers which mutated the fields owned by the operator.
+	Managers []string `json:"managers"`
+	// MutateTime is the last time the fields are mutated by the managers.
+	// +optional
+	// +nullable
+	MutateTime *metav1.Time `json:"mutateTime,omitempty"`
+	// Reverted is true if the resource is reverted to the spec applied by the operator after the last mutation.
+	// +optional
+	Reverted bool `json:"reverted,omitempty"`
+}
+
+// ConfigConflict is an item of the config defaults overridden by the config of a component.
+type ConfigConflict struct {
+	// Component is the component whose config overrides the item.
+	Component MemberType `json:"component"`
+	// Key is the dotted key of the item, e.g. security.ssl-ca.
+	Key string `json:"key"`
+	// Default is the value of the item in the config defaults.
+	// +optional
+	Default string `json:"default,omitempty"`
+	// Value is the value of the item in the config of the component.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// AcrossK8sResolverType is the convention to resolve the services of the peer clusters
+// +k8s:openapi-gen=true
+type AcrossK8sResolverType string
+
+const (
+	// AcrossK8sResolverClusterDomain resolves a service as `<service>.<namespace>.svc.<clusterDomain>`
+	// with the cluster domain of the Kubernetes cluster the service is deployed in.
+	AcrossK8sResolverClusterDomain AcrossK8sResolverType = "ClusterDomain"
+	// AcrossK8sResolverExternalDNS resolves a service as `<service>.<namespace>.<domain>`, which is
+	// the record published by external-dns for the service.
+	AcrossK8sResolverExternalDNS AcrossK8sResolverType = "ExternalDNS"
+	// AcrossK8sResolverClusterSet resolves a service as `<service>.<namespace>.svc.<domain>` with the
+	// cluster set domain of the multi-cluster services API, e.g. the services exported by Submariner.
+	AcrossK8sResolverClusterSet AcrossK8sResolverType = "ClusterSet"
+	// AcrossK8sResolverClusterMesh resolves a service as `<service>.<namespace>` by the local DNS, which
+	// is the global service shared by the clusters in a Cilium cluster mesh.
+	AcrossK8sResolverClusterMesh AcrossK8sResolverType = "ClusterMesh"
+	// AcrossK8sResolverStatic resolves the services by the static endpoints.
+	AcrossK8sResolverStatic AcrossK8sResolverType = "Static"
+)
+
+// DefaultClusterSetDomain is the default domain of the services exported by the multi-cluster services API
+const DefaultClusterSetDomain = "clusterset.local"
+
+// ExternalPDSpec describes the PD cluster deployed outside of this Kubernetes cluster
+// +k8s:openapi-gen=true
+type ExternalPDSpec struct {
+	// Endpoints are the client URLs of the external PD, e.g. https://pd-0.example.com:2379,
+	// all the endpoints must have the same scheme, https means TLS is enabled
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+
+	// TLSClientSecretName is the name of the secret containing the client certs (ca.crt, tls.crt and tls.key)
+	// used by tidb-operator to access the external PD, the cluster client secret of the TidbCluster is used if not set
+	// +optional
+	TLSClientSecretName *string `json:"tlsClientSecretName,omitempty"`
+}
+
+// AcrossK8sResolver configures how the services of the peer clusters are resolved
+// +k8s:openapi-gen=true
+type AcrossK8sResolver struct {
+	// Type of the resolver
+	// +kubebuilder:validation:Enum:="ClusterDomain";"ExternalDNS";"ClusterSet";"ClusterMesh";"Static"
+	Type AcrossK8sResolverType `json:"type"`
+	// Domain is the DNS zone of the records published by external-dns for the ExternalDNS resolver,
+	// or the cluster set domain for the ClusterSet resolver which defaults to clusterset.local.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+	// Endpoints maps the hosts resolved by the ClusterDomain convention to the hosts or IPs
+	// to connect to for the Static resolver, the hosts not mapped are resolved by the ClusterDomain convention.
+	// +optional
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+}
+
+// ClusterCloneMethod is the way the data of the source cluster is cloned
+type ClusterCloneMethod string
+
+const (
+	// ClusterCloneMethodBR clones the data by a BR snapshot backup of the source cluster, which is
+	// restored after the PD and TiKV of the cluster are ready.
+	ClusterCloneMethodBR ClusterCloneMethod = "BR"
+	// ClusterCloneMethodVolumeSnapshot clones the data by a volume snapshot backup of the source cluster,
+	// the TiKV volumes are restored from the snapshots, so spec.recoveryMode must be enabled.
+	ClusterCloneMethodVolumeSnapshot ClusterCloneMethod = "VolumeSnapshot"
+)
+
+// ClusterCloneFrom configures the clone from the source TidbCluster
+// +k8s:openapi-gen=true
+type ClusterCloneFrom struct {
+	// Name and namespace of the source TidbCluster, the namespace defaults to the one of this cluster.
+	TidbClusterRef `json:",inline"`
+
+	// Method is the way the data is cloned.
+	// +kubebuilder:default=BR
+	// +kubebuilder:validation:Enum:="BR";"VolumeSnapshot"
+	// +optional
+	Method ClusterCloneMethod `json:"method,omitempty"`
+
+	// S3, Gcs and Azblob are the storage the backup is saved to, one of them is required.
+	// +optional
+	S3 *S3StorageProvider `json:"s3,omitempty"`
+	// +optional
+	Gcs *GcsStorageProvider `json:"gcs,omitempty"`
+	// +optional
+	Azblob *AzblobStorageProvider `json:"azblob,omitempty"`
+
+	// BR configures the backup and the restore, its cluster is set by the controller.
+	// +optional
+	BR *BRConfig `json:"br,omitempty"`
+
+	// ToolImage is the BR image used by the backup and the restore.
+	// +optional
+	ToolImage string `json:"toolImage,omitempty"`
+
+	// ServiceAccount of the backup and the restore jobs.
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// ClusterClonePhase is the current phase of the clone
+type ClusterClonePhase string
+
+const (
+	// ClusterCloneBackup means the source cluster is being backed up.
+	ClusterCloneBackup ClusterClonePhase = "Backup"
+	// ClusterCloneRestore means the backup is being restored to the cluster.
+	ClusterCloneRestore ClusterClonePhase = "Restore"
+	// ClusterCloneComplete means the cluster is cloned.
+	ClusterCloneComplete ClusterClonePhase = "Complete"
+	// ClusterCloneFailed means the backup or the restore failed, the clone is not retried.
+	ClusterCloneFailed ClusterClonePhase = "Failed"
+)
+
+// ClusterCloneStatus is the progress of the clone from the source cluster
+type ClusterCloneStatus struct {
+	// Phase of the clone.
+	Phase ClusterClonePhase `json:"phase"`
+	// Backup is the name of the backup of the source cluster.
+	// +optional
+	Backup string `json:"backup,omitempty"`
+	// Restore is the name of the restore to the cluster.
+	// +optional
+	Restore string `json:"restore,omitempty"`
+	// Message describes the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CompleteTime is the time the clone completes.
+	// +optional
+	// +nullable
+	CompleteTime *metav1.Time `json:"completeTime,omitempty"`
+}
+
+// StuckUpgradeReport is the safety report of an upgrade blocked on an unhealthy member, which describes
+// the regions at risk if the upgrade continues without waiting for the member to recover.
+type StuckUpgradeReport struct {
+	// ID identifies the report, the `tidb.pingcap.com/force-continue-upgrade` annotation must be set
+	// to it to force the upgrade to continue, so that a report is always published before the upgrade is forced.
+	ID string `json:"id"`
+	// Component the blocked member belongs to.
+	Component MemberType `json:"component"`
+	// Member is the name of the Pod the upgrade is blocked on.
+	Member string `json:"member"`
+	// StoreID is the id of the store of the blocked member.
+	// +optional
+	StoreID string `json:"storeID,omitempty"`
+	// Reason why the upgrade is blocked.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// NextMember is the name of the Pod to be upgraded next if the upgrade continues.
+	// +optional
+	NextMember string `json:"nextMember,omitempty"`
+	// MaxReplicas is the max-replicas of the replication config of PD.
+	// +optional
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+	// Regions is the number of the regions with a replica on the stores of the blocked and the next member.
+	// +optional
+	Regions int32 `json:"regions,omitempty"`
+	// Leaders is the number of the regions whose leader is on the stores of the blocked and the next member.
+	// +optional
+	Leaders int32 `json:"leaders,omitempty"`
+	// UnderReplicatedRegions is the number of the regions with less voters than required by the placement rules.
+	// +optional
+	UnderReplicatedRegions int32 `json:"underReplicatedRegions,omitempty"`
+	// RegionsAtRisk is the number of the regions that would have less than a quorum of voters available
+	// when the blocked and the next member are unavailable at the same time.
+	// +optional
+	RegionsAtRisk int32 `json:"regionsAtRisk,omitempty"`
+	// RegionIDsAtRisk are the ids of the regions at risk, at most 20 of them are listed.
+	// +optional
+	RegionIDsAtRisk []uint64 `json:"regionIDsAtRisk,omitempty"`
+	// The time the report was last updated.
+	// +optional
+	// +nullable
+	ReportTime metav1.Time `json:"reportTime,omitempty"`
+	// ForcedBy is the user who forced the upgrade to continue, as recorded by the admission webhook.
+	// +optional
+	ForcedBy string `json:"forcedBy,omitempty"`
+	// The time the upgrade was forced to continue.
+	// +optional
+	// +nullable
+	ForcedTime *metav1.Time `json:"forcedTime,omitempty"`
 }
 
 // PDStatus is PD status
@@ -1548,6 +3226,9 @@ type PDStatus struct {
 	FailureMembers  map[string]PDFailureMember `json:"failureMembers,omitempty"`
 	UnjoinedMembers map[string]UnjoinedMember  `json:"unjoinedMembers,omitempty"`
 	Image           string                     `json:"image,omitempty"`
+	// Learners contains the names of the PD members which are non-voting learners
+	// +optional
+	Learners []string `json:"learners,omitempty"`
 	// Volumes contains the status of all volumes.
 	Volumes map[StorageVolumeName]*StorageVolumeStatus `json:"volumes,omitempty"`
 	// Represents the latest available observations of a component's state.
@@ -1556,6 +3237,9 @@ type PDStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// Indicates that a Volume replace using VolumeReplacing feature is in progress.
 	VolReplaceInProgress bool `json:"volReplaceInProgress,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // PDMSStatus is PD microservice status
@@ -1574,6 +3258,9 @@ type PDMSStatus struct {
 	// +optional
 	// +nullable
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // PDMember is PD member
@@ -1632,6 +3319,139 @@ type TiDBStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// Indicates that a Volume replace using VolumeReplacing feature is in progress.
 	VolReplaceInProgress bool `json:"volReplaceInProgress,omitempty"`
+	// Canary is the status of the canary upgrade of TiDB.
+	// +optional
+	Canary *TiDBCanaryStatus `json:"canary,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// TiDBCanaryStrategy is the canary upgrade configuration of TiDB. The pods with the highest ordinals are
+// upgraded as the canary first, then they are soaked for a period, in which the SLOs of the canary are checked
+// against the Prometheus scraping the cluster. The rest of the pods are upgraded once the soak period passes,
+// or the canary is rolled back to the current revision if an SLO is breached.
+// +k8s:openapi-gen=true
+type TiDBCanaryStrategy struct {
+	// Replicas is the number of the pods upgraded as the canary, which must be less than the replicas of TiDB.
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas"`
+
+	// SoakDuration is how long the canary is held after it's upgraded.
+	SoakDuration metav1.Duration `json:"soakDuration"`
+
+	// PrometheusURL is the URL of the Prometheus scraping the cluster, e.g. the one of a TidbMonitor
+	// `http://<monitor>-prometheus.<namespace>:9090`. The SLOs aren't checked if it's not set.
+	// +optional
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// CheckInterval is the interval between two checks of the SLOs during the soak period.
+	// Optional: Defaults to 30s
+	// +optional
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+
+	// LatencySLO is the SLO of the 99th percentile latency of the queries served by the canary.
+	// The latency isn't checked if it's not set.
+	// +optional
+	LatencySLO *metav1.Duration `json:"latencySLO,omitempty"`
+
+	// MaxErrorPercent is the max percent of the failed queries served by the canary.
+	// The error rate isn't checked if it's not set.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxErrorPercent *int32 `json:"maxErrorPercent,omitempty"`
+}
+
+// LoadBalancerProvider is the cloud provider of an external load balancer
+type LoadBalancerProvider string
+
+const (
+	// LoadBalancerProviderAWS is an AWS Elastic Load Balancing target group
+	LoadBalancerProviderAWS LoadBalancerProvider = "aws"
+	// LoadBalancerProviderGCP is a GCP network endpoint group
+	LoadBalancerProviderGCP LoadBalancerProvider = "gcp"
+)
+
+// TiDBLoadBalancerReadiness is the readiness gate of the TiDB pods registered in an external load balancer
+// by their pod IPs, exactly one of aws and gcp must be set.
+// The cloud API is called with the credentials of tidb-controller-manager.
+// +k8s:openapi-gen=true
+type TiDBLoadBalancerReadiness struct {
+	// ConditionType is the condition type of the readiness gate.
+	// Optional: Defaults to tidb.pingcap.com/load-balancer-ready
+	// +optional
+	ConditionType string `json:"conditionType,omitempty"`
+
+	// AWS is the target group of the pods, whose target type must be ip.
+	// +optional
+	AWS *AWSTargetGroup `json:"aws,omitempty"`
+
+	// GCP is the network endpoint group of the pods.
+	// +optional
+	GCP *GCPNetworkEndpointGroup `json:"gcp,omitempty"`
+}
+
+// AWSTargetGroup is an AWS Elastic Load Balancing target group
+// +k8s:openapi-gen=true
+type AWSTargetGroup struct {
+	// TargetGroupARN is the ARN of the target group.
+	TargetGroupARN string `json:"targetGroupARN"`
+
+	// Region is the region of the target group.
+	// Optional: Defaults to the region of the ARN
+	// +optional
+	Region string `json:"region,omitempty"`
+}
+
+// GCPNetworkEndpointGroup is a zonal GCP network endpoint group, which has an instance of the same
+// name in each zone of the pods
+// +k8s:openapi-gen=true
+type GCPNetworkEndpointGroup struct {
+	// Project is the project of the network endpoint group.
+	Project string `json:"project"`
+
+	// Name is the name of the network endpoint group.
+	Name string `json:"name"`
+
+	// Zones are the zones of the network endpoint group.
+	// +kubebuilder:validation:MinItems=1
+	Zones []string `json:"zones"`
+}
+
+// TiDBCanaryPhase is the phase of the canary upgrade of TiDB
+type TiDBCanaryPhase string
+
+const (
+	// TiDBCanaryUpgrading means the pods of the canary are being upgraded.
+	TiDBCanaryUpgrading TiDBCanaryPhase = "Upgrading"
+	// TiDBCanarySoaking means the canary is upgraded and its SLOs are being checked.
+	TiDBCanarySoaking TiDBCanaryPhase = "Soaking"
+	// TiDBCanaryPromoted means the canary passed the soak period and the rest of the pods are upgraded.
+	TiDBCanaryPromoted TiDBCanaryPhase = "Promoted"
+	// TiDBCanaryRolledBack means an SLO of the canary was breached and it's rolled back to the current
+	// revision, the upgrade is held until the spec of TiDB is changed.
+	TiDBCanaryRolledBack TiDBCanaryPhase = "RolledBack"
+)
+
+// TiDBCanaryStatus is the status of the canary upgrade of TiDB
+type TiDBCanaryStatus struct {
+	// Revision is the revision of the StatefulSet the canary is upgraded to.
+	Revision string `json:"revision"`
+	// Phase is the phase of the canary.
+	Phase TiDBCanaryPhase `json:"phase"`
+	// Pods are the pods of the canary.
+	// +optional
+	Pods []string `json:"pods,omitempty"`
+	// SoakStartTime is the time the soak period started.
+	// +optional
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+	// LastCheckTime is the last time the SLOs were checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// Message is the result of the last check of the SLOs, or the reason of the rollback.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // TiDBMember is TiDB member
@@ -1669,6 +3489,14 @@ const (
 	PDLeaderTransferExpirationTimeAnnKey = "tidb.pingcap.com/pd-evict-leader-expiration-time"
 	// ReplaceVolumeAnnKey is the annotation key to replace disks used by pod.
 	ReplaceVolumeAnnKey = "tidb.pingcap.com/replace-volume"
+	// TiCDCDrainCaptureAnnKey is the annotation key to move the ownership and the tables off the TiCDC capture.
+	TiCDCDrainCaptureAnnKey = "tidb.pingcap.com/ticdc-drain-capture"
+	// NodeDrainAnnKey is the annotation key set by the operator on the pods whose leaders or captures are
+	// moved off for the draining node, the value is the name of the node.
+	NodeDrainAnnKey = "tidb.pingcap.com/node-drain"
+	// DebugContainerAnnKey is the annotation key to attach an ephemeral debug container to pod,
+	// the annotation is removed after the container is attached.
+	DebugContainerAnnKey = "tidb.pingcap.com/debug-container"
 )
 
 // The `Value` of annotation controls the behavior when the leader count drops to zero, the valid value is one of:
@@ -1685,8 +3513,15 @@ const (
 // - `none`: doing nothing.
 // - `delete-pod`: delete pod.
 const (
-	TransferLeaderValueNone      = "none"
-	TransferLeaderValueDeletePod = "delete-pod"
+	TransferLeaderValueNone      = "none"
+	TransferLeaderValueDeletePod = "delete-pod"
+)
+
+// The `Value` of TiCDC drain capture annotation, the valid value is one of:
+//
+// - `none`: doing nothing after the capture is drained.
+const (
+	DrainCaptureValueNone = "none"
 )
 
 // The `Value` of TiDB deletion annotation controls the behavior when the tidb pod got deleted, the valid value is one of:
@@ -1703,6 +3538,12 @@ const (
 	ReplaceVolumeValueTrue = "true"
 )
 
+// The `Value` of debug container annotation is the image of the debug container, or `default` to use
+// the debug image of the operator, which contains pd-ctl and tikv-ctl.
+const (
+	DebugContainerValueDefault = "default"
+)
+
 type EvictLeaderStatus struct {
 	PodCreateTime metav1.Time `json:"podCreateTime,omitempty"`
 	BeginTime     metav1.Time `json:"beginTime,omitempty"`
@@ -1738,6 +3579,44 @@ type TiKVStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// Indicates that a Volume replace using VolumeReplacing feature is in progress.
 	VolReplaceInProgress bool `json:"volReplaceInProgress,omitempty"`
+	// Witness is the status of the TiKV witness stores
+	// +optional
+	Witness *TiKVWitnessStatus `json:"witness,omitempty"`
+	// ScaleOutBalance is the status of the rebalancing after scaling out, the PD settings tuned by
+	// spec.tikv.scaleOutBalancePolicy are reverted when it's removed
+	// +optional
+	ScaleOutBalance *ScaleOutBalanceStatus `json:"scaleOutBalance,omitempty"`
+	// Encryption is the status of the encryption at rest of TiKV
+	// +optional
+	Encryption *TiKVEncryptionStatus `json:"encryption,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ScaleOutBalanceStatus is the status of the rebalancing after scaling TiKV out
+type ScaleOutBalanceStatus struct {
+	// StartTime is the time the scale out is started
+	StartTime metav1.Time `json:"startTime"`
+	// Replicas is the replicas of TiKV scaled out to
+	Replicas int32 `json:"replicas"`
+	// BaselineStores are the ids of the stores before scaling out
+	BaselineStores []string `json:"baselineStores,omitempty"`
+	// StoreLimits are the add-peer store limits of the new stores before tuning, keyed by the store id
+	StoreLimits map[string]string `json:"storeLimits,omitempty"`
+	// RegionScheduleLimit is the schedule.region-schedule-limit of PD before tuning,
+	// nil if it's not tuned
+	RegionScheduleLimit *int64 `json:"regionScheduleLimit,omitempty"`
+}
+
+// TiKVWitnessStatus is the status of the TiKV witness stores
+type TiKVWitnessStatus struct {
+	Phase       MemberPhase             `json:"phase,omitempty"`
+	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	Stores      map[string]TiKVStore    `json:"stores,omitempty"` // key: store id
+	// PlacementRulesApplied indicates whether the placement rules placing one witness replica
+	// of each region on the witness stores are applied in PD
+	PlacementRulesApplied bool `json:"placementRulesApplied,omitempty"`
 }
 
 // TiFlashStatus is TiFlash status
@@ -1759,6 +3638,29 @@ type TiFlashStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// Indicates that a Volume replace using VolumeReplacing feature is in progress.
 	VolReplaceInProgress bool `json:"volReplaceInProgress,omitempty"`
+	// TableReplicas is the sync status of the TiFlash replicas in spec.tiflash.tableReplicas
+	// +optional
+	TableReplicas []TiFlashTableReplicaStatus `json:"tableReplicas,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// TiFlashTableReplicaStatus is the sync status of the TiFlash replicas of a database or table
+type TiFlashTableReplicaStatus struct {
+	Database string `json:"database"`
+	// +optional
+	Table string `json:"table,omitempty"`
+	// Count is the number of the TiFlash replicas set
+	Count int32 `json:"count"`
+	// Available indicates whether the TiFlash replicas of all the tables are available
+	Available bool `json:"available"`
+	// Progress is the average sync progress of the TiFlash replicas of the tables, from 0 to 1
+	// +optional
+	Progress float64 `json:"progress,omitempty"`
+	// Message is the error met when setting the TiFlash replicas
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // TiProxyMember is TiProxy member
@@ -1785,6 +3687,9 @@ type TiProxyStatus struct {
 	// +optional
 	// +nullable
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // TiCDCStatus is TiCDC status
@@ -1799,6 +3704,9 @@ type TiCDCStatus struct {
 	// +optional
 	// +nullable
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // TiCDCCapture is TiCDC Capture status
@@ -1827,6 +3735,18 @@ type TiKVStore struct {
 	// It is set when evicting leader and used to wait for most leaders to transfer back after upgrade.
 	// It is unset after leader transfer is completed.
 	LeaderCountBeforeUpgrade *int32 `json:"leaderCountBeforeUpgrade,omitempty"`
+	// SlowSince is the time since which the slow score of the store stays at or above the threshold
+	// of spec.tikv.slowStoreDetection.
+	// +optional
+	// +nullable
+	SlowSince *metav1.Time `json:"slowSince,omitempty"`
+	// Slow is true if the slow score of the store stays at or above the threshold longer than the
+	// duration of spec.tikv.slowStoreDetection.
+	// +optional
+	Slow bool `json:"slow,omitempty"`
+	// SlowStoreMitigation is the mitigation applied to the store as it's slow.
+	// +optional
+	SlowStoreMitigation TiKVSlowStoreMitigation `json:"slowStoreMitigation,omitempty"`
 }
 
 // TiKVFailureStore is the tikv failure store information
@@ -1864,6 +3784,9 @@ type PumpStatus struct {
 	// +optional
 	// +nullable
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the TidbCluster spec acted upon by the last successful sync of the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // TiDBTLSClient can enable TLS connection between TiDB server and MySQL client
@@ -2034,6 +3957,49 @@ type S3StorageProvider struct {
 	Options []string `json:"options,omitempty"`
 	// ForcePathStyle for the backup and restore to connect s3 with path style(true) or virtual host(false).
 	ForcePathStyle *bool `json:"forcePathStyle,omitempty"`
+	// InCluster uses an S3 compatible object store running in the Kubernetes cluster, e.g. MinIO,
+	// for the environments without external S3. The operator discovers the endpoint and the
+	// credentials of the object store and fills them into Endpoint and SecretName.
+	// +optional
+	InCluster *InClusterObjectStore `json:"inCluster,omitempty"`
+}
+
+// InClusterObjectStore is an S3 compatible object store in the namespace of the backup or restore.
+// +k8s:openapi-gen=true
+type InClusterObjectStore struct {
+	// ServiceName is the name of the Service of the object store.
+	ServiceName string `json:"serviceName"`
+	// Port of the S3 API of the object store. Defaults to the first port of the Service.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// CredentialsSecretName is the name of the Secret which stores the credentials of the object store,
+	// either in the keys "access_key" and "secret_key", or in the root user keys of the MinIO charts.
+	// Defaults to "<serviceName>-credentials".
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+	// Provision deploys a minimal single-node MinIO as the object store if it does not exist,
+	// and creates the bucket of the backup or restore in it.
+	// +optional
+	Provision *ObjectStoreProvision `json:"provision,omitempty"`
+}
+
+// ObjectStoreProvision is the minimal object store provisioned by the operator.
+// +k8s:openapi-gen=true
+type ObjectStoreProvision struct {
+	// Image of MinIO.
+	// +kubebuilder:default="minio/minio"
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Storage is the size of the volume that stores the objects.
+	// +kubebuilder:default="10Gi"
+	// +optional
+	Storage string `json:"storage,omitempty"`
+	// StorageClassName of the volume that stores the objects.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// Resources of the MinIO container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -2185,11 +4151,58 @@ type Progress struct {
 	Step string `json:"step,omitempty"`
 	// Progress is the backup progress value
 	Progress float64 `json:"progress,omitempty"`
+	// Speed is the speed of the step reported by BR, e.g. 12.3MB/s
+	// +optional
+	Speed string `json:"speed,omitempty"`
 	// LastTransitionTime is the update time
 	// +nullable
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
+// BRNotificationEvent is the event of the backup or restore posted to the webhook
+type BRNotificationEvent string
+
+const (
+	// BRNotificationEventStarted is posted when the backup or restore starts running
+	BRNotificationEventStarted BRNotificationEvent = "Started"
+	// BRNotificationEventProgress is posted when the progress of a step reaches a milestone
+	BRNotificationEventProgress BRNotificationEvent = "Progress"
+	// BRNotificationEventCompleted is posted when the backup or restore completes
+	BRNotificationEventCompleted BRNotificationEvent = "Completed"
+	// BRNotificationEventFailed is posted when the backup or restore fails
+	BRNotificationEventFailed BRNotificationEvent = "Failed"
+)
+
+// BRNotifications configures the webhook notifications of the backup or restore,
+// which are posted by tidb-operator with the best effort.
+// +k8s:openapi-gen=true
+type BRNotifications struct {
+	// Webhook is the webhook the notifications are posted to
+	Webhook BRNotificationWebhook `json:"webhook"`
+
+	// Events are the events to notify, all the events are notified if not set
+	// +optional
+	Events []BRNotificationEvent `json:"events,omitempty"`
+
+	// ProgressMilestones are the progress percentages of the steps to notify by the Progress event,
+	// default to 25, 50 and 75
+	// +optional
+	ProgressMilestones []int32 `json:"progressMilestones,omitempty"`
+}
+
+// BRNotificationWebhook is the webhook the notifications are posted to
+// +k8s:openapi-gen=true
+type BRNotificationWebhook struct {
+	// URL is the URL of the webhook, exactly one of url and secretName should be set
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// SecretName is the name of the secret in the namespace of the backup or restore, whose key `url`
+	// is the URL of the webhook and the optional key `token` is used as the bearer token of the requests
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
 // BackupSpec contains the backup specification for a tidb cluster.
 // +k8s:openapi-gen=true
 // +kubebuilder:validation:XValidation:rule="has(self.logSubcommand) ? !has(self.logStop) : true",message="Field `logStop` is the old version field, please use `logSubcommand` instead"
@@ -2274,6 +4287,8 @@ type BackupSpec struct {
 	// ToolImage specifies the tool image used in `Backup`, which supports BR and Dumpling images.
 	// For examples `spec.toolImage: pingcap/br:v4.0.8` or `spec.toolImage: pingcap/dumpling:v4.0.8`
 	// For BR image, if it does not contain tag, Pod will use image 'ToolImage:${TiKV_Version}'.
+	// It can be used to hotfix the BR of a backup without upgrading the operator or the cluster, the major
+	// version of the BR image should be the same as the version of TiKV of the cluster.
 	// +optional
 	ToolImage string `json:"toolImage,omitempty"`
 	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the images.
@@ -2294,6 +4309,11 @@ type BackupSpec struct {
 	CleanPolicy CleanPolicyType `json:"cleanPolicy,omitempty"`
 	// CleanOption controls the behavior of clean.
 	CleanOption *CleanOption `json:"cleanOption,omitempty"`
+	// DeletionProtection puts the backup on hold for compliance retention. While it's set, the backup
+	// is skipped by the garbage collection of the backup schedule, the deletion of the Backup CR is
+	// denied by the admission webhook if enabled, and the backup data is retained regardless of cleanPolicy.
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
 
 	// PodSecurityContext of the component
 	// +optional
@@ -2302,6 +4322,11 @@ type BackupSpec struct {
 	// PriorityClassName of Backup Job Pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// PreemptionPolicy of Backup Job Pods. It requires PriorityClassName and must match the
+	// preemptionPolicy of the PriorityClass, otherwise the admission rejects the pods
+	// +optional
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
 	// BackoffRetryPolicy the backoff retry policy, currently only valid for snapshot backup
 	BackoffRetryPolicy BackoffRetryPolicy `json:"backoffRetryPolicy,omitempty"`
 
@@ -2314,6 +4339,131 @@ type BackupSpec struct {
 	// VolumeBackupInitJobMaxActiveSeconds represents the deadline (in seconds) of the vbk init job
 	// +kubebuilder:default=600
 	VolumeBackupInitJobMaxActiveSeconds int `json:"volumeBackupInitJobMaxActiveSeconds,omitempty"`
+
+	// Throttle adjusts the backup threads of TiKV by the load of the cluster while the snapshot backup
+	// is running, the threads are set back when the backup finishes.
+	// +optional
+	Throttle *BackupThrottle `json:"throttle,omitempty"`
+
+	// BackupReplicaReadPolicy makes BR read the data of the snapshot backup from the followers or the
+	// learners on the labeled TiKV stores instead of the leaders, to reduce the impact on the workload.
+	// +optional
+	BackupReplicaReadPolicy *BackupReplicaReadPolicy `json:"backupReplicaReadPolicy,omitempty"`
+
+	// Notifications posts the events of the backup to a webhook, so that the pipelines are notified
+	// instead of polling the status of the backup.
+	// +optional
+	Notifications *BRNotifications `json:"notifications,omitempty"`
+}
+
+// BackupReplicaReadMode is the kind of the replicas BR reads the data of a snapshot backup from.
+type BackupReplicaReadMode string
+
+const (
+	// BackupReplicaReadLeader reads the data from the leaders, which is the default behavior of BR.
+	BackupReplicaReadLeader BackupReplicaReadMode = "Leader"
+	// BackupReplicaReadFollower reads the data from the followers on the labeled TiKV stores.
+	BackupReplicaReadFollower BackupReplicaReadMode = "Follower"
+	// BackupReplicaReadLearner reads the data from the learners on the labeled TiKV stores, which are
+	// usually dedicated to the backups by a placement rule.
+	BackupReplicaReadLearner BackupReplicaReadMode = "Learner"
+)
+
+// BackupReplicaReadPolicy configures the replicas BR reads the data of a snapshot backup from. The backup
+// job isn't created until a TiKV store matching the label is up, and the learner placement rule is checked
+// in the Learner mode. TiFlash stores can't serve the backups and are never matched.
+// +k8s:openapi-gen=true
+type BackupReplicaReadPolicy struct {
+	// Mode is the kind of the replicas the data is read from.
+	// Optional: Defaults to Leader
+	// +kubebuilder:validation:Enum=Leader;Follower;Learner
+	// +optional
+	Mode BackupReplicaReadMode `json:"mode,omitempty"`
+
+	// StoreLabel is the label of the TiKV stores the data is read from in the format of `key:value`,
+	// it's passed to the `--replica-read-label` flag of BR. It's required in the Follower and Learner modes.
+	// +optional
+	StoreLabel string `json:"storeLabel,omitempty"`
+
+	// LearnerRule is the placement rule placing the learners on the labeled stores, it's checked to be a
+	// learner rule constrained to the store label before the backup in the Learner mode.
+	// +optional
+	LearnerRule *PlacementRuleRef `json:"learnerRule,omitempty"`
+
+	// FallbackToLeader makes the backup read from the leaders if no TiKV store matching the label is up,
+	// the backup waits for the stores otherwise.
+	// +optional
+	FallbackToLeader bool `json:"fallbackToLeader,omitempty"`
+}
+
+// PlacementRuleRef refers to a placement rule of PD.
+// +k8s:openapi-gen=true
+type PlacementRuleRef struct {
+	// GroupID is the group of the rule.
+	// Optional: Defaults to pd
+	// +optional
+	GroupID string `json:"groupID,omitempty"`
+	// ID is the id of the rule.
+	ID string `json:"id"`
+}
+
+// BackupThrottle configures the closed-loop throttling of the snapshot backup. The load is queried from
+// the Prometheus scraping the cluster periodically, the backup threads of TiKV are halved when the CPU or
+// IO usage exceeds its limit, and increased by one otherwise. The backup is paused, which is throttled to
+// the min threads, while the latency of the TiDB queries breaches the SLO.
+// +k8s:openapi-gen=true
+type BackupThrottle struct {
+	// PrometheusURL is the URL of the Prometheus scraping the cluster, e.g. the one of a TidbMonitor
+	// `http://<monitor>-prometheus.<namespace>:9090`.
+	PrometheusURL string `json:"prometheusURL"`
+
+	// Interval between two adjustments.
+	// Optional: Defaults to 30s
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// MaxCPUUsage is the max CPU usage of a TiKV, in percent of its CPU quota.
+	// Optional: Defaults to 80
+	// +optional
+	MaxCPUUsage *int32 `json:"maxCPUUsage,omitempty"`
+
+	// MaxIOBandwidth is the max IO bandwidth of a TiKV per second, the IO isn't limited if it's not set.
+	// +optional
+	MaxIOBandwidth *resource.Quantity `json:"maxIOBandwidth,omitempty"`
+
+	// LatencySLO is the SLO of the 99th percentile latency of the TiDB queries, the backup is paused when
+	// it's breached. The latency isn't checked if it's not set.
+	// +optional
+	LatencySLO *metav1.Duration `json:"latencySLO,omitempty"`
+
+	// MinThreads is the min backup threads of a TiKV.
+	// Optional: Defaults to 1
+	// +optional
+	MinThreads *int32 `json:"minThreads,omitempty"`
+
+	// MaxThreads is the max backup threads of a TiKV.
+	// Optional: Defaults to the backup threads of TiKV when the backup starts
+	// +optional
+	MaxThreads *int32 `json:"maxThreads,omitempty"`
+}
+
+// BackupThrottleStatus is the current throttling of the backup
+type BackupThrottleStatus struct {
+	// Threads is the current backup threads of TiKV.
+	Threads int32 `json:"threads"`
+	// OriginalThreads is the backup threads of TiKV before the throttled backups of the cluster, which are set back
+	// when the last of them finishes.
+	OriginalThreads int32 `json:"originalThreads"`
+	// Paused indicates whether the backup is paused as the latency SLO is breached.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// Reason of the last adjustment.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// LastAdjustTime is the time of the last adjustment.
+	// +optional
+	// +nullable
+	LastAdjustTime *metav1.Time `json:"lastAdjustTime,omitempty"`
 }
 
 // FederalVolumeBackupPhase represents a phase to execute in federal volume backup
@@ -2413,6 +4563,9 @@ type BackoffRetryRecord struct {
 type BackupConditionType string
 
 const (
+	// BackupPending means the backup job is waiting to be created because too many
+	// backup and restore jobs are running
+	BackupPending BackupConditionType = "Pending"
 	// BackupScheduled means the backup related job has been created
 	BackupScheduled BackupConditionType = "Scheduled"
 	// BackupRunning means the backup is currently being executed.
@@ -2545,6 +4698,41 @@ type BackupStatus struct {
 	Progresses []Progress `json:"progresses,omitempty"`
 	// BackoffRetryStatus is status of the backoff retry, it will be used when backup pod or job exited unexpectedly
 	BackoffRetryStatus []BackoffRetryRecord `json:"backoffRetryStatus,omitempty"`
+	// StorageClass is the storage class the backup data has been transitioned to by
+	// the storage class transition of the backup schedule.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+	// StorageClassTransitionTime is the time at which the backup data was transitioned.
+	// +optional
+	// +nullable
+	StorageClassTransitionTime *metav1.Time `json:"storageClassTransitionTime,omitempty"`
+	// Throttle is the current throttling of the backup by spec.throttle.
+	// +optional
+	// +nullable
+	Throttle *BackupThrottleStatus `json:"throttle,omitempty"`
+	// JobSummaries are the summaries of the jobs of the backup deleted by the job garbage collector.
+	// +optional
+	JobSummaries []BRJobSummary `json:"jobSummaries,omitempty"`
+}
+
+// BRJobSummary is the summary of a finished job of the backup or restore, which is retained
+// in the status after the job is deleted by the job garbage collector of tidb-operator
+type BRJobSummary struct {
+	// Name is the name of the job
+	Name string `json:"name"`
+	// Succeeded is true if the job completed successfully
+	Succeeded bool `json:"succeeded"`
+	// StartTime is the time at which the job was started
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// FinishTime is the time at which the job completed or failed
+	// +optional
+	// +nullable
+	FinishTime *metav1.Time `json:"finishTime,omitempty"`
+	// Message is the reason of the failure of the job
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
@@ -2586,8 +4774,21 @@ type BackupScheduleList struct {
 type BackupScheduleSpec struct {
 	// Schedule specifies the cron string used for backup scheduling.
 	Schedule string `json:"schedule"`
+	// TimeZone is the name of the time zone in the IANA time zone database, e.g. "Asia/Shanghai", in which
+	// the schedule is evaluated. The times skipped by the DST transitions are delayed until the transitions,
+	// and the times repeated are scheduled only once. The local time zone of the operator is used if empty.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 	// Pause means paused backupSchedule
 	Pause bool `json:"pause,omitempty"`
+	// PauseMode specifies what is paused when pause is true, defaults to Snapshot.
+	// Snapshot pauses the snapshot backups only, the log backup keeps running.
+	// Log pauses the log backup only, the snapshot backups keep running.
+	// All pauses both the snapshot backups and the log backup.
+	// The log backup is paused and resumed by BR through the log subcommand of the log backup.
+	// +kubebuilder:validation:Enum=Snapshot;Log;All
+	// +optional
+	PauseMode BackupSchedulePauseMode `json:"pauseMode,omitempty"`
 	// MaxBackups is to specify how many backups we want to keep
 	// 0 is magic number to indicate un-limited backups.
 	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred
@@ -2597,6 +4798,19 @@ type BackupScheduleSpec struct {
 	MaxReservedTime *string `json:"maxReservedTime,omitempty"`
 	// CompactInterval is to specify how long backups we want to compact.
 	CompactInterval *string `json:"compactInterval,omitempty"`
+	// CompactSchedule runs the compaction of the log backup on a cron instead of every
+	// CompactInterval, CompactInterval is ignored if it's set.
+	// +optional
+	CompactSchedule *CompactSchedule `json:"compactSchedule,omitempty"`
+	// StorageClassTransition transitions the data of the snapshot backups to a colder
+	// storage class some days after they complete, to reduce the storage cost.
+	// +optional
+	StorageClassTransition *StorageClassTransition `json:"storageClassTransition,omitempty"`
+	// MissedRunPolicy is to specify how the scheduled times missed while the
+	// operator was not running are handled, defaults to RunOnce.
+	// +kubebuilder:validation:Enum=Skip;RunOnce;RunAll
+	// +optional
+	MissedRunPolicy MissedRunPolicy `json:"missedRunPolicy,omitempty"`
 	// BackupTemplate is the specification of the backup structure to get scheduled.
 	BackupTemplate BackupSpec `json:"backupTemplate"`
 	// LogBackupTemplate is the specification of the log backup structure to get scheduled.
@@ -2622,6 +4836,68 @@ type BackupScheduleSpec struct {
 	StorageProvider `json:",inline"`
 }
 
+// StorageClassTransition is the transition of the backup data to a colder storage class.
+// The data is transitioned by a job for each backup, which changes the storage class of
+// the objects in place. Only S3, GCS and Azure Blob Storage are supported.
+type StorageClassTransition struct {
+	// Days is the number of days after a backup completes before its data is transitioned.
+	// +kubebuilder:validation:Minimum=1
+	Days int32 `json:"days"`
+	// StorageClass is the storage class the data is transitioned to, e.g. STANDARD_IA or
+	// GLACIER_IR for S3, NEARLINE, COLDLINE or ARCHIVE for GCS, and Cool for Azure Blob Storage.
+	// GLACIER and DEEP_ARCHIVE of S3 and Archive of Azure Blob Storage are not allowed, as the
+	// objects in them must be restored before BR can read them.
+	StorageClass string `json:"storageClass"`
+}
+
+// CompactSchedule is the schedule of the compaction of the log backup. At each scheduled time,
+// the log backup is compacted from the last compact progress up to its checkpoint.
+type CompactSchedule struct {
+	// Schedule is the cron string of the compaction, which is evaluated in the time zone
+	// of the backup schedule.
+	Schedule string `json:"schedule"`
+	// Windows are the time windows of the day in which the compaction can start, in the
+	// format of "HH:MM-HH:MM" evaluated in the time zone of the backup schedule, e.g. "22:00-06:00".
+	// A scheduled compaction due out of the windows is delayed until the next window opens.
+	// The compaction can start at any time if empty.
+	// +optional
+	Windows []string `json:"windows,omitempty"`
+	// MaxConcurrentCompacts is the maximum number of the compact backups running at the same
+	// time in the namespace, a scheduled compaction is delayed while the limit is reached.
+	// 0 means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentCompacts int32 `json:"maxConcurrentCompacts,omitempty"`
+}
+
+// BackupSchedulePauseMode represents what is paused by a paused backup schedule.
+type BackupSchedulePauseMode string
+
+const (
+	// BackupSchedulePauseModeSnapshot means the snapshot backups are paused, and the log
+	// backup keeps running.
+	BackupSchedulePauseModeSnapshot BackupSchedulePauseMode = "Snapshot"
+	// BackupSchedulePauseModeLog means the log backup is paused, and the snapshot backups
+	// keep running.
+	BackupSchedulePauseModeLog BackupSchedulePauseMode = "Log"
+	// BackupSchedulePauseModeAll means both the snapshot backups and the log backup are paused.
+	BackupSchedulePauseModeAll BackupSchedulePauseMode = "All"
+)
+
+// MissedRunPolicy represents how a backup schedule handles the scheduled times it missed.
+type MissedRunPolicy string
+
+const (
+	// MissedRunPolicySkip means the missed runs are skipped, and only the scheduled time
+	// which is just due is run.
+	MissedRunPolicySkip MissedRunPolicy = "Skip"
+	// MissedRunPolicyRunOnce means the missed runs are coalesced into one backup.
+	MissedRunPolicyRunOnce MissedRunPolicy = "RunOnce"
+	// MissedRunPolicyRunAll means a backup is run for every missed scheduled time,
+	// one after another.
+	MissedRunPolicyRunAll MissedRunPolicy = "RunAll"
+)
+
 // BackupScheduleStatus represents the current state of a BackupSchedule.
 type BackupScheduleStatus struct {
 	// LastBackup represents the last backup.
@@ -2632,14 +4908,65 @@ type BackupScheduleStatus struct {
 	LogBackup *string `json:"logBackup,omitempty"`
 	// LogBackupStartTs represents the start time of log backup
 	LogBackupStartTs *metav1.Time `json:"logBackupStartTs,omitempty"`
+	// LogBackupPaused is true if the log backup is paused by the pause mode of the schedule,
+	// the log backup is resumed only if it's paused by the schedule.
+	// +optional
+	LogBackupPaused bool `json:"logBackupPaused,omitempty"`
 	// LastBackupTime represents the last time the backup was successfully created.
 	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
 	// LastCompactProgress represents the endTs of the last compact
 	LastCompactProgress *metav1.Time `json:"lastCompactProgress,omitempty"`
 	// LastCompactExecutionTs represents the execution time of the last compact
 	LastCompactExecutionTs *metav1.Time `json:"lastCompactExecutionTs,omitempty"`
+	// LastCompactScheduleTime represents the last scheduled time of the compact schedule
+	// that has been handled.
+	// +optional
+	LastCompactScheduleTime *metav1.Time `json:"lastCompactScheduleTime,omitempty"`
 	// AllBackupCleanTime represents the time when all backup entries are cleaned up
 	AllBackupCleanTime *metav1.Time `json:"allBackupCleanTime,omitempty"`
+	// LastScheduleTime represents the last scheduled time that has been handled,
+	// whether a backup was run for it or not.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// MissedRuns represents the number of scheduled times that were missed without
+	// running a backup.
+	// +optional
+	MissedRuns int32 `json:"missedRuns,omitempty"`
+	// LastMissedRunTime represents the last scheduled time that was missed.
+	// +optional
+	LastMissedRunTime *metav1.Time `json:"lastMissedRunTime,omitempty"`
+	// StorageUsage represents the storage used by the snapshot backups of the schedule,
+	// it's refreshed by listing the backup objects after a backup completes.
+	// +optional
+	StorageUsage *BackupScheduleStorageUsage `json:"storageUsage,omitempty"`
+}
+
+// BackupScheduleStorageUsage represents the storage used by the completed snapshot
+// backups of a backup schedule, measured by listing the objects in the backup storage.
+type BackupScheduleStorageUsage struct {
+	// LastBackup is the name of the last completed backup.
+	LastBackup string `json:"lastBackup,omitempty"`
+	// LastBackupSize is the size of the objects of the last completed backup in bytes.
+	LastBackupSize int64 `json:"lastBackupSize,omitempty"`
+	// LastBackupSizeReadable is the human readable format of LastBackupSize.
+	LastBackupSizeReadable string `json:"lastBackupSizeReadable,omitempty"`
+	// TotalSize is the size of the objects of all the completed backups kept by the schedule in bytes.
+	TotalSize int64 `json:"totalSize,omitempty"`
+	// TotalSizeReadable is the human readable format of TotalSize.
+	TotalSizeReadable string `json:"totalSizeReadable,omitempty"`
+	// BackupCount is the number of the completed backups kept by the schedule.
+	BackupCount int32 `json:"backupCount,omitempty"`
+	// RemainingBackups is the number of backups that can be created before the oldest
+	// one is deleted, only set when maxBackups is the GC policy.
+	// +optional
+	RemainingBackups *int32 `json:"remainingBackups,omitempty"`
+	// OldestBackupExpireTime is the time when the oldest backup is deleted, only set
+	// when maxReservedTime is the GC policy.
+	// +optional
+	OldestBackupExpireTime *metav1.Time `json:"oldestBackupExpireTime,omitempty"`
+	// UpdateTime is the last time the usage was refreshed.
+	// +optional
+	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
 }
 
 // +genclient
@@ -2703,6 +5030,9 @@ const (
 type RestoreConditionType string
 
 const (
+	// RestorePending means the restore job is waiting to be created because too many
+	// backup and restore jobs are running
+	RestorePending RestoreConditionType = "Pending"
 	// RestoreScheduled means the restore job has been created to do tidb cluster restore
 	RestoreScheduled RestoreConditionType = "Scheduled"
 	// RestoreRunning means the Restore is currently being executed.
@@ -2722,6 +5052,8 @@ const (
 	RestoreDataComplete RestoreConditionType = "DataComplete"
 	// RestoreTiKVComplete means in volume restore, all TiKV instances are started and up
 	RestoreTiKVComplete RestoreConditionType = "TikvComplete"
+	// RestoreIntegrityChecking means the data is restored and the integrity check is running
+	RestoreIntegrityChecking RestoreConditionType = "IntegrityChecking"
 	// RestoreComplete means the Restore has successfully executed and the
 	// backup data has been loaded into tidb cluster.
 	RestoreComplete RestoreConditionType = "Complete"
@@ -2853,6 +5185,11 @@ type RestoreSpec struct {
 	// PriorityClassName of Restore Job Pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// PreemptionPolicy of Restore Job Pods. It requires PriorityClassName and must match the
+	// preemptionPolicy of the PriorityClass, otherwise the admission rejects the pods
+	// +optional
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
 	// Additional volumes of component pod.
 	// +optional
 	AdditionalVolumes []corev1.Volume `json:"additionalVolumes,omitempty"`
@@ -2864,6 +5201,25 @@ type RestoreSpec struct {
 	TolerateSingleTiKVOutage bool `json:"tolerateSingleTiKVOutage,omitempty"`
 	// +kubebuilder:default=0
 	BackoffLimit int32 `json:"backoffLimit,omitempty"`
+	// IntegrityCheck runs `ADMIN CHECKSUM TABLE` on the restored tables after the data is restored,
+	// and compares the result with the checksums recorded in the backup.
+	// It requires `.spec.to` to connect to the restored cluster, and it's ignored in PiTR and volume-snapshot mode.
+	// +optional
+	IntegrityCheck *RestoreIntegrityCheckSpec `json:"integrityCheck,omitempty"`
+
+	// Notifications posts the events of the restore to a webhook, so that the pipelines are notified
+	// instead of polling the status of the restore.
+	// +optional
+	Notifications *BRNotifications `json:"notifications,omitempty"`
+}
+
+// RestoreIntegrityCheckSpec configures the data integrity check after the restore
+// +k8s:openapi-gen=true
+type RestoreIntegrityCheckSpec struct {
+	// TableFilter limits the tables to check, it supports the wildcard syntax of BR table filter.
+	// Optional: Defaults to all the restored tables
+	// +optional
+	TableFilter []string `json:"tableFilter,omitempty"`
 }
 
 // FederalVolumeRestorePhase represents a phase to execute in federal volume restore
@@ -2921,6 +5277,64 @@ type RestoreStatus struct {
 	// Progresses is the progress of restore.
 	// +nullable
 	Progresses []Progress `json:"progresses,omitempty"`
+	// IntegrityCheck is the result of the data integrity check after the restore.
+	// +optional
+	IntegrityCheck *RestoreIntegrityCheckStatus `json:"integrityCheck,omitempty"`
+	// VersionCompatibility is the result of the version compatibility check between the cluster
+	// the backup was taken from and the cluster to restore to.
+	// +optional
+	VersionCompatibility *RestoreVersionCompatibility `json:"versionCompatibility,omitempty"`
+	// JobSummaries are the summaries of the jobs of the restore deleted by the job garbage collector.
+	// +optional
+	JobSummaries []BRJobSummary `json:"jobSummaries,omitempty"`
+}
+
+// RestoreVersionCompatibility is the result of the version compatibility check of the restore
+type RestoreVersionCompatibility struct {
+	// SourceVersion is the version of the cluster the backup was taken from, recorded in the backup metadata
+	SourceVersion string `json:"sourceVersion"`
+	// TargetVersion is the version of the cluster to restore to
+	TargetVersion string `json:"targetVersion"`
+	// BROptions are the BR options required by the cross-version restore, they are put before
+	// spec.br.options so that they can be overridden.
+	// +optional
+	BROptions []string `json:"brOptions,omitempty"`
+}
+
+// RestoreIntegrityCheckStatus is the result of the data integrity check after the restore
+type RestoreIntegrityCheckStatus struct {
+	// Passed is true if the total kvs and bytes of all the checked tables match the backup
+	Passed bool `json:"passed"`
+	// CheckedTables is the number of the checked tables
+	CheckedTables int32 `json:"checkedTables"`
+	// Mismatches are the tables whose checksums don't match the backup,
+	// at most 100 tables are recorded.
+	// +optional
+	Mismatches []TableChecksumMismatch `json:"mismatches,omitempty"`
+	// TimeCompleted is the time at which the check was completed.
+	// +nullable
+	TimeCompleted metav1.Time `json:"timeCompleted,omitempty"`
+}
+
+// TableChecksumMismatch records a table whose checksum doesn't match the backup
+type TableChecksumMismatch struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+	// Expected is the checksum recorded in the backup
+	Expected TableChecksum `json:"expected"`
+	// Actual is the checksum of the restored table, it's empty if the table is not found
+	// +optional
+	Actual *TableChecksum `json:"actual,omitempty"`
+}
+
+// TableChecksum is the result of `ADMIN CHECKSUM TABLE`
+type TableChecksum struct {
+	// Crc64Xor is formatted as a decimal string as it may overflow int64.
+	// It's recorded for reference only and not compared, as the table IDs
+	// in the keys are rewritten by the restore.
+	Crc64Xor   string `json:"crc64Xor"`
+	TotalKvs   uint64 `json:"totalKvs"`
+	TotalBytes uint64 `json:"totalBytes"`
 }
 
 // +k8s:openapi-gen=true
@@ -3033,6 +5447,13 @@ type DMClusterSpec struct {
 	// +kubebuilder:default=Retain
 	PVReclaimPolicy *corev1.PersistentVolumeReclaimPolicy `json:"pvReclaimPolicy,omitempty"`
 
+	// DeletionPolicy is the policy to delete the cluster and its data, the policies other than Background
+	// are carried out by a finalizer of the operator before the cluster is deleted.
+	// Optional: Defaults to Background
+	// +kubebuilder:validation:Enum=Background;RetainData;Purge
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
 	// ImagePullPolicy of DM cluster Pods
 	// +kubebuilder:default=IfNotPresent
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
@@ -3063,6 +5484,17 @@ type DMClusterSpec struct {
 	// +optional
 	TLSClientSecretNames []string `json:"tlsClientSecretNames,omitempty"`
 
+	// DatabaseTLS configures the TLS connections to the upstream sources and the downstream targets.
+	// The client certificates of the sources are applied to the sources with the same names through
+	// the OpenAPI of dm-master. The secrets of the targets are mounted into dm-master at
+	// /var/lib/source-tls/<secretName>, and the security configs referring to them are rendered into
+	// the ConfigMap <cluster>-dm-database-tls mounted at /etc/dm-database-tls, to be merged into the
+	// task configs started by dmctl in dm-master.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	DatabaseTLS []DMDatabaseTLS `json:"databaseTLS,omitempty"`
+
 	// Whether Hostnetwork is enabled for DM cluster Pods
 	// Optional: Defaults to false
 	// +optional
@@ -3077,6 +5509,22 @@ type DMClusterSpec struct {
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 
+	// PreemptionPolicy of DM cluster Pods. It requires PriorityClassName and must match the
+	// preemptionPolicy of the PriorityClass, otherwise the admission rejects the pods
+	// Optional: Defaults to omitted
+	// +optional
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
+	// RuntimeClassName of DM cluster Pods
+	// Optional: Defaults to omitted
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// SchedulingGates of DM cluster Pods, the pods are not scheduled until the gates are removed from them
+	// Optional: Defaults to omitted
+	// +optional
+	SchedulingGates []corev1.PodSchedulingGate `json:"schedulingGates,omitempty"`
+
 	// Base node selectors of DM cluster Pods, components may add or override selectors upon this respectively
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -3108,6 +5556,10 @@ type DMClusterSpec struct {
 	// +optional
 	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
 
+	// HostAliases is an optional list of hosts and IPs that will be injected into the pod's hosts file.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
 	// PodSecurityContext of the component
 	// +optional
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
@@ -3133,10 +5585,47 @@ type DMClusterSpec struct {
 	// +optional
 	SuspendAction *SuspendAction `json:"suspendAction,omitempty"`
 
+	// InjectResourceHints indicates whether to inject GOMAXPROCS and GOMEMLIMIT derived from
+	// the resource limits for all components.
+	// Note that changing it may cause the pods to be recreated.
+	// Optional: Defaults to false
+	// +optional
+	InjectResourceHints *bool `json:"injectResourceHints,omitempty"`
+
 	// PreferIPv6 indicates whether to prefer IPv6 addresses for all components.
 	PreferIPv6 bool `json:"preferIPv6,omitempty"`
 }
 
+// DMDatabaseType is the type of a database connected by DM
+type DMDatabaseType string
+
+const (
+	// DMDatabaseTypeSource is an upstream source of DM
+	DMDatabaseTypeSource DMDatabaseType = "source"
+	// DMDatabaseTypeTarget is a downstream target of DM
+	DMDatabaseTypeTarget DMDatabaseType = "target"
+)
+
+// DMDatabaseTLS is the TLS configuration to connect to an upstream source or a downstream target
+// +k8s:openapi-gen=true
+type DMDatabaseTLS struct {
+	// Name of the source or the target. For the sources, it is the source-id of the source the client
+	// certificate is applied to. For the targets, the security config is rendered into <name>.yaml of the ConfigMap.
+	Name string `json:"name"`
+
+	// Type of the database, "source" for an upstream source and "target" for a downstream target
+	// +kubebuilder:validation:Enum:="source";"target"
+	Type DMDatabaseType `json:"type"`
+
+	// SecretName is the name of the secret which stores the client certificate of the database,
+	// with the keys ca.crt, tls.crt and tls.key
+	SecretName string `json:"secretName"`
+
+	// CertAllowedCN is the allowed common names of the certificate of the database
+	// +optional
+	CertAllowedCN []string `json:"certAllowedCN,omitempty"`
+}
+
 // DMClusterStatus represents the current status of a dm cluster.
 type DMClusterStatus struct {
 	Master MasterStatus `json:"master,omitempty"`
@@ -3275,6 +5764,56 @@ type WorkerSpec struct {
 	// Failover is the configurations of failover
 	// +optional
 	Failover *Failover `json:"failover,omitempty"`
+
+	// RelayStorage stores the relay logs in a dedicated PVC mounted at /var/lib/dm-worker-relay instead
+	// of the data volume, so the relay logs don't fill the data volume.
+	// It takes effect only when the dm-worker statefulset is created, as the PVC templates of the
+	// statefulset are immutable.
+	// +optional
+	RelayStorage *DMRelayStorage `json:"relayStorage,omitempty"`
+
+	// RelayPurge is the purge policy of the relay logs applied to all the sources through the OpenAPI
+	// of dm-master, which is enabled in the config of dm-master if it's set. It requires DM v6.0 or later.
+	// +optional
+	RelayPurge *DMRelayPurgePolicy `json:"relayPurge,omitempty"`
+}
+
+// DMRelayStorage is the dedicated storage of the relay logs of dm-worker
+// +k8s:openapi-gen=true
+type DMRelayStorage struct {
+	// The storageClassName of the persistent volume for the relay logs.
+	// Defaults to Kubernetes default storage class.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// StorageSize is the request storage size for the relay logs.
+	// Defaults to "10Gi".
+	// +optional
+	StorageSize string `json:"storageSize,omitempty"`
+}
+
+// DMRelayPurgePolicy is the purge policy of the relay logs of the sources, the relay logs still
+// being read by the tasks are never purged
+// +k8s:openapi-gen=true
+type DMRelayPurgePolicy struct {
+	// Interval is the interval in seconds between the purges of the relay logs.
+	// Optional: Defaults to 3600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Interval *int64 `json:"interval,omitempty"`
+
+	// Expires is the hours the relay logs are kept for, 0 means the relay logs never expire.
+	// Optional: Defaults to 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Expires *int64 `json:"expires,omitempty"`
+
+	// RemainSpace is the free space in GB of the relay storage below which the oldest relay logs
+	// are purged.
+	// Optional: Defaults to 15
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RemainSpace *int64 `json:"remainSpace,omitempty"`
 }
 
 // DMClusterCondition is dm cluster condition
@@ -3541,6 +6080,25 @@ type ScalePolicy struct {
 	ScaleOutParallelism *int32 `json:"scaleOutParallelism,omitempty"`
 }
 
+// RollingUpdateStrategy is the rolling update configuration of a component
+// +k8s:openapi-gen=true
+type RollingUpdateStrategy struct {
+	// MaxUnavailable is the max number of pods upgraded at the same time, it can be an absolute number
+	// or a percentage of the replicas. For TiKV, the pods are upgraded at the same time only if the
+	// majority of replicas of every region is kept available according to the replication config of PD.
+	// Defaults to 1
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Partition keeps the pods with an ordinal less than it at the old revision, which can be used
+	// for canary upgrades. The pods below the partition are held intentionally, so the component returns
+	// to the Normal phase once the pods at or above the partition are upgraded, and is upgraded again
+	// when the partition is lowered.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Partition *int32 `json:"partition,omitempty"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
@@ -3632,6 +6190,11 @@ type CompactSpec struct {
 	// PriorityClassName of Backup Job Pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// PreemptionPolicy of Backup Job Pods. It requires PriorityClassName and must match the
+	// preemptionPolicy of the PriorityClass, otherwise the admission rejects the pods
+	// +optional
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
 	// BackoffRetryPolicy the backoff retry policy, currently only valid for snapshot backup
 	// +kubebuilder:default=6
 	MaxRetryTimes int32 `json:"maxRetryTimes,omitempty"`
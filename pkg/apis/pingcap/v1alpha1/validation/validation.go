@@ -21,6 +21,7 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
@@ -31,11 +32,21 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilnet "k8s.io/utils/net"
 )
 
+const (
+	// shellSpecialChars are the characters not allowed in the additional arguments
+	shellSpecialChars = " \t\n\"'`\\$;|&<>(){}[]*?!#~"
+)
+
+// envReferenceRegexp matches the `$(VAR_NAME)` references in the additional arguments
+var envReferenceRegexp = regexp.MustCompile(`\$\([A-Za-z_][A-Za-z0-9_]*\)`)
+
 // ValidateTidbCluster validates a TidbCluster, it performs basic validation for all TidbClusters despite it is legacy
 // or not
 func ValidateTidbCluster(tc *v1alpha1.TidbCluster) field.ErrorList {
@@ -80,6 +91,7 @@ func ValidateTiDBDashboard(td *v1alpha1.TidbDashboard) field.ErrorList {
 	}
 
 	allErrs = append(allErrs, validateComponentSpec(&td.Spec.ComponentSpec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePreemptionPolicy(td.Spec.PreemptionPolicy, td.Spec.PriorityClassName, field.NewPath("spec"))...)
 
 	if len(td.Spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(td.Spec.StorageVolumes, field.NewPath("spec").Child("storageVolumes"))...)
@@ -88,6 +100,184 @@ func ValidateTiDBDashboard(td *v1alpha1.TidbDashboard) field.ErrorList {
 	return allErrs
 }
 
+// ValidateTidbClusterReplication validates a TidbClusterReplication
+func ValidateTidbClusterReplication(tcr *v1alpha1.TidbClusterReplication) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if tcr.Spec.Primary.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("primary", "name"), "the primary cluster must be specified"))
+	}
+	if tcr.Spec.Secondary.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("secondary", "name"), "the secondary cluster must be specified"))
+	}
+	if tcr.Spec.Primary.Name == tcr.Spec.Secondary.Name && tcr.PrimaryNamespace() == tcr.SecondaryNamespace() &&
+		tcr.Spec.Primary.ClusterDomain == tcr.Spec.Secondary.ClusterDomain {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("secondary"), tcr.Spec.Secondary, "the secondary cluster must be different from the primary cluster"))
+	}
+
+	switch tcr.GetMode() {
+	case v1alpha1.ReplicationModeTiCDC:
+	case v1alpha1.ReplicationModePITR:
+		if tcr.Spec.PITR == nil {
+			allErrs = append(allErrs, field.Required(specPath.Child("pitr"), "pitr must be specified in PITR mode"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("mode"), tcr.Spec.Mode,
+			[]string{string(v1alpha1.ReplicationModeTiCDC), string(v1alpha1.ReplicationModePITR)}))
+	}
+
+	if tcr.Spec.MaxPromoteLag != nil {
+		if _, err := time.ParseDuration(*tcr.Spec.MaxPromoteLag); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("maxPromoteLag"), *tcr.Spec.MaxPromoteLag, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidatePumpMigration validates a PumpMigration
+func ValidatePumpMigration(pm *v1alpha1.PumpMigration) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if pm.Spec.Cluster.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("cluster", "name"), "the cluster must be specified"))
+	}
+	if pm.Spec.Cluster.Namespace != "" && pm.Spec.Cluster.Namespace != pm.Namespace {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("cluster", "namespace"), pm.Spec.Cluster.Namespace, "the cluster must be in the namespace of the migration"))
+	}
+	if len(pm.Spec.Changefeeds) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("changefeeds"), "at least one changefeed must be specified"))
+	}
+	ids := map[string]struct{}{}
+	for i, cf := range pm.Spec.Changefeeds {
+		cfPath := specPath.Child("changefeeds").Index(i)
+		if cf.ID == "" {
+			allErrs = append(allErrs, field.Required(cfPath.Child("id"), "the changefeed id must be specified"))
+		} else if _, ok := ids[cf.ID]; ok {
+			allErrs = append(allErrs, field.Duplicate(cfPath.Child("id"), cf.ID))
+		}
+		ids[cf.ID] = struct{}{}
+		if cf.SinkURI == "" && cf.SinkURISecretName == "" {
+			allErrs = append(allErrs, field.Required(cfPath.Child("sinkURI"), "either sinkURI or sinkURISecretName must be specified"))
+		}
+	}
+
+	if pm.Spec.MaxCatchUpLag != nil {
+		if _, err := time.ParseDuration(*pm.Spec.MaxCatchUpLag); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("maxCatchUpLag"), *pm.Spec.MaxCatchUpLag, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// tidbPrivilegeRegex matches the names of the static and dynamic privileges of TiDB,
+// e.g. SELECT, ALL PRIVILEGES or BACKUP_ADMIN
+var tidbPrivilegeRegex = regexp.MustCompile(`^[A-Za-z_]+( [A-Za-z_]+)*$`)
+
+// tidbUserMaxLength is the max length of the names of the users of TiDB
+const tidbUserMaxLength = 32
+
+// ValidateTidbAccount validates a TidbAccount
+func ValidateTidbAccount(ta *v1alpha1.TidbAccount) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if ta.Spec.Cluster.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("cluster", "name"), "the cluster must be specified"))
+	}
+	if ta.Spec.User == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("user"), "the user must be specified"))
+	} else if len(ta.Spec.User) > tidbUserMaxLength {
+		allErrs = append(allErrs, field.TooLong(specPath.Child("user"), ta.Spec.User, tidbUserMaxLength))
+	}
+	if ta.Spec.PasswordSecret.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("passwordSecret", "name"), "the password secret must be specified"))
+	}
+	if ta.Spec.PasswordSecret.Key == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("passwordSecret", "key"), "the key of the password in the secret must be specified"))
+	}
+
+	for i := range ta.Spec.Privileges {
+		p := &ta.Spec.Privileges[i]
+		pPath := specPath.Child("privileges").Index(i)
+		if len(p.Privileges) == 0 {
+			allErrs = append(allErrs, field.Required(pPath.Child("privileges"), "at least one privilege must be specified"))
+		}
+		for j, name := range p.Privileges {
+			if !tidbPrivilegeRegex.MatchString(name) {
+				allErrs = append(allErrs, field.Invalid(pPath.Child("privileges").Index(j), name, "must be the name of a privilege, e.g. SELECT"))
+			}
+		}
+		if _, _, ok := p.GetLevel(); !ok {
+			allErrs = append(allErrs, field.Invalid(pPath.Child("on"), p.On, "must be *.*, db.* or db.table"))
+		}
+	}
+
+	if ta.Spec.TLS != nil {
+		switch ta.Spec.TLS.Require {
+		case "", v1alpha1.TLSRequireNone, v1alpha1.TLSRequireSSL, v1alpha1.TLSRequireX509:
+		default:
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("tls", "require"), ta.Spec.TLS.Require,
+				[]string{string(v1alpha1.TLSRequireNone), string(v1alpha1.TLSRequireSSL), string(v1alpha1.TLSRequireX509)}))
+		}
+	}
+
+	switch ta.GetReclaimPolicy() {
+	case v1alpha1.TidbAccountReclaimRetain, v1alpha1.TidbAccountReclaimDelete:
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("reclaimPolicy"), ta.Spec.ReclaimPolicy,
+			[]string{string(v1alpha1.TidbAccountReclaimRetain), string(v1alpha1.TidbAccountReclaimDelete)}))
+	}
+
+	return allErrs
+}
+
+// ValidateBackupSchedule validates a BackupSchedule, the cron expression of the schedule is validated
+// by the caller as the cron parser is not a dependency of the API
+func ValidateBackupSchedule(bs *v1alpha1.BackupSchedule) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if bs.Spec.Schedule == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("schedule"), "the schedule must be specified"))
+	}
+	if bs.Spec.TimeZone != "" {
+		if _, err := time.LoadLocation(bs.Spec.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("timeZone"), bs.Spec.TimeZone, err.Error()))
+		}
+	}
+	if cs := bs.Spec.CompactSchedule; cs != nil {
+		fldPath := specPath.Child("compactSchedule")
+		if cs.Schedule == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("schedule"), "the schedule of the compaction must be specified"))
+		}
+		for i, window := range cs.Windows {
+			if _, _, err := v1alpha1.ParseCompactWindow(window); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("windows").Index(i), window, err.Error()))
+			}
+		}
+		if cs.MaxConcurrentCompacts < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxConcurrentCompacts"), cs.MaxConcurrentCompacts, "must be greater than or equal to 0"))
+		}
+		if bs.Spec.LogBackupTemplate == nil || bs.Spec.CompactBackupTemplate == nil {
+			allErrs = append(allErrs, field.Required(specPath.Child("compactBackupTemplate"), "the log backup and the compact backup templates are required by the compact schedule"))
+		}
+	}
+	if transition := bs.Spec.StorageClassTransition; transition != nil {
+		fldPath := specPath.Child("storageClassTransition", "storageClass")
+		if transition.StorageClass == "" {
+			allErrs = append(allErrs, field.Required(fldPath, "the storage class must be specified"))
+		} else if v1alpha1.IsArchiveStorageClass(transition.StorageClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath, transition.StorageClass, "the data in an archive storage class can't be read by BR without restoring the objects first"))
+		}
+	}
+
+	return allErrs
+}
+
 func ValidateTidbMonitor(monitor *v1alpha1.TidbMonitor) field.ErrorList {
 	allErrs := field.ErrorList{}
 	// validate monitor service
@@ -98,9 +288,31 @@ func ValidateTidbMonitor(monitor *v1alpha1.TidbMonitor) field.ErrorList {
 	allErrs = append(allErrs, validateService(&monitor.Spec.Prometheus.Service, field.NewPath("spec"))...)
 	allErrs = append(allErrs, validatePromDurationStr(monitor.Spec.Prometheus.RetentionTime, field.NewPath("spec"))...)
 	allErrs = append(allErrs, validateService(&monitor.Spec.Reloader.Service, field.NewPath("spec"))...)
+	if am := monitor.Spec.Alertmanager; am != nil {
+		fldPath := field.NewPath("spec", "alertmanager")
+		allErrs = append(allErrs, validateService(&am.Service, fldPath)...)
+		if am.URL != nil && len(*am.URL) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), "", "url must not be empty"))
+		}
+		if am.URL == nil && len(am.BaseImage) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("baseImage"), "baseImage is required to deploy alertmanager"))
+		}
+	}
+	if monitor.Spec.Thanos != nil {
+		allErrs = append(allErrs, validateThanosSpec(monitor, field.NewPath("spec", "thanos"))...)
+	}
+	if monitor.Spec.GrafanaExport != nil {
+		allErrs = append(allErrs, validateGrafanaExport(monitor.Spec.GrafanaExport, field.NewPath("spec", "grafanaExport"))...)
+	}
+	for i, ref := range monitor.Spec.ExtraAlertRules {
+		if len(ref.Name) == 0 {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec", "extraAlertRules").Index(i).Child("name"), "the name of the configmap is required"))
+		}
+	}
 	if monitor.Spec.Persistent {
 		allErrs = append(allErrs, validateStorageInfo(monitor.Spec.Storage, field.NewPath("spec"))...)
 	}
+	allErrs = append(allErrs, validatePreemptionPolicy(monitor.Spec.PreemptionPolicy, monitor.Spec.PriorityClassName, field.NewPath("spec"))...)
 	return allErrs
 }
 
@@ -125,10 +337,16 @@ func validateDMAnnotations(anns map[string]string, fldPath *field.Path) field.Er
 
 func validateTiDBClusterSpec(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateSchedulingGates(spec.SchedulingGates, fldPath.Child("schedulingGates"))...)
+	allErrs = append(allErrs, validateRestartAt(spec.Annotations, fldPath.Child("annotations"))...)
+	allErrs = append(allErrs, validateTiDBClusterPreemptionPolicy(spec, fldPath)...)
 
 	allErrs = append(allErrs, validateDiscoverySpec(spec.Discovery, fldPath.Child("discovery"))...)
 	if spec.PD != nil {
 		allErrs = append(allErrs, validatePDSpec(spec.PD, fldPath.Child("pd"))...)
+		if spec.PD.LearnerReplicas > 0 {
+			allErrs = append(allErrs, validatePDLearners(spec, fldPath)...)
+		}
 	}
 	if spec.PDMS != nil {
 		for _, comp := range spec.PDMS {
@@ -153,12 +371,376 @@ func validateTiDBClusterSpec(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path
 	if spec.PDAddresses != nil {
 		allErrs = append(allErrs, validatePDAddresses(spec.PDAddresses, fldPath.Child("pdAddresses"))...)
 	}
+	if spec.ExternalPD != nil {
+		allErrs = append(allErrs, validateExternalPD(spec, fldPath)...)
+	}
 	if spec.StartScriptV2FeatureFlags != nil {
 		allErrs = append(allErrs, validateStartScriptFeatureFlags(spec.StartScriptV2FeatureFlags, fldPath.Child("startScriptV2FeatureFlags"))...)
 	}
+	if spec.SuspendAction != nil {
+		allErrs = append(allErrs, validateSuspendAction(spec.SuspendAction, []v1alpha1.MemberType{
+			v1alpha1.TiProxyMemberType,
+			v1alpha1.TiDBMemberType,
+			v1alpha1.TiCDCMemberType,
+			v1alpha1.TiFlashMemberType,
+			v1alpha1.TiKVMemberType,
+			v1alpha1.PumpMemberType,
+			v1alpha1.PDMemberType,
+			v1alpha1.PDMSTSOMemberType,
+			v1alpha1.PDMSSchedulingMemberType,
+		}, fldPath.Child("suspendAction"))...)
+	}
+	if spec.UpgradePolicy != nil {
+		allErrs = append(allErrs, validateUpgradePolicy(spec.UpgradePolicy, fldPath.Child("upgradePolicy"))...)
+	}
+	if spec.PropagatePolicy != nil {
+		allErrs = append(allErrs, validatePropagatePolicy(spec.PropagatePolicy, fldPath.Child("propagatePolicy"))...)
+	}
+	if spec.Velero != nil {
+		allErrs = append(allErrs, validateVeleroSpec(spec.Velero, fldPath.Child("velero"))...)
+	}
+	if spec.AcrossK8sResolver != nil {
+		allErrs = append(allErrs, validateAcrossK8sResolver(spec.AcrossK8sResolver, fldPath.Child("acrossK8sResolver"))...)
+	}
+	if spec.ClusterCloneFrom != nil {
+		allErrs = append(allErrs, validateClusterCloneFrom(spec.ClusterCloneFrom, fldPath.Child("clusterCloneFrom"))...)
+	}
+	if spec.DriftProtection != nil {
+		allErrs = append(allErrs, validateDriftProtection(spec.DriftProtection, fldPath.Child("driftProtection"))...)
+	}
+	if spec.ResourceRecommendation != nil {
+		allErrs = append(allErrs, validateResourceRecommendation(spec.ResourceRecommendation, fldPath.Child("resourceRecommendation"))...)
+	}
+	allErrs = append(allErrs, validateDeletionPolicy(spec.DeletionPolicy, fldPath.Child("deletionPolicy"))...)
+	if spec.ProfileCapture != nil {
+		allErrs = append(allErrs, validateProfileCapture(spec, fldPath.Child("profileCapture"))...)
+	}
+	if spec.PrometheusMonitor != nil {
+		allErrs = append(allErrs, validatePrometheusMonitor(spec.PrometheusMonitor, fldPath.Child("prometheusMonitor"))...)
+	}
+	allErrs = append(allErrs, validateGRPCProbes(spec, fldPath)...)
+	return allErrs
+}
+
+// validateClusterCloneFrom checks the source cluster and the storage of the backup are set
+func validateClusterCloneFrom(from *v1alpha1.ClusterCloneFrom, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if from.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "the name of the source cluster is required"))
+	}
+	storages := 0
+	for _, set := range []bool{from.S3 != nil, from.Gcs != nil, from.Azblob != nil} {
+		if set {
+			storages++
+		}
+	}
+	if storages != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, storages, "exactly one of s3, gcs and azblob must be set as the storage of the backup"))
+	}
+	switch from.Method {
+	case "", v1alpha1.ClusterCloneMethodBR, v1alpha1.ClusterCloneMethodVolumeSnapshot:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("method"), from.Method, []string{
+			string(v1alpha1.ClusterCloneMethodBR),
+			string(v1alpha1.ClusterCloneMethodVolumeSnapshot),
+		}))
+	}
+	return allErrs
+}
+
+// validateDriftProtection checks the policy and the exempt managers of the drift protection
+func validateDriftProtection(protection *v1alpha1.DriftProtection, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch protection.Policy {
+	case "", v1alpha1.DriftPolicyAlert, v1alpha1.DriftPolicyRevert:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("policy"), protection.Policy, []string{
+			string(v1alpha1.DriftPolicyAlert),
+			string(v1alpha1.DriftPolicyRevert),
+		}))
+	}
+	for i, manager := range protection.ExemptManagers {
+		if manager == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("exemptManagers").Index(i), "the field manager must not be empty"))
+		}
+	}
+	return allErrs
+}
+
+// validateDeletionPolicy checks the deletion policy of a cluster is supported
+func validateDeletionPolicy(policy v1alpha1.DeletionPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch policy {
+	case "", v1alpha1.DeletionPolicyBackground, v1alpha1.DeletionPolicyRetainData, v1alpha1.DeletionPolicyPurge:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, policy, []string{
+			string(v1alpha1.DeletionPolicyBackground),
+			string(v1alpha1.DeletionPolicyRetainData),
+			string(v1alpha1.DeletionPolicyPurge),
+		}))
+	}
+	return allErrs
+}
+
+// validateProfileCapture checks the member, the type and the storage of the profile capture
+func validateProfileCapture(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	capture := spec.ProfileCapture
+	deployed := map[v1alpha1.MemberType]bool{
+		v1alpha1.PDMemberType:   spec.PD != nil,
+		v1alpha1.TiKVMemberType: spec.TiKV != nil,
+		v1alpha1.TiDBMemberType: spec.TiDB != nil,
+	}
+	if d, ok := deployed[capture.Component]; !ok {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("component"), capture.Component, []string{
+			string(v1alpha1.PDMemberType),
+			string(v1alpha1.TiKVMemberType),
+			string(v1alpha1.TiDBMemberType),
+		}))
+	} else if !d {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("component"), capture.Component, "the component is not deployed by this cluster"))
+	}
+	if capture.Ordinal < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ordinal"), capture.Ordinal, "must not be negative"))
+	}
+	switch capture.Type {
+	case "", v1alpha1.ProfileTypeCPU, v1alpha1.ProfileTypeHeap:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), capture.Type, []string{
+			string(v1alpha1.ProfileTypeCPU),
+			string(v1alpha1.ProfileTypeHeap),
+		}))
+	}
+	if capture.Duration != nil && capture.Duration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("duration"), capture.Duration.Duration.String(), "must be positive"))
+	}
+	storages := 0
+	for _, set := range []bool{capture.S3 != nil, capture.Gcs != nil, capture.Azblob != nil} {
+		if set {
+			storages++
+		}
+	}
+	if storages != 1 || capture.Local != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, storages, "exactly one of s3, gcs and azblob must be set as the storage of the profile"))
+	}
+	return allErrs
+}
+
+// validatePrometheusMonitor checks the labels and the scrape interval of the PodMonitors
+func validatePrometheusMonitor(monitor *v1alpha1.PrometheusMonitorSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := metav1validation.ValidateLabels(monitor.Labels, fldPath.Child("labels"))
+	if monitor.Interval != nil && monitor.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), monitor.Interval.Duration.String(), "must be positive"))
+	}
+	return allErrs
+}
+
+// validateResourceRecommendation checks the components, the bounds and the auto apply of the resource recommendation
+func validateResourceRecommendation(policy *v1alpha1.ResourceRecommendationPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	supported := []v1alpha1.MemberType{
+		v1alpha1.PDMemberType,
+		v1alpha1.TiDBMemberType,
+		v1alpha1.TiKVMemberType,
+		v1alpha1.TiFlashMemberType,
+		v1alpha1.TiCDCMemberType,
+		v1alpha1.TiProxyMemberType,
+	}
+	for i, component := range policy.Components {
+		valid := false
+		for _, c := range supported {
+			if component == c {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("components").Index(i), component, memberTypesToStrings(supported)))
+		}
+	}
+	if policy.MarginPercent != nil && *policy.MarginPercent < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("marginPercent"), *policy.MarginPercent, "must not be negative"))
+	}
+	if policy.Window != nil && policy.Window.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("window"), policy.Window.Duration.String(), "must be positive"))
+	}
+	for _, bounds := range []struct {
+		name string
+		list corev1.ResourceList
+	}{{"minAllowed", policy.MinAllowed}, {"maxAllowed", policy.MaxAllowed}} {
+		for name := range bounds.list {
+			if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+				allErrs = append(allErrs, field.NotSupported(fldPath.Child(bounds.name).Key(string(name)), name,
+					[]string{string(corev1.ResourceCPU), string(corev1.ResourceMemory)}))
+			}
+		}
+	}
+	for name, lower := range policy.MinAllowed {
+		if upper, ok := policy.MaxAllowed[name]; ok && lower.Cmp(upper) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("minAllowed").Key(string(name)), lower.String(),
+				fmt.Sprintf("must not be greater than maxAllowed %s", upper.String())))
+		}
+	}
+	if apply := policy.AutoApply; apply != nil {
+		if apply.Schedule == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("autoApply", "schedule"), "the schedule of the maintenance windows must be specified"))
+		}
+		if apply.Duration != nil && apply.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("autoApply", "duration"), apply.Duration.Duration.String(), "must be positive"))
+		}
+		if apply.MinChangePercent != nil && *apply.MinChangePercent < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("autoApply", "minChangePercent"), *apply.MinChangePercent, "must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+// validateAcrossK8sResolver checks the resolver has what its type needs to resolve the services
+func validateAcrossK8sResolver(resolver *v1alpha1.AcrossK8sResolver, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch resolver.Type {
+	case v1alpha1.AcrossK8sResolverExternalDNS:
+		if resolver.Domain == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("domain"), "domain is required by the ExternalDNS resolver"))
+		}
+	case v1alpha1.AcrossK8sResolverStatic:
+		if len(resolver.Endpoints) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("endpoints"), "endpoints are required by the Static resolver"))
+		}
+	case v1alpha1.AcrossK8sResolverClusterDomain, v1alpha1.AcrossK8sResolverClusterSet, v1alpha1.AcrossK8sResolverClusterMesh:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), resolver.Type, []string{
+			string(v1alpha1.AcrossK8sResolverClusterDomain),
+			string(v1alpha1.AcrossK8sResolverExternalDNS),
+			string(v1alpha1.AcrossK8sResolverClusterSet),
+			string(v1alpha1.AcrossK8sResolverClusterMesh),
+			string(v1alpha1.AcrossK8sResolverStatic),
+		}))
+	}
+	return allErrs
+}
+
+// validateGRPCProbes checks the gRPC readiness probes are only used by tikv and tiflash
+// without TLS, as the kubelet doesn't support TLS for the gRPC probes
+func validateGRPCProbes(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	isGRPC := func(probe *v1alpha1.Probe) bool {
+		return probe != nil && probe.Type != nil && *probe.Type == v1alpha1.GRPCProbeType
+	}
+	notSupported := func(component string) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child(component, "readinessProbe", "type"), v1alpha1.GRPCProbeType,
+			[]string{v1alpha1.TCPProbeType, v1alpha1.CommandProbeType}))
+	}
+	if spec.PD != nil && isGRPC(spec.PD.ReadinessProbe) {
+		notSupported("pd")
+	}
+	if spec.TiDB != nil && isGRPC(spec.TiDB.ReadinessProbe) {
+		notSupported("tidb")
+	}
+	if spec.TiProxy != nil && isGRPC(spec.TiProxy.ReadinessProbe) {
+		notSupported("tiproxy")
+	}
+	if spec.TLSCluster == nil || !spec.TLSCluster.Enabled {
+		return allErrs
+	}
+	if spec.TiKV != nil && isGRPC(spec.TiKV.ReadinessProbe) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("tikv", "readinessProbe", "type"), v1alpha1.GRPCProbeType,
+			"grpc probe is not supported when TLS is enabled"))
+	}
+	if spec.TiFlash != nil && isGRPC(spec.TiFlash.ReadinessProbe) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("tiflash", "readinessProbe", "type"), v1alpha1.GRPCProbeType,
+			"grpc probe is not supported when TLS is enabled"))
+	}
+	return allErrs
+}
+
+// validatePropagatePolicy validates the keys are not empty and "*" is only used as the suffix
+func validatePropagatePolicy(policy *v1alpha1.PropagatePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	validateKeys := func(keys []string, fldPath *field.Path) {
+		for i, key := range keys {
+			if strings.TrimSuffix(key, "*") == "" || strings.Contains(strings.TrimSuffix(key, "*"), "*") {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, "must be a key or a prefix of keys ending with \"*\""))
+			}
+		}
+	}
+	validateKeys(policy.Labels, fldPath.Child("labels"))
+	validateKeys(policy.Annotations, fldPath.Child("annotations"))
+	return allErrs
+}
+
+// validateVeleroSpec validates each component has at most one hook, and each hook has a command
+func validateVeleroSpec(spec *v1alpha1.VeleroSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	components := map[v1alpha1.MemberType]bool{}
+	for i, hook := range spec.Hooks {
+		idxPath := fldPath.Child("hooks").Index(i)
+		if hook.Component == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("component"), "component must be specified"))
+		} else if components[hook.Component] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("component"), hook.Component))
+		}
+		components[hook.Component] = true
+		if len(hook.PreCommand) == 0 && len(hook.PostCommand) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath, "preCommand or postCommand must be specified"))
+		}
+		if hook.OnError != "" && hook.OnError != "Continue" && hook.OnError != "Fail" {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("onError"), hook.OnError, []string{"Continue", "Fail"}))
+		}
+	}
 	return allErrs
 }
 
+func validateUpgradePolicy(policy *v1alpha1.UpgradePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if _, err := semver.NewConstraint(policy.VersionRange); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("versionRange"), policy.VersionRange, err.Error()))
+	}
+	if len(policy.Versions) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("versions"), "at least one version must be specified"))
+	}
+	for i, version := range policy.Versions {
+		if _, err := semver.NewVersion(version); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("versions").Index(i), version, err.Error()))
+		}
+	}
+	if policy.Schedule == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("schedule"), "the schedule of the maintenance windows must be specified"))
+	}
+	if policy.Duration != nil && policy.Duration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("duration"), policy.Duration.Duration.String(), "must be positive"))
+	}
+	return allErrs
+}
+
+func validateSuspendAction(action *v1alpha1.SuspendAction, components []v1alpha1.MemberType, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if action.Component != "" {
+		valid := false
+		for _, c := range components {
+			if action.Component == c {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("component"), action.Component, memberTypesToStrings(components)))
+		}
+	}
+	if action.GracePeriod != nil && action.GracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gracePeriod"), action.GracePeriod.Duration.String(), "must not be negative"))
+	}
+	return allErrs
+}
+
+func memberTypesToStrings(types []v1alpha1.MemberType) []string {
+	strs := make([]string, 0, len(types))
+	for _, t := range types {
+		strs = append(strs, t.String())
+	}
+	return strs
+}
+
 func validateDiscoverySpec(spec v1alpha1.DiscoverySpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if spec.ComponentSpec != nil {
@@ -177,6 +759,39 @@ func validatePDSpec(spec *v1alpha1.PDSpec, fldPath *field.Path) field.ErrorList
 	if spec.Service != nil {
 		allErrs = append(allErrs, validateService(spec.Service, fldPath)...)
 	}
+	if spec.LogVolume != nil && spec.LogVolume.VolumeName != "" {
+		allErrs = append(allErrs, validateVolumeName(spec.LogVolume.VolumeName, spec.StorageVolumes, spec.AdditionalVolumes, spec.AdditionalVolumeMounts, fldPath.Child("logVolume"))...)
+	}
+	allErrs = append(allErrs, validatePDLeaderPriorities(spec.LeaderPriorities, fldPath.Child("leaderPriorities"))...)
+	return allErrs
+}
+
+// validatePDLearners validates that the PD learners get their start args from the discovery service,
+// which adds them to the PD cluster as learners and is only supported by start script v2
+func validatePDLearners(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.StartScriptVersion != v1alpha1.StartScriptV2 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("startScriptVersion"), spec.StartScriptVersion, "start script v2 is required by the pd learners"))
+	}
+	if len(spec.PDAddresses) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pdAddresses"), spec.PDAddresses, "pd learners can't join the pd cluster by the pd addresses"))
+	}
+	return allErrs
+}
+
+func validatePDLeaderPriorities(priorities []v1alpha1.PDLeaderPriority, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, p := range priorities {
+		idxPath := fldPath.Index(i)
+		if (len(p.Ordinals) == 0) == (p.Zone == "") {
+			allErrs = append(allErrs, field.Invalid(idxPath, p, "exactly one of ordinals and zone should be specified"))
+		}
+		for j, ordinal := range p.Ordinals {
+			if ordinal < 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("ordinals").Index(j), ordinal, "ordinal should not be negative"))
+			}
+		}
+	}
 	return allErrs
 }
 
@@ -204,6 +819,48 @@ func validatePDAddresses(arrayOfAddresses []string, fldPath *field.Path) field.E
 	return allErrs
 }
 
+// validateExternalPD validates the external PD, which can't be used together with the PD managed by tidb-operator
+func validateExternalPD(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	endpointsPath := fldPath.Child("externalPD", "endpoints")
+	if len(spec.ExternalPD.Endpoints) == 0 {
+		allErrs = append(allErrs, field.Required(endpointsPath, "at least one endpoint of the external pd is required"))
+	}
+	var scheme string
+	for i, endpoint := range spec.ExternalPD.Endpoints {
+		idxPath := endpointsPath.Index(i)
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			allErrs = append(allErrs, field.Invalid(idxPath, endpoint, "endpoint must be in the format of http(s)://{ADDRESS}:{PORT}"))
+			continue
+		}
+		if scheme == "" {
+			scheme = u.Scheme
+		} else if u.Scheme != scheme {
+			allErrs = append(allErrs, field.Invalid(idxPath, endpoint, "all the endpoints must have the same scheme"))
+		}
+	}
+	if spec.PD != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("pd"), "pd can't be managed when the external pd is used"))
+	}
+	if len(spec.PDMS) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("pdms"), "pdms can't be managed when the external pd is used"))
+	}
+	if spec.Cluster != nil && spec.Cluster.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("cluster"), "the external pd can't be used by a heterogeneous cluster"))
+	}
+	if spec.AcrossK8s {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("acrossK8s"), "the external pd can't be used across kubernetes clusters"))
+	}
+	if len(spec.PDAddresses) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("pdAddresses"), "pd addresses can't be used together with the external pd"))
+	}
+	if spec.StartScriptVersion != v1alpha1.StartScriptV2 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("startScriptVersion"), spec.StartScriptVersion, "start script v2 is required by the external pd"))
+	}
+	return allErrs
+}
+
 func validateStartScriptFeatureFlags(featureFlags []v1alpha1.StartScriptV2FeatureFlag, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	for i, ff := range featureFlags {
@@ -221,9 +878,13 @@ func validateTiKVSpec(spec *v1alpha1.TiKVSpec, fldPath *field.Path) field.ErrorL
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
 	allErrs = append(allErrs, validateRequestsStorage(spec.ResourceRequirements.Requests, fldPath)...)
 	allErrs = append(allErrs, validateScalePolicy(&spec.ScalePolicy, fldPath.Child("scalePolicy"))...)
+	allErrs = append(allErrs, validateRollingUpdateStrategy(spec.RollingUpdateStrategy, fldPath.Child("rollingUpdateStrategy"))...)
 	if len(spec.DataSubDir) > 0 {
 		allErrs = append(allErrs, validateLocalDescendingPath(spec.DataSubDir, fldPath.Child("dataSubDir"))...)
 	}
+	if spec.StorageProfile != "" && !spec.StorageProfile.IsValid() {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("storageProfile"), spec.StorageProfile, v1alpha1.TiKVStorageProfiles()))
+	}
 	if len(spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
@@ -233,7 +894,113 @@ func validateTiKVSpec(spec *v1alpha1.TiKVSpec, fldPath *field.Path) field.ErrorL
 	if spec.ShouldSeparateRocksDBLog() && spec.RocksDBLogVolumeName != "" {
 		allErrs = append(allErrs, validateVolumeName(spec.RocksDBLogVolumeName, spec.StorageVolumes, spec.AdditionalVolumes, spec.AdditionalVolumeMounts, fldPath)...)
 	}
+	if spec.LogVolume != nil && spec.LogVolume.VolumeName != "" {
+		allErrs = append(allErrs, validateVolumeName(spec.LogVolume.VolumeName, spec.StorageVolumes, spec.AdditionalVolumes, spec.AdditionalVolumeMounts, fldPath.Child("logVolume"))...)
+	}
 	allErrs = append(allErrs, validateTimeDurationStr(spec.EvictLeaderTimeout, fldPath.Child("evictLeaderTimeout"))...)
+	if spec.Witness != nil {
+		allErrs = append(allErrs, validateTiKVWitnessSpec(spec.Witness, fldPath.Child("witness"))...)
+	}
+	allErrs = append(allErrs, validateTiKVStoreWeights(spec.StoreWeights, fldPath.Child("storeWeights"))...)
+	if spec.SlowStoreDetection != nil {
+		allErrs = append(allErrs, validateTiKVSlowStoreDetection(spec.SlowStoreDetection, fldPath.Child("slowStoreDetection"))...)
+	}
+	if spec.Encryption != nil {
+		if spec.Config == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("config"), "the config of tikv is required by the encryption"))
+		}
+		allErrs = append(allErrs, validateTiKVMasterKeySource(&spec.Encryption.MasterKey, fldPath.Child("encryption", "masterKey"))...)
+	}
+	return allErrs
+}
+
+func validateTiKVMasterKeySource(key *v1alpha1.TiKVMasterKeySource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	sources := 0
+	if key.AWSKMS != nil {
+		sources++
+		if key.AWSKMS.KeyID == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("awsKMS", "keyID"), "the key id must be specified"))
+		}
+		if key.AWSKMS.Region == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("awsKMS", "region"), "the region must be specified"))
+		}
+	}
+	if key.GCPKMS != nil {
+		sources++
+		if key.GCPKMS.KeyID == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("gcpKMS", "keyID"), "the key id must be specified"))
+		}
+	}
+	if key.Vault != nil {
+		sources++
+		if key.Vault.Role == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("vault", "role"), "the vault role must be specified"))
+		}
+		if key.Vault.Path == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("vault", "path"), "the path of the secret must be specified"))
+		}
+	}
+	if key.Secret != nil {
+		sources++
+		if key.Secret.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("secret", "name"), "the name of the secret must be specified"))
+		}
+	}
+	if sources != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, sources, "exactly one of awsKMS, gcpKMS, vault and secret must be specified"))
+	}
+	return allErrs
+}
+
+func validateTiKVStoreWeights(weights []v1alpha1.TiKVStoreWeight, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, w := range weights {
+		idxPath := fldPath.Index(i)
+		if len(w.PodNames) == 0 && len(w.NodeSelector) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath, "podNames or nodeSelector must be set"))
+		}
+		if w.LeaderWeight != nil && *w.LeaderWeight < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("leaderWeight"), *w.LeaderWeight, "must be greater than or equal to 0"))
+		}
+		if w.RegionWeight != nil && *w.RegionWeight < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("regionWeight"), *w.RegionWeight, "must be greater than or equal to 0"))
+		}
+	}
+	return allErrs
+}
+
+func validateTiKVSlowStoreDetection(d *v1alpha1.TiKVSlowStoreDetection, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if d.SlowScoreThreshold != nil && (*d.SlowScoreThreshold < 1 || *d.SlowScoreThreshold > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("slowScoreThreshold"), *d.SlowScoreThreshold, "must be between 1 and 100"))
+	}
+	if d.Duration != nil && d.Duration.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("duration"), d.Duration.Duration.String(), "must not be negative"))
+	}
+	switch d.Mitigation {
+	case "", v1alpha1.TiKVSlowStoreMitigationNone, v1alpha1.TiKVSlowStoreMitigationLeaderWeight, v1alpha1.TiKVSlowStoreMitigationEvictLeader:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mitigation"), d.Mitigation, []string{
+			string(v1alpha1.TiKVSlowStoreMitigationNone),
+			string(v1alpha1.TiKVSlowStoreMitigationLeaderWeight),
+			string(v1alpha1.TiKVSlowStoreMitigationEvictLeader),
+		}))
+	}
+	if d.MaxMitigatedStores != nil && *d.MaxMitigatedStores < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxMitigatedStores"), *d.MaxMitigatedStores, "must be greater than or equal to 1"))
+	}
+	return allErrs
+}
+
+func validateTiKVWitnessSpec(spec *v1alpha1.TiKVWitnessSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), spec.Replicas, "replicas must not be negative"))
+	}
+	if storage, ok := spec.Requests[corev1.ResourceStorage]; ok && storage.IsZero() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requests.storage"), storage.String(), "storage request must not be zero"))
+	}
 	return allErrs
 }
 
@@ -246,6 +1013,9 @@ func validateTiFlashSpec(spec *v1alpha1.TiFlashSpec, fldPath *field.Path) field.
 			spec.StorageClaims, "storageClaims should be configured at least one item."))
 	}
 	allErrs = append(allErrs, validateScalePolicy(&spec.ScalePolicy, fldPath.Child("scalePolicy"))...)
+	if spec.TableReplicas != nil {
+		allErrs = append(allErrs, validateTiFlashTableReplicas(spec.TableReplicas.Tables, fldPath.Child("tableReplicas", "tables"))...)
+	}
 
 	// fix storageClaim
 	for _, storageClaim := range spec.StorageClaims {
@@ -257,6 +1027,37 @@ func validateTiFlashSpec(spec *v1alpha1.TiFlashSpec, fldPath *field.Path) field.
 	return allErrs
 }
 
+// validateTiFlashTableReplicas validates that each database or table is declared only once, and
+// the tables of a database declared as a whole are not declared again
+func validateTiFlashTableReplicas(tables []v1alpha1.TiFlashTableReplica, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	databases := map[string]bool{}
+	for _, t := range tables {
+		if t.Table == "" {
+			databases[t.Database] = true
+		}
+	}
+	declared := map[v1alpha1.TiFlashTableReplica]bool{}
+	for i, t := range tables {
+		idxPath := fldPath.Index(i)
+		if t.Database == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("database"), "database should be specified"))
+			continue
+		}
+		key := v1alpha1.TiFlashTableReplica{Database: t.Database, Table: t.Table}
+		if declared[key] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, key))
+			continue
+		}
+		declared[key] = true
+		if t.Table != "" && databases[t.Database] {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("table"), t.Table,
+				fmt.Sprintf("the tables of database %s are declared as a whole", t.Database)))
+		}
+	}
+	return allErrs
+}
+
 func validateTiCDCSpec(spec *v1alpha1.TiCDCSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
@@ -321,15 +1122,149 @@ func validateTiDBSpec(spec *v1alpha1.TiDBSpec, fldPath *field.Path) field.ErrorL
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
 	allErrs = append(allErrs, validateScalePolicy(&spec.ScalePolicy, fldPath.Child("scalePolicy"))...)
+	allErrs = append(allErrs, validateRollingUpdateStrategy(spec.RollingUpdateStrategy, fldPath.Child("rollingUpdateStrategy"))...)
 	if spec.Service != nil {
 		allErrs = append(allErrs, validateService(&spec.Service.ServiceSpec, fldPath)...)
 	}
+	allErrs = append(allErrs, validateTiDBServices(spec.Services, fldPath.Child("services"))...)
 	if len(spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
 	if spec.ShouldSeparateSlowLog() && spec.SlowLogVolumeName != "" {
 		allErrs = append(allErrs, validateVolumeName(spec.SlowLogVolumeName, spec.StorageVolumes, spec.AdditionalVolumes, spec.AdditionalVolumeMounts, fldPath)...)
 	}
+	if spec.LogVolume != nil && spec.LogVolume.VolumeName != "" {
+		allErrs = append(allErrs, validateVolumeName(spec.LogVolume.VolumeName, spec.StorageVolumes, spec.AdditionalVolumes, spec.AdditionalVolumeMounts, fldPath.Child("logVolume"))...)
+	}
+	if spec.LogShipping != nil {
+		allErrs = append(allErrs, validateTiDBLogShipping(spec.LogShipping, fldPath.Child("logShipping"))...)
+	}
+	if spec.Canary != nil {
+		allErrs = append(allErrs, validateTiDBCanary(spec.Canary, spec.Replicas, fldPath.Child("canary"))...)
+	}
+	if spec.LoadBalancerReadiness != nil {
+		allErrs = append(allErrs, validateTiDBLoadBalancerReadiness(spec.LoadBalancerReadiness, fldPath.Child("loadBalancerReadiness"))...)
+	}
+	return allErrs
+}
+
+func validateTiDBLoadBalancerReadiness(readiness *v1alpha1.TiDBLoadBalancerReadiness, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if readiness.ConditionType != "" {
+		for _, msg := range validation.IsQualifiedName(readiness.ConditionType) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("conditionType"), readiness.ConditionType, msg))
+		}
+	}
+	if (readiness.AWS == nil) == (readiness.GCP == nil) {
+		allErrs = append(allErrs, field.Invalid(fldPath, readiness, "exactly one of aws and gcp must be set"))
+	}
+	if aws := readiness.AWS; aws != nil {
+		if !strings.HasPrefix(aws.TargetGroupARN, "arn:") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("aws", "targetGroupARN"), aws.TargetGroupARN, "must be the ARN of a target group"))
+		}
+	}
+	if gcp := readiness.GCP; gcp != nil {
+		gcpPath := fldPath.Child("gcp")
+		if gcp.Project == "" {
+			allErrs = append(allErrs, field.Required(gcpPath.Child("project"), "project must not be empty"))
+		}
+		if gcp.Name == "" {
+			allErrs = append(allErrs, field.Required(gcpPath.Child("name"), "name must not be empty"))
+		}
+		if len(gcp.Zones) == 0 {
+			allErrs = append(allErrs, field.Required(gcpPath.Child("zones"), "at least one zone must be set"))
+		}
+	}
+	return allErrs
+}
+
+func validateTiDBCanary(canary *v1alpha1.TiDBCanaryStrategy, replicas int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if canary.Replicas < 1 || canary.Replicas >= replicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), canary.Replicas, "must be at least 1 and less than the replicas of tidb"))
+	}
+	if canary.SoakDuration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("soakDuration"), canary.SoakDuration.Duration.String(), "must be greater than 0"))
+	}
+	if canary.CheckInterval != nil && canary.CheckInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("checkInterval"), canary.CheckInterval.Duration.String(), "must be greater than 0"))
+	}
+	if canary.MaxErrorPercent != nil && (*canary.MaxErrorPercent < 0 || *canary.MaxErrorPercent > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxErrorPercent"), *canary.MaxErrorPercent, "must be between 0 and 100"))
+	}
+	if canary.PrometheusURL == "" && (canary.LatencySLO != nil || canary.MaxErrorPercent != nil) {
+		allErrs = append(allErrs, field.Required(fldPath.Child("prometheusURL"), "prometheusURL is required to check the SLOs of the canary"))
+	}
+	return allErrs
+}
+
+func validateTiDBServices(services []v1alpha1.TiDBService, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]struct{}{}
+	for i, svc := range services {
+		idxPath := fldPath.Index(i)
+		switch {
+		case svc.Name == "":
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name must not be empty"))
+		case svc.Name == "peer":
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), svc.Name, "name is reserved by the headless service of tidb"))
+		default:
+			for _, msg := range validation.IsDNS1123Label(svc.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), svc.Name, msg))
+			}
+		}
+		if _, ok := names[svc.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), svc.Name))
+		}
+		names[svc.Name] = struct{}{}
+		if len(svc.Spec.LoadBalancerSourceRanges) > 0 {
+			if _, err := utilnet.ParseIPNets(svc.Spec.LoadBalancerSourceRanges...); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("spec", "loadBalancerSourceRanges"), svc.Spec.LoadBalancerSourceRanges, "expecting a list of IP ranges, e.g. 10.0.0.0/24"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateTiDBLogShipping(spec *v1alpha1.TiDBLogShippingSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.AuditLogFile != "" && !filepath.IsAbs(spec.AuditLogFile) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("auditLogFile"), spec.AuditLogFile, "auditLogFile must be an absolute path"))
+	}
+	if len(spec.Outputs) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("outputs"), "at least one output must be set"))
+	}
+	for i, output := range spec.Outputs {
+		idxPath := fldPath.Child("outputs").Index(i)
+		count := 0
+		if output.Loki != nil {
+			count++
+			if output.Loki.Host == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("loki", "host"), "host must not be empty"))
+			}
+		}
+		if output.S3 != nil {
+			count++
+			if output.S3.Bucket == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("s3", "bucket"), "bucket must not be empty"))
+			}
+			if output.S3.Region == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("s3", "region"), "region must not be empty"))
+			}
+		}
+		if output.Kafka != nil {
+			count++
+			if len(output.Kafka.Brokers) == 0 {
+				allErrs = append(allErrs, field.Required(idxPath.Child("kafka", "brokers"), "brokers must not be empty"))
+			}
+			if output.Kafka.Topic == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("kafka", "topic"), "topic must not be empty"))
+			}
+		}
+		if count != 1 {
+			allErrs = append(allErrs, field.Invalid(idxPath, output, "exactly one of loki, s3 and kafka must be set"))
+		}
+	}
 	return allErrs
 }
 
@@ -346,6 +1281,7 @@ func validatePumpSpec(spec *v1alpha1.PumpSpec, fldPath *field.Path) field.ErrorL
 
 func validateDMClusterSpec(spec *v1alpha1.DMClusterSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateSchedulingGates(spec.SchedulingGates, fldPath.Child("schedulingGates"))...)
 	if spec.Version != "" {
 		clusterVersionLT2, _ := clusterVersionLessThan2(spec.Version)
 		if clusterVersionLT2 {
@@ -354,8 +1290,51 @@ func validateDMClusterSpec(spec *v1alpha1.DMClusterSpec, fldPath *field.Path) fi
 	}
 	allErrs = append(allErrs, validateDMDiscoverySpec(spec.Discovery, fldPath.Child("discovery"))...)
 	allErrs = append(allErrs, validateMasterSpec(&spec.Master, fldPath.Child("master"))...)
+	allErrs = append(allErrs, validatePreemptionPolicy(spec.PreemptionPolicy, spec.PriorityClassName, fldPath)...)
+	allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.Master.ComponentSpec, spec.PriorityClassName, fldPath.Child("master"))...)
+	allErrs = append(allErrs, validateNoRestartAt(spec.Annotations, fldPath.Child("annotations"))...)
+	allErrs = append(allErrs, validateNoRestartAt(spec.Master.Annotations, fldPath.Child("master", "annotations"))...)
 	if spec.Worker != nil {
 		allErrs = append(allErrs, validateWorkerSpec(spec.Worker, fldPath.Child("worker"))...)
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.Worker.ComponentSpec, spec.PriorityClassName, fldPath.Child("worker"))...)
+		allErrs = append(allErrs, validateNoRestartAt(spec.Worker.Annotations, fldPath.Child("worker", "annotations"))...)
+	}
+	if spec.SuspendAction != nil {
+		allErrs = append(allErrs, validateSuspendAction(spec.SuspendAction, []v1alpha1.MemberType{
+			v1alpha1.DMWorkerMemberType,
+			v1alpha1.DMMasterMemberType,
+		}, fldPath.Child("suspendAction"))...)
+	}
+	allErrs = append(allErrs, validateDMDatabaseTLS(spec.DatabaseTLS, fldPath.Child("databaseTLS"))...)
+	allErrs = append(allErrs, validateDeletionPolicy(spec.DeletionPolicy, fldPath.Child("deletionPolicy"))...)
+	return allErrs
+}
+
+func validateDMDatabaseTLS(databaseTLS []v1alpha1.DMDatabaseTLS, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]struct{}{}
+	for i, tls := range databaseTLS {
+		idxPath := fldPath.Index(i)
+		if tls.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name must not be empty"))
+		} else {
+			for _, msg := range validation.IsConfigMapKey(tls.Name + ".yaml") {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), tls.Name, msg))
+			}
+		}
+		if _, ok := names[tls.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), tls.Name))
+		}
+		names[tls.Name] = struct{}{}
+		switch tls.Type {
+		case v1alpha1.DMDatabaseTypeSource, v1alpha1.DMDatabaseTypeTarget:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("type"), tls.Type,
+				[]string{string(v1alpha1.DMDatabaseTypeSource), string(v1alpha1.DMDatabaseTypeTarget)}))
+		}
+		if tls.SecretName == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("secretName"), "secretName must not be empty"))
+		}
 	}
 	return allErrs
 }
@@ -381,6 +1360,28 @@ func validateMasterSpec(spec *v1alpha1.MasterSpec, fldPath *field.Path) field.Er
 func validateWorkerSpec(spec *v1alpha1.WorkerSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	if spec.RelayStorage != nil && spec.RelayStorage.StorageSize != "" {
+		if _, err := resource.ParseQuantity(spec.RelayStorage.StorageSize); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("relayStorage", "storageSize"), spec.RelayStorage.StorageSize, err.Error()))
+		}
+	}
+	if spec.RelayPurge != nil {
+		allErrs = append(allErrs, validateDMRelayPurgePolicy(spec.RelayPurge, fldPath.Child("relayPurge"))...)
+	}
+	return allErrs
+}
+
+func validateDMRelayPurgePolicy(policy *v1alpha1.DMRelayPurgePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy.Interval != nil && *policy.Interval < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), *policy.Interval, "must be greater than 0"))
+	}
+	if policy.Expires != nil && *policy.Expires < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("expires"), *policy.Expires, "must not be negative"))
+	}
+	if policy.RemainSpace != nil && *policy.RemainSpace < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("remainSpace"), *policy.RemainSpace, "must not be negative"))
+	}
 	return allErrs
 }
 
@@ -391,7 +1392,9 @@ func validateTidbNGMonitoringSpec(spec *v1alpha1.TidbNGMonitoringSpec, fldPath *
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("clusters"), len(spec.Clusters), "must have at least one item"))
 	}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validatePreemptionPolicy(spec.PreemptionPolicy, spec.PriorityClassName, fldPath)...)
 	allErrs = append(allErrs, validateNGMonitoringSpec(&spec.NGMonitoring, fldPath.Child("ngMonitoring"))...)
+	allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.NGMonitoring.ComponentSpec, spec.PriorityClassName, fldPath.Child("ngMonitoring"))...)
 
 	return allErrs
 }
@@ -403,6 +1406,20 @@ func validateNGMonitoringSpec(spec *v1alpha1.NGMonitoringSpec, fldPath *field.Pa
 	if len(spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
+	if spec.Retention != nil {
+		if spec.Retention.TopSQL != "" {
+			allErrs = append(allErrs, validatePromDurationStr(&spec.Retention.TopSQL, fldPath.Child("retention", "topSQL"))...)
+		}
+		if spec.Retention.ConProf != "" {
+			allErrs = append(allErrs, validateTimeDurationStr(&spec.Retention.ConProf, fldPath.Child("retention", "conprof"))...)
+			if spec.Cleanup == nil {
+				allErrs = append(allErrs, field.Required(fldPath.Child("cleanup"), "cleanup is required to apply the retention of the continuous profiling data"))
+			}
+		}
+	}
+	if spec.Cleanup != nil && spec.Cleanup.Interval != "" {
+		allErrs = append(allErrs, validateTimeDurationStr(&spec.Cleanup.Interval, fldPath.Child("cleanup", "interval"))...)
+	}
 
 	return allErrs
 }
@@ -411,7 +1428,134 @@ func validateComponentSpec(spec *v1alpha1.ComponentSpec, fldPath *field.Path) fi
 	allErrs := field.ErrorList{}
 	// TODO validate other fields
 	allErrs = append(allErrs, validateEnv(spec.Env, fldPath.Child("env"))...)
+	allErrs = append(allErrs, validateAdditionalArgs(spec.AdditionalArgs, fldPath.Child("additionalArgs"))...)
 	allErrs = append(allErrs, validateAdditionalContainers(spec.AdditionalContainers, fldPath.Child("additionalContainers"))...)
+	allErrs = append(allErrs, validateSchedulingGates(spec.SchedulingGates, fldPath.Child("schedulingGates"))...)
+	allErrs = append(allErrs, validateRestartAt(spec.Annotations, fldPath.Child("annotations"))...)
+	return allErrs
+}
+
+// validateRestartAt validates the time of the scheduled rolling restart is in RFC3339 format
+func validateRestartAt(anns map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	value, ok := anns[label.AnnRestartAtKey]
+	if !ok {
+		return allErrs
+	}
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Key(label.AnnRestartAtKey), value, "must be a time in RFC3339 format"))
+	}
+	return allErrs
+}
+
+// validateNoRestartAt rejects the scheduled rolling restart of the clusters not supporting it, e.g. DMCluster
+func validateNoRestartAt(anns map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if _, ok := anns[label.AnnRestartAtKey]; ok {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Key(label.AnnRestartAtKey), "the scheduled rolling restart is not supported by DMCluster"))
+	}
+	return allErrs
+}
+
+// validateSchedulingGates validates the names of the scheduling gates are qualified names and unique
+func validateSchedulingGates(gates []corev1.PodSchedulingGate, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := map[string]bool{}
+	for i, gate := range gates {
+		namePath := fldPath.Index(i).Child("name")
+		for _, msg := range validation.IsQualifiedName(gate.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, gate.Name, msg))
+		}
+		if seen[gate.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, gate.Name))
+		}
+		seen[gate.Name] = true
+	}
+	return allErrs
+}
+
+// validatePreemptionPolicy validates the preemption policy is set along with the priority class the pods run with,
+// as the admission fills in the policy of the priority class and rejects the pods setting a different one
+func validatePreemptionPolicy(policy *corev1.PreemptionPolicy, priorityClassName *string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy == nil {
+		return allErrs
+	}
+	switch *policy {
+	case corev1.PreemptLowerPriority, corev1.PreemptNever:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("preemptionPolicy"), *policy, []string{string(corev1.PreemptLowerPriority), string(corev1.PreemptNever)}))
+	}
+	if priorityClassName == nil || *priorityClassName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("priorityClassName"), "priorityClassName is required when preemptionPolicy is set, and the preemptionPolicy must match the one of the priority class"))
+	}
+	return allErrs
+}
+
+// validateComponentPreemptionPolicy validates the preemption policy of a component, which runs with the
+// priority class of the cluster if it doesn't set its own
+func validateComponentPreemptionPolicy(spec *v1alpha1.ComponentSpec, clusterPriorityClassName *string, fldPath *field.Path) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+	priorityClassName := spec.PriorityClassName
+	if priorityClassName == nil {
+		priorityClassName = clusterPriorityClassName
+	}
+	return validatePreemptionPolicy(spec.PreemptionPolicy, priorityClassName, fldPath)
+}
+
+// validateTiDBClusterPreemptionPolicy validates the preemption policies of the cluster and its components
+func validateTiDBClusterPreemptionPolicy(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validatePreemptionPolicy(spec.PreemptionPolicy, spec.PriorityClassName, fldPath)...)
+	allErrs = append(allErrs, validateComponentPreemptionPolicy(spec.Discovery.ComponentSpec, spec.PriorityClassName, fldPath.Child("discovery"))...)
+	if spec.PD != nil {
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.PD.ComponentSpec, spec.PriorityClassName, fldPath.Child("pd"))...)
+	}
+	for i, comp := range spec.PDMS {
+		if comp != nil {
+			allErrs = append(allErrs, validateComponentPreemptionPolicy(&comp.ComponentSpec, spec.PriorityClassName, fldPath.Child("pdms").Index(i))...)
+		}
+	}
+	if spec.TiKV != nil {
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.TiKV.ComponentSpec, spec.PriorityClassName, fldPath.Child("tikv"))...)
+	}
+	if spec.TiDB != nil {
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.TiDB.ComponentSpec, spec.PriorityClassName, fldPath.Child("tidb"))...)
+	}
+	if spec.TiFlash != nil {
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.TiFlash.ComponentSpec, spec.PriorityClassName, fldPath.Child("tiflash"))...)
+	}
+	if spec.TiCDC != nil {
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.TiCDC.ComponentSpec, spec.PriorityClassName, fldPath.Child("ticdc"))...)
+	}
+	if spec.TiProxy != nil {
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.TiProxy.ComponentSpec, spec.PriorityClassName, fldPath.Child("tiproxy"))...)
+	}
+	if spec.Pump != nil {
+		allErrs = append(allErrs, validateComponentPreemptionPolicy(&spec.Pump.ComponentSpec, spec.PriorityClassName, fldPath.Child("pump"))...)
+	}
+	return allErrs
+}
+
+// validateAdditionalArgs validates the additional arguments, which are appended to the
+// command line in the start script, so the shell special characters are not allowed
+// except the `$(VAR_NAME)` references.
+func validateAdditionalArgs(args []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, arg := range args {
+		idxPath := fldPath.Index(i)
+		if len(arg) == 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath, arg, "argument must not be empty"))
+			continue
+		}
+		rest := envReferenceRegexp.ReplaceAllString(arg, "")
+		if strings.ContainsAny(rest, shellSpecialChars) {
+			allErrs = append(allErrs, field.Invalid(idxPath, arg,
+				"argument must not contain whitespaces, quotes or shell special characters except the $(VAR_NAME) references"))
+		}
+	}
 	return allErrs
 }
 
@@ -491,13 +1635,18 @@ func validateEnvVarValueFrom(ev corev1.EnvVar, fldPath *field.Path) field.ErrorL
 
 	numSources := 0
 
+	// the downward API references can be used in the additional arguments
 	if ev.ValueFrom.FieldRef != nil {
 		numSources++
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("fieldRef"), "", "fieldRef is not supported"))
+		if len(ev.ValueFrom.FieldRef.FieldPath) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("fieldRef", "fieldPath"), ""))
+		}
 	}
 	if ev.ValueFrom.ResourceFieldRef != nil {
 		numSources++
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("resourceFieldRef"), "", "resourceFieldRef is not supported"))
+		if len(ev.ValueFrom.ResourceFieldRef.Resource) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("resourceFieldRef", "resource"), ""))
+		}
 	}
 	if ev.ValueFrom.ConfigMapKeyRef != nil {
 		numSources++
@@ -509,7 +1658,7 @@ func validateEnvVarValueFrom(ev corev1.EnvVar, fldPath *field.Path) field.ErrorL
 	}
 
 	if numSources == 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath, "", "must specify one of: `configMapKeyRef` or `secretKeyRef`"))
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "must specify one of: `fieldRef`, `resourceFieldRef`, `configMapKeyRef` or `secretKeyRef`"))
 	} else if len(ev.Value) != 0 {
 		if numSources != 0 {
 			allErrs = append(allErrs, field.Invalid(fldPath, "", "may not be specified when `value` is not empty"))
@@ -561,6 +1710,7 @@ func ValidateCreateTidbCluster(tc *v1alpha1.TidbCluster) field.ErrorList {
 	// basic validation
 	allErrs = append(allErrs, ValidateTidbCluster(tc)...)
 	allErrs = append(allErrs, validateNewTidbClusterSpec(&tc.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateTidbClusterSemantics(tc, field.NewPath("spec"))...)
 	return allErrs
 }
 
@@ -577,6 +1727,7 @@ func ValidateUpdateTidbCluster(old, tc *v1alpha1.TidbCluster) field.ErrorList {
 	allErrs = append(allErrs, validateUpdatePDConfig(old.Spec.PD, tc.Spec.PD, field.NewPath("spec.pd.config"))...)
 	allErrs = append(allErrs, disallowMutateBootstrapSQLConfigMapName(old.Spec.TiDB, tc.Spec.TiDB, field.NewPath("spec.tidb.bootstrapSQLConfigMapName"))...)
 	allErrs = append(allErrs, disallowUsingLegacyAPIInNewCluster(old, tc)...)
+	allErrs = append(allErrs, validateTidbClusterSemantics(tc, field.NewPath("spec"))...)
 
 	return allErrs
 }
@@ -613,6 +1764,43 @@ func validateNewTidbClusterSpec(spec *v1alpha1.TidbClusterSpec, path *field.Path
 	return allErrs
 }
 
+// validateTidbClusterSemantics validates the relations between the fields of a TidbCluster, which
+// can't be reconciled successfully if they are violated.
+func validateTidbClusterSemantics(tc *v1alpha1.TidbCluster, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	spec := &tc.Spec
+
+	// the stores of TiKV may be provided by the referenced cluster for a heterogeneous cluster
+	if spec.TiFlash != nil && spec.TiFlash.Replicas > 0 && spec.Cluster == nil &&
+		(spec.TiKV == nil || spec.TiKV.Replicas < 1) {
+		allErrs = append(allErrs, field.Invalid(path.Child("tiflash.replicas"), spec.TiFlash.Replicas,
+			"TiFlash requires at least one TiKV, set spec.tikv.replicas to 1 or more"))
+	}
+
+	if spec.PD != nil && spec.PD.LearnerReplicas > 0 && !tc.PDSupports(v1alpha1.PDFeatureLearner) {
+		allErrs = append(allErrs, field.Invalid(path.Child("pd.learnerReplicas"), spec.PD.LearnerReplicas,
+			fmt.Sprintf("PD learners require PD version %s or later, but the version is %s", v1alpha1.PDFeatureMinVersion(v1alpha1.PDFeatureLearner), tc.PDVersion())))
+	}
+
+	if spec.PD != nil && spec.PD.Mode == "ms" && !tc.PDSupports(v1alpha1.PDFeatureMicroservices) {
+		allErrs = append(allErrs, field.Invalid(path.Child("pd.mode"), spec.PD.Mode,
+			fmt.Sprintf("PD microservices mode requires PD version %s or later, but the version is %s", v1alpha1.PDFeatureMinVersion(v1alpha1.PDFeatureMicroservices), tc.PDVersion())))
+	}
+
+	if spec.ClusterDomain != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.ClusterDomain) {
+			allErrs = append(allErrs, field.Invalid(path.Child("clusterDomain"), spec.ClusterDomain, msg))
+		}
+	}
+
+	// the pods crash on binding the ports if the host ports conflict on a node
+	for _, conflict := range v1alpha1.HostNetworkPortConflicts(tc.HostNetworkPorts()) {
+		allErrs = append(allErrs, field.Invalid(path.Child("hostNetwork"), conflict.Port,
+			fmt.Sprintf("%s in the host network, change the ports of the additional containers or disable hostNetwork of the components", conflict)))
+	}
+	return allErrs
+}
+
 // disallowUsingLegacyAPIInNewCluster checks if user use the legacy API in newly create cluster during update
 // TODO(aylei): this could be removed after we enable validateTidbCluster() in update, which is more strict
 func disallowUsingLegacyAPIInNewCluster(old, tc *v1alpha1.TidbCluster) field.ErrorList {
@@ -782,6 +1970,62 @@ func validatePromDurationStr(timeStr *string, fldPath *field.Path) field.ErrorLi
 	return allErrs
 }
 
+func validateThanosSpec(monitor *v1alpha1.TidbMonitor, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	thanos := monitor.Spec.Thanos
+	if (thanos.Query != nil || thanos.Store != nil || thanos.Ruler != nil) && len(thanos.BaseImage) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("baseImage"), "baseImage is required to deploy the thanos components"))
+	}
+	if thanos.Query != nil {
+		allErrs = append(allErrs, validateService(&thanos.Query.Service, fldPath.Child("query"))...)
+		if thanos.ListenLocal {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("listenLocal"), thanos.ListenLocal, "the thanos sidecar must not listen on loopback to be queried by the thanos query"))
+		}
+	}
+	if thanos.Store != nil {
+		allErrs = append(allErrs, validateService(&thanos.Store.Service, fldPath.Child("store"))...)
+		// the config file is only mounted in the sidecar, so the config must be referenced from a secret
+		if thanos.ObjectStorageConfig == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("objectStorageConfig"), "objectStorageConfig is required to deploy the thanos store gateway"))
+		}
+	}
+	if ruler := thanos.Ruler; ruler != nil {
+		rulerPath := fldPath.Child("ruler")
+		allErrs = append(allErrs, validateService(&ruler.Service, rulerPath)...)
+		if thanos.Query == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("query"), "thanos query is required to deploy the thanos ruler"))
+		}
+		if len(ruler.EvaluationInterval) > 0 {
+			allErrs = append(allErrs, validatePromDurationStr(&ruler.EvaluationInterval, rulerPath.Child("evaluationInterval"))...)
+		}
+		for i, ref := range ruler.RuleConfigMaps {
+			if len(ref.Name) == 0 {
+				allErrs = append(allErrs, field.Required(rulerPath.Child("ruleConfigMaps").Index(i).Child("name"), "the name of the configmap is required"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateGrafanaExport checks the kubectl image, the labels and annotations of the exported ConfigMaps
+// and the URL of the exported datasources
+func validateGrafanaExport(export *v1alpha1.GrafanaExportSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(export.BaseImage) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("baseImage"), "baseImage of kubectl is required to export the dashboards"))
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabels(export.DashboardLabels, fldPath.Child("dashboardLabels"))...)
+	allErrs = append(allErrs, metav1validation.ValidateLabels(export.DatasourceLabels, fldPath.Child("datasourceLabels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(export.Annotations, fldPath.Child("annotations"))...)
+	if export.PrometheusURL != nil {
+		u, err := url.Parse(*export.PrometheusURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("prometheusURL"), *export.PrometheusURL, "must be an absolute URL"))
+		}
+	}
+	return allErrs
+}
+
 // clusterVersionLessThan2 makes sure that deployed dm cluster version not to be v1.0.x
 func clusterVersionLessThan2(version string) (bool, error) {
 	v, err := semver.NewVersion(version)
@@ -822,6 +2066,29 @@ func validateStorageInfo(storage string, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateRollingUpdateStrategy(strategy *v1alpha1.RollingUpdateStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if strategy == nil {
+		return allErrs
+	}
+	if mu := strategy.MaxUnavailable; mu != nil {
+		if mu.Type == intstr.String {
+			v, err := intstr.GetScaledValueFromIntOrPercent(mu, 100, false)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), mu.String(), err.Error()))
+			} else if v <= 0 || v > 100 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), mu.String(), "maxUnavailable should be a percentage in (0%, 100%]"))
+			}
+		} else if mu.IntValue() <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), mu.IntValue(), "maxUnavailable should be positive"))
+		}
+	}
+	if strategy.Partition != nil && *strategy.Partition < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("partition"), *strategy.Partition, "partition should not be negative"))
+	}
+	return allErrs
+}
+
 func validateScalePolicy(scalePolicy *v1alpha1.ScalePolicy, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if scalePolicy.ScaleInParallelism != nil && *scalePolicy.ScaleInParallelism <= 0 {
@@ -16,6 +16,7 @@ package validation
 import (
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
@@ -492,6 +493,82 @@ func TestValidateTidbMonitor(t *testing.T) {
 	}
 }
 
+func TestValidateTidbMonitorAlertmanager(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitor()
+	monitor.Spec.Alertmanager = &v1alpha1.AlertmanagerSpec{}
+	monitor.Spec.ExtraAlertRules = []corev1.LocalObjectReference{{Name: "rules"}, {}}
+	errs := ValidateTidbMonitor(monitor)
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Field).To(Equal("spec.alertmanager.baseImage"))
+	g.Expect(errs[1].Field).To(Equal("spec.extraAlertRules[1].name"))
+
+	// the image is not required for an external alertmanager
+	monitor.Spec.Alertmanager.URL = pointer.StringPtr("alertmanager.monitoring:9093")
+	monitor.Spec.ExtraAlertRules = monitor.Spec.ExtraAlertRules[:1]
+	g.Expect(ValidateTidbMonitor(monitor)).To(BeEmpty())
+
+	monitor.Spec.Alertmanager.URL = pointer.StringPtr("")
+	errs = ValidateTidbMonitor(monitor)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.alertmanager.url"))
+}
+
+func TestValidateTidbMonitorThanos(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitor()
+	monitor.Spec.Thanos = &v1alpha1.ThanosSpec{}
+	g.Expect(ValidateTidbMonitor(monitor)).To(BeEmpty())
+
+	monitor.Spec.Thanos.Store = &v1alpha1.ThanosComponentSpec{}
+	monitor.Spec.Thanos.Ruler = &v1alpha1.ThanosRulerSpec{
+		EvaluationInterval: "1x",
+		RuleConfigMaps:     []corev1.LocalObjectReference{{Name: "rules"}, {}},
+	}
+	errs := ValidateTidbMonitor(monitor)
+	g.Expect(errs).To(HaveLen(5))
+	g.Expect(errs[0].Field).To(Equal("spec.thanos.baseImage"))
+	g.Expect(errs[1].Field).To(Equal("spec.thanos.objectStorageConfig"))
+	g.Expect(errs[2].Field).To(Equal("spec.thanos.query"))
+	g.Expect(errs[3].Field).To(Equal("spec.thanos.ruler.evaluationInterval"))
+	g.Expect(errs[4].Field).To(Equal("spec.thanos.ruler.ruleConfigMaps[1].name"))
+
+	monitor.Spec.Thanos.BaseImage = "thanosio/thanos"
+	monitor.Spec.Thanos.ObjectStorageConfig = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+		Key:                  "objstore.yml",
+	}
+	monitor.Spec.Thanos.Query = &v1alpha1.ThanosQuerySpec{}
+	monitor.Spec.Thanos.Ruler.EvaluationInterval = "30s"
+	monitor.Spec.Thanos.Ruler.RuleConfigMaps = monitor.Spec.Thanos.Ruler.RuleConfigMaps[:1]
+	g.Expect(ValidateTidbMonitor(monitor)).To(BeEmpty())
+}
+
+func TestValidateTidbMonitorGrafanaExport(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitor()
+	monitor.Spec.GrafanaExport = &v1alpha1.GrafanaExportSpec{
+		DashboardLabels: map[string]string{"grafana_dashboard": "@"},
+		PrometheusURL:   pointer.StringPtr("basic-prometheus:9090"),
+	}
+	errs := ValidateTidbMonitor(monitor)
+	g.Expect(errs).To(HaveLen(3))
+	g.Expect(errs[0].Field).To(Equal("spec.grafanaExport.baseImage"))
+	g.Expect(errs[1].Field).To(Equal("spec.grafanaExport.dashboardLabels"))
+	g.Expect(errs[2].Field).To(Equal("spec.grafanaExport.prometheusURL"))
+
+	monitor.Spec.GrafanaExport = &v1alpha1.GrafanaExportSpec{
+		MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "bitnami/kubectl", Version: "1.28"},
+		DashboardLabels:  map[string]string{"grafana_dashboard": "1"},
+		Annotations:      map[string]string{"grafana_folder": "TiDB"},
+		PrometheusURL:    pointer.StringPtr("http://basic-prometheus.monitoring:9090"),
+	}
+	g.Expect(ValidateTidbMonitor(monitor)).To(BeEmpty())
+}
+
 func TestValidateDMCluster(t *testing.T) {
 	g := NewGomegaWithT(t)
 	tests := []struct {
@@ -499,6 +576,7 @@ func TestValidateDMCluster(t *testing.T) {
 		version           string
 		masterReplicas    int32
 		masterStorageSize string
+		masterAnnotations map[string]string
 		expectedError     string
 	}{
 		{
@@ -512,6 +590,14 @@ func TestValidateDMCluster(t *testing.T) {
 			masterReplicas: 3,
 			expectedError:  "storageSize must not be empty",
 		},
+		{
+			name:              "scheduled rolling restart",
+			version:           "nightly",
+			masterReplicas:    3,
+			masterStorageSize: "10Gi",
+			masterAnnotations: map[string]string{label.AnnRestartAtKey: "2024-05-01T02:00:00Z"},
+			expectedError:     "not supported by DMCluster",
+		},
 		{
 			name:              "correct configuration",
 			version:           "nightly",
@@ -526,6 +612,7 @@ func TestValidateDMCluster(t *testing.T) {
 			dc.Spec.Version = tt.version
 			dc.Spec.Master.Replicas = tt.masterReplicas
 			dc.Spec.Master.StorageSize = tt.masterStorageSize
+			dc.Spec.Master.Annotations = tt.masterAnnotations
 			err := ValidateDMCluster(dc)
 			if tt.expectedError != "" {
 				g.Expect(len(err)).Should(Equal(1))
@@ -535,6 +622,20 @@ func TestValidateDMCluster(t *testing.T) {
 	}
 }
 
+func TestValidateWorkerRelay(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &v1alpha1.WorkerSpec{
+		RelayStorage: &v1alpha1.DMRelayStorage{StorageSize: "100Gi"},
+		RelayPurge:   &v1alpha1.DMRelayPurgePolicy{Interval: pointer.Int64(600), Expires: pointer.Int64(24), RemainSpace: pointer.Int64(0)},
+	}
+	g.Expect(validateWorkerSpec(spec, field.NewPath("spec", "worker"))).To(BeEmpty())
+
+	spec.RelayStorage.StorageSize = "100G1"
+	spec.RelayPurge = &v1alpha1.DMRelayPurgePolicy{Interval: pointer.Int64(0), Expires: pointer.Int64(-1), RemainSpace: pointer.Int64(-1)}
+	g.Expect(validateWorkerSpec(spec, field.NewPath("spec", "worker"))).To(HaveLen(4))
+}
+
 func newTidbCluster() *v1alpha1.TidbCluster {
 	tc := &v1alpha1.TidbCluster{
 		Spec: v1alpha1.TidbClusterSpec{
@@ -749,6 +850,169 @@ func TestValidateStartScriptFeatureFlags(t *testing.T) {
 	}
 }
 
+func TestValidateAdditionalArgs(t *testing.T) {
+	successCases := [][]string{
+		{"--metrics-flush-interval=10s", "--log-level=info"},
+		{"--advertise-host=$(POD_IP)", "--labels=zone=$(ZONE),host=$(NODE_NAME)"},
+		{}, //empty
+	}
+
+	for _, c := range successCases {
+		errs := validateAdditionalArgs(c, field.NewPath("additionalArgs"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := [][]string{
+		{""},
+		{"--log-level info"},
+		{"--flag=$POD_IP"},
+		{"--flag=${POD_IP}"},
+		{"--flag=$(echo 1)"},
+		{"--flag=1;rm"},
+		{`--flag="1"`},
+	}
+
+	for _, c := range errorCases {
+		errs := validateAdditionalArgs(c, field.NewPath("additionalArgs"))
+		if len(errs) != 1 {
+			t.Errorf("expected 1 failure for %s but there was %d", c, len(errs))
+		}
+	}
+}
+
+func TestValidateSchedulingGates(t *testing.T) {
+	successCases := [][]corev1.PodSchedulingGate{
+		{{Name: "kueue.x-k8s.io/admission"}, {Name: "example.com/capacity"}},
+		{}, //empty
+	}
+
+	for _, c := range successCases {
+		errs := validateSchedulingGates(c, field.NewPath("schedulingGates"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := [][]corev1.PodSchedulingGate{
+		{{Name: ""}},
+		{{Name: "example.com/capacity gate"}},
+		{{Name: "example.com/capacity"}, {Name: "example.com/capacity"}},
+	}
+
+	for _, c := range errorCases {
+		errs := validateSchedulingGates(c, field.NewPath("schedulingGates"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v", c)
+		}
+	}
+}
+
+func TestValidateRestartAt(t *testing.T) {
+	successCases := []map[string]string{
+		{label.AnnRestartAtKey: "2024-05-01T02:00:00Z"},
+		{label.AnnRestartAtKey: "2024-05-01T10:00:00+08:00"},
+		{"foo": "bar"},
+		nil,
+	}
+
+	for _, c := range successCases {
+		errs := validateRestartAt(c, field.NewPath("annotations"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := []map[string]string{
+		{label.AnnRestartAtKey: ""},
+		{label.AnnRestartAtKey: "2024-05-01 02:00:00"},
+		{label.AnnRestartAtKey: "now"},
+	}
+
+	for _, c := range errorCases {
+		errs := validateRestartAt(c, field.NewPath("annotations"))
+		if len(errs) != 1 {
+			t.Errorf("expected 1 failure for %v but there was %d", c, len(errs))
+		}
+	}
+}
+
+func TestValidateTiDBClusterPreemptionPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	never := corev1.PreemptNever
+	unknown := corev1.PreemptionPolicy("Always")
+
+	tests := []struct {
+		name     string
+		modify   func(spec *v1alpha1.TidbClusterSpec)
+		errorNum int
+	}{
+		{
+			name:     "no preemption policy",
+			modify:   func(spec *v1alpha1.TidbClusterSpec) {},
+			errorNum: 0,
+		},
+		{
+			name: "cluster policy with cluster priority class",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.PriorityClassName = pointer.StringPtr("high")
+				spec.PreemptionPolicy = &never
+			},
+			errorNum: 0,
+		},
+		{
+			name: "component policy with cluster priority class",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.PriorityClassName = pointer.StringPtr("high")
+				spec.TiKV.PreemptionPolicy = &never
+			},
+			errorNum: 0,
+		},
+		{
+			name: "component policy with component priority class",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.TiDB.PriorityClassName = pointer.StringPtr("high")
+				spec.TiDB.PreemptionPolicy = &never
+			},
+			errorNum: 0,
+		},
+		{
+			name: "cluster policy without priority class",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.PreemptionPolicy = &never
+			},
+			errorNum: 1,
+		},
+		{
+			name: "component policy without priority class",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.TiDB.PriorityClassName = pointer.StringPtr("high")
+				spec.PD.PreemptionPolicy = &never
+			},
+			errorNum: 1,
+		},
+		{
+			name: "unsupported policy",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.PriorityClassName = pointer.StringPtr("high")
+				spec.PreemptionPolicy = &unknown
+			},
+			errorNum: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		spec := &v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+			TiDB: &v1alpha1.TiDBSpec{},
+		}
+		tt.modify(spec)
+		g.Expect(validateTiDBClusterPreemptionPolicy(spec, field.NewPath("spec"))).To(HaveLen(tt.errorNum), tt.name)
+	}
+}
+
 func TestValidatePDSpec(t *testing.T) {
 	g := NewGomegaWithT(t)
 	tests := []struct {
@@ -852,6 +1116,135 @@ func TestValidateTiFlashSpec(t *testing.T) {
 	}
 }
 
+func TestValidateTidbClusterSemantics(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		update         func(tc *v1alpha1.TidbCluster)
+		expectedErrors int
+	}{
+		{
+			name:           "valid",
+			update:         func(tc *v1alpha1.TidbCluster) {},
+			expectedErrors: 0,
+		},
+		{
+			name: "tiflash without tikv",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Replicas = 0
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "tiflash with tikv of the referenced cluster",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV = nil
+				tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Name: "ref"}
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "pd learners with old version",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.LearnerReplicas = 1
+				tc.Spec.Version = "v6.5.0"
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "pd learners with new version",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.LearnerReplicas = 1
+				tc.Spec.Version = "v7.5.0"
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "pd microservices mode with old version",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Mode = "ms"
+				tc.Spec.Version = "v7.5.0"
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "pd microservices mode with new version",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Mode = "ms"
+				tc.Spec.PD.Version = pointer.StringPtr("v8.1.0")
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "pd microservices mode with nightly version",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Mode = "ms"
+				tc.Spec.Version = "nightly"
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "invalid cluster domain",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.ClusterDomain = "Cluster_Local"
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "host network without conflicts",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.HostNetwork = pointer.BoolPtr(true)
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "additional container conflicts with the component in the host network",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.HostNetwork = pointer.BoolPtr(true)
+				tc.Spec.TiKV.AdditionalContainers = []corev1.Container{{
+					Name:  "exporter",
+					Ports: []corev1.ContainerPort{{ContainerPort: v1alpha1.DefaultTiKVStatusPort}},
+				}}
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "additional container conflicts with the undeclared port of another component",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.HostNetwork = pointer.BoolPtr(true)
+				tc.Spec.TiDB = &v1alpha1.TiDBSpec{}
+				tc.Spec.TiDB.AdditionalContainers = []corev1.Container{{
+					Name:  "exporter",
+					Ports: []corev1.ContainerPort{{ContainerPort: v1alpha1.DefaultTiFlashProxyStatusPort}},
+				}}
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "additional container in the pod network",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.AdditionalContainers = []corev1.Container{{
+					Name:  "exporter",
+					Ports: []corev1.ContainerPort{{ContainerPort: v1alpha1.DefaultTiKVStatusPort}},
+				}}
+			},
+			expectedErrors: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTidbClusterWithTiflash()
+			tc.Spec.Version = "v7.1.0"
+			tc.Spec.PD.BaseImage = "pingcap/pd"
+			tc.Spec.TiKV.Replicas = 3
+			tc.Spec.ClusterDomain = "cluster.local"
+			tt.update(tc)
+			err := validateTidbClusterSemantics(tc, field.NewPath("spec"))
+			g.Expect(err).Should(HaveLen(tt.expectedErrors), "%v", err)
+		})
+	}
+}
+
 func Test_disallowMutateBootstrapSQLConfigMapName(t *testing.T) {
 	g := NewGomegaWithT(t)
 	tests := []struct {
@@ -922,3 +1315,1252 @@ func Test_disallowMutateBootstrapSQLConfigMapName(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTidbClusterReplication(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newTCR := func() *v1alpha1.TidbClusterReplication {
+		return &v1alpha1.TidbClusterReplication{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tcr"},
+			Spec: v1alpha1.TidbClusterReplicationSpec{
+				Primary:   v1alpha1.TidbClusterRef{Name: "primary"},
+				Secondary: v1alpha1.TidbClusterRef{Name: "secondary"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(tcr *v1alpha1.TidbClusterReplication)
+		errorNum int
+	}{
+		{
+			name:     "valid TiCDC mode",
+			modify:   func(tcr *v1alpha1.TidbClusterReplication) {},
+			errorNum: 0,
+		},
+		{
+			name: "same clusters",
+			modify: func(tcr *v1alpha1.TidbClusterReplication) {
+				tcr.Spec.Secondary = v1alpha1.TidbClusterRef{Namespace: "ns", Name: "primary"}
+			},
+			errorNum: 1,
+		},
+		{
+			name: "same names in different namespaces",
+			modify: func(tcr *v1alpha1.TidbClusterReplication) {
+				tcr.Spec.Secondary = v1alpha1.TidbClusterRef{Namespace: "standby", Name: "primary"}
+			},
+			errorNum: 0,
+		},
+		{
+			name: "PITR mode without pitr",
+			modify: func(tcr *v1alpha1.TidbClusterReplication) {
+				tcr.Spec.Mode = v1alpha1.ReplicationModePITR
+			},
+			errorNum: 1,
+		},
+		{
+			name: "unknown mode and invalid max promote lag",
+			modify: func(tcr *v1alpha1.TidbClusterReplication) {
+				tcr.Spec.Mode = "DR"
+				tcr.Spec.MaxPromoteLag = pointer.StringPtr("10")
+			},
+			errorNum: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		tcr := newTCR()
+		tt.modify(tcr)
+		g.Expect(ValidateTidbClusterReplication(tcr)).To(HaveLen(tt.errorNum), tt.name)
+	}
+}
+
+func TestValidatePumpMigration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newPM := func() *v1alpha1.PumpMigration {
+		return &v1alpha1.PumpMigration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pm"},
+			Spec: v1alpha1.PumpMigrationSpec{
+				Cluster:     v1alpha1.TidbClusterRef{Name: "basic"},
+				Changefeeds: []v1alpha1.PumpMigrationChangefeed{{ID: "cf-1", SinkURI: "mysql://root:@downstream:4000/"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(pm *v1alpha1.PumpMigration)
+		errorNum int
+	}{
+		{
+			name:     "valid",
+			modify:   func(pm *v1alpha1.PumpMigration) {},
+			errorNum: 0,
+		},
+		{
+			name: "cluster in another namespace",
+			modify: func(pm *v1alpha1.PumpMigration) {
+				pm.Spec.Cluster.Namespace = "other"
+			},
+			errorNum: 1,
+		},
+		{
+			name: "no changefeeds",
+			modify: func(pm *v1alpha1.PumpMigration) {
+				pm.Spec.Changefeeds = nil
+			},
+			errorNum: 1,
+		},
+		{
+			name: "duplicated id and no sink",
+			modify: func(pm *v1alpha1.PumpMigration) {
+				pm.Spec.Changefeeds = append(pm.Spec.Changefeeds, v1alpha1.PumpMigrationChangefeed{ID: "cf-1"})
+			},
+			errorNum: 2,
+		},
+		{
+			name: "invalid max catch up lag",
+			modify: func(pm *v1alpha1.PumpMigration) {
+				pm.Spec.MaxCatchUpLag = pointer.StringPtr("10")
+			},
+			errorNum: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		pm := newPM()
+		tt.modify(pm)
+		g.Expect(ValidatePumpMigration(pm)).To(HaveLen(tt.errorNum), tt.name)
+	}
+}
+
+func TestValidateBackupSchedule(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		spec     v1alpha1.BackupScheduleSpec
+		errorNum int
+	}{
+		{
+			name:     "local time zone",
+			spec:     v1alpha1.BackupScheduleSpec{Schedule: "0 2 * * *"},
+			errorNum: 0,
+		},
+		{
+			name:     "valid time zone",
+			spec:     v1alpha1.BackupScheduleSpec{Schedule: "0 2 * * *", TimeZone: "Asia/Shanghai"},
+			errorNum: 0,
+		},
+		{
+			name:     "invalid time zone",
+			spec:     v1alpha1.BackupScheduleSpec{Schedule: "0 2 * * *", TimeZone: "Asia/Nowhere"},
+			errorNum: 1,
+		},
+		{
+			name:     "no schedule",
+			spec:     v1alpha1.BackupScheduleSpec{TimeZone: "UTC"},
+			errorNum: 1,
+		},
+		{
+			name: "valid storage class transition",
+			spec: v1alpha1.BackupScheduleSpec{
+				Schedule:               "0 2 * * *",
+				StorageClassTransition: &v1alpha1.StorageClassTransition{Days: 7, StorageClass: "GLACIER_IR"},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "archive storage class transition",
+			spec: v1alpha1.BackupScheduleSpec{
+				Schedule:               "0 2 * * *",
+				StorageClassTransition: &v1alpha1.StorageClassTransition{Days: 7, StorageClass: "DEEP_ARCHIVE"},
+			},
+			errorNum: 1,
+		},
+		{
+			name: "valid compact schedule",
+			spec: v1alpha1.BackupScheduleSpec{
+				Schedule:              "0 2 * * *",
+				LogBackupTemplate:     &v1alpha1.BackupSpec{Mode: v1alpha1.BackupModeLog},
+				CompactBackupTemplate: &v1alpha1.CompactSpec{},
+				CompactSchedule: &v1alpha1.CompactSchedule{
+					Schedule:              "0 */6 * * *",
+					Windows:               []string{"22:00-06:00", "12:00-13:30"},
+					MaxConcurrentCompacts: 2,
+				},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "invalid compact schedule",
+			spec: v1alpha1.BackupScheduleSpec{
+				Schedule: "0 2 * * *",
+				CompactSchedule: &v1alpha1.CompactSchedule{
+					Windows:               []string{"22:00", "25:00-06:00", "06:00-06:00"},
+					MaxConcurrentCompacts: -1,
+				},
+			},
+			errorNum: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		bs := &v1alpha1.BackupSchedule{Spec: tt.spec}
+		g.Expect(ValidateBackupSchedule(bs)).To(HaveLen(tt.errorNum), tt.name)
+	}
+}
+
+func TestValidateSuspendAction(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	components := []v1alpha1.MemberType{v1alpha1.TiDBMemberType, v1alpha1.TiKVMemberType, v1alpha1.PDMemberType}
+	tests := []struct {
+		name     string
+		action   v1alpha1.SuspendAction
+		errorNum int
+	}{
+		{
+			name:     "all components",
+			action:   v1alpha1.SuspendAction{SuspendStatefulSet: true},
+			errorNum: 0,
+		},
+		{
+			name:     "valid component and grace period",
+			action:   v1alpha1.SuspendAction{SuspendStatefulSet: true, Component: v1alpha1.TiKVMemberType, GracePeriod: &metav1.Duration{Duration: time.Minute}},
+			errorNum: 0,
+		},
+		{
+			name:     "unknown component",
+			action:   v1alpha1.SuspendAction{SuspendStatefulSet: true, Component: v1alpha1.DMMasterMemberType},
+			errorNum: 1,
+		},
+		{
+			name:     "negative grace period",
+			action:   v1alpha1.SuspendAction{SuspendStatefulSet: true, GracePeriod: &metav1.Duration{Duration: -time.Minute}},
+			errorNum: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		errs := validateSuspendAction(&tt.action, components, field.NewPath("spec", "suspendAction"))
+		g.Expect(errs).To(HaveLen(tt.errorNum), tt.name)
+	}
+}
+
+func TestValidateUpgradePolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		modify   func(policy *v1alpha1.UpgradePolicy)
+		errorNum int
+	}{
+		{
+			name:     "valid",
+			modify:   func(policy *v1alpha1.UpgradePolicy) {},
+			errorNum: 0,
+		},
+		{
+			name: "invalid version range",
+			modify: func(policy *v1alpha1.UpgradePolicy) {
+				policy.VersionRange = "~foo"
+			},
+			errorNum: 1,
+		},
+		{
+			name: "invalid versions",
+			modify: func(policy *v1alpha1.UpgradePolicy) {
+				policy.Versions = []string{"v7.5.1", "latest", "nightly"}
+			},
+			errorNum: 2,
+		},
+		{
+			name: "no versions and schedule",
+			modify: func(policy *v1alpha1.UpgradePolicy) {
+				policy.Versions = nil
+				policy.Schedule = ""
+			},
+			errorNum: 2,
+		},
+		{
+			name: "zero duration",
+			modify: func(policy *v1alpha1.UpgradePolicy) {
+				policy.Duration = &metav1.Duration{}
+			},
+			errorNum: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		policy := &v1alpha1.UpgradePolicy{
+			VersionRange: "~7.5",
+			Versions:     []string{"v7.5.0", "v7.5.1"},
+			Schedule:     "0 2 * * 6",
+		}
+		tt.modify(policy)
+		errs := validateUpgradePolicy(policy, field.NewPath("spec", "upgradePolicy"))
+		g.Expect(errs).To(HaveLen(tt.errorNum), tt.name)
+	}
+}
+
+func TestValidatePDLearners(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		spec     v1alpha1.TidbClusterSpec
+		errorNum int
+	}{
+		{
+			name:     "start script v2",
+			spec:     v1alpha1.TidbClusterSpec{StartScriptVersion: v1alpha1.StartScriptV2},
+			errorNum: 0,
+		},
+		{
+			name:     "start script v1",
+			spec:     v1alpha1.TidbClusterSpec{},
+			errorNum: 1,
+		},
+		{
+			name:     "pd addresses",
+			spec:     v1alpha1.TidbClusterSpec{StartScriptVersion: v1alpha1.StartScriptV2, PDAddresses: []string{"http://pd:2379"}},
+			errorNum: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test.spec.PD = &v1alpha1.PDSpec{Replicas: 3, LearnerReplicas: 1}
+		errs := validatePDLearners(&test.spec, field.NewPath("spec"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateExternalPD(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		modify   func(spec *v1alpha1.TidbClusterSpec)
+		errorNum int
+	}{
+		{
+			name:     "valid",
+			modify:   func(spec *v1alpha1.TidbClusterSpec) {},
+			errorNum: 0,
+		},
+		{
+			name: "no endpoints",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.ExternalPD.Endpoints = nil
+			},
+			errorNum: 1,
+		},
+		{
+			name: "invalid endpoints",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.ExternalPD.Endpoints = []string{"pd-0.example.com:2379", "tcp://pd-1.example.com:2379"}
+			},
+			errorNum: 2,
+		},
+		{
+			name: "mixed schemes",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.ExternalPD.Endpoints = []string{"https://pd-0.example.com:2379", "http://pd-1.example.com:2379"}
+			},
+			errorNum: 1,
+		},
+		{
+			name: "local pd",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.PD = &v1alpha1.PDSpec{Replicas: 3}
+				spec.PDAddresses = []string{"http://pd:2379"}
+			},
+			errorNum: 2,
+		},
+		{
+			name: "heterogeneous cluster",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.Cluster = &v1alpha1.TidbClusterRef{Name: "basic"}
+				spec.AcrossK8s = true
+			},
+			errorNum: 2,
+		},
+		{
+			name: "start script v1",
+			modify: func(spec *v1alpha1.TidbClusterSpec) {
+				spec.StartScriptVersion = v1alpha1.StartScriptV1
+			},
+			errorNum: 1,
+		},
+	}
+
+	for _, test := range tests {
+		spec := &v1alpha1.TidbClusterSpec{
+			StartScriptVersion: v1alpha1.StartScriptV2,
+			ExternalPD: &v1alpha1.ExternalPDSpec{
+				Endpoints: []string{"https://pd-0.example.com:2379", "https://pd-1.example.com:2379"},
+			},
+		}
+		test.modify(spec)
+		errs := validateExternalPD(spec, field.NewPath("spec"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidatePDLeaderPriorities(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name       string
+		priorities []v1alpha1.PDLeaderPriority
+		errorNum   int
+	}{
+		{
+			name: "valid",
+			priorities: []v1alpha1.PDLeaderPriority{
+				{Ordinals: []int32{0, 1}, Priority: 5},
+				{Zone: "us-east-1a", Priority: 3},
+			},
+			errorNum: 0,
+		},
+		{
+			name:       "neither ordinals nor zone",
+			priorities: []v1alpha1.PDLeaderPriority{{Priority: 5}},
+			errorNum:   1,
+		},
+		{
+			name:       "both ordinals and zone",
+			priorities: []v1alpha1.PDLeaderPriority{{Ordinals: []int32{0}, Zone: "us-east-1a", Priority: 5}},
+			errorNum:   1,
+		},
+		{
+			name:       "negative ordinal",
+			priorities: []v1alpha1.PDLeaderPriority{{Ordinals: []int32{0, -1}, Priority: 5}},
+			errorNum:   1,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validatePDLeaderPriorities(test.priorities, field.NewPath("spec", "pd", "leaderPriorities"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiKVStoreWeights(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		weights  []v1alpha1.TiKVStoreWeight
+		errorNum int
+	}{
+		{
+			name: "valid",
+			weights: []v1alpha1.TiKVStoreWeight{
+				{PodNames: []string{"basic-tikv-0"}, LeaderWeight: pointer.Float64(0.5)},
+				{NodeSelector: map[string]string{"disk": "hdd"}, LeaderWeight: pointer.Float64(0), RegionWeight: pointer.Float64(0.5)},
+			},
+			errorNum: 0,
+		},
+		{
+			name:     "neither pod names nor node selector",
+			weights:  []v1alpha1.TiKVStoreWeight{{LeaderWeight: pointer.Float64(0.5)}},
+			errorNum: 1,
+		},
+		{
+			name:     "negative weights",
+			weights:  []v1alpha1.TiKVStoreWeight{{PodNames: []string{"basic-tikv-0"}, LeaderWeight: pointer.Float64(-1), RegionWeight: pointer.Float64(-1)}},
+			errorNum: 2,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiKVStoreWeights(test.weights, field.NewPath("spec", "tikv", "storeWeights"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiKVSlowStoreDetection(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name      string
+		detection v1alpha1.TiKVSlowStoreDetection
+		errorNum  int
+	}{
+		{
+			name:      "defaults",
+			detection: v1alpha1.TiKVSlowStoreDetection{},
+			errorNum:  0,
+		},
+		{
+			name: "valid",
+			detection: v1alpha1.TiKVSlowStoreDetection{
+				SlowScoreThreshold: pointer.Int32(60),
+				Duration:           &metav1.Duration{Duration: 10 * time.Minute},
+				Mitigation:         v1alpha1.TiKVSlowStoreMitigationEvictLeader,
+				MaxMitigatedStores: pointer.Int32(2),
+			},
+			errorNum: 0,
+		},
+		{
+			name: "invalid",
+			detection: v1alpha1.TiKVSlowStoreDetection{
+				SlowScoreThreshold: pointer.Int32(101),
+				Duration:           &metav1.Duration{Duration: -time.Minute},
+				Mitigation:         "Restart",
+				MaxMitigatedStores: pointer.Int32(0),
+			},
+			errorNum: 4,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiKVSlowStoreDetection(&test.detection, field.NewPath("spec", "tikv", "slowStoreDetection"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiFlashTableReplicas(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		tables   []v1alpha1.TiFlashTableReplica
+		errorNum int
+	}{
+		{
+			name: "valid",
+			tables: []v1alpha1.TiFlashTableReplica{
+				{Database: "db1", Count: 1},
+				{Database: "db2", Table: "t1", Count: 2},
+				{Database: "db2", Table: "t2", Count: 0},
+			},
+			errorNum: 0,
+		},
+		{
+			name:     "database not specified",
+			tables:   []v1alpha1.TiFlashTableReplica{{Table: "t1", Count: 1}},
+			errorNum: 1,
+		},
+		{
+			name: "duplicated table",
+			tables: []v1alpha1.TiFlashTableReplica{
+				{Database: "db1", Table: "t1", Count: 1},
+				{Database: "db1", Table: "t1", Count: 2},
+			},
+			errorNum: 1,
+		},
+		{
+			name: "table of database declared as a whole",
+			tables: []v1alpha1.TiFlashTableReplica{
+				{Database: "db1", Table: "t1", Count: 2},
+				{Database: "db1", Count: 1},
+			},
+			errorNum: 1,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiFlashTableReplicas(test.tables, field.NewPath("spec", "tiflash", "tableReplicas", "tables"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidatePropagatePolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		policy   v1alpha1.PropagatePolicy
+		errorNum int
+	}{
+		{
+			name:     "valid",
+			policy:   v1alpha1.PropagatePolicy{Labels: []string{"team", "example.com/*"}, Annotations: []string{"note"}},
+			errorNum: 0,
+		},
+		{
+			name:     "all the keys are not allowed",
+			policy:   v1alpha1.PropagatePolicy{Annotations: []string{"*"}},
+			errorNum: 1,
+		},
+		{
+			name:     "invalid keys",
+			policy:   v1alpha1.PropagatePolicy{Labels: []string{"", "example.*/team"}, Annotations: []string{"note**"}},
+			errorNum: 3,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validatePropagatePolicy(&test.policy, field.NewPath("spec", "propagatePolicy"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateAcrossK8sResolver(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		resolver v1alpha1.AcrossK8sResolver
+		errorNum int
+	}{
+		{
+			name:     "cluster set",
+			resolver: v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterSet},
+			errorNum: 0,
+		},
+		{
+			name:     "external dns without domain",
+			resolver: v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverExternalDNS},
+			errorNum: 1,
+		},
+		{
+			name:     "static without endpoints",
+			resolver: v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverStatic},
+			errorNum: 1,
+		},
+		{
+			name:     "unknown type",
+			resolver: v1alpha1.AcrossK8sResolver{Type: "Consul"},
+			errorNum: 1,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateAcrossK8sResolver(&test.resolver, field.NewPath("spec", "acrossK8sResolver"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateClusterCloneFrom(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		from     v1alpha1.ClusterCloneFrom
+		errorNum int
+	}{
+		{
+			name: "valid",
+			from: v1alpha1.ClusterCloneFrom{
+				TidbClusterRef: v1alpha1.TidbClusterRef{Name: "source"},
+				S3:             &v1alpha1.S3StorageProvider{Bucket: "backup"},
+			},
+			errorNum: 0,
+		},
+		{
+			name:     "no source and storage",
+			from:     v1alpha1.ClusterCloneFrom{},
+			errorNum: 2,
+		},
+		{
+			name: "multiple storages and unknown method",
+			from: v1alpha1.ClusterCloneFrom{
+				TidbClusterRef: v1alpha1.TidbClusterRef{Name: "source"},
+				Method:         "Dumpling",
+				S3:             &v1alpha1.S3StorageProvider{Bucket: "backup"},
+				Gcs:            &v1alpha1.GcsStorageProvider{Bucket: "backup"},
+			},
+			errorNum: 2,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateClusterCloneFrom(&test.from, field.NewPath("spec", "clusterCloneFrom"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateDriftProtection(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name       string
+		protection v1alpha1.DriftProtection
+		errorNum   int
+	}{
+		{
+			name:       "default policy",
+			protection: v1alpha1.DriftProtection{},
+			errorNum:   0,
+		},
+		{
+			name:       "revert with exempt managers",
+			protection: v1alpha1.DriftProtection{Policy: v1alpha1.DriftPolicyRevert, ExemptManagers: []string{"kubectl-edit"}},
+			errorNum:   0,
+		},
+		{
+			name:       "invalid fields",
+			protection: v1alpha1.DriftProtection{Policy: "Ignore", ExemptManagers: []string{""}},
+			errorNum:   2,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateDriftProtection(&test.protection, field.NewPath("spec", "driftProtection"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateDeletionPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, policy := range []v1alpha1.DeletionPolicy{"", v1alpha1.DeletionPolicyBackground, v1alpha1.DeletionPolicyRetainData, v1alpha1.DeletionPolicyPurge} {
+		g.Expect(validateDeletionPolicy(policy, field.NewPath("spec", "deletionPolicy"))).To(BeEmpty(), string(policy))
+	}
+	g.Expect(validateDeletionPolicy("Foreground", field.NewPath("spec", "deletionPolicy"))).To(HaveLen(1))
+}
+
+func TestValidateProfileCapture(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		capture  v1alpha1.ProfileCaptureSpec
+		errorNum int
+	}{
+		{
+			name: "cpu profile of tikv",
+			capture: v1alpha1.ProfileCaptureSpec{
+				Component:       v1alpha1.TiKVMemberType,
+				Ordinal:         1,
+				Duration:        &metav1.Duration{Duration: time.Minute},
+				StorageProvider: v1alpha1.StorageProvider{S3: &v1alpha1.S3StorageProvider{Bucket: "profiles"}},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "component not deployed",
+			capture: v1alpha1.ProfileCaptureSpec{
+				Component:       v1alpha1.TiDBMemberType,
+				Type:            v1alpha1.ProfileTypeHeap,
+				StorageProvider: v1alpha1.StorageProvider{Gcs: &v1alpha1.GcsStorageProvider{Bucket: "profiles"}},
+			},
+			errorNum: 1,
+		},
+		{
+			name: "invalid fields",
+			capture: v1alpha1.ProfileCaptureSpec{
+				Component: v1alpha1.TiFlashMemberType,
+				Ordinal:   -1,
+				Type:      "Goroutine",
+				Duration:  &metav1.Duration{Duration: -time.Second},
+				StorageProvider: v1alpha1.StorageProvider{
+					S3:    &v1alpha1.S3StorageProvider{Bucket: "profiles"},
+					Local: &v1alpha1.LocalStorageProvider{},
+				},
+			},
+			errorNum: 5,
+		},
+	}
+	for _, test := range tests {
+		spec := &v1alpha1.TidbClusterSpec{
+			PD:             &v1alpha1.PDSpec{},
+			TiKV:           &v1alpha1.TiKVSpec{},
+			ProfileCapture: &test.capture,
+		}
+		errs := validateProfileCapture(spec, field.NewPath("spec", "profileCapture"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name+": %v", errs)
+	}
+}
+
+func TestValidatePrometheusMonitor(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		monitor  v1alpha1.PrometheusMonitorSpec
+		errorNum int
+	}{
+		{
+			name: "valid",
+			monitor: v1alpha1.PrometheusMonitorSpec{
+				Labels:   map[string]string{"release": "kube-prometheus-stack"},
+				Interval: &metav1.Duration{Duration: 30 * time.Second},
+			},
+			errorNum: 0,
+		},
+		{
+			name:     "empty",
+			monitor:  v1alpha1.PrometheusMonitorSpec{},
+			errorNum: 0,
+		},
+		{
+			name: "invalid fields",
+			monitor: v1alpha1.PrometheusMonitorSpec{
+				Labels:   map[string]string{"release": "kube prometheus"},
+				Interval: &metav1.Duration{},
+			},
+			errorNum: 2,
+		},
+	}
+	for _, test := range tests {
+		errs := validatePrometheusMonitor(&test.monitor, field.NewPath("spec", "prometheusMonitor"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name+": %v", errs)
+	}
+}
+
+func TestValidateResourceRecommendation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		policy   v1alpha1.ResourceRecommendationPolicy
+		errorNum int
+	}{
+		{
+			name:     "default policy",
+			policy:   v1alpha1.ResourceRecommendationPolicy{},
+			errorNum: 0,
+		},
+		{
+			name: "bounds and auto apply",
+			policy: v1alpha1.ResourceRecommendationPolicy{
+				Components: []v1alpha1.MemberType{v1alpha1.TiKVMemberType, v1alpha1.TiDBMemberType},
+				MinAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				MaxAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceMemory: resource.MustParse("32Gi")},
+				AutoApply:  &v1alpha1.ResourceRecommendationAutoApply{Schedule: "0 2 * * 6"},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "invalid fields",
+			policy: v1alpha1.ResourceRecommendationPolicy{
+				Components:    []v1alpha1.MemberType{v1alpha1.PumpMemberType},
+				MarginPercent: pointer.Int32(-1),
+				MinAllowed:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceStorage: resource.MustParse("1Gi")},
+				MaxAllowed:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				AutoApply:     &v1alpha1.ResourceRecommendationAutoApply{Duration: &metav1.Duration{Duration: -time.Hour}},
+			},
+			errorNum: 6,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateResourceRecommendation(&test.policy, field.NewPath("spec", "resourceRecommendation"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateVeleroSpec(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		hooks    []v1alpha1.VeleroBackupHook
+		errorNum int
+	}{
+		{
+			name: "valid",
+			hooks: []v1alpha1.VeleroBackupHook{
+				{Component: v1alpha1.TiKVMemberType, PreCommand: []string{"sync"}, OnError: "Continue"},
+				{Component: v1alpha1.PDMemberType, PostCommand: []string{"sync"}},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "duplicated component",
+			hooks: []v1alpha1.VeleroBackupHook{
+				{Component: v1alpha1.TiKVMemberType, PreCommand: []string{"sync"}},
+				{Component: v1alpha1.TiKVMemberType, PostCommand: []string{"sync"}},
+			},
+			errorNum: 1,
+		},
+		{
+			name:     "invalid hook",
+			hooks:    []v1alpha1.VeleroBackupHook{{OnError: "Ignore"}},
+			errorNum: 3,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateVeleroSpec(&v1alpha1.VeleroSpec{Hooks: test.hooks}, field.NewPath("spec", "velero"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateGRPCProbes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	grpcProbe := func() v1alpha1.ComponentSpec {
+		return v1alpha1.ComponentSpec{ReadinessProbe: &v1alpha1.Probe{Type: pointer.StringPtr(v1alpha1.GRPCProbeType)}}
+	}
+	tests := []struct {
+		name     string
+		spec     v1alpha1.TidbClusterSpec
+		errorNum int
+	}{
+		{
+			name: "tikv and tiflash",
+			spec: v1alpha1.TidbClusterSpec{
+				TiKV:    &v1alpha1.TiKVSpec{ComponentSpec: grpcProbe()},
+				TiFlash: &v1alpha1.TiFlashSpec{ComponentSpec: grpcProbe()},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "pd and tidb",
+			spec: v1alpha1.TidbClusterSpec{
+				PD:   &v1alpha1.PDSpec{ComponentSpec: grpcProbe()},
+				TiDB: &v1alpha1.TiDBSpec{ComponentSpec: grpcProbe()},
+			},
+			errorNum: 2,
+		},
+		{
+			name: "tls enabled",
+			spec: v1alpha1.TidbClusterSpec{
+				TLSCluster: &v1alpha1.TLSCluster{Enabled: true},
+				TiKV:       &v1alpha1.TiKVSpec{ComponentSpec: grpcProbe()},
+				TiFlash:    &v1alpha1.TiFlashSpec{ComponentSpec: grpcProbe()},
+			},
+			errorNum: 2,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateGRPCProbes(&test.spec, field.NewPath("spec"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateDMDatabaseTLS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name        string
+		databaseTLS []v1alpha1.DMDatabaseTLS
+		errorNum    int
+	}{
+		{
+			name: "valid",
+			databaseTLS: []v1alpha1.DMDatabaseTLS{
+				{Name: "mysql-01", Type: v1alpha1.DMDatabaseTypeSource, SecretName: "mysql-01-tls"},
+				{Name: "tidb", Type: v1alpha1.DMDatabaseTypeTarget, SecretName: "tidb-client-tls", CertAllowedCN: []string{"tidb"}},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "duplicated names",
+			databaseTLS: []v1alpha1.DMDatabaseTLS{
+				{Name: "mysql-01", Type: v1alpha1.DMDatabaseTypeSource, SecretName: "mysql-01-tls"},
+				{Name: "mysql-01", Type: v1alpha1.DMDatabaseTypeSource, SecretName: "mysql-02-tls"},
+			},
+			errorNum: 1,
+		},
+		{
+			name: "invalid fields",
+			databaseTLS: []v1alpha1.DMDatabaseTLS{
+				{Name: "mysql/01", Type: "upstream"},
+			},
+			errorNum: 3,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateDMDatabaseTLS(test.databaseTLS, field.NewPath("spec", "databaseTLS"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiDBServices(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		services []v1alpha1.TiDBService
+		errorNum int
+	}{
+		{
+			name: "valid",
+			services: []v1alpha1.TiDBService{
+				{Name: "internal", Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, LoadBalancerSourceRanges: []string{"10.0.0.0/8"}}},
+				{Name: "headless", Spec: corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone}},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "duplicated names",
+			services: []v1alpha1.TiDBService{
+				{Name: "internal"},
+				{Name: "internal"},
+			},
+			errorNum: 1,
+		},
+		{
+			name: "invalid fields",
+			services: []v1alpha1.TiDBService{
+				{Name: ""},
+				{Name: "peer"},
+				{Name: "Internal_LB"},
+				{Name: "external", Spec: corev1.ServiceSpec{LoadBalancerSourceRanges: []string{"10.0.0.0"}}},
+			},
+			errorNum: 4,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiDBServices(test.services, field.NewPath("spec", "tidb", "services"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiDBLogShipping(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		spec     v1alpha1.TiDBLogShippingSpec
+		errorNum int
+	}{
+		{
+			name: "valid",
+			spec: v1alpha1.TiDBLogShippingSpec{
+				AuditLogFile: "/var/log/tidb/audit.log",
+				Outputs: []v1alpha1.LogShippingOutput{
+					{Loki: &v1alpha1.LokiLogOutput{Host: "loki"}},
+					{S3: &v1alpha1.S3LogOutput{Bucket: "logs", Region: "us-west-2"}},
+					{Kafka: &v1alpha1.KafkaLogOutput{Brokers: []string{"kafka:9092"}, Topic: "tidb-logs"}},
+				},
+			},
+			errorNum: 0,
+		},
+		{
+			name:     "no outputs",
+			spec:     v1alpha1.TiDBLogShippingSpec{AuditLogFile: "audit.log"},
+			errorNum: 2,
+		},
+		{
+			name: "invalid outputs",
+			spec: v1alpha1.TiDBLogShippingSpec{
+				Outputs: []v1alpha1.LogShippingOutput{
+					{},
+					{Loki: &v1alpha1.LokiLogOutput{Host: "loki"}, Kafka: &v1alpha1.KafkaLogOutput{}},
+					{S3: &v1alpha1.S3LogOutput{}},
+				},
+			},
+			errorNum: 6,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiDBLogShipping(&test.spec, field.NewPath("spec", "tidb", "logShipping"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiDBCanary(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		spec     v1alpha1.TiDBCanaryStrategy
+		errorNum int
+	}{
+		{
+			name: "valid",
+			spec: v1alpha1.TiDBCanaryStrategy{
+				Replicas:        1,
+				SoakDuration:    metav1.Duration{Duration: 30 * time.Minute},
+				PrometheusURL:   "http://monitor-prometheus:9090",
+				LatencySLO:      &metav1.Duration{Duration: time.Second},
+				MaxErrorPercent: pointer.Int32Ptr(1),
+			},
+			errorNum: 0,
+		},
+		{
+			name: "without slo",
+			spec: v1alpha1.TiDBCanaryStrategy{
+				Replicas:     2,
+				SoakDuration: metav1.Duration{Duration: 30 * time.Minute},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "invalid",
+			spec: v1alpha1.TiDBCanaryStrategy{
+				Replicas:        3,
+				CheckInterval:   &metav1.Duration{},
+				MaxErrorPercent: pointer.Int32Ptr(101),
+			},
+			errorNum: 5,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiDBCanary(&test.spec, 3, field.NewPath("spec", "tidb", "canary"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiDBLoadBalancerReadiness(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name      string
+		readiness v1alpha1.TiDBLoadBalancerReadiness
+		errorNum  int
+	}{
+		{
+			name: "aws",
+			readiness: v1alpha1.TiDBLoadBalancerReadiness{
+				AWS: &v1alpha1.AWSTargetGroup{TargetGroupARN: "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/tidb/73e2d6bc24d8a067"},
+			},
+			errorNum: 0,
+		},
+		{
+			name: "gcp",
+			readiness: v1alpha1.TiDBLoadBalancerReadiness{
+				ConditionType: "cloud.google.com/load-balancer-neg-ready",
+				GCP:           &v1alpha1.GCPNetworkEndpointGroup{Project: "project", Name: "tidb", Zones: []string{"us-central1-a"}},
+			},
+			errorNum: 0,
+		},
+		{
+			name:      "none",
+			readiness: v1alpha1.TiDBLoadBalancerReadiness{},
+			errorNum:  1,
+		},
+		{
+			name: "invalid",
+			readiness: v1alpha1.TiDBLoadBalancerReadiness{
+				ConditionType: "load balancer ready",
+				AWS:           &v1alpha1.AWSTargetGroup{TargetGroupARN: "tidb"},
+				GCP:           &v1alpha1.GCPNetworkEndpointGroup{},
+			},
+			errorNum: 6,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiDBLoadBalancerReadiness(&test.readiness, field.NewPath("spec", "tidb", "loadBalancerReadiness"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTiKVMasterKeySource(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		key      v1alpha1.TiKVMasterKeySource
+		errorNum int
+	}{
+		{
+			name:     "aws kms",
+			key:      v1alpha1.TiKVMasterKeySource{AWSKMS: &v1alpha1.TiKVAWSKMSMasterKey{KeyID: "key", Region: "us-west-2"}},
+			errorNum: 0,
+		},
+		{
+			name:     "vault",
+			key:      v1alpha1.TiKVMasterKeySource{Vault: &v1alpha1.TiKVVaultMasterKey{Role: "tikv", Path: "secret/data/tikv"}},
+			errorNum: 0,
+		},
+		{
+			name:     "no source",
+			key:      v1alpha1.TiKVMasterKeySource{},
+			errorNum: 1,
+		},
+		{
+			name: "multiple invalid sources",
+			key: v1alpha1.TiKVMasterKeySource{
+				GCPKMS: &v1alpha1.TiKVGCPKMSMasterKey{},
+				Secret: &v1alpha1.TiKVSecretMasterKey{},
+			},
+			errorNum: 3,
+		},
+	}
+
+	for _, test := range tests {
+		errs := validateTiKVMasterKeySource(&test.key, field.NewPath("spec", "tikv", "encryption", "masterKey"))
+		g.Expect(errs).To(HaveLen(test.errorNum), test.name)
+	}
+}
+
+func TestValidateTidbAccount(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newTA := func() *v1alpha1.TidbAccount {
+		return &v1alpha1.TidbAccount{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app"},
+			Spec: v1alpha1.TidbAccountSpec{
+				Cluster: v1alpha1.TidbClusterRef{Name: "basic"},
+				User:    "app",
+				PasswordSecret: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "app-password"},
+					Key:                  "password",
+				},
+				Privileges: []v1alpha1.TidbAccountPrivilege{
+					{Privileges: []string{"SELECT", "INSERT"}, On: "app.*"},
+					{Privileges: []string{"ALL PRIVILEGES"}, On: "app.t1"},
+					{Privileges: []string{"BACKUP_ADMIN"}},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(ta *v1alpha1.TidbAccount)
+		errorNum int
+	}{
+		{
+			name:     "valid",
+			modify:   func(ta *v1alpha1.TidbAccount) {},
+			errorNum: 0,
+		},
+		{
+			name: "no cluster and user",
+			modify: func(ta *v1alpha1.TidbAccount) {
+				ta.Spec.Cluster.Name = ""
+				ta.Spec.User = ""
+			},
+			errorNum: 2,
+		},
+		{
+			name: "user too long",
+			modify: func(ta *v1alpha1.TidbAccount) {
+				ta.Spec.User = strings.Repeat("u", 33)
+			},
+			errorNum: 1,
+		},
+		{
+			name: "no password key",
+			modify: func(ta *v1alpha1.TidbAccount) {
+				ta.Spec.PasswordSecret.Key = ""
+			},
+			errorNum: 1,
+		},
+		{
+			name: "invalid privileges",
+			modify: func(ta *v1alpha1.TidbAccount) {
+				ta.Spec.Privileges = []v1alpha1.TidbAccountPrivilege{
+					{On: "app.*"},
+					{Privileges: []string{"SELECT; DROP DATABASE app"}, On: "app.*"},
+					{Privileges: []string{"SELECT"}, On: "*.t1"},
+					{Privileges: []string{"SELECT"}, On: "app"},
+				}
+			},
+			errorNum: 4,
+		},
+		{
+			name: "invalid tls requirement and reclaim policy",
+			modify: func(ta *v1alpha1.TidbAccount) {
+				ta.Spec.TLS = &v1alpha1.TidbAccountTLS{Require: "Cert"}
+				ta.Spec.ReclaimPolicy = "Recycle"
+			},
+			errorNum: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		ta := newTA()
+		tt.modify(ta)
+		g.Expect(ValidateTidbAccount(ta)).To(HaveLen(tt.errorNum), tt.name)
+	}
+}
@@ -30,8 +30,83 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSTargetGroup) DeepCopyInto(out *AWSTargetGroup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSTargetGroup.
+func (in *AWSTargetGroup) DeepCopy() *AWSTargetGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSTargetGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AcrossK8sResolver) DeepCopyInto(out *AcrossK8sResolver) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AcrossK8sResolver.
+func (in *AcrossK8sResolver) DeepCopy() *AcrossK8sResolver {
+	if in == nil {
+		return nil
+	}
+	out := new(AcrossK8sResolver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerSpec) DeepCopyInto(out *AlertmanagerSpec) {
+	*out = *in
+	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerSpec.
+func (in *AlertmanagerSpec) DeepCopy() *AlertmanagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzblobStorageProvider) DeepCopyInto(out *AzblobStorageProvider) {
 	*out = *in
@@ -99,6 +174,73 @@ func (in *BRConfig) DeepCopy() *BRConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BRJobSummary) DeepCopyInto(out *BRJobSummary) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishTime != nil {
+		in, out := &in.FinishTime, &out.FinishTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BRJobSummary.
+func (in *BRJobSummary) DeepCopy() *BRJobSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(BRJobSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BRNotificationWebhook) DeepCopyInto(out *BRNotificationWebhook) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BRNotificationWebhook.
+func (in *BRNotificationWebhook) DeepCopy() *BRNotificationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(BRNotificationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BRNotifications) DeepCopyInto(out *BRNotifications) {
+	*out = *in
+	out.Webhook = in.Webhook
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]BRNotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProgressMilestones != nil {
+		in, out := &in.ProgressMilestones, &out.ProgressMilestones
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BRNotifications.
+func (in *BRNotifications) DeepCopy() *BRNotifications {
+	if in == nil {
+		return nil
+	}
+	out := new(BRNotifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackoffRetryPolicy) DeepCopyInto(out *BackoffRetryPolicy) {
 	*out = *in
@@ -221,6 +363,27 @@ func (in *BackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupReplicaReadPolicy) DeepCopyInto(out *BackupReplicaReadPolicy) {
+	*out = *in
+	if in.LearnerRule != nil {
+		in, out := &in.LearnerRule, &out.LearnerRule
+		*out = new(PlacementRuleRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupReplicaReadPolicy.
+func (in *BackupReplicaReadPolicy) DeepCopy() *BackupReplicaReadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupReplicaReadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSchedule) DeepCopyInto(out *BackupSchedule) {
 	*out = *in
@@ -300,6 +463,16 @@ func (in *BackupScheduleSpec) DeepCopyInto(out *BackupScheduleSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CompactSchedule != nil {
+		in, out := &in.CompactSchedule, &out.CompactSchedule
+		*out = new(CompactSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClassTransition != nil {
+		in, out := &in.StorageClassTransition, &out.StorageClassTransition
+		*out = new(StorageClassTransition)
+		**out = **in
+	}
 	in.BackupTemplate.DeepCopyInto(&out.BackupTemplate)
 	if in.LogBackupTemplate != nil {
 		in, out := &in.LogBackupTemplate, &out.LogBackupTemplate
@@ -364,10 +537,27 @@ func (in *BackupScheduleStatus) DeepCopyInto(out *BackupScheduleStatus) {
 		in, out := &in.LastCompactExecutionTs, &out.LastCompactExecutionTs
 		*out = (*in).DeepCopy()
 	}
+	if in.LastCompactScheduleTime != nil {
+		in, out := &in.LastCompactScheduleTime, &out.LastCompactScheduleTime
+		*out = (*in).DeepCopy()
+	}
 	if in.AllBackupCleanTime != nil {
 		in, out := &in.AllBackupCleanTime, &out.AllBackupCleanTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastMissedRunTime != nil {
+		in, out := &in.LastMissedRunTime, &out.LastMissedRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StorageUsage != nil {
+		in, out := &in.StorageUsage, &out.StorageUsage
+		*out = new(BackupScheduleStorageUsage)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -381,6 +571,35 @@ func (in *BackupScheduleStatus) DeepCopy() *BackupScheduleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupScheduleStorageUsage) DeepCopyInto(out *BackupScheduleStorageUsage) {
+	*out = *in
+	if in.RemainingBackups != nil {
+		in, out := &in.RemainingBackups, &out.RemainingBackups
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OldestBackupExpireTime != nil {
+		in, out := &in.OldestBackupExpireTime, &out.OldestBackupExpireTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdateTime != nil {
+		in, out := &in.UpdateTime, &out.UpdateTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupScheduleStorageUsage.
+func (in *BackupScheduleStorageUsage) DeepCopy() *BackupScheduleStorageUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupScheduleStorageUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 	*out = *in
@@ -450,6 +669,11 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 		*out = new(v1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
 	out.BackoffRetryPolicy = in.BackoffRetryPolicy
 	if in.AdditionalVolumes != nil {
 		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
@@ -465,6 +689,21 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Throttle != nil {
+		in, out := &in.Throttle, &out.Throttle
+		*out = new(BackupThrottle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupReplicaReadPolicy != nil {
+		in, out := &in.BackupReplicaReadPolicy, &out.BackupReplicaReadPolicy
+		*out = new(BackupReplicaReadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(BRNotifications)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -511,6 +750,22 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StorageClassTransitionTime != nil {
+		in, out := &in.StorageClassTransitionTime, &out.StorageClassTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Throttle != nil {
+		in, out := &in.Throttle, &out.Throttle
+		*out = new(BackupThrottleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobSummaries != nil {
+		in, out := &in.JobSummaries, &out.JobSummaries
+		*out = make([]BRJobSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -524,6 +779,72 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupThrottle) DeepCopyInto(out *BackupThrottle) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxCPUUsage != nil {
+		in, out := &in.MaxCPUUsage, &out.MaxCPUUsage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxIOBandwidth != nil {
+		in, out := &in.MaxIOBandwidth, &out.MaxIOBandwidth
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LatencySLO != nil {
+		in, out := &in.LatencySLO, &out.LatencySLO
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinThreads != nil {
+		in, out := &in.MinThreads, &out.MinThreads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxThreads != nil {
+		in, out := &in.MaxThreads, &out.MaxThreads
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupThrottle.
+func (in *BackupThrottle) DeepCopy() *BackupThrottle {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupThrottle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupThrottleStatus) DeepCopyInto(out *BackupThrottleStatus) {
+	*out = *in
+	if in.LastAdjustTime != nil {
+		in, out := &in.LastAdjustTime, &out.LastAdjustTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupThrottleStatus.
+func (in *BackupThrottleStatus) DeepCopy() *BackupThrottleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupThrottleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
@@ -636,6 +957,63 @@ func (in *CleanOption) DeepCopy() *CleanOption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCloneFrom) DeepCopyInto(out *ClusterCloneFrom) {
+	*out = *in
+	out.TidbClusterRef = in.TidbClusterRef
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3StorageProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gcs != nil {
+		in, out := &in.Gcs, &out.Gcs
+		*out = new(GcsStorageProvider)
+		**out = **in
+	}
+	if in.Azblob != nil {
+		in, out := &in.Azblob, &out.Azblob
+		*out = new(AzblobStorageProvider)
+		**out = **in
+	}
+	if in.BR != nil {
+		in, out := &in.BR, &out.BR
+		*out = new(BRConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCloneFrom.
+func (in *ClusterCloneFrom) DeepCopy() *ClusterCloneFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCloneFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCloneStatus) DeepCopyInto(out *ClusterCloneStatus) {
+	*out = *in
+	if in.CompleteTime != nil {
+		in, out := &in.CompleteTime, &out.CompleteTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCloneStatus.
+func (in *ClusterCloneStatus) DeepCopy() *ClusterCloneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCloneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterRef) DeepCopyInto(out *ClusterRef) {
 	*out = *in
@@ -653,9 +1031,31 @@ func (in *ClusterRef) DeepCopy() *ClusterRef {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CommonConfig) DeepCopyInto(out *CommonConfig) {
+func (in *ClusterSuspendStatus) DeepCopyInto(out *ClusterSuspendStatus) {
 	*out = *in
-	if in.TmpPath != nil {
+	if in.SuspendedComponents != nil {
+		in, out := &in.SuspendedComponents, &out.SuspendedComponents
+		*out = make([]MemberType, len(*in))
+		copy(*out, *in)
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSuspendStatus.
+func (in *ClusterSuspendStatus) DeepCopy() *ClusterSuspendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSuspendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonConfig) DeepCopyInto(out *CommonConfig) {
+	*out = *in
+	if in.TmpPath != nil {
 		in, out := &in.TmpPath, &out.TmpPath
 		*out = new(string)
 		**out = **in
@@ -856,6 +1256,27 @@ func (in *CompactRetryRecord) DeepCopy() *CompactRetryRecord {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompactSchedule) DeepCopyInto(out *CompactSchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompactSchedule.
+func (in *CompactSchedule) DeepCopy() *CompactSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(CompactSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompactSpec) DeepCopyInto(out *CompactSpec) {
 	*out = *in
@@ -895,6 +1316,11 @@ func (in *CompactSpec) DeepCopyInto(out *CompactSpec) {
 		*out = new(v1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
 	if in.AdditionalVolumes != nil {
 		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
 		*out = make([]v1.Volume, len(*in))
@@ -973,16 +1399,36 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(v1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AffinityPreset != nil {
+		in, out := &in.AffinityPreset, &out.AffinityPreset
+		*out = new(AffinityPreset)
+		**out = **in
+	}
 	if in.PriorityClassName != nil {
 		in, out := &in.PriorityClassName, &out.PriorityClassName
 		*out = new(string)
 		**out = **in
 	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
 	if in.SchedulerName != nil {
 		in, out := &in.SchedulerName, &out.SchedulerName
 		*out = new(string)
 		**out = **in
 	}
+	if in.SchedulingGates != nil {
+		in, out := &in.SchedulingGates, &out.SchedulingGates
+		*out = make([]v1.PodSchedulingGate, len(*in))
+		copy(*out, *in)
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -1035,6 +1481,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalArgs != nil {
+		in, out := &in.AdditionalArgs, &out.AdditionalArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
 		*out = make([]v1.Container, len(*in))
@@ -1068,6 +1519,13 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(v1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TerminationGracePeriodSeconds != nil {
 		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
 		*out = new(int64)
@@ -1083,6 +1541,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 	if in.SuspendAction != nil {
 		in, out := &in.SuspendAction, &out.SuspendAction
 		*out = new(SuspendAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InjectResourceHints != nil {
+		in, out := &in.InjectResourceHints, &out.InjectResourceHints
+		*out = new(bool)
 		**out = **in
 	}
 	if in.ReadinessProbe != nil {
@@ -1103,6 +1566,43 @@ func (in *ComponentSpec) DeepCopy() *ComponentSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigBackupPolicy) DeepCopyInto(out *ConfigBackupPolicy) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigBackupPolicy.
+func (in *ConfigBackupPolicy) DeepCopy() *ConfigBackupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigBackupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigConflict) DeepCopyInto(out *ConfigConflict) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigConflict.
+func (in *ConfigConflict) DeepCopy() *ConfigConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapRef) DeepCopyInto(out *ConfigMapRef) {
 	*out = *in
@@ -1370,6 +1870,13 @@ func (in *DMClusterSpec) DeepCopyInto(out *DMClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DatabaseTLS != nil {
+		in, out := &in.DatabaseTLS, &out.DatabaseTLS
+		*out = make([]DMDatabaseTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.HostNetwork != nil {
 		in, out := &in.HostNetwork, &out.HostNetwork
 		*out = new(bool)
@@ -1385,6 +1892,21 @@ func (in *DMClusterSpec) DeepCopyInto(out *DMClusterSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SchedulingGates != nil {
+		in, out := &in.SchedulingGates, &out.SchedulingGates
+		*out = make([]v1.PodSchedulingGate, len(*in))
+		copy(*out, *in)
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -1418,6 +1940,13 @@ func (in *DMClusterSpec) DeepCopyInto(out *DMClusterSpec) {
 		*out = new(v1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
 		*out = new(v1.PodSecurityContext)
@@ -1433,6 +1962,11 @@ func (in *DMClusterSpec) DeepCopyInto(out *DMClusterSpec) {
 	if in.SuspendAction != nil {
 		in, out := &in.SuspendAction, &out.SuspendAction
 		*out = new(SuspendAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InjectResourceHints != nil {
+		in, out := &in.InjectResourceHints, &out.InjectResourceHints
+		*out = new(bool)
 		**out = **in
 	}
 	return
@@ -1473,6 +2007,27 @@ func (in *DMClusterStatus) DeepCopy() *DMClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DMDatabaseTLS) DeepCopyInto(out *DMDatabaseTLS) {
+	*out = *in
+	if in.CertAllowedCN != nil {
+		in, out := &in.CertAllowedCN, &out.CertAllowedCN
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DMDatabaseTLS.
+func (in *DMDatabaseTLS) DeepCopy() *DMDatabaseTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(DMDatabaseTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DMDiscoverySpec) DeepCopyInto(out *DMDiscoverySpec) {
 	*out = *in
@@ -1538,6 +2093,58 @@ func (in *DMMonitorSpec) DeepCopy() *DMMonitorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DMRelayPurgePolicy) DeepCopyInto(out *DMRelayPurgePolicy) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Expires != nil {
+		in, out := &in.Expires, &out.Expires
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RemainSpace != nil {
+		in, out := &in.RemainSpace, &out.RemainSpace
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DMRelayPurgePolicy.
+func (in *DMRelayPurgePolicy) DeepCopy() *DMRelayPurgePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DMRelayPurgePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DMRelayStorage) DeepCopyInto(out *DMRelayStorage) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DMRelayStorage.
+func (in *DMRelayStorage) DeepCopy() *DMRelayStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(DMRelayStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DMSecurityConfig) DeepCopyInto(out *DMSecurityConfig) {
 	*out = *in
@@ -1719,6 +2326,11 @@ func (in *DiscoverySpec) DeepCopyInto(out *DiscoverySpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.LivenessProbe != nil {
 		in, out := &in.LivenessProbe, &out.LivenessProbe
 		*out = new(Probe)
@@ -1737,6 +2349,45 @@ func (in *DiscoverySpec) DeepCopy() *DiscoverySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionLock) DeepCopyInto(out *DisruptionLock) {
+	*out = *in
+	in.AcquireTime.DeepCopyInto(&out.AcquireTime)
+	in.RenewTime.DeepCopyInto(&out.RenewTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionLock.
+func (in *DisruptionLock) DeepCopy() *DisruptionLock {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionLock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftProtection) DeepCopyInto(out *DriftProtection) {
+	*out = *in
+	if in.ExemptManagers != nil {
+		in, out := &in.ExemptManagers, &out.ExemptManagers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftProtection.
+func (in *DriftProtection) DeepCopy() *DriftProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DumplingConfig) DeepCopyInto(out *DumplingConfig) {
 	*out = *in
@@ -1823,6 +2474,32 @@ func (in *Experimental) DeepCopy() *Experimental {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalPDSpec) DeepCopyInto(out *ExternalPDSpec) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSClientSecretName != nil {
+		in, out := &in.TLSClientSecretName, &out.TLSClientSecretName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalPDSpec.
+func (in *ExternalPDSpec) DeepCopy() *ExternalPDSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalPDSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Failover) DeepCopyInto(out *Failover) {
 	*out = *in
@@ -2263,6 +2940,27 @@ func (in *FlashUser) DeepCopy() *FlashUser {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPNetworkEndpointGroup) DeepCopyInto(out *GCPNetworkEndpointGroup) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPNetworkEndpointGroup.
+func (in *GCPNetworkEndpointGroup) DeepCopy() *GCPNetworkEndpointGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPNetworkEndpointGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GcsStorageProvider) DeepCopyInto(out *GcsStorageProvider) {
 	*out = *in
@@ -2280,37 +2978,80 @@ func (in *GcsStorageProvider) DeepCopy() *GcsStorageProvider {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
+func (in *GrafanaExportSpec) DeepCopyInto(out *GrafanaExportSpec) {
 	*out = *in
 	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
-	in.Service.DeepCopyInto(&out.Service)
-	if in.UsernameSecret != nil {
-		in, out := &in.UsernameSecret, &out.UsernameSecret
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PasswordSecret != nil {
-		in, out := &in.PasswordSecret, &out.PasswordSecret
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Envs != nil {
-		in, out := &in.Envs, &out.Envs
+	if in.DashboardLabels != nil {
+		in, out := &in.DashboardLabels, &out.DashboardLabels
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Ingress != nil {
-		in, out := &in.Ingress, &out.Ingress
-		*out = new(IngressSpec)
-		(*in).DeepCopyInto(*out)
+	if in.DatasourceLabels != nil {
+		in, out := &in.DatasourceLabels, &out.DatasourceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.AdditionalVolumeMounts != nil {
-		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PrometheusURL != nil {
+		in, out := &in.PrometheusURL, &out.PrometheusURL
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaExportSpec.
+func (in *GrafanaExportSpec) DeepCopy() *GrafanaExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
+	*out = *in
+	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
+	in.Service.DeepCopyInto(&out.Service)
+	if in.UsernameSecret != nil {
+		in, out := &in.UsernameSecret, &out.UsernameSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	return
@@ -2352,6 +3093,64 @@ func (in *HelperSpec) DeepCopy() *HelperSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostNetworkPort) DeepCopyInto(out *HostNetworkPort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostNetworkPort.
+func (in *HostNetworkPort) DeepCopy() *HostNetworkPort {
+	if in == nil {
+		return nil
+	}
+	out := new(HostNetworkPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostNetworkPortConflict) DeepCopyInto(out *HostNetworkPortConflict) {
+	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]HostNetworkPort, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostNetworkPortConflict.
+func (in *HostNetworkPortConflict) DeepCopy() *HostNetworkPortConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(HostNetworkPortConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InClusterObjectStore) DeepCopyInto(out *InClusterObjectStore) {
+	*out = *in
+	if in.Provision != nil {
+		in, out := &in.Provision, &out.Provision
+		*out = new(ObjectStoreProvision)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InClusterObjectStore.
+func (in *InClusterObjectStore) DeepCopy() *InClusterObjectStore {
+	if in == nil {
+		return nil
+	}
+	out := new(InClusterObjectStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
 	*out = *in
@@ -2495,6 +3294,27 @@ func (in *IsolationRead) DeepCopy() *IsolationRead {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaLogOutput) DeepCopyInto(out *KafkaLogOutput) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaLogOutput.
+func (in *KafkaLogOutput) DeepCopy() *KafkaLogOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaLogOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalStorageProvider) DeepCopyInto(out *LocalStorageProvider) {
 	*out = *in
@@ -2589,6 +3409,68 @@ func (in *Log) DeepCopy() *Log {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogRotationSpec) DeepCopyInto(out *LogRotationSpec) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxDays != nil {
+		in, out := &in.MaxDays, &out.MaxDays
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxBackups != nil {
+		in, out := &in.MaxBackups, &out.MaxBackups
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogRotationSpec.
+func (in *LogRotationSpec) DeepCopy() *LogRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogShippingOutput) DeepCopyInto(out *LogShippingOutput) {
+	*out = *in
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(LokiLogOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3LogOutput)
+		**out = **in
+	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaLogOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogShippingOutput.
+func (in *LogShippingOutput) DeepCopy() *LogShippingOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(LogShippingOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogSubCommandStatus) DeepCopyInto(out *LogSubCommandStatus) {
 	*out = *in
@@ -2631,6 +3513,60 @@ func (in *LogTailerSpec) DeepCopy() *LogTailerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogVolumeSpec) DeepCopyInto(out *LogVolumeSpec) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(LogRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tailer != nil {
+		in, out := &in.Tailer, &out.Tailer
+		*out = new(LogTailerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogVolumeSpec.
+func (in *LogVolumeSpec) DeepCopy() *LogVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiLogOutput) DeepCopyInto(out *LokiLogOutput) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiLogOutput.
+func (in *LokiLogOutput) DeepCopy() *LokiLogOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(LokiLogOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MasterConfig) DeepCopyInto(out *MasterConfig) {
 	*out = *in
@@ -2972,6 +3908,38 @@ func (in *MonitorContainer) DeepCopy() *MonitorContainer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NGMonitoringCleanupSpec) DeepCopyInto(out *NGMonitoringCleanupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NGMonitoringCleanupSpec.
+func (in *NGMonitoringCleanupSpec) DeepCopy() *NGMonitoringCleanupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NGMonitoringCleanupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NGMonitoringRetentionSpec) DeepCopyInto(out *NGMonitoringRetentionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NGMonitoringRetentionSpec.
+func (in *NGMonitoringRetentionSpec) DeepCopy() *NGMonitoringRetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NGMonitoringRetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NGMonitoringSpec) DeepCopyInto(out *NGMonitoringSpec) {
 	*out = *in
@@ -2989,6 +3957,16 @@ func (in *NGMonitoringSpec) DeepCopyInto(out *NGMonitoringSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(NGMonitoringRetentionSpec)
+		**out = **in
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(NGMonitoringCleanupSpec)
+		**out = **in
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -3014,6 +3992,11 @@ func (in *NGMonitoringStatus) DeepCopyInto(out *NGMonitoringStatus) {
 		*out = new(appsv1.StatefulSetStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(NGMonitoringStorageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3027,6 +4010,32 @@ func (in *NGMonitoringStatus) DeepCopy() *NGMonitoringStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NGMonitoringStorageStatus) DeepCopyInto(out *NGMonitoringStorageStatus) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LastCleanupTime != nil {
+		in, out := &in.LastCleanupTime, &out.LastCleanupTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NGMonitoringStorageStatus.
+func (in *NGMonitoringStorageStatus) DeepCopy() *NGMonitoringStorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NGMonitoringStorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Networks) DeepCopyInto(out *Networks) {
 	*out = *in
@@ -3049,38 +4058,81 @@ func (in *Networks) DeepCopy() *Networks {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservedStorageVolumeStatus) DeepCopyInto(out *ObservedStorageVolumeStatus) {
+func (in *NodeDrainPolicy) DeepCopyInto(out *NodeDrainPolicy) {
 	*out = *in
-	out.CurrentCapacity = in.CurrentCapacity.DeepCopy()
-	out.ModifiedCapacity = in.ModifiedCapacity.DeepCopy()
-	out.ResizedCapacity = in.ResizedCapacity.DeepCopy()
+	if in.TaintKeys != nil {
+		in, out := &in.TaintKeys, &out.TaintKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedStorageVolumeStatus.
-func (in *ObservedStorageVolumeStatus) DeepCopy() *ObservedStorageVolumeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainPolicy.
+func (in *NodeDrainPolicy) DeepCopy() *NodeDrainPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservedStorageVolumeStatus)
+	out := new(NodeDrainPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenTracing) DeepCopyInto(out *OpenTracing) {
+func (in *ObjectStoreProvision) DeepCopyInto(out *ObjectStoreProvision) {
 	*out = *in
-	if in.Enable != nil {
-		in, out := &in.Enable, &out.Enable
-		*out = new(bool)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.Sampler != nil {
-		in, out := &in.Sampler, &out.Sampler
-		*out = new(OpenTracingSampler)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Reporter != nil {
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreProvision.
+func (in *ObjectStoreProvision) DeepCopy() *ObjectStoreProvision {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreProvision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedStorageVolumeStatus) DeepCopyInto(out *ObservedStorageVolumeStatus) {
+	*out = *in
+	out.CurrentCapacity = in.CurrentCapacity.DeepCopy()
+	out.ModifiedCapacity = in.ModifiedCapacity.DeepCopy()
+	out.ResizedCapacity = in.ResizedCapacity.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedStorageVolumeStatus.
+func (in *ObservedStorageVolumeStatus) DeepCopy() *ObservedStorageVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedStorageVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenTracing) DeepCopyInto(out *OpenTracing) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Sampler != nil {
+		in, out := &in.Sampler, &out.Sampler
+		*out = new(OpenTracingSampler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reporter != nil {
 		in, out := &in.Reporter, &out.Reporter
 		*out = new(OpenTracingReporter)
 		(*in).DeepCopyInto(*out)
@@ -3406,6 +4458,27 @@ func (in PDLabelPropertyConfig) DeepCopy() PDLabelPropertyConfig {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDLeaderPriority) DeepCopyInto(out *PDLeaderPriority) {
+	*out = *in
+	if in.Ordinals != nil {
+		in, out := &in.Ordinals, &out.Ordinals
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDLeaderPriority.
+func (in *PDLeaderPriority) DeepCopy() *PDLeaderPriority {
+	if in == nil {
+		return nil
+	}
+	out := new(PDLeaderPriority)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PDLogConfig) DeepCopyInto(out *PDLogConfig) {
 	*out = *in
@@ -3997,6 +5070,11 @@ func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LogVolume != nil {
+		in, out := &in.LogVolume, &out.LogVolume
+		*out = new(LogVolumeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = new(PDConfigWraper)
@@ -4022,6 +5100,13 @@ func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.LeaderPriorities != nil {
+		in, out := &in.LeaderPriorities, &out.LeaderPriorities
+		*out = make([]PDLeaderPriority, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -4072,6 +5157,11 @@ func (in *PDStatus) DeepCopyInto(out *PDStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Learners != nil {
+		in, out := &in.Learners, &out.Learners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Volumes != nil {
 		in, out := &in.Volumes, &out.Volumes
 		*out = make(map[StorageVolumeName]*StorageVolumeStatus, len(*in))
@@ -4155,6 +5245,41 @@ func (in PDStoreLabels) DeepCopy() PDStoreLabels {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PITRReplicationSpec) DeepCopyInto(out *PITRReplicationSpec) {
+	*out = *in
+	in.LogBackupTemplate.DeepCopyInto(&out.LogBackupTemplate)
+	in.RestoreTemplate.DeepCopyInto(&out.RestoreTemplate)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITRReplicationSpec.
+func (in *PITRReplicationSpec) DeepCopy() *PITRReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PITRReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotation) DeepCopyInto(out *PasswordRotation) {
+	*out = *in
+	in.LastRotationTime.DeepCopyInto(&out.LastRotationTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotation.
+func (in *PasswordRotation) DeepCopy() *PasswordRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Performance) DeepCopyInto(out *Performance) {
 	*out = *in
@@ -4282,6 +5407,22 @@ func (in *PessimisticTxn) DeepCopy() *PessimisticTxn {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRuleRef) DeepCopyInto(out *PlacementRuleRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRuleRef.
+func (in *PlacementRuleRef) DeepCopy() *PlacementRuleRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRuleRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlanCache) DeepCopyInto(out *PlanCache) {
 	*out = *in
@@ -4437,6 +5578,46 @@ func (in *Profile) DeepCopy() *Profile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileCaptureSpec) DeepCopyInto(out *ProfileCaptureSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.StorageProvider.DeepCopyInto(&out.StorageProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileCaptureSpec.
+func (in *ProfileCaptureSpec) DeepCopy() *ProfileCaptureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileCaptureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileCaptureStatus) DeepCopyInto(out *ProfileCaptureStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileCaptureStatus.
+func (in *ProfileCaptureStatus) DeepCopy() *ProfileCaptureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileCaptureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Progress) DeepCopyInto(out *Progress) {
 	*out = *in
@@ -4485,6 +5666,34 @@ func (in *PrometheusConfiguration) DeepCopy() *PrometheusConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusMonitorSpec) DeepCopyInto(out *PrometheusMonitorSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusMonitorSpec.
+func (in *PrometheusMonitorSpec) DeepCopy() *PrometheusMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusReloaderSpec) DeepCopyInto(out *PrometheusReloaderSpec) {
 	*out = *in
@@ -4553,6 +5762,32 @@ func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagatePolicy) DeepCopyInto(out *PropagatePolicy) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagatePolicy.
+func (in *PropagatePolicy) DeepCopy() *PropagatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
 	*out = *in
@@ -4671,103 +5906,267 @@ func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PumpNodeStatus) DeepCopyInto(out *PumpNodeStatus) {
+func (in *PumpMigration) DeepCopyInto(out *PumpMigration) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpNodeStatus.
-func (in *PumpNodeStatus) DeepCopy() *PumpNodeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMigration.
+func (in *PumpMigration) DeepCopy() *PumpMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(PumpNodeStatus)
+	out := new(PumpMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PumpMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PumpSpec) DeepCopyInto(out *PumpSpec) {
+func (in *PumpMigrationChangefeed) DeepCopyInto(out *PumpMigrationChangefeed) {
 	*out = *in
-	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
-	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
-		**out = **in
-	}
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = (*in).DeepCopy()
-	}
-	if in.SetTimeZone != nil {
-		in, out := &in.SetTimeZone, &out.SetTimeZone
-		*out = new(bool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpSpec.
-func (in *PumpSpec) DeepCopy() *PumpSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMigrationChangefeed.
+func (in *PumpMigrationChangefeed) DeepCopy() *PumpMigrationChangefeed {
 	if in == nil {
 		return nil
 	}
-	out := new(PumpSpec)
+	out := new(PumpMigrationChangefeed)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PumpStatus) DeepCopyInto(out *PumpStatus) {
+func (in *PumpMigrationChangefeedStatus) DeepCopyInto(out *PumpMigrationChangefeedStatus) {
 	*out = *in
-	if in.StatefulSet != nil {
-		in, out := &in.StatefulSet, &out.StatefulSet
-		*out = new(appsv1.StatefulSetStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Members != nil {
-		in, out := &in.Members, &out.Members
-		*out = make([]*PumpNodeStatus, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(PumpNodeStatus)
-				**out = **in
-			}
-		}
-	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make(map[StorageVolumeName]*StorageVolumeStatus, len(*in))
-		for key, val := range *in {
-			var outVal *StorageVolumeStatus
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = new(StorageVolumeStatus)
-				(*in).DeepCopyInto(*out)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.CheckpointTime != nil {
+		in, out := &in.CheckpointTime, &out.CheckpointTime
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpStatus.
-func (in *PumpStatus) DeepCopy() *PumpStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMigrationChangefeedStatus.
+func (in *PumpMigrationChangefeedStatus) DeepCopy() *PumpMigrationChangefeedStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PumpStatus)
+	out := new(PumpMigrationChangefeedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpMigrationList) DeepCopyInto(out *PumpMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PumpMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMigrationList.
+func (in *PumpMigrationList) DeepCopy() *PumpMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PumpMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpMigrationSpec) DeepCopyInto(out *PumpMigrationSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.TiCDC != nil {
+		in, out := &in.TiCDC, &out.TiCDC
+		*out = new(TiCDCSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Changefeeds != nil {
+		in, out := &in.Changefeeds, &out.Changefeeds
+		*out = make([]PumpMigrationChangefeed, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxCatchUpLag != nil {
+		in, out := &in.MaxCatchUpLag, &out.MaxCatchUpLag
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMigrationSpec.
+func (in *PumpMigrationSpec) DeepCopy() *PumpMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpMigrationStatus) DeepCopyInto(out *PumpMigrationStatus) {
+	*out = *in
+	if in.Changefeeds != nil {
+		in, out := &in.Changefeeds, &out.Changefeeds
+		*out = make([]PumpMigrationChangefeedStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CaughtUpTime != nil {
+		in, out := &in.CaughtUpTime, &out.CaughtUpTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BinlogDisabledTime != nil {
+		in, out := &in.BinlogDisabledTime, &out.BinlogDisabledTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompleteTime != nil {
+		in, out := &in.CompleteTime, &out.CompleteTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMigrationStatus.
+func (in *PumpMigrationStatus) DeepCopy() *PumpMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpNodeStatus) DeepCopyInto(out *PumpNodeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpNodeStatus.
+func (in *PumpNodeStatus) DeepCopy() *PumpNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpSpec) DeepCopyInto(out *PumpSpec) {
+	*out = *in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+	if in.SetTimeZone != nil {
+		in, out := &in.SetTimeZone, &out.SetTimeZone
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpSpec.
+func (in *PumpSpec) DeepCopy() *PumpSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpStatus) DeepCopyInto(out *PumpStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]*PumpNodeStatus, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(PumpNodeStatus)
+				**out = **in
+			}
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make(map[StorageVolumeName]*StorageVolumeStatus, len(*in))
+		for key, val := range *in {
+			var outVal *StorageVolumeStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(StorageVolumeStatus)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpStatus.
+func (in *PumpStatus) DeepCopy() *PumpStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -4918,6 +6317,149 @@ func (in *RemoteWriteSpec) DeepCopy() *RemoteWriteSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDrift) DeepCopyInto(out *ResourceDrift) {
+	*out = *in
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MutateTime != nil {
+		in, out := &in.MutateTime, &out.MutateTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDrift.
+func (in *ResourceDrift) DeepCopy() *ResourceDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	if in.PeakUsage != nil {
+		in, out := &in.PeakUsage, &out.PeakUsage
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.PeakTime != nil {
+		in, out := &in.PeakTime, &out.PeakTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastApplyTime != nil {
+		in, out := &in.LastApplyTime, &out.LastApplyTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendationAutoApply) DeepCopyInto(out *ResourceRecommendationAutoApply) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinChangePercent != nil {
+		in, out := &in.MinChangePercent, &out.MinChangePercent
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendationAutoApply.
+func (in *ResourceRecommendationAutoApply) DeepCopy() *ResourceRecommendationAutoApply {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendationAutoApply)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendationPolicy) DeepCopyInto(out *ResourceRecommendationPolicy) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]MemberType, len(*in))
+		copy(*out, *in)
+	}
+	if in.MarginPercent != nil {
+		in, out := &in.MarginPercent, &out.MarginPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.AutoApply != nil {
+		in, out := &in.AutoApply, &out.AutoApply
+		*out = new(ResourceRecommendationAutoApply)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendationPolicy.
+func (in *ResourceRecommendationPolicy) DeepCopy() *ResourceRecommendationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Restore) DeepCopyInto(out *Restore) {
 	*out = *in
@@ -4963,6 +6505,51 @@ func (in *RestoreCondition) DeepCopy() *RestoreCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreIntegrityCheckSpec) DeepCopyInto(out *RestoreIntegrityCheckSpec) {
+	*out = *in
+	if in.TableFilter != nil {
+		in, out := &in.TableFilter, &out.TableFilter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreIntegrityCheckSpec.
+func (in *RestoreIntegrityCheckSpec) DeepCopy() *RestoreIntegrityCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreIntegrityCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreIntegrityCheckStatus) DeepCopyInto(out *RestoreIntegrityCheckStatus) {
+	*out = *in
+	if in.Mismatches != nil {
+		in, out := &in.Mismatches, &out.Mismatches
+		*out = make([]TableChecksumMismatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.TimeCompleted.DeepCopyInto(&out.TimeCompleted)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreIntegrityCheckStatus.
+func (in *RestoreIntegrityCheckStatus) DeepCopy() *RestoreIntegrityCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreIntegrityCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestoreList) DeepCopyInto(out *RestoreList) {
 	*out = *in
@@ -5056,6 +6643,11 @@ func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
 		*out = new(v1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
 	if in.AdditionalVolumes != nil {
 		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
 		*out = make([]v1.Volume, len(*in))
@@ -5070,6 +6662,16 @@ func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IntegrityCheck != nil {
+		in, out := &in.IntegrityCheck, &out.IntegrityCheck
+		*out = new(RestoreIntegrityCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(BRNotifications)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -5102,6 +6704,23 @@ func (in *RestoreStatus) DeepCopyInto(out *RestoreStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IntegrityCheck != nil {
+		in, out := &in.IntegrityCheck, &out.IntegrityCheck
+		*out = new(RestoreIntegrityCheckStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VersionCompatibility != nil {
+		in, out := &in.VersionCompatibility, &out.VersionCompatibility
+		*out = new(RestoreVersionCompatibility)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobSummaries != nil {
+		in, out := &in.JobSummaries, &out.JobSummaries
+		*out = make([]BRJobSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -5115,6 +6734,69 @@ func (in *RestoreStatus) DeepCopy() *RestoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreVersionCompatibility) DeepCopyInto(out *RestoreVersionCompatibility) {
+	*out = *in
+	if in.BROptions != nil {
+		in, out := &in.BROptions, &out.BROptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreVersionCompatibility.
+func (in *RestoreVersionCompatibility) DeepCopy() *RestoreVersionCompatibility {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreVersionCompatibility)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdateStrategy) DeepCopyInto(out *RollingUpdateStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateStrategy.
+func (in *RollingUpdateStrategy) DeepCopy() *RollingUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3LogOutput) DeepCopyInto(out *S3LogOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3LogOutput.
+func (in *S3LogOutput) DeepCopy() *S3LogOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(S3LogOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3StorageProvider) DeepCopyInto(out *S3StorageProvider) {
 	*out = *in
@@ -5128,6 +6810,11 @@ func (in *S3StorageProvider) DeepCopyInto(out *S3StorageProvider) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.InCluster != nil {
+		in, out := &in.InCluster, &out.InCluster
+		*out = new(InClusterObjectStore)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -5164,6 +6851,81 @@ func (in *SafeTLSConfig) DeepCopy() *SafeTLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleOutBalancePolicy) DeepCopyInto(out *ScaleOutBalancePolicy) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StoreLimit != nil {
+		in, out := &in.StoreLimit, &out.StoreLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RegionScheduleLimit != nil {
+		in, out := &in.RegionScheduleLimit, &out.RegionScheduleLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BalancedPercent != nil {
+		in, out := &in.BalancedPercent, &out.BalancedPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleOutBalancePolicy.
+func (in *ScaleOutBalancePolicy) DeepCopy() *ScaleOutBalancePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleOutBalancePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleOutBalanceStatus) DeepCopyInto(out *ScaleOutBalanceStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.BaselineStores != nil {
+		in, out := &in.BaselineStores, &out.BaselineStores
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StoreLimits != nil {
+		in, out := &in.StoreLimits, &out.StoreLimits
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RegionScheduleLimit != nil {
+		in, out := &in.RegionScheduleLimit, &out.RegionScheduleLimit
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleOutBalanceStatus.
+func (in *ScaleOutBalanceStatus) DeepCopy() *ScaleOutBalanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleOutBalanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScalePolicy) DeepCopyInto(out *ScalePolicy) {
 	*out = *in
@@ -5272,6 +7034,45 @@ func (in *Security) DeepCopy() *Security {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTestCheck) DeepCopyInto(out *SelfTestCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTestCheck.
+func (in *SelfTestCheck) DeepCopy() *SelfTestCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTestCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTestReport) DeepCopyInto(out *SelfTestReport) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]SelfTestCheck, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTestReport.
+func (in *SelfTestReport) DeepCopy() *SelfTestReport {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTestReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -5452,6 +7253,22 @@ func (in *StorageClaim) DeepCopy() *StorageClaim {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClassTransition) DeepCopyInto(out *StorageClassTransition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassTransition.
+func (in *StorageClassTransition) DeepCopy() *StorageClassTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClassTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageProvider) DeepCopyInto(out *StorageProvider) {
 	*out = *in
@@ -5526,9 +7343,57 @@ func (in *StorageVolumeStatus) DeepCopy() *StorageVolumeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StuckUpgradeReport) DeepCopyInto(out *StuckUpgradeReport) {
+	*out = *in
+	if in.RegionIDsAtRisk != nil {
+		in, out := &in.RegionIDsAtRisk, &out.RegionIDsAtRisk
+		*out = make([]uint64, len(*in))
+		copy(*out, *in)
+	}
+	in.ReportTime.DeepCopyInto(&out.ReportTime)
+	if in.ForcedTime != nil {
+		in, out := &in.ForcedTime, &out.ForcedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StuckUpgradeReport.
+func (in *StuckUpgradeReport) DeepCopy() *StuckUpgradeReport {
+	if in == nil {
+		return nil
+	}
+	out := new(StuckUpgradeReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SuggestedAction) DeepCopyInto(out *SuggestedAction) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuggestedAction.
+func (in *SuggestedAction) DeepCopy() *SuggestedAction {
+	if in == nil {
+		return nil
+	}
+	out := new(SuggestedAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SuspendAction) DeepCopyInto(out *SuspendAction) {
 	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -5542,6 +7407,40 @@ func (in *SuspendAction) DeepCopy() *SuspendAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncRecord) DeepCopyInto(out *SyncRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make(map[MemberType]MemberPhase, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncRecord.
+func (in *SyncRecord) DeepCopy() *SyncRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSCluster) DeepCopyInto(out *TLSCluster) {
 	*out = *in
@@ -5575,6 +7474,116 @@ func (in *TLSConfig) DeepCopy() *TLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TableChecksum) DeepCopyInto(out *TableChecksum) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TableChecksum.
+func (in *TableChecksum) DeepCopy() *TableChecksum {
+	if in == nil {
+		return nil
+	}
+	out := new(TableChecksum)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TableChecksumMismatch) DeepCopyInto(out *TableChecksumMismatch) {
+	*out = *in
+	out.Expected = in.Expected
+	if in.Actual != nil {
+		in, out := &in.Actual, &out.Actual
+		*out = new(TableChecksum)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TableChecksumMismatch.
+func (in *TableChecksumMismatch) DeepCopy() *TableChecksumMismatch {
+	if in == nil {
+		return nil
+	}
+	out := new(TableChecksumMismatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosComponentSpec) DeepCopyInto(out *ThanosComponentSpec) {
+	*out = *in
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosComponentSpec.
+func (in *ThanosComponentSpec) DeepCopy() *ThanosComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosQuerySpec) DeepCopyInto(out *ThanosQuerySpec) {
+	*out = *in
+	in.ThanosComponentSpec.DeepCopyInto(&out.ThanosComponentSpec)
+	if in.ReplicaLabels != nil {
+		in, out := &in.ReplicaLabels, &out.ReplicaLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQuerySpec.
+func (in *ThanosQuerySpec) DeepCopy() *ThanosQuerySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosQuerySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosRulerSpec) DeepCopyInto(out *ThanosRulerSpec) {
+	*out = *in
+	in.ThanosComponentSpec.DeepCopyInto(&out.ThanosComponentSpec)
+	if in.RuleConfigMaps != nil {
+		in, out := &in.RuleConfigMaps, &out.RuleConfigMaps
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosRulerSpec.
+func (in *ThanosRulerSpec) DeepCopy() *ThanosRulerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosRulerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThanosSpec) DeepCopyInto(out *ThanosSpec) {
 	*out = *in
@@ -5611,6 +7620,21 @@ func (in *ThanosSpec) DeepCopyInto(out *ThanosSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(ThanosQuerySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Store != nil {
+		in, out := &in.Store, &out.Store
+		*out = new(ThanosComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ruler != nil {
+		in, out := &in.Ruler, &out.Ruler
+		*out = new(ThanosRulerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -5676,6 +7700,22 @@ func (in *TiCDCConfig) DeepCopy() *TiCDCConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiCDCReplicationSpec) DeepCopyInto(out *TiCDCReplicationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCReplicationSpec.
+func (in *TiCDCReplicationSpec) DeepCopy() *TiCDCReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiCDCReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiCDCSpec) DeepCopyInto(out *TiCDCSpec) {
 	*out = *in
@@ -5792,6 +7832,67 @@ func (in *TiDBAccessConfig) DeepCopy() *TiDBAccessConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBCanaryStatus) DeepCopyInto(out *TiDBCanaryStatus) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakStartTime != nil {
+		in, out := &in.SoakStartTime, &out.SoakStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBCanaryStatus.
+func (in *TiDBCanaryStatus) DeepCopy() *TiDBCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBCanaryStrategy) DeepCopyInto(out *TiDBCanaryStrategy) {
+	*out = *in
+	out.SoakDuration = in.SoakDuration
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LatencySLO != nil {
+		in, out := &in.LatencySLO, &out.LatencySLO
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxErrorPercent != nil {
+		in, out := &in.MaxErrorPercent, &out.MaxErrorPercent
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBCanaryStrategy.
+func (in *TiDBCanaryStrategy) DeepCopy() *TiDBCanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBCanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiDBConfig) DeepCopyInto(out *TiDBConfig) {
 	*out = *in
@@ -6088,6 +8189,63 @@ func (in *TiDBInitializer) DeepCopy() *TiDBInitializer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBLoadBalancerReadiness) DeepCopyInto(out *TiDBLoadBalancerReadiness) {
+	*out = *in
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSTargetGroup)
+		**out = **in
+	}
+	if in.GCP != nil {
+		in, out := &in.GCP, &out.GCP
+		*out = new(GCPNetworkEndpointGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBLoadBalancerReadiness.
+func (in *TiDBLoadBalancerReadiness) DeepCopy() *TiDBLoadBalancerReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBLoadBalancerReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBLogShippingSpec) DeepCopyInto(out *TiDBLogShippingSpec) {
+	*out = *in
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]LogShippingOutput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBLogShippingSpec.
+func (in *TiDBLogShippingSpec) DeepCopy() *TiDBLogShippingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBLogShippingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiDBMember) DeepCopyInto(out *TiDBMember) {
 	*out = *in
@@ -6095,12 +8253,43 @@ func (in *TiDBMember) DeepCopyInto(out *TiDBMember) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBMember.
-func (in *TiDBMember) DeepCopy() *TiDBMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBMember.
+func (in *TiDBMember) DeepCopy() *TiDBMember {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBService) DeepCopyInto(out *TiDBService) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBService.
+func (in *TiDBService) DeepCopy() *TiDBService {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBMember)
+	out := new(TiDBService)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -6186,6 +8375,13 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		*out = new(TiDBServiceSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]TiDBService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.BinlogEnabled != nil {
 		in, out := &in.BinlogEnabled, &out.BinlogEnabled
 		*out = new(bool)
@@ -6206,6 +8402,16 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		*out = new(TiDBSlowLogTailerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LogShipping != nil {
+		in, out := &in.LogShipping, &out.LogShipping
+		*out = new(TiDBLogShippingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogVolume != nil {
+		in, out := &in.LogVolume, &out.LogVolume
+		*out = new(LogVolumeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TLSClient != nil {
 		in, out := &in.TLSClient, &out.TLSClient
 		*out = new(TiDBTLSClient)
@@ -6254,6 +8460,21 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		**out = **in
 	}
 	in.ScalePolicy.DeepCopyInto(&out.ScalePolicy)
+	if in.RollingUpdateStrategy != nil {
+		in, out := &in.RollingUpdateStrategy, &out.RollingUpdateStrategy
+		*out = new(RollingUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(TiDBCanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadBalancerReadiness != nil {
+		in, out := &in.LoadBalancerReadiness, &out.LoadBalancerReadiness
+		*out = new(TiDBLoadBalancerReadiness)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CustomizedStartupProbe != nil {
 		in, out := &in.CustomizedStartupProbe, &out.CustomizedStartupProbe
 		*out = new(CustomizedProbe)
@@ -6333,6 +8554,11 @@ func (in *TiDBStatus) DeepCopyInto(out *TiDBStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(TiDBCanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -6454,6 +8680,27 @@ func (in *TiFlashProxyConfigWraper) DeepCopy() *TiFlashProxyConfigWraper {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiFlashScaleInPreflight) DeepCopyInto(out *TiFlashScaleInPreflight) {
+	*out = *in
+	if in.TLSClientSecretName != nil {
+		in, out := &in.TLSClientSecretName, &out.TLSClientSecretName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiFlashScaleInPreflight.
+func (in *TiFlashScaleInPreflight) DeepCopy() *TiFlashScaleInPreflight {
+	if in == nil {
+		return nil
+	}
+	out := new(TiFlashScaleInPreflight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiFlashSpec) DeepCopyInto(out *TiFlashSpec) {
 	*out = *in
@@ -6497,6 +8744,16 @@ func (in *TiFlashSpec) DeepCopyInto(out *TiFlashSpec) {
 		**out = **in
 	}
 	in.ScalePolicy.DeepCopyInto(&out.ScalePolicy)
+	if in.TableReplicas != nil {
+		in, out := &in.TableReplicas, &out.TableReplicas
+		*out = new(TiFlashTableReplicas)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleInPreflight != nil {
+		in, out := &in.ScaleInPreflight, &out.ScaleInPreflight
+		*out = new(TiFlashScaleInPreflight)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -6568,6 +8825,11 @@ func (in *TiFlashStatus) DeepCopyInto(out *TiFlashStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TableReplicas != nil {
+		in, out := &in.TableReplicas, &out.TableReplicas
+		*out = make([]TiFlashTableReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -6581,6 +8843,80 @@ func (in *TiFlashStatus) DeepCopy() *TiFlashStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiFlashTableReplica) DeepCopyInto(out *TiFlashTableReplica) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiFlashTableReplica.
+func (in *TiFlashTableReplica) DeepCopy() *TiFlashTableReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(TiFlashTableReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiFlashTableReplicaStatus) DeepCopyInto(out *TiFlashTableReplicaStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiFlashTableReplicaStatus.
+func (in *TiFlashTableReplicaStatus) DeepCopy() *TiFlashTableReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiFlashTableReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiFlashTableReplicas) DeepCopyInto(out *TiFlashTableReplicas) {
+	*out = *in
+	if in.TLSClientSecretName != nil {
+		in, out := &in.TLSClientSecretName, &out.TLSClientSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]TiFlashTableReplica, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiFlashTableReplicas.
+func (in *TiFlashTableReplicas) DeepCopy() *TiFlashTableReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(TiFlashTableReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVAWSKMSMasterKey) DeepCopyInto(out *TiKVAWSKMSMasterKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVAWSKMSMasterKey.
+func (in *TiKVAWSKMSMasterKey) DeepCopy() *TiKVAWSKMSMasterKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVAWSKMSMasterKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVBackupConfig) DeepCopyInto(out *TiKVBackupConfig) {
 	*out = *in
@@ -7360,6 +9696,53 @@ func (in *TiKVEncryptionConfig) DeepCopy() *TiKVEncryptionConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVEncryptionSpec) DeepCopyInto(out *TiKVEncryptionSpec) {
+	*out = *in
+	in.MasterKey.DeepCopyInto(&out.MasterKey)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVEncryptionSpec.
+func (in *TiKVEncryptionSpec) DeepCopy() *TiKVEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVEncryptionStatus) DeepCopyInto(out *TiKVEncryptionStatus) {
+	*out = *in
+	in.MasterKey.DeepCopyInto(&out.MasterKey)
+	if in.PreviousMasterKey != nil {
+		in, out := &in.PreviousMasterKey, &out.PreviousMasterKey
+		*out = new(TiKVMasterKeySource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastRotationStartTime != nil {
+		in, out := &in.LastRotationStartTime, &out.LastRotationStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRotationCompletionTime != nil {
+		in, out := &in.LastRotationCompletionTime, &out.LastRotationCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVEncryptionStatus.
+func (in *TiKVEncryptionStatus) DeepCopy() *TiKVEncryptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVEncryptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVFailureStore) DeepCopyInto(out *TiKVFailureStore) {
 	*out = *in
@@ -7420,6 +9803,22 @@ func (in *TiKVGCConfig) DeepCopy() *TiKVGCConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVGCPKMSMasterKey) DeepCopyInto(out *TiKVGCPKMSMasterKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVGCPKMSMasterKey.
+func (in *TiKVGCPKMSMasterKey) DeepCopy() *TiKVGCPKMSMasterKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVGCPKMSMasterKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVImportConfig) DeepCopyInto(out *TiKVImportConfig) {
 	*out = *in
@@ -7504,6 +9903,42 @@ func (in *TiKVMasterKeyConfig) DeepCopy() *TiKVMasterKeyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVMasterKeySource) DeepCopyInto(out *TiKVMasterKeySource) {
+	*out = *in
+	if in.AWSKMS != nil {
+		in, out := &in.AWSKMS, &out.AWSKMS
+		*out = new(TiKVAWSKMSMasterKey)
+		**out = **in
+	}
+	if in.GCPKMS != nil {
+		in, out := &in.GCPKMS, &out.GCPKMS
+		*out = new(TiKVGCPKMSMasterKey)
+		**out = **in
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(TiKVVaultMasterKey)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(TiKVSecretMasterKey)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVMasterKeySource.
+func (in *TiKVMasterKeySource) DeepCopy() *TiKVMasterKeySource {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVMasterKeySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVPDConfig) DeepCopyInto(out *TiKVPDConfig) {
 	*out = *in
@@ -8029,6 +10464,22 @@ func (in *TiKVReadPoolConfig) DeepCopy() *TiKVReadPoolConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVSecretMasterKey) DeepCopyInto(out *TiKVSecretMasterKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVSecretMasterKey.
+func (in *TiKVSecretMasterKey) DeepCopy() *TiKVSecretMasterKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVSecretMasterKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVSecurityConfig) DeepCopyInto(out *TiKVSecurityConfig) {
 	*out = *in
@@ -8315,6 +10766,37 @@ func (in *TiKVServerConfig) DeepCopy() *TiKVServerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVSlowStoreDetection) DeepCopyInto(out *TiKVSlowStoreDetection) {
+	*out = *in
+	if in.SlowScoreThreshold != nil {
+		in, out := &in.SlowScoreThreshold, &out.SlowScoreThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxMitigatedStores != nil {
+		in, out := &in.MaxMitigatedStores, &out.MaxMitigatedStores
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVSlowStoreDetection.
+func (in *TiKVSlowStoreDetection) DeepCopy() *TiKVSlowStoreDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVSlowStoreDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 	*out = *in
@@ -8345,6 +10827,11 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		*out = new(LogTailerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LogVolume != nil {
+		in, out := &in.LogVolume, &out.LogVolume
+		*out = new(LogVolumeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -8387,12 +10874,44 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.StoreWeights != nil {
+		in, out := &in.StoreWeights, &out.StoreWeights
+		*out = make([]TiKVStoreWeight, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SlowStoreDetection != nil {
+		in, out := &in.SlowStoreDetection, &out.SlowStoreDetection
+		*out = new(TiKVSlowStoreDetection)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ScalePolicy.DeepCopyInto(&out.ScalePolicy)
+	if in.RollingUpdateStrategy != nil {
+		in, out := &in.RollingUpdateStrategy, &out.RollingUpdateStrategy
+		*out = new(RollingUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SpareVolReplaceReplicas != nil {
 		in, out := &in.SpareVolReplaceReplicas, &out.SpareVolReplaceReplicas
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Witness != nil {
+		in, out := &in.Witness, &out.Witness
+		*out = new(TiKVWitnessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleOutBalancePolicy != nil {
+		in, out := &in.ScaleOutBalancePolicy, &out.ScaleOutBalancePolicy
+		*out = new(ScaleOutBalancePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(TiKVEncryptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8479,6 +10998,21 @@ func (in *TiKVStatus) DeepCopyInto(out *TiKVStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Witness != nil {
+		in, out := &in.Witness, &out.Witness
+		*out = new(TiKVWitnessStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleOutBalance != nil {
+		in, out := &in.ScaleOutBalance, &out.ScaleOutBalance
+		*out = new(ScaleOutBalanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(TiKVEncryptionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8608,15 +11142,57 @@ func (in *TiKVStore) DeepCopyInto(out *TiKVStore) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.SlowSince != nil {
+		in, out := &in.SlowSince, &out.SlowSince
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVStore.
+func (in *TiKVStore) DeepCopy() *TiKVStore {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVStoreWeight) DeepCopyInto(out *TiKVStoreWeight) {
+	*out = *in
+	if in.PodNames != nil {
+		in, out := &in.PodNames, &out.PodNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LeaderWeight != nil {
+		in, out := &in.LeaderWeight, &out.LeaderWeight
+		*out = new(float64)
+		**out = **in
+	}
+	if in.RegionWeight != nil {
+		in, out := &in.RegionWeight, &out.RegionWeight
+		*out = new(float64)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVStore.
-func (in *TiKVStore) DeepCopy() *TiKVStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVStoreWeight.
+func (in *TiKVStoreWeight) DeepCopy() *TiKVStoreWeight {
 	if in == nil {
 		return nil
 	}
-	out := new(TiKVStore)
+	out := new(TiKVStoreWeight)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -8769,6 +11345,72 @@ func (in *TiKVUnifiedReadPoolConfig) DeepCopy() *TiKVUnifiedReadPoolConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVVaultMasterKey) DeepCopyInto(out *TiKVVaultMasterKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVVaultMasterKey.
+func (in *TiKVVaultMasterKey) DeepCopy() *TiKVVaultMasterKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVVaultMasterKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVWitnessSpec) DeepCopyInto(out *TiKVWitnessSpec) {
+	*out = *in
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVWitnessSpec.
+func (in *TiKVWitnessSpec) DeepCopy() *TiKVWitnessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVWitnessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVWitnessStatus) DeepCopyInto(out *TiKVWitnessStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stores != nil {
+		in, out := &in.Stores, &out.Stores
+		*out = make(map[string]TiKVStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVWitnessStatus.
+func (in *TiKVWitnessStatus) DeepCopy() *TiKVWitnessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVWitnessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiProxyConfigWraper) DeepCopyInto(out *TiProxyConfigWraper) {
 	*out = *in
@@ -8903,6 +11545,164 @@ func (in *TiProxyStatus) DeepCopy() *TiProxyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbAccount) DeepCopyInto(out *TidbAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbAccount.
+func (in *TidbAccount) DeepCopy() *TidbAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbAccountList) DeepCopyInto(out *TidbAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TidbAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbAccountList.
+func (in *TidbAccountList) DeepCopy() *TidbAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbAccountPrivilege) DeepCopyInto(out *TidbAccountPrivilege) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbAccountPrivilege.
+func (in *TidbAccountPrivilege) DeepCopy() *TidbAccountPrivilege {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbAccountPrivilege)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbAccountSpec) DeepCopyInto(out *TidbAccountSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	in.PasswordSecret.DeepCopyInto(&out.PasswordSecret)
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]TidbAccountPrivilege, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TidbAccountTLS)
+		**out = **in
+	}
+	if in.TLSClientSecretName != nil {
+		in, out := &in.TLSClientSecretName, &out.TLSClientSecretName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbAccountSpec.
+func (in *TidbAccountSpec) DeepCopy() *TidbAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbAccountStatus) DeepCopyInto(out *TidbAccountStatus) {
+	*out = *in
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbAccountStatus.
+func (in *TidbAccountStatus) DeepCopy() *TidbAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbAccountTLS) DeepCopyInto(out *TidbAccountTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbAccountTLS.
+func (in *TidbAccountTLS) DeepCopy() *TidbAccountTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbAccountTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TidbCluster) DeepCopyInto(out *TidbCluster) {
 	*out = *in
@@ -8964,18 +11764,95 @@ func (in *TidbClusterList) DeepCopyInto(out *TidbClusterList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterList.
-func (in *TidbClusterList) DeepCopy() *TidbClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterList.
+func (in *TidbClusterList) DeepCopy() *TidbClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterRef) DeepCopyInto(out *TidbClusterRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterRef.
+func (in *TidbClusterRef) DeepCopy() *TidbClusterRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbClusterRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterReplication) DeepCopyInto(out *TidbClusterReplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterReplication.
+func (in *TidbClusterReplication) DeepCopy() *TidbClusterReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbClusterReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbClusterReplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterReplicationList) DeepCopyInto(out *TidbClusterReplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TidbClusterReplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterReplicationList.
+func (in *TidbClusterReplicationList) DeepCopy() *TidbClusterReplicationList {
 	if in == nil {
 		return nil
 	}
-	out := new(TidbClusterList)
+	out := new(TidbClusterReplicationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TidbClusterList) DeepCopyObject() runtime.Object {
+func (in *TidbClusterReplicationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -8983,17 +11860,58 @@ func (in *TidbClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TidbClusterRef) DeepCopyInto(out *TidbClusterRef) {
+func (in *TidbClusterReplicationSpec) DeepCopyInto(out *TidbClusterReplicationSpec) {
 	*out = *in
+	out.Primary = in.Primary
+	out.Secondary = in.Secondary
+	if in.TiCDC != nil {
+		in, out := &in.TiCDC, &out.TiCDC
+		*out = new(TiCDCReplicationSpec)
+		**out = **in
+	}
+	if in.PITR != nil {
+		in, out := &in.PITR, &out.PITR
+		*out = new(PITRReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxPromoteLag != nil {
+		in, out := &in.MaxPromoteLag, &out.MaxPromoteLag
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterRef.
-func (in *TidbClusterRef) DeepCopy() *TidbClusterRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterReplicationSpec.
+func (in *TidbClusterReplicationSpec) DeepCopy() *TidbClusterReplicationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TidbClusterRef)
+	out := new(TidbClusterReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterReplicationStatus) DeepCopyInto(out *TidbClusterReplicationStatus) {
+	*out = *in
+	if in.CheckpointTime != nil {
+		in, out := &in.CheckpointTime, &out.CheckpointTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PromoteTime != nil {
+		in, out := &in.PromoteTime, &out.PromoteTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterReplicationStatus.
+func (in *TidbClusterReplicationStatus) DeepCopy() *TidbClusterReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbClusterReplicationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -9053,6 +11971,31 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = new(HelperSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClusterCloneFrom != nil {
+		in, out := &in.ClusterCloneFrom, &out.ClusterCloneFrom
+		*out = new(ClusterCloneFrom)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftProtection != nil {
+		in, out := &in.DriftProtection, &out.DriftProtection
+		*out = new(DriftProtection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRecommendation != nil {
+		in, out := &in.ResourceRecommendation, &out.ResourceRecommendation
+		*out = new(ResourceRecommendationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProfileCapture != nil {
+		in, out := &in.ProfileCapture, &out.ProfileCapture
+		*out = new(ProfileCaptureSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusMonitor != nil {
+		in, out := &in.PrometheusMonitor, &out.PrometheusMonitor
+		*out = new(PrometheusMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PVReclaimPolicy != nil {
 		in, out := &in.PVReclaimPolicy, &out.PVReclaimPolicy
 		*out = new(v1.PersistentVolumeReclaimPolicy)
@@ -9093,6 +12036,21 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SchedulingGates != nil {
+		in, out := &in.SchedulingGates, &out.SchedulingGates
+		*out = make([]v1.PodSchedulingGate, len(*in))
+		copy(*out, *in)
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -9114,6 +12072,16 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.PropagatePolicy != nil {
+		in, out := &in.PropagatePolicy, &out.PropagatePolicy
+		*out = new(PropagatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Velero != nil {
+		in, out := &in.Velero, &out.Velero
+		*out = new(VeleroSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -9126,6 +12094,13 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = new(v1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]Service, len(*in))
@@ -9136,6 +12111,11 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.AcrossK8sResolver != nil {
+		in, out := &in.AcrossK8sResolver, &out.AcrossK8sResolver
+		*out = new(AcrossK8sResolver)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Cluster != nil {
 		in, out := &in.Cluster, &out.Cluster
 		*out = new(TidbClusterRef)
@@ -9146,6 +12126,11 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExternalPD != nil {
+		in, out := &in.ExternalPD, &out.ExternalPD
+		*out = new(ExternalPDSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
 		*out = new(v1.PodSecurityContext)
@@ -9161,8 +12146,28 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 	if in.SuspendAction != nil {
 		in, out := &in.SuspendAction, &out.SuspendAction
 		*out = new(SuspendAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InjectResourceHints != nil {
+		in, out := &in.InjectResourceHints, &out.InjectResourceHints
+		*out = new(bool)
 		**out = **in
 	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(UpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeDrain != nil {
+		in, out := &in.NodeDrain, &out.NodeDrain
+		*out = new(NodeDrainPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigBackup != nil {
+		in, out := &in.ConfigBackup, &out.ConfigBackup
+		*out = new(ConfigBackupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StartScriptV2FeatureFlags != nil {
 		in, out := &in.StartScriptV2FeatureFlags, &out.StartScriptV2FeatureFlags
 		*out = make([]StartScriptV2FeatureFlag, len(*in))
@@ -9213,6 +12218,93 @@ func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SuggestedActions != nil {
+		in, out := &in.SuggestedActions, &out.SuggestedActions
+		*out = make([]SuggestedAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ZoneDistributions != nil {
+		in, out := &in.ZoneDistributions, &out.ZoneDistributions
+		*out = make([]ZoneDistribution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SelfTest != nil {
+		in, out := &in.SelfTest, &out.SelfTest
+		*out = new(SelfTestReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProfileCapture != nil {
+		in, out := &in.ProfileCapture, &out.ProfileCapture
+		*out = new(ProfileCaptureStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitors != nil {
+		in, out := &in.PodMonitors, &out.PodMonitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisruptionLock != nil {
+		in, out := &in.DisruptionLock, &out.DisruptionLock
+		*out = new(DisruptionLock)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(ClusterSuspendStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradePolicyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigConflicts != nil {
+		in, out := &in.ConfigConflicts, &out.ConfigConflicts
+		*out = make([]ConfigConflict, len(*in))
+		copy(*out, *in)
+	}
+	if in.StuckUpgrade != nil {
+		in, out := &in.StuckUpgrade, &out.StuckUpgrade
+		*out = new(StuckUpgradeReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clone != nil {
+		in, out := &in.Clone, &out.Clone
+		*out = new(ClusterCloneStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]ResourceDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SyncHistory != nil {
+		in, out := &in.SyncHistory, &out.SyncHistory
+		*out = make([]SyncRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeReplaces != nil {
+		in, out := &in.VolumeReplaces, &out.VolumeReplaces
+		*out = make([]VolumeReplaceProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -9515,6 +12607,13 @@ func (in *TidbInitializerSpec) DeepCopy() *TidbInitializerSpec {
 func (in *TidbInitializerStatus) DeepCopyInto(out *TidbInitializerStatus) {
 	*out = *in
 	in.JobStatus.DeepCopyInto(&out.JobStatus)
+	if in.PasswordRotations != nil {
+		in, out := &in.PasswordRotations, &out.PasswordRotations
+		*out = make([]PasswordRotation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -9603,6 +12702,11 @@ func (in *TidbMonitorSpec) DeepCopyInto(out *TidbMonitorSpec) {
 		*out = new(GrafanaSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GrafanaExport != nil {
+		in, out := &in.GrafanaExport, &out.GrafanaExport
+		*out = new(GrafanaExportSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Reloader.DeepCopyInto(&out.Reloader)
 	in.Initializer.DeepCopyInto(&out.Initializer)
 	if in.DM != nil {
@@ -9630,6 +12734,16 @@ func (in *TidbMonitorSpec) DeepCopyInto(out *TidbMonitorSpec) {
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -9673,6 +12787,16 @@ func (in *TidbMonitorSpec) DeepCopyInto(out *TidbMonitorSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Alertmanager != nil {
+		in, out := &in.Alertmanager, &out.Alertmanager
+		*out = new(AlertmanagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraAlertRules != nil {
+		in, out := &in.ExtraAlertRules, &out.ExtraAlertRules
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.AlertManagerRulesVersion != nil {
 		in, out := &in.AlertManagerRulesVersion, &out.AlertManagerRulesVersion
 		*out = new(string)
@@ -9947,6 +13071,76 @@ func (in *UnjoinedMember) DeepCopy() *UnjoinedMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeDecision) DeepCopyInto(out *UpgradeDecision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeDecision.
+func (in *UpgradeDecision) DeepCopy() *UpgradeDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePolicy) DeepCopyInto(out *UpgradePolicy) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicy.
+func (in *UpgradePolicy) DeepCopy() *UpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePolicyStatus) DeepCopyInto(out *UpgradePolicyStatus) {
+	*out = *in
+	if in.NextWindowTime != nil {
+		in, out := &in.NextWindowTime, &out.NextWindowTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Decisions != nil {
+		in, out := &in.Decisions, &out.Decisions
+		*out = make([]UpgradeDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicyStatus.
+func (in *UpgradePolicyStatus) DeepCopy() *UpgradePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -9978,6 +13172,85 @@ func (in *User) DeepCopy() *User {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VeleroBackupHook) DeepCopyInto(out *VeleroBackupHook) {
+	*out = *in
+	if in.PreCommand != nil {
+		in, out := &in.PreCommand, &out.PreCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostCommand != nil {
+		in, out := &in.PostCommand, &out.PostCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroBackupHook.
+func (in *VeleroBackupHook) DeepCopy() *VeleroBackupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(VeleroBackupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VeleroSpec) DeepCopyInto(out *VeleroSpec) {
+	*out = *in
+	if in.ExcludeGeneratedResources != nil {
+		in, out := &in.ExcludeGeneratedResources, &out.ExcludeGeneratedResources
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]VeleroBackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroSpec.
+func (in *VeleroSpec) DeepCopy() *VeleroSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VeleroSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeReplaceProgress) DeepCopyInto(out *VolumeReplaceProgress) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeReplaceProgress.
+func (in *VolumeReplaceProgress) DeepCopy() *VolumeReplaceProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeReplaceProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerConfig) DeepCopyInto(out *WorkerConfig) {
 	*out = *in
@@ -10094,6 +13367,16 @@ func (in *WorkerSpec) DeepCopyInto(out *WorkerSpec) {
 		*out = new(Failover)
 		**out = **in
 	}
+	if in.RelayStorage != nil {
+		in, out := &in.RelayStorage, &out.RelayStorage
+		*out = new(DMRelayStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RelayPurge != nil {
+		in, out := &in.RelayPurge, &out.RelayPurge
+		*out = new(DMRelayPurgePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -10163,3 +13446,26 @@ func (in *WorkerStatus) DeepCopy() *WorkerStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneDistribution) DeepCopyInto(out *ZoneDistribution) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneDistribution.
+func (in *ZoneDistribution) DeepCopy() *ZoneDistribution {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneDistribution)
+	in.DeepCopyInto(out)
+	return out
+}
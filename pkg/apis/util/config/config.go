@@ -18,6 +18,7 @@ import (
 	stdjson "encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -66,6 +67,46 @@ func (c *GenericConfig) Merge(other *GenericConfig) error {
 	return nil
 }
 
+// MergeDefaults merges the content in `defaults` to `c` without overwriting the values in `c`.
+//
+// Map values will be merged. The keys of the values in `c` different from the ones in `defaults`
+// are returned in dotted form, e.g. security.ssl-ca, sorted.
+func (c *GenericConfig) MergeDefaults(defaults *GenericConfig) []string {
+	if defaults == nil {
+		return nil
+	}
+	if c.MP == nil {
+		c.MP = map[string]interface{}{}
+	}
+	conflicts := mergeDefaults(c.MP, defaults.MP, "")
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func mergeDefaults(ms, defaults map[string]interface{}, prefix string) []string {
+	var conflicts []string
+	for k, v := range defaults {
+		myVal, ok := ms[k]
+		if !ok {
+			ms[k] = deepcopy.Copy(v)
+			continue
+		}
+
+		myValR, iAmMap := strKeyMap(myVal).(map[string]interface{})
+		defaultValR, defaultIsMap := strKeyMap(v).(map[string]interface{})
+		if iAmMap && defaultIsMap {
+			ms[k] = myValR
+			conflicts = append(conflicts, mergeDefaults(myValR, defaultValR, prefix+k+".")...)
+			continue
+		}
+		// the integers may be decoded as float64 from JSON
+		if !reflect.DeepEqual(myVal, v) && fmt.Sprint(myVal) != fmt.Sprint(v) {
+			conflicts = append(conflicts, prefix+k)
+		}
+	}
+	return conflicts
+}
+
 func (c *GenericConfig) MarshalTOML() ([]byte, error) {
 	if c == nil {
 		return nil, nil
@@ -208,6 +208,44 @@ func TestSetIfNil(t *testing.T) {
 	g.Expect(c.Get("nil_key").MustInt()).Should(Equal(v))
 }
 
+func TestMergeDefaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defaults := New(map[string]interface{}{})
+	err := defaults.UnmarshalTOML([]byte(`
+a = 1
+b = "default"
+[security]
+ssl-ca = "/ca.pem"
+require-secure-transport = true
+[log.file]
+max-days = 30
+`))
+	g.Expect(err).Should(BeNil())
+
+	c := New(map[string]interface{}{})
+	err = c.UnmarshalJSON([]byte(`{"a": 1, "b": "spec", "security": {"require-secure-transport": false}, "log": "info"}`))
+	g.Expect(err).Should(BeNil())
+
+	conflicts := c.MergeDefaults(defaults)
+	g.Expect(conflicts).Should(Equal([]string{"b", "log", "security.require-secure-transport"}))
+	g.Expect(c.Get("a").MustInt()).Should(Equal(int64(1)))
+	g.Expect(c.Get("b").MustString()).Should(Equal("spec"))
+	g.Expect(c.Get("security.ssl-ca").MustString()).Should(Equal("/ca.pem"))
+	g.Expect(c.Get("security.require-secure-transport").Interface()).Should(Equal(false))
+	g.Expect(c.Get("log").MustString()).Should(Equal("info"))
+
+	// the defaults are not changed by the config merged to
+	c.Set("security.ssl-ca", "/other.pem")
+	g.Expect(defaults.Get("security.ssl-ca").MustString()).Should(Equal("/ca.pem"))
+
+	// nil config
+	c = New(nil)
+	g.Expect(c.MergeDefaults(defaults)).Should(BeEmpty())
+	g.Expect(c.Get("log.file.max-days").MustInt()).Should(Equal(int64(30)))
+	g.Expect(c.MergeDefaults(nil)).Should(BeEmpty())
+}
+
 func TestDel(t *testing.T) {
 	g := NewGomegaWithT(t)
 	kv := map[string]int64{
@@ -0,0 +1,118 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ActionResult is the result of an action
+type ActionResult struct {
+	Message string `json:"message"`
+}
+
+// FailoverRequest is the body of the PD leader failover, the leader is transferred to the
+// first healthy member in name order if the target is empty
+type FailoverRequest struct {
+	Target string `json:"target,omitempty"`
+}
+
+// triggerBackup requests a backup out of the schedule by the annotation tidb.pingcap.com/backup-now
+func (s *Server) triggerBackup(r *http.Request, user *authnv1.UserInfo) (interface{}, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				label.AnnBackupNowKey: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+	return s.patchBackupSchedule(r, user, patch, "BackupTriggered", "a backup is requested")
+}
+
+func (s *Server) pauseBackupSchedule(r *http.Request, user *authnv1.UserInfo) (interface{}, error) {
+	patch := map[string]interface{}{"spec": map[string]interface{}{"pause": true}}
+	return s.patchBackupSchedule(r, user, patch, "BackupSchedulePaused", "the backup schedule is paused")
+}
+
+func (s *Server) resumeBackupSchedule(r *http.Request, user *authnv1.UserInfo) (interface{}, error) {
+	patch := map[string]interface{}{"spec": map[string]interface{}{"pause": false}}
+	return s.patchBackupSchedule(r, user, patch, "BackupScheduleResumed", "the backup schedule is resumed")
+}
+
+func (s *Server) patchBackupSchedule(r *http.Request, user *authnv1.UserInfo, patch map[string]interface{}, reason, msg string) (interface{}, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	ns, name := r.PathValue("namespace"), r.PathValue("name")
+	bs, err := s.deps.Clientset.PingcapV1alpha1().BackupSchedules(ns).Patch(r.Context(), name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	s.deps.Recorder.Eventf(bs, corev1.EventTypeNormal, reason, "%s by user %s via the API server", msg, user.Username)
+	return &ActionResult{Message: msg}, nil
+}
+
+// failoverPDLeader transfers the PD leader to another healthy member, e.g. to move the leader away
+// from a degraded node
+func (s *Server) failoverPDLeader(r *http.Request, user *authnv1.UserInfo) (interface{}, error) {
+	req := &FailoverRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil && err != io.EOF {
+		return nil, badRequest("invalid body: %v", err)
+	}
+	tc, err := s.deps.Clientset.PingcapV1alpha1().TidbClusters(r.PathValue("namespace")).Get(r.Context(), r.PathValue("name"), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if tc.Spec.PD == nil {
+		return nil, badRequest("tc %s/%s has no PD", tc.Namespace, tc.Name)
+	}
+	leader := tc.Status.PD.Leader.Name
+	var candidates []string
+	for name, member := range tc.Status.PD.Members {
+		if member.Health && name != leader {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, badRequest("tc %s/%s has no healthy PD member to transfer the leader %s to", tc.Namespace, tc.Name, leader)
+	}
+	sort.Strings(candidates)
+	target := candidates[0]
+	if len(req.Target) > 0 {
+		i := sort.SearchStrings(candidates, req.Target)
+		if i == len(candidates) || candidates[i] != req.Target {
+			return nil, badRequest("PD member %s is not a healthy follower of tc %s/%s", req.Target, tc.Namespace, tc.Name)
+		}
+		target = req.Target
+	}
+	if err := controller.GetPDClient(s.deps.PDControl, tc).TransferPDLeader(target); err != nil {
+		return nil, fmt.Errorf("failed to transfer the PD leader of tc %s/%s to %s: %v", tc.Namespace, tc.Name, target, err)
+	}
+	msg := fmt.Sprintf("the PD leader is transferred from %s to %s", leader, target)
+	s.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDLeaderFailover", "%s by user %s via the API server", msg, user.Username)
+	return &ActionResult{Message: msg}, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apiserver serves the read-only summaries of the tidb clusters and the safe actions, e.g.
+// triggering a backup, so that the platform portals can integrate without manipulating the CRs.
+// The requests are authenticated by TokenReview and authorized by SubjectAccessReview against the
+// permissions of the users on the CRs.
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const apiPrefix = "/api/v1/namespaces/{namespace}"
+
+// handlerFunc handles an authorized request, the result is encoded in JSON as the response
+type handlerFunc func(r *http.Request, user *authnv1.UserInfo) (interface{}, error)
+
+// requestError is the error caused by the request, e.g. an invalid body
+type requestError struct {
+	code int
+	msg  string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{code: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+// Server is the HTTP handler of the API server
+type Server struct {
+	deps *controller.Dependencies
+	mux  *http.ServeMux
+	// audiences are the audiences the tokens must be issued for
+	audiences []string
+}
+
+// NewServer returns the API server
+func NewServer(deps *controller.Dependencies) *Server {
+	s := &Server{
+		deps:      deps,
+		mux:       http.NewServeMux(),
+		audiences: deps.CLIConfig.GetAPIServerAudiences(),
+	}
+	s.handle(http.MethodGet, "/tidbclusters", "list", "tidbclusters", s.listClusters)
+	s.handle(http.MethodGet, "/tidbclusters/{name}", "get", "tidbclusters", s.getCluster)
+	s.handle(http.MethodPost, "/tidbclusters/{name}/failover", "update", "tidbclusters", s.failoverPDLeader)
+	s.handle(http.MethodPost, "/backupschedules/{name}/trigger", "update", "backupschedules", s.triggerBackup)
+	s.handle(http.MethodPost, "/backupschedules/{name}/pause", "update", "backupschedules", s.pauseBackupSchedule)
+	s.handle(http.MethodPost, "/backupschedules/{name}/resume", "update", "backupschedules", s.resumeBackupSchedule)
+	return s
+}
+
+// NewHTTPServer returns the HTTP server of the API server listening on the address, it must serve TLS
+// unless the address is a loopback address as validated by CLIConfig.ValidateAPIServer
+func NewHTTPServer(deps *controller.Dependencies, addr string) *http.Server {
+	return &http.Server{
+		Addr:    addr,
+		Handler: NewServer(deps),
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle registers the handler of a route, the user must be allowed to take the verb on the resource
+// named in the path, or all the resources in the namespace if the path has no name
+func (s *Server) handle(method, path, verb, resource string, h handlerFunc) {
+	s.mux.HandleFunc(method+" "+apiPrefix+path, func(w http.ResponseWriter, r *http.Request) {
+		user, err := s.authenticate(r)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		attrs := &authzv1.ResourceAttributes{
+			Namespace: r.PathValue("namespace"),
+			Verb:      verb,
+			Group:     v1alpha1.SchemeGroupVersion.Group,
+			Resource:  resource,
+			Name:      r.PathValue("name"),
+		}
+		if err := s.authorize(r.Context(), user, attrs); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		result, err := h(r, user)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// authenticate reviews the bearer token of the request and returns the user it belongs to, the token
+// must be issued for one of the audiences of the API server
+func (s *Server) authenticate(r *http.Request) (*authnv1.UserInfo, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || len(token) == 0 {
+		return nil, errors.New("bearer token is required")
+	}
+	review := &authnv1.TokenReview{Spec: authnv1.TokenReviewSpec{Token: token, Audiences: s.audiences}}
+	review, err := s.deps.KubeClientset.AuthenticationV1().TokenReviews().Create(r.Context(), review, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("failed to review the token of the request %s %s: %v", r.Method, r.URL.Path, err)
+		return nil, errors.New("failed to review the token")
+	}
+	if !review.Status.Authenticated {
+		return nil, fmt.Errorf("invalid token: %s", review.Status.Error)
+	}
+	// the authenticators not aware of the audiences accept the token without returning the audiences
+	if !sets.NewString(review.Status.Audiences...).HasAny(s.audiences...) {
+		return nil, fmt.Errorf("invalid token: the token is not issued for the audiences %v", s.audiences)
+	}
+	return &review.Status.User, nil
+}
+
+// authorize reviews whether the user is allowed to access the resource
+func (s *Server) authorize(ctx context.Context, user *authnv1.UserInfo, attrs *authzv1.ResourceAttributes) error {
+	extra := map[string]authzv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authzv1.ExtraValue(v)
+	}
+	review := &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			ResourceAttributes: attrs,
+			User:               user.Username,
+			Groups:             user.Groups,
+			UID:                user.UID,
+			Extra:              extra,
+		},
+	}
+	review, err := s.deps.KubeClientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("failed to review the access of user %s to %s %s/%s: %v", user.Username, attrs.Resource, attrs.Namespace, attrs.Name, err)
+		return errors.New("failed to review the access")
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("user %s is not allowed to %s %s in namespace %s: %s", user.Username, attrs.Verb, attrs.Resource, attrs.Namespace, review.Status.Reason)
+	}
+	return nil
+}
+
+func statusOf(err error) int {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.code
+	}
+	if status, ok := err.(apierrors.APIStatus); ok {
+		return int(status.Status().Code)
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.Errorf("failed to write the response: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	appsv1 "k8s.io/api/apps/v1"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newFakeServer returns the API server authenticating the token "admin" as user admin, who is only
+// allowed to access the resources in namespace ns. The token "other" is issued for another audience.
+func newFakeServer() (*Server, *controller.Dependencies) {
+	deps := controller.NewFakeDependencies()
+	kubeCli := deps.KubeClientset.(*kubefake.Clientset)
+	kubeCli.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authnv1.TokenReview)
+		switch review.Spec.Token {
+		case "admin":
+			review.Status.Authenticated = true
+			review.Status.User = authnv1.UserInfo{Username: "admin"}
+			review.Status.Audiences = review.Spec.Audiences
+		case "other":
+			review.Status.Authenticated = true
+			review.Status.User = authnv1.UserInfo{Username: "admin"}
+			review.Status.Audiences = []string{"https://kubernetes.default.svc"}
+		}
+		return true, review, nil
+	})
+	kubeCli.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.User == "admin" && review.Spec.ResourceAttributes.Namespace == "ns"
+		return true, review, nil
+	})
+	return NewServer(deps), deps
+}
+
+func doRequest(s *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	return w
+}
+
+func newTidbCluster() *v1alpha1.TidbCluster {
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "ns"},
+		Spec: v1alpha1.TidbClusterSpec{
+			Version: "v8.1.0",
+			PD:      &v1alpha1.PDSpec{},
+			TiKV:    &v1alpha1.TiKVSpec{},
+		},
+	}
+	tc.Status.PD.Phase = v1alpha1.NormalPhase
+	tc.Status.PD.StatefulSet = &appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3}
+	tc.Status.PD.Leader = v1alpha1.PDMember{Name: "basic-pd-0", Health: true}
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"basic-pd-0": {Name: "basic-pd-0", Health: true},
+		"basic-pd-1": {Name: "basic-pd-1", Health: false},
+		"basic-pd-2": {Name: "basic-pd-2", Health: true},
+	}
+	return tc
+}
+
+func TestServerAuth(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, _ := newFakeServer()
+
+	w := doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/tidbclusters", "", "")
+	g.Expect(w.Code).To(Equal(http.StatusUnauthorized))
+	w = doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/tidbclusters", "guest", "")
+	g.Expect(w.Code).To(Equal(http.StatusUnauthorized))
+	w = doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/tidbclusters", "other", "")
+	g.Expect(w.Code).To(Equal(http.StatusUnauthorized))
+	w = doRequest(s, http.MethodGet, "/api/v1/namespaces/other/tidbclusters", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusForbidden))
+	w = doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/tidbclusters", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+}
+
+func TestServerClusterSummary(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, deps := newFakeServer()
+
+	w := doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/tidbclusters/basic", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusNotFound))
+
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters("ns").Create(context.TODO(), newTidbCluster(), metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	w = doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/tidbclusters/basic", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	summary := &ClusterSummary{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), summary)).To(Succeed())
+	g.Expect(summary.Version).To(Equal("v8.1.0"))
+	g.Expect(summary.Ready).To(BeFalse())
+	g.Expect(summary.Components).To(ConsistOf(
+		ComponentSummary{Type: v1alpha1.PDMemberType, Phase: v1alpha1.NormalPhase, Replicas: 3, ReadyReplicas: 3},
+		ComponentSummary{Type: v1alpha1.TiKVMemberType},
+	))
+
+	w = doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/tidbclusters", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	var summaries []ClusterSummary
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &summaries)).To(Succeed())
+	g.Expect(summaries).To(HaveLen(1))
+	g.Expect(summaries[0].Name).To(Equal("basic"))
+}
+
+func TestServerBackupScheduleActions(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, deps := newFakeServer()
+
+	bs := &v1alpha1.BackupSchedule{ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "ns"}}
+	_, err := deps.Clientset.PingcapV1alpha1().BackupSchedules("ns").Create(context.TODO(), bs, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	w := doRequest(s, http.MethodPost, "/api/v1/namespaces/ns/backupschedules/daily/trigger", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	bs, err = deps.Clientset.PingcapV1alpha1().BackupSchedules("ns").Get(context.TODO(), "daily", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bs.Annotations).To(HaveKey(label.AnnBackupNowKey))
+
+	w = doRequest(s, http.MethodPost, "/api/v1/namespaces/ns/backupschedules/daily/pause", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	bs, err = deps.Clientset.PingcapV1alpha1().BackupSchedules("ns").Get(context.TODO(), "daily", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bs.Spec.Pause).To(BeTrue())
+
+	w = doRequest(s, http.MethodPost, "/api/v1/namespaces/ns/backupschedules/daily/resume", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	bs, err = deps.Clientset.PingcapV1alpha1().BackupSchedules("ns").Get(context.TODO(), "daily", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bs.Spec.Pause).To(BeFalse())
+
+	// the actions are not served by GET
+	w = doRequest(s, http.MethodGet, "/api/v1/namespaces/ns/backupschedules/daily/pause", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+}
+
+func TestServerFailoverPDLeader(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, deps := newFakeServer()
+
+	tc := newTidbCluster()
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters("ns").Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	pdClient := controller.NewFakePDClient(deps.PDControl.(*pdapi.FakePDControl), tc)
+	var transferredTo string
+	pdClient.AddReaction(pdapi.TransferPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		transferredTo = action.Name
+		return nil, nil
+	})
+
+	// the unhealthy member can't be the leader
+	w := doRequest(s, http.MethodPost, "/api/v1/namespaces/ns/tidbclusters/basic/failover", "admin", `{"target":"basic-pd-1"}`)
+	g.Expect(w.Code).To(Equal(http.StatusBadRequest))
+	g.Expect(transferredTo).To(BeEmpty())
+
+	w = doRequest(s, http.MethodPost, "/api/v1/namespaces/ns/tidbclusters/basic/failover", "admin", "")
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(transferredTo).To(Equal("basic-pd-2"))
+}
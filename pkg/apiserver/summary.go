@@ -0,0 +1,95 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSummary is the read-only summary of a tidb cluster
+type ClusterSummary struct {
+	Namespace  string             `json:"namespace"`
+	Name       string             `json:"name"`
+	Version    string             `json:"version,omitempty"`
+	Paused     bool               `json:"paused"`
+	Ready      bool               `json:"ready"`
+	Message    string             `json:"message,omitempty"`
+	Components []ComponentSummary `json:"components"`
+}
+
+// ComponentSummary is the read-only summary of a component of a tidb cluster
+type ComponentSummary struct {
+	Type          v1alpha1.MemberType  `json:"type"`
+	Phase         v1alpha1.MemberPhase `json:"phase,omitempty"`
+	Replicas      int32                `json:"replicas"`
+	ReadyReplicas int32                `json:"readyReplicas"`
+}
+
+// listClusters reads the clusters from the API server instead of the informers, which only run in the
+// leader, so that all the replicas of the operator serve the requests
+func (s *Server) listClusters(r *http.Request, _ *authnv1.UserInfo) (interface{}, error) {
+	tcs, err := s.deps.Clientset.PingcapV1alpha1().TidbClusters(r.PathValue("namespace")).List(r.Context(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]ClusterSummary, 0, len(tcs.Items))
+	for i := range tcs.Items {
+		summaries = append(summaries, summarizeCluster(&tcs.Items[i]))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+func (s *Server) getCluster(r *http.Request, _ *authnv1.UserInfo) (interface{}, error) {
+	tc, err := s.deps.Clientset.PingcapV1alpha1().TidbClusters(r.PathValue("namespace")).Get(r.Context(), r.PathValue("name"), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return summarizeCluster(tc), nil
+}
+
+func summarizeCluster(tc *v1alpha1.TidbCluster) ClusterSummary {
+	summary := ClusterSummary{
+		Namespace:  tc.Namespace,
+		Name:       tc.Name,
+		Version:    tc.Spec.Version,
+		Paused:     tc.Spec.Paused,
+		Components: []ComponentSummary{},
+	}
+	if cond := utiltidbcluster.GetTidbClusterReadyCondition(tc.Status); cond != nil {
+		summary.Ready = cond.Status == corev1.ConditionTrue
+		summary.Message = cond.Message
+	}
+	for _, status := range tc.AllComponentStatus() {
+		component := ComponentSummary{
+			Type:  status.MemberType(),
+			Phase: status.GetPhase(),
+		}
+		if sts := status.GetStatefulSet(); sts != nil {
+			component.Replicas = sts.Replicas
+			component.ReadyReplicas = sts.ReadyReplicas
+		}
+		summary.Components = append(summary.Components, component)
+	}
+	return summary
+}
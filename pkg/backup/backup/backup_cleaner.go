@@ -275,6 +275,7 @@ func (bc *backupCleaner) makeCleanJob(backup *v1alpha1.Backup) (*batchv1.Job, st
 			Affinity:          backup.Spec.Affinity,
 			Volumes:           volumes,
 			PriorityClassName: backup.Spec.PriorityClassName,
+			PreemptionPolicy:  backup.Spec.PreemptionPolicy,
 		},
 	}
 
@@ -474,6 +475,7 @@ func (bc *backupCleaner) makeStopLogBackupJob(backup *v1alpha1.Backup) (*batchv1
 			Affinity:          backup.Spec.Affinity,
 			Volumes:           volumes,
 			PriorityClassName: backup.Spec.PriorityClassName,
+			PreemptionPolicy:  backup.Spec.PreemptionPolicy,
 		},
 	}
 
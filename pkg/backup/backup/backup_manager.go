@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/backup/objectstore"
 	"github.com/pingcap/tidb-operator/pkg/backup/snapshotter"
 	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
 	"github.com/pingcap/tidb-operator/pkg/controller"
@@ -43,11 +44,13 @@ import (
 )
 
 type backupManager struct {
-	deps             *controller.Dependencies
-	backupCleaner    BackupCleaner
-	backupTracker    BackupTracker
-	statusUpdater    controller.BackupConditionUpdaterInterface
-	manifestFetchers []ManifestFetcher
+	deps                *controller.Dependencies
+	backupCleaner       BackupCleaner
+	backupTracker       BackupTracker
+	backupThrottler     BackupThrottler
+	objectStoreResolver objectstore.Resolver
+	statusUpdater       controller.BackupConditionUpdaterInterface
+	manifestFetchers    []ManifestFetcher
 }
 
 // NewBackupManager return backupManager
@@ -60,11 +63,13 @@ func NewBackupManager(deps *controller.Dependencies) backup.BackupManager {
 		NewTiDBNgMonitoringFetcher(deps.TiDBNGMonitoringLister),
 	}
 	return &backupManager{
-		deps:             deps,
-		backupCleaner:    NewBackupCleaner(deps, statusUpdater),
-		backupTracker:    NewBackupTracker(deps, statusUpdater),
-		statusUpdater:    statusUpdater,
-		manifestFetchers: manifestFetchers,
+		deps:                deps,
+		backupCleaner:       NewBackupCleaner(deps, statusUpdater),
+		backupTracker:       NewBackupTracker(deps, statusUpdater),
+		backupThrottler:     NewBackupThrottler(deps, statusUpdater),
+		objectStoreResolver: objectstore.NewResolver(deps),
+		statusUpdater:       statusUpdater,
+		manifestFetchers:    manifestFetchers,
 	}
 }
 
@@ -156,6 +161,16 @@ func (bm *backupManager) syncBackupJob(backup *v1alpha1.Backup) error {
 		return nil
 	}
 
+	if err = bm.resolveInClusterObjectStore(backup); err != nil {
+		klog.Errorf("backup %s/%s resolve in-cluster object store error %v.", ns, name, err)
+		return err
+	}
+
+	// wait for the running backup and restore jobs to be fewer than the limits
+	if err = bm.waitBackupJobAdmitted(backup); err != nil {
+		return err
+	}
+
 	// make backup job
 	var job *batchv1.Job
 	var reason string
@@ -179,6 +194,11 @@ func (bm *backupManager) syncBackupJob(backup *v1alpha1.Backup) error {
 		return errMsg
 	}
 
+	// the throttle adjusts the backup threads once the backup is running
+	if err := bm.backupThrottler.StartThrottle(backup); err != nil {
+		klog.Warningf("backup %s/%s start throttling failed, err: %v", ns, name, err)
+	}
+
 	return bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
 		Command: logBackupSubcommand,
 		Type:    v1alpha1.BackupScheduled,
@@ -186,6 +206,50 @@ func (bm *backupManager) syncBackupJob(backup *v1alpha1.Backup) error {
 	}, updateStatus)
 }
 
+// resolveInClusterObjectStore fills the endpoint and the secret of the in-cluster object store into the backup,
+// so that the backup jobs and the cleaner access the object store as an ordinary S3 storage
+func (bm *backupManager) resolveInClusterObjectStore(backup *v1alpha1.Backup) error {
+	ns := backup.GetNamespace()
+	name := backup.GetName()
+	changed, err := bm.objectStoreResolver.Resolve(backup, ns, backup.Spec.S3)
+	if err != nil || !changed {
+		return err
+	}
+
+	if _, err := bm.deps.Clientset.PingcapV1alpha1().Backups(ns).Update(context.TODO(), backup, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("backup %s/%s update endpoint of the in-cluster object store failed, err: %v", ns, name, err)
+	}
+	return controller.RequeueErrorf("backup %s/%s resolved endpoint %s of the in-cluster object store", ns, name, backup.Spec.S3.Endpoint)
+}
+
+// waitBackupJobAdmitted keeps the snapshot backup pending until the job is admitted by the limiter
+// of the concurrent backup and restore jobs
+func (bm *backupManager) waitBackupJobAdmitted(backup *v1alpha1.Backup) error {
+	if backup.Spec.Mode == v1alpha1.BackupModeLog || backup.Spec.Mode == v1alpha1.BackupModeVolumeSnapshot {
+		return nil
+	}
+
+	ns := backup.GetNamespace()
+	name := backup.GetName()
+	admitted, reason, err := bm.deps.BRJobLimiter.Admit(ns, backup.GetBackupJobName())
+	if err != nil {
+		return fmt.Errorf("backup %s/%s check job concurrency failed, err: %v", ns, name, err)
+	}
+	if admitted {
+		return nil
+	}
+
+	if err := bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:    v1alpha1.BackupPending,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ConcurrencyLimitExceeded",
+		Message: reason,
+	}, nil); err != nil {
+		return err
+	}
+	return controller.RequeueErrorf("backup %s/%s is pending: %s", ns, name, reason)
+}
+
 // validateBackup validates backup and returns error if backup is invalid
 func (bm *backupManager) validateBackup(backup *v1alpha1.Backup) error {
 	ns := backup.GetNamespace()
@@ -623,6 +687,7 @@ func (bm *backupManager) makeExportJob(backup *v1alpha1.Backup) (*batchv1.Job, s
 				},
 			}, volumes...),
 			PriorityClassName: backup.Spec.PriorityClassName,
+			PreemptionPolicy:  backup.Spec.PreemptionPolicy,
 		},
 	}
 
@@ -724,6 +789,14 @@ func (bm *backupManager) makeBRBackupJob(backup *v1alpha1.Backup) (*batchv1.Job,
 		args = append(args, fmt.Sprintf("--mode=%s", v1alpha1.BackupModeVolumeSnapshot))
 	default:
 		args = append(args, fmt.Sprintf("--mode=%s", v1alpha1.BackupModeSnapshot))
+		var replicaReadLabel string
+		replicaReadLabel, reason, err = bm.replicaReadLabel(backup, tc)
+		if err != nil {
+			return nil, reason, fmt.Errorf("backup %s/%s, %v", ns, name, err)
+		}
+		if replicaReadLabel != "" {
+			args = append(args, fmt.Sprintf("--replicaReadLabel=%s", replicaReadLabel))
+		}
 	}
 
 	jobLabels := util.CombineStringMap(label.NewBackup().Instance(backup.GetInstanceName()).BackupJob().Backup(name), backup.Labels)
@@ -850,6 +923,7 @@ func (bm *backupManager) makeBRBackupJob(backup *v1alpha1.Backup) (*batchv1.Job,
 			Affinity:          backup.Spec.Affinity,
 			Volumes:           volumes,
 			PriorityClassName: backup.Spec.PriorityClassName,
+			PreemptionPolicy:  backup.Spec.PreemptionPolicy,
 		},
 	}
 
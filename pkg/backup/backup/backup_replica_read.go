@@ -0,0 +1,114 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultPlacementRuleGroup is the group of the placement rules created by PD
+	defaultPlacementRuleGroup = "pd"
+	placementRuleRoleLearner  = "learner"
+)
+
+// replicaReadLabel returns the store label BR reads the data of the snapshot backup from, it's empty if
+// the data is read from the leaders. An error is returned if the backup should wait for the stores.
+func (bm *backupManager) replicaReadLabel(backup *v1alpha1.Backup, tc *v1alpha1.TidbCluster) (string, string, error) {
+	policy := backup.Spec.BackupReplicaReadPolicy
+	if policy == nil || policy.Mode == "" || policy.Mode == v1alpha1.BackupReplicaReadLeader {
+		return "", "", nil
+	}
+	key, value, err := backuputil.ParseReplicaReadLabel(policy.StoreLabel)
+	if err != nil {
+		return "", "InvalidSpec", err
+	}
+
+	pdClient := controller.GetPDClient(bm.deps.PDControl, tc)
+	storesInfo, err := pdClient.GetStores()
+	if err != nil {
+		return "", "GetStoresFailed", fmt.Errorf("failed to get stores of tidbcluster %s/%s: %v", tc.Namespace, tc.Name, err)
+	}
+	matched := 0
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || store.Store.StateName != v1alpha1.TiKVStateUp {
+			continue
+		}
+		// the replicas on TiFlash can't serve the backup
+		if !util.MatchLabelFromStoreLabels(store.Store.Labels, label.TiKVLabelVal) {
+			continue
+		}
+		for _, l := range store.Store.Labels {
+			if l.Key == key && l.Value == value {
+				matched++
+				break
+			}
+		}
+	}
+	if matched == 0 {
+		if policy.FallbackToLeader {
+			klog.Warningf("backup %s/%s reads from the leaders since no up TiKV store matches label %s", backup.Namespace, backup.Name, policy.StoreLabel)
+			bm.deps.Recorder.Eventf(backup, corev1.EventTypeWarning, "ReplicaReadFallback",
+				"no up TiKV store matches label %s, the data is read from the leaders", policy.StoreLabel)
+			return "", "", nil
+		}
+		return "", "ReplicaReadStoresNotReady", fmt.Errorf("no up TiKV store matches label %s", policy.StoreLabel)
+	}
+
+	if policy.Mode == v1alpha1.BackupReplicaReadLearner && policy.LearnerRule != nil {
+		if err := checkLearnerRule(pdClient, policy.LearnerRule, key, value); err != nil {
+			return "", "LearnerRuleMismatched", err
+		}
+	}
+	klog.Infof("backup %s/%s reads from the %ss on %d TiKV stores matching label %s", backup.Namespace, backup.Name, policy.Mode, matched, policy.StoreLabel)
+	return policy.StoreLabel, "", nil
+}
+
+// checkLearnerRule checks the placement rule places learners on the stores with the label
+func checkLearnerRule(pdClient pdapi.PDClient, ref *v1alpha1.PlacementRuleRef, key, value string) error {
+	groupID := ref.GroupID
+	if groupID == "" {
+		groupID = defaultPlacementRuleGroup
+	}
+	rule, err := pdClient.GetPlacementRule(groupID, ref.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get placement rule %s/%s: %v", groupID, ref.ID, err)
+	}
+	if rule == nil {
+		return fmt.Errorf("placement rule %s/%s does not exist", groupID, ref.ID)
+	}
+	if rule.Role != placementRuleRoleLearner || rule.Count <= 0 {
+		return fmt.Errorf("placement rule %s/%s places %d %s replicas, not learners", groupID, ref.ID, rule.Count, rule.Role)
+	}
+	for _, constraint := range rule.LabelConstraints {
+		if constraint.Key != key || constraint.Op != "in" {
+			continue
+		}
+		for _, v := range constraint.Values {
+			if v == value {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("placement rule %s/%s does not constrain the learners to label %s:%s", groupID, ref.ID, key, value)
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+)
+
+func TestReplicaReadLabel(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	bm := NewBackupManager(deps).(*backupManager)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Namespace = "ns"
+	tc.Name = "test"
+	backup := &v1alpha1.Backup{}
+	backup.Namespace = "ns"
+	backup.Name = "backup"
+
+	stores := []*pdapi.StoreInfo{
+		{Store: &pdapi.MetaStore{StateName: v1alpha1.TiKVStateUp, Store: &metapb.Store{Id: 1}}},
+		// TiFlash stores are never matched
+		{Store: &pdapi.MetaStore{StateName: v1alpha1.TiKVStateUp, Store: &metapb.Store{Id: 2, Labels: []*metapb.StoreLabel{
+			{Key: "engine", Value: "tiflash"}, {Key: "role", Value: "backup"},
+		}}}},
+	}
+	var rule *pdapi.PlacementRule
+	pdClient := controller.NewFakePDClient(deps.PDControl.(*pdapi.FakePDControl), tc)
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: stores}, nil
+	})
+	pdClient.AddReaction(pdapi.GetPlacementRuleActionType, func(action *pdapi.Action) (interface{}, error) {
+		if rule == nil || rule.GroupID != action.Rule.GroupID || rule.ID != action.Rule.ID {
+			return nil, nil
+		}
+		return rule, nil
+	})
+
+	// the leaders are read from by default
+	storeLabel, _, err := bm.replicaReadLabel(backup, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(storeLabel).To(BeEmpty())
+
+	// the backup waits for the labeled TiKV stores
+	backup.Spec.BackupReplicaReadPolicy = &v1alpha1.BackupReplicaReadPolicy{
+		Mode:       v1alpha1.BackupReplicaReadFollower,
+		StoreLabel: "role:backup",
+	}
+	_, reason, err := bm.replicaReadLabel(backup, tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(reason).To(Equal("ReplicaReadStoresNotReady"))
+
+	// or falls back to the leaders
+	backup.Spec.BackupReplicaReadPolicy.FallbackToLeader = true
+	storeLabel, _, err = bm.replicaReadLabel(backup, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(storeLabel).To(BeEmpty())
+
+	// the followers on the labeled TiKV stores are read from
+	stores = append(stores, &pdapi.StoreInfo{Store: &pdapi.MetaStore{StateName: v1alpha1.TiKVStateUp, Store: &metapb.Store{Id: 3, Labels: []*metapb.StoreLabel{
+		{Key: "role", Value: "backup"},
+	}}}})
+	storeLabel, _, err = bm.replicaReadLabel(backup, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(storeLabel).To(Equal("role:backup"))
+
+	// the learner rule is checked in the Learner mode
+	backup.Spec.BackupReplicaReadPolicy.Mode = v1alpha1.BackupReplicaReadLearner
+	backup.Spec.BackupReplicaReadPolicy.LearnerRule = &v1alpha1.PlacementRuleRef{ID: "backup-learner"}
+	_, reason, err = bm.replicaReadLabel(backup, tc)
+	g.Expect(err).To(MatchError(ContainSubstring("does not exist")))
+	g.Expect(reason).To(Equal("LearnerRuleMismatched"))
+
+	rule = &pdapi.PlacementRule{GroupID: "pd", ID: "backup-learner", Role: "follower", Count: 1}
+	_, _, err = bm.replicaReadLabel(backup, tc)
+	g.Expect(err).To(MatchError(ContainSubstring("not learners")))
+
+	rule.Role = "learner"
+	rule.LabelConstraints = []pdapi.LabelConstraint{{Key: "role", Op: "in", Values: []string{"backup"}}}
+	storeLabel, _, err = bm.replicaReadLabel(backup, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(storeLabel).To(Equal("role:backup"))
+}
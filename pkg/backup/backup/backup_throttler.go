@@ -0,0 +1,494 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util/promquery"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+var (
+	defaultThrottleInterval = 30 * time.Second
+)
+
+const (
+	defaultThrottleMaxCPUUsage = 80
+	defaultThrottleMinThreads  = 1
+	throttleQueryTimeout       = 10 * time.Second
+
+	// throttleCPUUsageQuery is the max CPU usage of the TiKVs in percent of their CPU quota
+	throttleCPUUsageQuery = `max(rate(process_cpu_seconds_total{%[1]s}[1m]) / tikv_server_cpu_cores_quota{%[1]s}) * 100`
+	// throttleIOBandwidthQuery is the max IO bandwidth of the TiKVs in bytes per second
+	throttleIOBandwidthQuery = `max(sum(rate(tikv_io_bytes{%s}[1m])) by (instance))`
+	// throttleLatencyQuery is the 99th percentile latency of the TiDB queries in seconds
+	throttleLatencyQuery = `histogram_quantile(0.99, sum(rate(tidb_server_handle_query_duration_seconds_bucket{%s}[1m])) by (le))`
+)
+
+// BackupThrottler implements the closed-loop throttling of the running snapshot backups by spec.throttle
+type BackupThrottler interface {
+	StartThrottle(backup *v1alpha1.Backup) error
+}
+
+// throttleLoad is the load of the cluster the backup threads are adjusted by
+type throttleLoad struct {
+	// cpuUsage is the max CPU usage of the TiKVs in percent
+	cpuUsage *float64
+	// ioBandwidth is the max IO bandwidth of the TiKVs in bytes per second
+	ioBandwidth *float64
+	// latency is the 99th percentile latency of the TiDB queries
+	latency *time.Duration
+}
+
+// the main processes of the backup throttle:
+// a. the throttle of a snapshot backup starts when its job is created, and the running backups are
+// added again when the throttler is initialized.
+// b. a go routine is started for each backup, which adjusts the backup threads of TiKV by the load periodically.
+// c. the backup threads before the first change are saved in the annotation of the cluster, which is shared
+// by all the throttled backups of the cluster.
+// d. the saved backup threads are set back when the last throttled backup of the cluster finishes, and the
+// go routine stops.
+type backupThrottler struct {
+	deps          *controller.Dependencies
+	statusUpdater controller.BackupConditionUpdaterInterface
+	querier       promquery.Querier
+	operateLock   sync.Mutex
+	backups       map[string]struct{}
+	// clusterLock serializes saving and setting back the original threads of the clusters
+	clusterLock sync.Mutex
+}
+
+// NewBackupThrottler returns a BackupThrottler
+func NewBackupThrottler(deps *controller.Dependencies, statusUpdater controller.BackupConditionUpdaterInterface) BackupThrottler {
+	throttler := &backupThrottler{
+		deps:          deps,
+		statusUpdater: statusUpdater,
+		querier:       promquery.NewQuerier(throttleQueryTimeout),
+		backups:       make(map[string]struct{}),
+	}
+	go throttler.initThrottleBackups()
+	return throttler
+}
+
+// initThrottleBackups throttles the running backups again after the controller restarts.
+func (bt *backupThrottler) initThrottleBackups() {
+	var (
+		backups *v1alpha1.BackupList
+		err     error
+	)
+	ns := ""
+	if !bt.deps.CLIConfig.ClusterScoped {
+		ns = os.Getenv("NAMESPACE")
+	}
+
+	err = retry.OnError(retry.DefaultRetry, func(e error) bool { return e != nil }, func() error {
+		backups, err = bt.deps.Clientset.PingcapV1alpha1().Backups(ns).List(context.TODO(), metav1.ListOptions{})
+		return err
+	})
+	if err != nil {
+		klog.Errorf("list backups error %v after retry, skip throttling the running backups when init, will throttle when backup starts", err)
+		return
+	}
+	for i := range backups.Items {
+		backup := &backups.Items[i]
+		if backup.Spec.Throttle != nil && v1alpha1.IsBackupRunning(backup) {
+			if err := bt.StartThrottle(backup); err != nil {
+				klog.Warningf("start throttling backup %s/%s error %v", backup.Namespace, backup.Name, err)
+			}
+		}
+	}
+}
+
+// StartThrottle starts to throttle the snapshot backup by its spec.throttle.
+func (bt *backupThrottler) StartThrottle(backup *v1alpha1.Backup) error {
+	if backup.Spec.Throttle == nil || backup.Spec.BR == nil || backup.Spec.Mode == v1alpha1.BackupModeLog ||
+		backup.Spec.Mode == v1alpha1.BackupModeVolumeSnapshot {
+		return nil
+	}
+	ns, name := backup.Namespace, backup.Name
+
+	bt.operateLock.Lock()
+	defer bt.operateLock.Unlock()
+
+	key := genThrottleKey(ns, name)
+	if _, exist := bt.backups[key]; exist {
+		return nil
+	}
+	klog.Infof("start throttling backup %s/%s", ns, name)
+	bt.backups[key] = struct{}{}
+	go bt.throttleLoop(ns, name)
+	return nil
+}
+
+func (bt *backupThrottler) removeBackup(ns, name string) {
+	bt.operateLock.Lock()
+	defer bt.operateLock.Unlock()
+	delete(bt.backups, genThrottleKey(ns, name))
+}
+
+// throttleLoop adjusts the backup threads periodically until the backup finishes.
+func (bt *backupThrottler) throttleLoop(ns, name string) {
+	defer bt.removeBackup(ns, name)
+
+	interval := defaultThrottleInterval
+	for {
+		time.Sleep(interval)
+
+		backup, err := bt.deps.BackupLister.Backups(ns).Get(name)
+		if errors.IsNotFound(err) {
+			klog.Infof("backup %s/%s has been deleted, stop throttling", ns, name)
+			return
+		}
+		if err != nil {
+			klog.Warningf("get backup %s/%s error %v, will skip to the next time", ns, name, err)
+			continue
+		}
+		if backup.Spec.Throttle == nil {
+			return
+		}
+		if backup.Spec.Throttle.Interval != nil && backup.Spec.Throttle.Interval.Duration > 0 {
+			interval = backup.Spec.Throttle.Interval.Duration
+		}
+		if backup.DeletionTimestamp != nil || v1alpha1.IsBackupComplete(backup) || v1alpha1.IsBackupFailed(backup) {
+			if err := bt.restoreThreads(backup); err != nil {
+				klog.Errorf("set back the backup threads of backup %s/%s error %v", ns, name, err)
+			}
+			klog.Infof("backup %s/%s finishes, stop throttling", ns, name)
+			return
+		}
+		if !v1alpha1.IsBackupRunning(backup) {
+			continue
+		}
+		if err := bt.throttle(backup); err != nil {
+			klog.Warningf("throttle backup %s/%s error %v, will retry in the next time", ns, name, err)
+		}
+	}
+}
+
+// throttle adjusts the backup threads of TiKV once by the current load of the cluster.
+func (bt *backupThrottler) throttle(backup *v1alpha1.Backup) error {
+	tc, err := bt.getTidbCluster(backup)
+	if err != nil {
+		return err
+	}
+	spec := backup.Spec.Throttle
+
+	status := backup.Status.Throttle.DeepCopy()
+	if status == nil {
+		// save the original threads before any change, the current ones may be changed by another backup
+		original, err := bt.saveOriginalThreads(tc)
+		if err != nil {
+			return err
+		}
+		current, err := bt.getThreads(tc)
+		if err != nil {
+			return err
+		}
+		status = &v1alpha1.BackupThrottleStatus{Threads: current, OriginalThreads: original}
+	}
+
+	load, err := bt.queryLoad(tc, spec)
+	if err != nil {
+		return err
+	}
+	minThreads, maxThreads := throttleThreadsRange(spec, status.OriginalThreads)
+	threads, paused, reason := nextBackupThreads(status.Threads, minThreads, maxThreads, load, spec)
+	if threads != status.Threads {
+		if err := bt.setThreads(tc, threads); err != nil {
+			return err
+		}
+		klog.Infof("backup %s/%s: set the backup threads of TiKV from %d to %d, %s", backup.Namespace, backup.Name, status.Threads, threads, reason)
+		now := metav1.Now()
+		status.LastAdjustTime = &now
+	}
+	status.Threads = threads
+	status.Paused = paused
+	status.Reason = reason
+	return bt.statusUpdater.Update(backup, nil, &controller.BackupUpdateStatus{Throttle: status})
+}
+
+// restoreThreads sets the backup threads of TiKV back to the ones before the throttled backups of the
+// cluster when the last of them finishes.
+func (bt *backupThrottler) restoreThreads(backup *v1alpha1.Backup) error {
+	status := backup.Status.Throttle
+	if status == nil {
+		return nil
+	}
+	tc, err := bt.getTidbCluster(backup)
+	if err != nil {
+		return err
+	}
+
+	bt.clusterLock.Lock()
+	defer bt.clusterLock.Unlock()
+
+	others, err := bt.hasOtherThrottledBackups(backup, tc)
+	if err != nil {
+		return err
+	}
+	if others {
+		klog.Infof("backup %s/%s finishes, the backup threads of %s/%s are kept for the other throttled backups", backup.Namespace, backup.Name, tc.Namespace, tc.Name)
+		return nil
+	}
+	tc, err = bt.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	value, ok := tc.Annotations[label.AnnBackupThrottleOriginalThreadsKey]
+	if !ok {
+		return nil
+	}
+	original, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("parse annotation %s of tidbcluster %s/%s failed, err: %v", label.AnnBackupThrottleOriginalThreadsKey, tc.Namespace, tc.Name, err)
+	}
+	if err := bt.setThreads(tc, int32(original)); err != nil {
+		return err
+	}
+	if err := bt.patchOriginalThreads(tc, nil); err != nil {
+		return err
+	}
+
+	status = status.DeepCopy()
+	status.OriginalThreads = int32(original)
+	status.Threads = status.OriginalThreads
+	status.Paused = false
+	status.Reason = "backup finished"
+	return bt.statusUpdater.Update(backup, nil, &controller.BackupUpdateStatus{Throttle: status})
+}
+
+// saveOriginalThreads returns the backup threads of TiKV before the throttled backups of the cluster,
+// which are read from TiKV and saved in the annotation of the cluster if they are not saved yet.
+func (bt *backupThrottler) saveOriginalThreads(tc *v1alpha1.TidbCluster) (int32, error) {
+	bt.clusterLock.Lock()
+	defer bt.clusterLock.Unlock()
+
+	tc, err := bt.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if value, ok := tc.Annotations[label.AnnBackupThrottleOriginalThreadsKey]; ok {
+		original, err := strconv.ParseInt(value, 10, 32)
+		if err == nil {
+			return int32(original), nil
+		}
+		klog.Warningf("parse annotation %s of tidbcluster %s/%s failed, err: %v, will save it again", label.AnnBackupThrottleOriginalThreadsKey, tc.Namespace, tc.Name, err)
+	}
+	original, err := bt.getThreads(tc)
+	if err != nil {
+		return 0, err
+	}
+	if err := bt.patchOriginalThreads(tc, &original); err != nil {
+		return 0, err
+	}
+	return original, nil
+}
+
+// patchOriginalThreads saves the original threads in the annotation of the cluster, or removes it if nil
+func (bt *backupThrottler) patchOriginalThreads(tc *v1alpha1.TidbCluster, original *int32) error {
+	var value interface{}
+	if original != nil {
+		value = strconv.Itoa(int(*original))
+	}
+	metadata := map[string]interface{}{
+		"annotations": map[string]interface{}{
+			label.AnnBackupThrottleOriginalThreadsKey: value,
+		},
+	}
+	if tc.ResourceVersion != "" {
+		// fail if the cluster is changed since the annotation is read
+		metadata["resourceVersion"] = tc.ResourceVersion
+	}
+	data, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+	if _, err := bt.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Patch(context.TODO(), tc.Name, types.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("patch annotation %s of tidbcluster %s/%s failed, err: %v", label.AnnBackupThrottleOriginalThreadsKey, tc.Namespace, tc.Name, err)
+	}
+	return nil
+}
+
+// hasOtherThrottledBackups returns whether there are other unfinished throttled backups of the cluster
+func (bt *backupThrottler) hasOtherThrottledBackups(backup *v1alpha1.Backup, tc *v1alpha1.TidbCluster) (bool, error) {
+	backups, err := bt.deps.BackupLister.List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, other := range backups {
+		if other.Namespace == backup.Namespace && other.Name == backup.Name {
+			continue
+		}
+		if other.Spec.Throttle == nil || other.Spec.BR == nil || other.DeletionTimestamp != nil || v1alpha1.IsBackupComplete(other) || v1alpha1.IsBackupFailed(other) {
+			continue
+		}
+		ns := other.Spec.BR.ClusterNamespace
+		if ns == "" {
+			ns = other.Namespace
+		}
+		if ns == tc.Namespace && other.Spec.BR.Cluster == tc.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (bt *backupThrottler) getTidbCluster(backup *v1alpha1.Backup) (*v1alpha1.TidbCluster, error) {
+	ns := backup.Spec.BR.ClusterNamespace
+	if ns == "" {
+		ns = backup.Namespace
+	}
+	tc, err := bt.deps.TiDBClusterLister.TidbClusters(ns).Get(backup.Spec.BR.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("get tidbcluster %s/%s failed, err: %v", ns, backup.Spec.BR.Cluster, err)
+	}
+	return tc, nil
+}
+
+// getThreads returns the backup threads of the first TiKV which can be reached
+func (bt *backupThrottler) getThreads(tc *v1alpha1.TidbCluster) (int32, error) {
+	var lastErr error
+	for _, store := range tc.Status.TiKV.Stores {
+		client := bt.deps.TiKVControl.GetTiKVPodClient(tc.Namespace, tc.Name, store.PodName, tc.Spec.ClusterDomain, tc.IsTLSClusterEnabled())
+		threads, err := client.GetBackupNumThreads()
+		if err == nil {
+			return threads, nil
+		}
+		lastErr = err
+	}
+	return 0, fmt.Errorf("get the backup threads of tikv of %s/%s failed, err: %v", tc.Namespace, tc.Name, lastErr)
+}
+
+// setThreads sets the backup threads of all the TiKVs
+func (bt *backupThrottler) setThreads(tc *v1alpha1.TidbCluster, threads int32) error {
+	for _, store := range tc.Status.TiKV.Stores {
+		client := bt.deps.TiKVControl.GetTiKVPodClient(tc.Namespace, tc.Name, store.PodName, tc.Spec.ClusterDomain, tc.IsTLSClusterEnabled())
+		if err := client.SetBackupNumThreads(threads); err != nil {
+			return fmt.Errorf("set the backup threads of tikv %s/%s to %d failed, err: %v", tc.Namespace, store.PodName, threads, err)
+		}
+	}
+	return nil
+}
+
+// queryLoad queries the load of the cluster the throttle is configured with
+func (bt *backupThrottler) queryLoad(tc *v1alpha1.TidbCluster, spec *v1alpha1.BackupThrottle) (throttleLoad, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), throttleQueryTimeout)
+	defer cancel()
+
+	load := throttleLoad{}
+	query := func(q string) (*float64, error) {
+		value, ok, err := bt.querier.Query(ctx, spec.PrometheusURL, q)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return &value, nil
+	}
+	var err error
+	if load.cpuUsage, err = query(fmt.Sprintf(throttleCPUUsageQuery, throttleSelector(tc, v1alpha1.TiKVMemberType))); err != nil {
+		return load, err
+	}
+	if spec.MaxIOBandwidth != nil {
+		if load.ioBandwidth, err = query(fmt.Sprintf(throttleIOBandwidthQuery, throttleSelector(tc, v1alpha1.TiKVMemberType))); err != nil {
+			return load, err
+		}
+	}
+	if spec.LatencySLO != nil {
+		seconds, err := query(fmt.Sprintf(throttleLatencyQuery, throttleSelector(tc, v1alpha1.TiDBMemberType)))
+		if err != nil {
+			return load, err
+		}
+		if seconds != nil {
+			latency := time.Duration(*seconds * float64(time.Second))
+			load.latency = &latency
+		}
+	}
+	return load, nil
+}
+
+// throttleSelector selects the metrics of the component of the cluster by the labels set by TidbMonitor
+func throttleSelector(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) string {
+	return fmt.Sprintf(`kubernetes_namespace=%q,cluster=%q,component=%q`, tc.Namespace, tc.Name, component)
+}
+
+// throttleThreadsRange returns the range the backup threads are adjusted in
+func throttleThreadsRange(spec *v1alpha1.BackupThrottle, original int32) (int32, int32) {
+	minThreads := int32(defaultThrottleMinThreads)
+	if spec.MinThreads != nil && *spec.MinThreads > 0 {
+		minThreads = *spec.MinThreads
+	}
+	maxThreads := original
+	if spec.MaxThreads != nil {
+		maxThreads = *spec.MaxThreads
+	}
+	if maxThreads < minThreads {
+		maxThreads = minThreads
+	}
+	return minThreads, maxThreads
+}
+
+// nextBackupThreads returns the backup threads by the load of the cluster. The backup is paused to the min
+// threads when the latency SLO is breached, the threads are halved when the CPU or IO usage exceeds its
+// limit and increased by one otherwise.
+func nextBackupThreads(current, minThreads, maxThreads int32, load throttleLoad, spec *v1alpha1.BackupThrottle) (int32, bool, string) {
+	if spec.LatencySLO != nil && load.latency != nil && *load.latency > spec.LatencySLO.Duration {
+		return minThreads, true, fmt.Sprintf("the latency %s breaches the SLO %s", load.latency.Truncate(time.Millisecond), spec.LatencySLO.Duration)
+	}
+
+	maxCPUUsage := float64(defaultThrottleMaxCPUUsage)
+	if spec.MaxCPUUsage != nil {
+		maxCPUUsage = float64(*spec.MaxCPUUsage)
+	}
+	var reason string
+	switch {
+	case load.cpuUsage != nil && *load.cpuUsage > maxCPUUsage:
+		reason = fmt.Sprintf("the cpu usage %.1f%% exceeds %.0f%%", *load.cpuUsage, maxCPUUsage)
+	case spec.MaxIOBandwidth != nil && load.ioBandwidth != nil && *load.ioBandwidth > float64(spec.MaxIOBandwidth.Value()):
+		reason = fmt.Sprintf("the io bandwidth %.0f bytes/s exceeds %s", *load.ioBandwidth, spec.MaxIOBandwidth.String())
+	}
+	if reason != "" {
+		threads := current / 2
+		if threads < minThreads {
+			threads = minThreads
+		}
+		return threads, false, reason
+	}
+
+	threads := current + 1
+	if threads > maxThreads {
+		threads = maxThreads
+	}
+	if threads < minThreads {
+		threads = minThreads
+	}
+	return threads, false, "the load is within the limits"
+}
+
+func genThrottleKey(ns, name string) string {
+	return fmt.Sprintf("%s.%s", ns, name)
+}
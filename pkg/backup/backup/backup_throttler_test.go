@@ -0,0 +1,219 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/tikvapi"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestNextBackupThreads(t *testing.T) {
+	g := NewGomegaWithT(t)
+	float := func(v float64) *float64 { return &v }
+	duration := func(d time.Duration) *time.Duration { return &d }
+	ioLimit := resource.MustParse("100Mi")
+
+	tests := []struct {
+		name    string
+		current int32
+		load    throttleLoad
+		spec    *v1alpha1.BackupThrottle
+		threads int32
+		paused  bool
+	}{
+		{
+			name:    "increase when the load is low",
+			current: 4,
+			load:    throttleLoad{cpuUsage: float(50)},
+			spec:    &v1alpha1.BackupThrottle{},
+			threads: 5,
+		},
+		{
+			name:    "not exceed the max threads",
+			current: 8,
+			load:    throttleLoad{cpuUsage: float(50)},
+			spec:    &v1alpha1.BackupThrottle{},
+			threads: 8,
+		},
+		{
+			name:    "halve when the cpu usage exceeds the default limit",
+			current: 8,
+			load:    throttleLoad{cpuUsage: float(90)},
+			spec:    &v1alpha1.BackupThrottle{},
+			threads: 4,
+		},
+		{
+			name:    "halve when the cpu usage exceeds the limit",
+			current: 8,
+			load:    throttleLoad{cpuUsage: float(60)},
+			spec:    &v1alpha1.BackupThrottle{MaxCPUUsage: pointer.Int32Ptr(50)},
+			threads: 4,
+		},
+		{
+			name:    "halve when the io bandwidth exceeds the limit",
+			current: 8,
+			load:    throttleLoad{ioBandwidth: float(200 * 1024 * 1024)},
+			spec:    &v1alpha1.BackupThrottle{MaxIOBandwidth: &ioLimit},
+			threads: 4,
+		},
+		{
+			name:    "not below the min threads",
+			current: 3,
+			load:    throttleLoad{cpuUsage: float(90)},
+			spec:    &v1alpha1.BackupThrottle{},
+			threads: 2,
+		},
+		{
+			name:    "pause when the latency SLO is breached",
+			current: 8,
+			load:    throttleLoad{cpuUsage: float(10), latency: duration(time.Second)},
+			spec:    &v1alpha1.BackupThrottle{LatencySLO: &metav1.Duration{Duration: 500 * time.Millisecond}},
+			threads: 2,
+			paused:  true,
+		},
+		{
+			name:    "increase when the latency is within the SLO",
+			current: 2,
+			load:    throttleLoad{latency: duration(100 * time.Millisecond)},
+			spec:    &v1alpha1.BackupThrottle{LatencySLO: &metav1.Duration{Duration: 500 * time.Millisecond}},
+			threads: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.MinThreads = pointer.Int32Ptr(2)
+			minThreads, maxThreads := throttleThreadsRange(tt.spec, 8)
+			g.Expect(minThreads).To(Equal(int32(2)))
+			g.Expect(maxThreads).To(Equal(int32(8)))
+			threads, paused, _ := nextBackupThreads(tt.current, minThreads, maxThreads, tt.load, tt.spec)
+			g.Expect(threads).To(Equal(tt.threads))
+			g.Expect(paused).To(Equal(tt.paused))
+		})
+	}
+}
+
+type fakeLoadQuerier struct {
+	values map[string]float64
+}
+
+func (q *fakeLoadQuerier) Query(_ context.Context, _, query string) (float64, bool, error) {
+	value, ok := q.values[query]
+	return value, ok, nil
+}
+
+type recordBackupUpdater struct {
+	status *controller.BackupUpdateStatus
+}
+
+func (u *recordBackupUpdater) Update(_ *v1alpha1.Backup, _ *v1alpha1.BackupCondition, status *controller.BackupUpdateStatus) error {
+	u.status = status
+	return nil
+}
+
+func TestBackupThrottle(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Namespace = "ns"
+	tc.Name = "tc"
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: "tc-tikv-0"},
+		"2": {ID: "2", PodName: "tc-tikv-1"},
+	}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(tc)).To(Succeed())
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).To(Succeed())
+	originalThreads := func() string {
+		tc, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+		g.Expect(err).To(Succeed())
+		return tc.Annotations[label.AnnBackupThrottleOriginalThreadsKey]
+	}
+
+	threads := map[string]int32{"tc-tikv-0": 8, "tc-tikv-1": 8}
+	for podName := range threads {
+		podName := podName
+		client := tikvapi.NewFakeTiKVClient()
+		client.AddReaction(tikvapi.GetBackupNumThreadsActionType, func(action *tikvapi.Action) (interface{}, error) {
+			return threads[podName], nil
+		})
+		client.AddReaction(tikvapi.SetBackupNumThreadsActionType, func(action *tikvapi.Action) (interface{}, error) {
+			threads[podName] = int32(action.ID)
+			return nil, nil
+		})
+		deps.TiKVControl.(*tikvapi.FakeTiKVControl).SetTiKVPodClient(tc.Namespace, tc.Name, podName, client)
+	}
+
+	updater := &recordBackupUpdater{}
+	querier := &fakeLoadQuerier{values: map[string]float64{}}
+	bt := &backupThrottler{deps: deps, statusUpdater: updater, querier: querier, backups: map[string]struct{}{}}
+
+	backup := &v1alpha1.Backup{}
+	backup.Namespace = tc.Namespace
+	backup.Name = "backup"
+	backup.Spec.BR = &v1alpha1.BRConfig{Cluster: tc.Name}
+	backup.Spec.Throttle = &v1alpha1.BackupThrottle{PrometheusURL: "http://prometheus:9090"}
+	cpuQuery := "max(rate(process_cpu_seconds_total{" + throttleSelector(tc, v1alpha1.TiKVMemberType) + "}[1m]) / tikv_server_cpu_cores_quota{" + throttleSelector(tc, v1alpha1.TiKVMemberType) + "}) * 100"
+
+	// the threads are halved on all the TiKVs when the cpu usage is high
+	querier.values[cpuQuery] = 95
+	g.Expect(bt.throttle(backup)).To(Succeed())
+	g.Expect(threads).To(Equal(map[string]int32{"tc-tikv-0": 4, "tc-tikv-1": 4}))
+	g.Expect(updater.status.Throttle.Threads).To(Equal(int32(4)))
+	g.Expect(updater.status.Throttle.OriginalThreads).To(Equal(int32(8)))
+	g.Expect(updater.status.Throttle.LastAdjustTime).NotTo(BeNil())
+	g.Expect(originalThreads()).To(Equal("8"))
+
+	// the threads are increased when the cpu usage is low
+	backup.Status.Throttle = updater.status.Throttle
+	querier.values[cpuQuery] = 30
+	g.Expect(bt.throttle(backup)).To(Succeed())
+	g.Expect(threads).To(Equal(map[string]int32{"tc-tikv-0": 5, "tc-tikv-1": 5}))
+
+	backup.Status.Throttle = updater.status.Throttle
+
+	// another backup of the cluster shares the original threads saved before the first change
+	other := backup.DeepCopy()
+	other.Name = "other"
+	other.Status.Throttle = nil
+	g.Expect(bt.throttle(other)).To(Succeed())
+	g.Expect(threads).To(Equal(map[string]int32{"tc-tikv-0": 6, "tc-tikv-1": 6}))
+	g.Expect(updater.status.Throttle.OriginalThreads).To(Equal(int32(8)))
+	other.Status.Throttle = updater.status.Throttle
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().Backups().Informer().GetIndexer().Add(other)).To(Succeed())
+
+	// the threads are kept when a backup finishes while the other one is running
+	g.Expect(bt.restoreThreads(backup)).To(Succeed())
+	g.Expect(threads).To(Equal(map[string]int32{"tc-tikv-0": 6, "tc-tikv-1": 6}))
+	g.Expect(originalThreads()).To(Equal("8"))
+
+	// the original threads are set back when the last backup finishes
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().Backups().Informer().GetIndexer().Delete(other)).To(Succeed())
+	g.Expect(bt.restoreThreads(other)).To(Succeed())
+	g.Expect(threads).To(Equal(map[string]int32{"tc-tikv-0": 8, "tc-tikv-1": 8}))
+	g.Expect(updater.status.Throttle.Threads).To(Equal(int32(8)))
+	g.Expect(originalThreads()).To(BeEmpty())
+}
@@ -14,40 +14,74 @@
 package backupschedule
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"path"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 	"github.com/pingcap/tidb-operator/pkg/backup"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
-	"github.com/robfig/cron"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 )
 
+const (
+	// storageUsageListTimeout is the timeout to list the objects of a backup
+	storageUsageListTimeout = time.Minute
+	// storageUsageCacheTTL is how long the size of a completed backup is cached, the objects of a
+	// completed backup don't change so it's only listed again after the cache expires
+	storageUsageCacheTTL = 24 * time.Hour
+	// missedRunDeadline is how late a scheduled time can be run with the Skip missed run policy
+	missedRunDeadline = 5 * time.Minute
+)
+
 type nowFn func() time.Time
 
+// objectsSizeFn returns the size of the objects in the storage of a backup
+type objectsSizeFn func(ns string, provider v1alpha1.StorageProvider) (int64, error)
+
 type backupScheduleManager struct {
-	deps *controller.Dependencies
-	now  nowFn
+	deps        *controller.Dependencies
+	now         nowFn
+	objectsSize objectsSizeFn
+
+	sizeLock sync.Mutex
+	// sizeCache caches the sizes of the completed backups, so the objects of a backup are not listed
+	// in the sync loop every time the storage usage is refreshed
+	sizeCache map[string]backupSizeEntry
+}
+
+// backupSizeEntry is the size of a completed backup cached until expireTime
+type backupSizeEntry struct {
+	size       int64
+	expireTime time.Time
 }
 
 // NewBackupScheduleManager return a *backupScheduleManager
 func NewBackupScheduleManager(deps *controller.Dependencies) backup.BackupScheduleManager {
-	return &backupScheduleManager{
-		deps: deps,
-		now:  time.Now,
+	bm := &backupScheduleManager{
+		deps:      deps,
+		now:       time.Now,
+		sizeCache: map[string]backupSizeEntry{},
 	}
+	bm.objectsSize = bm.listObjectsSize
+	return bm
 }
 
 func (bm *backupScheduleManager) doCompact(bs *v1alpha1.BackupSchedule, startTime, endTime, now time.Time) error {
@@ -71,14 +105,9 @@ func (bm *backupScheduleManager) createCompact(bs *v1alpha1.BackupSchedule, chec
 		return nil
 	}
 
-	var startTs, endTs time.Time
-	switch {
-	case bs.Status.LogBackupStartTs == nil:
-		return fmt.Errorf("Compact failed: %s/%s, please start a log backup before compact it", bs.GetNamespace(), bs.GetName())
-	case bs.Status.LastCompactProgress == nil:
-		startTs = bs.Status.LogBackupStartTs.Time
-	default:
-		startTs = bs.Status.LastCompactProgress.Time
+	startTs, err := compactStartTs(bs)
+	if err != nil {
+		return err
 	}
 
 	if !startTs.Before(*checkpoint) {
@@ -91,7 +120,7 @@ func (bm *backupScheduleManager) createCompact(bs *v1alpha1.BackupSchedule, chec
 		return fmt.Errorf("failed to parse compact interval: %w", err)
 	}
 
-	endTs = calEndTs(startTs, span, *checkpoint)
+	endTs := calEndTs(startTs, span, *checkpoint)
 	klog.Infof("backupSchedule %s/%s expected startTs is %v, endTs is %v", bs.GetNamespace(), bs.GetName(), startTs, endTs)
 
 	if endTs.After(*checkpoint) {
@@ -119,10 +148,115 @@ func (bm *backupScheduleManager) createCompact(bs *v1alpha1.BackupSchedule, chec
 	return nil
 }
 
+// compactStartTs returns the time from which the log backup is compacted next.
+func compactStartTs(bs *v1alpha1.BackupSchedule) (time.Time, error) {
+	switch {
+	case bs.Status.LogBackupStartTs == nil:
+		return time.Time{}, fmt.Errorf("Compact failed: %s/%s, please start a log backup before compact it", bs.GetNamespace(), bs.GetName())
+	case bs.Status.LastCompactProgress == nil:
+		return bs.Status.LogBackupStartTs.Time, nil
+	default:
+		return bs.Status.LastCompactProgress.Time, nil
+	}
+}
+
+// createScheduledCompact compacts the log backup up to the checkpoint when a time of the compact
+// schedule is due, the compaction is delayed while it's out of the windows or the number of the
+// running compact backups in the namespace reaches the limit.
+func (bm *backupScheduleManager) createScheduledCompact(bs *v1alpha1.BackupSchedule, checkpoint *time.Time) error {
+	cs := bs.Spec.CompactSchedule
+	if cs == nil || bs.Status.LogBackup == nil || checkpoint == nil {
+		return nil
+	}
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+
+	startTs, err := compactStartTs(bs)
+	if err != nil {
+		return err
+	}
+	sched, err := util.ParseCronSchedule(cs.Schedule, bs.Spec.TimeZone)
+	if err != nil {
+		return fmt.Errorf("parse compact schedule %s/%s cron format %s failed, err: %v", ns, bsName, cs.Schedule, err)
+	}
+	loc := time.Local
+	if bs.Spec.TimeZone != "" {
+		if loc, err = time.LoadLocation(bs.Spec.TimeZone); err != nil {
+			return fmt.Errorf("unknown time zone %q of backup schedule %s/%s: %v", bs.Spec.TimeZone, ns, bsName, err)
+		}
+	}
+
+	earliestTime := bs.Status.LogBackupStartTs.Time
+	if bs.Status.LastCompactScheduleTime != nil {
+		earliestTime = bs.Status.LastCompactScheduleTime.Time
+	}
+	now := bm.now()
+	// only the latest due time is run, the times missed are coalesced into it
+	var scheduledTime time.Time
+	for t := sched.Next(earliestTime); !t.After(now); t = sched.Next(t) {
+		scheduledTime = t
+	}
+	if scheduledTime.IsZero() {
+		return nil
+	}
+	if !cs.InCompactWindows(now.In(loc)) {
+		klog.V(4).Infof("backupSchedule %s/%s compact: %v is out of the windows %v, wait for the next window", ns, bsName, now, cs.Windows)
+		return nil
+	}
+	if cs.MaxConcurrentCompacts > 0 {
+		running, err := bm.runningCompacts(ns)
+		if err != nil {
+			return err
+		}
+		if running >= int(cs.MaxConcurrentCompacts) {
+			klog.Infof("backupSchedule %s/%s compact: %d compact backups are running in the namespace, wait for them to finish", ns, bsName, running)
+			return nil
+		}
+	}
+
+	if !startTs.Before(*checkpoint) {
+		klog.Infof("backupSchedule %s/%s compact: startTs %v is after checkpoint %v, skip compact", ns, bsName, startTs, checkpoint)
+		bs.Status.LastCompactScheduleTime = &metav1.Time{Time: scheduledTime}
+		return nil
+	}
+	klog.Infof("backupSchedule %s/%s compact for scheduled time %v: from %v to %v",
+		ns,
+		bsName,
+		scheduledTime,
+		startTs.Format(v1alpha1.BackupTimestampFormat),
+		checkpoint.Format(v1alpha1.BackupTimestampFormat),
+	)
+	if err := bm.doCompact(bs, startTs, *checkpoint, now); err != nil {
+		return err
+	}
+	bs.Status.LastCompactScheduleTime = &metav1.Time{Time: scheduledTime}
+	return nil
+}
+
+// runningCompacts returns the number of the compact backups which are not finished in the namespace.
+func (bm *backupScheduleManager) runningCompacts(ns string) (int, error) {
+	compacts, err := bm.deps.CompactBackupLister.CompactBackups(ns).List(labels.Everything())
+	if err != nil {
+		return 0, fmt.Errorf("list compact backups in namespace %s failed: %w", ns, err)
+	}
+	running := 0
+	for _, compact := range compacts {
+		if compact.Status.State != string(v1alpha1.BackupComplete) && compact.Status.State != string(v1alpha1.BackupFailed) {
+			running++
+		}
+	}
+	return running, nil
+}
+
 func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) (err error) {
+	defer bm.refreshStorageUsage(bs)
+	defer bm.transitionStorageClass(bs)
 	defer bm.backupGC(bs)
 
-	if bs.Spec.Pause {
+	if err := bm.syncLogBackupPause(bs); err != nil {
+		return err
+	}
+	if bs.GetPauseMode() == v1alpha1.BackupSchedulePauseModeAll {
 		return controller.IgnoreErrorf("backupSchedule %s/%s has been paused", bs.GetNamespace(), bs.GetName())
 	}
 
@@ -133,6 +267,10 @@ func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) (err error) {
 		break
 
 	case bs.Status.LogBackup == nil:
+		// the log backup is started once it's not paused
+		if bs.IsLogBackupPaused() {
+			break
+		}
 		if err = bm.createLogBackup(bs); err != nil {
 			return err
 		}
@@ -151,44 +289,142 @@ func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) (err error) {
 		}
 		if err := bm.canPerformNextCompact(bs); err != nil {
 			klog.Errorf("backupSchedule %s/%s can not perform next compact, err: %v", bs.GetNamespace(), bs.GetName(), err)
+		} else if bs.Spec.CompactSchedule != nil {
+			if err := bm.createScheduledCompact(bs, checkpoint); err != nil {
+				klog.Errorf("backupSchedule %s/%s perform scheduled compact failed, err: %v", bs.GetNamespace(), bs.GetName(), err)
+			}
 		} else if err := bm.createCompact(bs, checkpoint, bm.now); err != nil {
 			klog.Errorf("backupSchedule %s/%s perform compact failed, err: %v", bs.GetNamespace(), bs.GetName(), err)
 		}
 	}()
 
 	// snapshot backup
+	if bs.IsSnapshotBackupPaused() {
+		return controller.IgnoreErrorf("backupSchedule %s/%s has been paused", bs.GetNamespace(), bs.GetName())
+	}
 	if err := bm.canPerformNextBackup(bs); err != nil {
 		return err
 	}
 
-	scheduledTime, err := getLastScheduledTime(bs, bm.now)
+	if _, ok := bs.Annotations[label.AnnBackupNowKey]; ok {
+		return bm.backupNow(bs)
+	}
+
+	scheduledTimes, err := getScheduledTimes(bs, bm.now)
 	if err != nil {
 		return err
 	}
+	if len(scheduledTimes) == 0 {
+		return nil
+	}
 
-	klog.Infof("backupSchedule %s/%s next scheduled time is %v", bs.GetNamespace(), bs.GetName(), scheduledTime)
+	runTime, missedTimes := bm.getRunTime(bs, scheduledTimes)
+	klog.Infof("backupSchedule %s/%s next scheduled time is %v, missed %d scheduled times", bs.GetNamespace(), bs.GetName(), runTime, len(missedTimes))
 
-	if scheduledTime == nil {
+	if runTime == nil {
+		recordMissedRuns(bs, missedTimes)
+		bs.Status.LastScheduleTime = &metav1.Time{Time: missedTimes[len(missedTimes)-1]}
 		return nil
 	}
-	*scheduledTime = bm.now()
+	scheduledTime := bm.now()
+
+	// Delete the last backup job for releasing the backup PVC
+	if err := bm.deleteLastBackupJob(bs); err != nil {
+		return err
+	}
+
+	backup, err := createBackup(bm.deps.BackupControl, bs, scheduledTime)
+	if err != nil {
+		return err
+	}
 
+	recordMissedRuns(bs, missedTimes)
+	bs.Status.LastBackup = backup.GetName()
+	bs.Status.LastBackupTime = &metav1.Time{Time: scheduledTime}
+	bs.Status.LastScheduleTime = &metav1.Time{Time: *runTime}
+	bs.Status.AllBackupCleanTime = nil
+	return nil
+}
+
+// backupNow creates a backup out of the schedule requested by the backup-now annotation,
+// the annotation is removed before the backup is created so that one request never creates
+// more than one backup even if the status fails to be persisted.
+func (bm *backupScheduleManager) backupNow(bs *v1alpha1.BackupSchedule) error {
 	// Delete the last backup job for releasing the backup PVC
 	if err := bm.deleteLastBackupJob(bs); err != nil {
 		return err
 	}
 
-	backup, err := createBackup(bm.deps.BackupControl, bs, *scheduledTime)
+	if err := bm.removeBackupNowAnnotation(bs); err != nil {
+		return err
+	}
+
+	now := bm.now()
+	backup, err := createBackup(bm.deps.BackupControl, bs, now)
 	if err != nil {
 		return err
 	}
+	klog.Infof("backupSchedule %s/%s created backup %s on demand", bs.GetNamespace(), bs.GetName(), backup.GetName())
 
 	bs.Status.LastBackup = backup.GetName()
-	bs.Status.LastBackupTime = &metav1.Time{Time: *scheduledTime}
+	bs.Status.LastBackupTime = &metav1.Time{Time: now}
 	bs.Status.AllBackupCleanTime = nil
 	return nil
 }
 
+// removeBackupNowAnnotation removes the backup-now annotation by a patch, which fails if the
+// annotation is changed in the meantime, e.g. by another request, so that the request is kept.
+func (bm *backupScheduleManager) removeBackupNowAnnotation(bs *v1alpha1.BackupSchedule) error {
+	annPath := "/metadata/annotations/" + strings.ReplaceAll(label.AnnBackupNowKey, "/", "~1")
+	patch := []map[string]interface{}{
+		{"op": "test", "path": annPath, "value": bs.Annotations[label.AnnBackupNowKey]},
+		{"op": "remove", "path": annPath},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	updated, err := bm.deps.Clientset.PingcapV1alpha1().BackupSchedules(bs.Namespace).Patch(context.TODO(), bs.Name, types.JSONPatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("backupSchedule %s/%s remove annotation %s failed, err: %v", bs.GetNamespace(), bs.GetName(), label.AnnBackupNowKey, err)
+	}
+	// keep the status updater from conflicting with the patch
+	bs.Annotations = updated.Annotations
+	bs.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// getRunTime returns the scheduled time to run a backup for according to the missed run
+// policy, and the scheduled times that are missed without running a backup. The run time
+// is nil if no backup should be run.
+func (bm *backupScheduleManager) getRunTime(bs *v1alpha1.BackupSchedule, scheduledTimes []time.Time) (*time.Time, []time.Time) {
+	last := scheduledTimes[len(scheduledTimes)-1]
+	switch bs.GetMissedRunPolicy() {
+	case v1alpha1.MissedRunPolicyRunAll:
+		// run the earliest one, the others are run by the following syncs one after another
+		first := scheduledTimes[0]
+		return &first, nil
+	case v1alpha1.MissedRunPolicySkip:
+		if bm.now().Sub(last) > missedRunDeadline {
+			return nil, scheduledTimes
+		}
+		return &last, scheduledTimes[:len(scheduledTimes)-1]
+	default:
+		return &last, scheduledTimes[:len(scheduledTimes)-1]
+	}
+}
+
+// recordMissedRuns records the scheduled times missed without running a backup in status.
+func recordMissedRuns(bs *v1alpha1.BackupSchedule, missedTimes []time.Time) {
+	if len(missedTimes) == 0 {
+		return
+	}
+	klog.Warningf("backupSchedule %s/%s missed %d scheduled times from %v to %v", bs.GetNamespace(), bs.GetName(),
+		len(missedTimes), missedTimes[0], missedTimes[len(missedTimes)-1])
+	bs.Status.MissedRuns += int32(len(missedTimes))
+	bs.Status.LastMissedRunTime = &metav1.Time{Time: missedTimes[len(missedTimes)-1]}
+}
+
 func (bm *backupScheduleManager) deleteLastBackupJob(bs *v1alpha1.BackupSchedule) error {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
@@ -352,6 +588,61 @@ func (bm *backupScheduleManager) createLogBackup(bs *v1alpha1.BackupSchedule) er
 	return nil
 }
 
+// syncLogBackupPause pauses the log backup of the schedule if it's paused by the pause mode, and
+// resumes it once the pause mode doesn't pause it anymore. The log backup task is paused and
+// resumed by BR, which is run by the backup controller as the log subcommand changes. The log
+// backups stopped or paused by the users are left as is.
+func (bm *backupScheduleManager) syncLogBackupPause(bs *v1alpha1.BackupSchedule) error {
+	pause := bs.IsLogBackupPaused()
+	if bs.Status.LogBackup == nil {
+		bs.Status.LogBackupPaused = false
+		return nil
+	}
+	if pause == bs.Status.LogBackupPaused {
+		return nil
+	}
+
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+	logBackupName := *bs.Status.LogBackup
+	logBackup, err := bm.deps.BackupControl.GetBackup(&v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      logBackupName,
+		},
+	})
+	if errors.IsNotFound(err) {
+		bs.Status.LogBackupPaused = false
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("backup schedule %s/%s, get log backup %s failed, err: %v", ns, bsName, logBackupName, err)
+	}
+	logBackup = logBackup.DeepCopy()
+
+	if !pause {
+		if logBackup.Spec.LogSubcommand == v1alpha1.LogPauseCommand {
+			if err := bm.deps.BackupControl.UpdateLogSubcommand(logBackup, v1alpha1.LogStartCommand); err != nil {
+				return fmt.Errorf("backup schedule %s/%s, resume log backup %s failed, err: %v", ns, bsName, logBackupName, err)
+			}
+			klog.Infof("backup schedule %s/%s, resume log backup %s", ns, bsName, logBackupName)
+		}
+		bs.Status.LogBackupPaused = false
+		return nil
+	}
+
+	running := logBackup.Spec.LogSubcommand == "" || logBackup.Spec.LogSubcommand == v1alpha1.LogStartCommand
+	if !running || logBackup.Spec.LogStop || v1alpha1.IsLogBackupAlreadyStop(logBackup) {
+		return nil
+	}
+	if err := bm.deps.BackupControl.UpdateLogSubcommand(logBackup, v1alpha1.LogPauseCommand); err != nil {
+		return fmt.Errorf("backup schedule %s/%s, pause log backup %s failed, err: %v", ns, bsName, logBackupName, err)
+	}
+	klog.Infof("backup schedule %s/%s, pause log backup %s", ns, bsName, logBackupName)
+	bs.Status.LogBackupPaused = true
+	return nil
+}
+
 func (bm *backupScheduleManager) getLogBackupCheckpoint(bs *v1alpha1.BackupSchedule) (*time.Time, error) {
 	if bs.Spec.LogBackupTemplate == nil || bs.Status.LogBackup == nil {
 		return nil, nil
@@ -402,16 +693,29 @@ func (bm *backupScheduleManager) getLogBackupCheckpoint(bs *v1alpha1.BackupSched
 // getLastScheduledTime return the newest time need to be scheduled according last backup time.
 // the return time is not before now and return nil if there's no such time.
 func getLastScheduledTime(bs *v1alpha1.BackupSchedule, nowFn nowFn) (*time.Time, error) {
+	scheduledTimes, err := getScheduledTimes(bs, nowFn)
+	if err != nil || len(scheduledTimes) == 0 {
+		return nil, err
+	}
+	scheduledTime := scheduledTimes[len(scheduledTimes)-1]
+	return &scheduledTime, nil
+}
+
+// getScheduledTimes return all the times need to be scheduled since the last handled scheduled
+// time in ascending order, the return times are not after now.
+func getScheduledTimes(bs *v1alpha1.BackupSchedule, nowFn nowFn) ([]time.Time, error) {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
 
-	sched, err := cron.ParseStandard(bs.Spec.Schedule)
+	sched, err := util.ParseCronSchedule(bs.Spec.Schedule, bs.Spec.TimeZone)
 	if err != nil {
 		return nil, fmt.Errorf("parse backup schedule %s/%s cron format %s failed, err: %v", ns, bsName, bs.Spec.Schedule, err)
 	}
 
 	var earliestTime time.Time
-	if bs.Status.LastBackupTime != nil {
+	if bs.Status.LastScheduleTime != nil {
+		earliestTime = bs.Status.LastScheduleTime.Time
+	} else if bs.Status.LastBackupTime != nil {
 		earliestTime = bs.Status.LastBackupTime.Time
 	} else if bs.Status.AllBackupCleanTime != nil {
 		// Recovery from a long paused backup schedule may cause problem like "incorrect clock",
@@ -452,16 +756,15 @@ func getLastScheduledTime(bs *v1alpha1.BackupSchedule, nowFn nowFn) (*time.Time,
 			klog.Warning("Too many missed start backup schedule time (> 1000). Fail current one.")
 			offset := sched.Next(t).Sub(t)
 			bs.Status.LastBackupTime = &metav1.Time{Time: time.Now().Add(-offset)}
+			bs.Status.LastScheduleTime = nil
 			return nil, nil
 		}
 	}
 
 	if len(scheduledTimes) == 0 {
 		klog.V(4).Infof("unmet backup schedule %s/%s start time, waiting for the next backup schedule period", ns, bsName)
-		return nil, nil
 	}
-	scheduledTime := scheduledTimes[len(scheduledTimes)-1]
-	return &scheduledTime, nil
+	return scheduledTimes, nil
 }
 
 func buildBackup(bs *v1alpha1.BackupSchedule, timestamp time.Time) *v1alpha1.Backup {
@@ -673,12 +976,18 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(bs *v1alpha1.BackupSc
 		}
 	}
 
+	var deleteCount int
 	for _, backup := range expiredBackups {
+		if backup.Spec.DeletionProtection {
+			klog.Infof("backup schedule %s/%s skip gc backup %s, it's protected from deletion", ns, bsName, backup.GetName())
+			continue
+		}
 		// delete the expired backup
 		if err = bm.deps.BackupControl.DeleteBackup(backup); err != nil {
 			klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
 			return
 		}
+		deleteCount += 1
 		klog.Infof("backup schedule %s/%s gc backup %s success", ns, bsName, backup.GetName())
 	}
 
@@ -704,7 +1013,7 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(bs *v1alpha1.BackupSc
 		klog.Infof("backup schedule %s/%s truncate log backup %s success, truncateTSO %d", ns, bsName, logBackup.GetName(), truncateTSO)
 	}
 
-	if len(expiredBackups) == len(backupsList) && len(expiredBackups) > 0 {
+	if deleteCount == len(backupsList) && deleteCount > 0 {
 		// All backups have been deleted, so the last backup information in the backupSchedule should be reset
 		bm.resetLastBackup(bs)
 	}
@@ -1019,6 +1328,10 @@ func (bm *backupScheduleManager) backupGCByMaxBackups(bs *v1alpha1.BackupSchedul
 		if i < int(*bs.Spec.MaxBackups) {
 			continue
 		}
+		if backup.Spec.DeletionProtection {
+			klog.Infof("backup schedule %s/%s skip gc backup %s, it's protected from deletion", ns, bsName, backup.GetName())
+			continue
+		}
 		// delete the backup
 		if err := bm.deps.BackupControl.DeleteBackup(backup); err != nil {
 			klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
@@ -1070,10 +1383,134 @@ func (bm *backupScheduleManager) compactGCByMaxBackups(bs *v1alpha1.BackupSchedu
 
 func (bm *backupScheduleManager) resetLastBackup(bs *v1alpha1.BackupSchedule) {
 	bs.Status.LastBackupTime = nil
+	bs.Status.LastScheduleTime = nil
 	bs.Status.LastBackup = ""
 	bs.Status.AllBackupCleanTime = &metav1.Time{Time: bm.now()}
 }
 
+// refreshStorageUsage refreshes the storage usage of the schedule once a backup completes
+// or is deleted, the retention budget is refreshed on every sync. The sizes of the backups
+// are cached, so only the newly completed backups are listed in the sync loop. The objects in
+// local storage can't be listed by the controller, so the backups stored there are not counted.
+func (bm *backupScheduleManager) refreshStorageUsage(bs *v1alpha1.BackupSchedule) {
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+
+	backupsList, err := bm.getBackupList(bs)
+	if err != nil {
+		klog.Errorf("refreshStorageUsage failed, err: %s", err)
+		return
+	}
+
+	var keptCount int32
+	for _, backup := range backupsList {
+		if backup.DeletionTimestamp == nil {
+			keptCount++
+		}
+	}
+	ascBackups, _ := separateSnapshotBackupsAndLogBackup(backupsList)
+	var completed []*v1alpha1.Backup
+	for _, backup := range ascBackups {
+		if !v1alpha1.IsBackupComplete(backup) || backup.DeletionTimestamp != nil || backup.Spec.BR == nil {
+			continue
+		}
+		if backuputil.GetStorageType(backup.Spec.StorageProvider) == v1alpha1.BackupStorageTypeLocal {
+			continue
+		}
+		completed = append(completed, backup)
+	}
+	if len(completed) == 0 {
+		bs.Status.StorageUsage = nil
+		return
+	}
+
+	last := completed[len(completed)-1]
+	usage := bs.Status.StorageUsage.DeepCopy()
+	if usage == nil || usage.LastBackup != last.Name || usage.BackupCount != int32(len(completed)) {
+		var totalSize, lastSize int64
+		for _, backup := range completed {
+			size, err := bm.backupSize(backup)
+			if err != nil {
+				klog.Errorf("backup schedule %s/%s, list objects of backup %s failed, err: %v", ns, bsName, backup.GetName(), err)
+				return
+			}
+			totalSize += size
+			lastSize = size
+		}
+		usage = &v1alpha1.BackupScheduleStorageUsage{
+			LastBackup:             last.Name,
+			LastBackupSize:         lastSize,
+			LastBackupSizeReadable: humanize.Bytes(uint64(lastSize)),
+			TotalSize:              totalSize,
+			TotalSizeReadable:      humanize.Bytes(uint64(totalSize)),
+			BackupCount:            int32(len(completed)),
+			UpdateTime:             &metav1.Time{Time: bm.now()},
+		}
+	}
+
+	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred.
+	usage.RemainingBackups = nil
+	usage.OldestBackupExpireTime = nil
+	if bs.Spec.MaxReservedTime != nil {
+		reservedTime, err := time.ParseDuration(*bs.Spec.MaxReservedTime)
+		if err == nil {
+			commitTime, err := config.ParseTSStringToGoTime(completed[0].Status.CommitTs)
+			if err == nil && !commitTime.IsZero() {
+				usage.OldestBackupExpireTime = &metav1.Time{Time: commitTime.Add(reservedTime)}
+			}
+		}
+	} else if bs.Spec.MaxBackups != nil && *bs.Spec.MaxBackups > 0 {
+		remaining := *bs.Spec.MaxBackups - keptCount
+		if remaining < 0 {
+			remaining = 0
+		}
+		usage.RemainingBackups = &remaining
+	}
+	bs.Status.StorageUsage = usage
+}
+
+// backupSize returns the size of the objects of a completed backup, the size is cached for
+// storageUsageCacheTTL so only the backups completed since the last refresh are listed.
+func (bm *backupScheduleManager) backupSize(backup *v1alpha1.Backup) (int64, error) {
+	// the backups with the same name may be recreated, so the UID is part of the key
+	key := fmt.Sprintf("%s/%s/%s", backup.Namespace, backup.Name, backup.UID)
+	now := bm.now()
+	bm.sizeLock.Lock()
+	for k, entry := range bm.sizeCache {
+		if !now.Before(entry.expireTime) {
+			delete(bm.sizeCache, k)
+		}
+	}
+	entry, ok := bm.sizeCache[key]
+	bm.sizeLock.Unlock()
+	if ok {
+		return entry.size, nil
+	}
+
+	size, err := bm.objectsSize(backup.Namespace, backup.Spec.StorageProvider)
+	if err != nil {
+		return 0, err
+	}
+	bm.sizeLock.Lock()
+	bm.sizeCache[key] = backupSizeEntry{size: size, expireTime: now.Add(storageUsageCacheTTL)}
+	bm.sizeLock.Unlock()
+	return size, nil
+}
+
+// listObjectsSize returns the size of the objects of a backup by listing them in the backup storage
+func (bm *backupScheduleManager) listObjectsSize(ns string, provider v1alpha1.StorageProvider) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storageUsageListTimeout)
+	defer cancel()
+
+	cred := backuputil.GetStorageCredential(ns, provider, bm.deps.SecretLister)
+	backend, err := backuputil.NewStorageBackend(provider, cred)
+	if err != nil {
+		return 0, err
+	}
+	defer backend.Close()
+	return backend.ObjectsSize(ctx)
+}
+
 func (bm *backupScheduleManager) getBackupList(bs *v1alpha1.BackupSchedule) ([]*v1alpha1.Backup, error) {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
@@ -334,6 +334,214 @@ func TestGetLastScheduledTime(t *testing.T) {
 	g.Expect(getTime).ShouldNot(BeNil())
 }
 
+func TestMissedRunPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+
+	midnight := time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local)
+	newBackupSchedule := func(name string, policy v1alpha1.MissedRunPolicy) *v1alpha1.BackupSchedule {
+		bs := &v1alpha1.BackupSchedule{}
+		bs.Namespace = "ns"
+		bs.Name = name
+		bs.Spec.Schedule = "0 0 * * *" // Run at midnight every day
+		bs.Spec.MissedRunPolicy = policy
+		// the scheduled times of the last 3 days are missed
+		bs.Status.LastScheduleTime = &metav1.Time{Time: midnight.AddDate(0, 0, -3)}
+		return bs
+	}
+
+	t.Log("test RunOnce")
+	m.now = func() time.Time { return midnight.Add(time.Minute) }
+	bs := newBackupSchedule("runonce", "")
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(bs.Status.LastBackup).ShouldNot(BeEmpty())
+	g.Expect(bs.Status.LastScheduleTime.Time).Should(Equal(midnight))
+	g.Expect(bs.Status.MissedRuns).Should(Equal(int32(2)))
+	g.Expect(bs.Status.LastMissedRunTime.Time).Should(Equal(midnight.AddDate(0, 0, -1)))
+
+	t.Log("test Skip within the deadline")
+	bs = newBackupSchedule("skip", v1alpha1.MissedRunPolicySkip)
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(bs.Status.LastBackup).ShouldNot(BeEmpty())
+	g.Expect(bs.Status.LastScheduleTime.Time).Should(Equal(midnight))
+	g.Expect(bs.Status.MissedRuns).Should(Equal(int32(2)))
+
+	t.Log("test Skip beyond the deadline")
+	m.now = func() time.Time { return midnight.Add(time.Hour) }
+	bs = newBackupSchedule("skip-late", v1alpha1.MissedRunPolicySkip)
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(bs.Status.LastBackup).Should(BeEmpty())
+	g.Expect(bs.Status.LastScheduleTime.Time).Should(Equal(midnight))
+	g.Expect(bs.Status.MissedRuns).Should(Equal(int32(3)))
+	g.Expect(bs.Status.LastMissedRunTime.Time).Should(Equal(midnight))
+
+	t.Log("test RunAll")
+	bs = newBackupSchedule("runall", v1alpha1.MissedRunPolicyRunAll)
+	for i := 2; i >= 0; i-- {
+		now := midnight.Add(time.Hour + time.Duration(2-i)*time.Minute)
+		m.now = func() time.Time { return now }
+		g.Expect(m.Sync(bs)).Should(Succeed())
+		g.Expect(bs.Status.LastScheduleTime.Time).Should(Equal(midnight.AddDate(0, 0, -i)))
+		g.Expect(bs.Status.MissedRuns).Should(BeZero())
+		// complete the backup created
+		bk, err := helper.deps.Clientset.PingcapV1alpha1().Backups(bs.Namespace).Get(context.TODO(), bs.Status.LastBackup, metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+		v1alpha1.UpdateBackupCondition(&bk.Status, &v1alpha1.BackupCondition{
+			Type:   v1alpha1.BackupComplete,
+			Status: v1.ConditionTrue,
+		})
+		helper.updateBackup(bk)
+	}
+	// all the missed runs are caught up
+	lastBackup := bs.Status.LastBackup
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(bs.Status.LastBackup).Should(Equal(lastBackup))
+}
+
+func TestBackupNow(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+	m.now = func() time.Time { return now }
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "backupnow"
+	bs.Spec.Schedule = "0 0 * * *"
+	bs.Status.LastScheduleTime = &metav1.Time{Time: now.Add(-12 * time.Hour)}
+
+	// no backup is created out of the schedule
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(bs.Status.LastBackup).Should(BeEmpty())
+
+	// no backup is created if the annotation fails to be removed
+	bs.Annotations = map[string]string{label.AnnBackupNowKey: "true"}
+	g.Expect(m.Sync(bs)).ShouldNot(Succeed())
+	g.Expect(bs.Status.LastBackup).Should(BeEmpty())
+	g.Expect(bs.Annotations).Should(HaveKey(label.AnnBackupNowKey))
+
+	_, err := helper.deps.Clientset.PingcapV1alpha1().BackupSchedules(bs.Namespace).Create(context.TODO(), bs, metav1.CreateOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(bs.Status.LastBackup).ShouldNot(BeEmpty())
+	g.Expect(bs.Status.LastBackupTime.Time).Should(Equal(now))
+	g.Expect(bs.Status.LastScheduleTime.Time).Should(Equal(now.Add(-12 * time.Hour)))
+	g.Expect(bs.Annotations).ShouldNot(HaveKey(label.AnnBackupNowKey))
+	_, err = helper.deps.Clientset.PingcapV1alpha1().Backups(bs.Namespace).Get(context.TODO(), bs.Status.LastBackup, metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	persisted, err := helper.deps.Clientset.PingcapV1alpha1().BackupSchedules(bs.Namespace).Get(context.TODO(), bs.Name, metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(persisted.Annotations).ShouldNot(HaveKey(label.AnnBackupNowKey))
+}
+
+func TestPauseMode(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+	m.now = func() time.Time { return now }
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "pause"
+	bs.Spec.Schedule = "0 0 * * *"
+	bs.Spec.LogBackupTemplate = &v1alpha1.BackupSpec{Mode: v1alpha1.BackupModeLog}
+	bs.Status.LastScheduleTime = &metav1.Time{Time: now.Add(-12 * time.Hour)}
+
+	logBackup := &v1alpha1.Backup{}
+	logBackup.Namespace = bs.Namespace
+	logBackup.Name = bs.GetLogBackupCRDName()
+	logBackup.Spec.Mode = v1alpha1.BackupModeLog
+	logBackup.Spec.LogSubcommand = v1alpha1.LogStartCommand
+	helper.createBackup(logBackup)
+	bs.Status.LogBackup = &logBackup.Name
+	bs.Status.LogBackupStartTs = &metav1.Time{Time: now.Add(-time.Hour)}
+
+	getLogSubcommand := func() v1alpha1.LogSubCommandType {
+		bk, err := helper.deps.Clientset.PingcapV1alpha1().Backups(bs.Namespace).Get(context.TODO(), logBackup.Name, metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+		return bk.Spec.LogSubcommand
+	}
+	expectPaused := func(err error) {
+		g.Expect(err).Should(BeAssignableToTypeOf(&controller.IgnoreError{}))
+		g.Expect(err.Error()).Should(MatchRegexp(".*has been paused.*"))
+	}
+
+	// the snapshot backups are paused by default, the log backup keeps running
+	bs.Spec.Pause = true
+	expectPaused(m.Sync(bs))
+	g.Expect(getLogSubcommand()).Should(Equal(v1alpha1.LogStartCommand))
+	g.Expect(bs.Status.LogBackupPaused).Should(BeFalse())
+
+	// the log backup is paused only
+	bs.Spec.PauseMode = v1alpha1.BackupSchedulePauseModeLog
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(getLogSubcommand()).Should(Equal(v1alpha1.LogPauseCommand))
+	g.Expect(bs.Status.LogBackupPaused).Should(BeTrue())
+
+	// both are paused
+	bs.Spec.PauseMode = v1alpha1.BackupSchedulePauseModeAll
+	expectPaused(m.Sync(bs))
+	g.Expect(getLogSubcommand()).Should(Equal(v1alpha1.LogPauseCommand))
+	g.Expect(bs.Status.LogBackupPaused).Should(BeTrue())
+
+	// the log backup paused by the schedule is resumed
+	bs.Spec.Pause = false
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(getLogSubcommand()).Should(Equal(v1alpha1.LogStartCommand))
+	g.Expect(bs.Status.LogBackupPaused).Should(BeFalse())
+
+	// the log backup paused by the users is not resumed by the schedule
+	bk, err := helper.deps.Clientset.PingcapV1alpha1().Backups(bs.Namespace).Get(context.TODO(), logBackup.Name, metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	bk.Spec.LogSubcommand = v1alpha1.LogPauseCommand
+	helper.updateBackup(bk)
+	bs.Spec.Pause = true
+	bs.Spec.PauseMode = v1alpha1.BackupSchedulePauseModeLog
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(bs.Status.LogBackupPaused).Should(BeFalse())
+	bs.Spec.Pause = false
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	g.Expect(getLogSubcommand()).Should(Equal(v1alpha1.LogPauseCommand))
+}
+
+func TestBackupGCDeletionProtection(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bsname"
+	bs.Spec.MaxBackups = pointer.Int32Ptr(1)
+
+	now := time.Now()
+	for i, name := range []string{"held", "expired", "latest"} {
+		bk := &v1alpha1.Backup{}
+		bk.Namespace = bs.Namespace
+		bk.Name = name
+		bk.CreationTimestamp = metav1.NewTime(now.Add(time.Duration(i) * time.Hour))
+		bk.Labels = label.NewBackupSchedule().Instance(bs.Name).BackupSchedule(bs.Name)
+		bk.Spec.DeletionProtection = name == "held"
+		helper.createBackup(bk)
+	}
+
+	// the backup protected from deletion is kept although it exceeds maxBackups
+	m.backupGC(bs)
+	bks := helper.checkBacklist(bs.Namespace, 2, false)
+	var names []string
+	for _, bk := range bks.Items {
+		names = append(names, bk.Name)
+	}
+	g.Expect(names).Should(ConsistOf("held", "latest"))
+}
+
 func TestBuildBackup(t *testing.T) {
 	now := time.Now()
 	var get *v1alpha1.Backup
@@ -483,6 +691,100 @@ func TestSyncWithCompactRoutineCompact(t *testing.T) {
 	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
 }
 
+func TestSyncWithScheduledCompact(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Date(2024, 1, 1, 3, 10, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		windows       []string
+		maxConcurrent int32
+		running       bool
+		scheduled     time.Time
+		expectCompact bool
+	}{
+		{
+			name:          "due in the window",
+			windows:       []string{"02:00-04:00"},
+			expectCompact: true,
+		},
+		{
+			name:          "due out of the windows",
+			windows:       []string{"04:00-05:00", "22:00-02:00"},
+			expectCompact: false,
+		},
+		{
+			name:          "concurrency limit reached",
+			maxConcurrent: 1,
+			running:       true,
+			expectCompact: false,
+		},
+		{
+			name:          "concurrency limit not reached",
+			maxConcurrent: 2,
+			running:       true,
+			expectCompact: true,
+		},
+		{
+			name:          "not due",
+			scheduled:     time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+			expectCompact: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Log(tt.name)
+		helper := newHelper(t)
+		deps := helper.deps
+		m := NewBackupScheduleManager(deps).(*backupScheduleManager)
+		m.now = func() time.Time { return now }
+
+		bs := &v1alpha1.BackupSchedule{}
+		bs.Namespace = "ns"
+		bs.Name = "bsname"
+		bs.Spec.Schedule = "0 0 * * *"
+		bs.Spec.TimeZone = "UTC"
+		bs.Spec.CompactSchedule = &v1alpha1.CompactSchedule{
+			Schedule:              "0 * * * *",
+			Windows:               tt.windows,
+			MaxConcurrentCompacts: tt.maxConcurrent,
+		}
+		bs.Spec.CompactBackupTemplate = &v1alpha1.CompactSpec{Concurrency: 4}
+		bs.Spec.LogBackupTemplate = &v1alpha1.BackupSpec{Mode: v1alpha1.BackupModeLog}
+		logBackup := buildLogBackup(bs, now.Add(-3*time.Hour))
+		logBackup.Status.CommitTs = getTSOStr(now.Add(-3 * time.Hour).Unix())
+		logBackup.Status.LogCheckpointTs = getTSOStr(now.Unix())
+		helper.createBackup(logBackup)
+		bs.Status.LogBackup = &logBackup.Name
+		bs.Status.LogBackupStartTs = &metav1.Time{Time: now.Add(-3 * time.Hour)}
+		bs.Status.LastBackupTime = &metav1.Time{Time: now}
+		if !tt.scheduled.IsZero() {
+			bs.Status.LastCompactScheduleTime = &metav1.Time{Time: tt.scheduled}
+		}
+		if tt.running {
+			running := &v1alpha1.CompactBackup{}
+			running.Namespace = bs.Namespace
+			running.Name = "running"
+			err := deps.InformerFactory.Pingcap().V1alpha1().CompactBackups().Informer().GetIndexer().Add(running)
+			g.Expect(err).Should(BeNil())
+		}
+
+		err := m.Sync(bs)
+		g.Expect(err).Should(BeNil())
+		if !tt.expectCompact {
+			g.Expect(bs.Status.LastCompact).Should(BeEmpty(), tt.name)
+			helper.close()
+			continue
+		}
+		compact, err := deps.CompactBackupLister.CompactBackups(bs.Namespace).Get(bs.Status.LastCompact)
+		g.Expect(err).Should(BeNil(), tt.name)
+		g.Expect(compact.Spec.StartTs).Should(Equal(now.Add(-3 * time.Hour).Format(v1alpha1.BackupTimestampFormat)))
+		g.Expect(compact.Spec.EndTs).Should(Equal(now.Format(v1alpha1.BackupTimestampFormat)))
+		g.Expect(bs.Status.LastCompactScheduleTime.Time.Equal(time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC))).Should(BeTrue(), tt.name)
+		helper.close()
+	}
+}
+
 func TestCalculateExpiredBackupsWithLogBackup(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testCase struct {
@@ -591,6 +893,90 @@ type helper struct {
 	stop chan struct{}
 }
 
+func TestRefreshStorageUsage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+
+	sizes := map[string]int64{"b1": 1024, "b2": 2048, "b3": 4096}
+	listCount := 0
+	m.objectsSize = func(ns string, provider v1alpha1.StorageProvider) (int64, error) {
+		listCount++
+		return sizes[provider.S3.Prefix], nil
+	}
+
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bsname"
+	bs.Spec.MaxBackups = pointer.Int32Ptr(5)
+
+	now := time.Now()
+	newBackup := func(name string, created time.Time, complete bool) *v1alpha1.Backup {
+		bk := fakeBackup(pointer.Int64Ptr(created.Unix()))
+		bk.Namespace = bs.Namespace
+		bk.Name = name
+		bk.CreationTimestamp = metav1.NewTime(created)
+		bk.Labels = label.NewBackupSchedule().Instance(bs.Name).BackupSchedule(bs.Name)
+		bk.Spec.BR = &v1alpha1.BRConfig{}
+		bk.Spec.S3 = &v1alpha1.S3StorageProvider{Prefix: name}
+		if complete {
+			bk.Status.Conditions = append(bk.Status.Conditions, v1alpha1.BackupCondition{
+				Type:   v1alpha1.BackupComplete,
+				Status: v1.ConditionTrue,
+			})
+		}
+		return bk
+	}
+	b1 := newBackup("b1", now.Add(-2*time.Hour), true)
+	b2 := newBackup("b2", now.Add(-time.Hour), true)
+	b3 := newBackup("b3", now, false)
+	helper.createBackup(b1)
+	helper.createBackup(b2)
+	helper.createBackup(b3)
+
+	// the running backup is not counted
+	m.refreshStorageUsage(bs)
+	usage := bs.Status.StorageUsage
+	g.Expect(usage).ShouldNot(BeNil())
+	g.Expect(usage.LastBackup).Should(Equal("b2"))
+	g.Expect(usage.LastBackupSize).Should(Equal(int64(2048)))
+	g.Expect(usage.TotalSize).Should(Equal(int64(3072)))
+	g.Expect(usage.TotalSizeReadable).Should(Equal("3.1 kB"))
+	g.Expect(usage.BackupCount).Should(Equal(int32(2)))
+	g.Expect(*usage.RemainingBackups).Should(Equal(int32(2)))
+	g.Expect(usage.OldestBackupExpireTime).Should(BeNil())
+	g.Expect(listCount).Should(Equal(2))
+
+	// the objects are not listed again before another backup completes
+	bs.Spec.MaxBackups = nil
+	bs.Spec.MaxReservedTime = pointer.StringPtr("24h")
+	m.refreshStorageUsage(bs)
+	g.Expect(listCount).Should(Equal(2))
+	g.Expect(bs.Status.StorageUsage.RemainingBackups).Should(BeNil())
+	g.Expect(bs.Status.StorageUsage.OldestBackupExpireTime.Unix()).Should(Equal(b1.CreationTimestamp.Add(24 * time.Hour).Unix()))
+
+	b3 = newBackup("b3", now, true)
+	helper.updateBackup(b3)
+	g.Eventually(func() bool {
+		bk, err := helper.deps.BackupLister.Backups(bs.Namespace).Get("b3")
+		return err == nil && v1alpha1.IsBackupComplete(bk)
+	}, time.Second*10).Should(BeTrue())
+	// only the newly completed backup is listed, the sizes of the others are cached
+	m.refreshStorageUsage(bs)
+	g.Expect(listCount).Should(Equal(3))
+	g.Expect(bs.Status.StorageUsage.LastBackup).Should(Equal("b3"))
+	g.Expect(bs.Status.StorageUsage.TotalSize).Should(Equal(int64(7168)))
+	g.Expect(bs.Status.StorageUsage.BackupCount).Should(Equal(int32(3)))
+
+	// the backups are listed again after the cache expires
+	m.now = func() time.Time { return now.Add(storageUsageCacheTTL + time.Hour) }
+	bs.Status.StorageUsage = nil
+	m.refreshStorageUsage(bs)
+	g.Expect(listCount).Should(Equal(6))
+	g.Expect(bs.Status.StorageUsage.TotalSize).Should(Equal(int64(7168)))
+}
+
 func newHelper(t *testing.T) *helper {
 	deps := controller.NewSimpleClientDependencies()
 	stop := make(chan struct{})
@@ -0,0 +1,182 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupschedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+// transitionStorageClass creates the jobs to transition the data of the backups completed for more
+// than spec.storageClassTransition.days to the storage class. Only one job runs at a time for a
+// schedule, and the failed jobs are not retried until they are deleted.
+func (bm *backupScheduleManager) transitionStorageClass(bs *v1alpha1.BackupSchedule) {
+	transition := bs.Spec.StorageClassTransition
+	if transition == nil {
+		return
+	}
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+	if v1alpha1.IsArchiveStorageClass(transition.StorageClass) {
+		klog.Warningf("backup schedule %s/%s, the backup data isn't transitioned to the archive storage class %s, as BR can't read it without restoring the objects first", ns, bsName, transition.StorageClass)
+		return
+	}
+
+	backupsList, err := bm.getBackupList(bs)
+	if err != nil {
+		klog.Errorf("transitionStorageClass failed, err: %s", err)
+		return
+	}
+	ascBackups, _ := separateSnapshotBackupsAndLogBackup(backupsList)
+	deadline := bm.now().Add(-time.Duration(transition.Days) * 24 * time.Hour)
+	for _, backup := range ascBackups {
+		if !needStorageClassTransition(backup, transition.StorageClass, deadline) {
+			continue
+		}
+		jobName := backup.GetStorageClassTransitionJobName(transition.StorageClass)
+		job, err := bm.deps.JobLister.Jobs(ns).Get(jobName)
+		if err == nil {
+			if !isJobFinished(job) {
+				return
+			}
+			// the backup status is not updated yet, or the job is failed
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			klog.Errorf("backup schedule %s/%s, get job %s/%s failed, err: %v", ns, bsName, ns, jobName, err)
+			return
+		}
+
+		job, reason, err := bm.makeStorageClassTransitionJob(backup, transition.StorageClass)
+		if err != nil {
+			klog.Errorf("backup schedule %s/%s, make storage class transition job for backup %s failed, reason: %s, err: %v", ns, bsName, backup.GetName(), reason, err)
+			return
+		}
+		if err := bm.deps.JobControl.CreateJob(backup, job); err != nil {
+			klog.Errorf("backup schedule %s/%s, create storage class transition job for backup %s failed, err: %v", ns, bsName, backup.GetName(), err)
+			return
+		}
+		klog.Infof("backup schedule %s/%s, transition the data of backup %s to storage class %s", ns, bsName, backup.GetName(), transition.StorageClass)
+		return
+	}
+}
+
+// needStorageClassTransition returns whether the data of the backup should be transitioned to the storage class
+func needStorageClassTransition(backup *v1alpha1.Backup, storageClass string, deadline time.Time) bool {
+	if !v1alpha1.IsBackupComplete(backup) || backup.DeletionTimestamp != nil || backup.Status.BackupPath == "" {
+		return false
+	}
+	// the data of volume snapshot backups are the snapshots of the volumes
+	if backup.Spec.Mode == v1alpha1.BackupModeVolumeSnapshot || backup.Status.StorageClass == storageClass {
+		return false
+	}
+	switch backuputil.GetStorageType(backup.Spec.StorageProvider) {
+	case v1alpha1.BackupStorageTypeS3, v1alpha1.BackupStorageTypeGcs, v1alpha1.BackupStorageTypeAzblob:
+	default:
+		return false
+	}
+	return !backup.Status.TimeCompleted.IsZero() && backup.Status.TimeCompleted.Time.Before(deadline)
+}
+
+func (bm *backupScheduleManager) makeStorageClassTransitionJob(backup *v1alpha1.Backup, storageClass string) (*batchv1.Job, string, error) {
+	ns := backup.GetNamespace()
+	name := backup.GetName()
+
+	envVars, reason, err := backuputil.GenerateStorageCertEnv(ns, backup.Spec.UseKMS, backup.Spec.StorageProvider, bm.deps.SecretLister)
+	if err != nil {
+		return nil, reason, err
+	}
+	envVars = util.AppendOverwriteEnv(envVars, backup.Spec.Env)
+
+	args := []string{
+		"transition",
+		fmt.Sprintf("--namespace=%s", ns),
+		fmt.Sprintf("--backupName=%s", name),
+		fmt.Sprintf("--storageClass=%s", storageClass),
+	}
+
+	serviceAccount := constants.DefaultServiceAccountName
+	if backup.Spec.ServiceAccount != "" {
+		serviceAccount = backup.Spec.ServiceAccount
+	}
+
+	jobLabels := util.CombineStringMap(label.NewBackup().Instance(backup.GetInstanceName()).TransitionJob().Backup(name), backup.Labels)
+	podSpec := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      jobLabels,
+			Annotations: backup.Annotations,
+		},
+		Spec: corev1.PodSpec{
+			SecurityContext:    backup.Spec.PodSecurityContext,
+			ServiceAccountName: serviceAccount,
+			Containers: []corev1.Container{
+				{
+					Name:            label.BackupJobLabelVal,
+					Image:           bm.deps.CLIConfig.TiDBBackupManagerImage,
+					Args:            args,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Env:             util.AppendEnvIfPresent(envVars, "TZ"),
+					Resources:       backup.Spec.ResourceRequirements,
+					VolumeMounts:    backup.Spec.AdditionalVolumeMounts,
+				},
+			},
+			RestartPolicy:     corev1.RestartPolicyNever,
+			Tolerations:       backup.Spec.Tolerations,
+			ImagePullSecrets:  backup.Spec.ImagePullSecrets,
+			Affinity:          backup.Spec.Affinity,
+			Volumes:           backup.Spec.AdditionalVolumes,
+			PriorityClassName: backup.Spec.PriorityClassName,
+			PreemptionPolicy:  backup.Spec.PreemptionPolicy,
+		},
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        backup.GetStorageClassTransitionJobName(storageClass),
+			Namespace:   ns,
+			Labels:      jobLabels,
+			Annotations: backup.Annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				controller.GetBackupOwnerRef(backup),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32Ptr(constants.DefaultBackoffLimit),
+			Template:     *podSpec,
+		},
+	}
+	return job, "", nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
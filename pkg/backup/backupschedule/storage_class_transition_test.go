@@ -0,0 +1,99 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupschedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestTransitionStorageClass(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bsname"
+	bs.Spec.StorageClassTransition = &v1alpha1.StorageClassTransition{Days: 7, StorageClass: "GLACIER_IR"}
+
+	newBackup := func(name string, completed time.Time, storageClass string) *v1alpha1.Backup {
+		bk := &v1alpha1.Backup{}
+		bk.Namespace = bs.Namespace
+		bk.Name = name
+		bk.CreationTimestamp = metav1.NewTime(completed.Add(-time.Hour))
+		bk.Labels = label.NewBackupSchedule().Instance(bs.Name).BackupSchedule(bs.Name)
+		bk.Spec.BR = &v1alpha1.BRConfig{}
+		bk.Spec.S3 = &v1alpha1.S3StorageProvider{Bucket: "bucket", Prefix: name}
+		bk.Status.BackupPath = "s3://bucket/" + name
+		bk.Status.TimeCompleted = metav1.NewTime(completed)
+		bk.Status.StorageClass = storageClass
+		bk.Status.Conditions = []v1alpha1.BackupCondition{{Type: v1alpha1.BackupComplete, Status: corev1.ConditionTrue}}
+		return bk
+	}
+	helper.createBackup(newBackup("b1", now.Add(-20*24*time.Hour), "GLACIER_IR"))
+	helper.createBackup(newBackup("b2", now.Add(-10*24*time.Hour), ""))
+	helper.createBackup(newBackup("b3", now.Add(-9*24*time.Hour), ""))
+	helper.createBackup(newBackup("b4", now.Add(-24*time.Hour), ""))
+
+	listJobs := func() []string {
+		jobs, err := helper.deps.JobLister.Jobs(bs.Namespace).List(labels.Everything())
+		g.Expect(err).Should(BeNil())
+		var names []string
+		for _, job := range jobs {
+			names = append(names, job.Name)
+		}
+		return names
+	}
+
+	// the job is created for the oldest backup not transitioned yet
+	m.transitionStorageClass(bs)
+	g.Eventually(listJobs, time.Second*10).Should(ConsistOf("transition-b2-glacier-ir"))
+	job, err := helper.deps.JobLister.Jobs(bs.Namespace).Get("transition-b2-glacier-ir")
+	g.Expect(err).Should(BeNil())
+	g.Expect(job.Spec.Template.Spec.Containers[0].Args).Should(ContainElement("--storageClass=GLACIER_IR"))
+
+	// only one job runs at a time
+	m.transitionStorageClass(bs)
+	g.Consistently(listJobs, time.Second).Should(ConsistOf("transition-b2-glacier-ir"))
+
+	// the next backup is transitioned after the job finishes
+	job = job.DeepCopy()
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	_, err = helper.deps.KubeClientset.BatchV1().Jobs(bs.Namespace).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Eventually(func() bool {
+		job, err := helper.deps.JobLister.Jobs(bs.Namespace).Get("transition-b2-glacier-ir")
+		return err == nil && isJobFinished(job)
+	}, time.Second*10).Should(BeTrue())
+	m.transitionStorageClass(bs)
+	g.Eventually(listJobs, time.Second*10).Should(ConsistOf("transition-b2-glacier-ir", "transition-b3-glacier-ir"))
+
+	// the data isn't transitioned to an archive storage class
+	bs.Spec.StorageClassTransition.StorageClass = "DEEP_ARCHIVE"
+	m.transitionStorageClass(bs)
+	g.Consistently(listJobs, time.Second).Should(ConsistOf("transition-b2-glacier-ir", "transition-b3-glacier-ir"))
+}
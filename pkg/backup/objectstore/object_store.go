@@ -0,0 +1,397 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	defaultImage   = "minio/minio"
+	defaultStorage = "10Gi"
+	defaultPort    = 9000
+	dataVolumeName = "data"
+	dataMountPath  = "/data"
+)
+
+// credentialsKeys are the keys of the access key and the secret key in the credentials Secret,
+// in order of preference. The BR jobs only read the first pair.
+var credentialsKeys = [][2]string{
+	{constants.S3AccessKey, constants.S3SecretKey},
+	// the root user of the MinIO chart
+	{"rootUser", "rootPassword"},
+	// the root user of the Bitnami MinIO chart
+	{"root-user", "root-password"},
+}
+
+// ensureBucket creates the bucket of the S3 storage provider if it does not exist, it is replaced in tests
+var ensureBucket = func(ns string, provider *v1alpha1.S3StorageProvider, secretLister corelisterv1.SecretLister) error {
+	storageProvider := v1alpha1.StorageProvider{S3: provider}
+	cred := backuputil.GetStorageCredential(ns, storageProvider, secretLister)
+	backend, err := backuputil.NewStorageBackend(storageProvider, cred)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	cli, ok := backend.AsS3()
+	if !ok {
+		return fmt.Errorf("storage backend of %s is not S3", provider.Endpoint)
+	}
+	_, err = cli.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(backend.GetBucket())})
+	if aerr, ok := err.(awserr.Error); ok {
+		if aerr.Code() == s3.ErrCodeBucketAlreadyOwnedByYou || aerr.Code() == s3.ErrCodeBucketAlreadyExists {
+			return nil
+		}
+	}
+	return err
+}
+
+// Resolver discovers the endpoint and the credentials of the in-cluster object store of backup and restore,
+// and provisions a minimal MinIO as the object store if required.
+type Resolver interface {
+	// Resolve fills the endpoint and the secret of the in-cluster object store into the S3 storage provider,
+	// and returns whether the provider is changed. It returns a RequeueError until the object store is ready.
+	Resolve(obj runtime.Object, ns string, provider *v1alpha1.S3StorageProvider) (bool, error)
+}
+
+type resolver struct {
+	deps *controller.Dependencies
+}
+
+// NewResolver returns a Resolver
+func NewResolver(deps *controller.Dependencies) Resolver {
+	return &resolver{deps: deps}
+}
+
+func (r *resolver) Resolve(obj runtime.Object, ns string, provider *v1alpha1.S3StorageProvider) (bool, error) {
+	if provider == nil || provider.InCluster == nil {
+		return false, nil
+	}
+	store := provider.InCluster
+
+	if store.Provision != nil {
+		if err := r.provision(obj, ns, store); err != nil {
+			return false, err
+		}
+	}
+
+	svc, err := r.deps.ServiceLister.Services(ns).Get(store.ServiceName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, controller.RequeueErrorf("service %s/%s of the in-cluster object store does not exist", ns, store.ServiceName)
+		}
+		return false, fmt.Errorf("get service %s/%s of the in-cluster object store failed, err: %v", ns, store.ServiceName, err)
+	}
+	port := store.Port
+	if port == 0 {
+		if len(svc.Spec.Ports) == 0 {
+			return false, fmt.Errorf("service %s/%s of the in-cluster object store has no port", ns, svc.Name)
+		}
+		port = svc.Spec.Ports[0].Port
+	}
+	secretName, err := r.resolveCredentials(obj, ns, store)
+	if err != nil {
+		return false, err
+	}
+
+	resolved := provider.DeepCopy()
+	resolved.Endpoint = fmt.Sprintf("http://%s.%s.svc:%d", svc.Name, ns, port)
+	resolved.SecretName = secretName
+	if resolved.ForcePathStyle == nil {
+		// the object stores in cluster are addressed by the service name instead of the virtual host of the bucket
+		resolved.ForcePathStyle = pointer.BoolPtr(true)
+	}
+
+	if store.Provision != nil {
+		if err := r.waitProvisioned(ns, store); err != nil {
+			return false, err
+		}
+		if err := ensureBucket(ns, resolved, r.deps.SecretLister); err != nil {
+			return false, fmt.Errorf("create bucket %s in the in-cluster object store %s/%s failed, err: %v", resolved.Bucket, ns, store.ServiceName, err)
+		}
+	}
+
+	changed := provider.Endpoint != resolved.Endpoint || provider.SecretName != resolved.SecretName || provider.ForcePathStyle == nil
+	*provider = *resolved
+	return changed, nil
+}
+
+// resolveCredentials returns the name of the Secret that stores the credentials of the object store
+// in the keys read by the BR jobs. The credentials in the other keys are copied to a new Secret.
+func (r *resolver) resolveCredentials(obj runtime.Object, ns string, store *v1alpha1.InClusterObjectStore) (string, error) {
+	name := credentialsSecretName(store)
+	secret, err := r.deps.SecretLister.Secrets(ns).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", controller.RequeueErrorf("secret %s/%s of the in-cluster object store does not exist", ns, name)
+		}
+		return "", fmt.Errorf("get secret %s/%s of the in-cluster object store failed, err: %v", ns, name, err)
+	}
+
+	for _, keys := range credentialsKeys {
+		accessKey, secretKey := secret.Data[keys[0]], secret.Data[keys[1]]
+		if len(accessKey) == 0 || len(secretKey) == 0 {
+			continue
+		}
+		if keys[0] == constants.S3AccessKey {
+			return name, nil
+		}
+
+		s3SecretName := fmt.Sprintf("%s-s3", name)
+		data := map[string][]byte{
+			constants.S3AccessKey: accessKey,
+			constants.S3SecretKey: secretKey,
+		}
+		if err := r.syncSecret(obj, ns, s3SecretName, store.ServiceName, data); err != nil {
+			return "", err
+		}
+		return s3SecretName, nil
+	}
+	return "", fmt.Errorf("secret %s/%s of the in-cluster object store has no access key and secret key", ns, name)
+}
+
+// syncSecret creates the secret with the data, or updates it if the data is changed
+func (r *resolver) syncSecret(obj runtime.Object, ns, name, storeName string, data map[string][]byte) error {
+	secret, err := r.deps.SecretLister.Secrets(ns).Get(name)
+	if errors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    objectStoreLabels(storeName),
+			},
+			Data: data,
+		}
+		if _, err := r.deps.KubeClientset.CoreV1().Secrets(ns).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create secret %s/%s of the in-cluster object store failed, err: %v", ns, name, err)
+		}
+		r.deps.Recorder.Eventf(obj, corev1.EventTypeNormal, "ObjectStoreCredentialsCreated", "create secret %s/%s for the credentials of the in-cluster object store", ns, name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get secret %s/%s of the in-cluster object store failed, err: %v", ns, name, err)
+	}
+
+	if bytes.Equal(secret.Data[constants.S3AccessKey], data[constants.S3AccessKey]) &&
+		bytes.Equal(secret.Data[constants.S3SecretKey], data[constants.S3SecretKey]) {
+		return nil
+	}
+	secret = secret.DeepCopy()
+	secret.Data = data
+	if _, err := r.deps.KubeClientset.CoreV1().Secrets(ns).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("update secret %s/%s of the in-cluster object store failed, err: %v", ns, name, err)
+	}
+	return nil
+}
+
+// provision creates the credentials, the volume, the deployment and the service of a single-node MinIO
+// if they do not exist. The existing ones are left as they are.
+func (r *resolver) provision(obj runtime.Object, ns string, store *v1alpha1.InClusterObjectStore) error {
+	name := store.ServiceName
+	secretName := credentialsSecretName(store)
+	provision := store.Provision
+	labels := objectStoreLabels(name)
+	created := false
+
+	if _, err := r.deps.SecretLister.Secrets(ns).Get(secretName); errors.IsNotFound(err) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: ns, Labels: labels},
+			Data: map[string][]byte{
+				constants.S3AccessKey: []byte(rand.String(16)),
+				constants.S3SecretKey: []byte(rand.String(32)),
+			},
+		}
+		if _, err := r.deps.KubeClientset.CoreV1().Secrets(ns).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create secret %s/%s of the in-cluster object store failed, err: %v", ns, secretName, err)
+		}
+		created = true
+	} else if err != nil {
+		return err
+	}
+
+	pvcName := fmt.Sprintf("%s-%s", name, dataVolumeName)
+	if _, err := r.deps.PVCLister.PersistentVolumeClaims(ns).Get(pvcName); errors.IsNotFound(err) {
+		storage := provision.Storage
+		if storage == "" {
+			storage = defaultStorage
+		}
+		quantity, err := resource.ParseQuantity(storage)
+		if err != nil {
+			return fmt.Errorf("invalid storage %s of the in-cluster object store %s/%s, err: %v", storage, ns, name, err)
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: ns, Labels: labels},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: provision.StorageClassName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
+				},
+			},
+		}
+		if _, err := r.deps.KubeClientset.CoreV1().PersistentVolumeClaims(ns).Create(context.TODO(), pvc, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create pvc %s/%s of the in-cluster object store failed, err: %v", ns, pvcName, err)
+		}
+		created = true
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := r.deps.DeploymentLister.Deployments(ns).Get(name); errors.IsNotFound(err) {
+		deploy := newDeployment(ns, pvcName, secretName, store)
+		if _, err := r.deps.KubeClientset.AppsV1().Deployments(ns).Create(context.TODO(), deploy, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create deployment %s/%s of the in-cluster object store failed, err: %v", ns, name, err)
+		}
+		created = true
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := r.deps.ServiceLister.Services(ns).Get(name); errors.IsNotFound(err) {
+		port := store.Port
+		if port == 0 {
+			port = defaultPort
+		}
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports: []corev1.ServicePort{{
+					Name:       "api",
+					Port:       port,
+					TargetPort: intstr.FromInt(defaultPort),
+					Protocol:   corev1.ProtocolTCP,
+				}},
+			},
+		}
+		if _, err := r.deps.KubeClientset.CoreV1().Services(ns).Create(context.TODO(), svc, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create service %s/%s of the in-cluster object store failed, err: %v", ns, name, err)
+		}
+		created = true
+	} else if err != nil {
+		return err
+	}
+
+	if created {
+		klog.Infof("provision in-cluster object store %s/%s", ns, name)
+		r.deps.Recorder.Eventf(obj, corev1.EventTypeNormal, "ObjectStoreProvisioned", "provision in-cluster object store %s/%s", ns, name)
+	}
+	return nil
+}
+
+// waitProvisioned waits for the provisioned object store to be ready
+func (r *resolver) waitProvisioned(ns string, store *v1alpha1.InClusterObjectStore) error {
+	deploy, err := r.deps.DeploymentLister.Deployments(ns).Get(store.ServiceName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return controller.RequeueErrorf("deployment %s/%s of the in-cluster object store does not exist", ns, store.ServiceName)
+		}
+		return err
+	}
+	if deploy.Status.ReadyReplicas < 1 {
+		return controller.RequeueErrorf("in-cluster object store %s/%s is not ready", ns, store.ServiceName)
+	}
+	return nil
+}
+
+func newDeployment(ns, pvcName, secretName string, store *v1alpha1.InClusterObjectStore) *appsv1.Deployment {
+	labels := objectStoreLabels(store.ServiceName)
+	image := store.Provision.Image
+	if image == "" {
+		image = defaultImage
+	}
+	credentialsEnv := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: store.ServiceName, Namespace: ns, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			// the volume is ReadWriteOnce, the old pod must be stopped before the new one starts
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:      "minio",
+						Image:     image,
+						Args:      []string{"server", dataMountPath},
+						Resources: store.Provision.Resources,
+						Env: []corev1.EnvVar{
+							credentialsEnv("MINIO_ROOT_USER", constants.S3AccessKey),
+							credentialsEnv("MINIO_ROOT_PASSWORD", constants.S3SecretKey),
+						},
+						Ports: []corev1.ContainerPort{{Name: "api", ContainerPort: defaultPort, Protocol: corev1.ProtocolTCP}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{Path: "/minio/health/ready", Port: intstr.FromInt(defaultPort)},
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{{Name: dataVolumeName, MountPath: dataMountPath}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: dataVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func credentialsSecretName(store *v1alpha1.InClusterObjectStore) string {
+	if store.CredentialsSecretName != "" {
+		return store.CredentialsSecretName
+	}
+	return fmt.Sprintf("%s-credentials", store.ServiceName)
+}
+
+func objectStoreLabels(name string) label.Label {
+	return label.NewOperatorManaged().Component(label.ObjectStoreLabelVal).Instance(name)
+}
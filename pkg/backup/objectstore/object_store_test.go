@@ -0,0 +1,134 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+)
+
+func TestResolvePreDeployed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	r := NewResolver(deps)
+	backup := &v1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup"}}
+	provider := &v1alpha1.S3StorageProvider{
+		Bucket:    "bucket",
+		InCluster: &v1alpha1.InClusterObjectStore{ServiceName: "minio"},
+	}
+
+	// the service does not exist
+	_, err := r.Resolve(backup, "ns", provider)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "minio"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "api", Port: 9000}, {Name: "console", Port: 9001}}},
+	}
+	g.Expect(deps.KubeInformerFactory.Core().V1().Services().Informer().GetIndexer().Add(svc)).Should(Succeed())
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "minio-credentials"},
+		Data:       map[string][]byte{"rootUser": []byte("user"), "rootPassword": []byte("password")},
+	}
+	g.Expect(deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer().Add(secret)).Should(Succeed())
+
+	// the root user of the MinIO chart is copied to a secret with the keys of the BR jobs
+	changed, err := r.Resolve(backup, "ns", provider)
+	g.Expect(err).Should(BeNil())
+	g.Expect(changed).Should(BeTrue())
+	g.Expect(provider.Endpoint).Should(Equal("http://minio.ns.svc:9000"))
+	g.Expect(provider.SecretName).Should(Equal("minio-credentials-s3"))
+	g.Expect(*provider.ForcePathStyle).Should(BeTrue())
+	s3Secret, err := deps.KubeClientset.CoreV1().Secrets("ns").Get(context.TODO(), "minio-credentials-s3", metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(string(s3Secret.Data[constants.S3AccessKey])).Should(Equal("user"))
+	g.Expect(string(s3Secret.Data[constants.S3SecretKey])).Should(Equal("password"))
+	g.Expect(deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer().Add(s3Secret)).Should(Succeed())
+
+	// the resolved provider is not changed again
+	changed, err = r.Resolve(backup, "ns", provider)
+	g.Expect(err).Should(BeNil())
+	g.Expect(changed).Should(BeFalse())
+
+	// the port overrides the first port of the service
+	provider.InCluster.Port = 9001
+	changed, err = r.Resolve(backup, "ns", provider)
+	g.Expect(err).Should(BeNil())
+	g.Expect(changed).Should(BeTrue())
+	g.Expect(provider.Endpoint).Should(Equal("http://minio.ns.svc:9001"))
+}
+
+func TestResolveProvisioned(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	r := NewResolver(deps)
+	var buckets []string
+	ensureBucket = func(ns string, provider *v1alpha1.S3StorageProvider, _ corelisterv1.SecretLister) error {
+		buckets = append(buckets, provider.Endpoint+"/"+provider.Bucket)
+		return nil
+	}
+	restore := &v1alpha1.Restore{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "restore"}}
+	provider := &v1alpha1.S3StorageProvider{
+		Bucket: "bucket",
+		InCluster: &v1alpha1.InClusterObjectStore{
+			ServiceName: "store",
+			Provision:   &v1alpha1.ObjectStoreProvision{},
+		},
+	}
+
+	// the object store is provisioned
+	_, err := r.Resolve(restore, "ns", provider)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+	secret, err := deps.KubeClientset.CoreV1().Secrets("ns").Get(context.TODO(), "store-credentials", metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(secret.Data[constants.S3AccessKey]).ShouldNot(BeEmpty())
+	g.Expect(secret.Data[constants.S3SecretKey]).ShouldNot(BeEmpty())
+	pvc, err := deps.KubeClientset.CoreV1().PersistentVolumeClaims("ns").Get(context.TODO(), "store-data", metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(pvc.Spec.Resources.Requests.Storage().String()).Should(Equal("10Gi"))
+	deploy, err := deps.KubeClientset.AppsV1().Deployments("ns").Get(context.TODO(), "store", metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(deploy.Spec.Template.Spec.Containers[0].Image).Should(Equal("minio/minio"))
+	svc, err := deps.KubeClientset.CoreV1().Services("ns").Get(context.TODO(), "store", metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(svc.Spec.Selector).Should(Equal(deploy.Spec.Template.Labels))
+
+	g.Expect(deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer().Add(secret)).Should(Succeed())
+	g.Expect(deps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)).Should(Succeed())
+	g.Expect(deps.KubeInformerFactory.Apps().V1().Deployments().Informer().GetIndexer().Add(deploy)).Should(Succeed())
+	g.Expect(deps.KubeInformerFactory.Core().V1().Services().Informer().GetIndexer().Add(svc)).Should(Succeed())
+
+	// wait for the object store to be ready
+	_, err = r.Resolve(restore, "ns", provider)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+	g.Expect(buckets).Should(BeEmpty())
+
+	deploy = deploy.DeepCopy()
+	deploy.Status.ReadyReplicas = 1
+	g.Expect(deps.KubeInformerFactory.Apps().V1().Deployments().Informer().GetIndexer().Update(deploy)).Should(Succeed())
+	changed, err := r.Resolve(restore, "ns", provider)
+	g.Expect(err).Should(BeNil())
+	g.Expect(changed).Should(BeTrue())
+	g.Expect(provider.Endpoint).Should(Equal("http://store.ns.svc:9000"))
+	g.Expect(provider.SecretName).Should(Equal("store-credentials"))
+	g.Expect(buckets).Should(Equal([]string{"http://store.ns.svc:9000/bucket"}))
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 	"github.com/pingcap/tidb-operator/pkg/backup"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/backup/objectstore"
 	"github.com/pingcap/tidb-operator/pkg/backup/snapshotter"
 	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
 	"github.com/pingcap/tidb-operator/pkg/controller"
@@ -52,15 +53,17 @@ const (
 )
 
 type restoreManager struct {
-	deps          *controller.Dependencies
-	statusUpdater controller.RestoreConditionUpdaterInterface
+	deps                *controller.Dependencies
+	objectStoreResolver objectstore.Resolver
+	statusUpdater       controller.RestoreConditionUpdaterInterface
 }
 
 // NewRestoreManager return restoreManager
 func NewRestoreManager(deps *controller.Dependencies) backup.RestoreManager {
 	return &restoreManager{
-		deps:          deps,
-		statusUpdater: controller.NewRealRestoreConditionUpdater(deps.Clientset, deps.RestoreLister, deps.Recorder),
+		deps:                deps,
+		objectStoreResolver: objectstore.NewResolver(deps),
+		statusUpdater:       controller.NewRealRestoreConditionUpdater(deps.Clientset, deps.RestoreLister, deps.Recorder),
 	}
 }
 
@@ -293,6 +296,22 @@ func (rm *restoreManager) syncRestoreJob(restore *v1alpha1.Restore) error {
 		return fmt.Errorf("restore %s/%s get job %s failed, err: %v", ns, name, restoreJobName, err)
 	}
 
+	if err := rm.resolveInClusterObjectStore(restore); err != nil {
+		klog.Errorf("restore %s/%s resolve in-cluster object store error %v.", ns, name, err)
+		return err
+	}
+
+	if restore.Spec.BR != nil && (restore.Spec.Mode == "" || restore.Spec.Mode == v1alpha1.RestoreModeSnapshot) {
+		if err := rm.checkVersionCompatibility(restore, tc); err != nil {
+			return err
+		}
+	}
+
+	// wait for the running backup and restore jobs to be fewer than the limits
+	if err := rm.waitRestoreJobAdmitted(restore); err != nil {
+		return err
+	}
+
 	if restore.Spec.Mode == v1alpha1.RestoreModePiTR {
 		// Note: perhaps better to reschedule here and wait the cluster config applied.
 		// But for now BR will also modify this configuration. This configuration map was
@@ -371,6 +390,50 @@ func (rm *restoreManager) syncRestoreJob(restore *v1alpha1.Restore) error {
 	return nil
 }
 
+// waitRestoreJobAdmitted keeps the restore pending until the job is admitted by the limiter
+// of the concurrent backup and restore jobs
+// resolveInClusterObjectStore fills the endpoint and the secret of the in-cluster object store into the restore,
+// so that the restore jobs access the object store as an ordinary S3 storage
+func (rm *restoreManager) resolveInClusterObjectStore(restore *v1alpha1.Restore) error {
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+	changed, err := rm.objectStoreResolver.Resolve(restore, ns, restore.Spec.S3)
+	if err != nil || !changed {
+		return err
+	}
+
+	if _, err := rm.deps.RestoreControl.UpdateRestore(restore); err != nil {
+		return fmt.Errorf("restore %s/%s update endpoint of the in-cluster object store failed, err: %v", ns, name, err)
+	}
+	return controller.RequeueErrorf("restore %s/%s resolved endpoint %s of the in-cluster object store", ns, name, restore.Spec.S3.Endpoint)
+}
+
+func (rm *restoreManager) waitRestoreJobAdmitted(restore *v1alpha1.Restore) error {
+	if restore.Spec.Mode == v1alpha1.RestoreModeVolumeSnapshot {
+		return nil
+	}
+
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+	admitted, reason, err := rm.deps.BRJobLimiter.Admit(ns, restore.GetRestoreJobName())
+	if err != nil {
+		return fmt.Errorf("restore %s/%s check job concurrency failed, err: %v", ns, name, err)
+	}
+	if admitted {
+		return nil
+	}
+
+	if err := rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+		Type:    v1alpha1.RestorePending,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ConcurrencyLimitExceeded",
+		Message: reason,
+	}, nil); err != nil {
+		return err
+	}
+	return controller.RequeueErrorf("restore %s/%s is pending: %s", ns, name, reason)
+}
+
 // syncPruneJob handles the lifecycle of prune jobs for failed restores
 func (rm *restoreManager) syncPruneJob(restore *v1alpha1.Restore) error {
 	ns := restore.GetNamespace()
@@ -854,6 +917,7 @@ func (rm *restoreManager) makeImportJob(restore *v1alpha1.Restore) (*batchv1.Job
 				},
 			}, volumes...),
 			PriorityClassName: restore.Spec.PriorityClassName,
+			PreemptionPolicy:  restore.Spec.PreemptionPolicy,
 		},
 	}
 
@@ -1092,6 +1156,7 @@ func (rm *restoreManager) makeRestoreJobWithMode(restore *v1alpha1.Restore, isPr
 			Affinity:          restore.Spec.Affinity,
 			Volumes:           volumes,
 			PriorityClassName: restore.Spec.PriorityClassName,
+			PreemptionPolicy:  restore.Spec.PreemptionPolicy,
 		},
 	}
 
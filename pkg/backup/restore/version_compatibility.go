@@ -0,0 +1,99 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// the first major version of BR
+const minBRMajorVersion = 4
+
+// versionCompatibleBROptions returns the BR options required to restore a backup taken from a cluster
+// of the source version to a cluster of the target version, or an error if the restore is not supported:
+//   - the downgrades across the minor or major versions are not supported, as the data format may be changed.
+//   - the downgrades across the patch versions skip the version check of BR.
+//   - the upgrades across the major versions skip the version check of BR and the restore of the system tables,
+//     as the schemas of the system tables may be changed.
+func versionCompatibleBROptions(source, target *semver.Version) ([]string, error) {
+	switch {
+	case source.Major() < minBRMajorVersion:
+		return nil, fmt.Errorf("the backup of version %s is not taken by BR", source)
+	case target.Major() < source.Major() || target.Major() == source.Major() && target.Minor() < source.Minor():
+		return nil, fmt.Errorf("restoring the backup of version %s to the cluster of the lower version %s is not supported", source, target)
+	case target.Major() == source.Major() && target.Minor() == source.Minor() && target.Patch() < source.Patch():
+		return []string{"--check-requirements=false"}, nil
+	case target.Major() > source.Major():
+		return []string{"--check-requirements=false", "--with-sys-table=false"}, nil
+	}
+	return nil, nil
+}
+
+// checkVersionCompatibility checks whether the backup can be restored to the cluster by the version
+// recorded in the backup metadata, and records the BR options required by the cross-version restore
+// in the status. The check is skipped if the version check of BR is disabled explicitly or any of the
+// versions is unknown.
+func (rm *restoreManager) checkVersionCompatibility(r *v1alpha1.Restore, tc *v1alpha1.TidbCluster) error {
+	if r.Status.VersionCompatibility != nil {
+		return nil
+	}
+	if r.Spec.BR.CheckRequirements != nil && !*r.Spec.BR.CheckRequirements {
+		return nil
+	}
+
+	_, targetVersion := backuputil.ParseImage(tc.TiKVImage())
+	target, err := semver.NewVersion(targetVersion)
+	if err != nil {
+		klog.Infof("restore %s/%s: skip the version compatibility check, unknown target version %q", r.Namespace, r.Name, targetVersion)
+		return nil
+	}
+	sourceVersion, err := backuputil.GetBRBackupClusterVersion(r, rm.deps.SecretLister)
+	if err != nil {
+		klog.Warningf("restore %s/%s: skip the version compatibility check, get the version of the backup failed: %v", r.Namespace, r.Name, err)
+		return nil
+	}
+	source, err := semver.NewVersion(sourceVersion)
+	if err != nil {
+		klog.Infof("restore %s/%s: skip the version compatibility check, unknown source version %q", r.Namespace, r.Name, sourceVersion)
+		return nil
+	}
+
+	options, err := versionCompatibleBROptions(source, target)
+	if err != nil {
+		rm.statusUpdater.Update(r, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreInvalid,
+			Status:  corev1.ConditionTrue,
+			Reason:  "IncompatibleVersion",
+			Message: err.Error(),
+		}, nil)
+		return controller.IgnoreErrorf("restore %s/%s is incompatible with tidbcluster %s/%s: %v", r.Namespace, r.Name, tc.Namespace, tc.Name, err)
+	}
+	if len(options) > 0 {
+		klog.Infof("restore %s/%s: restore the backup of version %s to the cluster of version %s with BR options %v", r.Namespace, r.Name, source, target, options)
+	}
+	return rm.statusUpdater.Update(r, nil, &controller.RestoreUpdateStatus{
+		VersionCompatibility: &v1alpha1.RestoreVersionCompatibility{
+			SourceVersion: sourceVersion,
+			TargetVersion: targetVersion,
+			BROptions:     options,
+		},
+	})
+}
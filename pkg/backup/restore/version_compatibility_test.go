@@ -0,0 +1,49 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	. "github.com/onsi/gomega"
+)
+
+func TestVersionCompatibleBROptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		source  string
+		target  string
+		options []string
+		invalid bool
+	}{
+		{source: "7.5.0", target: "v7.5.0"},
+		{source: "7.1.0", target: "v7.5.1"},
+		{source: "7.5.2", target: "v7.5.1", options: []string{"--check-requirements=false"}},
+		{source: "6.5.0", target: "v7.5.0", options: []string{"--check-requirements=false", "--with-sys-table=false"}},
+		{source: "7.5.0", target: "v7.1.0", invalid: true},
+		{source: "8.1.0", target: "v7.5.0", invalid: true},
+		{source: "3.0.0", target: "v7.5.0", invalid: true},
+	}
+	for _, c := range cases {
+		options, err := versionCompatibleBROptions(semver.MustParse(c.source), semver.MustParse(c.target))
+		if c.invalid {
+			g.Expect(err).Should(HaveOccurred(), "%s -> %s", c.source, c.target)
+			continue
+		}
+		g.Expect(err).Should(Succeed(), "%s -> %s", c.source, c.target)
+		g.Expect(options).Should(Equal(c.options), "%s -> %s", c.source, c.target)
+	}
+}
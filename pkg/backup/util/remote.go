@@ -33,6 +33,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gocloud.dev/blob"
 	"gocloud.dev/blob/azureblob"
 	"gocloud.dev/blob/driver"
@@ -48,6 +50,7 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 )
 
 const (
@@ -161,6 +164,36 @@ func (b *StorageBackend) ListPage(opts *blob.ListOptions) *PageIterator {
 	}
 }
 
+// ObjectsSize returns the total size of the objects under the prefix of the storage backend
+func (b *StorageBackend) ObjectsSize(ctx context.Context) (size int64, err error) {
+	ctx, span := b.startSpan(ctx, "Storage.ObjectsSize")
+	defer func() { tracing.End(span, err) }()
+
+	iter := b.ListPage(nil)
+	for {
+		objs, err := iter.Next(ctx, 1000)
+		if err == io.EOF {
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		for _, obj := range objs {
+			if !obj.IsDir {
+				size += obj.Size
+			}
+		}
+	}
+}
+
+// startSpan starts a span for a call to the storage backend
+func (b *StorageBackend) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		attribute.String("storage.type", string(b.StorageType())),
+		attribute.String("storage.bucket", b.GetBucket()),
+		attribute.String("storage.prefix", b.GetPrefix()))
+}
+
 func (b *StorageBackend) AsS3() (*s3.S3, bool) {
 	var s3cli *s3.S3
 	if ok := b.Bucket.As(&s3cli); !ok {
@@ -224,6 +257,14 @@ type BatchDeleteObjectsResult struct {
 // Depending on storage type, it use function 'BatchDeleteObjectsOfS3' or 'BatchDeleteObjectsConcurrently'
 func (b *StorageBackend) BatchDeleteObjects(ctx context.Context, objs []*blob.ListObject, opt v1alpha1.BatchDeleteOption) *BatchDeleteObjectsResult {
 	var result *BatchDeleteObjectsResult
+	ctx, span := b.startSpan(ctx, "Storage.BatchDeleteObjects")
+	defer func() {
+		var err error
+		if len(result.Errors) > 0 {
+			err = fmt.Errorf("failed to delete %d of %d objects", len(result.Errors), len(objs))
+		}
+		tracing.End(span, err)
+	}()
 
 	s3cli, ok := b.AsS3()
 	if !opt.DisableBatchConcurrency && ok {
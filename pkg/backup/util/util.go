@@ -25,10 +25,13 @@ import (
 	"unsafe"
 
 	"github.com/Masterminds/semver"
+	"github.com/gogo/protobuf/proto"
+	kvbackup "github.com/pingcap/kvproto/pkg/brpb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/wait"
 	corelisterv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/util/retry"
@@ -501,6 +504,10 @@ func ValidateBackup(backup *v1alpha1.Backup, tikvImage string, tc *v1alpha1.Tidb
 			return fmt.Errorf("table should be configured for BR with backup type table in spec of %s/%s", ns, name)
 		}
 
+		if err := ValidateBRToolImage(backup.Spec.ToolImage, tikvImage); err != nil {
+			return fmt.Errorf("%v in spec of %s/%s", err, ns, name)
+		}
+
 		// validate storage providers
 		if backup.Spec.S3 != nil {
 			if err := validateS3(ns, name, backup.Spec.S3); err != nil {
@@ -560,10 +567,96 @@ func ValidateBackup(backup *v1alpha1.Backup, tikvImage string, tc *v1alpha1.Tidb
 				return fmt.Errorf("fail to parse retryTimeout %s of backup %s/%s, %v", backup.Spec.BackoffRetryPolicy.RetryTimeout, backup.Namespace, backup.Name, err)
 			}
 		}
+
+		if err := validateBackupThrottle(ns, name, backup.Spec.Throttle); err != nil {
+			return err
+		}
+		if err := validateBackupReplicaReadPolicy(ns, name, backup.Spec.BackupReplicaReadPolicy); err != nil {
+			return err
+		}
+	}
+	if err := validatePreemptionPolicy(ns, name, backup.Spec.PreemptionPolicy, backup.Spec.PriorityClassName); err != nil {
+		return err
+	}
+	return validateBRNotifications(ns, name, backup.Spec.Notifications)
+}
+
+// validateBackupThrottle validates the closed-loop throttling of the backup
+func validateBackupThrottle(ns, name string, throttle *v1alpha1.BackupThrottle) error {
+	if throttle == nil {
+		return nil
+	}
+	if throttle.PrometheusURL == "" {
+		return fmt.Errorf("prometheusURL should be configured for throttle in spec of %s/%s", ns, name)
+	}
+	if throttle.MaxCPUUsage != nil && (*throttle.MaxCPUUsage <= 0 || *throttle.MaxCPUUsage > 100) {
+		return fmt.Errorf("maxCPUUsage %d of throttle should be in (0, 100] in spec of %s/%s", *throttle.MaxCPUUsage, ns, name)
+	}
+	if throttle.MinThreads != nil && throttle.MaxThreads != nil && *throttle.MinThreads > *throttle.MaxThreads {
+		return fmt.Errorf("minThreads %d of throttle is larger than maxThreads %d in spec of %s/%s", *throttle.MinThreads, *throttle.MaxThreads, ns, name)
 	}
 	return nil
 }
 
+// validateBackupReplicaReadPolicy validates the replicas the backup reads from
+func validateBackupReplicaReadPolicy(ns, name string, policy *v1alpha1.BackupReplicaReadPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	switch policy.Mode {
+	case "", v1alpha1.BackupReplicaReadLeader:
+		return nil
+	case v1alpha1.BackupReplicaReadFollower, v1alpha1.BackupReplicaReadLearner:
+	default:
+		return fmt.Errorf("mode %s of backupReplicaReadPolicy is not supported in spec of %s/%s", policy.Mode, ns, name)
+	}
+	if _, _, err := ParseReplicaReadLabel(policy.StoreLabel); err != nil {
+		return fmt.Errorf("%v in backupReplicaReadPolicy in spec of %s/%s", err, ns, name)
+	}
+	if policy.LearnerRule != nil && policy.LearnerRule.ID == "" {
+		return fmt.Errorf("id of learnerRule should be configured in backupReplicaReadPolicy in spec of %s/%s", ns, name)
+	}
+	return nil
+}
+
+// validateBRNotifications validates the webhook notifications of the backup or restore
+func validateBRNotifications(ns, name string, notifications *v1alpha1.BRNotifications) error {
+	if notifications == nil {
+		return nil
+	}
+	if (notifications.Webhook.URL == "") == (notifications.Webhook.SecretName == "") {
+		return fmt.Errorf("exactly one of url and secretName of the webhook should be configured for notifications in spec of %s/%s", ns, name)
+	}
+	if notifications.Webhook.URL != "" {
+		if u, err := url.Parse(notifications.Webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid url %s of the webhook for notifications in spec of %s/%s", notifications.Webhook.URL, ns, name)
+		}
+	}
+	for _, event := range notifications.Events {
+		switch event {
+		case v1alpha1.BRNotificationEventStarted, v1alpha1.BRNotificationEventProgress,
+			v1alpha1.BRNotificationEventCompleted, v1alpha1.BRNotificationEventFailed:
+		default:
+			return fmt.Errorf("event %s of notifications is not supported in spec of %s/%s", event, ns, name)
+		}
+	}
+	for _, m := range notifications.ProgressMilestones {
+		if m <= 0 || m > 100 {
+			return fmt.Errorf("progress milestone %d of notifications should be in (0, 100] in spec of %s/%s", m, ns, name)
+		}
+	}
+	return nil
+}
+
+// ParseReplicaReadLabel parses the store label `key:value` of the replica read of BR
+func ParseReplicaReadLabel(storeLabel string) (string, string, error) {
+	kv := strings.SplitN(storeLabel, ":", 2)
+	if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+		return "", "", fmt.Errorf("storeLabel %q should be in the format of key:value", storeLabel)
+	}
+	return kv[0], kv[1], nil
+}
+
 // ValidateRestore checks whether a restore spec is valid.
 func ValidateRestore(restore *v1alpha1.Restore, tikvImage string, acrossK8s bool) error {
 	ns := restore.Namespace
@@ -586,6 +679,12 @@ func ValidateRestore(restore *v1alpha1.Restore, tikvImage string, acrossK8s bool
 			return fmt.Errorf("cluster should be configured for BR in spec of %s/%s", ns, name)
 		}
 
+		if restore.Spec.IntegrityCheck != nil {
+			if reason := validateAccessConfig(restore.Spec.To); reason != "" {
+				return fmt.Errorf("integrity check requires the access config of the restored cluster, "+reason, ns, name)
+			}
+		}
+
 		if restore.Spec.Type != "" &&
 			restore.Spec.Type != v1alpha1.BackupTypeFull &&
 			restore.Spec.Type != v1alpha1.BackupTypeDB &&
@@ -635,6 +734,18 @@ func ValidateRestore(restore *v1alpha1.Restore, tikvImage string, acrossK8s bool
 			}
 		}
 	}
+	if err := validatePreemptionPolicy(ns, name, restore.Spec.PreemptionPolicy, restore.Spec.PriorityClassName); err != nil {
+		return err
+	}
+	return validateBRNotifications(ns, name, restore.Spec.Notifications)
+}
+
+// validatePreemptionPolicy validates the preemption policy of the job pods is set along with their priority class,
+// as the admission rejects the pods setting a policy different from the one of the priority class
+func validatePreemptionPolicy(ns, name string, policy *corev1.PreemptionPolicy, priorityClassName string) error {
+	if policy != nil && priorityClassName == "" {
+		return fmt.Errorf("priorityClassName should be configured along with preemptionPolicy in spec of %s/%s", ns, name)
+	}
 	return nil
 }
 
@@ -656,6 +767,17 @@ func validateS3(ns, name string, s3 *v1alpha1.S3StorageProvider) error {
 			return fmt.Errorf("host not found in endpoint %s %s", s3.Endpoint, configuredForBR)
 		}
 	}
+
+	if s3.InCluster != nil {
+		if s3.InCluster.ServiceName == "" {
+			return fmt.Errorf("service name of the in-cluster object store should be %s", configuredForBR)
+		}
+		if provision := s3.InCluster.Provision; provision != nil && provision.Storage != "" {
+			if _, err := resource.ParseQuantity(provision.Storage); err != nil {
+				return fmt.Errorf("invalid storage %s of the in-cluster object store is %s", provision.Storage, configuredForBR)
+			}
+		}
+	}
 	return nil
 }
 
@@ -697,6 +819,30 @@ func ParseImage(image string) (string, string) {
 	return name, tag
 }
 
+// ValidateBRToolImage checks whether the version of the BR tool image is compatible with the version of
+// the TiKV image, BR only supports the clusters of the same major version. The images without a tag, which
+// use the version of TiKV, the images referenced by digest and the tags which are not semantic versions,
+// e.g. nightly, are not checked.
+func ValidateBRToolImage(toolImage, tikvImage string) error {
+	if toolImage == "" || !strings.ContainsRune(toolImage, ':') || strings.ContainsRune(toolImage, '@') {
+		return nil
+	}
+	_, toolVersion := ParseImage(toolImage)
+	_, tikvVersion := ParseImage(tikvImage)
+	tv, err := semver.NewVersion(toolVersion)
+	if err != nil {
+		return nil
+	}
+	cv, err := semver.NewVersion(tikvVersion)
+	if err != nil {
+		return nil
+	}
+	if tv.Major() != cv.Major() {
+		return fmt.Errorf("tool image %s is not compatible with the cluster of version %s, the major versions should be the same", toolImage, tikvVersion)
+	}
+	return nil
+}
+
 // canSkipSetGCLifeTime returns if setting tikv_gc_life_time can be skipped based on the TiKV version
 func canSkipSetGCLifeTime(image string) bool {
 	_, version := ParseImage(image)
@@ -779,6 +925,31 @@ func GetOptions(provider v1alpha1.StorageProvider) []string {
 	}
 }
 
+// GetBRBackupClusterVersion gets the version of the cluster the backup was taken from, which is recorded
+// in the BR backup metadata in cloud storage
+func GetBRBackupClusterVersion(r *v1alpha1.Restore, secretLister corelisterv1.SecretLister) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cred := GetStorageCredential(r.Namespace, r.Spec.StorageProvider, secretLister)
+	s, err := NewStorageBackend(r.Spec.StorageProvider, cred)
+	if err != nil {
+		return "", err
+	}
+	defer s.Close()
+
+	data, err := s.ReadAll(ctx, constants.MetaFile)
+	if err != nil {
+		return "", fmt.Errorf("read backup meta from bucket %s and prefix %s, err: %v", s.GetBucket(), s.GetPrefix(), err)
+	}
+	backupMeta := &kvbackup.BackupMeta{}
+	if err := proto.Unmarshal(data, backupMeta); err != nil {
+		return "", fmt.Errorf("unmarshal backup meta from bucket %s and prefix %s, err: %v", s.GetBucket(), s.GetPrefix(), err)
+	}
+	// the version is quoted by some versions of BR
+	return strings.Trim(backupMeta.ClusterVersion, `"`), nil
+}
+
 // getVolSnapBackupMetaData get backup metadata from cloud storage
 func GetVolSnapBackupMetaData(r *v1alpha1.Restore, secretLister corelisterv1.SecretLister) (*EBSBasedBRMeta, error) {
 	// since the restore meta is small (~5M), assume 1 minutes is enough
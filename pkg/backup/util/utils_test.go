@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 )
 
 func TestCheckAllKeysExistInSecret(t *testing.T) {
@@ -550,6 +551,88 @@ func TestValidateBackup(t *testing.T) {
 
 	backup.Spec.S3.Endpoint = "s3://localhost:80"
 	match("")
+
+	backup.Spec.S3.InCluster = &v1alpha1.InClusterObjectStore{}
+	match("service name of the in-cluster object store should be configured")
+
+	backup.Spec.S3.InCluster.ServiceName = "minio"
+	backup.Spec.S3.InCluster.Provision = &v1alpha1.ObjectStoreProvision{Storage: "ten"}
+	match("invalid storage ten of the in-cluster object store")
+
+	backup.Spec.S3.InCluster.Provision.Storage = "10Gi"
+	match("")
+
+	backup.Spec.ToolImage = "pingcap/br:v5.4.0"
+	match("tool image pingcap/br:v5.4.0 is not compatible with the cluster of version v4.0.8")
+
+	backup.Spec.ToolImage = "pingcap/br:v4.0.16"
+	match("")
+
+	backup.Spec.Throttle = &v1alpha1.BackupThrottle{}
+	match("prometheusURL should be configured for throttle")
+
+	backup.Spec.Throttle.PrometheusURL = "http://prometheus:9090"
+	backup.Spec.Throttle.MaxCPUUsage = pointer.Int32Ptr(120)
+	match("maxCPUUsage 120 of throttle should be in")
+
+	backup.Spec.Throttle.MaxCPUUsage = pointer.Int32Ptr(80)
+	backup.Spec.Throttle.MinThreads = pointer.Int32Ptr(4)
+	backup.Spec.Throttle.MaxThreads = pointer.Int32Ptr(2)
+	match("minThreads 4 of throttle is larger than maxThreads 2")
+
+	backup.Spec.Throttle.MaxThreads = pointer.Int32Ptr(8)
+	match("")
+
+	backup.Spec.BackupReplicaReadPolicy = &v1alpha1.BackupReplicaReadPolicy{Mode: "Witness"}
+	match("mode Witness of backupReplicaReadPolicy is not supported")
+
+	backup.Spec.BackupReplicaReadPolicy.Mode = v1alpha1.BackupReplicaReadLearner
+	backup.Spec.BackupReplicaReadPolicy.StoreLabel = "role="
+	match("storeLabel \"role=\" should be in the format of key:value")
+
+	backup.Spec.BackupReplicaReadPolicy.StoreLabel = "role:backup"
+	backup.Spec.BackupReplicaReadPolicy.LearnerRule = &v1alpha1.PlacementRuleRef{}
+	match("id of learnerRule should be configured")
+
+	backup.Spec.BackupReplicaReadPolicy.LearnerRule.ID = "backup-learner"
+	match("")
+
+	backup.Spec.Notifications = &v1alpha1.BRNotifications{}
+	match("exactly one of url and secretName of the webhook should be configured")
+
+	backup.Spec.Notifications.Webhook.URL = "hooks.example.com/backup"
+	match("invalid url hooks.example.com/backup of the webhook")
+
+	backup.Spec.Notifications.Webhook.URL = "https://hooks.example.com/backup"
+	backup.Spec.Notifications.Events = []v1alpha1.BRNotificationEvent{v1alpha1.BRNotificationEventFailed, "Paused"}
+	match("event Paused of notifications is not supported")
+
+	backup.Spec.Notifications.Events = []v1alpha1.BRNotificationEvent{v1alpha1.BRNotificationEventFailed}
+	backup.Spec.Notifications.ProgressMilestones = []int32{50, 120}
+	match("progress milestone 120 of notifications should be in")
+
+	backup.Spec.Notifications.ProgressMilestones = []int32{50, 100}
+	match("")
+
+	never := corev1.PreemptNever
+	backup.Spec.PreemptionPolicy = &never
+	match("priorityClassName should be configured along with preemptionPolicy")
+
+	backup.Spec.PriorityClassName = "backup"
+	match("")
+}
+
+func TestValidateBRToolImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(ValidateBRToolImage("", "pingcap/tikv:v7.5.0")).Should(Succeed())
+	g.Expect(ValidateBRToolImage("pingcap/br", "pingcap/tikv:v7.5.0")).Should(Succeed())
+	g.Expect(ValidateBRToolImage("pingcap/br:nightly", "pingcap/tikv:v7.5.0")).Should(Succeed())
+	g.Expect(ValidateBRToolImage("pingcap/br:v7.5.1", "pingcap/tikv:latest")).Should(Succeed())
+	g.Expect(ValidateBRToolImage("pingcap/br:v7.5.1", "pingcap/tikv:v7.5.0")).Should(Succeed())
+	g.Expect(ValidateBRToolImage("registry:5000/pingcap/br:v7.1.5", "pingcap/tikv:v7.5.0")).Should(Succeed())
+	g.Expect(ValidateBRToolImage("pingcap/br:v8.1.0", "pingcap/tikv:v7.5.0")).ShouldNot(Succeed())
+	g.Expect(ValidateBRToolImage("pingcap/br:v6.5.0", "pingcap/tikv:v7.5.0")).ShouldNot(Succeed())
 }
 
 func TestValidateRestore(t *testing.T) {
@@ -610,6 +693,13 @@ func TestValidateRestore(t *testing.T) {
 
 	restore.Spec.S3.Endpoint = "s3://localhost:80"
 	match("")
+
+	never := corev1.PreemptNever
+	restore.Spec.PreemptionPolicy = &never
+	match("priorityClassName should be configured along with preemptionPolicy")
+
+	restore.Spec.PriorityClassName = "restore"
+	match("")
 }
 
 func TestGetImageTag(t *testing.T) {
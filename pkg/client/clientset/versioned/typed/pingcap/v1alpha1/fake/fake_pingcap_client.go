@@ -45,14 +45,26 @@ func (c *FakePingcapV1alpha1) DataResources(namespace string) v1alpha1.DataResou
 	return &FakeDataResources{c, namespace}
 }
 
+func (c *FakePingcapV1alpha1) PumpMigrations(namespace string) v1alpha1.PumpMigrationInterface {
+	return &FakePumpMigrations{c, namespace}
+}
+
 func (c *FakePingcapV1alpha1) Restores(namespace string) v1alpha1.RestoreInterface {
 	return &FakeRestores{c, namespace}
 }
 
+func (c *FakePingcapV1alpha1) TidbAccounts(namespace string) v1alpha1.TidbAccountInterface {
+	return &FakeTidbAccounts{c, namespace}
+}
+
 func (c *FakePingcapV1alpha1) TidbClusters(namespace string) v1alpha1.TidbClusterInterface {
 	return &FakeTidbClusters{c, namespace}
 }
 
+func (c *FakePingcapV1alpha1) TidbClusterReplications(namespace string) v1alpha1.TidbClusterReplicationInterface {
+	return &FakeTidbClusterReplications{c, namespace}
+}
+
 func (c *FakePingcapV1alpha1) TidbDashboards(namespace string) v1alpha1.TidbDashboardInterface {
 	return &FakeTidbDashboards{c, namespace}
 }
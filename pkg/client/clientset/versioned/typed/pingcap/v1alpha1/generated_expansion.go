@@ -25,10 +25,16 @@ type DMClusterExpansion interface{}
 
 type DataResourceExpansion interface{}
 
+type PumpMigrationExpansion interface{}
+
 type RestoreExpansion interface{}
 
+type TidbAccountExpansion interface{}
+
 type TidbClusterExpansion interface{}
 
+type TidbClusterReplicationExpansion interface{}
+
 type TidbDashboardExpansion interface{}
 
 type TidbInitializerExpansion interface{}
@@ -30,8 +30,11 @@ type PingcapV1alpha1Interface interface {
 	CompactBackupsGetter
 	DMClustersGetter
 	DataResourcesGetter
+	PumpMigrationsGetter
 	RestoresGetter
+	TidbAccountsGetter
 	TidbClustersGetter
+	TidbClusterReplicationsGetter
 	TidbDashboardsGetter
 	TidbInitializersGetter
 	TidbMonitorsGetter
@@ -63,14 +66,26 @@ func (c *PingcapV1alpha1Client) DataResources(namespace string) DataResourceInte
 	return newDataResources(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) PumpMigrations(namespace string) PumpMigrationInterface {
+	return newPumpMigrations(c, namespace)
+}
+
 func (c *PingcapV1alpha1Client) Restores(namespace string) RestoreInterface {
 	return newRestores(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) TidbAccounts(namespace string) TidbAccountInterface {
+	return newTidbAccounts(c, namespace)
+}
+
 func (c *PingcapV1alpha1Client) TidbClusters(namespace string) TidbClusterInterface {
 	return newTidbClusters(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) TidbClusterReplications(namespace string) TidbClusterReplicationInterface {
+	return newTidbClusterReplications(c, namespace)
+}
+
 func (c *PingcapV1alpha1Client) TidbDashboards(namespace string) TidbDashboardInterface {
 	return newTidbDashboards(c, namespace)
 }
@@ -31,10 +31,16 @@ type Interface interface {
 	DMClusters() DMClusterInformer
 	// DataResources returns a DataResourceInformer.
 	DataResources() DataResourceInformer
+	// PumpMigrations returns a PumpMigrationInformer.
+	PumpMigrations() PumpMigrationInformer
 	// Restores returns a RestoreInformer.
 	Restores() RestoreInformer
+	// TidbAccounts returns a TidbAccountInformer.
+	TidbAccounts() TidbAccountInformer
 	// TidbClusters returns a TidbClusterInformer.
 	TidbClusters() TidbClusterInformer
+	// TidbClusterReplications returns a TidbClusterReplicationInformer.
+	TidbClusterReplications() TidbClusterReplicationInformer
 	// TidbDashboards returns a TidbDashboardInformer.
 	TidbDashboards() TidbDashboardInformer
 	// TidbInitializers returns a TidbInitializerInformer.
@@ -81,16 +87,31 @@ func (v *version) DataResources() DataResourceInformer {
 	return &dataResourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// PumpMigrations returns a PumpMigrationInformer.
+func (v *version) PumpMigrations() PumpMigrationInformer {
+	return &pumpMigrationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Restores returns a RestoreInformer.
 func (v *version) Restores() RestoreInformer {
 	return &restoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TidbAccounts returns a TidbAccountInformer.
+func (v *version) TidbAccounts() TidbAccountInformer {
+	return &tidbAccountInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TidbClusters returns a TidbClusterInformer.
 func (v *version) TidbClusters() TidbClusterInformer {
 	return &tidbClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TidbClusterReplications returns a TidbClusterReplicationInformer.
+func (v *version) TidbClusterReplications() TidbClusterReplicationInformer {
+	return &tidbClusterReplicationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TidbDashboards returns a TidbDashboardInformer.
 func (v *version) TidbDashboards() TidbDashboardInformer {
 	return &tidbDashboardInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -55,6 +55,14 @@ type DataResourceListerExpansion interface{}
 // DataResourceNamespaceLister.
 type DataResourceNamespaceListerExpansion interface{}
 
+// PumpMigrationListerExpansion allows custom methods to be added to
+// PumpMigrationLister.
+type PumpMigrationListerExpansion interface{}
+
+// PumpMigrationNamespaceListerExpansion allows custom methods to be added to
+// PumpMigrationNamespaceLister.
+type PumpMigrationNamespaceListerExpansion interface{}
+
 // RestoreListerExpansion allows custom methods to be added to
 // RestoreLister.
 type RestoreListerExpansion interface{}
@@ -63,6 +71,14 @@ type RestoreListerExpansion interface{}
 // RestoreNamespaceLister.
 type RestoreNamespaceListerExpansion interface{}
 
+// TidbAccountListerExpansion allows custom methods to be added to
+// TidbAccountLister.
+type TidbAccountListerExpansion interface{}
+
+// TidbAccountNamespaceListerExpansion allows custom methods to be added to
+// TidbAccountNamespaceLister.
+type TidbAccountNamespaceListerExpansion interface{}
+
 // TidbClusterListerExpansion allows custom methods to be added to
 // TidbClusterLister.
 type TidbClusterListerExpansion interface{}
@@ -71,6 +87,14 @@ type TidbClusterListerExpansion interface{}
 // TidbClusterNamespaceLister.
 type TidbClusterNamespaceListerExpansion interface{}
 
+// TidbClusterReplicationListerExpansion allows custom methods to be added to
+// TidbClusterReplicationLister.
+type TidbClusterReplicationListerExpansion interface{}
+
+// TidbClusterReplicationNamespaceListerExpansion allows custom methods to be added to
+// TidbClusterReplicationNamespaceLister.
+type TidbClusterReplicationNamespaceListerExpansion interface{}
+
 // TidbDashboardListerExpansion allows custom methods to be added to
 // TidbDashboardLister.
 type TidbDashboardListerExpansion interface{}
@@ -24,6 +24,7 @@ import (
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/third_party/k8s"
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -56,17 +57,20 @@ type defaultBackupControl struct {
 }
 
 // UpdateBackup executes the core logic loop for a Backup.
-func (c *defaultBackupControl) UpdateBackup(backup *v1alpha1.Backup) error {
+func (c *defaultBackupControl) UpdateBackup(backup *v1alpha1.Backup) (err error) {
+	ctx, span := tracing.StartObject(context.Background(), "Backup.Reconcile", backup.GetNamespace(), backup.GetName())
+	defer func() { tracing.End(span, err) }()
+
 	backup.SetGroupVersionKind(controller.BackupControllerKind)
-	if err := c.addProtectionFinalizer(backup); err != nil {
+	if err := tracing.Phase(ctx, "add_finalizer", func() error { return c.addProtectionFinalizer(backup) }); err != nil {
 		return err
 	}
 
-	if err := c.removeProtectionFinalizer(backup); err != nil {
+	if err := tracing.Phase(ctx, "remove_finalizer", func() error { return c.removeProtectionFinalizer(backup) }); err != nil {
 		return err
 	}
 
-	return c.updateBackup(backup)
+	return tracing.Phase(ctx, "sync", func() error { return c.updateBackup(backup) })
 }
 
 // UpdateStatus updates the status for a Backup, include condition and status info
@@ -59,6 +59,11 @@ func NewController(deps *controller.Dependencies) *Controller {
 	backupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: c.updateBackup,
 		UpdateFunc: func(old, cur interface{}) {
+			if oldBackup, ok := old.(*v1alpha1.Backup); ok {
+				if curBackup, ok := cur.(*v1alpha1.Backup); ok {
+					deps.BRNotifier.NotifyBackup(oldBackup, curBackup)
+				}
+			}
 			c.updateBackup(cur)
 		},
 		DeleteFunc: c.updateBackup,
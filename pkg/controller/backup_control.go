@@ -50,6 +50,7 @@ type BackupControlInterface interface {
 	GetBackup(backup *v1alpha1.Backup) (*v1alpha1.Backup, error)
 	DeleteBackup(backup *v1alpha1.Backup) error
 	TruncateLogBackup(logBackup *v1alpha1.Backup, truncateTSO uint64) error
+	UpdateLogSubcommand(logBackup *v1alpha1.Backup, subcommand v1alpha1.LogSubCommandType) error
 }
 
 type realBackupControl struct {
@@ -127,6 +128,22 @@ func (c *realBackupControl) TruncateLogBackup(backup *v1alpha1.Backup, truncateT
 	return err
 }
 
+func (c *realBackupControl) UpdateLogSubcommand(backup *v1alpha1.Backup, subcommand v1alpha1.LogSubCommandType) error {
+	ns := backup.GetNamespace()
+	backupName := backup.GetName()
+
+	bsName := backup.GetLabels()[label.BackupScheduleLabelKey]
+	backup.Spec.LogSubcommand = subcommand
+	_, err := c.cli.PingcapV1alpha1().Backups(ns).Update(context.TODO(), backup, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("failed to update the subcommand of Log Backup: [%s/%s] for backupSchedule/%s to %s, err: %v", ns, backupName, bsName, subcommand, err)
+	} else {
+		klog.V(4).Infof("update the subcommand of log backup: [%s/%s] to %s successfully, backupSchedule/%s", ns, backupName, subcommand, bsName)
+	}
+	c.recordBackupEvent("update", backup, err)
+	return err
+}
+
 func (c *realBackupControl) recordBackupEvent(verb string, backup *v1alpha1.Backup, err error) {
 	backupName := backup.GetName()
 	ns := backup.GetNamespace()
@@ -212,4 +229,15 @@ func (fbc *FakeBackupControl) TruncateLogBackup(backup *v1alpha1.Backup, truncat
 	return fbc.backupIndexer.Update(backup)
 }
 
+// UpdateLogSubcommand updates the subcommand of the log backup
+func (fbc *FakeBackupControl) UpdateLogSubcommand(backup *v1alpha1.Backup, subcommand v1alpha1.LogSubCommandType) error {
+	defer fbc.createBackupTracker.Inc()
+	if fbc.createBackupTracker.ErrorReady() {
+		defer fbc.createBackupTracker.Reset()
+		return fbc.createBackupTracker.GetError()
+	}
+	backup.Spec.LogSubcommand = subcommand
+	return fbc.backupIndexer.Update(backup)
+}
+
 var _ BackupControlInterface = &FakeBackupControl{}
@@ -22,6 +22,8 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
 	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
@@ -62,8 +64,16 @@ type BackupUpdateStatus struct {
 	ProgressStep *string
 	// Progress is the step's progress value.
 	Progress *float64
+	// ProgressSpeed is the step's speed reported by BR.
+	ProgressSpeed *string
 	// ProgressUpdateTime is the progress update time.
 	ProgressUpdateTime *metav1.Time
+	// StorageClass is the storage class the backup data is transitioned to.
+	StorageClass *string
+	// StorageClassTransitionTime is the time at which the backup data was transitioned.
+	StorageClassTransitionTime *metav1.Time
+	// Throttle is the current throttling of the backup.
+	Throttle *v1alpha1.BackupThrottleStatus
 
 	// RetryNum is the number of retry
 	RetryNum *int
@@ -79,6 +89,9 @@ type BackupUpdateStatus struct {
 	OriginalReason *string
 }
 
+// BRProgressStepStarted is the reason of the event emitted when BR starts a new step of the backup or restore
+const BRProgressStepStarted = "BRProgressStepStarted"
+
 // BackupConditionUpdaterInterface enables updating Backup conditions.
 type BackupConditionUpdaterInterface interface {
 	Update(backup *v1alpha1.Backup, condition *v1alpha1.BackupCondition, newStatus *BackupUpdateStatus) error
@@ -115,6 +128,7 @@ func (u *realBackupConditionUpdater) Update(backup *v1alpha1.Backup, condition *
 			utilruntime.HandleError(fmt.Errorf("error getting updated backup %s/%s from lister: %v", ns, backupName, err))
 			return err
 		}
+		isNewStep := newStatus != nil && isNewBRProgressStep(backup.Status.Progresses, newStatus.ProgressStep)
 		isUpdate := false
 		// log backup needs update both subcommand status and whole backup status.
 		if backup.Spec.Mode == v1alpha1.BackupModeLog {
@@ -126,6 +140,9 @@ func (u *realBackupConditionUpdater) Update(backup *v1alpha1.Backup, condition *
 			_, updateErr := u.cli.PingcapV1alpha1().Backups(ns).Update(context.TODO(), backup, metav1.UpdateOptions{})
 			if updateErr == nil {
 				klog.Infof("Backup: [%s/%s] updated successfully", ns, backupName)
+				if isNewStep {
+					u.recorder.Eventf(backup, corev1.EventTypeNormal, BRProgressStepStarted, "BR step %q started", *newStatus.ProgressStep)
+				}
 				return nil
 			}
 			klog.Errorf("Failed to update backup [%s/%s], error: %v", ns, backupName, updateErr)
@@ -185,13 +202,24 @@ func updateBackupStatus(status *v1alpha1.BackupStatus, newStatus *BackupUpdateSt
 		isUpdate = true
 	}
 	if newStatus.ProgressStep != nil {
-		progresses, updated := updateBRProgress(status.Progresses, newStatus.ProgressStep, newStatus.Progress, newStatus.ProgressUpdateTime)
+		progresses, updated := updateBRProgress(status.Progresses, newStatus.ProgressStep, newStatus.Progress, newStatus.ProgressSpeed, newStatus.ProgressUpdateTime)
 		if updated {
 			status.Progresses = progresses
 			isUpdate = true
 		}
 	}
 
+	if newStatus.StorageClass != nil && status.StorageClass != *newStatus.StorageClass {
+		status.StorageClass = *newStatus.StorageClass
+		status.StorageClassTransitionTime = newStatus.StorageClassTransitionTime
+		isUpdate = true
+	}
+
+	if newStatus.Throttle != nil && !apiequality.Semantic.DeepEqual(status.Throttle, newStatus.Throttle) {
+		status.Throttle = newStatus.Throttle.DeepCopy()
+		isUpdate = true
+	}
+
 	if newStatus.RetryNum != nil || newStatus.RealRetryAt != nil {
 		isUpdate = updateBackoffRetryStatus(status, newStatus)
 	}
@@ -473,7 +501,7 @@ func updateLogSubCommandConditionOnly(status *v1alpha1.LogSubCommandStatus, cond
 }
 
 // updateBRProgress updates progress for backup/restore.
-func updateBRProgress(progresses []v1alpha1.Progress, step *string, progress *float64, updateTime *metav1.Time) ([]v1alpha1.Progress, bool) {
+func updateBRProgress(progresses []v1alpha1.Progress, step *string, progress *float64, speed *string, updateTime *metav1.Time) ([]v1alpha1.Progress, bool) {
 	var oldProgress *v1alpha1.Progress
 	for i, p := range progresses {
 		if p.Step == *step {
@@ -494,11 +522,15 @@ func updateBRProgress(progresses []v1alpha1.Progress, step *string, progress *fl
 	// no such progress, will new
 	if oldProgress == nil {
 		makeSureLastProgressOver()
-		progresses = append(progresses, v1alpha1.Progress{
+		newProgress := v1alpha1.Progress{
 			Step:               *step,
 			Progress:           *progress,
 			LastTransitionTime: *updateTime,
-		})
+		}
+		if speed != nil {
+			newProgress.Speed = *speed
+		}
+		progresses = append(progresses, newProgress)
 		return progresses, true
 	}
 
@@ -508,6 +540,11 @@ func updateBRProgress(progresses []v1alpha1.Progress, step *string, progress *fl
 		isUpdate = true
 	}
 
+	if speed != nil && oldProgress.Speed != *speed {
+		oldProgress.Speed = *speed
+		isUpdate = true
+	}
+
 	if oldProgress.LastTransitionTime != *updateTime {
 		oldProgress.LastTransitionTime = *updateTime
 		isUpdate = true
@@ -516,6 +553,19 @@ func updateBRProgress(progresses []v1alpha1.Progress, step *string, progress *fl
 	return progresses, isUpdate
 }
 
+// isNewBRProgressStep returns whether the step is not in the progresses yet
+func isNewBRProgressStep(progresses []v1alpha1.Progress, step *string) bool {
+	if step == nil {
+		return false
+	}
+	for _, p := range progresses {
+		if p.Step == *step {
+			return false
+		}
+	}
+	return true
+}
+
 func updateBackoffRetryStatus(status *v1alpha1.BackupStatus, newStatus *BackupUpdateStatus) bool {
 	isUpdate := false
 	currentRecord := getCurrentBackoffRetryRecord(status, newStatus)
@@ -0,0 +1,134 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+)
+
+// brJobAdmissionTTL is how long an admitted job is counted before it shows up in the job lister
+const brJobAdmissionTTL = time.Minute
+
+// BRJobLimiter limits the number of the backup and restore jobs running concurrently, both in
+// the whole operator and in each namespace, so that the jobs created by many backup schedules
+// firing together do not saturate the network or the IO of TiKV.
+type BRJobLimiter struct {
+	maxJobs             int
+	maxJobsPerNamespace int
+	jobLister           batchlisters.JobLister
+
+	lock sync.Mutex
+	// admitted contains the jobs admitted but may not be seen by the job lister yet
+	admitted map[string]time.Time
+	now      func() time.Time
+}
+
+// NewBRJobLimiter returns a BRJobLimiter, a limit of 0 means unlimited.
+func NewBRJobLimiter(maxJobs, maxJobsPerNamespace int, jobLister batchlisters.JobLister) *BRJobLimiter {
+	return &BRJobLimiter{
+		maxJobs:             maxJobs,
+		maxJobsPerNamespace: maxJobsPerNamespace,
+		jobLister:           jobLister,
+		admitted:            map[string]time.Time{},
+		now:                 time.Now,
+	}
+}
+
+// Admit returns whether the job can be created now, the reason is returned if it can't.
+// The admitted job is counted as running until it's created and finished.
+func (l *BRJobLimiter) Admit(ns, jobName string) (bool, string, error) {
+	if l == nil || (l.maxJobs <= 0 && l.maxJobsPerNamespace <= 0) {
+		return true, "", nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	running, err := l.runningJobs()
+	if err != nil {
+		return false, "", err
+	}
+	key := ns + "/" + jobName
+	if _, ok := running[key]; ok {
+		return true, "", nil
+	}
+
+	total, inNamespace := len(running), 0
+	for _, jobNs := range running {
+		if jobNs == ns {
+			inNamespace++
+		}
+	}
+	if l.maxJobs > 0 && total >= l.maxJobs {
+		return false, fmt.Sprintf("%d backup and restore jobs are running, reaching the limit %d of the operator", total, l.maxJobs), nil
+	}
+	if l.maxJobsPerNamespace > 0 && inNamespace >= l.maxJobsPerNamespace {
+		return false, fmt.Sprintf("%d backup and restore jobs are running in namespace %s, reaching the limit %d of each namespace", inNamespace, ns, l.maxJobsPerNamespace), nil
+	}
+
+	l.admitted[key] = l.now()
+	return true, "", nil
+}
+
+// runningJobs returns the namespaces of the running jobs and the admitted jobs, keyed by namespace/name
+func (l *BRJobLimiter) runningJobs() (map[string]string, error) {
+	running := map[string]string{}
+	created := map[string]struct{}{}
+	for _, jobLabel := range []label.Label{label.NewBackup().BackupJob(), label.NewRestore().RestoreJob()} {
+		selector, err := jobLabel.Selector()
+		if err != nil {
+			return nil, err
+		}
+		jobs, err := l.jobLister.List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("list backup and restore jobs failed, err: %v", err)
+		}
+		for _, job := range jobs {
+			key := job.Namespace + "/" + job.Name
+			created[key] = struct{}{}
+			if !isJobFinished(job) {
+				running[key] = job.Namespace
+			}
+		}
+	}
+
+	now := l.now()
+	for key, admitTime := range l.admitted {
+		if _, ok := created[key]; ok || now.Sub(admitTime) > brJobAdmissionTTL {
+			delete(l.admitted, key)
+			continue
+		}
+		ns, _, _ := strings.Cut(key, "/")
+		running[key] = ns
+	}
+	return running, nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
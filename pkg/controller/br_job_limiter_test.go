@@ -0,0 +1,95 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBRJobLimiter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	informer := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0).Batch().V1().Jobs()
+	addJob := func(ns, name string, l label.Label, finished bool) {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: l}}
+		if finished {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		}
+		g.Expect(informer.Informer().GetIndexer().Add(job)).To(Succeed())
+	}
+
+	// unlimited
+	admitted, _, err := NewBRJobLimiter(0, 0, informer.Lister()).Admit("ns1", "backup-a")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeTrue())
+
+	limiter := NewBRJobLimiter(3, 2, informer.Lister())
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	addJob("ns1", "backup-a", label.NewBackup().BackupJob(), false)
+	addJob("ns1", "backup-b", label.NewBackup().BackupJob(), true)
+	addJob("ns1", "restore-c", label.NewRestore().RestoreWarmUpJob(), false)
+
+	// the finished job and the jobs other than backup and restore are not counted
+	admitted, _, err = limiter.Admit("ns1", "restore-d")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeTrue())
+
+	// the admitted job is counted before it's created
+	admitted, reason, err := limiter.Admit("ns1", "backup-e")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeFalse())
+	g.Expect(reason).To(ContainSubstring("namespace ns1"))
+
+	// the job admitted or running is admitted again
+	admitted, _, err = limiter.Admit("ns1", "restore-d")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeTrue())
+	admitted, _, err = limiter.Admit("ns1", "backup-a")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeTrue())
+
+	admitted, _, err = limiter.Admit("ns2", "backup-f")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeTrue())
+	admitted, reason, err = limiter.Admit("ns2", "backup-g")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeFalse())
+	g.Expect(reason).To(ContainSubstring("limit 3 of the operator"))
+
+	// the admitted job is not counted after it finishes
+	addJob("ns1", "restore-d", label.NewRestore().RestoreJob(), true)
+	admitted, _, err = limiter.Admit("ns2", "backup-g")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeTrue())
+
+	// the admitted job is not counted if it's not created in time
+	now = now.Add(2 * brJobAdmissionTTL)
+	addJob("ns1", "backup-a", label.NewBackup().BackupJob(), true)
+	admitted, _, err = limiter.Admit("ns1", "backup-e")
+	g.Expect(err).To(Succeed())
+	g.Expect(admitted).To(BeTrue())
+	g.Expect(limiter.admitted).To(HaveLen(1))
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// brNotificationTimeout is the timeout of posting a notification to the webhook
+	brNotificationTimeout = 10 * time.Second
+	// brNotificationURLKey is the key of the webhook URL in the notification secret
+	brNotificationURLKey = "url"
+	// brNotificationTokenKey is the key of the bearer token in the notification secret
+	brNotificationTokenKey = "token"
+
+	// BRNotificationFailed is the reason of the event emitted when a notification can't be posted
+	BRNotificationFailed = "BRNotificationFailed"
+)
+
+// defaultBRProgressMilestones are the progress percentages notified by default
+var defaultBRProgressMilestones = []int32{25, 50, 75}
+
+// BRNotification is the payload posted to the webhook of the backup or restore
+type BRNotification struct {
+	// Kind is Backup or Restore
+	Kind      string                       `json:"kind"`
+	Namespace string                       `json:"namespace"`
+	Name      string                       `json:"name"`
+	Event     v1alpha1.BRNotificationEvent `json:"event"`
+	Phase     string                       `json:"phase"`
+	// Step and Progress are only set by the Progress event
+	Step     string  `json:"step,omitempty"`
+	Progress float64 `json:"progress,omitempty"`
+	// Message is the reason of the Failed event
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// BRNotifier posts the events of the backups and restores to the webhooks configured in their
+// spec.notifications. The events are detected from the status changes seen by the informers,
+// so the changes happened while tidb-operator is down are not notified.
+type BRNotifier struct {
+	secretLister corelisterv1.SecretLister
+	recorder     record.EventRecorder
+	client       *http.Client
+	now          func() time.Time
+}
+
+// NewBRNotifier returns a BRNotifier
+func NewBRNotifier(secretLister corelisterv1.SecretLister, recorder record.EventRecorder) *BRNotifier {
+	return &BRNotifier{
+		secretLister: secretLister,
+		recorder:     recorder,
+		client:       &http.Client{Timeout: brNotificationTimeout},
+		now:          time.Now,
+	}
+}
+
+// NotifyBackup posts the events between the old and the current status of the backup asynchronously
+func (n *BRNotifier) NotifyBackup(old, cur *v1alpha1.Backup) {
+	if n == nil || cur.Spec.Notifications == nil {
+		return
+	}
+	notifications := brNotificationsOf(cur.Spec.Notifications,
+		string(old.Status.Phase), string(cur.Status.Phase), old.Status.Progresses, cur.Status.Progresses)
+	n.postAll(cur, "Backup", cur.Namespace, cur.Name, cur.Spec.Notifications, notifications, backupFailedMessage(cur))
+}
+
+// NotifyRestore posts the events between the old and the current status of the restore asynchronously
+func (n *BRNotifier) NotifyRestore(old, cur *v1alpha1.Restore) {
+	if n == nil || cur.Spec.Notifications == nil {
+		return
+	}
+	notifications := brNotificationsOf(cur.Spec.Notifications,
+		string(old.Status.Phase), string(cur.Status.Phase), old.Status.Progresses, cur.Status.Progresses)
+	n.postAll(cur, "Restore", cur.Namespace, cur.Name, cur.Spec.Notifications, notifications, restoreFailedMessage(cur))
+}
+
+func (n *BRNotifier) postAll(obj runtime.Object, kind, ns, name string, spec *v1alpha1.BRNotifications, notifications []BRNotification, failedMessage string) {
+	if len(notifications) == 0 {
+		return
+	}
+	for i := range notifications {
+		notifications[i].Kind = kind
+		notifications[i].Namespace = ns
+		notifications[i].Name = name
+		notifications[i].Time = n.now()
+		if notifications[i].Event == v1alpha1.BRNotificationEventFailed {
+			notifications[i].Message = failedMessage
+		}
+	}
+	go func() {
+		for _, notification := range notifications {
+			if err := n.post(ns, &spec.Webhook, &notification); err != nil {
+				klog.Warningf("%s %s/%s: failed to post the %s notification: %v", kind, ns, name, notification.Event, err)
+				n.recorder.Eventf(obj, corev1.EventTypeWarning, BRNotificationFailed, "Failed to post the %s notification: %v", notification.Event, err)
+			}
+		}
+	}()
+}
+
+// post posts the notification to the webhook
+func (n *BRNotifier) post(ns string, webhook *v1alpha1.BRNotificationWebhook, notification *BRNotification) error {
+	url, token := webhook.URL, ""
+	if webhook.SecretName != "" {
+		secret, err := n.secretLister.Secrets(ns).Get(webhook.SecretName)
+		if err != nil {
+			return fmt.Errorf("failed to get the secret %s/%s: %v", ns, webhook.SecretName, err)
+		}
+		url, token = string(secret.Data[brNotificationURLKey]), string(secret.Data[brNotificationTokenKey])
+	}
+	if url == "" {
+		return fmt.Errorf("the url of the webhook is empty")
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), brNotificationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook responded %s", resp.Status)
+	}
+	return nil
+}
+
+// brNotificationsOf returns the notifications of the events between the old and the current phase and progresses,
+// only the highest milestone reached by a step is notified if the progress crosses several milestones at once
+func brNotificationsOf(spec *v1alpha1.BRNotifications, oldPhase, curPhase string, oldProgresses, curProgresses []v1alpha1.Progress) []BRNotification {
+	var notifications []BRNotification
+	notify := func(event v1alpha1.BRNotificationEvent) bool {
+		return len(spec.Events) == 0 || slices.Contains(spec.Events, event)
+	}
+
+	if oldPhase != curPhase {
+		var event v1alpha1.BRNotificationEvent
+		switch curPhase {
+		case string(v1alpha1.BackupRunning):
+			event = v1alpha1.BRNotificationEventStarted
+		case string(v1alpha1.BackupComplete):
+			event = v1alpha1.BRNotificationEventCompleted
+		case string(v1alpha1.BackupFailed):
+			event = v1alpha1.BRNotificationEventFailed
+		}
+		if event != "" && notify(event) {
+			notifications = append(notifications, BRNotification{Event: event, Phase: curPhase})
+		}
+	}
+
+	if !notify(v1alpha1.BRNotificationEventProgress) {
+		return notifications
+	}
+	milestones := spec.ProgressMilestones
+	if len(milestones) == 0 {
+		milestones = defaultBRProgressMilestones
+	}
+	for _, cur := range curProgresses {
+		var oldProgress float64
+		for _, old := range oldProgresses {
+			if old.Step == cur.Step {
+				oldProgress = old.Progress
+			}
+		}
+		var reached int32 = -1
+		for _, m := range milestones {
+			if oldProgress < float64(m) && cur.Progress >= float64(m) && m > reached {
+				reached = m
+			}
+		}
+		if reached >= 0 {
+			notifications = append(notifications, BRNotification{
+				Event:    v1alpha1.BRNotificationEventProgress,
+				Phase:    curPhase,
+				Step:     cur.Step,
+				Progress: cur.Progress,
+			})
+		}
+	}
+	return notifications
+}
+
+func backupFailedMessage(backup *v1alpha1.Backup) string {
+	for _, c := range backup.Status.Conditions {
+		if c.Type == v1alpha1.BackupFailed {
+			return c.Message
+		}
+	}
+	return ""
+}
+
+func restoreFailedMessage(restore *v1alpha1.Restore) string {
+	for _, c := range restore.Status.Conditions {
+		if c.Type == v1alpha1.RestoreFailed {
+			return c.Message
+		}
+	}
+	return ""
+}
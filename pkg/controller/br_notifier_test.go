@@ -0,0 +1,120 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestBRNotificationsOf(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	events := func(notifications []BRNotification) []v1alpha1.BRNotificationEvent {
+		var res []v1alpha1.BRNotificationEvent
+		for _, n := range notifications {
+			res = append(res, n.Event)
+		}
+		return res
+	}
+	spec := &v1alpha1.BRNotifications{}
+
+	notifications := brNotificationsOf(spec, string(v1alpha1.BackupPrepare), string(v1alpha1.BackupRunning), nil, nil)
+	g.Expect(events(notifications)).To(Equal([]v1alpha1.BRNotificationEvent{v1alpha1.BRNotificationEventStarted}))
+
+	notifications = brNotificationsOf(spec, string(v1alpha1.BackupRunning), string(v1alpha1.BackupRunning), nil, nil)
+	g.Expect(notifications).To(BeEmpty())
+
+	notifications = brNotificationsOf(spec, string(v1alpha1.BackupRunning), string(v1alpha1.BackupFailed), nil, nil)
+	g.Expect(events(notifications)).To(Equal([]v1alpha1.BRNotificationEvent{v1alpha1.BRNotificationEventFailed}))
+
+	// only the highest milestone crossed by a step is notified
+	old := []v1alpha1.Progress{{Step: "Full Backup", Progress: 10}}
+	cur := []v1alpha1.Progress{{Step: "Full Backup", Progress: 60}, {Step: "Checksum", Progress: 20}}
+	notifications = brNotificationsOf(spec, string(v1alpha1.BackupRunning), string(v1alpha1.BackupRunning), old, cur)
+	g.Expect(notifications).To(Equal([]BRNotification{{
+		Event:    v1alpha1.BRNotificationEventProgress,
+		Phase:    string(v1alpha1.BackupRunning),
+		Step:     "Full Backup",
+		Progress: 60,
+	}}))
+
+	spec.ProgressMilestones = []int32{10}
+	notifications = brNotificationsOf(spec, string(v1alpha1.BackupRunning), string(v1alpha1.BackupComplete), old, cur)
+	g.Expect(events(notifications)).To(Equal([]v1alpha1.BRNotificationEvent{
+		v1alpha1.BRNotificationEventCompleted, v1alpha1.BRNotificationEventProgress,
+	}))
+	g.Expect(notifications[1].Step).To(Equal("Checksum"))
+
+	spec.Events = []v1alpha1.BRNotificationEvent{v1alpha1.BRNotificationEventFailed}
+	notifications = brNotificationsOf(spec, string(v1alpha1.BackupRunning), string(v1alpha1.BackupComplete), old, cur)
+	g.Expect(notifications).To(BeEmpty())
+}
+
+func TestBRNotifierPost(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var received []BRNotification
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unavailable" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var n BRNotification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, n)
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	kubeCli := kubefake.NewSimpleClientset()
+	informerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	secretInformer := informerFactory.Core().V1().Secrets()
+	secretInformer.Informer().GetIndexer().Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "webhook"},
+		Data: map[string][]byte{
+			brNotificationURLKey:   []byte(server.URL),
+			brNotificationTokenKey: []byte("secret-token"),
+		},
+	})
+	n := NewBRNotifier(secretInformer.Lister(), record.NewFakeRecorder(10))
+
+	notification := &BRNotification{Kind: "Backup", Namespace: "ns", Name: "backup", Event: v1alpha1.BRNotificationEventCompleted}
+	err := n.post("ns", &v1alpha1.BRNotificationWebhook{URL: server.URL}, notification)
+	g.Expect(err).NotTo(HaveOccurred())
+	err = n.post("ns", &v1alpha1.BRNotificationWebhook{SecretName: "webhook"}, notification)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(received).To(HaveLen(2))
+	g.Expect(received[0].Event).To(Equal(v1alpha1.BRNotificationEventCompleted))
+	g.Expect(authorizations).To(Equal([]string{"", "Bearer secret-token"}))
+
+	err = n.post("ns", &v1alpha1.BRNotificationWebhook{SecretName: "not-exist"}, notification)
+	g.Expect(err).To(HaveOccurred())
+	err = n.post("ns", &v1alpha1.BRNotificationWebhook{URL: server.URL + "/unavailable"}, notification)
+	g.Expect(err).To(HaveOccurred())
+}
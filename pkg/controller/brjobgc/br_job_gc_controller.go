@@ -0,0 +1,308 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package brjobgc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// interval is the interval between the collections of the finished jobs
+	interval = 5 * time.Minute
+	// maxJobSummaries is the number of the job summaries kept in the status of a backup or restore
+	maxJobSummaries = 10
+)
+
+// finishedJob is a finished job of a finished backup or restore
+type finishedJob struct {
+	job        *batchv1.Job
+	owner      runtime.Object
+	succeeded  bool
+	finishTime time.Time
+	message    string
+}
+
+// Controller deletes the finished jobs of the finished backups and restores, including the backup,
+// restore, clean and warmup jobs, by the TTL and the history limits periodically, so that the
+// completed job pods don't accumulate in the namespaces. The summaries of the deleted jobs are kept
+// in the status of the backups and restores.
+//
+// The jobs are deleted by tidb-operator instead of the TTL controller of Kubernetes, as the jobs of
+// the running backups and restores are recreated once they are deleted.
+type Controller struct {
+	deps            *controller.Dependencies
+	ttl             time.Duration
+	successfulLimit int
+	failedLimit     int
+	now             func() time.Time
+}
+
+// NewController returns a garbage collector of the backup and restore jobs
+func NewController(deps *controller.Dependencies) *Controller {
+	return &Controller{
+		deps:            deps,
+		ttl:             deps.CLIConfig.BRJobTTLAfterFinished,
+		successfulLimit: deps.CLIConfig.BRSuccessfulJobsHistoryLimit,
+		failedLimit:     deps.CLIConfig.BRFailedJobsHistoryLimit,
+		now:             time.Now,
+	}
+}
+
+// Name returns the name of the controller.
+func (c *Controller) Name() string {
+	return "br-job-gc"
+}
+
+// Run collects the finished jobs every interval until stopCh is closed, the workers are ignored
+// as the collections are serial.
+func (c *Controller) Run(_ int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	if c.ttl <= 0 && c.successfulLimit < 0 && c.failedLimit < 0 {
+		<-stopCh
+		return
+	}
+	klog.Infof("Starting br-job-gc controller, ttl %s, successful jobs history limit %d, failed jobs history limit %d",
+		c.ttl, c.successfulLimit, c.failedLimit)
+	defer klog.Info("Shutting down br-job-gc controller")
+
+	wait.Until(func() {
+		if err := c.Collect(); err != nil {
+			klog.Errorf("br-job-gc: collect the finished jobs failed: %v", err)
+		}
+	}, interval, stopCh)
+}
+
+// Collect deletes the finished jobs exceeding the TTL or the history limits after recording
+// their summaries in the status of their owners
+func (c *Controller) Collect() error {
+	jobs, err := c.findFinishedJobs()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, j := range c.expiredJobs(jobs) {
+		if err := c.recordSummary(j); err != nil {
+			errs = append(errs, fmt.Errorf("record the summary of job %s/%s failed: %v", j.job.Namespace, j.job.Name, err))
+			continue
+		}
+		if err := c.deps.JobControl.DeleteJob(j.owner, j.job); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("delete job %s/%s failed: %v", j.job.Namespace, j.job.Name, err))
+			continue
+		}
+		klog.Infof("br-job-gc: deleted finished job %s/%s", j.job.Namespace, j.job.Name)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// findFinishedJobs returns the finished jobs whose owning backups or restores are finished
+func (c *Controller) findFinishedJobs() ([]finishedJob, error) {
+	jobs, err := c.deps.JobLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var finished []finishedJob
+	for _, job := range jobs {
+		if job.DeletionTimestamp != nil {
+			continue
+		}
+		ref := metav1.GetControllerOf(job)
+		if ref == nil || ref.APIVersion != v1alpha1.SchemeGroupVersion.String() {
+			continue
+		}
+		succeeded, finishTime, message, ok := jobResult(job)
+		if !ok {
+			continue
+		}
+		owner, err := c.finishedOwner(job.Namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		if owner == nil {
+			continue
+		}
+		finished = append(finished, finishedJob{
+			job:        job,
+			owner:      owner,
+			succeeded:  succeeded,
+			finishTime: finishTime,
+			message:    message,
+		})
+	}
+	return finished, nil
+}
+
+// jobResult returns whether the job succeeded, the time it finished and the failure message,
+// false is returned if the job is not finished yet
+func jobResult(job *batchv1.Job) (bool, time.Time, string, bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return true, cond.LastTransitionTime.Time, "", true
+		case batchv1.JobFailed:
+			return false, cond.LastTransitionTime.Time, cond.Message, true
+		}
+	}
+	return false, time.Time{}, "", false
+}
+
+// finishedOwner returns the backup or restore referred by the owner reference if it's finished,
+// nil is returned for the running ones, whose jobs are never deleted
+func (c *Controller) finishedOwner(ns string, ref *metav1.OwnerReference) (runtime.Object, error) {
+	switch ref.Kind {
+	case controller.BackupControllerKind.Kind:
+		backup, err := c.deps.BackupLister.Backups(ns).Get(ref.Name)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// the clean jobs of the backups being deleted are waited by the backup cleaner
+		if backup.UID != ref.UID || backup.DeletionTimestamp != nil {
+			return nil, nil
+		}
+		if backup.Spec.Mode == v1alpha1.BackupModeLog {
+			if !v1alpha1.IsLogBackupAlreadyStop(backup) {
+				return nil, nil
+			}
+		} else if !v1alpha1.IsBackupComplete(backup) && !v1alpha1.IsBackupFailed(backup) {
+			return nil, nil
+		}
+		return backup, nil
+	case controller.RestoreControllerKind.Kind:
+		restore, err := c.deps.RestoreLister.Restores(ns).Get(ref.Name)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if restore.UID != ref.UID || restore.DeletionTimestamp != nil {
+			return nil, nil
+		}
+		if !v1alpha1.IsRestoreComplete(restore) && !v1alpha1.IsRestoreFailed(restore) {
+			return nil, nil
+		}
+		return restore, nil
+	}
+	return nil, nil
+}
+
+// expiredJobs returns the jobs finished longer than the TTL, and the jobs exceeding the history
+// limits in each namespace, the latest finished jobs are kept
+func (c *Controller) expiredJobs(jobs []finishedJob) []finishedJob {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].finishTime.After(jobs[j].finishTime)
+	})
+
+	var expired []finishedJob
+	succeeded, failed := map[string]int{}, map[string]int{}
+	for _, j := range jobs {
+		ns := j.job.Namespace
+		var exceeded bool
+		if j.succeeded {
+			exceeded = c.successfulLimit >= 0 && succeeded[ns] >= c.successfulLimit
+			succeeded[ns]++
+		} else {
+			exceeded = c.failedLimit >= 0 && failed[ns] >= c.failedLimit
+			failed[ns]++
+		}
+		if exceeded || (c.ttl > 0 && c.now().Sub(j.finishTime) >= c.ttl) {
+			expired = append(expired, j)
+		}
+	}
+	return expired
+}
+
+// recordSummary records the summary of the job in the status of its owner, the latest summaries
+// are kept
+func (c *Controller) recordSummary(j finishedJob) error {
+	summary := v1alpha1.BRJobSummary{
+		Name:       j.job.Name,
+		Succeeded:  j.succeeded,
+		StartTime:  j.job.Status.StartTime,
+		FinishTime: &metav1.Time{Time: j.finishTime},
+		Message:    j.message,
+	}
+	ns := j.job.Namespace
+	switch owner := j.owner.(type) {
+	case *v1alpha1.Backup:
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			backup, err := c.deps.Clientset.PingcapV1alpha1().Backups(ns).Get(context.TODO(), owner.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			summaries, ok := appendSummary(backup.Status.JobSummaries, summary)
+			if !ok {
+				return nil
+			}
+			backup.Status.JobSummaries = summaries
+			_, err = c.deps.Clientset.PingcapV1alpha1().Backups(ns).Update(context.TODO(), backup, metav1.UpdateOptions{})
+			return err
+		})
+	case *v1alpha1.Restore:
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			restore, err := c.deps.Clientset.PingcapV1alpha1().Restores(ns).Get(context.TODO(), owner.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			summaries, ok := appendSummary(restore.Status.JobSummaries, summary)
+			if !ok {
+				return nil
+			}
+			restore.Status.JobSummaries = summaries
+			_, err = c.deps.Clientset.PingcapV1alpha1().Restores(ns).Update(context.TODO(), restore, metav1.UpdateOptions{})
+			return err
+		})
+	}
+	return nil
+}
+
+// appendSummary appends the summary if it's not recorded yet, and drops the earliest summaries
+// exceeding maxJobSummaries
+func appendSummary(summaries []v1alpha1.BRJobSummary, summary v1alpha1.BRJobSummary) ([]v1alpha1.BRJobSummary, bool) {
+	for _, s := range summaries {
+		if s.Name == summary.Name {
+			return summaries, false
+		}
+	}
+	summaries = append(summaries, summary)
+	if len(summaries) > maxJobSummaries {
+		summaries = summaries[len(summaries)-maxJobSummaries:]
+	}
+	return summaries, true
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package brjobgc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCollect(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	deps.CLIConfig.BRJobTTLAfterFinished = 24 * time.Hour
+	deps.CLIConfig.BRSuccessfulJobsHistoryLimit = 1
+	deps.CLIConfig.BRFailedJobsHistoryLimit = -1
+	c := NewController(deps)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	newBackup := func(name string, phase v1alpha1.BackupConditionType) *v1alpha1.Backup {
+		backup := &v1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID(name)}}
+		v1alpha1.UpdateBackupCondition(&backup.Status, &v1alpha1.BackupCondition{Type: phase, Status: corev1.ConditionTrue})
+		return backup
+	}
+	backups := []*v1alpha1.Backup{
+		newBackup("complete-1", v1alpha1.BackupComplete),
+		newBackup("complete-2", v1alpha1.BackupComplete),
+		newBackup("failed", v1alpha1.BackupFailed),
+		newBackup("running", v1alpha1.BackupRunning),
+	}
+	for _, backup := range backups {
+		g.Expect(deps.InformerFactory.Pingcap().V1alpha1().Backups().Informer().GetIndexer().Add(backup)).Should(Succeed())
+		_, err := deps.Clientset.PingcapV1alpha1().Backups("ns").Create(context.TODO(), backup, metav1.CreateOptions{})
+		g.Expect(err).Should(Succeed())
+	}
+	restore := &v1alpha1.Restore{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "restore", UID: "restore"}}
+	v1alpha1.UpdateRestoreCondition(&restore.Status, &v1alpha1.RestoreCondition{Type: v1alpha1.RestoreFailed, Status: corev1.ConditionTrue})
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().Restores().Informer().GetIndexer().Add(restore)).Should(Succeed())
+	_, err := deps.Clientset.PingcapV1alpha1().Restores("ns").Create(context.TODO(), restore, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+
+	newJob := func(name string, owner metav1.OwnerReference, condition batchv1.JobConditionType, age time.Duration) *batchv1.Job {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns",
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		}}
+		if condition != "" {
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type:               condition,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(now.Add(-age)),
+				Message:            "job failed",
+			}}
+		}
+		return job
+	}
+	jobs := []*batchv1.Job{
+		// the latest successful job is kept by the history limit
+		newJob("backup-complete-1", controller.GetBackupOwnerRef(backups[0]), batchv1.JobComplete, time.Hour),
+		newJob("backup-complete-2", controller.GetBackupOwnerRef(backups[1]), batchv1.JobComplete, 2*time.Hour),
+		// the failed jobs are kept until the TTL
+		newJob("backup-failed", controller.GetBackupOwnerRef(backups[2]), batchv1.JobFailed, time.Hour),
+		newJob("restore-restore", controller.GetRestoreOwnerRef(restore), batchv1.JobFailed, 48*time.Hour),
+		// the jobs of the running backups are never deleted
+		newJob("backup-running", controller.GetBackupOwnerRef(backups[3]), batchv1.JobFailed, 48*time.Hour),
+		// the jobs of the recreated backups are ignored
+		newJob("backup-recreated", controller.GetBackupOwnerRef(&v1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "complete-1", UID: "old"},
+		}), batchv1.JobComplete, 48*time.Hour),
+		// the running jobs are never deleted
+		newJob("restore-running", controller.GetRestoreOwnerRef(restore), "", 0),
+	}
+	for _, job := range jobs {
+		g.Expect(deps.KubeInformerFactory.Batch().V1().Jobs().Informer().GetIndexer().Add(job)).Should(Succeed())
+	}
+
+	finished, err := c.findFinishedJobs()
+	g.Expect(err).Should(Succeed())
+	var names []string
+	for _, j := range c.expiredJobs(finished) {
+		names = append(names, j.job.Name)
+	}
+	g.Expect(names).Should(ConsistOf("backup-complete-2", "restore-restore"))
+
+	g.Expect(c.Collect()).Should(Succeed())
+	backup, err := deps.Clientset.PingcapV1alpha1().Backups("ns").Get(context.TODO(), "complete-2", metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(backup.Status.JobSummaries).Should(HaveLen(1))
+	g.Expect(backup.Status.JobSummaries[0].Name).Should(Equal("backup-complete-2"))
+	g.Expect(backup.Status.JobSummaries[0].Succeeded).Should(BeTrue())
+	r, err := deps.Clientset.PingcapV1alpha1().Restores("ns").Get(context.TODO(), "restore", metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(r.Status.JobSummaries).Should(HaveLen(1))
+	g.Expect(r.Status.JobSummaries[0].Succeeded).Should(BeFalse())
+	g.Expect(r.Status.JobSummaries[0].Message).Should(Equal("job failed"))
+
+	// the summaries are recorded once
+	g.Expect(c.Collect()).Should(Succeed())
+	r, err = deps.Clientset.PingcapV1alpha1().Restores("ns").Get(context.TODO(), "restore", metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(r.Status.JobSummaries).Should(HaveLen(1))
+}
+
+func TestAppendSummary(t *testing.T) {
+	g := NewGomegaWithT(t)
+	var summaries []v1alpha1.BRJobSummary
+	for i := 0; i < maxJobSummaries+2; i++ {
+		var ok bool
+		summaries, ok = appendSummary(summaries, v1alpha1.BRJobSummary{Name: string(rune('a' + i))})
+		g.Expect(ok).Should(BeTrue())
+	}
+	g.Expect(summaries).Should(HaveLen(maxJobSummaries))
+	g.Expect(summaries[0].Name).Should(Equal("c"))
+	_, ok := appendSummary(summaries, v1alpha1.BRJobSummary{Name: "c"})
+	g.Expect(ok).Should(BeFalse())
+}
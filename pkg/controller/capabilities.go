@@ -0,0 +1,169 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	utildiscovery "github.com/pingcap/tidb-operator/pkg/util/discovery"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+)
+
+var (
+	// volumeExpansionMinVersion is the version in which the ExpandPersistentVolumes feature gate is enabled by default
+	volumeExpansionMinVersion = version.MustParseGeneric("v1.11.0")
+	// nativeSidecarMinVersion is the version in which the SidecarContainers feature gate is enabled by default
+	nativeSidecarMinVersion = version.MustParseGeneric("v1.29.0")
+	// schedulingGatesMinVersion is the version in which the PodSchedulingReadiness feature gate is enabled by default
+	schedulingGatesMinVersion = version.MustParseGeneric("v1.27.0")
+)
+
+// Capabilities describes the optional features supported by the Kubernetes cluster.
+// They are probed once at startup and used to degrade the reconciliation gracefully
+// instead of failing in the middle of it on older Kubernetes clusters.
+type Capabilities struct {
+	// ServerVersion is the git version of the Kubernetes API server
+	ServerVersion string
+	// VolumeSnapshot is true if the CSI volume snapshot API snapshot.storage.k8s.io/v1 is served
+	VolumeSnapshot bool
+	// VolumeExpansion is true if the persistent volume claims can be expanded
+	VolumeExpansion bool
+	// PDBGroupVersion is the group version served for the pod disruption budgets,
+	// it's empty if the pod disruption budgets are not supported
+	PDBGroupVersion string
+	// NativeSidecar is true if the init containers with restartPolicy Always are run as sidecars
+	NativeSidecar bool
+	// SchedulingGates is true if the scheduling gates of the pods are respected by the scheduler
+	SchedulingGates bool
+	// PodMonitor is true if the PodMonitor CRD monitoring.coreos.com/v1 of prometheus-operator is installed
+	PodMonitor bool
+}
+
+// AllCapabilities returns the capabilities with all features supported,
+// it's used if the capabilities can't be probed.
+func AllCapabilities() *Capabilities {
+	return &Capabilities{
+		VolumeSnapshot:  true,
+		VolumeExpansion: true,
+		PDBGroupVersion: "policy/v1",
+		NativeSidecar:   true,
+		SchedulingGates: true,
+		PodMonitor:      true,
+	}
+}
+
+// ProbeCapabilities probes the capabilities of the Kubernetes cluster by the discovery client
+func ProbeCapabilities(cli discovery.DiscoveryInterface) (*Capabilities, error) {
+	info, err := cli.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %v", err)
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server version %q: %v", info.GitVersion, err)
+	}
+	caps := &Capabilities{
+		ServerVersion:   info.GitVersion,
+		VolumeExpansion: serverVersion.AtLeast(volumeExpansionMinVersion),
+		NativeSidecar:   serverVersion.AtLeast(nativeSidecarMinVersion),
+		SchedulingGates: serverVersion.AtLeast(schedulingGatesMinVersion),
+	}
+
+	caps.VolumeSnapshot, err = utildiscovery.IsAPIGroupVersionResourceSupported(cli, "snapshot.storage.k8s.io/v1", "volumesnapshots")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check resource snapshot.storage.k8s.io/v1/volumesnapshots: %v", err)
+	}
+	caps.PodMonitor, err = utildiscovery.IsAPIGroupVersionResourceSupported(cli, "monitoring.coreos.com/v1", "podmonitors")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check resource monitoring.coreos.com/v1/podmonitors: %v", err)
+	}
+	for _, gv := range []string{"policy/v1", "policy/v1beta1"} {
+		supported, err := utildiscovery.IsAPIGroupVersionResourceSupported(cli, gv, "poddisruptionbudgets")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check resource %s/poddisruptionbudgets: %v", gv, err)
+		}
+		if supported {
+			caps.PDBGroupVersion = gv
+			break
+		}
+	}
+	return caps, nil
+}
+
+// String returns a summary of the capabilities for logging
+func (c *Capabilities) String() string {
+	return fmt.Sprintf("server version: %s, volume snapshot: %t, volume expansion: %t, pdb: %s, native sidecar: %t, scheduling gates: %t, pod monitor: %t",
+		c.ServerVersion, c.VolumeSnapshot, c.VolumeExpansion, c.PDBGroupVersion, c.NativeSidecar, c.SchedulingGates, c.PodMonitor)
+}
+
+// DegradedFeatures returns the messages of the features used by the tidb cluster
+// but not supported by the Kubernetes cluster
+func (c *Capabilities) DegradedFeatures(tc *v1alpha1.TidbCluster) []string {
+	var msgs []string
+	if !c.NativeSidecar {
+		if components := nativeSidecarComponents(tc); len(components) > 0 {
+			msgs = append(msgs, fmt.Sprintf("native sidecars are not supported by Kubernetes %s, the log tailers of %s run as regular containers",
+				c.ServerVersion, strings.Join(components, ", ")))
+		}
+	}
+	if !c.VolumeExpansion {
+		msgs = append(msgs, fmt.Sprintf("volume expansion is not supported by Kubernetes %s, the storage size increases are not applied", c.ServerVersion))
+	}
+	if !c.SchedulingGates {
+		if components := schedulingGateComponents(tc); len(components) > 0 {
+			msgs = append(msgs, fmt.Sprintf("scheduling gates are not supported by Kubernetes %s, the pods of %s are scheduled regardless of them",
+				c.ServerVersion, strings.Join(components, ", ")))
+		}
+	}
+	if !c.PodMonitor && tc.Spec.PrometheusMonitor != nil {
+		msgs = append(msgs, "the PodMonitor CRD of prometheus-operator is not installed, the PodMonitors of the components are not created")
+	}
+	return msgs
+}
+
+// nativeSidecarComponents returns the components of the tidb cluster whose log tailers are native sidecars
+func nativeSidecarComponents(tc *v1alpha1.TidbCluster) []string {
+	logVolumeSidecar := func(vol *v1alpha1.LogVolumeSpec) bool {
+		return vol != nil && vol.Tailer != nil && vol.Tailer.UseSidecar
+	}
+	var components []string
+	if pd := tc.Spec.PD; pd != nil && logVolumeSidecar(pd.LogVolume) {
+		components = append(components, v1alpha1.PDMemberType.String())
+	}
+	if tidb := tc.Spec.TiDB; tidb != nil && (logVolumeSidecar(tidb.LogVolume) || tidb.GetSlowLogTailerSpec().UseSidecar ||
+		(tidb.LogShipping != nil && tidb.LogShipping.UseSidecar)) {
+		components = append(components, v1alpha1.TiDBMemberType.String())
+	}
+	if tikv := tc.Spec.TiKV; tikv != nil && (logVolumeSidecar(tikv.LogVolume) || tikv.GetLogTailerSpec().UseSidecar) {
+		components = append(components, v1alpha1.TiKVMemberType.String())
+	}
+	if tiflash := tc.Spec.TiFlash; tiflash != nil && tiflash.LogTailer != nil && tiflash.LogTailer.UseSidecar {
+		components = append(components, v1alpha1.TiFlashMemberType.String())
+	}
+	return components
+}
+
+// schedulingGateComponents returns the components of the tidb cluster whose pods have scheduling gates
+func schedulingGateComponents(tc *v1alpha1.TidbCluster) []string {
+	var components []string
+	for _, component := range tc.AllComponentSpec() {
+		if len(component.SchedulingGates()) > 0 {
+			components = append(components, component.MemberType().String())
+		}
+	}
+	return components
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newDiscovery := func(gitVersion string, resources ...*metav1.APIResourceList) *fakediscovery.FakeDiscovery {
+		cli := kubefake.NewSimpleClientset()
+		cli.Fake.Resources = resources
+		d := cli.Discovery().(*fakediscovery.FakeDiscovery)
+		d.FakedServerVersion = &version.Info{GitVersion: gitVersion}
+		return d
+	}
+
+	caps, err := ProbeCapabilities(newDiscovery("v1.30.2",
+		&metav1.APIResourceList{GroupVersion: "snapshot.storage.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "volumesnapshots"}}},
+		&metav1.APIResourceList{GroupVersion: "policy/v1", APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets"}}},
+		&metav1.APIResourceList{GroupVersion: "monitoring.coreos.com/v1", APIResources: []metav1.APIResource{{Name: "podmonitors"}}},
+	))
+	g.Expect(err).Should(Succeed())
+	g.Expect(caps).Should(Equal(&Capabilities{
+		ServerVersion:   "v1.30.2",
+		VolumeSnapshot:  true,
+		VolumeExpansion: true,
+		PDBGroupVersion: "policy/v1",
+		NativeSidecar:   true,
+		SchedulingGates: true,
+		PodMonitor:      true,
+	}))
+
+	caps, err = ProbeCapabilities(newDiscovery("v1.10.13-eks-1",
+		&metav1.APIResourceList{GroupVersion: "policy/v1beta1", APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets"}}},
+	))
+	g.Expect(err).Should(Succeed())
+	g.Expect(caps).Should(Equal(&Capabilities{
+		ServerVersion:   "v1.10.13-eks-1",
+		PDBGroupVersion: "policy/v1beta1",
+	}))
+
+	_, err = ProbeCapabilities(newDiscovery("unknown"))
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestDegradedFeatures(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Spec.PD = &v1alpha1.PDSpec{LogVolume: &v1alpha1.LogVolumeSpec{Tailer: &v1alpha1.LogTailerSpec{UseSidecar: true}}}
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{LogTailer: &v1alpha1.LogTailerSpec{UseSidecar: true}}
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{}
+
+	g.Expect(AllCapabilities().DegradedFeatures(tc)).Should(BeEmpty())
+
+	caps := &Capabilities{ServerVersion: "v1.27.3", VolumeExpansion: true}
+	g.Expect(caps.DegradedFeatures(tc)).Should(Equal([]string{
+		"native sidecars are not supported by Kubernetes v1.27.3, the log tailers of pd, tikv run as regular containers",
+	}))
+
+	caps.VolumeExpansion = false
+	tc.Spec.PD = nil
+	tc.Spec.TiKV = nil
+	g.Expect(caps.DegradedFeatures(tc)).Should(Equal([]string{
+		"volume expansion is not supported by Kubernetes v1.27.3, the storage size increases are not applied",
+	}))
+
+	caps.VolumeExpansion = true
+	tc.Spec.PrometheusMonitor = &v1alpha1.PrometheusMonitorSpec{}
+	g.Expect(caps.DegradedFeatures(tc)).Should(Equal([]string{
+		"the PodMonitor CRD of prometheus-operator is not installed, the PodMonitors of the components are not created",
+	}))
+
+	caps.PodMonitor = true
+	caps.ServerVersion = "v1.26.5"
+	tc.Spec.TiDB.SchedulingGates = []corev1.PodSchedulingGate{{Name: "kueue.x-k8s.io/admission"}}
+	g.Expect(caps.DegradedFeatures(tc)).Should(Equal([]string{
+		"scheduling gates are not supported by Kubernetes v1.26.5, the pods of tidb are scheduled regardless of them",
+	}))
+}
@@ -465,6 +465,7 @@ func (c *Controller) makeCompactJob(compact *v1alpha1.CompactBackup) (*batchv1.J
 			Affinity:          compact.Spec.Affinity,
 			Volumes:           volumes,
 			PriorityClassName: compact.Spec.PriorityClassName,
+			PreemptionPolicy:  compact.Spec.PreemptionPolicy,
 		},
 	}
 
@@ -536,6 +537,9 @@ func (c *Controller) validate(compact *v1alpha1.CompactBackup) error {
 	if spec.MaxRetryTimes < 0 {
 		return errors.NewNoStackError("maxRetryTimes must be greater than or equal to 0")
 	}
+	if spec.PreemptionPolicy != nil && spec.PriorityClassName == "" {
+		return errors.NewNoStackError("priorityClassName must be set along with preemptionPolicy")
+	}
 	return nil
 }
 
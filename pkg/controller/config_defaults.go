@@ -0,0 +1,241 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+)
+
+// ConfigDefaults are the config defaults of the components applied to all the clusters managed by
+// the operator, so the platform teams can enforce the baselines, e.g. the security settings, without
+// changing each CR. The final config of a component is layered as:
+//
+//	operator defaults < config defaults < spec.<component>.config
+//
+// It's loaded from the file specified by --config-defaults-file, which maps the components to
+// their config in TOML format, e.g.
+//
+//	tidb: |
+//	  [security]
+//	  require-secure-transport = true
+//	tikv: |
+//	  [security.encryption]
+//	  data-encryption-method = "aes256-ctr"
+type ConfigDefaults struct {
+	configs map[v1alpha1.MemberType]*config.GenericConfig
+}
+
+// configDefaultsComponents are the components supporting the config defaults, the config of
+// TiFlash is the common config of it.
+var configDefaultsComponents = []v1alpha1.MemberType{
+	v1alpha1.PDMemberType,
+	v1alpha1.TiKVMemberType,
+	v1alpha1.TiDBMemberType,
+	v1alpha1.TiFlashMemberType,
+	v1alpha1.TiCDCMemberType,
+	v1alpha1.TiProxyMemberType,
+}
+
+// LoadConfigDefaults loads the config defaults from the YAML file, nil is returned if path is empty
+func LoadConfigDefaults(path string) (*ConfigDefaults, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config defaults file %s failed: %v", path, err)
+	}
+	return ParseConfigDefaults(data)
+}
+
+// ParseConfigDefaults parses the config defaults from the YAML content
+func ParseConfigDefaults(data []byte) (*ConfigDefaults, error) {
+	texts := map[v1alpha1.MemberType]string{}
+	if err := yaml.Unmarshal(data, &texts); err != nil {
+		return nil, fmt.Errorf("parse config defaults failed: %v", err)
+	}
+	d := &ConfigDefaults{configs: map[v1alpha1.MemberType]*config.GenericConfig{}}
+	for component, text := range texts {
+		if !isConfigDefaultsComponent(component) {
+			return nil, fmt.Errorf("config defaults of component %s are not supported", component)
+		}
+		cfg := config.New(map[string]interface{}{})
+		if err := cfg.UnmarshalTOML([]byte(text)); err != nil {
+			return nil, fmt.Errorf("parse config defaults of component %s failed: %v", component, err)
+		}
+		d.configs[component] = cfg
+	}
+	return d, nil
+}
+
+func isConfigDefaultsComponent(component v1alpha1.MemberType) bool {
+	for _, c := range configDefaultsComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// Layer returns the config of the component layered on the config defaults, and the keys of the
+// config defaults overridden by the config with different values. The config is not changed.
+func (d *ConfigDefaults) Layer(component v1alpha1.MemberType, cfg *config.GenericConfig) (*config.GenericConfig, []string) {
+	layered := cfg.DeepCopy()
+	if layered == nil {
+		layered = config.New(map[string]interface{}{})
+	}
+	if d == nil {
+		return layered, nil
+	}
+	return layered, layered.MergeDefaults(d.configs[component])
+}
+
+// Apply returns the tidb cluster whose config of the component is layered on the config defaults,
+// tc is returned if there are no config defaults of the component. tc is not changed, so the
+// returned one is only used to render the config.
+func (d *ConfigDefaults) Apply(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) *v1alpha1.TidbCluster {
+	if d == nil || d.configs[component] == nil {
+		return tc
+	}
+	cfg, ok := componentConfig(tc, component)
+	if !ok {
+		return tc
+	}
+	layered, _ := d.Layer(component, cfg)
+	out := *tc
+	switch component {
+	case v1alpha1.PDMemberType:
+		spec := *tc.Spec.PD
+		spec.Config = &v1alpha1.PDConfigWraper{GenericConfig: layered}
+		out.Spec.PD = &spec
+	case v1alpha1.TiKVMemberType:
+		spec := *tc.Spec.TiKV
+		spec.Config = &v1alpha1.TiKVConfigWraper{GenericConfig: layered}
+		out.Spec.TiKV = &spec
+	case v1alpha1.TiDBMemberType:
+		spec := *tc.Spec.TiDB
+		spec.Config = &v1alpha1.TiDBConfigWraper{GenericConfig: layered}
+		out.Spec.TiDB = &spec
+	case v1alpha1.TiFlashMemberType:
+		spec := *tc.Spec.TiFlash
+		wrapper := &v1alpha1.TiFlashConfigWraper{Common: &v1alpha1.TiFlashCommonConfigWraper{GenericConfig: layered}}
+		if spec.Config != nil {
+			wrapper.Proxy = spec.Config.Proxy
+		}
+		spec.Config = wrapper
+		out.Spec.TiFlash = &spec
+	case v1alpha1.TiCDCMemberType:
+		spec := *tc.Spec.TiCDC
+		spec.Config = &v1alpha1.CDCConfigWraper{GenericConfig: layered}
+		out.Spec.TiCDC = &spec
+	case v1alpha1.TiProxyMemberType:
+		spec := *tc.Spec.TiProxy
+		spec.Config = &v1alpha1.TiProxyConfigWraper{GenericConfig: layered}
+		out.Spec.TiProxy = &spec
+	}
+	return &out
+}
+
+// Conflicts returns the items of the config defaults overridden by the config of the components
+// of the tidb cluster with different values
+func (d *ConfigDefaults) Conflicts(tc *v1alpha1.TidbCluster) []v1alpha1.ConfigConflict {
+	if d == nil {
+		return nil
+	}
+	var conflicts []v1alpha1.ConfigConflict
+	for _, component := range configDefaultsComponents {
+		defaults := d.configs[component]
+		if defaults == nil {
+			continue
+		}
+		cfg, ok := componentConfig(tc, component)
+		if !ok {
+			continue
+		}
+		_, keys := d.Layer(component, cfg)
+		for _, key := range keys {
+			conflicts = append(conflicts, v1alpha1.ConfigConflict{
+				Component: component,
+				Key:       key,
+				Default:   fmt.Sprint(defaults.Get(key).Interface()),
+				Value:     fmt.Sprint(cfg.Get(key).Interface()),
+			})
+		}
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		if conflicts[i].Component != conflicts[j].Component {
+			return conflicts[i].Component < conflicts[j].Component
+		}
+		return conflicts[i].Key < conflicts[j].Key
+	})
+	return conflicts
+}
+
+// componentConfig returns the config of the component in the spec of the tidb cluster, false is
+// returned if the component is not deployed
+func componentConfig(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) (*config.GenericConfig, bool) {
+	switch component {
+	case v1alpha1.PDMemberType:
+		if tc.Spec.PD == nil {
+			return nil, false
+		}
+		if tc.Spec.PD.Config != nil {
+			return tc.Spec.PD.Config.GenericConfig, true
+		}
+	case v1alpha1.TiKVMemberType:
+		if tc.Spec.TiKV == nil {
+			return nil, false
+		}
+		if tc.Spec.TiKV.Config != nil {
+			return tc.Spec.TiKV.Config.GenericConfig, true
+		}
+	case v1alpha1.TiDBMemberType:
+		if tc.Spec.TiDB == nil {
+			return nil, false
+		}
+		if tc.Spec.TiDB.Config != nil {
+			return tc.Spec.TiDB.Config.GenericConfig, true
+		}
+	case v1alpha1.TiFlashMemberType:
+		if tc.Spec.TiFlash == nil {
+			return nil, false
+		}
+		if tc.Spec.TiFlash.Config != nil && tc.Spec.TiFlash.Config.Common != nil {
+			return tc.Spec.TiFlash.Config.Common.GenericConfig, true
+		}
+	case v1alpha1.TiCDCMemberType:
+		if tc.Spec.TiCDC == nil {
+			return nil, false
+		}
+		if tc.Spec.TiCDC.Config != nil {
+			return tc.Spec.TiCDC.Config.GenericConfig, true
+		}
+	case v1alpha1.TiProxyMemberType:
+		if tc.Spec.TiProxy == nil {
+			return nil, false
+		}
+		if tc.Spec.TiProxy.Config != nil {
+			return tc.Spec.TiProxy.Config.GenericConfig, true
+		}
+	default:
+		return nil, false
+	}
+	return nil, true
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+const testConfigDefaults = `
+tidb: |
+  [security]
+  require-secure-transport = true
+  ssl-ca = "/var/lib/tidb-server-tls/ca.crt"
+tiflash: |
+  [security]
+  redact-info-log = true
+`
+
+func TestParseConfigDefaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	d, err := ParseConfigDefaults([]byte(testConfigDefaults))
+	g.Expect(err).Should(Succeed())
+	g.Expect(d.configs).Should(HaveLen(2))
+	g.Expect(d.configs[v1alpha1.TiDBMemberType].Get("security.require-secure-transport").Interface()).Should(Equal(true))
+
+	_, err = ParseConfigDefaults([]byte("pump: |\n  a = 1\n"))
+	g.Expect(err).Should(MatchError(ContainSubstring("pump are not supported")))
+	_, err = ParseConfigDefaults([]byte("tidb: |\n  a = \n"))
+	g.Expect(err).Should(HaveOccurred())
+
+	d, err = LoadConfigDefaults("")
+	g.Expect(err).Should(Succeed())
+	g.Expect(d).Should(BeNil())
+}
+
+func TestConfigDefaultsApply(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	d, err := ParseConfigDefaults([]byte(testConfigDefaults))
+	g.Expect(err).Should(Succeed())
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{Config: v1alpha1.NewTiDBConfig()}
+	tc.Spec.TiDB.Config.Set("security.require-secure-transport", false)
+	tc.Spec.TiDB.Config.Set("log.level", "warn")
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{}
+	tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{}
+
+	// spec.config takes precedence over the config defaults
+	applied := d.Apply(tc, v1alpha1.TiDBMemberType)
+	g.Expect(applied.Spec.TiDB.Config.Get("security.require-secure-transport").Interface()).Should(Equal(false))
+	g.Expect(applied.Spec.TiDB.Config.Get("security.ssl-ca").MustString()).Should(Equal("/var/lib/tidb-server-tls/ca.crt"))
+	g.Expect(applied.Spec.TiDB.Config.Get("log.level").MustString()).Should(Equal("warn"))
+	// tc is not changed
+	g.Expect(tc.Spec.TiDB.Config.Get("security.ssl-ca")).Should(BeNil())
+
+	// the config is created if it's not specified
+	applied = d.Apply(tc, v1alpha1.TiFlashMemberType)
+	g.Expect(applied.Spec.TiFlash.Config.Common.Get("security.redact-info-log").Interface()).Should(Equal(true))
+	g.Expect(tc.Spec.TiFlash.Config).Should(BeNil())
+
+	// no config defaults of the component
+	g.Expect(d.Apply(tc, v1alpha1.TiKVMemberType)).Should(BeIdenticalTo(tc))
+	var nilDefaults *ConfigDefaults
+	g.Expect(nilDefaults.Apply(tc, v1alpha1.TiDBMemberType)).Should(BeIdenticalTo(tc))
+
+	g.Expect(d.Conflicts(tc)).Should(Equal([]v1alpha1.ConfigConflict{
+		{Component: v1alpha1.TiDBMemberType, Key: "security.require-secure-transport", Default: "true", Value: "false"},
+	}))
+	g.Expect(nilDefaults.Conflicts(tc)).Should(BeEmpty())
+}
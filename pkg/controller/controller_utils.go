@@ -23,6 +23,7 @@ import (
 	"github.com/dustin/go-humanize"
 	fedv1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/scheme"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
@@ -58,6 +59,9 @@ var (
 	// backupScheduleControllerKind contains the schema.GroupVersionKind for backupschedule controller type.
 	backupScheduleControllerKind = v1alpha1.SchemeGroupVersion.WithKind("BackupSchedule")
 
+	// tidbClusterReplicationControllerKind contains the schema.GroupVersionKind for TidbClusterReplication controller type.
+	tidbClusterReplicationControllerKind = v1alpha1.SchemeGroupVersion.WithKind("TidbClusterReplication")
+
 	// tidbMonitorControllerKind contains the schema.GroupVersionKind for TidbMonitor controller type.
 	tidbMonitorControllerKind = v1alpha1.SchemeGroupVersion.WithKind("TidbMonitor")
 
@@ -201,6 +205,20 @@ func GetBackupScheduleOwnerRef(bs *v1alpha1.BackupSchedule) metav1.OwnerReferenc
 	}
 }
 
+// GetTidbClusterReplicationOwnerRef returns TidbClusterReplication's OwnerReference
+func GetTidbClusterReplicationOwnerRef(tcr *v1alpha1.TidbClusterReplication) metav1.OwnerReference {
+	controller := true
+	blockOwnerDeletion := true
+	return metav1.OwnerReference{
+		APIVersion:         tidbClusterReplicationControllerKind.GroupVersion().String(),
+		Kind:               tidbClusterReplicationControllerKind.Kind,
+		Name:               tcr.GetName(),
+		UID:                tcr.GetUID(),
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
+
 // GetFedVolumeBackupScheduleOwnerRef returns FedVolumeBackupSchedule's OwnerReference
 func GetFedVolumeBackupScheduleOwnerRef(vbks *fedv1alpha1.VolumeBackupSchedule) metav1.OwnerReference {
 	controller := true
@@ -343,6 +361,16 @@ func TiKVPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tikv-peer", clusterName)
 }
 
+// TiKVWitnessMemberName returns tikv witness member name
+func TiKVWitnessMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-tikv-witness", clusterName)
+}
+
+// TiKVWitnessPeerMemberName returns tikv witness peer service name
+func TiKVWitnessPeerMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-tikv-witness-peer", clusterName)
+}
+
 // TiFlashMemberName returns tiflash member name
 func TiFlashMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tiflash", clusterName)
@@ -383,6 +411,11 @@ func TiDBPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tidb-peer", clusterName)
 }
 
+// TiDBServiceName returns the name of the named client service of tidb
+func TiDBServiceName(clusterName, name string) string {
+	return fmt.Sprintf("%s-tidb-%s", clusterName, name)
+}
+
 // PumpMemberName returns pump member name
 func PumpMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-pump", clusterName)
@@ -393,6 +426,11 @@ func TiDBInitializerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tidb-initializer", clusterName)
 }
 
+// TiDBInitializerAppliedPasswordSecretName returns the name of the secret keeping the passwords applied by tidb initializer
+func TiDBInitializerAppliedPasswordSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-applied-password", TiDBInitializerMemberName(clusterName))
+}
+
 // For backward compatibility, pump peer member name do not has -peer suffix
 // PumpPeerMemberName returns pump peer service name
 func PumpPeerMemberName(clusterName string) string {
@@ -424,6 +462,11 @@ func DMWorkerPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-dm-worker-peer", clusterName)
 }
 
+// DMDatabaseTLSConfigMapName returns the name of the configmap of the dm source and target security configs
+func DMDatabaseTLSConfigMapName(clusterName string) string {
+	return fmt.Sprintf("%s-dm-database-tls", clusterName)
+}
+
 // TiDBInitSecret returns tidb init secret name
 func TiDBInitSecret(clusterName string) string {
 	return fmt.Sprintf("%s-init", clusterName)
@@ -451,10 +494,64 @@ func FormatClusterDomain(clusterDomain string) string {
 	return "." + clusterDomain
 }
 
+var envReferenceRegexp = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+// FormatAdditionalArgs converts the `$(VAR_NAME)` references in the additional arguments
+// of a component to the shell form `${VAR_NAME}`, so they are expanded by the start script.
+func FormatAdditionalArgs(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	formatted := make([]string, 0, len(args))
+	for _, arg := range args {
+		formatted = append(formatted, envReferenceRegexp.ReplaceAllString(arg, "$${$1}"))
+	}
+	return formatted
+}
+
+// AdditionalArgsHaveFlag returns whether the additional arguments of a component set the flag,
+// which may be given as -name, --name, -name=value or --name=value and may be quoted.
+func AdditionalArgsHaveFlag(args []string, name string) bool {
+	for _, arg := range args {
+		arg = strings.Trim(arg, `"'`)
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		flag := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if i := strings.IndexByte(flag, '='); i >= 0 {
+			flag = flag[:i]
+		}
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
+
 func PDPeerFullyDomain(name, ns, clusterDomain string) string {
 	return fmt.Sprintf("%s.%s.svc%s", PDPeerMemberName(name), ns, FormatClusterDomain(clusterDomain))
 }
 
+// PDPeerAddress returns the host of the PD peer service of the cluster referenced by tc, which is resolved
+// by the resolver of tc if it's deployed across Kubernetes clusters.
+func PDPeerAddress(tc *v1alpha1.TidbCluster) string {
+	ref := tc.Spec.Cluster
+	if tc.Spec.AcrossK8sResolver == nil {
+		return PDPeerFullyDomain(ref.Name, ref.Namespace, ref.ClusterDomain)
+	}
+	return pdapi.NewResolver(tc.Spec.AcrossK8sResolver).ServiceHost(pdapi.Namespace(ref.Namespace), PDPeerMemberName(ref.Name), ref.ClusterDomain)
+}
+
+// PodAdvertiseHost returns the host of the pod advertised to the other members of tc, which is
+// `<pod>.<peerService>.<namespace>.svc[.<clusterDomain>]` by default, or the one resolved by the
+// resolver of tc if it's deployed across Kubernetes clusters.
+func PodAdvertiseHost(tc *v1alpha1.TidbCluster, pod, peerService string) string {
+	if tc.Spec.AcrossK8sResolver != nil {
+		return pdapi.NewResolver(tc.Spec.AcrossK8sResolver).ServiceHost(pdapi.Namespace(tc.Namespace), fmt.Sprintf("%s.%s", pod, peerService), tc.Spec.ClusterDomain)
+	}
+	return fmt.Sprintf("%s.%s.%s.svc%s", pod, peerService, tc.Namespace, FormatClusterDomain(tc.Spec.ClusterDomain))
+}
+
 // AnnAdditionalProm adds additional prometheus scarping configuration annotation for the pod
 // which has multiple metrics endpoint
 // we assumes that the metrics path is as same as the previous metrics path
@@ -297,6 +297,32 @@ func TestAnnProm(t *testing.T) {
 	g.Expect(ann["prometheus.io/port"]).To(Equal("9090"))
 }
 
+func TestFormatAdditionalArgs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(FormatAdditionalArgs(nil)).To(BeNil())
+	g.Expect(FormatAdditionalArgs([]string{
+		"--metrics-flush-interval=10s",
+		"--advertise-host=$(POD_IP)",
+		"--labels=zone=$(ZONE),host=$(NODE_NAME)",
+	})).To(Equal([]string{
+		"--metrics-flush-interval=10s",
+		"--advertise-host=${POD_IP}",
+		"--labels=zone=${ZONE},host=${NODE_NAME}",
+	}))
+}
+
+func TestAdditionalArgsHaveFlag(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(AdditionalArgsHaveFlag(nil, "log-file")).To(BeFalse())
+	g.Expect(AdditionalArgsHaveFlag([]string{"-log-file=/var/log/pump.log"}, "log-file")).To(BeTrue())
+	g.Expect(AdditionalArgsHaveFlag([]string{"--log-file", "/var/log/pump.log"}, "log-file")).To(BeTrue())
+	g.Expect(AdditionalArgsHaveFlag([]string{`--log-file="/var/log/pump.log"`}, "log-file")).To(BeTrue())
+	g.Expect(AdditionalArgsHaveFlag([]string{`'--log-file=/var/log/pump.log'`}, "log-file")).To(BeTrue())
+	g.Expect(AdditionalArgsHaveFlag([]string{"--log-file-max-days=3", "log-file"}, "log-file")).To(BeFalse())
+}
+
 func TestMemberConfigMapName(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -535,6 +561,128 @@ func TestTrimName(t *testing.T) {
 	g.Expect(PDMSTrimName(name)).To(Equal("tso"))
 }
 
+func TestPodAdvertiseHost(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		name          string
+		resolver      *v1alpha1.AcrossK8sResolver
+		clusterDomain string
+		expect        string
+	}{
+		{
+			name:   "default",
+			expect: "demo-tikv-0.demo-tikv-peer.default.svc",
+		},
+		{
+			name:          "default with cluster domain",
+			clusterDomain: "cluster-1.com",
+			expect:        "demo-tikv-0.demo-tikv-peer.default.svc.cluster-1.com",
+		},
+		{
+			name:          "cluster domain",
+			resolver:      &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterDomain},
+			clusterDomain: "cluster-1.com",
+			expect:        "demo-tikv-0.demo-tikv-peer.default.svc.cluster-1.com",
+		},
+		{
+			name:          "external dns",
+			resolver:      &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverExternalDNS, Domain: "db.example.com"},
+			clusterDomain: "cluster-1.com",
+			expect:        "demo-tikv-0.demo-tikv-peer.default.db.example.com",
+		},
+		{
+			name:          "cluster set",
+			resolver:      &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterSet},
+			clusterDomain: "cluster-1.com",
+			expect:        "demo-tikv-0.demo-tikv-peer.default.svc.clusterset.local",
+		},
+		{
+			name:          "cluster mesh",
+			resolver:      &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterMesh},
+			clusterDomain: "cluster-1.com",
+			expect:        "demo-tikv-0.demo-tikv-peer.default",
+		},
+		{
+			name: "static",
+			resolver: &v1alpha1.AcrossK8sResolver{
+				Type:      v1alpha1.AcrossK8sResolverStatic,
+				Endpoints: map[string]string{"demo-tikv-0.demo-tikv-peer.default.svc.cluster-1.com": "10.0.0.1"},
+			},
+			clusterDomain: "cluster-1.com",
+			expect:        "10.0.0.1",
+		},
+		{
+			name: "static not mapped",
+			resolver: &v1alpha1.AcrossK8sResolver{
+				Type:      v1alpha1.AcrossK8sResolverStatic,
+				Endpoints: map[string]string{"demo-tikv-1.demo-tikv-peer.default.svc.cluster-1.com": "10.0.0.1"},
+			},
+			clusterDomain: "cluster-1.com",
+			expect:        "demo-tikv-0.demo-tikv-peer.default.svc.cluster-1.com",
+		},
+	}
+
+	for _, c := range cases {
+		t.Log(c.name)
+		tc := newTidbCluster()
+		tc.Spec.AcrossK8sResolver = c.resolver
+		tc.Spec.ClusterDomain = c.clusterDomain
+		g.Expect(PodAdvertiseHost(tc, "demo-tikv-0", TiKVPeerMemberName(tc.Name))).To(Equal(c.expect))
+	}
+}
+
+func TestPDPeerAddress(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		name     string
+		resolver *v1alpha1.AcrossK8sResolver
+		expect   string
+	}{
+		{
+			name:   "default",
+			expect: "basic-pd-peer.ns.svc.cluster-1.com",
+		},
+		{
+			name:     "cluster domain",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterDomain},
+			expect:   "basic-pd-peer.ns.svc.cluster-1.com",
+		},
+		{
+			name:     "external dns",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverExternalDNS, Domain: "db.example.com"},
+			expect:   "basic-pd-peer.ns.db.example.com",
+		},
+		{
+			name:     "cluster set",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterSet, Domain: "mesh.local"},
+			expect:   "basic-pd-peer.ns.svc.mesh.local",
+		},
+		{
+			name:     "cluster mesh",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterMesh},
+			expect:   "basic-pd-peer.ns",
+		},
+		{
+			name: "static",
+			resolver: &v1alpha1.AcrossK8sResolver{
+				Type:      v1alpha1.AcrossK8sResolverStatic,
+				Endpoints: map[string]string{"basic-pd-peer.ns.svc.cluster-1.com": "pd.example.com"},
+			},
+			expect: "pd.example.com",
+		},
+	}
+
+	for _, c := range cases {
+		t.Log(c.name)
+		tc := newTidbCluster()
+		tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Name: "basic", Namespace: "ns", ClusterDomain: "cluster-1.com"}
+		tc.Spec.AcrossK8sResolver = c.resolver
+		g.Expect(PDPeerAddress(tc)).To(Equal(c.expect))
+	}
+}
+
 func collectEvents(source <-chan string) []string {
 	done := false
 	events := make([]string, 0)
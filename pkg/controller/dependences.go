@@ -17,6 +17,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -33,6 +35,7 @@ import (
 	extensionslister "k8s.io/client-go/listers/extensions/v1beta1"
 	networklister "k8s.io/client-go/listers/networking/v1"
 	storagelister "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -81,6 +84,18 @@ type CLIConfig struct {
 	WaitDuration          time.Duration
 	// ResyncDuration is the resync time of informer
 	ResyncDuration time.Duration
+	// ResyncDurations overrides the resync time of the informers of the CRD kinds, e.g. TidbCluster=1m,TidbMonitor=10m
+	ResyncDurations string
+	// DegradedClusterResyncDuration is the resync time of the degraded clusters,
+	// which are also synced before the healthy ones
+	DegradedClusterResyncDuration time.Duration
+	// StoreStateWatchInterval is the interval to poll the store states of the tidb clusters from PD,
+	// the tidb cluster is synced at once when a store becomes Down. 0 disables the polling, which is the default
+	// as the PD of every tidb cluster is requested each interval
+	StoreStateWatchInterval time.Duration
+	// ShutdownGracePeriod is the max time to wait for the in-flight syncs to finish
+	// when the operator is shutting down. 0 disables the draining and the requeue hints
+	ShutdownGracePeriod time.Duration
 	// DetectNodeFailure enables detection of node failures for stateful failure pods for recovery
 	DetectNodeFailure bool
 	// PodHardRecoveryPeriod is the hard recovery period for a failure pod
@@ -90,6 +105,8 @@ type CLIConfig struct {
 	TestMode               bool
 	TiDBBackupManagerImage string
 	TiDBDiscoveryImage     string
+	// DebugImage is the default image of the ephemeral debug containers attached to the pods
+	DebugImage string
 	// Selector is used to filter CR labels to decide
 	// what resources should be watched and synced by controller
 	Selector string
@@ -97,31 +114,102 @@ type CLIConfig struct {
 	// KubeClientQPS indicates the maximum QPS to the kubenetes API server from client.
 	KubeClientQPS   float64
 	KubeClientBurst int
+	// KubeClientQPSPerNamespace indicates the maximum QPS to the kubenetes API server from client
+	// for the resources in each namespace, 0 means unlimited.
+	KubeClientQPSPerNamespace   float64
+	KubeClientBurstPerNamespace int
+	// PDCircuitBreakerThreshold is the number of consecutive failed requests to a PD cluster after which
+	// the requests to it are rejected for PDCircuitBreakerCoolOff, the circuit breaker is disabled if it's 0
+	PDCircuitBreakerThreshold int
+	PDCircuitBreakerCoolOff   time.Duration
+
+	// TracingEndpoint is the OTLP gRPC endpoint the spans of the reconciles are exported to,
+	// tracing is disabled if it's empty
+	TracingEndpoint string
+	// TracingInsecure disables the TLS of the connection to the tracing endpoint
+	TracingInsecure bool
+	// TracingSampleRatio is the ratio of the reconciles to be traced
+	TracingSampleRatio float64
+
+	// BRJobConcurrency is the maximum number of the backup and restore jobs running concurrently
+	// in the operator, 0 means unlimited
+	BRJobConcurrency int
+	// BRJobConcurrencyPerNamespace is the maximum number of the backup and restore jobs running
+	// concurrently in each namespace, 0 means unlimited
+	BRJobConcurrencyPerNamespace int
+
+	// BRJobTTLAfterFinished is the time the finished backup and restore jobs are kept after the backups
+	// and restores finish, 0 means the jobs are kept until the backups and restores are deleted
+	BRJobTTLAfterFinished time.Duration
+	// BRSuccessfulJobsHistoryLimit is the number of the successful backup and restore jobs kept in each
+	// namespace, negative means unlimited
+	BRSuccessfulJobsHistoryLimit int
+	// BRFailedJobsHistoryLimit is the number of the failed backup and restore jobs kept in each namespace,
+	// negative means unlimited
+	BRFailedJobsHistoryLimit int
+
+	// ImagePolicyFile is the YAML file of the image policy applied to all the pods created by the operator,
+	// e.g. the registry mirrors and the digests of the images
+	ImagePolicyFile string
+	// OrphanGCPolicy is the policy of the orphan garbage collector for the statefulsets, services and
+	// configmaps managed by the operator whose owning CRs no longer exist: None, Report or Delete
+	OrphanGCPolicy string
+	// OrphanGCPVCPolicy is the policy of the orphan garbage collector for the PVCs
+	OrphanGCPVCPolicy string
+	// OrphanGCInterval is the interval between the collections of the orphan garbage collector
+	OrphanGCInterval time.Duration
+	// OrphanGCGracePeriod is the minimum age of the resources collected by the orphan garbage collector
+	OrphanGCGracePeriod time.Duration
+	// ConfigDefaultsFile is the YAML file of the config defaults of the components applied to all the
+	// clusters managed by the operator, the config of the components in the CRs take precedence
+	ConfigDefaultsFile string
+	// APIServerAddress is the address the API server exposing the cluster summaries and the safe
+	// actions listens on, the API server is disabled if it's empty
+	APIServerAddress string
+	// APIServerTLSCertFile and APIServerTLSKeyFile are the certificate and key files of the API server,
+	// they can be empty only if the API server listens on a loopback address
+	APIServerTLSCertFile string
+	APIServerTLSKeyFile  string
+	// APIServerAudiences are the comma separated audiences the tokens reviewed by the API server must be
+	// issued for, so the tokens issued for the other services can't be replayed to the API server
+	APIServerAudiences string
 }
 
 // DefaultCLIConfig returns the default command line configuration
 func DefaultCLIConfig() *CLIConfig {
 	return &CLIConfig{
-		Workers:                5,
-		ClusterScoped:          true,
-		AutoFailover:           true,
-		PDFailoverPeriod:       5 * time.Minute,
-		TiKVFailoverPeriod:     5 * time.Minute,
-		TiDBFailoverPeriod:     5 * time.Minute,
-		TiFlashFailoverPeriod:  5 * time.Minute,
-		MasterFailoverPeriod:   5 * time.Minute,
-		WorkerFailoverPeriod:   5 * time.Minute,
-		LeaseDuration:          15 * time.Second,
-		RenewDeadline:          10 * time.Second,
-		RetryPeriod:            2 * time.Second,
-		ResourceLock:           resourcelock.LeasesResourceLock, // k8s uses leases by default from v1.20
-		WaitDuration:           5 * time.Second,
-		ResyncDuration:         30 * time.Second,
-		PodHardRecoveryPeriod:  24 * time.Hour,
-		DetectNodeFailure:      false,
-		TiDBBackupManagerImage: "pingcap/tidb-backup-manager:latest",
-		TiDBDiscoveryImage:     "pingcap/tidb-operator:latest",
-		Selector:               "",
+		Workers:                       5,
+		ClusterScoped:                 true,
+		AutoFailover:                  true,
+		PDFailoverPeriod:              5 * time.Minute,
+		TiKVFailoverPeriod:            5 * time.Minute,
+		TiDBFailoverPeriod:            5 * time.Minute,
+		TiFlashFailoverPeriod:         5 * time.Minute,
+		MasterFailoverPeriod:          5 * time.Minute,
+		WorkerFailoverPeriod:          5 * time.Minute,
+		LeaseDuration:                 15 * time.Second,
+		RenewDeadline:                 10 * time.Second,
+		RetryPeriod:                   2 * time.Second,
+		ResourceLock:                  resourcelock.LeasesResourceLock, // k8s uses leases by default from v1.20
+		WaitDuration:                  5 * time.Second,
+		ResyncDuration:                30 * time.Second,
+		DegradedClusterResyncDuration: 10 * time.Second,
+		ShutdownGracePeriod:           20 * time.Second,
+		PodHardRecoveryPeriod:         24 * time.Hour,
+		DetectNodeFailure:             false,
+		TiDBBackupManagerImage:        "pingcap/tidb-backup-manager:latest",
+		TiDBDiscoveryImage:            "pingcap/tidb-operator:latest",
+		DebugImage:                    "pingcap/tidb-debug:latest",
+		Selector:                      "",
+		TracingSampleRatio:            1,
+		BRSuccessfulJobsHistoryLimit:  -1,
+		BRFailedJobsHistoryLimit:      -1,
+		OrphanGCPolicy:                "None",
+		OrphanGCPVCPolicy:             "None",
+		OrphanGCInterval:              time.Hour,
+		OrphanGCGracePeriod:           time.Hour,
+		PDCircuitBreakerCoolOff:       pdapi.DefaultCircuitBreakerCoolOff,
+		APIServerAudiences:            "tidb-operator-api-server",
 	}
 }
 
@@ -144,10 +232,15 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.DurationVar(&c.PodHardRecoveryPeriod, "pod-hard-recovery-period", c.PodHardRecoveryPeriod, "Hard recovery period for a failure pod default(24h)")
 	flag.BoolVar(&c.DetectNodeFailure, "detect-node-failure", c.DetectNodeFailure, "Automatically detect node failures")
 	flag.DurationVar(&c.ResyncDuration, "resync-duration", c.ResyncDuration, "Resync time of informer")
+	flag.StringVar(&c.ResyncDurations, "resync-durations", c.ResyncDurations, "Resync time of the informers of the CRD kinds overriding resync-duration, e.g. TidbCluster=1m,TidbMonitor=10m. The resync time of a tidbcluster or dmcluster can be overridden by the annotation tidb.pingcap.com/resync-duration")
+	flag.DurationVar(&c.DegradedClusterResyncDuration, "degraded-cluster-resync-duration", c.DegradedClusterResyncDuration, "Resync time of the degraded clusters, e.g. clusters with failed members or in upgrading, which are synced before the healthy ones")
+	flag.DurationVar(&c.StoreStateWatchInterval, "store-state-watch-interval", c.StoreStateWatchInterval, "Interval to poll the store states of the tidb clusters from PD, the tidb cluster is synced at once when a store becomes Down, 0 disables the polling. It requests the PD of every tidb cluster each interval, so keep it long with many clusters, e.g. 30s")
+	flag.DurationVar(&c.ShutdownGracePeriod, "shutdown-grace-period", c.ShutdownGracePeriod, "The max time to wait for the in-flight syncs to finish when tidb-operator is shutting down, it should be less than the termination grace period of the pod. 0 disables the draining and deletes the persisted requeue hints")
 	flag.BoolVar(&c.TestMode, "test-mode", false, "whether tidb-operator run in test mode")
 	flag.StringVar(&c.TiDBBackupManagerImage, "tidb-backup-manager-image", c.TiDBBackupManagerImage, "The image of backup manager tool")
 	// TODO: actually we just want to use the same image with tidb-controller-manager, but DownwardAPI cannot get image ID, see if there is any better solution
 	flag.StringVar(&c.TiDBDiscoveryImage, "tidb-discovery-image", c.TiDBDiscoveryImage, "The image of the tidb discovery service")
+	flag.StringVar(&c.DebugImage, "debug-image", c.DebugImage, "The default image of the ephemeral debug containers attached to the pods by the annotation tidb.pingcap.com/debug-container")
 	flag.StringVar(&c.Selector, "selector", c.Selector, "Selector (label query) to filter on, supports '=', '==', and '!='")
 
 	// see https://pkg.go.dev/k8s.io/client-go/tools/leaderelection#LeaderElectionConfig for the config
@@ -157,6 +250,79 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.StringVar(&c.ResourceLock, "leader-resource-lock", c.ResourceLock, "The type of resource object that is used for locking during leader election")
 	flag.Float64Var(&c.KubeClientQPS, "kube-client-qps", c.KubeClientQPS, "The maximum QPS to the kubenetes API server from client")
 	flag.IntVar(&c.KubeClientBurst, "kube-client-burst", c.KubeClientBurst, "The maximum burst for throttle to the kubenetes API server from client")
+	flag.Float64Var(&c.KubeClientQPSPerNamespace, "kube-client-qps-per-namespace", c.KubeClientQPSPerNamespace, "The maximum QPS to the kubenetes API server from client for the resources in each namespace, so a cluster syncing in a hot loop can't starve the others. 0 means unlimited")
+	flag.IntVar(&c.KubeClientBurstPerNamespace, "kube-client-burst-per-namespace", c.KubeClientBurstPerNamespace, "The maximum burst for throttle to the kubenetes API server from client for the resources in each namespace, defaults to kube-client-qps-per-namespace")
+	flag.IntVar(&c.PDCircuitBreakerThreshold, "pd-circuit-breaker-threshold", c.PDCircuitBreakerThreshold, "The number of consecutive failed requests to a PD cluster after which the requests to it are rejected for pd-circuit-breaker-cool-off, so a struggling PD is not overwhelmed by the operator. Only the transport errors and the 502, 503 and 504 responses count as failures. 0 (the default) disables the circuit breaker")
+	flag.DurationVar(&c.PDCircuitBreakerCoolOff, "pd-circuit-breaker-cool-off", c.PDCircuitBreakerCoolOff, "The duration the requests to a PD cluster are rejected for after the circuit breaker opens")
+	flag.StringVar(&c.TracingEndpoint, "tracing-endpoint", c.TracingEndpoint, "The OTLP gRPC endpoint, e.g. otel-collector:4317, the traces of the reconciles are exported to. Tracing is disabled if it's empty")
+	flag.BoolVar(&c.TracingInsecure, "tracing-insecure", c.TracingInsecure, "Whether to disable the TLS of the connection to the tracing endpoint")
+	flag.Float64Var(&c.TracingSampleRatio, "tracing-sample-ratio", c.TracingSampleRatio, "The ratio of the reconciles to be traced, in the range [0, 1]")
+	flag.IntVar(&c.BRJobConcurrency, "br-job-concurrency", c.BRJobConcurrency, "The maximum number of the backup and restore jobs running concurrently, the backups and restores exceeding the limit are kept pending. 0 means unlimited")
+	flag.IntVar(&c.BRJobConcurrencyPerNamespace, "br-job-concurrency-per-namespace", c.BRJobConcurrencyPerNamespace, "The maximum number of the backup and restore jobs running concurrently in each namespace, the backups and restores exceeding the limit are kept pending. 0 means unlimited")
+	flag.StringVar(&c.ImagePolicyFile, "image-policy-file", c.ImagePolicyFile, "The YAML file of the image policy, e.g. the registry mirrors, the digests of the images and the blocked tags, applied to all the pods created by tidb-operator")
+	flag.DurationVar(&c.BRJobTTLAfterFinished, "br-job-ttl-after-finished", c.BRJobTTLAfterFinished, "The time the finished backup and restore jobs are kept after the backups and restores finish, the summaries of the deleted jobs are kept in the status. 0 means the jobs are kept until the backups and restores are deleted")
+	flag.IntVar(&c.BRSuccessfulJobsHistoryLimit, "br-successful-jobs-history-limit", c.BRSuccessfulJobsHistoryLimit, "The number of the successful jobs of the finished backups and restores kept in each namespace, negative means unlimited")
+	flag.IntVar(&c.BRFailedJobsHistoryLimit, "br-failed-jobs-history-limit", c.BRFailedJobsHistoryLimit, "The number of the failed jobs of the finished backups and restores kept in each namespace, negative means unlimited")
+	flag.StringVar(&c.OrphanGCPolicy, "orphan-gc-policy", c.OrphanGCPolicy, "The policy for the statefulsets, services and configmaps managed by tidb-operator whose owning CRs no longer exist: None, Report or Delete")
+	flag.StringVar(&c.OrphanGCPVCPolicy, "orphan-gc-pvc-policy", c.OrphanGCPVCPolicy, "The policy for the PVCs managed by tidb-operator whose owning CRs no longer exist: None, Report or Delete")
+	flag.DurationVar(&c.OrphanGCInterval, "orphan-gc-interval", c.OrphanGCInterval, "The interval between the collections of the orphan resources")
+	flag.DurationVar(&c.OrphanGCGracePeriod, "orphan-gc-grace-period", c.OrphanGCGracePeriod, "The minimum age of the orphan resources to be reported or deleted")
+	flag.StringVar(&c.ConfigDefaultsFile, "config-defaults-file", c.ConfigDefaultsFile, "The YAML file mapping the components to their config defaults in TOML format, applied to all the clusters managed by tidb-operator. The config in the CRs takes precedence and the overridden items are reported in the status of the clusters")
+	flag.StringVar(&c.APIServerAddress, "api-server-address", c.APIServerAddress, "The address, e.g. :6443, the API server exposing the cluster summaries and the safe actions listens on. The requests are authenticated by TokenReview and authorized by SubjectAccessReview. The API server is disabled if it's empty")
+	flag.StringVar(&c.APIServerTLSCertFile, "api-server-tls-cert-file", c.APIServerTLSCertFile, "The certificate file of the API server")
+	flag.StringVar(&c.APIServerTLSKeyFile, "api-server-tls-key-file", c.APIServerTLSKeyFile, "The private key file of the API server")
+	flag.StringVar(&c.APIServerAudiences, "api-server-audiences", c.APIServerAudiences, "The comma separated audiences the bearer tokens sent to the API server must be issued for, e.g. by the projected service account tokens")
+}
+
+// ValidateLeaderElection validates the durations of the leader election, the lease must be longer than the
+// renew deadline, which must be longer than the retry period with its jitter
+func (c *CLIConfig) ValidateLeaderElection() error {
+	if c.LeaseDuration <= c.RenewDeadline {
+		return fmt.Errorf("leader-lease-duration %v must be greater than leader-renew-deadline %v", c.LeaseDuration, c.RenewDeadline)
+	}
+	if c.RetryPeriod <= 0 {
+		return fmt.Errorf("leader-retry-period %v must be positive", c.RetryPeriod)
+	}
+	if c.RenewDeadline <= time.Duration(leaderelection.JitterFactor*float64(c.RetryPeriod)) {
+		return fmt.Errorf("leader-renew-deadline %v must be greater than %v times leader-retry-period %v", c.RenewDeadline, leaderelection.JitterFactor, c.RetryPeriod)
+	}
+	return nil
+}
+
+// ValidateAPIServer validates the config of the API server. The bearer tokens of the users are sent to
+// the API server, so it must serve TLS unless it listens on a loopback address only.
+func (c *CLIConfig) ValidateAPIServer() error {
+	if c.APIServerAddress == "" {
+		return nil
+	}
+	if (c.APIServerTLSCertFile == "") != (c.APIServerTLSKeyFile == "") {
+		return fmt.Errorf("api-server-tls-cert-file and api-server-tls-key-file must be set together")
+	}
+	if len(c.GetAPIServerAudiences()) == 0 {
+		return fmt.Errorf("api-server-audiences must be set")
+	}
+	if c.APIServerTLSCertFile != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(c.APIServerAddress)
+	if err != nil {
+		return fmt.Errorf("invalid api-server-address %q: %v", c.APIServerAddress, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("api-server-tls-cert-file and api-server-tls-key-file are required unless api-server-address %q is a loopback address", c.APIServerAddress)
+	}
+	return nil
+}
+
+// GetAPIServerAudiences returns the audiences the tokens reviewed by the API server must be issued for
+func (c *CLIConfig) GetAPIServerAudiences() []string {
+	var audiences []string
+	for _, audience := range strings.Split(c.APIServerAudiences, ",") {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			audiences = append(audiences, audience)
+		}
+	}
+	return audiences
 }
 
 // HasNodePermission returns whether the user has permission for node operations.
@@ -214,32 +380,49 @@ type Dependencies struct {
 	KubeInformerFactory            kubeinformers.SharedInformerFactory
 	LabelFilterKubeInformerFactory kubeinformers.SharedInformerFactory
 	Recorder                       record.EventRecorder
+	// SyncTracker tracks the in-flight syncs for graceful shutdown
+	SyncTracker *SyncTracker
+	// Fencing holds the fencing token of the leadership of the operator
+	Fencing *Fencing
+	// Requeuer requeues the objects to the queues of their controllers
+	Requeuer *Requeuer
+	// BRJobLimiter limits the backup and restore jobs running concurrently
+	BRJobLimiter *BRJobLimiter
+	// BRNotifier posts the events of the backups and restores to their webhooks
+	BRNotifier *BRNotifier
+	// Capabilities are the optional features supported by the Kubernetes cluster
+	Capabilities *Capabilities
+	// ConfigDefaults are the config defaults of the components applied to all the clusters
+	ConfigDefaults *ConfigDefaults
 
 	// Listers
-	ServiceLister          corelisterv1.ServiceLister
-	EndpointLister         corelisterv1.EndpointsLister
-	PVCLister              corelisterv1.PersistentVolumeClaimLister
-	PVLister               corelisterv1.PersistentVolumeLister
-	PodLister              corelisterv1.PodLister
-	NodeLister             corelisterv1.NodeLister
-	SecretLister           corelisterv1.SecretLister
-	ConfigMapLister        corelisterv1.ConfigMapLister
-	StatefulSetLister      appslisters.StatefulSetLister
-	DeploymentLister       appslisters.DeploymentLister
-	JobLister              batchlisters.JobLister
-	IngressLister          networklister.IngressLister
-	IngressV1Beta1Lister   extensionslister.IngressLister // TODO: in order to be compatibility with kubernetes which less than v1.19, remove it if v1.19- is not supported
-	StorageClassLister     storagelister.StorageClassLister
-	TiDBClusterLister      listers.TidbClusterLister
-	DMClusterLister        listers.DMClusterLister
-	BackupLister           listers.BackupLister
-	CompactBackupLister    listers.CompactBackupLister
-	RestoreLister          listers.RestoreLister
-	BackupScheduleLister   listers.BackupScheduleLister
-	TiDBInitializerLister  listers.TidbInitializerLister
-	TiDBMonitorLister      listers.TidbMonitorLister
-	TiDBNGMonitoringLister listers.TidbNGMonitoringLister
-	TiDBDashboardLister    listers.TidbDashboardLister
+	ServiceLister                corelisterv1.ServiceLister
+	EndpointLister               corelisterv1.EndpointsLister
+	PVCLister                    corelisterv1.PersistentVolumeClaimLister
+	PVLister                     corelisterv1.PersistentVolumeLister
+	PodLister                    corelisterv1.PodLister
+	NodeLister                   corelisterv1.NodeLister
+	SecretLister                 corelisterv1.SecretLister
+	ConfigMapLister              corelisterv1.ConfigMapLister
+	StatefulSetLister            appslisters.StatefulSetLister
+	DeploymentLister             appslisters.DeploymentLister
+	JobLister                    batchlisters.JobLister
+	IngressLister                networklister.IngressLister
+	IngressV1Beta1Lister         extensionslister.IngressLister // TODO: in order to be compatibility with kubernetes which less than v1.19, remove it if v1.19- is not supported
+	StorageClassLister           storagelister.StorageClassLister
+	TiDBClusterLister            listers.TidbClusterLister
+	DMClusterLister              listers.DMClusterLister
+	BackupLister                 listers.BackupLister
+	CompactBackupLister          listers.CompactBackupLister
+	RestoreLister                listers.RestoreLister
+	BackupScheduleLister         listers.BackupScheduleLister
+	TiDBInitializerLister        listers.TidbInitializerLister
+	TiDBMonitorLister            listers.TidbMonitorLister
+	TiDBNGMonitoringLister       listers.TidbNGMonitoringLister
+	TiDBDashboardLister          listers.TidbDashboardLister
+	TiDBClusterReplicationLister listers.TidbClusterReplicationLister
+	PumpMigrationLister          listers.PumpMigrationLister
+	TiDBAccountLister            listers.TidbAccountLister
 
 	// Controls
 	Controls
@@ -254,7 +437,8 @@ func newRealControls(
 	genericCli client.Client,
 	informerFactory informers.SharedInformerFactory,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
-	recorder record.EventRecorder) Controls {
+	recorder record.EventRecorder,
+	fencing *Fencing) Controls {
 	// Shared variables to construct `Dependencies` and some of its fields
 	var (
 		secretLister      = kubeInformerFactory.Core().V1().Secrets().Lister()
@@ -279,13 +463,13 @@ func newRealControls(
 	return Controls{
 		JobControl:         NewRealJobControl(kubeClientset, recorder),
 		ConfigMapControl:   NewRealConfigMapControl(kubeClientset, recorder),
-		StatefulSetControl: NewRealStatefuSetControl(kubeClientset, statefulSetLister, recorder),
+		StatefulSetControl: NewFencedStatefulSetControl(kubeClientset, statefulSetLister, recorder, fencing),
 		ServiceControl:     NewRealServiceControl(kubeClientset, serviceLister, recorder),
 		PVControl:          NewRealPVControl(kubeClientset, pvcLister, pvLister, recorder),
-		PVCControl:         NewRealPVCControl(kubeClientset, recorder, pvcLister),
+		PVCControl:         NewFencedPVCControl(kubeClientset, recorder, pvcLister, fencing),
 		GeneralPVCControl:  NewRealGeneralPVCControl(kubeClientset, recorder),
 		GenericControl:     genericCtrl,
-		PodControl:         NewRealPodControl(kubeClientset, pdapi.NewDefaultPDControl(secretLister), podLister, recorder),
+		PodControl:         NewFencedPodControl(kubeClientset, pdapi.NewDefaultPDControl(secretLister), podLister, recorder, fencing),
 		TypedControl:       NewTypedControl(genericCtrl),
 		PDControl:          pdControl,
 		TiKVControl:        tikvControl,
@@ -360,32 +544,41 @@ func newDependencies(
 		KubeInformerFactory:            kubeInformerFactory,
 		LabelFilterKubeInformerFactory: labelFilterKubeInformerFactory,
 		Recorder:                       recorder,
+		SyncTracker:                    NewSyncTracker(),
+		Fencing:                        NewFencing(),
+		Requeuer:                       NewRequeuer(),
+		BRJobLimiter:                   NewBRJobLimiter(cliCfg.BRJobConcurrency, cliCfg.BRJobConcurrencyPerNamespace, kubeInformerFactory.Batch().V1().Jobs().Lister()),
+		BRNotifier:                     NewBRNotifier(kubeInformerFactory.Core().V1().Secrets().Lister(), recorder),
+		Capabilities:                   AllCapabilities(),
 
 		// Listers
-		ServiceLister:          kubeInformerFactory.Core().V1().Services().Lister(),
-		EndpointLister:         kubeInformerFactory.Core().V1().Endpoints().Lister(),
-		PVCLister:              kubeInformerFactory.Core().V1().PersistentVolumeClaims().Lister(),
-		PVLister:               pvLister,
-		PodLister:              kubeInformerFactory.Core().V1().Pods().Lister(),
-		NodeLister:             nodeLister,
-		SecretLister:           kubeInformerFactory.Core().V1().Secrets().Lister(),
-		ConfigMapLister:        labelFilterKubeInformerFactory.Core().V1().ConfigMaps().Lister(),
-		StatefulSetLister:      kubeInformerFactory.Apps().V1().StatefulSets().Lister(),
-		DeploymentLister:       kubeInformerFactory.Apps().V1().Deployments().Lister(),
-		StorageClassLister:     scLister,
-		JobLister:              kubeInformerFactory.Batch().V1().Jobs().Lister(),
-		IngressLister:          ingLister,
-		IngressV1Beta1Lister:   ingv1beta1Lister,
-		TiDBClusterLister:      informerFactory.Pingcap().V1alpha1().TidbClusters().Lister(),
-		DMClusterLister:        informerFactory.Pingcap().V1alpha1().DMClusters().Lister(),
-		BackupLister:           informerFactory.Pingcap().V1alpha1().Backups().Lister(),
-		CompactBackupLister:    informerFactory.Pingcap().V1alpha1().CompactBackups().Lister(),
-		RestoreLister:          informerFactory.Pingcap().V1alpha1().Restores().Lister(),
-		BackupScheduleLister:   informerFactory.Pingcap().V1alpha1().BackupSchedules().Lister(),
-		TiDBInitializerLister:  informerFactory.Pingcap().V1alpha1().TidbInitializers().Lister(),
-		TiDBMonitorLister:      informerFactory.Pingcap().V1alpha1().TidbMonitors().Lister(),
-		TiDBNGMonitoringLister: informerFactory.Pingcap().V1alpha1().TidbNGMonitorings().Lister(),
-		TiDBDashboardLister:    informerFactory.Pingcap().V1alpha1().TidbDashboards().Lister(),
+		ServiceLister:                kubeInformerFactory.Core().V1().Services().Lister(),
+		EndpointLister:               kubeInformerFactory.Core().V1().Endpoints().Lister(),
+		PVCLister:                    kubeInformerFactory.Core().V1().PersistentVolumeClaims().Lister(),
+		PVLister:                     pvLister,
+		PodLister:                    kubeInformerFactory.Core().V1().Pods().Lister(),
+		NodeLister:                   nodeLister,
+		SecretLister:                 kubeInformerFactory.Core().V1().Secrets().Lister(),
+		ConfigMapLister:              labelFilterKubeInformerFactory.Core().V1().ConfigMaps().Lister(),
+		StatefulSetLister:            kubeInformerFactory.Apps().V1().StatefulSets().Lister(),
+		DeploymentLister:             kubeInformerFactory.Apps().V1().Deployments().Lister(),
+		StorageClassLister:           scLister,
+		JobLister:                    kubeInformerFactory.Batch().V1().Jobs().Lister(),
+		IngressLister:                ingLister,
+		IngressV1Beta1Lister:         ingv1beta1Lister,
+		TiDBClusterLister:            informerFactory.Pingcap().V1alpha1().TidbClusters().Lister(),
+		DMClusterLister:              informerFactory.Pingcap().V1alpha1().DMClusters().Lister(),
+		BackupLister:                 informerFactory.Pingcap().V1alpha1().Backups().Lister(),
+		CompactBackupLister:          informerFactory.Pingcap().V1alpha1().CompactBackups().Lister(),
+		RestoreLister:                informerFactory.Pingcap().V1alpha1().Restores().Lister(),
+		BackupScheduleLister:         informerFactory.Pingcap().V1alpha1().BackupSchedules().Lister(),
+		TiDBInitializerLister:        informerFactory.Pingcap().V1alpha1().TidbInitializers().Lister(),
+		TiDBMonitorLister:            informerFactory.Pingcap().V1alpha1().TidbMonitors().Lister(),
+		TiDBNGMonitoringLister:       informerFactory.Pingcap().V1alpha1().TidbNGMonitorings().Lister(),
+		TiDBDashboardLister:          informerFactory.Pingcap().V1alpha1().TidbDashboards().Lister(),
+		TiDBClusterReplicationLister: informerFactory.Pingcap().V1alpha1().TidbClusterReplications().Lister(),
+		PumpMigrationLister:          informerFactory.Pingcap().V1alpha1().PumpMigrations().Lister(),
+		TiDBAccountLister:            informerFactory.Pingcap().V1alpha1().TidbAccounts().Lister(),
 
 		AWSConfig: cfg,
 	}, nil
@@ -415,6 +608,11 @@ func NewDependencies(ns string, cliCfg *CLIConfig, clientset versioned.Interface
 		}
 	}
 	options = append(options, informers.WithTweakListOptions(tweakListOptionsFunc))
+	resyncConfig, err := ParseResyncDurations(cliCfg.ResyncDurations)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, informers.WithCustomResyncConfig(resyncConfig))
 
 	// Initialize the informer factories
 	informerFactory := informers.NewSharedInformerFactoryWithOptions(clientset, cliCfg.ResyncDuration, options...)
@@ -431,7 +629,22 @@ func NewDependencies(ns string, cliCfg *CLIConfig, clientset versioned.Interface
 	if err != nil {
 		return nil, err
 	}
-	deps.Controls = newRealControls(cliCfg, clientset, kubeClientset, genericCli, informerFactory, kubeInformerFactory, recorder)
+	caps, err := ProbeCapabilities(kubeClientset.Discovery())
+	if err != nil {
+		klog.Warningf("failed to probe the capabilities of the Kubernetes cluster, assume all features are supported: %v", err)
+	} else {
+		klog.Infof("capabilities of the Kubernetes cluster: %s", caps)
+		deps.Capabilities = caps
+	}
+	imagePolicy, err := LoadImagePolicy(cliCfg.ImagePolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	deps.ConfigDefaults, err = LoadConfigDefaults(cliCfg.ConfigDefaultsFile)
+	if err != nil {
+		return nil, err
+	}
+	deps.Controls = WithImagePolicy(newRealControls(cliCfg, clientset, kubeClientset, genericCli, informerFactory, kubeInformerFactory, recorder, deps.Fencing), imagePolicy, recorder)
 	return deps, nil
 }
 
@@ -50,3 +50,55 @@ func TestFakeTidbCluster(t *testing.T) {
 		}, time.Second*10).Should(BeNil())
 	}
 }
+
+func TestValidateLeaderElection(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cfg := DefaultCLIConfig()
+	g.Expect(cfg.ValidateLeaderElection()).To(Succeed())
+
+	cfg.LeaseDuration = 60 * time.Second
+	cfg.RenewDeadline = 40 * time.Second
+	cfg.RetryPeriod = 5 * time.Second
+	g.Expect(cfg.ValidateLeaderElection()).To(Succeed())
+
+	cfg.RenewDeadline = 60 * time.Second
+	g.Expect(cfg.ValidateLeaderElection()).NotTo(Succeed())
+
+	cfg.RenewDeadline = 5 * time.Second
+	g.Expect(cfg.ValidateLeaderElection()).NotTo(Succeed())
+
+	cfg.RenewDeadline = 40 * time.Second
+	cfg.RetryPeriod = 0
+	g.Expect(cfg.ValidateLeaderElection()).NotTo(Succeed())
+}
+
+func TestValidateAPIServer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cfg := DefaultCLIConfig()
+	g.Expect(cfg.ValidateAPIServer()).To(Succeed())
+	g.Expect(cfg.GetAPIServerAudiences()).To(Equal([]string{"tidb-operator-api-server"}))
+
+	// TLS is required unless the API server listens on a loopback address
+	cfg.APIServerAddress = ":6443"
+	g.Expect(cfg.ValidateAPIServer()).NotTo(Succeed())
+	cfg.APIServerTLSCertFile = "tls.crt"
+	g.Expect(cfg.ValidateAPIServer()).NotTo(Succeed())
+	cfg.APIServerTLSKeyFile = "tls.key"
+	g.Expect(cfg.ValidateAPIServer()).To(Succeed())
+
+	cfg.APIServerTLSCertFile, cfg.APIServerTLSKeyFile = "", ""
+	for _, addr := range []string{"127.0.0.1:6443", "[::1]:6443", "localhost:6443"} {
+		cfg.APIServerAddress = addr
+		g.Expect(cfg.ValidateAPIServer()).To(Succeed(), addr)
+	}
+	cfg.APIServerAddress = "0.0.0.0:6443"
+	g.Expect(cfg.ValidateAPIServer()).NotTo(Succeed())
+
+	cfg.APIServerAddress = "127.0.0.1:6443"
+	cfg.APIServerAudiences = " , "
+	g.Expect(cfg.ValidateAPIServer()).NotTo(Succeed())
+	cfg.APIServerAudiences = "a, b"
+	g.Expect(cfg.GetAPIServerAudiences()).To(Equal([]string{"a", "b"}))
+}
@@ -14,6 +14,7 @@
 package dmcluster
 
 import (
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
@@ -25,6 +26,7 @@ import (
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // ControlInterface implements the control logic for updating DMClusters and their children StatefulSets.
@@ -42,6 +44,7 @@ func NewDefaultDMClusterControl(
 	masterMemberManager manager.DMManager,
 	workerMemberManager manager.DMManager,
 	reclaimPolicyManager manager.DMManager,
+	deletionPolicyManager manager.DMManager,
 	orphanPodsCleaner member.OrphanPodsCleaner,
 	pvcCleaner member.PVCCleanerInterface,
 	pvcResizer member.PVCResizerInterface,
@@ -53,6 +56,7 @@ func NewDefaultDMClusterControl(
 		masterMemberManager,
 		workerMemberManager,
 		reclaimPolicyManager,
+		deletionPolicyManager,
 		//metaManager,
 		orphanPodsCleaner,
 		pvcCleaner,
@@ -64,10 +68,11 @@ func NewDefaultDMClusterControl(
 }
 
 type defaultDMClusterControl struct {
-	dcControl            controller.DMClusterControlInterface
-	masterMemberManager  manager.DMManager
-	workerMemberManager  manager.DMManager
-	reclaimPolicyManager manager.DMManager
+	dcControl             controller.DMClusterControlInterface
+	masterMemberManager   manager.DMManager
+	workerMemberManager   manager.DMManager
+	reclaimPolicyManager  manager.DMManager
+	deletionPolicyManager manager.DMManager
 	//metaManager       manager.DMManager
 	orphanPodsCleaner member.OrphanPodsCleaner
 	pvcCleaner        member.PVCCleanerInterface
@@ -80,6 +85,10 @@ type defaultDMClusterControl struct {
 // UpdateStatefulSet executes the core logic loop for a dmcluster.
 func (c *defaultDMClusterControl) UpdateDMCluster(dc *v1alpha1.DMCluster) error {
 	c.defaulting(dc)
+	// the cluster being deleted is cleaned up by its deletion policy instead of being synced
+	if dc.DeletionTimestamp != nil && controllerutil.ContainsFinalizer(dc, label.ClusterDeletionFinalizer) {
+		return c.deletionPolicyManager.SyncDM(dc)
+	}
 	if !c.validate(dc) {
 		return nil // fatal error, no need to retry on invalid object
 	}
@@ -126,6 +135,11 @@ func (c *defaultDMClusterControl) updateDMCluster(dc *v1alpha1.DMCluster) error
 		return err
 	}
 
+	// keeping the deletion finalizer of the cluster in line with spec.deletionPolicy
+	if err := c.deletionPolicyManager.SyncDM(dc); err != nil {
+		return err
+	}
+
 	// cleaning all orphan pods(dm-master or dm-worker which don't have a related PVC) managed by operator
 	skipReasons, err := c.orphanPodsCleaner.Clean(dc)
 	if err != nil {
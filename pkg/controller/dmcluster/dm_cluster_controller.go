@@ -55,6 +55,7 @@ func NewController(deps *controller.Dependencies) *Controller {
 			mm.NewMasterMemberManager(deps, mm.NewMasterScaler(deps), mm.NewMasterUpgrader(deps), mm.NewMasterFailover(deps), suspender),
 			mm.NewWorkerMemberManager(deps, mm.NewWorkerScaler(deps), mm.NewWorkerFailover(deps), suspender),
 			meta.NewReclaimPolicyManager(deps),
+			meta.NewDeletionPolicyManager(deps),
 			mm.NewOrphanPodsCleaner(deps),
 			mm.NewRealPVCCleaner(deps),
 			mm.NewPVCResizer(deps),
@@ -73,6 +74,10 @@ func NewController(deps *controller.Dependencies) *Controller {
 	dmClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: c.enqueueDMCluster,
 		UpdateFunc: func(old, cur interface{}) {
+			// the clusters with an overridden resync duration are requeued by the controller itself
+			if controller.IsOverriddenResync(old, cur) {
+				return
+			}
 			c.enqueueDMCluster(cur)
 		},
 		DeleteFunc: c.enqueueDMCluster,
@@ -133,10 +138,26 @@ func (c *Controller) processNextWorkItem() bool {
 		c.queue.AddRateLimited(key)
 	} else {
 		c.queue.Forget(key)
+		if d, ok := c.resyncDurationOf(key.(string)); ok {
+			c.queue.AddAfter(key, d)
+		}
 	}
 	return true
 }
 
+// resyncDurationOf returns the resync duration overridden by the annotation of the dmcluster of the given key
+func (c *Controller) resyncDurationOf(key string) (time.Duration, bool) {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, false
+	}
+	dc, err := c.deps.DMClusterLister.DMClusters(ns).Get(name)
+	if err != nil {
+		return 0, false
+	}
+	return controller.ResyncDurationOf(dc)
+}
+
 // sync syncs the given dmcluster.
 func (c *Controller) sync(key string) (err error) {
 	startTime := time.Now()
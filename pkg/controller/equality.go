@@ -71,6 +71,19 @@ func SetServiceLastAppliedConfigAnnotation(svc *corev1.Service) error {
 	return nil
 }
 
+// SetConfigMapLastAppliedConfigAnnotation set last applied data to ConfigMap's annotation
+func SetConfigMapLastAppliedConfigAnnotation(cm *corev1.ConfigMap) error {
+	b, err := json.Marshal(cm.Data)
+	if err != nil {
+		return err
+	}
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[LastAppliedConfigAnnotation] = string(b)
+	return nil
+}
+
 // ServiceEqual compares the new Service's spec with old Service's last applied config
 func ServiceEqual(newSvc, oldSvc *corev1.Service) (bool, error) {
 	oldSpec := corev1.ServiceSpec{}
@@ -0,0 +1,159 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// fencingTimeout is the timeout to read the leader election record
+const fencingTimeout = 5 * time.Second
+
+// Fencing guards the destructive operations of the operator by its leadership, the operation is rejected if
+// the operator is no longer the leader. The fencing token is derived from the leader transitions of the leader
+// election record, so a new leader always has a greater token than the deposed ones.
+//
+// The token read from the record is reused for at most the renew deadline of the leader election, within
+// which the leader steps down if it fails to renew the lease, so the guarded operations don't double the
+// requests to the API server. The PD member and store deletions, which can't be undone, read the record live.
+//
+// The token is stamped into the statefulsets updated by the operator, and the update is rejected if the
+// statefulset has been stamped with a greater token. So a leader paused longer than the lease, e.g. by a
+// long GC or a network partition, can't issue late destructive operations against the clusters now owned
+// by the new leader when it wakes up.
+type Fencing struct {
+	lock sync.RWMutex
+	// leaderLock is the leader election lock, nil means the operator runs without leader election
+	leaderLock resourcelock.Interface
+	// maxTokenAge is the longest time the token read from the record is reused, 0 means it's never reused
+	maxTokenAge time.Duration
+	// token is the token read from the record at observedAt, 0 means no token has been read
+	token      int64
+	observedAt time.Time
+}
+
+// NewFencing returns a Fencing without leader election lock
+func NewFencing() *Fencing {
+	return &Fencing{}
+}
+
+// SetLock sets the leader election lock the leadership of the operator is held by, the token read from the
+// record is reused for at most maxTokenAge, which should not be longer than the renew deadline
+func (f *Fencing) SetLock(leaderLock resourcelock.Interface, maxTokenAge time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.leaderLock = leaderLock
+	f.maxTokenAge = maxTokenAge
+	f.token = 0
+}
+
+// Token returns the fencing token of the leadership of the operator, the token read from the leader election
+// record is reused if it's not older than maxTokenAge. 0 is returned if the operator runs without leader
+// election. An error is returned if the record can't be read or the operator is not the leader.
+func (f *Fencing) Token() (int64, error) {
+	return f.getToken(false)
+}
+
+// Check returns an error if the operator is not the leader, it's called before the destructive operations,
+// e.g. deleting a pod or a PVC.
+func (f *Fencing) Check() error {
+	_, err := f.getToken(false)
+	return err
+}
+
+// CheckLive is like Check but always reads the leader election record, it's called before the destructive
+// operations that can't be undone, e.g. deleting a PD member or a store.
+func (f *Fencing) CheckLive() error {
+	_, err := f.getToken(true)
+	return err
+}
+
+func (f *Fencing) getToken(live bool) (int64, error) {
+	if f == nil {
+		return 0, nil
+	}
+	f.lock.RLock()
+	leaderLock := f.leaderLock
+	token, observedAt, maxTokenAge := f.token, f.observedAt, f.maxTokenAge
+	f.lock.RUnlock()
+	if leaderLock == nil {
+		return 0, nil
+	}
+	if !live && token > 0 && time.Since(observedAt) < maxTokenAge {
+		return token, nil
+	}
+
+	// the token is aged from the start of the read, so it expires no later than the lease it's read from
+	observedAt = time.Now()
+	token, err := readToken(leaderLock)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err != nil {
+		f.token = 0
+		return 0, err
+	}
+	f.token, f.observedAt = token, observedAt
+	return token, nil
+}
+
+// readToken reads the leader election record and returns the fencing token of the leadership of the operator
+func readToken(leaderLock resourcelock.Interface) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fencingTimeout)
+	defer cancel()
+	record, _, err := leaderLock.Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the leader election record of %s: %v", leaderLock.Describe(), err)
+	}
+	if record.HolderIdentity != leaderLock.Identity() {
+		return 0, fmt.Errorf("%s is not the leader of %s, the leader is %q", leaderLock.Identity(), leaderLock.Describe(), record.HolderIdentity)
+	}
+	return int64(record.LeaderTransitions) + 1, nil
+}
+
+// Stamp stamps the fencing token into the annotations of obj to be updated, current is the latest version of
+// the object known. An error is returned if the operator is not the leader, or current has been stamped with
+// a greater token by a newer leader. Nothing is stamped if the operator runs without leader election.
+func (f *Fencing) Stamp(obj, current metav1.Object) error {
+	token, err := f.Token()
+	if err != nil {
+		return err
+	}
+	if token == 0 {
+		return nil
+	}
+	if current != nil {
+		if value, ok := current.GetAnnotations()[label.AnnFencingTokenKey]; ok {
+			stamped, err := strconv.ParseInt(value, 10, 64)
+			if err == nil && stamped > token {
+				return fmt.Errorf("%s/%s has been updated by a newer leader of the operator with fencing token %d, the token of this operator is %d",
+					current.GetNamespace(), current.GetName(), stamped, token)
+			}
+		}
+	}
+	anns := obj.GetAnnotations()
+	if anns == nil {
+		anns = map[string]string{}
+	}
+	anns[label.AnnFencingTokenKey] = strconv.FormatInt(token, 10)
+	obj.SetAnnotations(anns)
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// fakeLeaderLock is a leader election lock whose record is set by the tests
+type fakeLeaderLock struct {
+	identity string
+	record   resourcelock.LeaderElectionRecord
+	err      error
+	// gets is the number of the reads of the record
+	gets int
+}
+
+func (l *fakeLeaderLock) Get(_ context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	l.gets++
+	if l.err != nil {
+		return nil, nil, l.err
+	}
+	record := l.record
+	return &record, nil, nil
+}
+
+func (l *fakeLeaderLock) Create(_ context.Context, ler resourcelock.LeaderElectionRecord) error {
+	l.record = ler
+	return nil
+}
+
+func (l *fakeLeaderLock) Update(_ context.Context, ler resourcelock.LeaderElectionRecord) error {
+	l.record = ler
+	return nil
+}
+
+func (l *fakeLeaderLock) RecordEvent(string) {}
+
+func (l *fakeLeaderLock) Identity() string {
+	return l.identity
+}
+
+func (l *fakeLeaderLock) Describe() string {
+	return "ns/tidb-controller-manager"
+}
+
+// newFakeLeaderLock returns a lock held by the operator with the leader transitions
+func newFakeLeaderLock(transitions int) *fakeLeaderLock {
+	return &fakeLeaderLock{
+		identity: "operator-0",
+		record:   resourcelock.LeaderElectionRecord{HolderIdentity: "operator-0", LeaderTransitions: transitions},
+	}
+}
+
+func TestFencingStamp(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newSet := func(token string) *apps.StatefulSet {
+		set := &apps.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "basic-tikv"}}
+		if token != "" {
+			set.Annotations = map[string]string{label.AnnFencingTokenKey: token}
+		}
+		return set
+	}
+
+	// nothing is stamped before the leadership is acquired
+	var nilFencing *Fencing
+	set := newSet("")
+	g.Expect(nilFencing.Stamp(set, newSet("5"))).To(Succeed())
+	fencing := NewFencing()
+	g.Expect(fencing.Stamp(set, newSet("5"))).To(Succeed())
+	g.Expect(set.Annotations).To(BeEmpty())
+
+	fencing.SetLock(newFakeLeaderLock(4), 0)
+	g.Expect(fencing.Stamp(set, newSet(""))).To(Succeed())
+	g.Expect(set.Annotations).To(HaveKeyWithValue(label.AnnFencingTokenKey, "5"))
+
+	set = newSet("")
+	g.Expect(fencing.Stamp(set, newSet("4"))).To(Succeed())
+	g.Expect(set.Annotations).To(HaveKeyWithValue(label.AnnFencingTokenKey, "5"))
+
+	set = newSet("")
+	g.Expect(fencing.Stamp(set, newSet("5"))).To(Succeed())
+
+	set = newSet("")
+	err := fencing.Stamp(set, newSet("6"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("fencing token 6"))
+	g.Expect(set.Annotations).To(BeEmpty())
+}
+
+func TestFencingCheck(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the operator without leader election is not fenced
+	var nilFencing *Fencing
+	g.Expect(nilFencing.Check()).To(Succeed())
+	fencing := NewFencing()
+	g.Expect(fencing.Check()).To(Succeed())
+
+	leaderLock := newFakeLeaderLock(1)
+	fencing.SetLock(leaderLock, 0)
+	g.Expect(fencing.Check()).To(Succeed())
+	token, err := fencing.Token()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(token).To(Equal(int64(2)))
+
+	// the record is read live, so the deposed leader is fenced once the leadership is taken over
+	leaderLock.record = resourcelock.LeaderElectionRecord{HolderIdentity: "operator-1", LeaderTransitions: 2}
+	err = fencing.Check()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not the leader"))
+	set := &apps.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "basic-tikv"}}
+	g.Expect(fencing.Stamp(set, nil)).NotTo(Succeed())
+	g.Expect(set.Annotations).To(BeEmpty())
+
+	// the operator is fenced if the record can't be read
+	leaderLock.err = errors.New("timeout")
+	g.Expect(fencing.Check()).NotTo(Succeed())
+}
+
+func TestFencingTokenReuse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	leaderLock := newFakeLeaderLock(1)
+	fencing := NewFencing()
+	fencing.SetLock(leaderLock, time.Minute)
+
+	// the token is reused within the max token age
+	g.Expect(fencing.Check()).To(Succeed())
+	g.Expect(fencing.Check()).To(Succeed())
+	token, err := fencing.Token()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(token).To(Equal(int64(2)))
+	g.Expect(leaderLock.gets).To(Equal(1))
+
+	// the PD deletions always read the record
+	leaderLock.record = resourcelock.LeaderElectionRecord{HolderIdentity: "operator-1", LeaderTransitions: 2}
+	g.Expect(fencing.CheckLive()).NotTo(Succeed())
+	g.Expect(leaderLock.gets).To(Equal(2))
+
+	// the failed read drops the token, so the record is read again
+	g.Expect(fencing.Check()).NotTo(Succeed())
+	g.Expect(leaderLock.gets).To(Equal(3))
+
+	// the token older than the max token age is read again
+	leaderLock.record.HolderIdentity = leaderLock.identity
+	g.Expect(fencing.Check()).To(Succeed())
+	g.Expect(leaderLock.gets).To(Equal(4))
+	fencing.observedAt = fencing.observedAt.Add(-time.Minute)
+	token, err = fencing.Token()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(token).To(Equal(int64(3)))
+	g.Expect(leaderLock.gets).To(Equal(5))
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/scheme"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -153,6 +154,7 @@ func (w *typedWrapper) CreateOrUpdateDeployment(controller client.Object, deploy
 		desiredDep := desired.(*appsv1.Deployment)
 
 		existingDep.Spec.Replicas = desiredDep.Spec.Replicas
+		RetainSyncedMeta(desiredDep, existingDep)
 		existingDep.Labels = desiredDep.Labels
 
 		if existingDep.Annotations == nil {
@@ -246,12 +248,23 @@ func (w *typedWrapper) CreateOrUpdateServiceAccount(controller client.Object, sa
 }
 
 func (w *typedWrapper) CreateOrUpdateConfigMap(controller client.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	// the data applied to the ConfigMaps of a TidbCluster is recorded to revert the drifts, see spec.driftProtection
+	if _, ok := controller.(*v1alpha1.TidbCluster); ok {
+		cm = cm.DeepCopy()
+		if err := SetConfigMapLastAppliedConfigAnnotation(cm); err != nil {
+			return nil, err
+		}
+	}
 	result, err := w.GenericControlInterface.CreateOrUpdate(controller, cm, func(existing, desired client.Object) error {
 		existingCm := existing.(*corev1.ConfigMap)
 		desiredCm := desired.(*corev1.ConfigMap)
 
 		existingCm.Data = desiredCm.Data
+		RetainSyncedMeta(desiredCm, existingCm)
 		existingCm.Labels = desiredCm.Labels
+		if existingCm.Annotations == nil {
+			existingCm.Annotations = map[string]string{}
+		}
 		for k, v := range desiredCm.Annotations {
 			existingCm.Annotations[k] = v
 		}
@@ -274,6 +287,7 @@ func (w *typedWrapper) CreateOrUpdateService(controller client.Object, svc *core
 		for k, v := range desiredSvc.Annotations {
 			existingSvc.Annotations[k] = v
 		}
+		RetainSyncedMeta(desiredSvc, existingSvc)
 		existingSvc.Labels = desiredSvc.Labels
 		equal, err := ServiceEqual(desiredSvc, existingSvc)
 		if err != nil {
@@ -0,0 +1,288 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultRegistry is the registry of the images without a registry, e.g. pingcap/tidb
+	defaultRegistry = "docker.io"
+	// defaultTag is the tag of the images without a tag or a digest
+	defaultTag = "latest"
+)
+
+// ImagePolicy rewrites and checks the images of all the pods created by the operator, so the
+// air-gapped deployments can pull the images from the mirrors without changing each CR.
+// It's loaded from the file specified by --image-policy-file, e.g.
+//
+//	registryMirrors:
+//	  docker.io: harbor.example.com/dockerhub
+//	  gcr.io/google-containers: harbor.example.com/gcr
+//	digests:
+//	  pingcap/tidb:v7.5.0: sha256:0123...
+//	requireDigest: true
+//	blockedTags: ["latest", "nightly"]
+type ImagePolicy struct {
+	// RegistryMirrors maps the registries, or the repository prefixes in the registries, to their
+	// mirrors. The longest matched prefix is replaced. The images without a registry are in docker.io,
+	// e.g. pingcap/tidb is matched by docker.io/pingcap.
+	RegistryMirrors map[string]string `json:"registryMirrors,omitempty"`
+	// Digests pins the images to the digests, the keys are the images specified in the CRs
+	// or the images after rewritten by the mirrors.
+	Digests map[string]string `json:"digests,omitempty"`
+	// RequireDigest rejects the images not pinned to a digest.
+	RequireDigest bool `json:"requireDigest,omitempty"`
+	// BlockedTags rejects the images with these tags, the images without a tag and a digest
+	// are regarded as the latest tag.
+	BlockedTags []string `json:"blockedTags,omitempty"`
+}
+
+// LoadImagePolicy loads the image policy from the YAML file, nil is returned if path is empty
+func LoadImagePolicy(path string) (*ImagePolicy, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read image policy file %s failed: %v", path, err)
+	}
+	p := &ImagePolicy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("parse image policy file %s failed: %v", path, err)
+	}
+	mirrors := make(map[string]string, len(p.RegistryMirrors))
+	for prefix, mirror := range p.RegistryMirrors {
+		mirrors[strings.TrimSuffix(prefix, "/")] = strings.TrimSuffix(mirror, "/")
+	}
+	p.RegistryMirrors = mirrors
+	for image, digest := range p.Digests {
+		if !strings.Contains(digest, ":") {
+			return nil, fmt.Errorf("digest %q of image %s is invalid, it should be like sha256:<hex>", digest, image)
+		}
+	}
+	return p, nil
+}
+
+// Image returns the image rewritten by the policy, an error is returned if the image is rejected
+func (p *ImagePolicy) Image(image string) (string, error) {
+	if p == nil || image == "" {
+		return image, nil
+	}
+
+	name, tag, digest := splitImage(image)
+	if mirrored, ok := p.mirror(name); ok {
+		name = mirrored
+	}
+	rewritten := joinImage(name, tag, digest)
+	if digest == "" {
+		if d, ok := p.Digests[image]; ok {
+			digest = d
+		} else if d, ok := p.Digests[rewritten]; ok {
+			digest = d
+		}
+		rewritten = joinImage(name, tag, digest)
+	}
+
+	if p.RequireDigest && digest == "" {
+		return "", fmt.Errorf("image %s is not pinned to a digest", image)
+	}
+	if tag == "" && digest == "" {
+		tag = defaultTag
+	}
+	for _, blocked := range p.BlockedTags {
+		if tag != "" && tag == blocked {
+			return "", fmt.Errorf("tag %s of image %s is blocked", tag, image)
+		}
+	}
+	return rewritten, nil
+}
+
+// mirror returns the name of the image in the mirror
+func (p *ImagePolicy) mirror(name string) (string, bool) {
+	full := normalizeImageName(name)
+	var matched string
+	for prefix := range p.RegistryMirrors {
+		if len(prefix) > len(matched) && (full == prefix || strings.HasPrefix(full, prefix+"/")) {
+			matched = prefix
+		}
+	}
+	if matched == "" {
+		return name, false
+	}
+	return p.RegistryMirrors[matched] + strings.TrimPrefix(full, matched), true
+}
+
+// ApplyToPodSpec rewrites the images of all the containers in the pod spec
+func (p *ImagePolicy) ApplyToPodSpec(spec *corev1.PodSpec) error {
+	if p == nil {
+		return nil
+	}
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			image, err := p.Image(containers[i].Image)
+			if err != nil {
+				return fmt.Errorf("container %s: %v", containers[i].Name, err)
+			}
+			containers[i].Image = image
+		}
+	}
+	return nil
+}
+
+// ApplyToObject rewrites the images of the pod template of the workload, the other objects are ignored
+func (p *ImagePolicy) ApplyToObject(obj runtime.Object) error {
+	switch o := obj.(type) {
+	case *apps.StatefulSet:
+		return p.ApplyToPodSpec(&o.Spec.Template.Spec)
+	case *apps.Deployment:
+		return p.ApplyToPodSpec(&o.Spec.Template.Spec)
+	case *batchv1.Job:
+		return p.ApplyToPodSpec(&o.Spec.Template.Spec)
+	case *corev1.Pod:
+		return p.ApplyToPodSpec(&o.Spec)
+	}
+	return nil
+}
+
+// normalizeImageName adds the default registry to the image name, e.g. pingcap/tidb is docker.io/pingcap/tidb
+func normalizeImageName(name string) string {
+	i := strings.IndexByte(name, '/')
+	if i < 0 {
+		return defaultRegistry + "/library/" + name
+	}
+	if first := name[:i]; !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return defaultRegistry + "/" + name
+	}
+	return name
+}
+
+// splitImage splits the image into the name, the tag and the digest
+func splitImage(image string) (name, tag, digest string) {
+	name = image
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name, digest = name[:i], name[i+1:]
+	}
+	// the colon before the last slash is the port of the registry
+	if i := strings.LastIndexByte(name, ':'); i > strings.LastIndexByte(name, '/') {
+		name, tag = name[:i], name[i+1:]
+	}
+	return name, tag, digest
+}
+
+func joinImage(name, tag, digest string) string {
+	image := name
+	if tag != "" {
+		image += ":" + tag
+	}
+	if digest != "" {
+		image += "@" + digest
+	}
+	return image
+}
+
+// WithImagePolicy wraps the controls creating the workloads so that the images of their pods
+// are rewritten by the policy.
+func WithImagePolicy(controls Controls, policy *ImagePolicy, recorder record.EventRecorder) Controls {
+	if policy == nil {
+		return controls
+	}
+	controls.StatefulSetControl = &imagePolicyStatefulSetControl{controls.StatefulSetControl, policy, recorder}
+	controls.JobControl = &imagePolicyJobControl{controls.JobControl, policy, recorder}
+	controls.GenericControl = &imagePolicyGenericControl{controls.GenericControl, policy, recorder}
+	controls.TypedControl = NewTypedControl(controls.GenericControl)
+	return controls
+}
+
+func applyImagePolicy(policy *ImagePolicy, recorder record.EventRecorder, controller runtime.Object, obj runtime.Object) error {
+	if err := policy.ApplyToObject(obj); err != nil {
+		name := ""
+		if mo, ok := obj.(metav1.Object); ok {
+			name = mo.GetName()
+		}
+		err = fmt.Errorf("%T %s is rejected by image policy, %v", obj, name, err)
+		recorder.Event(controller, corev1.EventTypeWarning, "FailedImagePolicy", err.Error())
+		return err
+	}
+	return nil
+}
+
+type imagePolicyStatefulSetControl struct {
+	StatefulSetControlInterface
+	policy   *ImagePolicy
+	recorder record.EventRecorder
+}
+
+func (c *imagePolicyStatefulSetControl) CreateStatefulSet(controller runtime.Object, set *apps.StatefulSet) error {
+	// the last applied config annotation has been set, so the rewritten images don't trigger updates
+	set = set.DeepCopy()
+	if err := applyImagePolicy(c.policy, c.recorder, controller, set); err != nil {
+		return err
+	}
+	return c.StatefulSetControlInterface.CreateStatefulSet(controller, set)
+}
+
+func (c *imagePolicyStatefulSetControl) UpdateStatefulSet(controller runtime.Object, set *apps.StatefulSet) (*apps.StatefulSet, error) {
+	set = set.DeepCopy()
+	if err := applyImagePolicy(c.policy, c.recorder, controller, set); err != nil {
+		return nil, err
+	}
+	return c.StatefulSetControlInterface.UpdateStatefulSet(controller, set)
+}
+
+type imagePolicyJobControl struct {
+	JobControlInterface
+	policy   *ImagePolicy
+	recorder record.EventRecorder
+}
+
+func (c *imagePolicyJobControl) CreateJob(object runtime.Object, job *batchv1.Job) error {
+	job = job.DeepCopy()
+	if err := applyImagePolicy(c.policy, c.recorder, object, job); err != nil {
+		return err
+	}
+	return c.JobControlInterface.CreateJob(object, job)
+}
+
+type imagePolicyGenericControl struct {
+	GenericControlInterface
+	policy   *ImagePolicy
+	recorder record.EventRecorder
+}
+
+func (c *imagePolicyGenericControl) CreateOrUpdate(controller, obj client.Object, mergeFn MergeFn, setOwnerFlag bool) (runtime.Object, error) {
+	if err := applyImagePolicy(c.policy, c.recorder, controller, obj); err != nil {
+		return nil, err
+	}
+	return c.GenericControlInterface.CreateOrUpdate(controller, obj, mergeFn, setOwnerFlag)
+}
+
+func (c *imagePolicyGenericControl) Create(controller, obj client.Object, setOwnerFlag bool) error {
+	if err := applyImagePolicy(c.policy, c.recorder, controller, obj); err != nil {
+		return err
+	}
+	return c.GenericControlInterface.Create(controller, obj, setOwnerFlag)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestImagePolicyImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := &ImagePolicy{
+		RegistryMirrors: map[string]string{
+			"docker.io":         "harbor.example.com/dockerhub",
+			"docker.io/pingcap": "harbor.example.com/pingcap",
+			"gcr.io":            "harbor.example.com/gcr",
+		},
+		Digests: map[string]string{
+			"pingcap/tidb:v7.5.0":                         "sha256:aaaa",
+			"harbor.example.com/pingcap/tikv:v7.5.0":      "sha256:bbbb",
+			"registry.example.com:5000/pingcap/pd:v7.5.0": "sha256:cccc",
+		},
+	}
+
+	tests := map[string]string{
+		"pingcap/tidb:v7.5.0":                         "harbor.example.com/pingcap/tidb:v7.5.0@sha256:aaaa",
+		"pingcap/tikv:v7.5.0":                         "harbor.example.com/pingcap/tikv:v7.5.0@sha256:bbbb",
+		"docker.io/pingcap/tiflash:v7.5.0":            "harbor.example.com/pingcap/tiflash:v7.5.0",
+		"busybox:1.36":                                "harbor.example.com/dockerhub/library/busybox:1.36",
+		"gcr.io/google-containers/pause":              "harbor.example.com/gcr/google-containers/pause",
+		"registry.example.com:5000/pingcap/pd:v7.5.0": "registry.example.com:5000/pingcap/pd:v7.5.0@sha256:cccc",
+		"quay.io/prometheus/prometheus@sha256:eeee":   "quay.io/prometheus/prometheus@sha256:eeee",
+		"": "",
+	}
+	for image, expected := range tests {
+		rewritten, err := p.Image(image)
+		g.Expect(err).To(Succeed(), image)
+		g.Expect(rewritten).To(Equal(expected), image)
+	}
+
+	var nilPolicy *ImagePolicy
+	g.Expect(nilPolicy.Image("pingcap/tidb:latest")).To(Equal("pingcap/tidb:latest"))
+
+	// the blocked tags and the images not pinned to a digest are rejected
+	p.BlockedTags = []string{"latest"}
+	_, err := p.Image("pingcap/tidb:latest")
+	g.Expect(err).To(HaveOccurred())
+	_, err = p.Image("pingcap/tidb")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(p.Image("pingcap/tidb@sha256:ffff")).To(Equal("harbor.example.com/pingcap/tidb@sha256:ffff"))
+
+	p.RequireDigest = true
+	_, err = p.Image("pingcap/tiflash:v7.5.0")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(p.Image("pingcap/tidb:v7.5.0")).To(Equal("harbor.example.com/pingcap/tidb:v7.5.0@sha256:aaaa"))
+}
+
+func TestImagePolicyApplyToObject(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p := &ImagePolicy{
+		RegistryMirrors: map[string]string{"docker.io": "harbor.example.com/dockerhub"},
+		BlockedTags:     []string{"latest"},
+	}
+	job := &batchv1.Job{}
+	job.Spec.Template.Spec = corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init", Image: "busybox:1.36"}},
+		Containers:     []corev1.Container{{Name: "br", Image: "pingcap/br:v7.5.0"}},
+	}
+	g.Expect(p.ApplyToObject(job)).To(Succeed())
+	g.Expect(job.Spec.Template.Spec.InitContainers[0].Image).To(Equal("harbor.example.com/dockerhub/library/busybox:1.36"))
+	g.Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("harbor.example.com/dockerhub/pingcap/br:v7.5.0"))
+
+	job.Spec.Template.Spec.Containers[0].Image = "pingcap/br"
+	g.Expect(p.ApplyToObject(job)).To(MatchError(ContainSubstring("container br")))
+
+	// the objects without pods are ignored
+	g.Expect(p.ApplyToObject(&corev1.ConfigMap{})).To(Succeed())
+}
+
+func TestLoadImagePolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p, err := LoadImagePolicy("")
+	g.Expect(err).To(Succeed())
+	g.Expect(p).To(BeNil())
+
+	path := filepath.Join(t.TempDir(), "image-policy.yaml")
+	g.Expect(os.WriteFile(path, []byte(`
+registryMirrors:
+  docker.io/: harbor.example.com/dockerhub/
+digests:
+  pingcap/tidb:v7.5.0: sha256:aaaa
+blockedTags: ["latest"]
+`), 0644)).To(Succeed())
+	p, err = LoadImagePolicy(path)
+	g.Expect(err).To(Succeed())
+	g.Expect(p.RegistryMirrors).To(Equal(map[string]string{"docker.io": "harbor.example.com/dockerhub"}))
+	g.Expect(p.Image("pingcap/tidb:v7.5.0")).To(Equal("harbor.example.com/dockerhub/pingcap/tidb:v7.5.0@sha256:aaaa"))
+
+	g.Expect(os.WriteFile(path, []byte("digests:\n  pingcap/tidb:v7.5.0: aaaa\n"), 0644)).To(Succeed())
+	_, err = LoadImagePolicy(path)
+	g.Expect(err).To(HaveOccurred())
+}
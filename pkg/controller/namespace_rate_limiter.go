@@ -0,0 +1,152 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+
+	"golang.org/x/time/rate"
+)
+
+// namespaceRateLimiterIdleTTL is how long the rate limiter of a namespace is kept without requests
+const namespaceRateLimiterIdleTTL = 10 * time.Minute
+
+// NamespaceRateLimiter limits the QPS to the kube-apiserver of each namespace by a token bucket,
+// so that a TidbCluster syncing in a hot loop can't consume all the QPS of the kube client and
+// starve the clusters in other namespaces. The requests not in a namespace and the watches are
+// not limited.
+type NamespaceRateLimiter struct {
+	qps   rate.Limit
+	burst int
+
+	lock      sync.Mutex
+	limiters  map[string]*namespaceLimiter
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+type namespaceLimiter struct {
+	*rate.Limiter
+	lastUsed time.Time
+}
+
+// NewNamespaceRateLimiter returns a NamespaceRateLimiter, nil is returned if qps is not positive.
+// The burst defaults to qps if it's not positive.
+func NewNamespaceRateLimiter(qps float64, burst int) *NamespaceRateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(qps)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return &NamespaceRateLimiter{
+		qps:      rate.Limit(qps),
+		burst:    burst,
+		limiters: map[string]*namespaceLimiter{},
+		now:      time.Now,
+	}
+}
+
+// Wrap wraps the round tripper so the requests are rate limited by their namespaces,
+// it can be used as the WrapTransport of the rest config.
+func (l *NamespaceRateLimiter) Wrap(rt http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return rt
+	}
+	return &namespaceRateLimitedRoundTripper{limiter: l, rt: rt}
+}
+
+// Wait blocks until the request in the namespace is allowed or the request is canceled
+func (l *NamespaceRateLimiter) Wait(req *http.Request, ns string) error {
+	limiter := l.get(ns)
+	start := l.now()
+	err := limiter.Wait(req.Context())
+	if waited := l.now().Sub(start); waited > time.Millisecond {
+		metrics.KubeClientThrottledRequests.WithLabelValues(ns).Inc()
+		metrics.KubeClientThrottledSeconds.WithLabelValues(ns).Add(waited.Seconds())
+	}
+	metrics.KubeClientRateLimiterTokens.WithLabelValues(ns).Set(limiter.Tokens())
+	return err
+}
+
+func (l *NamespaceRateLimiter) get(ns string) *rate.Limiter {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastPrune) > namespaceRateLimiterIdleTTL {
+		for name, limiter := range l.limiters {
+			if now.Sub(limiter.lastUsed) > namespaceRateLimiterIdleTTL {
+				delete(l.limiters, name)
+				metrics.KubeClientRateLimiterTokens.DeleteLabelValues(name)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	limiter, ok := l.limiters[ns]
+	if !ok {
+		limiter = &namespaceLimiter{Limiter: rate.NewLimiter(l.qps, l.burst)}
+		l.limiters[ns] = limiter
+	}
+	limiter.lastUsed = now
+	return limiter.Limiter
+}
+
+type namespaceRateLimitedRoundTripper struct {
+	limiter *NamespaceRateLimiter
+	rt      http.RoundTripper
+}
+
+func (t *namespaceRateLimitedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if ns := requestNamespace(req); ns != "" {
+		if err := t.limiter.Wait(req, ns); err != nil {
+			return nil, err
+		}
+	}
+	return t.rt.RoundTrip(req)
+}
+
+// requestNamespace returns the namespace of the resource requested, empty string is returned
+// for the watches and the requests not in a namespace.
+func requestNamespace(req *http.Request) string {
+	if req.URL == nil || req.URL.Query().Get("watch") == "true" {
+		return ""
+	}
+	// the path is /api/v1/namespaces/{ns}/{resource} or /apis/{group}/{version}/namespaces/{ns}/{resource}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, part := range parts {
+		if part != "api" && part != "apis" {
+			continue
+		}
+		j := i + 2
+		if part == "apis" {
+			j = i + 3
+		}
+		// the namespace objects themselves are not in a namespace
+		if len(parts) > j+2 && parts[j] == "namespaces" {
+			return parts[j+1]
+		}
+		return ""
+	}
+	return ""
+}
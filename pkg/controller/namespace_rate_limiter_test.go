@@ -0,0 +1,78 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRequestNamespace(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := map[string]string{
+		"/api/v1/namespaces/ns1/pods":                                "ns1",
+		"/api/v1/namespaces/ns1/pods/pod-1/status":                   "ns1",
+		"/apis/pingcap.com/v1alpha1/namespaces/ns2/tidbclusters/tc":  "ns2",
+		"/prefix/apis/apps/v1/namespaces/ns3/statefulsets":           "ns3",
+		"/api/v1/namespaces/ns1":                                     "",
+		"/api/v1/nodes":                                              "",
+		"/apis/pingcap.com/v1alpha1/tidbclusters":                    "",
+		"/api/v1/namespaces/ns1/pods?watch=true":                     "",
+		"/apis/storage.k8s.io/v1/storageclasses/namespaces/ns1/fake": "",
+	}
+	for path, ns := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		g.Expect(requestNamespace(req)).To(Equal(ns), path)
+	}
+}
+
+func TestNamespaceRateLimiter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(NewNamespaceRateLimiter(0, 10)).To(BeNil())
+
+	requests := 0
+	rt := NewNamespaceRateLimiter(1, 2).Wrap(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+	do := func(path string, timeout time.Duration) error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+		return err
+	}
+
+	// the burst of the namespace is used up
+	g.Expect(do("/api/v1/namespaces/ns1/pods", time.Second)).To(Succeed())
+	g.Expect(do("/api/v1/namespaces/ns1/pods", time.Second)).To(Succeed())
+	g.Expect(do("/api/v1/namespaces/ns1/pods", 10*time.Millisecond)).NotTo(Succeed())
+
+	// the other namespaces and the requests not in a namespace are not affected
+	g.Expect(do("/api/v1/namespaces/ns2/pods", 10*time.Millisecond)).To(Succeed())
+	g.Expect(do("/api/v1/nodes", 10*time.Millisecond)).To(Succeed())
+	g.Expect(requests).To(Equal(4))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,387 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orphangc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// Policy is the policy of the orphan garbage collector
+type Policy string
+
+const (
+	// PolicyNone ignores the orphan resources
+	PolicyNone Policy = "None"
+	// PolicyReport logs the orphan resources and exports the number of them in the metrics
+	PolicyReport Policy = "Report"
+	// PolicyDelete deletes the orphan resources after reporting them
+	PolicyDelete Policy = "Delete"
+)
+
+const (
+	kindStatefulSet = "StatefulSet"
+	kindService     = "Service"
+	kindConfigMap   = "ConfigMap"
+	kindPVC         = "PersistentVolumeClaim"
+)
+
+// orphan is a resource managed by the operator whose owning CR no longer exists
+type orphan struct {
+	kind      string
+	namespace string
+	name      string
+	uid       types.UID
+	owner     string
+}
+
+func (o orphan) String() string {
+	return fmt.Sprintf("%s %s/%s (owner %s)", o.kind, o.namespace, o.name, o.owner)
+}
+
+// Controller collects the statefulsets, services, configmaps and PVCs managed by the operator whose
+// owning CRs no longer exist periodically, e.g. after the CRs are lost by an etcd restore or deleted
+// with the orphan propagation policy, and reports or deletes them by the policies.
+type Controller struct {
+	deps        *controller.Dependencies
+	policy      Policy
+	pvcPolicy   Policy
+	interval    time.Duration
+	gracePeriod time.Duration
+	now         func() time.Time
+}
+
+// NewController returns a orphan garbage collector
+func NewController(deps *controller.Dependencies) *Controller {
+	return &Controller{
+		deps:        deps,
+		policy:      parsePolicy(deps.CLIConfig.OrphanGCPolicy),
+		pvcPolicy:   parsePolicy(deps.CLIConfig.OrphanGCPVCPolicy),
+		interval:    deps.CLIConfig.OrphanGCInterval,
+		gracePeriod: deps.CLIConfig.OrphanGCGracePeriod,
+		now:         time.Now,
+	}
+}
+
+func parsePolicy(policy string) Policy {
+	switch p := Policy(policy); p {
+	case PolicyNone, PolicyReport, PolicyDelete:
+		return p
+	case "":
+		return PolicyNone
+	default:
+		klog.Errorf("unknown orphan gc policy %q, the orphan resources are ignored", policy)
+		return PolicyNone
+	}
+}
+
+// Name returns the name of the controller.
+func (c *Controller) Name() string {
+	return "orphan-gc"
+}
+
+// Run collects the orphan resources every interval until stopCh is closed, the workers are ignored
+// as the collections are serial.
+func (c *Controller) Run(_ int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	if c.policy == PolicyNone && c.pvcPolicy == PolicyNone {
+		<-stopCh
+		return
+	}
+	klog.Infof("Starting orphan-gc controller, policy %s, pvc policy %s", c.policy, c.pvcPolicy)
+	defer klog.Info("Shutting down orphan-gc controller")
+
+	interval := c.interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	wait.Until(func() {
+		if err := c.Collect(); err != nil {
+			klog.Errorf("orphan-gc: collect the orphan resources failed: %v", err)
+		}
+	}, interval, stopCh)
+}
+
+// Collect finds the orphan resources, reports and deletes them by the policies
+func (c *Controller) Collect() error {
+	orphans, err := c.findOrphans()
+	if err != nil {
+		return err
+	}
+
+	metrics.OrphanResources.Reset()
+	var errs []error
+	for _, o := range orphans {
+		policy := c.policy
+		if o.kind == kindPVC {
+			policy = c.pvcPolicy
+		}
+		if policy == PolicyNone {
+			continue
+		}
+		metrics.OrphanResources.WithLabelValues(o.namespace, o.kind).Inc()
+		if policy != PolicyDelete {
+			klog.Warningf("orphan-gc: found orphan resource %s", o)
+			continue
+		}
+		if err := c.delete(o); err != nil {
+			errs = append(errs, fmt.Errorf("delete orphan resource %s failed: %v", o, err))
+			continue
+		}
+		metrics.OrphanResourcesDeleted.WithLabelValues(o.namespace, o.kind).Inc()
+		klog.Infof("orphan-gc: deleted orphan resource %s", o)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// findOrphans returns the resources managed by the operator whose owning CRs no longer exist
+func (c *Controller) findOrphans() ([]orphan, error) {
+	selector := labels.SelectorFromSet(label.NewOperatorManaged().Labels())
+	var objs []metav1.Object
+	var kinds []string
+	if c.policy != PolicyNone {
+		sets, err := c.deps.StatefulSetLister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range sets {
+			objs, kinds = append(objs, o), append(kinds, kindStatefulSet)
+		}
+		svcs, err := c.deps.ServiceLister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range svcs {
+			objs, kinds = append(objs, o), append(kinds, kindService)
+		}
+		cms, err := c.deps.ConfigMapLister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range cms {
+			objs, kinds = append(objs, o), append(kinds, kindConfigMap)
+		}
+	}
+	if c.pvcPolicy != PolicyNone {
+		pvcs, err := c.deps.PVCLister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range pvcs {
+			objs, kinds = append(objs, o), append(kinds, kindPVC)
+		}
+	}
+
+	var orphans []orphan
+	for i, obj := range objs {
+		if obj.GetDeletionTimestamp() != nil || c.now().Sub(obj.GetCreationTimestamp().Time) < c.gracePeriod {
+			continue
+		}
+		owner, orphaned, err := c.isOrphan(obj)
+		if err != nil {
+			return nil, err
+		}
+		if orphaned {
+			orphans = append(orphans, orphan{
+				kind:      kinds[i],
+				namespace: obj.GetNamespace(),
+				name:      obj.GetName(),
+				uid:       obj.GetUID(),
+				owner:     owner,
+			})
+		}
+	}
+	return orphans, nil
+}
+
+// ownerKinds are the kinds of the CRs owning the resources managed by the operator
+var ownerKinds = sets.NewString(
+	v1alpha1.TiDBClusterKind,
+	v1alpha1.DMClusterKind,
+	v1alpha1.TiDBMonitorKind,
+	v1alpha1.TiDBNGMonitoringKind,
+	v1alpha1.TiDBDashboardKind,
+)
+
+// tidbClusterComponents are the components whose resources are owned by a TidbCluster, the other resources
+// with the name label tidb-cluster, e.g. the components of Thanos, are owned by a TidbMonitor
+var tidbClusterComponents = sets.NewString(
+	label.PDLabelVal,
+	label.PDMSTSOLabelVal,
+	label.PDMSSchedulingLabelVal,
+	label.TiDBLabelVal,
+	label.TiKVLabelVal,
+	label.TiKVWitnessLabelVal,
+	label.TiFlashLabelVal,
+	label.TiCDCLabelVal,
+	label.TiProxyLabelVal,
+	label.PumpLabelVal,
+	label.DiscoveryLabelVal,
+)
+
+// isOrphan returns the owning CR of the resource, and whether the CR no longer exists. The owning CR is
+// the controller of the resource. The PVCs created from the volume claim templates of the StatefulSets
+// have no owner, so their CRs are found by the labels. The resources controlled by the other kinds or
+// without a known owner are never orphans.
+func (c *Controller) isOrphan(obj metav1.Object) (string, bool, error) {
+	ns := obj.GetNamespace()
+	kind, name, uid := "", "", types.UID("")
+	if ref := metav1.GetControllerOf(obj); ref != nil {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != v1alpha1.SchemeGroupVersion.Group || !ownerKinds.Has(ref.Kind) {
+			return "", false, nil
+		}
+		kind, name, uid = ref.Kind, ref.Name, ref.UID
+	} else if _, ok := obj.(*corev1.PersistentVolumeClaim); ok {
+		ls := obj.GetLabels()
+		kind, name = ownerKind(ls), ls[label.InstanceLabelKey]
+	}
+	if kind == "" || name == "" {
+		return "", false, nil
+	}
+	owner := fmt.Sprintf("%s %s/%s", kind, ns, name)
+
+	// the CR may be recreated with the same name, e.g. after an etcd restore, so it's matched by the UID
+	exists, err := c.ownerExists(kind, ns, name, uid)
+	if err != nil {
+		return owner, false, err
+	}
+	return owner, !exists, nil
+}
+
+// ownerKind returns the kind of the CR owning the resource with the labels
+func ownerKind(ls map[string]string) string {
+	switch ls[label.NameLabelKey] {
+	case "tidb-cluster":
+		component := ls[label.ComponentLabelKey]
+		if tidbClusterComponents.Has(component) {
+			return v1alpha1.TiDBClusterKind
+		}
+		if component == label.TiDBMonitorVal {
+			return v1alpha1.TiDBMonitorKind
+		}
+	case "dm-cluster":
+		return v1alpha1.DMClusterKind
+	case "tidb-ng-monitoring":
+		return v1alpha1.TiDBNGMonitoringKind
+	case "tidb-dashboard":
+		return v1alpha1.TiDBDashboardKind
+	}
+	return ""
+}
+
+// ownerExists returns whether the CR of the kind exists. The CR not found in the cache is confirmed
+// by the API server, as the CRs may be filtered by the selector of the operator or not synced yet.
+// The uid is empty if the CR is found by the labels, and the instance label of a TidbCluster may be
+// the instance name of a heterogeneous cluster instead of its name.
+func (c *Controller) ownerExists(kind, ns, name string, uid types.UID) (bool, error) {
+	found := func(meta metav1.Object) bool {
+		return uid == "" || meta.GetUID() == uid
+	}
+	switch kind {
+	case v1alpha1.TiDBClusterKind:
+		tcs, err := c.deps.TiDBClusterLister.TidbClusters(ns).List(labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		for _, tc := range tcs {
+			if (tc.GetName() == name || tc.GetInstanceName() == name) && found(tc) {
+				return true, nil
+			}
+		}
+		list, err := c.deps.Clientset.PingcapV1alpha1().TidbClusters(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for i := range list.Items {
+			if (list.Items[i].GetName() == name || list.Items[i].GetInstanceName() == name) && found(&list.Items[i]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case v1alpha1.DMClusterKind:
+		if dc, err := c.deps.DMClusterLister.DMClusters(ns).Get(name); err == nil && found(dc) {
+			return true, nil
+		}
+		dc, err := c.deps.Clientset.PingcapV1alpha1().DMClusters(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		return c.exists(dc, err, found)
+	case v1alpha1.TiDBMonitorKind:
+		if tm, err := c.deps.TiDBMonitorLister.TidbMonitors(ns).Get(name); err == nil && found(tm) {
+			return true, nil
+		}
+		tm, err := c.deps.Clientset.PingcapV1alpha1().TidbMonitors(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		return c.exists(tm, err, found)
+	case v1alpha1.TiDBNGMonitoringKind:
+		if tngm, err := c.deps.TiDBNGMonitoringLister.TidbNGMonitorings(ns).Get(name); err == nil && found(tngm) {
+			return true, nil
+		}
+		tngm, err := c.deps.Clientset.PingcapV1alpha1().TidbNGMonitorings(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		return c.exists(tngm, err, found)
+	case v1alpha1.TiDBDashboardKind:
+		if td, err := c.deps.TiDBDashboardLister.TidbDashboards(ns).Get(name); err == nil && found(td) {
+			return true, nil
+		}
+		td, err := c.deps.Clientset.PingcapV1alpha1().TidbDashboards(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		return c.exists(td, err, found)
+	}
+	return true, nil
+}
+
+func (c *Controller) exists(obj metav1.Object, err error, found func(metav1.Object) bool) (bool, error) {
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return found(obj), nil
+}
+
+// delete deletes the orphan resource if it's not recreated
+func (c *Controller) delete(o orphan) error {
+	opts := metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &o.uid}}
+	var err error
+	switch o.kind {
+	case kindStatefulSet:
+		err = c.deps.KubeClientset.AppsV1().StatefulSets(o.namespace).Delete(context.TODO(), o.name, opts)
+	case kindService:
+		err = c.deps.KubeClientset.CoreV1().Services(o.namespace).Delete(context.TODO(), o.name, opts)
+	case kindConfigMap:
+		err = c.deps.KubeClientset.CoreV1().ConfigMaps(o.namespace).Delete(context.TODO(), o.name, opts)
+	case kindPVC:
+		err = c.deps.KubeClientset.CoreV1().PersistentVolumeClaims(o.namespace).Delete(context.TODO(), o.name, opts)
+	}
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
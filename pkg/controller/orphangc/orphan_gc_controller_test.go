@@ -0,0 +1,141 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orphangc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCollect(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	deps.CLIConfig.OrphanGCPolicy = string(PolicyDelete)
+	deps.CLIConfig.OrphanGCPVCPolicy = string(PolicyReport)
+	deps.CLIConfig.OrphanGCGracePeriod = time.Hour
+	c := NewController(deps)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	// the cluster in the cache
+	cached := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cached", UID: "cached"}}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(cached)).Should(Succeed())
+	// the cluster not synced to the cache yet
+	uncached := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "uncached", UID: "uncached"}}
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters("ns").Create(context.TODO(), uncached, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+
+	// the resources are controlled by the cluster whose UID is the instance
+	objMeta := func(name, instance string, age time.Duration) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              name,
+			UID:               types.UID(name),
+			Labels:            label.New().Instance(instance).TiKV(),
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: instance, UID: types.UID(instance)},
+			}, controller.ControllerKind)},
+		}
+	}
+	sets := []*appsv1.StatefulSet{
+		{ObjectMeta: objMeta("cached-tikv", "cached", 2*time.Hour)},
+		{ObjectMeta: objMeta("uncached-tikv", "uncached", 2*time.Hour)},
+		{ObjectMeta: objMeta("deleted-tikv", "deleted", 2*time.Hour)},
+		{ObjectMeta: objMeta("young-tikv", "young", time.Minute)},
+	}
+	// the cluster is recreated with the same name
+	recreated := objMeta("recreated-tikv", "cached", 2*time.Hour)
+	recreated.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cached", UID: "old"},
+	}, controller.ControllerKind)}
+	sets = append(sets, &appsv1.StatefulSet{ObjectMeta: recreated})
+	for _, set := range sets {
+		g.Expect(deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(set)).Should(Succeed())
+		_, err := deps.KubeClientset.AppsV1().StatefulSets("ns").Create(context.TODO(), set, metav1.CreateOptions{})
+		g.Expect(err).Should(Succeed())
+	}
+	// the PVCs have no owner and are matched by the labels
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: objMeta("tikv-deleted-tikv-0", "deleted", 2*time.Hour)}
+	pvc.OwnerReferences = nil
+	g.Expect(deps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)).Should(Succeed())
+	_, err = deps.KubeClientset.CoreV1().PersistentVolumeClaims("ns").Create(context.TODO(), pvc, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+
+	// the services of Thanos are controlled by the TidbMonitor with the same labels as a TidbCluster's
+	tm := &v1alpha1.TidbMonitor{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "monitor", UID: "monitor"}}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbMonitors().Informer().GetIndexer().Add(tm)).Should(Succeed())
+	thanos := objMeta("monitor-thanos-query", "monitor", 2*time.Hour)
+	thanos.Labels = label.NewMonitor().Instance("monitor").Component("thanos-query")
+	thanos.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(tm, v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.TiDBMonitorKind))}
+	// the resource controlled by another kind is never an orphan
+	foreign := objMeta("foreign", "deleted", 2*time.Hour)
+	foreign.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy", UID: "deploy"},
+	}, appsv1.SchemeGroupVersion.WithKind("Deployment"))}
+	for _, meta := range []metav1.ObjectMeta{thanos, foreign} {
+		svc := &corev1.Service{ObjectMeta: meta}
+		g.Expect(deps.KubeInformerFactory.Core().V1().Services().Informer().GetIndexer().Add(svc)).Should(Succeed())
+		_, err = deps.KubeClientset.CoreV1().Services("ns").Create(context.TODO(), svc, metav1.CreateOptions{})
+		g.Expect(err).Should(Succeed())
+	}
+
+	g.Expect(c.Collect()).Should(Succeed())
+
+	for name, deleted := range map[string]bool{
+		"cached-tikv":    false,
+		"uncached-tikv":  false,
+		"deleted-tikv":   true,
+		"young-tikv":     false,
+		"recreated-tikv": true,
+	} {
+		_, err := deps.KubeClientset.AppsV1().StatefulSets("ns").Get(context.TODO(), name, metav1.GetOptions{})
+		g.Expect(errors.IsNotFound(err)).Should(Equal(deleted), name)
+	}
+	for _, name := range []string{thanos.Name, foreign.Name} {
+		_, err := deps.KubeClientset.CoreV1().Services("ns").Get(context.TODO(), name, metav1.GetOptions{})
+		g.Expect(err).Should(Succeed(), name)
+	}
+	// the orphan PVC is only reported
+	_, err = deps.KubeClientset.CoreV1().PersistentVolumeClaims("ns").Get(context.TODO(), pvc.Name, metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+}
+
+func TestOwnerKind(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(ownerKind(label.New().TiKV())).Should(Equal(v1alpha1.TiDBClusterKind))
+	g.Expect(ownerKind(label.NewMonitor().Monitor())).Should(Equal(v1alpha1.TiDBMonitorKind))
+	g.Expect(ownerKind(label.NewMonitor().Component("thanos-store"))).Should(BeEmpty())
+	g.Expect(ownerKind(label.NewDM().DMMaster())).Should(Equal(v1alpha1.DMClusterKind))
+	g.Expect(ownerKind(label.NewOperatorManaged())).Should(BeEmpty())
+}
+
+func TestParsePolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(parsePolicy("")).Should(Equal(PolicyNone))
+	g.Expect(parsePolicy("Report")).Should(Equal(PolicyReport))
+	g.Expect(parsePolicy("Delete")).Should(Equal(PolicyDelete))
+	g.Expect(parsePolicy("delete")).Should(Equal(PolicyNone))
+}
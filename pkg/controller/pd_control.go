@@ -20,11 +20,16 @@ import (
 
 // getPDClientFromService gets the pd client from the TidbCluster
 func getPDClientFromService(pdControl pdapi.PDControlInterface, tc *v1alpha1.TidbCluster) pdapi.PDClient {
+	if tc.HasExternalPD() {
+		return pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsExternalPDTLSEnabled(),
+			ExternalPDClientOptions(tc, tc.Spec.ExternalPD.Endpoints[0])...)
+	}
 	if tc.Heterogeneous() && tc.WithoutLocalPD() {
 		return pdControl.GetPDClient(pdapi.Namespace(tc.Spec.Cluster.Namespace), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled(),
 			pdapi.TLSCertFromTC(pdapi.Namespace(tc.GetNamespace()), tc.GetName()),
 			pdapi.ClusterRef(tc.Spec.Cluster.ClusterDomain),
 			pdapi.UseHeadlessService(tc.Spec.AcrossK8s),
+			pdapi.ResolveWith(tc.Spec.AcrossK8sResolver),
 		)
 	}
 	// cluster domain may be empty
@@ -38,6 +43,7 @@ func getPDMSClientFromService(pdControl pdapi.PDControlInterface, tc *v1alpha1.T
 			pdapi.TLSCertFromTC(pdapi.Namespace(tc.GetNamespace()), tc.GetName()),
 			pdapi.ClusterRef(tc.Spec.Cluster.ClusterDomain),
 			pdapi.UseHeadlessService(tc.Spec.AcrossK8s),
+			pdapi.ResolveWith(tc.Spec.AcrossK8sResolver),
 		)
 	}
 	// cluster domain may be empty
@@ -53,6 +59,10 @@ func getPDMSClientFromService(pdControl pdapi.PDControlInterface, tc *v1alpha1.T
 func GetPDClient(pdControl pdapi.PDControlInterface, tc *v1alpha1.TidbCluster) pdapi.PDClient {
 	pdClient := getPDClientFromService(pdControl, tc)
 
+	if tc.HasExternalPD() {
+		return getExternalPDClient(pdControl, tc, pdClient)
+	}
+
 	if len(tc.Status.PD.PeerMembers) == 0 {
 		return pdClient
 	}
@@ -73,6 +83,30 @@ func GetPDClient(pdControl pdapi.PDControlInterface, tc *v1alpha1.TidbCluster) p
 	return pdClient
 }
 
+// getExternalPDClient tries the endpoints of the external PD one by one until an available one is found
+func getExternalPDClient(pdControl pdapi.PDControlInterface, tc *v1alpha1.TidbCluster, pdClient pdapi.PDClient) pdapi.PDClient {
+	if _, err := pdClient.GetHealth(); err == nil {
+		return pdClient
+	}
+	for _, endpoint := range tc.Spec.ExternalPD.Endpoints[1:] {
+		pdEndpointClient := pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsExternalPDTLSEnabled(),
+			ExternalPDClientOptions(tc, endpoint)...)
+		if _, err := pdEndpointClient.GetHealth(); err == nil {
+			return pdEndpointClient
+		}
+	}
+	return pdClient
+}
+
+// ExternalPDClientOptions returns the options of the clients connecting to the given endpoint of the external PD
+func ExternalPDClientOptions(tc *v1alpha1.TidbCluster, endpoint string) []pdapi.Option {
+	opts := []pdapi.Option{pdapi.SpecifyClient(endpoint, endpoint)}
+	if secret := tc.Spec.ExternalPD.TLSClientSecretName; secret != nil && *secret != "" {
+		opts = append(opts, pdapi.TLSCertFromSecret(pdapi.Namespace(tc.GetNamespace()), *secret))
+	}
+	return opts
+}
+
 // GetPDClientForMember tries to return a PDClient for a specific PD member.
 func GetPDClientForMember(pdControl pdapi.PDControlInterface, tc *v1alpha1.TidbCluster, member *v1alpha1.PDMember) pdapi.PDClient {
 	if member == nil {
@@ -116,6 +150,11 @@ func NewFakePDClient(pdControl *pdapi.FakePDControl, tc *v1alpha1.TidbCluster) *
 	if tc.Spec.ClusterDomain != "" {
 		pdControl.SetPDClientWithClusterDomain(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.Spec.ClusterDomain, pdClient)
 	}
+	if tc.HasExternalPD() {
+		for _, endpoint := range tc.Spec.ExternalPD.Endpoints {
+			pdControl.SetPDClientForKey(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), endpoint, pdClient)
+		}
+	}
 	pdControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
 
 	return pdClient
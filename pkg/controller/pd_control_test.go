@@ -113,3 +113,32 @@ func TestGetPDClient(t *testing.T) {
 		testFn(&tests[i], t)
 	}
 }
+
+func TestGetExternalPDClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	tc.Spec.PD = nil
+	tc.Spec.ExternalPD = &v1alpha1.ExternalPDSpec{
+		Endpoints: []string{"http://pd-0.example.com:2379", "http://pd-1.example.com:2379"},
+	}
+	kubeCli := kubefake.NewSimpleClientset()
+	informer := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	pdControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
+
+	pdClient0 := pdapi.NewFakePDClient()
+	pdClient0.AddReaction(pdapi.GetHealthActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, fmt.Errorf("external PD pd-0 crashed")
+	})
+	pdControl.SetPDClientForKey(pdapi.Namespace(tc.Namespace), tc.Name, "http://pd-0.example.com:2379", pdClient0)
+	pdClient1 := pdapi.NewFakePDClient()
+	pdClient1.AddReaction(pdapi.GetHealthActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
+			{Name: "pd-1", MemberID: uint64(2), ClientUrls: []string{"http://pd-1.example.com:2379"}, Health: true},
+		}}, nil
+	})
+	pdControl.SetPDClientForKey(pdapi.Namespace(tc.Namespace), tc.Name, "http://pd-1.example.com:2379", pdClient1)
+
+	pdClient := GetPDClient(pdControl, tc)
+	g.Expect(pdClient).To(BeIdenticalTo(pdClient1))
+}
@@ -51,6 +51,8 @@ type realPodControl struct {
 	pdControl pdapi.PDControlInterface
 	podLister corelisters.PodLister
 	recorder  record.EventRecorder
+	// fencing rejects the pod deletions if the operator is no longer the leader
+	fencing *Fencing
 }
 
 // NewRealPodControl creates a new PodControlInterface
@@ -68,6 +70,24 @@ func NewRealPodControl(
 	}
 }
 
+// NewFencedPodControl creates a new PodControlInterface rejecting the pod deletions if the operator is no
+// longer the leader
+func NewFencedPodControl(
+	kubeCli kubernetes.Interface,
+	pdControl pdapi.PDControlInterface,
+	podLister corelisters.PodLister,
+	recorder record.EventRecorder,
+	fencing *Fencing,
+) PodControlInterface {
+	return &realPodControl{
+		kubeCli:   kubeCli,
+		pdControl: pdControl,
+		podLister: podLister,
+		recorder:  recorder,
+		fencing:   fencing,
+	}
+}
+
 func (c *realPodControl) UpdatePod(controller runtime.Object, pod *corev1.Pod) (*corev1.Pod, error) {
 	controllerMo, ok := controller.(metav1.Object)
 	if !ok {
@@ -247,6 +267,10 @@ func (c *realPodControl) deletePod(controller runtime.Object, pod *corev1.Pod, n
 	namespace := controllerMo.GetNamespace()
 
 	podName := pod.GetName()
+	if err := c.fencing.Check(); err != nil {
+		klog.Errorf("refuse to delete Pod: [%s/%s], %s: %s, %v", namespace, podName, kind, namespace, err)
+		return err
+	}
 	preconditions := metav1.Preconditions{UID: &pod.UID, ResourceVersion: &pod.ResourceVersion}
 	deleteOptions := metav1.DeleteOptions{Preconditions: &preconditions}
 	// If nonGraceful is false, then use default grace period
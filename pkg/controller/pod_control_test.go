@@ -429,3 +429,30 @@ func newPodSpec(volumeName, pvcName string) corev1.PodSpec {
 		},
 	}
 }
+
+func TestPodControlDeletePodFenced(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	pod := newPod(tc)
+	fakeClient, pdControl, podLister, _, recorder := newFakeClientRecorderAndPDControl()
+	leaderLock := newFakeLeaderLock(1)
+	fencing := NewFencing()
+	fencing.SetLock(leaderLock, 0)
+	control := NewFencedPodControl(fakeClient, pdControl, podLister, recorder, fencing)
+	deleted := 0
+	fakeClient.AddReactor("delete", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		deleted++
+		return true, nil, nil
+	})
+
+	// the deposed leader can't delete the pod
+	leaderLock.record.HolderIdentity = "operator-1"
+	err := control.DeletePod(tc, pod)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(deleted).To(Equal(0))
+
+	leaderLock.record.HolderIdentity = leaderLock.identity
+	err = control.DeletePod(tc, pod)
+	g.Expect(err).To(Succeed())
+	g.Expect(deleted).To(Equal(1))
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+
+	wq "k8s.io/client-go/util/workqueue"
+)
+
+// unfinishedWorkUpdatePeriod is how often the metrics of the unfinished work are updated,
+// the same as the work queue of client-go
+const unfinishedWorkUpdatePeriod = 500 * time.Millisecond
+
+// priorityQueue is a two-tier work queue, the items of the high priority tier are always
+// handed out before the items of the low priority tier. It keeps the guarantees of the
+// work queue of client-go:
+//   - an item is processed by only one worker at the same time
+//   - an item added several times before it is processed is processed only once
+type priorityQueue struct {
+	// isHighPriority is called when an item is queued to decide its tier
+	isHighPriority func(item interface{}) bool
+
+	cond *sync.Cond
+	high []interface{}
+	low  []interface{}
+	// dirty contains all items that need to be processed
+	dirty map[interface{}]struct{}
+	// processing contains all items that are currently being processed
+	processing map[interface{}]struct{}
+	metrics    *priorityQueueMetrics
+
+	shuttingDown bool
+	drain        bool
+}
+
+var _ wq.Interface = &priorityQueue{}
+
+// NewPriorityRateLimitingQueue returns a named rate limited work queue, in which the items
+// judged by isHighPriority are processed before the others. It can be used to make sure
+// the broken objects are not delayed by a resync of all the objects.
+// The delayed adds are handled by the delaying queue of client-go, so the pending adds of
+// the same item are merged, and the queue reports the same metrics as client-go.
+func NewPriorityRateLimitingQueue(rateLimiter wq.RateLimiter, name string, isHighPriority func(item interface{}) bool) wq.RateLimitingInterface {
+	q := newPriorityQueue(name, isHighPriority)
+	return wq.NewRateLimitingQueueWithConfig(rateLimiter, wq.RateLimitingQueueConfig{
+		DelayingQueue: wq.NewDelayingQueueWithConfig(wq.DelayingQueueConfig{
+			Name:            name,
+			MetricsProvider: metrics.WorkQueueMetricsProvider(),
+			Queue:           q,
+		}),
+	})
+}
+
+func newPriorityQueue(name string, isHighPriority func(item interface{}) bool) *priorityQueue {
+	q := &priorityQueue{
+		isHighPriority: isHighPriority,
+		cond:           sync.NewCond(&sync.Mutex{}),
+		dirty:          map[interface{}]struct{}{},
+		processing:     map[interface{}]struct{}{},
+		metrics:        newPriorityQueueMetrics(name, metrics.WorkQueueMetricsProvider()),
+	}
+	go q.updateUnfinishedWorkLoop()
+	return q
+}
+
+// Add marks item as needing processing.
+func (q *priorityQueue) Add(item interface{}) {
+	high := q.isHighPriority(item)
+
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if _, ok := q.dirty[item]; ok {
+		// the item may become high priority while it is waiting in the low priority tier
+		if high && q.removeLow(item) {
+			q.high = append(q.high, item)
+		}
+		return
+	}
+	q.metrics.add(item)
+	q.dirty[item] = struct{}{}
+	if _, ok := q.processing[item]; ok {
+		// it will be queued again when it's done
+		return
+	}
+	q.push(item, high)
+	q.cond.Signal()
+}
+
+func (q *priorityQueue) push(item interface{}, high bool) {
+	if high {
+		q.high = append(q.high, item)
+	} else {
+		q.low = append(q.low, item)
+	}
+}
+
+func (q *priorityQueue) removeLow(item interface{}) bool {
+	for i := range q.low {
+		if q.low[i] == item {
+			q.low = append(q.low[:i], q.low[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the current queue length, for informational purposes only.
+func (q *priorityQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.high) + len(q.low)
+}
+
+// Get blocks until it can return an item to be processed. The items of the high
+// priority tier are returned first.
+func (q *priorityQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.high) == 0 && len(q.low) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	var item interface{}
+	switch {
+	case len(q.high) > 0:
+		item, q.high = q.high[0], q.high[1:]
+	case len(q.low) > 0:
+		item, q.low = q.low[0], q.low[1:]
+	default:
+		// we must be shutting down
+		return nil, true
+	}
+	q.metrics.get(item)
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+	return item, false
+}
+
+// Done marks item as done processing, and if it has been marked as dirty again
+// while it was being processed, it will be re-added to the queue for re-processing.
+func (q *priorityQueue) Done(item interface{}) {
+	// decide the tier before taking the lock as isHighPriority may be slow
+	high := q.isHighPriority(item)
+
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.metrics.done(item)
+	delete(q.processing, item)
+	if _, ok := q.dirty[item]; ok {
+		q.push(item, high)
+		q.cond.Signal()
+	}
+	if len(q.processing) == 0 {
+		// wake up ShutDownWithDrain
+		q.cond.Broadcast()
+	}
+}
+
+// ShutDown will cause q to ignore all new items added to it and immediately
+// instruct the worker goroutines to exit.
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain will cause q to ignore all new items added to it and wait
+// for the items being processed to be done.
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+func (q *priorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func (q *priorityQueue) updateUnfinishedWorkLoop() {
+	t := time.NewTicker(unfinishedWorkUpdatePeriod)
+	defer t.Stop()
+	for range t.C {
+		if !func() bool {
+			q.cond.L.Lock()
+			defer q.cond.L.Unlock()
+			if q.shuttingDown {
+				return false
+			}
+			q.metrics.updateUnfinishedWork()
+			return true
+		}() {
+			return
+		}
+	}
+}
+
+// priorityQueueMetrics records the metrics of the work queue of client-go for the priority queue
+type priorityQueueMetrics struct {
+	depth                   wq.GaugeMetric
+	adds                    wq.CounterMetric
+	latency                 wq.HistogramMetric
+	workDuration            wq.HistogramMetric
+	unfinishedWorkSeconds   wq.SettableGaugeMetric
+	longestRunningProcessor wq.SettableGaugeMetric
+
+	addTimes             map[interface{}]time.Time
+	processingStartTimes map[interface{}]time.Time
+}
+
+func newPriorityQueueMetrics(name string, provider wq.MetricsProvider) *priorityQueueMetrics {
+	if name == "" {
+		return nil
+	}
+	return &priorityQueueMetrics{
+		depth:                   provider.NewDepthMetric(name),
+		adds:                    provider.NewAddsMetric(name),
+		latency:                 provider.NewLatencyMetric(name),
+		workDuration:            provider.NewWorkDurationMetric(name),
+		unfinishedWorkSeconds:   provider.NewUnfinishedWorkSecondsMetric(name),
+		longestRunningProcessor: provider.NewLongestRunningProcessorSecondsMetric(name),
+		addTimes:                map[interface{}]time.Time{},
+		processingStartTimes:    map[interface{}]time.Time{},
+	}
+}
+
+func (m *priorityQueueMetrics) add(item interface{}) {
+	if m == nil {
+		return
+	}
+	m.adds.Inc()
+	m.depth.Inc()
+	if _, ok := m.addTimes[item]; !ok {
+		m.addTimes[item] = time.Now()
+	}
+}
+
+func (m *priorityQueueMetrics) get(item interface{}) {
+	if m == nil {
+		return
+	}
+	m.depth.Dec()
+	m.processingStartTimes[item] = time.Now()
+	if startTime, ok := m.addTimes[item]; ok {
+		m.latency.Observe(time.Since(startTime).Seconds())
+		delete(m.addTimes, item)
+	}
+}
+
+func (m *priorityQueueMetrics) done(item interface{}) {
+	if m == nil {
+		return
+	}
+	if startTime, ok := m.processingStartTimes[item]; ok {
+		m.workDuration.Observe(time.Since(startTime).Seconds())
+		delete(m.processingStartTimes, item)
+	}
+}
+
+func (m *priorityQueueMetrics) updateUnfinishedWork() {
+	if m == nil {
+		return
+	}
+	var total, oldest float64
+	for _, startTime := range m.processingStartTimes {
+		age := time.Since(startTime).Seconds()
+		total += age
+		if age > oldest {
+			oldest = age
+		}
+	}
+	m.unfinishedWorkSeconds.Set(total)
+	m.longestRunningProcessor.Set(oldest)
+}
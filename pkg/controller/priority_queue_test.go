@@ -0,0 +1,107 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	wq "k8s.io/client-go/util/workqueue"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	highPriority := map[interface{}]bool{"degraded-1": true, "degraded-2": true}
+	q := NewPriorityRateLimitingQueue(wq.DefaultControllerRateLimiter(), "test", func(item interface{}) bool {
+		return highPriority[item]
+	})
+
+	q.Add("healthy-1")
+	q.Add("degraded-1")
+	q.Add("healthy-2")
+	q.Add("healthy-1")
+	q.Add("degraded-2")
+	g.Expect(q.Len()).To(Equal(4))
+
+	get := func() interface{} {
+		item, shutdown := q.Get()
+		g.Expect(shutdown).To(BeFalse())
+		return item
+	}
+
+	// high priority items are handed out first
+	g.Expect(get()).To(Equal("degraded-1"))
+	g.Expect(get()).To(Equal("degraded-2"))
+
+	// an item in the low priority tier is promoted when it becomes high priority
+	highPriority["healthy-2"] = true
+	q.Add("healthy-2")
+	g.Expect(q.Len()).To(Equal(2))
+	g.Expect(get()).To(Equal("healthy-2"))
+
+	// an item being processed is queued again only after it's done
+	q.Add("degraded-1")
+	g.Expect(q.Len()).To(Equal(1))
+	q.Done("degraded-1")
+	g.Expect(q.Len()).To(Equal(2))
+	g.Expect(get()).To(Equal("degraded-1"))
+	g.Expect(get()).To(Equal("healthy-1"))
+	g.Expect(q.Len()).To(Equal(0))
+
+	q.AddAfter("healthy-3", 10*time.Millisecond)
+	g.Eventually(q.Len).Should(Equal(1))
+	g.Expect(get()).To(Equal("healthy-3"))
+
+	for _, item := range []string{"degraded-1", "degraded-2", "healthy-1", "healthy-2", "healthy-3"} {
+		q.Done(item)
+	}
+	q.ShutDownWithDrain()
+	g.Expect(q.ShuttingDown()).To(BeTrue())
+	_, shutdown := q.Get()
+	g.Expect(shutdown).To(BeTrue())
+	q.Add("healthy-1")
+	g.Expect(q.Len()).To(Equal(0))
+
+	q = NewPriorityRateLimitingQueue(wq.DefaultControllerRateLimiter(), "test", func(item interface{}) bool {
+		return false
+	})
+	q.AddRateLimited("healthy-1")
+	g.Expect(q.NumRequeues("healthy-1")).To(Equal(1))
+	g.Eventually(q.Len).Should(Equal(1))
+	q.Forget("healthy-1")
+	g.Expect(q.NumRequeues("healthy-1")).To(Equal(0))
+}
+
+func TestPriorityQueueMergeAddAfter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	q := NewPriorityRateLimitingQueue(wq.DefaultControllerRateLimiter(), "", func(item interface{}) bool {
+		return false
+	})
+	defer q.ShutDown()
+
+	// the pending adds of the same item are merged, and the earliest one wins
+	for i := 0; i < 3; i++ {
+		q.AddAfter("healthy-1", 200*time.Millisecond)
+	}
+	q.AddAfter("healthy-1", 10*time.Millisecond)
+	g.Eventually(q.Len).Should(Equal(1))
+	item, shutdown := q.Get()
+	g.Expect(shutdown).To(BeFalse())
+	g.Expect(item).To(Equal("healthy-1"))
+	q.Done(item)
+	g.Consistently(q.Len, 400*time.Millisecond).Should(Equal(0))
+}
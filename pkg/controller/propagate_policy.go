@@ -0,0 +1,177 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reservedKeyPrefixes are the prefixes of the label and annotation keys that are never propagated,
+// as they are used by Kubernetes and the operator to select and manage the resources
+var reservedKeyPrefixes = []string{
+	"app.kubernetes.io/",
+	"kubectl.kubernetes.io/",
+	"pingcap.com/",
+	"tidb.pingcap.com/",
+}
+
+// PropagateMeta sets the labels and annotations of the tidb cluster selected by spec.propagatePolicy
+// to obj, and removes the ones propagated to obj before but not selected any more. It returns whether
+// obj is changed.
+func PropagateMeta(tc *v1alpha1.TidbCluster, obj metav1.Object) bool {
+	var labelKeys, annKeys []string
+	if policy := tc.Spec.PropagatePolicy; policy != nil {
+		labelKeys, annKeys = policy.Labels, policy.Annotations
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labelsChanged := propagate(selectKeys(tc.GetLabels(), labelKeys), labels, annotations, label.AnnPropagatedLabelsKey)
+	annsChanged := propagate(selectKeys(tc.GetAnnotations(), annKeys), annotations, annotations, label.AnnPropagatedAnnotationsKey)
+	if !labelsChanged && !annsChanged {
+		return false
+	}
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// RetainSyncedMeta copies the labels and annotations synced to oldObj by the meta manager to newObj,
+// i.e. the ones propagated from the tidb cluster and the Velero label. It's used when newObj is built
+// from the spec of the tidb cluster to update oldObj, so that they are not removed by the update.
+func RetainSyncedMeta(newObj, oldObj metav1.Object) {
+	RetainPropagatedMeta(newObj, oldObj)
+	if v, ok := oldObj.GetLabels()[label.VeleroExcludeFromBackupLabelKey]; ok {
+		labels := newObj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		if _, ok := labels[label.VeleroExcludeFromBackupLabelKey]; !ok {
+			labels[label.VeleroExcludeFromBackupLabelKey] = v
+			newObj.SetLabels(labels)
+		}
+	}
+}
+
+// RetainPropagatedMeta copies the labels and annotations propagated to oldObj to newObj, it's used
+// when newObj is built from the spec of the tidb cluster to update oldObj, so that the propagated
+// ones are not removed by the update.
+func RetainPropagatedMeta(newObj, oldObj metav1.Object) {
+	oldAnns := oldObj.GetAnnotations()
+	retain := func(m map[string]string, old map[string]string, recordKey string) map[string]string {
+		record, ok := oldAnns[recordKey]
+		if !ok {
+			return m
+		}
+		if m == nil {
+			m = map[string]string{}
+		}
+		for _, k := range splitKeys(record) {
+			if _, ok := m[k]; !ok {
+				if v, ok := old[k]; ok {
+					m[k] = v
+				}
+			}
+		}
+		return m
+	}
+	newObj.SetLabels(retain(newObj.GetLabels(), oldObj.GetLabels(), label.AnnPropagatedLabelsKey))
+	anns := retain(newObj.GetAnnotations(), oldAnns, label.AnnPropagatedAnnotationsKey)
+	for _, k := range []string{label.AnnPropagatedLabelsKey, label.AnnPropagatedAnnotationsKey} {
+		if v, ok := oldAnns[k]; ok {
+			if anns == nil {
+				anns = map[string]string{}
+			}
+			anns[k] = v
+		}
+	}
+	newObj.SetAnnotations(anns)
+}
+
+// propagate sets desired to target and removes the keys recorded in annotations[recordKey] but not
+// desired, then records the keys of desired. It returns whether target or the record is changed.
+func propagate(desired, target, annotations map[string]string, recordKey string) bool {
+	changed := false
+	for _, k := range splitKeys(annotations[recordKey]) {
+		if _, ok := desired[k]; ok || isReservedKey(k) {
+			continue
+		}
+		if _, ok := target[k]; ok {
+			delete(target, k)
+			changed = true
+		}
+	}
+	keys := make([]string, 0, len(desired))
+	for k, v := range desired {
+		keys = append(keys, k)
+		if old, ok := target[k]; !ok || old != v {
+			target[k] = v
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+	record := strings.Join(keys, ",")
+	if old, ok := annotations[recordKey]; ok && record == "" {
+		delete(annotations, recordKey)
+		changed = true
+	} else if record != "" && old != record {
+		annotations[recordKey] = record
+		changed = true
+	}
+	return changed
+}
+
+// selectKeys returns the items of m whose keys are selected by patterns
+func selectKeys(m map[string]string, patterns []string) map[string]string {
+	selected := map[string]string{}
+	for k, v := range m {
+		if isReservedKey(k) {
+			continue
+		}
+		for _, p := range patterns {
+			if k == p || strings.HasSuffix(p, "*") && strings.HasPrefix(k, strings.TrimSuffix(p, "*")) {
+				selected[k] = v
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func isReservedKey(k string) bool {
+	for _, prefix := range reservedKeyPrefixes {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitKeys(record string) []string {
+	if record == "" {
+		return nil
+	}
+	return strings.Split(record, ",")
+}
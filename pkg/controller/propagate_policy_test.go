@@ -0,0 +1,142 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPropagateMeta(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+			Labels: map[string]string{
+				"team":                       "db",
+				"cost.example.com/center":    "1001",
+				"cost.example.com/owner":     "alice",
+				"env":                        "prod",
+				label.InstanceLabelKey:       "other",
+				"tidb.pingcap.com/something": "x",
+			},
+			Annotations: map[string]string{
+				"note": "hello",
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				label.InstanceLabelKey: "test",
+				"env":                  "dev",
+			},
+		},
+	}
+
+	// nothing is propagated without the policy
+	g.Expect(PropagateMeta(tc, pod)).To(BeFalse())
+
+	// the selected keys are propagated and recorded, the reserved keys are never propagated
+	tc.Spec.PropagatePolicy = &v1alpha1.PropagatePolicy{
+		Labels:      []string{"team", "cost.example.com/*", label.InstanceLabelKey, "tidb.pingcap.com/*"},
+		Annotations: []string{"note"},
+	}
+	g.Expect(PropagateMeta(tc, pod)).To(BeTrue())
+	g.Expect(pod.Labels).To(Equal(map[string]string{
+		label.InstanceLabelKey:    "test",
+		"env":                     "dev",
+		"team":                    "db",
+		"cost.example.com/center": "1001",
+		"cost.example.com/owner":  "alice",
+	}))
+	g.Expect(pod.Annotations).To(Equal(map[string]string{
+		"note":                            "hello",
+		label.AnnPropagatedLabelsKey:      "cost.example.com/center,cost.example.com/owner,team",
+		label.AnnPropagatedAnnotationsKey: "note",
+	}))
+	g.Expect(PropagateMeta(tc, pod)).To(BeFalse())
+
+	// the changed values are propagated, and the removed keys are removed
+	tc.Labels["team"] = "infra"
+	delete(tc.Labels, "cost.example.com/owner")
+	g.Expect(PropagateMeta(tc, pod)).To(BeTrue())
+	g.Expect(pod.Labels["team"]).To(Equal("infra"))
+	g.Expect(pod.Labels).NotTo(HaveKey("cost.example.com/owner"))
+	g.Expect(pod.Annotations[label.AnnPropagatedLabelsKey]).To(Equal("cost.example.com/center,team"))
+
+	// all the propagated keys are removed with the policy, the others are kept
+	tc.Spec.PropagatePolicy = nil
+	g.Expect(PropagateMeta(tc, pod)).To(BeTrue())
+	g.Expect(pod.Labels).To(Equal(map[string]string{
+		label.InstanceLabelKey: "test",
+		"env":                  "dev",
+	}))
+	g.Expect(pod.Annotations).To(BeEmpty())
+}
+
+func TestRetainPropagatedMeta(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	oldSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"team": "db", "app": "old"},
+			Annotations: map[string]string{
+				"note":                            "hello",
+				"other":                           "old",
+				label.AnnPropagatedLabelsKey:      "team",
+				label.AnnPropagatedAnnotationsKey: "note",
+			},
+		},
+	}
+	newSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "new"},
+		},
+	}
+	RetainPropagatedMeta(newSvc, oldSvc)
+	g.Expect(newSvc.Labels).To(Equal(map[string]string{"team": "db", "app": "new"}))
+	g.Expect(newSvc.Annotations).To(Equal(map[string]string{
+		"note":                            "hello",
+		label.AnnPropagatedLabelsKey:      "team",
+		label.AnnPropagatedAnnotationsKey: "note",
+	}))
+
+	// nothing is retained if nothing is propagated
+	newSvc = &corev1.Service{}
+	RetainPropagatedMeta(newSvc, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "db"}}})
+	g.Expect(newSvc.Labels).To(BeNil())
+	g.Expect(newSvc.Annotations).To(BeNil())
+}
+
+func TestRetainSyncedMeta(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	oldCm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"team": "db", label.VeleroExcludeFromBackupLabelKey: "true"},
+			Annotations: map[string]string{label.AnnPropagatedLabelsKey: "team"},
+		},
+	}
+	newCm := &corev1.ConfigMap{}
+	RetainSyncedMeta(newCm, oldCm)
+	g.Expect(newCm.Labels).To(Equal(oldCm.Labels))
+	g.Expect(newCm.Annotations).To(Equal(oldCm.Annotations))
+}
@@ -0,0 +1,379 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pumpmigration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/third_party/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+// sinkURIKey is the key of the sink URI in the secret of a changefeed
+const sinkURIKey = "sinkURI"
+
+// ControlInterface abstracts the business logic for PumpMigration reconciliation.
+type ControlInterface interface {
+	Reconcile(*v1alpha1.PumpMigration) error
+}
+
+// NewPumpMigrationControl returns a new instance of ControlInterface
+func NewPumpMigrationControl(deps *controller.Dependencies) ControlInterface {
+	return &defaultPumpMigrationControl{
+		deps: deps,
+	}
+}
+
+type defaultPumpMigrationControl struct {
+	deps *controller.Dependencies
+}
+
+func (c *defaultPumpMigrationControl) Reconcile(pm *v1alpha1.PumpMigration) error {
+	if pm.DeletionTimestamp != nil {
+		return nil
+	}
+	if !c.validate(pm) {
+		return nil
+	}
+	if pm.Status.Phase == v1alpha1.PumpMigrationComplete {
+		return nil
+	}
+
+	oldStatus := pm.Status.DeepCopy()
+	if pm.Status.Phase == "" {
+		pm.Status.Phase = v1alpha1.PumpMigrationPending
+	}
+
+	err := c.sync(pm)
+
+	if !apiequality.Semantic.DeepEqual(&pm.Status, oldStatus) {
+		if _, updateErr := c.updateStatus(pm.DeepCopy()); updateErr != nil {
+			return updateErr
+		}
+	}
+	return err
+}
+
+// sync migrates the cluster step by step, each step returns a requeue error until it's done:
+// provision TiCDC, create the changefeeds and wait for them to catch up, disable the binlog
+// of TiDB, and then decommission the pumps.
+func (c *defaultPumpMigrationControl) sync(pm *v1alpha1.PumpMigration) error {
+	tc, err := c.deps.TiDBClusterLister.TidbClusters(pm.Namespace).Get(pm.Spec.Cluster.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			setPhase(pm, v1alpha1.PumpMigrationFailed, fmt.Sprintf("cluster %s/%s is not found", pm.Namespace, pm.Spec.Cluster.Name))
+			return nil
+		}
+		return fmt.Errorf("pump migration %s/%s get cluster failed: %v", pm.Namespace, pm.Name, err)
+	}
+
+	if ok, err := c.syncTiCDC(pm, tc); !ok || err != nil {
+		return err
+	}
+	if ok, err := c.syncChangefeeds(pm, tc); !ok || err != nil {
+		return err
+	}
+	if !pm.Spec.DecommissionPump {
+		setPhase(pm, v1alpha1.PumpMigrationCaughtUp, "the changefeeds have caught up, set decommissionPump to decommission the pumps")
+		return nil
+	}
+	if err := c.disableBinlog(pm, tc); err != nil {
+		return err
+	}
+	if err := c.decommissionPump(pm, tc); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	pm.Status.CompleteTime = &now
+	msg := fmt.Sprintf("cluster %s/%s is migrated to TiCDC", tc.Namespace, tc.Name)
+	setPhase(pm, v1alpha1.PumpMigrationComplete, msg)
+	c.deps.Recorder.Event(pm, corev1.EventTypeNormal, "Migrated", msg)
+	return nil
+}
+
+// syncTiCDC provisions TiCDC for the cluster with the spec of the migration if the cluster
+// has no TiCDC, it returns true once all the TiCDC captures are ready.
+func (c *defaultPumpMigrationControl) syncTiCDC(pm *v1alpha1.PumpMigration, tc *v1alpha1.TidbCluster) (bool, error) {
+	if tc.Spec.TiCDC == nil {
+		if pm.Spec.TiCDC == nil {
+			setPhase(pm, v1alpha1.PumpMigrationFailed, fmt.Sprintf("cluster %s/%s has no TiCDC and spec.ticdc is not specified", tc.Namespace, tc.Name))
+			return false, nil
+		}
+		newTC := tc.DeepCopy()
+		newTC.Spec.TiCDC = pm.Spec.TiCDC.DeepCopy()
+		if _, err := c.deps.TiDBClusterControl.Update(newTC); err != nil {
+			return false, fmt.Errorf("pump migration %s/%s provision TiCDC failed: %v", pm.Namespace, pm.Name, err)
+		}
+		klog.Infof("pump migration %s/%s provisioned TiCDC for cluster %s", pm.Namespace, pm.Name, tc.Name)
+		setPhase(pm, v1alpha1.PumpMigrationProvisioningTiCDC, "waiting for the TiCDC captures to be ready")
+		return false, controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	if !tc.TiCDCAllCapturesReady() {
+		setPhase(pm, v1alpha1.PumpMigrationProvisioningTiCDC, "waiting for the TiCDC captures to be ready")
+		return false, controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	return true, nil
+}
+
+// syncChangefeeds creates the changefeeds and records their status, it returns true once
+// all the changefeeds have caught up.
+func (c *defaultPumpMigrationControl) syncChangefeeds(pm *v1alpha1.PumpMigration, tc *v1alpha1.TidbCluster) (bool, error) {
+	var (
+		statuses []v1alpha1.PumpMigrationChangefeedStatus
+		created  []string
+		maxLag   time.Duration
+		caughtUp = true
+	)
+	for _, cf := range pm.Spec.Changefeeds {
+		info, err := c.deps.CDCControl.GetChangefeed(tc, cf.ID)
+		if err != nil {
+			return false, fmt.Errorf("pump migration %s/%s get changefeed %s failed: %v", pm.Namespace, pm.Name, cf.ID, err)
+		}
+		if info == nil {
+			sinkURI, err := c.sinkURI(pm, cf)
+			if err != nil {
+				return false, err
+			}
+			if err := c.deps.CDCControl.CreateChangefeed(tc, cf.ID, sinkURI, cf.StartTs); err != nil {
+				return false, fmt.Errorf("pump migration %s/%s create changefeed %s failed: %v", pm.Namespace, pm.Name, cf.ID, err)
+			}
+			klog.Infof("pump migration %s/%s created changefeed %s", pm.Namespace, pm.Name, cf.ID)
+			created = append(created, cf.ID)
+			statuses = append(statuses, v1alpha1.PumpMigrationChangefeedStatus{ID: cf.ID})
+			continue
+		}
+
+		status := v1alpha1.PumpMigrationChangefeedStatus{ID: cf.ID, State: info.State}
+		if info.State == "failed" || info.State == "error" {
+			msg := fmt.Sprintf("changefeed %s is %s", cf.ID, info.State)
+			if info.Error != nil {
+				msg = fmt.Sprintf("%s: %s", msg, info.Error.Message)
+			}
+			pm.Status.Changefeeds = append(statuses, status)
+			setPhase(pm, v1alpha1.PumpMigrationFailed, msg)
+			return false, nil
+		}
+		if info.CheckpointTSO == 0 {
+			caughtUp = false
+		} else {
+			checkpoint := config.TSToGoTime(info.CheckpointTSO)
+			lag := time.Since(checkpoint).Truncate(time.Second)
+			if lag < 0 {
+				lag = 0
+			}
+			t := metav1.NewTime(checkpoint)
+			status.CheckpointTime = &t
+			status.Lag = lag.String()
+			if lag > maxLag {
+				maxLag = lag
+			}
+			if lag > pm.MaxCatchUpLag() {
+				caughtUp = false
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	pm.Status.Changefeeds = statuses
+	pm.Status.Lag = maxLag.String()
+	if len(created) > 0 {
+		setPhase(pm, v1alpha1.PumpMigrationCatchingUp, fmt.Sprintf("waiting for changefeeds %v to start", created))
+		return false, controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	// the lag may fluctuate after the changefeeds have caught up, which doesn't block the migration
+	if pm.Status.CaughtUpTime != nil {
+		return true, nil
+	}
+	if !caughtUp {
+		setPhase(pm, v1alpha1.PumpMigrationCatchingUp, fmt.Sprintf("waiting for the lag of the changefeeds to be within %s", pm.MaxCatchUpLag()))
+		return false, controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	now := metav1.Now()
+	pm.Status.CaughtUpTime = &now
+	c.deps.Recorder.Event(pm, corev1.EventTypeNormal, "CaughtUp", "the changefeeds have caught up")
+	return true, nil
+}
+
+// disableBinlog disables the binlog of TiDB and waits for the TiDB pods to be restarted
+func (c *defaultPumpMigrationControl) disableBinlog(pm *v1alpha1.PumpMigration, tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiDB == nil {
+		return nil
+	}
+	if tc.IsTiDBBinlogEnabled() {
+		// the time is saved before the binlog is disabled, otherwise the TiDB pods restarted with
+		// binlog disabled can't be told from the old ones if the status fails to be saved after
+		now := metav1.Now()
+		pm.Status.BinlogDisabledTime = &now
+		setPhase(pm, v1alpha1.PumpMigrationDisablingBinlog, "waiting for the TiDB pods to be restarted with binlog disabled")
+		updated, err := c.updateStatus(pm.DeepCopy())
+		if err != nil {
+			return err
+		}
+		pm.ResourceVersion = updated.ResourceVersion
+
+		newTC := tc.DeepCopy()
+		newTC.Spec.TiDB.BinlogEnabled = pointer.BoolPtr(false)
+		if _, err := c.deps.TiDBClusterControl.Update(newTC); err != nil {
+			return fmt.Errorf("pump migration %s/%s disable binlog failed: %v", pm.Namespace, pm.Name, err)
+		}
+		klog.Infof("pump migration %s/%s disabled the binlog of cluster %s", pm.Namespace, pm.Name, tc.Name)
+		return controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	if pm.Status.BinlogDisabledTime == nil {
+		// the binlog has been disabled before the migration
+		return nil
+	}
+
+	// the pumps can't be decommissioned until no TiDB writes binlog, otherwise the writes fail
+	selector, err := label.New().Instance(tc.Name).TiDB().Selector()
+	if err != nil {
+		return err
+	}
+	pods, err := c.deps.PodLister.Pods(tc.Namespace).List(selector)
+	if err != nil {
+		return fmt.Errorf("pump migration %s/%s list TiDB pods failed: %v", pm.Namespace, pm.Name, err)
+	}
+	restarted := 0
+	for _, pod := range pods {
+		if pod.CreationTimestamp.After(pm.Status.BinlogDisabledTime.Time) && k8s.IsPodReady(pod) {
+			restarted++
+		}
+	}
+	if restarted != len(pods) || int32(restarted) < tc.TiDBStsDesiredReplicas() {
+		setPhase(pm, v1alpha1.PumpMigrationDisablingBinlog, fmt.Sprintf("waiting for the TiDB pods to be restarted with binlog disabled, %d/%d restarted", restarted, len(pods)))
+		return controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	return nil
+}
+
+// decommissionPump scales in the pumps to 0, the pumps are made offline one by one by the pump scaler
+func (c *defaultPumpMigrationControl) decommissionPump(pm *v1alpha1.PumpMigration, tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Pump == nil {
+		return nil
+	}
+	if tc.Spec.Pump.Replicas != 0 {
+		newTC := tc.DeepCopy()
+		newTC.Spec.Pump.Replicas = 0
+		if _, err := c.deps.TiDBClusterControl.Update(newTC); err != nil {
+			return fmt.Errorf("pump migration %s/%s scale in pumps failed: %v", pm.Namespace, pm.Name, err)
+		}
+		klog.Infof("pump migration %s/%s scaled in the pumps of cluster %s", pm.Namespace, pm.Name, tc.Name)
+		setPhase(pm, v1alpha1.PumpMigrationDecommissioningPump, "waiting for the pumps to be offline")
+		return controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	if sts := tc.Status.Pump.StatefulSet; sts != nil && sts.Replicas != 0 {
+		setPhase(pm, v1alpha1.PumpMigrationDecommissioningPump, fmt.Sprintf("waiting for the pumps to be offline, %d left", sts.Replicas))
+		return controller.RequeueErrorf("pump migration %s/%s: %s", pm.Namespace, pm.Name, pm.Status.Message)
+	}
+	return nil
+}
+
+// sinkURI returns the URI of the downstream of the changefeed
+func (c *defaultPumpMigrationControl) sinkURI(pm *v1alpha1.PumpMigration, cf v1alpha1.PumpMigrationChangefeed) (string, error) {
+	if cf.SinkURISecretName == "" {
+		return cf.SinkURI, nil
+	}
+	secret, err := c.deps.SecretLister.Secrets(pm.Namespace).Get(cf.SinkURISecretName)
+	if err != nil {
+		return "", fmt.Errorf("pump migration %s/%s get secret %s failed: %v", pm.Namespace, pm.Name, cf.SinkURISecretName, err)
+	}
+	uri, ok := secret.Data[sinkURIKey]
+	if !ok {
+		return "", fmt.Errorf("pump migration %s/%s: secret %s has no key %s", pm.Namespace, pm.Name, cf.SinkURISecretName, sinkURIKey)
+	}
+	return string(uri), nil
+}
+
+func (c *defaultPumpMigrationControl) updateStatus(pm *v1alpha1.PumpMigration) (*v1alpha1.PumpMigration, error) {
+	var (
+		ns     = pm.GetNamespace()
+		name   = pm.GetName()
+		status = pm.Status.DeepCopy()
+		update *v1alpha1.PumpMigration
+	)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var updateErr error
+		update, updateErr = c.deps.Clientset.PingcapV1alpha1().PumpMigrations(ns).UpdateStatus(context.TODO(), pm, metav1.UpdateOptions{})
+		if updateErr == nil {
+			klog.Infof("PumpMigration: [%s/%s], update status successfully", ns, name)
+			return nil
+		}
+
+		klog.V(4).Infof("PumpMigration: [%s/%s], update status failed, error: %v", ns, name, updateErr)
+
+		// If failed to update status, then:
+		// get the latest PumpMigration, override the status to local newest, prepare for next update.
+		if updated, err := c.deps.PumpMigrationLister.PumpMigrations(ns).Get(name); err == nil {
+			pm = updated.DeepCopy()
+			pm.Status = *status
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated PumpMigration %s/%s from lister: %v", ns, name, err))
+		}
+
+		return updateErr
+	})
+	if err != nil {
+		klog.Errorf("PumpMigration: [%s/%s], failed to updateStatus, error: %v", ns, name, err)
+	}
+
+	return update, err
+}
+
+func (c *defaultPumpMigrationControl) validate(pm *v1alpha1.PumpMigration) bool {
+	errs := v1alpha1validation.ValidatePumpMigration(pm)
+	if len(errs) > 0 {
+		aggregatedErr := errs.ToAggregate()
+		klog.Errorf("pump migration %s/%s is not valid and must be fixed first, aggregated error: %v", pm.GetNamespace(), pm.GetName(), aggregatedErr)
+		c.deps.Recorder.Event(pm, corev1.EventTypeWarning, "FailedValidation", aggregatedErr.Error())
+		return false
+	}
+	return true
+}
+
+func setPhase(pm *v1alpha1.PumpMigration, phase v1alpha1.PumpMigrationPhase, message string) {
+	pm.Status.Phase = phase
+	pm.Status.Message = message
+}
+
+type FakePumpMigrationControl struct {
+	reconcile func(*v1alpha1.PumpMigration) error
+}
+
+func (c *FakePumpMigrationControl) MockReconcile(reconcile func(*v1alpha1.PumpMigration) error) {
+	c.reconcile = reconcile
+}
+
+func (c *FakePumpMigrationControl) Reconcile(pm *v1alpha1.PumpMigration) error {
+	if c.reconcile != nil {
+		return c.reconcile(pm)
+	}
+	return nil
+}
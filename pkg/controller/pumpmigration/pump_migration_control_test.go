@@ -0,0 +1,208 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pumpmigration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPumpMigration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	control := NewPumpMigrationControl(deps)
+	cdcControl := deps.CDCControl.(*controller.FakeTiCDCControl)
+	tcIndexer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+
+	g.Expect(tcIndexer.Add(&v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+			TiDB: &v1alpha1.TiDBSpec{Replicas: 1},
+			Pump: &v1alpha1.PumpSpec{Replicas: 1},
+		},
+	})).To(Succeed())
+	pm := newPumpMigration(g, deps)
+	pm.Spec.TiCDC = &v1alpha1.TiCDCSpec{Replicas: 1}
+	getTC := func() *v1alpha1.TidbCluster {
+		tc, err := deps.TiDBClusterLister.TidbClusters(corev1.NamespaceDefault).Get("cluster")
+		g.Expect(err).To(Succeed())
+		return tc.DeepCopy()
+	}
+
+	var info *controller.ChangefeedInfo
+	var sinkURI string
+	var startTs uint64
+	cdcControl.GetChangefeedFn = func(tc *v1alpha1.TidbCluster, id string) (*controller.ChangefeedInfo, error) {
+		return info, nil
+	}
+	cdcControl.CreateChangefeedFn = func(tc *v1alpha1.TidbCluster, id, uri string, ts uint64) error {
+		sinkURI = uri
+		startTs = ts
+		return nil
+	}
+
+	// TiCDC is provisioned for the cluster
+	err := control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationProvisioningTiCDC))
+	tc := getTC()
+	g.Expect(tc.Spec.TiCDC).NotTo(BeNil())
+
+	// the changefeeds are created once the captures are ready
+	tc.Status.TiCDC.Captures = map[string]v1alpha1.TiCDCCapture{"capture-0": {Ready: true}}
+	g.Expect(tcIndexer.Update(tc)).To(Succeed())
+	err = control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationCatchingUp))
+	g.Expect(sinkURI).To(Equal("mysql://root:@downstream:4000/"))
+	g.Expect(startTs).To(Equal(uint64(100)))
+
+	// the migration waits for the changefeeds to catch up
+	info = &controller.ChangefeedInfo{ID: "cf", State: "normal", CheckpointTSO: config.GoTimeToTS(time.Now().Add(-time.Minute))}
+	err = control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationCatchingUp))
+	g.Expect(pm.Status.Lag).To(Equal("1m0s"))
+
+	info.CheckpointTSO = config.GoTimeToTS(time.Now())
+	g.Expect(control.Reconcile(pm)).To(Succeed())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationCaughtUp))
+	g.Expect(pm.Status.CaughtUpTime).NotTo(BeNil())
+	g.Expect(getTC().IsTiDBBinlogEnabled()).To(BeTrue())
+
+	// the time the binlog is disabled is saved before the cluster is updated
+	pm.Spec.DecommissionPump = true
+	deps.TiDBClusterControl.(*controller.FakeTidbClusterControl).SetUpdateTidbClusterError(errors.New("API server down"), 0)
+	err = control.Reconcile(pm)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(controller.IsRequeueError(err)).To(BeFalse())
+	saved, err := deps.Clientset.PingcapV1alpha1().PumpMigrations(pm.Namespace).Get(context.TODO(), pm.Name, metav1.GetOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(saved.Status.BinlogDisabledTime).NotTo(BeNil())
+	g.Expect(getTC().IsTiDBBinlogEnabled()).To(BeTrue())
+
+	// the binlog is disabled and the migration waits for the TiDB pods to be restarted
+	err = control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationDisablingBinlog))
+	g.Expect(pm.Status.BinlogDisabledTime).NotTo(BeNil())
+	g.Expect(getTC().IsTiDBBinlogEnabled()).To(BeFalse())
+
+	err = control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationDisablingBinlog))
+
+	g.Expect(deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer().Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster-tidb-0",
+			Namespace:         corev1.NamespaceDefault,
+			Labels:            label.New().Instance("cluster").TiDB().Labels(),
+			CreationTimestamp: metav1.NewTime(time.Now().Add(time.Second)),
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	})).To(Succeed())
+
+	// the pumps are decommissioned after the TiDB pods are restarted
+	err = control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationDecommissioningPump))
+	tc = getTC()
+	g.Expect(tc.Spec.Pump.Replicas).To(BeZero())
+
+	tc.Status.Pump.StatefulSet = &appsv1.StatefulSetStatus{Replicas: 1}
+	g.Expect(tcIndexer.Update(tc)).To(Succeed())
+	err = control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationDecommissioningPump))
+
+	tc.Status.Pump.StatefulSet.Replicas = 0
+	g.Expect(tcIndexer.Update(tc)).To(Succeed())
+	g.Expect(control.Reconcile(pm)).To(Succeed())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationComplete))
+	g.Expect(pm.Status.CompleteTime).NotTo(BeNil())
+
+	// nothing is done after the migration is complete
+	cdcControl.GetChangefeedFn = nil
+	g.Expect(control.Reconcile(pm)).To(Succeed())
+}
+
+func TestPumpMigrationFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	control := NewPumpMigrationControl(deps)
+	cdcControl := deps.CDCControl.(*controller.FakeTiCDCControl)
+
+	// the cluster is not found
+	pm := newPumpMigration(g, deps)
+	g.Expect(control.Reconcile(pm)).To(Succeed())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationFailed))
+
+	// the cluster has no TiCDC and spec.ticdc is not specified
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(&v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+			TiDB: &v1alpha1.TiDBSpec{},
+			Pump: &v1alpha1.PumpSpec{},
+		},
+	})).To(Succeed())
+	g.Expect(control.Reconcile(pm)).To(Succeed())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationFailed))
+
+	// the changefeed is failed
+	pm.Spec.TiCDC = &v1alpha1.TiCDCSpec{}
+	err := control.Reconcile(pm)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationProvisioningTiCDC))
+	cdcControl.GetChangefeedFn = func(tc *v1alpha1.TidbCluster, id string) (*controller.ChangefeedInfo, error) {
+		return &controller.ChangefeedInfo{ID: id, State: "failed"}, nil
+	}
+	g.Expect(control.Reconcile(pm)).To(Succeed())
+	g.Expect(pm.Status.Phase).To(Equal(v1alpha1.PumpMigrationFailed))
+	g.Expect(pm.Status.Message).To(Equal("changefeed cf is failed"))
+}
+
+func newPumpMigration(g *GomegaWithT, deps *controller.Dependencies) *v1alpha1.PumpMigration {
+	pm := &v1alpha1.PumpMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.PumpMigrationSpec{
+			Cluster: v1alpha1.TidbClusterRef{Name: "cluster"},
+			Changefeeds: []v1alpha1.PumpMigrationChangefeed{
+				{ID: "cf", SinkURI: "mysql://root:@downstream:4000/", StartTs: 100},
+			},
+		},
+	}
+	_, err := deps.Clientset.PingcapV1alpha1().PumpMigrations(pm.Namespace).Create(context.TODO(), pm, metav1.CreateOptions{})
+	g.Expect(err).To(Succeed())
+	return pm
+}
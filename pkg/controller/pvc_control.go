@@ -47,6 +47,8 @@ type realPVCControl struct {
 	kubeCli   kubernetes.Interface
 	recorder  record.EventRecorder
 	pvcLister corelisters.PersistentVolumeClaimLister
+	// fencing rejects the PVC deletions if the operator is no longer the leader
+	fencing *Fencing
 }
 
 // NewRealPVCControl creates a new PVCControlInterface
@@ -61,6 +63,21 @@ func NewRealPVCControl(
 	}
 }
 
+// NewFencedPVCControl creates a new PVCControlInterface rejecting the PVC deletions if the operator is no
+// longer the leader
+func NewFencedPVCControl(
+	kubeCli kubernetes.Interface,
+	recorder record.EventRecorder,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	fencing *Fencing) PVCControlInterface {
+	return &realPVCControl{
+		kubeCli:   kubeCli,
+		recorder:  recorder,
+		pvcLister: pvcLister,
+		fencing:   fencing,
+	}
+}
+
 func (c *realPVCControl) GetPVC(name, namespace string) (*corev1.PersistentVolumeClaim, error) {
 	return c.pvcLister.PersistentVolumeClaims(namespace).Get(name)
 }
@@ -75,6 +92,10 @@ func (c *realPVCControl) DeletePVC(controller runtime.Object, pvc *corev1.Persis
 	namespace := controllerMo.GetNamespace()
 
 	pvcName := pvc.GetName()
+	if err := c.fencing.Check(); err != nil {
+		klog.Errorf("refuse to delete PVC: [%s/%s], %s: %s, %v", namespace, pvcName, kind, name, err)
+		return err
+	}
 	err := c.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), pvcName, metav1.DeleteOptions{})
 	if err != nil {
 		klog.Errorf("failed to delete PVC: [%s/%s], %s: %s, %v", namespace, pvcName, kind, name, err)
@@ -0,0 +1,63 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Requeuer requeues the objects to the queues of their controllers after a duration, it's used
+// by the managers to sync an object again at the time they are waiting for, e.g. a scheduled
+// rolling restart, instead of waiting for the next resync.
+type Requeuer struct {
+	lock sync.RWMutex
+	// addAfters are the AddAfter functions of the queues of the controllers by the kinds of the objects
+	addAfters map[string]func(item interface{}, duration time.Duration)
+}
+
+// NewRequeuer returns a Requeuer
+func NewRequeuer() *Requeuer {
+	return &Requeuer{
+		addAfters: map[string]func(item interface{}, duration time.Duration){},
+	}
+}
+
+// Register registers the AddAfter function of the queue of the controller of the kind
+func (r *Requeuer) Register(kind string, addAfter func(item interface{}, duration time.Duration)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.addAfters[kind] = addAfter
+}
+
+// RequeueAfter adds the key of the object of the kind to the queue of its controller after the duration,
+// it does nothing if the controller of the kind is not registered
+func (r *Requeuer) RequeueAfter(kind string, obj metav1.Object, duration time.Duration) {
+	r.lock.RLock()
+	addAfter, ok := r.addAfters[kind]
+	r.lock.RUnlock()
+	if !ok {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Warningf("failed to get the key of %s %s/%s to requeue: %v", kind, obj.GetNamespace(), obj.GetName(), err)
+		return
+	}
+	addAfter(key, duration)
+}
@@ -14,10 +14,13 @@
 package restore
 
 import (
+	"context"
+
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup"
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -46,7 +49,10 @@ type defaultRestoreControl struct {
 var _ ControlInterface = &defaultRestoreControl{}
 
 // UpdateRestore executes the core logic loop for a Restore.
-func (c *defaultRestoreControl) UpdateRestore(restore *v1alpha1.Restore) error {
+func (c *defaultRestoreControl) UpdateRestore(restore *v1alpha1.Restore) (err error) {
+	_, span := tracing.StartObject(context.Background(), "Restore.Reconcile", restore.GetNamespace(), restore.GetName())
+	defer func() { tracing.End(span, err) }()
+
 	restore.SetGroupVersionKind(controller.RestoreControllerKind)
 	return c.restoreManager.Sync(restore)
 }
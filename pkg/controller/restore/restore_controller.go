@@ -57,6 +57,11 @@ func NewController(deps *controller.Dependencies) *Controller {
 	restoreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: c.updateRestore,
 		UpdateFunc: func(old, cur interface{}) {
+			if oldRestore, ok := old.(*v1alpha1.Restore); ok {
+				if curRestore, ok := cur.(*v1alpha1.Restore); ok {
+					deps.BRNotifier.NotifyRestore(oldRestore, curRestore)
+				}
+			}
 			c.updateRestore(cur)
 		},
 		DeleteFunc: c.enqueueRestore,
@@ -22,6 +22,8 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
 	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
@@ -44,8 +46,14 @@ type RestoreUpdateStatus struct {
 	ProgressStep *string
 	// Progress is the step's progress value.
 	Progress *float64
+	// ProgressSpeed is the step's speed reported by BR.
+	ProgressSpeed *string
 	// ProgressUpdateTime is the progress update time.
 	ProgressUpdateTime *metav1.Time
+	// IntegrityCheck is the result of the data integrity check.
+	IntegrityCheck *v1alpha1.RestoreIntegrityCheckStatus
+	// VersionCompatibility is the result of the version compatibility check.
+	VersionCompatibility *v1alpha1.RestoreVersionCompatibility
 }
 
 // RestoreConditionUpdaterInterface enables updating Restore conditions.
@@ -86,12 +94,16 @@ func (u *realRestoreConditionUpdater) Update(restore *v1alpha1.Restore, conditio
 			utilruntime.HandleError(fmt.Errorf("error getting updated restore %s/%s from lister: %v", ns, restoreName, err))
 			return err
 		}
+		isNewStep := newStatus != nil && isNewBRProgressStep(restore.Status.Progresses, newStatus.ProgressStep)
 		isStatusUpdate = updateRestoreStatus(&restore.Status, newStatus)
 		isConditionUpdate = v1alpha1.UpdateRestoreCondition(&restore.Status, condition)
 		if isStatusUpdate || isConditionUpdate {
 			_, updateErr := u.cli.PingcapV1alpha1().Restores(ns).Update(context.TODO(), restore, metav1.UpdateOptions{})
 			if updateErr == nil {
 				klog.Infof("Restore: [%s/%s] updated successfully", ns, restoreName)
+				if isNewStep {
+					u.recorder.Eventf(restore, corev1.EventTypeNormal, BRProgressStepStarted, "BR step %q started", *newStatus.ProgressStep)
+				}
 				return nil
 			}
 			klog.Errorf("Failed to update restore [%s/%s], error: %v", ns, restoreName, updateErr)
@@ -123,12 +135,20 @@ func updateRestoreStatus(status *v1alpha1.RestoreStatus, newStatus *RestoreUpdat
 		isUpdate = true
 	}
 	if newStatus.ProgressStep != nil {
-		progresses, updated := updateBRProgress(status.Progresses, newStatus.ProgressStep, newStatus.Progress, newStatus.ProgressUpdateTime)
+		progresses, updated := updateBRProgress(status.Progresses, newStatus.ProgressStep, newStatus.Progress, newStatus.ProgressSpeed, newStatus.ProgressUpdateTime)
 		if updated {
 			status.Progresses = progresses
 			isUpdate = true
 		}
 	}
+	if newStatus.IntegrityCheck != nil && !apiequality.Semantic.DeepEqual(status.IntegrityCheck, newStatus.IntegrityCheck) {
+		status.IntegrityCheck = newStatus.IntegrityCheck
+		isUpdate = true
+	}
+	if newStatus.VersionCompatibility != nil && !apiequality.Semantic.DeepEqual(status.VersionCompatibility, newStatus.VersionCompatibility) {
+		status.VersionCompatibility = newStatus.VersionCompatibility
+		isUpdate = true
+	}
 
 	return isUpdate
 }
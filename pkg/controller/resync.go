@@ -0,0 +1,89 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// ParseResyncDurations parses the resync durations of the CRD kinds in the format of
+// `<kind>=<duration>[,<kind>=<duration>...]`, e.g. TidbCluster=1m,TidbMonitor=10m,
+// into the custom resync config of the informer factory
+func ParseResyncDurations(s string) (map[metav1.Object]time.Duration, error) {
+	config := map[metav1.Object]time.Duration{}
+	if strings.TrimSpace(s) == "" {
+		return config, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		kind, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid resync duration %q, must be <kind>=<duration>", item)
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			return nil, fmt.Errorf("invalid resync duration %q of %s", value, kind)
+		}
+		obj, err := scheme.Scheme.New(v1alpha1.SchemeGroupVersion.WithKind(kind))
+		if err != nil {
+			return nil, fmt.Errorf("unknown kind %s in the resync durations: %v", kind, err)
+		}
+		metaObj, ok := obj.(metav1.Object)
+		if !ok {
+			return nil, fmt.Errorf("kind %s in the resync durations is not an object", kind)
+		}
+		config[metaObj] = duration
+	}
+	return config, nil
+}
+
+// ResyncDurationOf returns the resync duration of the object overridden by the annotation
+// tidb.pingcap.com/resync-duration, false is returned if it's not overridden
+func ResyncDurationOf(obj metav1.Object) (time.Duration, bool) {
+	value, ok := obj.GetAnnotations()[label.AnnResyncDurationKey]
+	if !ok {
+		return 0, false
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		klog.Warningf("%s/%s: invalid annotation %s=%s, ignore it", obj.GetNamespace(), obj.GetName(), label.AnnResyncDurationKey, value)
+		return 0, false
+	}
+	return duration, true
+}
+
+// IsOverriddenResync returns whether the update event is a resync of the informer for an object whose
+// resync duration is overridden, such events are ignored as the object is resynced by its controller
+func IsOverriddenResync(old, cur interface{}) bool {
+	oldObj, ok := old.(metav1.Object)
+	if !ok {
+		return false
+	}
+	curObj, ok := cur.(metav1.Object)
+	if !ok {
+		return false
+	}
+	if oldObj.GetResourceVersion() != curObj.GetResourceVersion() {
+		return false
+	}
+	_, ok = ResyncDurationOf(curObj)
+	return ok
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseResyncDurations(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	config, err := ParseResyncDurations("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config).To(BeEmpty())
+
+	config, err = ParseResyncDurations("TidbCluster=1m, TidbMonitor=10m")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config).To(HaveLen(2))
+	durations := map[string]time.Duration{}
+	for obj, d := range config {
+		switch obj.(type) {
+		case *v1alpha1.TidbCluster:
+			durations["TidbCluster"] = d
+		case *v1alpha1.TidbMonitor:
+			durations["TidbMonitor"] = d
+		}
+	}
+	g.Expect(durations).To(Equal(map[string]time.Duration{
+		"TidbCluster": time.Minute,
+		"TidbMonitor": 10 * time.Minute,
+	}))
+
+	for _, s := range []string{
+		"TidbCluster",
+		"TidbCluster=abc",
+		"TidbCluster=-1m",
+		"Foo=1m",
+	} {
+		_, err = ParseResyncDurations(s)
+		g.Expect(err).To(HaveOccurred(), s)
+	}
+}
+
+func TestResyncDurationOf(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	_, ok := ResyncDurationOf(tc)
+	g.Expect(ok).To(BeFalse())
+
+	for value, expected := range map[string]time.Duration{
+		"30s": 30 * time.Second,
+		"abc": 0,
+		"0s":  0,
+	} {
+		tc.Annotations = map[string]string{label.AnnResyncDurationKey: value}
+		d, ok := ResyncDurationOf(tc)
+		g.Expect(ok).To(Equal(expected > 0), value)
+		g.Expect(d).To(Equal(expected), value)
+	}
+}
+
+func TestIsOverriddenResync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newTC := func(rv string, annotations map[string]string) *v1alpha1.TidbCluster {
+		return &v1alpha1.TidbCluster{
+			ObjectMeta: metav1.ObjectMeta{ResourceVersion: rv, Annotations: annotations},
+		}
+	}
+	overridden := map[string]string{label.AnnResyncDurationKey: "10m"}
+
+	g.Expect(IsOverriddenResync(newTC("1", overridden), newTC("1", overridden))).To(BeTrue())
+	g.Expect(IsOverriddenResync(newTC("1", overridden), newTC("2", overridden))).To(BeFalse())
+	g.Expect(IsOverriddenResync(newTC("1", nil), newTC("1", nil))).To(BeFalse())
+	g.Expect(IsOverriddenResync("foo", newTC("1", overridden))).To(BeFalse())
+}
@@ -142,6 +142,8 @@ func (c *realServiceControl) SyncComponentService(tc runtime.Object, newSvc, old
 	if newSvc == nil || oldSvc == nil {
 		return nil, fmt.Errorf("ServiceAnnLabelEqual: newservice or oldService is nil")
 	}
+	// the labels and annotations synced by the meta manager are not in the new service
+	RetainSyncedMeta(newSvc, oldSvc)
 	if newSvc.Annotations == nil {
 		newSvc.Annotations = map[string]string{}
 	}
@@ -272,8 +274,14 @@ func (c *FakeServiceControl) SyncComponentService(tc runtime.Object, svc *corev1
 }
 
 // DeleteService deletes the service of SvcIndexer
-func (c *FakeServiceControl) DeleteService(_ runtime.Object, _ *corev1.Service) error {
-	return nil
+func (c *FakeServiceControl) DeleteService(_ runtime.Object, svc *corev1.Service) error {
+	defer c.deleteStatefulSetTracker.Inc()
+	if c.deleteStatefulSetTracker.ErrorReady() {
+		defer c.deleteStatefulSetTracker.Reset()
+		return c.deleteStatefulSetTracker.GetError()
+	}
+
+	return c.SvcIndexer.Delete(svc)
 }
 
 var _ ServiceControlInterface = &FakeServiceControl{}
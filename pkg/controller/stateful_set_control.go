@@ -44,11 +44,20 @@ type realStatefulSetControl struct {
 	kubeCli   kubernetes.Interface
 	setLister appslisters.StatefulSetLister
 	recorder  record.EventRecorder
+	// fencing stamps the fencing token of the operator leader into the updated statefulsets, and rejects the
+	// deletions if the operator is no longer the leader
+	fencing *Fencing
 }
 
 // NewRealStatefuSetControl returns a StatefulSetControlInterface
 func NewRealStatefuSetControl(kubeCli kubernetes.Interface, setLister appslisters.StatefulSetLister, recorder record.EventRecorder) StatefulSetControlInterface {
-	return &realStatefulSetControl{kubeCli, setLister, recorder}
+	return &realStatefulSetControl{kubeCli: kubeCli, setLister: setLister, recorder: recorder}
+}
+
+// NewFencedStatefulSetControl returns a StatefulSetControlInterface rejecting the updates of the statefulsets
+// stamped by a newer leader of the operator
+func NewFencedStatefulSetControl(kubeCli kubernetes.Interface, setLister appslisters.StatefulSetLister, recorder record.EventRecorder, fencing *Fencing) StatefulSetControlInterface {
+	return &realStatefulSetControl{kubeCli: kubeCli, setLister: setLister, recorder: recorder, fencing: fencing}
 }
 
 // CreateStatefulSet create a StatefulSet for a controller
@@ -88,6 +97,15 @@ func (c *realStatefulSetControl) UpdateStatefulSet(controller runtime.Object, se
 
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		// TODO: verify if StatefulSet identity(name, namespace, labels) matches TidbCluster
+		if c.fencing != nil {
+			var current metav1.Object = set
+			if cached, err := c.setLister.StatefulSets(namespace).Get(setName); err == nil {
+				current = cached
+			}
+			if err := c.fencing.Stamp(set, current); err != nil {
+				return err
+			}
+		}
 		var updateErr error
 		updatedSS, updateErr = c.kubeCli.AppsV1().StatefulSets(namespace).Update(context.TODO(), set, metav1.UpdateOptions{})
 		if updateErr == nil {
@@ -121,6 +139,9 @@ func (c *realStatefulSetControl) DeleteStatefulSet(controller runtime.Object, se
 	name := controllerMo.GetName()
 	namespace := controllerMo.GetNamespace()
 
+	if err := c.fencing.Check(); err != nil {
+		return err
+	}
 	err := c.kubeCli.AppsV1().StatefulSets(namespace).Delete(context.TODO(), set.Name, opts)
 	c.recordStatefulSetEvent("delete", kind, name, controller, set, err)
 	return err
@@ -19,6 +19,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -154,6 +155,40 @@ func TestStatefulSetControlUpdateStatefulSetConflictSuccess(t *testing.T) {
 	g.Expect(updateSS.Labels).To(Equal(set.Labels))
 }
 
+func TestStatefulSetControlUpdateStatefulSetFenced(t *testing.T) {
+	g := NewGomegaWithT(t)
+	recorder := record.NewFakeRecorder(10)
+	tc := newTidbCluster()
+	set := newStatefulSet(tc, "pd")
+	fakeClient := &fake.Clientset{}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	oldSet := newStatefulSet(tc, "pd")
+	oldSet.Annotations = map[string]string{
+		label.AnnFencingTokenKey: "3",
+	}
+	err := indexer.Add(oldSet)
+	g.Expect(err).To(Succeed())
+	setLister := appslisters.NewStatefulSetLister(indexer)
+	fencing := NewFencing()
+	control := NewFencedStatefulSetControl(fakeClient, setLister, recorder, fencing)
+	fakeClient.AddReactor("update", "statefulsets", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		return true, update.GetObject(), nil
+	})
+
+	// the deposed leader can't update the statefulset stamped by the new leader
+	leaderLock := newFakeLeaderLock(1)
+	fencing.SetLock(leaderLock, 0)
+	_, err = control.UpdateStatefulSet(tc, set.DeepCopy())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("newer leader"))
+
+	leaderLock.record.LeaderTransitions = 2
+	updateSS, err := control.UpdateStatefulSet(tc, set.DeepCopy())
+	g.Expect(err).To(Succeed())
+	g.Expect(updateSS.Annotations).To(HaveKeyWithValue(label.AnnFencingTokenKey, "3"))
+}
+
 func TestStatefulSetControlDeleteStatefulSet(t *testing.T) {
 	g := NewGomegaWithT(t)
 	recorder := record.NewFakeRecorder(10)
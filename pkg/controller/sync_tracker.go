@@ -0,0 +1,250 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// requeueHintsKey is the key of the requeue hints in the data of the ConfigMap
+	requeueHintsKey = "keys"
+	// drainPollInterval is the interval to check whether all in-flight syncs finish
+	drainPollInterval = 100 * time.Millisecond
+)
+
+// SyncTracker tracks the in-flight syncs of the controllers to shut down the operator
+// gracefully. After draining starts, no new sync is started, and the in-flight syncs
+// are waited for a bounded time, so that an operation like scaling in a TiKV store is
+// not interrupted in the middle.
+//
+// It also records the requeue hints, i.e. the keys not synced successfully and the keys
+// still queued when draining starts, which are persisted when the operator exits and
+// synced first by the next leader.
+type SyncTracker struct {
+	lock     sync.Mutex
+	draining bool
+	// inFlight contains the keys being synced
+	inFlight map[string]struct{}
+	// hints contains the keys that need to be synced again
+	hints map[string]struct{}
+	// queues contains the work queues of the controllers, which are shut down when draining starts
+	queues map[string]workqueue.Interface
+}
+
+// NewSyncTracker returns a SyncTracker
+func NewSyncTracker() *SyncTracker {
+	return &SyncTracker{
+		inFlight: map[string]struct{}{},
+		hints:    map[string]struct{}{},
+		queues:   map[string]workqueue.Interface{},
+	}
+}
+
+// RegisterQueue registers the work queue of the controller, the keys still queued when
+// draining starts are recorded as requeue hints
+func (t *SyncTracker) RegisterQueue(controller string, queue workqueue.Interface) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.queues[controller] = queue
+}
+
+func trackerKey(controller, key string) string {
+	return controller + "/" + key
+}
+
+// StartSync marks the key of the controller as being synced. It returns false if the
+// tracker is draining, and the key is recorded as a requeue hint in that case.
+func (t *SyncTracker) StartSync(controller, key string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	k := trackerKey(controller, key)
+	if t.draining {
+		t.hints[k] = struct{}{}
+		return false
+	}
+	t.inFlight[k] = struct{}{}
+	return true
+}
+
+// FinishSync marks the sync of the key of the controller as finished. The key is kept
+// as a requeue hint until it's synced successfully.
+func (t *SyncTracker) FinishSync(controller, key string, needRequeue bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	k := trackerKey(controller, key)
+	delete(t.inFlight, k)
+	if needRequeue {
+		t.hints[k] = struct{}{}
+	} else {
+		delete(t.hints, k)
+	}
+}
+
+// IsHinted returns whether the key of the controller is a requeue hint
+func (t *SyncTracker) IsHinted(controller, key string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, ok := t.hints[trackerKey(controller, key)]
+	return ok
+}
+
+// AddHints adds the requeue hints, which are usually loaded from the last run
+func (t *SyncTracker) AddHints(hints []string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, k := range hints {
+		t.hints[k] = struct{}{}
+	}
+}
+
+// PendingKeys returns the keys being synced and the requeue hints
+func (t *SyncTracker) PendingKeys() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	keys := make([]string, 0, len(t.inFlight)+len(t.hints))
+	for k := range t.hints {
+		keys = append(keys, k)
+	}
+	for k := range t.inFlight {
+		if _, ok := t.hints[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Drain stops starting new syncs and waits at most timeout for the in-flight syncs
+// to finish. It returns false if some syncs are still in flight after timeout.
+//
+// The registered queues are shut down, and the keys still queued are recorded as
+// requeue hints, so the keys returned by PendingKeys after Drain include them.
+func (t *SyncTracker) Drain(timeout time.Duration) bool {
+	t.lock.Lock()
+	t.draining = true
+	queues := make(map[string]workqueue.Interface, len(t.queues))
+	for controller, queue := range t.queues {
+		queues[controller] = queue
+	}
+	t.lock.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for controller, queue := range queues {
+			t.drainQueue(controller, queue)
+		}
+	}()
+
+	err := wait.PollImmediate(drainPollInterval, timeout, func() (bool, error) {
+		select {
+		case <-drained:
+		default:
+			return false, nil
+		}
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return len(t.inFlight) == 0, nil
+	})
+	return err == nil
+}
+
+// drainQueue shuts down the queue and records the keys still queued as requeue hints.
+// The keys got by the workers concurrently are recorded by StartSync, so it waits for
+// the workers to be done with them.
+func (t *SyncTracker) drainQueue(controller string, queue workqueue.Interface) {
+	queue.ShutDown()
+	for {
+		for {
+			key, quit := queue.Get()
+			if quit {
+				break
+			}
+			if k, ok := key.(string); ok {
+				t.lock.Lock()
+				t.hints[trackerKey(controller, k)] = struct{}{}
+				t.lock.Unlock()
+			}
+			queue.Done(key)
+		}
+		queue.ShutDownWithDrain()
+		// the keys added while being synced are queued again when the workers are done with them
+		if queue.Len() == 0 {
+			return
+		}
+	}
+}
+
+// SaveRequeueHints persists the requeue hints into the ConfigMap ns/name
+func SaveRequeueHints(ctx context.Context, kubeCli kubernetes.Interface, ns, name string, hints []string) error {
+	cm, err := kubeCli.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Data:       map[string]string{requeueHintsKey: strings.Join(hints, "\n")},
+		}
+		_, err = kubeCli.CoreV1().ConfigMaps(ns).Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[requeueHintsKey] = strings.Join(hints, "\n")
+	_, err = kubeCli.CoreV1().ConfigMaps(ns).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// DeleteRequeueHints deletes the ConfigMap ns/name persisting the requeue hints,
+// so the stale hints are not loaded after the requeue hints are enabled again.
+func DeleteRequeueHints(ctx context.Context, kubeCli kubernetes.Interface, ns, name string) error {
+	err := kubeCli.CoreV1().ConfigMaps(ns).Delete(ctx, name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// LoadRequeueHints loads the requeue hints persisted in the ConfigMap ns/name, it
+// returns nothing if the ConfigMap doesn't exist.
+func LoadRequeueHints(ctx context.Context, kubeCli kubernetes.Interface, ns, name string) ([]string, error) {
+	cm, err := kubeCli.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var hints []string
+	for _, k := range strings.Split(cm.Data[requeueHintsKey], "\n") {
+		if k = strings.TrimSpace(k); k != "" {
+			hints = append(hints, k)
+		}
+	}
+	return hints, nil
+}
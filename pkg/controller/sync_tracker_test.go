@@ -0,0 +1,119 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestSyncTracker(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tracker := NewSyncTracker()
+	g.Expect(tracker.StartSync("tidbcluster", "ns/tc1")).To(BeTrue())
+	g.Expect(tracker.StartSync("tidbcluster", "ns/tc2")).To(BeTrue())
+	tracker.FinishSync("tidbcluster", "ns/tc2", true)
+	g.Expect(tracker.IsHinted("tidbcluster", "ns/tc2")).To(BeTrue())
+	g.Expect(tracker.IsHinted("tidbcluster", "ns/tc1")).To(BeFalse())
+	g.Expect(tracker.PendingKeys()).To(Equal([]string{"tidbcluster/ns/tc1", "tidbcluster/ns/tc2"}))
+
+	// the hint is removed after the key is synced successfully
+	g.Expect(tracker.StartSync("tidbcluster", "ns/tc2")).To(BeTrue())
+	tracker.FinishSync("tidbcluster", "ns/tc2", false)
+	g.Expect(tracker.IsHinted("tidbcluster", "ns/tc2")).To(BeFalse())
+
+	// draining times out as tc1 is still in flight
+	g.Expect(tracker.Drain(200 * time.Millisecond)).To(BeFalse())
+	// no new sync is started after draining starts
+	g.Expect(tracker.StartSync("tidbcluster", "ns/tc3")).To(BeFalse())
+	g.Expect(tracker.IsHinted("tidbcluster", "ns/tc3")).To(BeTrue())
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		tracker.FinishSync("tidbcluster", "ns/tc1", false)
+	}()
+	g.Expect(tracker.Drain(5 * time.Second)).To(BeTrue())
+	g.Expect(tracker.PendingKeys()).To(Equal([]string{"tidbcluster/ns/tc3"}))
+}
+
+func TestSyncTrackerDrainQueue(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tracker := NewSyncTracker()
+	queue := workqueue.New()
+	tracker.RegisterQueue("tidbcluster", queue)
+	g.Expect(tracker.StartSync("tidbcluster", "ns/tc1")).To(BeTrue())
+	queue.Add("ns/tc3")
+	queue.Add("ns/tc2")
+	queue.Add("ns/tc4")
+
+	// a worker gets a key concurrently and is rejected as draining starts
+	key, quit := queue.Get()
+	g.Expect(quit).To(BeFalse())
+	g.Expect(key).To(Equal("ns/tc3"))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		g.Expect(tracker.StartSync("tidbcluster", key.(string))).To(BeFalse())
+		queue.Done(key)
+		tracker.FinishSync("tidbcluster", "ns/tc1", false)
+	}()
+
+	g.Expect(tracker.Drain(5 * time.Second)).To(BeTrue())
+	g.Expect(queue.ShuttingDown()).To(BeTrue())
+	g.Expect(queue.Len()).To(BeZero())
+	// the keys still queued and the key got by the worker are recorded, the synced key is not
+	g.Expect(tracker.PendingKeys()).To(Equal([]string{"tidbcluster/ns/tc2", "tidbcluster/ns/tc3", "tidbcluster/ns/tc4"}))
+
+	// no key is queued after the queue is shut down
+	queue.Add("ns/tc5")
+	g.Expect(tracker.PendingKeys()).To(Equal([]string{"tidbcluster/ns/tc2", "tidbcluster/ns/tc3", "tidbcluster/ns/tc4"}))
+}
+
+func TestRequeueHints(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kubeCli := kubefake.NewSimpleClientset()
+	ctx := context.Background()
+
+	hints, err := LoadRequeueHints(ctx, kubeCli, "ns", "hints")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hints).To(BeEmpty())
+
+	g.Expect(SaveRequeueHints(ctx, kubeCli, "ns", "hints", []string{"tidbcluster/ns/tc1", "tidbcluster/ns/tc2"})).To(Succeed())
+	hints, err = LoadRequeueHints(ctx, kubeCli, "ns", "hints")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hints).To(Equal([]string{"tidbcluster/ns/tc1", "tidbcluster/ns/tc2"}))
+
+	g.Expect(SaveRequeueHints(ctx, kubeCli, "ns", "hints", nil)).To(Succeed())
+	hints, err = LoadRequeueHints(ctx, kubeCli, "ns", "hints")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hints).To(BeEmpty())
+
+	g.Expect(DeleteRequeueHints(ctx, kubeCli, "ns", "hints")).To(Succeed())
+	_, err = kubeCli.CoreV1().ConfigMaps("ns").Get(ctx, "hints", metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+	g.Expect(DeleteRequeueHints(ctx, kubeCli, "ns", "hints")).To(Succeed())
+
+	tracker := NewSyncTracker()
+	tracker.AddHints([]string{"tidbcluster/ns/tc1"})
+	g.Expect(tracker.IsHinted("tidbcluster", "ns/tc1")).To(BeTrue())
+}
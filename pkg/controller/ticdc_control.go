@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -49,6 +50,32 @@ type drainCaptureResp struct {
 	CurrentTableCount int `json:"current_table_count"`
 }
 
+// errChangefeedNotExists is the error code returned by TiCDC when a changefeed doesn't exist
+const errChangefeedNotExists = "ErrChangeFeedNotExists"
+
+// ChangefeedInfo is the information of a changefeed returned by the TiCDC open API
+type ChangefeedInfo struct {
+	ID             string           `json:"id"`
+	State          string           `json:"state"`
+	CheckpointTSO  uint64           `json:"checkpoint_tso"`
+	CheckpointTime string           `json:"checkpoint_time"`
+	Error          *ChangefeedError `json:"error,omitempty"`
+}
+
+// ChangefeedError is the error of a changefeed
+type ChangefeedError struct {
+	Addr    string `json:"addr"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// createChangefeedRequest is request for `CreateChangefeed`
+type createChangefeedRequest struct {
+	ChangefeedID string `json:"changefeed_id"`
+	SinkURI      string `json:"sink_uri"`
+	StartTs      uint64 `json:"start_ts,omitempty"`
+}
+
 // TiCDCControlInterface is the interface that knows how to manage ticdc captures
 type TiCDCControlInterface interface {
 	// GetStatus returns ticdc's status
@@ -62,9 +89,21 @@ type TiCDCControlInterface interface {
 	// otherwise caller should retry resign owner.
 	// If there is only one capture, it always return true.
 	ResignOwner(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error)
+	// GetOwner returns the ordinal of the owner capture, which is queried from the capture of the ordinal.
+	// Returns false if the owner is not found or is not a capture of the cluster,
+	// e.g. the TiCDC doesn't support the API or the owner is being elected.
+	GetOwner(tc *v1alpha1.TidbCluster, ordinal int32) (ownerOrdinal int32, found bool, err error)
 	// IsHealthy gets the healthy status of TiCDC cluster.
 	// Returns true if the TiCDC cluster is heathy.
 	IsHealthy(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error)
+	// GetChangefeed returns the changefeed with the ID.
+	// Returns nil if the changefeed doesn't exist.
+	GetChangefeed(tc *v1alpha1.TidbCluster, id string) (*ChangefeedInfo, error)
+	// CreateChangefeed creates a changefeed that replicates the data to the sink from the start ts,
+	// it starts from the current TSO if startTs is 0.
+	CreateChangefeed(tc *v1alpha1.TidbCluster, id, sinkURI string, startTs uint64) error
+	// RemoveChangefeed removes the changefeed with the ID.
+	RemoveChangefeed(tc *v1alpha1.TidbCluster, id string) error
 }
 
 // defaultTiCDCControl is default implementation of TiCDCControlInterface.
@@ -223,6 +262,30 @@ func (c *defaultTiCDCControl) ResignOwner(tc *v1alpha1.TidbCluster, ordinal int3
 	return false, nil
 }
 
+func (c *defaultTiCDCControl) GetOwner(tc *v1alpha1.TidbCluster, ordinal int32) (int32, bool, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return 0, false, err
+	}
+
+	captures, _, err := getCaptures(httpClient, c.getBaseURL(tc, ordinal))
+	if err != nil {
+		return 0, false, err
+	}
+	_, owner := getOrdinalAndOwnerCaptureInfo(tc, ordinal, captures)
+	if owner == nil {
+		return 0, false, nil
+	}
+	// the advertise address of the capture starts with ${POD_NAME}
+	hostName := strings.SplitN(owner.AdvertiseAddr, ".", 2)[0]
+	ownerOrdinal, err := strconv.ParseInt(strings.TrimPrefix(hostName, TiCDCMemberName(tc.GetName())+"-"), 10, 32)
+	if err != nil || !strings.Contains(owner.AdvertiseAddr, getCaptureAdvertiseAddressPrefix(tc, int32(ownerOrdinal))) {
+		// the owner is a capture of another cluster
+		return 0, false, nil
+	}
+	return int32(ownerOrdinal), true, nil
+}
+
 func (c *defaultTiCDCControl) IsHealthy(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
 	httpClient, err := c.getHTTPClient(tc)
 	if err != nil {
@@ -269,6 +332,83 @@ func (c *defaultTiCDCControl) IsHealthy(tc *v1alpha1.TidbCluster, ordinal int32)
 	return true, nil
 }
 
+func (c *defaultTiCDCControl) GetChangefeed(tc *v1alpha1.TidbCluster, id string) (*ChangefeedInfo, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	// the request is forwarded to the owner by any capture
+	res, err := httpClient.Get(fmt.Sprintf("%s/api/v2/changefeeds/%s", c.getBaseURL(tc, 0), id))
+	if err != nil {
+		return nil, fmt.Errorf("ticdc get changefeed %s failed, request error: %v", id, err)
+	}
+	defer httputil.DeferClose(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ticdc get changefeed %s failed, read response error: %v", id, err)
+	}
+	if res.StatusCode >= 400 {
+		if strings.Contains(string(body), errChangefeedNotExists) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ticdc get changefeed %s failed, error response %s:%v", id, string(body), res.StatusCode)
+	}
+
+	info := &ChangefeedInfo{}
+	if err := json.Unmarshal(body, info); err != nil {
+		return nil, fmt.Errorf("ticdc get changefeed %s failed, unmarshal response error: %v", id, err)
+	}
+	return info, nil
+}
+
+func (c *defaultTiCDCControl) CreateChangefeed(tc *v1alpha1.TidbCluster, id, sinkURI string, startTs uint64) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	payloadBody, err := json.Marshal(createChangefeedRequest{ChangefeedID: id, SinkURI: sinkURI, StartTs: startTs})
+	if err != nil {
+		return fmt.Errorf("ticdc create changefeed %s failed, marshal request error: %v", id, err)
+	}
+	res, err := httpClient.Post(c.getBaseURL(tc, 0)+"/api/v2/changefeeds", "application/json", bytes.NewReader(payloadBody))
+	if err != nil {
+		return fmt.Errorf("ticdc create changefeed %s failed, request error: %v", id, err)
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode >= 400 {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("ticdc create changefeed %s failed, error response %s:%v", id, string(body), res.StatusCode)
+	}
+	return nil
+}
+
+func (c *defaultTiCDCControl) RemoveChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/v2/changefeeds/%s", c.getBaseURL(tc, 0), id), nil)
+	if err != nil {
+		return fmt.Errorf("ticdc remove changefeed %s failed, new request error: %v", id, err)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("ticdc remove changefeed %s failed, request error: %v", id, err)
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode >= 400 {
+		body, _ := io.ReadAll(res.Body)
+		if strings.Contains(string(body), errChangefeedNotExists) {
+			return nil
+		}
+		return fmt.Errorf("ticdc remove changefeed %s failed, error response %s:%v", id, string(body), res.StatusCode)
+	}
+	return nil
+}
+
 func (c *defaultTiCDCControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32) string {
 	if c.testURL != "" {
 		return c.testURL
@@ -287,6 +427,10 @@ func getCaptureAdvertiseAddressPrefix(tc *v1alpha1.TidbCluster, ordinal int32) s
 	ns := tc.GetNamespace()
 	hostName := fmt.Sprintf("%s-%d", TiCDCMemberName(tcName), ordinal)
 
+	if tc.Spec.AcrossK8sResolver != nil {
+		// the address is resolved by the resolver of the cluster deployed across Kubernetes clusters
+		return PodAdvertiseHost(tc, hostName, TiCDCPeerMemberName(tcName))
+	}
 	prefix := fmt.Sprintf("%s.%s.%s", hostName, TiCDCPeerMemberName(tcName), ns)
 	if len(tc.Spec.ClusterDomain) > 0 {
 		// When setting up TiCDC across multiple Kubernetes clusters,
@@ -347,6 +491,11 @@ type FakeTiCDCControl struct {
 	DrainCaptureFn func(tc *v1alpha1.TidbCluster, ordinal int32) (tableCount int, retry bool, err error)
 	ResignOwnerFn  func(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error)
 	IsHealthyFn    func(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error)
+	GetOwnerFn     func(tc *v1alpha1.TidbCluster, ordinal int32) (ownerOrdinal int32, found bool, err error)
+
+	GetChangefeedFn    func(tc *v1alpha1.TidbCluster, id string) (*ChangefeedInfo, error)
+	CreateChangefeedFn func(tc *v1alpha1.TidbCluster, id, sinkURI string, startTs uint64) error
+	RemoveChangefeedFn func(tc *v1alpha1.TidbCluster, id string) error
 }
 
 // NewFakeTiCDCControl returns a FakeTiCDCControl instance
@@ -381,3 +530,31 @@ func (c *FakeTiCDCControl) IsHealthy(tc *v1alpha1.TidbCluster, ordinal int32) (b
 	}
 	return c.IsHealthyFn(tc, ordinal)
 }
+
+func (c *FakeTiCDCControl) GetOwner(tc *v1alpha1.TidbCluster, ordinal int32) (int32, bool, error) {
+	if c.GetOwnerFn == nil {
+		return 0, false, fmt.Errorf("undefined GetOwner")
+	}
+	return c.GetOwnerFn(tc, ordinal)
+}
+
+func (c *FakeTiCDCControl) GetChangefeed(tc *v1alpha1.TidbCluster, id string) (*ChangefeedInfo, error) {
+	if c.GetChangefeedFn == nil {
+		return nil, fmt.Errorf("undefined GetChangefeed")
+	}
+	return c.GetChangefeedFn(tc, id)
+}
+
+func (c *FakeTiCDCControl) CreateChangefeed(tc *v1alpha1.TidbCluster, id, sinkURI string, startTs uint64) error {
+	if c.CreateChangefeedFn == nil {
+		return fmt.Errorf("undefined CreateChangefeed")
+	}
+	return c.CreateChangefeedFn(tc, id, sinkURI, startTs)
+}
+
+func (c *FakeTiCDCControl) RemoveChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	if c.RemoveChangefeedFn == nil {
+		return fmt.Errorf("undefined RemoveChangefeed")
+	}
+	return c.RemoveChangefeedFn(tc, id)
+}
@@ -175,6 +175,80 @@ func TestTiCDCControllerResignOwner(t *testing.T) {
 	}
 }
 
+func TestTiCDCControllerGetOwner(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cdc := defaultTiCDCControl{}
+	tc := getTidbCluster()
+
+	cases := []struct {
+		caseName      string
+		captures      []captureInfo
+		status        int
+		expectedOwner int32
+		expectedFound bool
+	}{
+		{
+			caseName: "owner found",
+			captures: []captureInfo{{
+				ID:            "1",
+				AdvertiseAddr: getCaptureAdvertiseAddressPrefix(tc, 1) + ":8301",
+			}, {
+				ID:            "2",
+				IsOwner:       true,
+				AdvertiseAddr: getCaptureAdvertiseAddressPrefix(tc, 2) + ":8301",
+			}},
+			expectedOwner: 2,
+			expectedFound: true,
+		},
+		{
+			caseName: "no owner",
+			captures: []captureInfo{{
+				ID:            "1",
+				AdvertiseAddr: getCaptureAdvertiseAddressPrefix(tc, 1) + ":8301",
+			}},
+			expectedFound: false,
+		},
+		{
+			caseName: "owner of another cluster",
+			captures: []captureInfo{{
+				ID:            "1",
+				AdvertiseAddr: getCaptureAdvertiseAddressPrefix(tc, 1) + ":8301",
+			}, {
+				ID:            "2",
+				IsOwner:       true,
+				AdvertiseAddr: "other-ticdc-0.other-ticdc-peer.default:8301",
+			}},
+			expectedFound: false,
+		},
+		{
+			caseName:      "get captures 503",
+			status:        http.StatusServiceUnavailable,
+			expectedFound: false,
+		},
+	}
+
+	for _, c := range cases {
+		mux := http.NewServeMux()
+		svr := httptest.NewServer(mux)
+		mux.HandleFunc("/api/v1/captures", func(w http.ResponseWriter, req *http.Request) {
+			if c.status != 0 {
+				w.WriteHeader(c.status)
+				return
+			}
+			payload, err := json.Marshal(c.captures)
+			g.Expect(err).Should(BeNil())
+			fmt.Fprint(w, string(payload))
+		})
+		cdc.testURL = svr.URL
+		owner, found, err := cdc.GetOwner(tc, 1)
+		g.Expect(err).Should(BeNil(), c.caseName)
+		g.Expect(found).Should(Equal(c.expectedFound), c.caseName)
+		g.Expect(owner).Should(Equal(c.expectedOwner), c.caseName)
+		svr.Close()
+	}
+}
+
 func TestTiCDCControllerDrainCaptureMultiClusters(t *testing.T) {
 	g := NewGomegaWithT(t)
 	cdc := defaultTiCDCControl{}
@@ -594,3 +668,54 @@ func TestTiCDCControllerIsHealthy(t *testing.T) {
 		svr.Close()
 	}
 }
+
+func TestTiCDCControllerChangefeed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cdc := defaultTiCDCControl{}
+	tc := getTidbCluster()
+
+	changefeeds := map[string]ChangefeedInfo{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/changefeeds", func(w http.ResponseWriter, req *http.Request) {
+		g.Expect(req.Method).Should(Equal("POST"))
+		var reqPayload createChangefeedRequest
+		g.Expect(json.NewDecoder(req.Body).Decode(&reqPayload)).Should(Succeed())
+		g.Expect(reqPayload.SinkURI).Should(Equal("mysql://root@secondary-tidb:4000/"))
+		changefeeds[reqPayload.ChangefeedID] = ChangefeedInfo{ID: reqPayload.ChangefeedID, State: "normal", CheckpointTSO: 1}
+		fmt.Fprint(w, "{}")
+	})
+	mux.HandleFunc("/api/v2/changefeeds/", func(w http.ResponseWriter, req *http.Request) {
+		id := req.URL.Path[len("/api/v2/changefeeds/"):]
+		info, ok := changefeeds[id]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error_msg":"changefeed not exists","error_code":"CDC:ErrChangeFeedNotExists"}`)
+			return
+		}
+		if req.Method == "DELETE" {
+			delete(changefeeds, id)
+			return
+		}
+		payload, err := json.Marshal(info)
+		g.Expect(err).Should(BeNil())
+		fmt.Fprint(w, string(payload))
+	})
+	svr := httptest.NewServer(mux)
+	defer svr.Close()
+	cdc.testURL = svr.URL
+
+	info, err := cdc.GetChangefeed(tc, "cf")
+	g.Expect(err).Should(BeNil())
+	g.Expect(info).Should(BeNil())
+
+	g.Expect(cdc.CreateChangefeed(tc, "cf", "mysql://root@secondary-tidb:4000/", 0)).Should(Succeed())
+	info, err = cdc.GetChangefeed(tc, "cf")
+	g.Expect(err).Should(BeNil())
+	g.Expect(info.State).Should(Equal("normal"))
+	g.Expect(info.CheckpointTSO).Should(Equal(uint64(1)))
+
+	g.Expect(cdc.RemoveChangefeed(tc, "cf")).Should(Succeed())
+	// removing a changefeed that doesn't exist succeeds
+	g.Expect(cdc.RemoveChangefeed(tc, "cf")).Should(Succeed())
+}
@@ -0,0 +1,254 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbaccount
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const nativePasswordPlugin = "mysql_native_password"
+
+var (
+	// authRegex matches the authentication of SHOW CREATE USER, e.g.
+	// IDENTIFIED WITH 'mysql_native_password' AS '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9'
+	authRegex = regexp.MustCompile(`IDENTIFIED WITH '([^']*)' AS '([^']*)'`)
+	// requireRegex matches the TLS requirements of SHOW CREATE USER, e.g. REQUIRE NONE PASSWORD EXPIRE DEFAULT
+	requireRegex = regexp.MustCompile(`REQUIRE (.+?) PASSWORD EXPIRE`)
+	// requireOptionRegex matches the SUBJECT and ISSUER options of the TLS requirements
+	requireOptionRegex = regexp.MustCompile(`(SUBJECT|ISSUER) '((?:[^'\\]|\\.)*)'`)
+	// grantRegex matches the privileges of SHOW GRANTS, e.g. GRANT SELECT,INSERT ON `db`.* TO 'u'@'%'
+	grantRegex = regexp.MustCompile(`^GRANT (.+?) ON (\S+) TO `)
+)
+
+// accountState is the observed state of a user
+type accountState struct {
+	// plugin is the authentication plugin of the user
+	plugin string
+	// authString is the authentication string of the user, which is the hash of the password
+	authString string
+	// require is the REQUIRE clause of the TLS requirements, e.g. NONE or SSL, it's empty if unknown
+	require string
+	// grants are the privileges of the user by the levels
+	grants map[string]*grantLevel
+}
+
+// grantLevel is the privileges granted on a level, e.g. `db`.*
+type grantLevel struct {
+	level      string
+	privileges sets.String
+}
+
+// passwordMatches returns whether the password of the user is the given one, it's always true
+// if the password can't be compared as the authentication plugin is not mysql_native_password
+func (s *accountState) passwordMatches(password string) bool {
+	if s.plugin != nativePasswordPlugin {
+		return true
+	}
+	return s.authString == nativePasswordHash(password)
+}
+
+// accountClient runs the SQL statements about the users
+type accountClient interface {
+	// GetAccount returns the state of the user, or nil if the user doesn't exist
+	GetAccount(ctx context.Context, user, host string) (*accountState, error)
+	Exec(ctx context.Context, stmt string) error
+	Close() error
+}
+
+// newSQLClient connects to the tidb service of the tidb cluster with the admin user of the account
+func (c *defaultTidbAccountControl) newSQLClient(ta *v1alpha1.TidbAccount, tc *v1alpha1.TidbCluster) (accountClient, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = ta.GetAdminUser()
+	if ta.Spec.AdminSecretName != "" {
+		secret, err := c.deps.SecretLister.Secrets(ta.Namespace).Get(ta.Spec.AdminSecretName)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Passwd = string(secret.Data[constants.TidbPasswordKey])
+	}
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s.%s.svc:%d", controller.TiDBMemberName(tc.Name), tc.Namespace, tc.Spec.TiDB.GetServicePort())
+	cfg.Timeout = accountTimeout
+	if tc.Spec.TiDB.IsTLSClientEnabled() && !tc.SkipTLSWhenConnectTiDB() {
+		tlsConfig, err := pdapi.GetTLSConfig(c.deps.SecretLister, pdapi.Namespace(tc.Namespace), util.TiDBClientTLSSecretName(tc.Name, ta.Spec.TLSClientSecretName))
+		if err != nil {
+			return nil, err
+		}
+		cfg.TLS = tlsConfig
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlAccountClient{db: sql.OpenDB(connector)}, nil
+}
+
+type sqlAccountClient struct {
+	db *sql.DB
+}
+
+func (c *sqlAccountClient) GetAccount(ctx context.Context, user, host string) (*accountState, error) {
+	var count int
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM mysql.user WHERE User = ? AND Host = ?", user, host).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	account := quoteString(user) + "@" + quoteString(host)
+
+	var createUser string
+	if err := c.db.QueryRowContext(ctx, "SHOW CREATE USER "+account).Scan(&createUser); err != nil {
+		return nil, err
+	}
+	state := parseCreateUser(createUser)
+
+	rows, err := c.db.QueryContext(ctx, "SHOW GRANTS FOR "+account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var grants []string
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	state.grants = parseGrants(grants)
+	return state, nil
+}
+
+func (c *sqlAccountClient) Exec(ctx context.Context, stmt string) error {
+	_, err := c.db.ExecContext(ctx, stmt)
+	return err
+}
+
+func (c *sqlAccountClient) Close() error {
+	return c.db.Close()
+}
+
+// parseCreateUser parses the authentication and TLS requirements from the result of SHOW CREATE USER
+func parseCreateUser(createUser string) *accountState {
+	state := &accountState{}
+	if m := authRegex.FindStringSubmatch(createUser); m != nil {
+		state.plugin, state.authString = m[1], m[2]
+	}
+	if m := requireRegex.FindStringSubmatch(createUser); m != nil {
+		state.require = m[1]
+	}
+	return state
+}
+
+// parseGrants parses the privileges by the levels from the result of SHOW GRANTS, the USAGE
+// privilege and the grants of the roles are ignored
+func parseGrants(grants []string) map[string]*grantLevel {
+	levels := map[string]*grantLevel{}
+	for _, grant := range grants {
+		m := grantRegex.FindStringSubmatch(grant)
+		if m == nil {
+			continue
+		}
+		key := levelKey(m[2])
+		for _, name := range strings.Split(m[1], ",") {
+			name = normalizePrivilege(name)
+			if name == "USAGE" {
+				continue
+			}
+			if levels[key] == nil {
+				levels[key] = &grantLevel{level: m[2], privileges: sets.NewString()}
+			}
+			levels[key].privileges.Insert(name)
+		}
+	}
+	return levels
+}
+
+// tlsRequire is the parsed TLS requirements to compare the REQUIRE clauses regardless of the format
+type tlsRequire struct {
+	ssl     string
+	subject string
+	issuer  string
+}
+
+// parseRequire parses a REQUIRE clause, e.g. NONE, SSL, X509 or SUBJECT '...' AND ISSUER '...'
+func parseRequire(require string) tlsRequire {
+	r := tlsRequire{}
+	for _, m := range requireOptionRegex.FindAllStringSubmatch(require, -1) {
+		switch m[1] {
+		case "SUBJECT":
+			r.subject = m[2]
+		case "ISSUER":
+			r.issuer = m[2]
+		}
+	}
+	if r.subject != "" || r.issuer != "" {
+		r.ssl = "X509"
+		return r
+	}
+	r.ssl = strings.ToUpper(strings.TrimSpace(require))
+	return r
+}
+
+// normalizePrivilege returns the privilege name in the format of SHOW GRANTS
+func normalizePrivilege(name string) string {
+	name = strings.ToUpper(strings.Join(strings.Fields(name), " "))
+	if name == "ALL" {
+		return "ALL PRIVILEGES"
+	}
+	return name
+}
+
+// levelKey returns the level without the quotes to match the levels of the spec and SHOW GRANTS
+func levelKey(level string) string {
+	return strings.ReplaceAll(level, "`", "")
+}
+
+// nativePasswordHash returns the authentication string of the password of mysql_native_password
+func nativePasswordHash(password string) string {
+	if password == "" {
+		return ""
+	}
+	first := sha1.Sum([]byte(password))
+	second := sha1.Sum(first[:])
+	return "*" + strings.ToUpper(hex.EncodeToString(second[:]))
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
@@ -0,0 +1,385 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbaccount
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// accountTimeout is the timeout to sync an account by SQL
+const accountTimeout = 10 * time.Second
+
+// ControlInterface abstracts the business logic for TidbAccount reconciliation.
+type ControlInterface interface {
+	Reconcile(*v1alpha1.TidbAccount) error
+}
+
+// NewTidbAccountControl returns a new instance of ControlInterface
+func NewTidbAccountControl(deps *controller.Dependencies) ControlInterface {
+	c := &defaultTidbAccountControl{deps: deps}
+	c.newClient = c.newSQLClient
+	return c
+}
+
+type defaultTidbAccountControl struct {
+	deps *controller.Dependencies
+	// newClient can be replaced in unit tests
+	newClient func(ta *v1alpha1.TidbAccount, tc *v1alpha1.TidbCluster) (accountClient, error)
+}
+
+// Reconcile creates the user of the account and keeps its password, TLS requirements and privileges
+// in sync with the spec. The account is checked on every resync of the informer, and the changes
+// made out of the spec, e.g. by a GRANT statement, are reported as drifts and set back.
+func (c *defaultTidbAccountControl) Reconcile(ta *v1alpha1.TidbAccount) error {
+	if ta.DeletionTimestamp != nil {
+		return c.reclaim(ta)
+	}
+	if !c.validate(ta) {
+		return nil
+	}
+	if err := c.syncFinalizer(ta); err != nil {
+		return err
+	}
+
+	oldStatus := ta.Status.DeepCopy()
+	if ta.Status.Phase == "" {
+		ta.Status.Phase = v1alpha1.TidbAccountPending
+	}
+
+	err := c.syncAccount(ta)
+	switch {
+	case err == nil:
+		ta.Status.Phase = v1alpha1.TidbAccountSynced
+		ta.Status.Message = ""
+		ta.Status.ObservedGeneration = ta.Generation
+	case controller.IsRequeueError(err):
+		ta.Status.Message = err.Error()
+	default:
+		ta.Status.Phase = v1alpha1.TidbAccountFailed
+		ta.Status.Message = err.Error()
+	}
+
+	if !apiequality.Semantic.DeepEqual(&ta.Status, oldStatus) {
+		if _, updateErr := c.updateStatus(ta.DeepCopy()); updateErr != nil {
+			return updateErr
+		}
+	}
+	return err
+}
+
+// syncAccount creates the user if it doesn't exist, or sets back the password, TLS requirements
+// and privileges of the user which are different from the spec
+func (c *defaultTidbAccountControl) syncAccount(ta *v1alpha1.TidbAccount) error {
+	ns, name := ta.GetNamespace(), ta.GetName()
+	tc, err := c.deps.TiDBClusterLister.TidbClusters(ta.ClusterNamespace()).Get(ta.Spec.Cluster.Name)
+	if err != nil {
+		return fmt.Errorf("tidb account %s/%s get cluster %s/%s failed: %v", ns, name, ta.ClusterNamespace(), ta.Spec.Cluster.Name, err)
+	}
+	if tc.Spec.TiDB == nil {
+		return fmt.Errorf("tidb account %s/%s: cluster %s/%s has no TiDB", ns, name, tc.Namespace, tc.Name)
+	}
+	if !tc.TiDBAllMembersReady() {
+		return controller.RequeueErrorf("tidb account %s/%s waits for the TiDB of cluster %s/%s to be ready", ns, name, tc.Namespace, tc.Name)
+	}
+
+	secret, err := c.deps.SecretLister.Secrets(ns).Get(ta.Spec.PasswordSecret.Name)
+	if err != nil {
+		return fmt.Errorf("tidb account %s/%s get password secret %s failed: %v", ns, name, ta.Spec.PasswordSecret.Name, err)
+	}
+	password, ok := secret.Data[ta.Spec.PasswordSecret.Key]
+	if !ok {
+		return fmt.Errorf("tidb account %s/%s: key %s is not found in password secret %s", ns, name, ta.Spec.PasswordSecret.Key, secret.Name)
+	}
+
+	client, err := c.newClient(ta, tc)
+	if err != nil {
+		return fmt.Errorf("tidb account %s/%s failed to connect to tidb: %v", ns, name, err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), accountTimeout)
+	defer cancel()
+
+	account := quoteString(ta.Spec.User) + "@" + quoteString(ta.GetHost())
+	require := expectedRequire(ta)
+	state, err := client.GetAccount(ctx, ta.Spec.User, ta.GetHost())
+	if err != nil {
+		return fmt.Errorf("tidb account %s/%s get user %s failed: %v", ns, name, account, err)
+	}
+
+	// the changes of the spec and the password secret are applied as they are, the differences
+	// found when they are unchanged are made out of the spec
+	specChanged := ta.Generation != ta.Status.ObservedGeneration
+	passwordChanged := secret.ResourceVersion != ta.Status.PasswordSecretVersion
+	var stmts, drifts []string
+	if state == nil {
+		stmts = append(stmts, fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s REQUIRE %s", account, quoteString(string(password)), require))
+		state = &accountState{}
+		// the privileges of a new user are all granted by the spec
+		specChanged = true
+	} else {
+		if passwordChanged || !state.passwordMatches(string(password)) {
+			stmts = append(stmts, fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", account, quoteString(string(password))))
+			if !passwordChanged {
+				drifts = append(drifts, "password")
+			}
+		}
+		if state.require != "" && parseRequire(state.require) != parseRequire(require) {
+			stmts = append(stmts, fmt.Sprintf("ALTER USER %s REQUIRE %s", account, require))
+			if !specChanged {
+				drifts = append(drifts, "require "+state.require)
+			}
+		}
+	}
+
+	grantStmts, grantDrifts := syncGrants(account, expectedGrants(ta), state.grants)
+	stmts = append(stmts, grantStmts...)
+	if !specChanged {
+		drifts = append(drifts, grantDrifts...)
+	}
+
+	for _, stmt := range stmts {
+		if err := client.Exec(ctx, stmt); err != nil {
+			// the statements contain the password, only the error is reported
+			return fmt.Errorf("tidb account %s/%s sync user %s failed: %v", ns, name, account, err)
+		}
+	}
+	if len(stmts) > 0 {
+		klog.Infof("tidb account %s/%s synced user %s with %d statements", ns, name, account, len(stmts))
+	}
+	if len(drifts) > 0 {
+		now := metav1.Now()
+		ta.Status.LastDriftTime = &now
+		ta.Status.Drifts = drifts
+		c.deps.Recorder.Eventf(ta, corev1.EventTypeWarning, "AccountDrifted", "user %s is changed out of the spec and set back: %s", account, strings.Join(drifts, "; "))
+	}
+	ta.Status.PasswordSecretVersion = secret.ResourceVersion
+	return nil
+}
+
+// syncFinalizer adds the finalizer to drop the user when the TidbAccount is deleted if the
+// reclaim policy is Delete, and removes it otherwise
+func (c *defaultTidbAccountControl) syncFinalizer(ta *v1alpha1.TidbAccount) error {
+	want := ta.GetReclaimPolicy() == v1alpha1.TidbAccountReclaimDelete
+	if controllerutil.ContainsFinalizer(ta, label.AccountProtectionFinalizer) == want {
+		return nil
+	}
+	if want {
+		controllerutil.AddFinalizer(ta, label.AccountProtectionFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(ta, label.AccountProtectionFinalizer)
+	}
+	updated, err := c.deps.Clientset.PingcapV1alpha1().TidbAccounts(ta.Namespace).Update(context.TODO(), ta, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("tidb account %s/%s update finalizers failed: %v", ta.Namespace, ta.Name, err)
+	}
+	ta.ObjectMeta = updated.ObjectMeta
+	return nil
+}
+
+// reclaim drops the user of the deleted TidbAccount and removes the finalizer, the user is
+// not dropped if the cluster has been deleted
+func (c *defaultTidbAccountControl) reclaim(ta *v1alpha1.TidbAccount) error {
+	if !controllerutil.ContainsFinalizer(ta, label.AccountProtectionFinalizer) {
+		return nil
+	}
+	ns, name := ta.GetNamespace(), ta.GetName()
+	tc, err := c.deps.TiDBClusterLister.TidbClusters(ta.ClusterNamespace()).Get(ta.Spec.Cluster.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("tidb account %s/%s get cluster %s/%s failed: %v", ns, name, ta.ClusterNamespace(), ta.Spec.Cluster.Name, err)
+	}
+	if err == nil && tc.DeletionTimestamp == nil {
+		client, err := c.newClient(ta, tc)
+		if err != nil {
+			return fmt.Errorf("tidb account %s/%s failed to connect to tidb: %v", ns, name, err)
+		}
+		defer client.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), accountTimeout)
+		defer cancel()
+		account := quoteString(ta.Spec.User) + "@" + quoteString(ta.GetHost())
+		if err := client.Exec(ctx, fmt.Sprintf("DROP USER IF EXISTS %s", account)); err != nil {
+			return fmt.Errorf("tidb account %s/%s drop user %s failed: %v", ns, name, account, err)
+		}
+		klog.Infof("tidb account %s/%s dropped user %s", ns, name, account)
+	}
+
+	controllerutil.RemoveFinalizer(ta, label.AccountProtectionFinalizer)
+	if _, err := c.deps.Clientset.PingcapV1alpha1().TidbAccounts(ns).Update(context.TODO(), ta, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("tidb account %s/%s remove finalizer failed: %v", ns, name, err)
+	}
+	return nil
+}
+
+// expectedRequire returns the REQUIRE clause of the TLS requirements in the spec
+func expectedRequire(ta *v1alpha1.TidbAccount) string {
+	tls := ta.Spec.TLS
+	if tls == nil {
+		return "NONE"
+	}
+	var options []string
+	if tls.Subject != "" {
+		options = append(options, "SUBJECT "+quoteString(tls.Subject))
+	}
+	if tls.Issuer != "" {
+		options = append(options, "ISSUER "+quoteString(tls.Issuer))
+	}
+	if len(options) > 0 {
+		return strings.Join(options, " AND ")
+	}
+	switch tls.Require {
+	case v1alpha1.TLSRequireSSL, v1alpha1.TLSRequireX509:
+		return string(tls.Require)
+	default:
+		return "NONE"
+	}
+}
+
+// expectedGrants returns the privileges in the spec by the levels
+func expectedGrants(ta *v1alpha1.TidbAccount) map[string]*grantLevel {
+	grants := map[string]*grantLevel{}
+	for i := range ta.Spec.Privileges {
+		p := &ta.Spec.Privileges[i]
+		database, table, ok := p.GetLevel()
+		if !ok {
+			continue
+		}
+		level := "*.*"
+		if database != "*" {
+			level = quoteIdentifier(database) + "."
+			if table == "*" {
+				level += "*"
+			} else {
+				level += quoteIdentifier(table)
+			}
+		}
+		key := levelKey(level)
+		if grants[key] == nil {
+			grants[key] = &grantLevel{level: level, privileges: sets.NewString()}
+		}
+		for _, name := range p.Privileges {
+			grants[key].privileges.Insert(normalizePrivilege(name))
+		}
+	}
+	return grants
+}
+
+// syncGrants returns the statements to grant the missing privileges and revoke the privileges out
+// of the spec, and the differences of the privileges
+func syncGrants(account string, expected, actual map[string]*grantLevel) (stmts, drifts []string) {
+	keys := sets.NewString()
+	for key := range expected {
+		keys.Insert(key)
+	}
+	for key := range actual {
+		keys.Insert(key)
+	}
+	for _, key := range keys.List() {
+		want, got := sets.NewString(), sets.NewString()
+		level := ""
+		if g := actual[key]; g != nil {
+			got, level = g.privileges, g.level
+		}
+		if g := expected[key]; g != nil {
+			want, level = g.privileges, g.level
+		}
+		if revoke := got.Difference(want); revoke.Len() > 0 {
+			stmts = append(stmts, fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(revoke.List(), ", "), level, account))
+			drifts = append(drifts, fmt.Sprintf("granted %s on %s", strings.Join(revoke.List(), ", "), level))
+		}
+		if grant := want.Difference(got); grant.Len() > 0 {
+			stmts = append(stmts, fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(grant.List(), ", "), level, account))
+			drifts = append(drifts, fmt.Sprintf("revoked %s on %s", strings.Join(grant.List(), ", "), level))
+		}
+	}
+	return stmts, drifts
+}
+
+func (c *defaultTidbAccountControl) updateStatus(ta *v1alpha1.TidbAccount) (*v1alpha1.TidbAccount, error) {
+	var (
+		ns     = ta.GetNamespace()
+		name   = ta.GetName()
+		status = ta.Status.DeepCopy()
+		update *v1alpha1.TidbAccount
+	)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var updateErr error
+		update, updateErr = c.deps.Clientset.PingcapV1alpha1().TidbAccounts(ns).UpdateStatus(context.TODO(), ta, metav1.UpdateOptions{})
+		if updateErr == nil {
+			klog.Infof("TidbAccount: [%s/%s], update status successfully", ns, name)
+			return nil
+		}
+
+		klog.V(4).Infof("TidbAccount: [%s/%s], update status failed, error: %v", ns, name, updateErr)
+
+		// If failed to update status, then:
+		// get the latest TidbAccount, override the status to local newest, prepare for next update.
+		if updated, err := c.deps.TiDBAccountLister.TidbAccounts(ns).Get(name); err == nil {
+			ta = updated.DeepCopy()
+			ta.Status = *status
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated TidbAccount %s/%s from lister: %v", ns, name, err))
+		}
+
+		return updateErr
+	})
+	if err != nil {
+		klog.Errorf("TidbAccount: [%s/%s], failed to updateStatus, error: %v", ns, name, err)
+	}
+
+	return update, err
+}
+
+func (c *defaultTidbAccountControl) validate(ta *v1alpha1.TidbAccount) bool {
+	errs := v1alpha1validation.ValidateTidbAccount(ta)
+	if len(errs) > 0 {
+		aggregatedErr := errs.ToAggregate()
+		klog.Errorf("tidb account %s/%s is not valid and must be fixed first, aggregated error: %v", ta.GetNamespace(), ta.GetName(), aggregatedErr)
+		c.deps.Recorder.Event(ta, corev1.EventTypeWarning, "FailedValidation", aggregatedErr.Error())
+		return false
+	}
+	return true
+}
+
+type FakeTidbAccountControl struct {
+	reconcile func(*v1alpha1.TidbAccount) error
+}
+
+func (c *FakeTidbAccountControl) MockReconcile(reconcile func(*v1alpha1.TidbAccount) error) {
+	c.reconcile = reconcile
+}
+
+func (c *FakeTidbAccountControl) Reconcile(ta *v1alpha1.TidbAccount) error {
+	if c.reconcile != nil {
+		return c.reconcile(ta)
+	}
+	return nil
+}
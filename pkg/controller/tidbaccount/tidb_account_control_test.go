@@ -0,0 +1,225 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbaccount
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type fakeAccountClient struct {
+	state *accountState
+	stmts []string
+}
+
+func (c *fakeAccountClient) GetAccount(_ context.Context, _, _ string) (*accountState, error) {
+	return c.state, nil
+}
+
+func (c *fakeAccountClient) Exec(_ context.Context, stmt string) error {
+	c.stmts = append(c.stmts, stmt)
+	return nil
+}
+
+func (c *fakeAccountClient) Close() error {
+	return nil
+}
+
+func TestTidbAccount(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	control := NewTidbAccountControl(deps).(*defaultTidbAccountControl)
+	client := &fakeAccountClient{}
+	control.newClient = func(_ *v1alpha1.TidbAccount, _ *v1alpha1.TidbCluster) (accountClient, error) {
+		client.stmts = nil
+		return client, nil
+	}
+	tcIndexer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+	secretIndexer := deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer()
+
+	ta := newTidbAccount(g, deps)
+
+	// the account waits for the TiDB to be ready
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+			TiDB: &v1alpha1.TiDBSpec{Replicas: 1},
+		},
+	}
+	g.Expect(tcIndexer.Add(tc)).To(Succeed())
+	err := control.Reconcile(ta)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(ta.Status.Phase).To(Equal(v1alpha1.TidbAccountPending))
+
+	// the account fails without the password secret
+	tc = tc.DeepCopy()
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{"cluster-tidb-0": {Health: true}}
+	g.Expect(tcIndexer.Update(tc)).To(Succeed())
+	g.Expect(control.Reconcile(ta)).NotTo(Succeed())
+	g.Expect(ta.Status.Phase).To(Equal(v1alpha1.TidbAccountFailed))
+
+	// the user is created with the privileges
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-password", Namespace: corev1.NamespaceDefault, ResourceVersion: "1"},
+		Data:       map[string][]byte{"password": []byte("123456")},
+	}
+	g.Expect(secretIndexer.Add(secret)).To(Succeed())
+	g.Expect(control.Reconcile(ta)).To(Succeed())
+	g.Expect(ta.Status.Phase).To(Equal(v1alpha1.TidbAccountSynced))
+	g.Expect(ta.Status.PasswordSecretVersion).To(Equal("1"))
+	g.Expect(ta.Status.Drifts).To(BeEmpty())
+	g.Expect(client.stmts).To(Equal([]string{
+		"CREATE USER 'app'@'%' IDENTIFIED BY '123456' REQUIRE SSL",
+		"GRANT BACKUP_ADMIN ON *.* TO 'app'@'%'",
+		"GRANT INSERT, SELECT ON `app`.* TO 'app'@'%'",
+	}))
+
+	// nothing is done if the user is in sync with the spec
+	client.state = &accountState{
+		plugin:     nativePasswordPlugin,
+		authString: nativePasswordHash("123456"),
+		require:    "SSL",
+		grants: map[string]*grantLevel{
+			"*.*":   {level: "*.*", privileges: sets.NewString("BACKUP_ADMIN")},
+			"app.*": {level: "`app`.*", privileges: sets.NewString("SELECT", "INSERT")},
+		},
+	}
+	g.Expect(control.Reconcile(ta)).To(Succeed())
+	g.Expect(client.stmts).To(BeEmpty())
+
+	// the changes out of the spec are set back and reported as drifts
+	client.state.authString = nativePasswordHash("654321")
+	client.state.grants["app.*"].privileges.Insert("DROP")
+	g.Expect(control.Reconcile(ta)).To(Succeed())
+	g.Expect(client.stmts).To(Equal([]string{
+		"ALTER USER 'app'@'%' IDENTIFIED BY '123456'",
+		"REVOKE DROP ON `app`.* FROM 'app'@'%'",
+	}))
+	g.Expect(ta.Status.Drifts).To(Equal([]string{"password", "granted DROP on `app`.*"}))
+	g.Expect(ta.Status.LastDriftTime).NotTo(BeNil())
+	client.state.authString = nativePasswordHash("123456")
+	client.state.grants["app.*"].privileges.Delete("DROP")
+
+	// the changes of the spec and the password are not drifts
+	ta.Generation = 2
+	ta.Spec.TLS = nil
+	ta.Spec.Privileges = ta.Spec.Privileges[:1]
+	secret = secret.DeepCopy()
+	secret.ResourceVersion = "2"
+	secret.Data["password"] = []byte("pass'word")
+	g.Expect(secretIndexer.Update(secret)).To(Succeed())
+	lastDrift := ta.Status.LastDriftTime
+	g.Expect(control.Reconcile(ta)).To(Succeed())
+	g.Expect(client.stmts).To(Equal([]string{
+		"ALTER USER 'app'@'%' IDENTIFIED BY 'pass\\'word'",
+		"ALTER USER 'app'@'%' REQUIRE NONE",
+		"REVOKE BACKUP_ADMIN ON *.* FROM 'app'@'%'",
+	}))
+	g.Expect(ta.Status.LastDriftTime).To(Equal(lastDrift))
+	g.Expect(ta.Status.ObservedGeneration).To(Equal(int64(2)))
+	g.Expect(ta.Status.PasswordSecretVersion).To(Equal("2"))
+}
+
+func TestTidbAccountReclaim(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	control := NewTidbAccountControl(deps).(*defaultTidbAccountControl)
+	client := &fakeAccountClient{}
+	control.newClient = func(_ *v1alpha1.TidbAccount, _ *v1alpha1.TidbCluster) (accountClient, error) {
+		return client, nil
+	}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(&v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: corev1.NamespaceDefault},
+		Spec:       v1alpha1.TidbClusterSpec{TiDB: &v1alpha1.TiDBSpec{}},
+	})).To(Succeed())
+
+	// the finalizer is added for the Delete reclaim policy
+	ta := newTidbAccount(g, deps)
+	ta.Spec.ReclaimPolicy = v1alpha1.TidbAccountReclaimDelete
+	g.Expect(control.Reconcile(ta)).NotTo(Succeed())
+	ta, err := deps.Clientset.PingcapV1alpha1().TidbAccounts(ta.Namespace).Get(context.TODO(), ta.Name, metav1.GetOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(ta.Finalizers).To(ContainElement(label.AccountProtectionFinalizer))
+
+	// the user is dropped and the finalizer is removed when the account is deleted
+	now := metav1.Now()
+	ta.DeletionTimestamp = &now
+	g.Expect(control.Reconcile(ta)).To(Succeed())
+	g.Expect(client.stmts).To(Equal([]string{"DROP USER IF EXISTS 'app'@'%'"}))
+	ta, err = deps.Clientset.PingcapV1alpha1().TidbAccounts(ta.Namespace).Get(context.TODO(), ta.Name, metav1.GetOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(ta.Finalizers).To(BeEmpty())
+}
+
+func TestParseAccount(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(nativePasswordHash("123456")).To(Equal("*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9"))
+	g.Expect(nativePasswordHash("")).To(BeEmpty())
+
+	state := parseCreateUser("CREATE USER 'app'@'%' IDENTIFIED WITH 'mysql_native_password' AS '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9' REQUIRE X509 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK")
+	g.Expect(state.plugin).To(Equal(nativePasswordPlugin))
+	g.Expect(state.passwordMatches("123456")).To(BeTrue())
+	g.Expect(state.passwordMatches("654321")).To(BeFalse())
+	g.Expect(state.require).To(Equal("X509"))
+
+	g.Expect(parseRequire("ISSUER '/CN=ca' SUBJECT '/CN=app'")).To(Equal(parseRequire("SUBJECT '/CN=app' AND ISSUER '/CN=ca'")))
+	g.Expect(parseRequire("SSL")).NotTo(Equal(parseRequire("NONE")))
+
+	grants := parseGrants([]string{
+		"GRANT USAGE ON *.* TO 'app'@'%'",
+		"GRANT SELECT,INSERT ON `app`.* TO 'app'@'%'",
+		"GRANT ALL PRIVILEGES ON `app`.`t1` TO 'app'@'%' WITH GRANT OPTION",
+		"GRANT 'reader'@'%' TO 'app'@'%'",
+	})
+	g.Expect(grants).To(HaveLen(2))
+	g.Expect(grants["app.*"].level).To(Equal("`app`.*"))
+	g.Expect(grants["app.*"].privileges.List()).To(Equal([]string{"INSERT", "SELECT"}))
+	g.Expect(grants["app.t1"].privileges.List()).To(Equal([]string{"ALL PRIVILEGES"}))
+}
+
+func newTidbAccount(g *GomegaWithT, deps *controller.Dependencies) *v1alpha1.TidbAccount {
+	ta := &v1alpha1.TidbAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: corev1.NamespaceDefault, Generation: 1},
+		Spec: v1alpha1.TidbAccountSpec{
+			Cluster: v1alpha1.TidbClusterRef{Name: "cluster"},
+			User:    "app",
+			PasswordSecret: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "app-password"},
+				Key:                  "password",
+			},
+			Privileges: []v1alpha1.TidbAccountPrivilege{
+				{Privileges: []string{"select", "INSERT"}, On: "app.*"},
+				{Privileges: []string{"BACKUP_ADMIN"}},
+			},
+			TLS: &v1alpha1.TidbAccountTLS{Require: v1alpha1.TLSRequireSSL},
+		},
+	}
+	_, err := deps.Clientset.PingcapV1alpha1().TidbAccounts(ta.Namespace).Create(context.TODO(), ta, metav1.CreateOptions{})
+	g.Expect(err).To(Succeed())
+	return ta
+}
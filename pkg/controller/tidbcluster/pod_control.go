@@ -83,6 +83,13 @@ func NewPodController(deps *controller.Dependencies) *PodController {
 			c.enqueuePod(cur)
 		},
 	})
+	// the pods on a node are synced again when the node is cordoned or tainted to be drained
+	if deps.NodeLister != nil {
+		nodesInformer := deps.KubeInformerFactory.Core().V1().Nodes()
+		nodesInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: c.enqueuePodsOnNode,
+		})
+	}
 
 	return c
 }
@@ -226,8 +233,17 @@ func (c *PodController) sync(key string) (result reconcile.Result, err error) {
 		klog.V(4).Infof("Finished syncing TidbCluster pod %q (%v)", key, duration)
 	}()
 
-	component := pod.Labels[label.ComponentLabelKey]
 	ctx := context.Background()
+	// the pod is synced again for the other annotations after the annotation is removed
+	if _, ok := pod.Annotations[v1alpha1.DebugContainerAnnKey]; ok {
+		return reconcile.Result{}, c.syncPodForDebugContainer(ctx, pod, tc)
+	}
+	// the pod is synced again for the annotations of the draining node after they are updated
+	if updated, err := c.syncPodForNodeDrain(pod, tc); err != nil || updated {
+		return reconcile.Result{}, err
+	}
+
+	component := pod.Labels[label.ComponentLabelKey]
 	switch component {
 	case label.PDLabelVal:
 		return c.syncPDPod(ctx, pod, tc)
@@ -237,6 +253,8 @@ func (c *PodController) sync(key string) (result reconcile.Result, err error) {
 		return c.syncTiDBPod(ctx, pod, tc)
 	case label.TiFlashLabelVal:
 		return c.syncTiFlashPod(ctx, pod, tc)
+	case label.TiCDCLabelVal:
+		return c.syncTiCDCPodForDrain(ctx, pod, tc)
 	default:
 		return reconcile.Result{}, nil
 	}
@@ -580,6 +598,10 @@ func (c *PodController) syncTiKVPodForReplaceVolume(ctx context.Context, pod *co
 		if !tc.TiKVAllStoresReady() {
 			return reconcile.Result{Requeue: true}, fmt.Errorf("Not all TIKV stores ready before replace")
 		}
+		if reason := pdapi.CheckStoresDeletable(pdClient, tc.TiKVDeletionSafetyLevel(), storeID); reason != "" {
+			klog.Infof("storeid %d can not be deleted to replace volume: %s", storeID, reason)
+			return reconcile.Result{RequeueAfter: c.recheckClusterStableDuration}, nil
+		}
 		// 1. Delete store
 		klog.Infof("storeid %d is Up, deleting due to replace volume annotation.", storeID)
 		pdClient.DeleteStore(storeID)
@@ -53,6 +53,14 @@ func (c *kvClient) FlushLogBackupTasks(ctx context.Context) error {
 	return nil
 }
 
+func (c *kvClient) GetBackupNumThreads() (int32, error) {
+	return 0, nil
+}
+
+func (c *kvClient) SetBackupNumThreads(threads int32) error {
+	return nil
+}
+
 func TestTiKVPodSyncForEviction(t *testing.T) {
 	interval := time.Millisecond * 100
 	timeout := time.Minute * 1
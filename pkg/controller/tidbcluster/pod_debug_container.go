@@ -0,0 +1,193 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	debugContainerName = "debug"
+	// the paths of the certs in the TLS volumes of the components
+	debugTLSCAFile   = "ca.crt"
+	debugTLSCertFile = "tls.crt"
+	debugTLSKeyFile  = "tls.key"
+)
+
+// syncPodForDebugContainer attaches an ephemeral debug container to the pod if the pod is annotated with
+// DebugContainerAnnKey. The container targets the main container of the component to share its process
+// namespace, and mounts the volumes of the main container, including the data and the TLS certs.
+func (c *PodController) syncPodForDebugContainer(ctx context.Context, pod *corev1.Pod, tc *v1alpha1.TidbCluster) error {
+	image, exist := pod.Annotations[v1alpha1.DebugContainerAnnKey]
+	if !exist {
+		return nil
+	}
+	if image == "" || image == v1alpha1.DebugContainerValueDefault {
+		image = c.deps.CLIConfig.DebugImage
+	}
+	if image == "" {
+		c.deps.Recorder.Eventf(pod, corev1.EventTypeWarning, "FailedAttachDebugContainer",
+			"the debug image is not set, set the image in annotation %s or the debug image of the operator", v1alpha1.DebugContainerAnnKey)
+		return c.removeDebugContainerAnnotation(pod, tc)
+	}
+
+	target := pod.Labels[label.ComponentLabelKey]
+	var targetContainer *corev1.Container
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == target {
+			targetContainer = &pod.Spec.Containers[i]
+			break
+		}
+	}
+	if targetContainer == nil {
+		klog.Warningf("Ignore annotation %q for Pod %s/%s, container %s is not found", v1alpha1.DebugContainerAnnKey, pod.Namespace, pod.Name, target)
+		return nil
+	}
+
+	// the container is attached but the annotation is not removed in the last sync
+	if name, ok := activeDebugContainer(pod, image); ok {
+		klog.Infof("debug container %s with image %s is already attached to Pod %s/%s", name, image, pod.Namespace, pod.Name)
+		return c.removeDebugContainerAnnotation(pod, tc)
+	}
+
+	container := newDebugContainer(pod, tc, targetContainer, image)
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, container)
+	updated, err := c.deps.KubeClientset.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(ctx, pod.Name, pod, metav1.UpdateOptions{})
+	if err != nil {
+		c.deps.Recorder.Eventf(pod, corev1.EventTypeWarning, "FailedAttachDebugContainer", "attach debug container %s failed: %v", container.Name, err)
+		return fmt.Errorf("failed to attach debug container to Pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	c.deps.Recorder.Eventf(pod, corev1.EventTypeNormal, "DebugContainerAttached",
+		"debug container %s is attached, run `kubectl attach -it -n %s %s -c %s` to debug", container.Name, pod.Namespace, pod.Name, container.Name)
+	klog.Infof("debug container %s is attached to Pod %s/%s", container.Name, pod.Namespace, pod.Name)
+
+	return c.removeDebugContainerAnnotation(updated, tc)
+}
+
+func (c *PodController) removeDebugContainerAnnotation(pod *corev1.Pod, tc *v1alpha1.TidbCluster) error {
+	pod = pod.DeepCopy()
+	delete(pod.Annotations, v1alpha1.DebugContainerAnnKey)
+	if _, err := c.deps.PodControl.UpdatePod(tc, pod); err != nil {
+		return fmt.Errorf("failed to delete annotation %q of Pod %s/%s: %v", v1alpha1.DebugContainerAnnKey, pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+// activeDebugContainer returns the name of the last debug container of the pod if it runs the image and is
+// not terminated, a new debug container is attached only after the last one exits.
+func activeDebugContainer(pod *corev1.Pod, image string) (string, bool) {
+	var last *corev1.EphemeralContainer
+	for i := range pod.Spec.EphemeralContainers {
+		if isDebugContainerName(pod.Spec.EphemeralContainers[i].Name) {
+			last = &pod.Spec.EphemeralContainers[i]
+		}
+	}
+	if last == nil || last.Image != image {
+		return "", false
+	}
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if status.Name == last.Name && status.State.Terminated != nil {
+			return "", false
+		}
+	}
+	return last.Name, true
+}
+
+func isDebugContainerName(name string) bool {
+	if name == debugContainerName {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(name, debugContainerName+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// newDebugContainer returns the ephemeral debug container for the target container of the pod, the
+// address of PD and the TLS certs are set in the env for pd-ctl and tikv-ctl.
+func newDebugContainer(pod *corev1.Pod, tc *v1alpha1.TidbCluster, target *corev1.Container, image string) corev1.EphemeralContainer {
+	name := debugContainerName
+	for i := 1; debugContainerExists(pod, name); i++ {
+		name = fmt.Sprintf("%s-%d", debugContainerName, i)
+	}
+
+	pdName, pdNamespace := controller.PDMemberName(tc.Name), tc.Namespace
+	if tc.Spec.PD == nil && tc.Spec.Cluster != nil && tc.Spec.Cluster.Name != "" {
+		pdName = controller.PDMemberName(tc.Spec.Cluster.Name)
+		if tc.Spec.Cluster.Namespace != "" {
+			pdNamespace = tc.Spec.Cluster.Namespace
+		}
+	}
+	env := []corev1.EnvVar{
+		{Name: "PD_ADDR", Value: fmt.Sprintf("%s://%s.%s:%d", tc.Scheme(), pdName, pdNamespace, v1alpha1.DefaultPDClientPort)},
+	}
+	if tc.IsTLSClusterEnabled() {
+		// the certs of the component are mounted at the same path as the target container
+		for _, m := range target.VolumeMounts {
+			if m.Name == target.Name+"-tls" {
+				env = append(env,
+					corev1.EnvVar{Name: "TLS_CA", Value: m.MountPath + "/" + debugTLSCAFile},
+					corev1.EnvVar{Name: "TLS_CERT", Value: m.MountPath + "/" + debugTLSCertFile},
+					corev1.EnvVar{Name: "TLS_KEY", Value: m.MountPath + "/" + debugTLSKeyFile},
+				)
+				break
+			}
+		}
+	}
+
+	return corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"/bin/sh"},
+			Stdin:           true,
+			TTY:             true,
+			Env:             env,
+			VolumeMounts:    target.VolumeMounts,
+		},
+		TargetContainerName: target.Name,
+	}
+}
+
+func debugContainerExists(pod *corev1.Pod, name string) bool {
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == name {
+			return true
+		}
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
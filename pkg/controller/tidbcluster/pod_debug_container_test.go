@@ -0,0 +1,104 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncPodForDebugContainer(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	tc := newTidbCluster()
+	tc.Spec.TLSCluster = &v1alpha1.TLSCluster{Enabled: true}
+	pod := newTiKVPod(tc)
+	pod.Spec.Containers = []corev1.Container{{
+		Name: "tikv",
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "tikv", MountPath: "/var/lib/tikv"},
+			{Name: "tikv-tls", ReadOnly: true, MountPath: "/var/lib/tikv-tls"},
+		},
+	}}
+	pod.Annotations = map[string]string{v1alpha1.DebugContainerAnnKey: v1alpha1.DebugContainerValueDefault}
+
+	deps := controller.NewFakeDependencies()
+	c := NewPodController(deps)
+	_, err := deps.KubeClientset.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+
+	attach := func() *corev1.Pod {
+		pod, err := deps.KubeClientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		g.Expect(err).Should(Succeed())
+		g.Expect(c.syncPodForDebugContainer(ctx, pod, tc)).Should(Succeed())
+		pod, err = deps.KubeClientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		g.Expect(err).Should(Succeed())
+		g.Expect(pod.Annotations).ShouldNot(HaveKey(v1alpha1.DebugContainerAnnKey))
+		return pod
+	}
+
+	pod = attach()
+	g.Expect(pod.Spec.EphemeralContainers).Should(HaveLen(1))
+	container := pod.Spec.EphemeralContainers[0]
+	g.Expect(container.Name).Should(Equal("debug"))
+	g.Expect(container.Image).Should(Equal(deps.CLIConfig.DebugImage))
+	g.Expect(container.TargetContainerName).Should(Equal("tikv"))
+	g.Expect(container.VolumeMounts).Should(Equal(pod.Spec.Containers[0].VolumeMounts))
+	g.Expect(container.Env).Should(ContainElements(
+		corev1.EnvVar{Name: "PD_ADDR", Value: "https://" + controller.PDMemberName(tc.Name) + "." + tc.Namespace + ":2379"},
+		corev1.EnvVar{Name: "TLS_CA", Value: "/var/lib/tikv-tls/ca.crt"},
+	))
+
+	// attach another container with the specified image
+	pod.Annotations[v1alpha1.DebugContainerAnnKey] = "busybox"
+	_, err = deps.KubeClientset.CoreV1().Pods(pod.Namespace).Update(ctx, pod, metav1.UpdateOptions{})
+	g.Expect(err).Should(Succeed())
+	pod = attach()
+	g.Expect(pod.Spec.EphemeralContainers).Should(HaveLen(2))
+	g.Expect(pod.Spec.EphemeralContainers[1].Name).Should(Equal("debug-1"))
+	g.Expect(pod.Spec.EphemeralContainers[1].Image).Should(Equal("busybox"))
+
+	// the annotation is not removed after the container is attached, the container is not attached again
+	annotate := func(image string) {
+		pod.Annotations = map[string]string{v1alpha1.DebugContainerAnnKey: image}
+		_, err = deps.KubeClientset.CoreV1().Pods(pod.Namespace).Update(ctx, pod, metav1.UpdateOptions{})
+		g.Expect(err).Should(Succeed())
+	}
+	annotate("busybox")
+	pod = attach()
+	g.Expect(pod.Spec.EphemeralContainers).Should(HaveLen(2))
+
+	// a new container is attached after the last one exits
+	pod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "debug-1",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+	}}
+	annotate("busybox")
+	pod = attach()
+	g.Expect(pod.Spec.EphemeralContainers).Should(HaveLen(3))
+	g.Expect(pod.Spec.EphemeralContainers[2].Name).Should(Equal("debug-2"))
+
+	// nothing is attached without the debug image
+	deps.CLIConfig.DebugImage = ""
+	annotate(v1alpha1.DebugContainerValueDefault)
+	pod = attach()
+	g.Expect(pod.Spec.EphemeralContainers).Should(HaveLen(3))
+}
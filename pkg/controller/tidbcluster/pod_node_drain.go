@@ -0,0 +1,173 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+
+	perrors "github.com/pingcap/errors"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// enqueuePodsOnNode enqueues the pods on the node if the node is cordoned, uncordoned or its taints are changed.
+func (c *PodController) enqueuePodsOnNode(old, cur interface{}) {
+	oldNode, ok := old.(*corev1.Node)
+	if !ok {
+		return
+	}
+	curNode, ok := cur.(*corev1.Node)
+	if !ok {
+		return
+	}
+	if oldNode.Spec.Unschedulable == curNode.Spec.Unschedulable && apiequality.Semantic.DeepEqual(oldNode.Spec.Taints, curNode.Spec.Taints) {
+		return
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{label.ManagedByLabelKey: label.TiDBOperator})
+	pods, err := c.deps.PodLister.List(selector)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list pods on node %s: %v", curNode.Name, err))
+		return
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == curNode.Name {
+			c.enqueuePod(pod)
+		}
+	}
+}
+
+// syncPodForNodeDrain annotates the pod to move its leaders or captures off if the node of the pod is going to be
+// drained, the annotations are handled by the other syncs of the pod. The annotations are removed after the node
+// is no longer draining, unless they are set by the user. It returns whether the pod is updated.
+func (c *PodController) syncPodForNodeDrain(pod *corev1.Pod, tc *v1alpha1.TidbCluster) (bool, error) {
+	component := pod.Labels[label.ComponentLabelKey]
+	drainingNode := ""
+	if policy := tc.Spec.NodeDrain; policy != nil && pod.Spec.NodeName != "" && c.deps.NodeLister != nil {
+		node, err := c.deps.NodeLister.Get(pod.Spec.NodeName)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, perrors.Annotatef(err, "failed to get node %s of Pod %s/%s", pod.Spec.NodeName, pod.Namespace, pod.Name)
+		}
+		if err == nil && isNodeDraining(node, policy) {
+			drainingNode = node.Name
+		}
+	}
+	markedNode, marked := pod.Annotations[v1alpha1.NodeDrainAnnKey]
+
+	switch {
+	case drainingNode != "" && !marked:
+		key, value := nodeDrainAnnotation(component, tc.Spec.NodeDrain.MigrateTiCDCCaptures)
+		if key == "" {
+			return false, nil
+		}
+		if _, exist := pod.Annotations[key]; exist {
+			// the annotation is set by the user, leave it as it is
+			return false, nil
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[key] = value
+		pod.Annotations[v1alpha1.NodeDrainAnnKey] = drainingNode
+		c.deps.Recorder.Eventf(pod, corev1.EventTypeNormal, "NodeDraining", "node %s is going to be drained, add annotation %s to Pod %s", drainingNode, key, pod.Name)
+		klog.Infof("node %s is going to be drained, add annotation %s to Pod %s/%s", drainingNode, key, pod.Namespace, pod.Name)
+	case drainingNode == "" && marked:
+		key, _ := nodeDrainAnnotation(component, true)
+		delete(pod.Annotations, key)
+		delete(pod.Annotations, v1alpha1.NodeDrainAnnKey)
+		klog.Infof("node %s is no longer draining, remove annotation %s from Pod %s/%s", markedNode, key, pod.Namespace, pod.Name)
+	default:
+		return false, nil
+	}
+
+	if _, err := c.deps.PodControl.UpdatePod(tc, pod); err != nil {
+		return false, perrors.Annotatef(err, "failed to update annotations of Pod %s/%s for the draining node", pod.Namespace, pod.Name)
+	}
+	return true, nil
+}
+
+// syncTiCDCPodForDrain moves the ownership and the tables off the TiCDC capture if the pod is annotated with
+// TiCDCDrainCaptureAnnKey. The capture is drained again periodically while the annotation exists, in case the
+// tables are scheduled back to it.
+func (c *PodController) syncTiCDCPodForDrain(ctx context.Context, pod *corev1.Pod, tc *v1alpha1.TidbCluster) (reconcile.Result, error) {
+	value, exist := pod.Annotations[v1alpha1.TiCDCDrainCaptureAnnKey]
+	if !exist {
+		return reconcile.Result{}, nil
+	}
+	if value != v1alpha1.DrainCaptureValueNone {
+		klog.Warningf("Ignore unknown value %q of annotation %q for Pod %s/%s", value, v1alpha1.TiCDCDrainCaptureAnnKey, pod.Namespace, pod.Name)
+		return reconcile.Result{}, nil
+	}
+
+	ordinal, err := util.GetOrdinalFromPodName(pod.Name)
+	if err != nil {
+		return reconcile.Result{}, perrors.Annotatef(err, "failed to get ordinal of Pod %s/%s", pod.Namespace, pod.Name)
+	}
+	resigned, err := c.deps.CDCControl.ResignOwner(tc, ordinal)
+	if err != nil {
+		return reconcile.Result{}, perrors.Annotatef(err, "failed to resign owner of TiCDC Pod %s/%s", pod.Namespace, pod.Name)
+	}
+	if !resigned {
+		klog.Infof("TiCDC Pod %s/%s is still the owner, try resign owner again", pod.Namespace, pod.Name)
+		return reconcile.Result{RequeueAfter: c.recheckLeaderCountDuration}, nil
+	}
+	tableCount, retry, err := c.deps.CDCControl.DrainCapture(tc, ordinal)
+	if err != nil {
+		return reconcile.Result{}, perrors.Annotatef(err, "failed to drain capture of TiCDC Pod %s/%s", pod.Namespace, pod.Name)
+	}
+	if retry || tableCount != 0 {
+		klog.Infof("TiCDC Pod %s/%s still has %d tables, wait draining", pod.Namespace, pod.Name, tableCount)
+		return reconcile.Result{RequeueAfter: c.recheckLeaderCountDuration}, nil
+	}
+	return reconcile.Result{RequeueAfter: RequeueInterval}, nil
+}
+
+// isNodeDraining returns whether the node is cordoned or has any of the taints of the policy
+func isNodeDraining(node *corev1.Node, policy *v1alpha1.NodeDrainPolicy) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range policy.TaintKeys {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeDrainAnnotation returns the annotation that moves the leaders or the captures off the pod of the component
+func nodeDrainAnnotation(component string, migrateTiCDCCaptures bool) (string, string) {
+	switch component {
+	case label.PDLabelVal:
+		return v1alpha1.PDLeaderTransferAnnKey, v1alpha1.TransferLeaderValueNone
+	case label.TiKVLabelVal:
+		return v1alpha1.EvictLeaderAnnKey, v1alpha1.EvictLeaderValueNone
+	case label.TiCDCLabelVal:
+		if migrateTiCDCCaptures {
+			return v1alpha1.TiCDCDrainCaptureAnnKey, v1alpha1.DrainCaptureValueNone
+		}
+	}
+	return "", ""
+}
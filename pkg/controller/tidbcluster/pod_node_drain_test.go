@@ -0,0 +1,130 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodSyncForNodeDrain(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	tc.Spec.NodeDrain = &v1alpha1.NodeDrainPolicy{TaintKeys: []string{"ToBeDeletedByClusterAutoscaler"}}
+	deps := controller.NewFakeDependencies()
+	podController := NewPodController(deps)
+	nodeIndexer := deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+	podIndexer := deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	g.Expect(nodeIndexer.Add(node)).Should(Succeed())
+	pod := newTiKVPod(tc)
+	pod.Spec.NodeName = node.Name
+	g.Expect(podIndexer.Add(pod)).Should(Succeed())
+
+	// the node is not draining
+	updated, err := podController.syncPodForNodeDrain(pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(updated).Should(BeFalse())
+
+	// the node is tainted to be drained
+	node = node.DeepCopy()
+	node.Spec.Taints = []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}}
+	g.Expect(nodeIndexer.Update(node)).Should(Succeed())
+	updated, err = podController.syncPodForNodeDrain(pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(updated).Should(BeTrue())
+	g.Expect(pod.Annotations).Should(HaveKeyWithValue(v1alpha1.EvictLeaderAnnKey, v1alpha1.EvictLeaderValueNone))
+	g.Expect(pod.Annotations).Should(HaveKeyWithValue(v1alpha1.NodeDrainAnnKey, node.Name))
+
+	// the annotations are set only once
+	updated, err = podController.syncPodForNodeDrain(pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(updated).Should(BeFalse())
+
+	// the annotations are removed after the node is no longer draining
+	node = node.DeepCopy()
+	node.Spec.Taints = nil
+	g.Expect(nodeIndexer.Update(node)).Should(Succeed())
+	updated, err = podController.syncPodForNodeDrain(pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(updated).Should(BeTrue())
+	g.Expect(pod.Annotations).ShouldNot(HaveKey(v1alpha1.EvictLeaderAnnKey))
+	g.Expect(pod.Annotations).ShouldNot(HaveKey(v1alpha1.NodeDrainAnnKey))
+
+	// the annotation set by the user is left as it is
+	pod.Annotations[v1alpha1.EvictLeaderAnnKey] = v1alpha1.EvictLeaderValueDeletePod
+	node = node.DeepCopy()
+	node.Spec.Unschedulable = true
+	g.Expect(nodeIndexer.Update(node)).Should(Succeed())
+	updated, err = podController.syncPodForNodeDrain(pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(updated).Should(BeFalse())
+	g.Expect(pod.Annotations).Should(HaveKeyWithValue(v1alpha1.EvictLeaderAnnKey, v1alpha1.EvictLeaderValueDeletePod))
+
+	// the pods on the node are enqueued after the node is cordoned
+	oldNode := node.DeepCopy()
+	oldNode.Spec.Unschedulable = false
+	podController.enqueuePodsOnNode(oldNode, node)
+	g.Expect(podController.queue.Len()).Should(Equal(1))
+}
+
+func TestTiCDCPodSyncForDrain(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	deps := controller.NewFakeDependencies()
+	podController := NewPodController(deps)
+	cdcControl := deps.CDCControl.(*controller.FakeTiCDCControl)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        controller.TiCDCMemberName(tc.Name) + "-1",
+			Namespace:   tc.Namespace,
+			Annotations: map[string]string{v1alpha1.TiCDCDrainCaptureAnnKey: v1alpha1.DrainCaptureValueNone},
+		},
+	}
+	tableCount := 2
+	var drained []int32
+	cdcControl.ResignOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+		return true, nil
+	}
+	cdcControl.DrainCaptureFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int, bool, error) {
+		drained = append(drained, ordinal)
+		return tableCount, false, nil
+	}
+
+	result, err := podController.syncTiCDCPodForDrain(context.TODO(), pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(result.RequeueAfter).Should(Equal(podController.recheckLeaderCountDuration))
+	g.Expect(drained).Should(Equal([]int32{1}))
+
+	// the capture is drained again periodically after all tables are moved off
+	tableCount = 0
+	result, err = podController.syncTiCDCPodForDrain(context.TODO(), pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(result.RequeueAfter).Should(Equal(RequeueInterval))
+
+	// the pod without the annotation is not drained
+	delete(pod.Annotations, v1alpha1.TiCDCDrainCaptureAnnKey)
+	result, err = podController.syncTiCDCPodForDrain(context.TODO(), pod, tc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(result.RequeueAfter).Should(BeZero())
+	g.Expect(drained).Should(HaveLen(2))
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultMaintenanceWindowDuration is the default duration of the maintenance windows
+	defaultMaintenanceWindowDuration = 2 * time.Hour
+	// maxUpgradeDecisions is the max number of the decisions kept in the status
+	maxUpgradeDecisions = 10
+
+	upgradeReasonPaused              = "Paused"
+	upgradeReasonOutsideWindow       = "OutsideMaintenanceWindow"
+	upgradeReasonClusterNotReady     = "ClusterNotReady"
+	upgradeReasonInvalidVersion      = "InvalidVersion"
+	upgradeReasonInvalidSchedule     = "InvalidSchedule"
+	upgradeReasonInMaintenanceWindow = "InMaintenanceWindow"
+)
+
+// TidbClusterAutoUpgrader upgrades spec.version of a tidb cluster to the latest version allowed
+// by spec.upgradePolicy during the maintenance windows, and records the decisions in the status.
+// The new version is patched to the spec before the components are synced to it.
+type TidbClusterAutoUpgrader interface {
+	Upgrade(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterAutoUpgrader struct {
+	deps *controller.Dependencies
+	// now can be replaced in unit tests
+	now func() time.Time
+}
+
+// NewTidbClusterAutoUpgrader returns a TidbClusterAutoUpgrader
+func NewTidbClusterAutoUpgrader(deps *controller.Dependencies) TidbClusterAutoUpgrader {
+	return &tidbClusterAutoUpgrader{
+		deps: deps,
+		now:  time.Now,
+	}
+}
+
+var _ TidbClusterAutoUpgrader = &tidbClusterAutoUpgrader{}
+
+func (u *tidbClusterAutoUpgrader) Upgrade(tc *v1alpha1.TidbCluster) error {
+	policy := tc.Spec.UpgradePolicy
+	if policy == nil {
+		tc.Status.Upgrade = nil
+		return nil
+	}
+	if tc.Status.Upgrade == nil {
+		tc.Status.Upgrade = &v1alpha1.UpgradePolicyStatus{}
+	}
+	status := tc.Status.Upgrade
+	now := u.now()
+
+	target, err := latestAllowedVersion(policy)
+	if err != nil {
+		u.decide(tc, v1alpha1.UpgradeDecisionSkip, "", upgradeReasonInvalidVersion, err.Error())
+		return nil
+	}
+	status.TargetVersion = ""
+	if target != nil {
+		status.TargetVersion = target.Original()
+	}
+
+	sched, err := cron.ParseStandard(policy.Schedule)
+	if err != nil {
+		u.decide(tc, v1alpha1.UpgradeDecisionSkip, status.TargetVersion, upgradeReasonInvalidSchedule,
+			fmt.Sprintf("parse schedule %q failed: %v", policy.Schedule, err))
+		return nil
+	}
+	next := metav1.NewTime(sched.Next(now))
+	status.NextWindowTime = &next
+
+	if target == nil {
+		return nil
+	}
+	current, err := semver.NewVersion(tc.Spec.Version)
+	if err != nil {
+		u.decide(tc, v1alpha1.UpgradeDecisionSkip, target.Original(), upgradeReasonInvalidVersion,
+			fmt.Sprintf("spec.version %q is not a semantic version", tc.Spec.Version))
+		return nil
+	}
+	if !target.GreaterThan(current) {
+		return nil
+	}
+
+	duration := defaultMaintenanceWindowDuration
+	if policy.Duration != nil {
+		duration = policy.Duration.Duration
+	}
+	switch {
+	case policy.Paused:
+		u.decide(tc, v1alpha1.UpgradeDecisionSkip, target.Original(), upgradeReasonPaused, "the upgrade policy is paused")
+	case sched.Next(now.Add(-duration)).After(now):
+		u.decide(tc, v1alpha1.UpgradeDecisionSkip, target.Original(), upgradeReasonOutsideWindow,
+			fmt.Sprintf("waiting for the next maintenance window at %s", next.UTC().Format(time.RFC3339)))
+	case !isTidbClusterReady(tc):
+		u.decide(tc, v1alpha1.UpgradeDecisionSkip, target.Original(), upgradeReasonClusterNotReady,
+			"the cluster is not ready, the upgrade is postponed")
+	default:
+		if err := patchTidbClusterSpec(u.deps, tc, map[string]interface{}{"version": target.Original()}); err != nil {
+			return err
+		}
+		u.decide(tc, v1alpha1.UpgradeDecisionUpgrade, target.Original(), upgradeReasonInMaintenanceWindow,
+			fmt.Sprintf("upgrade from %s to %s", tc.Spec.Version, target.Original()))
+		klog.Infof("TidbCluster: [%s/%s] is upgraded from %s to %s by the upgrade policy", tc.Namespace, tc.Name, tc.Spec.Version, target.Original())
+		u.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "AutoUpgrade", "upgrade from %s to %s by the upgrade policy", tc.Spec.Version, target.Original())
+		tc.Spec.Version = target.Original()
+	}
+	return nil
+}
+
+// decide records the decision in the status, the decision same as the last one is not recorded again
+func (u *tidbClusterAutoUpgrader) decide(tc *v1alpha1.TidbCluster, action v1alpha1.UpgradeDecisionAction, toVersion, reason, message string) {
+	status := tc.Status.Upgrade
+	if n := len(status.Decisions); n > 0 {
+		last := status.Decisions[n-1]
+		if last.Action == action && last.FromVersion == tc.Spec.Version && last.ToVersion == toVersion && last.Reason == reason {
+			return
+		}
+	}
+	status.Decisions = append(status.Decisions, v1alpha1.UpgradeDecision{
+		Time:        metav1.NewTime(u.now()),
+		Action:      action,
+		FromVersion: tc.Spec.Version,
+		ToVersion:   toVersion,
+		Reason:      reason,
+		Message:     message,
+	})
+	if n := len(status.Decisions); n > maxUpgradeDecisions {
+		status.Decisions = status.Decisions[n-maxUpgradeDecisions:]
+	}
+}
+
+// patchTidbClusterSpec persists the changes of the spec made by the sync before the components are synced,
+// as the status update at the end of the sync is skipped for an unchanged status and keeps only the status
+// on conflicts. The resource version fails the patch if the tc is changed since it's read.
+func patchTidbClusterSpec(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, spec map[string]interface{}) error {
+	metadata := map[string]interface{}{}
+	if tc.ResourceVersion != "" {
+		metadata["resourceVersion"] = tc.ResourceVersion
+	}
+	data, err := json.Marshal(map[string]interface{}{"metadata": metadata, "spec": spec})
+	if err != nil {
+		return err
+	}
+	updated, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Patch(context.TODO(), tc.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("patch the spec of tc %s/%s failed: %v", tc.Namespace, tc.Name, err)
+	}
+	tc.ResourceVersion = updated.ResourceVersion
+	tc.Generation = updated.Generation
+	return nil
+}
+
+// latestAllowedVersion returns the latest version in the versions of the policy
+// within the version range, nil is returned if there is no such version
+func latestAllowedVersion(policy *v1alpha1.UpgradePolicy) (*semver.Version, error) {
+	constraint, err := semver.NewConstraint(policy.VersionRange)
+	if err != nil {
+		return nil, fmt.Errorf("parse version range %q failed: %v", policy.VersionRange, err)
+	}
+	var latest *semver.Version
+	for _, version := range policy.Versions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return nil, fmt.Errorf("parse version %q failed: %v", version, err)
+		}
+		if constraint.Check(v) && (latest == nil || v.GreaterThan(latest)) {
+			latest = v
+		}
+	}
+	return latest, nil
+}
+
+func isTidbClusterReady(tc *v1alpha1.TidbCluster) bool {
+	cond := utiltidbcluster.GetTidbClusterReadyCondition(tc.Status)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+type fakeTidbClusterAutoUpgrader struct{}
+
+// NewFakeTidbClusterAutoUpgrader returns a fake TidbClusterAutoUpgrader
+func NewFakeTidbClusterAutoUpgrader() TidbClusterAutoUpgrader {
+	return &fakeTidbClusterAutoUpgrader{}
+}
+
+func (u *fakeTidbClusterAutoUpgrader) Upgrade(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
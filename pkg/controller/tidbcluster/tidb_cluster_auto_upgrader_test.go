@@ -0,0 +1,129 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterAutoUpgrader(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the maintenance windows begin at 02:00 every Saturday
+	now := time.Date(2024, 6, 7, 12, 0, 0, 0, time.Local) // Friday
+	deps := controller.NewFakeDependencies()
+	upgrader := NewTidbClusterAutoUpgrader(deps).(*tidbClusterAutoUpgrader)
+	upgrader.now = func() time.Time { return now }
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.TidbClusterSpec{
+			Version: "v7.5.0",
+			UpgradePolicy: &v1alpha1.UpgradePolicy{
+				VersionRange: "~7.5",
+				Versions:     []string{"v7.1.5", "v7.5.0", "v7.5.2", "v7.5.1", "v8.1.0"},
+				Schedule:     "0 2 * * 6",
+			},
+		},
+	}
+
+	// outside the maintenance window
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Spec.Version).To(Equal("v7.5.0"))
+	g.Expect(tc.Status.Upgrade.TargetVersion).To(Equal("v7.5.2"))
+	g.Expect(tc.Status.Upgrade.NextWindowTime.Time).To(Equal(time.Date(2024, 6, 8, 2, 0, 0, 0, time.Local)))
+	g.Expect(tc.Status.Upgrade.Decisions).To(HaveLen(1))
+	g.Expect(tc.Status.Upgrade.Decisions[0].Reason).To(Equal(upgradeReasonOutsideWindow))
+
+	// the same decision is not recorded again
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Status.Upgrade.Decisions).To(HaveLen(1))
+
+	// in the maintenance window but the cluster is not ready
+	now = time.Date(2024, 6, 8, 3, 0, 0, 0, time.Local)
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Spec.Version).To(Equal("v7.5.0"))
+	g.Expect(tc.Status.Upgrade.Decisions).To(HaveLen(2))
+	g.Expect(tc.Status.Upgrade.Decisions[1].Reason).To(Equal(upgradeReasonClusterNotReady))
+
+	// paused
+	tc.Status.Conditions = []v1alpha1.TidbClusterCondition{{Type: v1alpha1.TidbClusterReady, Status: corev1.ConditionTrue}}
+	tc.Spec.UpgradePolicy.Paused = true
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Spec.Version).To(Equal("v7.5.0"))
+	g.Expect(tc.Status.Upgrade.Decisions[2].Reason).To(Equal(upgradeReasonPaused))
+
+	// the version isn't changed if it fails to be persisted
+	tc.Spec.UpgradePolicy.Paused = false
+	g.Expect(upgrader.Upgrade(tc)).NotTo(Succeed())
+	g.Expect(tc.Spec.Version).To(Equal("v7.5.0"))
+	g.Expect(tc.Status.Upgrade.Decisions).To(HaveLen(3))
+
+	// upgraded to the latest patch release
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Spec.Version).To(Equal("v7.5.2"))
+	persisted, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(persisted.Spec.Version).To(Equal("v7.5.2"))
+	g.Expect(tc.Status.Upgrade.Decisions).To(HaveLen(4))
+	g.Expect(tc.Status.Upgrade.Decisions[3]).To(Equal(v1alpha1.UpgradeDecision{
+		Time:        metav1.NewTime(now),
+		Action:      v1alpha1.UpgradeDecisionUpgrade,
+		FromVersion: "v7.5.0",
+		ToVersion:   "v7.5.2",
+		Reason:      upgradeReasonInMaintenanceWindow,
+		Message:     "upgrade from v7.5.0 to v7.5.2",
+	}))
+
+	// nothing is done if the cluster is up to date
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Status.Upgrade.Decisions).To(HaveLen(4))
+
+	// the invalid schedule is recorded
+	tc.Spec.UpgradePolicy.Versions = append(tc.Spec.UpgradePolicy.Versions, "v7.5.3")
+	tc.Spec.UpgradePolicy.Schedule = "every saturday"
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Spec.Version).To(Equal("v7.5.2"))
+	g.Expect(tc.Status.Upgrade.Decisions[4].Reason).To(Equal(upgradeReasonInvalidSchedule))
+
+	// the status is removed with the policy
+	tc.Spec.UpgradePolicy = nil
+	g.Expect(upgrader.Upgrade(tc)).To(Succeed())
+	g.Expect(tc.Status.Upgrade).To(BeNil())
+}
+
+func TestUpgradeDecisionsLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	upgrader := NewTidbClusterAutoUpgrader(controller.NewFakeDependencies()).(*tidbClusterAutoUpgrader)
+	tc := &v1alpha1.TidbCluster{
+		Status: v1alpha1.TidbClusterStatus{Upgrade: &v1alpha1.UpgradePolicyStatus{}},
+	}
+	for i := 0; i < maxUpgradeDecisions+2; i++ {
+		tc.Spec.Version = "v7.5." + string(rune('a'+i))
+		upgrader.decide(tc, v1alpha1.UpgradeDecisionSkip, "v7.5.2", upgradeReasonPaused, "")
+	}
+	g.Expect(tc.Status.Upgrade.Decisions).To(HaveLen(maxUpgradeDecisions))
+	g.Expect(tc.Status.Upgrade.Decisions[0].FromVersion).To(Equal("v7.5.c"))
+}
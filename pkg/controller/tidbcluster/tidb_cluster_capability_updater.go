@@ -0,0 +1,69 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TidbClusterCapabilityUpdater interface that explains in the conditions of a tidb cluster
+// which features used by the cluster are degraded because they are not supported by the
+// Kubernetes cluster.
+type TidbClusterCapabilityUpdater interface {
+	Update(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterCapabilityUpdater struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterCapabilityUpdater returns a TidbClusterCapabilityUpdater
+func NewTidbClusterCapabilityUpdater(deps *controller.Dependencies) TidbClusterCapabilityUpdater {
+	return &tidbClusterCapabilityUpdater{
+		deps: deps,
+	}
+}
+
+var _ TidbClusterCapabilityUpdater = &tidbClusterCapabilityUpdater{}
+
+func (u *tidbClusterCapabilityUpdater) Update(tc *v1alpha1.TidbCluster) error {
+	var degraded []string
+	if u.deps.Capabilities != nil {
+		degraded = u.deps.Capabilities.DegradedFeatures(tc)
+	}
+	if len(degraded) == 0 {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterCapabilitiesDegraded)
+		return nil
+	}
+	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterCapabilitiesDegraded, corev1.ConditionTrue,
+		utiltidbcluster.CapabilitiesNotSupported, strings.Join(degraded, "; "))
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+	return nil
+}
+
+type fakeTidbClusterCapabilityUpdater struct{}
+
+// NewFakeTidbClusterCapabilityUpdater returns a fake TidbClusterCapabilityUpdater
+func NewFakeTidbClusterCapabilityUpdater() TidbClusterCapabilityUpdater {
+	return &fakeTidbClusterCapabilityUpdater{}
+}
+
+func (u *fakeTidbClusterCapabilityUpdater) Update(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTidbClusterCapabilityUpdater(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	updater := NewTidbClusterCapabilityUpdater(deps)
+	tc := &v1alpha1.TidbCluster{}
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{LogTailer: &v1alpha1.LogTailerSpec{UseSidecar: true}}
+
+	// all features are supported
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterCapabilitiesDegraded)).To(BeNil())
+
+	// native sidecars are not supported
+	deps.Capabilities = &controller.Capabilities{ServerVersion: "v1.27.3", VolumeExpansion: true}
+	g.Expect(updater.Update(tc)).To(Succeed())
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterCapabilitiesDegraded)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.CapabilitiesNotSupported))
+	g.Expect(cond.Message).To(ContainSubstring("the log tailers of tikv run as regular containers"))
+
+	// the condition is removed if the feature is not used any more
+	tc.Spec.TiKV.LogTailer.UseSidecar = false
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterCapabilitiesDegraded)).To(BeNil())
+}
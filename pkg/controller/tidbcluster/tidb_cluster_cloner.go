@@ -0,0 +1,223 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// TidbClusterCloner clones the cluster in spec.clusterCloneFrom to a new cluster by a backup of
+// the source cluster, which is restored to the new cluster, and reports the progress in the status.
+// A failed clone is not retried, the backup and the restore are kept to be investigated.
+type TidbClusterCloner interface {
+	Clone(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterCloner struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterCloner returns a TidbClusterCloner
+func NewTidbClusterCloner(deps *controller.Dependencies) TidbClusterCloner {
+	return &tidbClusterCloner{deps: deps}
+}
+
+var _ TidbClusterCloner = &tidbClusterCloner{}
+
+func (c *tidbClusterCloner) Clone(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.ClusterCloneFrom == nil || tc.IsCloneFinished() {
+		return nil
+	}
+	if tc.Status.Clone == nil {
+		if tc.Status.ClusterID != "" {
+			// the data of the cluster would be overwritten by the restore
+			c.setPhase(tc, v1alpha1.ClusterCloneFailed, "only a new cluster can be cloned, the cluster is already bootstrapped")
+			return nil
+		}
+		tc.Status.Clone = &v1alpha1.ClusterCloneStatus{Phase: v1alpha1.ClusterCloneBackup}
+	}
+
+	backup, err := c.syncBackup(tc)
+	if err != nil || backup == nil {
+		return err
+	}
+	return c.syncRestore(tc, backup)
+}
+
+// syncBackup creates the backup of the source cluster, and returns it once it's complete
+func (c *tidbClusterCloner) syncBackup(tc *v1alpha1.TidbCluster) (*v1alpha1.Backup, error) {
+	ns, tcName := tc.GetNamespace(), tc.GetName()
+
+	backup, err := c.deps.BackupLister.Backups(ns).Get(tc.CloneBackupName())
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("tidbcluster: [%s/%s] get clone backup %s failed: %v", ns, tcName, tc.CloneBackupName(), err)
+	}
+	if errors.IsNotFound(err) {
+		backup, err = c.deps.Clientset.PingcapV1alpha1().Backups(ns).Create(context.TODO(), c.newBackup(tc), metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("tidbcluster: [%s/%s] create clone backup %s failed: %v", ns, tcName, tc.CloneBackupName(), err)
+		}
+		klog.Infof("tidbcluster: [%s/%s] created backup %s of %s/%s to clone", ns, tcName, backup.Name, tc.CloneSourceNamespace(), tc.Spec.ClusterCloneFrom.Name)
+	}
+
+	tc.Status.Clone.Backup = backup.Name
+	switch {
+	case v1alpha1.IsBackupFailed(backup), v1alpha1.IsBackupInvalid(backup):
+		c.setPhase(tc, v1alpha1.ClusterCloneFailed, fmt.Sprintf("backup %s is failed or invalid", backup.Name))
+		return nil, nil
+	case !v1alpha1.IsBackupComplete(backup):
+		c.setPhase(tc, v1alpha1.ClusterCloneBackup, fmt.Sprintf("backup %s of %s/%s is running", backup.Name, tc.CloneSourceNamespace(), tc.Spec.ClusterCloneFrom.Name))
+		return nil, nil
+	}
+	return backup, nil
+}
+
+// syncRestore restores the backup to the cluster. The BR restore waits for the PD and TiKV of the cluster
+// to be ready, while the volume snapshots are restored before TiKV starts in the recovery mode.
+func (c *tidbClusterCloner) syncRestore(tc *v1alpha1.TidbCluster, backup *v1alpha1.Backup) error {
+	ns, tcName := tc.GetNamespace(), tc.GetName()
+
+	restore, err := c.deps.RestoreLister.Restores(ns).Get(tc.CloneRestoreName())
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("tidbcluster: [%s/%s] get clone restore %s failed: %v", ns, tcName, tc.CloneRestoreName(), err)
+	}
+	if errors.IsNotFound(err) {
+		switch tc.CloneMethod() {
+		case v1alpha1.ClusterCloneMethodVolumeSnapshot:
+			if !tc.Spec.RecoveryMode {
+				c.setPhase(tc, v1alpha1.ClusterCloneFailed, "spec.recoveryMode must be enabled to restore the volume snapshots")
+				return nil
+			}
+		default:
+			if !tc.PDAllMembersReady() || !tc.TiKVAllStoresReady() {
+				c.setPhase(tc, v1alpha1.ClusterCloneRestore, "waiting for the PD and TiKV to be ready to restore")
+				return nil
+			}
+		}
+		restore, err = c.deps.Clientset.PingcapV1alpha1().Restores(ns).Create(context.TODO(), c.newRestore(tc, backup), metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("tidbcluster: [%s/%s] create clone restore %s failed: %v", ns, tcName, tc.CloneRestoreName(), err)
+		}
+		klog.Infof("tidbcluster: [%s/%s] created restore %s of backup %s to clone", ns, tcName, restore.Name, backup.Name)
+	}
+
+	tc.Status.Clone.Restore = restore.Name
+	switch {
+	case v1alpha1.IsRestoreComplete(restore):
+		now := metav1.Now()
+		tc.Status.Clone.CompleteTime = &now
+		msg := fmt.Sprintf("cloned from %s/%s by restore %s", tc.CloneSourceNamespace(), tc.Spec.ClusterCloneFrom.Name, restore.Name)
+		c.setPhase(tc, v1alpha1.ClusterCloneComplete, msg)
+		c.deps.Recorder.Event(tc, corev1.EventTypeNormal, "Cloned", msg)
+	case v1alpha1.IsRestoreFailed(restore), v1alpha1.IsRestoreInvalid(restore):
+		c.setPhase(tc, v1alpha1.ClusterCloneFailed, fmt.Sprintf("restore %s is failed or invalid", restore.Name))
+	default:
+		c.setPhase(tc, v1alpha1.ClusterCloneRestore, fmt.Sprintf("restore %s is running", restore.Name))
+	}
+	return nil
+}
+
+func (c *tidbClusterCloner) setPhase(tc *v1alpha1.TidbCluster, phase v1alpha1.ClusterClonePhase, message string) {
+	if tc.Status.Clone == nil {
+		tc.Status.Clone = &v1alpha1.ClusterCloneStatus{}
+	}
+	if phase == v1alpha1.ClusterCloneFailed && tc.Status.Clone.Phase != phase {
+		c.deps.Recorder.Event(tc, corev1.EventTypeWarning, "CloneFailed", message)
+	}
+	tc.Status.Clone.Phase = phase
+	tc.Status.Clone.Message = message
+}
+
+func (c *tidbClusterCloner) newBackup(tc *v1alpha1.TidbCluster) *v1alpha1.Backup {
+	from := tc.Spec.ClusterCloneFrom
+	mode := v1alpha1.BackupModeSnapshot
+	if tc.CloneMethod() == v1alpha1.ClusterCloneMethodVolumeSnapshot {
+		mode = v1alpha1.BackupModeVolumeSnapshot
+	}
+	br := &v1alpha1.BRConfig{}
+	if from.BR != nil {
+		br = from.BR.DeepCopy()
+	}
+	br.Cluster = from.Name
+	br.ClusterNamespace = tc.CloneSourceNamespace()
+	return &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            tc.CloneBackupName(),
+			Namespace:       tc.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: v1alpha1.BackupSpec{
+			Mode:            mode,
+			StorageProvider: cloneStorageProvider(from),
+			BR:              br,
+			ToolImage:       from.ToolImage,
+			ServiceAccount:  from.ServiceAccount,
+		},
+	}
+}
+
+func (c *tidbClusterCloner) newRestore(tc *v1alpha1.TidbCluster, backup *v1alpha1.Backup) *v1alpha1.Restore {
+	from := tc.Spec.ClusterCloneFrom
+	mode := v1alpha1.RestoreModeSnapshot
+	if tc.CloneMethod() == v1alpha1.ClusterCloneMethodVolumeSnapshot {
+		mode = v1alpha1.RestoreModeVolumeSnapshot
+	}
+	br := &v1alpha1.BRConfig{}
+	if from.BR != nil {
+		br = from.BR.DeepCopy()
+	}
+	br.Cluster = tc.GetName()
+	br.ClusterNamespace = tc.GetNamespace()
+	return &v1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            tc.CloneRestoreName(),
+			Namespace:       tc.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: v1alpha1.RestoreSpec{
+			Mode:            mode,
+			StorageProvider: *backup.Spec.StorageProvider.DeepCopy(),
+			BR:              br,
+			ToolImage:       from.ToolImage,
+			ServiceAccount:  from.ServiceAccount,
+		},
+	}
+}
+
+func cloneStorageProvider(from *v1alpha1.ClusterCloneFrom) v1alpha1.StorageProvider {
+	return v1alpha1.StorageProvider{
+		S3:     from.S3.DeepCopy(),
+		Gcs:    from.Gcs.DeepCopy(),
+		Azblob: from.Azblob.DeepCopy(),
+	}
+}
+
+type fakeTidbClusterCloner struct{}
+
+// NewFakeTidbClusterCloner returns a fake TidbClusterCloner
+func NewFakeTidbClusterCloner() TidbClusterCloner {
+	return &fakeTidbClusterCloner{}
+}
+
+func (c *fakeTidbClusterCloner) Clone(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTidbClusterForClone() *v1alpha1.TidbCluster {
+	return &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "staging-ns"},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{Replicas: 1},
+			TiKV: &v1alpha1.TiKVSpec{Replicas: 1},
+			ClusterCloneFrom: &v1alpha1.ClusterCloneFrom{
+				TidbClusterRef: v1alpha1.TidbClusterRef{Name: "prod", Namespace: "prod-ns"},
+				S3:             &v1alpha1.S3StorageProvider{Bucket: "clone"},
+			},
+		},
+	}
+}
+
+func TestTidbClusterClonerBR(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	cloner := NewTidbClusterCloner(deps)
+	tc := newTidbClusterForClone()
+
+	// the backup of the source cluster is created
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	g.Expect(tc.Status.Clone.Phase).To(Equal(v1alpha1.ClusterCloneBackup))
+	g.Expect(tc.Status.Clone.Backup).To(Equal("staging-clone-backup"))
+	backup, err := deps.Clientset.PingcapV1alpha1().Backups(tc.Namespace).Get(context.TODO(), "staging-clone-backup", metav1.GetOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(backup.Spec.Mode).To(Equal(v1alpha1.BackupModeSnapshot))
+	g.Expect(backup.Spec.BR.Cluster).To(Equal("prod"))
+	g.Expect(backup.Spec.BR.ClusterNamespace).To(Equal("prod-ns"))
+	g.Expect(backup.Spec.S3.Bucket).To(Equal("clone"))
+	g.Expect(backup.OwnerReferences).To(HaveLen(1))
+
+	// the restore waits for the PD and TiKV to be ready
+	backup.Status.Conditions = []v1alpha1.BackupCondition{{Type: v1alpha1.BackupComplete, Status: corev1.ConditionTrue}}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().Backups().Informer().GetIndexer().Add(backup)).To(Succeed())
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	g.Expect(tc.Status.Clone.Phase).To(Equal(v1alpha1.ClusterCloneRestore))
+	g.Expect(tc.Status.Clone.Restore).To(BeEmpty())
+
+	tc.Status.ClusterID = "1"
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{"staging-pd-0": {Health: true}}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{"1": {State: v1alpha1.TiKVStateUp}}
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	g.Expect(tc.Status.Clone.Restore).To(Equal("staging-clone-restore"))
+	restore, err := deps.Clientset.PingcapV1alpha1().Restores(tc.Namespace).Get(context.TODO(), "staging-clone-restore", metav1.GetOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(restore.Spec.Mode).To(Equal(v1alpha1.RestoreModeSnapshot))
+	g.Expect(restore.Spec.BR.Cluster).To(Equal("staging"))
+	g.Expect(restore.Spec.BR.ClusterNamespace).To(Equal("staging-ns"))
+	g.Expect(restore.Spec.S3.Bucket).To(Equal("clone"))
+
+	// the clone is complete once the restore is complete
+	restore.Status.Conditions = []v1alpha1.RestoreCondition{{Type: v1alpha1.RestoreComplete, Status: corev1.ConditionTrue}}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().Restores().Informer().GetIndexer().Add(restore)).To(Succeed())
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	g.Expect(tc.Status.Clone.Phase).To(Equal(v1alpha1.ClusterCloneComplete))
+	g.Expect(tc.Status.Clone.CompleteTime).NotTo(BeNil())
+	g.Expect(tc.IsCloneFinished()).To(BeTrue())
+}
+
+func TestTidbClusterClonerFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// a bootstrapped cluster is not cloned
+	deps := controller.NewFakeDependencies()
+	cloner := NewTidbClusterCloner(deps)
+	tc := newTidbClusterForClone()
+	tc.Status.ClusterID = "1"
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	g.Expect(tc.Status.Clone.Phase).To(Equal(v1alpha1.ClusterCloneFailed))
+	_, err := deps.Clientset.PingcapV1alpha1().Backups(tc.Namespace).Get(context.TODO(), "staging-clone-backup", metav1.GetOptions{})
+	g.Expect(err).NotTo(Succeed())
+
+	// the volume snapshots are restored in the recovery mode only
+	deps = controller.NewFakeDependencies()
+	cloner = NewTidbClusterCloner(deps)
+	tc = newTidbClusterForClone()
+	tc.Spec.ClusterCloneFrom.Method = v1alpha1.ClusterCloneMethodVolumeSnapshot
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	backup, err := deps.Clientset.PingcapV1alpha1().Backups(tc.Namespace).Get(context.TODO(), "staging-clone-backup", metav1.GetOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(backup.Spec.Mode).To(Equal(v1alpha1.BackupModeVolumeSnapshot))
+	backup.Status.Conditions = []v1alpha1.BackupCondition{{Type: v1alpha1.BackupComplete, Status: corev1.ConditionTrue}}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().Backups().Informer().GetIndexer().Add(backup)).To(Succeed())
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	g.Expect(tc.Status.Clone.Phase).To(Equal(v1alpha1.ClusterCloneFailed))
+
+	// nothing is done after the clone fails
+	tc.Spec.RecoveryMode = true
+	g.Expect(cloner.Clone(tc)).To(Succeed())
+	_, err = deps.Clientset.PingcapV1alpha1().Restores(tc.Namespace).Get(context.TODO(), "staging-clone-restore", metav1.GetOptions{})
+	g.Expect(err).NotTo(Succeed())
+}
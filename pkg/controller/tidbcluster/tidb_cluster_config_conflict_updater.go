@@ -0,0 +1,73 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+const configDefaultsOverridden = "ConfigDefaultsOverridden"
+
+// TidbClusterConfigConflictUpdater interface that reports the items of the config defaults of the
+// operator overridden by the config of the components of a tidb cluster in the status, so the
+// platform teams can find the clusters deviating from the baselines.
+type TidbClusterConfigConflictUpdater interface {
+	Update(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterConfigConflictUpdater struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterConfigConflictUpdater returns a TidbClusterConfigConflictUpdater
+func NewTidbClusterConfigConflictUpdater(deps *controller.Dependencies) TidbClusterConfigConflictUpdater {
+	return &tidbClusterConfigConflictUpdater{
+		deps: deps,
+	}
+}
+
+var _ TidbClusterConfigConflictUpdater = &tidbClusterConfigConflictUpdater{}
+
+func (u *tidbClusterConfigConflictUpdater) Update(tc *v1alpha1.TidbCluster) error {
+	conflicts := u.deps.ConfigDefaults.Conflicts(tc)
+	if apiequality.Semantic.DeepEqual(conflicts, tc.Status.ConfigConflicts) {
+		return nil
+	}
+	if len(conflicts) > 0 {
+		items := make([]string, 0, len(conflicts))
+		for _, c := range conflicts {
+			items = append(items, fmt.Sprintf("%s %s=%s (default %s)", c.Component, c.Key, c.Value, c.Default))
+		}
+		u.deps.Recorder.Event(tc, corev1.EventTypeNormal, configDefaultsOverridden, strings.Join(items, "; "))
+	}
+	tc.Status.ConfigConflicts = conflicts
+	return nil
+}
+
+type fakeTidbClusterConfigConflictUpdater struct{}
+
+// NewFakeTidbClusterConfigConflictUpdater returns a fake TidbClusterConfigConflictUpdater
+func NewFakeTidbClusterConfigConflictUpdater() TidbClusterConfigConflictUpdater {
+	return &fakeTidbClusterConfigConflictUpdater{}
+}
+
+func (u *fakeTidbClusterConfigConflictUpdater) Update(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
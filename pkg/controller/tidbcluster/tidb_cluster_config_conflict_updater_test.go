@@ -0,0 +1,56 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTidbClusterConfigConflictUpdater(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	recorder := deps.Recorder.(*record.FakeRecorder)
+	var err error
+	deps.ConfigDefaults, err = controller.ParseConfigDefaults([]byte("pd: |\n  [security]\n  redact-info-log = true\n"))
+	g.Expect(err).Should(Succeed())
+	updater := NewTidbClusterConfigConflictUpdater(deps)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Spec.PD = &v1alpha1.PDSpec{Config: v1alpha1.NewPDConfig()}
+	g.Expect(updater.Update(tc)).Should(Succeed())
+	g.Expect(tc.Status.ConfigConflicts).Should(BeEmpty())
+	g.Expect(recorder.Events).Should(BeEmpty())
+
+	// the overridden item is reported once
+	tc.Spec.PD.Config.Set("security.redact-info-log", false)
+	g.Expect(updater.Update(tc)).Should(Succeed())
+	g.Expect(tc.Status.ConfigConflicts).Should(Equal([]v1alpha1.ConfigConflict{
+		{Component: v1alpha1.PDMemberType, Key: "security.redact-info-log", Default: "true", Value: "false"},
+	}))
+	g.Expect(recorder.Events).Should(HaveLen(1))
+	g.Expect(<-recorder.Events).Should(ContainSubstring("pd security.redact-info-log=false (default true)"))
+	g.Expect(updater.Update(tc)).Should(Succeed())
+	g.Expect(recorder.Events).Should(BeEmpty())
+
+	// the status is cleared after the conflict is resolved
+	tc.Spec.PD.Config.Del("security.redact-info-log")
+	g.Expect(updater.Update(tc)).Should(Succeed())
+	g.Expect(tc.Status.ConfigConflicts).Should(BeEmpty())
+}
@@ -14,6 +14,12 @@
 package tidbcluster
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
@@ -23,11 +29,14 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/volumes"
 	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // ControlInterface implements the control logic for updating TidbClusters and their children StatefulSets.
@@ -48,6 +57,7 @@ func NewDefaultTidbClusterControl(
 	tidbMemberManager manager.Manager,
 	tiproxyMemberManager manager.Manager,
 	reclaimPolicyManager manager.Manager,
+	deletionPolicyManager manager.Manager,
 	metaManager manager.Manager,
 	orphanPodsCleaner member.OrphanPodsCleaner,
 	pvcCleaner member.PVCCleanerInterface,
@@ -60,6 +70,21 @@ func NewDefaultTidbClusterControl(
 	discoveryManager member.TidbDiscoveryManager,
 	tidbClusterStatusManager manager.Manager,
 	conditionUpdater TidbClusterConditionUpdater,
+	suggestedActionUpdater TidbClusterSuggestedActionUpdater,
+	zoneDistributionUpdater TidbClusterZoneDistributionUpdater,
+	hostPortUpdater TidbClusterHostPortUpdater,
+	configConflictUpdater TidbClusterConfigConflictUpdater,
+	capabilityUpdater TidbClusterCapabilityUpdater,
+	selfTester TidbClusterSelfTester,
+	autoUpgrader TidbClusterAutoUpgrader,
+	tiflashReplicaSyncer TiFlashReplicaSyncer,
+	cloner TidbClusterCloner,
+	driftDetector TidbClusterDriftDetector,
+	resourceRecommender TidbClusterResourceRecommender,
+	profileCapturer TidbClusterProfileCapturer,
+	podMonitorSyncer TidbClusterPodMonitorSyncer,
+	tlsSecretChecker TidbClusterTLSSecretChecker,
+	disruptionLock member.DisruptionLock,
 	recorder record.EventRecorder) ControlInterface {
 	return &defaultTidbClusterControl{
 		tcControl:                tcControl,
@@ -69,6 +94,7 @@ func NewDefaultTidbClusterControl(
 		tidbMemberManager:        tidbMemberManager,
 		tiproxyMemberManager:     tiproxyMemberManager,
 		reclaimPolicyManager:     reclaimPolicyManager,
+		deletionPolicyManager:    deletionPolicyManager,
 		metaManager:              metaManager,
 		orphanPodsCleaner:        orphanPodsCleaner,
 		pvcCleaner:               pvcCleaner,
@@ -80,10 +106,35 @@ func NewDefaultTidbClusterControl(
 		discoveryManager:         discoveryManager,
 		tidbClusterStatusManager: tidbClusterStatusManager,
 		conditionUpdater:         conditionUpdater,
+		suggestedActionUpdater:   suggestedActionUpdater,
+		zoneDistributionUpdater:  zoneDistributionUpdater,
+		hostPortUpdater:          hostPortUpdater,
+		configConflictUpdater:    configConflictUpdater,
+		capabilityUpdater:        capabilityUpdater,
+		selfTester:               selfTester,
+		autoUpgrader:             autoUpgrader,
+		tiflashReplicaSyncer:     tiflashReplicaSyncer,
+		cloner:                   cloner,
+		driftDetector:            driftDetector,
+		resourceRecommender:      resourceRecommender,
+		profileCapturer:          profileCapturer,
+		podMonitorSyncer:         podMonitorSyncer,
+		tlsSecretChecker:         tlsSecretChecker,
+		disruptionLock:           disruptionLock,
 		recorder:                 recorder,
+		fullSyncs:                map[types.UID]fullSyncRecord{},
 	}
 }
 
+// fullSyncInterval is the longest time the member managers of a settled cluster are not synced
+const fullSyncInterval = 5 * time.Minute
+
+// fullSyncRecord records the last sync of the member managers of a cluster
+type fullSyncRecord struct {
+	annotations map[string]string
+	time        time.Time
+}
+
 type defaultTidbClusterControl struct {
 	tcControl                controller.TidbClusterControlInterface
 	pdMemberManager          manager.Manager
@@ -92,6 +143,7 @@ type defaultTidbClusterControl struct {
 	tidbMemberManager        manager.Manager
 	tiproxyMemberManager     manager.Manager
 	reclaimPolicyManager     manager.Manager
+	deletionPolicyManager    manager.Manager
 	metaManager              manager.Manager
 	orphanPodsCleaner        member.OrphanPodsCleaner
 	pvcCleaner               member.PVCCleanerInterface
@@ -103,20 +155,86 @@ type defaultTidbClusterControl struct {
 	discoveryManager         member.TidbDiscoveryManager
 	tidbClusterStatusManager manager.Manager
 	conditionUpdater         TidbClusterConditionUpdater
+	suggestedActionUpdater   TidbClusterSuggestedActionUpdater
+	zoneDistributionUpdater  TidbClusterZoneDistributionUpdater
+	hostPortUpdater          TidbClusterHostPortUpdater
+	configConflictUpdater    TidbClusterConfigConflictUpdater
+	capabilityUpdater        TidbClusterCapabilityUpdater
+	selfTester               TidbClusterSelfTester
+	autoUpgrader             TidbClusterAutoUpgrader
+	tiflashReplicaSyncer     TiFlashReplicaSyncer
+	cloner                   TidbClusterCloner
+	driftDetector            TidbClusterDriftDetector
+	resourceRecommender      TidbClusterResourceRecommender
+	profileCapturer          TidbClusterProfileCapturer
+	podMonitorSyncer         TidbClusterPodMonitorSyncer
+	tlsSecretChecker         TidbClusterTLSSecretChecker
+	disruptionLock           member.DisruptionLock
 	recorder                 record.EventRecorder
+
+	fullSyncLock sync.Mutex
+	fullSyncs    map[types.UID]fullSyncRecord
 }
 
 // UpdateTidbCluster executes the core logic loop for a tidbcluster.
-func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster) error {
+func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster) (err error) {
+	ctx, span := tracing.StartObject(context.Background(), "TidbCluster.Reconcile", tc.GetNamespace(), tc.GetName())
+	defer func() { tracing.End(span, err) }()
+	defer tracing.Bind(ctx)()
+
 	c.defaulting(tc)
+	// the cluster being deleted is cleaned up by its deletion policy instead of being synced
+	if tc.DeletionTimestamp != nil && controllerutil.ContainsFinalizer(tc, label.ClusterDeletionFinalizer) {
+		c.forgetFullSync(tc)
+		return c.deletionPolicyManager.Sync(tc)
+	}
 	if !c.validate(tc) {
 		return nil // fatal error, no need to retry on invalid object
 	}
 
 	var errs []error
 	oldStatus := tc.Status.DeepCopy()
+	oldSpec := tc.Spec.DeepCopy()
+
+	// the version upgraded by the upgrade policy is synced to the components in this round
+	if err := c.autoUpgrader.Upgrade(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	// the requests applied by the resource recommendation are synced to the components in this round
+	if err := c.resourceRecommender.Recommend(tc); err != nil {
+		errs = append(errs, err)
+	}
 
-	if err := c.updateTidbCluster(tc); err != nil {
+	// the clone is started before the cluster is bootstrapped, so that only a new cluster is cloned
+	if err := c.cloner.Clone(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	// the drifts of the child resources are reverted before they are synced
+	if err := c.driftDetector.Detect(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	// the members are not synced until the TLS secrets exist. If the spec of the observed generation has been
+	// acted upon, only the status is refreshed unless the cluster needs the member managers to reconcile it
+	if err := c.tlsSecretChecker.Check(tc); err != nil {
+		errs = append(errs, err)
+	} else if apiequality.Semantic.DeepEqual(&tc.Spec, oldSpec) && c.syncStatusOnly(ctx, tc) {
+		klog.V(4).Infof("tidb cluster %s/%s of generation %d is settled, only its status is refreshed", tc.GetNamespace(), tc.GetName(), tc.Generation)
+	} else if err := c.updateTidbCluster(ctx, tc); err != nil {
+		errs = append(errs, err)
+	} else {
+		tc.Status.ObservedGeneration = tc.Generation
+		c.recordFullSync(tc)
+	}
+
+	// the disruption lock of the shared PD is released once the components are neither upgrading nor scaling
+	if err := c.disruptionLock.Release(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.tiflashReplicaSyncer.Sync(tc); err != nil {
 		errs = append(errs, err)
 	}
 
@@ -124,10 +242,47 @@ func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster)
 		errs = append(errs, err)
 	}
 
+	if err := c.suggestedActionUpdater.Update(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.zoneDistributionUpdater.Update(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.hostPortUpdater.Update(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.configConflictUpdater.Update(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.capabilityUpdater.Update(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.selfTester.Test(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.profileCapturer.Capture(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.podMonitorSyncer.Sync(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	recordSyncHistory(tc, oldStatus, errs, time.Now())
+
 	if apiequality.Semantic.DeepEqual(&tc.Status, oldStatus) {
 		return errorutils.NewAggregate(errs)
 	}
-	if _, err := c.tcControl.UpdateTidbCluster(tc.DeepCopy(), &tc.Status, oldStatus); err != nil {
+	if err := tracing.Phase(ctx, "status", func() error {
+		_, err := c.tcControl.UpdateTidbCluster(tc.DeepCopy(), &tc.Status, oldStatus)
+		return err
+	}); err != nil {
 		errs = append(errs, err)
 	}
 
@@ -149,18 +304,24 @@ func (c *defaultTidbClusterControl) defaulting(tc *v1alpha1.TidbCluster) {
 	defaulting.SetTidbClusterDefault(tc)
 }
 
-func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster) error {
+func (c *defaultTidbClusterControl) updateTidbCluster(ctx context.Context, tc *v1alpha1.TidbCluster) error {
 	c.recordMetrics(tc)
 
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
 	// syncing all PVs managed by operator's reclaim policy to Retain
-	if err := c.reclaimPolicyManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "pv_reclaim_policy", func() error { return c.reclaimPolicyManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "pv_reclaim_policy").Inc()
 		return err
 	}
 
+	// keeping the deletion finalizer of the cluster in line with spec.deletionPolicy
+	if err := tracing.Phase(ctx, "deletion_policy", func() error { return c.deletionPolicyManager.Sync(tc) }); err != nil {
+		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "deletion_policy").Inc()
+		return err
+	}
+
 	// cleaning all orphan pods(pd, tikv or tiflash which don't have a related PVC) managed by operator
 	// this could be useful when failover run into an undesired situation as described in PD failover function
 	skipReasons, err := c.orphanPodsCleaner.Clean(tc)
@@ -175,7 +336,7 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	}
 
 	// reconcile TiDB discovery service
-	if err := c.discoveryManager.Reconcile(tc); err != nil {
+	if err := tracing.Phase(ctx, "discovery", func() error { return c.discoveryManager.Reconcile(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "discovery").Inc()
 		return err
 	}
@@ -192,11 +353,15 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - create or update the pdms headless service
 	//   - create the pdms statefulset
 	//   - sync pdms cluster status from pdms to TidbCluster object
-	//   - upgrade the pdms cluster
+	//   - upgrade the pdms cluster, the scheduling service is upgraded before the tso service,
+	//     and both of them are upgraded before the pd cluster
 	//   - scale out/in the pdms cluster
-	if err := c.pdMSMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "pdms", func() error { return c.pdMSMemberManager.Sync(tc) }); err != nil {
 		return err
 	}
+	for _, status := range tc.Status.PDMS {
+		status.SetObservedGeneration(tc.Generation)
+	}
 
 	// works that should be done to make the pd cluster current state match the desired state:
 	//   - create or update the pd service
@@ -209,10 +374,11 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the pd cluster
 	//   - scale out/in the pd cluster
 	//   - failover the pd cluster
-	if err := c.pdMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "pd", func() error { return c.pdMemberManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "pd").Inc()
 		return err
 	}
+	observeComponentGeneration(tc, v1alpha1.PDMemberType)
 
 	// works that should be done to make the tiproxy cluster current state match the desired state:
 	//   - create or update the tiproxy service
@@ -222,10 +388,11 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the tiproxy cluster
 	//   - scale out/in the tiproxy cluster
 	//   - failover the tiproxy cluster
-	if err := c.tiproxyMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "tiproxy", func() error { return c.tiproxyMemberManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "tiproxy").Inc()
 		return err
 	}
+	observeComponentGeneration(tc, v1alpha1.TiProxyMemberType)
 
 	// works that should be done to make the tiflash cluster current state match the desired state:
 	//   - waiting for the tidb cluster available
@@ -236,10 +403,11 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the tiflash cluster
 	//   - scale out/in the tiflash cluster
 	//   - failover the tiflash cluster
-	if err := c.tiflashMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "tiflash", func() error { return c.tiflashMemberManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "tiflash").Inc()
 		return err
 	}
+	observeComponentGeneration(tc, v1alpha1.TiFlashMemberType)
 
 	// works that should be done to make the tikv cluster current state match the desired state:
 	//   - waiting for the pd cluster available(pd cluster is in quorum)
@@ -250,16 +418,18 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the tikv cluster
 	//   - scale out/in the tikv cluster
 	//   - failover the tikv cluster
-	if err := c.tikvMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "tikv", func() error { return c.tikvMemberManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "tikv").Inc()
 		return err
 	}
+	observeComponentGeneration(tc, v1alpha1.TiKVMemberType)
 
 	// syncing the pump cluster
-	if err := c.pumpMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "pump", func() error { return c.pumpMemberManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "pump").Inc()
 		return err
 	}
+	observeComponentGeneration(tc, v1alpha1.PumpMemberType)
 
 	// works that should be done to make the tidb cluster current state match the desired state:
 	//   - waiting for the tikv cluster available(at least one peer works)
@@ -269,26 +439,28 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the tidb cluster
 	//   - scale out/in the tidb cluster
 	//   - failover the tidb cluster
-	if err := c.tidbMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "tidb", func() error { return c.tidbMemberManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "tidb").Inc()
 		return err
 	}
+	observeComponentGeneration(tc, v1alpha1.TiDBMemberType)
 
 	// works that should be done to make the ticdc cluster current state match the desired state:
 	//   - waiting for the pd cluster available(pd cluster is in quorum)
 	//   - waiting for the tikv cluster available(at least one peer works)
 	//   - create or update ticdc deployment
 	//   - sync ticdc cluster status from pd to TidbCluster object
-	if err := c.ticdcMemberManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "ticdc", func() error { return c.ticdcMemberManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "ticdc").Inc()
 		return err
 	}
+	observeComponentGeneration(tc, v1alpha1.TiCDCMemberType)
 
 	// syncing the labels from Pod to PVC and PV, these labels include:
 	//   - label.StoreIDLabelKey
 	//   - label.MemberIDLabelKey
 	//   - label.NamespaceLabelKey
-	if err := c.metaManager.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "meta", func() error { return c.metaManager.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "meta").Inc()
 		return err
 	}
@@ -307,27 +479,137 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 
 	// Replace volumes if necessary. Note: if enabled, takes precedence over pvcModifier.
 	if features.DefaultFeatureGate.Enabled(features.VolumeReplacing) || tc.IsPVCReplaceEnabled() {
-		if err := c.pvcReplacer.Sync(tc); err != nil {
+		if err := tracing.Phase(ctx, "pvc_replacer_sync", func() error { return c.pvcReplacer.Sync(tc) }); err != nil {
 			metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "pvc_replacer_sync").Inc()
 			return err
 		}
 	}
 
 	// modify volumes if necessary
-	if err := c.pvcModifier.Sync(tc); err != nil {
+	if err := tracing.Phase(ctx, "pvc_modifier", func() error { return c.pvcModifier.Sync(tc) }); err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "pvc_modifier").Inc()
 		return err
 	}
 
 	// syncing the some tidbcluster status attributes
 	// 	- sync tidbmonitor reference
-	err = c.tidbClusterStatusManager.Sync(tc)
+	err = tracing.Phase(ctx, "cluster_status", func() error { return c.tidbClusterStatusManager.Sync(tc) })
 	if err != nil {
 		metrics.ClusterUpdateErrors.WithLabelValues(ns, tcName, "cluster_status").Inc()
 	}
 	return err
 }
 
+// syncStatusOnly refreshes the status of the components without syncing the member managers if the spec of the
+// current generation has been acted upon and the cluster is settled. It returns false if the member managers need to
+// sync the cluster, e.g. the annotations are changed, a member is down or the last full sync is too old.
+func (c *defaultTidbClusterControl) syncStatusOnly(ctx context.Context, tc *v1alpha1.TidbCluster) bool {
+	if tc.Generation == 0 || tc.Generation != tc.Status.ObservedGeneration || len(tc.Spec.PDMS) > 0 {
+		return false
+	}
+	if !c.fullSyncFresh(tc) || !clusterSettled(tc) {
+		return false
+	}
+
+	managers := []manager.Manager{
+		c.pdMemberManager,
+		c.tiproxyMemberManager,
+		c.tiflashMemberManager,
+		c.tikvMemberManager,
+		c.pumpMemberManager,
+		c.tidbMemberManager,
+		c.ticdcMemberManager,
+	}
+	err := tracing.Phase(ctx, "status_refresh", func() error {
+		for _, m := range managers {
+			sm, ok := m.(manager.StatusManager)
+			if !ok {
+				return fmt.Errorf("%T can't sync the status only", m)
+			}
+			if err := sm.SyncStatus(tc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		klog.Warningf("failed to refresh the status of tidb cluster %s/%s, sync the members instead: %v", tc.GetNamespace(), tc.GetName(), err)
+		return false
+	}
+	// the refreshed status may show that the cluster needs to be reconciled
+	return clusterSettled(tc)
+}
+
+// clusterSettled returns whether all components of the cluster are up to date and ready, so there is nothing for
+// the member managers to reconcile
+func clusterSettled(tc *v1alpha1.TidbCluster) bool {
+	for _, status := range tc.AllComponentStatus() {
+		if status.GetPhase() != v1alpha1.NormalPhase || status.GetVolReplaceInProgress() {
+			return false
+		}
+		sts := status.GetStatefulSet()
+		if sts == nil || sts.CurrentRevision != sts.UpdateRevision || sts.ReadyReplicas != sts.Replicas {
+			return false
+		}
+	}
+	if tc.Spec.PD != nil && (!tc.PDAllMembersReady() || len(tc.Status.PD.FailureMembers) > 0) {
+		return false
+	}
+	if tc.Spec.TiKV != nil && (!tc.TiKVAllStoresReady() || len(tc.Status.TiKV.FailureStores) > 0) {
+		return false
+	}
+	if tc.Spec.TiDB != nil && (!tc.TiDBAllMembersReady() || len(tc.Status.TiDB.FailureMembers) > 0) {
+		return false
+	}
+	if tc.Spec.TiFlash != nil && (!tc.TiFlashAllStoresReady() || len(tc.Status.TiFlash.FailureStores) > 0) {
+		return false
+	}
+	if tc.Spec.TiCDC != nil && !tc.TiCDCAllCapturesReady() {
+		return false
+	}
+	if tc.Spec.TiProxy != nil && !tc.TiProxyAllMembersReady() {
+		return false
+	}
+	return true
+}
+
+// fullSyncFresh returns whether the member managers synced the cluster with the same annotations
+// within fullSyncInterval, the annotations aren't part of the generation but trigger the member managers
+func (c *defaultTidbClusterControl) fullSyncFresh(tc *v1alpha1.TidbCluster) bool {
+	c.fullSyncLock.Lock()
+	defer c.fullSyncLock.Unlock()
+	record, ok := c.fullSyncs[tc.GetUID()]
+	if !ok || time.Since(record.time) > fullSyncInterval {
+		return false
+	}
+	return apiequality.Semantic.DeepEqual(record.annotations, tc.GetAnnotations())
+}
+
+func (c *defaultTidbClusterControl) recordFullSync(tc *v1alpha1.TidbCluster) {
+	annotations := make(map[string]string, len(tc.GetAnnotations()))
+	for k, v := range tc.GetAnnotations() {
+		annotations[k] = v
+	}
+	c.fullSyncLock.Lock()
+	defer c.fullSyncLock.Unlock()
+	c.fullSyncs[tc.GetUID()] = fullSyncRecord{annotations: annotations, time: time.Now()}
+}
+
+func (c *defaultTidbClusterControl) forgetFullSync(tc *v1alpha1.TidbCluster) {
+	c.fullSyncLock.Lock()
+	defer c.fullSyncLock.Unlock()
+	delete(c.fullSyncs, tc.GetUID())
+}
+
+// observeComponentGeneration records the generation of the spec acted upon by the sync of the component
+func observeComponentGeneration(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType) {
+	for _, status := range tc.AllComponentStatus() {
+		if status.MemberType() == memberType {
+			status.SetObservedGeneration(tc.Generation)
+		}
+	}
+}
+
 func (c *defaultTidbClusterControl) recordMetrics(tc *v1alpha1.TidbCluster) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
@@ -295,6 +295,105 @@ func TestTidbClusterStatusEquality(t *testing.T) {
 	g.Expect(apiequality.Semantic.DeepEqual(&tcStatus, tcStatusCopy)).To(Equal(false))
 }
 
+func TestTidbClusterControlObservedGeneration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Generation = 3
+	control, _, _, _, tikvMemberManager, _, _, _, _ := newFakeTidbClusterControl()
+
+	tikvMemberManager.SetSyncError(fmt.Errorf("tikv member manager sync error"))
+	err := control.UpdateTidbCluster(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(tc.Status.PD.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(tc.Status.TiKV.ObservedGeneration).To(Equal(int64(0)))
+	g.Expect(tc.Status.TiDB.ObservedGeneration).To(Equal(int64(0)))
+	g.Expect(tc.Status.ObservedGeneration).To(Equal(int64(0)))
+
+	tikvMemberManager.SetSyncError(nil)
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.TiKV.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(tc.Status.TiDB.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(tc.Status.ObservedGeneration).To(Equal(int64(3)))
+
+	// the spec of a new generation is acted upon again
+	tc.Generation = 4
+	tikvMemberManager.SetSyncError(fmt.Errorf("tikv member manager sync error"))
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(tc.Status.PD.ObservedGeneration).To(Equal(int64(4)))
+	g.Expect(tc.Status.TiKV.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(tc.Status.ObservedGeneration).To(Equal(int64(3)))
+}
+
+func TestTidbClusterControlSyncStatusOnly(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Generation = 3
+	control, _, _, _, tikvMemberManager, _, _, _, _ := newFakeTidbClusterControl()
+	err := control.UpdateTidbCluster(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.ObservedGeneration).To(Equal(int64(3)))
+
+	// the members are synced while the cluster isn't settled
+	tikvMemberManager.SetSyncError(fmt.Errorf("tikv member manager sync error"))
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).To(HaveOccurred())
+
+	// only the status of a settled cluster is refreshed when the generation has been observed
+	settleTidbClusterForTidbClusterControl(tc)
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the members are synced for a new generation
+	tc.Generation = 4
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(tc.Status.ObservedGeneration).To(Equal(int64(3)))
+
+	tikvMemberManager.SetSyncError(nil)
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.ObservedGeneration).To(Equal(int64(4)))
+
+	// the members are synced when the annotations are changed
+	tikvMemberManager.SetSyncError(fmt.Errorf("tikv member manager sync error"))
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	tc.Annotations = map[string]string{"tidb.pingcap.com/restartedAt": "now"}
+	err = control.UpdateTidbCluster(tc)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func settleTidbClusterForTidbClusterControl(tc *v1alpha1.TidbCluster) {
+	for _, status := range tc.AllComponentStatus() {
+		status.SetPhase(v1alpha1.NormalPhase)
+		status.SetStatefulSet(&apps.StatefulSetStatus{
+			Replicas:        2,
+			ReadyReplicas:   2,
+			CurrentRevision: "rev",
+			UpdateRevision:  "rev",
+		})
+	}
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{}
+	for i := 0; i < int(tc.Spec.PD.Replicas); i++ {
+		name := fmt.Sprintf("pd-%d", i)
+		tc.Status.PD.Members[name] = v1alpha1.PDMember{Name: name, Health: true}
+	}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+	for i := 0; i < int(tc.Spec.TiKV.Replicas); i++ {
+		id := fmt.Sprintf("%d", i)
+		tc.Status.TiKV.Stores[id] = v1alpha1.TiKVStore{ID: id, State: v1alpha1.TiKVStateUp}
+	}
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{}
+	for i := 0; i < int(tc.Spec.TiDB.Replicas); i++ {
+		name := fmt.Sprintf("tidb-%d", i)
+		tc.Status.TiDB.Members[name] = v1alpha1.TiDBMember{Name: name, Health: true}
+	}
+}
+
 func newFakeTidbClusterControl() (
 	ControlInterface,
 	*meta.FakeReclaimPolicyManager,
@@ -334,6 +433,7 @@ func newFakeTidbClusterControl() (
 		tidbMemberManager,
 		tiproxyMemberManager,
 		reclaimPolicyManager,
+		meta.NewFakeDeletionPolicyManager(),
 		metaManager,
 		orphanPodCleaner,
 		pvcCleaner,
@@ -345,6 +445,21 @@ func newFakeTidbClusterControl() (
 		discoveryManager,
 		statusManager,
 		&tidbClusterConditionUpdater{},
+		NewFakeTidbClusterSuggestedActionUpdater(),
+		NewFakeTidbClusterZoneDistributionUpdater(),
+		NewFakeTidbClusterHostPortUpdater(),
+		NewFakeTidbClusterConfigConflictUpdater(),
+		NewFakeTidbClusterCapabilityUpdater(),
+		NewFakeTidbClusterSelfTester(),
+		NewFakeTidbClusterAutoUpgrader(),
+		NewFakeTiFlashReplicaSyncer(),
+		NewFakeTidbClusterCloner(),
+		NewFakeTidbClusterDriftDetector(),
+		NewFakeTidbClusterResourceRecommender(),
+		NewFakeTidbClusterProfileCapturer(),
+		NewFakeTidbClusterPodMonitorSyncer(),
+		NewFakeTidbClusterTLSSecretChecker(),
+		mm.NewFakeDisruptionLock(),
 		recorder,
 	)
 
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -34,6 +35,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/manager/suspender"
 	"github.com/pingcap/tidb-operator/pkg/manager/volumes"
 	"github.com/pingcap/tidb-operator/pkg/metrics"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 )
 
 // Controller controls tidbclusters.
@@ -44,47 +46,38 @@ type Controller struct {
 	control ControlInterface
 	// tidbclusters that need to be synced.
 	queue workqueue.RateLimitingInterface
+	// storeWatcher enqueues the tidbclusters when their stores become Down
+	storeWatcher *tidbClusterStoreWatcher
 }
 
 // NewController creates a tidbcluster controller.
 func NewController(deps *controller.Dependencies) *Controller {
-	suspender := suspender.NewSuspender(deps)
-	podVolumeModifier := volumes.NewPodVolumeModifier(deps)
-
 	c := &Controller{
-		deps: deps,
-		control: NewDefaultTidbClusterControl(
-			deps.TiDBClusterControl,
-			mm.NewPDMemberManager(deps, mm.NewPDScaler(deps), mm.NewPDUpgrader(deps), mm.NewPDFailover(deps), suspender, podVolumeModifier),
-			mm.NewPDMSMemberManager(deps, mm.NewPDMSScaler(deps), mm.NewPDMSUpgrader(deps), suspender, podVolumeModifier),
-			mm.NewTiKVMemberManager(deps, mm.NewTiKVFailover(deps), mm.NewTiKVScaler(deps), mm.NewTiKVUpgrader(deps, podVolumeModifier), suspender, podVolumeModifier),
-			mm.NewTiDBMemberManager(deps, mm.NewTiDBScaler(deps), mm.NewTiDBUpgrader(deps), mm.NewTiDBFailover(deps), suspender, podVolumeModifier),
-			mm.NewTiProxyMemberManager(deps, mm.NewTiProxyScaler(deps), mm.NewTiProxyUpgrader(deps), suspender),
-			meta.NewReclaimPolicyManager(deps),
-			meta.NewMetaManager(deps),
-			mm.NewOrphanPodsCleaner(deps),
-			mm.NewRealPVCCleaner(deps),
-			volumes.NewPVCModifier(deps),
-			volumes.NewPVCReplacer(deps),
-			mm.NewPumpMemberManager(deps, mm.NewPumpScaler(deps), suspender, podVolumeModifier),
-			mm.NewTiFlashMemberManager(deps, mm.NewTiFlashFailover(deps), mm.NewTiFlashScaler(deps), mm.NewTiFlashUpgrader(deps), suspender, podVolumeModifier),
-			mm.NewTiCDCMemberManager(deps, mm.NewTiCDCScaler(deps), mm.NewTiCDCUpgrader(deps), suspender, podVolumeModifier),
-			mm.NewTidbDiscoveryManager(deps),
-			mm.NewTidbClusterStatusManager(deps),
-			&tidbClusterConditionUpdater{},
-			deps.Recorder,
-		),
-		queue: workqueue.NewNamedRateLimitingQueue(
-			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
-			"tidbcluster",
-		),
+		deps:    deps,
+		control: NewTidbClusterControl(deps),
 	}
+	// degraded clusters are synced before the healthy ones, so that a resync of all the
+	// clusters doesn't delay the recovery of the broken ones
+	c.queue = controller.NewPriorityRateLimitingQueue(
+		controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
+		"tidbcluster",
+		c.isDegraded,
+	)
+	deps.Requeuer.Register(controller.ControllerKind.Kind, c.queue.AddAfter)
+	deps.SyncTracker.RegisterQueue(c.Name(), c.queue)
+	c.storeWatcher = newTidbClusterStoreWatcher(deps, deps.CLIConfig.StoreStateWatchInterval, func(key string) {
+		c.queue.Add(key)
+	})
 
 	tidbClusterInformer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters()
 	statefulsetInformer := deps.KubeInformerFactory.Apps().V1().StatefulSets()
 	tidbClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: c.enqueueTidbCluster,
 		UpdateFunc: func(old, cur interface{}) {
+			// the clusters with an overridden resync duration are requeued by the controller itself
+			if controller.IsOverriddenResync(old, cur) {
+				return
+			}
 			c.enqueueTidbCluster(cur)
 		},
 		DeleteFunc: c.enqueueTidbCluster,
@@ -100,6 +93,51 @@ func NewController(deps *controller.Dependencies) *Controller {
 	return c
 }
 
+// NewTidbClusterControl returns the ControlInterface used by the tidbcluster controller,
+// which is composed of the member managers and the updaters created with the dependencies
+func NewTidbClusterControl(deps *controller.Dependencies) ControlInterface {
+	suspender := suspender.NewSuspender(deps)
+	podVolumeModifier := volumes.NewPodVolumeModifier(deps)
+
+	return NewDefaultTidbClusterControl(
+		deps.TiDBClusterControl,
+		mm.NewPDMemberManager(deps, mm.NewPDScaler(deps), mm.NewPDUpgrader(deps), mm.NewPDFailover(deps), suspender, podVolumeModifier),
+		mm.NewPDMSMemberManager(deps, mm.NewPDMSScaler(deps), mm.NewPDMSUpgrader(deps), suspender, podVolumeModifier),
+		mm.NewTiKVMemberManager(deps, mm.NewTiKVFailover(deps), mm.NewTiKVScaler(deps), mm.NewTiKVUpgrader(deps, podVolumeModifier), suspender, podVolumeModifier),
+		mm.NewTiDBMemberManager(deps, mm.NewTiDBScaler(deps), mm.NewTiDBUpgrader(deps), mm.NewTiDBFailover(deps), suspender, podVolumeModifier),
+		mm.NewTiProxyMemberManager(deps, mm.NewTiProxyScaler(deps), mm.NewTiProxyUpgrader(deps), suspender),
+		meta.NewReclaimPolicyManager(deps),
+		meta.NewDeletionPolicyManager(deps),
+		meta.NewMetaManager(deps),
+		mm.NewOrphanPodsCleaner(deps),
+		mm.NewRealPVCCleaner(deps),
+		volumes.NewPVCModifier(deps),
+		volumes.NewPVCReplacer(deps),
+		mm.NewPumpMemberManager(deps, mm.NewPumpScaler(deps), suspender, podVolumeModifier),
+		mm.NewTiFlashMemberManager(deps, mm.NewTiFlashFailover(deps), mm.NewTiFlashScaler(deps), mm.NewTiFlashUpgrader(deps), suspender, podVolumeModifier),
+		mm.NewTiCDCMemberManager(deps, mm.NewTiCDCScaler(deps), mm.NewTiCDCUpgrader(deps), suspender, podVolumeModifier),
+		mm.NewTidbDiscoveryManager(deps),
+		mm.NewTidbClusterStatusManager(deps),
+		&tidbClusterConditionUpdater{},
+		NewTidbClusterSuggestedActionUpdater(deps),
+		NewTidbClusterZoneDistributionUpdater(deps),
+		NewTidbClusterHostPortUpdater(deps),
+		NewTidbClusterConfigConflictUpdater(deps),
+		NewTidbClusterCapabilityUpdater(deps),
+		NewTidbClusterSelfTester(deps),
+		NewTidbClusterAutoUpgrader(deps),
+		NewTiFlashReplicaSyncer(deps),
+		NewTidbClusterCloner(deps),
+		NewTidbClusterDriftDetector(deps),
+		NewTidbClusterResourceRecommender(deps),
+		NewTidbClusterProfileCapturer(deps),
+		NewTidbClusterPodMonitorSyncer(deps),
+		NewTidbClusterTLSSecretChecker(deps),
+		mm.NewDisruptionLock(deps),
+		deps.Recorder,
+	)
+}
+
 // Name returns the name of the tidbcluster controller
 func (c *Controller) Name() string {
 	return "tidbcluster"
@@ -109,6 +147,7 @@ func (c *Controller) Name() string {
 func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	defer c.queue.ShutDown()
+	defer c.storeWatcher.Stop()
 
 	klog.Info("Starting tidbcluster controller")
 	defer klog.Info("Shutting down tidbcluster controller")
@@ -137,7 +176,16 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	// the syncs may change the membership of PD and TiKV, so they are tracked to
+	// avoid being interrupted when the operator is shutting down
+	if !c.deps.SyncTracker.StartSync(c.Name(), key.(string)) {
+		// keep getting the keys until the queue is shut down, so they are all recorded as requeue hints
+		klog.Infof("TidbCluster: %v, skip syncing as tidb-operator is shutting down", key.(string))
+		return true
+	}
+	err := c.sync(key.(string))
+	c.deps.SyncTracker.FinishSync(c.Name(), key.(string), err != nil)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("TidbCluster: %v, still need sync: %v, requeuing", key.(string), err)
 		} else {
@@ -146,10 +194,58 @@ func (c *Controller) processNextWorkItem() bool {
 		c.queue.AddRateLimited(key)
 	} else {
 		c.queue.Forget(key)
+		if c.isDegraded(key) {
+			c.queue.AddAfter(key, c.deps.CLIConfig.DegradedClusterResyncDuration)
+		} else if d, ok := c.resyncDurationOf(key); ok {
+			c.queue.AddAfter(key, d)
+		}
 	}
 	return true
 }
 
+// isDegraded returns whether the tidbcluster of the given key is degraded
+func (c *Controller) isDegraded(key interface{}) bool {
+	ns, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	if err != nil {
+		return false
+	}
+	tc, err := c.deps.TiDBClusterLister.TidbClusters(ns).Get(name)
+	if err != nil {
+		return false
+	}
+	// the clusters not synced successfully before the last shutdown are also synced first
+	return isTidbClusterDegraded(tc) || c.deps.SyncTracker.IsHinted(c.Name(), key.(string))
+}
+
+// resyncDurationOf returns the resync duration overridden by the annotation of the tidbcluster of the given key
+func (c *Controller) resyncDurationOf(key interface{}) (time.Duration, bool) {
+	ns, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	if err != nil {
+		return 0, false
+	}
+	tc, err := c.deps.TiDBClusterLister.TidbClusters(ns).Get(name)
+	if err != nil {
+		return 0, false
+	}
+	return controller.ResyncDurationOf(tc)
+}
+
+// isTidbClusterDegraded returns true if the tidbcluster has failed members, is being
+// upgraded or is not ready
+func isTidbClusterDegraded(tc *v1alpha1.TidbCluster) bool {
+	if len(tc.Status.PD.FailureMembers) > 0 ||
+		len(tc.Status.TiKV.FailureStores) > 0 ||
+		len(tc.Status.TiFlash.FailureStores) > 0 ||
+		len(tc.Status.TiDB.FailureMembers) > 0 {
+		return true
+	}
+	if tc.PDUpgrading() || tc.TiKVUpgrading() || tc.TiFlashUpgrading() || tc.TiDBUpgrading() {
+		return true
+	}
+	cond := utiltidbcluster.GetTidbClusterReadyCondition(tc.Status)
+	return cond != nil && cond.Status == corev1.ConditionFalse
+}
+
 // sync syncs the given tidbcluster.
 func (c *Controller) sync(key string) (err error) {
 	startTime := time.Now()
@@ -197,6 +293,7 @@ func (c *Controller) enqueueTidbCluster(obj interface{}) {
 		return
 	}
 	c.queue.Add(key)
+	c.storeWatcher.Watch(key)
 }
 
 // addStatefulSet adds the tidbcluster for the statefulset to the sync queue
@@ -264,6 +264,43 @@ func TestTidbClusterControllerSync(t *testing.T) {
 
 }
 
+func TestTidbClusterControllerDegradedClusterPriority(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeDeps := controller.NewFakeDependencies()
+	tcc := NewController(fakeDeps)
+	tcc.control = NewFakeTidbClusterControlInterface()
+	tcIndexer := fakeDeps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+
+	healthy := newTidbCluster()
+	healthy.Name = "healthy"
+	upgrading := newTidbCluster()
+	upgrading.Name = "upgrading"
+	upgrading.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	failed := newTidbCluster()
+	failed.Name = "failed"
+	failed.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{"failed-pd-0": {PodName: "failed-pd-0"}}
+	notReady := newTidbCluster()
+	notReady.Name = "not-ready"
+	notReady.Status.Conditions = []v1alpha1.TidbClusterCondition{{Type: v1alpha1.TidbClusterReady, Status: corev1.ConditionFalse}}
+
+	for _, tc := range []*v1alpha1.TidbCluster{healthy, upgrading, failed, notReady} {
+		g.Expect(tcIndexer.Add(tc)).To(Succeed())
+		tcc.enqueueTidbCluster(tc)
+	}
+	g.Expect(isTidbClusterDegraded(healthy)).To(BeFalse())
+	g.Expect(isTidbClusterDegraded(upgrading)).To(BeTrue())
+	g.Expect(isTidbClusterDegraded(failed)).To(BeTrue())
+	g.Expect(isTidbClusterDegraded(notReady)).To(BeTrue())
+
+	var keys []string
+	for i := 0; i < 4; i++ {
+		key, _ := tcc.queue.Get()
+		keys = append(keys, key.(string))
+	}
+	g.Expect(keys).To(Equal([]string{"default/upgrading", "default/failed", "default/not-ready", "default/healthy"}))
+}
+
 func newTidbCluster() *v1alpha1.TidbCluster {
 	return &v1alpha1.TidbCluster{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,285 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	driftKindStatefulSet = "StatefulSet"
+	driftKindConfigMap   = "ConfigMap"
+)
+
+// TidbClusterDriftDetector detects the StatefulSets and ConfigMaps of the cluster whose fields owned by
+// the operator are mutated by other field managers, alerts the drifts and reverts them by spec.driftProtection.
+type TidbClusterDriftDetector interface {
+	Detect(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterDriftDetector struct {
+	deps *controller.Dependencies
+	// fieldManager is the field manager of the operator, which is the prefix of the user agent of its clients
+	fieldManager string
+}
+
+// NewTidbClusterDriftDetector returns a TidbClusterDriftDetector
+func NewTidbClusterDriftDetector(deps *controller.Dependencies) TidbClusterDriftDetector {
+	return &tidbClusterDriftDetector{
+		deps:         deps,
+		fieldManager: filepath.Base(os.Args[0]),
+	}
+}
+
+var _ TidbClusterDriftDetector = &tidbClusterDriftDetector{}
+
+func (d *tidbClusterDriftDetector) Detect(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.DriftProtection == nil {
+		tc.Status.Drifts = nil
+		return nil
+	}
+	ns, tcName := tc.GetNamespace(), tc.GetName()
+	selector, err := label.New().Instance(tc.GetInstanceName()).Selector()
+	if err != nil {
+		return err
+	}
+
+	var drifts []v1alpha1.ResourceDrift
+	setsToRevert := map[string]*apps.StatefulSet{}
+	cmsToRevert := map[string]*corev1.ConfigMap{}
+	sets, err := d.deps.StatefulSetLister.StatefulSets(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("tidbcluster: [%s/%s] list statefulsets failed: %v", ns, tcName, err)
+	}
+	for _, set := range sets {
+		if !metav1.IsControlledBy(set, tc) {
+			continue
+		}
+		applied := map[string]interface{}{"spec": lastAppliedFields(set.Annotations, statefulSetRevertedFields...)}
+		if drift := d.detectDrift(tc, driftKindStatefulSet, set.Name, set.ManagedFields, applied); drift != nil {
+			drifts = append(drifts, *drift)
+			setsToRevert[set.Name] = set
+		}
+	}
+	cms, err := d.deps.ConfigMapLister.ConfigMaps(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("tidbcluster: [%s/%s] list configmaps failed: %v", ns, tcName, err)
+	}
+	for _, cm := range cms {
+		if !metav1.IsControlledBy(cm, tc) {
+			continue
+		}
+		applied := map[string]interface{}{"data": lastAppliedFields(cm.Annotations)}
+		if drift := d.detectDrift(tc, driftKindConfigMap, cm.Name, cm.ManagedFields, applied); drift != nil {
+			drifts = append(drifts, *drift)
+			cmsToRevert[cm.Name] = cm
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Kind != drifts[j].Kind {
+			return drifts[i].Kind > drifts[j].Kind
+		}
+		return drifts[i].Name < drifts[j].Name
+	})
+
+	detected := map[string]v1alpha1.ResourceDrift{}
+	for _, drift := range tc.Status.Drifts {
+		detected[drift.Kind+"/"+drift.Name] = drift
+	}
+	for i := range drifts {
+		drift := &drifts[i]
+		if old, ok := detected[drift.Kind+"/"+drift.Name]; ok && old.MutateTime.Equal(drift.MutateTime) {
+			// the drift is handled already
+			drift.Reverted = old.Reverted
+			continue
+		}
+
+		msg := fmt.Sprintf("%s %s is mutated by %s", drift.Kind, drift.Name, strings.Join(drift.Managers, ","))
+		if tc.Spec.DriftProtection.Policy == v1alpha1.DriftPolicyRevert && !tc.Spec.Paused {
+			var err error
+			switch drift.Kind {
+			case driftKindStatefulSet:
+				err = d.revertStatefulSet(tc, setsToRevert[drift.Name])
+			case driftKindConfigMap:
+				err = d.revertConfigMap(tc, cmsToRevert[drift.Name])
+			}
+			if err != nil {
+				return err
+			}
+			drift.Reverted = true
+			msg += ", reverted to the config applied by the operator"
+		}
+		d.deps.Recorder.Event(tc, corev1.EventTypeWarning, "ResourceDrifted", msg)
+	}
+	tc.Status.Drifts = drifts
+	return nil
+}
+
+// detectDrift returns the drift of a resource if the field managers other than the operator and the
+// exempt ones own the fields applied by the operator
+func (d *tidbClusterDriftDetector) detectDrift(tc *v1alpha1.TidbCluster, kind, name string, entries []metav1.ManagedFieldsEntry, applied map[string]interface{}) *v1alpha1.ResourceDrift {
+	exempt := map[string]struct{}{d.fieldManager: {}}
+	for _, manager := range tc.Spec.DriftProtection.ExemptManagers {
+		exempt[manager] = struct{}{}
+	}
+
+	var drift *v1alpha1.ResourceDrift
+	for _, entry := range entries {
+		// the status is updated by the other controllers, e.g. kube-controller-manager
+		if entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		if _, ok := exempt[entry.Manager]; ok {
+			continue
+		}
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			klog.Warningf("tidbcluster: [%s/%s] parse the managed fields of %s %s failed: %v", tc.GetNamespace(), tc.GetName(), kind, name, err)
+			continue
+		}
+		if !intersectAppliedFields(fields, applied) {
+			continue
+		}
+		if drift == nil {
+			drift = &v1alpha1.ResourceDrift{Kind: kind, Name: name}
+		}
+		drift.Managers = append(drift.Managers, entry.Manager)
+		if entry.Time != nil && (drift.MutateTime == nil || drift.MutateTime.Before(entry.Time)) {
+			drift.MutateTime = entry.Time.DeepCopy()
+		}
+	}
+	if drift != nil {
+		sort.Strings(drift.Managers)
+	}
+	return drift
+}
+
+// statefulSetRevertedFields are the fields of the spec of a StatefulSet reverted to the last applied config
+var statefulSetRevertedFields = []string{"replicas", "updateStrategy", "template"}
+
+// lastAppliedFields returns the given fields of the config last applied by the operator, or all the fields
+// if none is given. If the config isn't recorded, true is returned to regard all the fields as applied.
+func lastAppliedFields(annotations map[string]string, names ...string) interface{} {
+	applied := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(annotations[controller.LastAppliedConfigAnnotation]), &applied); err != nil {
+		return true
+	}
+	if len(names) == 0 {
+		return applied
+	}
+	fields := map[string]interface{}{}
+	for _, name := range names {
+		if v, ok := applied[name]; ok {
+			fields[name] = v
+		}
+	}
+	return fields
+}
+
+// intersectAppliedFields returns true if the managed fields intersect the fields of the config applied by
+// the operator. The fields under a list or a scalar of the applied config are regarded as a whole, e.g. a
+// container of the pod template is owned by the operator if the containers are applied.
+func intersectAppliedFields(fields map[string]json.RawMessage, applied map[string]interface{}) bool {
+	for key, raw := range fields {
+		name, ok := strings.CutPrefix(key, "f:")
+		if !ok {
+			continue
+		}
+		value, ok := applied[name]
+		if !ok {
+			continue
+		}
+		sub, ok := value.(map[string]interface{})
+		if !ok {
+			return true
+		}
+		children := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &children); err != nil || len(children) == 0 {
+			return true
+		}
+		if intersectAppliedFields(children, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// revertStatefulSet reverts the statefulset to the spec last applied by the operator, then the
+// mutated fields are owned by the operator again
+func (d *tidbClusterDriftDetector) revertStatefulSet(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
+	lastApplied, ok := set.Annotations[controller.LastAppliedConfigAnnotation]
+	if !ok {
+		return fmt.Errorf("tidbcluster: [%s/%s] statefulset %s has no last applied config to revert to", tc.GetNamespace(), tc.GetName(), set.Name)
+	}
+	spec := apps.StatefulSetSpec{}
+	if err := json.Unmarshal([]byte(lastApplied), &spec); err != nil {
+		return fmt.Errorf("tidbcluster: [%s/%s] unmarshal the last applied config of statefulset %s failed: %v", tc.GetNamespace(), tc.GetName(), set.Name, err)
+	}
+
+	newSet := set.DeepCopy()
+	newSet.Spec.Replicas = spec.Replicas
+	newSet.Spec.UpdateStrategy = spec.UpdateStrategy
+	newSet.Spec.Template = spec.Template
+	if _, err := d.deps.StatefulSetControl.UpdateStatefulSet(tc, newSet); err != nil {
+		return err
+	}
+	klog.Infof("tidbcluster: [%s/%s] reverted the drift of statefulset %s", tc.GetNamespace(), tc.GetName(), set.Name)
+	return nil
+}
+
+// revertConfigMap reverts the configmap to the data last applied by the operator before the members
+// are synced, otherwise the mutated data differs from the desired one and a new configmap is rolled out
+// with the RollingUpdate config update strategy
+func (d *tidbClusterDriftDetector) revertConfigMap(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) error {
+	lastApplied, ok := cm.Annotations[controller.LastAppliedConfigAnnotation]
+	if !ok {
+		return fmt.Errorf("tidbcluster: [%s/%s] configmap %s has no last applied config to revert to", tc.GetNamespace(), tc.GetName(), cm.Name)
+	}
+	data := map[string]string{}
+	if err := json.Unmarshal([]byte(lastApplied), &data); err != nil {
+		return fmt.Errorf("tidbcluster: [%s/%s] unmarshal the last applied config of configmap %s failed: %v", tc.GetNamespace(), tc.GetName(), cm.Name, err)
+	}
+
+	newCm := cm.DeepCopy()
+	newCm.Data = data
+	if _, err := d.deps.ConfigMapControl.UpdateConfigMap(tc, newCm); err != nil {
+		return err
+	}
+	klog.Infof("tidbcluster: [%s/%s] reverted the drift of configmap %s", tc.GetNamespace(), tc.GetName(), cm.Name)
+	return nil
+}
+
+type fakeTidbClusterDriftDetector struct{}
+
+// NewFakeTidbClusterDriftDetector returns a fake TidbClusterDriftDetector
+func NewFakeTidbClusterDriftDetector() TidbClusterDriftDetector {
+	return &fakeTidbClusterDriftDetector{}
+}
+
+func (d *fakeTidbClusterDriftDetector) Detect(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func newManagedFieldsEntry(manager, fields string, t time.Time) metav1.ManagedFieldsEntry {
+	mt := metav1.NewTime(t)
+	return metav1.ManagedFieldsEntry{
+		Manager:   manager,
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		Time:      &mt,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestTidbClusterDriftDetector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	detector := &tidbClusterDriftDetector{deps: deps, fieldManager: "tidb-controller-manager"}
+	recorder := deps.Recorder.(*record.FakeRecorder)
+	tc := newTidbClusterForClone()
+	tc.Spec.ClusterCloneFrom = nil
+	tc.Spec.DriftProtection = &v1alpha1.DriftProtection{ExemptManagers: []string{"argocd-controller"}}
+	now := time.Now()
+
+	appliedSet := &apps.StatefulSet{
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(3),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "tikv", Image: "pingcap/tikv:v7.5.0"}}}},
+		},
+	}
+	g.Expect(mngerutils.SetStatefulSetLastAppliedConfigAnnotation(appliedSet)).To(Succeed())
+	set := appliedSet.DeepCopy()
+	set.Name = controller.TiKVMemberName(tc.Name)
+	set.Namespace = tc.Namespace
+	set.Labels = label.New().Instance(tc.GetInstanceName()).TiKV().Labels()
+	set.OwnerReferences = []metav1.OwnerReference{controller.GetOwnerRef(tc)}
+	set.Spec.Replicas = pointer.Int32Ptr(5)
+	set.ManagedFields = []metav1.ManagedFieldsEntry{
+		newManagedFieldsEntry("tidb-controller-manager", `{"f:metadata":{},"f:spec":{"f:template":{}}}`, now.Add(-time.Hour)),
+		newManagedFieldsEntry("kubectl-edit", `{"f:spec":{"f:replicas":{}}}`, now),
+		newManagedFieldsEntry("kube-controller-manager", `{"f:status":{}}`, now),
+		// the fields not applied by the operator aren't drifts
+		newManagedFieldsEntry("helm", `{"f:spec":{"f:template":{"f:spec":{"f:tolerations":{}}}}}`, now),
+	}
+	set.ManagedFields[2].Subresource = "status"
+	g.Expect(deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(set)).To(Succeed())
+
+	cm := &corev1.ConfigMap{Data: map[string]string{"config-file": "[log]\nlevel = \"info\"\n"}}
+	g.Expect(controller.SetConfigMapLastAppliedConfigAnnotation(cm)).To(Succeed())
+	cm.Data["config-file"] = "[log]\nlevel = \"debug\"\n"
+	cm.Data["extra"] = "extra"
+	cm.Name = controller.TiKVMemberName(tc.Name)
+	cm.Namespace = tc.Namespace
+	cm.Labels = label.New().Instance(tc.GetInstanceName()).TiKV().Labels()
+	cm.OwnerReferences = []metav1.OwnerReference{controller.GetOwnerRef(tc)}
+	cm.ManagedFields = []metav1.ManagedFieldsEntry{
+		newManagedFieldsEntry("argocd-controller", `{"f:data":{"f:config-file":{}}}`, now),
+		newManagedFieldsEntry("helm", `{"f:metadata":{"f:labels":{}},"f:data":{"f:extra":{}}}`, now),
+	}
+	g.Expect(deps.LabelFilterKubeInformerFactory.Core().V1().ConfigMaps().Informer().GetIndexer().Add(cm)).To(Succeed())
+
+	// the drift is alerted
+	g.Expect(detector.Detect(tc)).To(Succeed())
+	g.Expect(tc.Status.Drifts).To(HaveLen(1))
+	g.Expect(tc.Status.Drifts[0].Kind).To(Equal("StatefulSet"))
+	g.Expect(tc.Status.Drifts[0].Name).To(Equal(set.Name))
+	g.Expect(tc.Status.Drifts[0].Managers).To(Equal([]string{"kubectl-edit"}))
+	g.Expect(tc.Status.Drifts[0].Reverted).To(BeFalse())
+	g.Expect(recorder.Events).To(HaveLen(1))
+	<-recorder.Events
+
+	// the drift alerted already isn't alerted again
+	g.Expect(detector.Detect(tc)).To(Succeed())
+	g.Expect(tc.Status.Drifts).To(HaveLen(1))
+	g.Expect(recorder.Events).To(HaveLen(0))
+
+	// the drift is reverted to the last applied spec after it's mutated again
+	tc.Spec.DriftProtection.Policy = v1alpha1.DriftPolicyRevert
+	set.ManagedFields[1] = newManagedFieldsEntry("kubectl-edit", `{"f:spec":{"f:replicas":{}}}`, now.Add(time.Minute))
+	g.Expect(deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Update(set)).To(Succeed())
+	g.Expect(detector.Detect(tc)).To(Succeed())
+	g.Expect(tc.Status.Drifts[0].Reverted).To(BeTrue())
+	g.Expect(recorder.Events).To(HaveLen(1))
+	<-recorder.Events
+	reverted, err := deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(set.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(*reverted.Spec.Replicas).To(Equal(int32(3)))
+
+	// the drift of the configmap is reverted to the last applied data
+	cm.ManagedFields = append(cm.ManagedFields, newManagedFieldsEntry("kubectl-patch", `{"f:data":{"f:config-file":{}}}`, now))
+	g.Expect(deps.LabelFilterKubeInformerFactory.Core().V1().ConfigMaps().Informer().GetIndexer().Update(cm)).To(Succeed())
+	g.Expect(detector.Detect(tc)).To(Succeed())
+	g.Expect(tc.Status.Drifts).To(HaveLen(2))
+	g.Expect(tc.Status.Drifts[1].Kind).To(Equal("ConfigMap"))
+	g.Expect(tc.Status.Drifts[1].Managers).To(Equal([]string{"kubectl-patch"}))
+	g.Expect(tc.Status.Drifts[1].Reverted).To(BeTrue())
+	g.Expect(recorder.Events).To(HaveLen(1))
+	<-recorder.Events
+	obj, exist, err := deps.ConfigMapControl.(*controller.FakeConfigMapControl).CmIndexer.GetByKey(tc.Namespace + "/" + cm.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(exist).To(BeTrue())
+	g.Expect(obj.(*corev1.ConfigMap).Data).To(Equal(map[string]string{"config-file": "[log]\nlevel = \"info\"\n"}))
+
+	// the drifts are cleared once the fields are owned by the operator again
+	reverted = reverted.DeepCopy()
+	reverted.ManagedFields = reverted.ManagedFields[:1]
+	g.Expect(deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Update(reverted)).To(Succeed())
+	cm.ManagedFields = cm.ManagedFields[:2]
+	g.Expect(deps.LabelFilterKubeInformerFactory.Core().V1().ConfigMaps().Informer().GetIndexer().Update(cm)).To(Succeed())
+	g.Expect(detector.Detect(tc)).To(Succeed())
+	g.Expect(tc.Status.Drifts).To(BeEmpty())
+}
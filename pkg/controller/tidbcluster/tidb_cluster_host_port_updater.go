@@ -0,0 +1,154 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// TidbClusterHostPortUpdater interface that checks whether the pods of a tidb cluster in the
+// host network are placed to nodes where the ports they bind are already bound by other pods,
+// which makes the pods crash on start, and explains the conflicts in the conditions.
+type TidbClusterHostPortUpdater interface {
+	Update(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterHostPortUpdater struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterHostPortUpdater returns a TidbClusterHostPortUpdater
+func NewTidbClusterHostPortUpdater(deps *controller.Dependencies) TidbClusterHostPortUpdater {
+	return &tidbClusterHostPortUpdater{
+		deps: deps,
+	}
+}
+
+var _ TidbClusterHostPortUpdater = &tidbClusterHostPortUpdater{}
+
+func (u *tidbClusterHostPortUpdater) Update(tc *v1alpha1.TidbCluster) error {
+	if len(tc.HostNetworkPorts()) == 0 {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterHostPortConflict)
+		return nil
+	}
+
+	// the pods of other clusters and applications may bind the ports of the nodes too
+	pods, err := u.deps.PodLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("cluster %s/%s list pods failed, err: %v", tc.Namespace, tc.Name, err)
+	}
+	podsByNode := map[string][]*corev1.Pod{}
+	for _, pod := range pods {
+		if !pod.Spec.HostNetwork || pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+
+	var conflicts []string
+	for node, pods := range podsByNode {
+		sort.Slice(pods, func(i, j int) bool {
+			return pods[i].Namespace+"/"+pods[i].Name < pods[j].Namespace+"/"+pods[j].Name
+		})
+		for i, pod := range pods {
+			if !isPodOfTidbCluster(tc, pod) {
+				continue
+			}
+			ports := hostPortsOfPod(tc, pod)
+			for j, other := range pods {
+				// the conflict between two pods of the cluster is reported once
+				if i == j || (j < i && isPodOfTidbCluster(tc, other)) {
+					continue
+				}
+				otherPorts := hostPortsOfPod(tc, other)
+				for _, port := range ports {
+					if _, ok := otherPorts[port.Port]; !ok {
+						continue
+					}
+					conflicts = append(conflicts, fmt.Sprintf("port %d of %s is bound by %s/%s on node %s",
+						port.Port, pod.Name, other.Namespace, other.Name, node))
+				}
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterHostPortConflict)
+		return nil
+	}
+	sort.Strings(conflicts)
+	message := strings.Join(conflicts, "; ")
+	if cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterHostPortConflict); cond == nil || cond.Message != message {
+		u.deps.Recorder.Event(tc, corev1.EventTypeWarning, utiltidbcluster.HostPortsConflicted, message)
+	}
+	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterHostPortConflict, corev1.ConditionTrue,
+		utiltidbcluster.HostPortsConflicted, message)
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+	return nil
+}
+
+func isPodOfTidbCluster(tc *v1alpha1.TidbCluster, pod *corev1.Pod) bool {
+	return pod.Namespace == tc.Namespace && pod.Labels[label.InstanceLabelKey] == tc.GetInstanceName() &&
+		pod.Labels[label.ManagedByLabelKey] == label.TiDBOperator
+}
+
+// hostPortsOfPod returns the ports bound on the node by the pod. The ports of the pods of the
+// cluster are planned from the spec, as some of the ports are not declared in the pod spec.
+func hostPortsOfPod(tc *v1alpha1.TidbCluster, pod *corev1.Pod) map[int32]v1alpha1.HostNetworkPort {
+	ports := map[int32]v1alpha1.HostNetworkPort{}
+	if isPodOfTidbCluster(tc, pod) {
+		component := v1alpha1.MemberType(pod.Labels[label.ComponentLabelKey])
+		// the witness stores of TiKV bind the same ports as TiKV
+		if component == v1alpha1.MemberType(label.TiKVWitnessLabelVal) {
+			component = v1alpha1.TiKVMemberType
+		}
+		for _, p := range tc.HostNetworkPortsOf(component) {
+			ports[p.Port] = p
+		}
+		if len(ports) > 0 {
+			return ports
+		}
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			port := p.ContainerPort
+			if p.HostPort != 0 {
+				port = p.HostPort
+			}
+			ports[port] = v1alpha1.HostNetworkPort{Name: c.Name, Port: port, Declared: true}
+		}
+	}
+	return ports
+}
+
+type fakeTidbClusterHostPortUpdater struct{}
+
+// NewFakeTidbClusterHostPortUpdater returns a fake TidbClusterHostPortUpdater
+func NewFakeTidbClusterHostPortUpdater() TidbClusterHostPortUpdater {
+	return &fakeTidbClusterHostPortUpdater{}
+}
+
+func (u *fakeTidbClusterHostPortUpdater) Update(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestTidbClusterHostPortUpdater(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	updater := NewTidbClusterHostPortUpdater(deps)
+	recorder := deps.Recorder.(*record.FakeRecorder)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+		},
+	}
+
+	podIndexer := deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	newPod := func(namespace, name string, l label.Label, node string, ports ...int32) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    l.Labels(),
+			},
+			Spec: corev1.PodSpec{
+				NodeName:    node,
+				HostNetwork: true,
+				Containers:  []corev1.Container{{Name: "main"}},
+			},
+		}
+		for _, port := range ports {
+			pod.Spec.Containers[0].Ports = append(pod.Spec.Containers[0].Ports, corev1.ContainerPort{ContainerPort: port})
+		}
+		return pod
+	}
+	podIndexer.Add(newPod(tc.Namespace, "test-pd-0", label.New().Instance("test").PD(), "node-a", 2379, 2380))
+	podIndexer.Add(newPod(tc.Namespace, "test-tikv-0", label.New().Instance("test").TiKV(), "node-a", 20160))
+	// the exporter binds the status port of TiKV, which is not declared in the pod spec
+	podIndexer.Add(newPod("monitoring", "exporter-0", label.Label{}, "node-a", 20180))
+
+	// nothing is checked if no component is in the host network
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterHostPortConflict)).To(BeNil())
+
+	tc.Spec.HostNetwork = pointer.BoolPtr(true)
+	g.Expect(updater.Update(tc)).To(Succeed())
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterHostPortConflict)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.HostPortsConflicted))
+	g.Expect(cond.Message).To(Equal("port 20180 of test-tikv-0 is bound by monitoring/exporter-0 on node node-a"))
+	g.Expect(recorder.Events).To(HaveLen(1))
+
+	// the event is not sent again for the same conflicts
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(recorder.Events).To(HaveLen(1))
+
+	// the condition is removed once the conflict is resolved
+	podIndexer.Delete(newPod("monitoring", "exporter-0", label.Label{}, "node-a"))
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterHostPortConflict)).To(BeNil())
+}
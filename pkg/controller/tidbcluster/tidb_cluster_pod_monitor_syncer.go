@@ -0,0 +1,334 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// podMonitorGVK is the kind of the PodMonitors of prometheus-operator, they are handled as unstructured
+// objects as the types of prometheus-operator aren't vendored
+var podMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+
+// TidbClusterPodMonitorSyncer creates a prometheus-operator PodMonitor for each component of a tidb cluster
+// when spec.prometheusMonitor is set, and deletes the PodMonitors no longer desired. The PodMonitors scrape
+// the same ports and paths as the `prometheus.io` annotations of the pods, and relabel the targets the
+// same as TidbMonitor so that the Grafana dashboards of TiDB work with them.
+type TidbClusterPodMonitorSyncer interface {
+	Sync(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterPodMonitorSyncer struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterPodMonitorSyncer returns a TidbClusterPodMonitorSyncer
+func NewTidbClusterPodMonitorSyncer(deps *controller.Dependencies) TidbClusterPodMonitorSyncer {
+	return &tidbClusterPodMonitorSyncer{
+		deps: deps,
+	}
+}
+
+var _ TidbClusterPodMonitorSyncer = &tidbClusterPodMonitorSyncer{}
+
+// podMetricsEndpoint is a port of the pods the metrics are scraped from
+type podMetricsEndpoint struct {
+	port int32
+	path string
+}
+
+// podMonitorTarget is a component of the tidb cluster scraped by a PodMonitor
+type podMonitorTarget struct {
+	component string
+	selector  label.Label
+	endpoints []podMetricsEndpoint
+	// clientCert is false if the component is scraped by https without the client certificate
+	clientCert bool
+}
+
+func (s *tidbClusterPodMonitorSyncer) Sync(tc *v1alpha1.TidbCluster) error {
+	if s.deps.Capabilities != nil && !s.deps.Capabilities.PodMonitor {
+		// the degraded feature is explained in the conditions by the capability updater
+		return nil
+	}
+
+	var errs []error
+	desired := map[string]bool{}
+	if tc.Spec.PrometheusMonitor != nil {
+		for _, target := range podMonitorTargets(tc) {
+			pm := newPodMonitor(tc, target)
+			desired[pm.GetName()] = true
+			if err := s.apply(tc, pm); err != nil {
+				errs = append(errs, fmt.Errorf("failed to sync PodMonitor %s/%s: %v", pm.GetNamespace(), pm.GetName(), err))
+			}
+		}
+	}
+
+	// the created PodMonitors are kept in the status until they are deleted
+	created := map[string]bool{}
+	for name := range desired {
+		created[name] = true
+	}
+	for _, name := range tc.Status.PodMonitors {
+		if desired[name] {
+			continue
+		}
+		if err := s.delete(tc, name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete PodMonitor %s/%s: %v", tc.Namespace, name, err))
+			created[name] = true
+		}
+	}
+	tc.Status.PodMonitors = nil
+	for name := range created {
+		tc.Status.PodMonitors = append(tc.Status.PodMonitors, name)
+	}
+	sort.Strings(tc.Status.PodMonitors)
+	return errorutils.NewAggregate(errs)
+}
+
+// apply creates the PodMonitor or updates its labels and spec if they are changed
+func (s *tidbClusterPodMonitorSyncer) apply(tc *v1alpha1.TidbCluster, desired *unstructured.Unstructured) error {
+	cli := s.deps.GenericClient
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(podMonitorGVK)
+	err := cli.Get(context.TODO(), client.ObjectKeyFromObject(desired), existing)
+	if errors.IsNotFound(err) {
+		return cli.Create(context.TODO(), desired)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(existing, tc) {
+		return fmt.Errorf("it already exists and isn't controlled by the tidb cluster")
+	}
+	if apiequality.Semantic.DeepEqual(existing.GetLabels(), desired.GetLabels()) &&
+		apiequality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	existing.SetLabels(desired.GetLabels())
+	existing.Object["spec"] = desired.Object["spec"]
+	return cli.Update(context.TODO(), existing)
+}
+
+// delete deletes the PodMonitor if it's controlled by the tidb cluster
+func (s *tidbClusterPodMonitorSyncer) delete(tc *v1alpha1.TidbCluster, name string) error {
+	cli := s.deps.GenericClient
+	pm := &unstructured.Unstructured{}
+	pm.SetGroupVersionKind(podMonitorGVK)
+	err := cli.Get(context.TODO(), client.ObjectKey{Namespace: tc.Namespace, Name: name}, pm)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(pm, tc) {
+		return nil
+	}
+	if err := cli.Delete(context.TODO(), pm); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// podMonitorTargets returns the components of the tidb cluster scraped by the PodMonitors
+func podMonitorTargets(tc *v1alpha1.TidbCluster) []podMonitorTarget {
+	instance := func() label.Label {
+		return label.New().Instance(tc.Name)
+	}
+	var targets []podMonitorTarget
+	if tc.Spec.PD != nil {
+		targets = append(targets, podMonitorTarget{
+			component:  label.PDLabelVal,
+			selector:   instance().PD(),
+			endpoints:  []podMetricsEndpoint{{port: v1alpha1.DefaultPDClientPort, path: "/metrics"}},
+			clientCert: true,
+		})
+	}
+	for _, ms := range tc.Spec.PDMS {
+		selector := instance().PDMS(ms.Name)
+		targets = append(targets, podMonitorTarget{
+			component:  selector[label.ComponentLabelKey],
+			selector:   selector,
+			endpoints:  []podMetricsEndpoint{{port: v1alpha1.DefaultPDClientPort, path: "/metrics"}},
+			clientCert: true,
+		})
+	}
+	if tc.Spec.TiKV != nil {
+		targets = append(targets, podMonitorTarget{
+			component:  label.TiKVLabelVal,
+			selector:   instance().TiKV(),
+			endpoints:  []podMetricsEndpoint{{port: v1alpha1.DefaultTiKVStatusPort, path: "/metrics"}},
+			clientCert: true,
+		})
+	}
+	if tc.Spec.TiDB != nil {
+		targets = append(targets, podMonitorTarget{
+			component:  label.TiDBLabelVal,
+			selector:   instance().TiDB(),
+			endpoints:  []podMetricsEndpoint{{port: v1alpha1.DefaultTiDBStatusPort, path: "/metrics"}},
+			clientCert: true,
+		})
+	}
+	if tc.Spec.TiFlash != nil {
+		targets = append(targets, podMonitorTarget{
+			component: label.TiFlashLabelVal,
+			selector:  instance().TiFlash(),
+			endpoints: []podMetricsEndpoint{
+				{port: v1alpha1.DefaultTiFlashMetricsPort, path: "/metrics"},
+				{port: v1alpha1.DefaultTiFlashProxyStatusPort, path: "/metrics"},
+			},
+			clientCert: true,
+		})
+	}
+	if tc.Spec.TiCDC != nil {
+		targets = append(targets, podMonitorTarget{
+			component:  label.TiCDCLabelVal,
+			selector:   instance().TiCDC(),
+			endpoints:  []podMetricsEndpoint{{port: v1alpha1.DefaultTiCDCPort, path: "/metrics"}},
+			clientCert: true,
+		})
+	}
+	if tc.Spec.Pump != nil {
+		targets = append(targets, podMonitorTarget{
+			component:  label.PumpLabelVal,
+			selector:   instance().Pump(),
+			endpoints:  []podMetricsEndpoint{{port: v1alpha1.DefaultPumpPort, path: "/metrics"}},
+			clientCert: true,
+		})
+	}
+	if tc.Spec.TiProxy != nil {
+		// tiproxy uses the certificates of tidb, there is no suitable CA for the peer addresses
+		targets = append(targets, podMonitorTarget{
+			component: label.TiProxyLabelVal,
+			selector:  instance().TiProxy(),
+			endpoints: []podMetricsEndpoint{{port: v1alpha1.DefaultTiProxyStatusPort, path: "/api/metrics"}},
+		})
+	}
+	return targets
+}
+
+// newPodMonitor returns the PodMonitor of the component, the targets are relabeled the same as TidbMonitor
+func newPodMonitor(tc *v1alpha1.TidbCluster, target podMonitorTarget) *unstructured.Unstructured {
+	monitor := tc.Spec.PrometheusMonitor
+	relabelings := []interface{}{
+		map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_namespace"},
+			"action":       "replace",
+			"targetLabel":  "kubernetes_namespace",
+		},
+		map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_pod_label_app_kubernetes_io_instance"},
+			"action":       "replace",
+			"targetLabel":  "cluster",
+		},
+		map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_pod_name"},
+			"action":       "replace",
+			"targetLabel":  "instance",
+		},
+		map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_pod_label_app_kubernetes_io_component"},
+			"action":       "replace",
+			"targetLabel":  "component",
+		},
+		map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_namespace", "__meta_kubernetes_pod_label_app_kubernetes_io_instance"},
+			"separator":    "-",
+			"targetLabel":  "tidb_cluster",
+		},
+	}
+
+	var endpoints []interface{}
+	for _, ep := range target.endpoints {
+		endpoint := map[string]interface{}{
+			"targetPort":  int64(ep.port),
+			"path":        ep.path,
+			"scheme":      "http",
+			"honorLabels": true,
+			"relabelings": relabelings,
+		}
+		if monitor.Interval != nil {
+			endpoint["interval"] = monitor.Interval.Duration.String()
+		}
+		if tc.IsTLSClusterEnabled() {
+			endpoint["scheme"] = "https"
+			tlsConfig := map[string]interface{}{
+				"insecureSkipVerify": true,
+			}
+			if target.clientCert {
+				secretName := util.ClusterClientTLSSecretName(tc.Name)
+				tlsConfig["ca"] = map[string]interface{}{
+					"secret": map[string]interface{}{"name": secretName, "key": corev1.ServiceAccountRootCAKey},
+				}
+				tlsConfig["cert"] = map[string]interface{}{
+					"secret": map[string]interface{}{"name": secretName, "key": corev1.TLSCertKey},
+				}
+				tlsConfig["keySecret"] = map[string]interface{}{"name": secretName, "key": corev1.TLSPrivateKeyKey}
+			}
+			endpoint["tlsConfig"] = tlsConfig
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	matchLabels := map[string]interface{}{}
+	for k, v := range target.selector {
+		matchLabels[k] = v
+	}
+	pm := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": matchLabels,
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{tc.Namespace},
+				},
+				"podMetricsEndpoints": endpoints,
+			},
+		},
+	}
+	pm.SetGroupVersionKind(podMonitorGVK)
+	pm.SetNamespace(tc.Namespace)
+	pm.SetName(fmt.Sprintf("%s-%s", tc.Name, target.component))
+	pm.SetLabels(util.CombineStringMap(label.New().Instance(tc.Name).Component(target.component).Labels(), monitor.Labels))
+	pm.SetOwnerReferences([]metav1.OwnerReference{controller.GetOwnerRef(tc)})
+	return pm
+}
+
+type fakeTidbClusterPodMonitorSyncer struct{}
+
+// NewFakeTidbClusterPodMonitorSyncer returns a fake TidbClusterPodMonitorSyncer
+func NewFakeTidbClusterPodMonitorSyncer() TidbClusterPodMonitorSyncer {
+	return &fakeTidbClusterPodMonitorSyncer{}
+}
+
+func (s *fakeTidbClusterPodMonitorSyncer) Sync(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
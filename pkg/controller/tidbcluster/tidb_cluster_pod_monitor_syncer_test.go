@@ -0,0 +1,139 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTidbClusterPodMonitorSyncer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	syncer := NewTidbClusterPodMonitorSyncer(deps)
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "basic", UID: "uid"},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+			TiDB: &v1alpha1.TiDBSpec{},
+		},
+	}
+	getPodMonitor := func(name string) (*unstructured.Unstructured, error) {
+		pm := &unstructured.Unstructured{}
+		pm.SetGroupVersionKind(podMonitorGVK)
+		return pm, deps.GenericClient.Get(context.TODO(), client.ObjectKey{Namespace: "ns", Name: name}, pm)
+	}
+
+	// the PodMonitors aren't created by default
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.PodMonitors).To(BeEmpty())
+
+	// the PodMonitors aren't created if the CRD isn't installed
+	tc.Spec.PrometheusMonitor = &v1alpha1.PrometheusMonitorSpec{
+		Labels:   map[string]string{"release": "kube-prometheus-stack"},
+		Interval: &metav1.Duration{Duration: 30 * time.Second},
+	}
+	deps.Capabilities = &controller.Capabilities{}
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.PodMonitors).To(BeEmpty())
+
+	deps.Capabilities = controller.AllCapabilities()
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.PodMonitors).To(Equal([]string{"basic-pd", "basic-tidb", "basic-tikv"}))
+	pm, err := getPodMonitor("basic-tikv")
+	g.Expect(err).To(Succeed())
+	g.Expect(pm.GetLabels()).To(HaveKeyWithValue("release", "kube-prometheus-stack"))
+	g.Expect(pm.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/component", "tikv"))
+	g.Expect(metav1.IsControlledBy(pm, tc)).To(BeTrue())
+	endpoints, _, _ := unstructured.NestedSlice(pm.Object, "spec", "podMetricsEndpoints")
+	g.Expect(endpoints).To(HaveLen(1))
+	endpoint := endpoints[0].(map[string]interface{})
+	g.Expect(endpoint["targetPort"]).To(BeEquivalentTo(v1alpha1.DefaultTiKVStatusPort))
+	g.Expect(endpoint["path"]).To(Equal("/metrics"))
+	g.Expect(endpoint["interval"]).To(Equal("30s"))
+
+	// the changed spec is applied
+	tc.Spec.PrometheusMonitor.Interval = nil
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	pm, err = getPodMonitor("basic-tikv")
+	g.Expect(err).To(Succeed())
+	endpoints, _, _ = unstructured.NestedSlice(pm.Object, "spec", "podMetricsEndpoints")
+	g.Expect(endpoints[0]).NotTo(HaveKey("interval"))
+
+	// the PodMonitor of the removed component is deleted
+	tc.Spec.TiDB = nil
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.PodMonitors).To(Equal([]string{"basic-pd", "basic-tikv"}))
+	_, err = getPodMonitor("basic-tidb")
+	g.Expect(err).To(HaveOccurred())
+
+	// the PodMonitors not controlled by the cluster aren't overwritten
+	other := newPodMonitor(tc, podMonitorTargets(tc)[0])
+	other.SetName("basic-tidb")
+	other.SetOwnerReferences(nil)
+	g.Expect(deps.GenericClient.Create(context.TODO(), other)).To(Succeed())
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{}
+	g.Expect(syncer.Sync(tc)).NotTo(Succeed())
+
+	// all the PodMonitors controlled by the cluster are deleted if the spec is removed
+	tc.Spec.PrometheusMonitor = nil
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.PodMonitors).To(BeEmpty())
+	_, err = getPodMonitor("basic-pd")
+	g.Expect(err).To(HaveOccurred())
+	_, err = getPodMonitor("basic-tidb")
+	g.Expect(err).To(Succeed())
+}
+
+func TestNewPodMonitorWithTLS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "basic"},
+		Spec: v1alpha1.TidbClusterSpec{
+			TLSCluster:        &v1alpha1.TLSCluster{Enabled: true},
+			TiFlash:           &v1alpha1.TiFlashSpec{},
+			TiProxy:           &v1alpha1.TiProxySpec{},
+			PrometheusMonitor: &v1alpha1.PrometheusMonitorSpec{},
+		},
+	}
+	targets := podMonitorTargets(tc)
+	g.Expect(targets).To(HaveLen(2))
+
+	tiflash := newPodMonitor(tc, targets[0])
+	g.Expect(tiflash.GetName()).To(Equal("basic-tiflash"))
+	endpoints, _, _ := unstructured.NestedSlice(tiflash.Object, "spec", "podMetricsEndpoints")
+	g.Expect(endpoints).To(HaveLen(2))
+	for _, ep := range endpoints {
+		g.Expect(ep).To(HaveKeyWithValue("scheme", "https"))
+		name, _, _ := unstructured.NestedString(ep.(map[string]interface{}), "tlsConfig", "keySecret", "name")
+		g.Expect(name).To(Equal("basic-cluster-client-secret"))
+	}
+
+	tiproxy := newPodMonitor(tc, targets[1])
+	endpoints, _, _ = unstructured.NestedSlice(tiproxy.Object, "spec", "podMetricsEndpoints")
+	g.Expect(endpoints).To(HaveLen(1))
+	g.Expect(endpoints[0]).To(HaveKeyWithValue("path", "/api/metrics"))
+	g.Expect(endpoints[0]).To(HaveKeyWithValue("tlsConfig", map[string]interface{}{"insecureSkipVerify": true}))
+}
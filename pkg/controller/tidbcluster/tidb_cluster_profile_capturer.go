@@ -0,0 +1,265 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultProfileDuration is the duration the CPU profile is sampled in by default
+	defaultProfileDuration = 30 * time.Second
+	// profileCaptureTimeout is the timeout of fetching and saving a profile besides the sampling
+	profileCaptureTimeout = time.Minute
+)
+
+// TidbClusterProfileCapturer captures the profile described by spec.profileCapture from the status port
+// of a member when it is requested by the `tidb.pingcap.com/profile-capture` annotation, and saves it to
+// the storage. The capture runs in the background and its status is written to the tidb cluster status.
+type TidbClusterProfileCapturer interface {
+	Capture(*v1alpha1.TidbCluster) error
+}
+
+// profileCapture is a capture running in the background
+type profileCapture struct {
+	status v1alpha1.ProfileCaptureStatus
+	done   bool
+	err    error
+}
+
+type tidbClusterProfileCapturer struct {
+	deps *controller.Dependencies
+
+	lock sync.Mutex
+	// captures are the running and finished captures not written to the status yet, keyed by the tidb cluster
+	captures map[string]*profileCapture
+
+	// fetch and save can be replaced in unit tests
+	fetch func(ctx context.Context, tc *v1alpha1.TidbCluster, url string) ([]byte, error)
+	save  func(ctx context.Context, ns string, provider v1alpha1.StorageProvider, path string, data []byte) error
+}
+
+// NewTidbClusterProfileCapturer returns a TidbClusterProfileCapturer
+func NewTidbClusterProfileCapturer(deps *controller.Dependencies) TidbClusterProfileCapturer {
+	c := &tidbClusterProfileCapturer{
+		deps:     deps,
+		captures: map[string]*profileCapture{},
+	}
+	c.fetch = c.fetchProfile
+	c.save = c.saveProfile
+	return c
+}
+
+var _ TidbClusterProfileCapturer = &tidbClusterProfileCapturer{}
+
+func (c *tidbClusterProfileCapturer) Capture(tc *v1alpha1.TidbCluster) error {
+	request, ok := tc.Annotations[label.AnnProfileCaptureKey]
+	if !ok || request == "" {
+		return nil
+	}
+	status := tc.Status.ProfileCapture
+	if status != nil && status.Request == request && status.Phase != v1alpha1.ProfileCaptureRunning {
+		return nil
+	}
+	if tc.Spec.ProfileCapture == nil {
+		tc.Status.ProfileCapture = &v1alpha1.ProfileCaptureStatus{
+			Request:        request,
+			Phase:          v1alpha1.ProfileCaptureFailed,
+			CompletionTime: metav1.Now(),
+			Message:        "spec.profileCapture is not set",
+		}
+		return nil
+	}
+
+	key := fmt.Sprintf("%s/%s", tc.Namespace, tc.Name)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	capture, ok := c.captures[key]
+	if !ok || capture.status.Request != request {
+		// the capture is started for a new request, or restarted if the operator restarted during it
+		capture = c.start(tc, request)
+		c.captures[key] = capture
+		tc.Status.ProfileCapture = capture.status.DeepCopy()
+		return controller.RequeueErrorf("profile capture %q of tidbcluster %s/%s is started", request, tc.Namespace, tc.Name)
+	}
+	if !capture.done {
+		return controller.RequeueErrorf("profile capture %q of tidbcluster %s/%s is running", request, tc.Namespace, tc.Name)
+	}
+
+	delete(c.captures, key)
+	status = capture.status.DeepCopy()
+	status.CompletionTime = metav1.Now()
+	if capture.err != nil {
+		status.Phase = v1alpha1.ProfileCaptureFailed
+		status.Message = capture.err.Error()
+		c.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "ProfileCaptureFailed", "failed to capture %s profile of %s: %v", status.Type, status.Member, capture.err)
+	} else {
+		status.Phase = v1alpha1.ProfileCaptureComplete
+		status.Message = fmt.Sprintf("the profile is saved to %s", status.Path)
+		c.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "ProfileCaptured", "%s profile of %s is saved to %s", status.Type, status.Member, status.Path)
+	}
+	tc.Status.ProfileCapture = status
+	klog.Infof("TidbCluster: [%s/%s] profile capture %q completed, phase: %s", tc.Namespace, tc.Name, request, status.Phase)
+	return nil
+}
+
+// start starts the capture in the background, c.lock must be held
+func (c *tidbClusterProfileCapturer) start(tc *v1alpha1.TidbCluster, request string) *profileCapture {
+	spec := tc.Spec.ProfileCapture.DeepCopy()
+	profileType := spec.Type
+	if profileType == "" {
+		profileType = v1alpha1.ProfileTypeCPU
+	}
+	duration := defaultProfileDuration
+	if spec.Duration != nil {
+		duration = spec.Duration.Duration
+	}
+
+	now := time.Now()
+	member, url, ext := profileTarget(tc, spec.Component, spec.Ordinal, profileType, duration)
+	capture := &profileCapture{
+		status: v1alpha1.ProfileCaptureStatus{
+			Request:   request,
+			Phase:     v1alpha1.ProfileCaptureRunning,
+			Member:    member,
+			Type:      profileType,
+			Path:      fmt.Sprintf("%s/%s/%s-%s-%s.%s", tc.Namespace, tc.Name, member, strings.ToLower(string(profileType)), now.UTC().Format("20060102150405"), ext),
+			StartTime: metav1.NewTime(now),
+		},
+	}
+	klog.Infof("TidbCluster: [%s/%s] start profile capture %q from %s", tc.Namespace, tc.Name, request, url)
+
+	tc = tc.DeepCopy()
+	path := capture.status.Path
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), duration+profileCaptureTimeout)
+		defer cancel()
+		data, err := c.fetch(ctx, tc, url)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch the profile from %s: %v", url, err)
+		} else if err = c.save(ctx, tc.Namespace, spec.StorageProvider, path, data); err != nil {
+			err = fmt.Errorf("failed to save the profile to %s: %v", path, err)
+		}
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		capture.done = true
+		capture.err = err
+	}()
+	return capture
+}
+
+// profileTarget returns the name of the member, the url of its profile and the extension of the profile.
+// PD and TiDB serve the profiles of Go, and TiKV serves the CPU profile as a flame graph.
+func profileTarget(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType, ordinal int32, profileType v1alpha1.ProfileType, duration time.Duration) (string, string, string) {
+	var setName, peerServiceName, apiPrefix string
+	var port int32
+	ext := "pb.gz"
+	switch component {
+	case v1alpha1.PDMemberType:
+		setName, peerServiceName = controller.PDMemberName(tc.Name), controller.PDPeerMemberName(tc.Name)
+		port, apiPrefix = v1alpha1.DefaultPDClientPort, "/pd/api/v1"
+	case v1alpha1.TiKVMemberType:
+		setName, peerServiceName = controller.TiKVMemberName(tc.Name), controller.TiKVPeerMemberName(tc.Name)
+		port = v1alpha1.DefaultTiKVStatusPort
+		ext = "prof"
+		if profileType == v1alpha1.ProfileTypeCPU {
+			ext = "svg"
+		}
+	default:
+		setName, peerServiceName = controller.TiDBMemberName(tc.Name), controller.TiDBPeerMemberName(tc.Name)
+		port = v1alpha1.DefaultTiDBStatusPort
+	}
+
+	member := fmt.Sprintf("%s-%d", setName, ordinal)
+	host := fmt.Sprintf("%s.%s.%s", member, peerServiceName, tc.Namespace)
+	if tc.Spec.ClusterDomain != "" {
+		host = fmt.Sprintf("%s.svc.%s", host, tc.Spec.ClusterDomain)
+	}
+	path := "/debug/pprof/heap"
+	if profileType == v1alpha1.ProfileTypeCPU {
+		path = fmt.Sprintf("/debug/pprof/profile?seconds=%d", int64(duration.Seconds()))
+	}
+	return member, fmt.Sprintf("%s://%s:%d%s%s", tc.Scheme(), host, port, apiPrefix, path), ext
+}
+
+// fetchProfile gets the profile with the client certificate of the cluster if TLS is enabled
+func (c *tidbClusterProfileCapturer) fetchProfile(ctx context.Context, tc *v1alpha1.TidbCluster, url string) ([]byte, error) {
+	client := &http.Client{}
+	if tc.IsTLSClusterEnabled() {
+		tlsConfig, err := pdapi.GetTLSConfig(c.deps.SecretLister, pdapi.Namespace(tc.Namespace), util.ClusterClientTLSSecretName(tc.Name))
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: true}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httputil.DeferClose(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error response %s: %v", string(body), res.StatusCode)
+	}
+	return body, nil
+}
+
+// saveProfile writes the profile to the storage with the credential in the namespace of the cluster
+func (c *tidbClusterProfileCapturer) saveProfile(ctx context.Context, ns string, provider v1alpha1.StorageProvider, path string, data []byte) error {
+	cred := backuputil.GetStorageCredential(ns, provider, c.deps.SecretLister)
+	s, err := backuputil.NewStorageBackend(provider, cred)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.WriteAll(ctx, path, data, nil)
+}
+
+type fakeTidbClusterProfileCapturer struct{}
+
+// NewFakeTidbClusterProfileCapturer returns a fake TidbClusterProfileCapturer
+func NewFakeTidbClusterProfileCapturer() TidbClusterProfileCapturer {
+	return &fakeTidbClusterProfileCapturer{}
+}
+
+func (c *fakeTidbClusterProfileCapturer) Capture(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterProfileCapturer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	capturer := NewTidbClusterProfileCapturer(deps).(*tidbClusterProfileCapturer)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{Replicas: 1},
+			TiKV: &v1alpha1.TiKVSpec{Replicas: 3},
+		},
+	}
+
+	var fetched, saved []string
+	capturer.fetch = func(_ context.Context, _ *v1alpha1.TidbCluster, url string) ([]byte, error) {
+		fetched = append(fetched, url)
+		if strings.Contains(url, "heap") {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return []byte("profile"), nil
+	}
+	capturer.save = func(_ context.Context, _ string, _ v1alpha1.StorageProvider, path string, data []byte) error {
+		saved = append(saved, path)
+		g.Expect(string(data)).To(Equal("profile"))
+		return nil
+	}
+	done := func() bool {
+		capturer.lock.Lock()
+		defer capturer.lock.Unlock()
+		capture, ok := capturer.captures["default/test"]
+		return ok && capture.done
+	}
+
+	// nothing is captured without the annotation
+	g.Expect(capturer.Capture(tc)).To(Succeed())
+	g.Expect(tc.Status.ProfileCapture).To(BeNil())
+
+	// the capture fails without spec.profileCapture
+	tc.Annotations = map[string]string{label.AnnProfileCaptureKey: "1"}
+	g.Expect(capturer.Capture(tc)).To(Succeed())
+	g.Expect(tc.Status.ProfileCapture.Phase).To(Equal(v1alpha1.ProfileCaptureFailed))
+
+	// the cpu profile is captured in the background and saved
+	tc.Annotations[label.AnnProfileCaptureKey] = "2"
+	tc.Spec.ProfileCapture = &v1alpha1.ProfileCaptureSpec{
+		Component:       v1alpha1.TiKVMemberType,
+		Ordinal:         1,
+		Duration:        &metav1.Duration{Duration: 10 * time.Second},
+		StorageProvider: v1alpha1.StorageProvider{S3: &v1alpha1.S3StorageProvider{Bucket: "profiles"}},
+	}
+	err := capturer.Capture(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	status := tc.Status.ProfileCapture
+	g.Expect(status.Request).To(Equal("2"))
+	g.Expect(status.Phase).To(Equal(v1alpha1.ProfileCaptureRunning))
+	g.Expect(status.Member).To(Equal("test-tikv-1"))
+	g.Expect(status.Type).To(Equal(v1alpha1.ProfileTypeCPU))
+	g.Expect(status.Path).To(HavePrefix("default/test/test-tikv-1-cpu-"))
+	g.Expect(status.Path).To(HaveSuffix(".svg"))
+	g.Eventually(done, 10*time.Second).Should(BeTrue())
+	g.Expect(fetched).To(Equal([]string{"http://test-tikv-1.test-tikv-peer.default:20180/debug/pprof/profile?seconds=10"}))
+	g.Expect(saved).To(Equal([]string{status.Path}))
+
+	g.Expect(capturer.Capture(tc)).To(Succeed())
+	g.Expect(tc.Status.ProfileCapture.Phase).To(Equal(v1alpha1.ProfileCaptureComplete))
+	g.Expect(tc.Status.ProfileCapture.CompletionTime.IsZero()).To(BeFalse())
+
+	// the same request is captured only once
+	g.Expect(capturer.Capture(tc)).To(Succeed())
+	g.Expect(fetched).To(HaveLen(1))
+
+	// the failure of fetching the profile is reported
+	tc.Annotations[label.AnnProfileCaptureKey] = "3"
+	tc.Spec.ProfileCapture.Component = v1alpha1.PDMemberType
+	tc.Spec.ProfileCapture.Type = v1alpha1.ProfileTypeHeap
+	g.Expect(controller.IsRequeueError(capturer.Capture(tc))).To(BeTrue())
+	g.Eventually(done, 10*time.Second).Should(BeTrue())
+	g.Expect(capturer.Capture(tc)).To(Succeed())
+	status = tc.Status.ProfileCapture
+	g.Expect(status.Phase).To(Equal(v1alpha1.ProfileCaptureFailed))
+	g.Expect(status.Message).To(ContainSubstring("connection refused"))
+	g.Expect(fetched[1]).To(Equal("http://test-pd-1.test-pd-peer.default:2379/pd/api/v1/debug/pprof/heap"))
+	g.Expect(saved).To(HaveLen(1))
+}
+
+func TestProfileTarget(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Name = "test"
+	tc.Namespace = "ns"
+	tc.Spec.ClusterDomain = "cluster.local"
+	tc.Spec.TLSCluster = &v1alpha1.TLSCluster{Enabled: true}
+
+	member, url, ext := profileTarget(tc, v1alpha1.TiDBMemberType, 0, v1alpha1.ProfileTypeCPU, time.Minute)
+	g.Expect(member).To(Equal("test-tidb-0"))
+	g.Expect(url).To(Equal("https://test-tidb-0.test-tidb-peer.ns.svc.cluster.local:10080/debug/pprof/profile?seconds=60"))
+	g.Expect(ext).To(Equal("pb.gz"))
+
+	_, url, ext = profileTarget(tc, v1alpha1.TiKVMemberType, 2, v1alpha1.ProfileTypeHeap, time.Minute)
+	g.Expect(url).To(Equal("https://test-tikv-2.test-tikv-peer.ns.svc.cluster.local:20180/debug/pprof/heap"))
+	g.Expect(ext).To(Equal("prof"))
+}
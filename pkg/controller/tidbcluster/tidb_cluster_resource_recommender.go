@@ -0,0 +1,379 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultRecommendationMarginPercent is the default headroom added to the peak usage
+	defaultRecommendationMarginPercent = 20
+	// defaultRecommendationWindow is the default duration the peak usage is observed in
+	defaultRecommendationWindow = 24 * time.Hour
+	// defaultRecommendationMinChangePercent is the default min change of the requests to apply a recommendation
+	defaultRecommendationMinChangePercent = 10
+	// recommendationInterval is the interval the usage of the components is observed at
+	recommendationInterval = time.Minute
+
+	// recommendedCPUStep and recommendedMemoryStep are what the recommendations are rounded up to
+	recommendedCPUStep    = 10      // milli cores
+	recommendedMemoryStep = 1 << 20 // bytes
+)
+
+// defaultRecommendationComponents are the components the resources are recommended for by default
+var defaultRecommendationComponents = []v1alpha1.MemberType{
+	v1alpha1.PDMemberType,
+	v1alpha1.TiDBMemberType,
+	v1alpha1.TiKVMemberType,
+	v1alpha1.TiFlashMemberType,
+	v1alpha1.TiCDCMemberType,
+	v1alpha1.TiProxyMemberType,
+}
+
+// TidbClusterResourceRecommender observes the resource usage of the components by the metrics API,
+// publishes the recommended requests in the status, and applies them to the spec during the maintenance
+// windows if spec.resourceRecommendation.autoApply is set. The applied requests are patched to the spec
+// before the components are synced to them.
+type TidbClusterResourceRecommender interface {
+	Recommend(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterResourceRecommender struct {
+	deps    *controller.Dependencies
+	metrics podMetricsGetter
+	// now can be replaced in unit tests
+	now func() time.Time
+}
+
+// NewTidbClusterResourceRecommender returns a TidbClusterResourceRecommender
+func NewTidbClusterResourceRecommender(deps *controller.Dependencies) TidbClusterResourceRecommender {
+	return &tidbClusterResourceRecommender{
+		deps:    deps,
+		metrics: &realPodMetricsGetter{deps: deps},
+		now:     time.Now,
+	}
+}
+
+var _ TidbClusterResourceRecommender = &tidbClusterResourceRecommender{}
+
+func (r *tidbClusterResourceRecommender) Recommend(tc *v1alpha1.TidbCluster) error {
+	policy := tc.Spec.ResourceRecommendation
+	if policy == nil {
+		tc.Status.ResourceRecommendations = nil
+		return nil
+	}
+	now := r.now()
+
+	components := policy.Components
+	if len(components) == 0 {
+		components = defaultRecommendationComponents
+	}
+	var recommendations []v1alpha1.ResourceRecommendation
+	for _, component := range components {
+		resources := componentResources(tc, component)
+		if resources == nil {
+			continue
+		}
+		rec := v1alpha1.ResourceRecommendation{Component: component}
+		for _, old := range tc.Status.ResourceRecommendations {
+			if old.Component == component {
+				rec = *old.DeepCopy()
+				break
+			}
+		}
+		if rec.LastUpdateTime == nil || now.Sub(rec.LastUpdateTime.Time) >= recommendationInterval {
+			if err := r.observe(tc, policy, &rec, resources, now); err != nil {
+				// the recommendation is kept until the metrics are available again
+				klog.Warningf("TidbCluster: [%s/%s] failed to observe the resource usage of %s: %v", tc.Namespace, tc.Name, component, err)
+			}
+		}
+		recommendations = append(recommendations, rec)
+	}
+	tc.Status.ResourceRecommendations = recommendations
+
+	if policy.AutoApply != nil {
+		return r.apply(tc, policy.AutoApply, now)
+	}
+	return nil
+}
+
+// observe updates the peak usage of the component by the current usage and recalculates the target
+func (r *tidbClusterResourceRecommender) observe(tc *v1alpha1.TidbCluster, policy *v1alpha1.ResourceRecommendationPolicy,
+	rec *v1alpha1.ResourceRecommendation, resources *corev1.ResourceRequirements, now time.Time) error {
+	selector, err := label.New().Instance(tc.Name).Component(rec.Component.String()).Selector()
+	if err != nil {
+		return err
+	}
+	pods, err := r.metrics.GetPodMetrics(tc.Namespace, selector)
+	if err != nil {
+		return err
+	}
+	usage := corev1.ResourceList{}
+	for _, pod := range pods {
+		for _, container := range pod.Containers {
+			if container.Name != rec.Component.String() {
+				continue
+			}
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				q, ok := container.Usage[name]
+				if !ok {
+					continue
+				}
+				if cur, ok := usage[name]; !ok || q.Cmp(cur) > 0 {
+					usage[name] = q.DeepCopy()
+				}
+			}
+		}
+	}
+	if len(usage) == 0 {
+		return fmt.Errorf("no usage of the %s containers is reported", rec.Component)
+	}
+
+	lastUpdate := metav1.NewTime(now)
+	rec.LastUpdateTime = &lastUpdate
+	window := defaultRecommendationWindow
+	if policy.Window != nil {
+		window = policy.Window.Duration
+	}
+	if rec.PeakTime == nil || now.Sub(rec.PeakTime.Time) > window {
+		rec.PeakUsage = usage
+		rec.PeakTime = &lastUpdate
+	} else {
+		for name, q := range usage {
+			if peak, ok := rec.PeakUsage[name]; !ok || q.Cmp(peak) > 0 {
+				if rec.PeakUsage == nil {
+					rec.PeakUsage = corev1.ResourceList{}
+				}
+				rec.PeakUsage[name] = q
+				rec.PeakTime = &lastUpdate
+			}
+		}
+	}
+
+	margin := int64(defaultRecommendationMarginPercent)
+	if policy.MarginPercent != nil {
+		margin = int64(*policy.MarginPercent)
+	}
+	target := corev1.ResourceList{}
+	for name, peak := range rec.PeakUsage {
+		var q resource.Quantity
+		switch name {
+		case corev1.ResourceCPU:
+			q = *resource.NewMilliQuantity(roundUp(peak.MilliValue()*(100+margin)/100, recommendedCPUStep), resource.DecimalSI)
+		case corev1.ResourceMemory:
+			q = *resource.NewQuantity(roundUp(peak.Value()*(100+margin)/100, recommendedMemoryStep), resource.BinarySI)
+		default:
+			continue
+		}
+		if lower, ok := policy.MinAllowed[name]; ok && q.Cmp(lower) < 0 {
+			q = lower.DeepCopy()
+		}
+		if upper, ok := policy.MaxAllowed[name]; ok && q.Cmp(upper) > 0 {
+			q = upper.DeepCopy()
+		}
+		// the requests must not exceed the limits
+		if limit, ok := resources.Limits[name]; ok && q.Cmp(limit) > 0 {
+			q = limit.DeepCopy()
+		}
+		target[name] = q
+	}
+	rec.Target = target
+	return nil
+}
+
+// apply applies the targets to the requests of the components once in a maintenance window, the spec
+// and the apply time are changed only after the requests are patched
+func (r *tidbClusterResourceRecommender) apply(tc *v1alpha1.TidbCluster, autoApply *v1alpha1.ResourceRecommendationAutoApply, now time.Time) error {
+	sched, err := cron.ParseStandard(autoApply.Schedule)
+	if err != nil {
+		klog.Warningf("TidbCluster: [%s/%s] parse the schedule %q of the resource recommendation failed: %v", tc.Namespace, tc.Name, autoApply.Schedule, err)
+		return nil
+	}
+	duration := defaultMaintenanceWindowDuration
+	if autoApply.Duration != nil {
+		duration = autoApply.Duration.Duration
+	}
+	if sched.Next(now.Add(-duration)).After(now) || !isTidbClusterReady(tc) {
+		return nil
+	}
+	minChange := int64(defaultRecommendationMinChangePercent)
+	if autoApply.MinChangePercent != nil {
+		minChange = int64(*autoApply.MinChangePercent)
+	}
+
+	requests := map[v1alpha1.MemberType]corev1.ResourceList{}
+	changes := map[v1alpha1.MemberType][]string{}
+	spec := map[string]interface{}{}
+	for _, rec := range tc.Status.ResourceRecommendations {
+		if rec.LastApplyTime != nil && now.Sub(rec.LastApplyTime.Time) < duration {
+			continue
+		}
+		resources := componentResources(tc, rec.Component)
+		if resources == nil {
+			continue
+		}
+		applied := resources.Requests.DeepCopy()
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			target, ok := rec.Target[name]
+			if !ok {
+				continue
+			}
+			current, ok := resources.Requests[name]
+			if ok && !changedByPercent(current, target, minChange) {
+				continue
+			}
+			if applied == nil {
+				applied = corev1.ResourceList{}
+			}
+			applied[name] = target.DeepCopy()
+			changes[rec.Component] = append(changes[rec.Component], fmt.Sprintf("%s %s -> %s", name, current.String(), target.String()))
+		}
+		if len(changes[rec.Component]) == 0 {
+			continue
+		}
+		requests[rec.Component] = applied
+		spec[rec.Component.String()] = map[string]interface{}{"requests": applied}
+	}
+	if len(spec) == 0 {
+		return nil
+	}
+	if err := patchTidbClusterSpec(r.deps, tc, spec); err != nil {
+		return err
+	}
+
+	for i := range tc.Status.ResourceRecommendations {
+		rec := &tc.Status.ResourceRecommendations[i]
+		applied, ok := requests[rec.Component]
+		if !ok {
+			continue
+		}
+		componentResources(tc, rec.Component).Requests = applied
+		applyTime := metav1.NewTime(now)
+		rec.LastApplyTime = &applyTime
+		klog.Infof("TidbCluster: [%s/%s] the recommended requests of %s are applied: %v", tc.Namespace, tc.Name, rec.Component, changes[rec.Component])
+		r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "ResourceRecommendationApplied",
+			"the recommended requests of %s are applied: %v", rec.Component, changes[rec.Component])
+	}
+	return nil
+}
+
+// componentResources returns the resources in the spec of the component, nil is returned if the
+// component isn't deployed or the resources of it aren't recommended
+func componentResources(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) *corev1.ResourceRequirements {
+	switch component {
+	case v1alpha1.PDMemberType:
+		if tc.Spec.PD != nil {
+			return &tc.Spec.PD.ResourceRequirements
+		}
+	case v1alpha1.TiDBMemberType:
+		if tc.Spec.TiDB != nil {
+			return &tc.Spec.TiDB.ResourceRequirements
+		}
+	case v1alpha1.TiKVMemberType:
+		if tc.Spec.TiKV != nil {
+			return &tc.Spec.TiKV.ResourceRequirements
+		}
+	case v1alpha1.TiFlashMemberType:
+		if tc.Spec.TiFlash != nil {
+			return &tc.Spec.TiFlash.ResourceRequirements
+		}
+	case v1alpha1.TiCDCMemberType:
+		if tc.Spec.TiCDC != nil {
+			return &tc.Spec.TiCDC.ResourceRequirements
+		}
+	case v1alpha1.TiProxyMemberType:
+		if tc.Spec.TiProxy != nil {
+			return &tc.Spec.TiProxy.ResourceRequirements
+		}
+	}
+	return nil
+}
+
+// changedByPercent returns whether target differs from current by more than percent of current
+func changedByPercent(current, target resource.Quantity, percent int64) bool {
+	cur, tgt := current.MilliValue(), target.MilliValue()
+	diff := tgt - cur
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff*100 > cur*percent
+}
+
+func roundUp(value, step int64) int64 {
+	return (value + step - 1) / step * step
+}
+
+// podMetrics is the resource usage of a pod reported by the metrics API, metrics.k8s.io/v1beta1
+type podMetrics struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Containers        []containerMetrics `json:"containers"`
+}
+
+type containerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+type podMetricsList struct {
+	Items []podMetrics `json:"items"`
+}
+
+// podMetricsGetter gets the resource usage of the pods
+type podMetricsGetter interface {
+	GetPodMetrics(namespace string, selector labels.Selector) ([]podMetrics, error)
+}
+
+type realPodMetricsGetter struct {
+	deps *controller.Dependencies
+}
+
+func (g *realPodMetricsGetter) GetPodMetrics(namespace string, selector labels.Selector) ([]podMetrics, error) {
+	data, err := g.deps.KubeClientset.Discovery().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", namespace, "pods").
+		Param("labelSelector", selector.String()).
+		DoRaw(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("get the pod metrics from the metrics API failed: %v", err)
+	}
+	list := &podMetricsList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("decode the pod metrics failed: %v", err)
+	}
+	return list.Items, nil
+}
+
+type fakeTidbClusterResourceRecommender struct{}
+
+// NewFakeTidbClusterResourceRecommender returns a fake TidbClusterResourceRecommender
+func NewFakeTidbClusterResourceRecommender() TidbClusterResourceRecommender {
+	return &fakeTidbClusterResourceRecommender{}
+}
+
+func (r *fakeTidbClusterResourceRecommender) Recommend(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
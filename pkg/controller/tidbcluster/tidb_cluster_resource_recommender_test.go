@@ -0,0 +1,170 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type fakePodMetricsGetter struct {
+	usages map[string][]corev1.ResourceList
+	err    error
+}
+
+func (g *fakePodMetricsGetter) GetPodMetrics(_ string, selector labels.Selector) ([]podMetrics, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	var pods []podMetrics
+	for component, usages := range g.usages {
+		if !selector.Matches(labels.Set(label.New().Instance("test").Component(component))) {
+			continue
+		}
+		for _, usage := range usages {
+			pods = append(pods, podMetrics{Containers: []containerMetrics{
+				{Name: component, Usage: usage},
+				{Name: "slowlog", Usage: resourceList("64", "64Gi")},
+			}})
+		}
+	}
+	return pods, nil
+}
+
+func resourceList(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func TestTidbClusterResourceRecommender(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the maintenance windows begin at 02:00 every Saturday
+	now := time.Date(2024, 6, 7, 12, 0, 0, 0, time.Local) // Friday
+	metrics := &fakePodMetricsGetter{usages: map[string][]corev1.ResourceList{
+		"tikv": {resourceList("1500m", "3Gi"), resourceList("2", "2Gi")},
+		"tidb": {resourceList("500m", "1Gi")},
+	}}
+	deps := controller.NewFakeDependencies()
+	recommender := NewTidbClusterResourceRecommender(deps).(*tidbClusterResourceRecommender)
+	recommender.now = func() time.Time { return now }
+	recommender.metrics = metrics
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiKV: &v1alpha1.TiKVSpec{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: resourceList("1", "4Gi"),
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			}},
+			TiDB: &v1alpha1.TiDBSpec{},
+			ResourceRecommendation: &v1alpha1.ResourceRecommendationPolicy{
+				MaxAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+				AutoApply:  &v1alpha1.ResourceRecommendationAutoApply{Schedule: "0 2 * * 6"},
+			},
+		},
+	}
+	targets := func() map[v1alpha1.MemberType]string {
+		m := map[v1alpha1.MemberType]string{}
+		for _, rec := range tc.Status.ResourceRecommendations {
+			cpu, memory := rec.Target[corev1.ResourceCPU], rec.Target[corev1.ResourceMemory]
+			m[rec.Component] = fmt.Sprintf("%s/%s", cpu.String(), memory.String())
+		}
+		return m
+	}
+	requests := func(resources corev1.ResourceRequirements) string {
+		cpu, memory := resources.Requests[corev1.ResourceCPU], resources.Requests[corev1.ResourceMemory]
+		return fmt.Sprintf("%s/%s", cpu.String(), memory.String())
+	}
+
+	// the recommendations are published outside the maintenance window
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	g.Expect(targets()).To(Equal(map[v1alpha1.MemberType]string{
+		v1alpha1.TiDBMemberType: "600m/1229Mi",
+		v1alpha1.TiKVMemberType: "2400m/3687Mi",
+	}))
+	g.Expect(requests(tc.Spec.TiKV.ResourceRequirements)).To(Equal("1/4Gi"))
+
+	// the usage isn't observed again within the interval
+	metrics.usages["tikv"] = []corev1.ResourceList{resourceList("3", "10Gi")}
+	now = now.Add(recommendationInterval / 2)
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	g.Expect(targets()[v1alpha1.TiKVMemberType]).To(Equal("2400m/3687Mi"))
+
+	// in the maintenance window but the cluster is not ready
+	now = time.Date(2024, 6, 8, 3, 0, 0, 0, time.Local)
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	// the targets are bounded by the limits and the max allowed
+	g.Expect(targets()[v1alpha1.TiKVMemberType]).To(Equal("3600m/8Gi"))
+	g.Expect(requests(tc.Spec.TiKV.ResourceRequirements)).To(Equal("1/4Gi"))
+
+	// the recommendations aren't applied if the requests fail to be persisted
+	tc.Status.Conditions = []v1alpha1.TidbClusterCondition{{Type: v1alpha1.TidbClusterReady, Status: corev1.ConditionTrue}}
+	g.Expect(recommender.Recommend(tc)).NotTo(Succeed())
+	g.Expect(requests(tc.Spec.TiKV.ResourceRequirements)).To(Equal("1/4Gi"))
+	for _, rec := range tc.Status.ResourceRecommendations {
+		g.Expect(rec.LastApplyTime).To(BeNil())
+	}
+
+	// the recommendations are applied
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	g.Expect(requests(tc.Spec.TiKV.ResourceRequirements)).To(Equal("3600m/8Gi"))
+	g.Expect(requests(tc.Spec.TiDB.ResourceRequirements)).To(Equal("600m/1229Mi"))
+	for _, rec := range tc.Status.ResourceRecommendations {
+		g.Expect(rec.LastApplyTime).To(Equal(&metav1.Time{Time: now}))
+	}
+	persisted, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requests(persisted.Spec.TiKV.ResourceRequirements)).To(Equal("3600m/8Gi"))
+	g.Expect(requests(persisted.Spec.TiDB.ResourceRequirements)).To(Equal("600m/1229Mi"))
+
+	// the recommendations are applied once in a maintenance window
+	tc.Spec.TiKV.Requests = resourceList("1", "4Gi")
+	now = now.Add(recommendationInterval)
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	g.Expect(requests(tc.Spec.TiKV.ResourceRequirements)).To(Equal("1/4Gi"))
+
+	// the recommendations are kept if the metrics aren't available
+	metrics.err = fmt.Errorf("the server could not find the requested resource")
+	now = now.Add(recommendationInterval)
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	g.Expect(targets()[v1alpha1.TiKVMemberType]).To(Equal("3600m/8Gi"))
+
+	// the peak usage out of the window is replaced by the current usage
+	metrics.err = nil
+	metrics.usages["tikv"] = []corev1.ResourceList{resourceList("1", "2Gi")}
+	now = now.Add(defaultRecommendationWindow + time.Hour)
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	g.Expect(targets()[v1alpha1.TiKVMemberType]).To(Equal("1200m/2458Mi"))
+
+	// the recommendations are removed with the policy
+	tc.Spec.ResourceRecommendation = nil
+	g.Expect(recommender.Recommend(tc)).To(Succeed())
+	g.Expect(tc.Status.ResourceRecommendations).To(BeNil())
+}
@@ -0,0 +1,431 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// selfTestTimeout is the timeout of each check
+	selfTestTimeout = 10 * time.Second
+)
+
+// TidbClusterSelfTester runs a non-destructive self-test against a tidb cluster
+// when it is requested by the `tidb.pingcap.com/self-test` annotation, and writes
+// the report to the tidb cluster status. The self-test runs in the background, so
+// the checks talking to the network don't block the sync.
+type TidbClusterSelfTester interface {
+	Test(*v1alpha1.TidbCluster) error
+}
+
+// selfTest is a self-test running in the background
+type selfTest struct {
+	report v1alpha1.SelfTestReport
+	done   bool
+}
+
+type tidbClusterSelfTester struct {
+	deps *controller.Dependencies
+
+	lock sync.Mutex
+	// tests are the running and finished self-tests not written to the status yet, keyed by the tidb cluster
+	tests map[string]*selfTest
+
+	// lookupHost and checkStorage can be replaced in unit tests
+	lookupHost   func(ctx context.Context, host string) ([]string, error)
+	checkStorage func(ctx context.Context, ns string, provider v1alpha1.StorageProvider) error
+}
+
+// NewTidbClusterSelfTester returns a TidbClusterSelfTester
+func NewTidbClusterSelfTester(deps *controller.Dependencies) TidbClusterSelfTester {
+	t := &tidbClusterSelfTester{
+		deps:       deps,
+		tests:      map[string]*selfTest{},
+		lookupHost: net.DefaultResolver.LookupHost,
+	}
+	t.checkStorage = t.checkBackupStorage
+	return t
+}
+
+var _ TidbClusterSelfTester = &tidbClusterSelfTester{}
+
+func (t *tidbClusterSelfTester) Test(tc *v1alpha1.TidbCluster) error {
+	request, ok := tc.Annotations[label.AnnSelfTestKey]
+	if !ok || request == "" {
+		return nil
+	}
+	report := tc.Status.SelfTest
+	if report != nil && report.Request == request && !report.CompletionTime.IsZero() {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s/%s", tc.Namespace, tc.Name)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	test, ok := t.tests[key]
+	if !ok || test.report.Request != request {
+		// the self-test is started for a new request, or restarted if the operator restarted during it
+		test = t.start(tc, request)
+		t.tests[key] = test
+		tc.Status.SelfTest = test.report.DeepCopy()
+		return controller.RequeueErrorf("self-test %q of tidbcluster %s/%s is started", request, tc.Namespace, tc.Name)
+	}
+	if !test.done {
+		return controller.RequeueErrorf("self-test %q of tidbcluster %s/%s is running", request, tc.Namespace, tc.Name)
+	}
+
+	delete(t.tests, key)
+	tc.Status.SelfTest = test.report.DeepCopy()
+	klog.Infof("TidbCluster: [%s/%s] self-test %q completed, passed: %t", tc.Namespace, tc.Name, request, test.report.Passed)
+	return nil
+}
+
+// start starts the self-test in the background, t.lock must be held
+func (t *tidbClusterSelfTester) start(tc *v1alpha1.TidbCluster, request string) *selfTest {
+	test := &selfTest{
+		report: v1alpha1.SelfTestReport{
+			Request:   request,
+			StartTime: metav1.Now(),
+		},
+	}
+	klog.Infof("TidbCluster: [%s/%s] start self-test %q", tc.Namespace, tc.Name, request)
+
+	tc = tc.DeepCopy()
+	go func() {
+		checks := t.runChecks(tc)
+		passed := true
+		for _, check := range checks {
+			if !check.Passed {
+				passed = false
+			}
+		}
+
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		test.report.Checks = checks
+		test.report.Passed = passed
+		test.report.CompletionTime = metav1.Now()
+		test.done = true
+	}()
+	return test
+}
+
+// runChecks runs the checks in parallel, a check fails if it's not completed in selfTestTimeout
+func (t *tidbClusterSelfTester) runChecks(tc *v1alpha1.TidbCluster) []v1alpha1.SelfTestCheck {
+	now := time.Now()
+	checks := []struct {
+		checkType v1alpha1.SelfTestCheckType
+		check     func() v1alpha1.SelfTestCheck
+	}{
+		{v1alpha1.SelfTestCheckPDHealth, func() v1alpha1.SelfTestCheck { return t.checkPDHealth(tc) }},
+		{v1alpha1.SelfTestCheckTiDBConnectivity, func() v1alpha1.SelfTestCheck { return t.checkTiDBConnectivity(tc) }},
+		{v1alpha1.SelfTestCheckPeerDNS, func() v1alpha1.SelfTestCheck { return t.checkPeerDNS(tc) }},
+		{v1alpha1.SelfTestCheckTLSChain, func() v1alpha1.SelfTestCheck { return t.checkTLSChain(tc, now) }},
+		{v1alpha1.SelfTestCheckBackupStorage, func() v1alpha1.SelfTestCheck { return t.checkBackupStorageReachable(tc) }},
+	}
+
+	results := make([]v1alpha1.SelfTestCheck, len(checks))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCheckWithTimeout(checks[i].checkType, selfTestTimeout, checks[i].check)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// runCheckWithTimeout returns a failed check if the check is not completed in timeout,
+// the check left running is abandoned
+func runCheckWithTimeout(checkType v1alpha1.SelfTestCheckType, timeout time.Duration, check func() v1alpha1.SelfTestCheck) v1alpha1.SelfTestCheck {
+	result := make(chan v1alpha1.SelfTestCheck, 1)
+	go func() {
+		result <- check()
+	}()
+	select {
+	case c := <-result:
+		return c
+	case <-time.After(timeout):
+		return v1alpha1.SelfTestCheck{
+			Type:    checkType,
+			Message: fmt.Sprintf("the check is not completed in %v", timeout),
+		}
+	}
+}
+
+func (t *tidbClusterSelfTester) checkPDHealth(tc *v1alpha1.TidbCluster) v1alpha1.SelfTestCheck {
+	check := v1alpha1.SelfTestCheck{Type: v1alpha1.SelfTestCheckPDHealth}
+	if tc.WithoutLocalPD() {
+		return skipped(check, "PD is not deployed by this cluster")
+	}
+
+	healthInfo, err := controller.GetPDClient(t.deps.PDControl, tc).GetHealth()
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to get PD health: %v", err)
+		return check
+	}
+	var unhealthy []string
+	for _, member := range healthInfo.Healths {
+		if !member.Health {
+			unhealthy = append(unhealthy, member.Name)
+		}
+	}
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		check.Message = fmt.Sprintf("PD members %s are unhealthy", strings.Join(unhealthy, ","))
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("%d PD members are healthy", len(healthInfo.Healths))
+	return check
+}
+
+func (t *tidbClusterSelfTester) checkTiDBConnectivity(tc *v1alpha1.TidbCluster) v1alpha1.SelfTestCheck {
+	check := v1alpha1.SelfTestCheck{Type: v1alpha1.SelfTestCheckTiDBConnectivity}
+	if tc.Spec.TiDB == nil {
+		return skipped(check, "TiDB is not deployed by this cluster")
+	}
+
+	var unreachable []string
+	ordinals := tc.TiDBStsDesiredOrdinals(true).List()
+	for _, ordinal := range ordinals {
+		name := fmt.Sprintf("%s-%d", controller.TiDBMemberName(tc.Name), ordinal)
+		healthy, err := t.deps.TiDBControl.GetHealth(tc, ordinal)
+		if err != nil || !healthy {
+			unreachable = append(unreachable, name)
+		}
+	}
+	if len(unreachable) > 0 {
+		check.Message = fmt.Sprintf("TiDB members %s can not be connected", strings.Join(unreachable, ","))
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("%d TiDB members can be connected", len(ordinals))
+	return check
+}
+
+func (t *tidbClusterSelfTester) checkPeerDNS(tc *v1alpha1.TidbCluster) v1alpha1.SelfTestCheck {
+	check := v1alpha1.SelfTestCheck{Type: v1alpha1.SelfTestCheckPeerDNS}
+
+	var hosts []string
+	addHost := func(podName, peerServiceName string) {
+		host := fmt.Sprintf("%s.%s.%s.svc", podName, peerServiceName, tc.Namespace)
+		if tc.Spec.ClusterDomain != "" {
+			host = fmt.Sprintf("%s.%s", host, tc.Spec.ClusterDomain)
+		}
+		hosts = append(hosts, host)
+	}
+	for name := range tc.Status.PD.Members {
+		addHost(name, controller.PDPeerMemberName(tc.Name))
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		addHost(store.PodName, controller.TiKVPeerMemberName(tc.Name))
+	}
+	for name := range tc.Status.TiDB.Members {
+		addHost(name, controller.TiDBPeerMemberName(tc.Name))
+	}
+	if len(hosts) == 0 {
+		return skipped(check, "no member is found in the cluster status")
+	}
+	sort.Strings(hosts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+	var failed []string
+	for _, host := range hosts {
+		if _, err := t.lookupHost(ctx, host); err != nil {
+			failed = append(failed, host)
+		}
+	}
+	if len(failed) > 0 {
+		check.Message = fmt.Sprintf("failed to resolve %s", strings.Join(failed, ","))
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("%d peer domain names are resolved", len(hosts))
+	return check
+}
+
+func (t *tidbClusterSelfTester) checkTLSChain(tc *v1alpha1.TidbCluster, now time.Time) v1alpha1.SelfTestCheck {
+	check := v1alpha1.SelfTestCheck{Type: v1alpha1.SelfTestCheckTLSChain}
+	if !tc.IsTLSClusterEnabled() {
+		return skipped(check, "TLS is not enabled between the components")
+	}
+
+	secretNames := []string{util.ClusterClientTLSSecretName(tc.Name)}
+	if !tc.WithoutLocalPD() {
+		secretNames = append(secretNames, util.ClusterTLSSecretName(tc.Name, label.PDLabelVal))
+	}
+	if tc.Spec.TiKV != nil {
+		secretNames = append(secretNames, util.ClusterTLSSecretName(tc.Name, label.TiKVLabelVal))
+	}
+	if tc.Spec.TiDB != nil {
+		secretNames = append(secretNames, util.ClusterTLSSecretName(tc.Name, label.TiDBLabelVal))
+	}
+	if tc.Spec.TiFlash != nil {
+		secretNames = append(secretNames, util.ClusterTLSSecretName(tc.Name, label.TiFlashLabelVal))
+	}
+	if tc.Spec.TiCDC != nil {
+		secretNames = append(secretNames, util.ClusterTLSSecretName(tc.Name, label.TiCDCLabelVal))
+	}
+
+	var errs []string
+	for _, name := range secretNames {
+		secret, err := t.deps.SecretLister.Secrets(tc.Namespace).Get(name)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("secret %s: %v", name, err))
+			continue
+		}
+		if err := verifyCertChain(secret, now); err != nil {
+			errs = append(errs, fmt.Sprintf("secret %s: %v", name, err))
+		}
+	}
+	if len(errs) > 0 {
+		check.Message = strings.Join(errs, "; ")
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("%d certificates are verified", len(secretNames))
+	return check
+}
+
+func (t *tidbClusterSelfTester) checkBackupStorageReachable(tc *v1alpha1.TidbCluster) v1alpha1.SelfTestCheck {
+	check := v1alpha1.SelfTestCheck{Type: v1alpha1.SelfTestCheckBackupStorage}
+
+	backups, err := t.deps.BackupLister.Backups(tc.Namespace).List(labels.Everything())
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to list backups: %v", err)
+		return check
+	}
+	var latest *v1alpha1.Backup
+	for _, backup := range backups {
+		if backup.Spec.BR == nil || backup.Spec.BR.Cluster != tc.Name {
+			continue
+		}
+		if backup.Spec.BR.ClusterNamespace != "" && backup.Spec.BR.ClusterNamespace != tc.Namespace {
+			continue
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&backup.CreationTimestamp) {
+			latest = backup
+		}
+	}
+	if latest == nil {
+		return skipped(check, "no Backup of the cluster is found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+	if err := t.checkStorage(ctx, latest.Namespace, latest.Spec.StorageProvider); err != nil {
+		check.Message = fmt.Sprintf("storage of backup %s is not reachable: %v", latest.Name, err)
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("storage of backup %s is reachable", latest.Name)
+	return check
+}
+
+// checkBackupStorage checks whether the storage can be accessed with the credential of the backup
+func (t *tidbClusterSelfTester) checkBackupStorage(ctx context.Context, ns string, provider v1alpha1.StorageProvider) error {
+	cred := backuputil.GetStorageCredential(ns, provider, t.deps.SecretLister)
+	s, err := backuputil.NewStorageBackend(provider, cred)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	// only the error matters, the meta file may not exist if the backup is not completed
+	_, err = s.Exists(ctx, constants.MetaFile)
+	return err
+}
+
+func skipped(check v1alpha1.SelfTestCheck, message string) v1alpha1.SelfTestCheck {
+	check.Passed = true
+	check.Skipped = true
+	check.Message = message
+	return check
+}
+
+// verifyCertChain verifies that the certificate in the secret is signed by the CA in the same secret
+func verifyCertChain(secret *corev1.Secret, now time.Time) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(secret.Data[corev1.ServiceAccountRootCAKey]) {
+		return fmt.Errorf("no CA certificate found in %s", corev1.ServiceAccountRootCAKey)
+	}
+
+	var certs []*x509.Certificate
+	rest := secret.Data[corev1.TLSCertKey]
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %v", corev1.TLSCertKey, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return fmt.Errorf("no certificate found in %s", corev1.TLSCertKey)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+type fakeTidbClusterSelfTester struct{}
+
+// NewFakeTidbClusterSelfTester returns a fake TidbClusterSelfTester
+func NewFakeTidbClusterSelfTester() TidbClusterSelfTester {
+	return &fakeTidbClusterSelfTester{}
+}
+
+func (t *fakeTidbClusterSelfTester) Test(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
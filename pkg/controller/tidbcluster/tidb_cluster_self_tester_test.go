@@ -0,0 +1,208 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterSelfTester(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tester := NewTidbClusterSelfTester(deps).(*tidbClusterSelfTester)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   corev1.NamespaceDefault,
+			Annotations: map[string]string{label.AnnSelfTestKey: "1"},
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:         &v1alpha1.PDSpec{Replicas: 1},
+			TiDB:       &v1alpha1.TiDBSpec{Replicas: 1},
+			TLSCluster: &v1alpha1.TLSCluster{Enabled: true},
+		},
+		Status: v1alpha1.TidbClusterStatus{
+			PD:   v1alpha1.PDStatus{Members: map[string]v1alpha1.PDMember{"test-pd-0": {Name: "test-pd-0"}}},
+			TiDB: v1alpha1.TiDBStatus{Members: map[string]v1alpha1.TiDBMember{"test-tidb-0": {Name: "test-tidb-0"}}},
+		},
+	}
+
+	pdClient := controller.NewFakePDClient(deps.PDControl.(*pdapi.FakePDControl), tc)
+	pdClient.AddReaction(pdapi.GetHealthActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{{Name: "test-pd-0", Health: true}}}, nil
+	})
+	deps.TiDBControl.(*controller.FakeTiDBControl).SetHealth(map[string]bool{"test-tidb-0": true})
+
+	unresolvable := "test-tidb-0.test-tidb-peer.default.svc"
+	tester.lookupHost = func(_ context.Context, host string) ([]string, error) {
+		if host == unresolvable {
+			return nil, fmt.Errorf("no such host")
+		}
+		return []string{"10.0.0.1"}, nil
+	}
+	tester.checkStorage = func(_ context.Context, _ string, _ v1alpha1.StorageProvider) error {
+		return nil
+	}
+
+	now := time.Now()
+	caCert, caKey := newTestCert(g, nil, nil, now.Add(time.Hour))
+	secretIndexer := deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer()
+	for _, name := range []string{
+		util.ClusterClientTLSSecretName(tc.Name),
+		util.ClusterTLSSecretName(tc.Name, label.PDLabelVal),
+		util.ClusterTLSSecretName(tc.Name, label.TiDBLabelVal),
+	} {
+		cert, _ := newTestCert(g, caCert, caKey, now.Add(time.Hour))
+		secretIndexer.Add(newTestTLSSecret(name, caCert, cert))
+	}
+
+	deps.InformerFactory.Pingcap().V1alpha1().Backups().Informer().GetIndexer().Add(&v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.BackupSpec{
+			BR: &v1alpha1.BRConfig{Cluster: "test"},
+		},
+	})
+
+	// the self-test runs in the background and the sync is requeued until it's completed
+	runSelfTest := func() {
+		t.Helper()
+		err := tester.Test(tc)
+		g.Expect(controller.IsRequeueError(err)).To(BeTrue(), fmt.Sprintf("%v", err))
+		g.Expect(tc.Status.SelfTest.CompletionTime.IsZero()).To(BeTrue())
+		g.Eventually(func() error {
+			return tester.Test(tc)
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+	}
+
+	runSelfTest()
+	report := tc.Status.SelfTest
+	g.Expect(report).NotTo(BeNil())
+	g.Expect(report.Request).To(Equal("1"))
+	g.Expect(report.Passed).To(BeFalse())
+	g.Expect(report.CompletionTime.IsZero()).To(BeFalse())
+	g.Expect(report.Checks).To(HaveLen(5))
+	for _, check := range report.Checks {
+		switch check.Type {
+		case v1alpha1.SelfTestCheckPeerDNS:
+			g.Expect(check.Passed).To(BeFalse())
+			g.Expect(check.Message).To(ContainSubstring(unresolvable))
+		default:
+			g.Expect(check.Passed).To(BeTrue(), check.Message)
+			g.Expect(check.Skipped).To(BeFalse(), check.Message)
+		}
+	}
+
+	// the same request is not run again
+	tester.lookupHost = func(_ context.Context, _ string) ([]string, error) {
+		return []string{"10.0.0.1"}, nil
+	}
+	g.Expect(tester.Test(tc)).To(Succeed())
+	g.Expect(tc.Status.SelfTest.Passed).To(BeFalse())
+
+	// a new request runs all checks again
+	tc.Annotations[label.AnnSelfTestKey] = "2"
+	runSelfTest()
+	g.Expect(tc.Status.SelfTest.Request).To(Equal("2"))
+	g.Expect(tc.Status.SelfTest.Passed).To(BeTrue())
+
+	// expired certificate
+	expired, _ := newTestCert(g, caCert, caKey, now.Add(-time.Minute))
+	secret := newTestTLSSecret(util.ClusterTLSSecretName(tc.Name, label.PDLabelVal), caCert, expired)
+	g.Expect(verifyCertChain(secret, now)).NotTo(Succeed())
+	secretIndexer.Update(secret)
+	tc.Annotations[label.AnnSelfTestKey] = "3"
+	runSelfTest()
+	g.Expect(tc.Status.SelfTest.Passed).To(BeFalse())
+	g.Expect(tc.Status.SelfTest.Checks[3].Type).To(Equal(v1alpha1.SelfTestCheckTLSChain))
+	g.Expect(tc.Status.SelfTest.Checks[3].Message).To(ContainSubstring("test-pd-cluster-secret"))
+}
+
+func TestRunCheckWithTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	check := runCheckWithTimeout(v1alpha1.SelfTestCheckPeerDNS, time.Second, func() v1alpha1.SelfTestCheck {
+		return v1alpha1.SelfTestCheck{Type: v1alpha1.SelfTestCheckPeerDNS, Passed: true}
+	})
+	g.Expect(check.Passed).To(BeTrue())
+
+	block := make(chan struct{})
+	defer close(block)
+	start := time.Now()
+	check = runCheckWithTimeout(v1alpha1.SelfTestCheckPDHealth, 100*time.Millisecond, func() v1alpha1.SelfTestCheck {
+		<-block
+		return v1alpha1.SelfTestCheck{Type: v1alpha1.SelfTestCheckPDHealth, Passed: true}
+	})
+	g.Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+	g.Expect(check.Type).To(Equal(v1alpha1.SelfTestCheckPDHealth))
+	g.Expect(check.Passed).To(BeFalse())
+	g.Expect(check.Message).To(ContainSubstring("not completed"))
+}
+
+// newTestCert returns a CA certificate if parent is nil, or a certificate signed by the parent otherwise
+func newTestCert(g *GomegaWithT, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, notAfter time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.Subject.CommonName = "test-ca"
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	g.Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).NotTo(HaveOccurred())
+	return cert, key
+}
+
+func newTestTLSSecret(name string, ca, cert *x509.Certificate) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: corev1.NamespaceDefault},
+		Data: map[string][]byte{
+			corev1.ServiceAccountRootCAKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}),
+			corev1.TLSCertKey:              pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+		},
+	}
+}
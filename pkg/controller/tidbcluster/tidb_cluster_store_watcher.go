@@ -0,0 +1,146 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// tidbClusterStoreWatcher polls the states of the TiKV and TiFlash stores from PD in a goroutine
+// per tidb cluster, and enqueues the tidb cluster as soon as a store becomes Down, so that the
+// failover doesn't wait for the next resync to notice the failure.
+type tidbClusterStoreWatcher struct {
+	deps     *controller.Dependencies
+	interval time.Duration
+	enqueue  func(key string)
+
+	lock    sync.Mutex
+	stopped bool
+	stopCh  chan struct{}
+	// watching are the keys of the tidb clusters being watched
+	watching map[string]bool
+}
+
+func newTidbClusterStoreWatcher(deps *controller.Dependencies, interval time.Duration, enqueue func(key string)) *tidbClusterStoreWatcher {
+	return &tidbClusterStoreWatcher{
+		deps:     deps,
+		interval: interval,
+		enqueue:  enqueue,
+		stopCh:   make(chan struct{}),
+		watching: map[string]bool{},
+	}
+}
+
+// Watch starts watching the stores of the tidb cluster if it is not watched yet. The watching
+// stops by itself when the tidb cluster is deleted or has no TiKV and TiFlash any more.
+func (w *tidbClusterStoreWatcher) Watch(key string) {
+	if w.interval <= 0 {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.stopped || w.watching[key] {
+		return
+	}
+	w.watching[key] = true
+	go w.watch(key)
+}
+
+// Stop stops watching all the tidb clusters
+func (w *tidbClusterStoreWatcher) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !w.stopped {
+		w.stopped = true
+		close(w.stopCh)
+	}
+}
+
+func (w *tidbClusterStoreWatcher) watch(key string) {
+	defer func() {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		delete(w.watching, key)
+	}()
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	klog.V(4).Infof("TidbCluster: %s, start watching the store states", key)
+	states := map[uint64]string{}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		tc, err := w.deps.TiDBClusterLister.TidbClusters(ns).Get(name)
+		if errors.IsNotFound(err) || err == nil && !hasStores(tc) {
+			klog.V(4).Infof("TidbCluster: %s, stop watching the store states", key)
+			return
+		}
+		if err != nil {
+			continue
+		}
+		if w.poll(tc, states) {
+			w.enqueue(key)
+		}
+	}
+}
+
+// poll updates the states of the stores from PD, and returns whether any store becomes Down
+// since the last poll
+func (w *tidbClusterStoreWatcher) poll(tc *v1alpha1.TidbCluster, states map[uint64]string) bool {
+	storesInfo, err := controller.GetPDClient(w.deps.PDControl, tc).GetStores()
+	if err != nil {
+		klog.V(4).Infof("TidbCluster: [%s/%s], failed to get the stores from pd: %v", tc.Namespace, tc.Name, err)
+		return false
+	}
+
+	down := false
+	current := map[uint64]string{}
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil {
+			continue
+		}
+		id, state := store.Store.GetId(), store.Store.StateName
+		current[id] = state
+		if last, ok := states[id]; ok && last != state && state == v1alpha1.TiKVStateDown {
+			klog.Infof("TidbCluster: [%s/%s], store %d becomes %s from %s", tc.Namespace, tc.Name, id, state, last)
+			down = true
+		}
+	}
+	for id := range states {
+		delete(states, id)
+	}
+	for id, state := range current {
+		states[id] = state
+	}
+	return down
+}
+
+func hasStores(tc *v1alpha1.TidbCluster) bool {
+	return tc.Spec.TiKV != nil || tc.Spec.TiFlash != nil
+}
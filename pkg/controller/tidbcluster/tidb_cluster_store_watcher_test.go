@@ -0,0 +1,90 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterStoreWatcher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiKV: &v1alpha1.TiKVSpec{},
+		},
+	}
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(tc)).To(Succeed())
+
+	var lock sync.Mutex
+	state := v1alpha1.TiKVStateUp
+	pdClient := controller.NewFakePDClient(deps.PDControl.(*pdapi.FakePDControl), tc)
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{
+			{Store: &pdapi.MetaStore{Store: &metapb.Store{Id: 1}, StateName: v1alpha1.TiKVStateUp}},
+			{Store: &pdapi.MetaStore{Store: &metapb.Store{Id: 2}, StateName: state}},
+		}}, nil
+	})
+	setState := func(s string) {
+		lock.Lock()
+		defer lock.Unlock()
+		state = s
+	}
+
+	// poll
+	w := newTidbClusterStoreWatcher(deps, time.Millisecond*10, func(string) {})
+	states := map[uint64]string{}
+	g.Expect(w.poll(tc, states)).To(BeFalse())
+	g.Expect(states).To(Equal(map[uint64]string{1: v1alpha1.TiKVStateUp, 2: v1alpha1.TiKVStateUp}))
+	setState(v1alpha1.TiKVStateDown)
+	g.Expect(w.poll(tc, states)).To(BeTrue())
+	g.Expect(w.poll(tc, states)).To(BeFalse())
+
+	// the store down at the first poll is not enqueued
+	g.Expect(w.poll(tc, map[uint64]string{})).To(BeFalse())
+
+	// watch
+	setState(v1alpha1.TiKVStateUp)
+	enqueued := make(chan string, 10)
+	w = newTidbClusterStoreWatcher(deps, time.Millisecond*10, func(key string) { enqueued <- key })
+	defer w.Stop()
+	w.Watch("default/test")
+	w.Watch("default/test")
+	g.Consistently(enqueued, time.Millisecond*100).ShouldNot(Receive())
+	setState(v1alpha1.TiKVStateDown)
+	g.Eventually(enqueued, time.Second).Should(Receive(Equal("default/test")))
+	g.Consistently(enqueued, time.Millisecond*100).ShouldNot(Receive())
+
+	// stop watching the deleted cluster
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Delete(tc)).To(Succeed())
+	g.Eventually(func() int {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		return len(w.watching)
+	}, time.Second).Should(Equal(0))
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/manager/member"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// storeStuckOfflineThreshold is the duration after which an Offline store is considered stuck
+	storeStuckOfflineThreshold = time.Hour
+	// evictLeaderBlockedThreshold is the duration after which the leader eviction before upgrade is considered blocked
+	evictLeaderBlockedThreshold = 30 * time.Minute
+	// pvcPendingThreshold is the duration after which a Pending PVC is considered stuck
+	pvcPendingThreshold = 10 * time.Minute
+)
+
+// TidbClusterSuggestedActionUpdater interface that translates the stuck states of
+// a tidb cluster into suggested actions in the tidb cluster status.
+type TidbClusterSuggestedActionUpdater interface {
+	Update(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterSuggestedActionUpdater struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterSuggestedActionUpdater returns a TidbClusterSuggestedActionUpdater
+func NewTidbClusterSuggestedActionUpdater(deps *controller.Dependencies) TidbClusterSuggestedActionUpdater {
+	return &tidbClusterSuggestedActionUpdater{
+		deps: deps,
+	}
+}
+
+var _ TidbClusterSuggestedActionUpdater = &tidbClusterSuggestedActionUpdater{}
+
+func (u *tidbClusterSuggestedActionUpdater) Update(tc *v1alpha1.TidbCluster) error {
+	now := time.Now()
+
+	actions := storeStuckOfflineActions(tc, now)
+
+	evictActions, err := u.evictLeaderBlockedActions(tc, now)
+	if err != nil {
+		return err
+	}
+	actions = append(actions, evictActions...)
+
+	pvcActions, err := u.pvcPendingActions(tc, now)
+	if err != nil {
+		return err
+	}
+	actions = append(actions, pvcActions...)
+
+	// the actions for skewed zones are maintained by TidbClusterZoneDistributionUpdater
+	for _, action := range tc.Status.SuggestedActions {
+		if action.Type == v1alpha1.SuggestedActionZoneSkewed {
+			actions = append(actions, action)
+		}
+	}
+
+	tc.Status.SuggestedActions = actions
+	return nil
+}
+
+func storeStuckOfflineActions(tc *v1alpha1.TidbCluster, now time.Time) []v1alpha1.SuggestedAction {
+	var actions []v1alpha1.SuggestedAction
+	check := func(component v1alpha1.MemberType, stores map[string]v1alpha1.TiKVStore) {
+		ids := make([]string, 0, len(stores))
+		for id := range stores {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			store := stores[id]
+			if store.State != v1alpha1.TiKVStateOffline {
+				continue
+			}
+			if store.LastTransitionTime.IsZero() || now.Sub(store.LastTransitionTime.Time) < storeStuckOfflineThreshold {
+				continue
+			}
+			action := v1alpha1.SuggestedAction{
+				Type:      v1alpha1.SuggestedActionStoreStuckOffline,
+				Component: component,
+				Target:    store.PodName,
+				Message: fmt.Sprintf("store %s of pod %s has been Offline since %s, make sure there are enough Up stores to place its region replicas, or cancel the deletion if the store is expected to be kept",
+					id, store.PodName, store.LastTransitionTime.UTC().Format(time.RFC3339)),
+				Since: store.LastTransitionTime,
+			}
+			if tc.Spec.PD != nil {
+				action.Command = fmt.Sprintf("kubectl exec -n %s %s -- /pd-ctl store cancel-delete %s",
+					tc.Namespace, member.PdPodName(tc.Name, 0), id)
+			}
+			actions = append(actions, action)
+		}
+	}
+	check(v1alpha1.TiKVMemberType, tc.Status.TiKV.Stores)
+	check(v1alpha1.TiFlashMemberType, tc.Status.TiFlash.Stores)
+	return actions
+}
+
+func (u *tidbClusterSuggestedActionUpdater) evictLeaderBlockedActions(tc *v1alpha1.TidbCluster, now time.Time) ([]v1alpha1.SuggestedAction, error) {
+	if tc.Spec.TiKV == nil || tc.Status.TiKV.Phase != v1alpha1.UpgradePhase {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(tc.Status.TiKV.Stores))
+	for id := range tc.Status.TiKV.Stores {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var actions []v1alpha1.SuggestedAction
+	for _, id := range ids {
+		store := tc.Status.TiKV.Stores[id]
+		pod, err := u.deps.PodLister.Pods(tc.Namespace).Get(store.PodName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s/%s: %v", tc.Namespace, store.PodName, err)
+		}
+		beginTime, evicting := member.EvictLeaderBeginTime(pod)
+		if !evicting || now.Sub(beginTime) < evictLeaderBlockedThreshold {
+			continue
+		}
+		actions = append(actions, v1alpha1.SuggestedAction{
+			Type:      v1alpha1.SuggestedActionEvictLeaderBlocked,
+			Component: v1alpha1.TiKVMemberType,
+			Target:    pod.Name,
+			Message: fmt.Sprintf("upgrade of pod %s is waiting for %d leader(s) of store %s to be evicted since %s, shorten spec.tikv.evictLeaderTimeout to continue the upgrade",
+				pod.Name, store.LeaderCount, id, beginTime.UTC().Format(time.RFC3339)),
+			Command: fmt.Sprintf(`kubectl patch tc %s -n %s --type merge -p '{"spec":{"tikv":{"evictLeaderTimeout":"1m"}}}'`,
+				tc.Name, tc.Namespace),
+			Since: metav1.NewTime(beginTime),
+		})
+	}
+	return actions, nil
+}
+
+func (u *tidbClusterSuggestedActionUpdater) pvcPendingActions(tc *v1alpha1.TidbCluster, now time.Time) ([]v1alpha1.SuggestedAction, error) {
+	selector, err := label.New().Instance(tc.GetInstanceName()).Selector()
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s/%s assemble label selector failed, err: %v", tc.Namespace, tc.Name, err)
+	}
+	pvcs, err := u.deps.PVCLister.PersistentVolumeClaims(tc.Namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s/%s list pvc failed, selector: %s, err: %v", tc.Namespace, tc.Name, selector, err)
+	}
+	sort.Slice(pvcs, func(i, j int) bool {
+		return pvcs[i].Name < pvcs[j].Name
+	})
+
+	var actions []v1alpha1.SuggestedAction
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase != corev1.ClaimPending || pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if now.Sub(pvc.CreationTimestamp.Time) < pvcPendingThreshold {
+			continue
+		}
+		actions = append(actions, v1alpha1.SuggestedAction{
+			Type:      v1alpha1.SuggestedActionPVCPending,
+			Component: v1alpha1.MemberType(pvc.Labels[label.ComponentLabelKey]),
+			Target:    pvc.Name,
+			Message: fmt.Sprintf("pvc %s has been Pending since %s, check whether its storage class exists and can provision volumes for the pod",
+				pvc.Name, pvc.CreationTimestamp.UTC().Format(time.RFC3339)),
+			Command: fmt.Sprintf("kubectl describe pvc %s -n %s", pvc.Name, tc.Namespace),
+			Since:   pvc.CreationTimestamp,
+		})
+	}
+	return actions, nil
+}
+
+type fakeTidbClusterSuggestedActionUpdater struct{}
+
+// NewFakeTidbClusterSuggestedActionUpdater returns a fake TidbClusterSuggestedActionUpdater
+func NewFakeTidbClusterSuggestedActionUpdater() TidbClusterSuggestedActionUpdater {
+	return &fakeTidbClusterSuggestedActionUpdater{}
+}
+
+func (u *fakeTidbClusterSuggestedActionUpdater) Update(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
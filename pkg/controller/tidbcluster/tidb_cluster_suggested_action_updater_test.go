@@ -0,0 +1,112 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterSuggestedActionUpdater(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	updater := NewTidbClusterSuggestedActionUpdater(deps)
+
+	longAgo := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+		},
+		Status: v1alpha1.TidbClusterStatus{
+			TiKV: v1alpha1.TiKVStatus{
+				Phase: v1alpha1.UpgradePhase,
+				Stores: map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateOffline, LastTransitionTime: longAgo},
+					"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, LastTransitionTime: longAgo, LeaderCount: 10},
+					"3": {ID: "3", PodName: "test-tikv-2", State: v1alpha1.TiKVStateOffline, LastTransitionTime: metav1.Now()},
+				},
+			},
+		},
+	}
+
+	podIndexer := deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	podIndexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tikv-1",
+			Namespace: corev1.NamespaceDefault,
+			Annotations: map[string]string{
+				"evictLeaderBeginTime": longAgo.Format(time.RFC3339),
+			},
+		},
+	})
+
+	pvcIndexer := deps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	pvcIndexer.Add(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "tikv-test-tikv-3",
+			Namespace:         corev1.NamespaceDefault,
+			CreationTimestamp: longAgo,
+			Labels:            label.New().Instance("test").TiKV().Labels(),
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	})
+	pvcIndexer.Add(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "tikv-test-tikv-0",
+			Namespace:         corev1.NamespaceDefault,
+			CreationTimestamp: longAgo,
+			Labels:            label.New().Instance("test").TiKV().Labels(),
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	})
+
+	g.Expect(updater.Update(tc)).To(Succeed())
+
+	actions := tc.Status.SuggestedActions
+	g.Expect(actions).To(HaveLen(3))
+
+	g.Expect(actions[0].Type).To(Equal(v1alpha1.SuggestedActionStoreStuckOffline))
+	g.Expect(actions[0].Target).To(Equal("test-tikv-0"))
+	g.Expect(actions[0].Command).To(Equal("kubectl exec -n default test-pd-0 -- /pd-ctl store cancel-delete 1"))
+
+	g.Expect(actions[1].Type).To(Equal(v1alpha1.SuggestedActionEvictLeaderBlocked))
+	g.Expect(actions[1].Target).To(Equal("test-tikv-1"))
+	g.Expect(actions[1].Command).To(ContainSubstring("kubectl patch tc test -n default"))
+
+	g.Expect(actions[2].Type).To(Equal(v1alpha1.SuggestedActionPVCPending))
+	g.Expect(actions[2].Component).To(Equal(v1alpha1.TiKVMemberType))
+	g.Expect(actions[2].Target).To(Equal("tikv-test-tikv-3"))
+
+	// stuck states are resolved
+	tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+	tc.Status.TiKV.Stores = nil
+	pvcIndexer.Delete(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "tikv-test-tikv-3", Namespace: corev1.NamespaceDefault},
+	})
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(tc.Status.SuggestedActions).To(BeEmpty())
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// maxSyncErrors is the max number of the errors recorded for a sync
+	maxSyncErrors = 5
+	// maxSyncErrorLength is the max length of an error recorded for a sync
+	maxSyncErrorLength = 256
+)
+
+// recordSyncHistory records the outcome of the sync in the sync history of the cluster. The outcome is
+// skipped if it's the same as the last one without new actions, so that the status isn't updated by
+// every sync of a steady cluster.
+func recordSyncHistory(tc *v1alpha1.TidbCluster, oldStatus *v1alpha1.TidbClusterStatus, errs []error, now time.Time) {
+	record := v1alpha1.SyncRecord{
+		Time:   metav1.NewTime(now),
+		Result: v1alpha1.SyncResultSuccess,
+	}
+	for _, component := range tc.AllComponentStatus() {
+		if record.Phases == nil {
+			record.Phases = map[v1alpha1.MemberType]v1alpha1.MemberPhase{}
+		}
+		record.Phases[component.MemberType()] = component.GetPhase()
+	}
+
+	requeueOnly := true
+	for _, err := range errs {
+		if !controller.IsRequeueError(err) {
+			requeueOnly = false
+		}
+		if len(record.Errors) < maxSyncErrors {
+			msg := err.Error()
+			if len(msg) > maxSyncErrorLength {
+				msg = msg[:maxSyncErrorLength] + "..."
+			}
+			record.Errors = append(record.Errors, msg)
+		}
+	}
+	if len(errs) > 0 {
+		record.Result = v1alpha1.SyncResultError
+		if requeueOnly {
+			record.Result = v1alpha1.SyncResultRequeue
+		}
+	}
+
+	oldTC := &v1alpha1.TidbCluster{Spec: tc.Spec, Status: *oldStatus}
+	for _, component := range oldTC.AllComponentStatus() {
+		phase, ok := record.Phases[component.MemberType()]
+		if ok && phase != component.GetPhase() {
+			record.Actions = append(record.Actions, fmt.Sprintf("%s phase %s -> %s", component.MemberType(), component.GetPhase(), phase))
+		}
+	}
+	sort.Strings(record.Actions)
+
+	history := tc.Status.SyncHistory
+	if n := len(history); n > 0 && len(record.Actions) == 0 {
+		last := history[n-1]
+		if last.Result == record.Result &&
+			apiequality.Semantic.DeepEqual(last.Phases, record.Phases) &&
+			apiequality.Semantic.DeepEqual(last.Errors, record.Errors) {
+			return
+		}
+	}
+	history = append(history, record)
+	if len(history) > v1alpha1.MaxSyncHistory {
+		history = history[len(history)-v1alpha1.MaxSyncHistory:]
+	}
+	tc.Status.SyncHistory = history
+}
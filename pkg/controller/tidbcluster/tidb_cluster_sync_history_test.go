@@ -0,0 +1,72 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+)
+
+func TestRecordSyncHistory(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Status.PD.Phase = v1alpha1.NormalPhase
+	tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+	tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+	now := time.Now()
+
+	// the first sync is recorded
+	oldStatus := tc.Status.DeepCopy()
+	recordSyncHistory(tc, oldStatus, nil, now)
+	g.Expect(tc.Status.SyncHistory).To(HaveLen(1))
+	g.Expect(tc.Status.SyncHistory[0].Result).To(Equal(v1alpha1.SyncResultSuccess))
+	g.Expect(tc.Status.SyncHistory[0].Phases).To(HaveKeyWithValue(v1alpha1.TiKVMemberType, v1alpha1.NormalPhase))
+	g.Expect(tc.Status.SyncHistory[0].Actions).To(BeEmpty())
+
+	// the sync with the same outcome isn't recorded again
+	oldStatus = tc.Status.DeepCopy()
+	recordSyncHistory(tc, oldStatus, nil, now.Add(time.Minute))
+	g.Expect(tc.Status.SyncHistory).To(HaveLen(1))
+
+	// the phase transitions are recorded as the actions
+	oldStatus = tc.Status.DeepCopy()
+	tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	recordSyncHistory(tc, oldStatus, []error{controller.RequeueErrorf("waiting for tikv upgrade")}, now.Add(2*time.Minute))
+	g.Expect(tc.Status.SyncHistory).To(HaveLen(2))
+	g.Expect(tc.Status.SyncHistory[1].Result).To(Equal(v1alpha1.SyncResultRequeue))
+	g.Expect(tc.Status.SyncHistory[1].Actions).To(Equal([]string{"tikv phase Normal -> Upgrade"}))
+	g.Expect(tc.Status.SyncHistory[1].Errors).To(Equal([]string{"waiting for tikv upgrade"}))
+
+	// the errors are truncated
+	oldStatus = tc.Status.DeepCopy()
+	recordSyncHistory(tc, oldStatus, []error{fmt.Errorf("%s", strings.Repeat("x", 1000))}, now.Add(3*time.Minute))
+	g.Expect(tc.Status.SyncHistory).To(HaveLen(3))
+	g.Expect(tc.Status.SyncHistory[2].Result).To(Equal(v1alpha1.SyncResultError))
+	g.Expect(tc.Status.SyncHistory[2].Errors[0]).To(HaveLen(maxSyncErrorLength + 3))
+
+	// only the recent records are kept
+	for i := 0; i < v1alpha1.MaxSyncHistory; i++ {
+		oldStatus = tc.Status.DeepCopy()
+		recordSyncHistory(tc, oldStatus, []error{fmt.Errorf("error %d", i)}, now.Add(time.Duration(4+i)*time.Minute))
+	}
+	g.Expect(tc.Status.SyncHistory).To(HaveLen(v1alpha1.MaxSyncHistory))
+	g.Expect(tc.Status.SyncHistory[v1alpha1.MaxSyncHistory-1].Errors).To(Equal([]string{fmt.Sprintf("error %d", v1alpha1.MaxSyncHistory-1)}))
+}
@@ -0,0 +1,310 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// tiflashReplicaTimeout is the timeout to sync the TiFlash replicas of a database or table
+	tiflashReplicaTimeout = 10 * time.Second
+	// defaultTiFlashReplicaUser is the default TiDB user to set the TiFlash replicas
+	defaultTiFlashReplicaUser = "root"
+)
+
+// TiFlashReplicaSyncer sets the TiFlash replicas of the databases and tables declared in
+// spec.tiflash.tableReplicas by SQL, and reports the sync progress in the tidb cluster status.
+// The replicas changed out of the spec are set back in the next sync, the replicas of a database
+// are set once for a number, after that only the tables with different replicas are set back.
+type TiFlashReplicaSyncer interface {
+	Sync(*v1alpha1.TidbCluster) error
+}
+
+// tiflashReplica is a row of information_schema.tiflash_replica
+type tiflashReplica struct {
+	Count     int32
+	Available bool
+	Progress  float64
+}
+
+// tiflashReplicaClient runs the SQL statements about the TiFlash replicas
+type tiflashReplicaClient interface {
+	// ListTables returns the base tables of the database
+	ListTables(ctx context.Context, database string) ([]string, error)
+	// GetReplicas returns the TiFlash replicas of the tables of the database by the table names
+	GetReplicas(ctx context.Context, database string) (map[string]tiflashReplica, error)
+	// SetReplica sets the TiFlash replicas of the table, or all the tables of the database if the table is empty
+	SetReplica(ctx context.Context, database, table string, count int32) error
+	Close() error
+}
+
+type tiflashReplicaSyncer struct {
+	deps *controller.Dependencies
+	// newClient can be replaced in unit tests
+	newClient func(tc *v1alpha1.TidbCluster) (tiflashReplicaClient, error)
+}
+
+// NewTiFlashReplicaSyncer returns a TiFlashReplicaSyncer
+func NewTiFlashReplicaSyncer(deps *controller.Dependencies) TiFlashReplicaSyncer {
+	s := &tiflashReplicaSyncer{deps: deps}
+	s.newClient = s.newSQLClient
+	return s
+}
+
+var _ TiFlashReplicaSyncer = &tiflashReplicaSyncer{}
+
+func (s *tiflashReplicaSyncer) Sync(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiFlash == nil || tc.Spec.TiFlash.TableReplicas == nil || len(tc.Spec.TiFlash.TableReplicas.Tables) == 0 {
+		tc.Status.TiFlash.TableReplicas = nil
+		return nil
+	}
+	if !tc.TiDBAllMembersReady() || !tc.TiFlashAllStoresReady() {
+		return nil
+	}
+
+	client, err := s.newClient(tc)
+	if err != nil {
+		return fmt.Errorf("tidbcluster: [%s/%s] failed to connect to tidb to sync tiflash replicas: %v", tc.Namespace, tc.Name, err)
+	}
+	defer client.Close()
+
+	tables := tc.Spec.TiFlash.TableReplicas.Tables
+	statuses := make([]v1alpha1.TiFlashTableReplicaStatus, 0, len(tables))
+	for _, t := range tables {
+		status := v1alpha1.TiFlashTableReplicaStatus{
+			Database: t.Database,
+			Table:    t.Table,
+			Count:    t.Count,
+		}
+		last := lastTiFlashReplicaStatus(tc, t)
+		if err := s.syncReplica(tc, client, t, last, &status); err != nil {
+			klog.Warningf("tidbcluster: [%s/%s] failed to sync tiflash replicas of %s: %v", tc.Namespace, tc.Name, tiflashReplicaTarget(t), err)
+			status.Message = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	tc.Status.TiFlash.TableReplicas = statuses
+	return nil
+}
+
+// syncReplica sets the TiFlash replicas of the database or table if any table has a different
+// number of replicas, and fills the sync progress in the status
+func (s *tiflashReplicaSyncer) syncReplica(tc *v1alpha1.TidbCluster, client tiflashReplicaClient,
+	t v1alpha1.TiFlashTableReplica, last, status *v1alpha1.TiFlashTableReplicaStatus) error {
+	ctx, cancel := context.WithTimeout(context.Background(), tiflashReplicaTimeout)
+	defer cancel()
+
+	tables := []string{t.Table}
+	if t.Table == "" {
+		var err error
+		if tables, err = client.ListTables(ctx, t.Database); err != nil {
+			return err
+		}
+	}
+	replicas, err := client.GetReplicas(ctx, t.Database)
+	if err != nil {
+		return err
+	}
+
+	// the replicas of the tables are set one by one in the background after the database is set,
+	// and some tables are skipped by TiDB, so the database is not set again for the same number,
+	// only the tables whose replicas are changed out of the spec are set back
+	databaseSet := t.Table == "" && last != nil && last.Count == t.Count && last.Message == ""
+	set := false
+	for _, table := range tables {
+		r, ok := replicas[table]
+		if r.Count == t.Count || (databaseSet && !ok) {
+			continue
+		}
+		target := t
+		if databaseSet {
+			target.Table = table
+		}
+		if err := client.SetReplica(ctx, target.Database, target.Table, target.Count); err != nil {
+			return err
+		}
+		klog.Infof("tidbcluster: [%s/%s] set %d tiflash replicas of %s", tc.Namespace, tc.Name, target.Count, tiflashReplicaTarget(target))
+		s.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "TiFlashReplicaSet", "set %d tiflash replicas of %s", target.Count, tiflashReplicaTarget(target))
+		set = true
+		if !databaseSet {
+			break
+		}
+	}
+	if set {
+		if replicas, err = client.GetReplicas(ctx, t.Database); err != nil {
+			return err
+		}
+	}
+
+	status.Available, status.Progress = tiflashReplicaProgress(tables, replicas, t.Count)
+	return nil
+}
+
+// tiflashReplicaProgress returns whether the TiFlash replicas of all the tables are available,
+// and the average sync progress of the tables
+func tiflashReplicaProgress(tables []string, replicas map[string]tiflashReplica, count int32) (bool, float64) {
+	if count == 0 || len(tables) == 0 {
+		return false, 0
+	}
+	available := true
+	var progress float64
+	for _, table := range tables {
+		r, ok := replicas[table]
+		if !ok || r.Count != count {
+			available = false
+			continue
+		}
+		if r.Available {
+			progress += 1
+			continue
+		}
+		available = false
+		progress += r.Progress
+	}
+	return available, progress / float64(len(tables))
+}
+
+// lastTiFlashReplicaStatus returns the status of the database or table in the last sync
+func lastTiFlashReplicaStatus(tc *v1alpha1.TidbCluster, t v1alpha1.TiFlashTableReplica) *v1alpha1.TiFlashTableReplicaStatus {
+	for i := range tc.Status.TiFlash.TableReplicas {
+		status := &tc.Status.TiFlash.TableReplicas[i]
+		if status.Database == t.Database && status.Table == t.Table {
+			return status
+		}
+	}
+	return nil
+}
+
+func tiflashReplicaTarget(t v1alpha1.TiFlashTableReplica) string {
+	if t.Table == "" {
+		return fmt.Sprintf("database %s", t.Database)
+	}
+	return fmt.Sprintf("table %s.%s", t.Database, t.Table)
+}
+
+// newSQLClient connects to the tidb service of the tidb cluster with the user in spec.tiflash.tableReplicas
+func (s *tiflashReplicaSyncer) newSQLClient(tc *v1alpha1.TidbCluster) (tiflashReplicaClient, error) {
+	spec := tc.Spec.TiFlash.TableReplicas
+	cfg := mysql.NewConfig()
+	cfg.User = spec.User
+	if cfg.User == "" {
+		cfg.User = defaultTiFlashReplicaUser
+	}
+	if spec.SecretName != "" {
+		secret, err := s.deps.SecretLister.Secrets(tc.Namespace).Get(spec.SecretName)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Passwd = string(secret.Data[constants.TidbPasswordKey])
+	}
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s.%s.svc:%d", controller.TiDBMemberName(tc.Name), tc.Namespace, tc.Spec.TiDB.GetServicePort())
+	cfg.Timeout = tiflashReplicaTimeout
+	if tc.Spec.TiDB.IsTLSClientEnabled() && !tc.SkipTLSWhenConnectTiDB() {
+		tlsConfig, err := pdapi.GetTLSConfig(s.deps.SecretLister, pdapi.Namespace(tc.Namespace), util.TiDBClientTLSSecretName(tc.Name, spec.TLSClientSecretName))
+		if err != nil {
+			return nil, err
+		}
+		cfg.TLS = tlsConfig
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTiFlashReplicaClient{db: sql.OpenDB(connector)}, nil
+}
+
+type sqlTiFlashReplicaClient struct {
+	db *sql.DB
+}
+
+func (c *sqlTiFlashReplicaClient) ListTables(ctx context.Context, database string) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, "SELECT TABLE_NAME FROM information_schema.tables WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'", database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (c *sqlTiFlashReplicaClient) GetReplicas(ctx context.Context, database string) (map[string]tiflashReplica, error) {
+	rows, err := c.db.QueryContext(ctx, "SELECT TABLE_NAME, REPLICA_COUNT, AVAILABLE, PROGRESS FROM information_schema.tiflash_replica WHERE TABLE_SCHEMA = ?", database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	replicas := map[string]tiflashReplica{}
+	for rows.Next() {
+		var table string
+		var r tiflashReplica
+		if err := rows.Scan(&table, &r.Count, &r.Available, &r.Progress); err != nil {
+			return nil, err
+		}
+		replicas[table] = r
+	}
+	return replicas, rows.Err()
+}
+
+func (c *sqlTiFlashReplicaClient) SetReplica(ctx context.Context, database, table string, count int32) error {
+	var stmt string
+	if table == "" {
+		stmt = fmt.Sprintf("ALTER DATABASE %s SET TIFLASH REPLICA %d", quoteIdentifier(database), count)
+	} else {
+		stmt = fmt.Sprintf("ALTER TABLE %s.%s SET TIFLASH REPLICA %d", quoteIdentifier(database), quoteIdentifier(table), count)
+	}
+	_, err := c.db.ExecContext(ctx, stmt)
+	return err
+}
+
+func (c *sqlTiFlashReplicaClient) Close() error {
+	return c.db.Close()
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+type fakeTiFlashReplicaSyncer struct{}
+
+// NewFakeTiFlashReplicaSyncer returns a fake TiFlashReplicaSyncer
+func NewFakeTiFlashReplicaSyncer() TiFlashReplicaSyncer {
+	return &fakeTiFlashReplicaSyncer{}
+}
+
+func (s *fakeTiFlashReplicaSyncer) Sync(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
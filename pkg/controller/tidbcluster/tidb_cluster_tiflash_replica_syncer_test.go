@@ -0,0 +1,153 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeTiFlashReplicaClient struct {
+	tables   map[string][]string
+	replicas map[string]map[string]tiflashReplica
+	set      []string
+}
+
+func (c *fakeTiFlashReplicaClient) ListTables(_ context.Context, database string) ([]string, error) {
+	return c.tables[database], nil
+}
+
+func (c *fakeTiFlashReplicaClient) GetReplicas(_ context.Context, database string) (map[string]tiflashReplica, error) {
+	replicas := map[string]tiflashReplica{}
+	for table, r := range c.replicas[database] {
+		replicas[table] = r
+	}
+	return replicas, nil
+}
+
+func (c *fakeTiFlashReplicaClient) SetReplica(_ context.Context, database, table string, count int32) error {
+	tables := []string{table}
+	if table == "" {
+		tables = c.tables[database]
+	}
+	for _, t := range tables {
+		found := false
+		for _, existing := range c.tables[database] {
+			found = found || existing == t
+		}
+		if !found {
+			return fmt.Errorf("table %s.%s doesn't exist", database, t)
+		}
+		if c.replicas[database] == nil {
+			c.replicas[database] = map[string]tiflashReplica{}
+		}
+		c.replicas[database][t] = tiflashReplica{Count: count}
+	}
+	c.set = append(c.set, fmt.Sprintf("%s.%s=%d", database, table, count))
+	return nil
+}
+
+func (c *fakeTiFlashReplicaClient) Close() error {
+	return nil
+}
+
+func TestTiFlashReplicaSyncer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	client := &fakeTiFlashReplicaClient{
+		tables: map[string][]string{
+			"db1": {"t1", "t2"},
+			"db2": {"t1"},
+		},
+		replicas: map[string]map[string]tiflashReplica{
+			"db1": {"t1": {Count: 1, Available: true, Progress: 1}},
+		},
+	}
+	syncer := NewTiFlashReplicaSyncer(controller.NewFakeDependencies()).(*tiflashReplicaSyncer)
+	syncer.newClient = func(_ *v1alpha1.TidbCluster) (tiflashReplicaClient, error) {
+		return client, nil
+	}
+
+	tc := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{Replicas: 1}
+	tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{
+		Replicas: 1,
+		TableReplicas: &v1alpha1.TiFlashTableReplicas{
+			Tables: []v1alpha1.TiFlashTableReplica{
+				{Database: "db1", Count: 1},
+				{Database: "db2", Table: "t1", Count: 2},
+				{Database: "db2", Table: "t2", Count: 1},
+			},
+		},
+	}
+
+	// nothing is done before the cluster is ready
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(client.set).To(BeEmpty())
+	g.Expect(tc.Status.TiFlash.TableReplicas).To(BeNil())
+
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{"test-tidb-0": {Health: true}}
+	tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{"1": {State: v1alpha1.TiKVStateUp}}
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(client.set).To(Equal([]string{"db1.=1", "db2.t1=2"}))
+	g.Expect(tc.Status.TiFlash.TableReplicas).To(HaveLen(3))
+	g.Expect(tc.Status.TiFlash.TableReplicas[0].Available).To(BeFalse())
+	g.Expect(tc.Status.TiFlash.TableReplicas[2].Message).To(ContainSubstring("doesn't exist"))
+
+	// the progress is reported
+	client.set = nil
+	client.replicas["db1"]["t1"] = tiflashReplica{Count: 1, Available: true, Progress: 1}
+	client.replicas["db1"]["t2"] = tiflashReplica{Count: 1, Progress: 0.5}
+	client.replicas["db2"]["t1"] = tiflashReplica{Count: 2, Available: true, Progress: 1}
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(client.set).To(BeEmpty())
+	g.Expect(tc.Status.TiFlash.TableReplicas[0]).To(Equal(v1alpha1.TiFlashTableReplicaStatus{
+		Database: "db1", Count: 1, Available: false, Progress: 0.75,
+	}))
+	g.Expect(tc.Status.TiFlash.TableReplicas[1]).To(Equal(v1alpha1.TiFlashTableReplicaStatus{
+		Database: "db2", Table: "t1", Count: 2, Available: true, Progress: 1,
+	}))
+
+	// the replicas changed out of the spec are set back
+	client.replicas["db2"]["t1"] = tiflashReplica{Count: 1, Available: true, Progress: 1}
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(client.set).To(Equal([]string{"db2.t1=2"}))
+
+	// the database is not set again for the tables without replicas, e.g. skipped by TiDB,
+	// but the tables with different replicas are set back
+	client.set = nil
+	client.tables["db1"] = append(client.tables["db1"], "t3")
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(client.set).To(BeEmpty())
+	client.replicas["db1"]["t2"] = tiflashReplica{Count: 2}
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(client.set).To(Equal([]string{"db1.t2=1"}))
+
+	// the database is set again for a new number
+	client.set = nil
+	tc.Spec.TiFlash.TableReplicas.Tables[0].Count = 2
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(client.set).To(Equal([]string{"db1.=2"}))
+
+	// the status is removed with the spec
+	tc.Spec.TiFlash.TableReplicas = nil
+	g.Expect(syncer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.TiFlash.TableReplicas).To(BeNil())
+}
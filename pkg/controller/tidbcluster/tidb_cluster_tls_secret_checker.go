@@ -0,0 +1,117 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// TidbClusterTLSSecretChecker interface that checks whether the TLS secrets of a tidb cluster with
+// TLS enabled exist. The secrets may be issued by cert-manager or applied by GitOps after the
+// cluster is created, so the members are not synced and the cluster is requeued until they exist.
+type TidbClusterTLSSecretChecker interface {
+	Check(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterTLSSecretChecker struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterTLSSecretChecker returns a TidbClusterTLSSecretChecker
+func NewTidbClusterTLSSecretChecker(deps *controller.Dependencies) TidbClusterTLSSecretChecker {
+	return &tidbClusterTLSSecretChecker{
+		deps: deps,
+	}
+}
+
+var _ TidbClusterTLSSecretChecker = &tidbClusterTLSSecretChecker{}
+
+func (c *tidbClusterTLSSecretChecker) Check(tc *v1alpha1.TidbCluster) error {
+	var missing []string
+	for _, name := range tidbClusterTLSSecrets(tc) {
+		_, err := c.deps.SecretLister.Secrets(tc.Namespace).Get(name)
+		if errors.IsNotFound(err) {
+			missing = append(missing, name)
+		} else if err != nil {
+			return fmt.Errorf("cluster %s/%s get secret %s failed, err: %v", tc.Namespace, tc.Name, name, err)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("TLS is enabled but the secrets %s are not found, the members are synced after they are created", strings.Join(missing, ", "))
+	c.deps.Recorder.Event(tc, corev1.EventTypeWarning, "TLSSecretNotFound", msg)
+	return controller.RequeueErrorf("cluster %s/%s: %s", tc.Namespace, tc.Name, msg)
+}
+
+// tidbClusterTLSSecrets returns the TLS secrets required by the components of the tidb cluster
+func tidbClusterTLSSecrets(tc *v1alpha1.TidbCluster) []string {
+	var secrets []string
+	if tc.IsTLSClusterEnabled() {
+		secrets = append(secrets, util.ClusterClientTLSSecretName(tc.Name))
+		for _, component := range tidbClusterTLSComponents(tc) {
+			secrets = append(secrets, util.ClusterTLSSecretName(tc.Name, component))
+		}
+	}
+	if tc.Spec.TiDB != nil && tc.Spec.TiDB.IsTLSClientEnabled() {
+		secrets = append(secrets, util.TiDBServerTLSSecretName(tc.Name))
+	}
+	return secrets
+}
+
+// tidbClusterTLSComponents returns the components which require the cluster TLS secrets
+func tidbClusterTLSComponents(tc *v1alpha1.TidbCluster) []string {
+	var components []string
+	if tc.Spec.PD != nil {
+		components = append(components, label.PDLabelVal)
+	}
+	if tc.Spec.TiKV != nil {
+		components = append(components, label.TiKVLabelVal)
+	}
+	if tc.Spec.TiDB != nil {
+		components = append(components, label.TiDBLabelVal)
+	}
+	if tc.Spec.TiFlash != nil {
+		components = append(components, label.TiFlashLabelVal)
+	}
+	if tc.Spec.TiCDC != nil {
+		components = append(components, label.TiCDCLabelVal)
+	}
+	if tc.Spec.TiProxy != nil {
+		components = append(components, label.TiProxyLabelVal)
+	}
+	if tc.Spec.Pump != nil {
+		components = append(components, label.PumpLabelVal)
+	}
+	return components
+}
+
+type fakeTidbClusterTLSSecretChecker struct{}
+
+// NewFakeTidbClusterTLSSecretChecker returns a fake TidbClusterTLSSecretChecker
+func NewFakeTidbClusterTLSSecretChecker() TidbClusterTLSSecretChecker {
+	return &fakeTidbClusterTLSSecretChecker{}
+}
+
+func (c *fakeTidbClusterTLSSecretChecker) Check(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
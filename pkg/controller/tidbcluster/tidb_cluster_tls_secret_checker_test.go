@@ -0,0 +1,67 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTidbClusterTLSSecretChecker(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	checker := NewTidbClusterTLSSecretChecker(deps)
+	recorder := deps.Recorder.(*record.FakeRecorder)
+	secretIndexer := deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer()
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+		},
+	}
+	addSecret := func(name string) {
+		g.Expect(secretIndexer.Add(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tc.Namespace},
+		})).To(Succeed())
+	}
+
+	// nothing is checked if TLS is not enabled
+	g.Expect(checker.Check(tc)).To(Succeed())
+
+	tc.Spec.TLSCluster = &v1alpha1.TLSCluster{Enabled: true}
+	addSecret("test-cluster-client-secret")
+	addSecret("test-pd-cluster-secret")
+	err := checker.Check(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("test-tikv-cluster-secret"))
+	g.Expect(err.Error()).NotTo(ContainSubstring("test-pd-cluster-secret"))
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring("TLSSecretNotFound"))
+
+	// the members are synced once the secrets are created
+	addSecret("test-tikv-cluster-secret")
+	g.Expect(checker.Check(tc)).To(Succeed())
+}
@@ -0,0 +1,312 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// TidbClusterZoneDistributionUpdater interface that computes the distributions of the
+// PD, TiKV and TiDB members across the topology domains, and suggests a rebalance plan
+// when a distribution violates the topology spread constraints of its component.
+type TidbClusterZoneDistributionUpdater interface {
+	Update(*v1alpha1.TidbCluster) error
+}
+
+type tidbClusterZoneDistributionUpdater struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterZoneDistributionUpdater returns a TidbClusterZoneDistributionUpdater
+func NewTidbClusterZoneDistributionUpdater(deps *controller.Dependencies) TidbClusterZoneDistributionUpdater {
+	return &tidbClusterZoneDistributionUpdater{
+		deps: deps,
+	}
+}
+
+var _ TidbClusterZoneDistributionUpdater = &tidbClusterZoneDistributionUpdater{}
+
+// zoneMove moves a pod from a topology domain to another one
+type zoneMove struct {
+	pod       string
+	from      string
+	fromCount int32
+	to        string
+	toCount   int32
+}
+
+func (u *tidbClusterZoneDistributionUpdater) Update(tc *v1alpha1.TidbCluster) error {
+	// the topology domains of the pods can't be resolved without the permission of nodes
+	if u.deps.NodeLister == nil {
+		return nil
+	}
+
+	components := []struct {
+		memberType v1alpha1.MemberType
+		enabled    bool
+		spec       v1alpha1.ComponentAccessor
+		label      label.Label
+	}{
+		{v1alpha1.PDMemberType, tc.Spec.PD != nil, tc.BasePDSpec(), label.New().Instance(tc.GetInstanceName()).PD()},
+		{v1alpha1.TiKVMemberType, tc.Spec.TiKV != nil, tc.BaseTiKVSpec(), label.New().Instance(tc.GetInstanceName()).TiKV()},
+		{v1alpha1.TiDBMemberType, tc.Spec.TiDB != nil, tc.BaseTiDBSpec(), label.New().Instance(tc.GetInstanceName()).TiDB()},
+	}
+
+	var (
+		distributions []v1alpha1.ZoneDistribution
+		skewed        []string
+		actions       []v1alpha1.SuggestedAction
+	)
+	for _, c := range components {
+		if !c.enabled {
+			continue
+		}
+		tscs := c.spec.TopologySpreadConstraints()
+		if len(tscs) == 0 {
+			continue
+		}
+
+		selector, err := c.label.Selector()
+		if err != nil {
+			return fmt.Errorf("cluster %s/%s assemble label selector failed, err: %v", tc.Namespace, tc.Name, err)
+		}
+		pods, err := u.deps.PodLister.Pods(tc.Namespace).List(selector)
+		if err != nil {
+			return fmt.Errorf("cluster %s/%s list pods failed, selector: %s, err: %v", tc.Namespace, tc.Name, selector, err)
+		}
+		sortPodsByOrdinal(pods)
+		nodes, err := u.deps.NodeLister.List(labels.SelectorFromSet(c.spec.NodeSelector()))
+		if err != nil {
+			return fmt.Errorf("cluster %s/%s list nodes failed, err: %v", tc.Namespace, tc.Name, err)
+		}
+
+		for _, tsc := range tscs {
+			zones, podsByZone, err := u.distribute(pods, nodes, tsc.TopologyKey)
+			if err != nil {
+				return err
+			}
+			skew := zoneSkew(zones)
+			distributions = append(distributions, v1alpha1.ZoneDistribution{
+				Component:   c.memberType,
+				TopologyKey: tsc.TopologyKey,
+				Zones:       zones,
+				MaxSkew:     tsc.MaxSkew,
+				Skew:        skew,
+			})
+			if skew <= tsc.MaxSkew {
+				continue
+			}
+
+			skewed = append(skewed, fmt.Sprintf("skew of %s members across %s is %d, exceeds max skew %d",
+				c.memberType, tsc.TopologyKey, skew, tsc.MaxSkew))
+			for _, move := range rebalanceZones(zones, podsByZone, tsc.MaxSkew) {
+				actions = append(actions, zoneSkewedAction(tc, c.memberType, tsc.TopologyKey, tsc.MaxSkew, move))
+			}
+		}
+	}
+
+	tc.Status.ZoneDistributions = distributions
+	setZoneSkewedActions(&tc.Status, actions)
+	if len(distributions) == 0 {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterZoneBalanced)
+		return nil
+	}
+	var cond *v1alpha1.TidbClusterCondition
+	if len(skewed) == 0 {
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterZoneBalanced, corev1.ConditionTrue,
+			utiltidbcluster.ZoneBalanced, "members are distributed as declared by the topology spread constraints")
+	} else {
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterZoneBalanced, corev1.ConditionFalse,
+			utiltidbcluster.ZoneSkewed, strings.Join(skewed, "; "))
+	}
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+	return nil
+}
+
+// distribute counts the pods scheduled to each domain of the topology key. All the domains
+// of the candidate nodes are counted, so a domain without any pod makes the skew visible.
+func (u *tidbClusterZoneDistributionUpdater) distribute(pods []*corev1.Pod, nodes []*corev1.Node, topologyKey string) (map[string]int32, map[string][]string, error) {
+	zones := map[string]int32{}
+	for _, node := range nodes {
+		if zone, ok := node.Labels[topologyKey]; ok {
+			zones[zone] = 0
+		}
+	}
+
+	podsByZone := map[string][]string{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		node, err := u.deps.NodeLister.Get(pod.Spec.NodeName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get node %s: %v", pod.Spec.NodeName, err)
+		}
+		zone, ok := node.Labels[topologyKey]
+		if !ok {
+			continue
+		}
+		zones[zone]++
+		podsByZone[zone] = append(podsByZone[zone], pod.Name)
+	}
+	return zones, podsByZone, nil
+}
+
+// zoneSkew returns the difference between the numbers of pods in the most and least
+// populated domains.
+func zoneSkew(zones map[string]int32) int32 {
+	if len(zones) == 0 {
+		return 0
+	}
+	first := true
+	var max, min int32
+	for _, n := range zones {
+		if first || n > max {
+			max = n
+		}
+		if first || n < min {
+			min = n
+		}
+		first = false
+	}
+	return max - min
+}
+
+// sortPodsByOrdinal sorts the pods by their ordinals, so that test-tikv-10 is after test-tikv-9.
+// The pods whose ordinals can't be parsed are sorted by their names after the others.
+func sortPodsByOrdinal(pods []*corev1.Pod) {
+	sort.Slice(pods, func(i, j int) bool {
+		oi, erri := util.GetOrdinalFromPodName(pods[i].Name)
+		oj, errj := util.GetOrdinalFromPodName(pods[j].Name)
+		if (erri == nil) != (errj == nil) {
+			return erri == nil
+		}
+		if erri == nil && oi != oj {
+			return oi < oj
+		}
+		return pods[i].Name < pods[j].Name
+	})
+}
+
+// rebalanceZones plans the moves to bring the skew of the domains within maxSkew by
+// moving a pod from the most populated domain to the least populated one at a time.
+// The pods with the largest ordinals are moved first.
+func rebalanceZones(zones map[string]int32, podsByZone map[string][]string, maxSkew int32) []zoneMove {
+	counts := make(map[string]int32, len(zones))
+	names := make([]string, 0, len(zones))
+	total := 0
+	for zone, n := range zones {
+		counts[zone] = n
+		names = append(names, zone)
+		total += int(n)
+	}
+	sort.Strings(names)
+	remaining := map[string][]string{}
+	for zone, pods := range podsByZone {
+		remaining[zone] = append([]string(nil), pods...)
+	}
+
+	var moves []zoneMove
+	for i := 0; i < total; i++ {
+		from, to := "", ""
+		for _, zone := range names {
+			if from == "" || counts[zone] > counts[from] {
+				from = zone
+			}
+			if to == "" || counts[zone] < counts[to] {
+				to = zone
+			}
+		}
+		if from == "" || counts[from]-counts[to] <= maxSkew || len(remaining[from]) == 0 {
+			break
+		}
+		pods := remaining[from]
+		moves = append(moves, zoneMove{
+			pod:       pods[len(pods)-1],
+			from:      from,
+			fromCount: counts[from],
+			to:        to,
+			toCount:   counts[to],
+		})
+		remaining[from] = pods[:len(pods)-1]
+		counts[from]--
+		counts[to]++
+	}
+	return moves
+}
+
+func zoneSkewedAction(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, topologyKey string, maxSkew int32, move zoneMove) v1alpha1.SuggestedAction {
+	action := v1alpha1.SuggestedAction{
+		Type:      v1alpha1.SuggestedActionZoneSkewed,
+		Component: memberType,
+		Target:    move.pod,
+		Message: fmt.Sprintf("pod %s is in %s=%s with %d %s member(s) while %s has %d, move it to %s to keep the skew within %d",
+			move.pod, topologyKey, move.from, move.fromCount, memberType, move.to, move.toCount, move.to, maxSkew),
+		Since: metav1.Now(),
+	}
+	if memberType == v1alpha1.TiDBMemberType {
+		// TiDB is stateless, the recreated pod is scheduled by the topology spread constraints
+		action.Command = fmt.Sprintf("kubectl delete pod %s -n %s", move.pod, tc.Namespace)
+	} else {
+		action.Message += ", its volumes keep it in the current domain, so they have to be recreated in the target domain"
+	}
+	return action
+}
+
+// setZoneSkewedActions replaces the actions for skewed zones in the status, the time an
+// action is first observed is kept.
+func setZoneSkewedActions(status *v1alpha1.TidbClusterStatus, actions []v1alpha1.SuggestedAction) {
+	var kept []v1alpha1.SuggestedAction
+	since := map[string]metav1.Time{}
+	for _, action := range status.SuggestedActions {
+		if action.Type != v1alpha1.SuggestedActionZoneSkewed {
+			kept = append(kept, action)
+			continue
+		}
+		since[string(action.Component)+"/"+action.Target] = action.Since
+	}
+	for _, action := range actions {
+		if t, ok := since[string(action.Component)+"/"+action.Target]; ok {
+			action.Since = t
+		}
+		kept = append(kept, action)
+	}
+	status.SuggestedActions = kept
+}
+
+type fakeTidbClusterZoneDistributionUpdater struct{}
+
+// NewFakeTidbClusterZoneDistributionUpdater returns a fake TidbClusterZoneDistributionUpdater
+func NewFakeTidbClusterZoneDistributionUpdater() TidbClusterZoneDistributionUpdater {
+	return &fakeTidbClusterZoneDistributionUpdater{}
+}
+
+func (u *fakeTidbClusterZoneDistributionUpdater) Update(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
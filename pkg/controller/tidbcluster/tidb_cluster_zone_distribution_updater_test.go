@@ -0,0 +1,170 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterZoneDistributionUpdater(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	updater := NewTidbClusterZoneDistributionUpdater(deps)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+			TiDB: &v1alpha1.TiDBSpec{},
+			TopologySpreadConstraints: []v1alpha1.TopologySpreadConstraint{
+				{TopologyKey: corev1.LabelTopologyZone},
+			},
+		},
+		Status: v1alpha1.TidbClusterStatus{
+			SuggestedActions: []v1alpha1.SuggestedAction{
+				{Type: v1alpha1.SuggestedActionPVCPending, Target: "tikv-test-tikv-3"},
+			},
+		},
+	}
+
+	nodeIndexer := deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+	for _, zone := range []string{"a", "b", "c"} {
+		nodeIndexer.Add(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-" + zone,
+				Labels: map[string]string{corev1.LabelTopologyZone: zone},
+			},
+		})
+	}
+
+	podIndexer := deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	addPods := func(l label.Label, component string, nodes ...string) {
+		for i, node := range nodes {
+			podIndexer.Add(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("test-%s-%d", component, i),
+					Namespace: corev1.NamespaceDefault,
+					Labels:    l.Labels(),
+				},
+				Spec: corev1.PodSpec{NodeName: node},
+			})
+		}
+	}
+	addPods(label.New().Instance("test").PD(), "pd", "node-a", "node-b", "node-c")
+	addPods(label.New().Instance("test").TiKV(), "tikv", "node-a", "node-a", "node-a", "node-b")
+	// the pod not scheduled yet is ignored
+	addPods(label.New().Instance("test").TiDB(), "tidb", "node-a", "node-a", "")
+
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(tc.Status.ZoneDistributions).To(Equal([]v1alpha1.ZoneDistribution{
+		{
+			Component:   v1alpha1.PDMemberType,
+			TopologyKey: corev1.LabelTopologyZone,
+			Zones:       map[string]int32{"a": 1, "b": 1, "c": 1},
+			MaxSkew:     1,
+			Skew:        0,
+		},
+		{
+			Component:   v1alpha1.TiKVMemberType,
+			TopologyKey: corev1.LabelTopologyZone,
+			Zones:       map[string]int32{"a": 3, "b": 1, "c": 0},
+			MaxSkew:     1,
+			Skew:        3,
+		},
+		{
+			Component:   v1alpha1.TiDBMemberType,
+			TopologyKey: corev1.LabelTopologyZone,
+			Zones:       map[string]int32{"a": 2, "b": 0, "c": 0},
+			MaxSkew:     1,
+			Skew:        2,
+		},
+	}))
+
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterZoneBalanced)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.ZoneSkewed))
+
+	actions := tc.Status.SuggestedActions
+	g.Expect(actions).To(HaveLen(3))
+	g.Expect(actions[0].Type).To(Equal(v1alpha1.SuggestedActionPVCPending))
+	g.Expect(actions[1].Type).To(Equal(v1alpha1.SuggestedActionZoneSkewed))
+	g.Expect(actions[1].Component).To(Equal(v1alpha1.TiKVMemberType))
+	g.Expect(actions[1].Target).To(Equal("test-tikv-2"))
+	g.Expect(actions[1].Command).To(BeEmpty())
+	g.Expect(actions[2].Component).To(Equal(v1alpha1.TiDBMemberType))
+	g.Expect(actions[2].Target).To(Equal("test-tidb-1"))
+	g.Expect(actions[2].Command).To(Equal("kubectl delete pod test-tidb-1 -n default"))
+
+	// the time the action is first observed is kept
+	since := metav1.NewTime(actions[1].Since.Add(-time.Hour))
+	tc.Status.SuggestedActions[1].Since = since
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(tc.Status.SuggestedActions).To(HaveLen(3))
+	g.Expect(tc.Status.SuggestedActions[1].Since).To(Equal(since))
+
+	// the condition is removed once no topology spread constraint is declared
+	tc.Spec.TopologySpreadConstraints = nil
+	g.Expect(updater.Update(tc)).To(Succeed())
+	g.Expect(tc.Status.ZoneDistributions).To(BeEmpty())
+	g.Expect(tc.Status.SuggestedActions).To(HaveLen(1))
+	g.Expect(utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterZoneBalanced)).To(BeNil())
+}
+
+func TestRebalanceZones(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	zones := map[string]int32{"a": 4, "b": 1, "c": 0}
+	podsByZone := map[string][]string{"a": {"p0", "p1", "p2", "p3"}, "b": {"p4"}}
+	g.Expect(zoneSkew(zones)).To(Equal(int32(4)))
+	g.Expect(rebalanceZones(zones, podsByZone, 1)).To(Equal([]zoneMove{
+		{pod: "p3", from: "a", fromCount: 4, to: "c", toCount: 0},
+		{pod: "p2", from: "a", fromCount: 3, to: "b", toCount: 1},
+	}))
+	g.Expect(rebalanceZones(zones, podsByZone, 4)).To(BeEmpty())
+	// the input is not modified
+	g.Expect(zones).To(Equal(map[string]int32{"a": 4, "b": 1, "c": 0}))
+	g.Expect(podsByZone["a"]).To(HaveLen(4))
+	g.Expect(zoneSkew(nil)).To(Equal(int32(0)))
+}
+
+func TestSortPodsByOrdinal(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var pods []*corev1.Pod
+	for _, name := range []string{"test-tikv-10", "test-tikv-2", "invalid", "test-tikv-9", "test-tikv-0"} {
+		pods = append(pods, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	sortPodsByOrdinal(pods)
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	g.Expect(names).To(Equal([]string{"test-tikv-0", "test-tikv-2", "test-tikv-9", "test-tikv-10", "invalid"}))
+}
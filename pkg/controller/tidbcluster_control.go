@@ -79,6 +79,9 @@ func (c *realTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster, new
 			// TiKV.EvictLeader is controlled by pod leader evictor in pkg/controller/tidbcluster/pod_control.go
 			// So don't overwrite it
 			status.TiKV.EvictLeader = tc.Status.TiKV.EvictLeader
+			// DisruptionLock is written by the clusters sharing the PD in pkg/manager/member/disruption_lock.go
+			// So don't overwrite it
+			status.DisruptionLock = tc.Status.DisruptionLock
 			tc.Status = *status
 		} else {
 			utilruntime.HandleError(fmt.Errorf("error getting updated TidbCluster %s/%s from lister: %v", ns, tcName, err))
@@ -0,0 +1,422 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbclusterreplication
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultSinkUser is the user to connect to the TiDB of the secondary cluster if it's not specified
+	defaultSinkUser = "root"
+	// sinkPasswordKey is the key of the password in the secret of the sink user
+	sinkPasswordKey = "password"
+)
+
+// ControlInterface abstracts the business logic for TidbClusterReplication reconciliation.
+type ControlInterface interface {
+	Reconcile(*v1alpha1.TidbClusterReplication) error
+}
+
+// NewTidbClusterReplicationControl returns a new instance of ControlInterface
+func NewTidbClusterReplicationControl(deps *controller.Dependencies) ControlInterface {
+	return &defaultTidbClusterReplicationControl{
+		deps: deps,
+	}
+}
+
+type defaultTidbClusterReplicationControl struct {
+	deps *controller.Dependencies
+}
+
+func (c *defaultTidbClusterReplicationControl) Reconcile(tcr *v1alpha1.TidbClusterReplication) error {
+	if tcr.DeletionTimestamp != nil {
+		return nil
+	}
+	if !c.validate(tcr) {
+		return nil
+	}
+	// the replication is stopped once the secondary cluster is promoted
+	if tcr.Status.Phase == v1alpha1.ReplicationPromoted {
+		return nil
+	}
+
+	oldStatus := tcr.Status.DeepCopy()
+	if tcr.Status.Phase == "" {
+		tcr.Status.Phase = v1alpha1.ReplicationPending
+	}
+
+	var err error
+	switch tcr.GetMode() {
+	case v1alpha1.ReplicationModePITR:
+		err = c.syncPITR(tcr)
+	default:
+		err = c.syncTiCDC(tcr)
+	}
+
+	if !apiequality.Semantic.DeepEqual(&tcr.Status, oldStatus) {
+		if _, updateErr := c.updateStatus(tcr.DeepCopy()); updateErr != nil {
+			return updateErr
+		}
+	}
+	return err
+}
+
+// syncTiCDC replicates the data by a changefeed of the TiCDC of the primary cluster. The
+// secondary cluster is promoted by removing the changefeed once the lag is within MaxPromoteLag.
+func (c *defaultTidbClusterReplicationControl) syncTiCDC(tcr *v1alpha1.TidbClusterReplication) error {
+	ns, name := tcr.GetNamespace(), tcr.GetName()
+	force := tcr.Spec.Promote && tcr.Spec.ForcePromote
+	id := tcr.ChangefeedID()
+
+	primary, err := c.deps.TiDBClusterLister.TidbClusters(tcr.PrimaryNamespace()).Get(tcr.Spec.Primary.Name)
+	if err != nil {
+		if errors.IsNotFound(err) && force {
+			// the primary cluster is lost, fail over to the secondary cluster
+			c.promote(tcr, fmt.Sprintf("primary cluster %s/%s is not found", tcr.PrimaryNamespace(), tcr.Spec.Primary.Name))
+			return nil
+		}
+		return fmt.Errorf("tidb cluster replication %s/%s get primary cluster failed: %v", ns, name, err)
+	}
+	if primary.Spec.TiCDC == nil {
+		setPhase(tcr, v1alpha1.ReplicationFailed, fmt.Sprintf("primary cluster %s/%s has no TiCDC", primary.Namespace, primary.Name))
+		return nil
+	}
+
+	info, err := c.deps.CDCControl.GetChangefeed(primary, id)
+	if err != nil {
+		if force {
+			c.promote(tcr, fmt.Sprintf("changefeed %s is unavailable: %v", id, err))
+			return nil
+		}
+		return fmt.Errorf("tidb cluster replication %s/%s get changefeed %s failed: %v", ns, name, id, err)
+	}
+	if info == nil {
+		if tcr.Spec.Promote {
+			// the changefeed has been removed by the last promotion which failed to update the status
+			c.promote(tcr, fmt.Sprintf("changefeed %s is removed", id))
+			return nil
+		}
+		sinkURI, err := c.sinkURI(tcr)
+		if err != nil {
+			return err
+		}
+		if err := c.deps.CDCControl.CreateChangefeed(primary, id, sinkURI, 0); err != nil {
+			return fmt.Errorf("tidb cluster replication %s/%s create changefeed %s failed: %v", ns, name, id, err)
+		}
+		klog.Infof("tidb cluster replication %s/%s created changefeed %s", ns, name, id)
+		tcr.Status.ChangefeedID = id
+		return controller.RequeueErrorf("tidb cluster replication %s/%s waits for changefeed %s to start", ns, name, id)
+	}
+
+	tcr.Status.ChangefeedID = id
+	if info.CheckpointTSO != 0 {
+		setCheckpoint(tcr, config.TSToGoTime(info.CheckpointTSO))
+	}
+	if info.State == "failed" || info.State == "error" {
+		msg := fmt.Sprintf("changefeed %s is %s", id, info.State)
+		if info.Error != nil {
+			msg = fmt.Sprintf("%s: %s", msg, info.Error.Message)
+		}
+		if !force {
+			setPhase(tcr, v1alpha1.ReplicationFailed, msg)
+			return nil
+		}
+	}
+
+	if !tcr.Spec.Promote {
+		setPhase(tcr, v1alpha1.ReplicationReplicating, "")
+		return nil
+	}
+	if err := c.checkPromoteLag(tcr); err != nil {
+		return err
+	}
+	if err := c.deps.CDCControl.RemoveChangefeed(primary, id); err != nil && !force {
+		return fmt.Errorf("tidb cluster replication %s/%s remove changefeed %s failed: %v", ns, name, id, err)
+	}
+	c.promote(tcr, fmt.Sprintf("changefeed %s is removed", id))
+	return nil
+}
+
+// syncPITR replicates the data by a log backup of the primary cluster. The secondary cluster
+// is promoted by a PiTR restore of the log backup.
+func (c *defaultTidbClusterReplicationControl) syncPITR(tcr *v1alpha1.TidbClusterReplication) error {
+	ns, name := tcr.GetNamespace(), tcr.GetName()
+
+	logBackup, err := c.deps.BackupLister.Backups(ns).Get(tcr.LogBackupName())
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("tidb cluster replication %s/%s get log backup %s failed: %v", ns, name, tcr.LogBackupName(), err)
+	}
+	if errors.IsNotFound(err) {
+		if tcr.Spec.Promote {
+			setPhase(tcr, v1alpha1.ReplicationFailed, fmt.Sprintf("log backup %s is not found", tcr.LogBackupName()))
+			return nil
+		}
+		logBackup, err = c.deps.Clientset.PingcapV1alpha1().Backups(ns).Create(context.TODO(), c.newLogBackup(tcr), metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("tidb cluster replication %s/%s create log backup %s failed: %v", ns, name, tcr.LogBackupName(), err)
+		}
+		klog.Infof("tidb cluster replication %s/%s created log backup %s", ns, name, tcr.LogBackupName())
+	}
+
+	tcr.Status.LogBackup = logBackup.Name
+	if logBackup.Status.LogCheckpointTs != "" {
+		checkpoint, err := config.ParseTSStringToGoTime(logBackup.Status.LogCheckpointTs)
+		if err != nil {
+			klog.Warningf("tidb cluster replication %s/%s parse checkpoint ts %s failed: %v", ns, name, logBackup.Status.LogCheckpointTs, err)
+		} else {
+			setCheckpoint(tcr, checkpoint)
+		}
+	}
+
+	if !tcr.Spec.Promote {
+		if v1alpha1.IsBackupFailed(logBackup) {
+			setPhase(tcr, v1alpha1.ReplicationFailed, fmt.Sprintf("log backup %s is failed", logBackup.Name))
+			return nil
+		}
+		setPhase(tcr, v1alpha1.ReplicationReplicating, "")
+		return nil
+	}
+
+	restore, err := c.deps.RestoreLister.Restores(ns).Get(tcr.RestoreName())
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("tidb cluster replication %s/%s get restore %s failed: %v", ns, name, tcr.RestoreName(), err)
+	}
+	if errors.IsNotFound(err) {
+		if !tcr.Spec.ForcePromote {
+			if err := c.checkPromoteLag(tcr); err != nil {
+				return err
+			}
+		}
+		restore, err = c.deps.Clientset.PingcapV1alpha1().Restores(ns).Create(context.TODO(), c.newRestore(tcr, logBackup), metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("tidb cluster replication %s/%s create restore %s failed: %v", ns, name, tcr.RestoreName(), err)
+		}
+		klog.Infof("tidb cluster replication %s/%s created restore %s", ns, name, tcr.RestoreName())
+	}
+
+	tcr.Status.Restore = restore.Name
+	switch {
+	case v1alpha1.IsRestoreComplete(restore):
+		// the log backup is useless once the logs are restored, stop it in best effort
+		if logBackup.Spec.LogSubcommand != v1alpha1.LogStopCommand {
+			stopped := logBackup.DeepCopy()
+			stopped.Spec.LogSubcommand = v1alpha1.LogStopCommand
+			if _, err := c.deps.Clientset.PingcapV1alpha1().Backups(ns).Update(context.TODO(), stopped, metav1.UpdateOptions{}); err != nil {
+				klog.Warningf("tidb cluster replication %s/%s stop log backup %s failed: %v", ns, name, logBackup.Name, err)
+			}
+		}
+		c.promote(tcr, fmt.Sprintf("restore %s is complete", restore.Name))
+	case v1alpha1.IsRestoreFailed(restore):
+		setPhase(tcr, v1alpha1.ReplicationFailed, fmt.Sprintf("restore %s is failed", restore.Name))
+	default:
+		setPhase(tcr, v1alpha1.ReplicationPromoting, fmt.Sprintf("restore %s is running", restore.Name))
+	}
+	return nil
+}
+
+// checkPromoteLag returns a requeue error if the lag exceeds MaxPromoteLag and the
+// promotion is not forced.
+func (c *defaultTidbClusterReplicationControl) checkPromoteLag(tcr *v1alpha1.TidbClusterReplication) error {
+	if tcr.Spec.ForcePromote {
+		return nil
+	}
+	maxLag := tcr.MaxPromoteLag()
+	if tcr.Status.CheckpointTime != nil {
+		lag := time.Since(tcr.Status.CheckpointTime.Time)
+		if lag <= maxLag {
+			return nil
+		}
+		setPhase(tcr, v1alpha1.ReplicationPromoting, fmt.Sprintf("waiting for the lag %s to be within %s", lag.Truncate(time.Second), maxLag))
+	} else {
+		setPhase(tcr, v1alpha1.ReplicationPromoting, "waiting for the checkpoint of the replication")
+	}
+	return controller.RequeueErrorf("tidb cluster replication %s/%s: %s", tcr.GetNamespace(), tcr.GetName(), tcr.Status.Message)
+}
+
+func (c *defaultTidbClusterReplicationControl) promote(tcr *v1alpha1.TidbClusterReplication, reason string) {
+	now := metav1.Now()
+	tcr.Status.PromoteTime = &now
+	msg := fmt.Sprintf("secondary cluster %s/%s is promoted, %s", tcr.SecondaryNamespace(), tcr.Spec.Secondary.Name, reason)
+	setPhase(tcr, v1alpha1.ReplicationPromoted, msg)
+	c.deps.Recorder.Event(tcr, corev1.EventTypeNormal, "Promoted", msg)
+}
+
+// sinkURI returns the URI of the TiDB of the secondary cluster, which the changefeed replicates to
+func (c *defaultTidbClusterReplicationControl) sinkURI(tcr *v1alpha1.TidbClusterReplication) (string, error) {
+	secondary, err := c.deps.TiDBClusterLister.TidbClusters(tcr.SecondaryNamespace()).Get(tcr.Spec.Secondary.Name)
+	if err != nil {
+		return "", fmt.Errorf("tidb cluster replication %s/%s get secondary cluster failed: %v", tcr.GetNamespace(), tcr.GetName(), err)
+	}
+	if secondary.Spec.TiDB == nil {
+		return "", fmt.Errorf("secondary cluster %s/%s has no TiDB", secondary.Namespace, secondary.Name)
+	}
+
+	user := defaultSinkUser
+	var password string
+	if spec := tcr.Spec.TiCDC; spec != nil {
+		if spec.User != "" {
+			user = spec.User
+		}
+		if spec.SecretName != "" {
+			secret, err := c.deps.SecretLister.Secrets(tcr.GetNamespace()).Get(spec.SecretName)
+			if err != nil {
+				return "", fmt.Errorf("tidb cluster replication %s/%s get secret %s failed: %v", tcr.GetNamespace(), tcr.GetName(), spec.SecretName, err)
+			}
+			password = string(secret.Data[sinkPasswordKey])
+		}
+	}
+
+	u := url.URL{
+		Scheme: "mysql",
+		User:   url.UserPassword(user, password),
+		Host: fmt.Sprintf("%s.%s.svc%s:%d", controller.TiDBMemberName(secondary.Name), secondary.Namespace,
+			controller.FormatClusterDomain(secondary.Spec.ClusterDomain), secondary.Spec.TiDB.GetServicePort()),
+		Path: "/",
+	}
+	return u.String(), nil
+}
+
+func (c *defaultTidbClusterReplicationControl) newLogBackup(tcr *v1alpha1.TidbClusterReplication) *v1alpha1.Backup {
+	spec := tcr.Spec.PITR.LogBackupTemplate.DeepCopy()
+	spec.Mode = v1alpha1.BackupModeLog
+	if spec.BR == nil {
+		spec.BR = &v1alpha1.BRConfig{}
+	}
+	spec.BR.Cluster = tcr.Spec.Primary.Name
+	spec.BR.ClusterNamespace = tcr.PrimaryNamespace()
+	return &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            tcr.LogBackupName(),
+			Namespace:       tcr.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbClusterReplicationOwnerRef(tcr)},
+		},
+		Spec: *spec,
+	}
+}
+
+func (c *defaultTidbClusterReplicationControl) newRestore(tcr *v1alpha1.TidbClusterReplication, logBackup *v1alpha1.Backup) *v1alpha1.Restore {
+	spec := tcr.Spec.PITR.RestoreTemplate.DeepCopy()
+	spec.Mode = v1alpha1.RestoreModePiTR
+	if spec.BR == nil {
+		spec.BR = &v1alpha1.BRConfig{}
+	}
+	spec.BR.Cluster = tcr.Spec.Secondary.Name
+	spec.BR.ClusterNamespace = tcr.SecondaryNamespace()
+	spec.StorageProvider = *logBackup.Spec.StorageProvider.DeepCopy()
+	spec.PitrRestoredTs = logBackup.Status.LogCheckpointTs
+	return &v1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            tcr.RestoreName(),
+			Namespace:       tcr.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbClusterReplicationOwnerRef(tcr)},
+		},
+		Spec: *spec,
+	}
+}
+
+func (c *defaultTidbClusterReplicationControl) updateStatus(tcr *v1alpha1.TidbClusterReplication) (*v1alpha1.TidbClusterReplication, error) {
+	var (
+		ns     = tcr.GetNamespace()
+		name   = tcr.GetName()
+		status = tcr.Status.DeepCopy()
+		update *v1alpha1.TidbClusterReplication
+	)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var updateErr error
+		update, updateErr = c.deps.Clientset.PingcapV1alpha1().TidbClusterReplications(ns).UpdateStatus(context.TODO(), tcr, metav1.UpdateOptions{})
+		if updateErr == nil {
+			klog.Infof("TidbClusterReplication: [%s/%s], update status successfully", ns, name)
+			return nil
+		}
+
+		klog.V(4).Infof("TidbClusterReplication: [%s/%s], update status failed, error: %v", ns, name, updateErr)
+
+		// If failed to update status, then:
+		// get the latest TidbClusterReplication, override the status to local newest, prepare for next update.
+		if updated, err := c.deps.TiDBClusterReplicationLister.TidbClusterReplications(ns).Get(name); err == nil {
+			tcr = updated.DeepCopy()
+			tcr.Status = *status
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated TidbClusterReplication %s/%s from lister: %v", ns, name, err))
+		}
+
+		return updateErr
+	})
+	if err != nil {
+		klog.Errorf("TidbClusterReplication: [%s/%s], failed to updateStatus, error: %v", ns, name, err)
+	}
+
+	return update, err
+}
+
+func (c *defaultTidbClusterReplicationControl) validate(tcr *v1alpha1.TidbClusterReplication) bool {
+	errs := v1alpha1validation.ValidateTidbClusterReplication(tcr)
+	if len(errs) > 0 {
+		aggregatedErr := errs.ToAggregate()
+		klog.Errorf("tidb cluster replication %s/%s is not valid and must be fixed first, aggregated error: %v", tcr.GetNamespace(), tcr.GetName(), aggregatedErr)
+		c.deps.Recorder.Event(tcr, corev1.EventTypeWarning, "FailedValidation", aggregatedErr.Error())
+		return false
+	}
+	return true
+}
+
+func setPhase(tcr *v1alpha1.TidbClusterReplication, phase v1alpha1.ReplicationPhase, message string) {
+	tcr.Status.Phase = phase
+	tcr.Status.Message = message
+}
+
+// setCheckpoint records the checkpoint and the lag of the replication, the lag is
+// truncated to seconds to avoid updating the status on every sync.
+func setCheckpoint(tcr *v1alpha1.TidbClusterReplication, checkpoint time.Time) {
+	t := metav1.NewTime(checkpoint)
+	tcr.Status.CheckpointTime = &t
+	lag := time.Since(checkpoint).Truncate(time.Second)
+	if lag < 0 {
+		lag = 0
+	}
+	tcr.Status.Lag = lag.String()
+}
+
+type FakeTidbClusterReplicationControl struct {
+	reconcile func(*v1alpha1.TidbClusterReplication) error
+}
+
+func (c *FakeTidbClusterReplicationControl) MockReconcile(reconcile func(*v1alpha1.TidbClusterReplication) error) {
+	c.reconcile = reconcile
+}
+
+func (c *FakeTidbClusterReplicationControl) Reconcile(tcr *v1alpha1.TidbClusterReplication) error {
+	if c.reconcile != nil {
+		return c.reconcile(tcr)
+	}
+	return nil
+}
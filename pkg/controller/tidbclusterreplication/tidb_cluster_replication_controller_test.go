@@ -0,0 +1,106 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbclusterreplication
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestControllerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name string
+
+		addIndexer bool
+		reconcile  func(tcr *v1alpha1.TidbClusterReplication) error
+
+		expectErrFn func(error)
+	}
+
+	cases := []testcase{
+		{
+			name:       "sync succeeded",
+			addIndexer: true,
+			reconcile:  nil,
+			expectErrFn: func(err error) {
+				g.Expect(err).Should(Succeed())
+			},
+		},
+		{
+			name:       "tidb cluster replication isn't found",
+			addIndexer: false,
+			reconcile: func(tcr *v1alpha1.TidbClusterReplication) error {
+				return fmt.Errorf("shouldn't arrive")
+			},
+			expectErrFn: func(err error) {
+				g.Expect(err).Should(Succeed())
+			},
+		},
+		{
+			name: "reconcile tidb cluster replication failed",
+			reconcile: func(tcr *v1alpha1.TidbClusterReplication) error {
+				return fmt.Errorf("reconcile failed")
+			},
+			addIndexer: true,
+			expectErrFn: func(err error) {
+				g.Expect(err).Should(HaveOccurred())
+				g.Expect(err).Should(MatchError("reconcile failed"))
+			},
+		},
+	}
+
+	for _, testcase := range cases {
+		t.Logf("testcase: %s", testcase.name)
+
+		fakeController, indexer := newFakeControllerForTest()
+		control := fakeController.control.(*FakeTidbClusterReplicationControl)
+
+		tcr := &v1alpha1.TidbClusterReplication{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: corev1.NamespaceDefault}}
+
+		if testcase.reconcile != nil {
+			control.MockReconcile(testcase.reconcile)
+		}
+		if testcase.addIndexer {
+			err := indexer.Add(tcr)
+			g.Expect(err).Should(Succeed())
+		}
+
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(tcr)
+		g.Expect(err).Should(Succeed())
+
+		err = fakeController.sync(key)
+		testcase.expectErrFn(err)
+	}
+}
+
+func newFakeControllerForTest() (*Controller, cache.Indexer) {
+	fakeDeps := controller.NewFakeDependencies()
+	indexer := fakeDeps.InformerFactory.Pingcap().V1alpha1().TidbClusterReplications().Informer().GetIndexer()
+	control := &FakeTidbClusterReplicationControl{}
+
+	fakeController := NewController(fakeDeps)
+	fakeController.control = control
+
+	return fakeController, indexer
+}
@@ -17,7 +17,9 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -58,10 +60,40 @@ func NewController(deps *controller.Dependencies) *Controller {
 	controller.WatchForController(jobInformer.Informer(), c.queue, func(ns, name string) (runtime.Object, error) {
 		return c.deps.TiDBInitializerLister.TidbInitializers(ns).Get(name)
 	}, m)
+	// the passwords are rotated when the password secrets are changed
+	deps.KubeInformerFactory.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			c.enqueueForPasswordSecret(cur)
+		},
+	})
 
 	return c
 }
 
+// enqueueForPasswordSecret enqueues the tidbinitializers rotating the passwords in the secret
+func (c *Controller) enqueueForPasswordSecret(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	tis, err := c.deps.TiDBInitializerLister.TidbInitializers(secret.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list tidbinitializers in namespace %s: %v", secret.Namespace, err))
+		return
+	}
+	for _, ti := range tis {
+		if !ti.Spec.RotatePassword || ti.Spec.PasswordSecret == nil || *ti.Spec.PasswordSecret != secret.Name {
+			continue
+		}
+		key, err := cache.MetaNamespaceKeyFunc(ti)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("cound't get key for object %+v: %v", ti, err))
+			continue
+		}
+		c.queue.Add(key)
+	}
+}
+
 // Name returns the name of the tidbinitializer controller
 func (c *Controller) Name() string {
 	return "tidbinitializer"
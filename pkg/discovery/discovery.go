@@ -20,12 +20,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
@@ -45,6 +47,13 @@ type tidbDiscovery struct {
 	dmClusters    map[string]*clusterInfo
 	pdControl     pdapi.PDControlInterface
 	masterControl dmapi.MasterControlInterface
+
+	// statelessBootstrap is set when discovery is served by multiple replicas, the registered
+	// peers can't be counted in memory then, so the first PD member bootstraps the cluster
+	statelessBootstrap bool
+	membersCacheTTL    time.Duration
+	membersCache       map[string]*cachedMembers
+	now                func() time.Time
 }
 
 type clusterInfo struct {
@@ -52,6 +61,29 @@ type clusterInfo struct {
 	peers           map[string]struct{}
 }
 
+type cachedMembers struct {
+	members  *pdapi.MembersInfo
+	expireAt time.Time
+}
+
+// Option configures the TiDBDiscovery
+type Option func(*tidbDiscovery)
+
+// WithStatelessBootstrap makes the discovery not rely on the peers registered in memory to
+// bootstrap the PD cluster, so that it can be served by multiple replicas
+func WithStatelessBootstrap(stateless bool) Option {
+	return func(d *tidbDiscovery) {
+		d.statelessBootstrap = stateless
+	}
+}
+
+// WithMembersCacheTTL caches the PD members for the ttl, zero disables the cache
+func WithMembersCacheTTL(ttl time.Duration) Option {
+	return func(d *tidbDiscovery) {
+		d.membersCacheTTL = ttl
+	}
+}
+
 type pdEndpointURL struct {
 	scheme       string
 	pdMemberName string
@@ -60,14 +92,20 @@ type pdEndpointURL struct {
 }
 
 // NewTiDBDiscovery returns a TiDBDiscovery
-func NewTiDBDiscovery(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface) TiDBDiscovery {
-	return &tidbDiscovery{
+func NewTiDBDiscovery(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface, opts ...Option) TiDBDiscovery {
+	d := &tidbDiscovery{
 		cli:           cli,
 		pdControl:     pdControl,
 		masterControl: masterControl,
 		clusters:      map[string]*clusterInfo{},
 		dmClusters:    map[string]*clusterInfo{},
+		membersCache:  map[string]*cachedMembers{},
+		now:           time.Now,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
@@ -98,6 +136,27 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 	}
 	keyName := fmt.Sprintf("%s/%s", ns, tcName)
 
+	if ordinal, err := util.GetOrdinalFromPodName(podName); err == nil && tc.PDIsLearnerOrdinal(ordinal) {
+		// the learner must not join as a voter, it waits until PD is upgraded or the learners are removed
+		if !tc.PDSupports(v1alpha1.PDFeatureLearner) {
+			return "", fmt.Errorf("pd %s of version %s can't join the pd cluster %s as a learner, %s or later is required",
+				podName, tc.PDVersion(), keyName, v1alpha1.PDFeatureMinVersion(v1alpha1.PDFeatureLearner))
+		}
+		return d.learnerArgs(tc, podName, advertisePeerUrl)
+	}
+
+	if d.statelessBootstrap {
+		membersInfo, err := d.getPDMembers(keyName, tc)
+		if err != nil {
+			if !isBootstrapPDMember(tc, podName) {
+				return "", err
+			}
+			klog.Infof("PD cluster %s is not available, bootstrap it with %s", keyName, podName)
+			return initialClusterArgs(tc, podName, advertisePeerUrl), nil
+		}
+		return joinArgs(membersInfo, podName, strArr[0]), nil
+	}
+
 	currentCluster := d.clusters[keyName]
 	if currentCluster == nil || currentCluster.resourceVersion != tc.ResourceVersion {
 		d.clusters[keyName] = &clusterInfo{
@@ -108,22 +167,114 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 	currentCluster = d.clusters[keyName]
 	currentCluster.peers[podName] = struct{}{}
 
-	// Should take failover replicas into consideration
-	if len(currentCluster.peers) == int(tc.PDStsDesiredReplicas()) && tc.Spec.Cluster == nil {
+	// Should take failover replicas into consideration, the learners join after the cluster is bootstrapped
+	if len(currentCluster.peers) == int(tc.PDStsDesiredReplicas()-tc.PDLearnerReplicas()) && tc.Spec.Cluster == nil {
 		delete(currentCluster.peers, podName)
-		pdAddresses := tc.Spec.PDAddresses
-		// Join an existing PD cluster if tc.Spec.PDAddresses is set
-		if len(pdAddresses) != 0 {
-			return fmt.Sprintf("--join=%s", strings.Join(pdAddresses, ",")), nil
+		return initialClusterArgs(tc, podName, advertisePeerUrl), nil
+	}
+
+	membersInfo, err := d.getPDMembers(keyName, tc)
+	if err != nil {
+		return "", err
+	}
+	delete(currentCluster.peers, podName)
+	return joinArgs(membersInfo, podName, strArr[0]), nil
+}
+
+// initialClusterArgs returns the args for the PD member to bootstrap the PD cluster
+func initialClusterArgs(tc *v1alpha1.TidbCluster, podName, advertisePeerUrl string) string {
+	pdAddresses := tc.Spec.PDAddresses
+	// Join an existing PD cluster if tc.Spec.PDAddresses is set
+	if len(pdAddresses) != 0 {
+		return fmt.Sprintf("--join=%s", strings.Join(pdAddresses, ","))
+	}
+	// Initialize the PD cluster with the FQDN format service record if deploy across k8s or tc.Spec.ClusterDomain is set
+	if tc.AcrossK8s() || tc.Spec.ClusterDomain != "" {
+		host := strings.Split(advertisePeerUrl, ":")[0]
+		return fmt.Sprintf("--initial-cluster=%s=%s://%s", host, tc.Scheme(), advertisePeerUrl)
+	}
+	// Initialize the PD cluster in the normal format service record.
+	return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, tc.Scheme(), advertisePeerUrl)
+}
+
+// learnerArgs adds the PD member to the PD cluster as a learner if it's not a member yet, and returns the
+// args for it to start as an existing member, with which the start script persists the initial cluster
+// into the join file of PD instead of joining by the member add of PD.
+func (d *tidbDiscovery) learnerArgs(tc *v1alpha1.TidbCluster, podName, advertisePeerUrl string) (string, error) {
+	ns := tc.GetNamespace()
+	etcdClient, err := d.pdControl.GetPDEtcdClient(pdapi.Namespace(ns), tc.GetName(), tc.IsTLSClusterEnabled(), pdapi.ClusterRef(tc.Spec.ClusterDomain))
+	if err != nil {
+		return "", err
+	}
+	defer etcdClient.Close()
+	members, err := etcdClient.ListMembers()
+	if err != nil {
+		return "", err
+	}
+
+	peerURL := fmt.Sprintf("%s://%s", tc.Scheme(), advertisePeerUrl)
+	name := podName
+	if tc.AcrossK8s() || tc.Spec.ClusterDomain != "" {
+		name = strings.Split(advertisePeerUrl, ":")[0]
+	}
+	var self *pdapi.EtcdMember
+	for _, member := range members {
+		for _, url := range member.PeerURLs {
+			if url == peerURL {
+				self = member
+			}
+		}
+	}
+	if self == nil {
+		self, err = etcdClient.AddLearner(peerURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to add %s as a learner of the pd cluster %s/%s: %v", peerURL, ns, tc.GetName(), err)
+		}
+		klog.Infof("added %s as a learner of the pd cluster %s/%s", peerURL, ns, tc.GetName())
+		members = append(members, self)
+	}
+
+	initialCluster := make([]string, 0, len(members))
+	for _, member := range members {
+		memberName := member.Name
+		if member.ID == self.ID {
+			memberName = name
+		}
+		// skip the other members not started yet
+		if memberName == "" {
+			continue
 		}
-		// Initialize the PD cluster with the FQDN format service record if deploy across k8s or tc.Spec.ClusterDomain is set
-		if tc.AcrossK8s() || tc.Spec.ClusterDomain != "" {
-			return fmt.Sprintf("--initial-cluster=%s=%s://%s", strArr[0], tc.Scheme(), advertisePeerUrl), nil
+		for _, url := range member.PeerURLs {
+			initialCluster = append(initialCluster, fmt.Sprintf("%s=%s", memberName, url))
 		}
-		// Initialize the PD cluster in the normal format service record.
-		return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, tc.Scheme(), advertisePeerUrl), nil
 	}
+	return fmt.Sprintf("--initial-cluster-existing=%s", strings.Join(initialCluster, ",")), nil
+}
+
+// isBootstrapPDMember returns whether the PD member bootstraps the PD cluster when discovery is stateless,
+// which is the member with the lowest ordinal before the cluster is bootstrapped
+func isBootstrapPDMember(tc *v1alpha1.TidbCluster, podName string) bool {
+	if tc.Spec.Cluster != nil || tc.Status.ClusterID != "" || len(tc.Status.PD.Members) != 0 {
+		return false
+	}
+	ordinals := tc.PDStsDesiredOrdinals(true).List()
+	if len(ordinals) == 0 {
+		return false
+	}
+	return podName == fmt.Sprintf("%s-%d", controller.PDMemberName(tc.Name), ordinals[0])
+}
 
+// getPDMembers returns the members of the PD cluster, which are cached for membersCacheTTL
+func (d *tidbDiscovery) getPDMembers(keyName string, tc *v1alpha1.TidbCluster) (*pdapi.MembersInfo, error) {
+	if d.membersCacheTTL > 0 {
+		if cached, ok := d.membersCache[keyName]; ok && d.now().Before(cached.expireAt) {
+			membersCacheRequests.WithLabelValues(cacheHit).Inc()
+			return cached.members, nil
+		}
+		membersCacheRequests.WithLabelValues(cacheMiss).Inc()
+	}
+
+	ns := tc.GetNamespace()
 	var pdClients []pdapi.PDClient
 
 	if tc.Spec.PD != nil {
@@ -142,6 +293,7 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 				pdapi.TLSCertFromTC(pdapi.Namespace(tc.GetNamespace()), tc.GetName()),
 				pdapi.ClusterRef(tc.Spec.Cluster.ClusterDomain),
 				pdapi.UseHeadlessService(tc.Spec.AcrossK8s),
+				pdapi.ResolveWith(tc.Spec.AcrossK8sResolver),
 			),
 		)
 	}
@@ -150,7 +302,10 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		pdClients = append(pdClients, d.pdControl.GetPDClient(pdapi.Namespace(ns), tc.Name, tc.IsTLSClusterEnabled(), pdapi.SpecifyClient(pdMember.ClientURL, pdMember.Name)))
 	}
 
-	var membersInfo *pdapi.MembersInfo
+	var (
+		membersInfo *pdapi.MembersInfo
+		err         error
+	)
 	for _, client := range pdClients {
 		membersInfo, err = client.GetMembers()
 		if err == nil {
@@ -158,9 +313,20 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		}
 	}
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if membersInfo == nil {
+		return nil, fmt.Errorf("no PD client available for %s", keyName)
 	}
 
+	if d.membersCacheTTL > 0 {
+		d.membersCache[keyName] = &cachedMembers{members: membersInfo, expireAt: d.now().Add(d.membersCacheTTL)}
+	}
+	return membersInfo, nil
+}
+
+// joinArgs returns the args for the PD member to join the PD cluster of the members
+func joinArgs(membersInfo *pdapi.MembersInfo, podName, host string) string {
 	membersArr := make([]string, 0)
 	for _, member := range membersInfo.Members {
 		// Corresponds to https://github.com/tikv/pd/blob/43baea981b406df26cd49e8b99cc42354f0a6696/server/join/join.go#L88.
@@ -173,14 +339,13 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		// In some failure situations, for example, delete the pd's data directory, pd will try to restart
 		// and get join info from discovery service. But pd embed etcd may still have the registered member info,
 		// which will return the argument to join pd itself, which is not suggested in pd.
-		if member.Name == podName || member.Name == strArr[0] {
+		if member.Name == podName || member.Name == host {
 			continue
 		}
 		memberURL := strings.ReplaceAll(member.PeerUrls[0], fmt.Sprintf(":%d", v1alpha1.DefaultPDPeerPort), fmt.Sprintf(":%d", v1alpha1.DefaultPDClientPort))
 		membersArr = append(membersArr, memberURL)
 	}
-	delete(currentCluster.peers, podName)
-	return fmt.Sprintf("--join=%s", strings.Join(membersArr, ",")), nil
+	return fmt.Sprintf("--join=%s", strings.Join(membersArr, ","))
 }
 
 func (d *tidbDiscovery) DiscoverDM(advertisePeerUrl string) (string, error) {
@@ -260,7 +425,7 @@ func (d *tidbDiscovery) VerifyPDEndpoint(pdURL string) (string, error) {
 
 	// if local pd doesn't exist, return target cluster pd peer addr
 	if tc.Heterogeneous() && tc.WithoutLocalPD() {
-		addr := controller.PDPeerFullyDomain(tc.Spec.Cluster.Name, tc.Spec.Cluster.Namespace, tc.Spec.Cluster.ClusterDomain)
+		addr := controller.PDPeerAddress(tc)
 		if pdEndpoint.scheme != "" {
 			addr = fmt.Sprintf("%s://%s", pdEndpoint.scheme, addr)
 		}
@@ -19,6 +19,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -535,6 +536,140 @@ func TestDiscoveryDiscovery(t *testing.T) {
 	}
 }
 
+func TestDiscoveryStatelessBootstrap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTC()
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informer := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
+	fakeMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
+	pdClient := pdapi.NewFakePDClient()
+	fakePDControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
+	cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+
+	var members *pdapi.MembersInfo
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		if members == nil {
+			return nil, fmt.Errorf("no members yet")
+		}
+		return members, nil
+	})
+
+	os.Setenv("MY_POD_NAMESPACE", "default")
+	td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli, WithStatelessBootstrap(true))
+
+	// only the first member bootstraps the cluster
+	_, err := td.Discover("demo-pd-1.demo-pd-peer.default.svc:2380")
+	g.Expect(err).To(HaveOccurred())
+	re, err := td.Discover("demo-pd-0.demo-pd-peer.default.svc:2380")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(re).To(Equal("--initial-cluster=demo-pd-0=http://demo-pd-0.demo-pd-peer.default.svc:2380"))
+	g.Expect(td.(*tidbDiscovery).clusters).To(BeEmpty())
+
+	members = &pdapi.MembersInfo{
+		Members: []*pdpb.Member{{Name: "demo-pd-0", PeerUrls: []string{"http://demo-pd-0.demo-pd-peer.default.svc:2380"}}},
+	}
+	re, err = td.Discover("demo-pd-1.demo-pd-peer.default.svc:2380")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(re).To(Equal("--join=http://demo-pd-0.demo-pd-peer.default.svc:2379"))
+
+	// the first member doesn't bootstrap the cluster again after the cluster is bootstrapped
+	members = nil
+	tc.Status.ClusterID = "1"
+	cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Update(context.TODO(), tc, metav1.UpdateOptions{})
+	_, err = td.Discover("demo-pd-0.demo-pd-peer.default.svc:2380")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestDiscoveryPDLearner(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTC()
+	tc.Spec.PD.LearnerReplicas = 1
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informer := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
+	fakeMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
+	etcdClient := pdapi.NewFakePDEtcdClient()
+	etcdClient.Members = []*pdapi.EtcdMember{
+		{ID: 1, Name: "demo-pd-0", PeerURLs: []string{"http://demo-pd-0.demo-pd-peer.default.svc:2380"}},
+		{ID: 2, Name: "demo-pd-1", PeerURLs: []string{"http://demo-pd-1.demo-pd-peer.default.svc:2380"}},
+		{ID: 3, Name: "demo-pd-2", PeerURLs: []string{"http://demo-pd-2.demo-pd-peer.default.svc:2380"}},
+	}
+	fakePDControl.SetPDEtcdClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), etcdClient)
+	cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+
+	os.Setenv("MY_POD_NAMESPACE", "default")
+	td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli)
+
+	expected := "--initial-cluster-existing=demo-pd-0=http://demo-pd-0.demo-pd-peer.default.svc:2380," +
+		"demo-pd-1=http://demo-pd-1.demo-pd-peer.default.svc:2380," +
+		"demo-pd-2=http://demo-pd-2.demo-pd-peer.default.svc:2380," +
+		"demo-pd-3=http://demo-pd-3.demo-pd-peer.default.svc:2380"
+	re, err := td.Discover("demo-pd-3.demo-pd-peer.default.svc:2380")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(re).To(Equal(expected))
+	g.Expect(etcdClient.Members).To(HaveLen(4))
+	g.Expect(etcdClient.Members[3].IsLearner).To(BeTrue())
+
+	// the learner is not added again when it restarts before it joins
+	re, err = td.Discover("demo-pd-3.demo-pd-peer.default.svc:2380")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(re).To(Equal(expected))
+	g.Expect(etcdClient.Members).To(HaveLen(4))
+
+	// the learner doesn't join the pd cluster of a version not supporting learners
+	tc.Spec.Version = "v6.5.0"
+	_, err = cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Update(context.TODO(), tc, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = td.Discover("demo-pd-3.demo-pd-peer.default.svc:2380")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(etcdClient.Members).To(HaveLen(4))
+}
+
+func TestDiscoveryMembersCache(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTC()
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informer := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
+	fakeMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
+	pdClient := pdapi.NewFakePDClient()
+	fakePDControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
+	cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+
+	calls := 0
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		calls++
+		return &pdapi.MembersInfo{
+			Members: []*pdpb.Member{{Name: "demo-pd-0", PeerUrls: []string{"http://demo-pd-0.demo-pd-peer.default.svc:2380"}}},
+		}, nil
+	})
+
+	os.Setenv("MY_POD_NAMESPACE", "default")
+	td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli, WithMembersCacheTTL(10*time.Second))
+	now := time.Now()
+	td.(*tidbDiscovery).now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		re, err := td.Discover("demo-pd-1.demo-pd-peer.default.svc:2380")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(re).To(Equal("--join=http://demo-pd-0.demo-pd-peer.default.svc:2379"))
+	}
+	g.Expect(calls).To(Equal(1))
+
+	// the members are fetched again after the cache expires
+	now = now.Add(11 * time.Second)
+	_, err := td.Discover("demo-pd-1.demo-pd-peer.default.svc:2380")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(calls).To(Equal(2))
+}
+
 func TestDiscoveryDMDiscovery(t *testing.T) {
 	g := NewGomegaWithT(t)
 
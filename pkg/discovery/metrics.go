@@ -0,0 +1,48 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+)
+
+var (
+	// RequestsTotal is the number of discovery requests, labeled by the type and the result
+	RequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_discovery",
+			Name:      "requests_total",
+			Help:      "Total number of requests served by discovery",
+		}, []string{"type", "result"})
+
+	membersCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_discovery",
+			Subsystem: "members_cache",
+			Name:      "requests_total",
+			Help:      "Total number of lookups of the cached PD members",
+		}, []string{"result"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		RequestsTotal,
+		membersCacheRequests,
+	)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
@@ -36,9 +37,9 @@ type server struct {
 }
 
 // NewServer creates a new server.
-func NewServer(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface) Server {
+func NewServer(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface, opts ...discovery.Option) Server {
 	s := &server{
-		discovery: discovery.NewTiDBDiscovery(pdControl, masterControl, cli, kubeCli),
+		discovery: discovery.NewTiDBDiscovery(pdControl, masterControl, cli, kubeCli, opts...),
 		container: restful.NewContainer(),
 	}
 	s.registerHandlers()
@@ -50,7 +51,15 @@ func (s *server) registerHandlers() {
 	ws.Route(ws.GET("/new/{advertise-peer-url}").To(s.newHandler))
 	ws.Route(ws.GET("/new/{advertise-peer-url}/{register-type}").To(s.newHandler))
 	ws.Route(ws.GET("/verify/{pd-url}").To(s.newVerifyHandler))
+	ws.Route(ws.GET("/health").To(s.healthHandler))
 	s.container.Add(ws)
+	s.container.ServeMux.Handle("/metrics", promhttp.Handler())
+}
+
+func (s *server) healthHandler(req *restful.Request, resp *restful.Response) {
+	if _, err := io.WriteString(resp, "ok"); err != nil {
+		klog.Errorf("failed to writeString: %v", err)
+	}
 }
 
 func (s *server) ListenAndServe(addr string) {
@@ -88,6 +97,7 @@ func (s *server) newHandler(req *restful.Request, resp *restful.Response) {
 		return
 	}
 	if err != nil {
+		discovery.RequestsTotal.WithLabelValues(registerType, "error").Inc()
 		klog.Errorf("failed to discover: %s, %v, register-type is: %s", advertisePeerURL, err, registerType)
 		if werr := resp.WriteError(http.StatusInternalServerError, err); werr != nil {
 			klog.Errorf("failed to writeError: %v", werr)
@@ -95,6 +105,7 @@ func (s *server) newHandler(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
+	discovery.RequestsTotal.WithLabelValues(registerType, "success").Inc()
 	klog.Infof("generated args for %s: %s, register-type: %s", advertisePeerURL, result, registerType)
 	if _, err := io.WriteString(resp, result); err != nil {
 		klog.Errorf("failed to writeString: %s, %v", result, err)
@@ -118,12 +129,15 @@ func (s *server) newVerifyHandler(req *restful.Request, resp *restful.Response)
 	var result string
 	result, err = s.discovery.VerifyPDEndpoint(pdPeerURL)
 	if err != nil {
+		discovery.RequestsTotal.WithLabelValues("verify", "error").Inc()
 		klog.Errorf("failed to verify pd-url: %s, %v", pdPeerURL, err)
 		if werr := resp.WriteError(http.StatusInternalServerError, err); werr != nil {
 			klog.Errorf("failed to writeError: %v", werr)
 		}
 		// Return default value if verification failed
 		result = pdPeerURL
+	} else {
+		discovery.RequestsTotal.WithLabelValues("verify", "success").Inc()
 	}
 
 	klog.Infof("return pd-url for %s: %s", pdPeerURL, result)
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"golang.org/x/sync/errgroup"
@@ -151,6 +152,124 @@ func TestServer(t *testing.T) {
 	}
 }
 
+func TestStatelessServer(t *testing.T) {
+	os.Setenv("MY_POD_NAMESPACE", "default")
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informer := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
+	fakeMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
+	pdClient := pdapi.NewFakePDClient()
+
+	// the pd members register to different replicas of discovery
+	var httpServers []*httptest.Server
+	for i := 0; i < 2; i++ {
+		s := NewServer(fakePDControl, fakeMasterControl, cli, kubeCli, discovery.WithStatelessBootstrap(true))
+		httpServer := httptest.NewServer(s.(*server).container.ServeMux)
+		defer httpServer.Close()
+		httpServers = append(httpServers, httpServer)
+	}
+
+	var lock sync.RWMutex
+	pdMemberInfos := &pdapi.MembersInfo{
+		Members: []*pdpb.Member{},
+	}
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		lock.RLock()
+		defer lock.RUnlock()
+		if len(pdMemberInfos.Members) <= 0 {
+			return nil, fmt.Errorf("no members yet")
+		}
+		ret := *pdMemberInfos
+		return &ret, nil
+	})
+	cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	fakePDControl.SetPDClient(pdapi.Namespace(tc.Namespace), tc.Name, pdClient)
+
+	var (
+		initial int32
+		join    int32
+	)
+
+	errg, _ := errgroup.WithContext(context.Background())
+
+	for i := 0; i < 3; i++ {
+		i := i
+		errg.Go(func() error {
+			for {
+				svc := fmt.Sprintf(`foo-pd-%d.foo-pd-peer.default.svc:2380`, i)
+				url := httpServers[i%2].URL + fmt.Sprintf("/new/%s", base64.StdEncoding.EncodeToString([]byte(svc)))
+				resp, err := http.Get(url)
+				if err != nil {
+					return err
+				}
+				if resp.StatusCode != http.StatusOK {
+					time.Sleep(time.Millisecond * 100)
+					continue
+				}
+				data, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+				lock.Lock()
+				pdMemberInfos.Members = append(pdMemberInfos.Members, &pdpb.Member{
+					Name: svc,
+					PeerUrls: []string{
+						svc,
+					},
+				})
+				lock.Unlock()
+				if strings.HasPrefix(string(data), "--join=") {
+					atomic.AddInt32(&join, 1)
+				} else if strings.HasPrefix(string(data), "--initial-cluster=foo-pd-0=") {
+					atomic.AddInt32(&initial, 1)
+				}
+				return nil
+			}
+		})
+	}
+
+	err := errg.Wait()
+	if err != nil {
+		t.Errorf("get pd info failed: %v", err)
+	}
+
+	if initial != 1 {
+		t.Errorf("initial expects 1, got %d", initial)
+	}
+	if join != 2 {
+		t.Errorf("join expects 2, got %d", join)
+	}
+}
+
+func TestHealthAndMetrics(t *testing.T) {
+	kubeCli := kubefake.NewSimpleClientset()
+	informer := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
+	fakeMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
+	s := NewServer(fakePDControl, fakeMasterControl, fake.NewSimpleClientset(), kubeCli)
+	httpServer := httptest.NewServer(s.(*server).container.ServeMux)
+	defer httpServer.Close()
+
+	for _, path := range []string{"/health", "/metrics"} {
+		resp, err := http.Get(httpServer.URL + path)
+		if err != nil {
+			t.Fatalf("get %s failed: %v", path, err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("read %s failed: %v", path, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s expects status 200, got %d", path, resp.StatusCode)
+		}
+		if path == "/health" && string(data) != "ok" {
+			t.Errorf("%s expects ok, got %s", path, data)
+		}
+	}
+}
+
 func TestDMServer(t *testing.T) {
 	os.Setenv("MY_POD_NAMESPACE", "default")
 	cli := fake.NewSimpleClientset()
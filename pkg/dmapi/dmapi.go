@@ -14,10 +14,12 @@
 package dmapi
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
@@ -36,11 +38,18 @@ type MasterClient interface {
 	EvictLeader() error
 	DeleteMaster(name string) error
 	DeleteWorker(name string) error
+	// GetSources returns all the sources through the OpenAPI of dm-master
+	GetSources() ([]*SourceInfo, error)
+	// UpdateSourcePurge updates the purge policy of the relay logs of the source through the OpenAPI of dm-master
+	UpdateSourcePurge(name string, purge SourcePurge) error
+	// UpdateSourceSecurity updates the TLS config to connect to the upstream database of the source through the OpenAPI of dm-master
+	UpdateSourceSecurity(name string, security SourceSecurity) error
 }
 
 var (
 	membersPrefix = "apis/v1alpha1/members"
 	leaderPrefix  = "apis/v1alpha1/leader"
+	sourcesPrefix = "api/v1/sources"
 )
 
 type RespHeader struct {
@@ -106,6 +115,36 @@ type LeaderResp struct {
 	ListMemberResp []*ListMemberLeader `json:"members,omitempty"`
 }
 
+// SourcePurge is the purge policy of the relay logs of a source
+type SourcePurge struct {
+	// Interval is the interval in seconds between the purges
+	Interval *int64 `json:"interval,omitempty"`
+	// Expires is the hours the relay logs are kept for, 0 means never expired
+	Expires *int64 `json:"expires,omitempty"`
+	// RemainSpace is the free disk space in GB below which the relay logs are purged
+	RemainSpace *int64 `json:"remain_space,omitempty"`
+}
+
+// SourceSecurity is the TLS config to connect to the upstream database of a source
+type SourceSecurity struct {
+	SSLCAContent   string   `json:"ssl_ca_content"`
+	SSLCertContent string   `json:"ssl_cert_content"`
+	SSLKeyContent  string   `json:"ssl_key_content"`
+	CertAllowedCN  []string `json:"cert_allowed_cn,omitempty"`
+}
+
+// SourceInfo is a source returned by the OpenAPI of dm-master
+type SourceInfo struct {
+	SourceName string          `json:"source_name"`
+	Purge      *SourcePurge    `json:"purge,omitempty"`
+	Security   *SourceSecurity `json:"security,omitempty"`
+}
+
+type SourcesResp struct {
+	Total int           `json:"total"`
+	Data  []*SourceInfo `json:"data"`
+}
+
 // masterClient is default implementation of MasterClient
 type masterClient struct {
 	url        string
@@ -225,6 +264,55 @@ func (c *masterClient) DeleteWorker(name string) error {
 	return c.deleteMember(query)
 }
 
+func (c *masterClient) GetSources() ([]*SourceInfo, error) {
+	apiURL := fmt.Sprintf("%s/%s", c.url, sourcesPrefix)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	sourcesResp := &SourcesResp{}
+	err = json.Unmarshal(body, sourcesResp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal list sources resp: %s, err: %s", body, err)
+	}
+	return sourcesResp.Data, nil
+}
+
+func (c *masterClient) UpdateSourcePurge(name string, purge SourcePurge) error {
+	if err := c.updateSource(name, "purge", purge); err != nil {
+		return fmt.Errorf("unable to update the purge policy of source %s, err: %s", name, err)
+	}
+	return nil
+}
+
+func (c *masterClient) UpdateSourceSecurity(name string, security SourceSecurity) error {
+	if err := c.updateSource(name, "security", security); err != nil {
+		return fmt.Errorf("unable to update the security of source %s, err: %s", name, err)
+	}
+	return nil
+}
+
+// updateSource sets the field of the source to the value
+func (c *masterClient) updateSource(name, field string, value interface{}) error {
+	apiURL := fmt.Sprintf("%s/%s/%s", c.url, sourcesPrefix, url.PathEscape(name))
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return err
+	}
+	// the source is updated as a whole, so the fields unknown to the operator are kept as they are
+	source := map[string]interface{}{}
+	if err := json.Unmarshal(body, &source); err != nil {
+		return fmt.Errorf("unable to unmarshal source resp: %s, err: %s", body, err)
+	}
+	source[field] = value
+	data, err := json.Marshal(map[string]interface{}{"source": source})
+	if err != nil {
+		return err
+	}
+	_, err = httputil.DoBodyOK(c.httpClient, apiURL, http.MethodPut, bytes.NewReader(data))
+	return err
+}
+
 // NewMasterClient returns a new MasterClient
 func NewMasterClient(url string, timeout time.Duration, tlsConfig *tls.Config, disableKeepalive bool) MasterClient {
 	return &masterClient{
@@ -20,12 +20,15 @@ import (
 type ActionType string
 
 const (
-	GetMastersActionType   ActionType = "GetMasters"
-	GetWorkersActionType   ActionType = "GetWorkers"
-	GetLeaderActionType    ActionType = "GetLeader"
-	EvictLeaderActionType  ActionType = "EvictLeader"
-	DeleteMasterActionType ActionType = "DeleteMaster"
-	DeleteWorkerActionType ActionType = "DeleteWorker"
+	GetMastersActionType           ActionType = "GetMasters"
+	GetWorkersActionType           ActionType = "GetWorkers"
+	GetLeaderActionType            ActionType = "GetLeader"
+	EvictLeaderActionType          ActionType = "EvictLeader"
+	DeleteMasterActionType         ActionType = "DeleteMaster"
+	DeleteWorkerActionType         ActionType = "DeleteWorker"
+	GetSourcesActionType           ActionType = "GetSources"
+	UpdateSourcePurgeActionType    ActionType = "UpdateSourcePurge"
+	UpdateSourceSecurityActionType ActionType = "UpdateSourceSecurity"
 )
 
 type NotFoundReaction struct {
@@ -37,9 +40,11 @@ func (nfr *NotFoundReaction) Error() string {
 }
 
 type Action struct {
-	ID     uint64
-	Name   string
-	Labels map[string]string
+	ID       uint64
+	Name     string
+	Labels   map[string]string
+	Purge    SourcePurge
+	Security SourceSecurity
 }
 
 type Reaction func(action *Action) (interface{}, error)
@@ -113,3 +118,24 @@ func (c *FakeMasterClient) DeleteWorker(_ string) error {
 	_, err := c.fakeAPI(DeleteWorkerActionType, action)
 	return err
 }
+
+func (c *FakeMasterClient) GetSources() ([]*SourceInfo, error) {
+	action := &Action{}
+	result, err := c.fakeAPI(GetSourcesActionType, action)
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*SourceInfo), nil
+}
+
+func (c *FakeMasterClient) UpdateSourcePurge(name string, purge SourcePurge) error {
+	action := &Action{Name: name, Purge: purge}
+	_, err := c.fakeAPI(UpdateSourcePurgeActionType, action)
+	return err
+}
+
+func (c *FakeMasterClient) UpdateSourceSecurity(name string, security SourceSecurity) error {
+	action := &Action{Name: name, Security: security}
+	_, err := c.fakeAPI(UpdateSourceSecurityActionType, action)
+	return err
+}
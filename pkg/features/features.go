@@ -32,6 +32,7 @@ var (
 		AdvancedStatefulSet: false,
 		VolumeModifying:     false,
 		VolumeReplacing:     false,
+		SlowStoreMitigation: false,
 	}
 	// DefaultFeatureGate is a shared global FeatureGate.
 	DefaultFeatureGate FeatureGate = NewDefaultFeatureGate()
@@ -51,6 +52,10 @@ const (
 	// VolumeReplacing controls whether to replace whole volumes by deleting and recreating on changes.
 	// tidb, tikv & pd supported. If enabled takes precedence over resizing/modifying.
 	VolumeReplacing string = "VolumeReplacing"
+
+	// SlowStoreMitigation controls whether to mitigate the slow TiKV stores by spec.tikv.slowStoreDetection.mitigation,
+	// the slow stores are only flagged in the status if it's disabled
+	SlowStoreMitigation string = "SlowStoreMitigation"
 )
 
 type FeatureGate interface {
@@ -77,6 +77,9 @@ func (bm *backupManager) Sync(volumeBackup *v1alpha1.VolumeBackup) error {
 	}
 
 	backupFinished, err := bm.runBackup(ctx, volumeBackup, backupMembers)
+	if v1alpha1.IsVolumeBackupInvalid(volumeBackup) {
+		return nil
+	}
 	if err != nil {
 		if _, ok := err.(*fedvolumebackup.BRDataPlaneFailedError); !ok {
 			return err
@@ -128,6 +131,11 @@ func (bm *backupManager) runBackup(ctx context.Context, volumeBackup *v1alpha1.V
 	}
 
 	if len(backupMembers) == 0 {
+		if err := validateCoordination(volumeBackup.Spec.Coordination); err != nil {
+			klog.Errorf("VolumeBackup %s/%s is invalid, err: %s", volumeBackup.Namespace, volumeBackup.Name, err.Error())
+			bm.setVolumeBackupInvalid(&volumeBackup.Status, reasonVolumeBackupInvalidCoordination, err.Error())
+			return false, nil
+		}
 		return false, bm.initializeVolumeBackup(ctx, volumeBackup)
 	}
 
@@ -136,6 +144,14 @@ func (bm *backupManager) runBackup(ctx context.Context, volumeBackup *v1alpha1.V
 		return false, err
 	}
 
+	coordinated, err := bm.coordinateTs(ctx, volumeBackup)
+	if err != nil {
+		return false, err
+	}
+	if coordinated {
+		return false, nil
+	}
+
 	newMemberCreatedOrUpdated, err := bm.executeVolumeBackup(ctx, volumeBackup, backupMembers)
 	if err != nil {
 		return false, err
@@ -458,6 +474,10 @@ func (bm *backupManager) waitVolumeBackupComplete(ctx context.Context, volumeBac
 		errMsg := genErrorMessageByFailedBackupMembers(failedBackups)
 		bm.setVolumeBackupFailed(&volumeBackup.Status, backupMembers, reasonVolumeBackupMemberFailed, errMsg)
 		return nil
+	} else if err := verifyCoordinatedTs(volumeBackup, backupMembers); err != nil {
+		bm.setVolumeBackupFailed(&volumeBackup.Status, backupMembers, reasonVolumeBackupInconsistent, err.Error())
+		klog.Errorf("VolumeBackup %s/%s failed, err: %s", volumeBackup.Namespace, volumeBackup.Name, err.Error())
+		return nil
 	} else {
 		klog.Infof("VolumeBackup %s/%s backup complete", volumeBackup.Namespace, volumeBackup.Name)
 		return bm.setVolumeBackupComplete(&volumeBackup.Status, backupMembers)
@@ -497,6 +517,15 @@ func (bm *backupManager) setVolumeBackupFailed(volumeBackupStatus *v1alpha1.Volu
 	})
 }
 
+func (bm *backupManager) setVolumeBackupInvalid(volumeBackupStatus *v1alpha1.VolumeBackupStatus, reason, message string) {
+	v1alpha1.UpdateVolumeBackupCondition(volumeBackupStatus, &v1alpha1.VolumeBackupCondition{
+		Type:    v1alpha1.VolumeBackupInvalid,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
 func (bm *backupManager) setVolumeBackupCleaned(volumeBackupStatus *v1alpha1.VolumeBackupStatus) {
 	v1alpha1.UpdateVolumeBackupCondition(volumeBackupStatus, &v1alpha1.VolumeBackupCondition{
 		Type:   v1alpha1.VolumeBackupCleaned,
@@ -585,7 +614,8 @@ func (bm *backupManager) buildBackupMember(volumeBackupName string, clusterMembe
 }
 
 func (bm *backupManager) skipSync(volumeBackup *v1alpha1.VolumeBackup) bool {
-	return volumeBackup.DeletionTimestamp == nil && (v1alpha1.IsVolumeBackupComplete(volumeBackup) || v1alpha1.IsVolumeBackupFailed(volumeBackup))
+	return volumeBackup.DeletionTimestamp == nil && (v1alpha1.IsVolumeBackupComplete(volumeBackup) ||
+		v1alpha1.IsVolumeBackupFailed(volumeBackup) || v1alpha1.IsVolumeBackupInvalid(volumeBackup))
 }
 
 func (bm *backupManager) generateBackupMemberName(volumeBackupName string) string {
@@ -15,7 +15,10 @@ package backup
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1"
@@ -529,3 +532,104 @@ func generateVolumeBackup(backupName, backupNamespace string) *v1alpha1.VolumeBa
 		},
 	}
 }
+
+func TestVolumeBackupCoordination(t *testing.T) {
+	ctx := context.Background()
+
+	runCoordinatedBackup := func(h *helper, coordination *v1alpha1.VolumeBackupCoordination) *v1alpha1.VolumeBackup {
+		volumeBackup := generateVolumeBackup(h.backupName, h.backupNamespace)
+		volumeBackup.Spec.Coordination = coordination
+		volumeBackup, err := h.deps.Clientset.FederationV1alpha1().VolumeBackups(h.backupNamespace).Create(ctx, volumeBackup, metav1.CreateOptions{})
+		h.g.Expect(err).To(gomega.BeNil())
+
+		// run initialize phase
+		err = h.bm.Sync(volumeBackup)
+		h.g.Expect(err).To(gomega.BeNil())
+		h.assertRunInitialize(ctx, volumeBackup)
+
+		// initialized, take the coordinated ts before executing the volume snapshots
+		h.setDataPlaneInitialized(ctx)
+		err = h.bm.Sync(volumeBackup)
+		h.g.Expect(err).To(gomega.BeNil())
+		h.g.Expect(volumeBackup.Status.CoordinatedTs).NotTo(gomega.BeEmpty())
+		h.g.Expect(volumeBackup.Status.Backups).To(gomega.HaveLen(1))
+
+		// run execute phase
+		err = h.bm.Sync(volumeBackup)
+		h.g.Expect(err).To(gomega.BeNil())
+		h.assertRunExecute(ctx, volumeBackup)
+
+		h.setDataPlaneSnapshotCreated(ctx)
+		err = h.bm.Sync(volumeBackup)
+		h.g.Expect(err).To(gomega.BeNil())
+		h.setDataPlaneInitializeComplete(ctx)
+		err = h.bm.Sync(volumeBackup)
+		h.g.Expect(err).To(gomega.HaveOccurred())
+		h.setDataPlaneVolumeComplete(ctx)
+		err = h.bm.Sync(volumeBackup)
+		h.g.Expect(err).To(gomega.BeNil())
+		h.assertRunTeardown(ctx, volumeBackup, false)
+
+		h.setDataPlaneComplete(ctx)
+		err = h.bm.Sync(volumeBackup)
+		h.g.Expect(err).To(gomega.BeNil())
+		return volumeBackup
+	}
+
+	// the commit ts of the members are not earlier than the ts of the external timestamp service
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("100\n"))
+	}))
+	defer server.Close()
+	h := newHelper(t, "backup-coordinated", "ns-1")
+	volumeBackup := runCoordinatedBackup(h, &v1alpha1.VolumeBackupCoordination{
+		TSOSource:   v1alpha1.VolumeBackupTSOSourceExternal,
+		ExternalTSO: &v1alpha1.ExternalTSOService{URL: server.URL},
+	})
+	h.g.Expect(volumeBackup.Status.CoordinatedTs).To(gomega.Equal("100"))
+	h.assertComplete(volumeBackup)
+
+	// the commit ts of the members are much earlier than the ts chosen by the control plane
+	h = newHelper(t, "backup-inconsistent", "ns-1")
+	volumeBackup = runCoordinatedBackup(h, &v1alpha1.VolumeBackupCoordination{
+		MaxClockSkew: &metav1.Duration{Duration: time.Minute},
+	})
+	h.assertFailed(volumeBackup)
+	_, cond := v1alpha1.GetVolumeBackupCondition(&volumeBackup.Status, v1alpha1.VolumeBackupFailed)
+	h.g.Expect(cond.Reason).To(gomega.Equal(reasonVolumeBackupInconsistent))
+
+	// the external timestamp service is required
+	h = newHelper(t, "backup-invalid", "ns-1")
+	volumeBackup = generateVolumeBackup(h.backupName, h.backupNamespace)
+	volumeBackup.Spec.Coordination = &v1alpha1.VolumeBackupCoordination{TSOSource: v1alpha1.VolumeBackupTSOSourceExternal}
+	h.g.Expect(h.bm.Sync(volumeBackup)).To(gomega.Succeed())
+	h.g.Expect(v1alpha1.IsVolumeBackupInvalid(volumeBackup)).To(gomega.BeTrue())
+	h.g.Expect(volumeBackup.Status.Backups).To(gomega.BeEmpty())
+}
+
+func TestRequestExternalTSO(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tso":
+			_, _ = w.Write([]byte("449835252449837057"))
+		case "/invalid":
+			_, _ = w.Write([]byte("now"))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ts, err := requestExternalTSO(ctx, &v1alpha1.ExternalTSOService{URL: server.URL + "/tso"})
+	g.Expect(err).To(gomega.Succeed())
+	g.Expect(ts).To(gomega.Equal(uint64(449835252449837057)))
+
+	_, err = requestExternalTSO(ctx, &v1alpha1.ExternalTSOService{URL: server.URL + "/invalid"})
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	_, err = requestExternalTSO(ctx, &v1alpha1.ExternalTSOService{URL: server.URL + "/unknown"})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+)
+
+const (
+	reasonVolumeBackupInvalidCoordination = "InvalidCoordination"
+	reasonVolumeBackupInconsistent        = "VolumeBackupInconsistent"
+	// maxTSOResponseSize is the max size of the response of the external timestamp service read
+	maxTSOResponseSize = 64
+)
+
+// validateCoordination checks the external timestamp service is set if it's the TSO source
+func validateCoordination(coordination *v1alpha1.VolumeBackupCoordination) error {
+	if coordination == nil {
+		return nil
+	}
+	switch coordination.GetTSOSource() {
+	case v1alpha1.VolumeBackupTSOSourceControlPlane:
+	case v1alpha1.VolumeBackupTSOSourceExternal:
+		if coordination.ExternalTSO == nil || coordination.ExternalTSO.URL == "" {
+			return fmt.Errorf("spec.coordination.externalTSO.url must be set for the TSO source %s", v1alpha1.VolumeBackupTSOSourceExternal)
+		}
+		if timeout := coordination.ExternalTSO.Timeout; timeout != nil && timeout.Duration <= 0 {
+			return fmt.Errorf("spec.coordination.externalTSO.timeout must be positive")
+		}
+	default:
+		return fmt.Errorf("unsupported TSO source %s in spec.coordination", coordination.TSOSource)
+	}
+	if coordination.MaxClockSkew != nil && coordination.MaxClockSkew.Duration < 0 {
+		return fmt.Errorf("spec.coordination.maxClockSkew must not be negative")
+	}
+	return nil
+}
+
+// coordinateTs takes the timestamp the volume snapshots are coordinated at and records it to the status,
+// it must be called after the GC and the PD schedulers are paused and before any volume snapshot is taken.
+func (bm *backupManager) coordinateTs(ctx context.Context, volumeBackup *v1alpha1.VolumeBackup) (coordinated bool, err error) {
+	coordination := volumeBackup.Spec.Coordination
+	if coordination == nil || volumeBackup.Status.CoordinatedTs != "" {
+		return false, nil
+	}
+
+	var ts uint64
+	switch coordination.GetTSOSource() {
+	case v1alpha1.VolumeBackupTSOSourceExternal:
+		ts, err = requestExternalTSO(ctx, coordination.ExternalTSO)
+		if err != nil {
+			return false, controller.RequeueErrorf("request TSO from external timestamp service %s error: %s", coordination.ExternalTSO.URL, err.Error())
+		}
+	default:
+		ts = config.GoTimeToTS(time.Now())
+	}
+	volumeBackup.Status.CoordinatedTs = strconv.FormatUint(ts, 10)
+	klog.Infof("VolumeBackup %s/%s coordinate volume snapshots at ts %d from %s",
+		volumeBackup.Namespace, volumeBackup.Name, ts, coordination.GetTSOSource())
+	return true, nil
+}
+
+// verifyCoordinatedTs checks the commit ts of every backup member is not earlier than the coordinated ts
+// by more than the tolerated clock skew, i.e. all the data committed before the coordinated ts are backed up
+func verifyCoordinatedTs(volumeBackup *v1alpha1.VolumeBackup, backupMembers []*volumeBackupMember) error {
+	coordination := volumeBackup.Spec.Coordination
+	if coordination == nil {
+		return nil
+	}
+	coordinatedTs, err := strconv.ParseUint(volumeBackup.Status.CoordinatedTs, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse coordinated ts %q error: %s", volumeBackup.Status.CoordinatedTs, err.Error())
+	}
+	earliest := config.TSToGoTime(coordinatedTs).Add(-coordination.GetMaxClockSkew())
+	for _, backupMember := range backupMembers {
+		commitTs, err := strconv.ParseUint(backupMember.backup.Status.CommitTs, 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse commit ts %q of backup member %s of cluster %s error: %s",
+				backupMember.backup.Status.CommitTs, backupMember.backup.Name, backupMember.k8sClusterName, err.Error())
+		}
+		if commitTime := config.TSToGoTime(commitTs); commitTime.Before(earliest) {
+			return fmt.Errorf("commit ts %d of backup member %s of cluster %s is %s earlier than the coordinated ts %d, exceeds the max clock skew %s",
+				commitTs, backupMember.backup.Name, backupMember.k8sClusterName,
+				config.TSToGoTime(coordinatedTs).Sub(commitTime), coordinatedTs, coordination.GetMaxClockSkew())
+		}
+	}
+	return nil
+}
+
+// requestExternalTSO requests a TSO from the external timestamp service
+func requestExternalTSO(ctx context.Context, svc *v1alpha1.ExternalTSOService) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, svc.GetTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, svc.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTSOResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	ts, err := strconv.ParseUint(strings.TrimSpace(string(body)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TSO %q: %s", strings.TrimSpace(string(body)), err.Error())
+	}
+	if ts == 0 {
+		return 0, fmt.Errorf("invalid TSO 0")
+	}
+	return ts, nil
+}
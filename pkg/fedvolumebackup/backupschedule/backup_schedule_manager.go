@@ -14,14 +14,16 @@
 package backupschedule
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/util"
-	"github.com/robfig/cron"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -80,7 +82,7 @@ func getLastScheduledTime(vbs *v1alpha1.VolumeBackupSchedule, nowFn nowFn) (*tim
 	ns := vbs.GetNamespace()
 	bsName := vbs.GetName()
 
-	sched, err := cron.ParseStandard(vbs.Spec.Schedule)
+	sched, err := util.ParseCronSchedule(vbs.Spec.Schedule, vbs.Spec.TimeZone)
 	if err != nil {
 		return nil, fmt.Errorf("parse backup schedule %s/%s cron format %s failed, err: %v", ns, bsName, vbs.Spec.Schedule, err)
 	}
@@ -249,16 +251,24 @@ func (bm *backupScheduleManager) backupGC(vbs *v1alpha1.VolumeBackupSchedule) {
 	ns := vbs.GetNamespace()
 	bsName := vbs.GetName()
 
-	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred.
+	hasMaxBackups := vbs.Spec.MaxBackups != nil && *vbs.Spec.MaxBackups > 0
+	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred
+	// unless the GC policy is CountAndAge.
+	if vbs.Spec.MaxReservedTime != nil && hasMaxBackups && vbs.Spec.GCPolicy == v1alpha1.VolumeBackupScheduleGCPolicyCountAndAge {
+		bm.backupGCByCountAndAge(vbs)
+		return
+	}
+
 	if vbs.Spec.MaxReservedTime != nil {
 		bm.backupGCByMaxReservedTime(vbs)
 		return
 	}
 
-	if vbs.Spec.MaxBackups != nil && *vbs.Spec.MaxBackups > 0 {
+	if hasMaxBackups {
 		bm.backupGCByMaxBackups(vbs)
 		return
 	}
+	vbs.Status.GCProgress = nil
 	klog.Warningf("backup schedule %s/%s does not set backup gc policy", ns, bsName)
 }
 
@@ -266,7 +276,7 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(vbs *v1alpha1.VolumeB
 	ns := vbs.GetNamespace()
 	bsName := vbs.GetName()
 
-	reservedTime, err := time.ParseDuration(*vbs.Spec.MaxReservedTime)
+	reservedTime, err := parseReservedTime(*vbs.Spec.MaxReservedTime)
 	if err != nil {
 		klog.Errorf("backup schedule %s/%s, invalid MaxReservedTime %s", ns, bsName, *vbs.Spec.MaxReservedTime)
 		return
@@ -280,6 +290,7 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(vbs *v1alpha1.VolumeB
 
 	ascBackups := sortSnapshotBackups(backupsList)
 	if len(ascBackups) == 0 {
+		vbs.Status.GCProgress = nil
 		return
 	}
 
@@ -291,26 +302,168 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(vbs *v1alpha1.VolumeB
 		return
 	}
 
+	bm.gcOldestExpiredBackup(vbs, expiredBackups, len(backupsList))
+}
+
+func (bm *backupScheduleManager) backupGCByCountAndAge(vbs *v1alpha1.VolumeBackupSchedule) {
+	ns := vbs.GetNamespace()
+	bsName := vbs.GetName()
+
+	reservedTime, err := parseReservedTime(*vbs.Spec.MaxReservedTime)
+	if err != nil {
+		klog.Errorf("backup schedule %s/%s, invalid MaxReservedTime %s", ns, bsName, *vbs.Spec.MaxReservedTime)
+		return
+	}
+
+	backupsList, err := bm.getBackupList(vbs)
+	if err != nil {
+		klog.Errorf("backupGCByCountAndAge, err: %s", err)
+		return
+	}
+
+	expiredBackups, err := calculateExpiredBackupsByCountAndAge(sortSnapshotBackups(backupsList), reservedTime, int(*vbs.Spec.MaxBackups))
+	if err != nil {
+		klog.Errorf("calculate expired backups by count and age, err: %s", err)
+		return
+	}
+
+	bm.gcOldestExpiredBackup(vbs, expiredBackups, len(backupsList))
+}
+
+// gcOldestExpiredBackup deletes the oldest expired backup. The backup is deleted only when all
+// the data planes confirm that it can be deleted, and the GC progress in every data plane is
+// recorded in the status of the backup schedule.
+func (bm *backupScheduleManager) gcOldestExpiredBackup(vbs *v1alpha1.VolumeBackupSchedule, expiredBackups []*v1alpha1.VolumeBackup, backupCount int) {
+	ns := vbs.GetNamespace()
+	bsName := vbs.GetName()
+
+	if len(expiredBackups) == 0 {
+		vbs.Status.GCProgress = nil
+		return
+	}
+
 	// In order to avoid throttling, we choose to do delete volumebackup one by one.
 	// Delete the oldest expired backup
-	if len(expiredBackups) > 0 {
-		backup := expiredBackups[0]
-		if backup.DeletionTimestamp != nil {
-			klog.Infof("Deletion is ongoing for backup schedule %s/%s, backup %s", ns, bsName, backup.GetName())
-			return
-		} else {
-			if err = bm.deps.FedVolumeBackupControl.DeleteVolumeBackup(backup); err != nil {
-				klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
-				return
-			}
-			klog.Infof("backup schedule %s/%s gc backup %s success", ns, bsName, backup.GetName())
+	backup := expiredBackups[0]
+	progress, confirmed := bm.getDataPlaneGCProgress(backup)
+	vbs.Status.GCProgress = progress
+
+	if backup.DeletionTimestamp != nil {
+		klog.Infof("Deletion is ongoing for backup schedule %s/%s, backup %s", ns, bsName, backup.GetName())
+		return
+	}
+	if !confirmed {
+		klog.Infof("backup schedule %s/%s, not all data planes confirm that backup %s can be deleted, skip gc", ns, bsName, backup.GetName())
+		return
+	}
 
-			if len(expiredBackups) == 1 && len(backupsList) == 1 {
-				// All backups have been deleted, so the last backup information in the backupSchedule should be reset
-				bm.resetLastBackup(vbs)
+	if err := bm.deps.FedVolumeBackupControl.DeleteVolumeBackup(backup); err != nil {
+		klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
+		return
+	}
+	klog.Infof("backup schedule %s/%s gc backup %s success", ns, bsName, backup.GetName())
+
+	if len(expiredBackups) == 1 && backupCount == 1 {
+		// All backups have been deleted, so the last backup information in the backupSchedule should be reset
+		bm.resetLastBackup(vbs)
+	}
+}
+
+// getDataPlaneGCProgress returns the GC progress of the backup in every data plane, and whether
+// all the data planes confirm that the backup can be deleted.
+func (bm *backupScheduleManager) getDataPlaneGCProgress(backup *v1alpha1.VolumeBackup) (*v1alpha1.VolumeBackupGCProgress, bool) {
+	ctx := context.Background()
+	deleting := backup.DeletionTimestamp != nil
+
+	memberStatuses := make(map[string]*v1alpha1.VolumeBackupMemberStatus, len(backup.Status.Backups))
+	for i := range backup.Status.Backups {
+		memberStatuses[backup.Status.Backups[i].K8sClusterName] = &backup.Status.Backups[i]
+	}
+
+	progress := &v1alpha1.VolumeBackupGCProgress{BackupName: backup.GetName()}
+	confirmed := true
+	for _, memberCluster := range backup.Spec.Clusters {
+		k8sClusterName := memberCluster.K8sClusterName
+		status := v1alpha1.VolumeBackupDataPlaneGCStatus{K8sClusterName: k8sClusterName}
+
+		// the backup member which is never created needn't to be deleted
+		noBackupPhase := v1alpha1.VolumeBackupDataPlaneGCConfirmed
+		if deleting {
+			noBackupPhase = v1alpha1.VolumeBackupDataPlaneGCDeleted
+		}
+
+		kubeClient, ok := bm.deps.FedClientset[k8sClusterName]
+		memberStatus, created := memberStatuses[k8sClusterName]
+		switch {
+		case !ok:
+			status.Phase = v1alpha1.VolumeBackupDataPlaneGCUnconfirmed
+			status.Message = fmt.Sprintf("not find kube client of cluster %s", k8sClusterName)
+		case !created:
+			status.Phase = noBackupPhase
+		default:
+			status.BackupName = memberStatus.BackupName
+			backupMember, err := kubeClient.PingcapV1alpha1().Backups(memberCluster.TCNamespace).Get(ctx, memberStatus.BackupName, metav1.GetOptions{})
+			switch {
+			case errors.IsNotFound(err):
+				status.Phase = noBackupPhase
+			case err != nil:
+				status.Phase = v1alpha1.VolumeBackupDataPlaneGCUnconfirmed
+				status.Message = fmt.Sprintf("get backup %s failed, err: %v", memberStatus.BackupName, err)
+			case backupMember.DeletionTimestamp != nil && pingcapv1alpha1.IsBackupCleanFailed(backupMember):
+				status.Phase = v1alpha1.VolumeBackupDataPlaneGCFailed
+				status.Message = "backup clean failed"
+			case backupMember.DeletionTimestamp != nil || deleting:
+				status.Phase = v1alpha1.VolumeBackupDataPlaneGCDeleting
+			case !pingcapv1alpha1.IsBackupComplete(backupMember) && !pingcapv1alpha1.IsBackupFailed(backupMember):
+				status.Phase = v1alpha1.VolumeBackupDataPlaneGCUnconfirmed
+				status.Message = fmt.Sprintf("backup %s is still running", memberStatus.BackupName)
+			default:
+				status.Phase = v1alpha1.VolumeBackupDataPlaneGCConfirmed
 			}
 		}
+
+		if status.Phase == v1alpha1.VolumeBackupDataPlaneGCUnconfirmed {
+			confirmed = false
+		}
+		progress.DataPlanes = append(progress.DataPlanes, status)
+	}
+	return progress, confirmed
+}
+
+// parseReservedTime parses MaxReservedTime, the units of day "d" and week "w" are supported
+// besides the units supported by time.ParseDuration, e.g. "7d", "2w" or "1d12h".
+func parseReservedTime(reservedTime string) (time.Duration, error) {
+	if reservedTime == "" {
+		return 0, fmt.Errorf("empty reserved time")
+	}
+	var duration time.Duration
+	rest := reservedTime
+	for _, u := range []struct {
+		unit     byte
+		duration time.Duration
+	}{
+		{unit: 'w', duration: 7 * 24 * time.Hour},
+		{unit: 'd', duration: 24 * time.Hour},
+	} {
+		i := strings.IndexByte(rest, u.unit)
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid reserved time %s", reservedTime)
+		}
+		duration += time.Duration(n) * u.duration
+		rest = rest[i+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, err
+		}
+		duration += d
 	}
+	return duration, nil
 }
 
 // sortSnapshotBackups return snapshot backups to be GCed order by create time asc
@@ -355,6 +508,23 @@ func calculateExpiredBackups(backupsList []*v1alpha1.VolumeBackup, reservedTime
 	return backupsList[:i], nil
 }
 
+// calculateExpiredBackupsByCountAndAge returns the backups which are neither one of the newest
+// maxBackups backups nor created within reservedTime, the backupsList is ordered by create time asc.
+func calculateExpiredBackupsByCountAndAge(backupsList []*v1alpha1.VolumeBackup, reservedTime time.Duration, maxBackups int) ([]*v1alpha1.VolumeBackup, error) {
+	expiredBackups, err := calculateExpiredBackups(backupsList, reservedTime)
+	if err != nil {
+		return nil, err
+	}
+	maxExpired := len(backupsList) - maxBackups
+	if maxExpired < 0 {
+		maxExpired = 0
+	}
+	if len(expiredBackups) > maxExpired {
+		expiredBackups = expiredBackups[:maxExpired]
+	}
+	return expiredBackups, nil
+}
+
 func (bm *backupScheduleManager) getBackupList(bs *v1alpha1.VolumeBackupSchedule) ([]*v1alpha1.VolumeBackup, error) {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
@@ -383,9 +553,6 @@ func (bm *backupScheduleManager) getBackupList(bs *v1alpha1.VolumeBackupSchedule
 }
 
 func (bm *backupScheduleManager) backupGCByMaxBackups(vbs *v1alpha1.VolumeBackupSchedule) {
-	ns := vbs.GetNamespace()
-	bsName := vbs.GetName()
-
 	backupsList, err := bm.getBackupList(vbs)
 	if err != nil {
 		klog.Errorf("backupGCByMaxBackups failed, err: %s", err)
@@ -394,28 +561,12 @@ func (bm *backupScheduleManager) backupGCByMaxBackups(vbs *v1alpha1.VolumeBackup
 
 	sort.Sort(byCreateTimeDesc(backupsList))
 
-	// In order to avoid throttling, we choose to do delete volumebackup one by one.
-	// Delete the oldest expired backup
+	var expiredBackups []*v1alpha1.VolumeBackup
 	if len(backupsList) > int(*vbs.Spec.MaxBackups) {
-		backup := backupsList[len(backupsList)-1]
-
-		if backup.DeletionTimestamp != nil {
-			klog.Infof("Deletion is ongoing for backup schedule %s/%s, backup %s", ns, bsName, backup.GetName())
-			return
-		}
-
-		if err = bm.deps.FedVolumeBackupControl.DeleteVolumeBackup(backup); err != nil {
-			klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
-			return
-		}
-		klog.Infof("backup schedule %s/%s gc backup %s success", ns, bsName, backup.GetName())
-
-		if len(backupsList) == 1 {
-			// All backups have been deleted, so the last backup information in the backupSchedule should be reset
-			bm.resetLastBackup(vbs)
-		}
-
+		// the oldest backup is GCed first
+		expiredBackups = []*v1alpha1.VolumeBackup{backupsList[len(backupsList)-1]}
 	}
+	bm.gcOldestExpiredBackup(vbs, expiredBackups, len(backupsList))
 }
 
 func (bm *backupScheduleManager) resetLastBackup(vbs *v1alpha1.VolumeBackupSchedule) {
@@ -415,6 +415,152 @@ func TestCalculateExpiredBackups(t *testing.T) {
 	}
 }
 
+func TestCalculateExpiredBackupsByCountAndAge(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var (
+		now       = time.Now()
+		last10Min = now.Add(-time.Minute * 10)
+		last1Day  = now.Add(-time.Hour * 24 * 1)
+		last2Day  = now.Add(-time.Hour * 24 * 2)
+		last3Day  = now.Add(-time.Hour * 24 * 3)
+	)
+	backups := sortSnapshotBackups([]*v1alpha1.VolumeBackup{
+		fakeFailedBackup(&last3Day),
+		fakeBackup(&last2Day),
+		fakeBackup(&last1Day),
+		fakeBackup(&last10Min),
+	})
+
+	testCases := []struct {
+		reservedTime              time.Duration
+		maxBackups                int
+		expectedDeleteBackupCount int
+	}{
+		// the expired backups exceeding max backups are deleted
+		{reservedTime: 24 * time.Hour, maxBackups: 2, expectedDeleteBackupCount: 2},
+		// the newest backups are kept even if they are expired
+		{reservedTime: 24 * time.Hour, maxBackups: 3, expectedDeleteBackupCount: 1},
+		{reservedTime: 24 * time.Hour, maxBackups: 10, expectedDeleteBackupCount: 0},
+		// the backups within reserved time are kept even if they exceed max backups
+		{reservedTime: 60 * time.Hour, maxBackups: 1, expectedDeleteBackupCount: 1},
+	}
+	for _, tc := range testCases {
+		deletedBackups, err := calculateExpiredBackupsByCountAndAge(backups, tc.reservedTime, tc.maxBackups)
+		g.Expect(err).Should(BeNil())
+		g.Expect(len(deletedBackups)).Should(Equal(tc.expectedDeleteBackupCount))
+	}
+}
+
+func TestParseReservedTime(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	testCases := []struct {
+		reservedTime string
+		expected     time.Duration
+		expectedErr  bool
+	}{
+		{reservedTime: "71h", expected: 71 * time.Hour},
+		{reservedTime: "7d", expected: 7 * 24 * time.Hour},
+		{reservedTime: "2w", expected: 14 * 24 * time.Hour},
+		{reservedTime: "1w2d12h30m", expected: 9*24*time.Hour + 12*time.Hour + 30*time.Minute},
+		{reservedTime: "", expectedErr: true},
+		{reservedTime: "1.5d", expectedErr: true},
+		{reservedTime: "1d2x", expectedErr: true},
+	}
+	for _, tc := range testCases {
+		duration, err := parseReservedTime(tc.reservedTime)
+		if tc.expectedErr {
+			g.Expect(err).Should(HaveOccurred(), tc.reservedTime)
+			continue
+		}
+		g.Expect(err).Should(BeNil(), tc.reservedTime)
+		g.Expect(duration).Should(Equal(tc.expected), tc.reservedTime)
+	}
+}
+
+func TestGCWithDataPlaneConfirmation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	deps := helper.deps
+	m := NewBackupScheduleManager(deps).(*backupScheduleManager)
+
+	bs := &v1alpha1.VolumeBackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bsname"
+
+	bk := fakeBackup(&time.Time{})
+	bk.Namespace = bs.Namespace
+	bk.Name = "backup"
+	bk.Spec.Clusters = []v1alpha1.VolumeBackupMemberCluster{
+		{K8sClusterName: controller.FakeDataPlaneName1, TCNamespace: "tc-ns"},
+		{K8sClusterName: controller.FakeDataPlaneName2, TCNamespace: "tc-ns"},
+		{K8sClusterName: controller.FakeDataPlaneName3, TCNamespace: "tc-ns"},
+	}
+	bk.Status.Backups = []v1alpha1.VolumeBackupMemberStatus{
+		{K8sClusterName: controller.FakeDataPlaneName1, TCNamespace: "tc-ns", BackupName: "backup-1"},
+		{K8sClusterName: controller.FakeDataPlaneName2, TCNamespace: "tc-ns", BackupName: "backup-2"},
+	}
+	helper.createBackup(bk)
+
+	newBackupMember := func(name string, conditionType pingcapv1alpha1.BackupConditionType) *pingcapv1alpha1.Backup {
+		return &pingcapv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tc-ns", Name: name},
+			Status: pingcapv1alpha1.BackupStatus{
+				Conditions: []pingcapv1alpha1.BackupCondition{{Type: conditionType, Status: v1.ConditionTrue}},
+			},
+		}
+	}
+	dataPlane1 := deps.FedClientset[controller.FakeDataPlaneName1].PingcapV1alpha1().Backups("tc-ns")
+	dataPlane2 := deps.FedClientset[controller.FakeDataPlaneName2].PingcapV1alpha1().Backups("tc-ns")
+	_, err := dataPlane1.Create(context.TODO(), newBackupMember("backup-1", pingcapv1alpha1.BackupComplete), metav1.CreateOptions{})
+	g.Expect(err).Should(BeNil())
+	_, err = dataPlane2.Create(context.TODO(), newBackupMember("backup-2", pingcapv1alpha1.BackupRunning), metav1.CreateOptions{})
+	g.Expect(err).Should(BeNil())
+
+	// the backup is still running in data plane 2
+	m.gcOldestExpiredBackup(bs, []*v1alpha1.VolumeBackup{bk}, 2)
+	g.Expect(bs.Status.GCProgress).Should(Equal(&v1alpha1.VolumeBackupGCProgress{
+		BackupName: "backup",
+		DataPlanes: []v1alpha1.VolumeBackupDataPlaneGCStatus{
+			{K8sClusterName: controller.FakeDataPlaneName1, BackupName: "backup-1", Phase: v1alpha1.VolumeBackupDataPlaneGCConfirmed},
+			{K8sClusterName: controller.FakeDataPlaneName2, BackupName: "backup-2", Phase: v1alpha1.VolumeBackupDataPlaneGCUnconfirmed, Message: "backup backup-2 is still running"},
+			{K8sClusterName: controller.FakeDataPlaneName3, Phase: v1alpha1.VolumeBackupDataPlaneGCConfirmed},
+		},
+	}))
+	helper.checkBacklist(bs.Namespace, 1)
+
+	// all data planes confirm
+	_, err = dataPlane2.Update(context.TODO(), newBackupMember("backup-2", pingcapv1alpha1.BackupFailed), metav1.UpdateOptions{})
+	g.Expect(err).Should(BeNil())
+	m.gcOldestExpiredBackup(bs, []*v1alpha1.VolumeBackup{bk}, 2)
+	for _, dataPlane := range bs.Status.GCProgress.DataPlanes {
+		g.Expect(dataPlane.Phase).Should(Equal(v1alpha1.VolumeBackupDataPlaneGCConfirmed))
+	}
+	helper.checkBacklist(bs.Namespace, 0)
+
+	// the deletion progress in the data planes
+	now := metav1.Now()
+	bk.DeletionTimestamp = &now
+	cleanFailed := newBackupMember("backup-2", pingcapv1alpha1.BackupCleanFailed)
+	cleanFailed.DeletionTimestamp = &now
+	_, err = dataPlane2.Update(context.TODO(), cleanFailed, metav1.UpdateOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(dataPlane1.Delete(context.TODO(), "backup-1", metav1.DeleteOptions{})).Should(Succeed())
+	progress, confirmed := m.getDataPlaneGCProgress(bk)
+	g.Expect(confirmed).Should(BeTrue())
+	g.Expect(progress.DataPlanes[0].Phase).Should(Equal(v1alpha1.VolumeBackupDataPlaneGCDeleted))
+	g.Expect(progress.DataPlanes[1].Phase).Should(Equal(v1alpha1.VolumeBackupDataPlaneGCFailed))
+	g.Expect(progress.DataPlanes[2].Phase).Should(Equal(v1alpha1.VolumeBackupDataPlaneGCDeleted))
+
+	// unreachable data plane
+	bk.DeletionTimestamp = nil
+	bk.Spec.Clusters = append(bk.Spec.Clusters, v1alpha1.VolumeBackupMemberCluster{K8sClusterName: "unknown"})
+	_, confirmed = m.getDataPlaneGCProgress(bk)
+	g.Expect(confirmed).Should(BeFalse())
+}
+
 type helper struct {
 	t    *testing.T
 	deps *controller.BrFedDependencies
@@ -23,6 +23,13 @@ type Manager interface {
 	Sync(*v1alpha1.TidbCluster) error
 }
 
+// StatusManager is implemented by the managers which can refresh the status of their component
+// without acting upon the spec.
+type StatusManager interface {
+	// SyncStatus syncs the status of the component to tidbcluster.
+	SyncStatus(*v1alpha1.TidbCluster) error
+}
+
 type DMManager interface {
 	// Sync implements the logic for syncing dmcluster.
 	SyncDM(*v1alpha1.DMCluster) error
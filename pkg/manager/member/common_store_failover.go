@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/util"
 
 	corev1 "k8s.io/api/core/v1"
@@ -176,6 +177,11 @@ func (sf *commonStoreFailover) invokeDeleteFailureStore(tc *v1alpha1.TidbCluster
 				return parseErr
 			}
 			pdCli := controller.GetPDClient(sf.deps.PDControl, tc)
+			if sf.storeAccess.GetMemberType() == v1alpha1.TiKVMemberType {
+				if reason := pdapi.CheckStoresDeletable(pdCli, tc.TiKVDeletionSafetyLevel(), storeUintId); reason != "" {
+					return controller.RequeueErrorf("%s store '%s' in cluster %s/%s can not be deleted now: %s", sf.storeAccess.GetMemberType(), failureStore.StoreID, ns, tcName, reason)
+				}
+			}
 			if deleteErr := pdCli.DeleteStore(storeUintId); deleteErr != nil {
 				return deleteErr
 			}
@@ -0,0 +1,216 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// disruptionLockTTL is how long the disruption lock is held after it's renewed for the last time,
+	// so that the lock of a cluster stuck or deleted during a disruptive operation expires.
+	disruptionLockTTL = 10 * time.Minute
+)
+
+// The disruptive operations run by only one of the clusters sharing a PD at a time
+const (
+	DisruptionPDUpgrade      = "PDUpgrade"
+	DisruptionTiKVUpgrade    = "TiKVUpgrade"
+	DisruptionTiKVScaleIn    = "TiKVScaleIn"
+	DisruptionTiFlashUpgrade = "TiFlashUpgrade"
+	DisruptionTiFlashScaleIn = "TiFlashScaleIn"
+)
+
+// DisruptionLock serializes the disruptive operations of the heterogeneous clusters sharing a PD, so that
+// they don't evict leaders or delete stores at the same time and each of them counts the healthy replicas
+// against the same PD without the others changing them. The lock is kept in the status of the cluster owning
+// the PD, and is a no-op for a cluster whose PD isn't shared.
+type DisruptionLock interface {
+	// Acquire acquires or renews the lock for the operation, a requeue error is returned if the lock
+	// is held by another cluster.
+	Acquire(tc *v1alpha1.TidbCluster, operation string) error
+	// Release releases the lock held by the cluster when none of its components is upgrading or scaling.
+	Release(tc *v1alpha1.TidbCluster) error
+}
+
+type disruptionLock struct {
+	deps *controller.Dependencies
+}
+
+// NewDisruptionLock returns a DisruptionLock
+func NewDisruptionLock(deps *controller.Dependencies) DisruptionLock {
+	return &disruptionLock{deps: deps}
+}
+
+func (l *disruptionLock) Acquire(tc *v1alpha1.TidbCluster, operation string) error {
+	return acquireDisruptionLock(l.deps, tc, operation)
+}
+
+func (l *disruptionLock) Release(tc *v1alpha1.TidbCluster) error {
+	for _, phase := range []v1alpha1.MemberPhase{tc.Status.PD.Phase, tc.Status.TiKV.Phase, tc.Status.TiFlash.Phase} {
+		if phase == v1alpha1.UpgradePhase || phase == v1alpha1.ScalePhase {
+			return nil
+		}
+	}
+	primary, err := pdOwner(l.deps, tc)
+	if err != nil || primary == nil {
+		return err
+	}
+	holder := fmt.Sprintf("%s/%s", tc.Namespace, tc.Name)
+	if lock := primary.Status.DisruptionLock; lock == nil || lock.Holder != holder {
+		return nil
+	}
+	return updateDisruptionLock(l.deps, tc, primary, func(lock *v1alpha1.DisruptionLock) (*v1alpha1.DisruptionLock, error) {
+		if lock == nil || lock.Holder != holder {
+			return lock, nil
+		}
+		klog.Infof("TidbCluster: [%s] releases the disruption lock of the pd of tidbcluster %s/%s after %s", holder, primary.Namespace, primary.Name, lock.Operation)
+		return nil, nil
+	})
+}
+
+// acquireDisruptionLock acquires the lock of the shared PD before the disruptive operation of tc is started
+// or continued, the lock is renewed periodically while the operation is running.
+func acquireDisruptionLock(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, operation string) error {
+	primary, err := sharedPDOwner(deps, tc)
+	if err != nil || primary == nil {
+		return err
+	}
+	holder := fmt.Sprintf("%s/%s", tc.Namespace, tc.Name)
+	now := time.Now()
+	if lock := primary.Status.DisruptionLock; lock != nil && lock.Holder == holder && lock.Operation == operation &&
+		now.Sub(lock.RenewTime.Time) < disruptionLockTTL/3 {
+		return nil
+	}
+
+	acquired := false
+	err = updateDisruptionLock(deps, tc, primary, func(lock *v1alpha1.DisruptionLock) (*v1alpha1.DisruptionLock, error) {
+		if lock != nil && lock.Holder != holder && now.Sub(lock.RenewTime.Time) < disruptionLockTTL {
+			return nil, controller.RequeueErrorf("tidbcluster %s is waiting for %s of tidbcluster %s sharing the pd to finish", holder, lock.Operation, lock.Holder)
+		}
+		acquireTime := metav1.NewTime(now)
+		if lock != nil && lock.Holder == holder {
+			acquireTime = lock.AcquireTime
+		} else {
+			acquired = true
+		}
+		return &v1alpha1.DisruptionLock{
+			Holder:      holder,
+			Operation:   operation,
+			AcquireTime: acquireTime,
+			RenewTime:   metav1.NewTime(now),
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+	if acquired {
+		klog.Infof("TidbCluster: [%s] acquires the disruption lock of the pd of tidbcluster %s/%s for %s", holder, primary.Namespace, primary.Name, operation)
+		deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "DisruptionLockAcquired",
+			"acquired the disruption lock of the pd of tidbcluster %s/%s for %s", primary.Namespace, primary.Name, operation)
+	}
+	return nil
+}
+
+// updateDisruptionLock updates the lock in the latest status of the primary cluster, the update conflicts
+// if the lock is changed by another cluster at the same time.
+func updateDisruptionLock(deps *controller.Dependencies, tc, primary *v1alpha1.TidbCluster,
+	update func(*v1alpha1.DisruptionLock) (*v1alpha1.DisruptionLock, error)) error {
+	latest, err := deps.Clientset.PingcapV1alpha1().TidbClusters(primary.Namespace).Get(context.TODO(), primary.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get tidbcluster %s/%s failed: %v", primary.Namespace, primary.Name, err)
+	}
+	lock, err := update(latest.Status.DisruptionLock)
+	if err != nil {
+		return err
+	}
+	latest.Status.DisruptionLock = lock
+	updated, err := deps.Clientset.PingcapV1alpha1().TidbClusters(latest.Namespace).Update(context.TODO(), latest, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update the disruption lock of tidbcluster %s/%s failed: %v", latest.Namespace, latest.Name, err)
+	}
+	if tc.Namespace == primary.Namespace && tc.Name == primary.Name {
+		// keep the status of the cluster being synced in line with the lock written
+		tc.ResourceVersion = updated.ResourceVersion
+		tc.Status.DisruptionLock = lock
+	}
+	return nil
+}
+
+// pdOwner returns the cluster owning the PD of tc, it's nil if the owner isn't managed by this operator,
+// e.g. it's deployed in another Kubernetes cluster.
+func pdOwner(deps *controller.Dependencies, tc *v1alpha1.TidbCluster) (*v1alpha1.TidbCluster, error) {
+	if !tc.Heterogeneous() || !tc.WithoutLocalPD() {
+		return tc, nil
+	}
+	ns := tc.Spec.Cluster.Namespace
+	if ns == "" {
+		ns = tc.Namespace
+	}
+	primary, err := deps.TiDBClusterLister.TidbClusters(ns).Get(tc.Spec.Cluster.Name)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return primary, err
+}
+
+// sharedPDOwner returns the cluster owning the PD of tc if the PD is shared by multiple clusters
+func sharedPDOwner(deps *controller.Dependencies, tc *v1alpha1.TidbCluster) (*v1alpha1.TidbCluster, error) {
+	primary, err := pdOwner(deps, tc)
+	if err != nil || primary == nil || primary != tc {
+		return primary, err
+	}
+	tcs, err := deps.TiDBClusterLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("list tidbclusters failed: %v", err)
+	}
+	for _, other := range tcs {
+		if !other.Heterogeneous() || !other.WithoutLocalPD() || other.Spec.Cluster.Name != tc.Name {
+			continue
+		}
+		ns := other.Spec.Cluster.Namespace
+		if ns == "" {
+			ns = other.Namespace
+		}
+		if ns == tc.Namespace {
+			return tc, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeDisruptionLock struct{}
+
+// NewFakeDisruptionLock returns a fake DisruptionLock
+func NewFakeDisruptionLock() DisruptionLock {
+	return &fakeDisruptionLock{}
+}
+
+func (l *fakeDisruptionLock) Acquire(_ *v1alpha1.TidbCluster, _ string) error {
+	return nil
+}
+
+func (l *fakeDisruptionLock) Release(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDisruptionLock(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	indexer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+	newTC := func(name string, heterogeneous bool) *v1alpha1.TidbCluster {
+		tc := &v1alpha1.TidbCluster{}
+		tc.Namespace = corev1.NamespaceDefault
+		tc.Name = name
+		tc.Spec.TiKV = &v1alpha1.TiKVSpec{}
+		if heterogeneous {
+			tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Name: "primary"}
+		} else {
+			tc.Spec.PD = &v1alpha1.PDSpec{}
+		}
+		created, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(indexer.Add(created)).To(Succeed())
+		return created.DeepCopy()
+	}
+	getLock := func() *v1alpha1.DisruptionLock {
+		primary, err := deps.Clientset.PingcapV1alpha1().TidbClusters(corev1.NamespaceDefault).Get(context.TODO(), "primary", metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		// sync the lister with the latest primary
+		g.Expect(indexer.Update(primary)).To(Succeed())
+		return primary.Status.DisruptionLock
+	}
+	lock := NewDisruptionLock(deps)
+
+	// the lock is a no-op for a cluster whose pd isn't shared
+	standalone := newTC("standalone", false)
+	g.Expect(lock.Acquire(standalone, DisruptionTiKVUpgrade)).To(Succeed())
+	g.Expect(standalone.Status.DisruptionLock).To(BeNil())
+
+	primary := newTC("primary", false)
+	het1 := newTC("het1", true)
+	het2 := newTC("het2", true)
+
+	// the lock is acquired by the first cluster and renewed by it
+	g.Expect(lock.Acquire(het1, DisruptionTiKVScaleIn)).To(Succeed())
+	held := getLock()
+	g.Expect(held.Holder).To(Equal("default/het1"))
+	g.Expect(held.Operation).To(Equal(DisruptionTiKVScaleIn))
+	g.Expect(lock.Acquire(het1, DisruptionTiKVUpgrade)).To(Succeed())
+	g.Expect(getLock().Operation).To(Equal(DisruptionTiKVUpgrade))
+	g.Expect(getLock().AcquireTime).To(Equal(held.AcquireTime))
+
+	// the other clusters wait for the lock
+	err := lock.Acquire(het2, DisruptionTiFlashUpgrade)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	err = lock.Acquire(primary, DisruptionPDUpgrade)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+
+	// the lock is released after the operation finishes
+	het1.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	g.Expect(lock.Release(het1)).To(Succeed())
+	g.Expect(getLock()).NotTo(BeNil())
+	het1.Status.TiKV.Phase = v1alpha1.NormalPhase
+	g.Expect(lock.Release(het1)).To(Succeed())
+	g.Expect(getLock()).To(BeNil())
+
+	// the lock acquired by the owner of the pd is kept in its status
+	g.Expect(lock.Acquire(primary, DisruptionPDUpgrade)).To(Succeed())
+	g.Expect(primary.Status.DisruptionLock.Holder).To(Equal("default/primary"))
+	g.Expect(getLock().Holder).To(Equal("default/primary"))
+
+	// the expired lock is taken over
+	latest, err := deps.Clientset.PingcapV1alpha1().TidbClusters(corev1.NamespaceDefault).Get(context.TODO(), "primary", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	latest.Status.DisruptionLock.RenewTime = metav1.NewTime(time.Now().Add(-disruptionLockTTL))
+	_, err = deps.Clientset.PingcapV1alpha1().TidbClusters(corev1.NamespaceDefault).Update(context.TODO(), latest, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lock.Acquire(het2, DisruptionTiFlashScaleIn)).To(Succeed())
+	g.Expect(getLock().Holder).To(Equal("default/het2"))
+}
@@ -0,0 +1,222 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// dmSourceTLSMountPath is the directory where the client certificates are mounted
+	dmSourceTLSMountPath = "/var/lib/source-tls"
+	// dmDatabaseTLSVolumeName is the volume of the rendered security configs of the targets
+	dmDatabaseTLSVolumeName = "dm-database-tls"
+	// dmDatabaseTLSMountPath is the directory where the rendered security configs are mounted
+	dmDatabaseTLSMountPath = "/etc/dm-database-tls"
+
+	failedUpdateSourceTLSReason = "FailedUpdateSourceTLS"
+)
+
+// dmSecurityConfig is the security section of the dm task configs
+type dmSecurityConfig struct {
+	SSLCA         string   `yaml:"ssl-ca"`
+	SSLCert       string   `yaml:"ssl-cert"`
+	SSLKey        string   `yaml:"ssl-key"`
+	CertAllowedCN []string `yaml:"cert-allowed-cn,omitempty"`
+}
+
+type dmDatabaseSecurityConfig struct {
+	Security dmSecurityConfig `yaml:"security"`
+}
+
+// dmTargetSecurityConfig is the part of the task config to connect to the downstream target with TLS
+type dmTargetSecurityConfig struct {
+	TargetDatabase dmDatabaseSecurityConfig `yaml:"target-database"`
+}
+
+// dmDatabaseTLSOf returns the TLS configs of the databases of the type in spec.databaseTLS
+func dmDatabaseTLSOf(dc *v1alpha1.DMCluster, databaseType v1alpha1.DMDatabaseType) []v1alpha1.DMDatabaseTLS {
+	var databaseTLS []v1alpha1.DMDatabaseTLS
+	for _, tls := range dc.Spec.DatabaseTLS {
+		if tls.Type == databaseType {
+			databaseTLS = append(databaseTLS, tls)
+		}
+	}
+	return databaseTLS
+}
+
+// getDMClientTLSVolumes returns the volumes and mounts of the client certificates for dm-master and dm-worker.
+// The certificates of the targets and their rendered security configs are only mounted into dm-master,
+// where the tasks are started by dmctl, and the certificates of the sources are applied by syncSourceTLS.
+func getDMClientTLSVolumes(dc *v1alpha1.DMCluster, memberType v1alpha1.MemberType) ([]corev1.Volume, []corev1.VolumeMount) {
+	var (
+		vols      []corev1.Volume
+		volMounts []corev1.VolumeMount
+	)
+	secretNames := map[string]struct{}{}
+	addSecret := func(secretName string) {
+		if _, ok := secretNames[secretName]; ok {
+			return
+		}
+		secretNames[secretName] = struct{}{}
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: secretName, ReadOnly: true, MountPath: path.Join(dmSourceTLSMountPath, secretName),
+		})
+		vols = append(vols, corev1.Volume{
+			Name: secretName, VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+				},
+			},
+		})
+	}
+	for _, tlsClientSecretName := range dc.Spec.TLSClientSecretNames {
+		addSecret(tlsClientSecretName)
+	}
+	if memberType != v1alpha1.DMMasterMemberType {
+		return vols, volMounts
+	}
+	targetTLS := dmDatabaseTLSOf(dc, v1alpha1.DMDatabaseTypeTarget)
+	for _, tls := range targetTLS {
+		addSecret(tls.SecretName)
+	}
+
+	if len(targetTLS) > 0 {
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: dmDatabaseTLSVolumeName, ReadOnly: true, MountPath: dmDatabaseTLSMountPath,
+		})
+		vols = append(vols, corev1.Volume{
+			Name: dmDatabaseTLSVolumeName, VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: controller.DMDatabaseTLSConfigMapName(dc.Name),
+					},
+				},
+			},
+		})
+	}
+	return vols, volMounts
+}
+
+// getDMDatabaseTLSConfigMap renders the security configs of the targets into <name>.yaml of the configmap, which
+// refer to the certificates mounted into dm-master and are merged into the task configs.
+// nil is returned if no target is set in spec.databaseTLS.
+func getDMDatabaseTLSConfigMap(dc *v1alpha1.DMCluster) (*corev1.ConfigMap, error) {
+	targetTLS := dmDatabaseTLSOf(dc, v1alpha1.DMDatabaseTypeTarget)
+	if len(targetTLS) == 0 {
+		return nil, nil
+	}
+
+	data := map[string]string{}
+	for _, tls := range targetTLS {
+		certDir := path.Join(dmSourceTLSMountPath, tls.SecretName)
+		config := dmTargetSecurityConfig{
+			TargetDatabase: dmDatabaseSecurityConfig{
+				Security: dmSecurityConfig{
+					SSLCA:         path.Join(certDir, tlsSecretRootCAKey),
+					SSLCert:       path.Join(certDir, corev1.TLSCertKey),
+					SSLKey:        path.Join(certDir, corev1.TLSPrivateKeyKey),
+					CertAllowedCN: tls.CertAllowedCN,
+				},
+			},
+		}
+		content, err := yaml.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		data[tls.Name+".yaml"] = string(content)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.DMDatabaseTLSConfigMapName(dc.Name),
+			Namespace:       dc.Namespace,
+			Labels:          label.NewDM().Instance(dc.GetInstanceName()).Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetDMOwnerRef(dc)},
+		},
+		Data: data,
+	}, nil
+}
+
+// syncSourceTLS applies the client certificates of the sources in spec.databaseTLS to the sources with the same
+// names through the OpenAPI of dm-master, so that dm-worker connects to the upstream databases with TLS.
+// The sources created later are applied in the following syncs.
+func (m *masterMemberManager) syncSourceTLS(dc *v1alpha1.DMCluster) error {
+	sourceTLS := dmDatabaseTLSOf(dc, v1alpha1.DMDatabaseTypeSource)
+	if len(sourceTLS) == 0 {
+		return nil
+	}
+
+	dmClient := controller.GetMasterClient(m.deps.DMMasterControl, dc)
+	sources, err := dmClient.GetSources()
+	if err != nil {
+		return fmt.Errorf("get sources failed: %v", err)
+	}
+	var errs []error
+	for _, source := range sources {
+		var tls *v1alpha1.DMDatabaseTLS
+		for i := range sourceTLS {
+			if sourceTLS[i].Name == source.SourceName {
+				tls = &sourceTLS[i]
+				break
+			}
+		}
+		if tls == nil {
+			continue
+		}
+		security, err := m.getSourceSecurity(dc, tls)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if source.Security != nil && equality.Semantic.DeepEqual(*source.Security, *security) {
+			continue
+		}
+		if err := dmClient.UpdateSourceSecurity(source.SourceName, *security); err != nil {
+			m.deps.Recorder.Eventf(dc, corev1.EventTypeWarning, failedUpdateSourceTLSReason,
+				"failed to update the TLS of source %s: %v", source.SourceName, err)
+			errs = append(errs, err)
+			continue
+		}
+		klog.Infof("DMCluster: [%s/%s]'s source %s is updated to connect with the TLS certificate in secret %s",
+			dc.Namespace, dc.Name, source.SourceName, tls.SecretName)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// getSourceSecurity returns the security of the source with the client certificate in the secret
+func (m *masterMemberManager) getSourceSecurity(dc *v1alpha1.DMCluster, tls *v1alpha1.DMDatabaseTLS) (*dmapi.SourceSecurity, error) {
+	secret, err := m.deps.SecretLister.Secrets(dc.Namespace).Get(tls.SecretName)
+	if err != nil {
+		return nil, fmt.Errorf("get secret %s of source %s failed: %v", tls.SecretName, tls.Name, err)
+	}
+	return &dmapi.SourceSecurity{
+		SSLCAContent:   string(secret.Data[tlsSecretRootCAKey]),
+		SSLCertContent: string(secret.Data[corev1.TLSCertKey]),
+		SSLKeyContent:  string(secret.Data[corev1.TLSPrivateKeyKey]),
+		CertAllowedCN:  tls.CertAllowedCN,
+	}, nil
+}
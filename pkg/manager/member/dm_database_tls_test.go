@@ -0,0 +1,132 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDMDatabaseTLSConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dc := &v1alpha1.DMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "dm", Namespace: corev1.NamespaceDefault},
+	}
+	cm, err := getDMDatabaseTLSConfigMap(dc)
+	g.Expect(err).Should(BeNil())
+	g.Expect(cm).Should(BeNil())
+
+	dc.Spec.DatabaseTLS = []v1alpha1.DMDatabaseTLS{
+		{Name: "mysql-01", Type: v1alpha1.DMDatabaseTypeSource, SecretName: "mysql-tls"},
+		{Name: "tidb", Type: v1alpha1.DMDatabaseTypeTarget, SecretName: "tidb-tls", CertAllowedCN: []string{"tidb-server"}},
+	}
+	cm, err = getDMDatabaseTLSConfigMap(dc)
+	g.Expect(err).Should(BeNil())
+	g.Expect(cm.Name).Should(Equal("dm-dm-database-tls"))
+	g.Expect(cm.Data).Should(Equal(map[string]string{
+		"tidb.yaml": `target-database:
+  security:
+    ssl-ca: /var/lib/source-tls/tidb-tls/ca.crt
+    ssl-cert: /var/lib/source-tls/tidb-tls/tls.crt
+    ssl-key: /var/lib/source-tls/tidb-tls/tls.key
+    cert-allowed-cn:
+    - tidb-server
+`,
+	}))
+}
+
+func TestGetDMClientTLSVolumes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dc := &v1alpha1.DMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "dm", Namespace: corev1.NamespaceDefault},
+		Spec: v1alpha1.DMClusterSpec{
+			TLSClientSecretNames: []string{"mysql-tls"},
+			DatabaseTLS: []v1alpha1.DMDatabaseTLS{
+				{Name: "mysql-01", Type: v1alpha1.DMDatabaseTypeSource, SecretName: "mysql-tls"},
+				{Name: "tidb", Type: v1alpha1.DMDatabaseTypeTarget, SecretName: "tidb-tls"},
+			},
+		},
+	}
+	vols, volMounts := getDMClientTLSVolumes(dc, v1alpha1.DMMasterMemberType)
+	var volNames, mountPaths []string
+	for _, vol := range vols {
+		volNames = append(volNames, vol.Name)
+	}
+	for _, volMount := range volMounts {
+		mountPaths = append(mountPaths, volMount.MountPath)
+	}
+	g.Expect(volNames).Should(Equal([]string{"mysql-tls", "tidb-tls", dmDatabaseTLSVolumeName}))
+	g.Expect(mountPaths).Should(Equal([]string{"/var/lib/source-tls/mysql-tls", "/var/lib/source-tls/tidb-tls", dmDatabaseTLSMountPath}))
+	g.Expect(vols[2].ConfigMap.Name).Should(Equal("dm-dm-database-tls"))
+
+	// the certificates of the targets are only used by dm-master
+	vols, _ = getDMClientTLSVolumes(dc, v1alpha1.DMWorkerMemberType)
+	g.Expect(vols).Should(HaveLen(1))
+	g.Expect(vols[0].Name).Should(Equal("mysql-tls"))
+}
+
+func TestSyncSourceTLS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dc := newDMClusterForMaster()
+	mmm, _, _, masterControl, _, _, _ := newFakeMasterMemberManager()
+	masterClient := controller.NewFakeMasterClient(masterControl, dc)
+	secretIndexer := mmm.deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer()
+
+	// the TLS of the sources is not managed if it's not set
+	g.Expect(mmm.syncSourceTLS(dc)).Should(Succeed())
+
+	dc.Spec.DatabaseTLS = []v1alpha1.DMDatabaseTLS{
+		{Name: "mysql-01", Type: v1alpha1.DMDatabaseTypeSource, SecretName: "mysql-tls", CertAllowedCN: []string{"mysql"}},
+		{Name: "mysql-02", Type: v1alpha1.DMDatabaseTypeSource, SecretName: "mysql-tls"},
+		{Name: "tidb", Type: v1alpha1.DMDatabaseTypeTarget, SecretName: "tidb-tls"},
+	}
+	g.Expect(secretIndexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-tls", Namespace: dc.Namespace},
+		Data: map[string][]byte{
+			tlsSecretRootCAKey:      []byte("ca"),
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	})).Should(Succeed())
+	synced := dmapi.SourceSecurity{SSLCAContent: "ca", SSLCertContent: "cert", SSLKeyContent: "key"}
+	masterClient.AddReaction(dmapi.GetSourcesActionType, func(action *dmapi.Action) (interface{}, error) {
+		return []*dmapi.SourceInfo{
+			{SourceName: "mysql-01"},
+			{SourceName: "mysql-02", Security: &synced},
+			{SourceName: "mysql-03"},
+		}, nil
+	})
+	updated := map[string]dmapi.SourceSecurity{}
+	masterClient.AddReaction(dmapi.UpdateSourceSecurityActionType, func(action *dmapi.Action) (interface{}, error) {
+		updated[action.Name] = action.Security
+		return nil, nil
+	})
+	g.Expect(mmm.syncSourceTLS(dc)).Should(Succeed())
+	g.Expect(updated).Should(Equal(map[string]dmapi.SourceSecurity{
+		"mysql-01": {SSLCAContent: "ca", SSLCertContent: "cert", SSLKeyContent: "key", CertAllowedCN: []string{"mysql"}},
+	}))
+
+	masterCM, err := getMasterConfigMap(dc)
+	g.Expect(err).Should(Succeed())
+	g.Expect(masterCM.Data["config-file"]).Should(ContainSubstring("openapi = true"))
+}
@@ -74,7 +74,7 @@ func (m *masterMemberManager) SyncDM(dc *v1alpha1.DMCluster) error {
 	component := v1alpha1.DMMasterMemberType
 	needSuspend, err := m.suspender.SuspendComponent(dc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, dc.GetNamespace(), dc.GetName())
@@ -91,8 +91,31 @@ func (m *masterMemberManager) SyncDM(dc *v1alpha1.DMCluster) error {
 		return err
 	}
 
+	// Sync the security configs of the targets
+	if err := m.syncDatabaseTLSConfigMap(dc); err != nil {
+		return err
+	}
+
 	// Sync dm-master StatefulSet
-	return m.syncMasterStatefulSetForDMCluster(dc)
+	if err := m.syncMasterStatefulSetForDMCluster(dc); err != nil {
+		return err
+	}
+
+	// the failure of applying the TLS of the sources does not block dm-master, it's retried in the next sync
+	if err := m.syncSourceTLS(dc); err != nil {
+		klog.Warningf("DMCluster: [%s/%s], sync the TLS of the sources failed: %v", dc.GetNamespace(), dc.GetName(), err)
+	}
+	return nil
+}
+
+// syncDatabaseTLSConfigMap syncs the configmap of the security configs of the targets
+func (m *masterMemberManager) syncDatabaseTLSConfigMap(dc *v1alpha1.DMCluster) error {
+	cm, err := getDMDatabaseTLSConfigMap(dc)
+	if err != nil || cm == nil {
+		return err
+	}
+	_, err = m.deps.TypedControl.CreateOrUpdateConfigMap(dc, cm)
+	return err
 }
 
 func (m *masterMemberManager) syncMasterServiceForDMCluster(dc *v1alpha1.DMCluster) error {
@@ -594,18 +617,9 @@ func getNewMasterSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 		})
 	}
 
-	for _, tlsClientSecretName := range dc.Spec.TLSClientSecretNames {
-		volMounts = append(volMounts, corev1.VolumeMount{
-			Name: tlsClientSecretName, ReadOnly: true, MountPath: fmt.Sprintf("/var/lib/source-tls/%s", tlsClientSecretName),
-		})
-		vols = append(vols, corev1.Volume{
-			Name: tlsClientSecretName, VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: tlsClientSecretName,
-				},
-			},
-		})
-	}
+	clientTLSVols, clientTLSVolMounts := getDMClientTLSVolumes(dc, v1alpha1.DMMasterMemberType)
+	vols = append(vols, clientTLSVols...)
+	volMounts = append(volMounts, clientTLSVolMounts...)
 
 	storageSize := DefaultStorageSize
 	if dc.Spec.Master.StorageSize != "" {
@@ -691,7 +705,7 @@ func getNewMasterSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 			},
 		})
 	}
-	masterContainer.Env = util.AppendEnv(env, baseMasterSpec.Env())
+	masterContainer.Env = util.AppendEnv(util.AppendEnv(env, baseMasterSpec.Env()), goRuntimeResourceEnv(baseMasterSpec, masterContainer.Resources))
 	masterContainer.EnvFrom = baseMasterSpec.EnvFrom()
 	podSpec.Volumes = append(vols, baseMasterSpec.AdditionalVolumes()...)
 
@@ -767,14 +781,20 @@ func getMasterConfigMap(dc *v1alpha1.DMCluster) (*corev1.ConfigMap, error) {
 		config.Set("ssl-key", path.Join(dmMasterClusterCertPath, corev1.TLSPrivateKeyKey))
 	}
 
+	// the relay purge policy and the TLS of the sources are applied to the sources through the OpenAPI
+	if (dc.Spec.Worker != nil && dc.Spec.Worker.RelayPurge != nil) || len(dmDatabaseTLSOf(dc, v1alpha1.DMDatabaseTypeSource)) > 0 {
+		config.Set("openapi", true)
+	}
+
 	confText, err := config.MarshalTOML()
 	if err != nil {
 		return nil, err
 	}
 
 	model := &startscriptv1.DMMasterStartScriptModel{
-		Scheme:  dc.Scheme(),
-		DataDir: filepath.Join(dmMasterDataVolumeMountPath, dc.Spec.Master.DataSubDir),
+		Scheme:         dc.Scheme(),
+		DataDir:        filepath.Join(dmMasterDataVolumeMountPath, dc.Spec.Master.DataSubDir),
+		AdditionalArgs: controller.FormatAdditionalArgs(dc.BaseMasterSpec().AdditionalArgs()),
 	}
 	if dc.Spec.Master.StartUpScriptVersion == "v1" {
 		model.CheckDomainScript = v1.DMMasterCheckDNSV1
@@ -78,7 +78,7 @@ func (m *workerMemberManager) SyncDM(dc *v1alpha1.DMCluster) error {
 	component := v1alpha1.DMWorkerMemberType
 	needSuspend, err := m.suspender.SuspendComponent(dc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, dc.GetNamespace(), dc.GetName())
@@ -95,7 +95,15 @@ func (m *workerMemberManager) SyncDM(dc *v1alpha1.DMCluster) error {
 	}
 
 	// Sync dm-worker StatefulSet
-	return m.syncWorkerStatefulSetForDMCluster(dc)
+	if err := m.syncWorkerStatefulSetForDMCluster(dc); err != nil {
+		return err
+	}
+
+	// the failure of syncing the relay purge policy does not block the dm-worker, it's retried in the next sync
+	if err := m.syncRelayPurge(dc); err != nil {
+		klog.Warningf("DMCluster: [%s/%s], sync the relay purge policy of the sources failed: %v", ns, dcName, err)
+	}
+	return nil
 }
 
 func (m *workerMemberManager) syncWorkerHeadlessServiceForDMCluster(dc *v1alpha1.DMCluster) error {
@@ -188,7 +196,11 @@ func (m *workerMemberManager) syncWorkerStatefulSetForDMCluster(dc *v1alpha1.DMC
 		return nil
 	}
 
-	cm, err := m.syncWorkerConfigMap(dc, oldSts)
+	relayStorage := workerRelayStorageEnabled(dc, oldSts)
+	if dc.Spec.Worker.RelayStorage != nil && !relayStorage {
+		klog.Warningf("DMCluster: [%s/%s], spec.worker.relayStorage is ignored as the dm-worker statefulset was created without it", ns, dcName)
+	}
+	cm, err := m.syncWorkerConfigMap(dc, oldSts, relayStorage)
 	if err != nil {
 		return err
 	}
@@ -203,7 +215,7 @@ func (m *workerMemberManager) syncWorkerStatefulSetForDMCluster(dc *v1alpha1.DMC
 		m.failover.Recover(dc)
 	}
 
-	newSts, err := getNewWorkerSetForDMCluster(dc, cm)
+	newSts, err := getNewWorkerSetForDMCluster(dc, cm, relayStorage)
 	if err != nil {
 		return err
 	}
@@ -334,8 +346,8 @@ func (m *workerMemberManager) workerStatefulSetIsUpgrading(set *apps.StatefulSet
 }
 
 // syncWorkerConfigMap syncs the configmap of dm-worker
-func (m *workerMemberManager) syncWorkerConfigMap(dc *v1alpha1.DMCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
-	newCm, err := getWorkerConfigMap(dc)
+func (m *workerMemberManager) syncWorkerConfigMap(dc *v1alpha1.DMCluster, set *apps.StatefulSet, relayStorage bool) (*corev1.ConfigMap, error) {
+	newCm, err := getWorkerConfigMap(dc, relayStorage)
 	if err != nil {
 		return nil, err
 	}
@@ -354,7 +366,7 @@ func (m *workerMemberManager) syncWorkerConfigMap(dc *v1alpha1.DMCluster, set *a
 	return m.deps.TypedControl.CreateOrUpdateConfigMap(dc, newCm)
 }
 
-func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap, relayStorage bool) (*apps.StatefulSet, error) {
 	ns := dc.Namespace
 	dcName := dc.Name
 	baseWorkerSpec := dc.BaseWorkerSpec()
@@ -372,6 +384,9 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 		{Name: "startup-script", ReadOnly: true, MountPath: "/usr/local/bin"},
 		{Name: dataVolumeName, MountPath: dmWorkerDataVolumeMountPath},
 	}
+	if relayStorage {
+		volMounts = append(volMounts, corev1.VolumeMount{Name: dmWorkerRelayVolumeName(), MountPath: dmWorkerRelayVolumeMountPath})
+	}
 	volMounts = append(volMounts, dc.Spec.Worker.AdditionalVolumeMounts...)
 
 	if dc.IsTLSClusterEnabled() {
@@ -413,18 +428,9 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 		})
 	}
 
-	for _, tlsClientSecretName := range dc.Spec.TLSClientSecretNames {
-		volMounts = append(volMounts, corev1.VolumeMount{
-			Name: tlsClientSecretName, ReadOnly: true, MountPath: fmt.Sprintf("/var/lib/source-tls/%s", tlsClientSecretName),
-		})
-		vols = append(vols, corev1.Volume{
-			Name: tlsClientSecretName, VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: tlsClientSecretName,
-				},
-			},
-		})
-	}
+	clientTLSVols, clientTLSVolMounts := getDMClientTLSVolumes(dc, v1alpha1.DMWorkerMemberType)
+	vols = append(vols, clientTLSVols...)
+	volMounts = append(volMounts, clientTLSVolMounts...)
 
 	storageSize := DefaultStorageSize
 	if dc.Spec.Worker.StorageSize != "" {
@@ -439,6 +445,46 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 			corev1.ResourceStorage: rs,
 		},
 	}
+	pvcs := []corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: dataVolumeName,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				StorageClassName: dc.Spec.Worker.StorageClassName,
+				Resources:        storageRequest,
+			},
+		},
+	}
+	if relayStorage {
+		relaySize := DefaultStorageSize
+		if dc.Spec.Worker.RelayStorage.StorageSize != "" {
+			relaySize = dc.Spec.Worker.RelayStorage.StorageSize
+		}
+		relayRequest, err := resource.ParseQuantity(relaySize)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse relay storage request for dm-worker, dmcluster %s/%s, error: %v", dc.Namespace, dc.Name, err)
+		}
+		pvcs = append(pvcs, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: dmWorkerRelayVolumeName(),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				StorageClassName: dc.Spec.Worker.RelayStorage.StorageClassName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: relayRequest,
+					},
+				},
+			},
+		})
+	}
 
 	setName := controller.DMWorkerMemberName(dcName)
 	stsLabels := label.NewDM().Instance(instanceName).DMWorker()
@@ -499,7 +545,7 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 			},
 		})
 	}
-	workerContainer.Env = util.AppendEnv(env, baseWorkerSpec.Env())
+	workerContainer.Env = util.AppendEnv(util.AppendEnv(env, baseWorkerSpec.Env()), goRuntimeResourceEnv(baseWorkerSpec, workerContainer.Resources))
 	workerContainer.EnvFrom = baseWorkerSpec.EnvFrom()
 	podSpec.Volumes = append(vols, baseWorkerSpec.AdditionalVolumes()...)
 
@@ -529,22 +575,9 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 				},
 				Spec: podSpec,
 			},
-			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
-				{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: dataVolumeName,
-					},
-					Spec: corev1.PersistentVolumeClaimSpec{
-						AccessModes: []corev1.PersistentVolumeAccessMode{
-							corev1.ReadWriteOnce,
-						},
-						StorageClassName: dc.Spec.Worker.StorageClassName,
-						Resources:        storageRequest,
-					},
-				},
-			},
-			ServiceName:         controller.DMWorkerPeerMemberName(dcName),
-			PodManagementPolicy: baseWorkerSpec.PodManagementPolicy(),
+			VolumeClaimTemplates: pvcs,
+			ServiceName:          controller.DMWorkerPeerMemberName(dcName),
+			PodManagementPolicy:  baseWorkerSpec.PodManagementPolicy(),
 			UpdateStrategy: apps.StatefulSetUpdateStrategy{
 				Type: baseWorkerSpec.StatefulSetUpdateStrategy(),
 			},
@@ -554,7 +587,7 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 	return workerSet, nil
 }
 
-func getWorkerConfigMap(dc *v1alpha1.DMCluster) (*corev1.ConfigMap, error) {
+func getWorkerConfigMap(dc *v1alpha1.DMCluster, relayStorage bool) (*corev1.ConfigMap, error) {
 	config := v1alpha1.NewWorkerConfig()
 	if dc.Spec.Worker.Config != nil {
 		config = dc.Spec.Worker.Config.DeepCopy()
@@ -567,13 +600,18 @@ func getWorkerConfigMap(dc *v1alpha1.DMCluster) (*corev1.ConfigMap, error) {
 		config.Set("ssl-key", path.Join(dmWorkerClusterCertPath, corev1.TLSPrivateKeyKey))
 	}
 
+	if relayStorage {
+		config.Set("relay-dir", dmWorkerRelayVolumeMountPath)
+	}
+
 	confText, err := config.MarshalTOML()
 	if err != nil {
 		return nil, err
 	}
 	startScript, err := startscriptv1.RenderDMWorkerStartScript(&startscriptv1.DMWorkerStartScriptModel{
-		DataDir:       filepath.Join(dmWorkerDataVolumeMountPath, dc.Spec.Worker.DataSubDir),
-		MasterAddress: controller.DMMasterMemberName(dc.Name) + ":8261",
+		DataDir:        filepath.Join(dmWorkerDataVolumeMountPath, dc.Spec.Worker.DataSubDir),
+		MasterAddress:  controller.DMMasterMemberName(dc.Name) + ":8261",
+		AdditionalArgs: controller.FormatAdditionalArgs(dc.BaseWorkerSpec().AdditionalArgs()),
 	})
 	if err != nil {
 		return nil, err
@@ -97,7 +97,7 @@ func TestWorkerMemberManagerSyncCreate(t *testing.T) {
 
 		cmName := controller.DMWorkerMemberName(dcName)
 		if dc.Spec.Worker != nil {
-			cmGen, err := getWorkerConfigMap(dc)
+			cmGen, err := getWorkerConfigMap(dc, false)
 			g.Expect(err).To(Succeed())
 			cmName = cmGen.Name
 			g.Expect(cmName).To(Equal(controller.DMWorkerMemberName(dcName))) // name not changed
@@ -253,11 +253,11 @@ func TestWorkerMemberManagerSyncUpdate(t *testing.T) {
 			ctls.generic.SetCreateOrUpdateError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
 		}
 
-		oldCm, err := getWorkerConfigMap(dc)
+		oldCm, err := getWorkerConfigMap(dc, false)
 		g.Expect(err).To(Succeed())
 		oldSvc := getNewWorkerHeadlessServiceForDMCluster(dc)
 		oldSvc.Spec.Ports[0].Port = 8888
-		oldSet, err := getNewWorkerSetForDMCluster(dc, oldCm)
+		oldSet, err := getNewWorkerSetForDMCluster(dc, oldCm, false)
 		g.Expect(err).To(Succeed())
 
 		g.Expect(indexers.set.Add(oldSet)).To(Succeed())
@@ -275,7 +275,7 @@ func TestWorkerMemberManagerSyncUpdate(t *testing.T) {
 
 		cmName := controller.DMWorkerMemberName(dcName)
 		if dc.Spec.Worker != nil {
-			cmGen, err := getWorkerConfigMap(dc)
+			cmGen, err := getWorkerConfigMap(dc, false)
 			g.Expect(err).To(Succeed())
 			cmName = cmGen.Name
 			g.Expect(cmName).To(Equal(controller.DMWorkerMemberName(dcName)))  // name not changed
@@ -641,11 +641,11 @@ func TestWorkerSyncConfigUpdate(t *testing.T) {
 			return test.workerInfos, nil
 		})
 
-		oldCm, err := getWorkerConfigMap(dc)
+		oldCm, err := getWorkerConfigMap(dc, false)
 		g.Expect(err).To(Succeed())
 		oldSvc := getNewWorkerHeadlessServiceForDMCluster(dc)
 		oldSvc.Spec.Ports[0].Port = 8888
-		oldSet, err := getNewWorkerSetForDMCluster(dc, oldCm)
+		oldSet, err := getNewWorkerSetForDMCluster(dc, oldCm, false)
 		g.Expect(err).To(Succeed())
 
 		g.Expect(indexers.set.Add(oldSet)).To(Succeed())
@@ -1333,7 +1333,7 @@ func TestGetNewWorkerSetForDMCluster(t *testing.T) {
 			if !tt.nilCM {
 				cm = &corev1.ConfigMap{}
 			}
-			sts, err := getNewWorkerSetForDMCluster(&tt.dc, cm)
+			sts, err := getNewWorkerSetForDMCluster(&tt.dc, cm, false)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("error %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1450,7 +1450,7 @@ log-level = "info"
 	for i := range tests {
 		tt := tests[i]
 		t.Run(tt.name, func(t *testing.T) {
-			cm, err := getWorkerConfigMap(&tt.dc)
+			cm, err := getWorkerConfigMap(&tt.dc, false)
 			g.Expect(err).To(Succeed())
 			g.Expect(cm.Name).To(Equal("foo-dm-worker"))
 			// startup-script is better to be validated in e2e test
@@ -0,0 +1,106 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// dmWorkerRelayVolumeMountPath is the mount path for the dedicated relay log volume of dm-worker
+	dmWorkerRelayVolumeMountPath = "/var/lib/dm-worker-relay"
+
+	// defaults of spec.worker.relayPurge, which are the defaults of DM
+	defaultRelayPurgeInterval    = 3600
+	defaultRelayPurgeExpires     = 0
+	defaultRelayPurgeRemainSpace = 15
+
+	failedUpdateRelayPurgeReason = "FailedUpdateRelayPurge"
+)
+
+// dmWorkerRelayVolumeName returns the name of the dedicated relay log volume of dm-worker
+func dmWorkerRelayVolumeName() string {
+	return string(v1alpha1.GetStorageVolumeName("relay", v1alpha1.DMWorkerMemberType))
+}
+
+// workerRelayStorageEnabled returns whether the relay logs are stored in the dedicated volume. The volume
+// can't be added to an existing statefulset without it, as the PVC templates of a statefulset are immutable.
+func workerRelayStorageEnabled(dc *v1alpha1.DMCluster, set *apps.StatefulSet) bool {
+	if dc.Spec.Worker.RelayStorage == nil {
+		return false
+	}
+	if set == nil {
+		return true
+	}
+	for _, pvc := range set.Spec.VolumeClaimTemplates {
+		if pvc.Name == dmWorkerRelayVolumeName() {
+			return true
+		}
+	}
+	return false
+}
+
+// relayPurgeOf returns the purge policy of the relay logs by spec.worker.relayPurge
+func relayPurgeOf(policy *v1alpha1.DMRelayPurgePolicy) dmapi.SourcePurge {
+	interval, expires, remainSpace := int64(defaultRelayPurgeInterval), int64(defaultRelayPurgeExpires), int64(defaultRelayPurgeRemainSpace)
+	if policy.Interval != nil {
+		interval = *policy.Interval
+	}
+	if policy.Expires != nil {
+		expires = *policy.Expires
+	}
+	if policy.RemainSpace != nil {
+		remainSpace = *policy.RemainSpace
+	}
+	return dmapi.SourcePurge{Interval: &interval, Expires: &expires, RemainSpace: &remainSpace}
+}
+
+// syncRelayPurge applies spec.worker.relayPurge to the sources whose purge policies differ from it.
+// The purge policies of the sources are not managed if it's not set.
+func (m *workerMemberManager) syncRelayPurge(dc *v1alpha1.DMCluster) error {
+	if dc.Spec.Worker.RelayPurge == nil {
+		return nil
+	}
+	purge := relayPurgeOf(dc.Spec.Worker.RelayPurge)
+
+	dmClient := controller.GetMasterClient(m.deps.DMMasterControl, dc)
+	sources, err := dmClient.GetSources()
+	if err != nil {
+		return fmt.Errorf("get sources failed: %v", err)
+	}
+	var errs []error
+	for _, source := range sources {
+		if source.Purge != nil && equality.Semantic.DeepEqual(*source.Purge, purge) {
+			continue
+		}
+		if err := dmClient.UpdateSourcePurge(source.SourceName, purge); err != nil {
+			m.deps.Recorder.Eventf(dc, corev1.EventTypeWarning, failedUpdateRelayPurgeReason,
+				"failed to update the relay purge policy of source %s: %v", source.SourceName, err)
+			errs = append(errs, err)
+			continue
+		}
+		klog.Infof("DMCluster: [%s/%s]'s source %s is updated to relay purge interval %ds, expires %dh, remain space %dGB",
+			dc.Namespace, dc.Name, source.SourceName, *purge.Interval, *purge.Expires, *purge.RemainSpace)
+	}
+	return errorutils.NewAggregate(errs)
+}
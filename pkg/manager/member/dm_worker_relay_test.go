@@ -0,0 +1,90 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	"k8s.io/utils/pointer"
+)
+
+func TestWorkerRelayStorage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dc := newDMClusterForWorker()
+	g.Expect(workerRelayStorageEnabled(dc, nil)).To(BeFalse())
+
+	dc.Spec.Worker.RelayStorage = &v1alpha1.DMRelayStorage{StorageClassName: pointer.String("local"), StorageSize: "100Gi"}
+	g.Expect(workerRelayStorageEnabled(dc, nil)).To(BeTrue())
+
+	cm, err := getWorkerConfigMap(dc, true)
+	g.Expect(err).To(Succeed())
+	g.Expect(cm.Data["config-file"]).To(ContainSubstring(fmt.Sprintf("relay-dir = %q", dmWorkerRelayVolumeMountPath)))
+
+	set, err := getNewWorkerSetForDMCluster(dc, cm, true)
+	g.Expect(err).To(Succeed())
+	g.Expect(set.Spec.VolumeClaimTemplates).To(HaveLen(2))
+	relayPVC := set.Spec.VolumeClaimTemplates[1]
+	g.Expect(relayPVC.Name).To(Equal("dm-worker-relay"))
+	g.Expect(relayPVC.Spec.StorageClassName).To(Equal(pointer.String("local")))
+	g.Expect(relayPVC.Spec.Resources.Requests.Storage().String()).To(Equal("100Gi"))
+	g.Expect(set.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(HaveField("MountPath", dmWorkerRelayVolumeMountPath)))
+	g.Expect(workerRelayStorageEnabled(dc, set)).To(BeTrue())
+
+	// the relay storage can't be added to the existing statefulset
+	set, err = getNewWorkerSetForDMCluster(dc, cm, false)
+	g.Expect(err).To(Succeed())
+	g.Expect(set.Spec.VolumeClaimTemplates).To(HaveLen(1))
+	g.Expect(workerRelayStorageEnabled(dc, set)).To(BeFalse())
+}
+
+func TestSyncRelayPurge(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dc := newDMClusterForWorker()
+	wmm, _, _, masterControl := newFakeWorkerMemberManager()
+	masterClient := controller.NewFakeMasterClient(masterControl, dc)
+
+	// the purge policies are not managed if it's not set
+	g.Expect(wmm.syncRelayPurge(dc)).To(Succeed())
+
+	dc.Spec.Worker.RelayPurge = &v1alpha1.DMRelayPurgePolicy{Expires: pointer.Int64(24)}
+	purge := relayPurgeOf(dc.Spec.Worker.RelayPurge)
+	g.Expect(*purge.Interval).To(Equal(int64(defaultRelayPurgeInterval)))
+	g.Expect(*purge.Expires).To(Equal(int64(24)))
+	g.Expect(*purge.RemainSpace).To(Equal(int64(defaultRelayPurgeRemainSpace)))
+
+	masterClient.AddReaction(dmapi.GetSourcesActionType, func(action *dmapi.Action) (interface{}, error) {
+		return []*dmapi.SourceInfo{
+			{SourceName: "synced", Purge: &purge},
+			{SourceName: "default"},
+		}, nil
+	})
+	updated := map[string]dmapi.SourcePurge{}
+	masterClient.AddReaction(dmapi.UpdateSourcePurgeActionType, func(action *dmapi.Action) (interface{}, error) {
+		updated[action.Name] = action.Purge
+		return nil, nil
+	})
+	g.Expect(wmm.syncRelayPurge(dc)).To(Succeed())
+	g.Expect(updated).To(Equal(map[string]dmapi.SourcePurge{"default": purge}))
+
+	masterCM, err := getMasterConfigMap(dc)
+	g.Expect(err).To(Succeed())
+	g.Expect(masterCM.Data["config-file"]).To(ContainSubstring("openapi = true"))
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// syncPDLearners records the learners of the pd cluster in the status, and promotes the learners whose
+// ordinals are less than spec.pd.replicas to voters. The learners are added by the discovery service
+// when they start.
+func (m *pdMemberManager) syncPDLearners(tc *v1alpha1.TidbCluster) error {
+	if tc.PDLearnerReplicas() == 0 && len(tc.Status.PD.Learners) == 0 {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	etcdClient, err := m.deps.PDControl.GetPDEtcdClient(pdapi.Namespace(ns), tcName, tc.IsTLSClusterEnabled(), pdapi.ClusterRef(tc.Spec.ClusterDomain))
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+	members, err := etcdClient.ListMembers()
+	if err != nil {
+		return err
+	}
+
+	var (
+		learners   []string
+		promoteErr error
+	)
+	for _, member := range members {
+		// skip the voters and the learners not started yet
+		if !member.IsLearner || member.Name == "" {
+			continue
+		}
+		podName := strings.SplitN(member.Name, ".", 2)[0]
+		// skip the members of the other clusters
+		if !strings.HasPrefix(podName, controller.PDMemberName(tcName)+"-") {
+			continue
+		}
+		ordinal, err := util.GetOrdinalFromPodName(podName)
+		if err != nil || ordinal >= tc.Spec.PD.Replicas {
+			learners = append(learners, member.Name)
+			continue
+		}
+		if err := etcdClient.PromoteMember(member.ID); err != nil {
+			learners = append(learners, member.Name)
+			promoteErr = controller.RequeueErrorf("TidbCluster: [%s/%s]'s pd learner %s can't be promoted yet: %v", ns, tcName, member.Name, err)
+			continue
+		}
+		klog.Infof("TidbCluster: [%s/%s]'s pd learner %s is promoted to a voter", ns, tcName, member.Name)
+		m.deps.Recorder.Event(tc, corev1.EventTypeNormal, "PDLearnerPromoted", fmt.Sprintf("pd learner %s is promoted to a voter", member.Name))
+	}
+	sort.Strings(learners)
+	tc.Status.PD.Learners = learners
+	return promoteErr
+}
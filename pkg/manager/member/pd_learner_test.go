@@ -0,0 +1,64 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSyncPDLearners(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pmm, _, _ := newFakePDMemberManager()
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.Replicas = 3
+	tc.Spec.PD.LearnerReplicas = 1
+	etcdClient := pdapi.NewFakePDEtcdClient()
+	etcdClient.Members = []*pdapi.EtcdMember{
+		{ID: 1, Name: "test-pd-0"},
+		{ID: 2, Name: "test-pd-1"},
+		{ID: 3, Name: "test-pd-2"},
+		{ID: 4, Name: "test-pd-3", IsLearner: true},
+		// not started yet
+		{ID: 5, IsLearner: true},
+	}
+	pmm.deps.PDControl.(*pdapi.FakePDControl).SetPDEtcdClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), etcdClient)
+
+	g.Expect(pmm.syncPDLearners(tc)).To(Succeed())
+	g.Expect(tc.Status.PD.Learners).To(ConsistOf("test-pd-3"))
+	g.Expect(etcdClient.Members[3].IsLearner).To(BeTrue())
+
+	// the learner moved to the replicas is promoted once it's in sync
+	tc.Spec.PD.Replicas = 4
+	tc.Spec.PD.LearnerReplicas = 0
+	etcdClient.PromoteErr = fmt.Errorf("can only promote a learner member which is in sync with leader")
+	err := pmm.syncPDLearners(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.Learners).To(ConsistOf("test-pd-3"))
+
+	etcdClient.PromoteErr = nil
+	g.Expect(pmm.syncPDLearners(tc)).To(Succeed())
+	g.Expect(tc.Status.PD.Learners).To(BeEmpty())
+	g.Expect(etcdClient.Members[3].IsLearner).To(BeFalse())
+
+	// nothing is checked once there is no learner
+	pmm.deps.PDControl.(*pdapi.FakePDControl).SetPDEtcdClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), nil)
+	g.Expect(pmm.syncPDLearners(tc)).To(Succeed())
+}
@@ -90,7 +90,7 @@ func (m *pdMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	component := v1alpha1.PDMemberType
 	needSuspend, err := m.suspender.SuspendComponent(tc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, tc.GetNamespace(), tc.GetName())
@@ -111,6 +111,14 @@ func (m *pdMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	return m.syncPDStatefulSetForTidbCluster(tc)
 }
 
+// SyncStatus refreshes the status of PD from its statefulset without acting upon the spec
+func (m *pdMemberManager) SyncStatus(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.PD == nil {
+		return nil
+	}
+	return syncStatusFromStatefulSet(m.deps, tc, controller.PDMemberName(tc.GetName()), m.syncTidbClusterStatus)
+}
+
 func (m *pdMemberManager) syncPDServiceForTidbCluster(tc *v1alpha1.TidbCluster) error {
 	if tc.Spec.Paused {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for pd service", tc.GetNamespace(), tc.GetName())
@@ -206,6 +214,16 @@ func (m *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return nil
 	}
 
+	if err := m.syncPDLeaderPriorities(tc); err != nil {
+		klog.Errorf("failed to sync leader priorities of TidbCluster: [%s/%s]'s pd members, error: %v", ns, tcName, err)
+	}
+	if err := m.syncPDLearners(tc); err != nil {
+		klog.Errorf("failed to sync learners of TidbCluster: [%s/%s]'s pd, error: %v", ns, tcName, err)
+	}
+	if err := m.syncPDRuntimeConfig(tc); err != nil {
+		klog.Errorf("failed to sync runtime config of TidbCluster: [%s/%s]'s pd, error: %v", ns, tcName, err)
+	}
+
 	cm, err := m.syncPDConfigMap(tc, oldPDSet)
 	if err != nil {
 		return err
@@ -214,6 +232,7 @@ func (m *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	if err != nil {
 		return err
 	}
+	degradeNativeSidecars(m.deps.Capabilities, newPDSet)
 	if setNotExist {
 		err = mngerutils.SetStatefulSetLastAppliedConfigAnnotation(newPDSet)
 		if err != nil {
@@ -435,13 +454,92 @@ func (m *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *a
 	return nil
 }
 
+// syncPDLeaderPriorities sets the leader priorities of the pd members according to spec.pd.leaderPriorities,
+// the priorities are compared with the ones in PD in each sync, so the new members are also reconciled
+func (m *pdMemberManager) syncPDLeaderPriorities(tc *v1alpha1.TidbCluster) error {
+	if len(tc.Spec.PD.LeaderPriorities) == 0 || !tc.Status.PD.Synced {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	pdClient := controller.GetPDClient(m.deps.PDControl, tc)
+	for name := range tc.Status.PD.Members {
+		podName := strings.SplitN(name, ".", 2)[0]
+		priority, err := m.desiredPDLeaderPriority(tc, podName)
+		if err != nil {
+			return err
+		}
+		current, err := pdClient.GetMemberLeaderPriority(name)
+		if err != nil {
+			return err
+		}
+		if current == priority {
+			continue
+		}
+		if err := pdClient.SetMemberLeaderPriority(name, priority); err != nil {
+			return err
+		}
+		klog.Infof("TidbCluster: [%s/%s]'s pd member %s leader priority is set from %d to %d", ns, tc.GetName(), name, current, priority)
+	}
+	return nil
+}
+
+// desiredPDLeaderPriority returns the priority of the first item in spec.pd.leaderPriorities
+// matching the pd pod, 0 is returned if no item matches
+func (m *pdMemberManager) desiredPDLeaderPriority(tc *v1alpha1.TidbCluster, podName string) (int, error) {
+	ordinal, err := util.GetOrdinalFromPodName(podName)
+	if err != nil {
+		return 0, err
+	}
+	var zone *string
+	for _, p := range tc.Spec.PD.LeaderPriorities {
+		if len(p.Ordinals) > 0 {
+			for _, o := range p.Ordinals {
+				if o == ordinal {
+					return p.Priority, nil
+				}
+			}
+			continue
+		}
+		if zone == nil {
+			z, err := m.getPodZone(tc.GetNamespace(), podName)
+			if err != nil {
+				return 0, err
+			}
+			zone = &z
+		}
+		if *zone != "" && *zone == p.Zone {
+			return p.Priority, nil
+		}
+	}
+	return 0, nil
+}
+
+// getPodZone returns the zone of the node the pod runs on
+func (m *pdMemberManager) getPodZone(ns, podName string) (string, error) {
+	pod, err := m.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s/%s: %v", ns, podName, err)
+	}
+	if pod.Spec.NodeName == "" {
+		return "", nil
+	}
+	if m.deps.NodeLister == nil {
+		return "", fmt.Errorf("no permission to get node %s of pod %s/%s", pod.Spec.NodeName, ns, podName)
+	}
+	node, err := m.deps.NodeLister.Get(pod.Spec.NodeName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get node %s of pod %s/%s: %v", pod.Spec.NodeName, ns, podName, err)
+	}
+	return node.Labels[corev1.LabelTopologyZone], nil
+}
+
 // syncPDConfigMap syncs the configmap of PD
 func (m *pdMemberManager) syncPDConfigMap(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
 	// For backward compatibility, only sync tidb configmap when .pd.config is non-nil
 	if tc.Spec.PD.Config == nil {
 		return nil, nil
 	}
-	newCm, err := getPDConfigMap(tc)
+	newCm, err := getPDConfigMap(m.deps.ConfigDefaults.Apply(tc, v1alpha1.PDMemberType))
 	if err != nil {
 		return nil, err
 	}
@@ -740,6 +838,16 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 		return nil, fmt.Errorf("get delete slots number of statefulset %s/%s failed, err:%v", ns, setName, err)
 	}
 
+	var containers []corev1.Container
+	logVolSpec, err := buildLogVolumePodSpec(tc, v1alpha1.PDMemberType, tc.Spec.PD.LogVolume, tc.Spec.PD.StorageVolumes, tc.Spec.PD.StorageClassName, tc.Spec.PD.AdditionalVolumeMounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log volume for cluster %s/%s: %v", ns, tcName, err)
+	}
+	vols = append(vols, logVolSpec.volumes...)
+	volMounts = append(volMounts, logVolSpec.volumeMounts...)
+	initContainers = append(initContainers, logVolSpec.initContainers...)
+	containers = append(containers, logVolSpec.containers...)
+
 	pdContainer := corev1.Container{
 		Name:            v1alpha1.PDMemberType.String(),
 		Image:           tc.PDImage(),
@@ -815,10 +923,11 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 			},
 		})
 	}
-	pdContainer.Env = util.AppendEnv(env, basePDSpec.Env())
+	pdContainer.Env = util.AppendEnv(util.AppendEnv(env, basePDSpec.Env()), goRuntimeResourceEnv(basePDSpec, pdContainer.Resources))
 	pdContainer.EnvFrom = basePDSpec.EnvFrom()
 	podSpec.Volumes = append(vols, basePDSpec.AdditionalVolumes()...)
-	podSpec.Containers, err = MergePatchContainers([]corev1.Container{pdContainer}, basePDSpec.AdditionalContainers())
+	containers = append(containers, pdContainer)
+	podSpec.Containers, err = MergePatchContainers(containers, basePDSpec.AdditionalContainers())
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge containers spec for PD of [%s/%s], error: %v", tc.Namespace, tc.Name, err)
 	}
@@ -904,6 +1013,14 @@ func getPDConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 		config.Set("dashboard.internal-proxy", *tc.Spec.PD.EnableDashboardInternalProxy)
 	}
 
+	if tc.Spec.PD.LogVolume != nil {
+		_, _, logFile, err := getLogVolume(v1alpha1.PDMemberType, tc.Spec.PD.LogVolume, tc.Spec.PD.StorageVolumes, tc.Spec.PD.StorageClassName, tc.Spec.PD.AdditionalVolumeMounts)
+		if err != nil {
+			return nil, fmt.Errorf("get log volume for tc %s/%s failed: %v", tc.Namespace, tc.Name, err)
+		}
+		setLogFileConfig(config.GenericConfig, logFile, tc.Spec.PD.LogVolume.Rotation)
+	}
+
 	confText, err := config.MarshalTOML()
 	if err != nil {
 		return nil, err
@@ -1025,3 +1142,7 @@ func (m *FakePDMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	}
 	return nil
 }
+
+func (m *FakePDMemberManager) SyncStatus(*v1alpha1.TidbCluster) error {
+	return nil
+}
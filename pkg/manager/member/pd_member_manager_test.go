@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
+	"k8s.io/utils/ptr"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
@@ -927,6 +928,60 @@ func TestPDMemberManagerSyncPDSts(t *testing.T) {
 	}
 }
 
+func TestPDMemberManagerSyncPDLeaderPriorities(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pmm, podIndexer, _ := newFakePDMemberManager()
+	nodeIndexer := pmm.deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.LeaderPriorities = []v1alpha1.PDLeaderPriority{
+		{Ordinals: []int32{0}, Priority: 5},
+		{Zone: "zone-b", Priority: 3},
+	}
+	tc.Status.PD.Synced = true
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{}
+	for i, zone := range []string{"zone-a", "zone-b", "zone-b"} {
+		podName := PdPodName(tc.Name, int32(i))
+		nodeName := fmt.Sprintf("node-%d", i)
+		tc.Status.PD.Members[podName] = v1alpha1.PDMember{Name: podName, Health: true}
+		g.Expect(podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: tc.Namespace},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		})).To(Succeed())
+		g.Expect(nodeIndexer.Add(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName, Labels: map[string]string{corev1.LabelTopologyZone: zone}},
+		})).To(Succeed())
+	}
+
+	priorities := map[string]int{PdPodName(tc.Name, 2): 3, PdPodName(tc.Name, 3): 1}
+	pdClient := controller.NewFakePDClient(pmm.deps.PDControl.(*pdapi.FakePDControl), tc)
+	pdClient.AddReaction(pdapi.GetMemberLeaderPriorityActionType, func(action *pdapi.Action) (interface{}, error) {
+		return priorities[action.Name], nil
+	})
+	updated := []string{}
+	pdClient.AddReaction(pdapi.SetMemberLeaderPriorityActionType, func(action *pdapi.Action) (interface{}, error) {
+		priorities[action.Name] = action.Priority
+		updated = append(updated, action.Name)
+		return nil, nil
+	})
+
+	g.Expect(pmm.syncPDLeaderPriorities(tc)).To(Succeed())
+	g.Expect(priorities).To(Equal(map[string]int{
+		PdPodName(tc.Name, 0): 5,
+		PdPodName(tc.Name, 1): 3,
+		PdPodName(tc.Name, 2): 3,
+		PdPodName(tc.Name, 3): 1,
+	}))
+	g.Expect(updated).To(ConsistOf(PdPodName(tc.Name, 0), PdPodName(tc.Name, 1)))
+
+	// the priorities are not updated again, and the member not matched any more is reset to 0
+	updated = updated[:0]
+	tc.Spec.PD.LeaderPriorities = tc.Spec.PD.LeaderPriorities[:1]
+	g.Expect(pmm.syncPDLeaderPriorities(tc)).To(Succeed())
+	g.Expect(updated).To(ConsistOf(PdPodName(tc.Name, 1), PdPodName(tc.Name, 2)))
+	g.Expect(priorities[PdPodName(tc.Name, 1)]).To(BeZero())
+}
+
 func newFakePDMemberManager() (*pdMemberManager, cache.Indexer, cache.Indexer) {
 	fakeDeps := controller.NewFakeDependencies()
 	podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
@@ -1980,6 +2035,95 @@ func TestGetNewPDSetForTidbCluster(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name: "pd with log volume",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD: &v1alpha1.PDSpec{
+						LogVolume: &v1alpha1.LogVolumeSpec{
+							SizeLimit: resource.NewQuantity(1024, resource.BinarySI),
+							Tailer:    &v1alpha1.LogTailerSpec{},
+						},
+					},
+					TiKV: &v1alpha1.TiKVSpec{},
+					TiDB: &v1alpha1.TiDBSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				podSpec := sts.Spec.Template.Spec
+				g.Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "log-volume",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: resource.NewQuantity(1024, resource.BinarySI)},
+					},
+				}))
+				logVolMount := corev1.VolumeMount{Name: "log-volume", MountPath: "/var/log/pd-log"}
+				g.Expect(podSpec.Containers).To(HaveLen(2))
+				g.Expect(podSpec.Containers[0].Name).To(Equal(v1alpha1.ContainerLogTailer.String()))
+				g.Expect(podSpec.Containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{logVolMount}))
+				g.Expect(podSpec.Containers[0].Command).To(ContainElement(ContainSubstring("/var/log/pd-log/pd.log")))
+				g.Expect(podSpec.Containers[1].Name).To(Equal(v1alpha1.PDMemberType.String()))
+				g.Expect(podSpec.Containers[1].VolumeMounts).To(ContainElement(logVolMount))
+			},
+		},
+		{
+			name: "pd with log volume in storage volumes",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD: &v1alpha1.PDSpec{
+						StorageVolumes: []v1alpha1.StorageVolume{
+							{Name: "log", StorageSize: "1Gi", MountPath: "/var/lib/log"},
+						},
+						LogVolume: &v1alpha1.LogVolumeSpec{
+							VolumeName: "log",
+							Tailer:     &v1alpha1.LogTailerSpec{UseSidecar: true},
+						},
+					},
+					TiKV: &v1alpha1.TiKVSpec{},
+					TiDB: &v1alpha1.TiDBSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				podSpec := sts.Spec.Template.Spec
+				for _, vol := range podSpec.Volumes {
+					g.Expect(vol.Name).NotTo(Equal("log-volume"))
+				}
+				g.Expect(podSpec.Containers).To(HaveLen(1))
+				g.Expect(podSpec.InitContainers).To(HaveLen(1))
+				g.Expect(podSpec.InitContainers[0].Name).To(Equal(v1alpha1.ContainerLogTailer.String()))
+				g.Expect(podSpec.InitContainers[0].RestartPolicy).To(Equal(ptr.To(corev1.ContainerRestartPolicyAlways)))
+				g.Expect(podSpec.InitContainers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: "pd-log", MountPath: "/var/lib/log"}}))
+				g.Expect(podSpec.InitContainers[0].Command).To(ContainElement(ContainSubstring("/var/lib/log/pd.log")))
+			},
+		},
+		{
+			name: "pd with unknown log volume",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD: &v1alpha1.PDSpec{
+						LogVolume: &v1alpha1.LogVolumeSpec{VolumeName: "log"},
+					},
+					TiKV: &v1alpha1.TiKVSpec{},
+					TiDB: &v1alpha1.TiDBSpec{},
+				},
+			},
+			wantErr: true,
+			testSts: func(sts *apps.StatefulSet) {},
+		},
 	}
 
 	for i := range tests {
@@ -100,7 +100,9 @@ func (m *pdMSMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 		tc.Status.PDMS = make(map[string]*v1alpha1.PDMSStatus)
 	}
 
-	for _, comp := range tc.Spec.PDMS {
+	// sync the PD microservices in the upgrade order, so that the upgrade of a microservice is started
+	// only after the ones before it have started in the same round
+	for _, comp := range sortPDMSByUpgradeOrder(tc.Spec.PDMS) {
 		curSpec := comp
 		if tc.Status.PDMS[curSpec.Name] == nil {
 			tc.Status.PDMS[curSpec.Name] = &v1alpha1.PDMSStatus{Name: curSpec.Name}
@@ -122,7 +124,7 @@ func (m *pdMSMemberManager) syncSingleService(tc *v1alpha1.TidbCluster, curSpec
 	componentMemberType := v1alpha1.PDMSMemberType(curService)
 	needSuspend, err := m.suspender.SuspendComponent(tc, componentMemberType)
 	if err != nil {
-		return fmt.Errorf("PDMS component %s for cluster [%s/%s] suspend failed: %v", curService, tc.GetNamespace(), tc.GetName(), err)
+		return fmt.Errorf("PDMS component %s for cluster [%s/%s] suspend failed: %w", curService, tc.GetNamespace(), tc.GetName(), err)
 	}
 	if needSuspend {
 		klog.Infof("PDMS component %s for cluster [%s/%s] is suspended, skip syncing", curService, tc.GetNamespace(), tc.GetName())
@@ -675,7 +677,7 @@ func (m *pdMSMemberManager) getNewPDMSStatefulSet(tc *v1alpha1.TidbCluster, cm *
 			},
 		})
 	}
-	pdMSContainer.Env = util.AppendEnv(env, basePDMSSpec.Env())
+	pdMSContainer.Env = util.AppendEnv(util.AppendEnv(env, basePDMSSpec.Env()), goRuntimeResourceEnv(basePDMSSpec, pdMSContainer.Resources))
 	pdMSContainer.EnvFrom = basePDMSSpec.EnvFrom()
 	podSpec.Volumes = append(vols, basePDMSSpec.AdditionalVolumes()...)
 	podSpec.Containers, err = MergePatchContainers([]corev1.Container{pdMSContainer}, basePDMSSpec.AdditionalContainers())
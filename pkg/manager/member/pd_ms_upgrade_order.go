@@ -0,0 +1,73 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+// pdMSUpgradeOrder is the order to upgrade the PD microservices in, the scheduling service is upgraded
+// before the TSO service, and PD is upgraded after all the PD microservices.
+var pdMSUpgradeOrder = []string{label.PDMSSchedulingLabelVal, label.PDMSTSOLabelVal}
+
+func pdMSUpgradeRank(name string) int {
+	for i, n := range pdMSUpgradeOrder {
+		if n == name {
+			return i
+		}
+	}
+	return len(pdMSUpgradeOrder)
+}
+
+// sortPDMSByUpgradeOrder returns a copy of the PD microservice specs sorted in the upgrade order
+func sortPDMSByUpgradeOrder(specs []*v1alpha1.PDMSSpec) []*v1alpha1.PDMSSpec {
+	sorted := make([]*v1alpha1.PDMSSpec, len(specs))
+	copy(sorted, specs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return pdMSUpgradeRank(sorted[i].Name) < pdMSUpgradeRank(sorted[j].Name)
+	})
+	return sorted
+}
+
+// pdMSUpgradeBlocker returns the reason why the upgrade of the PD microservice can't be started now,
+// "" is returned if it can. An upgrade in progress is never blocked, so that the components upgraded
+// at the same time by an older version of the operator don't wait for each other.
+func pdMSUpgradeBlocker(tc *v1alpha1.TidbCluster, name string) string {
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase {
+		return "pd is upgrading"
+	}
+	for _, spec := range sortPDMSByUpgradeOrder(tc.Spec.PDMS) {
+		if spec.Name == name {
+			break
+		}
+		if status := tc.Status.PDMS[spec.Name]; status != nil && status.Phase == v1alpha1.UpgradePhase {
+			return fmt.Sprintf("pd microservice %s is upgrading", spec.Name)
+		}
+	}
+	return ""
+}
+
+// pdUpgradeBlocker returns the reason why the upgrade of PD can't be started now, "" is returned if it can
+func pdUpgradeBlocker(tc *v1alpha1.TidbCluster) string {
+	for _, spec := range sortPDMSByUpgradeOrder(tc.Spec.PDMS) {
+		if status := tc.Status.PDMS[spec.Name]; status != nil && status.Phase == v1alpha1.UpgradePhase {
+			return fmt.Sprintf("pd microservice %s is upgrading", spec.Name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func TestPDMSUpgradeOrder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Spec.PDMS = []*v1alpha1.PDMSSpec{{Name: "tso"}, {Name: "scheduling"}}
+	tc.Status.PDMS = map[string]*v1alpha1.PDMSStatus{
+		"tso":        {Name: "tso", Phase: v1alpha1.NormalPhase},
+		"scheduling": {Name: "scheduling", Phase: v1alpha1.NormalPhase},
+	}
+
+	var names []string
+	for _, spec := range sortPDMSByUpgradeOrder(tc.Spec.PDMS) {
+		names = append(names, spec.Name)
+	}
+	g.Expect(names).To(Equal([]string{"scheduling", "tso"}))
+	g.Expect(tc.Spec.PDMS[0].Name).To(Equal("tso"))
+
+	// nothing is upgrading
+	g.Expect(pdMSUpgradeBlocker(tc, "scheduling")).To(BeEmpty())
+	g.Expect(pdMSUpgradeBlocker(tc, "tso")).To(BeEmpty())
+	g.Expect(pdUpgradeBlocker(tc)).To(BeEmpty())
+
+	// tso and pd wait for scheduling
+	tc.Status.PDMS["scheduling"].Phase = v1alpha1.UpgradePhase
+	g.Expect(pdMSUpgradeBlocker(tc, "tso")).To(ContainSubstring("scheduling"))
+	g.Expect(pdUpgradeBlocker(tc)).To(ContainSubstring("scheduling"))
+
+	// scheduling doesn't wait for tso
+	tc.Status.PDMS["scheduling"].Phase = v1alpha1.NormalPhase
+	tc.Status.PDMS["tso"].Phase = v1alpha1.UpgradePhase
+	g.Expect(pdMSUpgradeBlocker(tc, "scheduling")).To(BeEmpty())
+	g.Expect(pdUpgradeBlocker(tc)).To(ContainSubstring("tso"))
+
+	// the microservices don't start upgrading while pd is upgrading
+	tc.Status.PDMS["tso"].Phase = v1alpha1.NormalPhase
+	tc.Status.PD.Phase = v1alpha1.UpgradePhase
+	g.Expect(pdMSUpgradeBlocker(tc, "scheduling")).To(ContainSubstring("pd"))
+}
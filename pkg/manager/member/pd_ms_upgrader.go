@@ -74,10 +74,20 @@ func (u *pdMSUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.St
 		return nil
 	}
 
-	tc.Status.PDMS[curService].Phase = v1alpha1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
+		if reason := pdMSUpgradeBlocker(tc, curService); reason != "" {
+			klog.Infof("TidbCluster: [%s/%s]'s pdMS %s can not be upgraded now because %s", ns, tcName, curService, reason)
+			_, podSpec, err := GetLastAppliedConfig(oldSet)
+			if err != nil {
+				return err
+			}
+			newSet.Spec.Template.Spec = *podSpec
+			return nil
+		}
+		tc.Status.PDMS[curService].Phase = v1alpha1.UpgradePhase
 		return nil
 	}
+	tc.Status.PDMS[curService].Phase = v1alpha1.UpgradePhase
 
 	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
 		// Manually bypass tidb-operator to modify statefulset directly, such as modify pd statefulset's RollingUpdate straregy to OnDelete strategy,
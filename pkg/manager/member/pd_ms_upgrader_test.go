@@ -209,6 +209,38 @@ func TestPDMSUpgraderUpgrade(t *testing.T) {
 			},
 		},
 
+		{
+			name: "wait for the scheduling service to be upgraded",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PDMS[tsoService].Synced = true
+				tc.Spec.PDMS = append(tc.Spec.PDMS, &v1alpha1.PDMSSpec{Name: "scheduling"})
+				tc.Status.PDMS["scheduling"] = &v1alpha1.PDMSStatus{Name: "scheduling", Phase: v1alpha1.UpgradePhase}
+			},
+			changeOldSet: func(set *apps.StatefulSet) {
+				set.Spec.Template.Spec.Containers[0].Image = "pd-old-image"
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PDMS[tsoService].Phase).To(Equal(v1alpha1.NormalPhase))
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("pd-old-image"))
+			},
+		},
+		{
+			name: "the upgrade in progress is not blocked by pd",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PDMS[tsoService].Synced = true
+				tc.Status.PD.Phase = v1alpha1.UpgradePhase
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PDMS[tsoService].Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
 		{
 			name: "ignore pd peers health if annotation is not set",
 			changeFn: func(tc *v1alpha1.TidbCluster) {
@@ -0,0 +1,148 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// pdRuntimeConfigSections are the sections of spec.pd.config reconciled with the runtime config of PD
+var pdRuntimeConfigSections = []string{"schedule", "replication"}
+
+// syncPDRuntimeConfig reverts the schedule.* and replication.* items of the runtime config of PD
+// changed out of the spec if spec.pd.enforceRuntimeConfig is enabled
+func (m *pdMemberManager) syncPDRuntimeConfig(tc *v1alpha1.TidbCluster) error {
+	if !tc.Spec.PD.EnforceRuntimeConfig || tc.Spec.PD.Config == nil || !tc.Status.PD.Synced {
+		return nil
+	}
+	pdClient := controller.GetPDClient(m.deps.PDControl, tc)
+	runtime, err := pdClient.GetConfig()
+	if err != nil {
+		return err
+	}
+	drift, err := pdRuntimeConfigDrift(tc.Spec.PD.Config, runtime)
+	if err != nil {
+		return err
+	}
+	// schedule.region-schedule-limit is tuned for the rebalancing after scaling out tikv
+	if tc.Status.TiKV.ScaleOutBalance != nil && tc.Status.TiKV.ScaleOutBalance.RegionScheduleLimit != nil {
+		delete(drift, pdRegionScheduleLimitKey)
+	}
+	if len(drift) == 0 {
+		return nil
+	}
+	if err := pdClient.UpdateConfig(drift); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(drift))
+	for key := range drift {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	klog.Infof("TidbCluster: [%s/%s]'s pd runtime config %v drifted from the spec and are reverted", tc.GetNamespace(), tc.GetName(), keys)
+	m.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDConfigDriftReverted", "pd runtime config %s drifted from the spec and are reverted", strings.Join(keys, ", "))
+	return nil
+}
+
+// pdRuntimeConfigDrift returns the items of spec.pd.config in the sections reconciled whose values
+// differ from the runtime config. The items not returned by the PD API are ignored.
+func pdRuntimeConfigDrift(desired *v1alpha1.PDConfigWraper, runtime *pdapi.PDConfigFromAPI) (map[string]interface{}, error) {
+	data, err := json.Marshal(runtime)
+	if err != nil {
+		return nil, err
+	}
+	items := map[string]interface{}{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	current := config.New(items)
+
+	drift := map[string]interface{}{}
+	for _, section := range pdRuntimeConfigSections {
+		items, ok := desired.Inner()[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range flattenConfig(section, items) {
+			currentValue := current.Get(key)
+			if currentValue == nil {
+				continue
+			}
+			if !configValueEqual(value, currentValue.Interface()) {
+				drift[key] = value
+			}
+		}
+	}
+	return drift, nil
+}
+
+// flattenConfig flattens the nested tables into the keys joined by "."
+func flattenConfig(prefix string, items map[string]interface{}) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for k, v := range items {
+		key := prefix + "." + k
+		if table, ok := v.(map[string]interface{}); ok {
+			for kk, vv := range flattenConfig(key, table) {
+				ret[kk] = vv
+			}
+			continue
+		}
+		ret[key] = v
+	}
+	return ret
+}
+
+// configValueEqual compares the value in the spec with the value returned by the PD API.
+// The PD API returns the booleans as strings, the string slices joined by "," and the
+// durations normalized, e.g. "1h0m0s".
+func configValueEqual(desired, current interface{}) bool {
+	return normalizeConfigValue(desired) == normalizeConfigValue(current)
+}
+
+func normalizeConfigValue(v interface{}) string {
+	switch val := v.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(val))
+		for _, item := range val {
+			items = append(items, normalizeConfigValue(item))
+		}
+		return strings.Join(items, ",")
+	case []string:
+		return strings.Join(val, ",")
+	case string:
+		if d, err := time.ParseDuration(val); err == nil {
+			return d.String()
+		}
+		return val
+	case float64:
+		if val == float64(int64(val)) {
+			return fmt.Sprint(int64(val))
+		}
+		return fmt.Sprint(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"k8s.io/utils/pointer"
+)
+
+func TestPDRuntimeConfigDrift(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	desired := v1alpha1.NewPDConfig()
+	g.Expect(desired.UnmarshalTOML([]byte(`
+[log]
+level = "warn"
+[schedule]
+leader-schedule-limit = 4
+region-schedule-limit = 2048
+max-store-down-time = "1h"
+enable-witness = true
+unknown-item = 1
+[replication]
+max-replicas = 5
+location-labels = ["zone", "host"]
+`))).To(Succeed())
+
+	runtime := &pdapi.PDConfigFromAPI{
+		Log: &pdapi.PDLogConfig{Level: "info"},
+		Schedule: &pdapi.PDScheduleConfig{
+			LeaderScheduleLimit: pointer.Uint64Ptr(4),
+			RegionScheduleLimit: pointer.Uint64Ptr(1024),
+			MaxStoreDownTime:    "1h0m0s",
+			EnableWitness:       pointer.BoolPtr(true),
+		},
+		Replication: &pdapi.PDReplicationConfig{
+			MaxReplicas:    pointer.Uint64Ptr(3),
+			LocationLabels: pdapi.StringSlice{"zone", "host"},
+		},
+	}
+
+	drift, err := pdRuntimeConfigDrift(desired, runtime)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drift).To(Equal(map[string]interface{}{
+		"schedule.region-schedule-limit": int64(2048),
+		"replication.max-replicas":       int64(5),
+	}))
+}
+
+func TestPDMemberManagerSyncPDRuntimeConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pmm, _, _ := newFakePDMemberManager()
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.Config = v1alpha1.NewPDConfig()
+	tc.Spec.PD.Config.Set("schedule.leader-schedule-limit", int64(8))
+	tc.Status.PD.Synced = true
+
+	runtime := &pdapi.PDConfigFromAPI{
+		Schedule: &pdapi.PDScheduleConfig{LeaderScheduleLimit: pointer.Uint64Ptr(4)},
+	}
+	pdClient := controller.NewFakePDClient(pmm.deps.PDControl.(*pdapi.FakePDControl), tc)
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return runtime, nil
+	})
+	var updated map[string]interface{}
+	pdClient.AddReaction(pdapi.UpdateConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		updated = action.Config
+		return nil, nil
+	})
+
+	// the runtime config is not reconciled by default
+	g.Expect(pmm.syncPDRuntimeConfig(tc)).To(Succeed())
+	g.Expect(updated).To(BeNil())
+
+	tc.Spec.PD.EnforceRuntimeConfig = true
+	g.Expect(pmm.syncPDRuntimeConfig(tc)).To(Succeed())
+	g.Expect(updated).To(Equal(map[string]interface{}{"schedule.leader-schedule-limit": int64(8)}))
+
+	// nothing is updated without drift
+	updated = nil
+	runtime.Schedule.LeaderScheduleLimit = pointer.Uint64Ptr(8)
+	g.Expect(pmm.syncPDRuntimeConfig(tc)).To(Succeed())
+	g.Expect(updated).To(BeNil())
+}
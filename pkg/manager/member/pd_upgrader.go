@@ -15,6 +15,7 @@ package member
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
@@ -71,10 +72,24 @@ func (u *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stat
 		return nil
 	}
 
-	tc.Status.PD.Phase = v1alpha1.UpgradePhase
+	if err := acquireDisruptionLock(u.deps, tc, DisruptionPDUpgrade); err != nil {
+		return err
+	}
+
 	if !templateEqual(newSet, oldSet) {
+		if reason := pdUpgradeBlocker(tc); reason != "" {
+			klog.Infof("TidbCluster: [%s/%s]'s pd can not be upgraded now because %s", ns, tcName, reason)
+			_, podSpec, err := GetLastAppliedConfig(oldSet)
+			if err != nil {
+				return err
+			}
+			newSet.Spec.Template.Spec = *podSpec
+			return nil
+		}
+		tc.Status.PD.Phase = v1alpha1.UpgradePhase
 		return nil
 	}
+	tc.Status.PD.Phase = v1alpha1.UpgradePhase
 
 	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
 		// Manually bypass tidb-operator to modify statefulset directly, such as modify pd statefulset's RollingUpdate straregy to OnDelete strategy,
@@ -222,7 +237,8 @@ func choosePDToTransferFromMembers(tc *v1alpha1.TidbCluster, newSet *apps.Statef
 		return pdName
 	}
 	pred := func(pdName string) bool {
-		return tc.Status.PD.Members[pdName].Health
+		// the learners can't be the leader
+		return tc.Status.PD.Members[pdName].Health && !slices.Contains(tc.Status.PD.Learners, pdName)
 	}
 
 	// set ordinal to max ordinal if ordinal isn't exist
@@ -81,7 +81,7 @@ func (m *pumpMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	component := v1alpha1.PumpMemberType
 	needSuspend, err := m.suspender.SuspendComponent(tc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, tc.GetNamespace(), tc.GetName())
@@ -94,6 +94,14 @@ func (m *pumpMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	return m.syncPumpStatefulSetForTidbCluster(tc)
 }
 
+// SyncStatus refreshes the status of Pump from its statefulset without acting upon the spec
+func (m *pumpMemberManager) SyncStatus(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Pump == nil {
+		return nil
+	}
+	return syncStatusFromStatefulSet(m.deps, tc, controller.PumpMemberName(tc.GetName()), m.syncTiDBClusterStatus)
+}
+
 // syncPumpStatefulSetForTidbCluster sync statefulset status of pump to tidbcluster
 func (m *pumpMemberManager) syncPumpStatefulSetForTidbCluster(tc *v1alpha1.TidbCluster) error {
 	oldPumpSetTemp, err := m.deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(controller.PumpMemberName(tc.Name))
@@ -160,11 +168,18 @@ func (p *pumpMemberManager) buildBinlogClient(tc *v1alpha1.TidbCluster, control
 func buildBinlogClient(tc *v1alpha1.TidbCluster, control pdapi.PDControlInterface) (client *binlog.Client, err error) {
 	var endpoints []string
 	var tlsConfig *tls.Config
-	if tc.Heterogeneous() && tc.WithoutLocalPD() {
+	if tc.HasExternalPD() {
+		endpoints, tlsConfig, err = control.GetEndpoints(pdapi.Namespace(tc.Namespace), tc.Name, tc.IsExternalPDTLSEnabled(),
+			controller.ExternalPDClientOptions(tc, tc.Spec.ExternalPD.Endpoints[0])...)
+		if err == nil {
+			endpoints = tc.Spec.ExternalPD.Endpoints
+		}
+	} else if tc.Heterogeneous() && tc.WithoutLocalPD() {
 		// connect to pd of other cluster and use own cert
 		endpoints, tlsConfig, err = control.GetEndpoints(pdapi.Namespace(tc.Spec.Cluster.Namespace), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled(),
 			pdapi.TLSCertFromTC(pdapi.Namespace(tc.Namespace), tc.Name),
 			pdapi.ClusterRef(tc.Spec.Cluster.ClusterDomain),
+			pdapi.ResolveWith(tc.Spec.AcrossK8sResolver),
 		)
 	} else {
 		endpoints, tlsConfig, err = control.GetEndpoints(pdapi.Namespace(tc.Namespace), tc.Name,
@@ -462,7 +477,7 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 				ContainerPort: v1alpha1.DefaultPumpPort,
 			}},
 			Resources:    controller.ContainerResource(tc.Spec.Pump.ResourceRequirements),
-			Env:          util.AppendEnv(envs, spec.Env()),
+			Env:          util.AppendEnv(util.AppendEnv(envs, spec.Env()), goRuntimeResourceEnv(spec, controller.ContainerResource(tc.Spec.Pump.ResourceRequirements))),
 			EnvFrom:      spec.EnvFrom(),
 			VolumeMounts: volumeMounts,
 			ReadinessProbe: &corev1.Probe{
@@ -598,3 +613,7 @@ func (m *FakePumpMemberManager) Sync(*v1alpha1.TidbCluster) error {
 	}
 	return nil
 }
+
+func (m *FakePumpMemberManager) SyncStatus(*v1alpha1.TidbCluster) error {
+	return nil
+}
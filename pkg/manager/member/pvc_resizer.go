@@ -550,6 +550,10 @@ func (p *pvcResizer) classifyVolumes(ctx *componentVolumeContext, volumes []*vol
 			klog.Warningf("Skip to resize PVC %q of %q: PVC have no storage class", pvcID, cid)
 			continue
 		}
+		if caps := p.deps.Capabilities; caps != nil && !caps.VolumeExpansion {
+			klog.Warningf("Skip to resize PVC %q of %q: volume expansion is not supported by Kubernetes %s", pvcID, cid, caps.ServerVersion)
+			continue
+		}
 		// check whether the storage class support
 		if p.deps.StorageClassLister != nil {
 			volumeExpansionSupported, err := isVolumeExpansionSupported(p.deps.StorageClassLister, *pvc.Spec.StorageClassName)
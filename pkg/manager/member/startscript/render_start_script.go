@@ -15,8 +15,10 @@ package startscript
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
 	v1 "github.com/pingcap/tidb-operator/pkg/manager/member/startscript/v1"
 	v2 "github.com/pingcap/tidb-operator/pkg/manager/member/startscript/v2"
 )
@@ -76,6 +78,16 @@ func RenderTiKVStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	return tikv[tc.StartScriptVersion()](tc)
 }
 
+// RenderTiKVWitnessStartScript renders the start script of the TiKV witness stores, which advertise
+// the addresses in the peer service of the witness stores instead of the peer service of TiKV.
+func RenderTiKVWitnessStartScript(tc *v1alpha1.TidbCluster) (string, error) {
+	script, err := RenderTiKVStartScript(tc)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(script, controller.TiKVPeerMemberName(tc.Name)+".", controller.TiKVWitnessPeerMemberName(tc.Name)+"."), nil
+}
+
 func RenderPDStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	// using start script v2 when enabled PDMS
 	if tc.Spec.PDMS != nil && (tc.Spec.PD != nil && tc.Spec.PD.Mode == "ms") {
@@ -55,7 +55,11 @@ func RenderTiKVStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	model.Addr = fmt.Sprintf("%s:%d", listenHost, v1alpha1.DefaultTiKVServerPort)
 	model.StatusAddr = fmt.Sprintf("%s:%d", listenHost, v1alpha1.DefaultTiKVStatusPort)
 
-	return renderTemplateFunc(tikvStartScriptTpl, model)
+	script, err := renderTemplateFunc(tikvStartScriptTpl, model)
+	if err != nil {
+		return "", err
+	}
+	return appendStartScriptWithArgs(script, "/tikv-server ${ARGS}", controller.FormatAdditionalArgs(tc.BaseTiKVSpec().AdditionalArgs())), nil
 }
 
 func RenderPDStartScript(tc *v1alpha1.TidbCluster) (string, error) {
@@ -79,7 +83,11 @@ func RenderPDStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 		).Parse(
 			replacePDStartScriptCustomPorts(pdStartScriptTplText)))
 
-	return renderTemplateFunc(pdStartScriptTpl, model)
+	script, err := renderTemplateFunc(pdStartScriptTpl, model)
+	if err != nil {
+		return "", err
+	}
+	return appendStartScriptWithArgs(script, "/pd-server ${ARGS}", controller.FormatAdditionalArgs(tc.BasePDSpec().AdditionalArgs())), nil
 }
 
 func RenderTiDBStartScript(tc *v1alpha1.TidbCluster) (string, error) {
@@ -100,8 +108,10 @@ func RenderTiDBStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 		model.Path = fmt.Sprintf("%s:%d", controller.PDMemberName(tc.Spec.Cluster.Name), v1alpha1.DefaultPDClientPort) // use pd of reference cluster
 	}
 
+	args := append([]string{}, tc.Spec.TiDB.Arguments...)
+	args = append(args, controller.FormatAdditionalArgs(tc.BaseTiDBSpec().AdditionalArgs())...)
 	var tidbStartScriptTpl = template.Must(template.New("tidb-start-script").Parse(
-		appendTiDBStartScriptWithArgs(tidbStartScriptTplText, tc.Spec.TiDB.Arguments)))
+		appendStartScriptWithArgs(tidbStartScriptTplText, "/tidb-server ${ARGS}", args)))
 	return renderTemplateFunc(tidbStartScriptTpl, model)
 }
 
@@ -119,16 +129,19 @@ func RenderPumpStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	}
 
 	pdAddr := fmt.Sprintf("%s://%s:%d", scheme, pdDomain, v1alpha1.DefaultPDClientPort)
+	additionalArgs := tc.BasePumpSpec().AdditionalArgs()
 	return renderTemplateFunc(pumpStartScriptTpl, &PumpStartScriptModel{
 		CommonModel: CommonModel{
 			AcrossK8s:     tc.AcrossK8s(),
 			ClusterDomain: tc.Spec.ClusterDomain,
 		},
-		Scheme:      scheme,
-		ClusterName: tc.Name,
-		PDAddr:      pdAddr,
-		LogLevel:    tc.PumpLogLevel(),
-		Namespace:   tc.GetNamespace(),
+		Scheme:        scheme,
+		ClusterName:   tc.Name,
+		PDAddr:        pdAddr,
+		LogLevel:      tc.PumpLogLevel(),
+		Namespace:     tc.GetNamespace(),
+		ExtraArgs:     strings.Join(controller.FormatAdditionalArgs(additionalArgs), " "),
+		CustomLogFile: controller.AdditionalArgsHaveFlag(additionalArgs, "log-file"),
 	})
 }
 
@@ -166,6 +179,7 @@ func RenderTiCDCStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	if tc.Spec.TiCDC.Config != nil && !tc.Spec.TiCDC.Config.OnlyOldItems() {
 		cmdArgs = append(cmdArgs, fmt.Sprintf("--config=%s", "/etc/ticdc/ticdc.toml"))
 	}
+	cmdArgs = append(cmdArgs, controller.FormatAdditionalArgs(tc.BaseTiCDCSpec().AdditionalArgs())...)
 
 	var script string
 
@@ -201,7 +215,9 @@ func RenderTiFlashStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	if tc.Spec.TiFlash.DoesMountCMInTiflashContainer() {
 		return RenderTiFlashStartScriptWithStartArgs(tc)
 	}
-	return "/tiflash/tiflash server --config-file /data0/config.toml", nil
+	cmdArgs := []string{"/tiflash/tiflash server --config-file /data0/config.toml"}
+	cmdArgs = append(cmdArgs, controller.FormatAdditionalArgs(tc.BaseTiFlashSpec().AdditionalArgs())...)
+	return strings.Join(cmdArgs, " "), nil
 }
 
 func RenderTiFlashStartScriptWithStartArgs(tc *v1alpha1.TidbCluster) (string, error) {
@@ -228,7 +244,11 @@ func RenderTiFlashStartScriptWithStartArgs(tc *v1alpha1.TidbCluster) (string, er
 
 	model.Addr = fmt.Sprintf("${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc%s:%d", controller.FormatClusterDomain(tc.Spec.ClusterDomain), v1alpha1.DefaultTiFlashFlashPort)
 
-	return renderTemplateFunc(tiflashStartScriptTpl, model)
+	script, err := renderTemplateFunc(tiflashStartScriptTpl, model)
+	if err != nil {
+		return "", err
+	}
+	return appendStartScriptWithArgs(script, "/tiflash/tiflash ${ARGS}", controller.FormatAdditionalArgs(tc.BaseTiFlashSpec().AdditionalArgs())), nil
 }
 
 func RenderTiFlashInitScript(tc *v1alpha1.TidbCluster) (string, error) {
@@ -105,12 +105,12 @@ echo "/tidb-server ${ARGS}"
 exec /tidb-server ${ARGS}
 `
 
-func appendTiDBStartScriptWithArgs(startScript string, args []string) string {
+// appendStartScriptWithArgs appends the args to the command cmd in the start script
+func appendStartScriptWithArgs(startScript, cmd string, args []string) string {
 	if len(args) == 0 {
 		return startScript
 	}
-	return strings.ReplaceAll(startScript, "/tidb-server ${ARGS}",
-		fmt.Sprintf("/tidb-server ${ARGS} %s", strings.Join(args, " ")))
+	return strings.ReplaceAll(startScript, cmd, fmt.Sprintf("%s %s", cmd, strings.Join(args, " ")))
 }
 
 type TidbStartScriptModel struct {
@@ -362,8 +362,9 @@ set -euo pipefail
 -L={{ .LogLevel }} \
 -advertise-addr=` + "`" + `echo ${HOSTNAME}` + "`" + `.{{ .ClusterName }}-pump{{ .FormatPumpZone }}:8250 \
 -config=/etc/pump/pump.toml \
--data-dir=/data \
--log-file=
+-data-dir=/data{{ if not .CustomLogFile }} \
+-log-file={{ end }}{{ if .ExtraArgs }} \
+{{ .ExtraArgs }}{{ end }}
 
 if [ $? == 0 ]; then
     echo $(date -u +"[%Y/%m/%d %H:%M:%S.%3N %:z]") "pump offline, please delete my pod"
@@ -388,6 +389,10 @@ type PumpStartScriptModel struct {
 	PDAddr      string
 	LogLevel    string
 	Namespace   string
+	// ExtraArgs are the additional arguments of pump
+	ExtraArgs string
+	// CustomLogFile is true if the log file is set by the additional arguments
+	CustomLogFile bool
 }
 
 func (pssm *PumpStartScriptModel) FormatPumpZone() string {
@@ -611,10 +616,15 @@ type DMMasterStartScriptModel struct {
 	Scheme            string
 	DataDir           string
 	CheckDomainScript string
+	AdditionalArgs    []string
 }
 
 func RenderDMMasterStartScript(model *DMMasterStartScriptModel) (string, error) {
-	return renderTemplateFunc(dmMasterStartScriptTpl, model)
+	script, err := renderTemplateFunc(dmMasterStartScriptTpl, model)
+	if err != nil {
+		return "", err
+	}
+	return appendStartScriptWithArgs(script, "/dm-master ${ARGS}", model.AdditionalArgs), nil
 }
 
 // dmWorkerStartScriptTpl is the dm-worker start script
@@ -669,12 +679,17 @@ exec /dm-worker ${ARGS}
 `))
 
 type DMWorkerStartScriptModel struct {
-	DataDir       string
-	MasterAddress string
+	DataDir        string
+	MasterAddress  string
+	AdditionalArgs []string
 }
 
 func RenderDMWorkerStartScript(model *DMWorkerStartScriptModel) (string, error) {
-	return renderTemplateFunc(dmWorkerStartScriptTpl, model)
+	script, err := renderTemplateFunc(dmWorkerStartScriptTpl, model)
+	if err != nil {
+		return "", err
+	}
+	return appendStartScriptWithArgs(script, "/dm-worker ${ARGS}", model.AdditionalArgs), nil
 }
 
 var tiflashStartScriptTplText = `#!/bin/sh
@@ -1320,6 +1320,47 @@ func TestRenderPumpStartScript(t *testing.T) {
 -data-dir=/data \
 -log-file=
 
+if [ $? == 0 ]; then
+    echo $(date -u +"[%Y/%m/%d %H:%M:%S.%3N %:z]") "pump offline, please delete my pod"
+    tail -f /dev/null
+fi`,
+		},
+		{
+			name: "additional args",
+			modifyTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Pump.AdditionalArgs = []string{"-gc=3"}
+			},
+			result: `set -euo pipefail
+
+/pump \
+-pd-urls=http://demo-pd:2379 \
+-L=info \
+-advertise-addr=` + "`" + `echo ${HOSTNAME}` + "`" + `.demo-pump:8250 \
+-config=/etc/pump/pump.toml \
+-data-dir=/data \
+-log-file= \
+-gc=3
+
+if [ $? == 0 ]; then
+    echo $(date -u +"[%Y/%m/%d %H:%M:%S.%3N %:z]") "pump offline, please delete my pod"
+    tail -f /dev/null
+fi`,
+		},
+		{
+			name: "set log file in additional args",
+			modifyTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Pump.AdditionalArgs = []string{"--log-file=/var/log/pump.log"}
+			},
+			result: `set -euo pipefail
+
+/pump \
+-pd-urls=http://demo-pd:2379 \
+-L=info \
+-advertise-addr=` + "`" + `echo ${HOSTNAME}` + "`" + `.demo-pump:8250 \
+-config=/etc/pump/pump.toml \
+-data-dir=/data \
+--log-file=/var/log/pump.log
+
 if [ $? == 0 ]; then
     echo $(date -u +"[%Y/%m/%d %H:%M:%S.%3N %:z]") "pump offline, please delete my pod"
     tail -f /dev/null
@@ -1590,6 +1631,13 @@ exec /cdc server --addr=0.0.0.0:8301 --advertise-addr=${POD_NAME}.${HEADLESS_SER
 			},
 			expectScript: `/cdc server --addr=0.0.0.0:8301 --advertise-addr=${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc:8301 --gc-ttl=86400 --log-file= --log-level=info --pd=http://start-script-test-pd:2379 --config=/etc/ticdc/ticdc.toml`,
 		},
+		{
+			name: "with additional args",
+			modifyTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiCDC.AdditionalArgs = []string{"--data-dir=/var/lib/$(POD_NAME)"}
+			},
+			expectScript: `/cdc server --addr=0.0.0.0:8301 --advertise-addr=${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc:8301 --gc-ttl=86400 --log-file= --log-level=info --pd=http://start-script-test-pd:2379 --data-dir=/var/lib/${POD_NAME}`,
+		},
 	}
 
 	for _, c := range cases {
@@ -14,11 +14,13 @@
 package v2
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"mvdan.cc/sh/v3/syntax"
 )
 
@@ -57,6 +59,129 @@ func TestScriptFormat(t *testing.T) {
 	}
 }
 
+func TestRenderStartScriptWithAcrossK8sResolver(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	resolvers := []struct {
+		name     string
+		resolver *v1alpha1.AcrossK8sResolver
+		// suffix is the suffix of the advertised hosts after `<pod>.<peer service>`
+		suffix string
+	}{
+		{
+			name:     "cluster domain",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterDomain},
+			suffix:   ".start-script-test-ns.svc.cluster-1.com",
+		},
+		{
+			name:     "external dns",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverExternalDNS, Domain: "db.example.com"},
+			suffix:   ".start-script-test-ns.db.example.com",
+		},
+		{
+			name:     "cluster set",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterSet},
+			suffix:   ".start-script-test-ns.svc.clusterset.local",
+		},
+		{
+			name:     "cluster mesh",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterMesh},
+			suffix:   ".start-script-test-ns",
+		},
+		{
+			// the hosts with the pod name variable can't be mapped, so the convention is used
+			name:     "static",
+			resolver: &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverStatic, Endpoints: map[string]string{"basic-pd.ns": "10.0.0.1"}},
+			suffix:   ".start-script-test-ns.svc.cluster-1.com",
+		},
+	}
+
+	components := []struct {
+		name   string
+		render func(tc *v1alpha1.TidbCluster) (string, error)
+		// expect returns the expected arg with the advertised host
+		expect func(host string) string
+	}{
+		{
+			name:   "pd",
+			render: RenderPDStartScript,
+			expect: func(suffix string) string {
+				return fmt.Sprintf("PD_DOMAIN=${PD_POD_NAME}.start-script-test-pd-peer%s\n", suffix)
+			},
+		},
+		{
+			name:   "tikv",
+			render: RenderTiKVStartScript,
+			expect: func(suffix string) string {
+				return fmt.Sprintf("--advertise-addr=${TIKV_POD_NAME}.start-script-test-tikv-peer%s:%d ", suffix, v1alpha1.DefaultTiKVServerPort)
+			},
+		},
+		{
+			name:   "tidb",
+			render: RenderTiDBStartScript,
+			expect: func(suffix string) string {
+				return fmt.Sprintf("--advertise-address=${TIDB_POD_NAME}.start-script-test-tidb-peer%s ", suffix)
+			},
+		},
+		{
+			name:   "ticdc",
+			render: RenderTiCDCStartScript,
+			expect: func(suffix string) string {
+				return fmt.Sprintf("--advertise-addr=${TICDC_POD_NAME}.start-script-test-ticdc-peer%s:%d ", suffix, v1alpha1.DefaultTiCDCPort)
+			},
+		},
+		{
+			name:   "tiproxy",
+			render: RenderTiProxyStartScript,
+			expect: func(suffix string) string {
+				return fmt.Sprintf("--advertise-addr=${TIPROXY_POD_NAME}.start-script-test-tiproxy-peer%s\"", suffix)
+			},
+		},
+		{
+			name:   "pump",
+			render: RenderPumpStartScript,
+			expect: func(suffix string) string {
+				return fmt.Sprintf("-advertise-addr=${PUMP_POD_NAME}.start-script-test-pump%s:%d ", suffix, v1alpha1.DefaultPumpPort)
+			},
+		},
+		{
+			name:   "tiflash",
+			render: RenderTiFlashStartScriptWithStartArgs,
+			expect: func(suffix string) string {
+				return fmt.Sprintf("--flash.service_addr=${POD_NAME}.start-script-test-tiflash-peer%s:%d ", suffix, v1alpha1.DefaultTiFlashFlashPort)
+			},
+		},
+	}
+
+	for _, r := range resolvers {
+		for _, c := range components {
+			t.Logf("test case: %s with %s resolver", c.name, r.name)
+
+			tc := &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					PD:      &v1alpha1.PDSpec{},
+					TiKV:    &v1alpha1.TiKVSpec{},
+					TiDB:    &v1alpha1.TiDBSpec{},
+					TiCDC:   &v1alpha1.TiCDCSpec{},
+					TiProxy: &v1alpha1.TiProxySpec{},
+					Pump:    &v1alpha1.PumpSpec{},
+					TiFlash: &v1alpha1.TiFlashSpec{},
+				},
+			}
+			tc.Name = "start-script-test"
+			tc.Namespace = "start-script-test-ns"
+			tc.Spec.AcrossK8s = true
+			tc.Spec.ClusterDomain = "cluster-1.com"
+			tc.Spec.AcrossK8sResolver = r.resolver
+
+			script, err := c.render(tc)
+			g.Expect(err).Should(gomega.Succeed())
+			g.Expect(script).Should(gomega.ContainSubstring(c.expect(r.suffix)))
+			g.Expect(validateScript(script)).Should(gomega.Succeed())
+		}
+	}
+}
+
 func validateScript(script string) error {
 	_, err := syntax.NewParser().Parse(strings.NewReader(script), "")
 	return err
@@ -52,6 +52,7 @@ type PDMSStartScriptModel struct {
 	PDMSDomain          string
 	ListenAddr          string
 	AdvertiseListenAddr string
+	ExtraArgs           string
 
 	AcrossK8s *AcrossK8sScriptModel
 }
@@ -63,10 +64,7 @@ func RenderPDStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	tcNS := tc.Namespace
 	peerServiceName := controller.PDPeerMemberName(tcName)
 
-	m.PDDomain = fmt.Sprintf("${PD_POD_NAME}.%s.%s.svc", peerServiceName, tcNS)
-	if tc.Spec.ClusterDomain != "" {
-		m.PDDomain = m.PDDomain + "." + tc.Spec.ClusterDomain
-	}
+	m.PDDomain = controller.PodAdvertiseHost(tc, "${PD_POD_NAME}", peerServiceName)
 
 	m.PDName = "${PD_POD_NAME}"
 	if tc.AcrossK8s() || tc.Spec.ClusterDomain != "" {
@@ -96,6 +94,8 @@ func RenderPDStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 
 	m.PDInitWaitTime = tc.PDInitWaitTime()
 
+	m.ExtraArgs = strings.Join(controller.FormatAdditionalArgs(tc.BasePDSpec().AdditionalArgs()), " ")
+
 	waitForDnsNameIpMatchOnStartup := slices.Contains(
 		tc.Spec.StartScriptV2FeatureFlags, v1alpha1.StartScriptV2FeatureFlagWaitForDnsNameIpMatch)
 
@@ -132,12 +132,16 @@ func renderPDMSStartScript(tc *v1alpha1.TidbCluster, name string) (string, error
 	tcName := tc.Name
 	tcNS := tc.Namespace
 
-	peerServiceName := controller.PDMSPeerMemberName(tcName, name)
-	m.PDMSDomain = fmt.Sprintf("${PDMS_POD_NAME}.%s.%s.svc", peerServiceName, tcNS)
-	if tc.Spec.ClusterDomain != "" {
-		m.PDMSDomain = m.PDMSDomain + "." + tc.Spec.ClusterDomain
+	for _, service := range tc.Spec.PDMS {
+		if service.Name == name {
+			m.ExtraArgs = strings.Join(controller.FormatAdditionalArgs(tc.BasePDMSSpec(service).AdditionalArgs()), " ")
+			break
+		}
 	}
 
+	peerServiceName := controller.PDMSPeerMemberName(tcName, name)
+	m.PDMSDomain = controller.PodAdvertiseHost(tc, "${PDMS_POD_NAME}", peerServiceName)
+
 	if check, err := pdMSSupportMicroservicesWithName.Check(tc.PDMSVersion(name)); check && err == nil {
 		m.PDMSName = "${PDMS_POD_NAME}"
 		if tc.Spec.ClusterDomain != "" {
@@ -265,7 +269,16 @@ elif [[ ! -d {{ .DataDir }}/member/wal ]]; then
         echo "waiting for discovery service to return start args ..."
         sleep $((RANDOM % 5))
     done
-    ARGS="${ARGS} ${result}"
+    if [[ ${result} == --initial-cluster-existing=* ]]; then
+        # the member is added to the cluster as a learner, start it with the persisted join config
+        mkdir -p {{ .DataDir }}
+        echo ${result#--initial-cluster-existing=} > {{ .DataDir }}/join
+        join=$(cat {{ .DataDir }}/join | tr "," "\n" | awk -F'=' '{print $2}' | tr "\n" ",")
+        join=${join%,}
+        ARGS="${ARGS} --join=${join}"
+    else
+        ARGS="${ARGS} ${result}"
+    fi
 fi
 {{- end }}
 
@@ -300,6 +313,9 @@ ARGS="` + pdEnableMicroservice + `--listen-addr={{ .ListenAddr }} \
 --backend-endpoints={{ .PDAddresses }} \
 --config=/etc/pd/pd.toml \
 "
+{{- if .ExtraArgs }}
+ARGS="${ARGS} {{ .ExtraArgs }}"
+{{- end }}
 
 echo "starting pd-server ..."
 sleep $((RANDOM % 10))
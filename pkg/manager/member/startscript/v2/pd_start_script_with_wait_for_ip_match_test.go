@@ -137,7 +137,16 @@ elif [[ ! -d /var/lib/pd/member/wal ]]; then
         echo "waiting for discovery service to return start args ..."
         sleep $((RANDOM % 5))
     done
-    ARGS="${ARGS} ${result}"
+    if [[ ${result} == --initial-cluster-existing=* ]]; then
+        # the member is added to the cluster as a learner, start it with the persisted join config
+        mkdir -p /var/lib/pd
+        echo ${result#--initial-cluster-existing=} > /var/lib/pd/join
+        join=$(cat /var/lib/pd/join | tr "," "\n" | awk -F'=' '{print $2}' | tr "\n" ",")
+        join=${join%,}
+        ARGS="${ARGS} --join=${join}"
+    else
+        ARGS="${ARGS} ${result}"
+    fi
 fi
 
 echo "starting pd-server ..."
@@ -253,7 +262,16 @@ elif [[ ! -d /var/lib/pd/member/wal ]]; then
         echo "waiting for discovery service to return start args ..."
         sleep $((RANDOM % 5))
     done
-    ARGS="${ARGS} ${result}"
+    if [[ ${result} == --initial-cluster-existing=* ]]; then
+        # the member is added to the cluster as a learner, start it with the persisted join config
+        mkdir -p /var/lib/pd
+        echo ${result#--initial-cluster-existing=} > /var/lib/pd/join
+        join=$(cat /var/lib/pd/join | tr "," "\n" | awk -F'=' '{print $2}' | tr "\n" ",")
+        join=${join%,}
+        ARGS="${ARGS} --join=${join}"
+    else
+        ARGS="${ARGS} ${result}"
+    fi
 fi
 
 echo "starting pd-server ..."
@@ -369,7 +387,16 @@ elif [[ ! -d /var/lib/pd/pd-data/member/wal ]]; then
         echo "waiting for discovery service to return start args ..."
         sleep $((RANDOM % 5))
     done
-    ARGS="${ARGS} ${result}"
+    if [[ ${result} == --initial-cluster-existing=* ]]; then
+        # the member is added to the cluster as a learner, start it with the persisted join config
+        mkdir -p /var/lib/pd/pd-data
+        echo ${result#--initial-cluster-existing=} > /var/lib/pd/pd-data/join
+        join=$(cat /var/lib/pd/pd-data/join | tr "," "\n" | awk -F'=' '{print $2}' | tr "\n" ",")
+        join=${join%,}
+        ARGS="${ARGS} --join=${join}"
+    else
+        ARGS="${ARGS} ${result}"
+    fi
 fi
 
 echo "starting pd-server ..."
@@ -485,7 +512,16 @@ elif [[ ! -d /var/lib/pd/member/wal ]]; then
         echo "waiting for discovery service to return start args ..."
         sleep $((RANDOM % 5))
     done
-    ARGS="${ARGS} ${result}"
+    if [[ ${result} == --initial-cluster-existing=* ]]; then
+        # the member is added to the cluster as a learner, start it with the persisted join config
+        mkdir -p /var/lib/pd
+        echo ${result#--initial-cluster-existing=} > /var/lib/pd/join
+        join=$(cat /var/lib/pd/join | tr "," "\n" | awk -F'=' '{print $2}' | tr "\n" ",")
+        join=${join%,}
+        ARGS="${ARGS} --join=${join}"
+    else
+        ARGS="${ARGS} ${result}"
+    fi
 fi
 
 echo "starting pd-server ..."
@@ -602,7 +638,16 @@ elif [[ ! -d /var/lib/pd/member/wal ]]; then
         echo "waiting for discovery service to return start args ..."
         sleep $((RANDOM % 5))
     done
-    ARGS="${ARGS} ${result}"
+    if [[ ${result} == --initial-cluster-existing=* ]]; then
+        # the member is added to the cluster as a learner, start it with the persisted join config
+        mkdir -p /var/lib/pd
+        echo ${result#--initial-cluster-existing=} > /var/lib/pd/join
+        join=$(cat /var/lib/pd/join | tr "," "\n" | awk -F'=' '{print $2}' | tr "\n" ",")
+        join=${join%,}
+        ARGS="${ARGS} --join=${join}"
+    else
+        ARGS="${ARGS} ${result}"
+    fi
 fi
 
 echo "starting pd-server ..."
@@ -719,7 +764,16 @@ elif [[ ! -d /var/lib/pd/member/wal ]]; then
         echo "waiting for discovery service to return start args ..."
         sleep $((RANDOM % 5))
     done
-    ARGS="${ARGS} ${result}"
+    if [[ ${result} == --initial-cluster-existing=* ]]; then
+        # the member is added to the cluster as a learner, start it with the persisted join config
+        mkdir -p /var/lib/pd
+        echo ${result#--initial-cluster-existing=} > /var/lib/pd/join
+        join=$(cat /var/lib/pd/join | tr "," "\n" | awk -F'=' '{print $2}' | tr "\n" ",")
+        join=${join%,}
+        ARGS="${ARGS} --join=${join}"
+    else
+        ARGS="${ARGS} ${result}"
+    fi
 fi
 
 echo "starting pd-server ..."
@@ -15,6 +15,7 @@ package v2
 
 import (
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -27,6 +28,8 @@ type PumpStartScriptModel struct {
 	LogLevel      string
 	AdvertiseAddr string
 	ExtraArgs     string
+	// CustomLogFile is true if the log file is set by the additional arguments
+	CustomLogFile bool
 
 	AcrossK8s *AcrossK8sScriptModel
 }
@@ -47,19 +50,25 @@ func RenderPumpStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 		m.PDAddr = "${result}" // get pd addr in subscript
 	} else if tc.Heterogeneous() && tc.WithoutLocalPD() {
 		m.PDAddr = fmt.Sprintf("%s://%s:%d", tc.Scheme(), controller.PDMemberName(tc.Spec.Cluster.Name), v1alpha1.DefaultPDClientPort) // use pd of reference cluster
+	} else if tc.HasExternalPD() {
+		m.PDAddr = strings.Join(tc.Spec.ExternalPD.Endpoints, ",") // use the external pd
 	}
 
 	m.LogLevel = tc.PumpLogLevel()
 
 	advertiseAddr := fmt.Sprintf("${PUMP_POD_NAME}.%s", peerServiceName)
-	if tc.Spec.ClusterDomain != "" {
+	if tc.Spec.AcrossK8sResolver != nil {
+		advertiseAddr = controller.PodAdvertiseHost(tc, "${PUMP_POD_NAME}", peerServiceName)
+	} else if tc.Spec.ClusterDomain != "" {
 		advertiseAddr = advertiseAddr + fmt.Sprintf(".%s.svc.%s", tcNS, tc.Spec.ClusterDomain)
 	} else if tc.Spec.ClusterDomain == "" && tc.AcrossK8s() {
 		advertiseAddr = advertiseAddr + fmt.Sprintf(".%s.svc", tcNS)
 	}
 	m.AdvertiseAddr = fmt.Sprintf("%s:%d", advertiseAddr, v1alpha1.DefaultPumpPort)
 
-	m.ExtraArgs = ""
+	additionalArgs := tc.BasePumpSpec().AdditionalArgs()
+	m.ExtraArgs = strings.Join(controller.FormatAdditionalArgs(additionalArgs), " ")
+	m.CustomLogFile = controller.AdditionalArgsHaveFlag(additionalArgs, "log-file")
 
 	return renderTemplateFunc(pumpStartScriptTpl, m)
 }
@@ -85,7 +94,9 @@ PUMP_POD_NAME=$HOSTNAME
 
 ARGS="-pd-urls={{ .PDAddr }} \
 -L {{ .LogLevel }} \
+{{- if not .CustomLogFile }}
 -log-file= \
+{{- end }}
 -advertise-addr={{ .AdvertiseAddr }} \
 -data-dir=/data \
 --config=/etc/pump/pump.toml"
@@ -114,6 +114,49 @@ echo "start pump-server ..."
 echo "/pump ${ARGS}"
 exec /pump ${ARGS}
 
+if [ $? == 0 ]; then
+    echo $(date -u +"[%Y/%m/%d %H:%M:%S.%3N %:z]") "pump offline, please delete my pod"
+    tail -f /dev/null
+fi
+`,
+		},
+		{
+			name: "set log file in additional args",
+			modifyTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Pump.AdditionalArgs = []string{"--log-file", "/var/log/$(PUMP_POD_NAME).log"}
+			},
+			expectScript: `#!/bin/sh
+
+set -uo pipefail
+
+ANNOTATIONS="/etc/podinfo/annotations"
+if [[ ! -f "${ANNOTATIONS}" ]]
+then
+    echo "${ANNOTATIONS} does't exist, exiting."
+    exit 1
+fi
+source ${ANNOTATIONS} 2>/dev/null
+
+runmode=${runmode:-normal}
+if [[ X${runmode} == Xdebug ]]
+then
+    echo "entering debug mode."
+    tail -f /dev/null
+fi
+
+PUMP_POD_NAME=$HOSTNAME
+
+ARGS="-pd-urls=http://start-script-test-pd:2379 \
+-L info \
+-advertise-addr=${PUMP_POD_NAME}.start-script-test-pump:8250 \
+-data-dir=/data \
+--config=/etc/pump/pump.toml"
+ARGS="${ARGS} --log-file /var/log/${PUMP_POD_NAME}.log"
+
+echo "start pump-server ..."
+echo "/pump ${ARGS}"
+exec /pump ${ARGS}
+
 if [ $? == 0 ]; then
     echo $(date -u +"[%Y/%m/%d %H:%M:%S.%3N %:z]") "pump offline, please delete my pod"
     tail -f /dev/null
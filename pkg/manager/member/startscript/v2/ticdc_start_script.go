@@ -46,11 +46,7 @@ func RenderTiCDCStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	peerServiceName := controller.TiCDCPeerMemberName(tcName)
 
 	// NB: TiCDC control relies the format.
-	// TODO move advertise addr format to package controller.
-	advertiseAddr := fmt.Sprintf("${TICDC_POD_NAME}.%s.%s.svc", peerServiceName, tcNS)
-	if tc.Spec.ClusterDomain != "" {
-		advertiseAddr = advertiseAddr + "." + tc.Spec.ClusterDomain
-	}
+	advertiseAddr := controller.PodAdvertiseHost(tc, "${TICDC_POD_NAME}", peerServiceName)
 	m.AdvertiseAddr = fmt.Sprintf("%s:%d", advertiseAddr, v1alpha1.DefaultTiCDCPort)
 
 	m.GCTTL = tc.TiCDCGCTTL()
@@ -61,7 +57,9 @@ func RenderTiCDCStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 
 	preferPDAddressesOverDiscovery := slices.Contains(
 		tc.Spec.StartScriptV2FeatureFlags, v1alpha1.StartScriptV2FeatureFlagPreferPDAddressesOverDiscovery)
-	if preferPDAddressesOverDiscovery {
+	if tc.HasExternalPD() {
+		m.PDAddresses = strings.Join(tc.Spec.ExternalPD.Endpoints, ",") // use the external pd
+	} else if preferPDAddressesOverDiscovery {
 		m.PDAddresses = strings.Join(tc.Spec.PDAddresses, ",")
 	}
 	if len(m.PDAddresses) == 0 {
@@ -88,6 +86,7 @@ func RenderTiCDCStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	if tc.Spec.TiCDC.Config != nil && !tc.Spec.TiCDC.Config.OnlyOldItems() {
 		extraArgs = append(extraArgs, fmt.Sprintf("--config=%s", "/etc/ticdc/ticdc.toml"))
 	}
+	extraArgs = append(extraArgs, controller.FormatAdditionalArgs(tc.BaseTiCDCSpec().AdditionalArgs())...)
 	if len(extraArgs) > 0 {
 		m.ExtraArgs = strings.Join(extraArgs, " ")
 	}
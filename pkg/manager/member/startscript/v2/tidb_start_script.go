@@ -41,7 +41,9 @@ func RenderTiDBStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 
 	preferPDAddressesOverDiscovery := slices.Contains(
 		tc.Spec.StartScriptV2FeatureFlags, v1alpha1.StartScriptV2FeatureFlagPreferPDAddressesOverDiscovery)
-	if preferPDAddressesOverDiscovery {
+	if tc.HasExternalPD() {
+		m.PDAddresses = strings.Join(tc.ExternalPDAddresses(), ",") // use the external pd
+	} else if preferPDAddressesOverDiscovery {
 		pdAddressesWithSchemeAndPort := addressesWithSchemeAndPort(tc.Spec.PDAddresses, "", v1alpha1.DefaultPDClientPort)
 		m.PDAddresses = strings.Join(pdAddressesWithSchemeAndPort, ",")
 	}
@@ -59,10 +61,7 @@ func RenderTiDBStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 		}
 	}
 
-	m.AdvertiseAddr = fmt.Sprintf("${TIDB_POD_NAME}.%s.%s.svc", peerServiceName, tcNS)
-	if tc.Spec.ClusterDomain != "" {
-		m.AdvertiseAddr = m.AdvertiseAddr + "." + tc.Spec.ClusterDomain
-	}
+	m.AdvertiseAddr = controller.PodAdvertiseHost(tc, "${TIDB_POD_NAME}", peerServiceName)
 
 	extraArgs := []string{}
 	if tc.IsTiDBBinlogEnabled() {
@@ -72,6 +71,7 @@ func RenderTiDBStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 		extraArgs = append(extraArgs, "--plugin-dir=/plugins")
 		extraArgs = append(extraArgs, fmt.Sprintf("--plugin-load=%s", strings.Join(plugins, ",")))
 	}
+	extraArgs = append(extraArgs, controller.FormatAdditionalArgs(tc.BaseTiDBSpec().AdditionalArgs())...)
 	if len(extraArgs) > 0 {
 		m.ExtraArgs = strings.Join(extraArgs, " ")
 	}
@@ -36,7 +36,7 @@ func RenderTiFlashStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 
 	m := &TiFlashStartScriptModel{}
 
-	m.ExtraArgs = ""
+	m.ExtraArgs = strings.Join(controller.FormatAdditionalArgs(tc.BaseTiFlashSpec().AdditionalArgs()), " ")
 
 	return renderTemplateFunc(tiflashStartScriptTpl, m)
 }
@@ -54,21 +54,27 @@ type TiFlashStartScriptWithStartArgsModel struct {
 }
 
 func RenderTiFlashStartScriptWithStartArgs(tc *v1alpha1.TidbCluster) (string, error) {
-	m := &TiFlashStartScriptWithStartArgsModel{
-		AdvertiseAddr: fmt.Sprintf("${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc%s:%d", controller.FormatClusterDomain(tc.Spec.ClusterDomain), v1alpha1.DefaultTiFlashProxyPort),
-		ExtraArgs:     "",
-	}
 	tcName := tc.Name
 	tcNS := tc.Namespace
+	advertiseHost := fmt.Sprintf("${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc%s", controller.FormatClusterDomain(tc.Spec.ClusterDomain))
+	if tc.Spec.AcrossK8sResolver != nil {
+		advertiseHost = controller.PodAdvertiseHost(tc, "${POD_NAME}", controller.TiFlashPeerMemberName(tcName))
+	}
+	m := &TiFlashStartScriptWithStartArgsModel{
+		AdvertiseAddr: fmt.Sprintf("%s:%d", advertiseHost, v1alpha1.DefaultTiFlashProxyPort),
+		ExtraArgs:     strings.Join(controller.FormatAdditionalArgs(tc.BaseTiFlashSpec().AdditionalArgs()), " "),
+	}
 
 	// only the tiflash learner supports dynamic configuration
 	if tc.Spec.EnableDynamicConfiguration != nil && *tc.Spec.EnableDynamicConfiguration {
-		m.AdvertiseStatusAddr = fmt.Sprintf("${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc%s:%d", controller.FormatClusterDomain(tc.Spec.ClusterDomain), v1alpha1.DefaultTiFlashProxyStatusPort)
+		m.AdvertiseStatusAddr = fmt.Sprintf("%s:%d", advertiseHost, v1alpha1.DefaultTiFlashProxyStatusPort)
 	}
 
 	preferPDAddressesOverDiscovery := slices.Contains(
 		tc.Spec.StartScriptV2FeatureFlags, v1alpha1.StartScriptV2FeatureFlagPreferPDAddressesOverDiscovery)
-	if preferPDAddressesOverDiscovery {
+	if tc.HasExternalPD() {
+		m.PDAddresses = strings.Join(tc.ExternalPDAddresses(), ",") // use the external pd
+	} else if preferPDAddressesOverDiscovery {
 		pdAddressesWithSchemeAndPort := addressesWithSchemeAndPort(tc.Spec.PDAddresses, "", v1alpha1.DefaultPDClientPort)
 		m.PDAddresses = strings.Join(pdAddressesWithSchemeAndPort, ",")
 	}
@@ -86,7 +92,7 @@ func RenderTiFlashStartScriptWithStartArgs(tc *v1alpha1.TidbCluster) (string, er
 		}
 	}
 
-	m.Addr = fmt.Sprintf("${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc%s:%d", controller.FormatClusterDomain(tc.Spec.ClusterDomain), v1alpha1.DefaultTiFlashFlashPort)
+	m.Addr = fmt.Sprintf("%s:%d", advertiseHost, v1alpha1.DefaultTiFlashFlashPort)
 
 	return renderTemplateFunc(tiflashStartScriptWithStartArgsTpl, m)
 }
@@ -49,7 +49,9 @@ func RenderTiKVStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 
 	preferPDAddressesOverDiscovery := slices.Contains(
 		tc.Spec.StartScriptV2FeatureFlags, v1alpha1.StartScriptV2FeatureFlagPreferPDAddressesOverDiscovery)
-	if preferPDAddressesOverDiscovery {
+	if tc.HasExternalPD() {
+		m.PDAddresses = strings.Join(tc.ExternalPDAddresses(), ",") // use the external pd
+	} else if preferPDAddressesOverDiscovery {
 		pdAddressesWithSchemeAndPort := addressesWithSchemeAndPort(tc.Spec.PDAddresses, "", v1alpha1.DefaultPDClientPort)
 		m.PDAddresses = strings.Join(pdAddressesWithSchemeAndPort, ",")
 	}
@@ -74,10 +76,7 @@ func RenderTiKVStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	m.Addr = fmt.Sprintf("%s:%d", listenHost, v1alpha1.DefaultTiKVServerPort)
 	m.StatusAddr = fmt.Sprintf("%s:%d", listenHost, v1alpha1.DefaultTiKVStatusPort)
 
-	advertiseHost := fmt.Sprintf("${TIKV_POD_NAME}.%s.%s.svc", peerServiceName, tcNS)
-	if tc.Spec.ClusterDomain != "" {
-		advertiseHost = advertiseHost + "." + tc.Spec.ClusterDomain
-	}
+	advertiseHost := controller.PodAdvertiseHost(tc, "${TIKV_POD_NAME}", peerServiceName)
 	m.AdvertiseHost = advertiseHost
 	m.AdvertiseAddr = fmt.Sprintf("%s:%d", advertiseHost, v1alpha1.DefaultTiKVServerPort)
 
@@ -89,12 +88,9 @@ func RenderTiKVStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 
 	extraArgs := []string{}
 	if tc.Spec.EnableDynamicConfiguration != nil && *tc.Spec.EnableDynamicConfiguration {
-		advertiseStatusAddr := fmt.Sprintf("${TIKV_POD_NAME}.%s.%s.svc", peerServiceName, tcNS)
-		if tc.Spec.ClusterDomain != "" {
-			advertiseStatusAddr = advertiseStatusAddr + "." + tc.Spec.ClusterDomain
-		}
-		extraArgs = append(extraArgs, fmt.Sprintf("--advertise-status-addr=%s:%d", advertiseStatusAddr, v1alpha1.DefaultTiKVStatusPort))
+		extraArgs = append(extraArgs, fmt.Sprintf("--advertise-status-addr=%s:%d", advertiseHost, v1alpha1.DefaultTiKVStatusPort))
 	}
+	extraArgs = append(extraArgs, controller.FormatAdditionalArgs(tc.BaseTiKVSpec().AdditionalArgs())...)
 	if len(extraArgs) > 0 {
 		m.ExtraArgs = strings.Join(extraArgs, " ")
 	}
@@ -536,6 +536,51 @@ if [ ! -z "${STORE_LABELS:-}" ]; then
   ARGS="${ARGS}${LABELS}"
 fi
 
+echo "starting tikv-server ..."
+echo "/tikv-server ${ARGS}"
+exec /tikv-server ${ARGS}
+`,
+		},
+		{
+			name: "with additional args",
+			modifyTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.AdditionalArgs = []string{"--metrics-flush-interval=10s", "--advertise-status-addr=$(POD_IP):20180"}
+			},
+			expectScript: `#!/bin/sh
+
+set -uo pipefail
+
+ANNOTATIONS="/etc/podinfo/annotations"
+if [[ ! -f "${ANNOTATIONS}" ]]
+then
+    echo "${ANNOTATIONS} does't exist, exiting."
+    exit 1
+fi
+source ${ANNOTATIONS} 2>/dev/null
+
+runmode=${runmode:-normal}
+if [[ X${runmode} == Xdebug ]]
+then
+    echo "entering debug mode."
+    tail -f /dev/null
+fi
+
+TIKV_POD_NAME=${POD_NAME:-$HOSTNAME}
+
+ARGS="--pd=start-script-test-pd:2379 \
+--advertise-addr=${TIKV_POD_NAME}.start-script-test-tikv-peer.start-script-test-ns.svc:20160 \
+--addr=0.0.0.0:20160 \
+--status-addr=0.0.0.0:20180 \
+--data-dir=/var/lib/tikv \
+--capacity=${CAPACITY} \
+--config=/etc/tikv/tikv.toml"
+ARGS="${ARGS} --metrics-flush-interval=10s --advertise-status-addr=${POD_IP}:20180"
+
+if [ ! -z "${STORE_LABELS:-}" ]; then
+  LABELS="--labels ${STORE_LABELS} "
+  ARGS="${ARGS}${LABELS}"
+fi
+
 echo "starting tikv-server ..."
 echo "/tikv-server ${ARGS}"
 exec /tikv-server ${ARGS}
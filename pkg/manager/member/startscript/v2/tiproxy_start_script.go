@@ -14,7 +14,7 @@
 package v2
 
 import (
-	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -24,18 +24,16 @@ import (
 // TiProxyStartScriptModel contain fields for rendering TiProxy start script
 type TiProxyStartScriptModel struct {
 	AdvertiseAddr string
+	ExtraArgs     string
 }
 
 // RenderTiProxyStartScript renders tiproxy start script for TidbCluster
 func RenderTiProxyStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	m := &TiProxyStartScriptModel{}
 	tcName := tc.Name
-	tcNS := tc.Namespace
 	peerServiceName := controller.TiProxyPeerMemberName(tcName)
-	m.AdvertiseAddr = fmt.Sprintf("${TIPROXY_POD_NAME}.%s.%s.svc", peerServiceName, tcNS)
-	if tc.Spec.ClusterDomain != "" {
-		m.AdvertiseAddr = m.AdvertiseAddr + "." + tc.Spec.ClusterDomain
-	}
+	m.AdvertiseAddr = controller.PodAdvertiseHost(tc, "${TIPROXY_POD_NAME}", peerServiceName)
+	m.ExtraArgs = strings.Join(controller.FormatAdditionalArgs(tc.BaseTiProxySpec().AdditionalArgs()), " ")
 	return renderTemplateFunc(template.Must(template.New("tiproxy").Parse(componentCommonScript+tiproxyStartScript)), m)
 }
 
@@ -48,6 +46,9 @@ ARGS="--config=/etc/proxy/proxy.toml"
 if [[ "$(/bin/tiproxy --help)" == *"advertise-addr"* ]]; then
   ARGS="${ARGS} --advertise-addr={{ .AdvertiseAddr }}"
 fi
+{{- if .ExtraArgs }}
+ARGS="${ARGS} {{ .ExtraArgs }}"
+{{- end }}
 
 echo "starting: tiproxy ${ARGS}"
 exec /bin/tiproxy ${ARGS}
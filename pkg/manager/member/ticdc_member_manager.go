@@ -106,7 +106,7 @@ func (m *ticdcMemberManager) syncTiCDCConfigMap(tc *v1alpha1.TidbCluster, set *a
 		return nil, nil
 	}
 
-	newCm, err := getTiCDCConfigMap(tc)
+	newCm, err := getTiCDCConfigMap(m.deps.ConfigDefaults.Apply(tc, v1alpha1.TiCDCMemberType))
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +140,7 @@ func (m *ticdcMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	component := v1alpha1.TiCDCMemberType
 	needSuspend, err := m.suspender.SuspendComponent(tc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, ns, tcName)
@@ -164,6 +164,14 @@ func (m *ticdcMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	return m.syncStatefulSet(tc)
 }
 
+// SyncStatus refreshes the status of TiCDC from its statefulset without acting upon the spec
+func (m *ticdcMemberManager) SyncStatus(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiCDC == nil {
+		return nil
+	}
+	return syncStatusFromStatefulSet(m.deps, tc, controller.TiCDCMemberName(tc.GetName()), m.syncTiCDCStatus)
+}
+
 func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
@@ -468,7 +476,7 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*ap
 		},
 		VolumeMounts: volMounts,
 		Resources:    controller.ContainerResource(tc.Spec.TiCDC.ResourceRequirements),
-		Env:          util.AppendEnv(envs, baseTiCDCSpec.Env()),
+		Env:          util.AppendEnv(util.AppendEnv(envs, baseTiCDCSpec.Env()), goRuntimeResourceEnv(baseTiCDCSpec, controller.ContainerResource(tc.Spec.TiCDC.ResourceRequirements))),
 		EnvFrom:      baseTiCDCSpec.EnvFrom(),
 	}
 	if cm != nil {
@@ -615,3 +623,7 @@ func (m *FakeTiCDCMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	}
 	return nil
 }
+
+func (m *FakeTiCDCMemberManager) SyncStatus(*v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 )
 
@@ -110,25 +111,16 @@ func (u *ticdcUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulS
 			return err
 		}
 		if support {
+			// The pods above the ordinal have been upgraded, move the ownership to them
+			// before the owner is upgraded, so the owner capture is upgraded last.
+			if err := u.moveOwnerToUpgradedCaptures(tc, pod, ordinal, podOrdinals[i+1:]); err != nil {
+				return err
+			}
 			err = gracefulDrainTiCDC(tc, u.deps.CDCControl, u.deps.PodControl, pod, ordinal, "Upgrade")
 			if err != nil {
 				return err
 			}
 			klog.Infof("ticdcUpgrade.Upgrade: %s graceful drain TiCDC complete in cluster %s/%s", podName, tc.GetNamespace(), tc.GetName())
-			// To prevent TiCDC service disruption, we need to resign owner
-			// gracefully from the next pod that is going to be upgraded.
-			// If the current pod is the last one to upgrade, skip resign owner.
-			hasNext := i-1 >= 0
-			if hasNext {
-				nextOrd := podOrdinals[i-1]
-				nextPodName := ticdcPodName(tcName, nextOrd)
-				klog.Infof("ticdcUpgrade.Upgrade: try to graceful resign owner from the next ticdc pod %s in cluster %s/%s", nextPodName, tc.GetNamespace(), tc.GetName())
-				err = gracefulResignOwnerTiCDC(tc, u.deps.CDCControl, u.deps.PodControl, pod, nextPodName, nextOrd, "Upgrade")
-				if err != nil {
-					return err
-				}
-				klog.Infof("ticdcUpgrade.Upgrade: %s graceful resign owner complete in cluster %s/%s", nextPodName, tc.GetNamespace(), tc.GetName())
-			}
 			klog.Infof("ticdcUpgrade.Upgrade: %s graceful shutdown complete in cluster %s/%s", podName, tc.GetNamespace(), tc.GetName())
 		}
 
@@ -138,3 +130,46 @@ func (u *ticdcUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulS
 
 	return nil
 }
+
+// moveOwnerToUpgradedCaptures resigns the owner until the ownership is held by an upgraded capture.
+// Once an upgraded capture is the owner, the ownership stays there for the rest of the upgrade,
+// instead of moving every time a capture is upgraded. If none of the captures is upgraded yet,
+// the ownership is resigned only if the capture to be upgraded is the owner.
+func (u *ticdcUpgrader) moveOwnerToUpgradedCaptures(tc *v1alpha1.TidbCluster, pod *corev1.Pod, ordinal int32, upgraded []int32) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	ownerOrdinal, found, err := u.deps.CDCControl.GetOwner(tc, ordinal)
+	if err != nil {
+		return controller.RequeueErrorf("ticdcUpgrader.Upgrade: cluster %s/%s fail to get TiCDC owner, error: %v", ns, tcName, err)
+	}
+	if !found {
+		// the owner is resigned when the capture is drained if it's the owner
+		return nil
+	}
+	for _, o := range upgraded {
+		if o == ownerOrdinal {
+			return nil
+		}
+	}
+	if len(upgraded) == 0 && ownerOrdinal != ordinal {
+		return nil
+	}
+
+	isTimeout, err := checkTiCDCGracefulShutdownTimeout(tc, u.deps.PodControl, pod, "Upgrade")
+	if err != nil {
+		return err
+	}
+	if isTimeout {
+		return nil
+	}
+	ownerPodName := ticdcPodName(tcName, ownerOrdinal)
+	klog.Infof("ticdcUpgrade.Upgrade: try to graceful resign owner from the ticdc pod %s before upgrading %s in cluster %s/%s", ownerPodName, pod.GetName(), ns, tcName)
+	if err := gracefulResignOwnerTiCDC(tc, u.deps.CDCControl, u.deps.PodControl, pod, ownerPodName, ownerOrdinal, "Upgrade"); err != nil {
+		return err
+	}
+	if len(upgraded) > 0 {
+		return controller.RequeueErrorf("ticdcUpgrader.Upgrade: cluster %s/%s owner %s is resigned, wait for an upgraded capture to become the owner", ns, tcName, ownerPodName)
+	}
+	return nil
+}
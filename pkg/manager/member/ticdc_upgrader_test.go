@@ -55,6 +55,9 @@ func TestTiCDCUpgrader_Upgrade(t *testing.T) {
 		cdcControl.GetStatusFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (*controller.CaptureStatus, error) {
 			return &controller.CaptureStatus{Version: cdcVersionOld}, nil
 		}
+		cdcControl.GetOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int32, bool, error) {
+			return 0, false, nil
+		}
 		cdcVersionNew := ticdcCrossUpgradeVersion
 		tc := newTidbClusterForTiCDCUpgrader()
 		tc.Spec.TiCDC.Version = &cdcVersionNew
@@ -175,6 +178,10 @@ func TestTiCDCUpgrader_Upgrade(t *testing.T) {
 					return &controller.CaptureStatus{Version: ticdcCrossUpgradeVersion}, nil
 				}
 
+				// the capture to be upgraded is the owner.
+				cdcControl.GetOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int32, bool, error) {
+					return 1, true, nil
+				}
 				// resignOwner always success.
 				cdcControl.ResignOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error) {
 					return true, nil
@@ -220,6 +227,58 @@ func TestTiCDCUpgrader_Upgrade(t *testing.T) {
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
 			},
 		},
+		{
+			name:        "graceful upgrade moves owner to upgraded capture",
+			errorExpect: true,
+			changeUpgrader: func(u *ticdcUpgrader) {
+				cdcControl := u.deps.CDCControl.(*controller.FakeTiCDCControl)
+				cdcControl.GetStatusFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (*controller.CaptureStatus, error) {
+					return &controller.CaptureStatus{Version: ticdcCrossUpgradeVersion}, nil
+				}
+				// the capture to be upgraded is the owner.
+				cdcControl.GetOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int32, bool, error) {
+					return 0, true, nil
+				}
+				cdcControl.ResignOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error) {
+					g.Expect(ordinal).To(Equal(int32(0)))
+					return true, nil
+				}
+				cdcControl.IsHealthyFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+					return true, nil
+				}
+				cdcControl.DrainCaptureFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int, bool, error) {
+					t.Fatal("the capture should not be drained before an upgraded capture becomes the owner")
+					return 0, false, nil
+				}
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiCDC.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "graceful upgrade with owner on upgraded capture",
+			changeUpgrader: func(u *ticdcUpgrader) {
+				cdcControl := u.deps.CDCControl.(*controller.FakeTiCDCControl)
+				cdcControl.GetStatusFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (*controller.CaptureStatus, error) {
+					return &controller.CaptureStatus{Version: ticdcCrossUpgradeVersion}, nil
+				}
+				cdcControl.GetOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int32, bool, error) {
+					return 1, true, nil
+				}
+				cdcControl.ResignOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error) {
+					g.Expect(ordinal).To(Equal(int32(0)))
+					return true, nil
+				}
+				cdcControl.DrainCaptureFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int, bool, error) {
+					return 0, false, nil
+				}
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiCDC.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
 		{
 			name: "normal with pod notReady",
 			changePods: func(pods []*corev1.Pod) {
@@ -0,0 +1,215 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+	"github.com/pingcap/tidb-operator/pkg/util/promquery"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultTiDBCanaryCheckInterval = 30 * time.Second
+	tidbCanaryQueryTimeout         = 10 * time.Second
+
+	// tidbCanaryLatencyQuery is the 99th percentile latency of the queries served by the canary in seconds
+	tidbCanaryLatencyQuery = `histogram_quantile(0.99, sum(rate(tidb_server_handle_query_duration_seconds_bucket{%s}[1m])) by (le))`
+	// tidbCanaryErrorQuery is the percent of the failed queries served by the canary
+	tidbCanaryErrorQuery = `sum(rate(tidb_server_execute_error_total{%[1]s}[1m])) / sum(rate(tidb_server_query_total{%[1]s}[1m])) * 100`
+)
+
+// tidbCanaryQuerier queries the SLOs of the canary, it's replaced in tests
+var tidbCanaryQuerier = promquery.NewQuerier(tidbCanaryQueryTimeout)
+
+// syncTiDBCanary drives the canary upgrade of TiDB by spec.tidb.canary, and returns the partition the pods are
+// upgraded down to. The pods of the canary are upgraded first and soaked, and the rest of the pods are upgraded
+// once the soak period passes. If an SLO of the canary is breached, the canary is rolled back and hold is true,
+// in which case the upgrade of the other pods must not go on.
+func syncTiDBCanary(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, podOrdinals []int32,
+	newSet *apps.StatefulSet, minReadySeconds int) (partition int32, hold bool, err error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	spec := tc.Spec.TiDB.Canary
+	stsStatus := tc.Status.TiDB.StatefulSet
+	if spec == nil || stsStatus == nil || stsStatus.UpdateRevision == stsStatus.CurrentRevision || len(podOrdinals) == 0 {
+		return 0, false, nil
+	}
+
+	canaryOrdinals := podOrdinals
+	if int(spec.Replicas) < len(podOrdinals) {
+		canaryOrdinals = podOrdinals[len(podOrdinals)-int(spec.Replicas):]
+	}
+	partition = canaryOrdinals[0]
+
+	status := tc.Status.TiDB.Canary
+	if status == nil || status.Revision != stsStatus.UpdateRevision {
+		status = &v1alpha1.TiDBCanaryStatus{
+			Revision: stsStatus.UpdateRevision,
+			Phase:    v1alpha1.TiDBCanaryUpgrading,
+		}
+		for _, i := range canaryOrdinals {
+			status.Pods = append(status.Pods, tidbPodName(tcName, i))
+		}
+		tc.Status.TiDB.Canary = status
+		klog.Infof("tidbcluster: [%s/%s] starts the canary upgrade of tidb to revision %s with pods %v", ns, tcName, status.Revision, status.Pods)
+	}
+
+	switch status.Phase {
+	case v1alpha1.TiDBCanaryPromoted:
+		return 0, false, nil
+
+	case v1alpha1.TiDBCanaryRolledBack:
+		return rollbackTiDBCanary(deps, tc, podOrdinals, newSet)
+
+	case v1alpha1.TiDBCanaryUpgrading:
+		for _, i := range canaryOrdinals {
+			upgraded, err := isTiDBCanaryPodUpgraded(deps, tc, i, minReadySeconds)
+			if err != nil {
+				return 0, false, err
+			}
+			if !upgraded {
+				return partition, false, nil
+			}
+		}
+		now := metav1.Now()
+		status.Phase = v1alpha1.TiDBCanarySoaking
+		status.SoakStartTime = &now
+		status.Message = fmt.Sprintf("the canary is upgraded, soak for %s", spec.SoakDuration.Duration)
+		klog.Infof("tidbcluster: [%s/%s]'s tidb canary %v is upgraded, soak for %s", ns, tcName, status.Pods, spec.SoakDuration.Duration)
+		return partition, false, nil
+	}
+
+	// the canary is soaking
+	interval := defaultTiDBCanaryCheckInterval
+	if spec.CheckInterval != nil {
+		interval = spec.CheckInterval.Duration
+	}
+	if status.LastCheckTime == nil || time.Since(status.LastCheckTime.Time) >= interval {
+		now := metav1.Now()
+		status.LastCheckTime = &now
+		breached, message, err := checkTiDBCanarySLOs(tc, spec, status.Pods)
+		if err != nil {
+			// the canary isn't rolled back only because the SLOs can't be queried
+			klog.Warningf("tidbcluster: [%s/%s] failed to check the SLOs of the tidb canary: %v", ns, tcName, err)
+			message = fmt.Sprintf("failed to check the SLOs: %v", err)
+		}
+		status.Message = message
+		if breached {
+			status.Phase = v1alpha1.TiDBCanaryRolledBack
+			deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "TiDBCanaryRolledBack",
+				"the tidb canary %v of revision %s is rolled back, %s", status.Pods, status.Revision, message)
+			return rollbackTiDBCanary(deps, tc, podOrdinals, newSet)
+		}
+	}
+	if status.SoakStartTime != nil && time.Since(status.SoakStartTime.Time) >= spec.SoakDuration.Duration {
+		status.Phase = v1alpha1.TiDBCanaryPromoted
+		deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "TiDBCanaryPromoted",
+			"the tidb canary %v of revision %s passed the soak period, upgrade the rest of the pods", status.Pods, status.Revision)
+		return 0, false, nil
+	}
+	return partition, false, nil
+}
+
+// isTiDBCanaryPodUpgraded returns whether the pod of the canary is upgraded and available
+func isTiDBCanaryPodUpgraded(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, ordinal int32, minReadySeconds int) (bool, error) {
+	ns := tc.GetNamespace()
+	podName := tidbPodName(tc.GetName(), ordinal)
+	pod, err := deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return false, fmt.Errorf("syncTiDBCanary: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tc.GetName(), err)
+	}
+	if pod.Labels[apps.ControllerRevisionHashLabelKey] != tc.Status.TiDB.StatefulSet.UpdateRevision {
+		return false, nil
+	}
+	return checkTiDBPodUpgraded(tc, pod, minReadySeconds) == nil, nil
+}
+
+// rollbackTiDBCanary keeps all the pods at the current revision, and deletes the pods of the canary upgraded
+// to the update revision, which are recreated at the current revision by the StatefulSet controller.
+func rollbackTiDBCanary(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, podOrdinals []int32, newSet *apps.StatefulSet) (int32, bool, error) {
+	ns := tc.GetNamespace()
+	partition := podOrdinals[len(podOrdinals)-1] + 1
+	mngerutils.SetUpgradePartition(newSet, partition)
+	for _, podName := range tc.Status.TiDB.Canary.Pods {
+		pod, err := deps.PodLister.Pods(ns).Get(podName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, true, fmt.Errorf("rollbackTiDBCanary: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tc.GetName(), err)
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != tc.Status.TiDB.StatefulSet.UpdateRevision || pod.DeletionTimestamp != nil {
+			continue
+		}
+		klog.Infof("tidbcluster: [%s/%s] rolls back the tidb canary pod %s", ns, tc.GetName(), podName)
+		if err := deps.PodControl.DeletePod(tc, pod); err != nil {
+			return 0, true, err
+		}
+	}
+	return partition, true, nil
+}
+
+// checkTiDBCanarySLOs queries the SLOs of the canary, and returns whether any of them is breached
+func checkTiDBCanarySLOs(tc *v1alpha1.TidbCluster, spec *v1alpha1.TiDBCanaryStrategy, pods []string) (bool, string, error) {
+	if spec.PrometheusURL == "" || (spec.LatencySLO == nil && spec.MaxErrorPercent == nil) {
+		return false, "no SLO is checked", nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tidbCanaryQueryTimeout)
+	defer cancel()
+
+	selector := fmt.Sprintf(`kubernetes_namespace=%q,cluster=%q,component=%q,instance=~%q`,
+		tc.Namespace, tc.Name, v1alpha1.TiDBMemberType, strings.Join(pods, "|"))
+	var results []string
+	if spec.LatencySLO != nil {
+		seconds, ok, err := tidbCanaryQuerier.Query(ctx, spec.PrometheusURL, fmt.Sprintf(tidbCanaryLatencyQuery, selector))
+		if err != nil {
+			return false, "", err
+		}
+		if ok {
+			latency := time.Duration(seconds * float64(time.Second))
+			if latency > spec.LatencySLO.Duration {
+				return true, fmt.Sprintf("the latency %s breaches the SLO %s", latency.Truncate(time.Millisecond), spec.LatencySLO.Duration), nil
+			}
+			results = append(results, fmt.Sprintf("the latency is %s", latency.Truncate(time.Millisecond)))
+		}
+	}
+	if spec.MaxErrorPercent != nil {
+		percent, ok, err := tidbCanaryQuerier.Query(ctx, spec.PrometheusURL, fmt.Sprintf(tidbCanaryErrorQuery, selector))
+		if err != nil {
+			return false, "", err
+		}
+		if ok {
+			if percent > float64(*spec.MaxErrorPercent) {
+				return true, fmt.Sprintf("the error rate %.2f%% exceeds %d%%", percent, *spec.MaxErrorPercent), nil
+			}
+			results = append(results, fmt.Sprintf("the error rate is %.2f%%", percent))
+		}
+	}
+	if len(results) == 0 {
+		return false, "no metrics of the canary", nil
+	}
+	return false, strings.Join(results, ", "), nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+type fakeCanaryQuerier struct {
+	latency float64
+}
+
+func (q *fakeCanaryQuerier) Query(_ context.Context, _, _ string) (float64, bool, error) {
+	return q.latency, true, nil
+}
+
+func TestTiDBCanaryUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	querier := &fakeCanaryQuerier{latency: 0.5}
+	origin := tidbCanaryQuerier
+	tidbCanaryQuerier = querier
+	defer func() { tidbCanaryQuerier = origin }()
+
+	upgrader, _, podInformer := newTiDBUpgrader()
+	for _, pod := range getTiDBPods() {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Status.PD.Phase = v1alpha1.NormalPhase
+	tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+	tc.Spec.TiDB.Canary = &v1alpha1.TiDBCanaryStrategy{
+		Replicas:      1,
+		SoakDuration:  metav1.Duration{Duration: time.Hour},
+		PrometheusURL: "http://prometheus:9090",
+		LatencySLO:    &metav1.Duration{Duration: time.Second},
+	}
+
+	upgrade := func() *int32 {
+		oldSet := newStatefulSetForTiDBUpgrader()
+		mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+		newSet := oldSet.DeepCopy()
+		g.Expect(upgrader.Upgrade(tc, oldSet, newSet)).To(Succeed())
+		return newSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+
+	// the canary pod is upgraded already, it starts to soak and the other pod is kept
+	g.Expect(upgrade()).To(Equal(pointer.Int32Ptr(1)))
+	g.Expect(tc.Status.TiDB.Canary.Revision).To(Equal("2"))
+	g.Expect(tc.Status.TiDB.Canary.Pods).To(Equal([]string{tidbPodName(upgradeTcName, 1)}))
+	g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.TiDBCanarySoaking))
+
+	// the SLOs are met during the soak period
+	g.Expect(upgrade()).To(Equal(pointer.Int32Ptr(1)))
+	g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.TiDBCanarySoaking))
+	g.Expect(tc.Status.TiDB.Canary.Message).To(Equal("the latency is 500ms"))
+
+	// the rest of the pods are upgraded after the soak period
+	tc.Status.TiDB.Canary.SoakStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+	g.Expect(upgrade()).To(Equal(pointer.Int32Ptr(0)))
+	g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.TiDBCanaryPromoted))
+
+	// the canary of a new revision is rolled back once the SLO is breached
+	tc.Status.TiDB.Canary.Revision = "1"
+	g.Expect(upgrade()).To(Equal(pointer.Int32Ptr(1)))
+	g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.TiDBCanarySoaking))
+	querier.latency = 2
+	g.Expect(upgrade()).To(Equal(pointer.Int32Ptr(2)))
+	g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.TiDBCanaryRolledBack))
+	_, err := podInformer.Lister().Pods(tc.Namespace).Get(tidbPodName(upgradeTcName, 1))
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	// the upgrade is held after the rollback
+	g.Expect(upgrade()).To(Equal(pointer.Int32Ptr(2)))
+}
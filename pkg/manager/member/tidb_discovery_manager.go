@@ -175,6 +175,7 @@ func (m *realTidbDiscoveryManager) getTidbDiscoveryDeployment(obj metav1.Object)
 		podSpec       corev1.PodSpec
 		readinessProb *corev1.Probe
 		livenessProbe *corev1.Probe
+		replicas      int32 = 1
 	)
 
 	switch cluster := obj.(type) {
@@ -189,6 +190,7 @@ func (m *realTidbDiscoveryManager) getTidbDiscoveryDeployment(obj metav1.Object)
 		if cluster.Spec.Discovery.LivenessProbe != nil {
 			livenessProbe = buildDiscoveryProb(cluster.Spec.Discovery.LivenessProbe)
 		}
+		replicas = cluster.DiscoveryReplicas()
 	case *v1alpha1.DMCluster:
 		resources = cluster.Spec.Discovery.ResourceRequirements
 		timezone = cluster.Timezone()
@@ -251,6 +253,10 @@ func (m *realTidbDiscoveryManager) getTidbDiscoveryDeployment(obj metav1.Object)
 			},
 		},
 	}
+	if replicas > 1 {
+		// the registered PD members can't be shared between replicas
+		discoveryContainer.Command = append(discoveryContainer.Command, "--stateless-bootstrap=true")
+	}
 	if readinessProb != nil {
 		discoveryContainer.ReadinessProbe = readinessProb
 	}
@@ -293,11 +299,16 @@ func (m *realTidbDiscoveryManager) getTidbDiscoveryDeployment(obj metav1.Object)
 
 	podLabels := util.CombineStringMap(l.Labels(), baseSpec.Labels())
 	podAnnotations := baseSpec.Annotations()
+	strategy := appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	if replicas > 1 {
+		// keep serving with the other replicas during the rolling update
+		strategy = appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+	}
 	d := &appsv1.Deployment{
 		ObjectMeta: meta,
 		Spec: appsv1.DeploymentSpec{
-			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
-			Replicas: pointer.Int32Ptr(1),
+			Strategy: strategy,
+			Replicas: pointer.Int32Ptr(replicas),
 			Selector: l.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -59,11 +60,20 @@ type InitManager interface {
 
 type tidbInitManager struct {
 	deps *controller.Dependencies
+	// now and newPasswordRotationClient can be replaced in unit tests
+	now                       func() time.Time
+	newPasswordRotationClient func(tc *v1alpha1.TidbCluster, ti *v1alpha1.TidbInitializer, rootPassword string) (passwordRotationClient, error)
 }
 
 // NewTiDBInitManager return tidbInitManager
 func NewTiDBInitManager(deps *controller.Dependencies) InitManager {
-	return &tidbInitManager{deps: deps}
+	return &tidbInitManager{
+		deps: deps,
+		now:  time.Now,
+		newPasswordRotationClient: func(tc *v1alpha1.TidbCluster, ti *v1alpha1.TidbInitializer, rootPassword string) (passwordRotationClient, error) {
+			return newPasswordRotationClient(deps, tc, ti, rootPassword)
+		},
+	}
 }
 
 func (m *tidbInitManager) Sync(ti *v1alpha1.TidbInitializer) error {
@@ -86,10 +96,16 @@ func (m *tidbInitManager) Sync(ti *v1alpha1.TidbInitializer) error {
 	if err != nil {
 		return err
 	}
-	return m.updateStatus(ti.DeepCopy())
+	ti = ti.DeepCopy()
+	rotated, rotateErr := m.syncPasswordRotation(ti, tc)
+	if err := m.updateStatus(ti, rotated); err != nil {
+		return err
+	}
+	return rotateErr
 }
 
-func (m *tidbInitManager) updateStatus(ti *v1alpha1.TidbInitializer) error {
+// updateStatus updates the status of ti by the job, the status is also updated if statusChanged is true
+func (m *tidbInitManager) updateStatus(ti *v1alpha1.TidbInitializer, statusChanged bool) error {
 	name := controller.TiDBInitializerMemberName(ti.Spec.Clusters.Name)
 	ns := ti.Namespace
 	job, err := m.deps.JobLister.Jobs(ns).Get(name)
@@ -111,7 +127,7 @@ func (m *tidbInitManager) updateStatus(ti *v1alpha1.TidbInitializer) error {
 		}
 	}
 
-	var update bool
+	update := statusChanged
 	if !apiequality.Semantic.DeepEqual(ti.Status.JobStatus, job.Status) {
 		job.Status.DeepCopyInto(&ti.Status.JobStatus)
 		update = true
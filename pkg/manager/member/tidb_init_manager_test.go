@@ -82,7 +82,7 @@ func TestTiDBInitManagerSync(t *testing.T) {
 			if err != nil {
 				return err
 			}
-			err = tim.updateStatus(ti.DeepCopy())
+			err = tim.updateStatus(ti.DeepCopy(), false)
 			*/
 			return err
 		}()
@@ -0,0 +1,211 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	rootUser = "root"
+	// passwordRotationTimeout is the timeout to rotate the passwords in a sync
+	passwordRotationTimeout = 30 * time.Second
+)
+
+// passwordRotationClient sets the passwords of the users in a tidb cluster
+type passwordRotationClient interface {
+	// SetPassword creates the user if it doesn't exist, and sets the password of the user
+	SetPassword(ctx context.Context, user, host, password string) error
+	Close() error
+}
+
+// syncPasswordRotation applies the passwords in spec.passwordSecret changed since they were applied
+// last time, and records the rotations in the status of ti. The applied passwords are kept in a
+// secret owned by ti, which is created with the passwords applied by the initialization job.
+func (m *tidbInitManager) syncPasswordRotation(ti *v1alpha1.TidbInitializer, tc *v1alpha1.TidbCluster) (bool, error) {
+	if !ti.Spec.RotatePassword || ti.Spec.PasswordSecret == nil || ti.Status.Phase != v1alpha1.InitializePhaseCompleted {
+		return false, nil
+	}
+	ns := ti.Namespace
+	secret, err := m.deps.SecretLister.Secrets(ns).Get(*ti.Spec.PasswordSecret)
+	if err != nil {
+		return false, fmt.Errorf("syncPasswordRotation: failed to get secret %s for TidbInitializer %s/%s, error: %v", *ti.Spec.PasswordSecret, ns, ti.Name, err)
+	}
+	desired := readPasswords(secret)
+
+	applied := &corev1.Secret{}
+	appliedName := controller.TiDBInitializerAppliedPasswordSecretName(ti.Spec.Clusters.Name)
+	exist, err := m.deps.TypedControl.Exist(client.ObjectKey{Namespace: ns, Name: appliedName}, applied)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		// the passwords are applied by the initialization job
+		_, err := m.deps.TypedControl.CreateOrUpdateSecret(ti, newAppliedPasswordSecret(ti, appliedName, desired))
+		return false, err
+	}
+	current := readPasswords(applied)
+
+	var users []string
+	for user, password := range desired {
+		if old, ok := current[user]; !ok || old != password {
+			users = append(users, user)
+		}
+	}
+	if len(users) == 0 {
+		return false, nil
+	}
+	// root is rotated at last, as the others are rotated by root
+	sort.Slice(users, func(i, j int) bool {
+		if users[i] == rootUser || users[j] == rootUser {
+			return users[j] == rootUser && users[i] != rootUser
+		}
+		return users[i] < users[j]
+	})
+
+	cli, err := m.newPasswordRotationClient(tc, ti, current[rootUser])
+	if err != nil {
+		return false, fmt.Errorf("syncPasswordRotation: failed to connect to tidb cluster %s/%s, error: %v", ns, tc.Name, err)
+	}
+	defer cli.Close()
+
+	host := "%"
+	if ti.Spec.PermitHost != nil {
+		host = *ti.Spec.PermitHost
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), passwordRotationTimeout)
+	defer cancel()
+	var rotateErr error
+	var rotated []string
+	for _, user := range users {
+		if err := cli.SetPassword(ctx, user, host, desired[user]); err != nil {
+			rotateErr = fmt.Errorf("syncPasswordRotation: failed to rotate the password of user %s for TidbInitializer %s/%s, error: %v", user, ns, ti.Name, err)
+			m.deps.Recorder.Eventf(ti, corev1.EventTypeWarning, "PasswordRotationFailed", "rotate the password of user %s failed: %v", user, err)
+			break
+		}
+		current[user] = desired[user]
+		rotated = append(rotated, user)
+	}
+	if len(rotated) == 0 {
+		return false, rotateErr
+	}
+
+	if _, err := m.deps.TypedControl.CreateOrUpdateSecret(ti, newAppliedPasswordSecret(ti, appliedName, current)); err != nil {
+		return false, err
+	}
+	now := metav1.NewTime(m.now())
+	for _, user := range rotated {
+		found := false
+		for i := range ti.Status.PasswordRotations {
+			if ti.Status.PasswordRotations[i].User == user {
+				ti.Status.PasswordRotations[i].LastRotationTime = now
+				found = true
+			}
+		}
+		if !found {
+			ti.Status.PasswordRotations = append(ti.Status.PasswordRotations, v1alpha1.PasswordRotation{User: user, LastRotationTime: now})
+		}
+	}
+	klog.Infof("TidbInitializer %s/%s, rotate the passwords of users %v", ns, ti.Name, rotated)
+	m.deps.Recorder.Eventf(ti, corev1.EventTypeNormal, "PasswordRotated", "rotate the passwords of users %s", strings.Join(rotated, ", "))
+	return true, rotateErr
+}
+
+// readPasswords returns the passwords of the users in the secret, the password is the first line of
+// the value as the initialization job reads it
+func readPasswords(secret *corev1.Secret) map[string]string {
+	passwords := map[string]string{}
+	for user, data := range secret.Data {
+		if strings.HasPrefix(user, ".") {
+			continue
+		}
+		passwords[user] = strings.SplitN(string(data), "\n", 2)[0]
+	}
+	return passwords
+}
+
+func newAppliedPasswordSecret(ti *v1alpha1.TidbInitializer, name string, passwords map[string]string) *corev1.Secret {
+	data := map[string][]byte{}
+	for user, password := range passwords {
+		data[user] = []byte(password)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ti.Namespace,
+			Labels:    label.NewInitializer().Instance(ti.Name).Initializer(ti.Name),
+		},
+		Data: data,
+	}
+}
+
+func newPasswordRotationClient(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, ti *v1alpha1.TidbInitializer, rootPassword string) (passwordRotationClient, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = rootUser
+	cfg.Passwd = rootPassword
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s.%s.svc:%d", controller.TiDBMemberName(tc.Name), tc.Namespace, tc.Spec.TiDB.GetServicePort())
+	cfg.Timeout = passwordRotationTimeout
+	// the placeholders of user names can't be prepared by the server
+	cfg.InterpolateParams = true
+	if tc.Spec.TiDB.IsTLSClientEnabled() && !tc.SkipTLSWhenConnectTiDB() {
+		tlsConfig, err := pdapi.GetTLSConfig(deps.SecretLister, pdapi.Namespace(tc.Namespace), util.TiDBClientTLSSecretName(tc.Name, ti.Spec.TLSClientSecretName))
+		if err != nil {
+			return nil, err
+		}
+		cfg.TLS = tlsConfig
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlPasswordRotationClient{db: sql.OpenDB(connector)}, nil
+}
+
+type sqlPasswordRotationClient struct {
+	db *sql.DB
+}
+
+func (c *sqlPasswordRotationClient) SetPassword(ctx context.Context, user, host, password string) error {
+	if user != rootUser {
+		if _, err := c.db.ExecContext(ctx, "CREATE USER IF NOT EXISTS ?@? IDENTIFIED BY ?", user, host, password); err != nil {
+			return err
+		}
+	}
+	_, err := c.db.ExecContext(ctx, "ALTER USER ?@? IDENTIFIED BY ?", user, host, password)
+	return err
+}
+
+func (c *sqlPasswordRotationClient) Close() error {
+	return c.db.Close()
+}
+
+var _ passwordRotationClient = &sqlPasswordRotationClient{}
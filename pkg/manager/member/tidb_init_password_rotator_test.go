@@ -0,0 +1,125 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fakePasswordRotationClient struct {
+	rootPassword string
+	passwords    map[string]string
+	failedUsers  map[string]bool
+	order        []string
+}
+
+func (c *fakePasswordRotationClient) SetPassword(_ context.Context, user, host, password string) error {
+	if c.failedUsers[user] {
+		return fmt.Errorf("access denied")
+	}
+	c.passwords[user+"@"+host] = password
+	c.order = append(c.order, user)
+	return nil
+}
+
+func (c *fakePasswordRotationClient) Close() error {
+	return nil
+}
+
+func TestSyncPasswordRotation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tim, _, _ := newFakeTiDBInitManager()
+	now := time.Now()
+	tim.now = func() time.Time { return now }
+	cli := &fakePasswordRotationClient{passwords: map[string]string{}, failedUsers: map[string]bool{}}
+	tim.newPasswordRotationClient = func(_ *v1alpha1.TidbCluster, _ *v1alpha1.TidbInitializer, rootPassword string) (passwordRotationClient, error) {
+		cli.rootPassword = rootPassword
+		return cli, nil
+	}
+
+	tc := newTidbClusterForTiDB()
+	ti := newTidbInitializerForTiDB()
+	ti.Spec.PasswordSecret = pointer.StringPtr("passwords")
+	ti.Spec.RotatePassword = true
+	ti.Spec.PermitHost = pointer.StringPtr("10.0.0.%")
+	ti.Status.Phase = v1alpha1.InitializePhaseCompleted
+
+	secretIndexer := tim.deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "passwords", Namespace: ti.Namespace},
+		Data:       map[string][]byte{"root": []byte("root1\n"), "app": []byte("app1")},
+	}
+	g.Expect(secretIndexer.Add(secret)).To(Succeed())
+	getApplied := func() map[string][]byte {
+		applied := &corev1.Secret{}
+		exist, err := tim.deps.TypedControl.Exist(client.ObjectKey{Namespace: ti.Namespace, Name: controller.TiDBInitializerAppliedPasswordSecretName(tc.Name)}, applied)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(exist).To(BeTrue())
+		return applied.Data
+	}
+
+	// the passwords applied by the initialization job are recorded
+	rotated, err := tim.syncPasswordRotation(ti, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotated).To(BeFalse())
+	g.Expect(getApplied()).To(Equal(map[string][]byte{"root": []byte("root1"), "app": []byte("app1")}))
+
+	// nothing is rotated if the passwords are not changed
+	rotated, err = tim.syncPasswordRotation(ti, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotated).To(BeFalse())
+
+	// the changed passwords are rotated by the applied root password, root is rotated at last
+	secret = secret.DeepCopy()
+	secret.Data = map[string][]byte{"root": []byte("root2"), "app": []byte("app2"), "new": []byte("new1")}
+	g.Expect(secretIndexer.Update(secret)).To(Succeed())
+	rotated, err = tim.syncPasswordRotation(ti, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotated).To(BeTrue())
+	g.Expect(cli.rootPassword).To(Equal("root1"))
+	g.Expect(cli.order).To(Equal([]string{"app", "new", "root"}))
+	g.Expect(cli.passwords).To(Equal(map[string]string{"root@10.0.0.%": "root2", "app@10.0.0.%": "app2", "new@10.0.0.%": "new1"}))
+	g.Expect(getApplied()).To(Equal(map[string][]byte{"root": []byte("root2"), "app": []byte("app2"), "new": []byte("new1")}))
+	g.Expect(ti.Status.PasswordRotations).To(ConsistOf(
+		v1alpha1.PasswordRotation{User: "root", LastRotationTime: metav1.NewTime(now)},
+		v1alpha1.PasswordRotation{User: "app", LastRotationTime: metav1.NewTime(now)},
+		v1alpha1.PasswordRotation{User: "new", LastRotationTime: metav1.NewTime(now)},
+	))
+
+	// the passwords rotated before the failure are recorded
+	now = now.Add(time.Hour)
+	cli.failedUsers["root"] = true
+	secret = secret.DeepCopy()
+	secret.Data = map[string][]byte{"root": []byte("root3"), "app": []byte("app3"), "new": []byte("new1")}
+	g.Expect(secretIndexer.Update(secret)).To(Succeed())
+	rotated, err = tim.syncPasswordRotation(ti, tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(rotated).To(BeTrue())
+	g.Expect(cli.rootPassword).To(Equal("root2"))
+	g.Expect(getApplied()).To(Equal(map[string][]byte{"root": []byte("root2"), "app": []byte("app3"), "new": []byte("new1")}))
+	g.Expect(ti.Status.PasswordRotations).To(ContainElement(v1alpha1.PasswordRotation{User: "app", LastRotationTime: metav1.NewTime(now)}))
+	g.Expect(ti.Status.PasswordRotations).To(ContainElement(v1alpha1.PasswordRotation{User: "root", LastRotationTime: metav1.NewTime(now.Add(-time.Hour))}))
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const (
+	lbReadinessReasonHealthy    = "TargetHealthy"
+	lbReadinessReasonNotHealthy = "TargetNotHealthy"
+)
+
+// lbTargetHealthChecker returns the IPs of the healthy targets of an external load balancer
+type lbTargetHealthChecker interface {
+	HealthyTargets(readiness *v1alpha1.TiDBLoadBalancerReadiness) (sets.String, error)
+}
+
+type cloudLBTargetHealthChecker struct{}
+
+func (c *cloudLBTargetHealthChecker) HealthyTargets(readiness *v1alpha1.TiDBLoadBalancerReadiness) (sets.String, error) {
+	switch {
+	case readiness.AWS != nil:
+		return awsHealthyTargets(readiness.AWS)
+	case readiness.GCP != nil:
+		return gcpHealthyTargets(readiness.GCP)
+	}
+	return nil, fmt.Errorf("no load balancer is set in the load balancer readiness")
+}
+
+func awsHealthyTargets(tg *v1alpha1.AWSTargetGroup) (sets.String, error) {
+	region := tg.Region
+	if region == "" {
+		tgARN, err := arn.Parse(tg.TargetGroupARN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse target group arn %s: %v", tg.TargetGroupARN, err)
+		}
+		region = tgARN.Region
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{Config: *aws.NewConfig().WithRegion(region)})
+	if err != nil {
+		return nil, err
+	}
+	out, err := elbv2.New(sess).DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(tg.TargetGroupARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the target health of %s: %v", tg.TargetGroupARN, err)
+	}
+	healthy := sets.NewString()
+	for _, desc := range out.TargetHealthDescriptions {
+		if desc.Target == nil || desc.TargetHealth == nil {
+			continue
+		}
+		if aws.StringValue(desc.TargetHealth.State) == elbv2.TargetHealthStateEnumHealthy {
+			healthy.Insert(aws.StringValue(desc.Target.Id))
+		}
+	}
+	return healthy, nil
+}
+
+func gcpHealthyTargets(neg *v1alpha1.GCPNetworkEndpointGroup) (sets.String, error) {
+	ctx := context.TODO()
+	svc, err := compute.NewService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	healthy := sets.NewString()
+	for _, zone := range neg.Zones {
+		req := &compute.NetworkEndpointGroupsListEndpointsRequest{HealthStatus: "SHOW"}
+		err := svc.NetworkEndpointGroups.ListNetworkEndpoints(neg.Project, zone, neg.Name, req).Pages(ctx, func(page *compute.NetworkEndpointGroupsListNetworkEndpoints) error {
+			for _, item := range page.Items {
+				if item.NetworkEndpoint == nil {
+					continue
+				}
+				for _, health := range item.Healths {
+					if health.HealthState == "HEALTHY" {
+						healthy.Insert(item.NetworkEndpoint.IpAddress)
+						break
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the network endpoints of %s/%s/%s: %v", neg.Project, zone, neg.Name, err)
+		}
+	}
+	return healthy, nil
+}
+
+// syncLoadBalancerReadiness sets the condition of the load balancer readiness gate of the TiDB pods,
+// which is set to true once the pod IP is a healthy target of the load balancer. The condition is
+// not flipped back afterwards, so that a flapping load balancer doesn't make the pods unready.
+func (m *tidbMemberManager) syncLoadBalancerReadiness(tc *v1alpha1.TidbCluster) error {
+	conditionType := tc.Spec.TiDB.GetLoadBalancerReadinessConditionType()
+	if conditionType == "" {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	selector, err := label.New().Instance(tcName).TiDB().Selector()
+	if err != nil {
+		return err
+	}
+	pods, err := m.deps.PodLister.Pods(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("syncLoadBalancerReadiness: failed to list pods for cluster %s/%s, error: %s", ns, tcName, err)
+	}
+	var pending []*corev1.Pod
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" || !hasReadinessGate(pod, conditionType) {
+			continue
+		}
+		if cond := getPodConditionFromList(pod.Status.Conditions, conditionType); cond != nil && cond.Status == corev1.ConditionTrue {
+			continue
+		}
+		pending = append(pending, pod)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	healthy, err := m.lbHealthChecker.HealthyTargets(tc.Spec.TiDB.LoadBalancerReadiness)
+	if err != nil {
+		return fmt.Errorf("syncLoadBalancerReadiness: failed to get the healthy targets for cluster %s/%s, error: %v", ns, tcName, err)
+	}
+	for _, pod := range pending {
+		cond := corev1.PodCondition{
+			Type:    conditionType,
+			Status:  corev1.ConditionFalse,
+			Reason:  lbReadinessReasonNotHealthy,
+			Message: fmt.Sprintf("pod IP %s is not a healthy target of the load balancer", pod.Status.PodIP),
+		}
+		if healthy.Has(pod.Status.PodIP) {
+			cond.Status = corev1.ConditionTrue
+			cond.Reason = lbReadinessReasonHealthy
+			cond.Message = fmt.Sprintf("pod IP %s is a healthy target of the load balancer", pod.Status.PodIP)
+		}
+		if old := getPodConditionFromList(pod.Status.Conditions, conditionType); old != nil && old.Status == cond.Status {
+			continue
+		}
+		if err := m.setPodCondition(pod, cond); err != nil {
+			return fmt.Errorf("syncLoadBalancerReadiness: failed to set condition %s of pod %s/%s, error: %v", conditionType, ns, pod.Name, err)
+		}
+		klog.Infof("syncLoadBalancerReadiness: set condition %s of pod %s/%s to %s", conditionType, ns, pod.Name, cond.Status)
+	}
+	return nil
+}
+
+func (m *tidbMemberManager) setPodCondition(pod *corev1.Pod, cond corev1.PodCondition) error {
+	pod = pod.DeepCopy()
+	cond.LastTransitionTime = metav1.Now()
+	found := false
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == cond.Type {
+			pod.Status.Conditions[i] = cond
+			found = true
+			break
+		}
+	}
+	if !found {
+		pod.Status.Conditions = append(pod.Status.Conditions, cond)
+	}
+	_, err := m.deps.KubeClientset.CoreV1().Pods(pod.Namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+	return err
+}
+
+func hasReadinessGate(pod *corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == conditionType {
+			return true
+		}
+	}
+	return false
+}
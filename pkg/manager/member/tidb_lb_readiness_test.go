@@ -0,0 +1,100 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type fakeLBTargetHealthChecker struct {
+	healthy sets.String
+	err     error
+	calls   int
+}
+
+func (c *fakeLBTargetHealthChecker) HealthyTargets(_ *v1alpha1.TiDBLoadBalancerReadiness) (sets.String, error) {
+	c.calls++
+	return c.healthy, c.err
+}
+
+func TestTiDBLoadBalancerReadiness(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiDB()
+	tc.Spec.TiDB.LoadBalancerReadiness = &v1alpha1.TiDBLoadBalancerReadiness{
+		AWS: &v1alpha1.AWSTargetGroup{TargetGroupARN: "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/tidb/73e2d6bc24d8a067"},
+	}
+	conditionType := tc.Spec.TiDB.GetLoadBalancerReadinessConditionType()
+	g.Expect(conditionType).To(Equal(corev1.PodConditionType("tidb.pingcap.com/load-balancer-ready")))
+
+	set, err := getNewTiDBSetForTidbCluster(tc, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Template.Spec.ReadinessGates).To(ConsistOf(corev1.PodReadinessGate{ConditionType: conditionType}))
+
+	tmm, _, _, indexers := newFakeTiDBMemberManager()
+	checker := &fakeLBTargetHealthChecker{healthy: sets.NewString("10.0.0.1")}
+	tmm.lbHealthChecker = checker
+	for i := 0; i < 3; i++ {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tidbPodName(tc.Name, int32(i)),
+				Namespace: tc.Namespace,
+				Labels:    label.New().Instance(tc.Name).TiDB().Labels(),
+			},
+			Status: corev1.PodStatus{PodIP: fmt.Sprintf("10.0.0.%d", i+1)},
+		}
+		// the last pod is created before the readiness gate is enabled
+		if i < 2 {
+			pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: conditionType}}
+		}
+		g.Expect(indexers.pod.Add(pod)).To(Succeed())
+		_, err := tmm.deps.KubeClientset.CoreV1().Pods(tc.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	getCondition := func(i int) *corev1.PodCondition {
+		pod, err := tmm.deps.KubeClientset.CoreV1().Pods(tc.Namespace).Get(context.TODO(), tidbPodName(tc.Name, int32(i)), metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		// sync the pod to the lister
+		g.Expect(indexers.pod.Update(pod)).To(Succeed())
+		return getPodConditionFromList(pod.Status.Conditions, conditionType)
+	}
+
+	g.Expect(tmm.syncLoadBalancerReadiness(tc)).To(Succeed())
+	g.Expect(checker.calls).To(Equal(1))
+	g.Expect(getCondition(0).Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(getCondition(1).Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(getCondition(1).Reason).To(Equal(lbReadinessReasonNotHealthy))
+	g.Expect(getCondition(2)).To(BeNil())
+
+	// the condition is not flipped back once it's true
+	checker.healthy = sets.NewString("10.0.0.2")
+	g.Expect(tmm.syncLoadBalancerReadiness(tc)).To(Succeed())
+	g.Expect(getCondition(0).Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(getCondition(1).Status).To(Equal(corev1.ConditionTrue))
+
+	// the load balancer is not checked if all the pods are ready
+	checker.err = fmt.Errorf("unreachable")
+	g.Expect(tmm.syncLoadBalancerReadiness(tc)).To(Succeed())
+	g.Expect(checker.calls).To(Equal(2))
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	defaultLogShipperImage = "fluent/fluent-bit:3.1.9"
+	logShipperContainer    = "log-shipper"
+
+	logShipperConfigVolume = "log-shipper-config"
+	logShipperConfigDir    = "/etc/log-shipper"
+	logShipperConfigKey    = "log-shipper-config"
+	logShipperParsersKey   = "log-shipper-parsers"
+	logShipperDBVolume     = "log-shipper-db"
+	logShipperDBDir        = "/var/lib/log-shipper"
+
+	logShipperSlowLogTag  = "slowlog"
+	logShipperAuditLogTag = "auditlog"
+	defaultLokiPort       = 3100
+)
+
+// logShipperParsers parses the multi-line slow query records which start with `# Time:`
+const logShipperParsers = `[MULTILINE_PARSER]
+    name          tidb_slowlog
+    type          regex
+    flush_timeout 1000
+    rule          "start_state" "/^# Time: /"        "cont"
+    rule          "cont"        "/^(?!# Time: ).*/" "cont"
+`
+
+// renderTiDBLogShipperConfig renders the fluent-bit config of the log shipper. The path of
+// the slow log and the names of the cluster and the pod are passed by the env of the container.
+func renderTiDBLogShipperConfig(tc *v1alpha1.TidbCluster) string {
+	spec := tc.Spec.TiDB.LogShipping
+	var b strings.Builder
+	section := func(name string, kvs ...string) {
+		fmt.Fprintf(&b, "[%s]\n", name)
+		for i := 0; i+1 < len(kvs); i += 2 {
+			fmt.Fprintf(&b, "    %-16s %s\n", kvs[i], kvs[i+1])
+		}
+		b.WriteString("\n")
+	}
+
+	section("SERVICE",
+		"Flush", "1",
+		"Log_Level", "info",
+		"Parsers_File", path.Join(logShipperConfigDir, "parsers.conf"))
+
+	var tags []string
+	if tc.Spec.TiDB.ShouldSeparateSlowLog() {
+		tags = append(tags, logShipperSlowLogTag)
+		section("INPUT",
+			"Name", "tail",
+			"Tag", logShipperSlowLogTag,
+			"Path", "${SLOW_LOG_FILE}",
+			"DB", path.Join(logShipperDBDir, logShipperSlowLogTag+".db"),
+			"multiline.parser", "tidb_slowlog",
+			"Refresh_Interval", "5")
+	}
+	if spec.AuditLogFile != "" {
+		tags = append(tags, logShipperAuditLogTag)
+		section("INPUT",
+			"Name", "tail",
+			"Tag", logShipperAuditLogTag,
+			"Path", spec.AuditLogFile,
+			"DB", path.Join(logShipperDBDir, logShipperAuditLogTag+".db"),
+			"Refresh_Interval", "5")
+	}
+	for _, tag := range tags {
+		section("FILTER",
+			"Name", "record_modifier",
+			"Match", tag,
+			"Record", "log_type "+tag)
+	}
+	section("FILTER",
+		"Name", "record_modifier",
+		"Match", "*",
+		"Record", "cluster ${CLUSTER_NAME}",
+		"Record", "instance ${POD_NAME}")
+
+	for _, output := range spec.Outputs {
+		switch {
+		case output.Loki != nil:
+			loki := output.Loki
+			port := loki.Port
+			if port == 0 {
+				port = defaultLokiPort
+			}
+			labels := []string{"cluster=${CLUSTER_NAME}", "instance=${POD_NAME}", "log=$log_type"}
+			var extra []string
+			for k, v := range loki.Labels {
+				extra = append(extra, fmt.Sprintf("%s=%s", k, v))
+			}
+			sort.Strings(extra)
+			kvs := []string{
+				"Name", "loki",
+				"Match", "*",
+				"Host", loki.Host,
+				"Port", fmt.Sprint(port),
+				"Labels", strings.Join(append(labels, extra...), ", "),
+			}
+			if loki.TenantID != "" {
+				kvs = append(kvs, "Tenant_ID", loki.TenantID)
+			}
+			if loki.TLS {
+				kvs = append(kvs, "TLS", "On")
+			}
+			section("OUTPUT", kvs...)
+		case output.S3 != nil:
+			s3 := output.S3
+			kvs := []string{
+				"Name", "s3",
+				"Match", "*",
+				"Bucket", s3.Bucket,
+				"Region", s3.Region,
+				"S3_Key_Format", path.Join("/", s3.Prefix, "${CLUSTER_NAME}", "${POD_NAME}", "$TAG", "%Y/%m/%d/%H%M%S-$UUID.gz"),
+				"Compression", "gzip",
+				"Use_Put_Object", "On",
+				"Total_File_Size", "50M",
+				"Upload_Timeout", "10m",
+			}
+			if s3.Endpoint != "" {
+				kvs = append(kvs, "Endpoint", s3.Endpoint)
+			}
+			section("OUTPUT", kvs...)
+		case output.Kafka != nil:
+			section("OUTPUT",
+				"Name", "kafka",
+				"Match", "*",
+				"Brokers", strings.Join(output.Kafka.Brokers, ","),
+				"Topics", output.Kafka.Topic)
+		}
+	}
+	return b.String()
+}
+
+// buildTiDBLogShipperContainer builds the log shipper container which mounts the slow log volume and
+// the volume of the audit log file, it returns the volumes used only by the log shipper as well.
+func buildTiDBLogShipperContainer(tc *v1alpha1.TidbCluster, configMap string, tidbVolMounts []corev1.VolumeMount,
+	slowLogVolMount *corev1.VolumeMount, slowLogFile string) (corev1.Container, []corev1.Volume, error) {
+	spec := tc.Spec.TiDB.LogShipping
+	image := spec.Image
+	if image == "" {
+		image = defaultLogShipperImage
+	}
+
+	volMounts := []corev1.VolumeMount{
+		{Name: logShipperConfigVolume, ReadOnly: true, MountPath: logShipperConfigDir},
+		{Name: logShipperDBVolume, MountPath: logShipperDBDir},
+	}
+	env := []corev1.EnvVar{
+		{Name: "CLUSTER_NAME", Value: tc.GetName()},
+		{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+			},
+		},
+	}
+	if slowLogVolMount != nil {
+		volMounts = append(volMounts, *slowLogVolMount)
+		env = append(env, corev1.EnvVar{Name: "SLOW_LOG_FILE", Value: slowLogFile})
+	}
+	if spec.AuditLogFile != "" {
+		auditLogVolMount, ok := findVolumeMountOfFile(tidbVolMounts, spec.AuditLogFile)
+		if !ok {
+			return corev1.Container{}, nil, fmt.Errorf("audit log file %s is not in any volume mounted to tidb", spec.AuditLogFile)
+		}
+		if slowLogVolMount == nil || auditLogVolMount.Name != slowLogVolMount.Name {
+			volMounts = append(volMounts, auditLogVolMount)
+		}
+	}
+
+	c := corev1.Container{
+		Name:            logShipperContainer,
+		Image:           image,
+		ImagePullPolicy: tc.HelperImagePullPolicy(),
+		Args:            []string{"-c", path.Join(logShipperConfigDir, "fluent-bit.conf")},
+		Resources:       controller.ContainerResource(spec.ResourceRequirements),
+		VolumeMounts:    volMounts,
+		Env:             util.AppendOverwriteEnv(env, spec.Env),
+	}
+	if spec.UseSidecar {
+		c.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+	}
+
+	vols := []corev1.Volume{
+		{
+			Name: logShipperConfigVolume, VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: configMap,
+					},
+					Items: []corev1.KeyToPath{
+						{Key: logShipperConfigKey, Path: "fluent-bit.conf"},
+						{Key: logShipperParsersKey, Path: "parsers.conf"},
+					},
+				},
+			},
+		},
+		{
+			Name: logShipperDBVolume, VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	return c, vols, nil
+}
+
+// findVolumeMountOfFile returns the volume mount with the longest mount path containing the file
+func findVolumeMountOfFile(volMounts []corev1.VolumeMount, file string) (corev1.VolumeMount, bool) {
+	var (
+		found corev1.VolumeMount
+		ok    bool
+	)
+	file = path.Clean(file)
+	for _, volMount := range volMounts {
+		dir := path.Clean(volMount.MountPath)
+		if !strings.HasPrefix(file, strings.TrimSuffix(dir, "/")+"/") {
+			continue
+		}
+		if !ok || len(dir) > len(path.Clean(found.MountPath)) {
+			found, ok = volMount, true
+		}
+	}
+	return found, ok
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTidbClusterForLogShipping() *v1alpha1.TidbCluster {
+	return &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns"},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiDB: &v1alpha1.TiDBSpec{
+				LogShipping: &v1alpha1.TiDBLogShippingSpec{
+					AuditLogFile: "/var/log/audit/tidb-audit.log",
+					Outputs: []v1alpha1.LogShippingOutput{
+						{Loki: &v1alpha1.LokiLogOutput{Host: "loki.monitoring", TenantID: "tidb", Labels: map[string]string{"env": "prod"}}},
+						{S3: &v1alpha1.S3LogOutput{Bucket: "logs", Region: "us-west-2", Prefix: "tidb"}},
+						{Kafka: &v1alpha1.KafkaLogOutput{Brokers: []string{"kafka-0:9092", "kafka-1:9092"}, Topic: "tidb-logs"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderTiDBLogShipperConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForLogShipping()
+	g.Expect(renderTiDBLogShipperConfig(tc)).To(Equal(`[SERVICE]
+    Flush            1
+    Log_Level        info
+    Parsers_File     /etc/log-shipper/parsers.conf
+
+[INPUT]
+    Name             tail
+    Tag              slowlog
+    Path             ${SLOW_LOG_FILE}
+    DB               /var/lib/log-shipper/slowlog.db
+    multiline.parser tidb_slowlog
+    Refresh_Interval 5
+
+[INPUT]
+    Name             tail
+    Tag              auditlog
+    Path             /var/log/audit/tidb-audit.log
+    DB               /var/lib/log-shipper/auditlog.db
+    Refresh_Interval 5
+
+[FILTER]
+    Name             record_modifier
+    Match            slowlog
+    Record           log_type slowlog
+
+[FILTER]
+    Name             record_modifier
+    Match            auditlog
+    Record           log_type auditlog
+
+[FILTER]
+    Name             record_modifier
+    Match            *
+    Record           cluster ${CLUSTER_NAME}
+    Record           instance ${POD_NAME}
+
+[OUTPUT]
+    Name             loki
+    Match            *
+    Host             loki.monitoring
+    Port             3100
+    Labels           cluster=${CLUSTER_NAME}, instance=${POD_NAME}, log=$log_type, env=prod
+    Tenant_ID        tidb
+
+[OUTPUT]
+    Name             s3
+    Match            *
+    Bucket           logs
+    Region           us-west-2
+    S3_Key_Format    /tidb/${CLUSTER_NAME}/${POD_NAME}/$TAG/%Y/%m/%d/%H%M%S-$UUID.gz
+    Compression      gzip
+    Use_Put_Object   On
+    Total_File_Size  50M
+    Upload_Timeout   10m
+
+[OUTPUT]
+    Name             kafka
+    Match            *
+    Brokers          kafka-0:9092,kafka-1:9092
+    Topics           tidb-logs
+
+`))
+}
+
+func TestBuildTiDBLogShipperContainer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForLogShipping()
+	slowLogVolMount := corev1.VolumeMount{Name: defaultSlowLogVolume, MountPath: defaultSlowLogDir}
+	auditLogVolMount := corev1.VolumeMount{Name: "audit", MountPath: "/var/log/audit"}
+	tidbVolMounts := []corev1.VolumeMount{
+		{Name: "config", ReadOnly: true, MountPath: "/etc/tidb"},
+		{Name: "log", MountPath: "/var/log"},
+		auditLogVolMount,
+		slowLogVolMount,
+	}
+
+	c, vols, err := buildTiDBLogShipperContainer(tc, "demo-tidb-abc", tidbVolMounts, &slowLogVolMount, defaultSlowLogFile)
+	g.Expect(err).To(Succeed())
+	g.Expect(c.Image).To(Equal(defaultLogShipperImage))
+	g.Expect(c.RestartPolicy).To(BeNil())
+	g.Expect(c.VolumeMounts).To(Equal([]corev1.VolumeMount{
+		{Name: logShipperConfigVolume, ReadOnly: true, MountPath: logShipperConfigDir},
+		{Name: logShipperDBVolume, MountPath: logShipperDBDir},
+		slowLogVolMount,
+		auditLogVolMount,
+	}))
+	g.Expect(c.Env).To(ContainElement(corev1.EnvVar{Name: "SLOW_LOG_FILE", Value: defaultSlowLogFile}))
+	g.Expect(vols).To(HaveLen(2))
+	g.Expect(vols[0].ConfigMap.Name).To(Equal("demo-tidb-abc"))
+
+	// the audit log file must be in a volume of tidb
+	tc.Spec.TiDB.LogShipping.AuditLogFile = "/tmp/audit.log"
+	_, _, err = buildTiDBLogShipperContainer(tc, "demo-tidb-abc", tidbVolMounts, &slowLogVolMount, defaultSlowLogFile)
+	g.Expect(err).To(HaveOccurred())
+}
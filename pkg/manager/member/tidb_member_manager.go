@@ -44,6 +44,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -92,6 +94,7 @@ type tidbMemberManager struct {
 	tidbFailover      Failover
 	suspender         suspender.Suspender
 	podVolumeModifier volumes.PodVolumeModifier
+	lbHealthChecker   lbTargetHealthChecker
 
 	tidbStatefulSetIsUpgradingFn func(corelisters.PodLister, *apps.StatefulSet, *v1alpha1.TidbCluster) (bool, error)
 }
@@ -105,6 +108,7 @@ func NewTiDBMemberManager(deps *controller.Dependencies, scaler Scaler, tidbUpgr
 		tidbFailover:                 tidbFailover,
 		suspender:                    spder,
 		podVolumeModifier:            pvm,
+		lbHealthChecker:              &cloudLBTargetHealthChecker{},
 		tidbStatefulSetIsUpgradingFn: tidbStatefulSetIsUpgrading,
 	}
 }
@@ -122,7 +126,7 @@ func (m *tidbMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	component := v1alpha1.TiDBMemberType
 	needSuspend, err := m.suspender.SuspendComponent(tc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, ns, tcName)
@@ -162,10 +166,24 @@ func (m *tidbMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 		m.syncInitializer(tc)
 	}
 
+	// Sync the readiness gate before the StatefulSet, whose rolling upgrade waits for the pods to be ready.
+	// The error is not returned so that a misconfigured load balancer doesn't block the other changes.
+	if err := m.syncLoadBalancerReadiness(tc); err != nil {
+		klog.Warningf("TidbCluster: [%s/%s], %v", ns, tcName, err)
+	}
+
 	// Sync TiDB StatefulSet
 	return m.syncTiDBStatefulSetForTidbCluster(tc)
 }
 
+// SyncStatus refreshes the status of TiDB from its statefulset without acting upon the spec
+func (m *tidbMemberManager) SyncStatus(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiDB == nil {
+		return nil
+	}
+	return syncStatusFromStatefulSet(m.deps, tc, controller.TiDBMemberName(tc.GetName()), m.syncTidbClusterStatus)
+}
+
 func (m *tidbMemberManager) syncRecoveryForTidbCluster(tc *v1alpha1.TidbCluster) error {
 	// Check whether the cluster is in recovery mode
 	// and whether the volumes have been restored for TiKV
@@ -270,6 +288,7 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 	if err != nil {
 		return err
 	}
+	degradeNativeSidecars(m.deps.Capabilities, newTiDBSet)
 
 	if setNotExist {
 		err = mngerutils.SetStatefulSetLastAppliedConfigAnnotation(newTiDBSet)
@@ -470,10 +489,52 @@ func (m *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
 
 	newSvc := getNewTiDBServiceOrNil(tc)
 	// TODO: delete tidb service if user remove the service spec deliberately
-	if newSvc == nil {
-		return nil
+	if newSvc != nil {
+		if err := m.syncTiDBClientService(tc, newSvc); err != nil {
+			return err
+		}
 	}
 
+	return m.syncTiDBNamedServices(tc)
+}
+
+// syncTiDBNamedServices syncs the named client services in spec.tidb.services and deletes the ones removed from it
+func (m *tidbMemberManager) syncTiDBNamedServices(tc *v1alpha1.TidbCluster) error {
+	svcNames := map[string]struct{}{}
+	for i := range tc.Spec.TiDB.Services {
+		newSvc := getNewTiDBNamedService(tc, &tc.Spec.TiDB.Services[i])
+		svcNames[newSvc.Name] = struct{}{}
+		if err := m.syncTiDBClientService(tc, newSvc); err != nil {
+			return err
+		}
+	}
+
+	selector, err := label.New().Instance(tc.GetInstanceName()).TiDB().Selector()
+	if err != nil {
+		return err
+	}
+	req, err := labels.NewRequirement(label.TiDBServiceLabelKey, selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	svcs, err := m.deps.ServiceLister.Services(tc.Namespace).List(selector.Add(*req))
+	if err != nil {
+		return fmt.Errorf("syncTiDBNamedServices: failed to list svc for cluster %s/%s, error: %s", tc.Namespace, tc.Name, err)
+	}
+	for _, svc := range svcs {
+		if _, ok := svcNames[svc.Name]; ok || !metav1.IsControlledBy(svc, tc) {
+			continue
+		}
+		klog.Infof("Delete TiDB service %s/%s removed from spec.tidb.services", svc.Namespace, svc.Name)
+		if err := m.deps.ServiceControl.DeleteService(tc, svc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncTiDBClientService creates or updates a client service of TiDB
+func (m *tidbMemberManager) syncTiDBClientService(tc *v1alpha1.TidbCluster, newSvc *corev1.Service) error {
 	ns := newSvc.Namespace
 
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(newSvc.Name)
@@ -485,7 +546,7 @@ func (m *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
 		return m.deps.ServiceControl.CreateService(tc, newSvc)
 	}
 	if err != nil {
-		return fmt.Errorf("syncTiDBService: failed to get svc %s for cluster %s/%s, error: %s", newSvc.Name, ns, tc.GetName(), err)
+		return fmt.Errorf("syncTiDBClientService: failed to get svc %s for cluster %s/%s, error: %s", newSvc.Name, ns, tc.GetName(), err)
 	}
 	oldSvc := oldSvcTmp.DeepCopy()
 	if newSvc.Annotations == nil {
@@ -542,7 +603,7 @@ func (m *tidbMemberManager) syncTiDBConfigMap(tc *v1alpha1.TidbCluster, set *app
 	if tc.Spec.TiDB.Config == nil {
 		return nil, nil
 	}
-	newCm, err := getTiDBConfigMap(tc)
+	newCm, err := getTiDBConfigMap(m.deps.ConfigDefaults.Apply(tc, v1alpha1.TiDBMemberType))
 	if err != nil {
 		return nil, err
 	}
@@ -600,6 +661,13 @@ func getTiDBConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	if tc.Spec.TiDB.IsBootstrapSQLEnabled() {
 		config.Set("initialize-sql-file", path.Join(bootstrapSQLFilePath, bootstrapSQLFileName))
 	}
+	if tc.Spec.TiDB.LogVolume != nil {
+		_, _, logFile, err := getLogVolume(v1alpha1.TiDBMemberType, tc.Spec.TiDB.LogVolume, tc.Spec.TiDB.StorageVolumes, tc.Spec.TiDB.StorageClassName, tc.Spec.TiDB.AdditionalVolumeMounts)
+		if err != nil {
+			return nil, fmt.Errorf("get log volume for tc %s/%s failed: %v", tc.Namespace, tc.Name, err)
+		}
+		setLogFileConfig(config.GenericConfig, logFile, tc.Spec.TiDB.LogVolume.Rotation)
+	}
 
 	// `DefaultTiDBServerPort`/`DefaultTiDBStatusPort` may be changed when building the binary
 	if v1alpha1.DefaultTiDBServerPort != int32(4000) {
@@ -623,6 +691,10 @@ func getTiDBConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 		"config-file":    string(confText),
 		"startup-script": startScript,
 	}
+	if tc.Spec.TiDB.LogShipping != nil {
+		data[logShipperConfigKey] = renderTiDBLogShipperConfig(tc)
+		data[logShipperParsersKey] = logShipperParsers
+	}
 	name := controller.TiDBMemberName(tc.Name)
 	instanceName := tc.GetInstanceName()
 	tidbLabels := label.New().Instance(instanceName).TiDB().Labels()
@@ -710,6 +782,47 @@ func getNewTiDBServiceOrNil(tc *v1alpha1.TidbCluster) *corev1.Service {
 	return tidbSvc
 }
 
+// getNewTiDBNamedService returns the named client service of TiDB in spec.tidb.services
+func getNewTiDBNamedService(tc *v1alpha1.TidbCluster, svcSpec *v1alpha1.TiDBService) *corev1.Service {
+	tidbSelector := label.New().Instance(tc.GetInstanceName()).TiDB()
+	tidbLabels := util.CombineStringMap(tidbSelector.Copy().UsedByEndUser().TiDBService(svcSpec.Name).Labels(), svcSpec.Labels)
+
+	spec := svcSpec.Spec.DeepCopy()
+	spec.Selector = util.CombineStringMap(tidbSelector.Labels(), spec.Selector)
+	if len(spec.Ports) == 0 {
+		spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "mysql-client",
+				Port:       tc.Spec.TiDB.GetServicePort(),
+				TargetPort: intstr.FromInt(int(v1alpha1.DefaultTiDBServerPort)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+			{
+				Name:       "status",
+				Port:       v1alpha1.DefaultTiDBStatusPort,
+				TargetPort: intstr.FromInt(int(v1alpha1.DefaultTiDBStatusPort)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		}
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiDBServiceName(tc.Name, svcSpec.Name),
+			Namespace:       tc.Namespace,
+			Labels:          tidbLabels,
+			Annotations:     util.CopyStringMap(svcSpec.Annotations),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: *spec,
+	}
+	if tc.Spec.PreferIPv6 {
+		SetServiceWhenPreferIPv6(svc)
+	}
+
+	return svc
+}
+
 func getNewTiDBHeadlessServiceForTidbCluster(tc *v1alpha1.TidbCluster) *corev1.Service {
 	ns := tc.Namespace
 	tcName := tc.Name
@@ -894,6 +1007,7 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 
 	var containers []corev1.Container
 	slowLogFileEnvVal := ""
+	var slowLogVolMount *corev1.VolumeMount
 	if tc.Spec.TiDB.ShouldSeparateSlowLog() {
 		// mount a shared volume and tail the slow log to STDOUT using a sidecar.
 		var slowQueryLogVolumeMount corev1.VolumeMount
@@ -932,13 +1046,17 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			}
 			slowLogFileEnvVal = path.Join(slowQueryLogVolumeMount.MountPath, slowQueryLogVolumeName)
 		}
+		slowLogVolMount = &slowQueryLogVolumeMount
+	}
+	// the slow log is shipped by the log shipper instead if log shipping is enabled
+	if slowLogVolMount != nil && tc.Spec.TiDB.LogShipping == nil {
 		logTailer := tc.Spec.TiDB.GetSlowLogTailerSpec()
 		c := corev1.Container{
 			Name:            v1alpha1.ContainerSlowLogTailer.String(),
 			Image:           tc.HelperImage(),
 			ImagePullPolicy: tc.HelperImagePullPolicy(),
 			Resources:       controller.ContainerResource(logTailer.ResourceRequirements),
-			VolumeMounts:    []corev1.VolumeMount{slowQueryLogVolumeMount},
+			VolumeMounts:    []corev1.VolumeMount{*slowLogVolMount},
 			Command: []string{
 				"sh",
 				"-c",
@@ -959,6 +1077,29 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 		}
 	}
 
+	logVolSpec, err := buildLogVolumePodSpec(tc, v1alpha1.TiDBMemberType, tc.Spec.TiDB.LogVolume, tc.Spec.TiDB.StorageVolumes, tc.Spec.TiDB.StorageClassName, tc.Spec.TiDB.AdditionalVolumeMounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log volume for cluster %s/%s: %v", ns, tcName, err)
+	}
+	vols = append(vols, logVolSpec.volumes...)
+	volMounts = append(volMounts, logVolSpec.volumeMounts...)
+	initContainers = append(initContainers, logVolSpec.initContainers...)
+	containers = append(containers, logVolSpec.containers...)
+
+	if tc.Spec.TiDB.LogShipping != nil {
+		// ship the slow log and the audit log to the outputs using a sidecar.
+		c, shipperVols, err := buildTiDBLogShipperContainer(tc, tidbConfigMap, volMounts, slowLogVolMount, slowLogFileEnvVal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build log shipper for cluster %s/%s: %v", ns, tcName, err)
+		}
+		vols = append(vols, shipperVols...)
+		if c.RestartPolicy != nil {
+			initContainers = append(initContainers, c)
+		} else {
+			containers = append(containers, c)
+		}
+	}
+
 	envs := []corev1.EnvVar{
 		{
 			Name:  "CLUSTER_NAME",
@@ -1087,7 +1228,6 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 
 	podSpec := baseTiDBSpec.BuildPodSpec()
 
-	var err error
 	podSpec.Containers, err = MergePatchContainers(containers, baseTiDBSpec.AdditionalContainers())
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge containers spec for TiDB of [%s/%s], error: %v", ns, tcName, err)
@@ -1100,6 +1240,9 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	if podSpec.ServiceAccountName == "" {
 		podSpec.ServiceAccountName = tc.Spec.ServiceAccount
 	}
+	if conditionType := tc.Spec.TiDB.GetLoadBalancerReadinessConditionType(); conditionType != "" {
+		podSpec.ReadinessGates = append(podSpec.ReadinessGates, corev1.PodReadinessGate{ConditionType: conditionType})
+	}
 
 	stsLabels := label.New().Instance(instanceName).TiDB()
 	podLabels := util.CombineStringMap(stsLabels, baseTiDBSpec.Labels())
@@ -1314,7 +1457,8 @@ func parserOrdinal(podName string) (int32, error) {
 }
 
 func tidbStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.StatefulSet, tc *v1alpha1.TidbCluster) (bool, error) {
-	if mngerutils.StatefulSetIsUpgrading(set) {
+	partition := tc.Spec.TiDB.RollingUpdateStrategy.GetPartition()
+	if statefulSetIsUpgrading(set, partition) {
 		return true, nil
 	}
 	selector, err := label.New().
@@ -1329,6 +1473,9 @@ func tidbStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.State
 		return false, fmt.Errorf("tidbStatefulSetIsUpgrading: failed to get pods for cluster %s/%s, selector %s, error: %s", tc.GetNamespace(), tc.GetInstanceName(), selector, err)
 	}
 	for _, pod := range tidbPods {
+		if heldByPartition(pod, partition) {
+			continue
+		}
 		revisionHash, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
 		if !exist {
 			return false, nil
@@ -1413,3 +1560,7 @@ func (m *FakeTiDBMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	}
 	return nil
 }
+
+func (m *FakeTiDBMemberManager) SyncStatus(*v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -275,6 +275,7 @@ func TestTiDBMemberManagerTiDBStatefulSetIsUpgrading(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name            string
+		updateTC        func(*v1alpha1.TidbCluster)
 		setUpdate       func(*apps.StatefulSet)
 		hasPod          bool
 		updatePod       func(*corev1.Pod)
@@ -287,6 +288,9 @@ func TestTiDBMemberManagerTiDBStatefulSetIsUpgrading(t *testing.T) {
 		tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{
 			UpdateRevision: "v3",
 		}
+		if test.updateTC != nil {
+			test.updateTC(tc)
+		}
 
 		set := &apps.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
@@ -363,6 +367,39 @@ func TestTiDBMemberManagerTiDBStatefulSetIsUpgrading(t *testing.T) {
 			errExpectFn:     nil,
 			expectUpgrading: false,
 		},
+		{
+			name: "pods below the partition are held at the old revision",
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.RollingUpdateStrategy = &v1alpha1.RollingUpdateStrategy{Partition: pointer.Int32Ptr(1)}
+			},
+			setUpdate: func(set *apps.StatefulSet) {
+				set.Status.CurrentRevision = "v2"
+				set.Status.UpdateRevision = "v3"
+			},
+			hasPod: true,
+			updatePod: func(pod *corev1.Pod) {
+				pod.Labels[apps.ControllerRevisionHashLabelKey] = "v2"
+			},
+			errExpectFn:     nil,
+			expectUpgrading: false,
+		},
+		{
+			name: "pods at or above the partition are upgrading",
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.RollingUpdateStrategy = &v1alpha1.RollingUpdateStrategy{Partition: pointer.Int32Ptr(1)}
+			},
+			setUpdate: func(set *apps.StatefulSet) {
+				set.Status.CurrentRevision = "v2"
+				set.Status.UpdateRevision = "v3"
+			},
+			hasPod: true,
+			updatePod: func(pod *corev1.Pod) {
+				pod.Name = ordinalPodName(v1alpha1.TiDBMemberType, "test", 1)
+				pod.Labels[apps.ControllerRevisionHashLabelKey] = "v2"
+			},
+			errExpectFn:     nil,
+			expectUpgrading: true,
+		},
 	}
 
 	for i := range tests {
@@ -832,6 +869,59 @@ func TestTiDBMemberManagerSyncTidbService(t *testing.T) {
 	}
 }
 
+func TestTiDBMemberManagerSyncTiDBNamedServices(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiDB()
+	tmm, _, _, indexers := newFakeTiDBMemberManager()
+	tc.Spec.TiDB.Services = []v1alpha1.TiDBService{
+		{
+			Name:        "internal",
+			Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+			Spec:        corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		},
+		{
+			Name:   "group-a",
+			Labels: map[string]string{"group": "a"},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: corev1.ClusterIPNone,
+				Selector:  map[string]string{"node-group": "a"},
+				Ports:     []corev1.ServicePort{{Name: "mysql", Port: 3306, TargetPort: intstr.FromInt(4000)}},
+			},
+		},
+	}
+	// the named service removed from the spec
+	_ = indexers.svc.Add(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiDBServiceName(tc.Name, "external"),
+			Namespace:       tc.Namespace,
+			Labels:          label.New().Instance(tc.GetInstanceName()).TiDB().UsedByEndUser().TiDBService("external").Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+	})
+
+	g.Expect(tmm.syncTiDBService(tc)).To(Succeed())
+
+	svc, err := tmm.deps.ServiceLister.Services(tc.Namespace).Get(controller.TiDBServiceName(tc.Name, "internal"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+	g.Expect(svc.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+	g.Expect(svc.Labels).To(HaveKeyWithValue(label.TiDBServiceLabelKey, "internal"))
+	g.Expect(svc.Spec.Selector).To(Equal(label.New().Instance(tc.GetInstanceName()).TiDB().Labels()))
+	g.Expect(svc.Spec.Ports).To(HaveLen(2))
+
+	svc, err = tmm.deps.ServiceLister.Services(tc.Namespace).Get(controller.TiDBServiceName(tc.Name, "group-a"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+	g.Expect(svc.Labels).To(HaveKeyWithValue("group", "a"))
+	g.Expect(svc.Spec.Selector).To(HaveKeyWithValue("node-group", "a"))
+	g.Expect(svc.Spec.Selector).To(HaveKeyWithValue(label.ComponentLabelKey, label.TiDBLabelVal))
+	g.Expect(svc.Spec.Ports).To(Equal([]corev1.ServicePort{{Name: "mysql", Port: 3306, TargetPort: intstr.FromInt(4000)}}))
+
+	_, err = tmm.deps.ServiceLister.Services(tc.Namespace).Get(controller.TiDBServiceName(tc.Name, "external"))
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
 type fakeIndexers struct {
 	pod    cache.Indexer
 	tc     cache.Indexer
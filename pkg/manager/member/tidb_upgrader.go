@@ -101,10 +101,26 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 		}
 	}
 
-	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	oldPartition := *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	partition := tc.Spec.TiDB.RollingUpdateStrategy.GetPartition()
+	maxUnavailable := tc.Spec.TiDB.RollingUpdateStrategy.GetMaxUnavailable(*oldSet.Spec.Replicas)
+
+	mngerutils.SetUpgradePartition(newSet, oldPartition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	canaryPartition, hold, err := syncTiDBCanary(u.deps, tc, podOrdinals, newSet, minReadySeconds)
+	if err != nil || hold {
+		return err
+	}
+	if canaryPartition > partition {
+		partition = canaryPartition
+	}
+	unavailable, started := 0, false
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
+		if i < partition {
+			klog.Infof("tidbcluster: [%s/%s]'s tidb pods with ordinal less than partition %d are kept at the old revision", ns, tcName, partition)
+			break
+		}
 		podName := tidbPodName(tcName, i)
 		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
 		if err != nil {
@@ -116,22 +132,61 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 		}
 
 		if revision == tc.Status.TiDB.StatefulSet.UpdateRevision {
-			if !k8s.IsPodAvailable(pod, int32(minReadySeconds), metav1.Now()) {
-				readyCond := k8s.GetPodReadyCondition(pod.Status)
-				if readyCond == nil || readyCond.Status != corev1.ConditionTrue {
-					return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tidb pod: [%s] is not ready", ns, tcName, podName)
-
+			if err := checkTiDBPodUpgraded(tc, pod, minReadySeconds); err != nil {
+				if maxUnavailable <= 1 {
+					return err
 				}
-				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tidb pod: [%s] is not available, last transition time is %v", ns, tcName, podName, readyCond.LastTransitionTime)
+				unavailable++
 			}
-			if member, exist := tc.Status.TiDB.Members[podName]; !exist || !member.Health {
-				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb upgraded pod: [%s] is not ready", ns, tcName, podName)
+			continue
+		}
+
+		if maxUnavailable <= 1 {
+			return u.upgradeTiDBPod(tc, i, newSet)
+		}
+		if i >= oldPartition {
+			// the statefulset controller upgrades the pods above the partition one by one,
+			// delete the pod to upgrade it in parallel with the others
+			unavailable++
+			if pod.DeletionTimestamp == nil {
+				if err := u.deps.PodControl.DeletePod(tc, pod); err != nil {
+					return err
+				}
 			}
 			continue
 		}
-		return u.upgradeTiDBPod(tc, i, newSet)
+		if unavailable >= maxUnavailable {
+			break
+		}
+		if err := u.upgradeTiDBPod(tc, i, newSet); err != nil {
+			return err
+		}
+		unavailable++
+		started = true
+	}
+
+	if unavailable > 0 && !started {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb is upgrading %d pods at the same time", ns, tcName, unavailable)
 	}
+	return nil
+}
 
+// checkTiDBPodUpgraded returns an error if the upgraded tidb pod is not available yet
+func checkTiDBPodUpgraded(tc *v1alpha1.TidbCluster, pod *corev1.Pod, minReadySeconds int) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := pod.GetName()
+	if !k8s.IsPodAvailable(pod, int32(minReadySeconds), metav1.Now()) {
+		readyCond := k8s.GetPodReadyCondition(pod.Status)
+		if readyCond == nil || readyCond.Status != corev1.ConditionTrue {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tidb pod: [%s] is not ready", ns, tcName, podName)
+
+		}
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tidb pod: [%s] is not available, last transition time is %v", ns, tcName, podName, readyCond.LastTransitionTime)
+	}
+	if member, exist := tc.Status.TiDB.Members[podName]; !exist || !member.Health {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb upgraded pod: [%s] is not ready", ns, tcName, podName)
+	}
 	return nil
 }
 
@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	podinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/utils/pointer"
 )
@@ -248,6 +249,53 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
 			},
 		},
+		{
+			name: "keep pods below the partition at the old revision",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				tc.Spec.TiDB.RollingUpdateStrategy = &v1alpha1.RollingUpdateStrategy{Partition: pointer.Int32Ptr(1)}
+			},
+			getLastAppliedConfigErr: false,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "upgrade pods in parallel with maxUnavailable",
+			changePods: func(pods []*corev1.Pod) {
+				pods[1].Status = *new(corev1.PodStatus)
+			},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				maxUnavailable := intstr.FromString("100%")
+				tc.Spec.TiDB.RollingUpdateStrategy = &v1alpha1.RollingUpdateStrategy{MaxUnavailable: &maxUnavailable}
+			},
+			getLastAppliedConfigErr: false,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
+		{
+			name: "wait for the upgrading pods with maxUnavailable",
+			changePods: func(pods []*corev1.Pod) {
+				pods[1].Status = *new(corev1.PodStatus)
+			},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				maxUnavailable := intstr.FromInt(1)
+				tc.Spec.TiDB.RollingUpdateStrategy = &v1alpha1.RollingUpdateStrategy{MaxUnavailable: &maxUnavailable}
+			},
+			getLastAppliedConfigErr: false,
+			errorExpect:             true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -97,12 +97,16 @@ func (m *TidbClusterStatusManager) syncTiDBInfoKey(tc *v1alpha1.TidbCluster) err
 	var pdEtcdClient pdapi.PDEtcdClient
 	var err error
 
-	if tc.Heterogeneous() && tc.WithoutLocalPD() {
+	if tc.HasExternalPD() {
+		pdEtcdClient, err = m.deps.PDControl.GetPDEtcdClient(pdapi.Namespace(tc.Namespace), tc.Name, tc.IsExternalPDTLSEnabled(),
+			controller.ExternalPDClientOptions(tc, tc.Spec.ExternalPD.Endpoints[0])...)
+	} else if tc.Heterogeneous() && tc.WithoutLocalPD() {
 		// connect to pd of other cluster and use own cert
 		pdEtcdClient, err = m.deps.PDControl.GetPDEtcdClient(pdapi.Namespace(tc.Spec.Cluster.Namespace), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled(),
 			pdapi.TLSCertFromTC(pdapi.Namespace(tc.Namespace), tc.Name),
 			pdapi.ClusterRef(tc.Spec.Cluster.ClusterDomain),
 			pdapi.UseHeadlessService(tc.Spec.AcrossK8s),
+			pdapi.ResolveWith(tc.Spec.AcrossK8sResolver),
 		)
 	} else {
 		pdEtcdClient, err = m.deps.PDControl.GetPDEtcdClient(pdapi.Namespace(tc.Namespace), tc.Name,
@@ -16,6 +16,7 @@ package member
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strings"
@@ -85,7 +86,7 @@ func (m *tiflashMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	component := v1alpha1.TiFlashMemberType
 	needSuspend, err := m.suspender.SuspendComponent(tc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, tc.GetNamespace(), tc.GetName())
@@ -110,6 +111,14 @@ func (m *tiflashMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	return m.syncStatefulSet(tc)
 }
 
+// SyncStatus refreshes the status of TiFlash from its statefulset without acting upon the spec
+func (m *tiflashMemberManager) SyncStatus(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiFlash == nil {
+		return nil
+	}
+	return syncStatusFromStatefulSet(m.deps, tc, controller.TiFlashMemberName(tc.GetName()), m.syncTidbClusterStatus)
+}
+
 func (m *tiflashMemberManager) syncRecoveryForTiFlash(tc *v1alpha1.TidbCluster) error {
 	// Check whether the cluster is in recovery mode
 	// and whether the volumes have been restored for TiKV
@@ -246,6 +255,7 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	if err != nil {
 		return err
 	}
+	degradeNativeSidecars(m.deps.Capabilities, newSet)
 	if setNotExist {
 		if !tc.PDIsAvailable() {
 			klog.Infof("TidbCluster: %s/%s, waiting for PD cluster running", ns, tcName)
@@ -294,7 +304,7 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 }
 
 func (m *tiflashMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
-	newCm, err := getTiFlashConfigMap(tc)
+	newCm, err := getTiFlashConfigMap(m.deps.ConfigDefaults.Apply(tc, v1alpha1.TiFlashMemberType))
 	if err != nil {
 		return nil, err
 	}
@@ -610,6 +620,19 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 		VolumeMounts: volMounts,
 		Resources:    controller.ContainerResource(tc.Spec.TiFlash.ResourceRequirements),
 	}
+
+	if tc.Spec.TiFlash.ReadinessProbe != nil {
+		tiflashContainer.ReadinessProbe = &corev1.Probe{
+			ProbeHandler:        buildTiFlashReadinessProbHandler(tc),
+			InitialDelaySeconds: int32(10),
+		}
+		if tc.Spec.TiFlash.ReadinessProbe.InitialDelaySeconds != nil {
+			tiflashContainer.ReadinessProbe.InitialDelaySeconds = *tc.Spec.TiFlash.ReadinessProbe.InitialDelaySeconds
+		}
+		if tc.Spec.TiFlash.ReadinessProbe.PeriodSeconds != nil {
+			tiflashContainer.ReadinessProbe.PeriodSeconds = *tc.Spec.TiFlash.ReadinessProbe.PeriodSeconds
+		}
+	}
 	podSpec := baseTiFlashSpec.BuildPodSpec()
 	if baseTiFlashSpec.HostNetwork() {
 		env = append(env, corev1.EnvVar{
@@ -709,6 +732,10 @@ func flashVolumeClaimTemplate(storageClaims []v1alpha1.StorageClaim) ([]corev1.P
 
 func getTiFlashConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	config := GetTiFlashConfig(tc)
+	if cpu := cpuLimitCores(tc.BaseTiFlashSpec(), tc.Spec.TiFlash.ResourceRequirements); cpu > 0 {
+		// TiFlash uses the cpu number of the node as the max threads of a query by default
+		config.Common.SetIfNil("profiles.default.max_threads", int64(math.Ceil(cpu)))
+	}
 
 	configText, err := config.Common.MarshalTOML()
 	if err != nil {
@@ -974,6 +1001,23 @@ func tiflashStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pd
 	return false, nil
 }
 
+// buildTiFlashReadinessProbHandler probes the proxy port of tiflash, which is the port the stores
+// of tiflash serve the raft and the gRPC health checking requests on
+func buildTiFlashReadinessProbHandler(tc *v1alpha1.TidbCluster) corev1.ProbeHandler {
+	if tp := tc.Spec.TiFlash.ReadinessProbe.Type; tp != nil && *tp == v1alpha1.GRPCProbeType {
+		return corev1.ProbeHandler{
+			GRPC: &corev1.GRPCAction{
+				Port: v1alpha1.DefaultTiFlashProxyPort,
+			},
+		}
+	}
+	return corev1.ProbeHandler{
+		TCPSocket: &corev1.TCPSocketAction{
+			Port: intstr.FromInt(int(v1alpha1.DefaultTiFlashProxyPort)),
+		},
+	}
+}
+
 type FakeTiFlashMemberManager struct {
 	err error
 }
@@ -996,3 +1040,7 @@ func (m *FakeTiFlashMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	}
 	return nil
 }
+
+func (m *FakeTiFlashMemberManager) SyncStatus(*v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -1676,6 +1676,47 @@ func TestGetNewTiFlashSetForTidbCluster(t *testing.T) {
 				}), "Expected the CAPACITY of tiflash is properly set")
 			},
 		},
+		{
+			name: "tiflash grpc readiness",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					TiFlash: &v1alpha1.TiFlashSpec{
+						ComponentSpec: v1alpha1.ComponentSpec{
+							ReadinessProbe: &v1alpha1.Probe{
+								Type: pointer.StringPtr(v1alpha1.GRPCProbeType),
+							},
+						},
+						StorageClaims: []v1alpha1.StorageClaim{
+							{
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceStorage: resource.MustParse("100Gi"),
+									},
+								},
+							},
+						},
+					},
+					TiDB: &v1alpha1.TiDBSpec{},
+					PD:   &v1alpha1.PDSpec{},
+					TiKV: &v1alpha1.TiKVSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				nameToContainer := MapContainers(&sts.Spec.Template.Spec)
+				tiflashContainer := nameToContainer[v1alpha1.TiFlashMemberType.String()]
+				g.Expect(tiflashContainer.ReadinessProbe).To(Equal(&corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						GRPC: &corev1.GRPCAction{Port: v1alpha1.DefaultTiFlashProxyPort},
+					},
+					InitialDelaySeconds: int32(10),
+				}))
+			},
+		},
 		{
 			name: "configure initializer",
 			tc: v1alpha1.TidbCluster{
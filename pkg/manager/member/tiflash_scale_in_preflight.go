@@ -0,0 +1,171 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// tiflashRuleGroup is the group of the placement rules of the TiFlash replicas set by TiDB
+	tiflashRuleGroup = "tiflash"
+	// tiflashPreflightTimeout is the timeout to query the TiFlash replicas of the tables
+	tiflashPreflightTimeout = 10 * time.Second
+)
+
+// tiflashTableReplica is a row of information_schema.tiflash_replica
+type tiflashTableReplica struct {
+	Database  string
+	Table     string
+	Count     int
+	Available bool
+}
+
+// tiflashPreflightClient queries the TiFlash replicas of the tables from TiDB
+type tiflashPreflightClient interface {
+	// ListReplicas returns the tables with TiFlash replicas
+	ListReplicas(ctx context.Context) ([]tiflashTableReplica, error)
+	Close() error
+}
+
+// tiflashScaleInPreflight returns how many TiFlash stores can be removed at the same time, including
+// the stores being removed. The remaining Up stores must be able to hold the replicas of all the
+// tables, and no new store is removed while the replicas of any table are unavailable.
+func (s *tiflashScaler) tiflashScaleInPreflight(tc *v1alpha1.TidbCluster) (int, error) {
+	var upStores, offlineStores int
+	for _, store := range tc.Status.TiFlash.Stores {
+		switch store.State {
+		case v1alpha1.TiKVStateUp:
+			upStores++
+		case v1alpha1.TiKVStateOffline:
+			offlineStores++
+		}
+	}
+
+	rules, err := controller.GetPDClient(s.deps.PDControl, tc).GetPlacementRulesByGroup(tiflashRuleGroup)
+	if err != nil {
+		return 0, fmt.Errorf("tiflash scale in preflight: failed to get the placement rules of tiflash for cluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+	required := 0
+	for _, rule := range rules {
+		if rule.Count > required {
+			required = rule.Count
+		}
+	}
+
+	client, err := s.newPreflightClient(tc)
+	if err != nil {
+		return 0, fmt.Errorf("tiflash scale in preflight: failed to connect to tidb for cluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), tiflashPreflightTimeout)
+	defer cancel()
+	replicas, err := client.ListReplicas(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("tiflash scale in preflight: failed to get the tiflash replicas for cluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+	var unavailable []string
+	for _, r := range replicas {
+		if r.Count > required {
+			required = r.Count
+		}
+		if !r.Available {
+			unavailable = append(unavailable, fmt.Sprintf("%s.%s", r.Database, r.Table))
+		}
+	}
+
+	if len(unavailable) > 0 {
+		klog.Infof("tiflash scale in preflight: the tiflash replicas of tables %v are unavailable for cluster %s/%s, wait for the %d stores being removed",
+			unavailable, tc.Namespace, tc.Name, offlineStores)
+		return offlineStores, nil
+	}
+	removable := upStores - required
+	if removable < 0 {
+		removable = 0
+	}
+	klog.Infof("tiflash scale in preflight: %d up stores and %d stores being removed, %d replicas are required for cluster %s/%s",
+		upStores, offlineStores, required, tc.Namespace, tc.Name)
+	return offlineStores + removable, nil
+}
+
+// newPreflightSQLClient connects to the tidb service of the tidb cluster with the user in spec.tiflash.scaleInPreflight
+func (s *tiflashScaler) newPreflightSQLClient(tc *v1alpha1.TidbCluster) (tiflashPreflightClient, error) {
+	spec := tc.Spec.TiFlash.ScaleInPreflight
+	cfg := mysql.NewConfig()
+	cfg.User = spec.User
+	if cfg.User == "" {
+		cfg.User = rootUser
+	}
+	if spec.SecretName != "" {
+		secret, err := s.deps.SecretLister.Secrets(tc.Namespace).Get(spec.SecretName)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Passwd = string(secret.Data[constants.TidbPasswordKey])
+	}
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s.%s.svc:%d", controller.TiDBMemberName(tc.Name), tc.Namespace, tc.Spec.TiDB.GetServicePort())
+	cfg.Timeout = tiflashPreflightTimeout
+	if tc.Spec.TiDB.IsTLSClientEnabled() && !tc.SkipTLSWhenConnectTiDB() {
+		tlsConfig, err := pdapi.GetTLSConfig(s.deps.SecretLister, pdapi.Namespace(tc.Namespace), util.TiDBClientTLSSecretName(tc.Name, spec.TLSClientSecretName))
+		if err != nil {
+			return nil, err
+		}
+		cfg.TLS = tlsConfig
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTiFlashPreflightClient{db: sql.OpenDB(connector)}, nil
+}
+
+type sqlTiFlashPreflightClient struct {
+	db *sql.DB
+}
+
+func (c *sqlTiFlashPreflightClient) ListReplicas(ctx context.Context) ([]tiflashTableReplica, error) {
+	rows, err := c.db.QueryContext(ctx, "SELECT TABLE_SCHEMA, TABLE_NAME, REPLICA_COUNT, AVAILABLE FROM information_schema.tiflash_replica")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var replicas []tiflashTableReplica
+	for rows.Next() {
+		var r tiflashTableReplica
+		if err := rows.Scan(&r.Database, &r.Table, &r.Count, &r.Available); err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, r)
+	}
+	return replicas, rows.Err()
+}
+
+func (c *sqlTiFlashPreflightClient) Close() error {
+	return c.db.Close()
+}
+
+var _ tiflashPreflightClient = &sqlTiFlashPreflightClient{}
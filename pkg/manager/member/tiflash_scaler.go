@@ -31,11 +31,15 @@ import (
 
 type tiflashScaler struct {
 	generalScaler
+	// newPreflightClient can be replaced in unit tests
+	newPreflightClient func(tc *v1alpha1.TidbCluster) (tiflashPreflightClient, error)
 }
 
 // NewTiFlashScaler returns a tiflash Scaler
 func NewTiFlashScaler(deps *controller.Dependencies) Scaler {
-	return &tiflashScaler{generalScaler: generalScaler{deps: deps}}
+	s := &tiflashScaler{generalScaler: generalScaler{deps: deps}}
+	s.newPreflightClient = s.newPreflightSQLClient
+	return s
 }
 
 func (s *tiflashScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
@@ -98,7 +102,24 @@ func (s *tiflashScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, ne
 		return nil
 	}
 
+	if err := acquireDisruptionLock(s.deps, tc, DisruptionTiFlashScaleIn); err != nil {
+		return err
+	}
+
 	scaleInParallelism := tc.Spec.TiFlash.GetScaleInParallelism()
+	if tc.Spec.TiFlash.ScaleInPreflight != nil && len(tc.Status.TiFlash.Stores) > 0 {
+		removable, err := s.tiflashScaleInPreflight(tc)
+		if err != nil {
+			return err
+		}
+		if removable == 0 {
+			return controller.RequeueErrorf("TiFlash of cluster %s/%s can't be scaled in, the replicas of the tables are unavailable or can't be held by the remaining stores", tc.Namespace, tc.Name)
+		}
+		// the parallelism is derived from the replicas if it's not set explicitly
+		if tc.Spec.TiFlash.ScalePolicy.ScaleInParallelism == nil || removable < scaleInParallelism {
+			scaleInParallelism = removable
+		}
+	}
 	_, ordinals, replicas, deleteSlots := scaleMulti(oldSet, newSet, scaleInParallelism)
 	klog.Infof("scaling in tiflash statefulset %s/%s, ordinal: %v (replicas: %d, delete slots: %v), scaleInParallelism: %v", oldSet.Namespace, oldSet.Name, ordinals, replicas, deleteSlots.List(), scaleInParallelism)
 
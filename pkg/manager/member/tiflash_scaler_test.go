@@ -14,6 +14,7 @@
 package member
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -1761,6 +1762,114 @@ func TestTiFlashScalerScaleInSimultaneouslyExtra(t *testing.T) {
 	}
 }
 
+type fakeTiFlashPreflightClient struct {
+	replicas []tiflashTableReplica
+}
+
+func (c *fakeTiFlashPreflightClient) ListReplicas(_ context.Context) ([]tiflashTableReplica, error) {
+	return c.replicas, nil
+}
+
+func (c *fakeTiFlashPreflightClient) Close() error {
+	return nil
+}
+
+func TestTiFlashScaleInPreflight(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name      string
+		storeFun  func(tc *v1alpha1.TidbCluster)
+		rules     []*pdapi.PlacementRule
+		replicas  []tiflashTableReplica
+		removable int
+	}{
+		{
+			name:      "no replicas",
+			storeFun:  normalTiFlashStoreFun,
+			removable: 5,
+		},
+		{
+			name:     "replicas required by placement rules",
+			storeFun: normalTiFlashStoreFun,
+			rules: []*pdapi.PlacementRule{
+				{GroupID: tiflashRuleGroup, ID: "table-45-r", Count: 2},
+				{GroupID: tiflashRuleGroup, ID: "table-46-r", Count: 1},
+			},
+			replicas:  []tiflashTableReplica{{Database: "db", Table: "t1", Count: 2, Available: true}},
+			removable: 3,
+		},
+		{
+			name:      "replicas required by tables",
+			storeFun:  normalTiFlashStoreFun,
+			replicas:  []tiflashTableReplica{{Database: "db", Table: "t1", Count: 4, Available: true}},
+			removable: 1,
+		},
+		{
+			name:      "remaining stores can't hold replicas",
+			storeFun:  normalTiFlashStoreFun,
+			replicas:  []tiflashTableReplica{{Database: "db", Table: "t1", Count: 5, Available: true}},
+			removable: 0,
+		},
+		{
+			name: "wait for stores being removed if replicas are unavailable",
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				normalTiFlashStoreFun(tc)
+				store := tc.Status.TiFlash.Stores["1"]
+				store.State = v1alpha1.TiKVStateOffline
+				tc.Status.TiFlash.Stores["1"] = store
+			},
+			replicas: []tiflashTableReplica{
+				{Database: "db", Table: "t1", Count: 1, Available: true},
+				{Database: "db", Table: "t2", Count: 1, Available: false},
+			},
+			removable: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTidbClusterForPD()
+			tt.storeFun(tc)
+			tc.Spec.TiFlash.ScaleInPreflight = &v1alpha1.TiFlashScaleInPreflight{}
+			scaler, pdControl, _, _, _ := newFakeTiFlashScaler()
+			scaler.newPreflightClient = func(_ *v1alpha1.TidbCluster) (tiflashPreflightClient, error) {
+				return &fakeTiFlashPreflightClient{replicas: tt.replicas}, nil
+			}
+			pdClient := controller.NewFakePDClient(pdControl, tc)
+			pdClient.AddReaction(pdapi.GetPlacementRulesByGroupActionType, func(action *pdapi.Action) (interface{}, error) {
+				g.Expect(action.Rule.GroupID).To(Equal(tiflashRuleGroup))
+				return tt.rules, nil
+			})
+
+			removable, err := scaler.tiflashScaleInPreflight(tc)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(removable).To(Equal(tt.removable))
+		})
+	}
+}
+
+func TestTiFlashScalerScaleInWithPreflight(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	normalTiFlashStoreFun(tc)
+	tc.Spec.TiFlash.ScaleInPreflight = &v1alpha1.TiFlashScaleInPreflight{}
+	scaler, pdControl, _, _, _ := newFakeTiFlashScaler()
+	scaler.newPreflightClient = func(_ *v1alpha1.TidbCluster) (tiflashPreflightClient, error) {
+		return &fakeTiFlashPreflightClient{replicas: []tiflashTableReplica{{Database: "db", Table: "t1", Count: 5, Available: true}}}, nil
+	}
+	controller.NewFakePDClient(pdControl, tc)
+
+	oldSet := newStatefulSetForPDScale()
+	newSet := oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(3)
+
+	// all the stores are required by the replicas
+	err := scaler.ScaleIn(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+}
+
 func newFakeTiFlashScaler(resyncDuration ...time.Duration) (*tiflashScaler, *pdapi.FakePDControl, cache.Indexer, cache.Indexer, *controller.FakePVCControl) {
 	fakeDeps := controller.NewFakeDependencies()
 	if len(resyncDuration) > 0 {
@@ -1770,7 +1879,7 @@ func newFakeTiFlashScaler(resyncDuration ...time.Duration) (*tiflashScaler, *pda
 	podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
 	pdControl := fakeDeps.PDControl.(*pdapi.FakePDControl)
 	pvcControl := fakeDeps.PVCControl.(*controller.FakePVCControl)
-	return &tiflashScaler{generalScaler{deps: fakeDeps}}, pdControl, pvcIndexer, podIndexer, pvcControl
+	return &tiflashScaler{generalScaler: generalScaler{deps: fakeDeps}}, pdControl, pvcIndexer, podIndexer, pvcControl
 }
 
 func normalTiFlashStoreFun(tc *v1alpha1.TidbCluster) {
@@ -229,6 +229,9 @@ func getTiFlashConfigV2(tc *v1alpha1.TidbCluster) *v1alpha1.TiFlashConfigWraper
 		if preferPDAddressesOverDiscovery && tc.Spec.StartScriptVersion == v1alpha1.StartScriptV2 {
 			pdAddr = strings.Join(tc.Spec.PDAddresses, ",")
 		}
+		if tc.HasExternalPD() {
+			pdAddr = strings.Join(tc.ExternalPDAddresses(), ",") // use the external pd
+		}
 		// tiflash require at least one configuration item in ["raft"] config group, otherwise
 		// tiflash with version less than v7.1.0 may encounter schema sync problems. So we keep this item
 		// even if this item is configured via command line args.
@@ -297,6 +300,10 @@ func getTiFlashConfig(tc *v1alpha1.TidbCluster) *v1alpha1.TiFlashConfigWraper {
 		}
 	}
 
+	if tc.HasExternalPD() {
+		config.Common.SetIfNil("raft.pd_addr", strings.Join(tc.ExternalPDAddresses(), ",")) // use the external pd
+	}
+
 	ref := tc.Spec.Cluster.DeepCopy()
 	noLocalPD := tc.WithoutLocalPD()
 	acrossK8s := tc.AcrossK8s()
@@ -0,0 +1,238 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultTiKVEncryptionMethod = "aes256-ctr"
+	defaultTiKVVaultKeyField    = "key"
+	defaultTiKVSecretKey        = "master-key"
+
+	tikvEncryptionMountPath    = "/var/lib/tikv-encryption"
+	tikvGCPCredentialsKey      = "credentials.json"
+	tikvVaultSecretsPath       = "/vault/secrets"
+	tikvMasterKeyName          = "master-key"
+	tikvPreviousMasterKeyName  = "previous-master-key"
+	vaultAnnAgentInject        = "vault.hashicorp.com/agent-inject"
+	vaultAnnRole               = "vault.hashicorp.com/role"
+	vaultAnnAgentInjectSecret  = "vault.hashicorp.com/agent-inject-secret-"
+	vaultAnnAgentInjectTmpl    = "vault.hashicorp.com/agent-inject-template-"
+	tikvEncryptionEventRotate  = "MasterKeyRotationStarted"
+	tikvEncryptionEventRotated = "MasterKeyRotationCompleted"
+)
+
+// syncTiKVEncryption syncs the status of the encryption at rest of TiKV by spec.tikv.encryption. When the master
+// key is changed, the old one is kept as the previous master key and the rotation lasts until all the TiKVs are
+// restarted with the new one, then the previous master key is removed from the config and the volumes of TiKV.
+// A new rotation waits for the running one.
+func (m *tikvMemberManager) syncTiKVEncryption(tc *v1alpha1.TidbCluster) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	spec := tc.Spec.TiKV.Encryption
+	if spec == nil {
+		tc.Status.TiKV.Encryption = nil
+		return
+	}
+	status := tc.Status.TiKV.Encryption
+	if status == nil {
+		tc.Status.TiKV.Encryption = &v1alpha1.TiKVEncryptionStatus{
+			Phase:     v1alpha1.TiKVEncryptionReady,
+			MasterKey: *spec.MasterKey.DeepCopy(),
+		}
+		return
+	}
+
+	sts := tc.Status.TiKV.StatefulSet
+	if status.Phase == v1alpha1.TiKVEncryptionRotating {
+		// the pods are rolling restarted by the annotation of the master key, so the rotation completes
+		// once the StatefulSet is updated to a new revision
+		if sts == nil || sts.UpdateRevision == status.RotationRevision || sts.CurrentRevision != sts.UpdateRevision ||
+			sts.UpdatedReplicas != sts.Replicas || tc.Status.TiKV.Phase != v1alpha1.NormalPhase {
+			return
+		}
+		now := metav1.Now()
+		status.Phase = v1alpha1.TiKVEncryptionReady
+		status.LastRotationCompletionTime = &now
+		// the data keys are encrypted by the new master key once TiKV restarts, so the old one is no longer
+		// needed and its Secret is unmounted by the next rolling update
+		status.PreviousMasterKey = nil
+		m.deps.Recorder.Event(tc, corev1.EventTypeNormal, tikvEncryptionEventRotated, "all the tikvs are restarted with the new master key")
+		klog.Infof("TidbCluster: [%s/%s] the rotation of the master key of tikv completes", ns, tcName)
+		return
+	}
+	if apiequality.Semantic.DeepEqual(status.MasterKey, spec.MasterKey) {
+		return
+	}
+
+	now := metav1.Now()
+	previous := status.MasterKey
+	status.PreviousMasterKey = &previous
+	status.MasterKey = *spec.MasterKey.DeepCopy()
+	status.Phase = v1alpha1.TiKVEncryptionRotating
+	status.LastRotationStartTime = &now
+	status.LastRotationCompletionTime = nil
+	status.RotationRevision = ""
+	if sts != nil {
+		status.RotationRevision = sts.UpdateRevision
+	}
+	m.deps.Recorder.Event(tc, corev1.EventTypeNormal, tikvEncryptionEventRotate, "rolling restart tikv to rotate the master key")
+	klog.Infof("TidbCluster: [%s/%s] starts to rotate the master key of tikv", ns, tcName)
+}
+
+// tikvEncryptionMasterKeys returns the master key and the previous master key TiKV should run with
+func tikvEncryptionMasterKeys(tc *v1alpha1.TidbCluster) (*v1alpha1.TiKVMasterKeySource, *v1alpha1.TiKVMasterKeySource) {
+	if status := tc.Status.TiKV.Encryption; status != nil {
+		return &status.MasterKey, status.PreviousMasterKey
+	}
+	return &tc.Spec.TiKV.Encryption.MasterKey, nil
+}
+
+// namedMasterKey is a master key with the name it's configured and mounted by
+type namedMasterKey struct {
+	name string
+	key  *v1alpha1.TiKVMasterKeySource
+}
+
+// tikvNamedMasterKeys returns the master key, and the previous master key if there is one
+func tikvNamedMasterKeys(tc *v1alpha1.TidbCluster) []namedMasterKey {
+	masterKey, previousMasterKey := tikvEncryptionMasterKeys(tc)
+	keys := []namedMasterKey{{name: tikvMasterKeyName, key: masterKey}}
+	if previousMasterKey != nil {
+		keys = append(keys, namedMasterKey{name: tikvPreviousMasterKeyName, key: previousMasterKey})
+	}
+	return keys
+}
+
+// setTiKVEncryptionConfig overrides `security.encryption` in the config of TiKV by spec.tikv.encryption
+func setTiKVEncryptionConfig(config *v1alpha1.TiKVConfigWraper, tc *v1alpha1.TidbCluster) {
+	spec := tc.Spec.TiKV.Encryption
+	config.Del("security.encryption")
+	method := spec.Method
+	if method == "" {
+		method = defaultTiKVEncryptionMethod
+	}
+	config.Set("security.encryption.data-encryption-method", method)
+	if spec.DataKeyRotationPeriod != "" {
+		config.Set("security.encryption.data-key-rotation-period", spec.DataKeyRotationPeriod)
+	}
+	for _, k := range tikvNamedMasterKeys(tc) {
+		setTiKVMasterKeyConfig(config, "security.encryption."+k.name, k.name, k.key)
+	}
+}
+
+func setTiKVMasterKeyConfig(config *v1alpha1.TiKVConfigWraper, prefix, name string, key *v1alpha1.TiKVMasterKeySource) {
+	switch {
+	case key.AWSKMS != nil:
+		config.Set(prefix+".type", "kms")
+		config.Set(prefix+".key-id", key.AWSKMS.KeyID)
+		config.Set(prefix+".region", key.AWSKMS.Region)
+		if key.AWSKMS.Endpoint != "" {
+			config.Set(prefix+".endpoint", key.AWSKMS.Endpoint)
+		}
+	case key.GCPKMS != nil:
+		config.Set(prefix+".type", "kms")
+		config.Set(prefix+".vendor", "gcp")
+		config.Set(prefix+".key-id", key.GCPKMS.KeyID)
+		if key.GCPKMS.CredentialsSecret != "" {
+			config.Set(prefix+".gcp.credential-file-path", path.Join(tikvEncryptionMountPath, name+"-gcp", tikvGCPCredentialsKey))
+		}
+	case key.Vault != nil:
+		config.Set(prefix+".type", "file")
+		config.Set(prefix+".path", path.Join(tikvVaultSecretsPath, "tikv-"+name))
+	case key.Secret != nil:
+		secretKey := key.Secret.Key
+		if secretKey == "" {
+			secretKey = defaultTiKVSecretKey
+		}
+		config.Set(prefix+".type", "file")
+		config.Set(prefix+".path", path.Join(tikvEncryptionMountPath, name, secretKey))
+	}
+}
+
+// tikvEncryptionVolumes returns the volumes of the Secrets the master keys are read from
+func tikvEncryptionVolumes(tc *v1alpha1.TidbCluster) ([]corev1.VolumeMount, []corev1.Volume) {
+	if tc.Spec.TiKV.Encryption == nil {
+		return nil, nil
+	}
+	var (
+		volMounts []corev1.VolumeMount
+		vols      []corev1.Volume
+	)
+	addSecret := func(volName, secretName string) {
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: "tikv-" + volName, ReadOnly: true, MountPath: path.Join(tikvEncryptionMountPath, volName),
+		})
+		vols = append(vols, corev1.Volume{
+			Name: "tikv-" + volName, VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+			},
+		})
+	}
+	for _, k := range tikvNamedMasterKeys(tc) {
+		switch {
+		case k.key.Secret != nil:
+			addSecret(k.name, k.key.Secret.Name)
+		case k.key.GCPKMS != nil && k.key.GCPKMS.CredentialsSecret != "":
+			addSecret(k.name+"-gcp", k.key.GCPKMS.CredentialsSecret)
+		}
+	}
+	return volMounts, vols
+}
+
+// tikvEncryptionPodAnnotations returns the annotations of the pods of TiKV for the encryption, which contain the
+// fingerprint of the master key to restart TiKV when it's rotated, and the annotations of the Vault Agent Injector
+// to render the master keys stored in Vault.
+func tikvEncryptionPodAnnotations(tc *v1alpha1.TidbCluster) (map[string]string, error) {
+	if tc.Spec.TiKV.Encryption == nil {
+		return nil, nil
+	}
+	masterKey, _ := tikvEncryptionMasterKeys(tc)
+	fingerprint, err := mngerutils.Sha256Sum(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("compute the fingerprint of the master key of tikv for tc %s/%s failed: %v", tc.Namespace, tc.Name, err)
+	}
+	anns := map[string]string{label.AnnTiKVMasterKey: fingerprint[:16]}
+	for _, k := range tikvNamedMasterKeys(tc) {
+		vault := k.key.Vault
+		if vault == nil {
+			continue
+		}
+		field := vault.Field
+		if field == "" {
+			field = defaultTiKVVaultKeyField
+		}
+		anns[vaultAnnAgentInject] = "true"
+		// the pod authenticates as the role of the master key if both keys are stored in vault
+		if _, ok := anns[vaultAnnRole]; !ok {
+			anns[vaultAnnRole] = vault.Role
+		}
+		anns[vaultAnnAgentInjectSecret+"tikv-"+k.name] = vault.Path
+		// the key file must end with a newline
+		anns[vaultAnnAgentInjectTmpl+"tikv-"+k.name] = fmt.Sprintf("{{- with secret %q -}}{{ .Data.data.%s }}{{ %q }}{{- end }}", vault.Path, field, "\n")
+	}
+	return anns, nil
+}
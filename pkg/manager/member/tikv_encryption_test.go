@@ -0,0 +1,135 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	. "github.com/onsi/gomega"
+	apps "k8s.io/api/apps/v1"
+)
+
+func TestSyncTiKVEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := &tikvMemberManager{deps: controller.NewFakeDependencies()}
+
+	tc := newTidbClusterForTiKVEncryption()
+	tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+	tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "1", Replicas: 3, UpdatedReplicas: 3}
+	m.syncTiKVEncryption(tc)
+	g.Expect(tc.Status.TiKV.Encryption.Phase).To(Equal(v1alpha1.TiKVEncryptionReady))
+	g.Expect(tc.Status.TiKV.Encryption.MasterKey.AWSKMS.KeyID).To(Equal("key-1"))
+
+	// the old master key is kept as the previous one when the master key is changed
+	tc.Spec.TiKV.Encryption.MasterKey.AWSKMS.KeyID = "key-2"
+	m.syncTiKVEncryption(tc)
+	status := tc.Status.TiKV.Encryption
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiKVEncryptionRotating))
+	g.Expect(status.MasterKey.AWSKMS.KeyID).To(Equal("key-2"))
+	g.Expect(status.PreviousMasterKey.AWSKMS.KeyID).To(Equal("key-1"))
+	g.Expect(status.RotationRevision).To(Equal("1"))
+
+	// a new rotation waits for the running one
+	tc.Spec.TiKV.Encryption.MasterKey.AWSKMS.KeyID = "key-3"
+	m.syncTiKVEncryption(tc)
+	g.Expect(status.MasterKey.AWSKMS.KeyID).To(Equal("key-2"))
+	tc.Status.TiKV.StatefulSet.UpdateRevision = "2"
+	tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
+	m.syncTiKVEncryption(tc)
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiKVEncryptionRotating))
+
+	// the rotation completes once all the pods are updated
+	tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "2", UpdateRevision: "2", Replicas: 3, UpdatedReplicas: 3}
+	m.syncTiKVEncryption(tc)
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiKVEncryptionReady))
+	g.Expect(status.LastRotationCompletionTime).NotTo(BeNil())
+	// the previous master key is removed with its volume
+	g.Expect(status.PreviousMasterKey).To(BeNil())
+	g.Expect(tikvNamedMasterKeys(tc)).To(HaveLen(1))
+	m.syncTiKVEncryption(tc)
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiKVEncryptionRotating))
+	g.Expect(status.MasterKey.AWSKMS.KeyID).To(Equal("key-3"))
+	g.Expect(status.PreviousMasterKey.AWSKMS.KeyID).To(Equal("key-2"))
+
+	tc.Spec.TiKV.Encryption = nil
+	m.syncTiKVEncryption(tc)
+	g.Expect(tc.Status.TiKV.Encryption).To(BeNil())
+}
+
+func TestTiKVEncryptionConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiKVEncryption()
+	tc.Spec.TiKV.Encryption.DataKeyRotationPeriod = "3d"
+	tc.Status.TiKV.Encryption = &v1alpha1.TiKVEncryptionStatus{
+		Phase:             v1alpha1.TiKVEncryptionRotating,
+		MasterKey:         v1alpha1.TiKVMasterKeySource{Vault: &v1alpha1.TiKVVaultMasterKey{Role: "tikv", Path: "secret/data/tikv"}},
+		PreviousMasterKey: &v1alpha1.TiKVMasterKeySource{Secret: &v1alpha1.TiKVSecretMasterKey{Name: "old-key"}},
+	}
+	config := v1alpha1.NewTiKVConfig()
+	config.Set("security.encryption.master-key.type", "plaintext")
+	setTiKVEncryptionConfig(config, tc)
+	data, err := config.MarshalTOML()
+	g.Expect(err).To(Succeed())
+	g.Expect(string(data)).To(Equal(strings.TrimLeft(`
+[security]
+  [security.encryption]
+    data-encryption-method = "aes256-ctr"
+    data-key-rotation-period = "3d"
+    [security.encryption.master-key]
+      path = "/vault/secrets/tikv-master-key"
+      type = "file"
+    [security.encryption.previous-master-key]
+      path = "/var/lib/tikv-encryption/previous-master-key/master-key"
+      type = "file"
+`, "\n")))
+
+	volMounts, vols := tikvEncryptionVolumes(tc)
+	g.Expect(volMounts).To(HaveLen(1))
+	g.Expect(volMounts[0].MountPath).To(Equal("/var/lib/tikv-encryption/previous-master-key"))
+	g.Expect(vols[0].Secret.SecretName).To(Equal("old-key"))
+
+	anns, err := tikvEncryptionPodAnnotations(tc)
+	g.Expect(err).To(Succeed())
+	g.Expect(anns).To(HaveKey(label.AnnTiKVMasterKey))
+	g.Expect(anns).To(HaveKeyWithValue("vault.hashicorp.com/role", "tikv"))
+	g.Expect(anns).To(HaveKeyWithValue("vault.hashicorp.com/agent-inject-secret-tikv-master-key", "secret/data/tikv"))
+	g.Expect(anns).To(HaveKeyWithValue("vault.hashicorp.com/agent-inject-template-tikv-master-key",
+		`{{- with secret "secret/data/tikv" -}}{{ .Data.data.key }}{{ "\n" }}{{- end }}`))
+
+	// the pods are restarted when the master key changes
+	tc.Status.TiKV.Encryption.MasterKey = v1alpha1.TiKVMasterKeySource{AWSKMS: &v1alpha1.TiKVAWSKMSMasterKey{KeyID: "key", Region: "us-west-2"}}
+	newAnns, err := tikvEncryptionPodAnnotations(tc)
+	g.Expect(err).To(Succeed())
+	g.Expect(newAnns[label.AnnTiKVMasterKey]).NotTo(Equal(anns[label.AnnTiKVMasterKey]))
+	g.Expect(newAnns).NotTo(HaveKey("vault.hashicorp.com/agent-inject"))
+}
+
+func newTidbClusterForTiKVEncryption() *v1alpha1.TidbCluster {
+	tc := &v1alpha1.TidbCluster{}
+	tc.Namespace = "ns"
+	tc.Name = "tc"
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{
+		Config: v1alpha1.NewTiKVConfig(),
+		Encryption: &v1alpha1.TiKVEncryptionSpec{
+			MasterKey: v1alpha1.TiKVMasterKeySource{AWSKMS: &v1alpha1.TiKVAWSKMSMasterKey{KeyID: "key-1", Region: "us-west-2"}},
+		},
+	}
+	return tc
+}
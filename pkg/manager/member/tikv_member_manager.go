@@ -104,7 +104,7 @@ func (m *tikvMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	component := v1alpha1.TiKVMemberType
 	needSuspend, err := m.suspender.SuspendComponent(tc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, ns, tcName)
@@ -143,7 +143,18 @@ func (m *tikvMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 			return err
 		}
 	}
-	return m.syncStatefulSetForTidbCluster(tc)
+	if err := m.syncStatefulSetForTidbCluster(tc); err != nil {
+		return err
+	}
+	return m.syncWitness(tc)
+}
+
+// SyncStatus refreshes the status of TiKV from its statefulset without acting upon the spec
+func (m *tikvMemberManager) SyncStatus(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiKV == nil {
+		return nil
+	}
+	return syncStatusFromStatefulSet(m.deps, tc, controller.TiKVMemberName(tc.GetName()), m.syncTiKVClusterStatus)
 }
 
 func (m *tikvMemberManager) checkRecoveryForTidbCluster(tc *v1alpha1.TidbCluster) error {
@@ -221,6 +232,7 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return nil
 	}
 
+	m.syncTiKVEncryption(tc)
 	cm, err := m.syncTiKVConfigMap(tc, oldSet)
 	if err != nil {
 		return err
@@ -240,6 +252,7 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	if err != nil {
 		return err
 	}
+	degradeNativeSidecars(m.deps.Capabilities, newSet)
 	if setNotExist {
 		err = mngerutils.SetStatefulSetLastAppliedConfigAnnotation(newSet)
 		if err != nil {
@@ -257,6 +270,21 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return err
 	}
 
+	// the failure of setting the store weights does not block the scaling and upgrading, it's retried in the next sync
+	if err := m.syncStoreWeights(tc); err != nil {
+		klog.Warningf("TidbCluster: [%s/%s], sync the weights of the tikv stores failed: %v", ns, tcName, err)
+	}
+
+	// the failure of mitigating the slow stores does not block the scaling and upgrading, it's retried in the next sync
+	if err := m.syncSlowStoreMitigation(tc); err != nil {
+		klog.Warningf("TidbCluster: [%s/%s], sync the mitigation of the slow tikv stores failed: %v", ns, tcName, err)
+	}
+
+	// the failure of tuning pd for the rebalancing does not block the scaling, it's retried in the next sync
+	if err := m.syncScaleOutBalance(tc); err != nil {
+		klog.Warningf("TidbCluster: [%s/%s], sync the scheduling of pd for scaling out tikv failed: %v", ns, tcName, err)
+	}
+
 	// Scaling takes precedence over upgrading because:
 	// - if a store fails in the upgrading, users may want to delete it or add
 	//   new replicas
@@ -301,7 +329,7 @@ func (m *tikvMemberManager) syncTiKVConfigMap(tc *v1alpha1.TidbCluster, set *app
 	if tc.Spec.TiKV.Config == nil {
 		return nil, nil
 	}
-	newCm, err := getTikVConfigMap(tc)
+	newCm, err := getTikVConfigMap(m.deps.ConfigDefaults.Apply(tc, v1alpha1.TiKVMemberType))
 	if err != nil {
 		return nil, err
 	}
@@ -451,6 +479,9 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			})
 		}
 	}
+	encryptionVolMounts, encryptionVols := tikvEncryptionVolumes(tc)
+	volMounts = append(volMounts, encryptionVolMounts...)
+	vols = append(vols, encryptionVols...)
 	// handle StorageVolumes and AdditionalVolumeMounts in ComponentSpec
 	storageVolMounts, additionalPVCs := util.BuildStorageVolumeAndVolumeMount(tc.Spec.TiKV.StorageVolumes, tc.Spec.TiKV.StorageClassName, v1alpha1.TiKVMemberType)
 	volMounts = append(volMounts, storageVolMounts...)
@@ -502,7 +533,11 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	stsLabels := labelTiKV(tc)
 	podLabels := util.CombineStringMap(stsLabels.Labels(), baseTiKVSpec.Labels())
 	setName := controller.TiKVMemberName(tcName)
-	podAnnotations := util.CombineStringMap(baseTiKVSpec.Annotations(), controller.AnnProm(v1alpha1.DefaultTiKVStatusPort, "/metrics"))
+	encryptionAnnotations, err := tikvEncryptionPodAnnotations(tc)
+	if err != nil {
+		return nil, err
+	}
+	podAnnotations := util.CombineStringMap(baseTiKVSpec.Annotations(), controller.AnnProm(v1alpha1.DefaultTiKVStatusPort, "/metrics"), encryptionAnnotations)
 	stsAnnotations := getStsAnnotations(tc.Annotations, label.TiKVLabelVal)
 	capacity := controller.TiKVCapacity(tc.Spec.TiKV.Limits)
 	headlessSvcName := controller.TiKVPeerMemberName(tcName)
@@ -638,6 +673,15 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 		}
 	}
 
+	logVolSpec, err := buildLogVolumePodSpec(tc, v1alpha1.TiKVMemberType, tc.Spec.TiKV.LogVolume, tc.Spec.TiKV.StorageVolumes, tc.Spec.TiKV.StorageClassName, tc.Spec.TiKV.AdditionalVolumeMounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log volume for cluster %s/%s: %v", ns, tcName, err)
+	}
+	vols = append(vols, logVolSpec.volumes...)
+	volMounts = append(volMounts, logVolSpec.volumeMounts...)
+	initContainers = append(initContainers, logVolSpec.initContainers...)
+	containers = append(containers, logVolSpec.containers...)
+
 	env := []corev1.EnvVar{
 		{
 			Name: "NAMESPACE",
@@ -869,6 +913,11 @@ func (m *tikvMemberManager) syncTiKVClusterStatus(tc *v1alpha1.TidbCluster, set
 		if err = endEvictLeaderForAllStore(m.deps, tc); err != nil {
 			return err
 		}
+
+		clearStuckUpgrade(tc, "")
+	}
+	if err = clearStuckUpgradeAnnotations(m.deps, tc); err != nil {
+		return err
 	}
 
 	// Scaling takes precedence over upgrading.
@@ -931,8 +980,9 @@ func (m *tikvMemberManager) syncTiKVClusterStatus(tc *v1alpha1.TidbCluster, set
 		// So we check the store owner to make sure it.
 		if store.Store != nil {
 			if pattern.Match([]byte(store.Store.Address)) {
+				m.syncSlowStoreStatus(tc, store, status, oldStore)
 				stores[status.ID] = *status
-			} else if util.MatchLabelFromStoreLabels(store.Store.Labels, label.TiKVLabelVal) {
+			} else if util.MatchLabelFromStoreLabels(store.Store.Labels, label.TiKVLabelVal) && !isWitnessStore(store.Store.Labels) {
 				peerStores[status.ID] = *status
 			}
 		}
@@ -965,6 +1015,7 @@ func (m *tikvMemberManager) syncTiKVClusterStatus(tc *v1alpha1.TidbCluster, set
 	tc.Status.TiKV.Stores = stores
 	tc.Status.TiKV.PeerStores = peerStores
 	tc.Status.TiKV.TombstoneStores = tombstoneStores
+	syncSlowStoreMetrics(tc)
 	tc.Status.TiKV.BootStrapped = true
 	tc.Status.TiKV.Image = ""
 	c := findContainerByName(set, "tikv")
@@ -1089,7 +1140,8 @@ func (m *tikvMemberManager) storeLabelsEqualNodeLabels(storeLabels []*metapb.Sto
 }
 
 func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi.PDControlInterface, set *apps.StatefulSet, tc *v1alpha1.TidbCluster) (bool, error) {
-	if mngerutils.StatefulSetIsUpgrading(set) {
+	partition := tc.Spec.TiKV.RollingUpdateStrategy.GetPartition()
+	if statefulSetIsUpgrading(set, partition) {
 		return true, nil
 	}
 	instanceName := tc.GetInstanceName()
@@ -1102,6 +1154,9 @@ func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi
 		return false, fmt.Errorf("tikvStatefulSetIsUpgrading: failed to get pods for cluster %s/%s, selector %s, error: %s", tc.GetNamespace(), instanceName, selector, err)
 	}
 	for _, pod := range tikvPods {
+		if heldByPartition(pod, partition) {
+			continue
+		}
 		revisionHash, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
 		if !exist {
 			return false, nil
@@ -1118,6 +1173,14 @@ func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi
 
 // TODO: Support check tikv status http request in future.
 func buildTiKVReadinessProbHandler(tc *v1alpha1.TidbCluster) corev1.ProbeHandler {
+	if tp := tc.Spec.TiKV.ReadinessProbe.Type; tp != nil && *tp == v1alpha1.GRPCProbeType {
+		// the store is ready only when its gRPC server is serving, the TCP port may be open earlier
+		return corev1.ProbeHandler{
+			GRPC: &corev1.GRPCAction{
+				Port: v1alpha1.DefaultTiKVServerPort,
+			},
+		}
+	}
 	return corev1.ProbeHandler{
 		TCPSocket: &corev1.TCPSocketAction{
 			Port: intstr.FromInt(int(v1alpha1.DefaultTiKVServerPort)),
@@ -1148,6 +1211,10 @@ func (m *FakeTiKVMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	return nil
 }
 
+func (m *FakeTiKVMemberManager) SyncStatus(*v1alpha1.TidbCluster) error {
+	return nil
+}
+
 // applyPiTRConfigOverride checks for active PiTR restores and overrides gc.ratio-threshold if found
 func (m *tikvMemberManager) applyPiTRConfigOverride(tc *v1alpha1.TidbCluster, newCm *corev1.ConfigMap) error {
 	// Check for active PiTR restores
@@ -0,0 +1,282 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// pdRegionScheduleLimitKey is the runtime config item of PD tuned by spec.tikv.scaleOutBalancePolicy
+	pdRegionScheduleLimitKey = "schedule.region-schedule-limit"
+
+	scaleOutBalanceStartedReason  = "ScaleOutBalanceStarted"
+	scaleOutBalanceFinishedReason = "ScaleOutBalanceFinished"
+)
+
+// syncScaleOutBalance tunes the scheduling of PD when TiKV is scaled out by multiple replicas:
+// the add-peer store limits of the new stores are raised once the stores are up, and
+// schedule.region-schedule-limit is changed if it's set in spec.tikv.scaleOutBalancePolicy.
+// The settings are reverted when the new stores are balanced, the rebalancing times out,
+// the scale out is canceled or the policy is removed.
+func (m *tikvMemberManager) syncScaleOutBalance(tc *v1alpha1.TidbCluster) error {
+	status := tc.Status.TiKV.ScaleOutBalance
+	if status == nil {
+		return m.startScaleOutBalance(tc)
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	if tc.Spec.TiKV.ScaleOutBalancePolicy == nil {
+		return m.finishScaleOutBalance(tc, "the policy is removed")
+	}
+	if tc.Spec.TiKV.Replicas < status.Replicas {
+		return m.finishScaleOutBalance(tc, fmt.Sprintf("the replicas are changed to %d", tc.Spec.TiKV.Replicas))
+	}
+	if time.Since(status.StartTime.Time) > tc.TiKVScaleOutBalanceTimeout() {
+		return m.finishScaleOutBalance(tc, fmt.Sprintf("the rebalancing does not finish in %s", tc.TiKVScaleOutBalanceTimeout()))
+	}
+	// scaled out again in the middle of the rebalancing
+	status.Replicas = tc.Spec.TiKV.Replicas
+
+	pdClient := controller.GetPDClient(m.deps.PDControl, tc)
+	newStores := scaleOutNewStores(tc)
+	var limits map[uint64]pdapi.StoreLimitConfig
+	var raised []string
+	for _, id := range newStores {
+		if _, ok := status.StoreLimits[id]; ok || tc.Status.TiKV.Stores[id].State != v1alpha1.TiKVStateUp {
+			continue
+		}
+		storeID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return err
+		}
+		if limits == nil {
+			if limits, err = pdClient.GetStoreLimits(); err != nil {
+				return fmt.Errorf("get store limits failed: %v", err)
+			}
+		}
+		limit, ok := limits[storeID]
+		if !ok {
+			continue
+		}
+		if status.StoreLimits == nil {
+			status.StoreLimits = map[string]string{}
+		}
+		status.StoreLimits[id] = strconv.FormatFloat(limit.AddPeer, 'f', -1, 64)
+		raised = append(raised, id)
+	}
+	if len(raised) > 0 {
+		// the original limits are saved before they are changed in pd, so they can always be reverted
+		if err := m.saveScaleOutBalance(tc); err != nil {
+			for _, id := range raised {
+				delete(status.StoreLimits, id)
+			}
+			return err
+		}
+		for _, id := range raised {
+			storeID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				return err
+			}
+			if err := pdClient.SetStoreLimit(storeID, pdapi.StoreLimitTypeAddPeer, float64(tc.TiKVScaleOutBalanceStoreLimit())); err != nil {
+				return err
+			}
+			klog.Infof("TidbCluster: [%s/%s]'s store %s add-peer limit is raised from %s to %d for the rebalancing",
+				ns, tcName, id, status.StoreLimits[id], tc.TiKVScaleOutBalanceStoreLimit())
+		}
+	}
+
+	balanced, err := m.scaleOutBalanced(tc, pdClient, newStores)
+	if err != nil {
+		return err
+	}
+	if balanced {
+		return m.finishScaleOutBalance(tc, "the new stores are balanced")
+	}
+	return nil
+}
+
+// startScaleOutBalance starts the rebalancing if TiKV is scaled out by at least spec.tikv.scaleOutBalancePolicy.minReplicas
+func (m *tikvMemberManager) startScaleOutBalance(tc *v1alpha1.TidbCluster) error {
+	policy := tc.Spec.TiKV.ScaleOutBalancePolicy
+	if policy == nil || !tc.TiKVBootStrapped() || tc.Status.TiKV.VolReplaceInProgress {
+		return nil
+	}
+	if tc.TiKVStsDesiredReplicas()-tc.TiKVStsActualReplicas() < tc.TiKVScaleOutBalanceMinReplicas() {
+		return nil
+	}
+
+	status := &v1alpha1.ScaleOutBalanceStatus{
+		StartTime: metav1.Now(),
+		Replicas:  tc.Spec.TiKV.Replicas,
+	}
+	for id := range tc.Status.TiKV.Stores {
+		status.BaselineStores = append(status.BaselineStores, id)
+	}
+	sort.Strings(status.BaselineStores)
+
+	if policy.RegionScheduleLimit != nil {
+		pdClient := controller.GetPDClient(m.deps.PDControl, tc)
+		config, err := pdClient.GetConfig()
+		if err != nil {
+			return fmt.Errorf("get pd config failed: %v", err)
+		}
+		if config.Schedule == nil || config.Schedule.RegionScheduleLimit == nil {
+			return fmt.Errorf("%s is not returned by pd", pdRegionScheduleLimitKey)
+		}
+		original := int64(*config.Schedule.RegionScheduleLimit)
+		status.RegionScheduleLimit = &original
+		// the original limit is saved before it's changed in pd, so it can always be reverted
+		tc.Status.TiKV.ScaleOutBalance = status
+		if err := m.saveScaleOutBalance(tc); err != nil {
+			tc.Status.TiKV.ScaleOutBalance = nil
+			return err
+		}
+		if err := pdClient.UpdateConfig(map[string]interface{}{pdRegionScheduleLimitKey: *policy.RegionScheduleLimit}); err != nil {
+			return err
+		}
+	}
+	tc.Status.TiKV.ScaleOutBalance = status
+
+	msg := fmt.Sprintf("tune the scheduling of pd for scaling out tikv from %d to %d replicas", tc.TiKVStsActualReplicas(), tc.Spec.TiKV.Replicas)
+	klog.Infof("TidbCluster: [%s/%s], %s", tc.GetNamespace(), tc.GetName(), msg)
+	m.deps.Recorder.Event(tc, corev1.EventTypeNormal, scaleOutBalanceStartedReason, msg)
+	return nil
+}
+
+// finishScaleOutBalance reverts the settings tuned for the rebalancing
+func (m *tikvMemberManager) finishScaleOutBalance(tc *v1alpha1.TidbCluster, reason string) error {
+	status := tc.Status.TiKV.ScaleOutBalance
+	pdClient := controller.GetPDClient(m.deps.PDControl, tc)
+
+	ids := make([]string, 0, len(status.StoreLimits))
+	for id := range status.StoreLimits {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		storeID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return err
+		}
+		rate, err := strconv.ParseFloat(status.StoreLimits[id], 64)
+		if err != nil {
+			return err
+		}
+		if _, ok := tc.Status.TiKV.Stores[id]; ok {
+			if err := pdClient.SetStoreLimit(storeID, pdapi.StoreLimitTypeAddPeer, rate); err != nil {
+				return err
+			}
+		}
+		delete(status.StoreLimits, id)
+	}
+	if status.RegionScheduleLimit != nil {
+		if err := pdClient.UpdateConfig(map[string]interface{}{pdRegionScheduleLimitKey: *status.RegionScheduleLimit}); err != nil {
+			return err
+		}
+	}
+	tc.Status.TiKV.ScaleOutBalance = nil
+
+	msg := fmt.Sprintf("revert the scheduling of pd tuned for scaling out tikv, %s", reason)
+	klog.Infof("TidbCluster: [%s/%s], %s", tc.GetNamespace(), tc.GetName(), msg)
+	m.deps.Recorder.Event(tc, corev1.EventTypeNormal, scaleOutBalanceFinishedReason, msg)
+	return nil
+}
+
+// saveScaleOutBalance saves the status of the rebalancing in the latest status of the tidb cluster, the status
+// is saved before pd is tuned, as the status of the sync is only saved after all the components are synced.
+func (m *tikvMemberManager) saveScaleOutBalance(tc *v1alpha1.TidbCluster) error {
+	latest, err := m.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get tidbcluster %s/%s failed: %v", tc.Namespace, tc.Name, err)
+	}
+	latest.Status.TiKV.ScaleOutBalance = tc.Status.TiKV.ScaleOutBalance.DeepCopy()
+	updated, err := m.deps.Clientset.PingcapV1alpha1().TidbClusters(latest.Namespace).Update(context.TODO(), latest, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("save the scale out balance status of tidbcluster %s/%s failed: %v", tc.Namespace, tc.Name, err)
+	}
+	tc.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// scaleOutBalanced returns whether all the new stores are up, and the region count of each new store
+// reaches spec.tikv.scaleOutBalancePolicy.balancedPercent of the average region count of the stores
+func (m *tikvMemberManager) scaleOutBalanced(tc *v1alpha1.TidbCluster, pdClient pdapi.PDClient, newStores []string) (bool, error) {
+	status := tc.Status.TiKV.ScaleOutBalance
+	if len(newStores) < int(status.Replicas)-len(status.BaselineStores) {
+		return false, nil
+	}
+	for _, id := range newStores {
+		if tc.Status.TiKV.Stores[id].State != v1alpha1.TiKVStateUp {
+			return false, nil
+		}
+	}
+
+	storesInfo, err := pdClient.GetStores()
+	if err != nil {
+		return false, fmt.Errorf("get stores failed: %v", err)
+	}
+	regionCounts := map[string]int{}
+	total := 0
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || store.Status == nil {
+			continue
+		}
+		id := strconv.FormatUint(store.Store.Id, 10)
+		if _, ok := tc.Status.TiKV.Stores[id]; !ok || store.Store.StateName != v1alpha1.TiKVStateUp {
+			continue
+		}
+		regionCounts[id] = store.Status.RegionCount
+		total += store.Status.RegionCount
+	}
+	if len(regionCounts) == 0 {
+		return false, nil
+	}
+	percent := int(tc.TiKVScaleOutBalancedPercent())
+	for _, id := range newStores {
+		count, ok := regionCounts[id]
+		if !ok || count*len(regionCounts)*100 < total*percent {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// scaleOutNewStores returns the ids of the stores created after the scale out is started
+func scaleOutNewStores(tc *v1alpha1.TidbCluster) []string {
+	baseline := map[string]struct{}{}
+	for _, id := range tc.Status.TiKV.ScaleOutBalance.BaselineStores {
+		baseline[id] = struct{}{}
+	}
+	var ids []string
+	for id := range tc.Status.TiKV.Stores {
+		if _, ok := baseline[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
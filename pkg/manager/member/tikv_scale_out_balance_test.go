@@ -0,0 +1,160 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestSyncScaleOutBalance(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiKV()
+	tc.Spec.TiKV.Replicas = 5
+	tc.Spec.TiKV.ScaleOutBalancePolicy = &v1alpha1.ScaleOutBalancePolicy{
+		RegionScheduleLimit: pointer.Int64Ptr(4096),
+	}
+	tc.Status.TiKV.BootStrapped = true
+	tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{Replicas: 3}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", State: v1alpha1.TiKVStateUp},
+		"2": {ID: "2", State: v1alpha1.TiKVStateUp},
+		"3": {ID: "3", State: v1alpha1.TiKVStateUp},
+	}
+	tmm, _, _, pdClient, _, _ := newFakeTiKVMemberManager(tc)
+	_, err := tmm.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).To(Succeed())
+	saved := func() *v1alpha1.ScaleOutBalanceStatus {
+		latest, err := tmm.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+		g.Expect(err).To(Succeed())
+		return latest.Status.TiKV.ScaleOutBalance
+	}
+
+	regionScheduleLimit := uint64(2048)
+	storeLimits := map[uint64]pdapi.StoreLimitConfig{}
+	regionCounts := map[uint64]int{1: 100, 2: 100, 3: 100}
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		limit := regionScheduleLimit
+		return &pdapi.PDConfigFromAPI{Schedule: &pdapi.PDScheduleConfig{RegionScheduleLimit: &limit}}, nil
+	})
+	pdClient.AddReaction(pdapi.UpdateConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		// the original limit is saved before pd is changed
+		g.Expect(saved().RegionScheduleLimit).NotTo(BeNil())
+		regionScheduleLimit = uint64(action.Config[pdRegionScheduleLimitKey].(int64))
+		return nil, nil
+	})
+	pdClient.AddReaction(pdapi.GetStoreLimitsActionType, func(action *pdapi.Action) (interface{}, error) {
+		limits := map[uint64]pdapi.StoreLimitConfig{}
+		for id := range regionCounts {
+			limits[id] = pdapi.StoreLimitConfig{AddPeer: 15, RemovePeer: 15}
+		}
+		for id, limit := range storeLimits {
+			limits[id] = limit
+		}
+		return limits, nil
+	})
+	pdClient.AddReaction(pdapi.SetStoreLimitActionType, func(action *pdapi.Action) (interface{}, error) {
+		g.Expect(action.Name).To(Equal(pdapi.StoreLimitTypeAddPeer))
+		g.Expect(saved().StoreLimits).To(HaveKey(strconv.FormatUint(action.ID, 10)))
+		storeLimits[action.ID] = pdapi.StoreLimitConfig{AddPeer: action.Rate, RemovePeer: 15}
+		return nil, nil
+	})
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		stores := &pdapi.StoresInfo{}
+		for id, count := range regionCounts {
+			stores.Stores = append(stores.Stores, &pdapi.StoreInfo{
+				Store:  &pdapi.MetaStore{Store: &metapb.Store{Id: id}, StateName: v1alpha1.TiKVStateUp},
+				Status: &pdapi.StoreStatus{RegionCount: count},
+			})
+		}
+		return stores, nil
+	})
+
+	// the rebalancing is started with the scale out
+	g.Expect(tmm.syncScaleOutBalance(tc)).To(Succeed())
+	status := tc.Status.TiKV.ScaleOutBalance
+	g.Expect(status).NotTo(BeNil())
+	g.Expect(status.Replicas).To(Equal(int32(5)))
+	g.Expect(status.BaselineStores).To(Equal([]string{"1", "2", "3"}))
+	g.Expect(*status.RegionScheduleLimit).To(Equal(int64(2048)))
+	g.Expect(regionScheduleLimit).To(Equal(uint64(4096)))
+
+	// the store limits of the new stores are raised once they are up
+	tc.Status.TiKV.Stores["4"] = v1alpha1.TiKVStore{ID: "4", State: v1alpha1.TiKVStateUp}
+	tc.Status.TiKV.Stores["5"] = v1alpha1.TiKVStore{ID: "5", State: v1alpha1.TiKVStateDown}
+	regionCounts[4] = 0
+	regionCounts[5] = 0
+	g.Expect(tmm.syncScaleOutBalance(tc)).To(Succeed())
+	g.Expect(status.StoreLimits).To(Equal(map[string]string{"4": "15"}))
+	g.Expect(storeLimits[4].AddPeer).To(Equal(float64(50)))
+	g.Expect(storeLimits).NotTo(HaveKey(uint64(1)))
+
+	tc.Status.TiKV.Stores["5"] = v1alpha1.TiKVStore{ID: "5", State: v1alpha1.TiKVStateUp}
+	g.Expect(tmm.syncScaleOutBalance(tc)).To(Succeed())
+	g.Expect(status.StoreLimits).To(HaveLen(2))
+	g.Expect(tc.Status.TiKV.ScaleOutBalance).NotTo(BeNil())
+
+	// the settings are reverted after the new stores are balanced
+	regionCounts = map[uint64]int{1: 60, 2: 60, 3: 60, 4: 55, 5: 55}
+	g.Expect(tmm.syncScaleOutBalance(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.ScaleOutBalance).To(BeNil())
+	g.Expect(storeLimits[4].AddPeer).To(Equal(float64(15)))
+	g.Expect(storeLimits[5].AddPeer).To(Equal(float64(15)))
+	g.Expect(regionScheduleLimit).To(Equal(uint64(2048)))
+
+	// nothing is done if the replicas scaled out are fewer than minReplicas
+	tc.Spec.TiKV.Replicas = 6
+	tc.Status.TiKV.StatefulSet.Replicas = 5
+	g.Expect(tmm.syncScaleOutBalance(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.ScaleOutBalance).To(BeNil())
+}
+
+func TestSyncScaleOutBalanceTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiKV()
+	tc.Spec.TiKV.ScaleOutBalancePolicy = &v1alpha1.ScaleOutBalancePolicy{Timeout: &metav1.Duration{Duration: time.Hour}}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", State: v1alpha1.TiKVStateUp},
+		"4": {ID: "4", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiKV.ScaleOutBalance = &v1alpha1.ScaleOutBalanceStatus{
+		StartTime:      metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+		Replicas:       3,
+		BaselineStores: []string{"1"},
+		StoreLimits:    map[string]string{"4": "15.5"},
+	}
+	tmm, _, _, pdClient, _, _ := newFakeTiKVMemberManager(tc)
+	var rates []float64
+	pdClient.AddReaction(pdapi.SetStoreLimitActionType, func(action *pdapi.Action) (interface{}, error) {
+		rates = append(rates, action.Rate)
+		return nil, nil
+	})
+
+	g.Expect(tmm.syncScaleOutBalance(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.ScaleOutBalance).To(BeNil())
+	g.Expect(rates).To(Equal([]float64{15.5}))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/third_party/k8s"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	apps "k8s.io/api/apps/v1"
@@ -113,6 +114,10 @@ func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSe
 		return nil
 	}
 
+	if err := acquireDisruptionLock(s.deps, tc, DisruptionTiKVScaleIn); err != nil {
+		return err
+	}
+
 	scaleInParallelism := tc.Spec.TiKV.GetScaleInParallelism()
 
 	_, ordinals, replicas, deleteSlots := scaleMulti(oldSet, newSet, scaleInParallelism)
@@ -141,9 +146,14 @@ func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSe
 			return fmt.Errorf("failed to get config in TidbCluster %s/%s", tc.GetNamespace(), tc.GetName())
 		}
 		maxReplicas = int(*(config.Replication.MaxReplicas))
-		// filter out TiFlash
+		if tc.TiKVWitnessRulesApplied() {
+			// one replica of each region is placed on the witness stores
+			maxReplicas--
+		}
+		// filter out TiFlash and the witness stores
 		for _, store := range storesInfo.Stores {
-			if store.Store != nil && store.Store.StateName == v1alpha1.TiKVStateUp && util.MatchLabelFromStoreLabels(store.Store.Labels, label.TiKVLabelVal) {
+			if store.Store != nil && store.Store.StateName == v1alpha1.TiKVStateUp && util.MatchLabelFromStoreLabels(store.Store.Labels, label.TiKVLabelVal) &&
+				!isWitnessStore(store.Store.Labels) {
 				upTikvStoreCount++
 			}
 		}
@@ -250,6 +260,9 @@ func (s *tikvScaler) scaleInOne(tc *v1alpha1.TidbCluster, skipPreCheck bool, upT
 			}
 
 			if state != v1alpha1.TiKVStateOffline && leaderEvictedOrTimeout {
+				if reason := pdapi.CheckStoresDeletable(pdc, tc.TiKVDeletionSafetyLevel(), id); reason != "" {
+					return deletedUpStore, controller.RequeueErrorf("TiKV %s/%s store %d can not be deleted now: %s", ns, podName, id, reason)
+				}
 				if err := pdc.DeleteStore(id); err != nil {
 					klog.Errorf("tikvScaler.ScaleIn: failed to delete store %d, %v", id, err)
 					return deletedUpStore, err
@@ -0,0 +1,217 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// slowStoreLeaderWeight is the leader weight of the slow stores mitigated by reducing the leader weights
+	slowStoreLeaderWeight = 0.1
+
+	slowStoreDetectedReason               = "SlowStoreDetected"
+	slowStoreRecoveredReason              = "SlowStoreRecovered"
+	slowStoreMitigatedReason              = "SlowStoreMitigated"
+	slowStoreMitigationRevertReason       = "SlowStoreMitigationReverted"
+	failedMitigateSlowStoreReason         = "FailedMitigateSlowStore"
+	failedRevertSlowStoreMitigationReason = "FailedRevertSlowStoreMitigation"
+)
+
+// syncSlowStoreStatus flags the store as slow in the status if its slow score stays at or above the
+// threshold longer than the duration of spec.tikv.slowStoreDetection
+func (m *tikvMemberManager) syncSlowStoreStatus(tc *v1alpha1.TidbCluster, store *pdapi.StoreInfo, status *v1alpha1.TiKVStore, oldStore v1alpha1.TiKVStore) {
+	// the mitigation is kept in the status until it's reverted
+	status.SlowStoreMitigation = oldStore.SlowStoreMitigation
+	if !tc.TiKVSlowStoreDetectionEnabled() || store.Store.StateName != v1alpha1.TiKVStateUp ||
+		store.Status.SlowScore < uint64(tc.TiKVSlowStoreScoreThreshold()) {
+		if oldStore.Slow {
+			m.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, slowStoreRecoveredReason,
+				"store %s of pod %s is no longer slow, slow score %d", status.ID, status.PodName, store.Status.SlowScore)
+		}
+		return
+	}
+
+	status.SlowSince = oldStore.SlowSince
+	if status.SlowSince == nil {
+		now := metav1.Now()
+		status.SlowSince = &now
+	}
+	status.Slow = time.Since(status.SlowSince.Time) >= tc.TiKVSlowStoreDuration()
+	if status.Slow && !oldStore.Slow {
+		klog.Warningf("TidbCluster: [%s/%s]'s store %s of pod %s is slow since %s, slow score %d",
+			tc.Namespace, tc.Name, status.ID, status.PodName, status.SlowSince.Format(time.RFC3339), store.Status.SlowScore)
+		m.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, slowStoreDetectedReason,
+			"store %s of pod %s is slow since %s, slow score %d, its disk may be failing",
+			status.ID, status.PodName, status.SlowSince.Format(time.RFC3339), store.Status.SlowScore)
+	}
+}
+
+// syncSlowStoreMetrics exports the number of the slow stores of the cluster
+func syncSlowStoreMetrics(tc *v1alpha1.TidbCluster) {
+	slow := 0
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.Slow {
+			slow++
+		}
+	}
+	metrics.ClusterTiKVSlowStores.WithLabelValues(tc.Namespace, tc.Name).Set(float64(slow))
+}
+
+// syncSlowStoreMitigation mitigates the slow stores by spec.tikv.slowStoreDetection.mitigation, and reverts
+// the mitigation of the stores recovered. At most maxMitigatedStores stores are mitigated at the same time,
+// and the mitigation is not changed during the upgrade of TiKV or the leader eviction of the component,
+// which evict and restore the leaders of the stores too.
+func (m *tikvMemberManager) syncSlowStoreMitigation(tc *v1alpha1.TidbCluster) error {
+	if tc.TiKVUpgrading() || tc.IsComponentLeaderEvicting(v1alpha1.TiKVMemberType) {
+		return nil
+	}
+	mitigation := v1alpha1.TiKVSlowStoreMitigationNone
+	if tc.TiKVSlowStoreDetectionEnabled() && features.DefaultFeatureGate.Enabled(features.SlowStoreMitigation) {
+		mitigation = tc.TiKVSlowStoreMitigation()
+	}
+
+	ids := make([]string, 0, len(tc.Status.TiKV.Stores))
+	mitigated := 0
+	for id, store := range tc.Status.TiKV.Stores {
+		if store.SlowStoreMitigation != "" {
+			mitigated++
+		} else if !store.Slow || mitigation == v1alpha1.TiKVSlowStoreMitigationNone {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	// the mitigated stores are synced first so the recovered ones make room for the others, and the stores
+	// slow for longer are mitigated first
+	sort.Slice(ids, func(i, j int) bool {
+		si, sj := tc.Status.TiKV.Stores[ids[i]], tc.Status.TiKV.Stores[ids[j]]
+		if (si.SlowStoreMitigation != "") != (sj.SlowStoreMitigation != "") {
+			return si.SlowStoreMitigation != ""
+		}
+		if si.SlowSince == nil || sj.SlowSince == nil {
+			return si.SlowSince != nil
+		}
+		return si.SlowSince.Before(sj.SlowSince)
+	})
+
+	pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		return fmt.Errorf("get stores failed: %v", err)
+	}
+	storeStatuses := map[string]*pdapi.StoreStatus{}
+	for _, s := range storesInfo.Stores {
+		if s.Store != nil && s.Status != nil {
+			storeStatuses[fmt.Sprintf("%d", s.Store.GetId())] = s.Status
+		}
+	}
+
+	var errs []error
+	for _, id := range ids {
+		store := tc.Status.TiKV.Stores[id]
+		storeStatus, ok := storeStatuses[id]
+		if !ok {
+			continue
+		}
+		var storeID uint64
+		if _, err := fmt.Sscanf(id, "%d", &storeID); err != nil {
+			errs = append(errs, fmt.Errorf("invalid store id %s: %v", id, err))
+			continue
+		}
+
+		switch {
+		case store.SlowStoreMitigation != "" && (store.SlowSince == nil || store.SlowStoreMitigation != mitigation):
+			if err := m.revertSlowStoreMitigation(tc, pdCli, storeID, store, storeStatus); err != nil {
+				m.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, failedRevertSlowStoreMitigationReason,
+					"failed to revert the mitigation %s of store %s of pod %s: %v", store.SlowStoreMitigation, id, store.PodName, err)
+				errs = append(errs, err)
+				continue
+			}
+			m.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, slowStoreMitigationRevertReason,
+				"the mitigation %s of store %s of pod %s is reverted", store.SlowStoreMitigation, id, store.PodName)
+			store.SlowStoreMitigation = ""
+			tc.Status.TiKV.Stores[id] = store
+			mitigated--
+		case store.SlowStoreMitigation != "":
+			// apply the mitigation again in case it's reverted by others, e.g. the end of an upgrade
+			if err := applySlowStoreMitigation(pdCli, storeID, store.SlowStoreMitigation, storeStatus); err != nil {
+				errs = append(errs, err)
+			}
+		case mitigated < tc.TiKVMaxMitigatedSlowStores():
+			if err := applySlowStoreMitigation(pdCli, storeID, mitigation, storeStatus); err != nil {
+				m.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, failedMitigateSlowStoreReason,
+					"failed to mitigate the slow store %s of pod %s by %s: %v", id, store.PodName, mitigation, err)
+				errs = append(errs, err)
+				continue
+			}
+			klog.Infof("TidbCluster: [%s/%s]'s slow store %s of pod %s is mitigated by %s", tc.Namespace, tc.Name, id, store.PodName, mitigation)
+			m.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, slowStoreMitigatedReason,
+				"the slow store %s of pod %s is mitigated by %s", id, store.PodName, mitigation)
+			store.SlowStoreMitigation = mitigation
+			tc.Status.TiKV.Stores[id] = store
+			mitigated++
+		}
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+func applySlowStoreMitigation(pdCli pdapi.PDClient, storeID uint64, mitigation v1alpha1.TiKVSlowStoreMitigation, storeStatus *pdapi.StoreStatus) error {
+	switch mitigation {
+	case v1alpha1.TiKVSlowStoreMitigationLeaderWeight:
+		if storeStatus.LeaderWeight == slowStoreLeaderWeight {
+			return nil
+		}
+		return pdCli.SetStoreWeight(storeID, slowStoreLeaderWeight, storeStatus.RegionWeight)
+	case v1alpha1.TiKVSlowStoreMitigationEvictLeader:
+		return pdCli.BeginEvictLeader(storeID)
+	}
+	return nil
+}
+
+// revertSlowStoreMitigation restores the leader weight by spec.tikv.storeWeights, or ends the leader eviction
+func (m *tikvMemberManager) revertSlowStoreMitigation(tc *v1alpha1.TidbCluster, pdCli pdapi.PDClient, storeID uint64,
+	store v1alpha1.TiKVStore, storeStatus *pdapi.StoreStatus) error {
+	switch store.SlowStoreMitigation {
+	case v1alpha1.TiKVSlowStoreMitigationLeaderWeight:
+		leaderWeight := defaultStoreWeight
+		if len(tc.Spec.TiKV.StoreWeights) > 0 {
+			pod, err := m.deps.PodLister.Pods(tc.Namespace).Get(store.PodName)
+			if err != nil {
+				return fmt.Errorf("get pod %s/%s failed: %v", tc.Namespace, store.PodName, err)
+			}
+			if leaderWeight, _, err = m.storeWeightsOfPod(tc.Spec.TiKV.StoreWeights, pod); err != nil {
+				return err
+			}
+		}
+		return pdCli.SetStoreWeight(storeID, leaderWeight, storeStatus.RegionWeight)
+	case v1alpha1.TiKVSlowStoreMitigationEvictLeader:
+		return pdCli.EndEvictLeader(storeID)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/features"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncSlowStoreStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiKV()
+	tc.Spec.TiKV.SlowStoreDetection = &v1alpha1.TiKVSlowStoreDetection{Duration: &metav1.Duration{Duration: time.Minute}}
+	tmm, _, _, _, _, _ := newFakeTiKVMemberManager(tc)
+
+	store := &pdapi.StoreInfo{
+		Store:  &pdapi.MetaStore{Store: &metapb.Store{Id: 1}, StateName: v1alpha1.TiKVStateUp},
+		Status: &pdapi.StoreStatus{SlowScore: 1},
+	}
+	sync := func(oldStore v1alpha1.TiKVStore) v1alpha1.TiKVStore {
+		status := getTiKVStore(store)
+		tmm.syncSlowStoreStatus(tc, store, status, oldStore)
+		return *status
+	}
+
+	// healthy
+	status := sync(v1alpha1.TiKVStore{})
+	g.Expect(status.SlowSince).To(BeNil())
+	g.Expect(status.Slow).To(BeFalse())
+
+	// the score is high but not long enough
+	store.Status.SlowScore = 90
+	status = sync(status)
+	g.Expect(status.SlowSince).NotTo(BeNil())
+	g.Expect(status.Slow).To(BeFalse())
+
+	// the score stays high longer than the duration
+	status.SlowSince = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	status = sync(status)
+	g.Expect(status.Slow).To(BeTrue())
+
+	// the mitigation is kept after the store recovers, until it's reverted
+	status.SlowStoreMitigation = v1alpha1.TiKVSlowStoreMitigationEvictLeader
+	store.Status.SlowScore = 1
+	status = sync(status)
+	g.Expect(status.SlowSince).To(BeNil())
+	g.Expect(status.Slow).To(BeFalse())
+	g.Expect(status.SlowStoreMitigation).To(Equal(v1alpha1.TiKVSlowStoreMitigationEvictLeader))
+}
+
+func TestSyncSlowStoreMitigation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	features.DefaultFeatureGate.Set("SlowStoreMitigation=true")
+	defer features.DefaultFeatureGate.Set("SlowStoreMitigation=false")
+
+	tc := newTidbClusterForTiKV()
+	tc.Spec.TiKV.SlowStoreDetection = &v1alpha1.TiKVSlowStoreDetection{Mitigation: v1alpha1.TiKVSlowStoreMitigationEvictLeader}
+	slowSince := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: time.Now().Add(-d)}
+	}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: "test-tikv-0", SlowSince: slowSince(time.Hour), Slow: true},
+		"2": {ID: "2", PodName: "test-tikv-1", SlowSince: slowSince(2 * time.Hour), Slow: true},
+		"3": {ID: "3", PodName: "test-tikv-2"},
+	}
+	tmm, _, _, pdClient, _, _ := newFakeTiKVMemberManager(tc)
+
+	stores := &pdapi.StoresInfo{}
+	for id := uint64(1); id <= 3; id++ {
+		stores.Stores = append(stores.Stores, &pdapi.StoreInfo{
+			Store:  &pdapi.MetaStore{Store: &metapb.Store{Id: id}, StateName: v1alpha1.TiKVStateUp},
+			Status: &pdapi.StoreStatus{LeaderWeight: 1, RegionWeight: 1},
+		})
+	}
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return stores, nil
+	})
+	evicting := map[uint64]bool{}
+	pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		evicting[action.ID] = true
+		return nil, nil
+	})
+	pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		delete(evicting, action.ID)
+		return nil, nil
+	})
+
+	// only the store slow for the longest is mitigated
+	g.Expect(tmm.syncSlowStoreMitigation(tc)).To(Succeed())
+	g.Expect(evicting).To(Equal(map[uint64]bool{2: true}))
+	g.Expect(tc.Status.TiKV.Stores["2"].SlowStoreMitigation).To(Equal(v1alpha1.TiKVSlowStoreMitigationEvictLeader))
+	g.Expect(tc.Status.TiKV.Stores["1"].SlowStoreMitigation).To(BeEmpty())
+
+	// the mitigation is not changed during the upgrade
+	store := tc.Status.TiKV.Stores["2"]
+	store.SlowSince, store.Slow = nil, false
+	tc.Status.TiKV.Stores["2"] = store
+	tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	g.Expect(tmm.syncSlowStoreMitigation(tc)).To(Succeed())
+	g.Expect(evicting).To(Equal(map[uint64]bool{2: true}))
+
+	// the mitigation of the recovered store is reverted, and the next slow store is mitigated
+	tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+	g.Expect(tmm.syncSlowStoreMitigation(tc)).To(Succeed())
+	g.Expect(evicting).To(Equal(map[uint64]bool{1: true}))
+	g.Expect(tc.Status.TiKV.Stores["2"].SlowStoreMitigation).To(BeEmpty())
+
+	// the mitigation is reverted if the detection is disabled
+	tc.Spec.TiKV.SlowStoreDetection = nil
+	g.Expect(tmm.syncSlowStoreMitigation(tc)).To(Succeed())
+	g.Expect(evicting).To(BeEmpty())
+	g.Expect(tc.Status.TiKV.Stores["1"].SlowStoreMitigation).To(BeEmpty())
+}
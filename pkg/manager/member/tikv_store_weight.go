@@ -0,0 +1,133 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultStoreWeight is the default leader and region weight of the stores in PD
+	defaultStoreWeight = 1.0
+
+	failedSetStoreWeightReason = "FailedSetStoreWeight"
+)
+
+// syncStoreWeights sets the leader and region weights of the TiKV stores in PD by spec.tikv.storeWeights.
+// The first entry selecting the pod of a store applies, and the stores selected by no entry get the
+// default weights.
+func (m *tikvMemberManager) syncStoreWeights(tc *v1alpha1.TidbCluster) error {
+	weights := tc.Spec.TiKV.StoreWeights
+	if len(weights) == 0 || !tc.TiKVBootStrapped() {
+		return nil
+	}
+	ns := tc.GetNamespace()
+
+	pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		return fmt.Errorf("get stores failed: %v", err)
+	}
+	pattern, err := regexp.Compile(fmt.Sprintf(tikvStoreLimitPattern, tc.Name, tc.Name, tc.Namespace, controller.FormatClusterDomainForRegex(tc.Spec.ClusterDomain)))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || store.Status == nil || !pattern.Match([]byte(store.Store.Address)) ||
+			store.Store.StateName != v1alpha1.TiKVStateUp {
+			continue
+		}
+		status := getTiKVStore(store)
+		if status == nil {
+			continue
+		}
+		// the leader weight of the slow store is managed by the mitigation until it's reverted
+		if tc.Status.TiKV.Stores[status.ID].SlowStoreMitigation == v1alpha1.TiKVSlowStoreMitigationLeaderWeight {
+			continue
+		}
+		pod, err := m.deps.PodLister.Pods(ns).Get(status.PodName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("get pod %s/%s failed: %v", ns, status.PodName, err))
+			continue
+		}
+		leaderWeight, regionWeight, err := m.storeWeightsOfPod(weights, pod)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if store.Status.LeaderWeight == leaderWeight && store.Status.RegionWeight == regionWeight {
+			continue
+		}
+		if err := pdCli.SetStoreWeight(store.Store.Id, leaderWeight, regionWeight); err != nil {
+			msg := fmt.Sprintf("failed to set leader weight %v and region weight %v for store (id: %d, pod: %s/%s): %v",
+				leaderWeight, regionWeight, store.Store.Id, ns, pod.Name, err)
+			m.deps.Recorder.Event(tc, corev1.EventTypeWarning, failedSetStoreWeightReason, msg)
+			errs = append(errs, err)
+			continue
+		}
+		klog.Infof("TidbCluster: [%s/%s]'s store %d of pod %s is set to leader weight %v and region weight %v",
+			ns, tc.GetName(), store.Store.Id, pod.Name, leaderWeight, regionWeight)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// storeWeightsOfPod returns the leader and region weights of the store of the pod by the first entry selecting the pod
+func (m *tikvMemberManager) storeWeightsOfPod(weights []v1alpha1.TiKVStoreWeight, pod *corev1.Pod) (float64, float64, error) {
+	var nodeLabels labels.Set
+	nodeFetched := false
+	for _, w := range weights {
+		matched := false
+		for _, name := range w.PodNames {
+			if name == pod.Name {
+				matched = true
+				break
+			}
+		}
+		if !matched && len(w.NodeSelector) > 0 && pod.Spec.NodeName != "" {
+			if !nodeFetched {
+				if m.deps.NodeLister == nil {
+					return 0, 0, fmt.Errorf("node lister is unavailable to select the store of pod %s/%s by node labels", pod.Namespace, pod.Name)
+				}
+				node, err := m.deps.NodeLister.Get(pod.Spec.NodeName)
+				if err != nil {
+					return 0, 0, fmt.Errorf("get node %s failed: %v", pod.Spec.NodeName, err)
+				}
+				nodeLabels, nodeFetched = labels.Set(node.Labels), true
+			}
+			matched = labels.SelectorFromSet(w.NodeSelector).Matches(nodeLabels)
+		}
+		if !matched {
+			continue
+		}
+		leaderWeight, regionWeight := defaultStoreWeight, defaultStoreWeight
+		if w.LeaderWeight != nil {
+			leaderWeight = *w.LeaderWeight
+		}
+		if w.RegionWeight != nil {
+			regionWeight = *w.RegionWeight
+		}
+		return leaderWeight, regionWeight, nil
+	}
+	return defaultStoreWeight, defaultStoreWeight, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestSyncStoreWeights(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiKV()
+	tc.Status.TiKV.BootStrapped = true
+	tc.Spec.TiKV.StoreWeights = []v1alpha1.TiKVStoreWeight{
+		{PodNames: []string{"test-tikv-0"}, LeaderWeight: pointer.Float64(0.5)},
+		{NodeSelector: map[string]string{"disk": "hdd"}, LeaderWeight: pointer.Float64(0.2), RegionWeight: pointer.Float64(0.5)},
+	}
+	tmm, _, _, pdClient, podIndexer, nodeIndexer := newFakeTiKVMemberManager(tc)
+
+	nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-hdd", Labels: map[string]string{"disk": "hdd"}}})
+	nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-ssd", Labels: map[string]string{"disk": "ssd"}}})
+	nodes := []string{"node-hdd", "node-hdd", "node-ssd"}
+	weights := map[uint64][2]float64{}
+	stores := &pdapi.StoresInfo{}
+	for i, node := range nodes {
+		podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-tikv-%d", i), Namespace: corev1.NamespaceDefault},
+			Spec:       corev1.PodSpec{NodeName: node},
+		})
+		id := uint64(i + 1)
+		weights[id] = [2]float64{1, 1}
+		if i == 2 {
+			// the weights set manually are reset
+			weights[id] = [2]float64{3, 3}
+		}
+		stores.Stores = append(stores.Stores, &pdapi.StoreInfo{
+			Store: &pdapi.MetaStore{
+				Store:     &metapb.Store{Id: id, Address: fmt.Sprintf("test-tikv-%d.test-tikv-peer.default.svc:20160", i)},
+				StateName: v1alpha1.TiKVStateUp,
+			},
+			Status: &pdapi.StoreStatus{},
+		})
+	}
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		for _, s := range stores.Stores {
+			s.Status.LeaderWeight, s.Status.RegionWeight = weights[s.Store.Id][0], weights[s.Store.Id][1]
+		}
+		return stores, nil
+	})
+	setCount := 0
+	pdClient.AddReaction(pdapi.SetStoreWeightActionType, func(action *pdapi.Action) (interface{}, error) {
+		setCount++
+		weights[action.ID] = action.Weights
+		return nil, nil
+	})
+
+	g.Expect(tmm.syncStoreWeights(tc)).To(Succeed())
+	g.Expect(weights).To(Equal(map[uint64][2]float64{
+		1: {0.5, 1}, // the first matched entry applies
+		2: {0.2, 0.5},
+		3: {1, 1},
+	}))
+	g.Expect(setCount).To(Equal(3))
+
+	// the weights are not set again
+	g.Expect(tmm.syncStoreWeights(tc)).To(Succeed())
+	g.Expect(setCount).To(Equal(3))
+
+	// the weights are not managed without the entries
+	tc.Spec.TiKV.StoreWeights = nil
+	weights[1] = [2]float64{2, 2}
+	g.Expect(tmm.syncStoreWeights(tc)).To(Succeed())
+	g.Expect(weights[1]).To(Equal([2]float64{2, 2}))
+
+	// the failures are returned
+	tc.Spec.TiKV.StoreWeights = []v1alpha1.TiKVStoreWeight{{PodNames: []string{"test-tikv-0"}, LeaderWeight: pointer.Float64(0)}}
+	pdClient.AddReaction(pdapi.SetStoreWeightActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, fmt.Errorf("failed to set store weight")
+	})
+	g.Expect(tmm.syncStoreWeights(tc)).To(MatchError(ContainSubstring("failed to set store weight")))
+}
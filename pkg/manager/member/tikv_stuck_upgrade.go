@@ -0,0 +1,175 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// stuckUpgradeReportDelay is the time an upgraded pod is allowed to be unhealthy after it's created
+	// before the upgrade is regarded as stuck
+	stuckUpgradeReportDelay = 5 * time.Minute
+	// stuckUpgradeReportInterval is the interval the report is refreshed at, as it scans the regions of
+	// the stores in PD
+	stuckUpgradeReportInterval = time.Minute
+)
+
+// checkTiKVPodHealthy returns an error if the tikv pod is not available or its store is not up
+func checkTiKVPodHealthy(tc *v1alpha1.TidbCluster, pod *corev1.Pod, store *v1alpha1.TiKVStore, minReadySeconds int) error {
+	if err := isPodAvailable(pod, minReadySeconds, tc); err != nil {
+		return err
+	}
+	if store.State != v1alpha1.TiKVStateUp {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", tc.GetNamespace(), tc.GetName(), pod.GetName())
+	}
+	return nil
+}
+
+// reportStuckUpgrade publishes the safety report of the upgrade blocked on the unhealthy tikv pod in the
+// tc status, the regions at risk are the ones that would lose the quorum if the next pod is upgraded
+// while the blocked one is unavailable. The report is refreshed at most once per stuckUpgradeReportInterval
+// and kept unchanged after the upgrade is forced.
+// Only one report is published at a time, the report of another pod is published after the pod
+// of the current one recovers or the upgrade is done.
+func (u *tikvUpgrader) reportStuckUpgrade(tc *v1alpha1.TidbCluster, pod *corev1.Pod, store *v1alpha1.TiKVStore, next *v1alpha1.TiKVStore, reason error) {
+	if time.Since(pod.CreationTimestamp.Time) < stuckUpgradeReportDelay {
+		return
+	}
+	old := tc.Status.StuckUpgrade
+	if old != nil && old.Component == v1alpha1.TiKVMemberType && (old.Member != pod.Name || old.ForcedTime != nil ||
+		time.Since(old.ReportTime.Time) < stuckUpgradeReportInterval) {
+		return
+	}
+
+	report := &v1alpha1.StuckUpgradeReport{
+		Component: v1alpha1.TiKVMemberType,
+		Member:    pod.Name,
+		StoreID:   store.ID,
+		Reason:    reason.Error(),
+	}
+	var storeIDs []uint64
+	for _, s := range []*v1alpha1.TiKVStore{store, next} {
+		if s == nil {
+			continue
+		}
+		id, err := strconv.ParseUint(s.ID, 10, 64)
+		if err != nil {
+			klog.Warningf("tidbcluster: [%s/%s]'s tikv store %s of pod %s has an invalid id", tc.Namespace, tc.Name, s.ID, s.PodName)
+			return
+		}
+		storeIDs = append(storeIDs, id)
+	}
+	if next != nil {
+		report.NextMember = next.PodName
+	}
+
+	pdClient := controller.GetPDClient(u.deps.PDControl, tc)
+	regions, err := pdapi.GetRegionSafetyReport(pdClient, storeIDs...)
+	if err != nil {
+		klog.Warningf("tidbcluster: [%s/%s] failed to get the region safety report of tikv stores %v: %v", tc.Namespace, tc.Name, storeIDs, err)
+		return
+	}
+	report.MaxReplicas = int32(regions.MaxReplicas)
+	report.Regions = int32(regions.Regions)
+	report.Leaders = int32(regions.Leaders)
+	report.UnderReplicatedRegions = int32(regions.UnderReplicatedRegions)
+	report.RegionsAtRisk = int32(regions.RegionsAtRisk)
+	report.RegionIDsAtRisk = regions.RegionIDsAtRisk
+
+	if old != nil && old.Component == report.Component && old.Member == report.Member {
+		// keep the id so that the annotation set for it stays valid
+		report.ID = old.ID
+	} else {
+		report.ID = fmt.Sprintf("%s-%d", pod.Name, time.Now().Unix())
+	}
+	report.ReportTime = metav1.NewTime(time.Now())
+	tc.Status.StuckUpgrade = report
+}
+
+// isUpgradeForced returns true if the upgrade blocked on the tikv pod is forced to continue by the
+// annotation set to the id of the published report, and records who forced it in the report.
+func (u *tikvUpgrader) isUpgradeForced(tc *v1alpha1.TidbCluster, podName string) bool {
+	report := tc.Status.StuckUpgrade
+	if report == nil || report.Component != v1alpha1.TiKVMemberType || report.Member != podName {
+		return false
+	}
+	if id, ok := tc.Annotations[label.AnnForceContinueUpgradeKey]; !ok || id != report.ID {
+		return false
+	}
+	if report.ForcedTime == nil {
+		now := metav1.NewTime(time.Now())
+		report.ForcedTime = &now
+		report.ForcedBy = tc.Annotations[label.AnnForceContinueUpgradeByKey]
+		klog.Warningf("tidbcluster: [%s/%s]'s tikv upgrade is forced to continue after pod %s by %q, %d regions are at risk",
+			tc.Namespace, tc.Name, podName, report.ForcedBy, report.RegionsAtRisk)
+		u.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "UpgradeForced",
+			"upgrade is forced to continue after unhealthy pod %s by %q, %d regions are at risk", podName, report.ForcedBy, report.RegionsAtRisk)
+	}
+	return true
+}
+
+// clearStuckUpgradeAnnotations removes the annotations forcing the upgrade to continue once there is no
+// report of a stuck tikv upgrade, e.g. the upgrade is done, so that they don't outlive the upgrade.
+func clearStuckUpgradeAnnotations(deps *controller.Dependencies, tc *v1alpha1.TidbCluster) error {
+	if tc.Status.StuckUpgrade != nil {
+		return nil
+	}
+	_, forced := tc.Annotations[label.AnnForceContinueUpgradeKey]
+	_, forcedBy := tc.Annotations[label.AnnForceContinueUpgradeByKey]
+	if !forced && !forcedBy {
+		return nil
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				label.AnnForceContinueUpgradeKey:   nil,
+				label.AnnForceContinueUpgradeByKey: nil,
+			},
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	updated, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Patch(context.TODO(), tc.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("tidbcluster: [%s/%s] failed to remove annotation %s: %v", tc.Namespace, tc.Name, label.AnnForceContinueUpgradeKey, err)
+	}
+	klog.Infof("tidbcluster: [%s/%s] removed annotation %s as the tikv upgrade is not stuck", tc.Namespace, tc.Name, label.AnnForceContinueUpgradeKey)
+	tc.Annotations = updated.Annotations
+	tc.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// clearStuckUpgrade removes the report of the upgrade blocked on the tikv pod after it recovers
+func clearStuckUpgrade(tc *v1alpha1.TidbCluster, podName string) {
+	if report := tc.Status.StuckUpgrade; report != nil && report.Component == v1alpha1.TiKVMemberType && (podName == "" || report.Member == podName) {
+		tc.Status.StuckUpgrade = nil
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestTiKVUpgraderForceContinue(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, pdControl, _, podInformer, _, _ := newTiKVUpgrader()
+
+	tc := newTidbClusterForTiKVUpgrader()
+	tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
+	tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
+	oldSet := oldStatefulSetForTiKVUpgrader()
+	oldSet.Status.CurrentReplicas = 2
+	oldSet.Status.UpdatedReplicas = 1
+	oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(2)
+	newSet := newStatefulSetForTiKVUpgrader()
+
+	// the upgraded pod upgrader-tikv-2 stays unavailable
+	pods := getTiKVPods(oldSet)
+	pods[2].CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	pods[2].Status.Conditions[0].Status = corev1.ConditionFalse
+	for _, pod := range pods {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{Replication: &pdapi.PDReplicationConfig{MaxReplicas: pointer.Uint64Ptr(3)}}, nil
+	})
+	pdClient.AddReaction(pdapi.GetRegionsByCheckActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.RegionsInfo{}, nil
+	})
+	scans := 0
+	pdClient.AddReaction(pdapi.GetStoreRegionsActionType, func(action *pdapi.Action) (interface{}, error) {
+		scans++
+		peers := []pdapi.RegionPeer{{ID: 1, StoreID: 1}, {ID: 2, StoreID: 2}, {ID: 3, StoreID: 3}}
+		return &pdapi.RegionsInfo{Count: 1, Regions: []*pdapi.RegionInfo{{ID: 1, Peers: peers, Leader: peers[0]}}}, nil
+	})
+	var evicting []uint64
+	pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		evicting = append(evicting, action.ID)
+		return nil, nil
+	})
+
+	// the report is published for the blocked pod and the next one
+	err := upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	report := tc.Status.StuckUpgrade
+	g.Expect(report).NotTo(BeNil())
+	g.Expect(report.Component).To(Equal(v1alpha1.TiKVMemberType))
+	g.Expect(report.Member).To(Equal(TikvPodName(upgradeTcName, 2)))
+	g.Expect(report.NextMember).To(Equal(TikvPodName(upgradeTcName, 1)))
+	g.Expect(report.StoreID).To(Equal("3"))
+	g.Expect(report.Regions).To(Equal(int32(1)))
+	g.Expect(report.RegionsAtRisk).To(Equal(int32(1)))
+	g.Expect(report.ForcedTime).To(BeNil())
+	g.Expect(evicting).To(BeEmpty())
+	g.Expect(scans).To(Equal(2))
+
+	// the regions are not scanned again until the report is due to be refreshed
+	err = upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(scans).To(Equal(2))
+	report.ReportTime = metav1.NewTime(time.Now().Add(-stuckUpgradeReportInterval))
+	err = upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(scans).To(Equal(4))
+	g.Expect(tc.Status.StuckUpgrade.ID).To(Equal(report.ID))
+	report = tc.Status.StuckUpgrade
+
+	// the annotation not set to the id of the report is ignored
+	tc.Annotations = map[string]string{label.AnnForceContinueUpgradeKey: report.Member}
+	err = upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(evicting).To(BeEmpty())
+
+	// the upgrade continues to the next pod after it's forced
+	tc.Annotations[label.AnnForceContinueUpgradeKey] = report.ID
+	tc.Annotations[label.AnnForceContinueUpgradeByKey] = "alice"
+	err = upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(evicting).To(Equal([]uint64{2}))
+	g.Expect(tc.Status.StuckUpgrade.ForcedBy).To(Equal("alice"))
+	g.Expect(tc.Status.StuckUpgrade.ForcedTime).NotTo(BeNil())
+	g.Expect(tc.Status.StuckUpgrade.ID).To(Equal(report.ID))
+
+	// the report is removed after the pod recovers
+	pods[2].Status.Conditions[0].Status = corev1.ConditionTrue
+	podInformer.Informer().GetIndexer().Update(pods[2])
+	upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(tc.Status.StuckUpgrade).To(BeNil())
+}
+
+func TestClearStuckUpgradeAnnotations(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+
+	tc := newTidbClusterForTiKVUpgrader()
+	tc.Annotations = map[string]string{
+		label.AnnForceContinueUpgradeKey:   "upgrader-tikv-2-1700000000",
+		label.AnnForceContinueUpgradeByKey: "alice",
+		"foo":                              "bar",
+	}
+	tc.Status.StuckUpgrade = &v1alpha1.StuckUpgradeReport{Component: v1alpha1.TiKVMemberType, Member: "upgrader-tikv-2"}
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the annotations are kept while the upgrade is stuck
+	g.Expect(clearStuckUpgradeAnnotations(deps, tc)).To(Succeed())
+	g.Expect(tc.Annotations).To(HaveKey(label.AnnForceContinueUpgradeKey))
+
+	// the annotations are removed once the upgrade is not stuck
+	tc.Status.StuckUpgrade = nil
+	g.Expect(clearStuckUpgradeAnnotations(deps, tc)).To(Succeed())
+	g.Expect(tc.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+	persisted, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(persisted.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+}
@@ -101,6 +101,10 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		return fmt.Errorf("cluster: [%s/%s]'s tikv status sync failed, can not to be upgraded", ns, tcName)
 	}
 
+	if err := acquireDisruptionLock(u.deps, tc, DisruptionTiKVUpgrade); err != nil {
+		return err
+	}
+
 	status.Phase = v1alpha1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
 		return nil
@@ -117,11 +121,19 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 	}
 
 	minReadySeconds := getMinReadySeconds(tc)
+	oldPartition := *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	partition := tc.Spec.TiKV.RollingUpdateStrategy.GetPartition()
+	maxUnavailable := tc.Spec.TiKV.RollingUpdateStrategy.GetMaxUnavailable(*oldSet.Spec.Replicas)
 
-	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	mngerutils.SetUpgradePartition(newSet, oldPartition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	unavailable := 0
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
+		if i < partition {
+			klog.Infof("tidbcluster: [%s/%s]'s tikv pods with ordinal less than partition %d are kept at the old revision", ns, tcName, partition)
+			break
+		}
 		store := getStoreByOrdinal(meta.GetName(), *status, i)
 		if store == nil {
 			mngerutils.SetUpgradePartition(newSet, i)
@@ -138,37 +150,175 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		}
 
 		if revision == status.StatefulSet.UpdateRevision {
-
-			if err := isPodAvailable(pod, minReadySeconds, tc); err != nil {
-				return err
+			if unhealthy := checkTiKVPodHealthy(tc, pod, store, minReadySeconds); unhealthy != nil {
+				if u.isUpgradeForced(tc, podName) {
+					klog.Warningf("tidbcluster: [%s/%s]'s tikv upgrade skips the unhealthy pod %s as it's forced to continue", ns, tcName, podName)
+					continue
+				}
+				var next *v1alpha1.TiKVStore
+				if _i > 0 && podOrdinals[_i-1] >= partition {
+					next = getStoreByOrdinal(tcName, *status, podOrdinals[_i-1])
+				}
+				u.reportStuckUpgrade(tc, pod, store, next, unhealthy)
+			} else {
+				clearStuckUpgrade(tc, podName)
 			}
-			if store.State != v1alpha1.TiKVStateUp {
-				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", ns, tcName, podName)
+			if err := u.checkTiKVPodUpgraded(tc, pod, store, minReadySeconds); err != nil {
+				if maxUnavailable <= 1 {
+					return err
+				}
+				unavailable++
 			}
+			continue
+		}
 
-			// If pods recreated successfully, endEvictLeader for the store on this Pod.
-			done, err := u.endEvictLeaderAfterUpgrade(tc, pod)
-			if err != nil {
-				return err
-			}
-			if !done {
-				return controller.RequeueErrorf("waiting to end evict leader of pod %s for tc %s/%s", podName, ns, tcName)
+		if maxUnavailable > 1 && i >= oldPartition {
+			// the leaders of the pods above the partition have been evicted, and the statefulset controller
+			// upgrades them one by one, delete the pod to upgrade it in parallel with the others
+			unavailable++
+			if pod.DeletionTimestamp == nil {
+				if err := u.deps.PodControl.DeletePod(tc, pod); err != nil {
+					return err
+				}
 			}
-
 			continue
 		}
+		if unavailable > 0 {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tikv is upgrading %d pods, wait for them to be available", ns, tcName, unavailable)
+		}
 
 		// verify that cluster is stable before each node upgrade
 		if unstableReason := u.isClusterStable(tc); unstableReason != "" {
 			return controller.RequeueErrorf("cluster is unstable: %s", unstableReason)
 		}
 
-		return u.upgradeTiKVPod(tc, i, newSet)
+		if maxUnavailable <= 1 {
+			return u.upgradeTiKVPod(tc, i, newSet)
+		}
+		ordinals, err := u.getTiKVUpgradeBatch(tc, *status, podOrdinals[:_i+1], partition, maxUnavailable)
+		if err != nil {
+			return err
+		}
+		return u.upgradeTiKVPods(tc, ordinals, newSet)
 	}
 
+	if unavailable > 0 {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tikv is upgrading %d pods, wait for them to be available", ns, tcName, unavailable)
+	}
 	return nil
 }
 
+// checkTiKVPodUpgraded returns an error if the upgraded tikv pod is not available yet, and ends
+// the leader eviction of the store after it's available
+func (u *tikvUpgrader) checkTiKVPodUpgraded(tc *v1alpha1.TidbCluster, pod *corev1.Pod, store *v1alpha1.TiKVStore, minReadySeconds int) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := pod.GetName()
+
+	if err := checkTiKVPodHealthy(tc, pod, store, minReadySeconds); err != nil {
+		return err
+	}
+
+	// If pods recreated successfully, endEvictLeader for the store on this Pod.
+	done, err := u.endEvictLeaderAfterUpgrade(tc, pod)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return controller.RequeueErrorf("waiting to end evict leader of pod %s for tc %s/%s", podName, ns, tcName)
+	}
+	return nil
+}
+
+// getTiKVUpgradeBatch returns the ordinals of the tikv pods to be upgraded at the same time, which are
+// taken from the end of the candidates in descending order, as the statefulset upgrades the pods by partition.
+// The batch is extended only if the majority of replicas of every region is kept available by the stores
+// out of it, that is, PD isolates the replicas by the first location label and the stores in the batch
+// are located in no more than (max-replicas - 1) / 2 of the isolated domains.
+func (u *tikvUpgrader) getTiKVUpgradeBatch(tc *v1alpha1.TidbCluster, status v1alpha1.TiKVStatus, candidates []int32, partition int32, maxUnavailable int) ([]int32, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	ordinals := []int32{candidates[len(candidates)-1]}
+
+	pdClient := controller.GetPDClient(u.deps.PDControl, tc)
+	config, err := pdClient.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("tikvUpgrader: failed to get pd config for tc %s/%s, error: %s", ns, tcName, err)
+	}
+	if config.Replication == nil || len(config.Replication.LocationLabels) == 0 {
+		klog.Infof("tidbcluster: [%s/%s]'s pd has no location labels, upgrade tikv pods one by one", ns, tcName)
+		return ordinals, nil
+	}
+	maxReplicas := 3
+	if config.Replication.MaxReplicas != nil {
+		maxReplicas = int(*config.Replication.MaxReplicas)
+	}
+	maxDomains := (maxReplicas - 1) / 2
+	if maxDomains < 1 {
+		return ordinals, nil
+	}
+
+	storesInfo, err := pdClient.GetStores()
+	if err != nil {
+		return nil, fmt.Errorf("tikvUpgrader: failed to get stores for tc %s/%s, error: %s", ns, tcName, err)
+	}
+	locationLabel := config.Replication.LocationLabels[0]
+	storeDomains := map[string]string{}
+	upDomains := map[string]struct{}{}
+	for _, s := range storesInfo.Stores {
+		if s.Store == nil || s.Store.Store == nil {
+			continue
+		}
+		for _, l := range s.Store.Labels {
+			if l.Key == locationLabel {
+				storeDomains[strconv.FormatUint(s.Store.Id, 10)] = l.Value
+				if s.Store.StateName == v1alpha1.TiKVStateUp {
+					upDomains[l.Value] = struct{}{}
+				}
+			}
+		}
+	}
+	if len(upDomains) < maxReplicas {
+		klog.Infof("tidbcluster: [%s/%s]'s tikv stores are located in %d domains of label %s, less than max-replicas %d, upgrade tikv pods one by one",
+			ns, tcName, len(upDomains), locationLabel, maxReplicas)
+		return ordinals, nil
+	}
+
+	batch := []int32{}
+	domains := map[string]struct{}{}
+	for k := len(candidates) - 1; k >= 0 && len(batch) < maxUnavailable; k-- {
+		i := candidates[k]
+		if i < partition {
+			break
+		}
+		store := getStoreByOrdinal(tcName, status, i)
+		if store == nil {
+			break
+		}
+		domain, ok := storeDomains[store.ID]
+		if !ok {
+			break
+		}
+		domains[domain] = struct{}{}
+		if len(domains) > maxDomains {
+			break
+		}
+		podName := TikvPodName(tcName, i)
+		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return nil, fmt.Errorf("tikvUpgrader: failed to get pod %s for tc %s/%s, error: %s", podName, ns, tcName, err)
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] == status.StatefulSet.UpdateRevision {
+			break
+		}
+		batch = append(batch, i)
+	}
+	if len(batch) == 0 {
+		return ordinals, nil
+	}
+	return batch, nil
+}
+
 func (u *tikvUpgrader) isClusterStable(tc *v1alpha1.TidbCluster) string {
 	if check, ok := tc.Annotations[annoKeyTiKVStoreStateCheck]; ok && check == "true" {
 		return pdapi.IsTiKVStable(controller.GetPDClient(u.deps.PDControl, tc))
@@ -201,10 +351,77 @@ func (u *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32, n
 		return controller.RequeueErrorf("upgradeTiKVPod: modifying volumes of pod %s for tc %s/%s", upgradePodName, ns, tcName)
 	}
 
+	if err := u.checkPodsDeletable(tc, []*corev1.Pod{upgradePod}); err != nil {
+		return err
+	}
+
 	mngerutils.SetUpgradePartition(newSet, ordinal)
 	return nil
 }
 
+// upgradeTiKVPods upgrades the tikv pods at the same time after the leaders of all of them are evicted
+func (u *tikvUpgrader) upgradeTiKVPods(tc *v1alpha1.TidbCluster, ordinals []int32, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	var pods []*corev1.Pod
+	for _, ordinal := range ordinals {
+		podName := TikvPodName(tcName, ordinal)
+		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return fmt.Errorf("upgradeTiKVPods: failed to get pod %s for tc %s/%s, error: %s", podName, ns, tcName, err)
+		}
+		pods = append(pods, pod)
+	}
+
+	allDone := true
+	for _, pod := range pods {
+		done, err := u.evictLeaderBeforeUpgrade(tc, pod)
+		if err != nil {
+			return fmt.Errorf("upgradeTiKVPods: failed to evict leader of pod %s for tc %s/%s, error: %s", pod.Name, ns, tcName, err)
+		}
+		allDone = allDone && done
+	}
+	if !allDone {
+		return controller.RequeueErrorf("upgradeTiKVPods: evicting leaders of pods %v for tc %s/%s", ordinals, ns, tcName)
+	}
+
+	for _, pod := range pods {
+		done, err := u.modifyVolumesBeforeUpgrade(tc, pod)
+		if err != nil {
+			return fmt.Errorf("upgradeTiKVPods: failed to modify volumes of pod %s for tc %s/%s, error: %s", pod.Name, ns, tcName, err)
+		}
+		allDone = allDone && done
+	}
+	if !allDone {
+		return controller.RequeueErrorf("upgradeTiKVPods: modifying volumes of pods %v for tc %s/%s", ordinals, ns, tcName)
+	}
+
+	if err := u.checkPodsDeletable(tc, pods); err != nil {
+		return err
+	}
+
+	mngerutils.SetUpgradePartition(newSet, ordinals[len(ordinals)-1])
+	return nil
+}
+
+// checkPodsDeletable returns a requeue error if the regions in PD are not safe to delete the tikv pods at the same time
+func (u *tikvUpgrader) checkPodsDeletable(tc *v1alpha1.TidbCluster, pods []*corev1.Pod) error {
+	var storeIDs []uint64
+	for _, pod := range pods {
+		storeID, err := TiKVStoreIDFromStatus(tc, pod.Name)
+		if err != nil {
+			return err
+		}
+		storeIDs = append(storeIDs, storeID)
+	}
+	pdClient := controller.GetPDClient(u.deps.PDControl, tc)
+	if reason := pdapi.CheckStoresDeletable(pdClient, tc.TiKVDeletionSafetyLevel(), storeIDs...); reason != "" {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tikv stores %v can not be upgraded now: %s", tc.GetNamespace(), tc.GetName(), storeIDs, reason)
+	}
+	return nil
+}
+
 func (u *tikvUpgrader) evictLeaderBeforeUpgrade(tc *v1alpha1.TidbCluster, upgradePod *corev1.Pod) (bool, error) {
 	logPrefix := fmt.Sprintf("evictLeaderBeforeUpgrade: for tikv pod %s/%s", upgradePod.Namespace, upgradePod.Name)
 
@@ -586,9 +803,26 @@ func (u *tikvUpgrader) isTiKVReadyToUpgrade(tc *v1alpha1.TidbCluster) string {
 	if tc.TiKVScaling() {
 		return fmt.Sprintf("tikv status is %s", tc.Status.TiKV.Phase)
 	}
+	if tc.TiKVWitnessUpgradingOrScaling() {
+		return fmt.Sprintf("tikv witness status is %s", tc.Status.TiKV.Witness.Phase)
+	}
 	return ""
 }
 
+// EvictLeaderBeginTime returns the time when the operator began to evict region leaders
+// of the TiKV pod before upgrading it, the second return value is false if the pod is not evicting.
+func EvictLeaderBeginTime(pod *corev1.Pod) (time.Time, bool) {
+	beginTimeStr, evicting := pod.Annotations[annoKeyEvictLeaderBeginTime]
+	if !evicting {
+		return time.Time{}, false
+	}
+	beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return beginTime, true
+}
+
 type fakeTiKVUpgrader struct{}
 
 // NewFakeTiKVUpgrader returns a fake tikv upgrader
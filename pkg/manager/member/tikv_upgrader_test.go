@@ -19,6 +19,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
@@ -1085,6 +1086,62 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 	}
 }
 
+func TestGetTiKVUpgradeBatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	upgrader, pdControl, _, podInformer, _, _ := newTiKVUpgrader()
+	tc := newTidbClusterForTiKVUpgrader()
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	oldSet := oldStatefulSetForTiKVUpgrader()
+	for _, pod := range getTiKVPods(oldSet) {
+		g.Expect(podInformer.Informer().GetIndexer().Add(pod)).To(Succeed())
+	}
+
+	var locationLabels []string
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{
+			Replication: &pdapi.PDReplicationConfig{LocationLabels: locationLabels, MaxReplicas: pointer.Uint64Ptr(3)},
+		}, nil
+	})
+	zones := map[uint64]string{1: "b", 2: "a", 3: "a", 4: "c"}
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		storesInfo := &pdapi.StoresInfo{}
+		for id, zone := range zones {
+			storesInfo.Stores = append(storesInfo.Stores, &pdapi.StoreInfo{
+				Store: &pdapi.MetaStore{
+					Store:     &metapb.Store{Id: id, Labels: []*metapb.StoreLabel{{Key: "zone", Value: zone}}},
+					StateName: v1alpha1.TiKVStateUp,
+				},
+			})
+		}
+		return storesInfo, nil
+	})
+
+	u := upgrader.(*tikvUpgrader)
+	candidates := []int32{0, 1, 2}
+
+	// upgrade one by one without location labels
+	ordinals, err := u.getTiKVUpgradeBatch(tc, tc.Status.TiKV, candidates, 0, 3)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ordinals).To(Equal([]int32{2}))
+
+	// the stores in the same zone are upgraded together
+	locationLabels = []string{"zone", "host"}
+	ordinals, err = u.getTiKVUpgradeBatch(tc, tc.Status.TiKV, candidates, 0, 3)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ordinals).To(Equal([]int32{2, 1}))
+
+	ordinals, err = u.getTiKVUpgradeBatch(tc, tc.Status.TiKV, candidates, 2, 3)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ordinals).To(Equal([]int32{2}))
+
+	// the replicas can't be isolated by zones
+	delete(zones, 4)
+	ordinals, err = u.getTiKVUpgradeBatch(tc, tc.Status.TiKV, candidates, 0, 3)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ordinals).To(Equal([]int32{2}))
+}
+
 func newTiKVUpgrader() (TiKVUpgrader, *pdapi.FakePDControl, *controller.FakePodControl, podinformers.PodInformer, *tikvapi.FakeTiKVControl, *volumes.FakePodVolumeModifier) {
 	fakeDeps := controller.NewFakeDependencies()
 	pdControl := fakeDeps.PDControl.(*pdapi.FakePDControl)
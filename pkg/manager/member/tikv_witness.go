@@ -0,0 +1,516 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/manager/member/startscript"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// witnessStoreLabelKey is the label of the witness stores in PD, the placement rules
+	// use it to place the witness replicas on the witness stores only
+	witnessStoreLabelKey = "witness"
+	witnessStoreLabelVal = "true"
+
+	tikvWitnessStorePattern = `%s-tikv-witness-\d+\.%s-tikv-witness-peer\.%s\.svc%s\:\d+`
+
+	placementRuleGroupPD   = "pd"
+	defaultPlacementRuleID = "default"
+	witnessPlacementRuleID = "witness"
+)
+
+// isWitnessStore returns whether the store is a TiKV witness store
+func isWitnessStore(storeLabels []*metapb.StoreLabel) bool {
+	for _, storeLabel := range storeLabels {
+		if storeLabel.Key == witnessStoreLabelKey && storeLabel.Value == witnessStoreLabelVal {
+			return true
+		}
+	}
+	return false
+}
+
+// syncWitness syncs the TiKV witness stores, which run in a separate statefulset so that they
+// can be sized and scheduled independently of the other TiKV stores.
+func (m *tikvMemberManager) syncWitness(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiKV.Witness == nil {
+		return nil
+	}
+	if tc.Status.TiKV.Witness == nil {
+		tc.Status.TiKV.Witness = &v1alpha1.TiKVWitnessStatus{}
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	svc := SvcConfig{
+		Name:       "peer",
+		Port:       v1alpha1.DefaultTiKVServerPort,
+		Headless:   true,
+		SvcLabel:   func(l label.Label) label.Label { return l.TiKVWitness() },
+		MemberName: controller.TiKVWitnessPeerMemberName,
+	}
+	if err := m.syncServiceForTidbCluster(tc, svc); err != nil {
+		return err
+	}
+
+	oldSetTmp, err := m.deps.StatefulSetLister.StatefulSets(ns).Get(controller.TiKVWitnessMemberName(tcName))
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("syncWitness: failed to get sts %s for cluster %s/%s, error: %s", controller.TiKVWitnessMemberName(tcName), ns, tcName, err)
+	}
+	setNotExist := errors.IsNotFound(err)
+
+	oldSet := oldSetTmp.DeepCopy()
+
+	if err := m.syncWitnessStatus(tc, oldSet); err != nil {
+		return err
+	}
+
+	if tc.Spec.Paused {
+		klog.V(4).Infof("tikv cluster %s/%s is paused, skip syncing for tikv witness statefulset", ns, tcName)
+		return nil
+	}
+
+	// the witness replicas can only be placed after the regions are created
+	if !tc.TiKVBootStrapped() {
+		return controller.RequeueErrorf("TidbCluster: [%s/%s], waiting for TiKV bootstrapped before creating witness stores", ns, tcName)
+	}
+
+	cm, err := m.syncTiKVWitnessConfigMap(tc, oldSet)
+	if err != nil {
+		return err
+	}
+
+	newSet, err := getNewTiKVWitnessSetForTidbCluster(tc, cm)
+	if err != nil {
+		return err
+	}
+	if setNotExist {
+		err = mngerutils.SetStatefulSetLastAppliedConfigAnnotation(newSet)
+		if err != nil {
+			return err
+		}
+		err = m.deps.StatefulSetControl.CreateStatefulSet(tc, newSet)
+		if err != nil {
+			return err
+		}
+		tc.Status.TiKV.Witness.StatefulSet = &apps.StatefulSetStatus{}
+		return nil
+	}
+
+	if err := m.syncWitnessPlacementRules(tc); err != nil {
+		return err
+	}
+
+	if err := m.scaleWitness(tc, oldSet, newSet); err != nil {
+		return err
+	}
+
+	// the witness stores are upgraded after the other TiKV stores, so that there are always
+	// enough replicas of each region when a store is restarted
+	if !templateEqual(newSet, oldSet) && (tc.TiKVUpgrading() || tc.TiKVScaling()) {
+		klog.Infof("TidbCluster: [%s/%s], can not upgrade tikv witness because tikv status is %s", ns, tcName, tc.Status.TiKV.Phase)
+		_, podSpec, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return err
+		}
+		newSet.Spec.Template.Spec = *podSpec
+	}
+
+	return mngerutils.UpdateStatefulSetWithPrecheck(m.deps, tc, "FailedUpdateTiKVWitnessSTS", newSet, oldSet)
+}
+
+func (m *tikvMemberManager) syncWitnessStatus(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
+	if set == nil {
+		// skip if not created yet
+		return nil
+	}
+	status := tc.Status.TiKV.Witness
+	status.StatefulSet = &set.Status
+
+	// Scaling takes precedence over upgrading.
+	if tc.Spec.TiKV.Witness.Replicas != *set.Spec.Replicas {
+		status.Phase = v1alpha1.ScalePhase
+	} else if mngerutils.StatefulSetIsUpgrading(set) {
+		status.Phase = v1alpha1.UpgradePhase
+	} else {
+		status.Phase = v1alpha1.NormalPhase
+	}
+
+	if !tc.TiKVBootStrapped() {
+		return nil
+	}
+
+	pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+	// This only returns Up/Down/Offline stores
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		return err
+	}
+	pattern, err := regexp.Compile(fmt.Sprintf(tikvWitnessStorePattern, tc.Name, tc.Name, tc.Namespace, controller.FormatClusterDomainForRegex(tc.Spec.ClusterDomain)))
+	if err != nil {
+		return err
+	}
+
+	previousStores := status.Stores
+	stores := map[string]v1alpha1.TiKVStore{}
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || !pattern.Match([]byte(store.Store.Address)) {
+			continue
+		}
+		storeStatus := getTiKVStore(store)
+		if storeStatus == nil {
+			continue
+		}
+		storeStatus.LastTransitionTime = metav1.Now()
+		if oldStore, exist := previousStores[storeStatus.ID]; exist && storeStatus.State == oldStore.State {
+			storeStatus.LastTransitionTime = oldStore.LastTransitionTime
+		}
+		stores[storeStatus.ID] = *storeStatus
+	}
+	status.Stores = stores
+	return nil
+}
+
+func (m *tikvMemberManager) syncTiKVWitnessConfigMap(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
+	newCm, err := getTiKVWitnessConfigMap(m.deps.ConfigDefaults.Apply(tc, v1alpha1.TiKVMemberType))
+	if err != nil || newCm == nil {
+		return nil, err
+	}
+
+	var inUseName string
+	if set != nil {
+		inUseName = mngerutils.FindConfigMapVolume(&set.Spec.Template.Spec, func(name string) bool {
+			return strings.HasPrefix(name, controller.TiKVWitnessMemberName(tc.Name))
+		})
+	}
+
+	err = mngerutils.UpdateConfigMapIfNeed(m.deps.ConfigMapLister, tc.BaseTiKVSpec().ConfigUpdateStrategy(), inUseName, newCm)
+	if err != nil {
+		return nil, err
+	}
+	return m.deps.TypedControl.CreateOrUpdateConfigMap(tc, newCm)
+}
+
+// syncWitnessPlacementRules places one voter replica of each region on the witness stores as a witness,
+// and the other replicas on the other stores. The rules are applied only after a witness store is up,
+// and removed when the witness stores are scaled in to 0.
+func (m *tikvMemberManager) syncWitnessPlacementRules(tc *v1alpha1.TidbCluster) error {
+	status := tc.Status.TiKV.Witness
+	pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+
+	if tc.Spec.TiKV.Witness.Replicas == 0 {
+		if !status.PlacementRulesApplied {
+			return nil
+		}
+		klog.Infof("TidbCluster: [%s/%s], remove the placement rules of the witness replicas", tc.Namespace, tc.Name)
+		if err := removeWitnessPlacementRules(pdCli); err != nil {
+			return err
+		}
+		status.PlacementRulesApplied = false
+		return nil
+	}
+
+	if status.PlacementRulesApplied {
+		return nil
+	}
+	upStores := 0
+	for _, store := range status.Stores {
+		if store.State == v1alpha1.TiKVStateUp {
+			upStores++
+		}
+	}
+	if upStores == 0 {
+		klog.Infof("TidbCluster: [%s/%s], waiting for the witness stores up before applying the placement rules", tc.Namespace, tc.Name)
+		return nil
+	}
+
+	config, err := pdCli.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.Replication != nil && config.Replication.EnablePlacementRules != nil && !*config.Replication.EnablePlacementRules {
+		klog.Infof("Cluster %s/%s enable-placement-rules is false, set it to true", tc.Namespace, tc.Name)
+		if err := pdCli.UpdateReplicationConfig(pdapi.PDReplicationConfig{EnablePlacementRules: pointer.BoolPtr(true)}); err != nil {
+			return err
+		}
+	}
+	if config.Schedule == nil || config.Schedule.EnableWitness == nil || !*config.Schedule.EnableWitness {
+		klog.Infof("Cluster %s/%s enable-witness is not true, set it to true", tc.Namespace, tc.Name)
+		if err := pdCli.UpdateScheduleConfig(pdapi.PDScheduleConfig{EnableWitness: pointer.BoolPtr(true)}); err != nil {
+			return err
+		}
+	}
+
+	var locationLabels []string
+	if config.Replication != nil {
+		locationLabels = config.Replication.LocationLabels
+	}
+	defaultRule, witnessRule, err := getWitnessPlacementRules(pdCli, locationLabels)
+	if err != nil {
+		return err
+	}
+	// add the witness replica before removing a full replica, so that there are always
+	// enough replicas of each region
+	if err := pdCli.SetPlacementRule(witnessRule); err != nil {
+		return err
+	}
+	if err := pdCli.SetPlacementRule(defaultRule); err != nil {
+		return err
+	}
+	klog.Infof("TidbCluster: [%s/%s], the placement rules of the witness replicas are applied", tc.Namespace, tc.Name)
+	status.PlacementRulesApplied = true
+	return nil
+}
+
+// getWitnessPlacementRules returns the default rule excluding the witness stores and the rule
+// placing the witness replicas on the witness stores.
+func getWitnessPlacementRules(pdCli pdapi.PDClient, locationLabels []string) (defaultRule, witnessRule *pdapi.PlacementRule, err error) {
+	defaultRule, err = pdCli.GetPlacementRule(placementRuleGroupPD, defaultPlacementRuleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if defaultRule == nil {
+		return nil, nil, fmt.Errorf("placement rule %s/%s not found", placementRuleGroupPD, defaultPlacementRuleID)
+	}
+	if !hasWitnessConstraint(defaultRule, "notIn") {
+		if defaultRule.Count < 2 {
+			return nil, nil, fmt.Errorf("placement rule %s/%s has %d replicas, at least 2 replicas are required to place a witness replica",
+				placementRuleGroupPD, defaultPlacementRuleID, defaultRule.Count)
+		}
+		defaultRule.Count--
+		defaultRule.LabelConstraints = append(defaultRule.LabelConstraints, pdapi.LabelConstraint{
+			Key: witnessStoreLabelKey, Op: "notIn", Values: []string{witnessStoreLabelVal},
+		})
+	}
+
+	witnessRule = &pdapi.PlacementRule{
+		GroupID:     placementRuleGroupPD,
+		ID:          witnessPlacementRuleID,
+		StartKeyHex: defaultRule.StartKeyHex,
+		EndKeyHex:   defaultRule.EndKeyHex,
+		Role:        "voter",
+		IsWitness:   true,
+		Count:       1,
+		LabelConstraints: []pdapi.LabelConstraint{
+			{Key: witnessStoreLabelKey, Op: "in", Values: []string{witnessStoreLabelVal}},
+		},
+		LocationLabels: locationLabels,
+	}
+	return defaultRule, witnessRule, nil
+}
+
+// removeWitnessPlacementRules restores the full replica of the default rule before removing the witness rule
+func removeWitnessPlacementRules(pdCli pdapi.PDClient) error {
+	defaultRule, err := pdCli.GetPlacementRule(placementRuleGroupPD, defaultPlacementRuleID)
+	if err != nil {
+		return err
+	}
+	if defaultRule != nil && hasWitnessConstraint(defaultRule, "notIn") {
+		constraints := make([]pdapi.LabelConstraint, 0, len(defaultRule.LabelConstraints))
+		for _, c := range defaultRule.LabelConstraints {
+			if c.Key != witnessStoreLabelKey {
+				constraints = append(constraints, c)
+			}
+		}
+		defaultRule.LabelConstraints = constraints
+		defaultRule.Count++
+		if err := pdCli.SetPlacementRule(defaultRule); err != nil {
+			return err
+		}
+	}
+	return pdCli.DeletePlacementRule(placementRuleGroupPD, witnessPlacementRuleID)
+}
+
+func hasWitnessConstraint(rule *pdapi.PlacementRule, op string) bool {
+	for _, c := range rule.LabelConstraints {
+		if c.Key == witnessStoreLabelKey && c.Op == op {
+			return true
+		}
+	}
+	return false
+}
+
+// scaleWitness scales the witness stores one by one, the store is deleted from PD and
+// becomes tombstone before its pod is removed.
+func (m *tikvMemberManager) scaleWitness(tc *v1alpha1.TidbCluster, oldSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	status := tc.Status.TiKV.Witness
+
+	scaling, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	if scaling == 0 {
+		return nil
+	}
+	pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, oldSet.Name, ordinal)
+
+	if scaling > 0 {
+		pvc, err := m.deps.PVCLister.PersistentVolumeClaims(ns).Get(pvcName)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("scaleWitness: failed to get pvc %s for cluster %s/%s, error: %s", pvcName, ns, tcName, err)
+		}
+		if err == nil {
+			if _, ok := pvc.Annotations[label.AnnPVCDeferDeleting]; ok {
+				resetReplicas(newSet, oldSet)
+				if err := m.deps.PVCControl.DeletePVC(tc, pvc); err != nil {
+					return err
+				}
+				return controller.RequeueErrorf("tikv witness scale out, cluster %s/%s deleted the defer deleting pvc %s, wait for next round", ns, tcName, pvcName)
+			}
+		}
+		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+		return nil
+	}
+
+	resetReplicas(newSet, oldSet)
+	podName := fmt.Sprintf("%s-%d", oldSet.Name, ordinal)
+	upStores := 0
+	for _, store := range status.Stores {
+		if store.State == v1alpha1.TiKVStateUp && store.PodName != podName {
+			upStores++
+		}
+	}
+	if status.PlacementRulesApplied && upStores == 0 {
+		return fmt.Errorf("can't scale in tikv witness of TidbCluster [%s/%s], no witness store would be up for the witness replicas, "+
+			"set the replicas to 0 to remove the placement rules first", ns, tcName)
+	}
+
+	for _, store := range status.Stores {
+		if store.PodName != podName {
+			continue
+		}
+		if store.State == v1alpha1.TiKVStateUp {
+			id, err := strconv.ParseUint(store.ID, 10, 64)
+			if err != nil {
+				return err
+			}
+			pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+			if err := pdCli.DeleteStore(id); err != nil {
+				return err
+			}
+			klog.Infof("tikv witness scale in: delete store %d for pod %s/%s successfully", id, ns, podName)
+		}
+		return controller.RequeueErrorf("tikv witness scale in, waiting for store %s of pod %s/%s to be tombstone", store.ID, ns, podName)
+	}
+
+	pvc, err := m.deps.PVCLister.PersistentVolumeClaims(ns).Get(pvcName)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("scaleWitness: failed to get pvc %s for cluster %s/%s, error: %s", pvcName, ns, tcName, err)
+	}
+	if err == nil {
+		if err := addDeferDeletingAnnoToPVC(tc, pvc.DeepCopy(), m.deps.PVCControl); err != nil {
+			return err
+		}
+	}
+	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+	return nil
+}
+
+func getTiKVWitnessConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
+	cm, err := getTikVConfigMap(tc)
+	if err != nil || cm == nil {
+		return nil, err
+	}
+	startScript, err := startscript.RenderTiKVWitnessStartScript(tc)
+	if err != nil {
+		return nil, fmt.Errorf("render start-script of tikv witness for tc %s/%s failed: %v", tc.Namespace, tc.Name, err)
+	}
+	cm.Data["startup-script"] = startScript
+	cm.Name = controller.TiKVWitnessMemberName(tc.Name)
+	cm.Labels = label.New().Instance(tc.GetInstanceName()).TiKVWitness().Labels()
+	return cm, nil
+}
+
+func getNewTiKVWitnessSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+	if cm == nil {
+		return nil, fmt.Errorf("the config of tikv is required by the tikv witness stores, tidbcluster %s/%s", tc.Namespace, tc.Name)
+	}
+	set, err := getNewTiKVSetForTidbCluster(tc, cm)
+	if err != nil {
+		return nil, err
+	}
+	witness := tc.Spec.TiKV.Witness
+
+	stsLabels := label.New().Instance(tc.GetInstanceName()).TiKVWitness()
+	set.Name = controller.TiKVWitnessMemberName(tc.Name)
+	set.Labels = stsLabels.Labels()
+	// the delete slots of tikv are not applied to the witness stores
+	delete(set.Annotations, helper.DeleteSlotsAnn)
+	set.Spec.Selector = stsLabels.LabelSelector()
+	set.Spec.Template.Labels = util.CombineStringMap(stsLabels.Labels(), tc.BaseTiKVSpec().Labels())
+	set.Spec.ServiceName = controller.TiKVWitnessPeerMemberName(tc.Name)
+	set.Spec.Replicas = pointer.Int32Ptr(witness.Replicas)
+	if set.Spec.UpdateStrategy.RollingUpdate != nil {
+		set.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(0)
+	}
+
+	overrideResources := len(witness.Requests) > 0 || len(witness.Limits) > 0
+	for i := range set.Spec.Template.Spec.Containers {
+		c := &set.Spec.Template.Spec.Containers[i]
+		if c.Name != v1alpha1.TiKVMemberType.String() {
+			continue
+		}
+		if overrideResources {
+			c.Resources = controller.ContainerResource(witness.ResourceRequirements)
+		}
+		for j := range c.Env {
+			switch c.Env[j].Name {
+			case "HEADLESS_SERVICE_NAME":
+				c.Env[j].Value = controller.TiKVWitnessPeerMemberName(tc.Name)
+			case "CAPACITY":
+				if overrideResources {
+					c.Env[j].Value = controller.TiKVCapacity(witness.Limits)
+				}
+			}
+		}
+		c.Env = util.AppendOverwriteEnv(c.Env, []corev1.EnvVar{{
+			Name:  "STORE_LABELS",
+			Value: fmt.Sprintf("%s=%s", witnessStoreLabelKey, witnessStoreLabelVal),
+		}})
+	}
+
+	dataVolumeName := string(v1alpha1.GetStorageVolumeName("", v1alpha1.TiKVMemberType))
+	for i := range set.Spec.VolumeClaimTemplates {
+		pvc := &set.Spec.VolumeClaimTemplates[i]
+		if pvc.Name != dataVolumeName {
+			continue
+		}
+		if storage, ok := witness.Requests[corev1.ResourceStorage]; ok {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: storage}
+		}
+		if witness.StorageClassName != nil {
+			pvc.Spec.StorageClassName = witness.StorageClassName
+		}
+	}
+	return set, nil
+}
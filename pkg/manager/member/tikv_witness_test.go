@@ -0,0 +1,132 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetNewTiKVWitnessSetForTidbCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiKV()
+	tc.Spec.TiKV.Witness = &v1alpha1.TiKVWitnessSpec{
+		ResourceRequirements: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:     resource.MustParse("500m"),
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			},
+		},
+		Replicas:         2,
+		StorageClassName: pointer.StringPtr("witness-storage-class"),
+	}
+
+	cm, err := getTiKVWitnessConfigMap(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Name).To(Equal("test-tikv-witness"))
+	g.Expect(cm.Labels[label.ComponentLabelKey]).To(Equal(label.TiKVWitnessLabelVal))
+	g.Expect(cm.Data["startup-script"]).NotTo(BeEmpty())
+
+	set, err := getNewTiKVWitnessSetForTidbCluster(tc, cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Name).To(Equal("test-tikv-witness"))
+	g.Expect(set.Labels[label.ComponentLabelKey]).To(Equal(label.TiKVWitnessLabelVal))
+	g.Expect(set.Spec.Selector.MatchLabels[label.ComponentLabelKey]).To(Equal(label.TiKVWitnessLabelVal))
+	g.Expect(set.Spec.Template.Labels[label.ComponentLabelKey]).To(Equal(label.TiKVWitnessLabelVal))
+	g.Expect(set.Spec.ServiceName).To(Equal("test-tikv-witness-peer"))
+	g.Expect(*set.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(*set.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(0)))
+	for _, vol := range set.Spec.Template.Spec.Volumes {
+		if vol.ConfigMap != nil {
+			g.Expect(vol.ConfigMap.Name).To(Equal("test-tikv-witness"))
+		}
+	}
+
+	c := set.Spec.Template.Spec.Containers[0]
+	g.Expect(c.Resources.Requests.Cpu().String()).To(Equal("500m"))
+	g.Expect(c.Env).To(ContainElements(
+		corev1.EnvVar{Name: "HEADLESS_SERVICE_NAME", Value: "test-tikv-witness-peer"},
+		corev1.EnvVar{Name: "STORE_LABELS", Value: "witness=true"},
+	))
+
+	pvc := set.Spec.VolumeClaimTemplates[0]
+	g.Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+	g.Expect(*pvc.Spec.StorageClassName).To(Equal("witness-storage-class"))
+
+	// the config of tikv is required
+	_, err = getNewTiKVWitnessSetForTidbCluster(tc, nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestWitnessPlacementRules(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rules := map[string]*pdapi.PlacementRule{
+		"pd/default": {GroupID: "pd", ID: "default", Role: "voter", Count: 3},
+	}
+	pdClient := pdapi.NewFakePDClient()
+	pdClient.AddReaction(pdapi.GetPlacementRuleActionType, func(action *pdapi.Action) (interface{}, error) {
+		rule, ok := rules[action.Rule.GroupID+"/"+action.Rule.ID]
+		if !ok {
+			return nil, nil
+		}
+		r := *rule
+		return &r, nil
+	})
+	pdClient.AddReaction(pdapi.SetPlacementRuleActionType, func(action *pdapi.Action) (interface{}, error) {
+		rules[action.Rule.GroupID+"/"+action.Rule.ID] = action.Rule
+		return nil, nil
+	})
+	pdClient.AddReaction(pdapi.DeletePlacementRuleActionType, func(action *pdapi.Action) (interface{}, error) {
+		delete(rules, action.Rule.GroupID+"/"+action.Rule.ID)
+		return nil, nil
+	})
+
+	defaultRule, witnessRule, err := getWitnessPlacementRules(pdClient, []string{"zone", "host"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(defaultRule.Count).To(Equal(2))
+	g.Expect(defaultRule.LabelConstraints).To(ConsistOf(pdapi.LabelConstraint{Key: "witness", Op: "notIn", Values: []string{"true"}}))
+	g.Expect(witnessRule.IsWitness).To(BeTrue())
+	g.Expect(witnessRule.Count).To(Equal(1))
+	g.Expect(witnessRule.LocationLabels).To(Equal([]string{"zone", "host"}))
+	g.Expect(witnessRule.LabelConstraints).To(ConsistOf(pdapi.LabelConstraint{Key: "witness", Op: "in", Values: []string{"true"}}))
+
+	// the rules are idempotent after applied
+	g.Expect(pdClient.SetPlacementRule(witnessRule)).To(Succeed())
+	g.Expect(pdClient.SetPlacementRule(defaultRule)).To(Succeed())
+	defaultRule, _, err = getWitnessPlacementRules(pdClient, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(defaultRule.Count).To(Equal(2))
+	g.Expect(defaultRule.LabelConstraints).To(HaveLen(1))
+
+	// the full replica is restored after the rules are removed
+	g.Expect(removeWitnessPlacementRules(pdClient)).To(Succeed())
+	g.Expect(rules).To(HaveLen(1))
+	g.Expect(rules["pd/default"].Count).To(Equal(3))
+	g.Expect(rules["pd/default"].LabelConstraints).To(BeEmpty())
+
+	// a witness replica can't be placed with only one replica
+	rules["pd/default"].Count = 1
+	_, _, err = getWitnessPlacementRules(pdClient, nil)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -98,7 +98,7 @@ func (m *tiproxyMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	component := v1alpha1.TiProxyMemberType
 	needSuspend, err := m.suspender.SuspendComponent(tc, component)
 	if err != nil {
-		return fmt.Errorf("suspend %s failed: %v", component, err)
+		return fmt.Errorf("suspend %s failed: %w", component, err)
 	}
 	if needSuspend {
 		klog.Infof("component %s for cluster %s/%s is suspended, skip syncing", component, ns, tcName)
@@ -122,6 +122,14 @@ func (m *tiproxyMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	return m.syncStatefulSet(tc)
 }
 
+// SyncStatus refreshes the status of TiProxy from its statefulset without acting upon the spec
+func (m *tiproxyMemberManager) SyncStatus(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiProxy == nil {
+		return nil
+	}
+	return syncStatusFromStatefulSet(m.deps, tc, controller.TiProxyMemberName(tc.GetName()), m.syncStatus)
+}
+
 // scaleInToZero is used to scale in tiproxy to zero, it will delete the sts and reset the tiproxy status.
 // Note: the corresponding k8s services, configmaps will remain unchanged.
 func (s *tiproxyMemberManager) handleIfTiProxyScaledToZero(tc *v1alpha1.TidbCluster) (abort bool, _ error) {
@@ -169,11 +177,13 @@ func (m *tiproxyMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *apps
 	}
 	if tc.Heterogeneous() && tc.WithoutLocalPD() {
 		PDAddr = fmt.Sprintf("%s:%d", controller.PDMemberName(tc.Spec.Cluster.Name), v1alpha1.DefaultPDClientPort) // use pd of reference cluster
+	} else if tc.HasExternalPD() {
+		PDAddr = strings.Join(tc.ExternalPDAddresses(), ",") // use the external pd
 	}
 
 	var cfgWrapper *v1alpha1.TiProxyConfigWraper
-	if tc.Spec.TiProxy.Config != nil {
-		cfgWrapper = tc.Spec.TiProxy.Config.DeepCopy()
+	if cfg := m.deps.ConfigDefaults.Apply(tc, v1alpha1.TiProxyMemberType).Spec.TiProxy.Config; cfg != nil {
+		cfgWrapper = cfg.DeepCopy()
 	} else {
 		cfgWrapper = v1alpha1.NewTiProxyConfig()
 	}
@@ -529,7 +539,7 @@ func (m *tiproxyMemberManager) getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *c
 		},
 		VolumeMounts: volMounts,
 		Resources:    controller.ContainerResource(tc.Spec.TiProxy.ResourceRequirements),
-		Env:          util.AppendEnv(envs, baseTiProxySpec.Env()),
+		Env:          util.AppendEnv(util.AppendEnv(envs, baseTiProxySpec.Env()), goRuntimeResourceEnv(baseTiProxySpec, controller.ContainerResource(tc.Spec.TiProxy.ResourceRequirements))),
 		EnvFrom:      baseTiProxySpec.EnvFrom(),
 	}
 	if probeHander := buildTiProxyReadinessProbeHandler(tc); probeHander != nil {
@@ -983,3 +993,7 @@ func (m *FakeTiProxyMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	}
 	return nil
 }
+
+func (m *FakeTiProxyMemberManager) SyncStatus(*v1alpha1.TidbCluster) error {
+	return nil
+}
@@ -16,6 +16,7 @@ package member
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"path"
 	"strconv"
 	"strings"
@@ -23,9 +24,11 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 	"github.com/pingcap/tidb-operator/pkg/apis/util/toml"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/manager/member/startscript"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
 	"github.com/pingcap/tidb-operator/pkg/third_party/k8s"
 	"github.com/pingcap/tidb-operator/pkg/util"
 
@@ -42,6 +45,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -51,6 +55,11 @@ const (
 	ImagePullBackOff = "ImagePullBackOff"
 	// ErrImagePull is the pod state of image pull failed
 	ErrImagePull = "ErrImagePull"
+
+	// logVolumeName is the name of the default emptyDir volume for the log file
+	logVolumeName = "log-volume"
+	// logVolumeMountPathPrefix is the parent directory of the default log volume mount path
+	logVolumeMountPathPrefix = "/var/log"
 )
 
 // The first version that moves the rocksdb info and raft info log to store and rotate as the TiKV log is v5.0.0
@@ -269,6 +278,30 @@ func getTikVConfigMapForTiKVSpec(tikvSpec *v1alpha1.TiKVSpec, tc *v1alpha1.TidbC
 		config.Set("security.cert-path", path.Join(tikvClusterCertPath, corev1.TLSCertKey))
 		config.Set("security.key-path", path.Join(tikvClusterCertPath, corev1.TLSPrivateKeyKey))
 	}
+	if tikvSpec.LogVolume != nil {
+		_, _, logFile, err := getLogVolume(v1alpha1.TiKVMemberType, tikvSpec.LogVolume, tikvSpec.StorageVolumes, tikvSpec.StorageClassName, tikvSpec.AdditionalVolumeMounts)
+		if err != nil {
+			return nil, fmt.Errorf("get log volume for tc %s/%s failed: %v", tc.Namespace, tc.Name, err)
+		}
+		setLogFileConfig(config.GenericConfig, logFile, tikvSpec.LogVolume.Rotation)
+	}
+	if tikvSpec.StorageProfile != "" {
+		config.ApplyStorageProfile(tikvSpec.StorageProfile)
+	}
+	// TiKV sizes the unified read pool by the cpu number of the node by default, the size of the storage
+	// profile is capped by the cpu limit
+	threads := tikvSpec.StorageProfile.ReadPoolThreads()
+	if cpu := cpuLimitCores(tc.BaseTiKVSpec(), tikvSpec.ResourceRequirements); cpu > 0 {
+		if n := int64(math.Max(4, math.Floor(cpu*0.8))); threads == 0 || n < threads {
+			threads = n
+		}
+	}
+	if threads > 0 {
+		config.SetIfNil("readpool.unified.max-thread-count", threads)
+	}
+	if tikvSpec.Encryption != nil {
+		setTiKVEncryptionConfig(config, tc)
+	}
 	confText, err := config.MarshalTOML()
 	if err != nil {
 		return nil, err
@@ -661,3 +694,202 @@ func SetServiceWhenPreferIPv6(svc *corev1.Service) {
 	policy := corev1.IPFamilyPolicyPreferDualStack
 	svc.Spec.IPFamilyPolicy = &policy
 }
+
+// getLogVolume returns the volume mount used to store the log file of the component and the path
+// of the log file. If no volume name is specified in logVolume, an emptyDir volume is returned
+// and it should be added to the pod.
+func getLogVolume(memberType v1alpha1.MemberType, logVolume *v1alpha1.LogVolumeSpec, storageVolumes []v1alpha1.StorageVolume,
+	storageClassName *string, additionalVolumeMounts []corev1.VolumeMount) (corev1.VolumeMount, *corev1.Volume, string, error) {
+	logFile := fmt.Sprintf("%s.log", memberType)
+	if logVolume.VolumeName == "" {
+		vol := &corev1.Volume{
+			Name: logVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: logVolume.SizeLimit},
+			},
+		}
+		volMount := corev1.VolumeMount{
+			Name:      logVolumeName,
+			MountPath: path.Join(logVolumeMountPathPrefix, fmt.Sprintf("%s-log", memberType)),
+		}
+		return volMount, vol, path.Join(volMount.MountPath, logFile), nil
+	}
+
+	storageVolMounts, _ := util.BuildStorageVolumeAndVolumeMount(storageVolumes, storageClassName, memberType)
+	volMountName := fmt.Sprintf("%s-%s", memberType.String(), logVolume.VolumeName)
+	for _, volMount := range storageVolMounts {
+		if volMount.Name == volMountName {
+			return volMount, nil, path.Join(volMount.MountPath, logFile), nil
+		}
+	}
+	for _, volMount := range additionalVolumeMounts {
+		if volMount.Name == logVolume.VolumeName {
+			return volMount, nil, path.Join(volMount.MountPath, logFile), nil
+		}
+	}
+	return corev1.VolumeMount{}, nil, "", fmt.Errorf("failed to get log volume %s of %s", logVolume.VolumeName, memberType)
+}
+
+// setLogFileConfig writes the log into the file and rotates it by the rotation policy
+func setLogFileConfig(cfg *config.GenericConfig, logFile string, rotation *v1alpha1.LogRotationSpec) {
+	cfg.Set("log.file.filename", logFile)
+	if rotation == nil {
+		return
+	}
+	if rotation.MaxSize != nil {
+		cfg.Set("log.file.max-size", *rotation.MaxSize)
+	}
+	if rotation.MaxDays != nil {
+		cfg.Set("log.file.max-days", *rotation.MaxDays)
+	}
+	if rotation.MaxBackups != nil {
+		cfg.Set("log.file.max-backups", *rotation.MaxBackups)
+	}
+}
+
+// buildLogTailerContainer mounts the log volume and tails the log file to STDOUT.
+// The container should be added to the init containers if it's a native sidecar.
+func buildLogTailerContainer(tc *v1alpha1.TidbCluster, logTailer *v1alpha1.LogTailerSpec, volMount corev1.VolumeMount, logFile string) corev1.Container {
+	c := corev1.Container{
+		Name:            v1alpha1.ContainerLogTailer.String(),
+		Image:           tc.HelperImage(),
+		ImagePullPolicy: tc.HelperImagePullPolicy(),
+		Resources:       controller.ContainerResource(logTailer.ResourceRequirements),
+		VolumeMounts:    []corev1.VolumeMount{volMount},
+		Command: []string{
+			"sh",
+			"-c",
+			fmt.Sprintf("touch %s; tail -n0 -F %s;", logFile, logFile),
+		},
+	}
+	if logTailer.UseSidecar {
+		c.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		// NOTE: tail cannot hanle sig TERM when it's PID is 1
+		c.Command = []string{
+			"sh",
+			"-c",
+			fmt.Sprintf(`trap "exit 0" TERM; touch %s; tail -n0 -F %s & wait $!`, logFile, logFile),
+		}
+	}
+	return c
+}
+
+// logVolumePodSpec is what the log volume of a component adds to its pod
+type logVolumePodSpec struct {
+	volumes        []corev1.Volume
+	volumeMounts   []corev1.VolumeMount
+	initContainers []corev1.Container
+	containers     []corev1.Container
+}
+
+// buildLogVolumePodSpec returns the log volume, its mount and the log tailer of the component,
+// nothing is returned if the component doesn't store its log in a log volume.
+func buildLogVolumePodSpec(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, logVolume *v1alpha1.LogVolumeSpec,
+	storageVolumes []v1alpha1.StorageVolume, storageClassName *string, additionalVolumeMounts []corev1.VolumeMount) (*logVolumePodSpec, error) {
+	spec := &logVolumePodSpec{}
+	if logVolume == nil {
+		return spec, nil
+	}
+	logVolMount, logVol, logFile, err := getLogVolume(memberType, logVolume, storageVolumes, storageClassName, additionalVolumeMounts)
+	if err != nil {
+		return nil, err
+	}
+	if logVol != nil {
+		spec.volumes = append(spec.volumes, *logVol)
+		spec.volumeMounts = append(spec.volumeMounts, logVolMount)
+	}
+	if logTailer := logVolume.Tailer; logTailer != nil {
+		// mount the log volume and tail the log to STDOUT using a sidecar.
+		c := buildLogTailerContainer(tc, logTailer, logVolMount, logFile)
+		if logTailer.UseSidecar {
+			spec.initContainers = append(spec.initContainers, c)
+		} else {
+			spec.containers = append(spec.containers, c)
+		}
+	}
+	return spec, nil
+}
+
+// degradeNativeSidecars moves the native sidecars in the init containers to the containers
+// if native sidecars are not supported by the Kubernetes cluster, otherwise the pods can't
+// be started because the init containers never complete.
+func degradeNativeSidecars(caps *controller.Capabilities, set *apps.StatefulSet) {
+	if caps == nil || caps.NativeSidecar || set == nil {
+		return
+	}
+	podSpec := &set.Spec.Template.Spec
+	var initContainers []corev1.Container
+	for _, c := range podSpec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			c.RestartPolicy = nil
+			podSpec.Containers = append(podSpec.Containers, c)
+			continue
+		}
+		initContainers = append(initContainers, c)
+	}
+	podSpec.InitContainers = initContainers
+}
+
+// goRuntimeResourceEnv returns GOMAXPROCS and GOMEMLIMIT derived from the resource limits,
+// so that the Go runtime respects the limits of the container instead of the resources of the node.
+func goRuntimeResourceEnv(spec v1alpha1.ComponentAccessor, resources corev1.ResourceRequirements) []corev1.EnvVar {
+	if !spec.InjectResourceHints() {
+		return nil
+	}
+	var envs []corev1.EnvVar
+	if cpu, ok := resources.Limits[corev1.ResourceCPU]; ok && !cpu.IsZero() {
+		// Value rounds up to the nearest integer
+		envs = append(envs, corev1.EnvVar{
+			Name:  "GOMAXPROCS",
+			Value: strconv.FormatInt(cpu.Value(), 10),
+		})
+	}
+	if memory, ok := resources.Limits[corev1.ResourceMemory]; ok && !memory.IsZero() {
+		// leave some headroom for the memory which is not managed by the Go runtime
+		envs = append(envs, corev1.EnvVar{
+			Name:  "GOMEMLIMIT",
+			Value: strconv.FormatInt(memory.Value()/10*9, 10),
+		})
+	}
+	return envs
+}
+
+// cpuLimitCores returns the cpu limit in cores, it returns 0 if there is no cpu limit.
+func cpuLimitCores(spec v1alpha1.ComponentAccessor, resources corev1.ResourceRequirements) float64 {
+	if !spec.InjectResourceHints() {
+		return 0
+	}
+	cpu, ok := resources.Limits[corev1.ResourceCPU]
+	if !ok {
+		return 0
+	}
+	return float64(cpu.MilliValue()) / 1000
+}
+
+// syncStatusFromStatefulSet syncs the status of a component with the statefulset in the cache, it's used to refresh
+// the status of a cluster whose spec has been acted upon
+func syncStatusFromStatefulSet(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, setName string, syncStatus func(*v1alpha1.TidbCluster, *apps.StatefulSet) error) error {
+	set, err := deps.StatefulSetLister.StatefulSets(tc.GetNamespace()).Get(setName)
+	if err != nil {
+		return fmt.Errorf("syncStatusFromStatefulSet: failed to get sts %s for cluster %s/%s, error: %s", setName, tc.GetNamespace(), tc.GetName(), err)
+	}
+	return syncStatus(tc, set.DeepCopy())
+}
+
+// statefulSetIsUpgrading is like mngerutils.StatefulSetIsUpgrading, but the revisions of the statefulset are not
+// compared if the pods with an ordinal less than partition are intentionally held at the old revision by the rolling
+// update strategy, as the statefulset never converges to the update revision. Whether the pods at or above the
+// partition are upgraded is checked by the revisions of the pods instead.
+func statefulSetIsUpgrading(set *apps.StatefulSet, partition int32) bool {
+	if partition <= 0 {
+		return mngerutils.StatefulSetIsUpgrading(set)
+	}
+	return set.Generation > set.Status.ObservedGeneration && *set.Spec.Replicas == set.Status.Replicas
+}
+
+// heldByPartition returns whether the pod is intentionally held at the old revision by the partition
+// of the rolling update strategy
+func heldByPartition(pod *corev1.Pod, partition int32) bool {
+	ordinal, err := util.GetOrdinalFromPodName(pod.GetName())
+	return err == nil && ordinal < partition
+}
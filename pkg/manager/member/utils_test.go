@@ -24,12 +24,17 @@ import (
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+	"k8s.io/utils/ptr"
 )
 
 func TestGetStsAnnotations(t *testing.T) {
@@ -978,3 +983,86 @@ func TestMergePatchContainersOrderPreserved(t *testing.T) {
 		}
 	}
 }
+
+func TestSetLogFileConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cfg := v1alpha1.NewTiKVConfig()
+	setLogFileConfig(cfg.GenericConfig, "/var/log/tikv-log/tikv.log", nil)
+	g.Expect(cfg.Get("log.file.filename").MustString()).To(Equal("/var/log/tikv-log/tikv.log"))
+	g.Expect(cfg.Get("log.file.max-size")).To(BeNil())
+
+	setLogFileConfig(cfg.GenericConfig, "/var/log/tikv-log/tikv.log", &v1alpha1.LogRotationSpec{
+		MaxSize:    pointer.Int64(300),
+		MaxBackups: pointer.Int64(3),
+	})
+	g.Expect(cfg.Get("log.file.max-size").MustInt()).To(Equal(int64(300)))
+	g.Expect(cfg.Get("log.file.max-backups").MustInt()).To(Equal(int64(3)))
+	g.Expect(cfg.Get("log.file.max-days")).To(BeNil())
+}
+
+func TestGoRuntimeResourceEnv(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{
+		Spec: v1alpha1.TidbClusterSpec{
+			PD: &v1alpha1.PDSpec{},
+		},
+	}
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1500m"),
+			corev1.ResourceMemory: resource.MustParse("10Gi"),
+		},
+	}
+	// disabled by default
+	g.Expect(goRuntimeResourceEnv(tc.BasePDSpec(), resources)).To(BeEmpty())
+	g.Expect(cpuLimitCores(tc.BasePDSpec(), resources)).To(BeZero())
+
+	// opt in at the cluster level
+	tc.Spec.InjectResourceHints = pointer.BoolPtr(true)
+	g.Expect(goRuntimeResourceEnv(tc.BasePDSpec(), resources)).To(Equal([]corev1.EnvVar{
+		{Name: "GOMAXPROCS", Value: "2"},
+		{Name: "GOMEMLIMIT", Value: "9663676416"},
+	}))
+	g.Expect(goRuntimeResourceEnv(tc.BasePDSpec(), corev1.ResourceRequirements{})).To(BeEmpty())
+
+	// the env of the user takes precedence
+	env := util.AppendEnv([]corev1.EnvVar{{Name: "GOMAXPROCS", Value: "4"}}, goRuntimeResourceEnv(tc.BasePDSpec(), resources))
+	g.Expect(env).To(Equal([]corev1.EnvVar{
+		{Name: "GOMAXPROCS", Value: "4"},
+		{Name: "GOMEMLIMIT", Value: "9663676416"},
+	}))
+
+	// the component level setting overrides the cluster level setting
+	tc.Spec.PD.InjectResourceHints = pointer.BoolPtr(false)
+	g.Expect(goRuntimeResourceEnv(tc.BasePDSpec(), resources)).To(BeEmpty())
+	g.Expect(cpuLimitCores(tc.BasePDSpec(), resources)).To(BeZero())
+}
+
+func TestDegradeNativeSidecars(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newSet := func() *apps.StatefulSet {
+		set := &apps.StatefulSet{}
+		set.Spec.Template.Spec.InitContainers = []corev1.Container{
+			{Name: "init"},
+			{Name: "log", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+		}
+		set.Spec.Template.Spec.Containers = []corev1.Container{{Name: "pd"}}
+		return set
+	}
+
+	// native sidecars are kept if they are supported
+	set := newSet()
+	degradeNativeSidecars(controller.AllCapabilities(), set)
+	g.Expect(set).To(Equal(newSet()))
+
+	set = newSet()
+	degradeNativeSidecars(&controller.Capabilities{}, set)
+	g.Expect(set.Spec.Template.Spec.InitContainers).To(Equal([]corev1.Container{{Name: "init"}}))
+	g.Expect(set.Spec.Template.Spec.Containers).To(Equal([]corev1.Container{{Name: "pd"}, {Name: "log"}}))
+}
@@ -0,0 +1,403 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var (
+	// tidbClusterDeletionOrder is the order the components of a tidb cluster are deleted in,
+	// the components depending on others first
+	tidbClusterDeletionOrder = []string{
+		label.TiProxyLabelVal,
+		label.TiDBLabelVal,
+		label.TiCDCLabelVal,
+		label.PumpLabelVal,
+		label.TiFlashLabelVal,
+		label.TiKVLabelVal,
+		label.PDMSSchedulingLabelVal,
+		label.PDMSTSOLabelVal,
+		label.PDLabelVal,
+	}
+	// dmClusterDeletionOrder is the order the components of a dm cluster are deleted in
+	dmClusterDeletionOrder = []string{
+		label.DMWorkerLabelVal,
+		label.DMMasterLabelVal,
+	}
+)
+
+// deletionTarget is a cluster whose deletion policy is carried out
+type deletionTarget struct {
+	kind   string
+	obj    client.Object
+	policy v1alpha1.DeletionPolicy
+	// selector selects the resources of the cluster
+	selector label.Label
+	// order is the order the components are deleted in
+	order []string
+	// update persists the finalizers of the cluster
+	update func() error
+	// deregister deregisters the cluster from the outside of it, returns true when it's done
+	deregister func() (bool, error)
+}
+
+type deletionPolicyManager struct {
+	deps *controller.Dependencies
+}
+
+// NewDeletionPolicyManager returns a *deletionPolicyManager which keeps the deletion finalizer of a cluster
+// in line with its deletion policy, and carries out the policy when the cluster is being deleted.
+func NewDeletionPolicyManager(deps *controller.Dependencies) *deletionPolicyManager {
+	return &deletionPolicyManager{
+		deps: deps,
+	}
+}
+
+func (m *deletionPolicyManager) Sync(tc *v1alpha1.TidbCluster) error {
+	return m.sync(&deletionTarget{
+		kind:     v1alpha1.TiDBClusterKind,
+		obj:      tc,
+		policy:   tc.Spec.DeletionPolicy,
+		selector: label.New().Instance(tc.Name),
+		order:    tidbClusterDeletionOrder,
+		update: func() error {
+			updated, err := m.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Update(context.TODO(), tc, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			tc.ResourceVersion = updated.ResourceVersion
+			return nil
+		},
+		deregister: func() (bool, error) {
+			return m.deregisterStores(tc)
+		},
+	})
+}
+
+func (m *deletionPolicyManager) SyncDM(dc *v1alpha1.DMCluster) error {
+	return m.sync(&deletionTarget{
+		kind:     v1alpha1.DMClusterKind,
+		obj:      dc,
+		policy:   dc.Spec.DeletionPolicy,
+		selector: label.NewDM().Instance(dc.Name),
+		order:    dmClusterDeletionOrder,
+		update: func() error {
+			updated, err := m.deps.Clientset.PingcapV1alpha1().DMClusters(dc.Namespace).Update(context.TODO(), dc, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			dc.ResourceVersion = updated.ResourceVersion
+			return nil
+		},
+	})
+}
+
+func (m *deletionPolicyManager) sync(t *deletionTarget) error {
+	ns, name := t.obj.GetNamespace(), t.obj.GetName()
+	policy := t.policy
+	if policy == "" {
+		policy = v1alpha1.DeletionPolicyBackground
+	}
+
+	if t.obj.GetDeletionTimestamp() == nil {
+		if err := m.syncFinalizer(t, policy != v1alpha1.DeletionPolicyBackground); err != nil {
+			return err
+		}
+		return m.reuseRetainedVolumes(t)
+	}
+	if !controllerutil.ContainsFinalizer(t.obj, label.ClusterDeletionFinalizer) {
+		return nil
+	}
+
+	if t.obj.GetAnnotations()[label.AnnForceDeleteKey] == "true" {
+		klog.Warningf("%s %s/%s is force deleted by annotation %s, the deletion policy %s is skipped", t.kind, ns, name, label.AnnForceDeleteKey, policy)
+		m.deps.Recorder.Eventf(t.obj, corev1.EventTypeWarning, "ForceDeleted",
+			"the remaining steps of the deletion policy %s are skipped by annotation %s", policy, label.AnnForceDeleteKey)
+		return m.syncFinalizer(t, false)
+	}
+
+	switch policy {
+	case v1alpha1.DeletionPolicyRetainData, v1alpha1.DeletionPolicyPurge:
+		if policy == v1alpha1.DeletionPolicyPurge && t.deregister != nil {
+			done, err := t.deregister()
+			if err != nil {
+				return err
+			}
+			if !done {
+				return controller.RequeueErrorf("%s %s/%s is waiting for being deregistered before the deletion", t.kind, ns, name)
+			}
+		}
+		if err := m.deleteComponents(t); err != nil {
+			return err
+		}
+		if policy == v1alpha1.DeletionPolicyPurge {
+			if err := m.purgeVolumes(t); err != nil {
+				return err
+			}
+		} else if err := m.retainVolumes(t); err != nil {
+			return err
+		}
+	}
+
+	klog.Infof("%s %s/%s has been cleaned up by the deletion policy %s", t.kind, ns, name, policy)
+	m.deps.Recorder.Eventf(t.obj, corev1.EventTypeNormal, "CleanedUp", "the cluster has been cleaned up by the deletion policy %s", policy)
+	return m.syncFinalizer(t, false)
+}
+
+// syncFinalizer adds the deletion finalizer to the cluster if it's needed, or removes it otherwise
+func (m *deletionPolicyManager) syncFinalizer(t *deletionTarget, needed bool) error {
+	has := controllerutil.ContainsFinalizer(t.obj, label.ClusterDeletionFinalizer)
+	switch {
+	case needed && !has:
+		controllerutil.AddFinalizer(t.obj, label.ClusterDeletionFinalizer)
+	case !needed && has:
+		controllerutil.RemoveFinalizer(t.obj, label.ClusterDeletionFinalizer)
+	default:
+		return nil
+	}
+	if err := t.update(); err != nil {
+		return fmt.Errorf("update the finalizers of %s %s/%s failed: %v", t.kind, t.obj.GetNamespace(), t.obj.GetName(), err)
+	}
+	return nil
+}
+
+// deleteComponents deletes the StatefulSets of the components in order, the next component is deleted
+// after all the pods of the previous one are gone
+func (m *deletionPolicyManager) deleteComponents(t *deletionTarget) error {
+	ns, name := t.obj.GetNamespace(), t.obj.GetName()
+	selector, err := t.selector.Selector()
+	if err != nil {
+		return err
+	}
+	sets, err := m.deps.StatefulSetLister.StatefulSets(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("list statefulsets of %s %s/%s failed: %v", t.kind, ns, name, err)
+	}
+	pods, err := m.deps.PodLister.Pods(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("list pods of %s %s/%s failed: %v", t.kind, ns, name, err)
+	}
+
+	for _, component := range t.order {
+		remaining := 0
+		for _, set := range sets {
+			if set.Labels[label.ComponentLabelKey] != component {
+				continue
+			}
+			remaining++
+			if set.DeletionTimestamp != nil {
+				continue
+			}
+			if err := m.deps.StatefulSetControl.DeleteStatefulSet(t.obj, set, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		for _, pod := range pods {
+			if pod.Labels[label.ComponentLabelKey] == component {
+				remaining++
+			}
+		}
+		if remaining > 0 {
+			return controller.RequeueErrorf("%s %s/%s is waiting for %s to be deleted", t.kind, ns, name, component)
+		}
+	}
+	return nil
+}
+
+// retainVolumes sets the reclaim policy of the PVs of the cluster to Retain and labels the PVCs as retained
+func (m *deletionPolicyManager) retainVolumes(t *deletionTarget) error {
+	pvcs, err := m.listPVCs(t)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		if err := m.patchPVReclaimPolicy(t, pvc, corev1.PersistentVolumeReclaimRetain); err != nil {
+			return err
+		}
+		if pvc.Labels[label.RetainedLabelKey] == "true" {
+			continue
+		}
+		pvc = pvc.DeepCopy()
+		if pvc.Labels == nil {
+			pvc.Labels = map[string]string{}
+		}
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Labels[label.RetainedLabelKey] = "true"
+		pvc.Annotations[label.AnnRetainedFromKey] = string(t.obj.GetUID())
+		if _, err := m.deps.PVCControl.UpdatePVC(t.obj, pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeVolumes sets the reclaim policy of the PVs of the cluster to Delete and deletes the PVCs
+func (m *deletionPolicyManager) purgeVolumes(t *deletionTarget) error {
+	pvcs, err := m.listPVCs(t)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		if err := m.patchPVReclaimPolicy(t, pvc, corev1.PersistentVolumeReclaimDelete); err != nil {
+			return err
+		}
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if err := m.deps.PVCControl.DeletePVC(t.obj, pvc); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// reuseRetainedVolumes removes the retained label from the PVCs reused by a new cluster with the same name
+func (m *deletionPolicyManager) reuseRetainedVolumes(t *deletionTarget) error {
+	pvcs, err := m.listPVCs(t)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		if _, ok := pvc.Labels[label.RetainedLabelKey]; !ok {
+			continue
+		}
+		pvc = pvc.DeepCopy()
+		delete(pvc.Labels, label.RetainedLabelKey)
+		delete(pvc.Annotations, label.AnnRetainedFromKey)
+		if _, err := m.deps.PVCControl.UpdatePVC(t.obj, pvc); err != nil {
+			return err
+		}
+		klog.Infof("%s %s/%s reuses the retained pvc %s", t.kind, t.obj.GetNamespace(), t.obj.GetName(), pvc.Name)
+	}
+	return nil
+}
+
+func (m *deletionPolicyManager) listPVCs(t *deletionTarget) ([]*corev1.PersistentVolumeClaim, error) {
+	selector, err := t.selector.Selector()
+	if err != nil {
+		return nil, err
+	}
+	pvcs, err := m.deps.PVCLister.PersistentVolumeClaims(t.obj.GetNamespace()).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("list pvcs of %s %s/%s failed: %v", t.kind, t.obj.GetNamespace(), t.obj.GetName(), err)
+	}
+	return pvcs, nil
+}
+
+func (m *deletionPolicyManager) patchPVReclaimPolicy(t *deletionTarget, pvc *corev1.PersistentVolumeClaim, policy corev1.PersistentVolumeReclaimPolicy) error {
+	if m.deps.PVLister == nil || pvc.Spec.VolumeName == "" {
+		return nil
+	}
+	pv, err := m.deps.PVLister.Get(pvc.Spec.VolumeName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get pv %s of %s %s/%s failed: %v", pvc.Spec.VolumeName, t.kind, t.obj.GetNamespace(), t.obj.GetName(), err)
+	}
+	if pv.Spec.PersistentVolumeReclaimPolicy == policy {
+		return nil
+	}
+	return m.deps.PVControl.PatchPVReclaimPolicy(t.obj, pv, policy)
+}
+
+// deregisterStores deletes the TiKV and TiFlash stores of a cluster joining the PD of another cluster
+// from PD, and returns true when none of them is left. The stores of a cluster with its own PD are
+// deleted together with the PD.
+func (m *deletionPolicyManager) deregisterStores(tc *v1alpha1.TidbCluster) (bool, error) {
+	if !tc.WithoutLocalPD() {
+		return true, nil
+	}
+	ids := map[uint64]struct{}{}
+	for _, stores := range []map[string]v1alpha1.TiKVStore{tc.Status.TiKV.Stores, tc.Status.TiFlash.Stores} {
+		for id := range stores {
+			storeID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				return false, fmt.Errorf("parse store id %q of tidbcluster %s/%s failed: %v", id, tc.Namespace, tc.Name, err)
+			}
+			ids[storeID] = struct{}{}
+		}
+	}
+	if len(ids) == 0 {
+		return true, nil
+	}
+
+	pdClient := controller.GetPDClient(m.deps.PDControl, tc)
+	storesInfo, err := pdClient.GetStores()
+	if pdapi.IsTiKVNotBootstrappedError(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get stores of tidbcluster %s/%s from pd failed: %v", tc.Namespace, tc.Name, err)
+	}
+	remaining := 0
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil {
+			continue
+		}
+		storeID := store.Store.GetId()
+		if _, ok := ids[storeID]; !ok || store.Store.StateName == v1alpha1.TiKVStateTombstone {
+			continue
+		}
+		remaining++
+		if store.Store.StateName == v1alpha1.TiKVStateOffline {
+			continue
+		}
+		if err := pdClient.DeleteStore(storeID); err != nil {
+			return false, fmt.Errorf("delete store %d of tidbcluster %s/%s from pd failed: %v", storeID, tc.Namespace, tc.Name, err)
+		}
+		klog.Infof("tidbcluster %s/%s deregisters store %d from pd", tc.Namespace, tc.Name, storeID)
+	}
+	return remaining == 0, nil
+}
+
+var _ manager.Manager = &deletionPolicyManager{}
+var _ manager.DMManager = &deletionPolicyManager{}
+
+type FakeDeletionPolicyManager struct {
+	err error
+}
+
+func NewFakeDeletionPolicyManager() *FakeDeletionPolicyManager {
+	return &FakeDeletionPolicyManager{}
+}
+
+func (m *FakeDeletionPolicyManager) SetSyncError(err error) {
+	m.err = err
+}
+
+func (m *FakeDeletionPolicyManager) Sync(_ *v1alpha1.TidbCluster) error {
+	return m.err
+}
+
+func (m *FakeDeletionPolicyManager) SyncDM(_ *v1alpha1.DMCluster) error {
+	return m.err
+}
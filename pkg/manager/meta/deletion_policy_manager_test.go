@@ -0,0 +1,211 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFakeDeletionPolicyManager(tc *v1alpha1.TidbCluster) *deletionPolicyManager {
+	deps := controller.NewFakeDependencies()
+	_, _ = deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	return NewDeletionPolicyManager(deps)
+}
+
+func newStatefulSetForDeletion(component string) *apps.StatefulSet {
+	set := &apps.StatefulSet{}
+	set.Namespace = corev1.NamespaceDefault
+	set.Name = controller.TestClusterName + "-" + component
+	set.Labels = label.New().Instance(controller.TestClusterName).Component(component)
+	return set
+}
+
+func TestDeletionPolicyManagerSyncFinalizer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForMeta()
+	tc.Spec.DeletionPolicy = v1alpha1.DeletionPolicyRetainData
+	m := newFakeDeletionPolicyManager(tc)
+	pvcIndexer := m.deps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	pvc := newPVC(tc, "1")
+	pvc.Labels[label.RetainedLabelKey] = "true"
+	pvc.Annotations = map[string]string{label.AnnRetainedFromKey: "old"}
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+
+	// the finalizer is added and the retained pvc is reused
+	g.Expect(m.Sync(tc)).To(Succeed())
+	g.Expect(tc.Finalizers).To(ConsistOf(label.ClusterDeletionFinalizer))
+	updated, err := m.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated.Finalizers).To(ConsistOf(label.ClusterDeletionFinalizer))
+	pvc, err = m.deps.PVCLister.PersistentVolumeClaims(pvc.Namespace).Get(pvc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pvc.Labels).NotTo(HaveKey(label.RetainedLabelKey))
+	g.Expect(pvc.Annotations).NotTo(HaveKey(label.AnnRetainedFromKey))
+
+	// the finalizer is removed with the background policy
+	tc.Spec.DeletionPolicy = v1alpha1.DeletionPolicyBackground
+	g.Expect(m.Sync(tc)).To(Succeed())
+	g.Expect(tc.Finalizers).To(BeEmpty())
+
+	// the cluster being deleted without the finalizer is left as it is
+	tc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	g.Expect(m.Sync(tc)).To(Succeed())
+	g.Expect(tc.Finalizers).To(BeEmpty())
+}
+
+func TestDeletionPolicyManagerRetainData(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForMeta()
+	tc.Spec.DeletionPolicy = v1alpha1.DeletionPolicyRetainData
+	tc.Finalizers = []string{label.ClusterDeletionFinalizer}
+	m := newFakeDeletionPolicyManager(tc)
+	tc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	informers := m.deps.KubeInformerFactory
+	setIndexer := informers.Apps().V1().StatefulSets().Informer().GetIndexer()
+	podIndexer := informers.Core().V1().Pods().Informer().GetIndexer()
+	pvcIndexer := informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	pvIndexer := informers.Core().V1().PersistentVolumes().Informer().GetIndexer()
+	sets := map[string]*apps.StatefulSet{}
+	for _, component := range []string{label.PDLabelVal, label.TiKVLabelVal, label.TiDBLabelVal} {
+		sets[component] = newStatefulSetForDeletion(component)
+		g.Expect(setIndexer.Add(sets[component])).To(Succeed())
+	}
+	pod := &corev1.Pod{}
+	pod.Namespace = corev1.NamespaceDefault
+	pod.Name = controller.TestClusterName + "-tikv-0"
+	pod.Labels = label.New().Instance(controller.TestClusterName).TiKV()
+	g.Expect(podIndexer.Add(pod)).To(Succeed())
+	g.Expect(pvcIndexer.Add(newPVC(tc, "1"))).To(Succeed())
+	g.Expect(pvIndexer.Add(newPV("1"))).To(Succeed())
+
+	// the components are deleted in order
+	err := m.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("waiting for tidb to be deleted"))
+	g.Expect(setIndexer.Delete(sets[label.TiDBLabelVal])).To(Succeed())
+	err = m.Sync(tc)
+	g.Expect(err.Error()).To(ContainSubstring("waiting for tikv to be deleted"))
+	g.Expect(setIndexer.Delete(sets[label.TiKVLabelVal])).To(Succeed())
+	err = m.Sync(tc)
+	g.Expect(err.Error()).To(ContainSubstring("waiting for tikv to be deleted"))
+	g.Expect(podIndexer.Delete(pod)).To(Succeed())
+	err = m.Sync(tc)
+	g.Expect(err.Error()).To(ContainSubstring("waiting for pd to be deleted"))
+	g.Expect(setIndexer.Delete(sets[label.PDLabelVal])).To(Succeed())
+
+	// the volumes are retained and the finalizer is removed
+	g.Expect(m.Sync(tc)).To(Succeed())
+	g.Expect(tc.Finalizers).To(BeEmpty())
+	pvc, err := m.deps.PVCLister.PersistentVolumeClaims(corev1.NamespaceDefault).Get("pvc-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pvc.Labels).To(HaveKeyWithValue(label.RetainedLabelKey, "true"))
+	g.Expect(pvc.Annotations).To(HaveKeyWithValue(label.AnnRetainedFromKey, string(tc.UID)))
+	pv, err := m.deps.PVLister.Get("pv-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+}
+
+func TestDeletionPolicyManagerPurge(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForMeta()
+	tc.Spec.DeletionPolicy = v1alpha1.DeletionPolicyPurge
+	tc.Spec.PD = nil
+	tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Name: "origin", Namespace: corev1.NamespaceDefault}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{"1": {ID: "1"}, "2": {ID: "2"}}
+	tc.Finalizers = []string{label.ClusterDeletionFinalizer}
+	m := newFakeDeletionPolicyManager(tc)
+	tc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	informers := m.deps.KubeInformerFactory
+	g.Expect(informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(newPVC(tc, "1"))).To(Succeed())
+	pv := newPV("1")
+	pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	g.Expect(informers.Core().V1().PersistentVolumes().Informer().GetIndexer().Add(pv)).To(Succeed())
+
+	pdClient := controller.NewFakePDClient(m.deps.PDControl.(*pdapi.FakePDControl), tc)
+	states := map[uint64]string{1: v1alpha1.TiKVStateUp, 2: v1alpha1.TiKVStateOffline, 3: v1alpha1.TiKVStateUp}
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		info := &pdapi.StoresInfo{}
+		for id, state := range states {
+			info.Stores = append(info.Stores, &pdapi.StoreInfo{Store: &pdapi.MetaStore{Store: &metapb.Store{Id: id}, StateName: state}})
+		}
+		return info, nil
+	})
+	var deleted []uint64
+	pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+		deleted = append(deleted, action.ID)
+		return nil, nil
+	})
+
+	// the stores of the cluster are deregistered
+	err := m.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(deleted).To(Equal([]uint64{1}))
+	g.Expect(tc.Finalizers).To(ConsistOf(label.ClusterDeletionFinalizer))
+
+	// the volumes are purged after the stores are tombstone
+	states[1] = v1alpha1.TiKVStateTombstone
+	states[2] = v1alpha1.TiKVStateTombstone
+	g.Expect(m.Sync(tc)).To(Succeed())
+	g.Expect(tc.Finalizers).To(BeEmpty())
+	_, err = m.deps.PVCLister.PersistentVolumeClaims(corev1.NamespaceDefault).Get("pvc-1")
+	g.Expect(err).To(HaveOccurred())
+	pv, err = m.deps.PVLister.Get("pv-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+}
+
+func TestDeletionPolicyManagerForceDelete(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dc := newDMClusterForMeta()
+	dc.Spec.DeletionPolicy = v1alpha1.DeletionPolicyPurge
+	dc.Finalizers = []string{label.ClusterDeletionFinalizer}
+	deps := controller.NewFakeDependencies()
+	_, err := deps.Clientset.PingcapV1alpha1().DMClusters(dc.Namespace).Create(context.TODO(), dc, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	m := NewDeletionPolicyManager(deps)
+	dc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	set := &apps.StatefulSet{}
+	set.Namespace = corev1.NamespaceDefault
+	set.Name = controller.TestClusterName + "-dm-master"
+	set.Labels = label.NewDM().Instance(controller.TestClusterName).DMMaster()
+	g.Expect(deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(set)).To(Succeed())
+
+	err = m.SyncDM(dc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("waiting for dm-master to be deleted"))
+
+	// the remaining steps are skipped by the annotation
+	dc.Annotations = map[string]string{label.AnnForceDeleteKey: "true"}
+	g.Expect(m.SyncDM(dc)).To(Succeed())
+	g.Expect(dc.Finalizers).To(BeEmpty())
+}
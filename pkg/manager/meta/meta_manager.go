@@ -107,7 +107,10 @@ func (m *metaManager) Sync(tc *v1alpha1.TidbCluster) error {
 		}
 	}
 
-	return nil
+	if err := m.syncPropagatedMeta(tc); err != nil {
+		return err
+	}
+	return m.syncVelero(tc)
 }
 
 var _ manager.Manager = &metaManager{}
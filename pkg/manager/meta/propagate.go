@@ -0,0 +1,109 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// syncPropagatedMeta propagates the labels and annotations of the tidb cluster selected by
+// spec.propagatePolicy to the statefulsets, pods, services, PVCs and jobs of the cluster
+func (m *metaManager) syncPropagatedMeta(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	instanceName := tc.GetInstanceName()
+
+	l, err := label.New().Instance(instanceName).Selector()
+	if err != nil {
+		return err
+	}
+
+	sets, err := m.deps.StatefulSetLister.StatefulSets(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncPropagatedMeta: failed to list statefulsets for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, set := range sets {
+		set = set.DeepCopy()
+		if !controller.PropagateMeta(tc, set) {
+			continue
+		}
+		if _, err := m.deps.StatefulSetControl.UpdateStatefulSet(tc, set); err != nil {
+			return err
+		}
+	}
+
+	pods, err := m.deps.PodLister.Pods(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncPropagatedMeta: failed to list pods for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, pod := range pods {
+		pod = pod.DeepCopy()
+		if !controller.PropagateMeta(tc, pod) {
+			continue
+		}
+		if _, err := m.deps.PodControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+	}
+
+	svcs, err := m.deps.ServiceLister.Services(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncPropagatedMeta: failed to list services for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, svc := range svcs {
+		svc = svc.DeepCopy()
+		if !controller.PropagateMeta(tc, svc) {
+			continue
+		}
+		if _, err := m.deps.ServiceControl.UpdateService(tc, svc); err != nil {
+			return err
+		}
+	}
+
+	pvcs, err := m.deps.PVCLister.PersistentVolumeClaims(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncPropagatedMeta: failed to list pvcs for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, pvc := range pvcs {
+		pvc = pvc.DeepCopy()
+		if !controller.PropagateMeta(tc, pvc) {
+			continue
+		}
+		if _, err := m.deps.PVCControl.UpdatePVC(tc, pvc); err != nil {
+			return err
+		}
+	}
+
+	jobs, err := m.deps.JobLister.Jobs(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncPropagatedMeta: failed to list jobs for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, job := range jobs {
+		job = job.DeepCopy()
+		if !controller.PropagateMeta(tc, job) {
+			continue
+		}
+		if _, err := m.deps.KubeClientset.BatchV1().Jobs(ns).Update(context.TODO(), job, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("syncPropagatedMeta: failed to update job %s/%s, error: %v", ns, job.Name, err)
+		}
+		klog.Infof("TidbCluster: [%s/%s], propagate labels and annotations to job %s", ns, instanceName, job.Name)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncPropagatedMeta(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForMeta()
+	tc.Labels["team"] = "db"
+	tc.Annotations = map[string]string{"note": "hello"}
+	tc.Spec.PropagatePolicy = &v1alpha1.PropagatePolicy{Labels: []string{"team"}, Annotations: []string{"note"}}
+
+	nmm, _, _, _, podIndexer, pvcIndexer, _ := newFakeMetaManager()
+	deps := nmm.deps
+	pod := newPod(tc)
+	g.Expect(podIndexer.Add(pod)).To(Succeed())
+	pvc := newPVC(tc, "1")
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: tc.Namespace, Labels: pod.Labels}}
+	g.Expect(deps.KubeInformerFactory.Core().V1().Services().Informer().GetIndexer().Add(svc)).To(Succeed())
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: tc.Namespace, Labels: pod.Labels}}
+	_, err := deps.KubeClientset.BatchV1().Jobs(tc.Namespace).Create(context.TODO(), job, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deps.KubeInformerFactory.Batch().V1().Jobs().Informer().GetIndexer().Add(job)).To(Succeed())
+
+	expectPropagated := func(obj metav1.Object, propagated bool) {
+		if propagated {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("team", "db"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("note", "hello"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(label.AnnPropagatedLabelsKey, "team"))
+		} else {
+			g.Expect(obj.GetLabels()).NotTo(HaveKey("team"))
+			g.Expect(obj.GetAnnotations()).NotTo(HaveKey("note"))
+			g.Expect(obj.GetAnnotations()).NotTo(HaveKey(label.AnnPropagatedLabelsKey))
+		}
+		g.Expect(obj.GetLabels()).To(HaveKeyWithValue(label.InstanceLabelKey, tc.GetInstanceName()))
+	}
+	check := func(propagated bool) {
+		pod, err := deps.PodLister.Pods(tc.Namespace).Get(pod.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		expectPropagated(pod, propagated)
+		pvc, err := deps.PVCLister.PersistentVolumeClaims(tc.Namespace).Get(pvc.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		expectPropagated(pvc, propagated)
+		svc, err := deps.ServiceLister.Services(tc.Namespace).Get(svc.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		expectPropagated(svc, propagated)
+		job, err := deps.KubeClientset.BatchV1().Jobs(tc.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		expectPropagated(job, propagated)
+		g.Expect(deps.KubeInformerFactory.Batch().V1().Jobs().Informer().GetIndexer().Update(job)).To(Succeed())
+	}
+
+	g.Expect(nmm.syncPropagatedMeta(tc)).To(Succeed())
+	check(true)
+
+	// the propagated labels and annotations are removed with the policy
+	tc.Spec.PropagatePolicy = nil
+	g.Expect(nmm.syncPropagatedMeta(tc)).To(Succeed())
+	check(false)
+}
@@ -0,0 +1,279 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// the annotation keys of the Velero backup hooks of the pods
+const (
+	veleroPreHookContainerKey  = "pre.hook.backup.velero.io/container"
+	veleroPreHookCommandKey    = "pre.hook.backup.velero.io/command"
+	veleroPreHookOnErrorKey    = "pre.hook.backup.velero.io/on-error"
+	veleroPreHookTimeoutKey    = "pre.hook.backup.velero.io/timeout"
+	veleroPostHookContainerKey = "post.hook.backup.velero.io/container"
+	veleroPostHookCommandKey   = "post.hook.backup.velero.io/command"
+	veleroPostHookOnErrorKey   = "post.hook.backup.velero.io/on-error"
+	veleroPostHookTimeoutKey   = "post.hook.backup.velero.io/timeout"
+)
+
+var veleroHookKeys = []string{
+	veleroPreHookContainerKey,
+	veleroPreHookCommandKey,
+	veleroPreHookOnErrorKey,
+	veleroPreHookTimeoutKey,
+	veleroPostHookContainerKey,
+	veleroPostHookCommandKey,
+	veleroPostHookOnErrorKey,
+	veleroPostHookTimeoutKey,
+}
+
+// syncVelero sets the Velero exclusion label and backup hooks to the resources of the tidb cluster
+// by spec.velero, and reconciles the statefulsets and PVCs restored by Velero. The label and hooks
+// are removed if spec.velero is not set.
+func (m *metaManager) syncVelero(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	instanceName := tc.GetInstanceName()
+	spec := tc.Spec.Velero
+	exclude := spec != nil && (spec.ExcludeGeneratedResources == nil || *spec.ExcludeGeneratedResources)
+	hooks := map[string]map[string]string{}
+	if spec != nil {
+		for _, hook := range spec.Hooks {
+			anns, err := veleroHookAnnotations(hook)
+			if err != nil {
+				return fmt.Errorf("syncVelero: invalid hook for component %s of cluster %s/%s, error: %v", hook.Component, ns, instanceName, err)
+			}
+			hooks[hook.Component.String()] = anns
+		}
+	}
+
+	l, err := label.New().Instance(instanceName).Selector()
+	if err != nil {
+		return err
+	}
+
+	pods, err := m.deps.PodLister.Pods(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncVelero: failed to list pods for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, pod := range pods {
+		pod = pod.DeepCopy()
+		anns, hasHooks := hooks[pod.Labels[label.ComponentLabelKey]]
+		labelChanged := setVeleroExcludeLabel(pod, exclude && !hasHooks)
+		if !setVeleroHookAnnotations(pod, anns) && !labelChanged {
+			continue
+		}
+		if _, err := m.deps.PodControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+	}
+
+	sets, err := m.deps.StatefulSetLister.StatefulSets(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncVelero: failed to list statefulsets for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, set := range sets {
+		set = set.DeepCopy()
+		changed := setVeleroExcludeLabel(set, exclude)
+		if spec != nil && spec.RestoreCompatibility && reconcileVeleroRestored(tc, set) {
+			klog.Infof("TidbCluster: [%s/%s], reconcile statefulset %s restored by velero", ns, instanceName, set.Name)
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if _, err := m.deps.StatefulSetControl.UpdateStatefulSet(tc, set); err != nil {
+			return err
+		}
+	}
+
+	if spec != nil && spec.RestoreCompatibility {
+		pvcs, err := m.deps.PVCLister.PersistentVolumeClaims(ns).List(l)
+		if err != nil {
+			return fmt.Errorf("syncVelero: failed to list pvcs for cluster %s/%s, error: %v", ns, instanceName, err)
+		}
+		for _, pvc := range pvcs {
+			pvc = pvc.DeepCopy()
+			if !reconcileVeleroRestored(tc, pvc) {
+				continue
+			}
+			// the IDs are set by the meta manager again once the new pods get them from PD
+			for _, k := range []string{label.ClusterIDLabelKey, label.MemberIDLabelKey, label.StoreIDLabelKey} {
+				delete(pvc.Labels, k)
+			}
+			klog.Infof("TidbCluster: [%s/%s], reconcile pvc %s restored by velero", ns, instanceName, pvc.Name)
+			if _, err := m.deps.PVCControl.UpdatePVC(tc, pvc); err != nil {
+				return err
+			}
+		}
+	}
+
+	svcs, err := m.deps.ServiceLister.Services(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncVelero: failed to list services for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, svc := range svcs {
+		svc = svc.DeepCopy()
+		if !setVeleroExcludeLabel(svc, exclude) {
+			continue
+		}
+		if _, err := m.deps.ServiceControl.UpdateService(tc, svc); err != nil {
+			return err
+		}
+	}
+
+	cms, err := m.deps.ConfigMapLister.ConfigMaps(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncVelero: failed to list configmaps for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, cm := range cms {
+		cm = cm.DeepCopy()
+		if !setVeleroExcludeLabel(cm, exclude) {
+			continue
+		}
+		if _, err := m.deps.ConfigMapControl.UpdateConfigMap(tc, cm); err != nil {
+			return err
+		}
+	}
+
+	deploys, err := m.deps.DeploymentLister.Deployments(ns).List(l)
+	if err != nil {
+		return fmt.Errorf("syncVelero: failed to list deployments for cluster %s/%s, error: %v", ns, instanceName, err)
+	}
+	for _, deploy := range deploys {
+		deploy = deploy.DeepCopy()
+		if !setVeleroExcludeLabel(deploy, exclude) {
+			continue
+		}
+		if _, err := m.deps.KubeClientset.AppsV1().Deployments(ns).Update(context.TODO(), deploy, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("syncVelero: failed to update deployment %s/%s, error: %v", ns, deploy.Name, err)
+		}
+	}
+	return nil
+}
+
+// veleroHookAnnotations returns the pod annotations of the Velero backup hook
+func veleroHookAnnotations(hook v1alpha1.VeleroBackupHook) (map[string]string, error) {
+	container := hook.Container
+	if container == "" {
+		container = hook.Component.String()
+	}
+	anns := map[string]string{}
+	set := func(command []string, containerKey, commandKey, onErrorKey, timeoutKey string) error {
+		if len(command) == 0 {
+			return nil
+		}
+		data, err := json.Marshal(command)
+		if err != nil {
+			return err
+		}
+		anns[containerKey] = container
+		anns[commandKey] = string(data)
+		if hook.OnError != "" {
+			anns[onErrorKey] = hook.OnError
+		}
+		if hook.Timeout != nil {
+			anns[timeoutKey] = hook.Timeout.Duration.String()
+		}
+		return nil
+	}
+	if err := set(hook.PreCommand, veleroPreHookContainerKey, veleroPreHookCommandKey, veleroPreHookOnErrorKey, veleroPreHookTimeoutKey); err != nil {
+		return nil, err
+	}
+	if err := set(hook.PostCommand, veleroPostHookContainerKey, veleroPostHookCommandKey, veleroPostHookOnErrorKey, veleroPostHookTimeoutKey); err != nil {
+		return nil, err
+	}
+	return anns, nil
+}
+
+// setVeleroHookAnnotations sets the annotations of the hooks to obj and removes the other hook
+// annotations, it returns whether obj is changed
+func setVeleroHookAnnotations(obj metav1.Object, hookAnns map[string]string) bool {
+	anns := obj.GetAnnotations()
+	if anns == nil {
+		anns = map[string]string{}
+	}
+	changed := false
+	for _, k := range veleroHookKeys {
+		v, desired := hookAnns[k]
+		old, ok := anns[k]
+		switch {
+		case desired && (!ok || old != v):
+			anns[k] = v
+			changed = true
+		case !desired && ok:
+			delete(anns, k)
+			changed = true
+		}
+	}
+	if changed {
+		obj.SetAnnotations(anns)
+	}
+	return changed
+}
+
+// setVeleroExcludeLabel sets or removes the Velero exclusion label of obj, it returns whether obj is changed
+func setVeleroExcludeLabel(obj metav1.Object, exclude bool) bool {
+	labels := obj.GetLabels()
+	_, ok := labels[label.VeleroExcludeFromBackupLabelKey]
+	if !exclude {
+		if !ok {
+			return false
+		}
+		delete(labels, label.VeleroExcludeFromBackupLabelKey)
+		obj.SetLabels(labels)
+		return true
+	}
+	if labels[label.VeleroExcludeFromBackupLabelKey] == "true" {
+		return false
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[label.VeleroExcludeFromBackupLabelKey] = "true"
+	obj.SetLabels(labels)
+	return true
+}
+
+// reconcileVeleroRestored points the owner references of obj restored by Velero to the tidb cluster,
+// and records the restore in the annotation so that obj is reconciled only once for a restore.
+// It returns false if obj is not restored by Velero or has been reconciled.
+func reconcileVeleroRestored(tc *v1alpha1.TidbCluster, obj metav1.Object) bool {
+	restore, ok := obj.GetLabels()[label.VeleroRestoreNameLabelKey]
+	if !ok || obj.GetAnnotations()[label.AnnVeleroRestoreKey] == restore {
+		return false
+	}
+	refs := obj.GetOwnerReferences()
+	for i := range refs {
+		if refs[i].Kind == v1alpha1.TiDBClusterKind && refs[i].Name == tc.GetName() {
+			refs[i].UID = tc.GetUID()
+		}
+	}
+	obj.SetOwnerReferences(refs)
+	anns := obj.GetAnnotations()
+	if anns == nil {
+		anns = map[string]string{}
+	}
+	anns[label.AnnVeleroRestoreKey] = restore
+	obj.SetAnnotations(anns)
+	return true
+}
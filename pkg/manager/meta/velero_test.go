@@ -0,0 +1,119 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+func TestSyncVelero(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForMeta()
+	tc.Spec.Velero = &v1alpha1.VeleroSpec{
+		Hooks: []v1alpha1.VeleroBackupHook{{
+			Component:  v1alpha1.TiKVMemberType,
+			PreCommand: []string{"/bin/sh", "-c", "sync"},
+			Timeout:    &metav1.Duration{Duration: time.Minute},
+			OnError:    "Continue",
+		}},
+		RestoreCompatibility: true,
+	}
+
+	nmm, _, _, _, podIndexer, pvcIndexer, _ := newFakeMetaManager()
+	deps := nmm.deps
+	pdPod := newPod(tc)
+	g.Expect(podIndexer.Add(pdPod)).To(Succeed())
+	tikvPod := newPod(tc)
+	tikvPod.Name = "tikv-0"
+	tikvPod.Labels = label.New().Instance(tc.GetInstanceName()).TiKV()
+	g.Expect(podIndexer.Add(tikvPod)).To(Succeed())
+
+	restored := func(obj metav1.Object) {
+		obj.SetLabels(label.New().Instance(tc.GetInstanceName()).TiKV().Labels())
+		obj.GetLabels()[label.VeleroRestoreNameLabelKey] = "restore-1"
+		obj.GetLabels()[label.StoreIDLabelKey] = "1"
+		obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: v1alpha1.TiDBClusterKind, Name: tc.Name, UID: types.UID("old")}})
+	}
+	set := &apps.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "tikv", Namespace: tc.Namespace}}
+	restored(set)
+	g.Expect(deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(set)).To(Succeed())
+	pvc := newPVC(tc, "1")
+	restored(pvc)
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+
+	g.Expect(nmm.syncVelero(tc)).To(Succeed())
+
+	// the pods with hooks are not excluded
+	pod, err := deps.PodLister.Pods(tc.Namespace).Get(pdPod.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Labels).To(HaveKeyWithValue(label.VeleroExcludeFromBackupLabelKey, "true"))
+	g.Expect(pod.Annotations).NotTo(HaveKey(veleroPreHookCommandKey))
+	pod, err = deps.PodLister.Pods(tc.Namespace).Get(tikvPod.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Labels).NotTo(HaveKey(label.VeleroExcludeFromBackupLabelKey))
+	g.Expect(pod.Annotations).To(Equal(map[string]string{
+		veleroPreHookContainerKey: "tikv",
+		veleroPreHookCommandKey:   `["/bin/sh","-c","sync"]`,
+		veleroPreHookOnErrorKey:   "Continue",
+		veleroPreHookTimeoutKey:   "1m0s",
+	}))
+
+	// the restored resources are reconciled
+	set, err = deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(set.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Labels).To(HaveKeyWithValue(label.VeleroExcludeFromBackupLabelKey, "true"))
+	g.Expect(set.OwnerReferences[0].UID).To(Equal(tc.UID))
+	g.Expect(set.Annotations).To(HaveKeyWithValue(label.AnnVeleroRestoreKey, "restore-1"))
+	pvc, err = deps.PVCLister.PersistentVolumeClaims(tc.Namespace).Get(pvc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pvc.Labels).NotTo(HaveKey(label.StoreIDLabelKey))
+	g.Expect(pvc.Labels).NotTo(HaveKey(label.VeleroExcludeFromBackupLabelKey))
+	g.Expect(pvc.Annotations).To(HaveKeyWithValue(label.AnnVeleroRestoreKey, "restore-1"))
+
+	// the restored resources are reconciled only once
+	pvc = pvc.DeepCopy()
+	pvc.Labels[label.StoreIDLabelKey] = "2"
+	g.Expect(pvcIndexer.Update(pvc)).To(Succeed())
+	g.Expect(nmm.syncVelero(tc)).To(Succeed())
+	pvc, err = deps.PVCLister.PersistentVolumeClaims(tc.Namespace).Get(pvc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pvc.Labels).To(HaveKeyWithValue(label.StoreIDLabelKey, "2"))
+
+	// the label is removed if the generated resources are not excluded
+	tc.Spec.Velero.ExcludeGeneratedResources = pointer.BoolPtr(false)
+	g.Expect(nmm.syncVelero(tc)).To(Succeed())
+	pod, err = deps.PodLister.Pods(tc.Namespace).Get(pdPod.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Labels).NotTo(HaveKey(label.VeleroExcludeFromBackupLabelKey))
+	set, err = deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(set.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Labels).NotTo(HaveKey(label.VeleroExcludeFromBackupLabelKey))
+
+	// the hooks are removed without spec.velero
+	tc.Spec.Velero = nil
+	g.Expect(nmm.syncVelero(tc)).To(Succeed())
+	pod, err = deps.PodLister.Pods(tc.Namespace).Get(tikvPod.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Annotations).To(BeEmpty())
+}
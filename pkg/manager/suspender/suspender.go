@@ -16,8 +16,10 @@ package suspender
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	errutil "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
@@ -27,10 +29,13 @@ import (
 )
 
 var (
+	// suspendOrderForTC is the dependency order to suspend the components, a component is
+	// suspended after all the components before it, and resumed in the reverse order.
 	suspendOrderForTC = []v1alpha1.MemberType{
+		v1alpha1.TiProxyMemberType,
 		v1alpha1.TiDBMemberType,
-		v1alpha1.TiFlashMemberType,
 		v1alpha1.TiCDCMemberType,
+		v1alpha1.TiFlashMemberType,
 		v1alpha1.TiKVMemberType,
 		v1alpha1.PumpMemberType,
 		v1alpha1.PDMemberType,
@@ -89,10 +94,18 @@ func (s *suspender) SuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.Mem
 		return false, fmt.Errorf("spec or status for component %s is not found", ctx.ComponentID())
 	}
 
+	if tc, ok := cluster.(*v1alpha1.TidbCluster); ok {
+		defer updateSuspendStatus(tc)
+	}
+
 	suspending := cluster.ComponentIsSuspending(comp)
 
 	if !needsSuspendComponent(ctx.cluster, ctx.component) {
 		if suspending {
+			if can, reason := canResumeComponent(ctx.cluster, ctx.component); !can {
+				klog.Infof("component %s can not be resumed now because: %s", ctx.ComponentID(), reason)
+				return true, nil
+			}
 			err := s.end(ctx)
 			return true, err
 		}
@@ -111,7 +124,12 @@ func (s *suspender) SuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.Mem
 		return true, err
 	}
 
-	err := s.suspendResources(ctx, ctx.spec.SuspendAction())
+	action := ctx.spec.SuspendAction()
+	if left := gracePeriodLeft(ctx.status, action); left > 0 {
+		return true, controller.RequeueErrorf("component %s is in the grace period of suspension, %s left", ctx.ComponentID(), left)
+	}
+
+	err := s.suspendResources(ctx, action)
 	return true, err
 }
 
@@ -195,6 +213,12 @@ func (s *suspender) begin(ctx *suspendComponentCtx) error {
 	klog.Infof("begin to suspend component %s and transfer phase from %s to %s",
 		ctx.ComponentID(), status.GetPhase(), phase)
 	ctx.status.SetPhase(phase)
+	ctx.status.SetCondition(metav1.Condition{
+		Type:    v1alpha1.ComponentSuspending,
+		Status:  metav1.ConditionTrue,
+		Reason:  "BeginSuspending",
+		Message: "The component is being suspended",
+	})
 	return nil
 }
 
@@ -204,9 +228,27 @@ func (s *suspender) end(ctx *suspendComponentCtx) error {
 	klog.Infof("end to suspend component %s and transfer phase from %s to %s",
 		ctx.ComponentID(), status.GetPhase(), phase)
 	ctx.status.SetPhase(phase)
+	ctx.status.SetCondition(metav1.Condition{
+		Type:    v1alpha1.ComponentSuspending,
+		Status:  metav1.ConditionFalse,
+		Reason:  "EndSuspending",
+		Message: "The component is resumed",
+	})
 	return nil
 }
 
+// gracePeriodLeft returns the time left of the grace period before the resources of the component are suspended
+func gracePeriodLeft(status v1alpha1.ComponentStatus, action *v1alpha1.SuspendAction) time.Duration {
+	if action == nil || action.GracePeriod == nil || action.GracePeriod.Duration <= 0 {
+		return 0
+	}
+	cond := meta.FindStatusCondition(status.GetConditions(), v1alpha1.ComponentSuspending)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return 0
+	}
+	return time.Until(cond.LastTransitionTime.Add(action.GracePeriod.Duration)).Truncate(time.Second)
+}
+
 // needsSuspendComponent returns whether suspender needs to to suspend the component
 func needsSuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) bool {
 	spec := cluster.ComponentSpec(comp)
@@ -217,6 +259,9 @@ func needsSuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) b
 	if action == nil {
 		return false
 	}
+	if action.Component != "" && !dependsOn(suspendOrder(cluster), comp, action.Component) {
+		return false
+	}
 
 	if action.SuspendStatefulSet {
 		return true
@@ -225,6 +270,31 @@ func needsSuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) b
 	return false
 }
 
+// suspendOrder returns the order to suspend the components of the cluster
+func suspendOrder(cluster v1alpha1.Cluster) []v1alpha1.MemberType {
+	switch cluster.(type) {
+	case *v1alpha1.TidbCluster:
+		return suspendOrderForTC
+	case *v1alpha1.DMCluster:
+		return suspendOrderForDM
+	}
+	return nil
+}
+
+// dependsOn returns whether the component is the target or is suspended before the target in the order
+func dependsOn(order []v1alpha1.MemberType, comp, target v1alpha1.MemberType) bool {
+	for _, typ := range order {
+		if typ == comp {
+			return true
+		}
+		if typ == target {
+			return false
+		}
+	}
+	// the components not in the order are only suspended with the whole cluster
+	return false
+}
+
 // canSuspendComponent checks whether suspender can start to suspend the component
 func canSuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) (bool, string) {
 	// only support to suspend Normal or Suspend cluster
@@ -234,14 +304,7 @@ func canSuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) (bo
 	}
 
 	// wait for other components to be suspended
-	var suspendOrder []v1alpha1.MemberType
-	switch cluster.(type) {
-	case *v1alpha1.TidbCluster:
-		suspendOrder = suspendOrderForTC
-	case *v1alpha1.DMCluster:
-		suspendOrder = suspendOrderForDM
-	}
-	for _, typ := range suspendOrder {
+	for _, typ := range suspendOrder(cluster) {
 		if typ == comp {
 			break
 		}
@@ -254,3 +317,83 @@ func canSuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) (bo
 
 	return true, ""
 }
+
+// canResumeComponent checks whether suspender can end the suspension of the component,
+// the components it depends on must be resumed and running first.
+func canResumeComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) (bool, string) {
+	order := suspendOrder(cluster)
+	found := false
+	for _, typ := range order {
+		if typ == comp {
+			found = true
+			continue
+		}
+		if !found || cluster.ComponentSpec(typ) == nil {
+			continue
+		}
+
+		if cluster.ComponentIsSuspending(typ) {
+			return false, fmt.Sprintf("wait another component %s to be resumed", typ)
+		}
+		status := cluster.ComponentStatus(typ)
+		if status == nil {
+			continue
+		}
+		sts := status.GetStatefulSet()
+		if sts == nil || sts.ReadyReplicas < sts.Replicas {
+			return false, fmt.Sprintf("wait another component %s to be ready", typ)
+		}
+	}
+	return true, ""
+}
+
+// updateSuspendStatus summarizes the suspension of the components to the status of the cluster
+func updateSuspendStatus(tc *v1alpha1.TidbCluster) {
+	var (
+		phase     v1alpha1.ClusterSuspendPhase
+		current   v1alpha1.MemberType
+		suspended []v1alpha1.MemberType
+	)
+	for _, typ := range suspendOrderForTC {
+		if tc.ComponentSpec(typ) == nil {
+			continue
+		}
+		needs := needsSuspendComponent(tc, typ)
+		if tc.ComponentIsSuspended(typ) {
+			suspended = append(suspended, typ)
+		}
+		switch {
+		case needs && !tc.ComponentIsSuspended(typ):
+			// the first component not suspended in the order is being suspended
+			if phase != v1alpha1.ClusterSuspending {
+				phase, current = v1alpha1.ClusterSuspending, typ
+			}
+		case !needs && tc.ComponentIsSuspending(typ):
+			// the last component still suspended in the order is the next to be resumed
+			if phase != v1alpha1.ClusterSuspending {
+				phase, current = v1alpha1.ClusterResuming, typ
+			}
+		case needs && phase == "":
+			phase = v1alpha1.ClusterSuspended
+		}
+	}
+
+	if phase == "" {
+		tc.Status.Suspend = nil
+		return
+	}
+	if phase == v1alpha1.ClusterSuspended {
+		current = ""
+	}
+	status := tc.Status.Suspend
+	if status == nil {
+		status = &v1alpha1.ClusterSuspendStatus{}
+		tc.Status.Suspend = status
+	}
+	if status.Phase != phase {
+		status.Phase = phase
+		status.LastTransitionTime = metav1.Now()
+	}
+	status.Component = current
+	status.SuspendedComponents = suspended
+}
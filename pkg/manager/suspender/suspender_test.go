@@ -16,6 +16,7 @@ package suspender
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
@@ -322,6 +323,24 @@ func TestNeedsSuspendComponent(t *testing.T) {
 				g.Expect(need).To(BeFalse())
 			},
 		},
+		"suspend the components depending on the component": {
+			setup: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.SuspendAction.Component = v1alpha1.TiKVMemberType
+			},
+			component: v1alpha1.TiDBMemberType,
+			expect: func(need bool) {
+				g.Expect(need).To(BeTrue())
+			},
+		},
+		"not suspend the components the component depends on": {
+			setup: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.SuspendAction.Component = v1alpha1.TiKVMemberType
+			},
+			component: v1alpha1.PDMemberType,
+			expect: func(need bool) {
+				g.Expect(need).To(BeFalse())
+			},
+		},
 	}
 
 	for name, c := range cases {
@@ -457,3 +476,92 @@ func TestCanSuspendComponent(t *testing.T) {
 		c.expect(can, reason)
 	}
 }
+
+func TestCanResumeComponent(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Name = "test-cluster"
+	tc.Namespace = "test-namespace"
+	tc.Spec.PD = &v1alpha1.PDSpec{}
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{}
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{}
+	tc.Status.PD.Phase = v1alpha1.SuspendPhase
+	tc.Status.TiKV.Phase = v1alpha1.SuspendPhase
+	tc.Status.TiDB.Phase = v1alpha1.SuspendPhase
+
+	// PD is resumed first
+	can, _ := canResumeComponent(tc, v1alpha1.PDMemberType)
+	g.Expect(can).To(BeTrue())
+	can, reason := canResumeComponent(tc, v1alpha1.TiKVMemberType)
+	g.Expect(can).To(BeFalse())
+	g.Expect(reason).To(Equal("wait another component pd to be resumed"))
+
+	// TiKV waits for PD to be ready
+	tc.Status.PD.Phase = v1alpha1.NormalPhase
+	can, reason = canResumeComponent(tc, v1alpha1.TiKVMemberType)
+	g.Expect(can).To(BeFalse())
+	g.Expect(reason).To(Equal("wait another component pd to be ready"))
+
+	tc.Status.PD.StatefulSet = &appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3}
+	can, _ = canResumeComponent(tc, v1alpha1.TiKVMemberType)
+	g.Expect(can).To(BeTrue())
+	can, reason = canResumeComponent(tc, v1alpha1.TiDBMemberType)
+	g.Expect(can).To(BeFalse())
+	g.Expect(reason).To(Equal("wait another component tikv to be resumed"))
+}
+
+func TestUpdateSuspendStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Spec.PD = &v1alpha1.PDSpec{}
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{}
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{}
+	tc.Status.PD.StatefulSet = &appsv1.StatefulSetStatus{}
+	tc.Status.TiKV.StatefulSet = &appsv1.StatefulSetStatus{}
+
+	updateSuspendStatus(tc)
+	g.Expect(tc.Status.Suspend).To(BeNil())
+
+	// TiDB has been suspended and TiKV is being suspended
+	tc.Spec.SuspendAction = &v1alpha1.SuspendAction{SuspendStatefulSet: true, Component: v1alpha1.TiKVMemberType}
+	tc.Status.TiDB.Phase = v1alpha1.SuspendPhase
+	updateSuspendStatus(tc)
+	g.Expect(tc.Status.Suspend.Phase).To(Equal(v1alpha1.ClusterSuspending))
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.TiKVMemberType))
+	g.Expect(tc.Status.Suspend.SuspendedComponents).To(Equal([]v1alpha1.MemberType{v1alpha1.TiDBMemberType}))
+
+	tc.Status.TiKV.Phase = v1alpha1.SuspendPhase
+	tc.Status.TiKV.StatefulSet = nil
+	updateSuspendStatus(tc)
+	g.Expect(tc.Status.Suspend.Phase).To(Equal(v1alpha1.ClusterSuspended))
+	g.Expect(tc.Status.Suspend.Component).To(BeEmpty())
+	g.Expect(tc.Status.Suspend.SuspendedComponents).To(Equal([]v1alpha1.MemberType{v1alpha1.TiDBMemberType, v1alpha1.TiKVMemberType}))
+
+	// TiKV is resumed before TiDB
+	tc.Spec.SuspendAction = nil
+	updateSuspendStatus(tc)
+	g.Expect(tc.Status.Suspend.Phase).To(Equal(v1alpha1.ClusterResuming))
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.TiKVMemberType))
+
+	tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+	tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+	updateSuspendStatus(tc)
+	g.Expect(tc.Status.Suspend).To(BeNil())
+}
+
+func TestGracePeriodLeft(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	status := &v1alpha1.TiDBStatus{}
+	action := &v1alpha1.SuspendAction{SuspendStatefulSet: true}
+	g.Expect(gracePeriodLeft(status, action)).To(BeZero())
+
+	action.GracePeriod = &metav1.Duration{Duration: time.Minute}
+	status.SetCondition(metav1.Condition{Type: v1alpha1.ComponentSuspending, Status: metav1.ConditionTrue})
+	g.Expect(gracePeriodLeft(status, action)).To(BeNumerically(">", 50*time.Second))
+
+	status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	g.Expect(gracePeriodLeft(status, action)).To(BeNumerically("<=", 0))
+}
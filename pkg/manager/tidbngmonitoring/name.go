@@ -32,6 +32,16 @@ func NGMonitoringHeadlessServiceName(tngm string) string {
 	return fmt.Sprintf("%s-ng-monitoring", tngm)
 }
 
+// NGMonitoringDataPVCName return the name of the data pvc of ng monitoring
+func NGMonitoringDataPVCName(tngm string) string {
+	return fmt.Sprintf("ng-monitoring-%s-0", NGMonitoringName(tngm))
+}
+
+// NGMonitoringCleanupJobName return the name of the cleanup job of ng monitoring
+func NGMonitoringCleanupJobName(tngm string) string {
+	return fmt.Sprintf("%s-ng-monitoring-cleanup", tngm)
+}
+
 // TCClientTLSSecretName return name of secret which contains client certs for tc
 func TCClientTLSSecretName(tngm string) string {
 	return fmt.Sprintf("%s-tc-client-tls", tngm)
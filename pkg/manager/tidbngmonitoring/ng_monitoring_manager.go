@@ -68,6 +68,11 @@ func (m *ngMonitoringManager) Sync(tngm *v1alpha1.TidbNGMonitoring, tc *v1alpha1
 		return err
 	}
 
+	err = m.syncStorage(tngm, tc)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -483,7 +488,7 @@ func GenerateNGMonitoringStartScript(tngm *v1alpha1.TidbNGMonitoring, tc *v1alph
 		TNGMName:            tngm.Name,
 		TNGMNamespace:       tngm.Namespace,
 		TNGMClusterDomain:   tngm.Spec.ClusterDomain,
-		TNGMRetentionPeriod: tngm.Spec.NGMonitoring.RetentionPeriod,
+		TNGMRetentionPeriod: tngm.NGMonitoringRetentionPeriod(),
 	}
 
 	script, err := model.RenderStartScript()
@@ -0,0 +1,268 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbngmonitoring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	ngmCleanupContainerName = "cleanup"
+	ngmCleanupVolumeName    = "data"
+)
+
+// syncStorage resizes the data volume to the storage request, runs the cleanup jobs and
+// populates the storage status of ng monitoring
+func (m *ngMonitoringManager) syncStorage(tngm *v1alpha1.TidbNGMonitoring, tc *v1alpha1.TidbCluster) error {
+	if tngm.Spec.Paused {
+		klog.V(4).Infof("tidb ng monitoring %s/%s is paused, skip syncing for ng monitoring storage", tngm.Namespace, tngm.Name)
+		return nil
+	}
+
+	if tngm.Status.NGMonitoring.Storage == nil {
+		tngm.Status.NGMonitoring.Storage = &v1alpha1.NGMonitoringStorageStatus{}
+	}
+
+	if err := m.syncDataVolume(tngm); err != nil {
+		return err
+	}
+	return m.syncCleanupJob(tngm, tc)
+}
+
+// syncDataVolume expands the data volume if the storage request is increased, shrinking is not supported
+func (m *ngMonitoringManager) syncDataVolume(tngm *v1alpha1.TidbNGMonitoring) error {
+	ns := tngm.GetNamespace()
+	status := tngm.Status.NGMonitoring.Storage
+
+	pvcName := NGMonitoringDataPVCName(tngm.Name)
+	pvc, err := m.deps.PVCLister.PersistentVolumeClaims(ns).Get(pvcName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("syncDataVolume: failed to get pvc %s for tidb ng monitoring %s/%s, error: %s", pvcName, ns, tngm.Name, err)
+	}
+
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	status.Capacity = capacity
+	desired, ok := tngm.Spec.NGMonitoring.Requests[corev1.ResourceStorage]
+	if !ok {
+		status.Resizing = false
+		return nil
+	}
+	status.Resizing = capacity.Cmp(desired) < 0
+
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if desired.Cmp(current) <= 0 {
+		return nil
+	}
+	if !m.isVolumeExpansionAllowed(pvc) {
+		klog.Warningf("tidb ng monitoring %s/%s: storage class of pvc %s does not allow volume expansion, skip resizing it from %s to %s",
+			ns, tngm.Name, pvcName, current.String(), desired.String())
+		return nil
+	}
+
+	newPVC := pvc.DeepCopy()
+	if newPVC.Spec.Resources.Requests == nil {
+		newPVC.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	newPVC.Spec.Resources.Requests[corev1.ResourceStorage] = desired
+	if _, err := m.deps.PVCControl.UpdatePVC(tngm, newPVC); err != nil {
+		return fmt.Errorf("syncDataVolume: failed to resize pvc %s for tidb ng monitoring %s/%s, error: %s", pvcName, ns, tngm.Name, err)
+	}
+	klog.Infof("tidb ng monitoring %s/%s: resize pvc %s from %s to %s", ns, tngm.Name, pvcName, current.String(), desired.String())
+	status.Resizing = true
+	return nil
+}
+
+// isVolumeExpansionAllowed returns false only if the storage class of the pvc is known to disallow volume expansion
+func (m *ngMonitoringManager) isVolumeExpansionAllowed(pvc *corev1.PersistentVolumeClaim) bool {
+	if m.deps.StorageClassLister == nil || pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return true
+	}
+	sc, err := m.deps.StorageClassLister.Get(*pvc.Spec.StorageClassName)
+	if err != nil {
+		klog.Warningf("failed to get storage class %s of pvc %s/%s, error: %v", *pvc.Spec.StorageClassName, pvc.Namespace, pvc.Name, err)
+		return true
+	}
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+}
+
+// syncCleanupJob runs a cleanup job in every cleanup interval. The job applies the retention of the
+// continuous profiling data, which is deleted by ng monitoring in the background, and reports the
+// size of the data in its termination message.
+func (m *ngMonitoringManager) syncCleanupJob(tngm *v1alpha1.TidbNGMonitoring, tc *v1alpha1.TidbCluster) error {
+	if tngm.Spec.NGMonitoring.Cleanup == nil {
+		return nil
+	}
+	ns := tngm.GetNamespace()
+	name := tngm.GetName()
+	status := tngm.Status.NGMonitoring.Storage
+
+	jobName := NGMonitoringCleanupJobName(name)
+	job, err := m.deps.JobLister.Jobs(ns).Get(jobName)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("syncCleanupJob: failed to get job %s for tidb ng monitoring %s/%s, error: %s", jobName, ns, name, err)
+	}
+	if err == nil {
+		finished, finishTime := jobFinishTime(job)
+		if !finished {
+			return nil
+		}
+		if job.Status.Succeeded > 0 {
+			if status.LastCleanupTime == nil || status.LastCleanupTime.Time.Before(finishTime) {
+				status.LastCleanupTime = &metav1.Time{Time: finishTime}
+			}
+			if used, err := m.getCleanupJobReportedUsage(tngm); err != nil {
+				klog.Warningf("tidb ng monitoring %s/%s: failed to get the storage usage reported by job %s, error: %v", ns, name, jobName, err)
+			} else if used != nil {
+				status.Used = used
+			}
+		}
+		if time.Since(finishTime) < tngm.NGMonitoringCleanupInterval() {
+			return nil
+		}
+		// the finished job is deleted and a new one is created in the next round
+		return m.deps.JobControl.DeleteJob(tngm, job)
+	}
+
+	// the data volume may be ReadWriteOnce, so the job must run on the node of ng monitoring
+	podName := fmt.Sprintf("%s-0", NGMonitoringName(name))
+	pod, err := m.deps.PodLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("syncCleanupJob: failed to get pod %s for tidb ng monitoring %s/%s, error: %s", podName, ns, name, err)
+	}
+	if pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	return m.deps.JobControl.CreateJob(tngm, GenerateNGMonitoringCleanupJob(tngm, tc, pod.Spec.NodeName))
+}
+
+// getCleanupJobReportedUsage returns the size of the data reported in the termination message of the cleanup job
+func (m *ngMonitoringManager) getCleanupJobReportedUsage(tngm *v1alpha1.TidbNGMonitoring) (*resource.Quantity, error) {
+	selector, err := label.NewTiDBNGMonitoring().Instance(tngm.GetInstanceName()).CleanJob().Selector()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := m.deps.PodLister.Pods(tngm.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != ngmCleanupContainerName || cs.State.Terminated == nil || cs.State.Terminated.ExitCode != 0 {
+				continue
+			}
+			kib, err := strconv.ParseInt(strings.TrimSpace(cs.State.Terminated.Message), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid storage usage %q reported by pod %s", cs.State.Terminated.Message, pod.Name)
+			}
+			return resource.NewQuantity(kib*1024, resource.BinarySI), nil
+		}
+	}
+	return nil, nil
+}
+
+// GenerateNGMonitoringCleanupJob build the cleanup job for ng monitoring
+func GenerateNGMonitoringCleanupJob(tngm *v1alpha1.TidbNGMonitoring, tc *v1alpha1.TidbCluster, nodeName string) *batchv1.Job {
+	jobLabels := label.NewTiDBNGMonitoring().Instance(tngm.GetInstanceName()).CleanJob()
+
+	image := tngm.Spec.NGMonitoring.Cleanup.Image
+	if image == "" {
+		image = tc.HelperImage()
+	}
+
+	var script strings.Builder
+	script.WriteString("set -e\n")
+	if r := tngm.Spec.NGMonitoring.Retention; r != nil && r.ConProf != "" {
+		if d, err := time.ParseDuration(r.ConProf); err == nil {
+			addr := fmt.Sprintf("%s.%s:%d", NGMonitoringHeadlessServiceName(tngm.Name), tngm.Namespace, ngmServicePort)
+			fmt.Fprintf(&script, "wget -q -O - --header 'Content-Type: application/json' --post-data '{\"continuous_profiling\":{\"data_retention_seconds\":%d}}' http://%s/config\n",
+				int64(d.Seconds()), addr)
+		}
+	}
+	fmt.Fprintf(&script, "du -sk %s | cut -f1 > /dev/termination-log\n", ngmPodDataVolumeMountDir)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            NGMonitoringCleanupJobName(tngm.Name),
+			Namespace:       tngm.Namespace,
+			Labels:          jobLabels,
+			OwnerReferences: []metav1.OwnerReference{controller.GetTiDBNGMonitoringOwnerRef(tngm)},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32Ptr(0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					NodeName:      nodeName,
+					Containers: []corev1.Container{
+						{
+							Name:            ngmCleanupContainerName,
+							Image:           image,
+							ImagePullPolicy: tc.HelperImagePullPolicy(),
+							Command:         []string{"/bin/sh", "-c", script.String()},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: ngmCleanupVolumeName, ReadOnly: true, MountPath: ngmPodDataVolumeMountDir},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: ngmCleanupVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: NGMonitoringDataPVCName(tngm.Name),
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobFinishTime returns whether the job is finished and the time it finished
+func jobFinishTime(job *batchv1.Job) (bool, time.Time) {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true, c.LastTransitionTime.Time
+		}
+	}
+	return false, time.Time{}
+}
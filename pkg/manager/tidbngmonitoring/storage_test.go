@@ -0,0 +1,130 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbngmonitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncDataVolume(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	manager := NewNGMonitorManager(deps)
+	tngm := newTidbNGMonitoringForStorage()
+	tngm.Status.NGMonitoring.Storage = &v1alpha1.NGMonitoringStorageStatus{}
+
+	// nothing to do before the pvc is created
+	g.Expect(manager.syncDataVolume(tngm)).To(Succeed())
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: NGMonitoringDataPVCName("ngm"), Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		},
+	}
+	g.Expect(deps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)).To(Succeed())
+
+	// the pvc is resized to the storage request
+	g.Expect(manager.syncDataVolume(tngm)).To(Succeed())
+	g.Expect(tngm.Status.NGMonitoring.Storage.Resizing).To(BeTrue())
+	g.Expect(tngm.Status.NGMonitoring.Storage.Capacity.String()).To(Equal("10Gi"))
+	resized, err := deps.PVCLister.PersistentVolumeClaims("default").Get(pvc.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(resized.Spec.Resources.Requests.Storage().String()).To(Equal("20Gi"))
+
+	// the pvc is not shrunk
+	tngm.Spec.NGMonitoring.Requests[corev1.ResourceStorage] = resource.MustParse("5Gi")
+	g.Expect(manager.syncDataVolume(tngm)).To(Succeed())
+	g.Expect(tngm.Status.NGMonitoring.Storage.Resizing).To(BeFalse())
+	resized, err = deps.PVCLister.PersistentVolumeClaims("default").Get(pvc.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(resized.Spec.Resources.Requests.Storage().String()).To(Equal("20Gi"))
+}
+
+func TestSyncCleanupJob(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	manager := NewNGMonitorManager(deps)
+	tngm := newTidbNGMonitoringForStorage()
+	tngm.Status.NGMonitoring.Storage = &v1alpha1.NGMonitoringStorageStatus{}
+	tc := &v1alpha1.TidbCluster{}
+	jobIndexer := deps.KubeInformerFactory.Batch().V1().Jobs().Informer().GetIndexer()
+	podIndexer := deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+
+	// the job is not created before ng monitoring is scheduled
+	g.Expect(manager.syncCleanupJob(tngm, tc)).To(Succeed())
+	g.Expect(jobIndexer.List()).To(BeEmpty())
+
+	g.Expect(podIndexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ngm-ng-monitoring-0", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+	})).To(Succeed())
+	g.Expect(manager.syncCleanupJob(tngm, tc)).To(Succeed())
+	job, err := deps.JobLister.Jobs("default").Get(NGMonitoringCleanupJobName("ngm"))
+	g.Expect(err).To(Succeed())
+	g.Expect(job.Spec.Template.Spec.NodeName).To(Equal("node-1"))
+	g.Expect(job.Spec.Template.Spec.Containers[0].Command[2]).To(ContainSubstring(`"data_retention_seconds":259200`))
+
+	// the usage reported by the finished job is recorded
+	finishTime := time.Now().Add(-time.Hour)
+	job.Status.Succeeded = 1
+	job.Status.Conditions = []batchv1.JobCondition{{
+		Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Time{Time: finishTime},
+	}}
+	g.Expect(jobIndexer.Update(job)).To(Succeed())
+	g.Expect(podIndexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ngm-ng-monitoring-cleanup-abcde",
+			Namespace: "default",
+			Labels:    label.NewTiDBNGMonitoring().Instance("ngm").CleanJob(),
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  ngmCleanupContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "1024\n"}},
+			}},
+		},
+	})).To(Succeed())
+	g.Expect(manager.syncCleanupJob(tngm, tc)).To(Succeed())
+	g.Expect(tngm.Status.NGMonitoring.Storage.Used.String()).To(Equal("1Mi"))
+	g.Expect(tngm.Status.NGMonitoring.Storage.LastCleanupTime.Time).To(BeTemporally("~", finishTime, time.Second))
+}
+
+func newTidbNGMonitoringForStorage() *v1alpha1.TidbNGMonitoring {
+	tngm := &v1alpha1.TidbNGMonitoring{}
+	tngm.Name = "ngm"
+	tngm.Namespace = "default"
+	tngm.Spec.NGMonitoring.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}
+	tngm.Spec.NGMonitoring.Retention = &v1alpha1.NGMonitoringRetentionSpec{ConProf: "72h"}
+	tngm.Spec.NGMonitoring.Cleanup = &v1alpha1.NGMonitoringCleanupSpec{}
+	return tngm
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ConfigBackupRollingRestart is the reason of the config backups taken before the pods are recreated
+	ConfigBackupRollingRestart = "rolling-restart"
+	// ConfigBackupScaleIn is the reason of the config backups taken before a component is scaled in
+	ConfigBackupScaleIn = "scale-in"
+	// ConfigBackupVolumeReplace is the reason of the config backups taken before the volumes are replaced
+	ConfigBackupVolumeReplace = "volume-replace"
+
+	configBackupUsedBy = "config-backup"
+	// configBackupStatefulSetKey is the key of the spec of the StatefulSet in the config backups
+	configBackupStatefulSetKey = "statefulset.json"
+)
+
+// now can be replaced in unit tests
+var now = time.Now
+
+// destructiveChange returns the reason the update of the StatefulSet is destructive,
+// empty is returned if it's not
+func destructiveChange(newSet, oldSet *apps.StatefulSet) string {
+	if oldSet.Spec.Replicas != nil && newSet.Spec.Replicas != nil && *newSet.Spec.Replicas < *oldSet.Spec.Replicas {
+		return ConfigBackupScaleIn
+	}
+	lastAppliedConfig, ok := oldSet.Annotations[LastAppliedConfigAnnotation]
+	if !ok {
+		return ""
+	}
+	oldSpec := apps.StatefulSetSpec{}
+	if err := json.Unmarshal([]byte(lastAppliedConfig), &oldSpec); err != nil {
+		return ""
+	}
+	delete(oldSpec.Template.Annotations, LastAppliedConfigAnnotation)
+	if !equality.Semantic.DeepEqual(oldSpec.Template, newSet.Spec.Template) {
+		return ConfigBackupRollingRestart
+	}
+	return ""
+}
+
+func configBackupLabels(tc *v1alpha1.TidbCluster, memberType string) label.Label {
+	return label.New().Instance(tc.Name).Component(memberType).UsedBy(configBackupUsedBy)
+}
+
+// BackupConfig backs up the spec of the StatefulSet of a component and the ConfigMaps mounted by
+// the pods into a ConfigMap before a destructive change is applied to the component, if
+// spec.configBackup is set. The content backed up last time is not backed up again, and the
+// oldest backups beyond the limit are deleted.
+func BackupConfig(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, set *apps.StatefulSet, reason string) error {
+	limit := tc.ConfigBackupLimit()
+	if limit == 0 {
+		return nil
+	}
+	ns := tc.Namespace
+	memberType := label.Label(set.Labels).ComponentType()
+
+	specData, err := json.Marshal(set.Spec)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{configBackupStatefulSetKey: string(specData)}
+	configs := map[string]map[string]string{}
+	for _, vol := range set.Spec.Template.Spec.Volumes {
+		if vol.ConfigMap == nil {
+			continue
+		}
+		cm, err := deps.ConfigMapLister.ConfigMaps(ns).Get(vol.ConfigMap.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("backup config: failed to get configmap %s/%s, error: %v", ns, vol.ConfigMap.Name, err)
+		}
+		configs[cm.Name] = cm.Data
+		for k, v := range cm.Data {
+			data[fmt.Sprintf("%s.%s", cm.Name, k)] = v
+		}
+	}
+	// the replicas are excluded so that a scale-in in multiple steps is backed up once
+	digest, err := Sha256Sum(struct {
+		Template corev1.PodTemplateSpec
+		Configs  map[string]map[string]string
+	}{set.Spec.Template, configs})
+	if err != nil {
+		return err
+	}
+
+	selector, err := configBackupLabels(tc, memberType).Selector()
+	if err != nil {
+		return err
+	}
+	backups, err := deps.ConfigMapLister.ConfigMaps(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("backup config: failed to list the config backups of %s/%s, error: %v", ns, set.Name, err)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].CreationTimestamp.Equal(&backups[j].CreationTimestamp) {
+			return backups[i].Name < backups[j].Name
+		}
+		return backups[i].CreationTimestamp.Before(&backups[j].CreationTimestamp)
+	})
+	if len(backups) > 0 && backups[len(backups)-1].Annotations[label.AnnConfigBackupDigestKey] == digest {
+		return nil
+	}
+
+	backup := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-backup-%s", set.Name, now().UTC().Format("20060102150405")),
+			Namespace: ns,
+			Labels:    configBackupLabels(tc, memberType).Labels(),
+			Annotations: map[string]string{
+				label.AnnConfigBackupReasonKey: reason,
+				label.AnnConfigBackupDigestKey: digest,
+			},
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Data: data,
+	}
+	if _, err := deps.ConfigMapControl.CreateConfigMap(tc, backup); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("backup config: failed to create configmap %s/%s, error: %v", ns, backup.Name, err)
+	}
+	klog.Infof("backup config: backed up the config of %s/%s to configmap %s before %s", ns, set.Name, backup.Name, reason)
+	deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "ConfigBackedUp", "backed up the config of %s to configmap %s before %s", set.Name, backup.Name, reason)
+
+	// the backup created is not in the list
+	for i := 0; i < len(backups)+1-limit; i++ {
+		if err := deps.ConfigMapControl.DeleteConfigMap(tc, backups[i]); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("backup config: failed to delete configmap %s/%s, error: %v", ns, backups[i].Name, err)
+		}
+	}
+	return nil
+}
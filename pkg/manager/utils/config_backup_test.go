@@ -0,0 +1,139 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func newStatefulSetForConfigBackup() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic-tikv",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    label.New().Instance("basic").TiKV().Labels(),
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "tikv", Image: "pingcap/tikv:v8.5.0"}},
+					Volumes: []corev1.Volume{{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "basic-tikv-6239346"},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestDestructiveChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	oldSet := newStatefulSetForConfigBackup()
+	g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+
+	newSet := oldSet.DeepCopy()
+	g.Expect(destructiveChange(newSet, oldSet)).To(BeEmpty())
+
+	newSet.Spec.Replicas = pointer.Int32Ptr(4)
+	g.Expect(destructiveChange(newSet, oldSet)).To(BeEmpty())
+
+	newSet.Spec.Replicas = pointer.Int32Ptr(2)
+	g.Expect(destructiveChange(newSet, oldSet)).To(Equal(ConfigBackupScaleIn))
+
+	newSet = oldSet.DeepCopy()
+	newSet.Spec.Template.Spec.Containers[0].Image = "pingcap/tikv:v8.5.1"
+	g.Expect(destructiveChange(newSet, oldSet)).To(Equal(ConfigBackupRollingRestart))
+}
+
+func TestBackupConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	deps.ConfigMapControl = controller.NewRealConfigMapControl(deps.KubeClientset, deps.Recorder)
+	cmIndexer := deps.LabelFilterKubeInformerFactory.Core().V1().ConfigMaps().Informer().GetIndexer()
+	tc := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: metav1.NamespaceDefault}}
+	set := newStatefulSetForConfigBackup()
+	g.Expect(cmIndexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic-tikv-6239346", Namespace: metav1.NamespaceDefault},
+		Data:       map[string]string{"config-file": "[storage]\nreserve-space = \"0MB\"\n"},
+	})).To(Succeed())
+
+	listBackups := func() []corev1.ConfigMap {
+		list, err := deps.KubeClientset.CoreV1().ConfigMaps(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		// sync the backups to the lister
+		for _, obj := range cmIndexer.List() {
+			if cm := obj.(*corev1.ConfigMap); cm.Labels[label.UsedByLabelKey] == configBackupUsedBy {
+				g.Expect(cmIndexer.Delete(cm)).To(Succeed())
+			}
+		}
+		for i := range list.Items {
+			g.Expect(cmIndexer.Add(&list.Items[i])).To(Succeed())
+		}
+		return list.Items
+	}
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+	backup := func(reason string) {
+		current = current.Add(time.Minute)
+		g.Expect(BackupConfig(deps, tc, set, reason)).To(Succeed())
+	}
+
+	// backups are disabled
+	backup(ConfigBackupScaleIn)
+	g.Expect(listBackups()).To(BeEmpty())
+
+	tc.Spec.ConfigBackup = &v1alpha1.ConfigBackupPolicy{Limit: pointer.Int32Ptr(2)}
+	backup(ConfigBackupScaleIn)
+	backups := listBackups()
+	g.Expect(backups).To(HaveLen(1))
+	g.Expect(backups[0].Name).To(Equal("basic-tikv-backup-20240101000200"))
+	g.Expect(backups[0].Annotations[label.AnnConfigBackupReasonKey]).To(Equal(ConfigBackupScaleIn))
+	g.Expect(backups[0].Data).To(HaveKey(configBackupStatefulSetKey))
+	g.Expect(backups[0].Data).To(HaveKeyWithValue("basic-tikv-6239346.config-file", "[storage]\nreserve-space = \"0MB\"\n"))
+
+	// the same content is not backed up again, even if the replicas change
+	set.Spec.Replicas = pointer.Int32Ptr(2)
+	backup(ConfigBackupScaleIn)
+	g.Expect(listBackups()).To(HaveLen(1))
+
+	// the oldest backups beyond the limit are deleted
+	for _, image := range []string{"pingcap/tikv:v8.5.1", "pingcap/tikv:v8.5.2"} {
+		set.Spec.Template.Spec.Containers[0].Image = image
+		backup(ConfigBackupRollingRestart)
+		listBackups()
+	}
+	backups = listBackups()
+	g.Expect(backups).To(HaveLen(2))
+	g.Expect([]string{backups[0].Name, backups[1].Name}).To(ConsistOf("basic-tikv-backup-20240101000400", "basic-tikv-backup-20240101000500"))
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -105,9 +106,45 @@ func UpdateStatefulSetWithPrecheck(
 		return fmt.Errorf("contains volumeMounts that do not have matched volume: %v", notExistMount)
 	}
 
+	if restartAt, held := holdScheduledRestart(newTiDBSet, oldTiDBSet, time.Now()); held {
+		// sync the cluster at the scheduled time rather than waiting for the next resync
+		deps.Requeuer.RequeueAfter(controller.ControllerKind.Kind, tc, time.Until(restartAt))
+	}
+
+	if reason := destructiveChange(newTiDBSet, oldTiDBSet); reason != "" {
+		if err := BackupConfig(deps, tc, oldTiDBSet, reason); err != nil {
+			return err
+		}
+	}
+
 	return UpdateStatefulSet(deps.StatefulSetControl, tc, newTiDBSet, oldTiDBSet)
 }
 
+// holdScheduledRestart keeps the restart-at annotation of the pod template of the old statefulset until the
+// time of the new one is reached, so that the pods are rolling restarted by the upgrader at the scheduled time.
+// It returns the scheduled time and whether the restart is held, the caller should sync again at the time.
+func holdScheduledRestart(newSet, oldSet *apps.StatefulSet, now time.Time) (time.Time, bool) {
+	value, ok := newSet.Spec.Template.Annotations[label.AnnRestartAtKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	oldValue, oldOK := oldSet.Spec.Template.Annotations[label.AnnRestartAtKey]
+	if oldOK && oldValue == value {
+		return time.Time{}, false
+	}
+	restartAt, err := time.Parse(time.RFC3339, value)
+	if err != nil || !restartAt.After(now) {
+		return time.Time{}, false
+	}
+	klog.Infof("statefulset %s/%s: the rolling restart is scheduled at %s", newSet.Namespace, newSet.Name, value)
+	if oldOK {
+		newSet.Spec.Template.Annotations[label.AnnRestartAtKey] = oldValue
+	} else {
+		delete(newSet.Spec.Template.Annotations, label.AnnRestartAtKey)
+	}
+	return restartAt, true
+}
+
 // UpdateStatefulSet is a template function to update the statefulset of components
 func UpdateStatefulSet(setCtl controller.StatefulSetControlInterface, object runtime.Object, newSet, oldSet *apps.StatefulSet) error {
 	isOrphan := metav1.GetControllerOf(oldSet) == nil
@@ -117,6 +154,8 @@ func UpdateStatefulSet(setCtl controller.StatefulSetControlInterface, object run
 	if oldSet.Annotations == nil {
 		oldSet.Annotations = map[string]string{}
 	}
+	// the labels and annotations synced by the meta manager are not in the new statefulset
+	controller.RetainSyncedMeta(newSet, oldSet)
 
 	// Check if an upgrade is needed.
 	// If not, early return.
@@ -15,8 +15,10 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -167,3 +169,71 @@ func TestNotExistMount(t *testing.T) {
 	mp = notExistMount(newSTS, oldSTS)
 	g.Expect(mp).ShouldNot(BeEmpty())
 }
+
+func TestHoldScheduledRestart(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now, _ := time.Parse(time.RFC3339, "2024-05-01T02:00:00Z")
+
+	newSetWith := func(anns map[string]string) *apps.StatefulSet {
+		return &apps.StatefulSet{
+			Spec: apps.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: anns},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		oldAnns  map[string]string
+		newAnns  map[string]string
+		expected map[string]string
+		held     bool
+	}{
+		{
+			name:     "no restart",
+			oldAnns:  map[string]string{"foo": "bar"},
+			newAnns:  map[string]string{"foo": "bar"},
+			expected: map[string]string{"foo": "bar"},
+		},
+		{
+			name:     "first restart in the future",
+			oldAnns:  map[string]string{"foo": "bar"},
+			newAnns:  map[string]string{"foo": "bar", label.AnnRestartAtKey: "2024-05-01T03:00:00Z"},
+			expected: map[string]string{"foo": "bar"},
+			held:     true,
+		},
+		{
+			name:     "restart in the future",
+			oldAnns:  map[string]string{label.AnnRestartAtKey: "2024-04-01T03:00:00Z"},
+			newAnns:  map[string]string{label.AnnRestartAtKey: "2024-05-01T03:00:00Z"},
+			expected: map[string]string{label.AnnRestartAtKey: "2024-04-01T03:00:00Z"},
+			held:     true,
+		},
+		{
+			name:     "restart time reached",
+			oldAnns:  map[string]string{label.AnnRestartAtKey: "2024-04-01T03:00:00Z"},
+			newAnns:  map[string]string{label.AnnRestartAtKey: "2024-05-01T02:00:00Z"},
+			expected: map[string]string{label.AnnRestartAtKey: "2024-05-01T02:00:00Z"},
+		},
+		{
+			name:     "restart already applied",
+			oldAnns:  map[string]string{label.AnnRestartAtKey: "2024-05-01T03:00:00Z"},
+			newAnns:  map[string]string{label.AnnRestartAtKey: "2024-05-01T03:00:00Z"},
+			expected: map[string]string{label.AnnRestartAtKey: "2024-05-01T03:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newSet := newSetWith(tt.newAnns)
+			restartAt, held := holdScheduledRestart(newSet, newSetWith(tt.oldAnns), now)
+			g.Expect(newSet.Spec.Template.Annotations).To(Equal(tt.expected))
+			g.Expect(held).To(Equal(tt.held))
+			if held {
+				g.Expect(restartAt.Format(time.RFC3339)).To(Equal(tt.newAnns[label.AnnRestartAtKey]))
+			}
+		})
+	}
+}
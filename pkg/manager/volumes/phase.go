@@ -112,6 +112,9 @@ func (p *podVolModifier) validate(vol *ActualVolume) error {
 	case result < 0:
 		return fmt.Errorf("can't shrunk size from %s to %s", &actual, &desired)
 	case result > 0:
+		if p.deps != nil && p.deps.Capabilities != nil && !p.deps.Capabilities.VolumeExpansion {
+			return fmt.Errorf("volume expansion is not supported by Kubernetes %s", p.deps.Capabilities.ServerVersion)
+		}
 		supported, err := isVolumeExpansionSupported(vol.StorageClass)
 		if err != nil {
 			klog.Warningf("volume expansion of storage class %s may be not supported, but it will be tried", vol.GetStorageClassName())
@@ -72,6 +72,70 @@ func (p *pvcReplacer) getVolReplaceStatusForComponent(tc *v1alpha1.TidbCluster,
 	return false, nil
 }
 
+// getVolReplaceProgressForComponent returns the progress of the volume replacement of the component
+func (p *pvcReplacer) getVolReplaceProgressForComponent(tc *v1alpha1.TidbCluster, comp v1alpha1.ComponentStatus) (*v1alpha1.VolumeReplaceProgress, error) {
+	ctx, err := p.utils.BuildContextForTC(tc, comp)
+	if err != nil {
+		return nil, err
+	}
+	progress := &v1alpha1.VolumeReplaceProgress{
+		Component: comp.MemberType(),
+		Pods:      int32(len(ctx.pods)),
+	}
+	for _, pod := range ctx.pods {
+		podSynced, err := p.utils.IsPodSyncedForReplacement(ctx, pod)
+		if err != nil {
+			return nil, err
+		}
+		if podSynced {
+			progress.ReplacedPods++
+		}
+		if isVolumeReplacing(pod) {
+			progress.ReplacingPod = pod.Name
+		}
+	}
+	return progress, nil
+}
+
+// updateVolReplaceProgress records the progress of the volume replacement of the component in the status,
+// the progress is removed once the replacement is completed
+func (p *pvcReplacer) updateVolReplaceProgress(tc *v1alpha1.TidbCluster, comp v1alpha1.ComponentStatus, inProgress bool) error {
+	var old *v1alpha1.VolumeReplaceProgress
+	progresses := make([]v1alpha1.VolumeReplaceProgress, 0, len(tc.Status.VolumeReplaces))
+	for i := range tc.Status.VolumeReplaces {
+		if tc.Status.VolumeReplaces[i].Component == comp.MemberType() {
+			old = &tc.Status.VolumeReplaces[i]
+			continue
+		}
+		progresses = append(progresses, tc.Status.VolumeReplaces[i])
+	}
+
+	if inProgress {
+		progress, err := p.getVolReplaceProgressForComponent(tc, comp)
+		if err != nil {
+			return err
+		}
+		if old != nil {
+			progress.StartTime = old.StartTime
+		} else {
+			now := metav1.Now()
+			progress.StartTime = &now
+			p.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "VolumeReplaceStarted",
+				"started to replace the volumes of %s, %d/%d pods are replaced", comp.MemberType(), progress.ReplacedPods, progress.Pods)
+		}
+		progresses = append(progresses, *progress)
+	} else if old != nil {
+		p.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "VolumeReplaceCompleted",
+			"the volumes of %d pods of %s are replaced", old.Pods, comp.MemberType())
+	}
+
+	if len(progresses) == 0 {
+		progresses = nil
+	}
+	tc.Status.VolumeReplaces = progresses
+	return nil
+}
+
 func (p *pvcReplacer) UpdateStatus(tc *v1alpha1.TidbCluster) error {
 	components := tc.AllComponentStatus()
 	errs := []error{}
@@ -99,6 +163,9 @@ func (p *pvcReplacer) UpdateStatus(tc *v1alpha1.TidbCluster) error {
 			klog.Infof("changing VolReplaceInProgress status to %t for %s/%s/%s", status, tc.GetNamespace(), tc.GetName(), comp.MemberType())
 		}
 		comp.SetVolReplaceInProgress(status)
+		if err := p.updateVolReplaceProgress(tc, comp, status); err != nil {
+			klog.Warningf("failed to get the volume replace progress of %s/%s:%s: %v", tc.GetNamespace(), tc.GetName(), comp.MemberType(), err)
+		}
 	}
 
 	return errutil.NewAggregate(errs)
@@ -137,6 +204,9 @@ func (p *pvcReplacer) replaceVolumes(ctx *componentVolumeContext) error {
 		// for replacing here to effect the config + volume change together.
 		return fmt.Errorf("component phase is Scaling, waiting to complete.")
 	}
+	if err := utils.BackupConfig(p.deps, ctx.tc, ctx.sts, utils.ConfigBackupVolumeReplace); err != nil {
+		return err
+	}
 	if err := p.tryToRecreateSTS(ctx); err != nil {
 		return err
 	}
@@ -185,6 +185,14 @@ func TestPvcReplacerStatus(t *testing.T) {
 		tc := makeTcAndK8Objects(deps, g, tt.sts, tt.pods)
 		replacer.UpdateStatus(tc)
 		g.Expect(tc.Status.TiKV.VolReplaceInProgress).To(Equal(tt.expectStatus))
+		if tt.expectStatus {
+			g.Expect(tc.Status.VolumeReplaces).To(HaveLen(1))
+			g.Expect(tc.Status.VolumeReplaces[0].Component).To(Equal(v1alpha1.TiKVMemberType))
+			g.Expect(tc.Status.VolumeReplaces[0].Pods).To(Equal(int32(len(tt.pods))))
+			g.Expect(tc.Status.VolumeReplaces[0].StartTime).NotTo(BeNil())
+		} else {
+			g.Expect(tc.Status.VolumeReplaces).To(BeEmpty())
+		}
 	}
 	tests := []testcase{
 		{
@@ -291,6 +299,40 @@ func TestPvcReplacerStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestPvcReplacerProgress(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	stop := make(chan struct{})
+	deps.KubeInformerFactory.Start(stop)
+	deps.KubeInformerFactory.WaitForCacheSync(stop)
+	defer close(stop)
+	replacer := NewPVCReplacer(deps)
+	tc := makeTcAndK8Objects(deps, g,
+		testSts{replicas: 3, vols: []testVolDef{{"tikv", "1Gi", "storageclass-2"}}},
+		[]testPod{
+			{vols: []testVolDef{{"tikv", "1Gi", "storageclass-2"}}},
+			{vols: []testVolDef{{"tikv", "1Gi", "storageclass-1"}}},
+			{vols: []testVolDef{{"tikv", "1Gi", "storageclass-1"}}},
+		})
+	tc.Spec.TiKV.StorageClassName = pointer.StringPtr("storageclass-2")
+
+	g.Expect(replacer.UpdateStatus(tc)).To(Succeed())
+	g.Expect(tc.Status.VolumeReplaces).To(HaveLen(1))
+	progress := tc.Status.VolumeReplaces[0]
+	g.Expect(progress.Pods).To(Equal(int32(3)))
+	g.Expect(progress.ReplacedPods).To(Equal(int32(1)))
+	g.Expect(progress.StartTime).NotTo(BeNil())
+
+	// the start time is kept until the replacement is completed
+	g.Expect(replacer.UpdateStatus(tc)).To(Succeed())
+	g.Expect(tc.Status.VolumeReplaces[0].StartTime).To(Equal(progress.StartTime))
+
+	// the progress is removed once the replacement is completed
+	g.Expect(replacer.(*pvcReplacer).updateVolReplaceProgress(tc, &tc.Status.TiKV, false)).To(Succeed())
+	g.Expect(tc.Status.VolumeReplaces).To(BeEmpty())
+}
+
 func TestPvcReplacerSync(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
@@ -0,0 +1,44 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	KubeClientThrottledRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "kube_client",
+			Name:      "throttled_requests_total",
+			Help:      "Number of requests to the kube-apiserver delayed by the rate limiter of each namespace",
+		}, []string{LabelNamespace})
+
+	KubeClientThrottledSeconds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "kube_client",
+			Name:      "throttled_seconds_total",
+			Help:      "Total time the requests to the kube-apiserver are delayed by the rate limiter of each namespace",
+		}, []string{LabelNamespace})
+
+	KubeClientRateLimiterTokens = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "kube_client",
+			Name:      "rate_limiter_tokens",
+			Help:      "Number of tokens left in the rate limiter of each namespace, negative if requests are waiting",
+		}, []string{LabelNamespace})
+)
@@ -79,5 +79,17 @@ func init() {
 
 		ClusterSpecReplicas,
 		ClusterUpdateErrors,
+		ClusterTiKVSlowStores,
+
+		OrphanResources,
+		OrphanResourcesDeleted,
+
+		KubeClientThrottledRequests,
+		KubeClientThrottledSeconds,
+		KubeClientRateLimiterTokens,
+
+		PDClientRequests,
+		PDClientRequestDuration,
+		PDClientCircuitBreakerOpen,
 	)
 }
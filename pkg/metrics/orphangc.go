@@ -0,0 +1,41 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// LabelKind is the kind of the Kubernetes resources
+	LabelKind = "kind"
+)
+
+var (
+	OrphanResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "orphan_gc",
+			Name:      "orphan_resources",
+			Help:      "Number of the resources managed by tidb-operator whose owning CRs no longer exist, found in the last collection",
+		}, []string{LabelNamespace, LabelKind})
+
+	OrphanResourcesDeleted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "orphan_gc",
+			Name:      "deleted_resources",
+			Help:      "Number of the orphan resources deleted by the orphan garbage collector",
+		}, []string{LabelNamespace, LabelKind})
+)
@@ -0,0 +1,56 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// LabelPD is the label of the address of the PD cluster requested by the operator
+	LabelPD = "pd"
+	// LabelEndpoint is the label of the API endpoint requested by the operator
+	LabelEndpoint = "endpoint"
+	// LabelMethod is the label of the HTTP method of the request
+	LabelMethod = "method"
+	// LabelResult is the label of the result of the request
+	LabelResult = "result"
+)
+
+var (
+	PDClientRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "pd_client",
+			Name:      "requests_total",
+			Help:      "Number of requests to PD by the operator, the result is the status code, error or rejected by the circuit breaker",
+		}, []string{LabelPD, LabelEndpoint, LabelMethod, LabelResult})
+
+	PDClientRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "pd_client",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the requests to PD by the operator",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{LabelPD, LabelEndpoint, LabelMethod})
+
+	PDClientCircuitBreakerOpen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "pd_client",
+			Name:      "circuit_breaker_open",
+			Help:      "Whether the requests to the PD cluster are rejected by the circuit breaker after consecutive failures",
+		}, []string{LabelPD})
+)
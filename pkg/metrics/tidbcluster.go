@@ -33,4 +33,12 @@ var (
 			Name:      "update_errors",
 			Help:      "Number of errors generated in each stage when updating TiDB Clusters",
 		}, []string{LabelNamespace, LabelName, LabelComponent})
+
+	ClusterTiKVSlowStores = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "tikv_slow_stores",
+			Help:      "Number of the TiKV stores flagged as slow by spec.tikv.slowStoreDetection in TidbCluster",
+		}, []string{LabelNamespace, LabelName})
 )
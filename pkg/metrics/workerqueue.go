@@ -141,3 +141,9 @@ func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name str
 func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
 	return retries.WithLabelValues(name)
 }
+
+// WorkQueueMetricsProvider returns the provider of the metrics of the work queues, it's used by the
+// work queues implemented by tidb-operator to report the same metrics as the ones of client-go.
+func WorkQueueMetricsProvider() workqueue.MetricsProvider {
+	return workqueueMetricsProvider{}
+}
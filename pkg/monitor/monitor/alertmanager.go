@@ -0,0 +1,241 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	alertmanagerPort = 9093
+	// alertmanagerConfigKey is the key of the config of Alertmanager in the ConfigMap
+	alertmanagerConfigKey = "alertmanager.yml"
+
+	// extraRulesPath is the directory the ConfigMaps of spec.extraAlertRules are mounted in,
+	// each ConfigMap is mounted in a sub directory so that the keys of them don't conflict
+	extraRulesPath = "/prometheus-extra-rules"
+
+	// defaultAlertmanagerConfig receives the alerts without sending them to anywhere,
+	// the alerts can be viewed in the UI of Alertmanager
+	defaultAlertmanagerConfig = `route:
+  receiver: "null"
+  group_by: ["alertname", "cluster"]
+receivers:
+  - name: "null"
+`
+)
+
+func GetAlertmanagerConfigMapName(monitor *v1alpha1.TidbMonitor) string {
+	return fmt.Sprintf("%s-monitor-alertmanager", monitor.Name)
+}
+
+func AlertmanagerName(name string, shard int32) string {
+	base := fmt.Sprintf("%s-alertmanager", name)
+	if shard == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s-alertmanager-shard-%d", name, shard)
+}
+
+func buildTidbMonitorAlertmanagerLabel(name string) map[string]string {
+	return label.NewMonitor().Instance(name).Monitor().UsedBy("alertmanager").Labels()
+}
+
+// getAlertmanagerURL returns the address of the Alertmanager the alerts are sent to,
+// Alertmanager deployed runs in the same pod as Prometheus
+func getAlertmanagerURL(monitor *v1alpha1.TidbMonitor) string {
+	if monitor.Spec.Alertmanager != nil {
+		if monitor.Spec.Alertmanager.URL != nil {
+			return *monitor.Spec.Alertmanager.URL
+		}
+		return fmt.Sprintf("127.0.0.1:%d", alertmanagerPort)
+	}
+	if monitor.Spec.AlertmanagerURL != nil {
+		return *monitor.Spec.AlertmanagerURL
+	}
+	return ""
+}
+
+// getAlertmanagerConfigMap returns the default config of Alertmanager deployed,
+// nil is returned if the config is referenced from a ConfigMap of the user
+func getAlertmanagerConfigMap(monitor *v1alpha1.TidbMonitor) *core.ConfigMap {
+	if !monitor.AlertmanagerDeployed() || monitor.Spec.Alertmanager.ConfigMapRef != nil {
+		return nil
+	}
+	return &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            GetAlertmanagerConfigMapName(monitor),
+			Namespace:       monitor.Namespace,
+			Labels:          buildTidbMonitorAlertmanagerLabel(monitor.Name),
+			OwnerReferences: []meta.OwnerReference{controller.GetTiDBMonitorOwnerRef(monitor)},
+		},
+		Data: map[string]string{
+			alertmanagerConfigKey: defaultAlertmanagerConfig,
+		},
+	}
+}
+
+func getMonitorAlertmanagerContainer(monitor *v1alpha1.TidbMonitor) core.Container {
+	spec := monitor.Spec.Alertmanager
+	commands := []string{
+		"/bin/alertmanager",
+		fmt.Sprintf("--config.file=/etc/alertmanager/config/%s", alertmanagerConfigKey),
+		"--storage.path=/data/alertmanager",
+		fmt.Sprintf("--web.listen-address=:%d", alertmanagerPort),
+		// every pod runs a standalone Alertmanager
+		"--cluster.listen-address=",
+	}
+	if len(spec.LogLevel) > 0 {
+		commands = append(commands, fmt.Sprintf("--log.level=%s", spec.LogLevel))
+	}
+	c := core.Container{
+		Name:      "alertmanager",
+		Image:     fmt.Sprintf("%s:%s", spec.BaseImage, spec.Version),
+		Resources: controller.ContainerResource(spec.ResourceRequirements),
+		Command:   commands,
+		Ports: []core.ContainerPort{
+			{
+				Name:          "alertmanager",
+				ContainerPort: alertmanagerPort,
+				Protocol:      core.ProtocolTCP,
+			},
+		},
+		Env: []core.EnvVar{
+			{
+				Name:  "TZ",
+				Value: monitor.Timezone(),
+			},
+		},
+		VolumeMounts: []core.VolumeMount{
+			{
+				Name:      v1alpha1.TidbMonitorMemberType.String(),
+				MountPath: "/data",
+			},
+			{
+				Name:      "alertmanager-config",
+				MountPath: "/etc/alertmanager/config",
+				ReadOnly:  true,
+			},
+		},
+		ReadinessProbe: &core.Probe{
+			ProbeHandler: core.ProbeHandler{
+				HTTPGet: &core.HTTPGetAction{
+					Path: "/-/ready",
+					Port: intstr.FromInt(alertmanagerPort),
+				},
+			},
+			TimeoutSeconds: 3,
+			PeriodSeconds:  5,
+		},
+	}
+	if spec.ImagePullPolicy != nil {
+		c.ImagePullPolicy = *spec.ImagePullPolicy
+	}
+	if spec.AdditionalVolumeMounts != nil {
+		c.VolumeMounts = append(c.VolumeMounts, spec.AdditionalVolumeMounts...)
+	}
+	return c
+}
+
+// getAlertmanagerVolumes returns the volumes of the config of Alertmanager deployed and the extra alert rules
+func getAlertmanagerVolumes(monitor *v1alpha1.TidbMonitor) []core.Volume {
+	var volumes []core.Volume
+	if monitor.AlertmanagerDeployed() {
+		configMapName := GetAlertmanagerConfigMapName(monitor)
+		if monitor.Spec.Alertmanager.ConfigMapRef != nil {
+			configMapName = monitor.Spec.Alertmanager.ConfigMapRef.Name
+		}
+		volumes = append(volumes, core.Volume{
+			Name: "alertmanager-config",
+			VolumeSource: core.VolumeSource{
+				ConfigMap: &core.ConfigMapVolumeSource{
+					LocalObjectReference: core.LocalObjectReference{Name: configMapName},
+				},
+			},
+		})
+	}
+	for i, ref := range monitor.Spec.ExtraAlertRules {
+		volumes = append(volumes, core.Volume{
+			Name: extraRulesVolumeName(i),
+			VolumeSource: core.VolumeSource{
+				ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: ref},
+			},
+		})
+	}
+	return volumes
+}
+
+// getExtraRulesVolumeMounts returns the volume mounts of the ConfigMaps of spec.extraAlertRules
+func getExtraRulesVolumeMounts(monitor *v1alpha1.TidbMonitor) []core.VolumeMount {
+	var mounts []core.VolumeMount
+	for i := range monitor.Spec.ExtraAlertRules {
+		mounts = append(mounts, core.VolumeMount{
+			Name:      extraRulesVolumeName(i),
+			MountPath: fmt.Sprintf("%s/%d", extraRulesPath, i),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+func extraRulesVolumeName(index int) string {
+	return fmt.Sprintf("extra-rules-%d", index)
+}
+
+func getAlertmanagerService(monitor *v1alpha1.TidbMonitor, shard int32, selector map[string]string) *core.Service {
+	spec := monitor.Spec.Alertmanager
+	portName := "http-alertmanager"
+	if monitor.BaseAlertmanagerSpec().PortName() != nil {
+		portName = *monitor.BaseAlertmanagerSpec().PortName()
+	}
+	alertmanagerLabel := label.NewMonitor().Instance(monitor.Name).Monitor().UsedBy("alertmanager")
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            AlertmanagerName(monitor.Name, shard),
+			Namespace:       monitor.Namespace,
+			Labels:          util.CombineStringMap(alertmanagerLabel.Labels(), spec.Service.Labels, monitor.Spec.Labels),
+			OwnerReferences: []meta.OwnerReference{controller.GetTiDBMonitorOwnerRef(monitor)},
+			Annotations:     util.CombineStringMap(spec.Service.Annotations, monitor.Spec.Annotations),
+		},
+		Spec: core.ServiceSpec{
+			Ports: []core.ServicePort{
+				{
+					Name:       portName,
+					Port:       alertmanagerPort,
+					Protocol:   core.ProtocolTCP,
+					TargetPort: intstr.FromInt(alertmanagerPort),
+				},
+			},
+			Type:     spec.Service.Type,
+			Selector: selector,
+		},
+	}
+	if monitor.BaseAlertmanagerSpec().ServiceType() == core.ServiceTypeLoadBalancer {
+		if spec.Service.LoadBalancerIP != nil {
+			svc.Spec.LoadBalancerIP = *spec.Service.LoadBalancerIP
+		}
+		if spec.Service.LoadBalancerSourceRanges != nil {
+			svc.Spec.LoadBalancerSourceRanges = spec.Service.LoadBalancerSourceRanges
+		}
+	}
+	return svc
+}
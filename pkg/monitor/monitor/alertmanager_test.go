@@ -0,0 +1,114 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func newTidbMonitorWithAlertmanager() *v1alpha1.TidbMonitor {
+	return &v1alpha1.TidbMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns"},
+		Spec: v1alpha1.TidbMonitorSpec{
+			Prometheus: v1alpha1.PrometheusSpec{MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "prom/prometheus", Version: "v2.27.1"}},
+			Reloader:   v1alpha1.ReloaderSpec{MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "pingcap/tidb-monitor-reloader", Version: "v1.0.1"}},
+			Alertmanager: &v1alpha1.AlertmanagerSpec{
+				MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "prom/alertmanager", Version: "v0.27.0"},
+			},
+			ExtraAlertRules: []corev1.LocalObjectReference{{Name: "team-rules"}, {Name: "slo-rules"}},
+		},
+	}
+}
+
+func TestAlertmanagerPrometheusConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithAlertmanager()
+	cm, err := getPromConfigMap(monitor, []ClusterRegexInfo{{Name: "basic", Namespace: "ns"}}, nil, 0, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	cfg := map[string]interface{}{}
+	g.Expect(yaml.Unmarshal([]byte(cm.Data["prometheus.yml"]), &cfg)).To(Succeed())
+	g.Expect(cfg["rule_files"]).To(Equal([]interface{}{
+		"/prometheus-rules/rules/*.rules.yml",
+		"/prometheus-extra-rules/*/*.rules.yml",
+	}))
+	g.Expect(cm.Data["prometheus.yml"]).To(ContainSubstring("127.0.0.1:9093"))
+
+	// the external alertmanager takes precedence over alertmanagerURL
+	monitor.Spec.AlertmanagerURL = pointer.StringPtr("legacy:9093")
+	monitor.Spec.Alertmanager.URL = pointer.StringPtr("alertmanager.monitoring:9093")
+	cm, err = getPromConfigMap(monitor, nil, nil, 0, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data["prometheus.yml"]).To(ContainSubstring("alertmanager.monitoring:9093"))
+	g.Expect(cm.Data["prometheus.yml"]).NotTo(ContainSubstring("legacy:9093"))
+}
+
+func TestAlertmanagerStatefulSet(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithAlertmanager()
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "foo-monitor"}}
+	tc := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "ns"}}
+	sts, err := getMonitorStatefulSet(sa, nil, monitor, tc, nil, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	containers := map[string]corev1.Container{}
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		containers[c.Name] = c
+	}
+	g.Expect(containers).To(HaveKey("alertmanager"))
+	g.Expect(containers["alertmanager"].Image).To(Equal("prom/alertmanager:v0.27.0"))
+	g.Expect(containers["prometheus"].VolumeMounts).To(ContainElements(
+		corev1.VolumeMount{Name: "extra-rules-0", MountPath: "/prometheus-extra-rules/0", ReadOnly: true},
+		corev1.VolumeMount{Name: "extra-rules-1", MountPath: "/prometheus-extra-rules/1", ReadOnly: true},
+	))
+
+	volumes := map[string]corev1.Volume{}
+	for _, v := range sts.Spec.Template.Spec.Volumes {
+		volumes[v.Name] = v
+	}
+	g.Expect(volumes["alertmanager-config"].ConfigMap.Name).To(Equal("foo-monitor-alertmanager"))
+	g.Expect(volumes["extra-rules-1"].ConfigMap.Name).To(Equal("slo-rules"))
+
+	cm := getAlertmanagerConfigMap(monitor)
+	g.Expect(cm.Name).To(Equal("foo-monitor-alertmanager"))
+	g.Expect(cm.Data).To(HaveKey("alertmanager.yml"))
+
+	var names []string
+	for _, svc := range getMonitorService(monitor) {
+		names = append(names, svc.Name)
+	}
+	g.Expect(names).To(ContainElement("foo-alertmanager"))
+
+	// the config of the user is mounted
+	monitor.Spec.Alertmanager.ConfigMapRef = &corev1.LocalObjectReference{Name: "my-alertmanager"}
+	g.Expect(getAlertmanagerConfigMap(monitor)).To(BeNil())
+	g.Expect(getAlertmanagerVolumes(monitor)[0].ConfigMap.Name).To(Equal("my-alertmanager"))
+
+	// nothing is deployed for an external alertmanager
+	monitor.Spec.Alertmanager.URL = pointer.StringPtr("alertmanager.monitoring:9093")
+	sts, err = getMonitorStatefulSet(sa, nil, monitor, tc, nil, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		g.Expect(c.Name).NotTo(Equal("alertmanager"))
+	}
+	g.Expect(getAlertmanagerConfigMap(monitor)).To(BeNil())
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// grafanaExportVolume is the volume the initializers generate the exported dashboards and datasources in
+	grafanaExportVolume = "grafana-export"
+	grafanaExportPath   = "/grafana-export"
+
+	grafanaExporterName = "grafana-exporter"
+)
+
+var (
+	defaultGrafanaDashboardLabels  = map[string]string{"grafana_dashboard": "1"}
+	defaultGrafanaDatasourceLabels = map[string]string{"grafana_datasource": "1"}
+)
+
+// grafanaExportInitCommand runs the initializer again to generate the dashboards and datasources
+// querying the Prometheus URL reachable from the external Grafana
+const grafanaExportInitCommand = `
+mkdir -p /grafana-export/dashboards /grafana-export/datasources
+GF_PROVISIONING_PATH=/grafana-export/dashboards \
+GF_DATASOURCE_PATH=/grafana-export/datasources \
+GF_TIDB_PROMETHEUS_URL=${GF_EXPORT_PROMETHEUS_URL} \
+GF_DM_PROMETHEUS_URL=${GF_EXPORT_PROMETHEUS_URL} \
+/usr/bin/init.sh`
+
+// grafanaExporterCommand applies a ConfigMap for each of the generated files, server-side apply is used
+// so that the large dashboards aren't duplicated in the last-applied-configuration annotation
+const grafanaExporterCommand = `set -e
+export_configmaps() {
+  for f in "$1"/*; do
+    [ -f "$f" ] || continue
+    name=$(basename "$f" | sed 's/\.[^.]*$//' | tr '[:upper:]_.' '[:lower:]--')
+    kubectl create configmap "${CONFIGMAP_PREFIX}-$2-${name}" --namespace="${NAMESPACE}" --from-file="$f" --dry-run=client -o json \
+      | kubectl patch --local -f - --type=merge -p "$3" -o json \
+      | kubectl apply --server-side --force-conflicts --field-manager=tidb-monitor -f -
+  done
+}
+export_configmaps /grafana-export/dashboards dashboard "${DASHBOARD_METADATA}"
+export_configmaps /grafana-export/datasources datasource "${DATASOURCE_METADATA}"`
+
+// getGrafanaExportPrometheusURL returns the address the exported datasources query, the loopback
+// address Grafana in the TidbMonitor pods queries is not reachable from the external Grafana
+func getGrafanaExportPrometheusURL(monitor *v1alpha1.TidbMonitor) string {
+	if url := monitor.Spec.GrafanaExport.PrometheusURL; url != nil {
+		return *url
+	}
+	if monitor.ThanosQueryDeployed() {
+		return getThanosQueryURL(monitor)
+	}
+	return fmt.Sprintf("http://%s.%s:9090", PrometheusName(monitor.Name, 0), monitor.Namespace)
+}
+
+func getGrafanaExportVolumeMount() core.VolumeMount {
+	return core.VolumeMount{
+		MountPath: grafanaExportPath,
+		Name:      grafanaExportVolume,
+	}
+}
+
+func getGrafanaExportEnvs(monitor *v1alpha1.TidbMonitor) []core.EnvVar {
+	return []core.EnvVar{
+		{
+			Name:  "GF_EXPORT_PROMETHEUS_URL",
+			Value: getGrafanaExportPrometheusURL(monitor),
+		},
+	}
+}
+
+// getGrafanaExportMetadata returns the merge patch of the metadata of the exported ConfigMaps,
+// they are owned by the TidbMonitor so that they are removed along with it
+func getGrafanaExportMetadata(monitor *v1alpha1.TidbMonitor, sidecarLabels map[string]string) (string, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":          util.CombineStringMap(buildTidbMonitorGrafanaLabel(monitor.Name), sidecarLabels),
+			"annotations":     monitor.Spec.GrafanaExport.Annotations,
+			"ownerReferences": []meta.OwnerReference{controller.GetTiDBMonitorOwnerRef(monitor)},
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getGrafanaExporterContainer returns the init container applying the dashboards and datasources
+// generated by the initializers as ConfigMaps
+func getGrafanaExporterContainer(monitor *v1alpha1.TidbMonitor) (core.Container, error) {
+	export := monitor.Spec.GrafanaExport
+	dashboardLabels := export.DashboardLabels
+	if len(dashboardLabels) == 0 {
+		dashboardLabels = defaultGrafanaDashboardLabels
+	}
+	datasourceLabels := export.DatasourceLabels
+	if len(datasourceLabels) == 0 {
+		datasourceLabels = defaultGrafanaDatasourceLabels
+	}
+	dashboardMetadata, err := getGrafanaExportMetadata(monitor, dashboardLabels)
+	if err != nil {
+		return core.Container{}, err
+	}
+	datasourceMetadata, err := getGrafanaExportMetadata(monitor, datasourceLabels)
+	if err != nil {
+		return core.Container{}, err
+	}
+	container := core.Container{
+		Name:    grafanaExporterName,
+		Image:   fmt.Sprintf("%s:%s", export.BaseImage, export.Version),
+		Command: []string{"/bin/sh", "-c", grafanaExporterCommand},
+		Env: []core.EnvVar{
+			{
+				Name:  "NAMESPACE",
+				Value: monitor.Namespace,
+			},
+			{
+				Name:  "CONFIGMAP_PREFIX",
+				Value: GrafanaName(monitor.Name, 0),
+			},
+			{
+				Name:  "DASHBOARD_METADATA",
+				Value: dashboardMetadata,
+			},
+			{
+				Name:  "DATASOURCE_METADATA",
+				Value: datasourceMetadata,
+			},
+		},
+		VolumeMounts: []core.VolumeMount{getGrafanaExportVolumeMount()},
+		Resources:    controller.ContainerResource(export.ResourceRequirements),
+	}
+	if export.ImagePullPolicy != nil {
+		container.ImagePullPolicy = *export.ImagePullPolicy
+	}
+	return container, nil
+}
+
+// getGrafanaExportPolicyRule allows the exporter to apply the ConfigMaps
+func getGrafanaExportPolicyRule() rbac.PolicyRule {
+	return rbac.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"get", "create", "patch"},
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func newTidbMonitorWithGrafanaExport() *v1alpha1.TidbMonitor {
+	return &v1alpha1.TidbMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns", UID: "uid"},
+		Spec: v1alpha1.TidbMonitorSpec{
+			Prometheus:  v1alpha1.PrometheusSpec{MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "prom/prometheus", Version: "v2.27.1"}},
+			Reloader:    v1alpha1.ReloaderSpec{MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "pingcap/tidb-monitor-reloader", Version: "v1.0.1"}},
+			Initializer: v1alpha1.InitializerSpec{MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "pingcap/tidb-monitor-initializer", Version: "v8.1.0"}},
+			GrafanaExport: &v1alpha1.GrafanaExportSpec{
+				MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "bitnami/kubectl", Version: "1.28"},
+				Annotations:      map[string]string{"grafana_folder": "TiDB"},
+			},
+		},
+	}
+}
+
+func TestGrafanaExportPrometheusURL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithGrafanaExport()
+	g.Expect(getGrafanaExportPrometheusURL(monitor)).To(Equal("http://foo-prometheus.ns:9090"))
+
+	monitor.Spec.Thanos = &v1alpha1.ThanosSpec{Query: &v1alpha1.ThanosQuerySpec{}}
+	g.Expect(getGrafanaExportPrometheusURL(monitor)).To(Equal("http://foo-thanos-query.ns:10902"))
+
+	monitor.Spec.GrafanaExport.PrometheusURL = pointer.StringPtr("https://prometheus.example.com")
+	g.Expect(getGrafanaExportPrometheusURL(monitor)).To(Equal("https://prometheus.example.com"))
+}
+
+func TestGrafanaExportStatefulSet(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithGrafanaExport()
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "foo-monitor"}}
+	tc := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "ns"}}
+	sts, err := getMonitorStatefulSet(sa, nil, monitor, tc, nil, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the exporter runs after the initializer generates the dashboards
+	initContainers := sts.Spec.Template.Spec.InitContainers
+	g.Expect(initContainers).To(HaveLen(2))
+	initializer, exporter := initContainers[0], initContainers[1]
+	g.Expect(initializer.Command[2]).To(ContainSubstring("GF_PROVISIONING_PATH=/grafana-export/dashboards"))
+	g.Expect(initializer.Env).To(ContainElement(corev1.EnvVar{Name: "GF_EXPORT_PROMETHEUS_URL", Value: "http://foo-prometheus.ns:9090"}))
+	g.Expect(initializer.VolumeMounts).To(ContainElement(getGrafanaExportVolumeMount()))
+
+	g.Expect(exporter.Name).To(Equal(grafanaExporterName))
+	g.Expect(exporter.Image).To(Equal("bitnami/kubectl:1.28"))
+	g.Expect(exporter.VolumeMounts).To(ConsistOf(getGrafanaExportVolumeMount()))
+	envs := map[string]string{}
+	for _, env := range exporter.Env {
+		envs[env.Name] = env.Value
+	}
+	g.Expect(envs["CONFIGMAP_PREFIX"]).To(Equal("foo-grafana"))
+	g.Expect(envs["NAMESPACE"]).To(Equal("ns"))
+
+	var patch struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	g.Expect(json.Unmarshal([]byte(envs["DASHBOARD_METADATA"]), &patch)).To(Succeed())
+	g.Expect(patch.Metadata.Labels).To(HaveKeyWithValue("grafana_dashboard", "1"))
+	g.Expect(patch.Metadata.Annotations).To(HaveKeyWithValue("grafana_folder", "TiDB"))
+	g.Expect(patch.Metadata.OwnerReferences).To(HaveLen(1))
+	g.Expect(patch.Metadata.OwnerReferences[0].UID).To(BeEquivalentTo("uid"))
+	g.Expect(json.Unmarshal([]byte(envs["DATASOURCE_METADATA"]), &patch)).To(Succeed())
+	g.Expect(patch.Metadata.Labels).To(HaveKeyWithValue("grafana_datasource", "1"))
+
+	volumes := map[string]corev1.Volume{}
+	for _, v := range sts.Spec.Template.Spec.Volumes {
+		volumes[v.Name] = v
+	}
+	g.Expect(volumes).To(HaveKey(grafanaExportVolume))
+
+	// nothing is exported by default
+	monitor.Spec.GrafanaExport = nil
+	sts, err = getMonitorStatefulSet(sa, nil, monitor, tc, nil, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sts.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+	g.Expect(sts.Spec.Template.Spec.InitContainers[0].Command[2]).NotTo(ContainSubstring("grafana-export"))
+}
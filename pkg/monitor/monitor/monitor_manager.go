@@ -159,6 +159,14 @@ func (m *MonitorManager) SyncMonitor(monitor *v1alpha1.TidbMonitor) error {
 		return err
 	}
 
+	// Sync Thanos
+	if err := m.syncThanos(monitor); err != nil {
+		message := fmt.Sprintf("Sync TidbMonitor[%s/%s] Thanos failed, err:%v", monitor.Namespace, monitor.Name, err)
+		m.deps.Recorder.Event(monitor, corev1.EventTypeWarning, FailedSync, message)
+		return err
+	}
+	klog.V(4).Infof("tm[%s/%s]'s thanos synced", monitor.Namespace, monitor.Name)
+
 	// Sync PV
 	if monitor.Spec.Persistent {
 		// syncing all PVs managed by this tidbmonitor
@@ -422,6 +430,13 @@ func (m *MonitorManager) syncTidbMonitorConfig(monitor *v1alpha1.TidbMonitor, st
 			return err
 		}
 	}
+	if alertmanagerCM := getAlertmanagerConfigMap(monitor); alertmanagerCM != nil {
+		_, err = m.deps.TypedControl.CreateOrUpdateConfigMap(monitor, alertmanagerCM)
+		if err != nil {
+			klog.Errorf("Fail to CreateOrUpdateConfigMap %s for tm[%s/%s]'s, err: %v", alertmanagerCM.Name, monitor.Namespace, monitor.Name, err)
+			return err
+		}
+	}
 	return err
 }
 
@@ -439,6 +454,9 @@ func (m *MonitorManager) syncTidbMonitorRbac(monitor *v1alpha1.TidbMonitor) (*co
 			Verbs:     []string{"get", "list", "watch"},
 		},
 	}
+	if monitor.GrafanaExportEnabled() {
+		policyRules = append(policyRules, getGrafanaExportPolicyRule())
+	}
 	if supported, err := utildiscovery.IsAPIGroupVersionSupported(m.discoveryInterface, "security.openshift.io/v1"); err != nil {
 		return nil, err
 	} else if supported {
@@ -549,6 +567,56 @@ func (m *MonitorManager) removeIngressIfExist(monitor *v1alpha1.TidbMonitor, nam
 	return m.deps.TypedControl.Delete(monitor, ingress)
 }
 
+// syncThanos syncs the Thanos components deployed alongside the Thanos sidecars,
+// the components removed from the spec are deleted
+func (m *MonitorManager) syncThanos(monitor *v1alpha1.TidbMonitor) error {
+	if monitor.ThanosQueryDeployed() {
+		for shard := int32(0); shard < monitor.GetShards(); shard++ {
+			if _, err := m.deps.TypedControl.CreateOrUpdateService(monitor, getThanosSidecarService(monitor, shard)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, component := range getThanosComponents(monitor) {
+		name := ThanosComponentName(monitor.Name, component.name)
+		if component.spec == nil {
+			if err := m.removeThanosComponentIfExist(monitor, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := m.deps.TypedControl.CreateOrUpdateService(monitor, getThanosComponentService(monitor, component.name, component.spec)); err != nil {
+			return err
+		}
+		deploy := getThanosComponentDeployment(monitor, component.name, component.spec, component.args(monitor))
+		if _, err := m.deps.TypedControl.CreateOrUpdateDeployment(monitor, deploy); err != nil {
+			klog.Errorf("Fail to CreateOrUpdateDeployment %s for tm[%s/%s], err: %v", name, monitor.Namespace, monitor.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// removeThanosComponentIfExist removes the Deployment and the Service of a Thanos component if they exist
+func (m *MonitorManager) removeThanosComponentIfExist(monitor *v1alpha1.TidbMonitor, name string) error {
+	deploy, err := m.deps.DeploymentLister.Deployments(monitor.Namespace).Get(name)
+	if err == nil {
+		if err := m.deps.TypedControl.Delete(monitor, deploy); err != nil {
+			return err
+		}
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	svc, err := m.deps.ServiceLister.Services(monitor.Namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return m.deps.TypedControl.Delete(monitor, svc)
+}
+
 func (m *MonitorManager) smoothMigrationToStatefulSet(monitor *v1alpha1.TidbMonitor) (bool, error) {
 	if m.deps.PVLister == nil {
 		klog.V(4).Infof("Persistent volumes lister is unavailable, skip migrating to statefulset for tm[%s/%s]. This may be caused by no relevant permissions",
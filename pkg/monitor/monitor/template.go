@@ -81,6 +81,7 @@ type MonitorConfigModel struct {
 	RemoteWriteCfg            *yaml.MapItem
 	EnableAlertRules          bool
 	EnableExternalRuleConfigs bool
+	EnableExtraRuleConfigs    bool
 	shards                    int32
 }
 
@@ -492,6 +493,10 @@ func RenderPrometheusConfig(model *MonitorConfigModel) (yaml.MapSlice, error) {
 			"/prometheus-external-rules/*.rules.yml",
 		}
 	}
+	if model.EnableExtraRuleConfigs {
+		// the extra rules are loaded in addition to the rules above
+		rulesPath = append(rulesPath, extraRulesPath+"/*/*.rules.yml")
+	}
 	if rulesPath != nil {
 		cfg = append(cfg, yaml.MapItem{
 			Key:   "rule_files",
@@ -0,0 +1,404 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	thanosGRPCPort = 10901
+	thanosHTTPPort = 10902
+
+	thanosQuery = "thanos-query"
+	thanosStore = "thanos-store"
+	thanosRuler = "thanos-ruler"
+
+	// thanosRulerReplicaLabel is the label distinguishing the series and alerts of the replicas of Thanos Ruler
+	thanosRulerReplicaLabel = "ruler_replica"
+	// thanosRulesPath is the directory the ConfigMaps of spec.thanos.ruler.ruleConfigMaps are mounted in,
+	// each ConfigMap is mounted in a sub directory so that the keys of them don't conflict
+	thanosRulesPath = "/etc/thanos/rules"
+
+	defaultThanosReplicas           = int32(2)
+	defaultThanosEvaluationInterval = "1m"
+)
+
+// ThanosComponentName returns the name of the Deployment and the Service of a Thanos component, e.g. basic-thanos-query
+func ThanosComponentName(name, component string) string {
+	return fmt.Sprintf("%s-%s", name, component)
+}
+
+// thanosSidecarServiceName returns the name of the headless Service of the Thanos sidecars of a shard,
+// which is also the governing Service of the StatefulSet of the shard
+func thanosSidecarServiceName(name string, shard int32) string {
+	return GetMonitorShardName(name, shard)
+}
+
+func buildThanosComponentLabel(name, component string) label.Label {
+	// the component label differs from the TidbMonitor pods so that the Services of Prometheus don't select them
+	return label.NewMonitor().Instance(name).Component(component)
+}
+
+// getThanosQueryURL returns the address of Thanos Query, which serves the Prometheus HTTP API
+func getThanosQueryURL(monitor *v1alpha1.TidbMonitor) string {
+	return fmt.Sprintf("http://%s.%s:%d", ThanosComponentName(monitor.Name, thanosQuery), monitor.Namespace, thanosHTTPPort)
+}
+
+// getPrometheusURL returns the address Grafana queries, Thanos Query is preferred so that
+// the series of all the shards and replicas are deduplicated
+func getPrometheusURL(monitor *v1alpha1.TidbMonitor) string {
+	if monitor.ThanosQueryDeployed() {
+		return getThanosQueryURL(monitor)
+	}
+	return "http://127.0.0.1:9090"
+}
+
+// getThanosReplicaLabels returns the labels Thanos Query deduplicates the series by
+func getThanosReplicaLabels(monitor *v1alpha1.TidbMonitor) []string {
+	if labels := monitor.Spec.Thanos.Query.ReplicaLabels; len(labels) > 0 {
+		return labels
+	}
+	var labels []string
+	replicaExternalLabelName := defaultReplicaExternalLabelName
+	if monitor.Spec.ReplicaExternalLabelName != nil {
+		replicaExternalLabelName = *monitor.Spec.ReplicaExternalLabelName
+	}
+	if replicaExternalLabelName != "" {
+		labels = append(labels, replicaExternalLabelName)
+	}
+	return append(labels, thanosRulerReplicaLabel)
+}
+
+// getThanosStoreEndpoints returns the StoreAPI servers Thanos Query fans out to
+func getThanosStoreEndpoints(monitor *v1alpha1.TidbMonitor) []string {
+	var endpoints []string
+	// the sidecars of all the replicas are resolved by the SRV records of the headless Services
+	for shard := int32(0); shard < monitor.GetShards(); shard++ {
+		endpoints = append(endpoints, fmt.Sprintf("dnssrv+_grpc._tcp.%s.%s.svc", thanosSidecarServiceName(monitor.Name, shard), monitor.Namespace))
+	}
+	if monitor.ThanosStoreDeployed() {
+		endpoints = append(endpoints, fmt.Sprintf("%s.%s:%d", ThanosComponentName(monitor.Name, thanosStore), monitor.Namespace, thanosGRPCPort))
+	}
+	if monitor.ThanosRulerDeployed() {
+		endpoints = append(endpoints, fmt.Sprintf("%s.%s:%d", ThanosComponentName(monitor.Name, thanosRuler), monitor.Namespace, thanosGRPCPort))
+	}
+	return append(endpoints, monitor.Spec.Thanos.Query.Endpoints...)
+}
+
+// getThanosRulerAlertmanagerURL returns the address of the Alertmanager Thanos Ruler sends the alerts to
+func getThanosRulerAlertmanagerURL(monitor *v1alpha1.TidbMonitor) string {
+	var url string
+	if monitor.AlertmanagerDeployed() {
+		// Alertmanager deployed runs in the TidbMonitor pods, so it's accessed by the Service
+		url = fmt.Sprintf("%s.%s:%d", AlertmanagerName(monitor.Name, 0), monitor.Namespace, alertmanagerPort)
+	} else {
+		url = getAlertmanagerURL(monitor)
+	}
+	if url != "" && !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+	return url
+}
+
+func getThanosQueryArgs(monitor *v1alpha1.TidbMonitor) []string {
+	args := []string{"query"}
+	for _, l := range getThanosReplicaLabels(monitor) {
+		args = append(args, fmt.Sprintf("--query.replica-label=%s", l))
+	}
+	for _, endpoint := range getThanosStoreEndpoints(monitor) {
+		args = append(args, fmt.Sprintf("--store=%s", endpoint))
+	}
+	return args
+}
+
+func getThanosStoreArgs(monitor *v1alpha1.TidbMonitor) []string {
+	return []string{"store", "--data-dir=/data"}
+}
+
+func getThanosRulerArgs(monitor *v1alpha1.TidbMonitor) []string {
+	spec := monitor.Spec.Thanos.Ruler
+	interval := defaultThanosEvaluationInterval
+	if len(spec.EvaluationInterval) > 0 {
+		interval = spec.EvaluationInterval
+	}
+	args := []string{
+		"rule",
+		"--data-dir=/data",
+		fmt.Sprintf("--eval-interval=%s", interval),
+		fmt.Sprintf("--rule-file=%s/*/*.yml", thanosRulesPath),
+		fmt.Sprintf("--rule-file=%s/*/*.yaml", thanosRulesPath),
+		fmt.Sprintf("--query=%s", strings.TrimPrefix(getThanosQueryURL(monitor), "http://")),
+		fmt.Sprintf(`--label=%s="$(POD_NAME)"`, thanosRulerReplicaLabel),
+	}
+	if url := getThanosRulerAlertmanagerURL(monitor); url != "" {
+		args = append(args, fmt.Sprintf("--alertmanagers.url=%s", url))
+	}
+	return args
+}
+
+func getThanosComponentContainer(monitor *v1alpha1.TidbMonitor, component string, spec *v1alpha1.ThanosComponentSpec, args []string) core.Container {
+	thanos := monitor.Spec.Thanos
+	args = append(args,
+		fmt.Sprintf("--grpc-address=0.0.0.0:%d", thanosGRPCPort),
+		fmt.Sprintf("--http-address=0.0.0.0:%d", thanosHTTPPort),
+	)
+	logLevel := spec.LogLevel
+	if len(logLevel) == 0 {
+		logLevel = thanos.LogLevel
+	}
+	if len(logLevel) > 0 {
+		args = append(args, fmt.Sprintf("--log.level=%s", logLevel))
+	}
+	if len(thanos.LogFormat) > 0 {
+		args = append(args, fmt.Sprintf("--log.format=%s", thanos.LogFormat))
+	}
+	c := core.Container{
+		Name:      component,
+		Image:     fmt.Sprintf("%s:%s", thanos.BaseImage, thanos.Version),
+		Resources: controller.ContainerResource(spec.ResourceRequirements),
+		Args:      args,
+		Env: []core.EnvVar{
+			{
+				Name: "POD_NAME",
+				ValueFrom: &core.EnvVarSource{
+					FieldRef: &core.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+			{
+				Name:  "TZ",
+				Value: monitor.Timezone(),
+			},
+		},
+		Ports: []core.ContainerPort{
+			{
+				Name:          "grpc",
+				ContainerPort: thanosGRPCPort,
+				Protocol:      core.ProtocolTCP,
+			},
+			{
+				Name:          "http",
+				ContainerPort: thanosHTTPPort,
+				Protocol:      core.ProtocolTCP,
+			},
+		},
+		ReadinessProbe: &core.Probe{
+			ProbeHandler: core.ProbeHandler{
+				HTTPGet: &core.HTTPGetAction{
+					Path: "/-/ready",
+					Port: intstr.FromInt(thanosHTTPPort),
+				},
+			},
+			TimeoutSeconds: 3,
+			PeriodSeconds:  5,
+		},
+	}
+	if thanos.ImagePullPolicy != nil {
+		c.ImagePullPolicy = *thanos.ImagePullPolicy
+	}
+	// the sidecar config file is not mounted in the Thanos components, only the secret is supported
+	if thanos.ObjectStorageConfig != nil && component != thanosQuery {
+		c.Args = append(c.Args, "--objstore.config=$(OBJSTORE_CONFIG)")
+		c.Env = append(c.Env, core.EnvVar{
+			Name: "OBJSTORE_CONFIG",
+			ValueFrom: &core.EnvVarSource{
+				SecretKeyRef: thanos.ObjectStorageConfig,
+			},
+		})
+	}
+	return c
+}
+
+// getThanosComponentDeployment returns the Deployment of a Thanos component,
+// the data directory of Thanos Store Gateway and Thanos Ruler is an emptyDir as the blocks are in the object storage
+func getThanosComponentDeployment(monitor *v1alpha1.TidbMonitor, component string, spec *v1alpha1.ThanosComponentSpec, args []string) *apps.Deployment {
+	replicas := defaultThanosReplicas
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+	deployLabels := buildThanosComponentLabel(monitor.Name, component)
+	container := getThanosComponentContainer(monitor, component, spec, args)
+	podSpec := core.PodSpec{
+		SecurityContext:  monitor.Spec.PodSecurityContext,
+		Tolerations:      monitor.Spec.Tolerations,
+		NodeSelector:     monitor.Spec.NodeSelector,
+		ImagePullSecrets: monitor.Spec.ImagePullSecrets,
+		// spread the replicas across the nodes for high availability
+		Affinity: &core.Affinity{
+			PodAntiAffinity: &core.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []core.WeightedPodAffinityTerm{
+					{
+						Weight: 100,
+						PodAffinityTerm: core.PodAffinityTerm{
+							LabelSelector: &meta.LabelSelector{MatchLabels: deployLabels.Labels()},
+							TopologyKey:   core.LabelHostname,
+						},
+					},
+				},
+			},
+		},
+	}
+	if component != thanosQuery {
+		container.VolumeMounts = append(container.VolumeMounts, core.VolumeMount{Name: "data", MountPath: "/data"})
+		podSpec.Volumes = append(podSpec.Volumes, core.Volume{
+			Name:         "data",
+			VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}},
+		})
+	}
+	if component == thanosRuler {
+		for i, ref := range monitor.Spec.Thanos.Ruler.RuleConfigMaps {
+			name := fmt.Sprintf("rules-%d", i)
+			container.VolumeMounts = append(container.VolumeMounts, core.VolumeMount{
+				Name:      name,
+				MountPath: fmt.Sprintf("%s/%d", thanosRulesPath, i),
+				ReadOnly:  true,
+			})
+			podSpec.Volumes = append(podSpec.Volumes, core.Volume{
+				Name: name,
+				VolumeSource: core.VolumeSource{
+					ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: ref},
+				},
+			})
+		}
+	}
+	podSpec.Containers = []core.Container{container}
+	if monitor.Spec.PriorityClassName != nil {
+		podSpec.PriorityClassName = *monitor.Spec.PriorityClassName
+	}
+
+	return &apps.Deployment{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            ThanosComponentName(monitor.Name, component),
+			Namespace:       monitor.Namespace,
+			Labels:          deployLabels.Labels(),
+			OwnerReferences: []meta.OwnerReference{controller.GetTiDBMonitorOwnerRef(monitor)},
+			Annotations:     util.CopyStringMap(monitor.Spec.Annotations),
+		},
+		Spec: apps.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &meta.LabelSelector{MatchLabels: deployLabels.Labels()},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: meta.ObjectMeta{
+					Labels:      util.CombineStringMap(deployLabels.Labels(), monitor.Spec.Labels),
+					Annotations: util.CopyStringMap(monitor.Spec.Annotations),
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+func getThanosComponentService(monitor *v1alpha1.TidbMonitor, component string, spec *v1alpha1.ThanosComponentSpec) *core.Service {
+	svcLabels := buildThanosComponentLabel(monitor.Name, component)
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            ThanosComponentName(monitor.Name, component),
+			Namespace:       monitor.Namespace,
+			Labels:          util.CombineStringMap(svcLabels.Labels(), spec.Service.Labels, monitor.Spec.Labels),
+			OwnerReferences: []meta.OwnerReference{controller.GetTiDBMonitorOwnerRef(monitor)},
+			Annotations:     util.CombineStringMap(spec.Service.Annotations, monitor.Spec.Annotations),
+		},
+		Spec: core.ServiceSpec{
+			Ports: []core.ServicePort{
+				{
+					Name:       "grpc",
+					Port:       thanosGRPCPort,
+					Protocol:   core.ProtocolTCP,
+					TargetPort: intstr.FromInt(thanosGRPCPort),
+				},
+				{
+					Name:       "http",
+					Port:       thanosHTTPPort,
+					Protocol:   core.ProtocolTCP,
+					TargetPort: intstr.FromInt(thanosHTTPPort),
+				},
+			},
+			Type:     spec.Service.Type,
+			Selector: svcLabels.Labels(),
+		},
+	}
+	if spec.Service.Type == core.ServiceTypeLoadBalancer {
+		if spec.Service.LoadBalancerIP != nil {
+			svc.Spec.LoadBalancerIP = *spec.Service.LoadBalancerIP
+		}
+		if spec.Service.LoadBalancerSourceRanges != nil {
+			svc.Spec.LoadBalancerSourceRanges = spec.Service.LoadBalancerSourceRanges
+		}
+	}
+	return svc
+}
+
+// getThanosSidecarService returns the headless Service resolving the Thanos sidecars of all the replicas of a shard
+func getThanosSidecarService(monitor *v1alpha1.TidbMonitor, shard int32) *core.Service {
+	instanceName := GetMonitorInstanceName(monitor, shard)
+	return &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            thanosSidecarServiceName(monitor.Name, shard),
+			Namespace:       monitor.Namespace,
+			Labels:          util.CombineStringMap(label.NewMonitor().Instance(monitor.Name).Monitor().UsedBy("thanos").Labels(), monitor.Spec.Labels),
+			OwnerReferences: []meta.OwnerReference{controller.GetTiDBMonitorOwnerRef(monitor)},
+		},
+		Spec: core.ServiceSpec{
+			ClusterIP: core.ClusterIPNone,
+			Ports: []core.ServicePort{
+				{
+					Name:       "grpc",
+					Port:       thanosGRPCPort,
+					Protocol:   core.ProtocolTCP,
+					TargetPort: intstr.FromInt(thanosGRPCPort),
+				},
+			},
+			Selector: map[string]string{
+				label.InstanceLabelKey:  instanceName,
+				label.NameLabelKey:      "tidb-cluster",
+				label.ComponentLabelKey: label.TiDBMonitorVal,
+			},
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// thanosComponent is a Thanos component deployed by TidbMonitor
+type thanosComponent struct {
+	name string
+	// spec is nil if the component is not deployed
+	spec *v1alpha1.ThanosComponentSpec
+	args func(*v1alpha1.TidbMonitor) []string
+}
+
+func getThanosComponents(monitor *v1alpha1.TidbMonitor) []thanosComponent {
+	query := thanosComponent{name: thanosQuery, args: getThanosQueryArgs}
+	store := thanosComponent{name: thanosStore, args: getThanosStoreArgs}
+	ruler := thanosComponent{name: thanosRuler, args: getThanosRulerArgs}
+	if monitor.ThanosQueryDeployed() {
+		query.spec = &monitor.Spec.Thanos.Query.ThanosComponentSpec
+	}
+	if monitor.ThanosStoreDeployed() {
+		store.spec = monitor.Spec.Thanos.Store
+	}
+	if monitor.ThanosRulerDeployed() {
+		ruler.spec = &monitor.Spec.Thanos.Ruler.ThanosComponentSpec
+	}
+	return []thanosComponent{query, store, ruler}
+}
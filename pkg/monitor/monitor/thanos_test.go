@@ -0,0 +1,135 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func newTidbMonitorWithThanos() *v1alpha1.TidbMonitor {
+	return &v1alpha1.TidbMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns"},
+		Spec: v1alpha1.TidbMonitorSpec{
+			Replicas: pointer.Int32Ptr(2),
+			Shards:   pointer.Int32Ptr(2),
+			Thanos: &v1alpha1.ThanosSpec{
+				MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "thanosio/thanos", Version: "v0.17.2"},
+				ObjectStorageConfig: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+					Key:                  "objstore.yml",
+				},
+				Query: &v1alpha1.ThanosQuerySpec{},
+				Store: &v1alpha1.ThanosComponentSpec{Replicas: pointer.Int32Ptr(1)},
+				Ruler: &v1alpha1.ThanosRulerSpec{
+					RuleConfigMaps: []corev1.LocalObjectReference{{Name: "rules"}},
+				},
+			},
+			Alertmanager: &v1alpha1.AlertmanagerSpec{
+				MonitorContainer: v1alpha1.MonitorContainer{BaseImage: "prom/alertmanager", Version: "v0.27.0"},
+			},
+		},
+	}
+}
+
+func TestThanosQueryDeployment(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithThanos()
+	deploy := getThanosComponentDeployment(monitor, thanosQuery, &monitor.Spec.Thanos.Query.ThanosComponentSpec, getThanosQueryArgs(monitor))
+	g.Expect(deploy.Name).To(Equal("foo-thanos-query"))
+	g.Expect(*deploy.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(deploy.Spec.Template.Labels[label.ComponentLabelKey]).To(Equal(thanosQuery))
+
+	c := deploy.Spec.Template.Spec.Containers[0]
+	g.Expect(c.Image).To(Equal("thanosio/thanos:v0.17.2"))
+	g.Expect(c.Args).To(ContainElements(
+		"query",
+		"--query.replica-label=prometheus_replica",
+		"--query.replica-label=ruler_replica",
+		"--store=dnssrv+_grpc._tcp.foo-monitor.ns.svc",
+		"--store=dnssrv+_grpc._tcp.foo-monitor-shard-1.ns.svc",
+		"--store=foo-thanos-store.ns:10901",
+		"--store=foo-thanos-ruler.ns:10901",
+	))
+	// Thanos Query doesn't access the object storage
+	g.Expect(c.Env).To(HaveLen(2))
+
+	// the replica labels can be customized
+	monitor.Spec.Thanos.Query.ReplicaLabels = []string{"replica"}
+	monitor.Spec.Thanos.Ruler = nil
+	args := getThanosQueryArgs(monitor)
+	g.Expect(args).To(ContainElement("--query.replica-label=replica"))
+	g.Expect(args).NotTo(ContainElement("--query.replica-label=prometheus_replica"))
+	g.Expect(args).NotTo(ContainElement("--store=foo-thanos-ruler.ns:10901"))
+}
+
+func TestThanosRulerDeployment(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithThanos()
+	deploy := getThanosComponentDeployment(monitor, thanosRuler, &monitor.Spec.Thanos.Ruler.ThanosComponentSpec, getThanosRulerArgs(monitor))
+	c := deploy.Spec.Template.Spec.Containers[0]
+	g.Expect(c.Args).To(ContainElements(
+		"rule",
+		"--eval-interval=1m",
+		"--query=foo-thanos-query.ns:10902",
+		`--label=ruler_replica="$(POD_NAME)"`,
+		"--alertmanagers.url=http://foo-alertmanager.ns:9093",
+		"--objstore.config=$(OBJSTORE_CONFIG)",
+	))
+	g.Expect(c.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "rules-0", MountPath: "/etc/thanos/rules/0", ReadOnly: true}))
+	g.Expect(deploy.Spec.Template.Spec.Volumes).To(HaveLen(2))
+
+	// the alerts are sent to the external Alertmanager
+	monitor.Spec.Alertmanager.URL = pointer.StringPtr("alertmanager.monitoring:9093")
+	g.Expect(getThanosRulerArgs(monitor)).To(ContainElement("--alertmanagers.url=http://alertmanager.monitoring:9093"))
+}
+
+func TestThanosSidecarService(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithThanos()
+	svc := getThanosSidecarService(monitor, 1)
+	g.Expect(svc.Name).To(Equal("foo-monitor-shard-1"))
+	g.Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+	g.Expect(svc.Spec.Selector[label.InstanceLabelKey]).To(Equal("foo-shard-1"))
+}
+
+func TestThanosGrafanaDataSource(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithThanos()
+	g.Expect(getPrometheusURL(monitor)).To(Equal("http://foo-thanos-query.ns:10902"))
+	monitor.Spec.Thanos.Query = nil
+	g.Expect(getPrometheusURL(monitor)).To(Equal("http://127.0.0.1:9090"))
+}
+
+func TestThanosComponents(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	monitor := newTidbMonitorWithThanos()
+	monitor.Spec.Thanos.Store = nil
+	deployed := map[string]bool{}
+	for _, component := range getThanosComponents(monitor) {
+		deployed[component.name] = component.spec != nil
+	}
+	g.Expect(deployed).To(Equal(map[string]bool{thanosQuery: true, thanosStore: false, thanosRuler: true}))
+}
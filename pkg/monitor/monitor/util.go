@@ -105,6 +105,9 @@ chmod 777 /data/prometheus
 chmod 777 /data/prometheus /data/grafana
 /usr/bin/init.sh`
 	}
+	if monitor.GrafanaExportEnabled() {
+		c += grafanaExportInitCommand
+	}
 	command := []string{
 		"/bin/sh",
 		"-c",
@@ -152,17 +155,15 @@ func getAlertManagerRulesVersion(monitor *v1alpha1.TidbMonitor) string {
 // If the namespace in ClusterRef is empty, we would set the TidbMonitor's namespace in the default
 func getPromConfigMap(monitor *v1alpha1.TidbMonitor, monitorClusterInfos []ClusterRegexInfo, dmClusterInfos []ClusterRegexInfo, shard int32, store *Store) (*core.ConfigMap, error) {
 	model := &MonitorConfigModel{
-		AlertmanagerURL:  "",
-		ClusterInfos:     monitorClusterInfos,
-		DMClusterInfos:   dmClusterInfos,
-		ExternalLabels:   buildExternalLabels(monitor),
-		EnableAlertRules: monitor.Spec.EnableAlertRules,
-		shards:           shard,
+		AlertmanagerURL:        getAlertmanagerURL(monitor),
+		ClusterInfos:           monitorClusterInfos,
+		DMClusterInfos:         dmClusterInfos,
+		ExternalLabels:         buildExternalLabels(monitor),
+		EnableAlertRules:       monitor.Spec.EnableAlertRules || monitor.Spec.Alertmanager != nil,
+		EnableExtraRuleConfigs: len(monitor.Spec.ExtraAlertRules) > 0,
+		shards:                 shard,
 	}
 
-	if monitor.Spec.AlertmanagerURL != nil {
-		model.AlertmanagerURL = *monitor.Spec.AlertmanagerURL
-	}
 	if monitor.Spec.Prometheus.Config != nil && monitor.Spec.Prometheus.Config.RuleConfigRef != nil {
 		model.EnableExternalRuleConfigs = true
 	}
@@ -323,7 +324,7 @@ func getMonitorInitContainer(monitor *v1alpha1.TidbMonitor, tc *v1alpha1.TidbClu
 			},
 			{
 				Name:  "GF_TIDB_PROMETHEUS_URL",
-				Value: "http://127.0.0.1:9090",
+				Value: getPrometheusURL(monitor),
 			},
 			{
 				Name:  "TIDB_VERSION",
@@ -387,6 +388,10 @@ func getMonitorInitContainer(monitor *v1alpha1.TidbMonitor, tc *v1alpha1.TidbClu
 		container.VolumeMounts = append(container.VolumeMounts, getGrafanaVolumeMounts()...)
 		container.Env = append(container.Env, getGrafanaEnvs()...)
 	}
+	if monitor.GrafanaExportEnabled() {
+		container.VolumeMounts = append(container.VolumeMounts, getGrafanaExportVolumeMount())
+		container.Env = append(container.Env, getGrafanaExportEnvs(monitor)...)
+	}
 
 	var envOverrides []core.EnvVar
 	for k, v := range monitor.Spec.Initializer.Envs {
@@ -424,7 +429,7 @@ func getMonitorDMInitContainer(monitor *v1alpha1.TidbMonitor, dc *v1alpha1.DMClu
 			},
 			{
 				Name:  "GF_DM_PROMETHEUS_URL",
-				Value: "http://127.0.0.1:9090",
+				Value: getPrometheusURL(monitor),
 			},
 			{
 				Name:  "DM_CLUSTER_NAMESPACE",
@@ -458,6 +463,10 @@ func getMonitorDMInitContainer(monitor *v1alpha1.TidbMonitor, dc *v1alpha1.DMClu
 		container.VolumeMounts = append(container.VolumeMounts, getGrafanaVolumeMounts()...)
 		container.Env = append(container.Env, getGrafanaEnvs()...)
 	}
+	if monitor.GrafanaExportEnabled() {
+		container.VolumeMounts = append(container.VolumeMounts, getGrafanaExportVolumeMount())
+		container.Env = append(container.Env, getGrafanaExportEnvs(monitor)...)
+	}
 
 	var envOverrides []core.EnvVar
 	for k, v := range monitor.Spec.DM.Initializer.Envs {
@@ -587,6 +596,7 @@ func getMonitorPrometheusContainer(monitor *v1alpha1.TidbMonitor, shard int32) c
 			ReadOnly:  true,
 		})
 	}
+	c.VolumeMounts = append(c.VolumeMounts, getExtraRulesVolumeMounts(monitor)...)
 	return c
 }
 
@@ -787,6 +797,10 @@ func getMonitorPrometheusReloaderContainer(monitor *v1alpha1.TidbMonitor, shard
 		})
 		c.Command = append(c.Command, "--watched-dir=/prometheus-external-rules")
 	}
+	for _, mount := range getExtraRulesVolumeMounts(monitor) {
+		c.VolumeMounts = append(c.VolumeMounts, mount)
+		c.Command = append(c.Command, fmt.Sprintf("--watched-dir=%s", mount.MountPath))
+	}
 	return c
 }
 
@@ -880,6 +894,14 @@ func getMonitorVolumes(monitor *v1alpha1.TidbMonitor) []core.Volume {
 		}
 		volumes = append(volumes, dataSource, dashboardsProvisioning, grafanaDashboard)
 	}
+	if monitor.GrafanaExportEnabled() {
+		volumes = append(volumes, core.Volume{
+			Name: grafanaExportVolume,
+			VolumeSource: core.VolumeSource{
+				EmptyDir: &core.EmptyDirVolumeSource{},
+			},
+		})
+	}
 	prometheusRules := core.Volume{
 		Name: "prometheus-rules",
 		VolumeSource: core.VolumeSource{
@@ -924,6 +946,8 @@ func getMonitorVolumes(monitor *v1alpha1.TidbMonitor) []core.Volume {
 		})
 	}
 
+	volumes = append(volumes, getAlertmanagerVolumes(monitor)...)
+
 	return volumes
 }
 
@@ -1069,6 +1093,9 @@ func getMonitorService(monitor *v1alpha1.TidbMonitor) []*core.Service {
 
 			services = append(services, grafanaService)
 		}
+		if monitor.AlertmanagerDeployed() {
+			services = append(services, getAlertmanagerService(monitor, shard, selector))
+		}
 	}
 
 	for _, svc := range services {
@@ -1228,6 +1255,13 @@ func getMonitorStatefulSet(sa *core.ServiceAccount, secret *core.Secret, monitor
 		dmInitContainer := getMonitorDMInitContainer(monitor, dc)
 		statefulSet.Spec.Template.Spec.InitContainers = append(statefulSet.Spec.Template.Spec.InitContainers, dmInitContainer)
 	}
+	if monitor.GrafanaExportEnabled() {
+		exporterContainer, err := getGrafanaExporterContainer(monitor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the grafana exporter for TiDBMonitor of [%s/%s], error: %v", monitor.Namespace, monitor.Name, err)
+		}
+		statefulSet.Spec.Template.Spec.InitContainers = append(statefulSet.Spec.Template.Spec.InitContainers, exporterContainer)
+	}
 	prometheusContainer := getMonitorPrometheusContainer(monitor, shard)
 	reloaderContainer := getMonitorReloaderContainer(monitor)
 	statefulSet.Spec.Template.Spec.Containers = append(statefulSet.Spec.Template.Spec.Containers, prometheusContainer, reloaderContainer)
@@ -1252,6 +1286,10 @@ func getMonitorStatefulSet(sa *core.ServiceAccount, secret *core.Secret, monitor
 		grafanaContainer := getMonitorGrafanaContainer(secret, monitor)
 		statefulSet.Spec.Template.Spec.Containers = append(statefulSet.Spec.Template.Spec.Containers, grafanaContainer)
 	}
+	if monitor.AlertmanagerDeployed() {
+		alertmanagerContainer := getMonitorAlertmanagerContainer(monitor)
+		statefulSet.Spec.Template.Spec.Containers = append(statefulSet.Spec.Template.Spec.Containers, alertmanagerContainer)
+	}
 	volumes := getMonitorVolumes(monitor)
 	statefulSet.Spec.Template.Spec.Volumes = volumes
 
@@ -1311,10 +1349,14 @@ func getMonitorStatefulSetSkeleton(sa *core.ServiceAccount, monitor *v1alpha1.Ti
 					Volumes:            []core.Volume{},
 					Tolerations:        monitor.Spec.Tolerations,
 					NodeSelector:       monitor.Spec.NodeSelector,
+					PreemptionPolicy:   monitor.Spec.PreemptionPolicy,
 				},
 			},
 		},
 	}
+	if monitor.Spec.PriorityClassName != nil {
+		statefulset.Spec.Template.Spec.PriorityClassName = *monitor.Spec.PriorityClassName
+	}
 	return statefulset
 }
 
@@ -15,6 +15,7 @@ package pdapi
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -32,12 +33,26 @@ const (
 	GetStoresActionType                         ActionType = "GetStores"
 	GetTombStoneStoresActionType                ActionType = "GetTombStoneStores"
 	GetStoreActionType                          ActionType = "GetStore"
+	GetStoreRegionsActionType                   ActionType = "GetStoreRegions"
+	GetRegionsByCheckActionType                 ActionType = "GetRegionsByCheck"
 	DeleteStoreActionType                       ActionType = "DeleteStore"
 	SetStoreStateActionType                     ActionType = "SetStoreState"
 	DeleteMemberByIDActionType                  ActionType = "DeleteMemberByID"
 	DeleteMemberActionType                      ActionType = "DeleteMember "
 	SetStoreLabelsActionType                    ActionType = "SetStoreLabels"
+	SetMemberLeaderPriorityActionType           ActionType = "SetMemberLeaderPriority"
+	GetMemberLeaderPriorityActionType           ActionType = "GetMemberLeaderPriority"
+	GetStoreLimitsActionType                    ActionType = "GetStoreLimits"
+	SetStoreLimitActionType                     ActionType = "SetStoreLimit"
+	SetStoreWeightActionType                    ActionType = "SetStoreWeight"
 	UpdateReplicationActionType                 ActionType = "UpdateReplicationConfig"
+	UpdateScheduleActionType                    ActionType = "UpdateScheduleConfig"
+	UpdateConfigActionType                      ActionType = "UpdateConfig"
+	GetPlacementRuleActionType                  ActionType = "GetPlacementRule"
+	GetPlacementRulesByGroupActionType          ActionType = "GetPlacementRulesByGroup"
+	GetPlacementRulesActionType                 ActionType = "GetPlacementRules"
+	SetPlacementRuleActionType                  ActionType = "SetPlacementRule"
+	DeletePlacementRuleActionType               ActionType = "DeletePlacementRule"
 	BeginEvictLeaderActionType                  ActionType = "BeginEvictLeader"
 	EndEvictLeaderActionType                    ActionType = "EndEvictLeader"
 	GetEvictLeaderSchedulersActionType          ActionType = "GetEvictLeaderSchedulers"
@@ -63,6 +78,12 @@ type Action struct {
 	Name        string
 	Labels      map[string]string
 	Replication PDReplicationConfig
+	Schedule    PDScheduleConfig
+	Rule        *PlacementRule
+	Priority    int
+	Config      map[string]interface{}
+	Rate        float64
+	Weights     [2]float64
 }
 
 type Reaction func(action *Action) (interface{}, error)
@@ -175,6 +196,28 @@ func (c *FakePDClient) GetStore(id uint64) (*StoreInfo, error) {
 	return result.(*StoreInfo), nil
 }
 
+func (c *FakePDClient) GetStoreRegions(id uint64) (*RegionsInfo, error) {
+	action := &Action{
+		ID: id,
+	}
+	result, err := c.fakeAPI(GetStoreRegionsActionType, action)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*RegionsInfo), nil
+}
+
+func (c *FakePDClient) GetRegionsByCheck(state RegionCheckState) (*RegionsInfo, error) {
+	action := &Action{
+		Name: string(state),
+	}
+	result, err := c.fakeAPI(GetRegionsByCheckActionType, action)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*RegionsInfo), nil
+}
+
 func (c *FakePDClient) DeleteStore(id uint64) error {
 	if reaction, ok := c.reactions[DeleteStoreActionType]; ok {
 		action := &Action{ID: id}
@@ -211,6 +254,24 @@ func (c *FakePDClient) DeleteMember(name string) error {
 	return nil
 }
 
+func (c *FakePDClient) SetMemberLeaderPriority(name string, priority int) error {
+	if reaction, ok := c.reactions[SetMemberLeaderPriorityActionType]; ok {
+		action := &Action{Name: name, Priority: priority}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) GetMemberLeaderPriority(name string) (int, error) {
+	action := &Action{Name: name}
+	result, err := c.fakeAPI(GetMemberLeaderPriorityActionType, action)
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
 // SetStoreLabels sets TiKV labels
 func (c *FakePDClient) SetStoreLabels(storeID uint64, labels map[string]string) (bool, error) {
 	if reaction, ok := c.reactions[SetStoreLabelsActionType]; ok {
@@ -221,6 +282,39 @@ func (c *FakePDClient) SetStoreLabels(storeID uint64, labels map[string]string)
 	return true, nil
 }
 
+// GetStoreLimits returns the store limits of all the stores
+func (c *FakePDClient) GetStoreLimits() (map[uint64]StoreLimitConfig, error) {
+	if reaction, ok := c.reactions[GetStoreLimitsActionType]; ok {
+		action := &Action{}
+		result, err := reaction(action)
+		if err != nil {
+			return nil, err
+		}
+		return result.(map[uint64]StoreLimitConfig), nil
+	}
+	return map[uint64]StoreLimitConfig{}, nil
+}
+
+// SetStoreLimit sets the store limit of the type for a store
+func (c *FakePDClient) SetStoreLimit(storeID uint64, limitType string, rate float64) error {
+	if reaction, ok := c.reactions[SetStoreLimitActionType]; ok {
+		action := &Action{ID: storeID, Name: limitType, Rate: rate}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+// SetStoreWeight sets the leader and region weights of a store
+func (c *FakePDClient) SetStoreWeight(storeID uint64, leaderWeight, regionWeight float64) error {
+	if reaction, ok := c.reactions[SetStoreWeightActionType]; ok {
+		action := &Action{ID: storeID, Weights: [2]float64{leaderWeight, regionWeight}}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
 // UpdateReplicationConfig updates the replication config
 func (c *FakePDClient) UpdateReplicationConfig(config PDReplicationConfig) error {
 	if reaction, ok := c.reactions[UpdateReplicationActionType]; ok {
@@ -231,6 +325,84 @@ func (c *FakePDClient) UpdateReplicationConfig(config PDReplicationConfig) error
 	return nil
 }
 
+// UpdateScheduleConfig updates the schedule config
+func (c *FakePDClient) UpdateScheduleConfig(config PDScheduleConfig) error {
+	if reaction, ok := c.reactions[UpdateScheduleActionType]; ok {
+		action := &Action{Schedule: config}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+// UpdateConfig updates the runtime config items
+func (c *FakePDClient) UpdateConfig(config map[string]interface{}) error {
+	if reaction, ok := c.reactions[UpdateConfigActionType]; ok {
+		action := &Action{Config: config}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+// GetPlacementRule returns the placement rule
+func (c *FakePDClient) GetPlacementRule(groupID, id string) (*PlacementRule, error) {
+	if reaction, ok := c.reactions[GetPlacementRuleActionType]; ok {
+		action := &Action{Rule: &PlacementRule{GroupID: groupID, ID: id}}
+		result, err := reaction(action)
+		if err != nil || result == nil {
+			return nil, err
+		}
+		return result.(*PlacementRule), nil
+	}
+	return nil, nil
+}
+
+// GetPlacementRulesByGroup returns the placement rules of the group
+func (c *FakePDClient) GetPlacementRulesByGroup(groupID string) ([]*PlacementRule, error) {
+	if reaction, ok := c.reactions[GetPlacementRulesByGroupActionType]; ok {
+		action := &Action{Rule: &PlacementRule{GroupID: groupID}}
+		result, err := reaction(action)
+		if err != nil || result == nil {
+			return nil, err
+		}
+		return result.([]*PlacementRule), nil
+	}
+	return nil, nil
+}
+
+// GetPlacementRules returns the placement rules of all the groups
+func (c *FakePDClient) GetPlacementRules() ([]*PlacementRule, error) {
+	if reaction, ok := c.reactions[GetPlacementRulesActionType]; ok {
+		result, err := reaction(&Action{})
+		if err != nil || result == nil {
+			return nil, err
+		}
+		return result.([]*PlacementRule), nil
+	}
+	return nil, nil
+}
+
+// SetPlacementRule creates or updates the placement rule
+func (c *FakePDClient) SetPlacementRule(rule *PlacementRule) error {
+	if reaction, ok := c.reactions[SetPlacementRuleActionType]; ok {
+		action := &Action{Rule: rule}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+// DeletePlacementRule deletes the placement rule
+func (c *FakePDClient) DeletePlacementRule(groupID, id string) error {
+	if reaction, ok := c.reactions[DeletePlacementRuleActionType]; ok {
+		action := &Action{Rule: &PlacementRule{GroupID: groupID, ID: id}}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
 func (c *FakePDClient) BeginEvictLeader(storeID uint64) error {
 	if reaction, ok := c.reactions[BeginEvictLeaderActionType]; ok {
 		action := &Action{ID: storeID}
@@ -349,3 +521,69 @@ func (c *FakePDMSClient) TransferPrimary(newPrimary string) error {
 	_, err := c.fakeAPI(PDMSTransferPrimaryActionType, action)
 	return err
 }
+
+// FakePDEtcdClient implements a fake version of PDEtcdClient, which keeps the keys and the members in memory.
+type FakePDEtcdClient struct {
+	KVs     map[string]string
+	Members []*EtcdMember
+	// PromoteErr is returned by PromoteMember if it's set
+	PromoteErr error
+	nextID     uint64
+}
+
+func NewFakePDEtcdClient() *FakePDEtcdClient {
+	return &FakePDEtcdClient{KVs: map[string]string{}, nextID: 1000}
+}
+
+func (c *FakePDEtcdClient) Get(key string, prefix bool) ([]*KeyValue, error) {
+	var kvs []*KeyValue
+	for k, v := range c.KVs {
+		if k == key || (prefix && strings.HasPrefix(k, key)) {
+			kvs = append(kvs, &KeyValue{Key: k, Value: []byte(v)})
+		}
+	}
+	return kvs, nil
+}
+
+func (c *FakePDEtcdClient) PutKey(key, value string) error {
+	c.KVs[key] = value
+	return nil
+}
+
+func (c *FakePDEtcdClient) PutTTLKey(key, value string, _ int64) error {
+	c.KVs[key] = value
+	return nil
+}
+
+func (c *FakePDEtcdClient) DeleteKey(key string) error {
+	delete(c.KVs, key)
+	return nil
+}
+
+func (c *FakePDEtcdClient) ListMembers() ([]*EtcdMember, error) {
+	return c.Members, nil
+}
+
+func (c *FakePDEtcdClient) AddLearner(peerURL string) (*EtcdMember, error) {
+	c.nextID++
+	member := &EtcdMember{ID: c.nextID, PeerURLs: []string{peerURL}, IsLearner: true}
+	c.Members = append(c.Members, member)
+	return member, nil
+}
+
+func (c *FakePDEtcdClient) PromoteMember(id uint64) error {
+	if c.PromoteErr != nil {
+		return c.PromoteErr
+	}
+	for _, m := range c.Members {
+		if m.ID == id {
+			m.IsLearner = false
+			return nil
+		}
+	}
+	return fmt.Errorf("member %d not found", id)
+}
+
+func (c *FakePDEtcdClient) Close() error {
+	return nil
+}
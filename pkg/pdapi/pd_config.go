@@ -195,6 +195,10 @@ type PDScheduleConfig struct {
 	// Only used to display
 	SchedulersPayload map[string]interface{} `toml:"schedulers-payload" json:"schedulers-payload,omitempty"`
 
+	// EnableWitness is the option to enable the witness replicas, which only keep the raft logs of the regions.
+	// Imported from v6.6.0
+	EnableWitness *bool `toml:"enable-witness,omitempty" json:"enable-witness,string,omitempty"`
+
 	// EnableOneWayMerge is the option to enable one way merge. This means a Region can only be merged into the next region of it.
 	// Imported from v3.1.0
 	EnableOneWayMerge *bool `toml:"enable-one-way-merge" json:"enable-one-way-merge,string,omitempty"`
@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	"k8s.io/client-go/kubernetes"
 	corelisterv1 "k8s.io/client-go/listers/core/v1"
@@ -76,6 +77,16 @@ func UseHeadlessService(headless bool) Option {
 	}
 }
 
+// ResolveWith sets the resolver of the services of the target TC, it is used when generating the client
+// address from TC deployed in another K8s cluster, the cluster domain convention is used by default.
+func ResolveWith(spec *v1alpha1.AcrossK8sResolver) Option {
+	return func(c *clientConfig) {
+		if spec != nil {
+			c.resolver = NewResolver(spec)
+		}
+	}
+}
+
 // PDControlInterface is an interface that knows how to manage and get tidb cluster's PD client
 type PDControlInterface interface {
 	// GetPDClient provides PDClient of the tidb cluster.
@@ -91,6 +102,7 @@ type PDControlInterface interface {
 type clientConfig struct {
 	clusterDomain string
 	headlessSvc   bool // use headless service to connect, default to use service
+	resolver      Resolver
 
 	// clientURL is PD/Etcd addr. If it is empty, will generate from target TC
 	clientURL string
@@ -121,9 +133,13 @@ func (c *clientConfig) completeForPDClient(namespace Namespace, tcName, serviceN
 	}
 
 	if c.clientURL == "" {
-		c.clientURL = genClientUrl(namespace, tcName, scheme, c.clusterDomain, serviceName, c.headlessSvc)
+		c.clientURL = genClientUrl(namespace, tcName, scheme, c.clusterDomain, serviceName, c.headlessSvc, c.resolver)
 	}
 	genKey := genClientKey(scheme, namespace, tcName, c.clusterDomain)
+	if c.resolver != nil {
+		// the clients of the same TC resolved in different ways are different
+		genKey = fmt.Sprintf("%s.%s", genKey, c.clientURL)
+	}
 	if c.clientKey == "" {
 		c.clientKey = genKey
 	} else {
@@ -143,10 +159,13 @@ func (c *clientConfig) completeForEtcdClient(namespace Namespace, tcName string)
 	}
 
 	if c.clientURL == "" {
-		c.clientURL = genEtcdClientUrl(namespace, tcName, c.clusterDomain, c.headlessSvc)
+		c.clientURL = genEtcdClientUrl(namespace, tcName, c.clusterDomain, c.headlessSvc, c.resolver)
 	}
 	if c.clientKey == "" {
 		c.clientKey = genEtcdClientKey(namespace, tcName, c.clusterDomain, c.tlsEnable)
+		if c.resolver != nil {
+			c.clientKey = fmt.Sprintf("%s.%s", c.clientKey, c.clientURL)
+		}
 	}
 }
 
@@ -250,14 +269,22 @@ func (pdc *defaultPDControl) GetPDClient(namespace Namespace, tcName string, tls
 			return &pdClient{url: config.clientURL, httpClient: &http.Client{Timeout: DefaultTimeout}}
 		}
 
-		return NewPDClient(config.clientURL, DefaultTimeout, tlsConfig)
+		return newPDClient(config.clientURL, DefaultTimeout, tlsConfig, clusterTraceTransport(namespace, tcName))
 	}
 	if _, ok := pdc.pdClients[config.clientKey]; !ok {
-		pdc.pdClients[config.clientKey] = NewPDClient(config.clientURL, DefaultTimeout, nil)
+		pdc.pdClients[config.clientKey] = newPDClient(config.clientURL, DefaultTimeout, nil, clusterTraceTransport(namespace, tcName))
 	}
 	return pdc.pdClients[config.clientKey]
 }
 
+// clusterTraceTransport traces the requests of the clients of a tidb cluster as the children of
+// the phase of its reconcile which sends them
+func clusterTraceTransport(namespace Namespace, tcName string) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return tracing.ObjectTransport(string(namespace), tcName, rt)
+	}
+}
+
 func checkServiceName(name string) bool {
 	return name == TSOServiceName || name == SchedulingServiceName
 }
@@ -280,11 +307,11 @@ func (pdc *defaultPDControl) GetPDMSClient(namespace Namespace, tcName, serviceN
 			return &pdMSClient{url: config.clientURL, httpClient: &http.Client{Timeout: DefaultTimeout}}
 		}
 
-		return NewPDMSClient(serviceName, config.clientURL, DefaultTimeout, tlsConfig)
+		return newPDMSClient(serviceName, config.clientURL, DefaultTimeout, tlsConfig, clusterTraceTransport(namespace, tcName))
 	}
 
 	if _, ok := pdc.pdMSClients[config.clientURL]; !ok {
-		pdc.pdMSClients[config.clientURL] = NewPDMSClient(serviceName, config.clientURL, DefaultTimeout, nil)
+		pdc.pdMSClients[config.clientURL] = newPDMSClient(serviceName, config.clientURL, DefaultTimeout, nil, clusterTraceTransport(namespace, tcName))
 	}
 	return pdc.pdMSClients[config.clientURL]
 }
@@ -305,7 +332,7 @@ func genEtcdClientKey(namespace Namespace, clusterName string, clusterDomain str
 
 // genClientUrl builds the url of cluster pd client
 // serviceName need to be `tso` or `scheduling`, use `pd` as default
-func genClientUrl(namespace Namespace, clusterName, scheme, clusterDomain, serviceName string, headlessSvc bool) string {
+func genClientUrl(namespace Namespace, clusterName, scheme, clusterDomain, serviceName string, headlessSvc bool, resolver Resolver) string {
 	svc := "pd"
 	if serviceName != "" && checkServiceName(serviceName) {
 		svc = serviceName
@@ -313,26 +340,24 @@ func genClientUrl(namespace Namespace, clusterName, scheme, clusterDomain, servi
 	if headlessSvc {
 		svc = fmt.Sprintf("%s-peer", svc)
 	}
-
-	if len(namespace) == 0 {
-		return fmt.Sprintf("%s://%s-%s:%d", scheme, clusterName, svc, v1alpha1.DefaultPDClientPort)
+	if resolver == nil {
+		resolver = clusterDomainResolver{}
 	}
-	if len(clusterDomain) == 0 {
-		return fmt.Sprintf("%s://%s-%s.%s:%d", scheme, clusterName, svc, string(namespace), v1alpha1.DefaultPDClientPort)
-	}
-	return fmt.Sprintf("%s://%s-%s.%s.svc.%s:%d", scheme, clusterName, svc, string(namespace), clusterDomain, v1alpha1.DefaultPDClientPort)
+	host := resolver.ServiceHost(namespace, fmt.Sprintf("%s-%s", clusterName, svc), clusterDomain)
+	return fmt.Sprintf("%s://%s:%d", scheme, host, v1alpha1.DefaultPDClientPort)
 }
 
 // genEtcdClientUrl builds the url of cluster pd etcd client
-func genEtcdClientUrl(namespace Namespace, clusterName, clusterDomain string, headlessSvc bool) string {
+func genEtcdClientUrl(namespace Namespace, clusterName, clusterDomain string, headlessSvc bool, resolver Resolver) string {
 	svc := "pd"
 	if headlessSvc {
 		svc = "pd-peer"
 	}
-	if clusterDomain == "" {
-		return fmt.Sprintf("%s-%s.%s:%d", clusterName, svc, string(namespace), v1alpha1.DefaultPDClientPort)
+	if resolver == nil {
+		resolver = clusterDomainResolver{}
 	}
-	return fmt.Sprintf("%s-%s.%s.svc.%s:%d", clusterName, svc, string(namespace), clusterDomain, v1alpha1.DefaultPDClientPort)
+	host := resolver.ServiceHost(namespace, fmt.Sprintf("%s-%s", clusterName, svc), clusterDomain)
+	return fmt.Sprintf("%s:%d", host, v1alpha1.DefaultPDClientPort)
 }
 
 // FakePDControl implements a fake version of PDControlInterface.
@@ -342,10 +367,14 @@ type FakePDControl struct {
 
 func NewFakePDControl(secretLister corelisterv1.SecretLister) *FakePDControl {
 	return &FakePDControl{
-		defaultPDControl{secretLister: secretLister, pdClients: map[string]PDClient{}, pdMSClients: map[string]PDMSClient{}},
+		defaultPDControl{secretLister: secretLister, pdClients: map[string]PDClient{}, pdEtcdClients: map[string]PDEtcdClient{}, pdMSClients: map[string]PDMSClient{}},
 	}
 }
 
+func (fpc *FakePDControl) SetPDEtcdClient(namespace Namespace, tcName string, etcdClient PDEtcdClient) {
+	fpc.defaultPDControl.pdEtcdClients[genEtcdClientKey(namespace, tcName, "", false)] = etcdClient
+}
+
 func (fpc *FakePDControl) SetPDClient(namespace Namespace, tcName string, pdclient PDClient) {
 	fpc.defaultPDControl.pdClients[genClientKey("http", namespace, tcName, "")] = pdclient
 }
@@ -365,11 +394,11 @@ func (fpc *FakePDControl) SetPDClientWithAddress(peerURL string, pdclient PDClie
 }
 
 func (fpc *FakePDControl) SetPDMSClient(namespace Namespace, tcName, curService string, pdmsclient PDMSClient) {
-	fpc.defaultPDControl.pdMSClients[genClientUrl(namespace, tcName, "http", "", curService, false)] = pdmsclient
+	fpc.defaultPDControl.pdMSClients[genClientUrl(namespace, tcName, "http", "", curService, false, nil)] = pdmsclient
 }
 
 func (fpc *FakePDControl) SetPDMSClientWithClusterDomain(namespace Namespace, tcName, tcClusterDomain, curService string, pdmsclient PDMSClient) {
-	fpc.defaultPDControl.pdMSClients[genClientUrl(namespace, tcName, "http", tcClusterDomain, curService, false)] = pdmsclient
+	fpc.defaultPDControl.pdMSClients[genClientUrl(namespace, tcName, "http", tcClusterDomain, curService, false, nil)] = pdmsclient
 }
 
 func (fpc *FakePDControl) SetPDMSClientWithAddress(peerURL string, pdmsclient PDMSClient) {
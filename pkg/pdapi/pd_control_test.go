@@ -17,6 +17,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 )
 
 func TestPDControl(t *testing.T) {
@@ -123,6 +124,41 @@ func TestPDControl(t *testing.T) {
 					g.Expect(etcdClient.headlessSvc).To(BeTrue())
 				},
 			},
+			{
+				name: "resolve with cluster set",
+				options: []Option{
+					UseHeadlessService(true),
+					ClusterRef("cluster.local"),
+					ResolveWith(&v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterSet}),
+				},
+				tcName: "target-cluster",
+				tcNS:   "target-namespace",
+				expectConfig: func(pdClient *clientConfig, etcdClient *clientConfig) {
+					g.Expect(pdClient.clientURL).To(Equal("http://target-cluster-pd-peer.target-namespace.svc.clusterset.local:2379"))
+					g.Expect(pdClient.clientKey).To(Equal("http.target-cluster.target-namespace.cluster.local.http://target-cluster-pd-peer.target-namespace.svc.clusterset.local:2379"))
+
+					g.Expect(etcdClient.clientURL).To(Equal("target-cluster-pd-peer.target-namespace.svc.clusterset.local:2379"))
+					g.Expect(etcdClient.clientKey).To(Equal("target-cluster.target-namespace.cluster.local.false.target-cluster-pd-peer.target-namespace.svc.clusterset.local:2379"))
+				},
+			},
+			{
+				name: "resolve with static endpoints",
+				options: []Option{
+					ClusterRef("cluster.local"),
+					ResolveWith(&v1alpha1.AcrossK8sResolver{
+						Type: v1alpha1.AcrossK8sResolverStatic,
+						Endpoints: map[string]string{
+							"target-cluster-pd.target-namespace.svc.cluster.local": "pd.example.com",
+						},
+					}),
+				},
+				tcName: "target-cluster",
+				tcNS:   "target-namespace",
+				expectConfig: func(pdClient *clientConfig, etcdClient *clientConfig) {
+					g.Expect(pdClient.clientURL).To(Equal("http://pd.example.com:2379"))
+					g.Expect(etcdClient.clientURL).To(Equal("pd.example.com:2379"))
+				},
+			},
 			{
 				name: "specify client",
 				options: []Option{
@@ -0,0 +1,250 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultCircuitBreakerCoolOff is the default duration the requests to a PD cluster
+	// are rejected for after the circuit breaker opens
+	DefaultCircuitBreakerCoolOff = 30 * time.Second
+
+	resultRejected = "rejected"
+	resultError    = "error"
+)
+
+// ErrCircuitBreakerOpen is returned for the requests to a PD cluster rejected by the circuit breaker
+// after consecutive failures, so a struggling PD is not overwhelmed by the retries of the operator.
+var ErrCircuitBreakerOpen = errors.New("circuit breaker is open after consecutive failures of PD")
+
+var (
+	breakerLock sync.Mutex
+	// the circuit breaker is disabled unless it's enabled by SetCircuitBreaker
+	breakerThreshold = 0
+	breakerCoolOff   = DefaultCircuitBreakerCoolOff
+	// breakers are shared by all the clients of the same PD, the clients with TLS are not cached
+	// by the PD control and a breaker per client would never open
+	breakers = map[string]*circuitBreaker{}
+	// now is replaced in the tests
+	now = time.Now
+
+	fenceLock sync.RWMutex
+	// fence is checked before the deletions of the PD members and stores, nil means no fence
+	fence func() error
+)
+
+// SetFence sets the check called before the deletions of the PD members and stores, the deletions are
+// rejected if it returns an error, e.g. the operator is no longer the leader.
+func SetFence(check func() error) {
+	fenceLock.Lock()
+	defer fenceLock.Unlock()
+	fence = check
+}
+
+// checkFence returns an error if the request is a deletion of a PD member or store rejected by the fence
+func checkFence(req *http.Request) error {
+	if req.Method != http.MethodDelete {
+		return nil
+	}
+	if !strings.HasPrefix(req.URL.Path, "/"+membersPrefix+"/") && !strings.HasPrefix(req.URL.Path, "/"+storePrefix+"/") {
+		return nil
+	}
+	fenceLock.RLock()
+	check := fence
+	fenceLock.RUnlock()
+	if check == nil {
+		return nil
+	}
+	return check()
+}
+
+// SetCircuitBreaker sets the number of consecutive failures after which the requests to a PD cluster
+// are rejected for the cool-off duration, the circuit breaker is disabled if threshold is not positive.
+func SetCircuitBreaker(threshold int, coolOff time.Duration) {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+	breakerThreshold = threshold
+	breakerCoolOff = coolOff
+	breakers = map[string]*circuitBreaker{}
+}
+
+func getCircuitBreaker(pd string) *circuitBreaker {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+	if breakerThreshold <= 0 {
+		return nil
+	}
+	cb, ok := breakers[pd]
+	if !ok {
+		cb = &circuitBreaker{pd: pd, threshold: breakerThreshold, coolOff: breakerCoolOff}
+		breakers[pd] = cb
+	}
+	return cb
+}
+
+// circuitBreaker opens after threshold consecutive failures and rejects the requests until the
+// cool-off duration elapses, then a single trial request is allowed to decide whether to close it.
+type circuitBreaker struct {
+	pd        string
+	threshold int
+	coolOff   time.Duration
+
+	lock      sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// allow returns whether a request can be sent to PD
+func (cb *circuitBreaker) allow() bool {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if cb.failures < cb.threshold {
+		return true
+	}
+	if now().Before(cb.openUntil) || cb.trial {
+		return false
+	}
+	// half open, let a single request through to probe PD
+	cb.trial = true
+	return true
+}
+
+// done records the result of a request allowed by the circuit breaker
+func (cb *circuitBreaker) done(success bool) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	cb.trial = false
+	if success {
+		if cb.failures >= cb.threshold {
+			klog.Infof("circuit breaker of PD %s is closed", cb.pd)
+			metrics.PDClientCircuitBreakerOpen.WithLabelValues(cb.pd).Set(0)
+		}
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.openUntil = now().Add(cb.coolOff)
+		klog.Warningf("circuit breaker of PD %s is open for %s after %d consecutive failures", cb.pd, cb.coolOff, cb.failures)
+		metrics.PDClientCircuitBreakerOpen.WithLabelValues(cb.pd).Set(1)
+	}
+}
+
+// auditedTransport records the metrics and logs of the requests to PD and rejects them
+// when the circuit breaker of the PD is open or the deletions are rejected by the fence
+type auditedTransport struct {
+	pd   string
+	next http.RoundTripper
+}
+
+// wrapTransport wraps the transport of the clients to the PD with the address
+func wrapTransport(addr string, next http.RoundTripper) http.RoundTripper {
+	pd := addr
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		pd = u.Host
+	}
+	return &auditedTransport{pd: pd, next: next}
+}
+
+func (t *auditedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := normalizeEndpoint(req.URL.Path)
+	if err := checkFence(req); err != nil {
+		metrics.PDClientRequests.WithLabelValues(t.pd, endpoint, req.Method, resultRejected).Inc()
+		klog.Errorf("request %s %s to PD %s is rejected by the fence: %v", req.Method, req.URL.Path, t.pd, err)
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	cb := getCircuitBreaker(t.pd)
+	if cb != nil && !cb.allow() {
+		metrics.PDClientRequests.WithLabelValues(t.pd, endpoint, req.Method, resultRejected).Inc()
+		klog.V(6).Infof("request %s %s to PD %s is rejected by the circuit breaker", req.Method, req.URL.Path, t.pd)
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrCircuitBreakerOpen)
+	}
+
+	start := now()
+	resp, err := t.next.RoundTrip(req)
+	duration := now().Sub(start)
+	metrics.PDClientRequestDuration.WithLabelValues(t.pd, endpoint, req.Method).Observe(duration.Seconds())
+
+	result := resultError
+	if err == nil {
+		result = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.PDClientRequests.WithLabelValues(t.pd, endpoint, req.Method, result).Inc()
+	klog.V(6).Infof("request %s %s to PD %s: %s, took %s", req.Method, req.URL.Path, t.pd, result, duration)
+
+	if cb != nil {
+		cb.done(!isPDUnavailable(resp, err))
+	}
+	return resp, err
+}
+
+// isPDUnavailable returns whether the request failed because PD is unreachable or overloaded, only such
+// failures count for the circuit breaker. The other errors responded by PD, e.g. 500 for a store that
+// doesn't exist, mean PD is serving.
+func isPDUnavailable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// normalizeEndpoint replaces the IDs and names in the path so the cardinality of the metrics is bounded,
+// e.g. /pd/api/v1/store/1 is normalized to /pd/api/v1/store/{id}
+func normalizeEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		switch {
+		case seg == "":
+		case i > 0 && segments[i-1] == "name":
+			segments[i] = "{name}"
+		case isDigits(seg):
+			segments[i] = "{id}"
+		default:
+			// e.g. evict-leader-scheduler-1
+			if idx := strings.LastIndex(seg, "-"); idx > 0 && isDigits(seg[idx+1:]) {
+				segments[i] = seg[:idx+1] + "{id}"
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	g := NewGomegaWithT(t)
+	SetCircuitBreaker(3, time.Minute)
+	defer SetCircuitBreaker(0, DefaultCircuitBreakerCoolOff)
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	var requests int32
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	svc := getClientServer(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Write([]byte("[]"))
+	})
+	defer svc.Close()
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, nil)
+
+	// the errors responded by PD don't count as failures
+	for i := 0; i < 5; i++ {
+		_, err := pdClient.GetHealth()
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, ErrCircuitBreakerOpen)).To(BeFalse())
+	}
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(5)))
+
+	// the circuit breaker opens after the consecutive failures
+	status.Store(http.StatusServiceUnavailable)
+	for i := 0; i < 3; i++ {
+		_, err := pdClient.GetHealth()
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, ErrCircuitBreakerOpen)).To(BeFalse())
+	}
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(8)))
+
+	// the requests are rejected without reaching PD
+	_, err := pdClient.GetHealth()
+	g.Expect(errors.Is(err, ErrCircuitBreakerOpen)).To(BeTrue())
+	// the clients of the same PD share the circuit breaker
+	_, err = NewPDClient(svc.URL, DefaultTimeout, nil).GetHealth()
+	g.Expect(errors.Is(err, ErrCircuitBreakerOpen)).To(BeTrue())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(8)))
+
+	// the trial request after the cool-off fails and the circuit breaker opens again
+	current = current.Add(time.Minute)
+	_, err = pdClient.GetHealth()
+	g.Expect(errors.Is(err, ErrCircuitBreakerOpen)).To(BeFalse())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(9)))
+	_, err = pdClient.GetHealth()
+	g.Expect(errors.Is(err, ErrCircuitBreakerOpen)).To(BeTrue())
+
+	// the circuit breaker closes after the trial request succeeds
+	status.Store(http.StatusOK)
+	current = current.Add(time.Minute)
+	_, err = pdClient.GetHealth()
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = pdClient.GetHealth()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(11)))
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the circuit breaker is disabled by default
+	svc := getClientServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer svc.Close()
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, nil)
+	for i := 0; i < 10; i++ {
+		_, err := pdClient.GetHealth()
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, ErrCircuitBreakerOpen)).To(BeFalse())
+	}
+}
+
+func TestFence(t *testing.T) {
+	g := NewGomegaWithT(t)
+	errNotLeader := errors.New("not the leader")
+	SetFence(func() error { return errNotLeader })
+	defer SetFence(nil)
+
+	var requests int32
+	svc := getClientServer(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	})
+	defer svc.Close()
+	transport := wrapTransport(svc.URL, http.DefaultTransport)
+	send := func(method, path string) error {
+		req, err := http.NewRequest(method, svc.URL+path, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		resp, err := transport.RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// the deletions of the members and stores are rejected without reaching PD
+	for _, path := range []string{"/pd/api/v1/store/1", "/pd/api/v1/members/name/pd-0", "/pd/api/v1/members/id/1"} {
+		g.Expect(errors.Is(send(http.MethodDelete, path), errNotLeader)).To(BeTrue())
+	}
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(0)))
+
+	// the other requests are allowed
+	g.Expect(send(http.MethodGet, "/pd/api/v1/store/1")).To(Succeed())
+	g.Expect(send(http.MethodDelete, "/pd/api/v1/schedulers/evict-leader-scheduler-1")).To(Succeed())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(2)))
+
+	SetFence(func() error { return nil })
+	g.Expect(send(http.MethodDelete, "/pd/api/v1/store/1")).To(Succeed())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(3)))
+}
+
+func TestNormalizeEndpoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tcs := map[string]string{
+		"/pd/api/v1/health":                                       "/pd/api/v1/health",
+		"/pd/api/v1/store/1":                                      "/pd/api/v1/store/{id}",
+		"/pd/api/v1/store/12/state":                               "/pd/api/v1/store/{id}/state",
+		"/pd/api/v1/members/name/basic-pd-0":                      "/pd/api/v1/members/name/{name}",
+		"/pd/api/v1/members/id/42":                                "/pd/api/v1/members/id/{id}",
+		"/pd/api/v1/schedulers/evict-leader-scheduler-3":          "/pd/api/v1/schedulers/evict-leader-scheduler-{id}",
+		"/pd/api/v1/scheduler-config/evict-leader-scheduler/list": "/pd/api/v1/scheduler-config/evict-leader-scheduler/list",
+	}
+	for path, want := range tcs {
+		g.Expect(normalizeEndpoint(path)).To(Equal(want), path)
+	}
+}
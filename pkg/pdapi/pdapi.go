@@ -25,6 +25,7 @@ import (
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 	"github.com/pingcap/tidb-operator/pkg/util/crypto"
 	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
 	"github.com/tikv/pd/pkg/typeutil"
@@ -64,11 +65,36 @@ type PDClient interface {
 	GetTombStoneStores() (*StoresInfo, error)
 	// GetStore gets a TiKV store for a specific store id from cluster
 	GetStore(storeID uint64) (*StoreInfo, error)
+	// GetStoreRegions lists the regions with a replica on a specific store
+	GetStoreRegions(storeID uint64) (*RegionsInfo, error)
+	// GetRegionsByCheck lists the regions in the unhealthy state checked by PD, e.g. RegionCheckMissPeer,
+	// which respects the placement rules
+	GetRegionsByCheck(state RegionCheckState) (*RegionsInfo, error)
 	// SetStoreLabels compares store labels with node labels
 	// for historic reasons, PD stores TiKV labels as []*StoreLabel which is a key-value pair slice
 	SetStoreLabels(storeID uint64, labels map[string]string) (bool, error)
+	// GetStoreLimits returns the store limits of all the stores, keyed by the store id
+	GetStoreLimits() (map[uint64]StoreLimitConfig, error)
+	// SetStoreLimit sets the store limit of the type, e.g. StoreLimitTypeAddPeer, for a store
+	SetStoreLimit(storeID uint64, limitType string, rate float64) error
+	// SetStoreWeight sets the leader and region weights of a store
+	SetStoreWeight(storeID uint64, leaderWeight, regionWeight float64) error
 	// UpdateReplicationConfig updates the replication config
 	UpdateReplicationConfig(config PDReplicationConfig) error
+	// UpdateScheduleConfig updates the schedule config, only the fields set are updated
+	UpdateScheduleConfig(config PDScheduleConfig) error
+	// UpdateConfig updates the runtime config items, the keys are in the format of "schedule.leader-schedule-limit"
+	UpdateConfig(config map[string]interface{}) error
+	// GetPlacementRule returns the placement rule, nil is returned if the rule does not exist
+	GetPlacementRule(groupID, id string) (*PlacementRule, error)
+	// GetPlacementRulesByGroup returns the placement rules of the group
+	GetPlacementRulesByGroup(groupID string) ([]*PlacementRule, error)
+	// GetPlacementRules returns the placement rules of all the groups
+	GetPlacementRules() ([]*PlacementRule, error)
+	// SetPlacementRule creates or updates the placement rule
+	SetPlacementRule(rule *PlacementRule) error
+	// DeletePlacementRule deletes the placement rule
+	DeletePlacementRule(groupID, id string) error
 	// DeleteStore deletes a TiKV store from cluster
 	DeleteStore(storeID uint64) error
 	// SetStoreState sets store to specified state.
@@ -77,6 +103,11 @@ type PDClient interface {
 	DeleteMember(name string) error
 	// DeleteMemberByID deletes a PD member from cluster
 	DeleteMemberByID(memberID uint64) error
+	// SetMemberLeaderPriority sets the leader priority of a PD member,
+	// the member with a higher priority is preferred to be the leader
+	SetMemberLeaderPriority(name string, priority int) error
+	// GetMemberLeaderPriority returns the leader priority of a PD member
+	GetMemberLeaderPriority(name string) (int, error)
 	// BeginEvictLeader initiates leader eviction for a storeID.
 	// This is used when upgrading a pod.
 	BeginEvictLeader(storeID uint64) error
@@ -106,16 +137,22 @@ type PDClient interface {
 }
 
 var (
-	healthPrefix           = "pd/api/v1/health"
-	membersPrefix          = "pd/api/v1/members"
-	storesPrefix           = "pd/api/v1/stores"
-	storePrefix            = "pd/api/v1/store"
-	configPrefix           = "pd/api/v1/config"
-	clusterIDPrefix        = "pd/api/v1/cluster"
-	schedulersPrefix       = "pd/api/v1/schedulers"
-	pdLeaderPrefix         = "pd/api/v1/leader"
-	pdLeaderTransferPrefix = "pd/api/v1/leader/transfer"
-	pdReplicationPrefix    = "pd/api/v1/config/replicate"
+	healthPrefix            = "pd/api/v1/health"
+	membersPrefix           = "pd/api/v1/members"
+	storesPrefix            = "pd/api/v1/stores"
+	storePrefix             = "pd/api/v1/store"
+	storeRegionsPrefix      = "pd/api/v1/regions/store"
+	regionsCheckPrefix      = "pd/api/v1/regions/check"
+	configPrefix            = "pd/api/v1/config"
+	clusterIDPrefix         = "pd/api/v1/cluster"
+	schedulersPrefix        = "pd/api/v1/schedulers"
+	pdLeaderPrefix          = "pd/api/v1/leader"
+	pdLeaderTransferPrefix  = "pd/api/v1/leader/transfer"
+	pdReplicationPrefix     = "pd/api/v1/config/replicate"
+	pdSchedulePrefix        = "pd/api/v1/config/schedule"
+	placementRulePrefix     = "pd/api/v1/config/rule"
+	placementRulesPrefix    = "pd/api/v1/config/rules/group"
+	allPlacementRulesPrefix = "pd/api/v1/config/rules"
 	// evictLeaderSchedulerConfigPrefix is the prefix of evict-leader-scheduler
 	// config API, available since PD v3.1.0.
 	evictLeaderSchedulerConfigPrefix = "pd/api/v1/scheduler-config/evict-leader-scheduler/list"
@@ -136,6 +173,11 @@ type pdClient struct {
 
 // NewPDClient returns a new PDClient
 func NewPDClient(url string, timeout time.Duration, tlsConfig *tls.Config) PDClient {
+	return newPDClient(url, timeout, tlsConfig, tracing.Transport)
+}
+
+// newPDClient returns a new pdClient whose transport is wrapped by traceTransport
+func newPDClient(url string, timeout time.Duration, tlsConfig *tls.Config, traceTransport func(http.RoundTripper) http.RoundTripper) *pdClient {
 	var disableKeepalive bool
 	if tlsConfig != nil {
 		disableKeepalive = true
@@ -144,7 +186,7 @@ func NewPDClient(url string, timeout time.Duration, tlsConfig *tls.Config) PDCli
 		url: url,
 		httpClient: &http.Client{
 			Timeout:   timeout,
-			Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: disableKeepalive},
+			Transport: traceTransport(wrapTransport(url, &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: disableKeepalive})),
 		},
 	}
 }
@@ -181,6 +223,11 @@ type StoreStatus struct {
 	ReceivingSnapCount uint32            `json:"receiving_snap_count"`
 	ApplyingSnapCount  uint32            `json:"applying_snap_count"`
 	IsBusy             bool              `json:"is_busy"`
+	LeaderWeight       float64           `json:"leader_weight"`
+	RegionWeight       float64           `json:"region_weight"`
+	// SlowScore is the slow score of the store ranging from 1 to 100, a higher score means the store
+	// is slower, e.g. as its disk is failing
+	SlowScore uint64 `json:"slow_score"`
 
 	StartTS         time.Time         `json:"start_ts"`
 	LastHeartbeatTS time.Time         `json:"last_heartbeat_ts"`
@@ -193,12 +240,69 @@ type StoreInfo struct {
 	Status *StoreStatus `json:"status"`
 }
 
+// StoreLimitConfig is the store limits of a store returned from PD RESTful interface,
+// the rates are the numbers of the peers added to or removed from the store per minute
+type StoreLimitConfig struct {
+	AddPeer    float64 `json:"add-peer"`
+	RemovePeer float64 `json:"remove-peer"`
+}
+
+const (
+	// StoreLimitTypeAddPeer limits the speed of adding peers to a store
+	StoreLimitTypeAddPeer = "add-peer"
+	// StoreLimitTypeRemovePeer limits the speed of removing peers from a store
+	StoreLimitTypeRemovePeer = "remove-peer"
+)
+
 // StoresInfo is stores info returned from PD RESTful interface
 type StoresInfo struct {
 	Count  int          `json:"count"`
 	Stores []*StoreInfo `json:"stores"`
 }
 
+// RegionPeer is a replica of a region returned from PD RESTful interface
+type RegionPeer struct {
+	ID        uint64 `json:"id"`
+	StoreID   uint64 `json:"store_id"`
+	RoleName  string `json:"role_name,omitempty"`
+	IsLearner bool   `json:"is_learner,omitempty"`
+	IsWitness bool   `json:"is_witness,omitempty"`
+}
+
+// RegionPeerStats is a replica of a region not responding in time
+type RegionPeerStats struct {
+	Peer        RegionPeer `json:"peer"`
+	DownSeconds uint64     `json:"down_seconds,omitempty"`
+}
+
+// RegionInfo is a single region info returned from PD RESTful interface
+type RegionInfo struct {
+	ID           uint64            `json:"id"`
+	Peers        []RegionPeer      `json:"peers,omitempty"`
+	Leader       RegionPeer        `json:"leader,omitempty"`
+	DownPeers    []RegionPeerStats `json:"down_peers,omitempty"`
+	PendingPeers []RegionPeer      `json:"pending_peers,omitempty"`
+}
+
+// RegionsInfo is regions info returned from PD RESTful interface
+type RegionsInfo struct {
+	Count   int           `json:"count"`
+	Regions []*RegionInfo `json:"regions"`
+}
+
+// RegionCheckState is the unhealthy state of the regions checked by PD
+type RegionCheckState string
+
+const (
+	// RegionCheckMissPeer is the state of the regions with less voters than required by the placement rules
+	// or max-replicas
+	RegionCheckMissPeer RegionCheckState = "miss-peer"
+	// RegionCheckDownPeer is the state of the regions with a down peer
+	RegionCheckDownPeer RegionCheckState = "down-peer"
+	// RegionCheckPendingPeer is the state of the regions with a pending peer
+	RegionCheckPendingPeer RegionCheckState = "pending-peer"
+)
+
 // MembersInfo is PD members info returned from PD RESTful interface
 // type Members map[string][]*pdpb.Member
 type MembersInfo struct {
@@ -275,6 +379,30 @@ type RecoveringMark struct {
 	Mark bool `json:"marked"`
 }
 
+// PlacementRule is the placement rule of the replicas of the regions, see
+// https://docs.pingcap.com/tidb/stable/configure-placement-rules
+type PlacementRule struct {
+	GroupID          string            `json:"group_id"`
+	ID               string            `json:"id"`
+	Index            int               `json:"index,omitempty"`
+	Override         bool              `json:"override,omitempty"`
+	StartKeyHex      string            `json:"start_key"`
+	EndKeyHex        string            `json:"end_key"`
+	Role             string            `json:"role"`
+	IsWitness        bool              `json:"is_witness,omitempty"`
+	Count            int               `json:"count"`
+	LabelConstraints []LabelConstraint `json:"label_constraints,omitempty"`
+	LocationLabels   []string          `json:"location_labels,omitempty"`
+	IsolationLevel   string            `json:"isolation_level,omitempty"`
+}
+
+// LabelConstraint is the constraint of the labels of the stores the replicas are placed on
+type LabelConstraint struct {
+	Key    string   `json:"key"`
+	Op     string   `json:"op"`
+	Values []string `json:"values"`
+}
+
 func (c *pdClient) GetHealth() (*HealthInfo, error) {
 	apiURL := fmt.Sprintf("%s/%s", c.url, healthPrefix)
 	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
@@ -408,6 +536,34 @@ func (c *pdClient) GetStore(storeID uint64) (*StoreInfo, error) {
 	return storeInfo, nil
 }
 
+func (c *pdClient) GetStoreRegions(storeID uint64) (*RegionsInfo, error) {
+	apiURL := fmt.Sprintf("%s/%s/%d", c.url, storeRegionsPrefix, storeID)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	regionsInfo := &RegionsInfo{}
+	err = json.Unmarshal(body, regionsInfo)
+	if err != nil {
+		return nil, err
+	}
+	return regionsInfo, nil
+}
+
+func (c *pdClient) GetRegionsByCheck(state RegionCheckState) (*RegionsInfo, error) {
+	apiURL := fmt.Sprintf("%s/%s/%s", c.url, regionsCheckPrefix, state)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	regionsInfo := &RegionsInfo{}
+	err = json.Unmarshal(body, regionsInfo)
+	if err != nil {
+		return nil, err
+	}
+	return regionsInfo, nil
+}
+
 func (c *pdClient) DeleteStore(storeID uint64) error {
 	var exist bool
 	stores, err := c.GetStores()
@@ -534,6 +690,37 @@ func (c *pdClient) DeleteMember(name string) error {
 	return fmt.Errorf("failed %v to delete member %s: %v", res.StatusCode, name, err2)
 }
 
+func (c *pdClient) SetMemberLeaderPriority(name string, priority int) error {
+	apiURL := fmt.Sprintf("%s/%s/name/%s", c.url, membersPrefix, name)
+	data, err := json.Marshal(map[string]int{"leader-priority": priority})
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err2 := httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to set leader priority of member %s: %v", res.StatusCode, name, err2)
+}
+
+func (c *pdClient) GetMemberLeaderPriority(name string) (int, error) {
+	members, err := c.GetMembers()
+	if err != nil {
+		return 0, err
+	}
+	for _, member := range members.Members {
+		if member.Name == name {
+			return int(member.LeaderPriority), nil
+		}
+	}
+	return 0, fmt.Errorf("member %s not found", name)
+}
+
 func (c *pdClient) SetStoreLabels(storeID uint64, labels map[string]string) (bool, error) {
 	apiURL := fmt.Sprintf("%s/%s/%d/label", c.url, storePrefix, storeID)
 	data, err := json.Marshal(labels)
@@ -552,6 +739,61 @@ func (c *pdClient) SetStoreLabels(storeID uint64, labels map[string]string) (boo
 	return false, fmt.Errorf("failed %v to set store labels: %v", res.StatusCode, err2)
 }
 
+func (c *pdClient) GetStoreLimits() (map[uint64]StoreLimitConfig, error) {
+	apiURL := fmt.Sprintf("%s/%s/limit", c.url, storesPrefix)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	limits := map[uint64]StoreLimitConfig{}
+	if err := json.Unmarshal(body, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+func (c *pdClient) SetStoreLimit(storeID uint64, limitType string, rate float64) error {
+	apiURL := fmt.Sprintf("%s/%s/%d/limit", c.url, storePrefix, storeID)
+	data, err := json.Marshal(map[string]interface{}{
+		"rate": rate,
+		"type": limitType,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to set %s limit of store %d: %v", res.StatusCode, limitType, storeID, err)
+}
+
+func (c *pdClient) SetStoreWeight(storeID uint64, leaderWeight, regionWeight float64) error {
+	apiURL := fmt.Sprintf("%s/%s/%d/weight", c.url, storePrefix, storeID)
+	data, err := json.Marshal(map[string]interface{}{
+		"leader": leaderWeight,
+		"region": regionWeight,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to set weights of store %d: %v", res.StatusCode, storeID, err)
+}
+
 func (c *pdClient) UpdateReplicationConfig(config PDReplicationConfig) error {
 	apiURL := fmt.Sprintf("%s/%s", c.url, pdReplicationPrefix)
 	data, err := json.Marshal(config)
@@ -570,6 +812,129 @@ func (c *pdClient) UpdateReplicationConfig(config PDReplicationConfig) error {
 	return fmt.Errorf("failed %v to update replication: %v", res.StatusCode, err)
 }
 
+func (c *pdClient) UpdateScheduleConfig(config PDScheduleConfig) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, pdSchedulePrefix)
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to update schedule config: %v", res.StatusCode, err)
+}
+
+func (c *pdClient) UpdateConfig(config map[string]interface{}) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, configPrefix)
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to update config: %v", res.StatusCode, err)
+}
+
+func (c *pdClient) GetPlacementRule(groupID, id string) (*PlacementRule, error) {
+	apiURL := fmt.Sprintf("%s/%s/%s/%s", c.url, placementRulePrefix, groupID, id)
+	res, err := c.httpClient.Get(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		err = httputil.ReadErrorBody(res.Body)
+		return nil, fmt.Errorf("failed %v to get placement rule %s/%s: %v", res.StatusCode, groupID, id, err)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	rule := &PlacementRule{}
+	if err := json.Unmarshal(body, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (c *pdClient) GetPlacementRulesByGroup(groupID string) ([]*PlacementRule, error) {
+	apiURL := fmt.Sprintf("%s/%s/%s", c.url, placementRulesPrefix, groupID)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*PlacementRule
+	if err := json.Unmarshal(body, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (c *pdClient) GetPlacementRules() ([]*PlacementRule, error) {
+	apiURL := fmt.Sprintf("%s/%s", c.url, allPlacementRulesPrefix)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*PlacementRule
+	if err := json.Unmarshal(body, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (c *pdClient) SetPlacementRule(rule *PlacementRule) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, placementRulePrefix)
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to set placement rule %s/%s: %v", res.StatusCode, rule.GroupID, rule.ID, err)
+}
+
+func (c *pdClient) DeletePlacementRule(groupID, id string) error {
+	apiURL := fmt.Sprintf("%s/%s/%s/%s", c.url, placementRulePrefix, groupID, id)
+	req, err := http.NewRequest("DELETE", apiURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to delete placement rule %s/%s: %v", res.StatusCode, groupID, id, err)
+}
+
 func (c *pdClient) BeginEvictLeader(storeID uint64) error {
 	leaderEvictInfo := getLeaderEvictSchedulerInfo(storeID)
 	apiURL := fmt.Sprintf("%s/%s", c.url, schedulersPrefix)
@@ -305,6 +305,61 @@ func TestGetStore(t *testing.T) {
 	}
 }
 
+func TestGetStoreRegions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	id := uint64(1)
+	regions := &RegionsInfo{
+		Count: 1,
+		Regions: []*RegionInfo{{
+			ID:           2,
+			Peers:        []RegionPeer{{ID: 3, StoreID: id}, {ID: 4, StoreID: 5}},
+			PendingPeers: []RegionPeer{{ID: 4, StoreID: 5}},
+		}},
+	}
+	regionsBytes, err := json.Marshal(regions)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("GET"), "test method")
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/%d", storeRegionsPrefix, id)), "test url")
+
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Write(regionsBytes)
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	result, err := pdClient.GetStoreRegions(id)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(regions))
+}
+
+func TestGetRegionsByCheck(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	regions := &RegionsInfo{
+		Count:   1,
+		Regions: []*RegionInfo{{ID: 2, Peers: []RegionPeer{{ID: 3, StoreID: 1}}}},
+	}
+	regionsBytes, err := json.Marshal(regions)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("GET"), "test method")
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/miss-peer", regionsCheckPrefix)), "test url")
+
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Write(regionsBytes)
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	result, err := pdClient.GetRegionsByCheck(RegionCheckMissPeer)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(regions))
+}
+
 func TestSetStoreLabels(t *testing.T) {
 	g := NewGomegaWithT(t)
 	id := uint64(1)
@@ -353,6 +408,195 @@ func TestSetStoreLabels(t *testing.T) {
 	}
 }
 
+func TestSetStoreWeight(t *testing.T) {
+	g := NewGomegaWithT(t)
+	id := uint64(1)
+	for _, ok := range []bool{true, false} {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/%d/weight", storePrefix, id)), "check url")
+
+			weights := map[string]float64{}
+			g.Expect(readJSON(request.Body, &weights)).To(Succeed())
+			g.Expect(weights).To(Equal(map[string]float64{"leader": 0.5, "region": 2}), "check weights")
+
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			if ok {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		defer svc.Close()
+
+		pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+		err := pdClient.SetStoreWeight(id, 0.5, 2)
+		if ok {
+			g.Expect(err).To(Succeed())
+		} else {
+			g.Expect(err).To(HaveOccurred())
+		}
+	}
+}
+
+func TestPlacementRule(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rule := &PlacementRule{
+		GroupID: "pd",
+		ID:      "witness",
+		Role:    "voter",
+		Count:   1,
+		LabelConstraints: []LabelConstraint{
+			{Key: "witness", Op: "in", Values: []string{"true"}},
+		},
+	}
+	rules := map[string]*PlacementRule{}
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		switch request.Method {
+		case "POST":
+			g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s", placementRulePrefix)), "check url")
+			r := &PlacementRule{}
+			g.Expect(readJSON(request.Body, r)).To(Succeed())
+			rules[r.GroupID+"/"+r.ID] = r
+		case "GET", "DELETE":
+			key := request.URL.Path[len(placementRulePrefix)+2:]
+			r, ok := rules[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if request.Method == "DELETE" {
+				delete(rules, key)
+				return
+			}
+			data, err := json.Marshal(r)
+			g.Expect(err).NotTo(HaveOccurred())
+			w.Write(data)
+		}
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	got, err := pdClient.GetPlacementRule("pd", "witness")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(BeNil())
+
+	g.Expect(pdClient.SetPlacementRule(rule)).To(Succeed())
+	got, err = pdClient.GetPlacementRule("pd", "witness")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(rule))
+
+	g.Expect(pdClient.DeletePlacementRule("pd", "witness")).To(Succeed())
+	g.Expect(rules).To(BeEmpty())
+	// deleting a rule not found is ok
+	g.Expect(pdClient.DeletePlacementRule("pd", "witness")).To(Succeed())
+}
+
+func TestGetPlacementRulesByGroup(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rules := []*PlacementRule{
+		{GroupID: "tiflash", ID: "table-45-r", Role: "learner", Count: 2},
+		{GroupID: "tiflash", ID: "table-46-r", Role: "learner", Count: 1},
+	}
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/tiflash", placementRulesPrefix)), "check url")
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		data, err := json.Marshal(rules)
+		g.Expect(err).NotTo(HaveOccurred())
+		w.Write(data)
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	got, err := pdClient.GetPlacementRulesByGroup("tiflash")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(rules))
+}
+
+func TestGetPlacementRules(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rules := []*PlacementRule{
+		{GroupID: "pd", ID: "default", Role: "voter", Count: 3},
+		{GroupID: "tiflash", ID: "table-45-r", Role: "learner", Count: 2},
+	}
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.URL.Path).To(Equal("/"+allPlacementRulesPrefix), "check url")
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		data, err := json.Marshal(rules)
+		g.Expect(err).NotTo(HaveOccurred())
+		w.Write(data)
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	got, err := pdClient.GetPlacementRules()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(rules))
+}
+
+func TestMemberLeaderPriority(t *testing.T) {
+	g := NewGomegaWithT(t)
+	members := &MembersInfo{
+		Members: []*pdpb.Member{{Name: "pd-0", MemberId: 1}, {Name: "pd-1", MemberId: 2}},
+	}
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		switch request.Method {
+		case "POST":
+			name := request.URL.Path[len(membersPrefix)+len("/name/")+1:]
+			body := map[string]int{}
+			g.Expect(readJSON(request.Body, &body)).To(Succeed())
+			for _, m := range members.Members {
+				if m.Name == name {
+					m.LeaderPriority = int32(body["leader-priority"])
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("member not found"))
+		case "GET":
+			g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s", membersPrefix)), "check url")
+			data, err := json.Marshal(members)
+			g.Expect(err).NotTo(HaveOccurred())
+			w.Write(data)
+		}
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	g.Expect(pdClient.SetMemberLeaderPriority("pd-1", 5)).To(Succeed())
+	priority, err := pdClient.GetMemberLeaderPriority("pd-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(priority).To(Equal(5))
+	priority, err = pdClient.GetMemberLeaderPriority("pd-0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(priority).To(BeZero())
+
+	g.Expect(pdClient.SetMemberLeaderPriority("pd-2", 5)).NotTo(Succeed())
+	_, err = pdClient.GetMemberLeaderPriority("pd-2")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestUpdateConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("POST"), "check method")
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s", configPrefix)), "check url")
+		body := map[string]interface{}{}
+		g.Expect(readJSON(request.Body, &body)).To(Succeed())
+		if _, ok := body["schedule.unknown"]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("config item unknown not found"))
+		}
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	g.Expect(pdClient.UpdateConfig(map[string]interface{}{"schedule.leader-schedule-limit": 4})).To(Succeed())
+	g.Expect(pdClient.UpdateConfig(map[string]interface{}{"schedule.unknown": 1})).NotTo(Succeed())
+}
+
 func TestDeleteMember(t *testing.T) {
 	g := NewGomegaWithT(t)
 	name := "testMember"
@@ -27,6 +27,15 @@ type KeyValue struct {
 	Value []byte
 }
 
+// EtcdMember is a member of the etcd cluster embedded in PD
+type EtcdMember struct {
+	ID uint64
+	// Name is empty if the member is added but not started yet
+	Name      string
+	PeerURLs  []string
+	IsLearner bool
+}
+
 type PDEtcdClient interface {
 	// Get the specific kvs.
 	// if prefix is true will return all kvs with the specified key as prefix
@@ -37,6 +46,13 @@ type PDEtcdClient interface {
 	PutTTLKey(key, value string, ttl int64) error
 	// DeleteKey will delete key from the target pd etcd cluster
 	DeleteKey(key string) error
+	// ListMembers lists the members of the target pd etcd cluster
+	ListMembers() ([]*EtcdMember, error)
+	// AddLearner adds a non-voting learner member with the peer url to the target pd etcd cluster
+	AddLearner(peerURL string) (*EtcdMember, error)
+	// PromoteMember promotes the learner member to a voting member, which fails if the learner
+	// is not in sync with the leader yet
+	PromoteMember(id uint64) error
 	// Close will close the etcd connection
 	Close() error
 }
@@ -121,3 +137,44 @@ func (c *pdEtcdClient) DeleteKey(key string) error {
 	}
 	return nil
 }
+
+func (c *pdEtcdClient) ListMembers() ([]*EtcdMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	resp, err := c.etcdClient.MemberList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]*EtcdMember, 0, len(resp.Members))
+	for _, m := range resp.Members {
+		members = append(members, &EtcdMember{
+			ID:        m.ID,
+			Name:      m.Name,
+			PeerURLs:  m.PeerURLs,
+			IsLearner: m.IsLearner,
+		})
+	}
+	return members, nil
+}
+
+func (c *pdEtcdClient) AddLearner(peerURL string) (*EtcdMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	resp, err := c.etcdClient.MemberAddAsLearner(ctx, []string{peerURL})
+	if err != nil {
+		return nil, err
+	}
+	return &EtcdMember{
+		ID:        resp.Member.ID,
+		Name:      resp.Member.Name,
+		PeerURLs:  resp.Member.PeerURLs,
+		IsLearner: resp.Member.IsLearner,
+	}, nil
+}
+
+func (c *pdEtcdClient) PromoteMember(id uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	_, err := c.etcdClient.MemberPromote(ctx, id)
+	return err
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/pingcap/tidb-operator/pkg/tracing"
 	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
 	"k8s.io/klog/v2"
 )
@@ -47,6 +48,11 @@ type pdMSClient struct {
 
 // NewPDMSClient returns a new PDClient
 func NewPDMSClient(serviceName, url string, timeout time.Duration, tlsConfig *tls.Config) *pdMSClient {
+	return newPDMSClient(serviceName, url, timeout, tlsConfig, tracing.Transport)
+}
+
+// newPDMSClient returns a new pdMSClient whose transport is wrapped by traceTransport
+func newPDMSClient(serviceName, url string, timeout time.Duration, tlsConfig *tls.Config, traceTransport func(http.RoundTripper) http.RoundTripper) *pdMSClient {
 	var disableKeepalive bool
 	if tlsConfig != nil {
 		disableKeepalive = true
@@ -56,7 +62,7 @@ func NewPDMSClient(serviceName, url string, timeout time.Duration, tlsConfig *tl
 		url:         url,
 		httpClient: &http.Client{
 			Timeout:   timeout,
-			Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: disableKeepalive},
+			Transport: traceTransport(wrapTransport(url, &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: disableKeepalive})),
 		},
 	}
 }
@@ -0,0 +1,277 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+const learnerRoleName = "Learner"
+
+// CheckStoresDeletable checks the regions with a replica on the stores in PD, and returns the reason why
+// the pods of the stores can not be deleted at the same time now, "" is returned if they can be deleted.
+// The replicas on the stores to be deleted are regarded as unavailable, the down and pending replicas of
+// a region on the other stores are regarded as unavailable too.
+//
+// A healthy region with at least 3 voters keeps its quorum when one of its replicas is unavailable, so
+// only the unhealthy regions checked by PD are checked if one store is deleted and every region requires
+// at least 3 voters. Otherwise, all the regions of the stores are listed and checked.
+func CheckStoresDeletable(pdClient PDClient, level v1alpha1.TiKVDeletionSafetyLevel, storeIDs ...uint64) string {
+	if level == "" || level == v1alpha1.TiKVDeletionSafetyNone || len(storeIDs) == 0 {
+		return ""
+	}
+
+	// the regions missing voters are checked by PD against the placement rules
+	missPeer := map[uint64]struct{}{}
+	if level == v1alpha1.TiKVDeletionSafetyStrict {
+		regionsInfo, err := pdClient.GetRegionsByCheck(RegionCheckMissPeer)
+		if err != nil {
+			return fmt.Sprintf("can't get the regions missing peers from PD: %s", err)
+		}
+		for _, region := range regionsInfo.Regions {
+			if region != nil {
+				missPeer[region.ID] = struct{}{}
+			}
+		}
+	}
+
+	deleting := map[uint64]struct{}{}
+	for _, id := range storeIDs {
+		deleting[id] = struct{}{}
+	}
+	regions, err := listRegionsToCheck(pdClient, storeIDs)
+	if err != nil {
+		return err.Error()
+	}
+	for _, region := range regions {
+		if reason := checkRegionDeletable(region, deleting, level, missPeer); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// listRegionsToCheck returns the regions with a replica on the stores which may lose the quorum if the
+// stores are deleted.
+func listRegionsToCheck(pdClient PDClient, storeIDs []uint64) ([]*RegionInfo, error) {
+	var candidates []*RegionInfo
+	onlyUnhealthy := false
+	if len(storeIDs) == 1 {
+		minVoters, err := getMinRequiredVoters(pdClient)
+		if err != nil {
+			return nil, fmt.Errorf("can't access PD: %s", err)
+		}
+		if minVoters >= 3 {
+			onlyUnhealthy = true
+			for _, state := range []RegionCheckState{RegionCheckMissPeer, RegionCheckDownPeer, RegionCheckPendingPeer} {
+				regionsInfo, err := pdClient.GetRegionsByCheck(state)
+				if err != nil {
+					return nil, fmt.Errorf("can't get the regions in state %s from PD: %s", state, err)
+				}
+				candidates = append(candidates, regionsInfo.Regions...)
+			}
+		}
+	}
+	if !onlyUnhealthy {
+		for _, id := range storeIDs {
+			regionsInfo, err := pdClient.GetStoreRegions(id)
+			if err != nil {
+				return nil, fmt.Errorf("can't get the regions of store %d from PD: %s", id, err)
+			}
+			candidates = append(candidates, regionsInfo.Regions...)
+		}
+	}
+
+	stores := map[uint64]struct{}{}
+	for _, id := range storeIDs {
+		stores[id] = struct{}{}
+	}
+	checked := map[uint64]struct{}{}
+	var regions []*RegionInfo
+	for _, region := range candidates {
+		if region == nil {
+			continue
+		}
+		if _, ok := checked[region.ID]; ok {
+			continue
+		}
+		checked[region.ID] = struct{}{}
+		for _, peer := range region.Peers {
+			if _, ok := stores[peer.StoreID]; ok {
+				regions = append(regions, region)
+				break
+			}
+		}
+	}
+	return regions, nil
+}
+
+// getMinRequiredVoters returns the least voters a region requires, which is max-replicas, or the least
+// voters required by the placement rules of the same key range if the placement rules are enabled.
+func getMinRequiredVoters(pdClient PDClient) (int, error) {
+	config, err := pdClient.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	if config.Replication == nil {
+		return 0, nil
+	}
+	if config.Replication.EnablePlacementRules == nil || !*config.Replication.EnablePlacementRules {
+		if config.Replication.MaxReplicas == nil {
+			return 0, nil
+		}
+		return int(*config.Replication.MaxReplicas), nil
+	}
+
+	rules, err := pdClient.GetPlacementRules()
+	if err != nil {
+		return 0, err
+	}
+	// the rules of the same group and key range add up, the sums are the lower bound of the voters
+	// of the regions in the ranges
+	voters := map[string]int{}
+	for _, rule := range rules {
+		key := rule.GroupID + "/" + rule.StartKeyHex + "/" + rule.EndKeyHex
+		if _, ok := voters[key]; !ok {
+			voters[key] = 0
+		}
+		if rule.Role != "learner" && !rule.IsWitness {
+			voters[key] += rule.Count
+		}
+	}
+	minVoters := 0
+	for _, count := range voters {
+		if count > 0 && (minVoters == 0 || count < minVoters) {
+			minVoters = count
+		}
+	}
+	return minVoters, nil
+}
+
+func checkRegionDeletable(region *RegionInfo, deleting map[uint64]struct{}, level v1alpha1.TiKVDeletionSafetyLevel, missPeer map[uint64]struct{}) string {
+	unhealthy := map[uint64]struct{}{}
+	for _, peer := range region.PendingPeers {
+		unhealthy[peer.ID] = struct{}{}
+	}
+	for _, stats := range region.DownPeers {
+		unhealthy[stats.Peer.ID] = struct{}{}
+	}
+
+	voters, available := 0, 0
+	for _, peer := range region.Peers {
+		_, isDeleting := deleting[peer.StoreID]
+		_, isUnhealthy := unhealthy[peer.ID]
+		if level == v1alpha1.TiKVDeletionSafetyStrict && isUnhealthy && !isDeleting {
+			return fmt.Sprintf("region %d has a down or pending peer %d on store %d", region.ID, peer.ID, peer.StoreID)
+		}
+		if peer.IsLearner || peer.RoleName == learnerRoleName {
+			continue
+		}
+		voters++
+		if !isDeleting && !isUnhealthy {
+			available++
+		}
+	}
+
+	if _, ok := missPeer[region.ID]; ok && level == v1alpha1.TiKVDeletionSafetyStrict {
+		return fmt.Sprintf("region %d has %d voters, less than required by the placement rules", region.ID, voters)
+	}
+	if available <= voters/2 {
+		return fmt.Sprintf("region %d would have %d of %d voters available, less than a quorum", region.ID, available, voters)
+	}
+	return ""
+}
+
+// maxRegionIDsAtRisk is the max number of the ids of the regions at risk listed in a RegionSafetyReport
+const maxRegionIDsAtRisk = 20
+
+// RegionSafetyReport summarizes the regions with a replica on the stores, and the ones of them that would
+// lose the quorum if the stores are unavailable at the same time.
+type RegionSafetyReport struct {
+	MaxReplicas            int
+	Regions                int
+	Leaders                int
+	UnderReplicatedRegions int
+	RegionsAtRisk          int
+	// RegionIDsAtRisk are the ids of the first regions at risk in ascending order
+	RegionIDsAtRisk []uint64
+}
+
+// GetRegionSafetyReport returns the safety report of the regions with a replica on the stores in PD,
+// the replicas on the stores are regarded as unavailable in the same way as CheckStoresDeletable.
+func GetRegionSafetyReport(pdClient PDClient, storeIDs ...uint64) (*RegionSafetyReport, error) {
+	report := &RegionSafetyReport{}
+	config, err := pdClient.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.Replication != nil && config.Replication.MaxReplicas != nil {
+		report.MaxReplicas = int(*config.Replication.MaxReplicas)
+	}
+	// the regions missing voters are checked by PD against the placement rules
+	missPeer := map[uint64]struct{}{}
+	missPeerInfo, err := pdClient.GetRegionsByCheck(RegionCheckMissPeer)
+	if err != nil {
+		return nil, fmt.Errorf("can't get the regions missing peers from PD: %s", err)
+	}
+	for _, region := range missPeerInfo.Regions {
+		if region != nil {
+			missPeer[region.ID] = struct{}{}
+		}
+	}
+
+	unavailable := map[uint64]struct{}{}
+	for _, id := range storeIDs {
+		unavailable[id] = struct{}{}
+	}
+	checked := map[uint64]struct{}{}
+	var atRisk []uint64
+	for _, id := range storeIDs {
+		regionsInfo, err := pdClient.GetStoreRegions(id)
+		if err != nil {
+			return nil, fmt.Errorf("can't get the regions of store %d from PD: %s", id, err)
+		}
+		for _, region := range regionsInfo.Regions {
+			if region == nil {
+				continue
+			}
+			if _, ok := checked[region.ID]; ok {
+				continue
+			}
+			checked[region.ID] = struct{}{}
+
+			report.Regions++
+			if _, ok := unavailable[region.Leader.StoreID]; ok {
+				report.Leaders++
+			}
+			if _, ok := missPeer[region.ID]; ok {
+				report.UnderReplicatedRegions++
+			}
+			if checkRegionDeletable(region, unavailable, v1alpha1.TiKVDeletionSafetyQuorum, nil) != "" {
+				atRisk = append(atRisk, region.ID)
+			}
+		}
+	}
+
+	sort.Slice(atRisk, func(i, j int) bool { return atRisk[i] < atRisk[j] })
+	report.RegionsAtRisk = len(atRisk)
+	if len(atRisk) > maxRegionIDsAtRisk {
+		atRisk = atRisk[:maxRegionIDsAtRisk]
+	}
+	report.RegionIDsAtRisk = atRisk
+	return report, nil
+}
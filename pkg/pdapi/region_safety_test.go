@@ -0,0 +1,267 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/utils/pointer"
+)
+
+// newFakeRegionsPDClient returns a fake PD client serving the regions of the stores, the regions
+// in the unhealthy states are checked against max-replicas 3
+func newFakeRegionsPDClient(regions map[uint64][]*RegionInfo, config *PDConfigFromAPI, scans *int) *FakePDClient {
+	pdClient := NewFakePDClient()
+	pdClient.AddReaction(GetConfigActionType, func(action *Action) (interface{}, error) {
+		return config, nil
+	})
+	pdClient.AddReaction(GetStoreRegionsActionType, func(action *Action) (interface{}, error) {
+		*scans++
+		rs, ok := regions[action.ID]
+		if !ok {
+			return nil, fmt.Errorf("store %d not found", action.ID)
+		}
+		return &RegionsInfo{Count: len(rs), Regions: rs}, nil
+	})
+	pdClient.AddReaction(GetRegionsByCheckActionType, func(action *Action) (interface{}, error) {
+		checked := map[uint64]struct{}{}
+		result := &RegionsInfo{}
+		for _, rs := range regions {
+			for _, region := range rs {
+				if _, ok := checked[region.ID]; ok {
+					continue
+				}
+				checked[region.ID] = struct{}{}
+				var match bool
+				switch RegionCheckState(action.Name) {
+				case RegionCheckMissPeer:
+					match = len(region.Peers) < 3
+				case RegionCheckDownPeer:
+					match = len(region.DownPeers) > 0
+				case RegionCheckPendingPeer:
+					match = len(region.PendingPeers) > 0
+				}
+				if match {
+					result.Regions = append(result.Regions, region)
+				}
+			}
+		}
+		result.Count = len(result.Regions)
+		return result, nil
+	})
+	return pdClient
+}
+
+func TestCheckStoresDeletable(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	peers := func(storeIDs ...uint64) []RegionPeer {
+		var ps []RegionPeer
+		for _, id := range storeIDs {
+			ps = append(ps, RegionPeer{ID: id * 10, StoreID: id})
+		}
+		return ps
+	}
+	healthy := &RegionInfo{ID: 1, Peers: peers(1, 2, 3)}
+	pending := &RegionInfo{ID: 2, Peers: peers(1, 2, 3), PendingPeers: peers(3)}
+	down := &RegionInfo{ID: 3, Peers: peers(1, 2, 3), DownPeers: []RegionPeerStats{{Peer: peers(2)[0]}}}
+	missing := &RegionInfo{ID: 4, Peers: peers(1, 2)}
+	learner := &RegionInfo{ID: 5, Peers: append(peers(1, 2, 3), RegionPeer{ID: 40, StoreID: 4, RoleName: learnerRoleName})}
+	single := &RegionInfo{ID: 6, Peers: peers(1)}
+
+	tests := []struct {
+		name        string
+		level       v1alpha1.TiKVDeletionSafetyLevel
+		regions     map[uint64][]*RegionInfo
+		stores      []uint64
+		maxReplicas uint64
+		rules       []*PlacementRule
+		ok          bool
+		scanned     bool
+	}{
+		{
+			name:    "regions are not checked",
+			level:   v1alpha1.TiKVDeletionSafetyNone,
+			regions: map[uint64][]*RegionInfo{1: {down}},
+			stores:  []uint64{1},
+			ok:      true,
+		},
+		{
+			name:    "quorum is kept",
+			level:   v1alpha1.TiKVDeletionSafetyQuorum,
+			regions: map[uint64][]*RegionInfo{3: {healthy, pending}},
+			stores:  []uint64{3},
+			ok:      true,
+		},
+		{
+			name:    "quorum is lost with a down peer",
+			level:   v1alpha1.TiKVDeletionSafetyQuorum,
+			regions: map[uint64][]*RegionInfo{1: {healthy, down}},
+			stores:  []uint64{1},
+			ok:      false,
+		},
+		{
+			name:    "quorum is lost by deleting two stores",
+			level:   v1alpha1.TiKVDeletionSafetyQuorum,
+			regions: map[uint64][]*RegionInfo{1: {healthy}, 2: {healthy}},
+			stores:  []uint64{1, 2},
+			ok:      false,
+			scanned: true,
+		},
+		{
+			name:    "learners are not counted",
+			level:   v1alpha1.TiKVDeletionSafetyQuorum,
+			regions: map[uint64][]*RegionInfo{1: {learner}},
+			stores:  []uint64{1},
+			ok:      true,
+		},
+		{
+			name:        "quorum is lost with less than 3 replicas",
+			level:       v1alpha1.TiKVDeletionSafetyQuorum,
+			regions:     map[uint64][]*RegionInfo{1: {single}},
+			stores:      []uint64{1},
+			maxReplicas: 1,
+			ok:          false,
+			scanned:     true,
+		},
+		{
+			name:    "quorum is lost with the placement rules requiring one voter",
+			level:   v1alpha1.TiKVDeletionSafetyQuorum,
+			regions: map[uint64][]*RegionInfo{1: {healthy, single}},
+			stores:  []uint64{1},
+			rules: []*PlacementRule{
+				{GroupID: "pd", ID: "default", Role: "voter", Count: 3},
+				{GroupID: "pd", ID: "default-learner", Role: "learner", Count: 1},
+				{GroupID: "TiDB_DDL_70", ID: "table_rule_70_0", StartKeyHex: "7480", EndKeyHex: "7481", Role: "leader", Count: 1},
+			},
+			ok:      false,
+			scanned: true,
+		},
+		{
+			name:    "quorum is kept with the placement rules requiring three voters",
+			level:   v1alpha1.TiKVDeletionSafetyQuorum,
+			regions: map[uint64][]*RegionInfo{1: {healthy}},
+			stores:  []uint64{1},
+			rules: []*PlacementRule{
+				{GroupID: "pd", ID: "default", Role: "voter", Count: 3},
+				{GroupID: "TiDB_DDL_70", ID: "table_rule_70_0", StartKeyHex: "7480", EndKeyHex: "7481", Role: "leader", Count: 1},
+				{GroupID: "TiDB_DDL_70", ID: "table_rule_70_1", StartKeyHex: "7480", EndKeyHex: "7481", Role: "follower", Count: 2},
+			},
+			ok: true,
+		},
+		{
+			name:    "strict with a pending peer",
+			level:   v1alpha1.TiKVDeletionSafetyStrict,
+			regions: map[uint64][]*RegionInfo{3: {healthy, pending}},
+			stores:  []uint64{1},
+			ok:      false,
+		},
+		{
+			name:    "strict with the down peer on the store to delete",
+			level:   v1alpha1.TiKVDeletionSafetyStrict,
+			regions: map[uint64][]*RegionInfo{2: {healthy, down}},
+			stores:  []uint64{2},
+			ok:      true,
+		},
+		{
+			name:    "strict with a missing replica",
+			level:   v1alpha1.TiKVDeletionSafetyStrict,
+			regions: map[uint64][]*RegionInfo{1: {missing}},
+			stores:  []uint64{1},
+			ok:      false,
+		},
+		{
+			name:    "regions can not be got",
+			level:   v1alpha1.TiKVDeletionSafetyQuorum,
+			regions: map[uint64][]*RegionInfo{},
+			stores:  []uint64{1, 2},
+			ok:      false,
+			scanned: true,
+		},
+	}
+
+	for _, tt := range tests {
+		config := &PDConfigFromAPI{Replication: &PDReplicationConfig{MaxReplicas: pointer.Uint64Ptr(3)}}
+		if tt.maxReplicas > 0 {
+			config.Replication.MaxReplicas = pointer.Uint64Ptr(tt.maxReplicas)
+		}
+		scans := 0
+		pdClient := newFakeRegionsPDClient(tt.regions, config, &scans)
+		if tt.rules != nil {
+			config.Replication.EnablePlacementRules = pointer.BoolPtr(true)
+			rules := tt.rules
+			pdClient.AddReaction(GetPlacementRulesActionType, func(action *Action) (interface{}, error) {
+				return rules, nil
+			})
+		}
+		reason := CheckStoresDeletable(pdClient, tt.level, tt.stores...)
+		g.Expect(reason == "").To(Equal(tt.ok), "%s: %s", tt.name, reason)
+		g.Expect(scans > 0).To(Equal(tt.scanned), tt.name)
+	}
+}
+
+func TestGetRegionSafetyReport(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	peers := func(storeIDs ...uint64) []RegionPeer {
+		var ps []RegionPeer
+		for _, id := range storeIDs {
+			ps = append(ps, RegionPeer{ID: id * 10, StoreID: id})
+		}
+		return ps
+	}
+	regions := map[uint64][]*RegionInfo{
+		1: {
+			{ID: 1, Peers: peers(1, 2, 3), Leader: peers(2)[0]},
+			{ID: 2, Peers: peers(1, 2, 3), Leader: peers(1)[0], DownPeers: []RegionPeerStats{{Peer: peers(3)[0]}}},
+			{ID: 3, Peers: peers(1, 3), Leader: peers(3)[0]},
+		},
+		2: {
+			{ID: 1, Peers: peers(1, 2, 3), Leader: peers(2)[0]},
+			{ID: 4, Peers: peers(2, 3, 4), Leader: peers(4)[0]},
+		},
+	}
+	scans := 0
+	pdClient := newFakeRegionsPDClient(regions, &PDConfigFromAPI{Replication: &PDReplicationConfig{MaxReplicas: pointer.Uint64Ptr(3)}}, &scans)
+
+	// only the blocked store is unavailable
+	report, err := GetRegionSafetyReport(pdClient, 1)
+	g.Expect(err).To(Succeed())
+	g.Expect(report).To(Equal(&RegionSafetyReport{
+		MaxReplicas:            3,
+		Regions:                3,
+		Leaders:                1,
+		UnderReplicatedRegions: 1,
+		RegionsAtRisk:          2,
+		RegionIDsAtRisk:        []uint64{2, 3},
+	}))
+
+	// the next store to upgrade is unavailable too
+	report, err = GetRegionSafetyReport(pdClient, 1, 2)
+	g.Expect(err).To(Succeed())
+	g.Expect(report).To(Equal(&RegionSafetyReport{
+		MaxReplicas:            3,
+		Regions:                4,
+		Leaders:                2,
+		UnderReplicatedRegions: 1,
+		RegionsAtRisk:          3,
+		RegionIDsAtRisk:        []uint64{1, 2, 3},
+	}))
+
+	_, err = GetRegionSafetyReport(pdClient, 3)
+	g.Expect(err).To(HaveOccurred())
+}
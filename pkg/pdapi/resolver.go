@@ -0,0 +1,107 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+// Resolver resolves the host of a service of a tidb cluster, which may be deployed in another
+// Kubernetes cluster when the tidb cluster is deployed across Kubernetes clusters.
+type Resolver interface {
+	// ServiceHost returns the host of the service in the namespace, the cluster domain is the one of
+	// the Kubernetes cluster the service is deployed in, which is empty for the local cluster.
+	ServiceHost(namespace Namespace, service, clusterDomain string) string
+}
+
+// NewResolver returns the resolver configured by the spec, the cluster domain convention is used if it's nil
+func NewResolver(spec *v1alpha1.AcrossK8sResolver) Resolver {
+	if spec == nil {
+		return clusterDomainResolver{}
+	}
+	switch spec.Type {
+	case v1alpha1.AcrossK8sResolverExternalDNS:
+		if spec.Domain != "" {
+			return externalDNSResolver{domain: spec.Domain}
+		}
+	case v1alpha1.AcrossK8sResolverClusterSet:
+		domain := spec.Domain
+		if domain == "" {
+			domain = v1alpha1.DefaultClusterSetDomain
+		}
+		return clusterSetResolver{domain: domain}
+	case v1alpha1.AcrossK8sResolverClusterMesh:
+		return clusterMeshResolver{}
+	case v1alpha1.AcrossK8sResolverStatic:
+		return staticResolver{endpoints: spec.Endpoints}
+	}
+	return clusterDomainResolver{}
+}
+
+// clusterDomainResolver resolves a service as `<service>.<namespace>.svc.<clusterDomain>`
+type clusterDomainResolver struct{}
+
+func (clusterDomainResolver) ServiceHost(namespace Namespace, service, clusterDomain string) string {
+	if len(namespace) == 0 {
+		return service
+	}
+	if len(clusterDomain) == 0 {
+		return fmt.Sprintf("%s.%s", service, string(namespace))
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", service, string(namespace), clusterDomain)
+}
+
+// externalDNSResolver resolves a service as `<service>.<namespace>.<domain>`
+type externalDNSResolver struct {
+	domain string
+}
+
+func (r externalDNSResolver) ServiceHost(namespace Namespace, service, clusterDomain string) string {
+	if len(namespace) == 0 {
+		return fmt.Sprintf("%s.%s", service, r.domain)
+	}
+	return fmt.Sprintf("%s.%s.%s", service, string(namespace), r.domain)
+}
+
+// clusterSetResolver resolves a service as `<service>.<namespace>.svc.<domain>`
+type clusterSetResolver struct {
+	domain string
+}
+
+func (r clusterSetResolver) ServiceHost(namespace Namespace, service, clusterDomain string) string {
+	return clusterDomainResolver{}.ServiceHost(namespace, service, r.domain)
+}
+
+// clusterMeshResolver resolves a service as `<service>.<namespace>` by the local DNS
+type clusterMeshResolver struct{}
+
+func (clusterMeshResolver) ServiceHost(namespace Namespace, service, clusterDomain string) string {
+	return clusterDomainResolver{}.ServiceHost(namespace, service, "")
+}
+
+// staticResolver resolves a service by the static endpoints keyed by the host resolved by the
+// cluster domain convention, the hosts not mapped are resolved by the convention
+type staticResolver struct {
+	endpoints map[string]string
+}
+
+func (r staticResolver) ServiceHost(namespace Namespace, service, clusterDomain string) string {
+	host := clusterDomainResolver{}.ServiceHost(namespace, service, clusterDomain)
+	if endpoint, ok := r.endpoints[host]; ok && endpoint != "" {
+		return endpoint
+	}
+	return host
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func TestResolver(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		name          string
+		spec          *v1alpha1.AcrossK8sResolver
+		namespace     Namespace
+		clusterDomain string
+		expect        string
+	}{
+		{
+			name:   "default without namespace",
+			expect: "basic-pd",
+		},
+		{
+			name:      "default without cluster domain",
+			namespace: "ns",
+			expect:    "basic-pd.ns",
+		},
+		{
+			name:          "default",
+			namespace:     "ns",
+			clusterDomain: "cluster.local",
+			expect:        "basic-pd.ns.svc.cluster.local",
+		},
+		{
+			name:          "cluster domain",
+			spec:          &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterDomain},
+			namespace:     "ns",
+			clusterDomain: "cluster-1.com",
+			expect:        "basic-pd.ns.svc.cluster-1.com",
+		},
+		{
+			name:          "external dns",
+			spec:          &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverExternalDNS, Domain: "db.example.com"},
+			namespace:     "ns",
+			clusterDomain: "cluster-1.com",
+			expect:        "basic-pd.ns.db.example.com",
+		},
+		{
+			name:          "external dns without domain",
+			spec:          &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverExternalDNS},
+			namespace:     "ns",
+			clusterDomain: "cluster-1.com",
+			expect:        "basic-pd.ns.svc.cluster-1.com",
+		},
+		{
+			name:          "cluster set",
+			spec:          &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterSet},
+			namespace:     "ns",
+			clusterDomain: "cluster-1.com",
+			expect:        "basic-pd.ns.svc.clusterset.local",
+		},
+		{
+			name:          "cluster set with domain",
+			spec:          &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterSet, Domain: "mcs.local"},
+			namespace:     "ns",
+			clusterDomain: "cluster-1.com",
+			expect:        "basic-pd.ns.svc.mcs.local",
+		},
+		{
+			name:          "cluster mesh",
+			spec:          &v1alpha1.AcrossK8sResolver{Type: v1alpha1.AcrossK8sResolverClusterMesh},
+			namespace:     "ns",
+			clusterDomain: "cluster-1.com",
+			expect:        "basic-pd.ns",
+		},
+		{
+			name: "static",
+			spec: &v1alpha1.AcrossK8sResolver{
+				Type:      v1alpha1.AcrossK8sResolverStatic,
+				Endpoints: map[string]string{"basic-pd.ns.svc.cluster-1.com": "10.0.0.1"},
+			},
+			namespace:     "ns",
+			clusterDomain: "cluster-1.com",
+			expect:        "10.0.0.1",
+		},
+		{
+			name: "static not mapped",
+			spec: &v1alpha1.AcrossK8sResolver{
+				Type:      v1alpha1.AcrossK8sResolverStatic,
+				Endpoints: map[string]string{"basic-pd.ns.svc.cluster-1.com": "10.0.0.1"},
+			},
+			namespace:     "ns",
+			clusterDomain: "cluster-2.com",
+			expect:        "basic-pd.ns.svc.cluster-2.com",
+		},
+	}
+
+	for _, c := range cases {
+		t.Logf("test case: %s", c.name)
+		host := NewResolver(c.spec).ServiceHost(c.namespace, "basic-pd", c.clusterDomain)
+		g.Expect(host).To(Equal(c.expect), c.name)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+// +k8s:deepcopy-gen=false
+type BackupScheduleStrategy struct{}
+
+func (BackupScheduleStrategy) NewObject() runtime.Object {
+	return &v1alpha1.BackupSchedule{}
+}
+
+func (BackupScheduleStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {}
+
+func (BackupScheduleStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {}
+
+func (BackupScheduleStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	if bs, ok := castBackupSchedule(obj); ok {
+		return validateBackupSchedule(bs)
+	}
+	return field.ErrorList{}
+}
+
+func (BackupScheduleStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	if bs, ok := castBackupSchedule(obj); ok {
+		return validateBackupSchedule(bs)
+	}
+	return field.ErrorList{}
+}
+
+func validateBackupSchedule(bs *v1alpha1.BackupSchedule) field.ErrorList {
+	allErrs := validation.ValidateBackupSchedule(bs)
+	specPath := field.NewPath("spec")
+	allErrs = append(allErrs, validateCronSchedule(specPath.Child("schedule"), bs.Spec.Schedule)...)
+	if cs := bs.Spec.CompactSchedule; cs != nil {
+		allErrs = append(allErrs, validateCronSchedule(specPath.Child("compactSchedule", "schedule"), cs.Schedule)...)
+	}
+	return allErrs
+}
+
+// validateCronSchedule validates the standard cron expression, the empty expression is reported
+// by the validation of the API
+func validateCronSchedule(fldPath *field.Path, schedule string) field.ErrorList {
+	if schedule == "" {
+		return nil
+	}
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, schedule, err.Error())}
+	}
+	return nil
+}
+
+func castBackupSchedule(obj runtime.Object) (*v1alpha1.BackupSchedule, bool) {
+	bs, ok := obj.(*v1alpha1.BackupSchedule)
+	if !ok {
+		klog.Errorf("Object %T is not v1alpah1.BackupSchedule, cannot processed by BackupScheduleStrategy", obj)
+		return nil, false
+	}
+	return bs, true
+}
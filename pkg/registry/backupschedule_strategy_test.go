@@ -0,0 +1,88 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestBackupScheduleStrategyValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	compact := func(schedule string) v1alpha1.BackupScheduleSpec {
+		return v1alpha1.BackupScheduleSpec{
+			Schedule:              "0 2 * * *",
+			LogBackupTemplate:     &v1alpha1.BackupSpec{Mode: v1alpha1.BackupModeLog},
+			CompactBackupTemplate: &v1alpha1.CompactSpec{},
+			CompactSchedule:       &v1alpha1.CompactSchedule{Schedule: schedule},
+		}
+	}
+	tests := []struct {
+		name   string
+		spec   v1alpha1.BackupScheduleSpec
+		fields []string
+	}{
+		{
+			name: "valid schedule",
+			spec: v1alpha1.BackupScheduleSpec{Schedule: "0 2 * * *", TimeZone: "Asia/Shanghai"},
+		},
+		{
+			name:   "invalid schedule",
+			spec:   v1alpha1.BackupScheduleSpec{Schedule: "0 25 * * *"},
+			fields: []string{"spec.schedule"},
+		},
+		{
+			name: "valid compact schedule",
+			spec: compact("0 */6 * * *"),
+		},
+		{
+			name:   "invalid compact schedule",
+			spec:   compact("every 6 hours"),
+			fields: []string{"spec.compactSchedule.schedule"},
+		},
+		{
+			name:   "empty compact schedule",
+			spec:   compact(""),
+			fields: []string{"spec.compactSchedule.schedule"},
+		},
+	}
+
+	errorFields := func(errs field.ErrorList) []string {
+		var fields []string
+		for _, err := range errs {
+			fields = append(fields, err.Field)
+		}
+		return fields
+	}
+	strategy := BackupScheduleStrategy{}
+	for _, tt := range tests {
+		bs := &v1alpha1.BackupSchedule{Spec: tt.spec}
+		for _, errs := range [][]string{
+			errorFields(strategy.Validate(ctx, bs)),
+			errorFields(strategy.ValidateUpdate(ctx, bs, bs.DeepCopy())),
+		} {
+			if len(tt.fields) == 0 {
+				g.Expect(errs).To(BeEmpty(), tt.name)
+			} else {
+				g.Expect(errs).To(Equal(tt.fields), tt.name)
+			}
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 )
 
 // CreateUpdateStrategy is a sub set of the RESTCreateUpdateStrategy interface of kube-apiserver, which abstracts the
@@ -75,3 +76,37 @@ type CreateUpdateStrategy interface {
 	// ValidateUpdate validates an update request for existing resource
 	ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList
 }
+
+// AuditAnnotator is implemented by the strategies that record the audit annotations of the admitted
+// update requests, the annotations are added to the audit events of the requests by kube-apiserver.
+type AuditAnnotator interface {
+	// AuditAnnotations returns the audit annotations of an update request
+	AuditAnnotations(ctx context.Context, obj, old runtime.Object) map[string]string
+}
+
+type kubeClientKey struct{}
+
+type userNameKey struct{}
+
+// WithKubeClient returns a copy of ctx carrying the kube client, the strategies use it to validate
+// the resources referenced by an object if it's present.
+func WithKubeClient(ctx context.Context, kubeCli kubernetes.Interface) context.Context {
+	return context.WithValue(ctx, kubeClientKey{}, kubeCli)
+}
+
+// KubeClientFrom returns the kube client carried by ctx
+func KubeClientFrom(ctx context.Context) (kubernetes.Interface, bool) {
+	kubeCli, ok := ctx.Value(kubeClientKey{}).(kubernetes.Interface)
+	return kubeCli, ok
+}
+
+// WithUserName returns a copy of ctx carrying the name of the user who sent the request
+func WithUserName(ctx context.Context, userName string) context.Context {
+	return context.WithValue(ctx, userNameKey{}, userName)
+}
+
+// UserNameFrom returns the name of the user carried by ctx
+func UserNameFrom(ctx context.Context) (string, bool) {
+	userName, ok := ctx.Value(userNameKey{}).(string)
+	return userName, ok
+}
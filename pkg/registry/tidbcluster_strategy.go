@@ -16,11 +16,15 @@ package registry
 import (
 	"context"
 
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
@@ -38,12 +42,58 @@ func (TidbClusterStrategy) PrepareForCreate(ctx context.Context, obj runtime.Obj
 }
 
 func (TidbClusterStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
-	// no op to not affect the cluster managed by old versions of the helm chart
+	// no defaulting to not affect the cluster managed by old versions of the helm chart,
+	// only the user who forces the upgrade to continue is recorded
+	oldTc, oldOk := castTidbCluster(old)
+	tc, ok := castTidbCluster(obj)
+	if !ok || !oldOk {
+		return
+	}
+	if !isForceContinueUpgradeChanged(tc, oldTc) {
+		// the recorded user can't be changed by the users
+		if by, ok := oldTc.Annotations[label.AnnForceContinueUpgradeByKey]; ok {
+			if tc.Annotations == nil {
+				tc.Annotations = map[string]string{}
+			}
+			tc.Annotations[label.AnnForceContinueUpgradeByKey] = by
+		} else {
+			delete(tc.Annotations, label.AnnForceContinueUpgradeByKey)
+		}
+		return
+	}
+	if tc.Annotations[label.AnnForceContinueUpgradeKey] == "" {
+		delete(tc.Annotations, label.AnnForceContinueUpgradeByKey)
+		return
+	}
+	userName, _ := UserNameFrom(ctx)
+	tc.Annotations[label.AnnForceContinueUpgradeByKey] = userName
+}
+
+// AuditAnnotations records who forces the upgrade blocked on an unhealthy member to continue
+func (TidbClusterStrategy) AuditAnnotations(ctx context.Context, obj, old runtime.Object) map[string]string {
+	oldTc, oldOk := castTidbCluster(old)
+	tc, ok := castTidbCluster(obj)
+	if !ok || !oldOk || !isForceContinueUpgradeChanged(tc, oldTc) {
+		return nil
+	}
+	userName, _ := UserNameFrom(ctx)
+	return map[string]string{
+		"force-continue-upgrade":    tc.Annotations[label.AnnForceContinueUpgradeKey],
+		"force-continue-upgrade-by": userName,
+	}
+}
+
+func isForceContinueUpgradeChanged(tc, old *v1alpha1.TidbCluster) bool {
+	return tc.Annotations[label.AnnForceContinueUpgradeKey] != old.Annotations[label.AnnForceContinueUpgradeKey]
 }
 
 func (TidbClusterStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
 	if tc, ok := castTidbCluster(obj); ok {
-		return validation.ValidateCreateTidbCluster(tc)
+		allErrs := validation.ValidateCreateTidbCluster(tc)
+		if kubeCli, ok := KubeClientFrom(ctx); ok {
+			allErrs = append(allErrs, validateTidbClusterResources(ctx, kubeCli, tc, nil)...)
+		}
+		return allErrs
 	}
 	return field.ErrorList{}
 }
@@ -52,11 +102,98 @@ func (TidbClusterStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.
 	oldTc, oldOk := castTidbCluster(old)
 	tc, ok := castTidbCluster(obj)
 	if ok && oldOk {
-		return validation.ValidateUpdateTidbCluster(oldTc, tc)
+		allErrs := validation.ValidateUpdateTidbCluster(oldTc, tc)
+		if kubeCli, ok := KubeClientFrom(ctx); ok {
+			allErrs = append(allErrs, validateTidbClusterResources(ctx, kubeCli, tc, oldTc)...)
+		}
+		return allErrs
 	}
 	return field.ErrorList{}
 }
 
+// validateTidbClusterResources validates that the storage classes referenced by the TidbCluster exist.
+// For an update, only the newly referenced resources are validated, so that a running cluster can
+// still be updated after the resources it used are deleted. The TLS secrets are not validated, they
+// may be issued by cert-manager or applied by GitOps after the TidbCluster is created.
+func validateTidbClusterResources(ctx context.Context, kubeCli kubernetes.Interface, tc, old *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	oldStorageClasses := map[string]struct{}{}
+	if old != nil {
+		for _, ref := range tidbClusterStorageClasses(old) {
+			oldStorageClasses[ref.name] = struct{}{}
+		}
+	}
+	for _, ref := range tidbClusterStorageClasses(tc) {
+		if _, ok := oldStorageClasses[ref.name]; ok {
+			continue
+		}
+		_, err := kubeCli.StorageV1().StorageClasses().Get(ctx, ref.name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(ref.path, ref.name))
+		} else if err != nil {
+			// don't block the request because of a transient error
+			klog.Warningf("get storage class %s for tc %s/%s failed, err: %v", ref.name, tc.Namespace, tc.Name, err)
+		}
+	}
+	return allErrs
+}
+
+type storageClassRef struct {
+	name string
+	path *field.Path
+}
+
+// tidbClusterStorageClasses returns the storage classes referenced by the TidbCluster
+func tidbClusterStorageClasses(tc *v1alpha1.TidbCluster) []storageClassRef {
+	var refs []storageClassRef
+	add := func(name *string, path *field.Path) {
+		if name != nil && *name != "" {
+			refs = append(refs, storageClassRef{name: *name, path: path})
+		}
+	}
+	addVolumes := func(volumes []v1alpha1.StorageVolume, path *field.Path) {
+		for i := range volumes {
+			add(volumes[i].StorageClassName, path.Index(i).Child("storageClassName"))
+		}
+	}
+
+	spec := field.NewPath("spec")
+	if pd := tc.Spec.PD; pd != nil {
+		add(pd.StorageClassName, spec.Child("pd", "storageClassName"))
+		addVolumes(pd.StorageVolumes, spec.Child("pd", "storageVolumes"))
+	}
+	for i, pdms := range tc.Spec.PDMS {
+		add(pdms.StorageClassName, spec.Child("pdms").Index(i).Child("storageClassName"))
+		addVolumes(pdms.StorageVolumes, spec.Child("pdms").Index(i).Child("storageVolumes"))
+	}
+	if tikv := tc.Spec.TiKV; tikv != nil {
+		add(tikv.StorageClassName, spec.Child("tikv", "storageClassName"))
+		addVolumes(tikv.StorageVolumes, spec.Child("tikv", "storageVolumes"))
+	}
+	if tidb := tc.Spec.TiDB; tidb != nil {
+		add(tidb.StorageClassName, spec.Child("tidb", "storageClassName"))
+		addVolumes(tidb.StorageVolumes, spec.Child("tidb", "storageVolumes"))
+	}
+	if tiflash := tc.Spec.TiFlash; tiflash != nil {
+		for i := range tiflash.StorageClaims {
+			add(tiflash.StorageClaims[i].StorageClassName, spec.Child("tiflash", "storageClaims").Index(i).Child("storageClassName"))
+		}
+	}
+	if ticdc := tc.Spec.TiCDC; ticdc != nil {
+		add(ticdc.StorageClassName, spec.Child("ticdc", "storageClassName"))
+		addVolumes(ticdc.StorageVolumes, spec.Child("ticdc", "storageVolumes"))
+	}
+	if tiproxy := tc.Spec.TiProxy; tiproxy != nil {
+		add(tiproxy.StorageClassName, spec.Child("tiproxy", "storageClassName"))
+		addVolumes(tiproxy.StorageVolumes, spec.Child("tiproxy", "storageVolumes"))
+	}
+	if pump := tc.Spec.Pump; pump != nil {
+		add(pump.StorageClassName, spec.Child("pump", "storageClassName"))
+	}
+	return refs
+}
+
 func castTidbCluster(obj runtime.Object) (*v1alpha1.TidbCluster, bool) {
 	tc, ok := obj.(*v1alpha1.TidbCluster)
 	if !ok {
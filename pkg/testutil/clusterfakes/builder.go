@@ -0,0 +1,243 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clusterfakes provides the fakes to test the automation built on the libraries of the operator
+// against the behavior of the operator, without a Kubernetes cluster or a running TiDB cluster:
+//   - TidbClusterBuilder builds the TidbClusters in realistic states
+//   - FakePD answers the PD API calls of the operator with an in-memory PD cluster
+//   - Harness runs the reconciles of the tidbcluster controller in a fake environment
+package clusterfakes
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultVersion is the version of the TidbClusters built by TidbClusterBuilder
+const DefaultVersion = "v7.5.0"
+
+// TidbClusterBuilder builds TidbClusters for tests
+type TidbClusterBuilder struct {
+	tc      *v1alpha1.TidbCluster
+	running bool
+}
+
+// NewTidbCluster returns a TidbClusterBuilder of a TidbCluster without any component
+func NewTidbCluster(namespace, name string) *TidbClusterBuilder {
+	return &TidbClusterBuilder{
+		tc: &v1alpha1.TidbCluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       controller.ControllerKind.Kind,
+				APIVersion: controller.ControllerKind.GroupVersion().String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				UID:       types.UID(fmt.Sprintf("%s-%s", namespace, name)),
+			},
+			Spec: v1alpha1.TidbClusterSpec{
+				Version: DefaultVersion,
+			},
+		},
+	}
+}
+
+// WithVersion sets the version of the cluster
+func (b *TidbClusterBuilder) WithVersion(version string) *TidbClusterBuilder {
+	b.tc.Spec.Version = version
+	return b
+}
+
+// WithPD adds PD with the replicas to the cluster
+func (b *TidbClusterBuilder) WithPD(replicas int32) *TidbClusterBuilder {
+	b.tc.Spec.PD = &v1alpha1.PDSpec{
+		Replicas:             replicas,
+		BaseImage:            "pingcap/pd",
+		Config:               v1alpha1.NewPDConfig(),
+		ResourceRequirements: storageRequest("10Gi"),
+	}
+	return b
+}
+
+// WithTiKV adds TiKV with the replicas to the cluster
+func (b *TidbClusterBuilder) WithTiKV(replicas int32) *TidbClusterBuilder {
+	b.tc.Spec.TiKV = &v1alpha1.TiKVSpec{
+		Replicas:             replicas,
+		BaseImage:            "pingcap/tikv",
+		Config:               v1alpha1.NewTiKVConfig(),
+		ResourceRequirements: storageRequest("100Gi"),
+	}
+	return b
+}
+
+// WithTiDB adds TiDB with the replicas to the cluster
+func (b *TidbClusterBuilder) WithTiDB(replicas int32) *TidbClusterBuilder {
+	b.tc.Spec.TiDB = &v1alpha1.TiDBSpec{
+		Replicas:  replicas,
+		BaseImage: "pingcap/tidb",
+		Config:    v1alpha1.NewTiDBConfig(),
+	}
+	return b
+}
+
+// WithAnnotation sets the annotation of the cluster
+func (b *TidbClusterBuilder) WithAnnotation(key, value string) *TidbClusterBuilder {
+	if b.tc.Annotations == nil {
+		b.tc.Annotations = map[string]string{}
+	}
+	b.tc.Annotations[key] = value
+	return b
+}
+
+// Modify calls the function to make the changes not covered by the builder
+func (b *TidbClusterBuilder) Modify(fn func(tc *v1alpha1.TidbCluster)) *TidbClusterBuilder {
+	fn(b.tc)
+	return b
+}
+
+// Running fills the status as the cluster is running normally, all the members are healthy,
+// the first PD member is the leader and the statefulsets are rolled out
+func (b *TidbClusterBuilder) Running() *TidbClusterBuilder {
+	b.running = true
+	return b
+}
+
+// Build returns the TidbCluster
+func (b *TidbClusterBuilder) Build() *v1alpha1.TidbCluster {
+	tc := b.tc.DeepCopy()
+	if !b.running {
+		return tc
+	}
+
+	now := metav1.Now()
+	if tc.Spec.PD != nil {
+		tc.Status.PD.Synced = true
+		tc.Status.PD.Phase = v1alpha1.NormalPhase
+		tc.Status.PD.Image = tc.PDImage()
+		tc.Status.PD.StatefulSet = rolledOutStatus(tc.Spec.PD.Replicas)
+		tc.Status.PD.Members = map[string]v1alpha1.PDMember{}
+		for i := int32(0); i < tc.Spec.PD.Replicas; i++ {
+			name := podName(tc, v1alpha1.PDMemberType, i)
+			tc.Status.PD.Members[name] = v1alpha1.PDMember{
+				Name:               name,
+				ID:                 fmt.Sprintf("%d", memberID(i)),
+				ClientURL:          pdClientURL(tc, name),
+				Health:             true,
+				LastTransitionTime: now,
+			}
+		}
+		tc.Status.PD.Leader = tc.Status.PD.Members[podName(tc, v1alpha1.PDMemberType, 0)]
+		tc.Status.ClusterID = fmt.Sprintf("%d", fakeClusterID)
+	}
+	if tc.Spec.TiKV != nil {
+		tc.Status.TiKV.Synced = true
+		tc.Status.TiKV.BootStrapped = true
+		tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+		tc.Status.TiKV.Image = tc.TiKVImage()
+		tc.Status.TiKV.StatefulSet = rolledOutStatus(tc.Spec.TiKV.Replicas)
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+		for i := int32(0); i < tc.Spec.TiKV.Replicas; i++ {
+			name := podName(tc, v1alpha1.TiKVMemberType, i)
+			id := fmt.Sprintf("%d", storeID(i))
+			tc.Status.TiKV.Stores[id] = v1alpha1.TiKVStore{
+				ID:                 id,
+				PodName:            name,
+				IP:                 tikvHost(tc, name),
+				State:              v1alpha1.TiKVStateUp,
+				LastTransitionTime: now,
+			}
+		}
+	}
+	if tc.Spec.TiDB != nil {
+		tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+		tc.Status.TiDB.Image = tc.TiDBImage()
+		tc.Status.TiDB.StatefulSet = rolledOutStatus(tc.Spec.TiDB.Replicas)
+		tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{}
+		for i := int32(0); i < tc.Spec.TiDB.Replicas; i++ {
+			name := podName(tc, v1alpha1.TiDBMemberType, i)
+			tc.Status.TiDB.Members[name] = v1alpha1.TiDBMember{
+				Name:               name,
+				Health:             true,
+				LastTransitionTime: now,
+			}
+		}
+	}
+	tc.Status.Conditions = []v1alpha1.TidbClusterCondition{
+		{
+			Type:               v1alpha1.TidbClusterReady,
+			Status:             corev1.ConditionTrue,
+			LastUpdateTime:     now,
+			LastTransitionTime: now,
+			Reason:             "Ready",
+			Message:            "TiDB cluster is fully up and running",
+		},
+	}
+	return tc
+}
+
+const (
+	fakeClusterID = 6789
+	pdClientPort  = 2379
+	tikvPort      = 20160
+)
+
+func storageRequest(size string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse(size),
+		},
+	}
+}
+
+func rolledOutStatus(replicas int32) *apps.StatefulSetStatus {
+	return &apps.StatefulSetStatus{
+		Replicas:        replicas,
+		ReadyReplicas:   replicas,
+		CurrentReplicas: replicas,
+		UpdatedReplicas: replicas,
+	}
+}
+
+// memberID and storeID return the ids of the PD members and TiKV stores of the ordinals
+func memberID(ordinal int32) uint64 {
+	return uint64(1000 + ordinal)
+}
+
+func storeID(ordinal int32) uint64 {
+	return uint64(1 + ordinal)
+}
+
+func podName(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, ordinal int32) string {
+	return fmt.Sprintf("%s-%s-%d", tc.Name, memberType, ordinal)
+}
+
+// pdClientURL returns the client url of the PD member of the pod, which matches the PD members of the cluster
+func pdClientURL(tc *v1alpha1.TidbCluster, podName string) string {
+	return fmt.Sprintf("http://%s.%s.%s.svc:%d", podName, controller.PDPeerMemberName(tc.Name), tc.Namespace, pdClientPort)
+}
+
+func tikvHost(tc *v1alpha1.TidbCluster, podName string) string {
+	return fmt.Sprintf("%s.%s.%s.svc", podName, controller.TiKVPeerMemberName(tc.Name), tc.Namespace)
+}
+
+// tikvAddress returns the address of the TiKV store of the pod, which matches the stores of the cluster
+func tikvAddress(tc *v1alpha1.TidbCluster, podName string) string {
+	return fmt.Sprintf("%s:%d", tikvHost(tc, podName), tikvPort)
+}
@@ -0,0 +1,367 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfakes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/controller/tidbcluster"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/tikvapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeNodeName is the node all the pods are scheduled to
+const fakeNodeName = "fake-node"
+
+// Harness runs the reconciles of the tidbcluster controller for a TidbCluster with the fake dependencies.
+// After each reconcile, the statefulsets are rolled out at once like the statefulset controller does,
+// the pods are created scheduled and ready, the PD members and TiKV stores of the new pods join the FakePD,
+// and the TiDB servers are healthy. The other components are reconciled but not simulated.
+type Harness struct {
+	// Deps is the fake dependencies used by the controller, the objects created by the
+	// controller can be got from the listers
+	Deps *controller.Dependencies
+	// PD is the PD cluster of the TidbCluster
+	PD *FakePD
+
+	control   tidbcluster.ControlInterface
+	namespace string
+	name      string
+}
+
+// NewHarness returns a Harness for the TidbCluster, the members and stores in the status
+// of the TidbCluster are added to the FakePD
+func NewHarness(tc *v1alpha1.TidbCluster) (*Harness, error) {
+	deps := controller.NewFakeDependencies()
+	if err := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(tc.DeepCopy()); err != nil {
+		return nil, err
+	}
+	if _, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc.DeepCopy(), metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fakeNodeName}}
+	if err := deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer().Add(node); err != nil {
+		return nil, err
+	}
+
+	pdControl, ok := deps.PDControl.(*pdapi.FakePDControl)
+	if !ok {
+		return nil, fmt.Errorf("unexpected pd control %T", deps.PDControl)
+	}
+	pd := NewFakePDForTidbCluster(tc)
+	pd.Install(pdControl, tc)
+
+	return &Harness{
+		Deps:      deps,
+		PD:        pd,
+		control:   tidbcluster.NewTidbClusterControl(deps),
+		namespace: tc.Namespace,
+		name:      tc.Name,
+	}, nil
+}
+
+// TidbCluster returns the current TidbCluster
+func (h *Harness) TidbCluster() (*v1alpha1.TidbCluster, error) {
+	tc, err := h.Deps.TiDBClusterLister.TidbClusters(h.namespace).Get(h.name)
+	if err != nil {
+		return nil, err
+	}
+	return tc.DeepCopy(), nil
+}
+
+// Update updates the TidbCluster with the function, like a user edits the TidbCluster
+func (h *Harness) Update(fn func(tc *v1alpha1.TidbCluster)) error {
+	tc, err := h.TidbCluster()
+	if err != nil {
+		return err
+	}
+	fn(tc)
+	return h.Deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Update(tc)
+}
+
+// Reconcile runs one reconcile of the TidbCluster and simulates Kubernetes and the TiDB cluster afterwards,
+// the error of the reconcile is returned, the controller requeues the TidbCluster on errors
+func (h *Harness) Reconcile() error {
+	tc, err := h.TidbCluster()
+	if err != nil {
+		return err
+	}
+	reconcileErr := h.control.UpdateTidbCluster(tc)
+	if err := h.simulate(); err != nil {
+		return fmt.Errorf("simulate tidbcluster %s/%s failed: %v", h.namespace, h.name, err)
+	}
+	return reconcileErr
+}
+
+// Run runs n reconciles and returns the error of the last one
+func (h *Harness) Run(n int) error {
+	var err error
+	for i := 0; i < n; i++ {
+		err = h.Reconcile()
+	}
+	return err
+}
+
+// RunUntil runs the reconciles until the condition of the TidbCluster is met,
+// an error is returned if it's not met after max reconciles
+func (h *Harness) RunUntil(max int, condition func(tc *v1alpha1.TidbCluster) bool) error {
+	var err error
+	for i := 0; i < max; i++ {
+		err = h.Reconcile()
+		tc, getErr := h.TidbCluster()
+		if getErr != nil {
+			return getErr
+		}
+		if condition(tc) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the condition is not met after %d reconciles, last error: %v", max, err)
+}
+
+// simulate rolls out the statefulsets of the TidbCluster and syncs the FakePD with the pods
+func (h *Harness) simulate() error {
+	selector, err := label.New().Instance(h.name).Selector()
+	if err != nil {
+		return err
+	}
+	sets, err := h.Deps.StatefulSetLister.StatefulSets(h.namespace).List(selector)
+	if err != nil {
+		return err
+	}
+	tidbHealth := map[string]bool{}
+	for _, set := range sets {
+		pods, err := h.rollOut(set.DeepCopy())
+		if err != nil {
+			return err
+		}
+		if label.Label(set.Labels).IsTiDB() {
+			for _, pod := range pods {
+				tidbHealth[pod.Name] = true
+			}
+		}
+	}
+	if tidbControl, ok := h.Deps.TiDBControl.(*controller.FakeTiDBControl); ok {
+		tidbControl.SetHealth(tidbHealth)
+	}
+	return nil
+}
+
+// rollOut creates the pods of the statefulset at the update revision from the partition,
+// deletes the pods out of the replicas and updates the status, the pods are returned
+func (h *Harness) rollOut(set *apps.StatefulSet) ([]*corev1.Pod, error) {
+	revision, err := templateRevision(set)
+	if err != nil {
+		return nil, err
+	}
+	replicas := int32(1)
+	if set.Spec.Replicas != nil {
+		replicas = *set.Spec.Replicas
+	}
+	partition := int32(0)
+	if set.Spec.UpdateStrategy.RollingUpdate != nil && set.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		partition = *set.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+
+	podIndexer := h.Deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := h.Deps.PodLister.Pods(set.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range existing {
+		if ordinal, ok := podOrdinal(set.Name, pod.Name); !ok || ordinal >= replicas {
+			if err := podIndexer.Delete(pod); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var pods []*corev1.Pod
+	updated := int32(0)
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		name := fmt.Sprintf("%s-%d", set.Name, ordinal)
+		pod, err := h.Deps.PodLister.Pods(set.Namespace).Get(name)
+		switch {
+		case errors.IsNotFound(err):
+			pod, err = h.createPod(set, name, revision)
+			if err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		case ordinal >= partition && pod.Labels[apps.ControllerRevisionHashLabelKey] != revision:
+			// the pod is recreated at the update revision with the same volumes
+			pod = newPod(set, name, revision)
+			if err := podIndexer.Update(pod); err != nil {
+				return nil, err
+			}
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] == revision {
+			updated++
+		}
+		pods = append(pods, pod)
+	}
+
+	set.Status.ObservedGeneration = set.Generation
+	set.Status.Replicas = replicas
+	set.Status.ReadyReplicas = replicas
+	set.Status.AvailableReplicas = replicas
+	set.Status.CurrentReplicas = replicas
+	set.Status.UpdatedReplicas = updated
+	set.Status.UpdateRevision = revision
+	if updated == replicas || set.Status.CurrentRevision == "" {
+		set.Status.CurrentRevision = revision
+	}
+	if err := h.Deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Update(set); err != nil {
+		return nil, err
+	}
+	return pods, nil
+}
+
+// createPod creates the pod with its PVCs, the PD member or TiKV store of the pod joins the FakePD
+func (h *Harness) createPod(set *apps.StatefulSet, name, revision string) (*corev1.Pod, error) {
+	pod := newPod(set, name, revision)
+	pvcIndexer := h.Deps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	for _, vct := range set.Spec.VolumeClaimTemplates {
+		pvcName := fmt.Sprintf("%s-%s", vct.Name, name)
+		if _, err := h.Deps.PVCLister.PersistentVolumeClaims(set.Namespace).Get(pvcName); err == nil {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: set.Namespace,
+				Name:      pvcName,
+				Labels:    pod.Labels,
+			},
+			Spec: vct.Spec,
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:    corev1.ClaimBound,
+				Capacity: vct.Spec.Resources.Requests,
+			},
+		}
+		if err := pvcIndexer.Add(pvc); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.Deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer().Add(pod); err != nil {
+		return nil, err
+	}
+
+	tc := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Namespace: h.namespace, Name: h.name}}
+	l := label.Label(pod.Labels)
+	switch {
+	case l.IsPD():
+		h.PD.AddMember(name, pdClientURL(tc, name))
+	case l.IsTiKV():
+		id := h.PD.AddStore(tikvAddress(tc, name))
+		tikvClient := tikvapi.NewFakeTiKVClient()
+		tikvClient.AddReaction(tikvapi.GetLeaderCountActionType, func(_ *tikvapi.Action) (interface{}, error) {
+			return h.PD.StoreLeaderCount(id), nil
+		})
+		if tikvControl, ok := h.Deps.TiKVControl.(*tikvapi.FakeTiKVControl); ok {
+			tikvControl.SetTiKVPodClient(h.namespace, h.name, name, tikvClient)
+		}
+	}
+	return pod, nil
+}
+
+// newPod returns the pod of the statefulset at the revision, which is scheduled and ready
+func newPod(set *apps.StatefulSet, name, revision string) *corev1.Pod {
+	template := set.Spec.Template.DeepCopy()
+	podLabels := map[string]string{}
+	for k, v := range template.Labels {
+		podLabels[k] = v
+	}
+	podLabels[apps.ControllerRevisionHashLabelKey] = revision
+	podLabels[apps.StatefulSetPodNameLabel] = name
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       set.Namespace,
+			Name:            name,
+			Labels:          podLabels,
+			Annotations:     template.Annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(set, apps.SchemeGroupVersion.WithKind("StatefulSet"))},
+		},
+		Spec: template.Spec,
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "127.0.0.1",
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	pod.Spec.NodeName = fakeNodeName
+	pod.Spec.Hostname = name
+	for _, vct := range set.Spec.VolumeClaimTemplates {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: vct.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: fmt.Sprintf("%s-%s", vct.Name, name),
+				},
+			},
+		})
+	}
+	for _, c := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  c.Name,
+			Image: c.Image,
+			Ready: true,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+// templateRevision returns the revision of the pod template of the statefulset
+func templateRevision(set *apps.StatefulSet) (string, error) {
+	data, err := json.Marshal(set.Spec.Template)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New32a()
+	hasher.Write(data)
+	return fmt.Sprintf("%s-%x", set.Name, hasher.Sum32()), nil
+}
+
+// podOrdinal returns the ordinal of the pod of the statefulset
+func podOrdinal(setName, podName string) (int32, bool) {
+	if !strings.HasPrefix(podName, setName+"-") {
+		return 0, false
+	}
+	ordinal, err := strconv.ParseInt(strings.TrimPrefix(podName, setName+"-"), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(ordinal), true
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfakes
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestHarnessCreateCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := NewTidbCluster("ns", "basic").WithPD(3).WithTiKV(3).WithTiDB(2).Build()
+	h, err := NewHarness(tc)
+	g.Expect(err).To(Succeed())
+
+	g.Expect(h.RunUntil(20, func(tc *v1alpha1.TidbCluster) bool {
+		return tc.Status.TiDB.StatefulSet != nil && tc.Status.TiDB.StatefulSet.ReadyReplicas == 2
+	})).To(Succeed())
+
+	for name, replicas := range map[string]int32{
+		controller.PDMemberName(tc.Name):   3,
+		controller.TiKVMemberName(tc.Name): 3,
+		controller.TiDBMemberName(tc.Name): 2,
+	} {
+		set, err := h.Deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(name)
+		g.Expect(err).To(Succeed())
+		g.Expect(*set.Spec.Replicas).To(Equal(replicas))
+	}
+	g.Expect(h.PD.Members()).To(ConsistOf("basic-pd-0", "basic-pd-1", "basic-pd-2"))
+	g.Expect(h.PD.Leader()).To(Equal("basic-pd-0"))
+
+	pods, err := h.Deps.PodLister.Pods(tc.Namespace).List(labels.SelectorFromSet(label.New().Instance(tc.Name).TiKV()))
+	g.Expect(err).To(Succeed())
+	g.Expect(pods).To(HaveLen(3))
+}
+
+func TestHarnessRunningCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := NewTidbCluster("ns", "basic").WithPD(3).WithTiKV(3).Running().Build()
+	h, err := NewHarness(tc)
+	g.Expect(err).To(Succeed())
+	g.Expect(h.PD.Members()).To(HaveLen(3))
+	g.Expect(h.PD.StoreState(storeID(0))).To(Equal(v1alpha1.TiKVStateUp))
+
+	// the stores of the running cluster are not added again
+	g.Expect(h.Run(3)).To(Succeed())
+	g.Expect(h.PD.AddStore(tikvAddress(tc, "basic-tikv-0"))).To(Equal(storeID(0)))
+
+	// scale in TiKV, the store is deleted and becomes tombstone
+	g.Expect(h.Update(func(tc *v1alpha1.TidbCluster) {
+		tc.Spec.TiKV.Replicas = 2
+	})).To(Succeed())
+	h.Run(5)
+	g.Expect(h.PD.StoreState(storeID(2))).To(Equal(v1alpha1.TiKVStateTombstone))
+}
+
+func TestFakePD(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pd := NewFakePD()
+	pd.AddMember("pd-0", "http://pd-0:2379")
+	pd.AddMember("pd-1", "http://pd-1:2379")
+	g.Expect(pd.Leader()).To(Equal("pd-0"))
+
+	// the leader fails over to the healthy member
+	pd.SetMemberHealth("pd-0", false)
+	g.Expect(pd.Leader()).To(Equal("pd-1"))
+
+	health, err := pd.Client.GetHealth()
+	g.Expect(err).To(Succeed())
+	g.Expect(health.Healths).To(HaveLen(2))
+
+	id := pd.AddStore("tikv-0:20160")
+	g.Expect(pd.AddStore("tikv-0:20160")).To(Equal(id))
+	g.Expect(pd.Client.DeleteStore(id)).To(Succeed())
+	g.Expect(pd.StoreState(id)).To(Equal(v1alpha1.TiKVStateTombstone))
+	g.Expect(pd.AddStore("tikv-0:20160")).NotTo(Equal(id))
+}
@@ -0,0 +1,403 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfakes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+)
+
+// FakePD is an in-memory PD cluster which answers the PD API calls of the operator through a FakePDClient.
+// The stores deleted are tombstone at once, as the data are migrated immediately, and the leader
+// of the stores under eviction is evicted at once.
+type FakePD struct {
+	lock sync.Mutex
+
+	// Client is the FakePDClient with the reactions backed by the FakePD
+	Client    *pdapi.FakePDClient
+	pdControl *pdapi.FakePDControl
+
+	members     map[string]*pdapi.MemberHealth
+	priorities  map[string]int
+	leader      string
+	nextMember  uint64
+	stores      map[uint64]*pdapi.StoreInfo
+	evicting    map[uint64]bool
+	nextStoreID uint64
+}
+
+// NewFakePD returns a FakePD without any member or store
+func NewFakePD() *FakePD {
+	pd := &FakePD{
+		Client:      pdapi.NewFakePDClient(),
+		members:     map[string]*pdapi.MemberHealth{},
+		priorities:  map[string]int{},
+		nextMember:  memberID(0),
+		stores:      map[uint64]*pdapi.StoreInfo{},
+		evicting:    map[uint64]bool{},
+		nextStoreID: storeID(0),
+	}
+	pd.addReactions()
+	return pd
+}
+
+// NewFakePDForTidbCluster returns a FakePD with the PD members, the leader and the TiKV stores
+// in the status of the TidbCluster, see TidbClusterBuilder.Running
+func NewFakePDForTidbCluster(tc *v1alpha1.TidbCluster) *FakePD {
+	pd := NewFakePD()
+	for _, m := range tc.Status.PD.Members {
+		id, err := strconv.ParseUint(m.ID, 10, 64)
+		if err != nil {
+			id = pd.nextMember
+		}
+		pd.members[m.Name] = &pdapi.MemberHealth{
+			Name:       m.Name,
+			MemberID:   id,
+			ClientUrls: []string{m.ClientURL},
+			Health:     m.Health,
+		}
+		if id >= pd.nextMember {
+			pd.nextMember = id + 1
+		}
+	}
+	pd.leader = tc.Status.PD.Leader.Name
+	for _, s := range tc.Status.TiKV.Stores {
+		id, err := strconv.ParseUint(s.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		pd.putStore(id, tikvAddress(tc, s.PodName), s.State)
+	}
+	return pd
+}
+
+// Install makes the FakePDControl return the client of the FakePD for the cluster and its members
+func (pd *FakePD) Install(pdControl *pdapi.FakePDControl, tc *v1alpha1.TidbCluster) {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	pd.pdControl = pdControl
+	pdControl.SetPDClient(pdapi.Namespace(tc.Namespace), tc.Name, pd.Client)
+	for name := range pd.members {
+		pdControl.SetPDClientWithAddress(name, pd.Client)
+	}
+}
+
+// AddMember adds a healthy PD member, the first member becomes the leader
+func (pd *FakePD) AddMember(name, clientURL string) {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	if _, ok := pd.members[name]; ok {
+		return
+	}
+	pd.members[name] = &pdapi.MemberHealth{
+		Name:       name,
+		MemberID:   pd.nextMember,
+		ClientUrls: []string{clientURL},
+		Health:     true,
+	}
+	pd.nextMember++
+	if pd.leader == "" {
+		pd.leader = name
+	}
+	if pd.pdControl != nil {
+		pd.pdControl.SetPDClientWithAddress(name, pd.Client)
+	}
+}
+
+// SetMemberHealth sets the health of the PD member, the leadership of an unhealthy
+// leader is taken over by another healthy member
+func (pd *FakePD) SetMemberHealth(name string, health bool) {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	m, ok := pd.members[name]
+	if !ok {
+		return
+	}
+	m.Health = health
+	if !health && pd.leader == name {
+		pd.leader = ""
+		for _, n := range pd.memberNames() {
+			if pd.members[n].Health {
+				pd.leader = n
+				break
+			}
+		}
+	}
+}
+
+// SetLeader sets the leader of the PD cluster
+func (pd *FakePD) SetLeader(name string) {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+	pd.leader = name
+}
+
+// Leader returns the name of the leader, empty if there is no leader
+func (pd *FakePD) Leader() string {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+	return pd.leader
+}
+
+// Members returns the names of the members
+func (pd *FakePD) Members() []string {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+	return pd.memberNames()
+}
+
+// AddStore adds an Up store with the address and returns its id,
+// the id of the existing store is returned if the address has a store not tombstone
+func (pd *FakePD) AddStore(address string) uint64 {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	for id, s := range pd.stores {
+		if s.Store.Address == address && s.Store.StateName != v1alpha1.TiKVStateTombstone {
+			return id
+		}
+	}
+	id := pd.nextStoreID
+	pd.putStore(id, address, v1alpha1.TiKVStateUp)
+	return id
+}
+
+func (pd *FakePD) putStore(id uint64, address, state string) {
+	pd.stores[id] = &pdapi.StoreInfo{
+		Store: &pdapi.MetaStore{
+			Store: &metapb.Store{
+				Id:      id,
+				Address: address,
+			},
+			StateName: state,
+		},
+		Status: &pdapi.StoreStatus{},
+	}
+	if id >= pd.nextStoreID {
+		pd.nextStoreID = id + 1
+	}
+}
+
+// SetStoreState sets the state of the store, e.g. v1alpha1.TiKVStateDown
+func (pd *FakePD) SetStoreState(id uint64, state string) {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	if s, ok := pd.stores[id]; ok {
+		s.Store.StateName = state
+	}
+}
+
+// SetStoreLeaderCount sets the leader count of the store
+func (pd *FakePD) SetStoreLeaderCount(id uint64, count int) {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	if s, ok := pd.stores[id]; ok {
+		s.Status.LeaderCount = count
+	}
+}
+
+// StoreLeaderCount returns the leader count of the store
+func (pd *FakePD) StoreLeaderCount(id uint64) int {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	if s, ok := pd.stores[id]; ok {
+		return s.Status.LeaderCount
+	}
+	return 0
+}
+
+// StoreState returns the state of the store, empty if the store does not exist
+func (pd *FakePD) StoreState(id uint64) string {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+
+	if s, ok := pd.stores[id]; ok {
+		return s.Store.StateName
+	}
+	return ""
+}
+
+func (pd *FakePD) memberNames() []string {
+	names := make([]string, 0, len(pd.members))
+	for name := range pd.members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (pd *FakePD) pdpbMember(name string) *pdpb.Member {
+	m := pd.members[name]
+	return &pdpb.Member{
+		Name:       m.Name,
+		MemberId:   m.MemberID,
+		ClientUrls: m.ClientUrls,
+	}
+}
+
+// storesInfo returns the stores which are tombstone or not
+func (pd *FakePD) storesInfo(tombstone bool) *pdapi.StoresInfo {
+	ids := make([]uint64, 0, len(pd.stores))
+	for id, s := range pd.stores {
+		if (s.Store.StateName == v1alpha1.TiKVStateTombstone) == tombstone {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	info := &pdapi.StoresInfo{}
+	for _, id := range ids {
+		info.Stores = append(info.Stores, pd.stores[id])
+	}
+	info.Count = len(info.Stores)
+	return info
+}
+
+func (pd *FakePD) addReactions() {
+	// locked runs the reaction with the lock held
+	locked := func(reaction pdapi.Reaction) pdapi.Reaction {
+		return func(action *pdapi.Action) (interface{}, error) {
+			pd.lock.Lock()
+			defer pd.lock.Unlock()
+			return reaction(action)
+		}
+	}
+
+	pd.Client.AddReaction(pdapi.GetHealthActionType, locked(func(_ *pdapi.Action) (interface{}, error) {
+		info := &pdapi.HealthInfo{}
+		for _, name := range pd.memberNames() {
+			info.Healths = append(info.Healths, *pd.members[name])
+		}
+		return info, nil
+	}))
+	pd.Client.AddReaction(pdapi.GetMembersActionType, locked(func(_ *pdapi.Action) (interface{}, error) {
+		info := &pdapi.MembersInfo{}
+		for _, name := range pd.memberNames() {
+			info.Members = append(info.Members, pd.pdpbMember(name))
+		}
+		if pd.leader != "" {
+			info.Leader = pd.pdpbMember(pd.leader)
+			info.EtcdLeader = info.Leader
+		}
+		return info, nil
+	}))
+	pd.Client.AddReaction(pdapi.GetClusterActionType, func(_ *pdapi.Action) (interface{}, error) {
+		return &metapb.Cluster{Id: fakeClusterID}, nil
+	})
+	pd.Client.AddReaction(pdapi.GetConfigActionType, func(_ *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{
+			Schedule:    &pdapi.PDScheduleConfig{},
+			Replication: &pdapi.PDReplicationConfig{},
+		}, nil
+	})
+	pd.Client.AddReaction(pdapi.GetPDLeaderActionType, locked(func(_ *pdapi.Action) (interface{}, error) {
+		if pd.leader == "" {
+			// the result is asserted to be a member even if there is an error
+			return (*pdpb.Member)(nil), fmt.Errorf("no leader")
+		}
+		return pd.pdpbMember(pd.leader), nil
+	}))
+	pd.Client.AddReaction(pdapi.TransferPDLeaderActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		m, ok := pd.members[action.Name]
+		if !ok || !m.Health {
+			return nil, fmt.Errorf("member %s is not healthy", action.Name)
+		}
+		pd.leader = action.Name
+		return nil, nil
+	}))
+	pd.Client.AddReaction(pdapi.DeleteMemberActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		delete(pd.members, action.Name)
+		if pd.leader == action.Name {
+			pd.leader = ""
+		}
+		return nil, nil
+	}))
+	pd.Client.AddReaction(pdapi.DeleteMemberByIDActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		for name, m := range pd.members {
+			if m.MemberID == action.ID {
+				delete(pd.members, name)
+				if pd.leader == name {
+					pd.leader = ""
+				}
+			}
+		}
+		return nil, nil
+	}))
+	pd.Client.AddReaction(pdapi.GetMemberLeaderPriorityActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		return pd.priorities[action.Name], nil
+	}))
+	pd.Client.AddReaction(pdapi.SetMemberLeaderPriorityActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		pd.priorities[action.Name] = action.Priority
+		return nil, nil
+	}))
+	pd.Client.AddReaction(pdapi.GetReadyActionType, func(_ *pdapi.Action) (interface{}, error) {
+		return true, nil
+	})
+	pd.Client.AddReaction(pdapi.GetStoresActionType, locked(func(_ *pdapi.Action) (interface{}, error) {
+		return pd.storesInfo(false), nil
+	}))
+	pd.Client.AddReaction(pdapi.GetTombStoneStoresActionType, locked(func(_ *pdapi.Action) (interface{}, error) {
+		return pd.storesInfo(true), nil
+	}))
+	pd.Client.AddReaction(pdapi.GetStoreActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		s, ok := pd.stores[action.ID]
+		if !ok {
+			return nil, fmt.Errorf("store %d not found", action.ID)
+		}
+		return s, nil
+	}))
+	pd.Client.AddReaction(pdapi.DeleteStoreActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		s, ok := pd.stores[action.ID]
+		if !ok {
+			return nil, fmt.Errorf("store %d not found", action.ID)
+		}
+		s.Store.StateName = v1alpha1.TiKVStateTombstone
+		return nil, nil
+	}))
+	pd.Client.AddReaction(pdapi.SetStoreLabelsActionType, func(_ *pdapi.Action) (interface{}, error) {
+		return true, nil
+	})
+	pd.Client.AddReaction(pdapi.BeginEvictLeaderActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		pd.evicting[action.ID] = true
+		if s, ok := pd.stores[action.ID]; ok {
+			s.Status.LeaderCount = 0
+		}
+		return nil, nil
+	}))
+	pd.Client.AddReaction(pdapi.EndEvictLeaderActionType, locked(func(action *pdapi.Action) (interface{}, error) {
+		delete(pd.evicting, action.ID)
+		return nil, nil
+	}))
+	// GetEvictLeaderSchedulersForStores shares the action type with GetEvictLeaderSchedulers and the operator
+	// only calls the former, the schedulers of all the stores are returned as the store ids are not passed
+	pd.Client.AddReaction(pdapi.GetEvictLeaderSchedulersActionType, locked(func(_ *pdapi.Action) (interface{}, error) {
+		schedulers := map[uint64]string{}
+		for id := range pd.evicting {
+			schedulers[id] = fmt.Sprintf("evict-leader-scheduler-%d", id)
+		}
+		return schedulers, nil
+	}))
+}
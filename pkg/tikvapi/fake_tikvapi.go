@@ -23,6 +23,8 @@ type ActionType string
 const (
 	GetLeaderCountActionType      ActionType = "GetLeaderCount"
 	FlushLogBackupTasksActionType ActionType = "FlushLogBackupTasks"
+	GetBackupNumThreadsActionType ActionType = "GetBackupNumThreads"
+	SetBackupNumThreadsActionType ActionType = "SetBackupNumThreads"
 )
 
 type NotFoundReaction struct {
@@ -81,3 +83,20 @@ func (c *FakeTiKVClient) FlushLogBackupTasks(ctx context.Context) error {
 	_, err := c.fakeAPI(FlushLogBackupTasksActionType, action)
 	return err
 }
+
+// GetBackupNumThreads implements TiKVClient.
+func (c *FakeTiKVClient) GetBackupNumThreads() (int32, error) {
+	action := &Action{}
+	result, err := c.fakeAPI(GetBackupNumThreadsActionType, action)
+	if err != nil {
+		return 0, err
+	}
+	return result.(int32), nil
+}
+
+// SetBackupNumThreads implements TiKVClient.
+func (c *FakeTiKVClient) SetBackupNumThreads(threads int32) error {
+	action := &Action{ID: uint64(threads)}
+	_, err := c.fakeAPI(SetBackupNumThreadsActionType, action)
+	return err
+}
@@ -14,8 +14,10 @@
 package tikvapi
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -25,6 +27,7 @@ import (
 
 	"github.com/pingcap/errors"
 	logbackup "github.com/pingcap/kvproto/pkg/logbackuppb"
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/prom2json"
 	"google.golang.org/grpc"
@@ -38,12 +41,17 @@ const (
 	metricNameRegionCount = "tikv_raftstore_region_count"
 	labelNameLeaderCount  = "leader"
 	metricsPrefix         = "metrics"
+	configPrefix          = "config"
 )
 
 // TiKVClient provides tikv server's api
 type TiKVClient interface {
 	GetLeaderCount() (int, error)
 	FlushLogBackupTasks(ctx context.Context) error
+	// GetBackupNumThreads returns the number of the threads of TiKV to process the backup
+	GetBackupNumThreads() (int32, error)
+	// SetBackupNumThreads changes the number of the threads of TiKV to process the backup online
+	SetBackupNumThreads(threads int32) error
 }
 
 type lazyGRPCConn struct {
@@ -136,6 +144,36 @@ func (c *tikvClient) GetLeaderCount() (int, error) {
 	return 0, fmt.Errorf("metric %s{type=\"%s\"} not found for %s", metricNameRegionCount, labelNameLeaderCount, apiURL)
 }
 
+// tikvBackupConfig is the backup section of the config returned by the TiKV status API
+type tikvBackupConfig struct {
+	Backup struct {
+		NumThreads int32 `json:"num-threads"`
+	} `json:"backup"`
+}
+
+func (c *tikvClient) GetBackupNumThreads() (int32, error) {
+	apiURL := fmt.Sprintf("%s/%s", c.url, configPrefix)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return 0, err
+	}
+	config := &tikvBackupConfig{}
+	if err := json.Unmarshal(body, config); err != nil {
+		return 0, err
+	}
+	return config.Backup.NumThreads, nil
+}
+
+func (c *tikvClient) SetBackupNumThreads(threads int32) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, configPrefix)
+	data, err := json.Marshal(map[string]int32{"backup.num-threads": threads})
+	if err != nil {
+		return err
+	}
+	_, err = httputil.PostBodyOK(c.httpClient, apiURL, bytes.NewBuffer(data))
+	return err
+}
+
 type TiKVClientOpts struct {
 	HTTPEndpoint      string
 	GRPCEndpoint      string
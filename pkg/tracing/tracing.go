@@ -0,0 +1,188 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing instruments the reconciles of the controllers with OpenTelemetry.
+// The spans are dropped by the no-op tracer provider of OpenTelemetry until Init is
+// called with an endpoint.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ServiceName is the name of the service the spans are reported by
+	ServiceName = "tidb-controller-manager"
+
+	instrumentationName = "github.com/pingcap/tidb-operator"
+)
+
+// Attribute keys of the spans
+const (
+	AttrNamespace = attribute.Key("tidb_operator.namespace")
+	AttrName      = attribute.Key("tidb_operator.name")
+	AttrPhase     = attribute.Key("tidb_operator.phase")
+)
+
+// Init sets up the global tracer provider that exports the spans to the OTLP gRPC endpoint,
+// the returned function flushes the pending spans and shuts down the exporter.
+// Nothing is set up if the endpoint is empty.
+func Init(ctx context.Context, endpoint string, insecure bool, sampleRatio float64) (func(context.Context) error, error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP trace exporter for %s failed: %v", endpoint, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create trace resource failed: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of the span in the context, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartObject starts a span for the reconcile of a namespaced object.
+func StartObject(ctx context.Context, name, namespace, objName string) (context.Context, trace.Span) {
+	ctx = context.WithValue(ctx, objectKey{}, objectKeyOf(namespace, objName))
+	return Start(ctx, name, AttrNamespace.String(namespace), AttrName.String(objName))
+}
+
+type objectKey struct{}
+
+func objectKeyOf(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// the contexts bound to the objects being reconciled, an object is reconciled by one worker
+// at a time, so the calls made without a context in its reconcile, e.g. the requests to PD,
+// are traced as the children of the span of the bound context.
+var (
+	boundContextsLock sync.RWMutex
+	boundContexts     = map[string]context.Context{}
+)
+
+// Bind binds ctx to the object whose reconcile is started by StartObject in ctx, until the
+// returned function is called. Nothing is bound if ctx is not of a reconcile.
+func Bind(ctx context.Context) func() {
+	key, ok := ctx.Value(objectKey{}).(string)
+	if !ok {
+		return func() {}
+	}
+	boundContextsLock.Lock()
+	defer boundContextsLock.Unlock()
+	old, hasOld := boundContexts[key]
+	boundContexts[key] = ctx
+	return func() {
+		boundContextsLock.Lock()
+		defer boundContextsLock.Unlock()
+		if hasOld {
+			boundContexts[key] = old
+		} else {
+			delete(boundContexts, key)
+		}
+	}
+}
+
+func boundContext(key string) (context.Context, bool) {
+	boundContextsLock.RLock()
+	defer boundContextsLock.RUnlock()
+	ctx, ok := boundContexts[key]
+	return ctx, ok
+}
+
+// End ends the span and records the error, if any.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Phase runs fn in a child span of the span in the context, the name of the phase
+// is recorded as an attribute so the phases of a reconcile can be aggregated.
+func Phase(ctx context.Context, phase string, fn func() error) error {
+	ctx, span := Start(ctx, phase, AttrPhase.String(phase))
+	unbind := Bind(ctx)
+	err := fn()
+	unbind()
+	End(span, err)
+	return err
+}
+
+// Transport wraps the round tripper so a client span is started for each request,
+// http.DefaultTransport is wrapped if rt is nil.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(rt)
+}
+
+// ObjectTransport wraps the round tripper like Transport for the client of a namespaced object,
+// the spans of the requests without a span in their contexts are started as the children of the
+// context bound to the object, if any.
+func ObjectTransport(namespace, name string, rt http.RoundTripper) http.RoundTripper {
+	return &objectTransport{key: objectKeyOf(namespace, name), rt: Transport(rt)}
+}
+
+type objectTransport struct {
+	key string
+	rt  http.RoundTripper
+}
+
+func (t *objectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		if ctx, ok := boundContext(t.key); ok {
+			req = req.WithContext(trace.ContextWithSpan(req.Context(), trace.SpanFromContext(ctx)))
+		}
+	}
+	return t.rt.RoundTrip(req)
+}
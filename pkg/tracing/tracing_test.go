@@ -0,0 +1,102 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestPhase(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	old := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(old)
+
+	ctx, span := StartObject(context.Background(), "TidbCluster.Reconcile", "default", "basic")
+	g.Expect(Phase(ctx, "pd", func() error { return nil })).To(Succeed())
+	err := Phase(ctx, "tikv", func() error { return fmt.Errorf("tikv is not ready") })
+	g.Expect(err).To(MatchError("tikv is not ready"))
+	End(span, err)
+
+	spans := exporter.GetSpans()
+	g.Expect(spans).To(HaveLen(3))
+	pd, tikv, root := spans[0], spans[1], spans[2]
+	g.Expect(root.Name).To(Equal("TidbCluster.Reconcile"))
+	g.Expect(root.Attributes).To(ContainElements(AttrNamespace.String("default"), AttrName.String("basic")))
+	g.Expect(root.Status.Code).To(Equal(codes.Error))
+
+	g.Expect(pd.Name).To(Equal("pd"))
+	g.Expect(pd.Parent.SpanID()).To(Equal(root.SpanContext.SpanID()))
+	g.Expect(pd.Status.Code).To(Equal(codes.Unset))
+	g.Expect(tikv.Parent.SpanID()).To(Equal(root.SpanContext.SpanID()))
+	g.Expect(tikv.Status.Code).To(Equal(codes.Error))
+	g.Expect(tikv.Status.Description).To(Equal("tikv is not ready"))
+	g.Expect(tikv.Events).To(HaveLen(1))
+}
+
+func TestObjectTransport(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	old := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(old)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := &http.Client{Transport: ObjectTransport("default", "basic", nil)}
+	get := func() error {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	ctx, span := StartObject(context.Background(), "TidbCluster.Reconcile", "default", "basic")
+	g.Expect(Phase(ctx, "pd", get)).To(Succeed())
+	End(span, nil)
+	// the requests out of the reconcile are traced as the root spans
+	g.Expect(get()).To(Succeed())
+
+	spans := exporter.GetSpans()
+	g.Expect(spans).To(HaveLen(4))
+	request, pd, root, orphan := spans[0], spans[1], spans[2], spans[3]
+	g.Expect(pd.Name).To(Equal("pd"))
+	g.Expect(request.Parent.SpanID()).To(Equal(pd.SpanContext.SpanID()))
+	g.Expect(request.SpanContext.TraceID()).To(Equal(root.SpanContext.TraceID()))
+	g.Expect(orphan.Parent.IsValid()).To(BeFalse())
+}
+
+func TestInitWithoutEndpoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	shutdown, err := Init(context.Background(), "", false, 1)
+	g.Expect(err).To(Succeed())
+	g.Expect(shutdown(context.Background())).To(Succeed())
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// ParseCronSchedule parses the standard cron expression evaluated in the time zone, e.g. "Asia/Shanghai".
+// The cron expression is evaluated in the local time zone of the operator if the time zone is empty.
+func ParseCronSchedule(spec, timeZone string) (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+	if timeZone == "" {
+		return sched, nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %v", timeZone, err)
+	}
+	return &zonedSchedule{sched: sched, loc: loc}, nil
+}
+
+// zonedSchedule evaluates the cron schedule in the wall clock of the time zone. The times skipped by the
+// DST transitions are delayed until the transitions, and the times repeated by the DST transitions are
+// scheduled only once.
+type zonedSchedule struct {
+	sched cron.Schedule
+	loc   *time.Location
+}
+
+func (s *zonedSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc)
+	// the wall clock has no DST transitions in UTC
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	for {
+		wall = s.sched.Next(wall)
+		if wall.IsZero() {
+			return wall
+		}
+		next := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, s.loc)
+		// the wall clock skipped by the DST transition is resolved before the transition, move it after
+		if actual := time.Date(next.Year(), next.Month(), next.Day(), next.Hour(), next.Minute(), next.Second(), 0, time.UTC); actual.Before(wall) {
+			next = next.Add(wall.Sub(actual))
+		}
+		// the wall clock repeated by the DST transition may be resolved to the first occurrence
+		if next.After(t) {
+			return next
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := ParseCronSchedule("0 2 * * *", "Mars/Olympus")
+	g.Expect(err).Should(HaveOccurred())
+	_, err = ParseCronSchedule("0 2 * *", "")
+	g.Expect(err).Should(HaveOccurred())
+
+	loc, err := time.LoadLocation("America/New_York")
+	g.Expect(err).Should(Succeed())
+	next := func(spec string, times int, from time.Time) []time.Time {
+		sched, err := ParseCronSchedule(spec, "America/New_York")
+		g.Expect(err).Should(Succeed())
+		var res []time.Time
+		for t := from; len(res) < times; {
+			t = sched.Next(t)
+			res = append(res, t)
+		}
+		return res
+	}
+
+	// evaluated in the time zone
+	g.Expect(next("0 2 * * *", 1, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))).Should(Equal([]time.Time{
+		time.Date(2024, 1, 10, 7, 0, 0, 0, time.UTC).In(loc),
+	}))
+
+	// the time skipped by the DST transition on 2024-03-10 is delayed to 03:30 EDT
+	g.Expect(next("30 2 * * *", 3, time.Date(2024, 3, 9, 0, 0, 0, 0, loc))).Should(Equal([]time.Time{
+		time.Date(2024, 3, 9, 2, 30, 0, 0, loc),
+		time.Date(2024, 3, 10, 7, 30, 0, 0, time.UTC).In(loc),
+		time.Date(2024, 3, 11, 2, 30, 0, 0, loc),
+	}))
+
+	// the time repeated by the DST transition on 2024-11-03 is scheduled only once
+	res := next("30 1 * * *", 2, time.Date(2024, 11, 3, 0, 0, 0, 0, loc))
+	g.Expect(res[0].Add(24 * time.Hour).Before(res[1])).Should(BeTrue())
+	g.Expect(res[1]).Should(Equal(time.Date(2024, 11, 4, 1, 30, 0, 0, loc)))
+	hourly := next("0 * * * *", 3, time.Date(2024, 11, 3, 0, 30, 0, 0, loc))
+	g.Expect(hourly[1].Sub(hourly[0])).Should(Equal(2 * time.Hour))
+	g.Expect(hourly[2].Sub(hourly[1])).Should(Equal(time.Hour))
+}
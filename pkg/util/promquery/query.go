@@ -0,0 +1,100 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
+)
+
+// Querier runs the instant queries against Prometheus
+type Querier interface {
+	// Query returns the max value of the result of the PromQL query, ok is false if the result is empty
+	Query(ctx context.Context, prometheusURL, query string) (value float64, ok bool, err error)
+}
+
+// NewQuerier returns a Querier which queries by the Prometheus HTTP API
+func NewQuerier(timeout time.Duration) Querier {
+	return &httpQuerier{httpClient: &http.Client{Timeout: timeout}}
+}
+
+// httpQuerier queries by the Prometheus HTTP API
+type httpQuerier struct {
+	httpClient *http.Client
+}
+
+// queryResponse is the response of the instant query of the Prometheus HTTP API
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (q *httpQuerier) Query(ctx context.Context, prometheusURL, query string) (float64, bool, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/query?query=%s", prometheusURL, url.QueryEscape(query))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	res, err := q.httpClient.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer httputil.DeferClose(res.Body)
+
+	resp := &queryResponse{}
+	if err := json.NewDecoder(res.Body).Decode(resp); err != nil {
+		return 0, false, fmt.Errorf("decode the response of query %q failed, err: %v", query, err)
+	}
+	if resp.Status != "success" {
+		return 0, false, fmt.Errorf("query %q failed, err: %s", query, resp.Error)
+	}
+
+	var value float64
+	ok := false
+	for _, r := range resp.Data.Result {
+		if len(r.Value) != 2 {
+			continue
+		}
+		s, isString := r.Value[1].(string)
+		if !isString {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("parse the value %q of query %q failed, err: %v", s, query, err)
+		}
+		if math.IsNaN(v) {
+			continue
+		}
+		if !ok || v > value {
+			value = v
+			ok = true
+		}
+	}
+	return value, ok, nil
+}
@@ -38,6 +38,14 @@ const (
 	TiCDCCaptureNotReady = "TiCDCCaptureNotReady"
 	// TiProxyUnhealthy is added when one of tiproxy pods is unhealthy.
 	TiProxyUnhealthy = "TiProxyUnhealthy"
+	// ZoneBalanced is added when the members are distributed as declared by the topology spread constraints.
+	ZoneBalanced = "ZoneBalanced"
+	// ZoneSkewed is added when the distribution of the members of a component exceeds the max skew.
+	ZoneSkewed = "ZoneSkewed"
+	// CapabilitiesNotSupported is added when some features used by the cluster are not supported by Kubernetes.
+	CapabilitiesNotSupported = "CapabilitiesNotSupported"
+	// HostPortsConflicted is added when the pods in the host network bind the same ports on a node.
+	HostPortsConflicted = "HostPortsConflicted"
 )
 
 // NewTidbClusterCondition creates a new tidbcluster condition.
@@ -78,6 +86,11 @@ func SetTidbClusterCondition(status *v1alpha1.TidbClusterStatus, condition v1alp
 	status.Conditions = append(newConditions, condition)
 }
 
+// RemoveTidbClusterCondition removes the tidb cluster condition with the provided type.
+func RemoveTidbClusterCondition(status *v1alpha1.TidbClusterStatus, condType v1alpha1.TidbClusterConditionType) {
+	status.Conditions = filterOutCondition(status.Conditions, condType)
+}
+
 // filterOutCondition returns a new slice of tidbcluster conditions without conditions with the provided type.
 func filterOutCondition(conditions []v1alpha1.TidbClusterCondition, condType v1alpha1.TidbClusterConditionType) []v1alpha1.TidbClusterCondition {
 	var newConditions []v1alpha1.TidbClusterCondition
@@ -105,7 +105,7 @@ func GetPodOrdinals(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType) (s
 	var replicas int32
 	if memberType == v1alpha1.PDMemberType {
 		ann = label.AnnPDDeleteSlots
-		replicas = tc.Spec.PD.Replicas
+		replicas = tc.Spec.PD.Replicas + tc.PDLearnerReplicas()
 	} else if memberType == v1alpha1.PDMSTSOMemberType {
 		for _, component := range tc.Spec.PDMS {
 			if strings.Contains(memberType.String(), component.Name) {
@@ -0,0 +1,144 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/openshift/generic-admission-server/pkg/apiserver"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/webhook/util"
+	admission "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// BackupAdmissionControl denies the deletion of the backups protected by spec.deletionProtection,
+// and the BR tool images in spec.toolImage which are not compatible with the version of the cluster
+type BackupAdmissionControl struct {
+	lock        sync.RWMutex
+	initialized bool
+	// operator client interface
+	operatorCli versioned.Interface
+}
+
+var _ apiserver.ValidatingAdmissionHook = &BackupAdmissionControl{}
+
+func NewBackupAdmissionControl() *BackupAdmissionControl {
+	return &BackupAdmissionControl{}
+}
+
+func (bc *BackupAdmissionControl) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	return schema.GroupVersionResource{
+			Group:    "admission.tidb.pingcap.com",
+			Version:  "v1alpha1",
+			Resource: "backupvalidations",
+		},
+		"backupvalidation"
+}
+
+func (bc *BackupAdmissionControl) Validate(ar *admission.AdmissionRequest) *admission.AdmissionResponse {
+	switch ar.Operation {
+	case admission.Delete:
+		return bc.validateDeletion(ar)
+	case admission.Create, admission.Update:
+		return bc.validateToolImage(ar)
+	}
+	return util.ARSuccess()
+}
+
+func (bc *BackupAdmissionControl) validateDeletion(ar *admission.AdmissionRequest) *admission.AdmissionResponse {
+	backup := &v1alpha1.Backup{}
+	if err := json.Unmarshal(ar.OldObject.Raw, backup); err != nil {
+		err = fmt.Errorf("backup %s/%s, decode request failed, err: %v", ar.Namespace, ar.Name, err)
+		klog.Error(err)
+		return util.ARFail(err)
+	}
+	if backup.Spec.DeletionProtection {
+		klog.Infof("deny deleting backup %s/%s, it's protected from deletion", ar.Namespace, ar.Name)
+		return util.ARFail(fmt.Errorf("backup %s/%s is protected from deletion, unset spec.deletionProtection before deleting it", ar.Namespace, ar.Name))
+	}
+	return util.ARSuccess()
+}
+
+// validateToolImage checks the BR tool image against the version of TiKV of the backed up cluster,
+// the check is skipped if the cluster does not exist and the backup fails in the controller
+func (bc *BackupAdmissionControl) validateToolImage(ar *admission.AdmissionRequest) *admission.AdmissionResponse {
+	backup := &v1alpha1.Backup{}
+	if err := json.Unmarshal(ar.Object.Raw, backup); err != nil {
+		err = fmt.Errorf("backup %s/%s, decode request failed, err: %v", ar.Namespace, ar.Name, err)
+		klog.Error(err)
+		return util.ARFail(err)
+	}
+	if backup.Spec.BR == nil || backup.Spec.ToolImage == "" {
+		return util.ARSuccess()
+	}
+	if ar.Operation == admission.Update {
+		oldBackup := &v1alpha1.Backup{}
+		if err := json.Unmarshal(ar.OldObject.Raw, oldBackup); err == nil && oldBackup.Spec.ToolImage == backup.Spec.ToolImage {
+			return util.ARSuccess()
+		}
+	}
+
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+	if !bc.initialized {
+		return &admission.AdmissionResponse{
+			Allowed: false,
+		}
+	}
+
+	ns := ar.Namespace
+	if backup.Spec.BR.ClusterNamespace != "" {
+		ns = backup.Spec.BR.ClusterNamespace
+	}
+	tc, err := bc.operatorCli.PingcapV1alpha1().TidbClusters(ns).Get(context.TODO(), backup.Spec.BR.Cluster, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return util.ARSuccess()
+	}
+	if err != nil {
+		err = fmt.Errorf("get tidbcluster %s/%s failed, backup %s/%s, err: %v", ns, backup.Spec.BR.Cluster, ar.Namespace, ar.Name, err)
+		klog.Error(err)
+		return util.ARFail(err)
+	}
+	if err := backuputil.ValidateBRToolImage(backup.Spec.ToolImage, tc.TiKVImage()); err != nil {
+		klog.Infof("deny backup %s/%s, %v", ar.Namespace, ar.Name, err)
+		return util.ARFail(fmt.Errorf("backup %s/%s, %v", ar.Namespace, ar.Name, err))
+	}
+	return util.ARSuccess()
+}
+
+// Initialize implements AdmissionHook.Initialize interface. It's is called as
+// a post-start hook.
+func (bc *BackupAdmissionControl) Initialize(cfg *rest.Config, stopCh <-chan struct{}) error {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	cli, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	bc.operatorCli = cli
+
+	bc.initialized = true
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	admission "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestBackupAdmissionControl(t *testing.T) {
+	g := NewGomegaWithT(t)
+	bc := NewBackupAdmissionControl()
+
+	newRequest := func(operation admission.Operation, deletionProtection bool) *admission.AdmissionRequest {
+		backup := &v1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup"},
+			Spec:       v1alpha1.BackupSpec{DeletionProtection: deletionProtection},
+		}
+		raw, err := json.Marshal(backup)
+		g.Expect(err).Should(Succeed())
+		return &admission.AdmissionRequest{
+			Operation: operation,
+			Namespace: backup.Namespace,
+			Name:      backup.Name,
+			Object:    runtime.RawExtension{Raw: raw},
+			OldObject: runtime.RawExtension{Raw: raw},
+		}
+	}
+
+	g.Expect(bc.Validate(newRequest(admission.Delete, false)).Allowed).Should(BeTrue())
+	g.Expect(bc.Validate(newRequest(admission.Update, true)).Allowed).Should(BeTrue())
+
+	resp := bc.Validate(newRequest(admission.Delete, true))
+	g.Expect(resp.Allowed).Should(BeFalse())
+	g.Expect(resp.Result.Message).Should(ContainSubstring("protected from deletion"))
+}
+
+func TestBackupAdmissionControlToolImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cli := fake.NewSimpleClientset()
+	bc := NewBackupAdmissionControl()
+	bc.initialized = true
+	bc.operatorCli = cli
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "demo"},
+		Spec: v1alpha1.TidbClusterSpec{
+			Version: "v7.5.0",
+			TiKV:    &v1alpha1.TiKVSpec{BaseImage: "pingcap/tikv"},
+		},
+	}
+	_, err := cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+
+	newRequest := func(cluster, toolImage string) *admission.AdmissionRequest {
+		backup := &v1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup"},
+			Spec: v1alpha1.BackupSpec{
+				BR:        &v1alpha1.BRConfig{Cluster: cluster},
+				ToolImage: toolImage,
+			},
+		}
+		raw, err := json.Marshal(backup)
+		g.Expect(err).Should(Succeed())
+		return &admission.AdmissionRequest{
+			Operation: admission.Create,
+			Namespace: backup.Namespace,
+			Name:      backup.Name,
+			Object:    runtime.RawExtension{Raw: raw},
+		}
+	}
+
+	g.Expect(bc.Validate(newRequest("demo", "")).Allowed).Should(BeTrue())
+	g.Expect(bc.Validate(newRequest("demo", "pingcap/br")).Allowed).Should(BeTrue())
+	g.Expect(bc.Validate(newRequest("demo", "pingcap/br:v7.5.1")).Allowed).Should(BeTrue())
+	// the cluster does not exist
+	g.Expect(bc.Validate(newRequest("other", "pingcap/br:v8.1.0")).Allowed).Should(BeTrue())
+
+	resp := bc.Validate(newRequest("demo", "pingcap/br:v8.1.0"))
+	g.Expect(resp.Allowed).Should(BeFalse())
+	g.Expect(resp.Result.Message).Should(ContainSubstring("not compatible"))
+}
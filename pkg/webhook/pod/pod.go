@@ -0,0 +1,224 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/openshift/generic-admission-server/pkg/apiserver"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/webhook/util"
+	admission "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// PodAdmissionControl denies deleting or evicting the pd pods whose deletion breaks the quorum
+// of the pd cluster, or which hold the pd leadership. For the leader, the leadership is transferred
+// to another healthy member and the request can be retried after the transfer is done.
+// The check is skipped for the pods annotated with tidb.pingcap.com/skip-pd-deletion-protection.
+type PodAdmissionControl struct {
+	lock        sync.RWMutex
+	initialized bool
+	// kubernetes client interface
+	kubeCli kubernetes.Interface
+	// operator client interface
+	operatorCli versioned.Interface
+	// pd control to access the pd clusters
+	pdControl pdapi.PDControlInterface
+}
+
+var _ apiserver.ValidatingAdmissionHook = &PodAdmissionControl{}
+
+func NewPodAdmissionControl() *PodAdmissionControl {
+	return &PodAdmissionControl{}
+}
+
+func (pc *PodAdmissionControl) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	return schema.GroupVersionResource{
+			Group:    "admission.tidb.pingcap.com",
+			Version:  "v1alpha1",
+			Resource: "podvalidations",
+		},
+		"podvalidation"
+}
+
+func (pc *PodAdmissionControl) Validate(ar *admission.AdmissionRequest) *admission.AdmissionResponse {
+	pc.lock.RLock()
+	defer pc.lock.RUnlock()
+	if !pc.initialized {
+		return &admission.AdmissionResponse{
+			Allowed: false,
+		}
+	}
+
+	namespace := ar.Namespace
+	name := ar.Name
+	pod := &corev1.Pod{}
+	switch {
+	case ar.Operation == admission.Delete && ar.SubResource == "":
+		if err := json.Unmarshal(ar.OldObject.Raw, pod); err != nil {
+			err = fmt.Errorf("pod %s/%s, decode request failed, err: %v", namespace, name, err)
+			klog.Error(err)
+			return util.ARFail(err)
+		}
+	case ar.Operation == admission.Create && ar.SubResource == "eviction":
+		p, err := pc.kubeCli.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return util.ARSuccess()
+		}
+		if err != nil {
+			err = fmt.Errorf("get pod %s/%s failed, err: %v", namespace, name, err)
+			klog.Error(err)
+			return util.ARFail(err)
+		}
+		pod = p
+	default:
+		return util.ARSuccess()
+	}
+
+	if !label.Label(pod.Labels).IsPD() || pod.DeletionTimestamp != nil {
+		return util.ARSuccess()
+	}
+	if pod.Annotations[label.AnnSkipPDDeletionProtectionKey] == "true" {
+		klog.Infof("admit deleting pd pod %s/%s, the deletion protection is skipped by annotation %s", namespace, name, label.AnnSkipPDDeletionProtectionKey)
+		return util.ARSuccess()
+	}
+	dryRun := ar.DryRun != nil && *ar.DryRun
+	if err := pc.admitDeletingPDPod(pod, dryRun); err != nil {
+		klog.Infof("deny deleting pd pod %s/%s, %v", namespace, name, err)
+		return util.ARFail(err)
+	}
+	return util.ARSuccess()
+}
+
+// admitDeletingPDPod returns an error if the pd pod can not be deleted now,
+// the leadership is not transferred for the dry-run requests
+func (pc *PodAdmissionControl) admitDeletingPDPod(pod *corev1.Pod, dryRun bool) error {
+	namespace := pod.Namespace
+	name := pod.Name
+	tcName := pod.Labels[label.InstanceLabelKey]
+	tc, err := pc.operatorCli.PingcapV1alpha1().TidbClusters(namespace).Get(context.TODO(), tcName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get tidbcluster %s/%s failed, err: %v", namespace, tcName, err)
+	}
+	if tc.DeletionTimestamp != nil {
+		// the pods are deleted with the tidb cluster
+		return nil
+	}
+
+	pdClient := controller.GetPDClient(pc.pdControl, tc)
+	healthInfo, err := pdClient.GetHealth()
+	if err != nil {
+		return fmt.Errorf("get health of the pd cluster of tidbcluster %s/%s failed, err: %v", namespace, tcName, err)
+	}
+	var member *pdapi.MemberHealth
+	healthy := 0
+	for i := range healthInfo.Healths {
+		m := &healthInfo.Healths[i]
+		if m.Health {
+			healthy++
+		}
+		if isMemberOfPod(m.Name, name) {
+			member = m
+		}
+	}
+	if member == nil || !member.Health {
+		// deleting the pods that are not healthy members does not affect the pd cluster
+		return nil
+	}
+	if healthy-1 <= len(healthInfo.Healths)/2 {
+		return fmt.Errorf("pd member %s is healthy and deleting it breaks the quorum of the pd cluster, %d of %d members are healthy, "+
+			"set annotation %s=true on the pod to skip the check", member.Name, healthy, len(healthInfo.Healths), label.AnnSkipPDDeletionProtectionKey)
+	}
+
+	leader, err := pdClient.GetPDLeader()
+	if err != nil {
+		return fmt.Errorf("get pd leader of tidbcluster %s/%s failed, err: %v", namespace, tcName, err)
+	}
+	if leader == nil || leader.GetName() != member.Name {
+		return nil
+	}
+	target := ""
+	for _, m := range healthInfo.Healths {
+		if m.Health && m.Name != member.Name {
+			target = m.Name
+			break
+		}
+	}
+	if target == "" {
+		return fmt.Errorf("pd member %s is the leader and there is no healthy member to transfer the leadership to", member.Name)
+	}
+	if dryRun {
+		return fmt.Errorf("pd member %s is the leader, transfer the leadership before deleting it", member.Name)
+	}
+	if err := pdClient.TransferPDLeader(target); err != nil {
+		return fmt.Errorf("pd member %s is the leader, transfer the leadership to %s failed, err: %v", member.Name, target, err)
+	}
+	klog.Infof("pd pod %s/%s is the leader, transfer the leadership to %s before deleting it", namespace, name, target)
+	return fmt.Errorf("pd member %s is the leader, the leadership is being transferred to %s, retry later", member.Name, target)
+}
+
+// isMemberOfPod returns whether the pd member is the member of the pod. The member name is the pod name,
+// or the FQDN of the pod if the cluster domain is set or the cluster is deployed across kubernetes.
+func isMemberOfPod(memberName, podName string) bool {
+	return memberName == podName || strings.HasPrefix(memberName, podName+".")
+}
+
+// Initialize implements AdmissionHook.Initialize interface. It's is called as
+// a post-start hook.
+func (pc *PodAdmissionControl) Initialize(cfg *rest.Config, stopCh <-chan struct{}) error {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	kubeCli, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	cli, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	// the secrets are used to access the pd clusters with tls enabled
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	secretLister := secretInformer.Lister()
+	kubeInformerFactory.Start(stopCh)
+	cache.WaitForCacheSync(stopCh, secretInformer.Informer().HasSynced)
+
+	pc.kubeCli = kubeCli
+	pc.operatorCli = cli
+	pc.pdControl = pdapi.NewDefaultPDControl(secretLister)
+
+	pc.initialized = true
+	return nil
+}
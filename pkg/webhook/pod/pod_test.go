@@ -0,0 +1,137 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	admission "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodAdmissionControl(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "demo"},
+	}
+	newPod := func(name string, l label.Label) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: tc.Namespace, Name: name, Labels: l},
+		}
+	}
+	pdLabels := label.New().Instance(tc.Name).PD()
+
+	kubeCli := kubefake.NewSimpleClientset()
+	cli := fake.NewSimpleClientset()
+	_, err := cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	pdControl := pdapi.NewFakePDControl(nil)
+	pdClient := pdapi.NewFakePDClient()
+	pdControl.SetPDClient(pdapi.Namespace(tc.Namespace), tc.Name, pdClient)
+
+	pc := NewPodAdmissionControl()
+	pc.initialized = true
+	pc.kubeCli = kubeCli
+	pc.operatorCli = cli
+	pc.pdControl = pdControl
+
+	healths := []pdapi.MemberHealth{
+		{Name: "demo-pd-0", Health: true},
+		{Name: "demo-pd-1", Health: true},
+		{Name: "demo-pd-2", Health: true},
+	}
+	pdClient.AddReaction(pdapi.GetHealthActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.HealthInfo{Healths: healths}, nil
+	})
+	pdClient.AddReaction(pdapi.GetPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdpb.Member{Name: "demo-pd-0"}, nil
+	})
+	transferredTo := ""
+	pdClient.AddReaction(pdapi.TransferPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		transferredTo = action.Name
+		return nil, nil
+	})
+
+	deleteRequest := func(pod *corev1.Pod) *admission.AdmissionRequest {
+		raw, err := json.Marshal(pod)
+		g.Expect(err).Should(Succeed())
+		return &admission.AdmissionRequest{
+			Operation: admission.Delete,
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			OldObject: runtime.RawExtension{Raw: raw},
+		}
+	}
+
+	// the pods of other components are not checked
+	g.Expect(pc.Validate(deleteRequest(newPod("demo-tikv-0", label.New().Instance(tc.Name).TiKV()))).Allowed).Should(BeTrue())
+
+	// the follower can be deleted if the quorum is kept
+	g.Expect(pc.Validate(deleteRequest(newPod("demo-pd-1", pdLabels))).Allowed).Should(BeTrue())
+
+	// the leadership is transferred before deleting the leader
+	resp := pc.Validate(deleteRequest(newPod("demo-pd-0", pdLabels)))
+	g.Expect(resp.Allowed).Should(BeFalse())
+	g.Expect(resp.Result.Message).Should(ContainSubstring("retry later"))
+	g.Expect(transferredTo).Should(Equal("demo-pd-1"))
+
+	// deleting the healthy member breaks the quorum if another member is unhealthy
+	healths[2].Health = false
+	resp = pc.Validate(deleteRequest(newPod("demo-pd-1", pdLabels)))
+	g.Expect(resp.Allowed).Should(BeFalse())
+	g.Expect(resp.Result.Message).Should(ContainSubstring("breaks the quorum"))
+
+	// the unhealthy member can be deleted
+	g.Expect(pc.Validate(deleteRequest(newPod("demo-pd-2", pdLabels))).Allowed).Should(BeTrue())
+
+	// the check is skipped by the annotation
+	pod := newPod("demo-pd-1", pdLabels)
+	pod.Annotations = map[string]string{label.AnnSkipPDDeletionProtectionKey: "true"}
+	g.Expect(pc.Validate(deleteRequest(pod)).Allowed).Should(BeTrue())
+
+	// the eviction is checked the same as the deletion
+	pod = newPod("demo-pd-1", pdLabels)
+	_, err = kubeCli.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	resp = pc.Validate(&admission.AdmissionRequest{
+		Operation:   admission.Create,
+		SubResource: "eviction",
+		Namespace:   pod.Namespace,
+		Name:        pod.Name,
+	})
+	g.Expect(resp.Allowed).Should(BeFalse())
+
+	// the pods of the deleted tidb cluster are not checked
+	g.Expect(cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Delete(context.TODO(), tc.Name, metav1.DeleteOptions{})).Should(Succeed())
+	g.Expect(pc.Validate(deleteRequest(newPod("demo-pd-1", pdLabels))).Allowed).Should(BeTrue())
+}
+
+func TestIsMemberOfPod(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(isMemberOfPod("demo-pd-1", "demo-pd-1")).Should(BeTrue())
+	g.Expect(isMemberOfPod("demo-pd-1.demo-pd-peer.ns.svc.cluster.local", "demo-pd-1")).Should(BeTrue())
+	g.Expect(isMemberOfPod("demo-pd-10", "demo-pd-1")).Should(BeFalse())
+}
@@ -16,12 +16,15 @@ package strategy
 import (
 	"context"
 	"encoding/json"
+	"sync"
 
 	"github.com/openshift/generic-admission-server/pkg/apiserver"
+	"github.com/pingcap/tidb-operator/pkg/registry"
 	"github.com/pingcap/tidb-operator/pkg/webhook/util"
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 )
@@ -29,13 +32,17 @@ import (
 // StrategyAdmissionHook is a admission webhook based on the registered strategies in the given registry
 type StrategyAdmissionHook struct {
 	registry *StrategyRegistry
+
+	lock sync.RWMutex
+	// kubeCli is used to validate the resources referenced by the objects, it's nil before initialized
+	kubeCli kubernetes.Interface
 }
 
 var _ apiserver.ValidatingAdmissionHook = &StrategyAdmissionHook{}
 var _ apiserver.MutatingAdmissionHook = &StrategyAdmissionHook{}
 
 func NewStrategyAdmissionHook(registry *StrategyRegistry) *StrategyAdmissionHook {
-	return &StrategyAdmissionHook{registry}
+	return &StrategyAdmissionHook{registry: registry}
 }
 
 func (w *StrategyAdmissionHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
@@ -70,16 +77,20 @@ func (w *StrategyAdmissionHook) Validate(ar *admissionv1.AdmissionRequest) *admi
 		klog.Errorf("admission validating failed: cannot unmarshal %s to %T", ar.Kind, obj)
 		return util.ARFail(err)
 	}
+	ctx := registry.WithUserName(context.TODO(), ar.UserInfo.Username)
+	if kubeCli := w.getKubeClient(); kubeCli != nil {
+		ctx = registry.WithKubeClient(ctx, kubeCli)
+	}
 	var allErr field.ErrorList
 	if ar.Operation == admissionv1.Create {
-		allErr = s.Validate(context.TODO(), obj)
+		allErr = s.Validate(ctx, obj)
 	} else {
 		old := s.NewObject()
 		if err := json.Unmarshal(ar.OldObject.Raw, old); err != nil {
 			klog.Errorf("admission validating failed: cannot unmarshal %s to %T", ar.Kind, old)
 			return util.ARFail(err)
 		}
-		allErr = s.ValidateUpdate(context.TODO(), obj, old)
+		allErr = s.ValidateUpdate(ctx, obj, old)
 	}
 	if len(allErr) > 0 {
 		return util.ARFail(allErr.ToAggregate())
@@ -101,23 +112,45 @@ func (w *StrategyAdmissionHook) Admit(ar *admissionv1.AdmissionRequest) *admissi
 		return util.ARFail(err)
 	}
 	original := obj.DeepCopyObject()
+	ctx := registry.WithUserName(context.TODO(), ar.UserInfo.Username)
+	var auditAnnotations map[string]string
 	if ar.Operation == admissionv1.Create {
-		s.PrepareForCreate(context.TODO(), obj)
+		s.PrepareForCreate(ctx, obj)
 	} else {
 		old := s.NewObject()
 		if err := json.Unmarshal(ar.OldObject.Raw, old); err != nil {
 			klog.Errorf("admission validating failed: cannot unmarshal %s to %T", ar.Kind, old)
 			return util.ARFail(err)
 		}
-		s.PrepareForUpdate(context.TODO(), obj, old)
+		s.PrepareForUpdate(ctx, obj, old)
+		if annotator, ok := s.(registry.AuditAnnotator); ok {
+			auditAnnotations = annotator.AuditAnnotations(ctx, obj, old)
+		}
 	}
 	patch, err := util.CreateJsonPatch(original, obj)
 	if err != nil {
 		return util.ARFail(err)
 	}
-	return util.ARPatch(patch)
+	resp := util.ARPatch(patch)
+	resp.AuditAnnotations = auditAnnotations
+	return resp
 }
 
+// Initialize implements AdmissionHook.Initialize interface. It's called as
+// a post-start hook.
 func (w *StrategyAdmissionHook) Initialize(cfg *rest.Config, stopCh <-chan struct{}) error {
+	kubeCli, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.kubeCli = kubeCli
 	return nil
 }
+
+func (w *StrategyAdmissionHook) getKubeClient() kubernetes.Interface {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.kubeCli
+}
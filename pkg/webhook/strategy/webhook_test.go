@@ -21,9 +21,12 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/registry"
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -215,6 +218,50 @@ func TestStrategyAdmissionHook_Validate(t *testing.T) {
 	}
 }
 
+func TestStrategyAdmissionHook_AdmitForceContinueUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r := NewRegistry()
+	r.Register(registry.TidbClusterStrategy{})
+	w := NewStrategyAdmissionHook(&r)
+
+	admit := func(tc, old *v1alpha1.TidbCluster) *admissionv1.AdmissionResponse {
+		gvk, err := controller.InferObjectKind(tc)
+		g.Expect(err).To(Succeed())
+		raw, err := json.Marshal(tc)
+		g.Expect(err).To(Succeed())
+		oldRaw, err := json.Marshal(old)
+		g.Expect(err).To(Succeed())
+		return w.Admit(&admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Kind: gvk.Kind, Group: gvk.Group, Version: gvk.Version},
+			Operation: admissionv1.Update,
+			Object:    runtime.RawExtension{Raw: raw},
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+			UserInfo:  authenticationv1.UserInfo{Username: "alice"},
+		})
+	}
+
+	old := &v1alpha1.TidbCluster{}
+	tc := old.DeepCopy()
+	tc.Annotations = map[string]string{label.AnnForceContinueUpgradeKey: "demo-tikv-2-1700000000"}
+	resp := admit(tc, old)
+	g.Expect(resp.Allowed).To(BeTrue())
+	g.Expect(string(resp.Patch)).To(ContainSubstring(`"value":"alice"`))
+	g.Expect(resp.AuditAnnotations).To(Equal(map[string]string{
+		"force-continue-upgrade":    "demo-tikv-2-1700000000",
+		"force-continue-upgrade-by": "alice",
+	}))
+
+	// the recorded user can't be changed without forcing the upgrade again
+	old = tc.DeepCopy()
+	old.Annotations[label.AnnForceContinueUpgradeByKey] = "bob"
+	tc = old.DeepCopy()
+	tc.Annotations[label.AnnForceContinueUpgradeByKey] = "alice"
+	resp = admit(tc, old)
+	g.Expect(resp.Allowed).To(BeTrue())
+	g.Expect(string(resp.Patch)).To(ContainSubstring(`"value":"bob"`))
+	g.Expect(resp.AuditAnnotations).To(BeEmpty())
+}
+
 type FakeStrategy struct {
 	prepareForCreateTracker controller.RequestTracker
 	prepareForUpdateTracker controller.RequestTracker
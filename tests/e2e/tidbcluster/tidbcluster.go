@@ -339,6 +339,40 @@ var _ = ginkgo.Describe("TiDBCluster", func() {
 		framework.ExpectNoError(err, "failed to wait for TidbCluster ready: %q", tc.Name)
 	})
 
+	ginkgo.It("should add a PD learner and promote it to a voter", func() {
+		ginkgo.By("Deploy initial tc with a PD learner")
+		clusterName := "pd-learner"
+		tc := fixture.GetTidbCluster(ns, clusterName, utilimage.TiDBLatest)
+		tc.Spec.StartScriptVersion = v1alpha1.StartScriptV2
+		tc.Spec.PD.Replicas = 3
+		tc.Spec.PD.LearnerReplicas = 1
+		tc.Spec.TiKV.Replicas = 1
+		tc.Spec.TiDB.Replicas = 1
+		utiltc.MustCreateTCWithComponentsReady(genericCli, oa, tc, 10*time.Minute, 5*time.Second)
+
+		learner := controller.PDMemberName(clusterName) + "-3"
+		err := utiltc.WaitForTCCondition(cli, tc.Namespace, tc.Name, time.Minute*5, time.Second*10,
+			func(tc *v1alpha1.TidbCluster) (bool, error) {
+				return len(tc.Status.PD.Learners) == 1 && tc.Status.PD.Learners[0] == learner, nil
+			})
+		framework.ExpectNoError(err, "failed to wait for PD member %q to join as a learner", learner)
+
+		ginkgo.By("Promote the PD learner to a voter")
+		err = controller.GuaranteedUpdate(genericCli, tc, func() error {
+			tc.Spec.PD.Replicas = 4
+			tc.Spec.PD.LearnerReplicas = 0
+			return nil
+		})
+		framework.ExpectNoError(err, "failed to promote the PD learner of TidbCluster: %q", tc.Name)
+		err = utiltc.WaitForTCCondition(cli, tc.Namespace, tc.Name, time.Minute*5, time.Second*10,
+			func(tc *v1alpha1.TidbCluster) (bool, error) {
+				return len(tc.Status.PD.Learners) == 0, nil
+			})
+		framework.ExpectNoError(err, "failed to wait for PD member %q to be promoted to a voter", learner)
+		err = oa.WaitForTidbClusterReady(tc, 5*time.Minute, 5*time.Second)
+		framework.ExpectNoError(err, "failed to wait for TidbCluster ready: %q", tc.Name)
+	})
+
 	ginkgo.It("should direct upgrade tc successfully when PD replicas less than 2.", func() {
 		clusterName := "upgrade-cluster-pd-1"
 		tc := fixture.GetTidbCluster(ns, clusterName, utilimage.TiDBLatestPrev)